@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/dag"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/iotaledger/hive.go/timeutil"
+)
+
+const (
+	// archiveCheckInterval is the interval in which the archiver checks for new milestones to archive.
+	archiveCheckInterval = 10 * time.Second
+)
+
+func runArchiver() {
+	if err := CorePlugin.Daemon().BackgroundWorker("Archiver", func(ctx context.Context) {
+		CorePlugin.LogInfo("Starting Archiver ... done")
+		ticker := timeutil.NewTicker(func() {
+			confirmedMilestoneIndex := deps.SyncManager.ConfirmedMilestoneIndex()
+			if confirmedMilestoneIndex <= archiveMilestonesToKeep {
+				return
+			}
+
+			if err := archiveMilestones(ctx, confirmedMilestoneIndex-archiveMilestonesToKeep); err != nil && !errors.Is(err, common.ErrOperationAborted) {
+				CorePlugin.LogWarnf("archiving milestones failed: %s", err)
+			}
+		}, archiveCheckInterval, ctx)
+		ticker.WaitForGracefulShutdown()
+		CorePlugin.LogInfo("Stopping Archiver... done")
+	}, shutdown.PriorityArchiver); err != nil {
+		CorePlugin.LogPanicf("failed to start worker: %s", err)
+	}
+}
+
+// archiveMilestones moves the cones of all milestones up to the target index to the archive database.
+func archiveMilestones(ctx context.Context, targetIndex milestone.Index) error {
+	return deps.Storage.ArchiveMilestones(ctx, targetIndex, func(msIndex milestone.Index) error {
+		msgCount, err := archiveMilestoneCone(ctx, msIndex)
+		if err != nil {
+			return err
+		}
+
+		CorePlugin.LogDebugf("Archived milestone (%d), messages: %d", msIndex, msgCount)
+		return nil
+	})
+}
+
+// archiveMilestoneCone moves all messages referenced by the given milestone to the archive database.
+func archiveMilestoneCone(ctx context.Context, msIndex milestone.Index) (int, error) {
+
+	cachedMs := deps.Storage.CachedMilestoneOrNil(msIndex) // milestone +1
+	if cachedMs == nil {
+		return 0, errors.New("milestone not found")
+	}
+	milestoneMessageID := cachedMs.Milestone().MessageID
+	cachedMs.Release(true) // milestone -1
+
+	var messageIDs hornet.MessageIDs
+
+	if err := dag.TraverseParentsOfMessage(
+		ctx,
+		deps.Storage,
+		milestoneMessageID,
+		// traversal stops if no more messages pass the given condition
+		// Caution: condition func is not in DFS order
+		func(cachedMsgMeta *storage.CachedMetadata) (bool, error) { // meta +1
+			defer cachedMsgMeta.Release(true) // meta -1
+			// messages of older milestones were already moved to the archive
+			referenced, at := cachedMsgMeta.Metadata().ReferencedWithIndex()
+			return referenced && at == msIndex, nil
+		},
+		// consumer
+		func(cachedMsgMeta *storage.CachedMetadata) error { // meta +1
+			defer cachedMsgMeta.Release(true) // meta -1
+			messageIDs = append(messageIDs, cachedMsgMeta.Metadata().MessageID())
+			return nil
+		},
+		// called on missing parents
+		func(parentMessageID hornet.MessageID) error { return nil },
+		// called on solid entry points
+		nil,
+		false); err != nil {
+		return 0, err
+	}
+
+	msgCount := 0
+	for _, messageID := range messageIDs {
+		archived, err := deps.Storage.ArchiveMessage(messageID)
+		if err != nil {
+			return msgCount, err
+		}
+
+		if archived {
+			msgCount++
+		}
+	}
+
+	return msgCount, nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/gohornet/hornet/pkg/keymanager"
 	"github.com/gohornet/hornet/pkg/metrics"
 	"github.com/gohornet/hornet/pkg/model/coordinator"
+	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/model/syncmanager"
 	"github.com/gohornet/hornet/pkg/model/utxo"
@@ -51,6 +52,8 @@ var (
 	CorePlugin *node.CorePlugin
 	deps       dependencies
 
+	archiveMilestonesToKeep milestone.Index
+
 	deleteDatabase = flag.Bool(CfgTangleDeleteDatabase, false, "whether to delete the database at startup")
 	deleteAll      = flag.Bool(CfgTangleDeleteAll, false, "whether to delete the database and snapshots at startup")
 
@@ -64,6 +67,9 @@ type dependencies struct {
 	UTXODatabase   *database.Database `name:"utxoDatabase"`
 	Storage        *storage.Storage
 	StorageMetrics *metrics.StorageMetrics
+	SyncManager    *syncmanager.SyncManager
+	NodeConfig     *configuration.Configuration `name:"nodeConfig"`
+	BelowMaxDepth  int                          `name:"belowMaxDepth"`
 }
 
 func initConfigPars(c *dig.Container) {
@@ -79,6 +85,7 @@ func initConfigPars(c *dig.Container) {
 		DatabasePath             string          `name:"databasePath"`
 		TangleDatabasePath       string          `name:"tangleDatabasePath"`
 		UTXODatabasePath         string          `name:"utxoDatabasePath"`
+		ArchiveDatabasePath      string          `name:"archiveDatabasePath"`
 		DeleteDatabaseFlag       bool            `name:"deleteDatabase"`
 		DeleteAllFlag            bool            `name:"deleteAll"`
 		DatabaseDebug            bool            `name:"databaseDebug"`
@@ -98,6 +105,7 @@ func initConfigPars(c *dig.Container) {
 			DatabasePath:             databasePath,
 			TangleDatabasePath:       filepath.Join(databasePath, TangleDatabaseDirectoryName),
 			UTXODatabasePath:         filepath.Join(databasePath, UTXODatabaseDirectoryName),
+			ArchiveDatabasePath:      deps.NodeConfig.String(CfgDatabaseArchivePath),
 			DeleteDatabaseFlag:       *deleteDatabase,
 			DeleteAllFlag:            *deleteAll,
 			DatabaseDebug:            deps.NodeConfig.Bool(CfgDatabaseDebug),
@@ -112,13 +120,14 @@ func provide(c *dig.Container) {
 
 	type databaseDeps struct {
 		dig.In
-		DeleteDatabaseFlag bool                         `name:"deleteDatabase"`
-		DeleteAllFlag      bool                         `name:"deleteAll"`
-		NodeConfig         *configuration.Configuration `name:"nodeConfig"`
-		DatabaseEngine     database.Engine              `name:"databaseEngine"`
-		DatabasePath       string                       `name:"databasePath"`
-		UTXODatabasePath   string                       `name:"utxoDatabasePath"`
-		TangleDatabasePath string                       `name:"tangleDatabasePath"`
+		DeleteDatabaseFlag  bool                         `name:"deleteDatabase"`
+		DeleteAllFlag       bool                         `name:"deleteAll"`
+		NodeConfig          *configuration.Configuration `name:"nodeConfig"`
+		DatabaseEngine      database.Engine              `name:"databaseEngine"`
+		DatabasePath        string                       `name:"databasePath"`
+		UTXODatabasePath    string                       `name:"utxoDatabasePath"`
+		TangleDatabasePath  string                       `name:"tangleDatabasePath"`
+		ArchiveDatabasePath string                       `name:"archiveDatabasePath"`
 	}
 
 	type databaseOut struct {
@@ -131,15 +140,34 @@ func provide(c *dig.Container) {
 
 		UTXODatabase        *database.Database       `name:"utxoDatabase"`
 		UTXODatabaseMetrics *metrics.DatabaseMetrics `name:"utxoDatabaseMetrics"`
+
+		// the archive database is nil if the archive is disabled
+		ArchiveDatabase        *database.Database       `name:"archiveDatabase"`
+		ArchiveDatabaseMetrics *metrics.DatabaseMetrics `name:"archiveDatabaseMetrics"`
 	}
 
 	if err := c.Provide(func(deps databaseDeps) databaseOut {
 
+		archiveEnabled := deps.NodeConfig.Bool(CfgDatabaseArchiveEnabled)
+
 		if deps.DeleteDatabaseFlag || deps.DeleteAllFlag {
 			// delete old database folder
 			if err := os.RemoveAll(deps.DatabasePath); err != nil {
 				CorePlugin.LogPanicf("deleting database folder failed: %s", err)
 			}
+
+			if archiveEnabled {
+				// the archive contains the history of the deleted database
+				if err := os.RemoveAll(deps.ArchiveDatabasePath); err != nil {
+					CorePlugin.LogPanicf("deleting archive database folder failed: %s", err)
+				}
+			}
+		}
+
+		if archiveEnabled {
+			if _, err := database.CheckDatabaseEngine(deps.ArchiveDatabasePath, true, deps.DatabaseEngine); err != nil {
+				CorePlugin.LogPanic(err)
+			}
 		}
 
 		// Check if we need to migrate a legacy database into the split format
@@ -159,24 +187,39 @@ func provide(c *dig.Container) {
 
 		tangleDatabaseMetrics := &metrics.DatabaseMetrics{}
 		utxoDatabaseMetrics := &metrics.DatabaseMetrics{}
+		archiveDatabaseMetrics := &metrics.DatabaseMetrics{}
 
 		switch targetEngine {
 		case database.EnginePebble:
+			var archiveDatabase *database.Database
+			if archiveEnabled {
+				archiveDatabase = newPebble(deps.ArchiveDatabasePath, archiveDatabaseMetrics)
+			}
+
 			return databaseOut{
-				StorageMetrics:        &metrics.StorageMetrics{},
-				TangleDatabase:        newPebble(deps.TangleDatabasePath, tangleDatabaseMetrics),
-				TangleDatabaseMetrics: tangleDatabaseMetrics,
-				UTXODatabase:          newPebble(deps.UTXODatabasePath, utxoDatabaseMetrics),
-				UTXODatabaseMetrics:   utxoDatabaseMetrics,
+				StorageMetrics:         &metrics.StorageMetrics{},
+				TangleDatabase:         newPebble(deps.TangleDatabasePath, tangleDatabaseMetrics),
+				TangleDatabaseMetrics:  tangleDatabaseMetrics,
+				UTXODatabase:           newPebble(deps.UTXODatabasePath, utxoDatabaseMetrics),
+				UTXODatabaseMetrics:    utxoDatabaseMetrics,
+				ArchiveDatabase:        archiveDatabase,
+				ArchiveDatabaseMetrics: archiveDatabaseMetrics,
 			}
 
 		case database.EngineRocksDB:
+			var archiveDatabase *database.Database
+			if archiveEnabled {
+				archiveDatabase = newRocksDB(deps.ArchiveDatabasePath, archiveDatabaseMetrics)
+			}
+
 			return databaseOut{
-				StorageMetrics:        &metrics.StorageMetrics{},
-				TangleDatabase:        newRocksDB(deps.TangleDatabasePath, tangleDatabaseMetrics),
-				TangleDatabaseMetrics: tangleDatabaseMetrics,
-				UTXODatabase:          newRocksDB(deps.UTXODatabasePath, utxoDatabaseMetrics),
-				UTXODatabaseMetrics:   utxoDatabaseMetrics,
+				StorageMetrics:         &metrics.StorageMetrics{},
+				TangleDatabase:         newRocksDB(deps.TangleDatabasePath, tangleDatabaseMetrics),
+				TangleDatabaseMetrics:  tangleDatabaseMetrics,
+				UTXODatabase:           newRocksDB(deps.UTXODatabasePath, utxoDatabaseMetrics),
+				UTXODatabaseMetrics:    utxoDatabaseMetrics,
+				ArchiveDatabase:        archiveDatabase,
+				ArchiveDatabaseMetrics: archiveDatabaseMetrics,
 			}
 
 		default:
@@ -205,9 +248,10 @@ func provide(c *dig.Container) {
 
 	type storageDeps struct {
 		dig.In
-		TangleDatabase *database.Database `name:"tangleDatabase"`
-		UTXODatabase   *database.Database `name:"utxoDatabase"`
-		Profile        *profile.Profile
+		TangleDatabase  *database.Database `name:"tangleDatabase"`
+		UTXODatabase    *database.Database `name:"utxoDatabase"`
+		ArchiveDatabase *database.Database `name:"archiveDatabase"`
+		Profile         *profile.Profile
 	}
 
 	type storageOut struct {
@@ -223,6 +267,10 @@ func provide(c *dig.Container) {
 			CorePlugin.LogPanicf("can't initialize storage: %s", err)
 		}
 
+		if deps.ArchiveDatabase != nil {
+			store.ConfigureArchiveStore(deps.ArchiveDatabase.KVStore())
+		}
+
 		store.PrintSnapshotInfo()
 
 		return storageOut{
@@ -284,6 +332,17 @@ func configure() {
 		CorePlugin.LogPanicf("failed to start worker: %s", err)
 	}
 
+	if deps.Storage.ArchiveEnabled() {
+		archiveMilestonesToKeep = milestone.Index(deps.NodeConfig.Int(CfgDatabaseArchiveMilestonesToKeep))
+
+		// messages below max depth are still needed by the tip selection and the solidifier
+		archiveMilestonesToKeepMin := milestone.Index(deps.BelowMaxDepth * 2)
+		if archiveMilestonesToKeep < archiveMilestonesToKeepMin {
+			CorePlugin.LogWarnf("parameter '%s' is too small (%d). value was changed to %d", CfgDatabaseArchiveMilestonesToKeep, archiveMilestonesToKeep, archiveMilestonesToKeepMin)
+			archiveMilestonesToKeep = archiveMilestonesToKeepMin
+		}
+	}
+
 	configureEvents()
 }
 
@@ -295,6 +354,10 @@ func run() {
 	}, shutdown.PriorityMetricsUpdater); err != nil {
 		CorePlugin.LogPanicf("failed to start worker: %s", err)
 	}
+
+	if deps.Storage.ArchiveEnabled() {
+		runArchiver()
+	}
 }
 
 func configureEvents() {
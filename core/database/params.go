@@ -16,6 +16,12 @@ const (
 	CfgDatabaseAutoRevalidation = "db.autoRevalidation"
 	// ignore the check for corrupted databases (should only be used for debug reasons).
 	CfgDatabaseDebug = "db.debug"
+	// whether to move the messages of old milestones to a separate archive database.
+	CfgDatabaseArchiveEnabled = "db.archive.enabled"
+	// the path to the archive database folder.
+	CfgDatabaseArchivePath = "db.archive.path"
+	// the amount of milestones whose messages are kept in the tangle database before they are moved to the archive database.
+	CfgDatabaseArchiveMilestonesToKeep = "db.archive.milestonesToKeep"
 )
 
 var params = &node.PluginParams{
@@ -26,6 +32,9 @@ var params = &node.PluginParams{
 			fs.String(CfgDatabasePath, "mainnetdb", "the path to the database folder")
 			fs.Bool(CfgDatabaseAutoRevalidation, false, "whether to automatically start revalidation on startup if the database is corrupted")
 			fs.Bool(CfgDatabaseDebug, false, "ignore the check for corrupted databases (should only be used for debug reasons)")
+			fs.Bool(CfgDatabaseArchiveEnabled, false, "whether to move the messages of old milestones to a separate archive database")
+			fs.String(CfgDatabaseArchivePath, "archivedb", "the path to the archive database folder")
+			fs.Int(CfgDatabaseArchiveMilestonesToKeep, 60480, "the amount of milestones whose messages are kept in the tangle database before they are moved to the archive database")
 			return fs
 		}(),
 	},
@@ -8,6 +8,7 @@ import (
 	"github.com/libp2p/go-libp2p-core/host"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/pkg/errors"
 	"go.uber.org/dig"
 
 	"github.com/gohornet/hornet/pkg/metrics"
@@ -16,6 +17,7 @@ import (
 	"github.com/gohornet/hornet/pkg/model/syncmanager"
 	"github.com/gohornet/hornet/pkg/node"
 	"github.com/gohornet/hornet/pkg/p2p"
+	"github.com/gohornet/hornet/pkg/pow"
 	"github.com/gohornet/hornet/pkg/profile"
 	"github.com/gohornet/hornet/pkg/protocol/gossip"
 	"github.com/gohornet/hornet/pkg/shutdown"
@@ -35,6 +37,7 @@ const (
 	heartbeatSentInterval   = 30 * time.Second
 	heartbeatReceiveTimeout = 100 * time.Second
 	checkHeartbeatsInterval = 5 * time.Second
+	checkHealthInterval     = 30 * time.Second
 
 	iotaGossipProtocolIDTemplate = "/iota-gossip/%d/1.0.0"
 )
@@ -71,12 +74,27 @@ type dependencies struct {
 	MessageProcessor *gossip.MessageProcessor
 	PeeringManager   *p2p.Manager
 	Host             host.Host
+	NodeConfig       *configuration.Configuration `name:"nodeConfig"`
 }
 
 func provide(c *dig.Container) {
 
-	if err := c.Provide(func() gossip.RequestQueue {
-		return gossip.NewRequestQueue()
+	type requestQueueDeps struct {
+		dig.In
+		NodeConfig *configuration.Configuration `name:"nodeConfig"`
+		Profile    *profile.Profile
+	}
+
+	if err := c.Provide(func(deps requestQueueDeps) gossip.RequestQueue {
+		var maxSize int
+		if deps.Profile.Caches.RequestQueue != nil {
+			maxSize = deps.Profile.Caches.RequestQueue.MaxSize
+		}
+
+		return gossip.NewRequestQueue(
+			gossip.WithRequestQueueMaxSize(maxSize),
+			gossip.WithRequestQueueTTL(deps.NodeConfig.Duration(CfgRequestsDiscardOlderThan)),
+		)
 	}); err != nil {
 		CorePlugin.LogPanic(err)
 	}
@@ -91,8 +109,8 @@ func provide(c *dig.Container) {
 		NodeConfig                *configuration.Configuration `name:"nodeConfig"`
 		NetworkID                 uint64                       `name:"networkId"`
 		DeserializationParameters *iotago.DeSerializationParameters
-		BelowMaxDepth             int     `name:"belowMaxDepth"`
-		MinPoWScore               float64 `name:"minPoWScore"`
+		BelowMaxDepth             int `name:"belowMaxDepth"`
+		PoWScoring                *pow.Scoring
 		Profile                   *profile.Profile
 	}
 
@@ -105,15 +123,19 @@ func provide(c *dig.Container) {
 			deps.ServerMetrics,
 			deps.DeserializationParameters,
 			&gossip.Options{
-				MinPoWScore:       deps.MinPoWScore,
-				NetworkID:         deps.NetworkID,
-				BelowMaxDepth:     milestone.Index(deps.BelowMaxDepth),
-				WorkUnitCacheOpts: deps.Profile.Caches.IncomingMessagesFilter,
+				PoWScoring:                  deps.PoWScoring,
+				NetworkID:                   deps.NetworkID,
+				BelowMaxDepth:               milestone.Index(deps.BelowMaxDepth),
+				WorkUnitCacheOpts:           deps.Profile.Caches.IncomingMessagesFilter,
+				MilestoneTimestampMaxFuture: deps.NodeConfig.Duration(CfgP2PGossipMilestoneTimestampMaxFuture),
+				MilestoneTimestampMaxPast:   deps.NodeConfig.Duration(CfgP2PGossipMilestoneTimestampMaxPast),
 			})
 		if err != nil {
 			CorePlugin.LogPanicf("MessageProcessor initialization failed: %s", err)
 		}
 
+		registerMessageFilters(msgProc, deps.NodeConfig)
+
 		return msgProc
 	}); err != nil {
 		CorePlugin.LogPanic(err)
@@ -130,6 +152,11 @@ func provide(c *dig.Container) {
 	}
 
 	if err := c.Provide(func(deps serviceDeps) *gossip.Service {
+		compression, err := gossip.ParseCompression(deps.NodeConfig.String(CfgP2PGossipCompression))
+		if err != nil {
+			CorePlugin.LogPanic(err)
+		}
+
 		return gossip.NewService(
 			protocol.ID(fmt.Sprintf(iotaGossipProtocolIDTemplate, deps.NetworkID)),
 			deps.Host,
@@ -139,6 +166,9 @@ func provide(c *dig.Container) {
 			gossip.WithUnknownPeersLimit(deps.NodeConfig.Int(CfgP2PGossipUnknownPeersLimit)),
 			gossip.WithStreamReadTimeout(deps.NodeConfig.Duration(CfgP2PGossipStreamReadTimeout)),
 			gossip.WithStreamWriteTimeout(deps.NodeConfig.Duration(CfgP2PGossipStreamWriteTimeout)),
+			gossip.WithCompression(compression),
+			gossip.WithStreamBandwidthLimits(deps.NodeConfig.Int(CfgP2PGossipBandwidthStreamUploadLimit), deps.NodeConfig.Int(CfgP2PGossipBandwidthStreamDownloadLimit)),
+			gossip.WithBandwidthLimits(deps.NodeConfig.Int(CfgP2PGossipBandwidthUploadLimit), deps.NodeConfig.Int(CfgP2PGossipBandwidthDownloadLimit)),
 		)
 	}); err != nil {
 		CorePlugin.LogPanic(err)
@@ -158,7 +188,8 @@ func provide(c *dig.Container) {
 			deps.GossipService,
 			deps.RequestQueue,
 			gossip.WithRequesterDiscardRequestsOlderThan(deps.NodeConfig.Duration(CfgRequestsDiscardOlderThan)),
-			gossip.WithRequesterPendingRequestReEnqueueInterval(deps.NodeConfig.Duration(CfgRequestsPendingReEnqueueInterval)))
+			gossip.WithRequesterPendingRequestReEnqueueInterval(deps.NodeConfig.Duration(CfgRequestsPendingReEnqueueInterval)),
+			gossip.WithRequesterStickyRequestTimeout(deps.NodeConfig.Duration(CfgRequestsStickyTimeout)))
 	}); err != nil {
 		CorePlugin.LogPanic(err)
 	}
@@ -213,7 +244,8 @@ func configure() {
 					proto.Parser.Events.Error.Trigger(err)
 					return
 				}
-				if _, err := proto.Parser.Read(buf[:r]); err != nil {
+				if _, err := proto.Parse(buf[:r]); err != nil {
+					deps.PeeringManager.ReportViolation(proto.PeerID, packetViolation(err))
 					return
 				}
 			}
@@ -298,6 +330,48 @@ func run() {
 	}, shutdown.PriorityHeartbeats); err != nil {
 		CorePlugin.LogPanicf("failed to start worker: %s", err)
 	}
+
+	minScore := deps.NodeConfig.Float64(CfgP2PGossipHealthMinScore)
+	if minScore <= 0 {
+		return
+	}
+
+	if err := CorePlugin.Daemon().BackgroundWorker("NeighborHealthCheck", func(ctx context.Context) {
+		healthTracker := gossip.NewHealthTracker(minScore, deps.NodeConfig.Duration(CfgP2PGossipHealthDropAfter), heartbeatSentInterval, heartbeatReceiveTimeout)
+		blacklistDuration := deps.NodeConfig.Duration(CfgP2PGossipHealthBlacklistDuration)
+
+		ticker := timeutil.NewTicker(func() {
+			checkHealth(healthTracker, blacklistDuration)
+		}, checkHealthInterval, ctx)
+		ticker.WaitForGracefulShutdown()
+	}, shutdown.PriorityHeartbeats); err != nil {
+		CorePlugin.LogPanicf("failed to start worker: %s", err)
+	}
+}
+
+// checkHealth evaluates the health of all neighbors and drops the neighbors
+// which stayed unhealthy for too long. dropped neighbors are blacklisted, so they
+// can't re-establish a gossip stream right away.
+func checkHealth(healthTracker *gossip.HealthTracker, blacklistDuration time.Duration) {
+
+	var protos []*gossip.Protocol
+	deps.GossipService.ForEach(func(proto *gossip.Protocol) bool {
+		protos = append(protos, proto)
+		return true
+	})
+
+	healthTracker.Update(protos, time.Now())
+
+	for _, proto := range protos {
+		health := proto.Health()
+		if !health.Unhealthy {
+			continue
+		}
+
+		CorePlugin.LogInfof("dropping neighbor %s because of a low health score (%0.2f)", proto.PeerID.ShortString(), health.Score)
+		deps.GossipService.BlacklistPeer(proto.PeerID, blacklistDuration)
+		_ = deps.PeeringManager.DisconnectPeer(proto.PeerID, errors.New("neighbor was dropped because of a low health score"))
+	}
 }
 
 // checkHeartbeats sends a heartbeat to each peer and also checks
@@ -353,3 +427,11 @@ func checkHeartbeats() {
 		}
 	}
 }
+
+// packetViolation returns the kind of protocol violation of a packet which couldn't be parsed.
+func packetViolation(err error) p2p.ProtocolViolation {
+	if errors.Is(err, gossip.ErrOversizedPacket) {
+		return p2p.ViolationOversizedPacket
+	}
+	return p2p.ViolationMalformedPacket
+}
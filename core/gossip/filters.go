@@ -0,0 +1,48 @@
+package gossip
+
+import (
+	"encoding/hex"
+
+	"github.com/gohornet/hornet/pkg/protocol/gossip"
+	"github.com/iotaledger/hive.go/configuration"
+)
+
+const (
+	// the name of the built-in filter which limits the message rate per peer.
+	messageFilterPeerRate = "peerRate"
+	// the name of the built-in filter which deprioritizes messages with duplicate payloads.
+	messageFilterDuplicatePayload = "duplicatePayload"
+	// the name of the built-in filter which rejects messages with certain tags.
+	messageFilterRejectedTags = "rejectedTags"
+)
+
+// registers the built-in message filters which are enabled in the config.
+func registerMessageFilters(msgProc *gossip.MessageProcessor, nodeConfig *configuration.Configuration) {
+
+	register := func(name string, filter gossip.MessageFilter) {
+		if err := msgProc.RegisterFilter(name, filter); err != nil {
+			CorePlugin.LogPanicf("unable to register message filter: %s", err)
+		}
+		CorePlugin.LogInfof("registered message filter %s", name)
+	}
+
+	if messagesPerSecond := nodeConfig.Float64(CfgP2PGossipFiltersPeerRateMessagesPerSecond); messagesPerSecond > 0 {
+		register(messageFilterPeerRate, gossip.NewPeerRateFilter(messagesPerSecond, nodeConfig.Int(CfgP2PGossipFiltersPeerRateBurst)))
+	}
+
+	if window := nodeConfig.Duration(CfgP2PGossipFiltersDuplicatePayloadWindow); window > 0 {
+		register(messageFilterDuplicatePayload, gossip.NewDuplicatePayloadFilter(window))
+	}
+
+	if tagPrefixesHex := nodeConfig.Strings(CfgP2PGossipFiltersRejectedTagPrefixes); len(tagPrefixesHex) > 0 {
+		tagPrefixes := make([][]byte, len(tagPrefixesHex))
+		for i, tagPrefixHex := range tagPrefixesHex {
+			tagPrefix, err := hex.DecodeString(tagPrefixHex)
+			if err != nil {
+				CorePlugin.LogPanicf("invalid tag prefix in %s: %s, error: %s", CfgP2PGossipFiltersRejectedTagPrefixes, tagPrefixHex, err)
+			}
+			tagPrefixes[i] = tagPrefix
+		}
+		register(messageFilterRejectedTags, gossip.NewTagFilter(tagPrefixes))
+	}
+}
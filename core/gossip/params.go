@@ -13,12 +13,42 @@ const (
 	CfgRequestsDiscardOlderThan = "requests.discardOlderThan"
 	// Defines the interval the pending requests are re-enqueued.
 	CfgRequestsPendingReEnqueueInterval = "requests.pendingReEnqueueInterval"
+	// Defines the time requests are only sent to the peer which sent a child of the requested message.
+	CfgRequestsStickyTimeout = "requests.stickyTimeout"
 	// Defines the maximum amount of unknown peers a gossip protocol connection is established to.
 	CfgP2PGossipUnknownPeersLimit = "p2p.gossip.unknownPeersLimit"
 	// Defines the read timeout for subsequent reads.
 	CfgP2PGossipStreamReadTimeout = "p2p.gossip.streamReadTimeout"
 	// Defines the write timeout for writes to the stream.
 	CfgP2PGossipStreamWriteTimeout = "p2p.gossip.streamWriteTimeout"
+	// Defines the compression used with peers which support it (none, snappy).
+	CfgP2PGossipCompression = "p2p.gossip.compression"
+	// Defines the upload limit of a single gossip stream in bytes per second (0 = unlimited).
+	CfgP2PGossipBandwidthStreamUploadLimit = "p2p.gossip.bandwidth.streamUploadLimit"
+	// Defines the download limit of a single gossip stream in bytes per second (0 = unlimited).
+	CfgP2PGossipBandwidthStreamDownloadLimit = "p2p.gossip.bandwidth.streamDownloadLimit"
+	// Defines the upload limit of all gossip streams in bytes per second (0 = unlimited).
+	CfgP2PGossipBandwidthUploadLimit = "p2p.gossip.bandwidth.uploadLimit"
+	// Defines the download limit of all gossip streams in bytes per second (0 = unlimited).
+	CfgP2PGossipBandwidthDownloadLimit = "p2p.gossip.bandwidth.downloadLimit"
+	// Defines the health score below which a neighbor is considered unhealthy (0 disables the check).
+	CfgP2PGossipHealthMinScore = "p2p.gossip.health.minScore"
+	// Defines the time the health score of a neighbor has to stay below the minimum until it gets dropped.
+	CfgP2PGossipHealthDropAfter = "p2p.gossip.health.dropAfter"
+	// Defines the time a dropped neighbor is blacklisted from establishing gossip streams.
+	CfgP2PGossipHealthBlacklistDuration = "p2p.gossip.health.blacklistDuration"
+	// Defines the maximum amount of messages per second a single peer may send before its messages are rejected (0 = disabled).
+	CfgP2PGossipFiltersPeerRateMessagesPerSecond = "p2p.gossip.filters.peerRate.messagesPerSecond"
+	// Defines the amount of messages a single peer may send at once before the rate limit applies.
+	CfgP2PGossipFiltersPeerRateBurst = "p2p.gossip.filters.peerRate.burst"
+	// Defines the time window in which messages with an already seen payload are not broadcasted (0 = disabled).
+	CfgP2PGossipFiltersDuplicatePayloadWindow = "p2p.gossip.filters.duplicatePayloadWindow"
+	// Defines the hex encoded tag prefixes of tagged data payloads which are rejected.
+	CfgP2PGossipFiltersRejectedTagPrefixes = "p2p.gossip.filters.rejectedTagPrefixes"
+	// Defines the maximum time the timestamp of a gossiped milestone may be ahead of the local clock (0 = disabled).
+	CfgP2PGossipMilestoneTimestampMaxFuture = "p2p.gossip.milestoneTimestamp.maxFuture"
+	// Defines the maximum time the timestamp of a gossiped milestone may be behind the latest known milestone (0 = disabled).
+	CfgP2PGossipMilestoneTimestampMaxPast = "p2p.gossip.milestoneTimestamp.maxPast"
 )
 
 var params = &node.PluginParams{
@@ -27,9 +57,24 @@ var params = &node.PluginParams{
 			fs := flag.NewFlagSet("", flag.ContinueOnError)
 			fs.Duration(CfgRequestsDiscardOlderThan, 15*time.Second, "the maximum time a request stays in the request queue")
 			fs.Duration(CfgRequestsPendingReEnqueueInterval, 5*time.Second, "the interval the pending requests are re-enqueued")
+			fs.Duration(CfgRequestsStickyTimeout, 3*time.Second, "the time requests are only sent to the peer which sent a child of the requested message")
 			fs.Int(CfgP2PGossipUnknownPeersLimit, 4, "maximum amount of unknown peers a gossip protocol connection is established to")
 			fs.Duration(CfgP2PGossipStreamReadTimeout, 60*time.Second, "the read timeout for reads from the gossip stream")
 			fs.Duration(CfgP2PGossipStreamWriteTimeout, 10*time.Second, "the write timeout for writes to the gossip stream")
+			fs.String(CfgP2PGossipCompression, "none", "the compression used with peers which support it (none, snappy)")
+			fs.Int(CfgP2PGossipBandwidthStreamUploadLimit, 0, "the upload limit of a single gossip stream in bytes per second (0 = unlimited)")
+			fs.Int(CfgP2PGossipBandwidthStreamDownloadLimit, 0, "the download limit of a single gossip stream in bytes per second (0 = unlimited)")
+			fs.Int(CfgP2PGossipBandwidthUploadLimit, 0, "the upload limit of all gossip streams in bytes per second (0 = unlimited)")
+			fs.Int(CfgP2PGossipBandwidthDownloadLimit, 0, "the download limit of all gossip streams in bytes per second (0 = unlimited)")
+			fs.Float64(CfgP2PGossipHealthMinScore, 0, "the health score (0-1) below which a neighbor is considered unhealthy (0 disables the check)")
+			fs.Duration(CfgP2PGossipHealthDropAfter, 10*time.Minute, "the time the health score of a neighbor has to stay below the minimum until it gets dropped")
+			fs.Duration(CfgP2PGossipHealthBlacklistDuration, 1*time.Hour, "the time a dropped neighbor is blacklisted from establishing gossip streams")
+			fs.Float64(CfgP2PGossipFiltersPeerRateMessagesPerSecond, 0, "the maximum amount of messages per second a single peer may send before its messages are rejected (0 = disabled)")
+			fs.Int(CfgP2PGossipFiltersPeerRateBurst, 100, "the amount of messages a single peer may send at once before the rate limit applies")
+			fs.Duration(CfgP2PGossipFiltersDuplicatePayloadWindow, 0, "the time window in which messages with an already seen payload are not broadcasted (0 = disabled)")
+			fs.StringSlice(CfgP2PGossipFiltersRejectedTagPrefixes, []string{}, "the hex encoded tag prefixes of tagged data payloads which are rejected")
+			fs.Duration(CfgP2PGossipMilestoneTimestampMaxFuture, 0, "the maximum time the timestamp of a gossiped milestone may be ahead of the local clock (0 = disabled)")
+			fs.Duration(CfgP2PGossipMilestoneTimestampMaxPast, 0, "the maximum time the timestamp of a gossiped milestone may be behind the latest known milestone (0 = disabled)")
 			return fs
 		}(),
 	},
@@ -50,7 +50,12 @@ func addMessageEventHandlers(proto *gossip.Protocol) {
 		proto.Metrics.ReceivedHeartbeats.Inc()
 		deps.ServerMetrics.ReceivedHeartbeats.Inc()
 
-		proto.LatestHeartbeat = gossip.ParseHeartbeat(data)
+		heartbeat, err := gossip.ParseHeartbeat(data)
+		if err != nil {
+			proto.Parser.Events.Error.Trigger(err)
+			return
+		}
+		proto.LatestHeartbeat = heartbeat
 
 		/*
 			if p.Autopeering != nil && p.LatestHeartbeat.SolidMilestoneIndex < tangle.SnapshotInfo().PruningIndex {
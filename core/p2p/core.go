@@ -9,7 +9,6 @@ import (
 	connmgr "github.com/libp2p/go-libp2p-connmgr"
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/host"
-	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/multiformats/go-multiaddr"
 	"go.uber.org/dig"
 
@@ -48,6 +47,7 @@ type dependencies struct {
 	PeerStoreContainer   *p2p.PeerStoreContainer
 	PeeringConfig        *configuration.Configuration `name:"peeringConfig"`
 	PeeringConfigManager *p2p.ConfigManager
+	IdentityRotator      *p2p.IdentityRotator
 }
 
 func initConfigPars(c *dig.Container) {
@@ -88,6 +88,8 @@ func provide(c *dig.Container) {
 		PeerStoreContainer *p2p.PeerStoreContainer
 		NodePrivateKey     crypto.PrivKey `name:"nodePrivateKey"`
 		Host               host.Host
+		IPFilter           *p2p.IPFilter
+		CircuitBreaker     *p2p.CircuitBreaker
 	}
 
 	if err := c.Provide(func(deps hostDeps) p2presult {
@@ -119,6 +121,19 @@ Your node identity private key can now be found at "%s".
 		// make sure nobody copies around the peer store since it contains the private key of the node
 		CorePlugin.LogInfof(`WARNING: never share your "%s" folder as it contains your node's private key!`, deps.P2PDatabasePath)
 
+		// activate a staged identity rotation once its grace period is over
+		if identityPrivKey == "" {
+			rotation, err := p2p.ActivateIdentityRotation(deps.P2PDatabasePath)
+			if err != nil {
+				CorePlugin.LogPanicf("activation of identity rotation failed: %s", err)
+			}
+			if rotation != nil {
+				CorePlugin.LogInfof("activated new peer identity %s, which replaces %s", rotation.NewID, rotation.OldID)
+			}
+		} else if _, err := p2p.LoadIdentityRotation(deps.P2PDatabasePath); err == nil {
+			CorePlugin.LogWarnf("won't activate the staged identity rotation: the private key for the peer identity is defined in the config (%s)", CfgP2PIdentityPrivKey)
+		}
+
 		// load up the previously generated identity or create a new one
 		privKey, newlyCreated, err := p2p.LoadOrCreateIdentityPrivateKey(deps.P2PDatabasePath, identityPrivKey)
 		if err != nil {
@@ -132,6 +147,21 @@ Your node identity private key can now be found at "%s".
 			CorePlugin.LogInfof(`loaded existing private key for peer identity from "%s"`, privKeyFilePath)
 		}
 
+		ipFilter, err := p2p.NewIPFilter(deps.NodeConfig.Strings(CfgP2PIPFilterAllow), deps.NodeConfig.Strings(CfgP2PIPFilterDeny))
+		if err != nil {
+			CorePlugin.LogPanicf("invalid IP filter config: %s", err)
+		}
+		res.IPFilter = ipFilter
+
+		if threshold := deps.NodeConfig.Int(CfgP2PCircuitBreakerViolationThreshold); threshold > 0 {
+			res.CircuitBreaker = p2p.NewCircuitBreaker(
+				threshold,
+				deps.NodeConfig.Duration(CfgP2PCircuitBreakerViolationWindow),
+				deps.NodeConfig.Duration(CfgP2PCircuitBreakerBanDuration),
+			)
+			ipFilter.SetCircuitBreaker(res.CircuitBreaker)
+		}
+
 		createdHost, err := libp2p.New(libp2p.Identity(privKey),
 			libp2p.ListenAddrStrings(deps.P2PBindMultiAddresses...),
 			libp2p.Peerstore(peerStoreContainer.Peerstore()),
@@ -142,6 +172,7 @@ Your node identity private key can now be found at "%s".
 				time.Minute,
 			)),
 			libp2p.NATPortMap(),
+			libp2p.ConnectionGater(ipFilter),
 		)
 		if err != nil {
 			CorePlugin.LogPanicf("unable to initialize peer: %s", err)
@@ -158,13 +189,25 @@ Your node identity private key can now be found at "%s".
 		Host                      host.Host
 		Config                    *configuration.Configuration `name:"nodeConfig"`
 		AutopeeringRunAsEntryNode bool                         `name:"autopeeringRunAsEntryNode"`
+		CircuitBreaker            *p2p.CircuitBreaker
 	}
 
 	if err := c.Provide(func(deps mngDeps) *p2p.Manager {
 		if !deps.AutopeeringRunAsEntryNode {
+			reconnectBackoff := p2p.ReconnectBackoff{
+				InitialInterval: deps.Config.Duration(CfgP2PReconnectInitialInterval),
+				Multiplier:      deps.Config.Float64(CfgP2PReconnectMultiplier),
+				MaxInterval:     deps.Config.Duration(CfgP2PReconnectMaxInterval),
+				Jitter:          deps.Config.Float64(CfgP2PReconnectJitter),
+			}
+			if err := reconnectBackoff.Validate(); err != nil {
+				CorePlugin.LogPanicf("invalid reconnect config: %s", err)
+			}
+
 			return p2p.NewManager(deps.Host,
 				p2p.WithManagerLogger(logger.NewLogger("P2P-Manager")),
-				p2p.WithManagerReconnectInterval(deps.Config.Duration(CfgP2PReconnectInterval), 1*time.Second),
+				p2p.WithManagerReconnectBackoff(reconnectBackoff),
+				p2p.WithManagerCircuitBreaker(deps.CircuitBreaker),
 			)
 		}
 		return nil
@@ -200,7 +243,7 @@ Your node identity private key can now be found at "%s".
 				CorePlugin.LogPanicf("invalid config peer address at pos %d: %s", i, err)
 			}
 
-			if err = p2pConfigManager.AddPeer(multiAddr, p.Alias); err != nil {
+			if err = p2pConfigManager.AddPeer(multiAddr, p.Alias, p.Transport, p.Reconnect); err != nil {
 				CorePlugin.LogWarnf("unable to add peer to config manager %s: %s", p.MultiAddress, err)
 			}
 		}
@@ -226,7 +269,7 @@ Your node identity private key can now be found at "%s".
 				alias = peerAliases[i]
 			}
 
-			if err = p2pConfigManager.AddPeer(multiAddr, alias); err != nil {
+			if err = p2pConfigManager.AddPeer(multiAddr, alias, "", nil); err != nil {
 				CorePlugin.LogWarnf("unable to add peer to config manager %s: %s", peerIDStr, err)
 			}
 		}
@@ -237,6 +280,44 @@ Your node identity private key can now be found at "%s".
 	}); err != nil {
 		CorePlugin.LogPanic(err)
 	}
+
+	type identityRotatorDeps struct {
+		dig.In
+		NodeConfig           *configuration.Configuration `name:"nodeConfig"`
+		P2PDatabasePath      string                       `name:"p2pDatabasePath"`
+		NodePrivateKey       crypto.PrivKey               `name:"nodePrivateKey"`
+		Host                 host.Host
+		PeeringManager       *p2p.Manager
+		PeeringConfigManager *p2p.ConfigManager
+	}
+
+	if err := c.Provide(func(deps identityRotatorDeps) *p2p.IdentityRotator {
+		if deps.PeeringManager == nil {
+			// Manager is optional, due to autopeering entry node
+			return nil
+		}
+
+		if deps.NodeConfig.String(CfgP2PIdentityPrivKey) != "" {
+			// the identity can't be rotated if the private key is defined in the config
+			return nil
+		}
+
+		identityRotator, err := p2p.NewIdentityRotator(
+			deps.Host,
+			deps.PeeringManager,
+			deps.PeeringConfigManager,
+			deps.P2PDatabasePath,
+			deps.NodePrivateKey,
+			deps.NodeConfig.Duration(CfgP2PIdentityRotationGracePeriod),
+			logger.NewLogger("P2P-IdentityRotation"),
+		)
+		if err != nil {
+			CorePlugin.LogPanicf("unable to load identity rotation: %s", err)
+		}
+		return identityRotator
+	}); err != nil {
+		CorePlugin.LogPanic(err)
+	}
 }
 
 func configure() {
@@ -282,23 +363,35 @@ func run() {
 	}, shutdown.PriorityP2PManager); err != nil {
 		CorePlugin.LogPanicf("failed to start worker: %s", err)
 	}
+
+	if deps.IdentityRotator == nil {
+		return
+	}
+
+	if err := CorePlugin.Daemon().BackgroundWorker("IdentityRotation", func(ctx context.Context) {
+		if rotation := deps.IdentityRotator.Rotation(); rotation != nil {
+			CorePlugin.LogInfof("announcing new peer identity %s, which is activated on restart after %s", rotation.NewID, rotation.ActivationTime().Format(time.RFC1123))
+		}
+		deps.IdentityRotator.Run(ctx)
+	}, shutdown.PriorityIdentityRotation); err != nil {
+		CorePlugin.LogPanicf("failed to start worker: %s", err)
+	}
 }
 
 // connects to the peers defined in the config.
 func connectConfigKnownPeers() {
 	for _, p := range deps.PeeringConfigManager.Peers() {
-		multiAddr, err := multiaddr.NewMultiaddr(p.MultiAddress)
+		addrInfo, err := p.AddrInfo()
 		if err != nil {
-			CorePlugin.LogPanicf("invalid peer address: %s", err)
+			CorePlugin.LogPanicf("invalid peer address info: %s", err)
 		}
 
-		addrInfo, err := peer.AddrInfoFromP2pAddr(multiAddr)
-		if err != nil {
-			CorePlugin.LogPanicf("invalid peer address info: %s", err)
+		if err = deps.PeeringManager.ApplyPeerReconnectConfig(addrInfo.ID, p.Reconnect); err != nil {
+			CorePlugin.LogWarnf("invalid reconnect config for peer (%s), using the default: %s", p.MultiAddress, err)
 		}
 
 		if err = deps.PeeringManager.ConnectPeer(addrInfo, p2p.PeerRelationKnown, p.Alias); err != nil {
-			CorePlugin.LogInfof("can't connect to peer (%s): %s", multiAddr.String(), err)
+			CorePlugin.LogInfof("can't connect to peer (%s): %s", p.MultiAddress, err)
 		}
 	}
 }
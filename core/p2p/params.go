@@ -19,8 +19,26 @@ const (
 	CfgP2PIdentityPrivKey = "p2p.identityPrivateKey"
 	// Defines the path to the p2p database.
 	CfgP2PDatabasePath = "p2p.db.path"
-	// Defines the time to wait before trying to reconnect to a disconnected peer.
-	CfgP2PReconnectInterval = "p2p.reconnectInterval"
+	// Defines the time to wait before the first attempt to reconnect to a disconnected peer.
+	CfgP2PReconnectInitialInterval = "p2p.reconnect.initialInterval"
+	// Defines the factor the time to wait is multiplied with after every failed reconnect attempt.
+	CfgP2PReconnectMultiplier = "p2p.reconnect.multiplier"
+	// Defines the maximum time to wait between two reconnect attempts.
+	CfgP2PReconnectMaxInterval = "p2p.reconnect.maxInterval"
+	// Defines the maximum random fraction of the time to wait that is added to it (0-1).
+	CfgP2PReconnectJitter = "p2p.reconnect.jitter"
+	// Defines the subnets (CIDR) from which incoming connections are accepted (empty = all).
+	CfgP2PIPFilterAllow = "p2p.ipFilter.allow"
+	// Defines the subnets (CIDR) from which incoming connections are rejected.
+	CfgP2PIPFilterDeny = "p2p.ipFilter.deny"
+	// Defines the amount of protocol violations within the window after which a peer is banned (0 = disabled).
+	CfgP2PCircuitBreakerViolationThreshold = "p2p.circuitBreaker.violationThreshold"
+	// Defines the time window in which the protocol violations of a peer are counted.
+	CfgP2PCircuitBreakerViolationWindow = "p2p.circuitBreaker.violationWindow"
+	// Defines the duration the identity and the IP addresses of a misbehaving peer are banned.
+	CfgP2PCircuitBreakerBanDuration = "p2p.circuitBreaker.banDuration"
+	// Defines the time a staged identity rotation is announced to the peers before the new identity is activated.
+	CfgP2PIdentityRotationGracePeriod = "p2p.identityRotation.gracePeriod"
 	// Defines the static peers this node should retain a connection to (config file).
 	CfgP2PPeers = "p2p.peers"
 	// Defines the aliases of the static peers (must be the same length like CfgP2PPeers) (CLI).
@@ -38,7 +56,16 @@ var params = &node.PluginParams{
 			fs.Int(CfgP2PConnMngLowWatermark, 5, "the minimum connections count to hold after the high watermark was reached")
 			fs.String(CfgP2PIdentityPrivKey, "", "private key used to derive the node identity (optional)")
 			fs.String(CfgP2PDatabasePath, "p2pstore", "the path to the p2p database")
-			fs.Duration(CfgP2PReconnectInterval, 30*time.Second, "the time to wait before trying to reconnect to a disconnected peer")
+			fs.Duration(CfgP2PReconnectInitialInterval, 5*time.Second, "the time to wait before the first attempt to reconnect to a disconnected peer")
+			fs.Float64(CfgP2PReconnectMultiplier, 2.0, "the factor the time to wait is multiplied with after every failed reconnect attempt")
+			fs.Duration(CfgP2PReconnectMaxInterval, 5*time.Minute, "the maximum time to wait between two reconnect attempts")
+			fs.Float64(CfgP2PReconnectJitter, 0.1, "the maximum random fraction of the time to wait that is added to it (0-1)")
+			fs.StringSlice(CfgP2PIPFilterAllow, nil, "the subnets (CIDR) from which incoming connections are accepted (empty = all)")
+			fs.StringSlice(CfgP2PIPFilterDeny, nil, "the subnets (CIDR) from which incoming connections are rejected")
+			fs.Int(CfgP2PCircuitBreakerViolationThreshold, 10, "the amount of protocol violations within the window after which a peer is banned (0 = disabled)")
+			fs.Duration(CfgP2PCircuitBreakerViolationWindow, 10*time.Minute, "the time window in which the protocol violations of a peer are counted")
+			fs.Duration(CfgP2PCircuitBreakerBanDuration, time.Hour, "the duration the identity and the IP addresses of a misbehaving peer are banned")
+			fs.Duration(CfgP2PIdentityRotationGracePeriod, 24*time.Hour, "the time a staged identity rotation is announced to the peers before the new identity is activated")
 			return fs
 		}(),
 		"peeringConfig": func() *flag.FlagSet {
@@ -2,9 +2,11 @@ package pow
 
 import (
 	"context"
+	"strings"
 
 	"go.uber.org/dig"
 
+	"github.com/gohornet/hornet/pkg/model/syncmanager"
 	"github.com/gohornet/hornet/pkg/node"
 	"github.com/gohornet/hornet/pkg/pow"
 	"github.com/gohornet/hornet/pkg/shutdown"
@@ -39,11 +41,32 @@ func provide(c *dig.Container) {
 		dig.In
 		NodeConfig  *configuration.Configuration `name:"nodeConfig"`
 		MinPoWScore float64                      `name:"minPoWScore"`
+		PoWScoring  *pow.Scoring
+		SyncManager *syncmanager.SyncManager
 	}
 
 	if err := c.Provide(func(deps handlerDeps) *pow.Handler {
+		worker, backend, err := pow.NewBackendWorker(deps.NodeConfig.String(CfgPoWBackend))
+		if err != nil {
+			CorePlugin.LogPanicf("failed to create PoW backend: %s (available: %s)", err, strings.Join(pow.AvailableBackends(), ", "))
+		}
+		CorePlugin.LogInfof("using PoW backend %s (available: %s)", backend, strings.Join(pow.AvailableBackends(), ", "))
+
 		// init the pow handler with all possible settings
-		return pow.New(deps.MinPoWScore, deps.NodeConfig.Duration(CfgPoWRefreshTipsInterval))
+		return pow.New(
+			deps.MinPoWScore,
+			deps.NodeConfig.Duration(CfgPoWRefreshTipsInterval),
+			pow.WithLogger(CorePlugin.Logger()),
+			pow.WithTargetScoreFunc(func() float64 {
+				// mine for the scoring version that is active for the next milestone
+				return deps.PoWScoring.MinPoWScore(deps.SyncManager.ConfirmedMilestoneIndex() + 1)
+			}),
+			pow.WithLocalWorker(backend, worker),
+			pow.WithCPUBudget(deps.NodeConfig.Int(CfgPoWCPUBudget)),
+			pow.WithRemoteEndpoints(deps.NodeConfig.Strings(CfgPoWRemoteEndpoints)...),
+			pow.WithRemoteJobTimeout(deps.NodeConfig.Duration(CfgPoWRemoteJobTimeout)),
+			pow.WithRemoteHealthCheckInterval(deps.NodeConfig.Duration(CfgPoWRemoteHealthCheckInterval)),
+		)
 	}); err != nil {
 		CorePlugin.LogPanic(err)
 	}
@@ -54,7 +77,7 @@ func run() {
 	// close the PoW handler on shutdown
 	if err := CorePlugin.Daemon().BackgroundWorker("PoW Handler", func(ctx context.Context) {
 		CorePlugin.LogInfo("Starting PoW Handler ... done")
-		<-ctx.Done()
+		deps.Handler.Run(ctx)
 		CorePlugin.LogInfo("Stopping PoW Handler ...")
 		CorePlugin.LogInfo("Stopping PoW Handler ... done")
 	}, shutdown.PriorityPoWHandler); err != nil {
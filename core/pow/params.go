@@ -6,11 +6,22 @@ import (
 	flag "github.com/spf13/pflag"
 
 	"github.com/gohornet/hornet/pkg/node"
+	"github.com/gohornet/hornet/pkg/pow"
 )
 
 const (
 	// CfgPoWRefreshTipsInterval is the interval for refreshing tips during PoW for spammer messages and messages passed without parents via API.
 	CfgPoWRefreshTipsInterval = "pow.refreshTipsInterval"
+	// CfgPoWBackend is the backend used for local PoW ("auto" = the available backend with the highest priority).
+	CfgPoWBackend = "pow.backend"
+	// CfgPoWCPUBudget is the percentage of the CPU cores that may be used for local PoW by all concurrent PoW requests together.
+	CfgPoWCPUBudget = "pow.cpuBudget"
+	// CfgPoWRemoteEndpoints are the endpoints of the remote workers the PoW is offloaded to (empty = local PoW only).
+	CfgPoWRemoteEndpoints = "pow.remote.endpoints"
+	// CfgPoWRemoteJobTimeout is the maximum duration of a PoW job on a remote worker before the next worker or local PoW is used.
+	CfgPoWRemoteJobTimeout = "pow.remote.jobTimeout"
+	// CfgPoWRemoteHealthCheckInterval is the interval for checking the health of the remote workers.
+	CfgPoWRemoteHealthCheckInterval = "pow.remote.healthCheckInterval"
 )
 
 var params = &node.PluginParams{
@@ -18,6 +29,11 @@ var params = &node.PluginParams{
 		"nodeConfig": func() *flag.FlagSet {
 			fs := flag.NewFlagSet("", flag.ContinueOnError)
 			fs.Duration(CfgPoWRefreshTipsInterval, 5*time.Second, "interval for refreshing tips during PoW for spammer messages and messages passed without parents via API")
+			fs.String(CfgPoWBackend, pow.BackendAuto, "the backend used for local PoW (\"auto\" = the available backend with the highest priority)")
+			fs.Int(CfgPoWCPUBudget, 60, "the percentage of the CPU cores that may be used for local PoW by all concurrent PoW requests together")
+			fs.StringSlice(CfgPoWRemoteEndpoints, []string{}, "the endpoints of the remote workers the PoW is offloaded to (empty = local PoW only)")
+			fs.Duration(CfgPoWRemoteJobTimeout, 30*time.Second, "the maximum duration of a PoW job on a remote worker before the next worker or local PoW is used")
+			fs.Duration(CfgPoWRemoteHealthCheckInterval, 10*time.Second, "the interval for checking the health of the remote workers")
 			return fs
 		}(),
 	},
@@ -7,6 +7,7 @@ import (
 	"github.com/shirou/gopsutil/mem"
 	"go.uber.org/dig"
 
+	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/node"
 	"github.com/gohornet/hornet/pkg/profile"
 	"github.com/iotaledger/hive.go/configuration"
@@ -107,6 +108,9 @@ func loadProfile(nodeConfig *configuration.Configuration, profilesConfig *config
 		if err := profilesConfig.Unmarshal(profileName, p); err != nil {
 			CorePlugin.LogPanic(err)
 		}
+		if err := storage.ValidatePartitionKeys(p.Caches); err != nil {
+			CorePlugin.LogPanicf("profile '%s' is invalid: %s", profileName, err)
+		}
 		p.Name = profileName
 	}
 	return p
@@ -162,6 +166,7 @@ var (
 			},
 			IncomingMessagesFilter: &profile.CacheOpts{
 				CacheTime:                  "5s",
+				MaxSize:                    100000,
 				ReleaseExecutorWorkerCount: 10,
 				LeakDetectionOptions: &profile.LeakDetectionOpts{
 					Enabled:               false,
@@ -169,6 +174,9 @@ var (
 					MaxConsumerHoldTime:   "100s",
 				},
 			},
+			RequestQueue: &profile.RequestQueueOpts{
+				MaxSize: 100000,
+			},
 		},
 	}
 
@@ -221,6 +229,7 @@ var (
 			},
 			IncomingMessagesFilter: &profile.CacheOpts{
 				CacheTime:                  "5s",
+				MaxSize:                    50000,
 				ReleaseExecutorWorkerCount: 10,
 				LeakDetectionOptions: &profile.LeakDetectionOpts{
 					Enabled:               false,
@@ -228,6 +237,9 @@ var (
 					MaxConsumerHoldTime:   "100s",
 				},
 			},
+			RequestQueue: &profile.RequestQueueOpts{
+				MaxSize: 50000,
+			},
 		},
 	}
 
@@ -280,6 +292,7 @@ var (
 			},
 			IncomingMessagesFilter: &profile.CacheOpts{
 				CacheTime:                  "2.5s",
+				MaxSize:                    25000,
 				ReleaseExecutorWorkerCount: 10,
 				LeakDetectionOptions: &profile.LeakDetectionOpts{
 					Enabled:               false,
@@ -287,6 +300,9 @@ var (
 					MaxConsumerHoldTime:   "100s",
 				},
 			},
+			RequestQueue: &profile.RequestQueueOpts{
+				MaxSize: 25000,
+			},
 		},
 	}
 
@@ -339,6 +355,7 @@ var (
 			},
 			IncomingMessagesFilter: &profile.CacheOpts{
 				CacheTime:                  "2s",
+				MaxSize:                    10000,
 				ReleaseExecutorWorkerCount: 10,
 				LeakDetectionOptions: &profile.LeakDetectionOpts{
 					Enabled:               false,
@@ -346,6 +363,9 @@ var (
 					MaxConsumerHoldTime:   "100s",
 				},
 			},
+			RequestQueue: &profile.RequestQueueOpts{
+				MaxSize: 10000,
+			},
 		},
 	}
 )
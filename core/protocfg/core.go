@@ -8,6 +8,7 @@ import (
 
 	"github.com/gohornet/hornet/pkg/model/coordinator"
 	"github.com/gohornet/hornet/pkg/node"
+	"github.com/gohornet/hornet/pkg/pow"
 	"github.com/iotaledger/hive.go/configuration"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
@@ -44,7 +45,8 @@ func initConfigPars(c *dig.Container) {
 		NetworkIDName             string               `name:"networkIdName"`
 		Bech32HRP                 iotago.NetworkPrefix `name:"bech32HRP"`
 		MinPoWScore               float64              `name:"minPoWScore"`
-		MilestonePublicKeyCount   int                  `name:"milestonePublicKeyCount"`
+		PoWScoring                *pow.Scoring
+		MilestonePublicKeyCount   int `name:"milestonePublicKeyCount"`
 		DeSerializationParameters *iotago.DeSerializationParameters
 	}
 
@@ -65,6 +67,28 @@ func initConfigPars(c *dig.Container) {
 			},
 		}
 
+		// without configured scoring ranges, the minimum PoW score is required with the scoring of the IOTA protocol.
+		if err := deps.NodeConfig.SetDefault(CfgProtocolPoWScoring, pow.ScoringRanges{
+			{
+				Scorer:      pow.ScorerCurlP81,
+				MinPoWScore: res.MinPoWScore,
+				StartIndex:  0,
+			},
+		}); err != nil {
+			CorePlugin.LogPanic(err)
+		}
+
+		var scoringRanges pow.ScoringRanges
+		if err := deps.NodeConfig.Unmarshal(CfgProtocolPoWScoring, &scoringRanges); err != nil {
+			CorePlugin.LogPanic(err)
+		}
+
+		scoring, err := pow.NewScoring(scoringRanges)
+		if err != nil {
+			CorePlugin.LogPanicf("invalid PoW scoring ranges: %s", err)
+		}
+		res.PoWScoring = scoring
+
 		if *cooPubKeyRangesFlag != "" {
 			// load from special CLI flag
 			if err := json.Unmarshal([]byte(*cooPubKeyRangesFlag), &res.PublicKeyRanges); err != nil {
@@ -14,6 +14,8 @@ const (
 	CfgProtocolBech32HRP = "protocol.bech32HRP"
 	// the minimum PoW score required by the network.
 	CfgProtocolMinPoWScore = "protocol.minPoWScore"
+	// the scorers and minimum PoW scores required by the network from a milestone index on (default: minPoWScore with the IOTA scoring).
+	CfgProtocolPoWScoring = "protocol.powScoring"
 	// the amount of public keys in a milestone.
 	CfgProtocolMilestonePublicKeyCount = "protocol.milestonePublicKeyCount"
 	// the ed25519 public key of the coordinator in hex representation.
@@ -3,6 +3,7 @@ package snapshot
 import (
 	"context"
 	"os"
+	"runtime"
 
 	"github.com/labstack/gommon/bytes"
 	flag "github.com/spf13/pflag"
@@ -176,7 +177,38 @@ func provide(c *dig.Container) {
 			CorePlugin.LogPanicf("%s has to be specified if %s is enabled", CfgPruningSizeTargetSize, CfgPruningSizeEnabled)
 		}
 
-		return snapshot.NewSnapshotManager(
+		schedule := &snapshot.Schedule{
+			Policy:   deps.NodeConfig.String(CfgSnapshotsSchedulePolicy),
+			Interval: milestone.Index(deps.NodeConfig.Int(CfgSnapshotsInterval)),
+			Cron:     deps.NodeConfig.String(CfgSnapshotsScheduleCron),
+		}
+		if schedule.LedgerGrowthBytes, err = bytes.Parse(deps.NodeConfig.String(CfgSnapshotsScheduleLedgerGrowth)); err != nil {
+			CorePlugin.LogPanicf("parameter %s invalid", CfgSnapshotsScheduleLedgerGrowth)
+		}
+
+		pruningThrottle := snapshot.PruningThrottle{
+			MilestonesPerMinute: deps.NodeConfig.Int(CfgPruningThrottleMilestonesPerMinute),
+		}
+		if pruningThrottle.MaxBytesPerSecond, err = bytes.Parse(deps.NodeConfig.String(CfgPruningThrottleMaxBytesPerSecond)); err != nil {
+			CorePlugin.LogPanicf("parameter %s invalid", CfgPruningThrottleMaxBytesPerSecond)
+		}
+
+		workerCount := deps.NodeConfig.Int(CfgSnapshotsWorkerCount)
+		if workerCount < 0 {
+			CorePlugin.LogPanicf("parameter %s invalid", CfgSnapshotsWorkerCount)
+		}
+		if workerCount == 0 {
+			workerCount = runtime.NumCPU()
+		}
+
+		var encryptionKey string
+		if deps.NodeConfig.Bool(CfgSnapshotsEncryptionEnabled) {
+			if encryptionKey, err = snapshot.LoadEncryptionKeyFromEnvironment(); err != nil {
+				CorePlugin.LogPanicf("parameter %s enabled: %s", CfgSnapshotsEncryptionEnabled, err)
+			}
+		}
+
+		snapshotManager, err := snapshot.NewSnapshotManager(
 			CorePlugin.Logger(),
 			deps.TangleDatabase,
 			deps.UTXODatabase,
@@ -189,20 +221,51 @@ func provide(c *dig.Container) {
 			deps.SnapshotsFullPath,
 			deps.SnapshotsDeltaPath,
 			deps.NodeConfig.Float64(CfgSnapshotsDeltaSizeThresholdPercentage),
+			encryptionKey,
+			workerCount,
 			downloadTargets,
 			solidEntryPointCheckThresholdPast,
 			solidEntryPointCheckThresholdFuture,
 			pruningThreshold,
 			snapshotDepth,
-			milestone.Index(deps.NodeConfig.Int(CfgSnapshotsInterval)),
+			schedule,
 			pruningMilestonesEnabled,
 			pruningMilestonesMaxMilestonesToKeep,
 			pruningSizeEnabled,
 			pruningTargetDatabaseSizeBytes,
 			deps.NodeConfig.Float64(CfgPruningSizeThresholdPercentage),
 			deps.NodeConfig.Duration(CfgPruningSizeCooldownTime),
+			pruningThrottle,
 			deps.PruningPruneReceipts,
+			deps.NodeConfig.Int(CfgPruningBatchSize),
 		)
+		if err != nil {
+			CorePlugin.LogPanicf("creating snapshot manager failed: %s", err)
+		}
+
+		if err := deps.NodeConfig.SetDefault(CfgSnapshotsTargets, []snapshot.S3TargetConfig{}); err != nil {
+			CorePlugin.LogPanic(err)
+		}
+
+		var targetConfigs []*snapshot.S3TargetConfig
+		if err := deps.NodeConfig.Unmarshal(CfgSnapshotsTargets, &targetConfigs); err != nil {
+			CorePlugin.LogPanic(err)
+		}
+
+		for _, targetConfig := range targetConfigs {
+			target, err := snapshot.NewS3Target(targetConfig)
+			if err != nil {
+				CorePlugin.LogPanic(err)
+			}
+			if err := snapshotManager.AddTarget(target, targetConfig.Automatic); err != nil {
+				CorePlugin.LogPanic(err)
+			}
+			CorePlugin.LogInfof("snapshot target: %s (bucket: %s, automatic: %v)", targetConfig.Name, targetConfig.Bucket, targetConfig.Automatic)
+		}
+
+		CorePlugin.LogInfof("snapshot schedule policy: %s", schedule.Policy)
+
+		return snapshotManager
 	}); err != nil {
 		CorePlugin.LogPanic(err)
 	}
@@ -266,4 +329,33 @@ func run() {
 	}, shutdown.PrioritySnapshots); err != nil {
 		CorePlugin.LogPanicf("failed to start worker: %s", err)
 	}
+
+	newConfirmedMilestonePruningSignal := make(chan milestone.Index)
+	onConfirmedMilestoneIndexChangedPruning := events.NewClosure(func(msIndex milestone.Index) {
+		select {
+		case newConfirmedMilestonePruningSignal <- msIndex:
+		default:
+		}
+	})
+
+	if err := CorePlugin.Daemon().BackgroundWorker("Pruning", func(ctx context.Context) {
+		CorePlugin.LogInfo("Starting Pruning ... done")
+
+		deps.Tangle.Events.ConfirmedMilestoneIndexChanged.Attach(onConfirmedMilestoneIndexChangedPruning)
+		defer deps.Tangle.Events.ConfirmedMilestoneIndexChanged.Detach(onConfirmedMilestoneIndexChangedPruning)
+
+		for {
+			select {
+			case <-ctx.Done():
+				CorePlugin.LogInfo("Stopping Pruning...")
+				CorePlugin.LogInfo("Stopping Pruning... done")
+				return
+
+			case confirmedMilestoneIndex := <-newConfirmedMilestonePruningSignal:
+				deps.SnapshotManager.PruneDatabaseForConfirmedMilestone(ctx, confirmedMilestoneIndex)
+			}
+		}
+	}, shutdown.PrioritySnapshots); err != nil {
+		CorePlugin.LogPanicf("failed to start worker: %s", err)
+	}
 }
@@ -6,6 +6,7 @@ import (
 	flag "github.com/spf13/pflag"
 
 	"github.com/gohornet/hornet/pkg/node"
+	"github.com/gohornet/hornet/pkg/snapshot"
 )
 
 const (
@@ -13,6 +14,12 @@ const (
 	CfgSnapshotsDepth = "snapshots.depth"
 	// interval, in milestones, at which snapshot files are created (snapshots are only created if the node is synced)
 	CfgSnapshotsInterval = "snapshots.interval"
+	// the policy that triggers the creation of snapshot files ("interval", "cron" or "ledgerGrowth")
+	CfgSnapshotsSchedulePolicy = "snapshots.schedule.policy"
+	// the cron expression in the local time of the node at which snapshot files are created (policy "cron")
+	CfgSnapshotsScheduleCron = "snapshots.schedule.cron"
+	// the growth of the ledger database at which snapshot files are created (policy "ledgerGrowth")
+	CfgSnapshotsScheduleLedgerGrowth = "snapshots.schedule.ledgerGrowth"
 	// path to the full snapshot file
 	CfgSnapshotsFullPath = "snapshots.fullPath"
 	// path to the delta snapshot file
@@ -20,8 +27,14 @@ const (
 	// create a full snapshot if the size of a delta snapshot reaches a certain percentage of the full snapshot
 	// (0.0 = always create delta snapshot to keep ms diff history)
 	CfgSnapshotsDeltaSizeThresholdPercentage = "snapshots.deltaSizeThresholdPercentage"
+	// the amount of workers that load and serialize the ledger while a snapshot is created (0 = amount of CPU cores)
+	CfgSnapshotsWorkerCount = "snapshots.workerCount"
+	// whether the created snapshot files are encrypted with the key from the SNAPSHOT_ENCRYPTION_KEY environment variable
+	CfgSnapshotsEncryptionEnabled = "snapshots.encryption.enabled"
 	// URLs to load the snapshot files from.
 	CfgSnapshotsDownloadURLs = "snapshots.downloadURLs"
+	// S3-compatible object storages the snapshots can be streamed to.
+	CfgSnapshotsTargets = "snapshots.targets"
 	// whether to delete old message data from the database based on maximum milestones to keep
 	CfgPruningMilestonesEnabled = "pruning.milestones.enabled"
 	// maximum amount of milestone cones to keep in the database
@@ -34,8 +47,14 @@ const (
 	CfgPruningSizeThresholdPercentage = "pruning.size.thresholdPercentage"
 	// cooldown time between two pruning by database size events
 	CfgPruningSizeCooldownTime = "pruning.size.cooldownTime"
+	// the maximum amount of milestones that are pruned per minute (0 = unlimited)
+	CfgPruningThrottleMilestonesPerMinute = "pruning.throttle.milestonesPerMinute"
+	// the maximum amount of message data that is deleted per second while pruning (0 = unlimited)
+	CfgPruningThrottleMaxBytesPerSecond = "pruning.throttle.maxBytesPerSecond"
 	// whether to delete old receipts data from the database
 	CfgPruningPruneReceipts = "pruning.pruneReceipts"
+	// the maximum amount of keys that are deleted from the database in a single batch while pruning
+	CfgPruningBatchSize = "pruning.batchSize"
 )
 
 var params = &node.PluginParams{
@@ -44,18 +63,26 @@ var params = &node.PluginParams{
 			fs := flag.NewFlagSet("", flag.ContinueOnError)
 			fs.Int(CfgSnapshotsDepth, 50, "the depth, respectively the starting point, at which a snapshot of the ledger is generated")
 			fs.Int(CfgSnapshotsInterval, 200, "interval, in milestones, at which snapshot files are created (snapshots are only created if the node is synced)")
+			fs.String(CfgSnapshotsSchedulePolicy, snapshot.SchedulePolicyInterval, "the policy that triggers the creation of snapshot files (\"interval\", \"cron\" or \"ledgerGrowth\")")
+			fs.String(CfgSnapshotsScheduleCron, "0 3 * * *", "the cron expression in the local time of the node at which snapshot files are created (policy \"cron\")")
+			fs.String(CfgSnapshotsScheduleLedgerGrowth, "1GB", "the growth of the ledger database at which snapshot files are created (policy \"ledgerGrowth\")")
 			fs.String(CfgSnapshotsFullPath, "snapshots/mainnet/full_snapshot.bin", "path to the full snapshot file")
 			fs.String(CfgSnapshotsDeltaPath, "snapshots/mainnet/delta_snapshot.bin", "path to the delta snapshot file")
 			fs.Float64(CfgSnapshotsDeltaSizeThresholdPercentage, 50.0, "create a full snapshot if the size of a delta snapshot reaches a certain percentage of the full snapshot (0.0 = always create delta snapshot to keep ms diff history)")
+			fs.Int(CfgSnapshotsWorkerCount, 0, "the amount of workers that load and serialize the ledger while a snapshot is created (0 = amount of CPU cores)")
+			fs.Bool(CfgSnapshotsEncryptionEnabled, false, "whether the created snapshot files are encrypted with the key from the SNAPSHOT_ENCRYPTION_KEY environment variable")
 			fs.Bool(CfgPruningMilestonesEnabled, false, "whether to delete old message data from the database based on maximum milestones to keep")
 			fs.Int(CfgPruningMilestonesMaxMilestonesToKeep, 60480, "maximum amount of milestone cones to keep in the database")
 			fs.Bool(CfgPruningSizeEnabled, true, "whether to delete old message data from the database based on maximum database size")
 			fs.String(CfgPruningSizeTargetSize, "30GB", "target size of the database")
 			fs.Float64(CfgPruningSizeThresholdPercentage, 10.0, "the percentage the database size gets reduced if the target size is reached")
 			fs.Duration(CfgPruningSizeCooldownTime, 5*time.Minute, "cooldown time between two pruning by database size events")
+			fs.Int(CfgPruningThrottleMilestonesPerMinute, 0, "the maximum amount of milestones that are pruned per minute (0 = unlimited)")
+			fs.String(CfgPruningThrottleMaxBytesPerSecond, "0", "the maximum amount of message data that is deleted per second while pruning (0 = unlimited)")
 			fs.Bool(CfgPruningPruneReceipts, false, "whether to delete old receipts data from the database")
+			fs.Int(CfgPruningBatchSize, 10000, "the maximum amount of keys that are deleted from the database in a single batch while pruning")
 			return fs
 		}(),
 	},
-	Masked: nil,
+	Masked: []string{CfgSnapshotsTargets},
 }
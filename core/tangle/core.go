@@ -33,6 +33,9 @@ const (
 	CfgTangleSyncedAtStartup = "syncedAtStartup"
 	// whether to revalidate the database on startup if corrupted
 	CfgTangleRevalidateDatabase = "revalidate"
+
+	// the amount of points that are kept per custom metric series for new dashboard clients.
+	customMetricsPointsPerSeries = 100
 )
 
 func init() {
@@ -85,6 +88,12 @@ func provide(c *dig.Container) {
 		CorePlugin.LogPanic(err)
 	}
 
+	if err := c.Provide(func() *metrics.CustomMetrics {
+		return metrics.NewCustomMetrics(customMetricsPointsPerSeries)
+	}); err != nil {
+		CorePlugin.LogPanic(err)
+	}
+
 	type milestoneManagerDeps struct {
 		dig.In
 		Storage                 *storage.Storage
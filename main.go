@@ -12,10 +12,12 @@ import (
 	"github.com/gohornet/hornet/core/snapshot"
 	"github.com/gohornet/hornet/core/tangle"
 	"github.com/gohornet/hornet/pkg/node"
+	"github.com/gohornet/hornet/plugins/alerting"
 	"github.com/gohornet/hornet/plugins/autopeering"
 	"github.com/gohornet/hornet/plugins/coordinator"
 	"github.com/gohornet/hornet/plugins/dashboard"
 	"github.com/gohornet/hornet/plugins/debug"
+	"github.com/gohornet/hornet/plugins/expirationwatcher"
 	"github.com/gohornet/hornet/plugins/faucet"
 	"github.com/gohornet/hornet/plugins/indexer"
 	"github.com/gohornet/hornet/plugins/migrator"
@@ -26,7 +28,9 @@ import (
 	"github.com/gohornet/hornet/plugins/receipt"
 	"github.com/gohornet/hornet/plugins/restapi"
 	restapiv2 "github.com/gohornet/hornet/plugins/restapi/v2"
+	"github.com/gohornet/hornet/plugins/slamonitor"
 	"github.com/gohornet/hornet/plugins/spammer"
+	"github.com/gohornet/hornet/plugins/standby"
 	"github.com/gohornet/hornet/plugins/urts"
 	"github.com/gohornet/hornet/plugins/versioncheck"
 	"github.com/gohornet/hornet/plugins/warpsync"
@@ -61,10 +65,14 @@ func main() {
 			migrator.Plugin,
 			receipt.Plugin,
 			prometheus.Plugin,
+			slamonitor.Plugin,
+			alerting.Plugin,
+			standby.Plugin,
 			debug.Plugin,
 			faucet.Plugin,
 			participation.Plugin,
 			indexer.Plugin,
+			expirationwatcher.Plugin,
 		}...),
 	)
 }
@@ -0,0 +1,18 @@
+package alerting
+
+import (
+	"github.com/iotaledger/hive.go/events"
+)
+
+// Events are the events issued by the alert manager.
+type Events struct {
+	// Fired when the condition of a rule held for the configured duration.
+	AlertFired *events.Event
+	// Fired when the condition of a rule of a fired alert doesn't hold anymore.
+	AlertResolved *events.Event
+}
+
+// AlertCaller is used to signal an Alert.
+func AlertCaller(handler interface{}, params ...interface{}) {
+	handler.(func(*Alert))(params[0].(*Alert))
+}
@@ -0,0 +1,262 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/events"
+)
+
+const (
+	// MetricSynced is 1 if the node is synced and 0 otherwise.
+	MetricSynced = "synced"
+	// MetricConnectedPeers is the amount of connected peers.
+	MetricConnectedPeers = "connectedPeers"
+	// MetricReferencedRate is the referenced rate of the latest confirmed milestone in percent.
+	MetricReferencedRate = "referencedRate"
+	// MetricMilestoneLag is the amount of milestones the confirmed milestone is behind the latest milestone.
+	MetricMilestoneLag = "milestoneLag"
+)
+
+var (
+	// the metrics the rules can be defined for.
+	knownMetrics = map[string]struct{}{
+		MetricSynced:         {},
+		MetricConnectedPeers: {},
+		MetricReferencedRate: {},
+		MetricMilestoneLag:   {},
+	}
+)
+
+// Operator compares the value of a metric to the threshold of a rule.
+type Operator string
+
+const (
+	// OperatorLess matches if the value is below the threshold.
+	OperatorLess Operator = "<"
+	// OperatorGreater matches if the value is above the threshold.
+	OperatorGreater Operator = ">"
+)
+
+// Rule defines the condition of an alert, e.g. "connectedPeers < 2 for 5m".
+type Rule struct {
+	// The unique name of the rule.
+	Name string `json:"name" koanf:"name"`
+	// The metric the rule is evaluated for.
+	Metric string `json:"metric" koanf:"metric"`
+	// The operator the value of the metric is compared to the threshold with ("<" or ">").
+	Operator Operator `json:"operator" koanf:"operator"`
+	// The threshold of the metric.
+	Threshold float64 `json:"threshold" koanf:"threshold"`
+	// How long the condition has to hold before the alert fires.
+	For time.Duration `json:"for" koanf:"for"`
+}
+
+// matches tells whether the condition of the rule holds for the given value.
+func (r *Rule) matches(value float64) bool {
+	switch r.Operator {
+	case OperatorLess:
+		return value < r.Threshold
+	case OperatorGreater:
+		return value > r.Threshold
+	default:
+		return false
+	}
+}
+
+func (r *Rule) validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("rule name missing")
+	}
+	if _, known := knownMetrics[r.Metric]; !known {
+		return fmt.Errorf("unknown metric of rule %s: %s", r.Name, r.Metric)
+	}
+	if r.Operator != OperatorLess && r.Operator != OperatorGreater {
+		return fmt.Errorf("unknown operator of rule %s: %s", r.Name, r.Operator)
+	}
+	if r.For < 0 {
+		return fmt.Errorf("negative duration of rule %s: %v", r.Name, r.For)
+	}
+	return nil
+}
+
+// Alert is the state of a rule whose condition holds.
+type Alert struct {
+	// The rule of the alert.
+	Rule *Rule
+	// The latest value of the metric of the rule.
+	Value float64
+	// Since when the condition of the rule holds.
+	Since time.Time
+	// When the alert fired, zero if it is still pending.
+	FiredAt time.Time
+	// When the alert was resolved, zero if it is still active.
+	ResolvedAt time.Time
+}
+
+func (a *Alert) MarshalJSON() ([]byte, error) {
+	unixOrZero := func(t time.Time) int64 {
+		if t.IsZero() {
+			return 0
+		}
+		return t.Unix()
+	}
+
+	return json.Marshal(struct {
+		Name       string   `json:"name"`
+		Metric     string   `json:"metric"`
+		Operator   Operator `json:"operator"`
+		Threshold  float64  `json:"threshold"`
+		For        string   `json:"for"`
+		Value      float64  `json:"value"`
+		Since      int64    `json:"since"`
+		FiredAt    int64    `json:"firedAt,omitempty"`
+		ResolvedAt int64    `json:"resolvedAt,omitempty"`
+	}{
+		Name:       a.Rule.Name,
+		Metric:     a.Rule.Metric,
+		Operator:   a.Rule.Operator,
+		Threshold:  a.Rule.Threshold,
+		For:        a.Rule.For.String(),
+		Value:      a.Value,
+		Since:      a.Since.Unix(),
+		FiredAt:    unixOrZero(a.FiredAt),
+		ResolvedAt: unixOrZero(a.ResolvedAt),
+	})
+}
+
+// Fired tells whether the condition held for the duration of the rule.
+func (a *Alert) Fired() bool {
+	return !a.FiredAt.IsZero()
+}
+
+// Manager evaluates the alert rules against the metrics of the node
+// and fires events if the condition of a rule held for its duration or doesn't hold anymore.
+type Manager struct {
+	// Events are the events issued by the manager.
+	Events *Events
+
+	rules []*Rule
+
+	sync.RWMutex
+	// the alerts of the rules whose condition holds, by rule name.
+	alerts map[string]*Alert
+}
+
+// NewManager creates a new Manager for the given rules.
+func NewManager(rules []*Rule) (*Manager, error) {
+	names := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return nil, err
+		}
+		if _, exists := names[rule.Name]; exists {
+			return nil, fmt.Errorf("duplicate rule name: %s", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+	}
+
+	return &Manager{
+		Events: &Events{
+			AlertFired:    events.NewEvent(AlertCaller),
+			AlertResolved: events.NewEvent(AlertCaller),
+		},
+		rules:  rules,
+		alerts: make(map[string]*Alert),
+	}, nil
+}
+
+// Rules returns the rules of the manager.
+func (m *Manager) Rules() []*Rule {
+	return m.rules
+}
+
+// Evaluate evaluates the rules against the given values of the metrics at the given time.
+// Rules whose metric is missing in the values are not evaluated.
+// AlertFired is fired for every rule whose condition held for its duration, AlertResolved for every fired alert whose condition doesn't hold anymore.
+func (m *Manager) Evaluate(now time.Time, values map[string]float64) {
+	fired, resolved := m.evaluate(now, values)
+
+	for _, alert := range fired {
+		m.Events.AlertFired.Trigger(alert)
+	}
+	for _, alert := range resolved {
+		m.Events.AlertResolved.Trigger(alert)
+	}
+}
+
+func (m *Manager) evaluate(now time.Time, values map[string]float64) (fired []*Alert, resolved []*Alert) {
+	m.Lock()
+	defer m.Unlock()
+
+	for _, rule := range m.rules {
+		value, has := values[rule.Metric]
+		if !has {
+			continue
+		}
+
+		alert, active := m.alerts[rule.Name]
+		if !rule.matches(value) {
+			if !active {
+				continue
+			}
+
+			delete(m.alerts, rule.Name)
+			if alert.Fired() {
+				alert.Value = value
+				alert.ResolvedAt = now
+				resolved = append(resolved, alert)
+			}
+			continue
+		}
+
+		if !active {
+			alert = &Alert{Rule: rule, Since: now}
+			m.alerts[rule.Name] = alert
+		}
+		alert.Value = value
+
+		if !alert.Fired() && now.Sub(alert.Since) >= rule.For {
+			alert.FiredAt = now
+			fired = append(fired, alert)
+		}
+	}
+
+	// copies are handed out, because the alerts are updated by the next evaluation
+	return copyAlerts(fired), copyAlerts(resolved)
+}
+
+func copyAlerts(alerts []*Alert) []*Alert {
+	copies := make([]*Alert, len(alerts))
+	for i, alert := range alerts {
+		alertCopy := *alert
+		copies[i] = &alertCopy
+	}
+	return copies
+}
+
+// ActiveAlerts returns the alerts that fired and are not resolved yet, ordered by the time they fired.
+func (m *Manager) ActiveAlerts() []*Alert {
+	m.RLock()
+	defer m.RUnlock()
+
+	var active []*Alert
+	for _, alert := range m.alerts {
+		if alert.Fired() {
+			active = append(active, alert)
+		}
+	}
+	active = copyAlerts(active)
+
+	sort.Slice(active, func(i, j int) bool {
+		if active[i].FiredAt.Equal(active[j].FiredAt) {
+			return active[i].Rule.Name < active[j].Rule.Name
+		}
+		return active[i].FiredAt.Before(active[j].FiredAt)
+	})
+
+	return active
+}
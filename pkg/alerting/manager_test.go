@@ -0,0 +1,88 @@
+package alerting_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/alerting"
+	"github.com/iotaledger/hive.go/events"
+)
+
+func TestManager(t *testing.T) {
+
+	manager, err := alerting.NewManager([]*alerting.Rule{
+		{Name: "not synced", Metric: alerting.MetricSynced, Operator: alerting.OperatorLess, Threshold: 1, For: 5 * time.Minute},
+		{Name: "few peers", Metric: alerting.MetricConnectedPeers, Operator: alerting.OperatorLess, Threshold: 2},
+	})
+	require.NoError(t, err)
+
+	var fired, resolved []*alerting.Alert
+	manager.Events.AlertFired.Attach(events.NewClosure(func(alert *alerting.Alert) {
+		fired = append(fired, alert)
+	}))
+	manager.Events.AlertResolved.Attach(events.NewClosure(func(alert *alerting.Alert) {
+		resolved = append(resolved, alert)
+	}))
+
+	start := time.Unix(1000, 0)
+
+	// a rule without duration fires right away
+	manager.Evaluate(start, map[string]float64{alerting.MetricSynced: 0, alerting.MetricConnectedPeers: 1})
+	require.Len(t, fired, 1)
+	require.Equal(t, "few peers", fired[0].Rule.Name)
+	require.Equal(t, 1.0, fired[0].Value)
+
+	// the alert of the other rule is pending until the condition held for its duration
+	manager.Evaluate(start.Add(4*time.Minute), map[string]float64{alerting.MetricSynced: 0, alerting.MetricConnectedPeers: 0})
+	require.Len(t, fired, 1)
+	require.Len(t, manager.ActiveAlerts(), 1)
+
+	manager.Evaluate(start.Add(5*time.Minute), map[string]float64{alerting.MetricSynced: 0, alerting.MetricConnectedPeers: 0})
+	require.Len(t, fired, 2)
+	require.Equal(t, "not synced", fired[1].Rule.Name)
+	require.Equal(t, start, fired[1].Since)
+
+	// alerts only fire once
+	manager.Evaluate(start.Add(6*time.Minute), map[string]float64{alerting.MetricSynced: 0, alerting.MetricConnectedPeers: 0})
+	require.Len(t, fired, 2)
+
+	active := manager.ActiveAlerts()
+	require.Len(t, active, 2)
+	require.Equal(t, "few peers", active[0].Rule.Name)
+	require.Equal(t, 0.0, active[0].Value)
+	require.Equal(t, "not synced", active[1].Rule.Name)
+
+	// missing metrics are not evaluated
+	manager.Evaluate(start.Add(7*time.Minute), map[string]float64{alerting.MetricConnectedPeers: 3})
+	require.Len(t, resolved, 1)
+	require.Equal(t, "few peers", resolved[0].Rule.Name)
+	require.Equal(t, start.Add(7*time.Minute), resolved[0].ResolvedAt)
+	require.Len(t, manager.ActiveAlerts(), 1)
+
+	// the duration starts again after the condition didn't hold
+	manager.Evaluate(start.Add(8*time.Minute), map[string]float64{alerting.MetricSynced: 1})
+	require.Len(t, resolved, 2)
+	manager.Evaluate(start.Add(9*time.Minute), map[string]float64{alerting.MetricSynced: 0})
+	manager.Evaluate(start.Add(10*time.Minute), map[string]float64{alerting.MetricSynced: 0})
+	require.Len(t, fired, 2)
+	require.Empty(t, manager.ActiveAlerts())
+}
+
+func TestManagerInvalidRules(t *testing.T) {
+
+	for _, rules := range [][]*alerting.Rule{
+		{{Metric: alerting.MetricSynced, Operator: alerting.OperatorLess}},
+		{{Name: "unknown metric", Metric: "unknown", Operator: alerting.OperatorLess}},
+		{{Name: "unknown operator", Metric: alerting.MetricSynced, Operator: "=="}},
+		{{Name: "negative duration", Metric: alerting.MetricSynced, Operator: alerting.OperatorLess, For: -time.Second}},
+		{
+			{Name: "duplicate", Metric: alerting.MetricSynced, Operator: alerting.OperatorLess},
+			{Name: "duplicate", Metric: alerting.MetricConnectedPeers, Operator: alerting.OperatorLess},
+		},
+	} {
+		_, err := alerting.NewManager(rules)
+		require.Error(t, err)
+	}
+}
@@ -7,6 +7,10 @@ const (
 	StorePrefixChildren             byte = 4
 	StorePrefixSnapshot             byte = 5
 	StorePrefixUnreferencedMessages byte = 6
+	StorePrefixArchiveInfo          byte = 8
+	StorePrefixPinnedMessages       byte = 9
+	StorePrefixMessageAnnotations   byte = 10
+	StorePrefixMetricsHistory       byte = 11
 	StorePrefixHealth               byte = 255
 )
 
@@ -0,0 +1,18 @@
+package expiration
+
+import (
+	"github.com/iotaledger/hive.go/events"
+)
+
+// Events are the events issued by the expiration watcher.
+type Events struct {
+	// Fired when an output enters the lead window before its expiration.
+	ExpiringSoon *events.Event
+	// Fired when the ownership of an output flipped to the return address.
+	Expired *events.Event
+}
+
+// NotificationCaller is used to signal a Notification.
+func NotificationCaller(handler interface{}, params ...interface{}) {
+	handler.(func(*Notification))(params[0].(*Notification))
+}
@@ -0,0 +1,242 @@
+package expiration
+
+import (
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/iotaledger/hive.go/events"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// Event is the kind of a notification about an expiring output.
+type Event string
+
+const (
+	// EventExpiringSoon is issued when an output enters the lead window before its expiration.
+	EventExpiringSoon Event = "expiringSoon"
+	// EventExpired is issued when the ownership of an output flipped to the return address.
+	EventExpired Event = "expired"
+)
+
+// Notification is issued for an unspent output with an expiration unlock condition.
+type Notification struct {
+	// The kind of the notification.
+	Event Event
+	// The ID of the output.
+	OutputID iotago.OutputID
+	// The address which owns the output until it expires, nil if the output is not owned by an address unlock condition.
+	Address iotago.Address
+	// The address which owns the output after it expired.
+	ReturnAddress iotago.Address
+	// The milestone index at which the output expires, zero if not set.
+	MilestoneIndex milestone.Index
+	// The unix time at which the output expires, zero if not set.
+	UnixTime uint32
+	// The confirmed milestone index at which the notification was issued.
+	ConfirmedMilestoneIndex milestone.Index
+}
+
+// JSONNotification is the JSON representation of a Notification.
+type JSONNotification struct {
+	// The kind of the notification.
+	Event Event `json:"event"`
+	// The hex encoded ID of the output.
+	OutputID string `json:"outputId"`
+	// The bech32 encoded address which owns the output until it expires.
+	Address string `json:"address,omitempty"`
+	// The bech32 encoded address which owns the output after it expired.
+	ReturnAddress string `json:"returnAddress"`
+	// The milestone index at which the output expires.
+	MilestoneIndex milestone.Index `json:"milestoneIndex,omitempty"`
+	// The unix time at which the output expires.
+	UnixTime uint32 `json:"unixTime,omitempty"`
+	// The confirmed milestone index at which the notification was issued.
+	ConfirmedMilestoneIndex milestone.Index `json:"confirmedMilestoneIndex"`
+}
+
+// JSON returns the JSON representation of the notification with the addresses encoded for the given network.
+func (n *Notification) JSON(bech32HRP iotago.NetworkPrefix) *JSONNotification {
+	result := &JSONNotification{
+		Event:                   n.Event,
+		OutputID:                hex.EncodeToString(n.OutputID[:]),
+		ReturnAddress:           n.ReturnAddress.Bech32(bech32HRP),
+		MilestoneIndex:          n.MilestoneIndex,
+		UnixTime:                n.UnixTime,
+		ConfirmedMilestoneIndex: n.ConfirmedMilestoneIndex,
+	}
+	if n.Address != nil {
+		result.Address = n.Address.Bech32(bech32HRP)
+	}
+	return result
+}
+
+// an unspent output with an expiration unlock condition.
+type watchedOutput struct {
+	outputID       iotago.OutputID
+	address        iotago.Address
+	returnAddress  iotago.Address
+	milestoneIndex milestone.Index
+	unixTime       uint32
+	// whether the ExpiringSoon event was already issued.
+	notified bool
+}
+
+// expiredAt tells whether the output is expired at the given milestone.
+// If both conditions are set, both need to be met.
+func (o *watchedOutput) expiredAt(index milestone.Index, timestamp uint32) bool {
+	return (o.milestoneIndex == 0 || o.milestoneIndex <= index) &&
+		(o.unixTime == 0 || o.unixTime <= timestamp)
+}
+
+func (o *watchedOutput) notification(event Event, confirmedMilestoneIndex milestone.Index) *Notification {
+	return &Notification{
+		Event:                   event,
+		OutputID:                o.outputID,
+		Address:                 o.address,
+		ReturnAddress:           o.returnAddress,
+		MilestoneIndex:          o.milestoneIndex,
+		UnixTime:                o.unixTime,
+		ConfirmedMilestoneIndex: confirmedMilestoneIndex,
+	}
+}
+
+// Watcher indexes the unspent outputs with an expiration unlock condition and
+// issues events shortly before and at the milestone at which the ownership flips to the return address.
+type Watcher struct {
+	// Events are the events issued by the watcher.
+	Events *Events
+
+	// the amount of milestones before the expiration milestone index at which ExpiringSoon is issued.
+	leadMilestones milestone.Index
+	// the duration before the expiration unix time at which ExpiringSoon is issued.
+	leadTime time.Duration
+
+	sync.Mutex
+	outputs map[iotago.OutputID]*watchedOutput
+}
+
+// NewWatcher creates a new Watcher which issues ExpiringSoon leadMilestones
+// milestones or leadTime before an output expires, whichever applies to its expiration unlock condition.
+func NewWatcher(leadMilestones milestone.Index, leadTime time.Duration) *Watcher {
+	return &Watcher{
+		Events: &Events{
+			ExpiringSoon: events.NewEvent(NotificationCaller),
+			Expired:      events.NewEvent(NotificationCaller),
+		},
+		leadMilestones: leadMilestones,
+		leadTime:       leadTime,
+		outputs:        make(map[iotago.OutputID]*watchedOutput),
+	}
+}
+
+// AddOutput adds the output to the watched outputs if it has an expiration unlock condition.
+func (w *Watcher) AddOutput(output *utxo.Output) {
+	w.Lock()
+	defer w.Unlock()
+
+	w.addOutputWithoutLocking(output)
+}
+
+func (w *Watcher) addOutputWithoutLocking(output *utxo.Output) {
+	unlockConditionOutput, ok := output.Output().(iotago.UnlockConditionOutput)
+	if !ok {
+		return
+	}
+
+	conditions, err := unlockConditionOutput.UnlockConditions().Set()
+	if err != nil {
+		return
+	}
+
+	expiration := conditions.Expiration()
+	if expiration == nil {
+		return
+	}
+
+	watched := &watchedOutput{
+		outputID:       *output.OutputID(),
+		returnAddress:  expiration.ReturnAddress,
+		milestoneIndex: milestone.Index(expiration.MilestoneIndex),
+		unixTime:       expiration.UnixTime,
+	}
+	if addressUnlock := conditions.Address(); addressUnlock != nil {
+		watched.address = addressUnlock.Address
+	}
+
+	w.outputs[watched.outputID] = watched
+}
+
+// ApplyLedgerUpdate adds the new outputs with an expiration unlock condition and removes the spent outputs.
+func (w *Watcher) ApplyLedgerUpdate(newOutputs utxo.Outputs, newSpents utxo.Spents) {
+	w.Lock()
+	defer w.Unlock()
+
+	for _, output := range newOutputs {
+		w.addOutputWithoutLocking(output)
+	}
+
+	for _, spent := range newSpents {
+		delete(w.outputs, *spent.OutputID())
+	}
+}
+
+// Count returns the amount of watched outputs.
+func (w *Watcher) Count() int {
+	w.Lock()
+	defer w.Unlock()
+
+	return len(w.outputs)
+}
+
+// RemoveExpired removes the outputs which are already expired at the given milestone without issuing events.
+// It returns the amount of removed outputs.
+func (w *Watcher) RemoveExpired(index milestone.Index, timestamp uint32) int {
+	w.Lock()
+	defer w.Unlock()
+
+	count := 0
+	for outputID, watched := range w.outputs {
+		if watched.expiredAt(index, timestamp) {
+			delete(w.outputs, outputID)
+			count++
+		}
+	}
+
+	return count
+}
+
+// CheckMilestone issues the events for the watched outputs at the given confirmed milestone.
+// Expired outputs are not watched anymore, since their ownership can't flip again.
+func (w *Watcher) CheckMilestone(index milestone.Index, timestamp uint32) {
+
+	leadIndex := index + w.leadMilestones
+	leadTimestamp := uint32(time.Unix(int64(timestamp), 0).Add(w.leadTime).Unix())
+
+	var expiringSoon, expired []*Notification
+
+	w.Lock()
+	for outputID, watched := range w.outputs {
+		if watched.expiredAt(index, timestamp) {
+			expired = append(expired, watched.notification(EventExpired, index))
+			delete(w.outputs, outputID)
+			continue
+		}
+
+		if !watched.notified && watched.expiredAt(leadIndex, leadTimestamp) {
+			expiringSoon = append(expiringSoon, watched.notification(EventExpiringSoon, index))
+			watched.notified = true
+		}
+	}
+	w.Unlock()
+
+	for _, notification := range expiringSoon {
+		w.Events.ExpiringSoon.Trigger(notification)
+	}
+
+	for _, notification := range expired {
+		w.Events.Expired.Trigger(notification)
+	}
+}
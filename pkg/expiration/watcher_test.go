@@ -0,0 +1,111 @@
+package expiration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/expiration"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	"github.com/iotaledger/hive.go/events"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func outputWithExpiration(address iotago.Address, returnAddress iotago.Address, index milestone.Index, unixTime uint32) *utxo.Output {
+	conditions := iotago.UnlockConditions{
+		&iotago.AddressUnlockCondition{Address: address},
+	}
+	if returnAddress != nil {
+		conditions = append(conditions, &iotago.ExpirationUnlockCondition{
+			ReturnAddress:  returnAddress,
+			MilestoneIndex: uint32(index),
+			UnixTime:       unixTime,
+		})
+	}
+
+	return utxo.CreateOutput(utils.RandOutputID(), utils.RandMessageID(), 1, 0, &iotago.ExtendedOutput{
+		Amount:     1_000_000,
+		Conditions: conditions,
+	})
+}
+
+func TestWatcher(t *testing.T) {
+
+	address := utils.RandAddress(iotago.AddressEd25519)
+	returnAddress := utils.RandAddress(iotago.AddressEd25519)
+
+	const startTimestamp = 1_600_000_000
+
+	byIndex := outputWithExpiration(address, returnAddress, 20, 0)
+	byTime := outputWithExpiration(address, returnAddress, 0, startTimestamp+3600)
+	byBoth := outputWithExpiration(address, returnAddress, 15, startTimestamp+7200)
+	spent := outputWithExpiration(address, returnAddress, 12, 0)
+	alreadyExpired := outputWithExpiration(address, returnAddress, 5, 0)
+	withoutExpiration := outputWithExpiration(address, nil, 0, 0)
+
+	watcher := expiration.NewWatcher(5, 10*time.Minute)
+	for _, output := range []*utxo.Output{byIndex, byTime, alreadyExpired, withoutExpiration} {
+		watcher.AddOutput(output)
+	}
+	// the output without expiration unlock condition is not watched
+	require.Equal(t, 3, watcher.Count())
+
+	require.Equal(t, 1, watcher.RemoveExpired(10, startTimestamp))
+	require.Equal(t, 2, watcher.Count())
+
+	watcher.ApplyLedgerUpdate(utxo.Outputs{byBoth, spent}, nil)
+	require.Equal(t, 4, watcher.Count())
+
+	var expiringSoon, expired []*expiration.Notification
+	watcher.Events.ExpiringSoon.Attach(events.NewClosure(func(notification *expiration.Notification) {
+		expiringSoon = append(expiringSoon, notification)
+	}))
+	watcher.Events.Expired.Attach(events.NewClosure(func(notification *expiration.Notification) {
+		expired = append(expired, notification)
+	}))
+
+	outputIDs := func(notifications []*expiration.Notification) iotago.OutputIDs {
+		var ids iotago.OutputIDs
+		for _, notification := range notifications {
+			ids = append(ids, notification.OutputID)
+		}
+		return ids
+	}
+
+	// the spent output is not watched anymore
+	spentOutput := utxo.NewSpent(spent, utils.RandTransactionID(), 11, startTimestamp)
+	watcher.ApplyLedgerUpdate(nil, utxo.Spents{spentOutput})
+	require.Equal(t, 3, watcher.Count())
+
+	watcher.CheckMilestone(15, startTimestamp+60)
+	require.Empty(t, expired)
+	// byBoth is within the lead window by index, but not by time
+	require.ElementsMatch(t, iotago.OutputIDs{*byIndex.OutputID()}, outputIDs(expiringSoon))
+	require.Equal(t, expiration.EventExpiringSoon, expiringSoon[0].Event)
+	require.Equal(t, address, expiringSoon[0].Address)
+	require.Equal(t, returnAddress, expiringSoon[0].ReturnAddress)
+	require.Equal(t, milestone.Index(15), expiringSoon[0].ConfirmedMilestoneIndex)
+
+	// ExpiringSoon is only issued once
+	expiringSoon = nil
+	watcher.CheckMilestone(16, startTimestamp+3000)
+	require.ElementsMatch(t, iotago.OutputIDs{*byTime.OutputID()}, outputIDs(expiringSoon))
+	// byBoth expired by index, but not by time
+	require.Empty(t, expired)
+
+	expiringSoon = nil
+	watcher.CheckMilestone(20, startTimestamp+6800)
+	require.ElementsMatch(t, iotago.OutputIDs{*byBoth.OutputID()}, outputIDs(expiringSoon))
+	require.ElementsMatch(t, iotago.OutputIDs{*byIndex.OutputID(), *byTime.OutputID()}, outputIDs(expired))
+	require.Equal(t, expiration.EventExpired, expired[0].Event)
+	require.Equal(t, 1, watcher.Count())
+
+	expiringSoon, expired = nil, nil
+	watcher.CheckMilestone(21, startTimestamp+7200)
+	require.Empty(t, expiringSoon)
+	require.ElementsMatch(t, iotago.OutputIDs{*byBoth.OutputID()}, outputIDs(expired))
+	require.Zero(t, watcher.Count())
+}
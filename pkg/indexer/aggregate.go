@@ -0,0 +1,56 @@
+package indexer
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+// AggregateResult holds the number of unspent outputs that match a filter and the sum of their amounts.
+type AggregateResult struct {
+	Count       uint64
+	Amount      uint64
+	LedgerIndex milestone.Index
+	Error       error
+}
+
+type aggregateQueryResult struct {
+	Count       uint64
+	Amount      uint64
+	LedgerIndex milestone.Index
+}
+
+// combineAggregateQuery counts the outputs of the filtered query and sums up their amounts,
+// without reading the outputs themselves.
+func (i *Indexer) combineAggregateQuery(query *gorm.DB) *AggregateResult {
+
+	// the filters need to be collected before the aggregation is added to the query
+	equalityColumns, rangeColumns := filterColumns(query)
+
+	query = whereUnspentAt(query.Select("count(*) AS count", "coalesce(sum(amount), 0) AS amount"), nil)
+
+	// the ledger index is queried together with the aggregation, so it matches the result
+	ledgerIndexQuery := i.db.Model(&status{}).Select("ledger_index")
+	joinedQuery := i.db.Table("(?), (?)", query, ledgerIndexQuery)
+
+	var result aggregateQueryResult
+
+	ts := time.Now()
+	if err := joinedQuery.Take(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// nothing was indexed yet
+			return &AggregateResult{}
+		}
+		return &AggregateResult{Error: err}
+	}
+	i.queryStats.recordQuery(i.db, query.Statement.Model, equalityColumns, rangeColumns, time.Since(ts))
+
+	return &AggregateResult{
+		Count:       result.Count,
+		Amount:      result.Amount,
+		LedgerIndex: result.LedgerIndex,
+	}
+}
@@ -0,0 +1,87 @@
+package indexer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func foundryOutputOfAlias(aliasID iotago.AliasID, serialNumber uint32, amount uint64) *utxo.Output {
+	return utxo.CreateOutput(utils.RandOutputID(), utils.RandMessageID(), 1, 0, &iotago.FoundryOutput{
+		Amount:            amount,
+		SerialNumber:      serialNumber,
+		TokenTag:          utils.RandTokenTag(),
+		CirculatingSupply: big.NewInt(0),
+		MaximumSupply:     big.NewInt(1000),
+		TokenScheme:       &iotago.SimpleTokenScheme{},
+		Conditions: iotago.UnlockConditions{
+			&iotago.AddressUnlockCondition{Address: aliasID.ToAddress()},
+		},
+	})
+}
+
+func TestIndexerAggregate(t *testing.T) {
+
+	idx, err := NewIndexer(t.TempDir())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, idx.CloseDatabase()) }()
+
+	address := utils.RandAddress(iotago.AddressEd25519)
+
+	// nothing was indexed yet
+	result := idx.ExtendedOutputsAggregateWithFilters(ExtendedOutputUnlockableByAddress(address))
+	require.NoError(t, result.Error)
+	require.Zero(t, result.Count)
+	require.Zero(t, result.Amount)
+
+	outputs := utxo.Outputs{
+		extendedOutputWithFeatureBlocks(address),
+		extendedOutputWithFeatureBlocks(address),
+		extendedOutputWithFeatureBlocks(address),
+		extendedOutputWithFeatureBlocks(utils.RandAddress(iotago.AddressEd25519)),
+	}
+
+	aliasID := utils.RandAliasID()
+	foundries := utxo.Outputs{
+		foundryOutputOfAlias(aliasID, 1, 1_000_000),
+		foundryOutputOfAlias(aliasID, 2, 2_000_000),
+		foundryOutputOfAlias(utils.RandAliasID(), 1, 3_000_000),
+	}
+	require.NoError(t, idx.UpdatedLedger(1, append(outputs, foundries...), nil))
+
+	result = idx.ExtendedOutputsAggregateWithFilters(ExtendedOutputUnlockableByAddress(address))
+	require.NoError(t, result.Error)
+	require.Equal(t, uint64(3), result.Count)
+	require.Equal(t, outputs[0].Deposit()+outputs[1].Deposit()+outputs[2].Deposit(), result.Amount)
+	require.Equal(t, milestone.Index(1), result.LedgerIndex)
+
+	// the number of foundries per alias
+	result = idx.FoundryOutputsAggregateWithFilters(FoundryUnlockableByAddress(aliasID.ToAddress()))
+	require.NoError(t, result.Error)
+	require.Equal(t, uint64(2), result.Count)
+	require.Equal(t, uint64(3_000_000), result.Amount)
+
+	// spent outputs are not contained
+	require.NoError(t, idx.UpdatedLedger(2, nil, utxo.Spents{
+		utxo.NewSpent(outputs[0], &iotago.TransactionID{}, 2, 0),
+	}))
+
+	result = idx.ExtendedOutputsAggregateWithFilters(ExtendedOutputUnlockableByAddress(address), ExtendedOutputPageSize(1))
+	require.NoError(t, result.Error)
+	require.Equal(t, uint64(2), result.Count)
+	require.Equal(t, outputs[1].Deposit()+outputs[2].Deposit(), result.Amount)
+	require.Equal(t, milestone.Index(2), result.LedgerIndex)
+
+	// no matching outputs
+	result = idx.NFTOutputsAggregateWithFilters(NFTUnlockableByAddress(address))
+	require.NoError(t, result.Error)
+	require.Zero(t, result.Count)
+	require.Zero(t, result.Amount)
+	require.Equal(t, milestone.Index(2), result.LedgerIndex)
+}
@@ -3,29 +3,39 @@ package indexer
 import (
 	"time"
 
+	"gorm.io/gorm"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
 
 type alias struct {
-	AliasID         aliasIDBytes  `gorm:"primaryKey;notnull"`
-	OutputID        outputIDBytes `gorm:"unique;notnull"`
+	OutputID        outputIDBytes `gorm:"primaryKey;notnull"`
+	AliasID         aliasIDBytes  `gorm:"notnull;index:alias_alias_id"`
 	Amount          uint64        `gorm:"notnull"`
 	StateController addressBytes  `gorm:"notnull;index:alias_state_controller"`
 	Governor        addressBytes  `gorm:"notnull;index:alias_governor"`
 	Issuer          addressBytes  `gorm:"index:alias_issuer"`
 	Sender          addressBytes  `gorm:"index:alias_sender"`
-	CreatedAt       time.Time     `gorm:"notnull"`
+	Metadata        []byte
+	CreatedAt       time.Time        `gorm:"notnull"`
+	BookedAt        milestone.Index  `gorm:"notnull;index:alias_booked_at"`
+	SpentAt         *milestone.Index `gorm:"index:alias_spent_at"`
 }
 
 type AliasFilterOptions struct {
-	stateController *iotago.Address
-	governor        *iotago.Address
-	issuer          *iotago.Address
-	sender          *iotago.Address
-	pageSize        int
-	cursor          *string
-	createdBefore   *time.Time
-	createdAfter    *time.Time
+	stateController  *iotago.Address
+	governor         *iotago.Address
+	issuer           *iotago.Address
+	sender           *iotago.Address
+	metadataContains []byte
+	pageSize         int
+	cursor           *string
+	sorting          resultSorting
+	createdBefore    *time.Time
+	createdAfter     *time.Time
+	bookedAt         *milestone.Index
+	anyOf            [][]*AliasFilterOptions
 }
 
 type AliasFilterOption func(*AliasFilterOptions)
@@ -54,6 +64,13 @@ func AliasIssuer(address iotago.Address) AliasFilterOption {
 	}
 }
 
+// AliasMetadataContains filters for aliases with a metadata feature block that contains the given data.
+func AliasMetadataContains(data []byte) AliasFilterOption {
+	return func(args *AliasFilterOptions) {
+		args.metadataContains = data
+	}
+}
+
 func AliasPageSize(pageSize int) AliasFilterOption {
 	return func(args *AliasFilterOptions) {
 		args.pageSize = pageSize
@@ -66,6 +83,27 @@ func AliasCursor(cursor string) AliasFilterOption {
 	}
 }
 
+// AliasBookedAt filters for outputs that were booked at the given milestone index.
+func AliasBookedAt(index milestone.Index) AliasFilterOption {
+	return func(args *AliasFilterOptions) {
+		args.bookedAt = &index
+	}
+}
+
+// AliasSortByAmount sorts the results by their amount.
+func AliasSortByAmount(order SortOrder) AliasFilterOption {
+	return func(args *AliasFilterOptions) {
+		args.sorting = resultSorting{column: sortByAmount, order: order}
+	}
+}
+
+// AliasSortByCreatedAt sorts the results by their creation time, which is the default.
+func AliasSortByCreatedAt(order SortOrder) AliasFilterOption {
+	return func(args *AliasFilterOptions) {
+		args.sorting = resultSorting{column: sortByCreatedAt, order: order}
+	}
+}
+
 func AliasCreatedBefore(time time.Time) AliasFilterOption {
 	return func(args *AliasFilterOptions) {
 		args.createdBefore = &time
@@ -78,6 +116,19 @@ func AliasCreatedAfter(time time.Time) AliasFilterOption {
 	}
 }
 
+// AliasAnyOf filters for aliases that match at least one of the given alternatives.
+// The filters of an alternative are combined with AND, its page size, cursor and sorting are ignored.
+// Every call adds another group that needs to match in addition to the other filters.
+func AliasAnyOf(alternatives ...[]AliasFilterOption) AliasFilterOption {
+	return func(args *AliasFilterOptions) {
+		group := make([]*AliasFilterOptions, 0, len(alternatives))
+		for _, alternative := range alternatives {
+			group = append(group, aliasFilterOptions(alternative))
+		}
+		args.anyOf = append(args.anyOf, group)
+	}
+}
+
 func aliasFilterOptions(optionalOptions []AliasFilterOption) *AliasFilterOptions {
 	result := &AliasFilterOptions{}
 
@@ -93,17 +144,39 @@ func (i *Indexer) AliasOutput(aliasID *iotago.AliasID) *IndexerResult {
 		Where("alias_id = ?", aliasID[:]).
 		Limit(1)
 
-	return i.combineOutputIDFilteredQuery(query, 0, nil)
+	return i.combineOutputIDFilteredQuery(query, 0, nil, resultSorting{})
 }
 
 func (i *Indexer) AliasOutputsWithFilters(filter ...AliasFilterOption) *IndexerResult {
 	opts := aliasFilterOptions(filter)
-	query := i.db.Model(&alias{})
 
+	query, err := opts.whereConditions(i.db.Model(&alias{}))
+	if err != nil {
+		return errorResult(err)
+	}
+
+	return i.combineOutputIDFilteredQuery(query, opts.pageSize, opts.cursor, opts.sorting)
+}
+
+// AliasOutputsAggregateWithFilters returns the number and the summed amount of the unspent outputs that match the filters.
+// The page size, the cursor and the sorting of the filters are ignored.
+func (i *Indexer) AliasOutputsAggregateWithFilters(filters ...AliasFilterOption) *AggregateResult {
+	opts := aliasFilterOptions(filters)
+
+	query, err := opts.whereConditions(i.db.Model(&alias{}))
+	if err != nil {
+		return &AggregateResult{Error: err}
+	}
+
+	return i.combineAggregateQuery(query)
+}
+
+// whereConditions adds the conditions of the filter options to the query.
+func (opts *AliasFilterOptions) whereConditions(query *gorm.DB) (*gorm.DB, error) {
 	if opts.stateController != nil {
 		addr, err := addressBytesForAddress(*opts.stateController)
 		if err != nil {
-			return errorResult(err)
+			return nil, err
 		}
 		query = query.Where("state_controller = ?", addr[:])
 	}
@@ -111,7 +184,7 @@ func (i *Indexer) AliasOutputsWithFilters(filter ...AliasFilterOption) *IndexerR
 	if opts.governor != nil {
 		addr, err := addressBytesForAddress(*opts.governor)
 		if err != nil {
-			return errorResult(err)
+			return nil, err
 		}
 		query = query.Where("governor = ?", addr[:])
 	}
@@ -119,7 +192,7 @@ func (i *Indexer) AliasOutputsWithFilters(filter ...AliasFilterOption) *IndexerR
 	if opts.sender != nil {
 		addr, err := addressBytesForAddress(*opts.sender)
 		if err != nil {
-			return errorResult(err)
+			return nil, err
 		}
 		query = query.Where("sender = ?", addr[:])
 	}
@@ -127,11 +200,15 @@ func (i *Indexer) AliasOutputsWithFilters(filter ...AliasFilterOption) *IndexerR
 	if opts.issuer != nil {
 		addr, err := addressBytesForAddress(*opts.issuer)
 		if err != nil {
-			return errorResult(err)
+			return nil, err
 		}
 		query = query.Where("issuer = ?", addr[:])
 	}
 
+	if len(opts.metadataContains) > 0 {
+		query = whereBytesContain(query, "metadata", opts.metadataContains)
+	}
+
 	if opts.createdBefore != nil {
 		query = query.Where("created_at < ?", *opts.createdBefore)
 	}
@@ -140,5 +217,21 @@ func (i *Indexer) AliasOutputsWithFilters(filter ...AliasFilterOption) *IndexerR
 		query = query.Where("created_at > ?", *opts.createdAfter)
 	}
 
-	return i.combineOutputIDFilteredQuery(query, opts.pageSize, opts.cursor)
+	if opts.bookedAt != nil {
+		query = query.Where("booked_at = ?", *opts.bookedAt)
+	}
+
+	for _, group := range opts.anyOf {
+		alternatives := make([]whereConditionsFunc, 0, len(group))
+		for _, alternative := range group {
+			alternatives = append(alternatives, alternative.whereConditions)
+		}
+
+		var err error
+		if query, err = whereAnyOf(query, alternatives); err != nil {
+			return nil, err
+		}
+	}
+
+	return query, nil
 }
@@ -0,0 +1,94 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestIndexerAnyOfFilters(t *testing.T) {
+
+	idx, err := NewIndexer(t.TempDir())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, idx.CloseDatabase()) }()
+
+	addressA := utils.RandAddress(iotago.AddressEd25519)
+	addressB := utils.RandAddress(iotago.AddressEd25519)
+	addressC := utils.RandAddress(iotago.AddressEd25519)
+
+	outputA := extendedOutputWithFeatureBlocks(addressA, &iotago.TagFeatureBlock{Tag: []byte("a")})
+	outputATagged := extendedOutputWithFeatureBlocks(addressA, &iotago.TagFeatureBlock{Tag: []byte("wanted")})
+	outputB := extendedOutputWithFeatureBlocks(addressB)
+	outputC := extendedOutputWithFeatureBlocks(addressC, &iotago.TagFeatureBlock{Tag: []byte("wanted")})
+
+	require.NoError(t, idx.UpdatedLedger(1, utxo.Outputs{outputA, outputATagged, outputB, outputC}, nil))
+
+	outputIDs := func(filters ...ExtendedOutputFilterOption) iotago.OutputIDs {
+		result := idx.ExtendedOutputsWithFilters(filters...)
+		require.NoError(t, result.Error)
+		return result.OutputIDs
+	}
+
+	// address A OR address B
+	require.ElementsMatch(t, iotago.OutputIDs{*outputA.OutputID(), *outputATagged.OutputID(), *outputB.OutputID()}, outputIDs(
+		ExtendedOutputAnyOf(
+			[]ExtendedOutputFilterOption{ExtendedOutputUnlockableByAddress(addressA)},
+			[]ExtendedOutputFilterOption{ExtendedOutputUnlockableByAddress(addressB)},
+		),
+	))
+
+	// (address A AND tag) OR address B
+	require.ElementsMatch(t, iotago.OutputIDs{*outputATagged.OutputID(), *outputB.OutputID()}, outputIDs(
+		ExtendedOutputAnyOf(
+			[]ExtendedOutputFilterOption{ExtendedOutputUnlockableByAddress(addressA), ExtendedOutputTag([]byte("wanted"))},
+			[]ExtendedOutputFilterOption{ExtendedOutputUnlockableByAddress(addressB)},
+		),
+	))
+
+	// tag AND (address A OR address B)
+	require.ElementsMatch(t, iotago.OutputIDs{*outputATagged.OutputID()}, outputIDs(
+		ExtendedOutputTag([]byte("wanted")),
+		ExtendedOutputAnyOf(
+			[]ExtendedOutputFilterOption{ExtendedOutputUnlockableByAddress(addressA)},
+			[]ExtendedOutputFilterOption{ExtendedOutputUnlockableByAddress(addressB)},
+		),
+	))
+
+	// (address A OR address C) AND (tag OR address B)
+	require.ElementsMatch(t, iotago.OutputIDs{*outputATagged.OutputID(), *outputC.OutputID()}, outputIDs(
+		ExtendedOutputAnyOf(
+			[]ExtendedOutputFilterOption{ExtendedOutputUnlockableByAddress(addressA)},
+			[]ExtendedOutputFilterOption{ExtendedOutputUnlockableByAddress(addressC)},
+		),
+		ExtendedOutputAnyOf(
+			[]ExtendedOutputFilterOption{ExtendedOutputTag([]byte("wanted"))},
+			[]ExtendedOutputFilterOption{ExtendedOutputUnlockableByAddress(addressB)},
+		),
+	))
+
+	// nested groups
+	require.ElementsMatch(t, iotago.OutputIDs{*outputATagged.OutputID(), *outputB.OutputID()}, outputIDs(
+		ExtendedOutputAnyOf(
+			[]ExtendedOutputFilterOption{ExtendedOutputUnlockableByAddress(addressB)},
+			[]ExtendedOutputFilterOption{
+				ExtendedOutputTag([]byte("wanted")),
+				ExtendedOutputAnyOf(
+					[]ExtendedOutputFilterOption{ExtendedOutputUnlockableByAddress(addressA)},
+					[]ExtendedOutputFilterOption{ExtendedOutputUnlockableByAddress(addressB)},
+				),
+			},
+		),
+	))
+
+	// an alternative without conditions matches everything
+	require.Len(t, outputIDs(
+		ExtendedOutputAnyOf(
+			[]ExtendedOutputFilterOption{ExtendedOutputUnlockableByAddress(addressA)},
+			[]ExtendedOutputFilterOption{},
+		),
+	), 4)
+}
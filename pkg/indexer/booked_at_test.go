@@ -0,0 +1,39 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestIndexerBookedAt(t *testing.T) {
+
+	idx, err := NewIndexer(t.TempDir())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, idx.CloseDatabase()) }()
+
+	address := utils.RandAddress(iotago.AddressEd25519)
+
+	first := extendedOutputWithFeatureBlocks(address)
+	require.NoError(t, idx.UpdatedLedger(1, utxo.Outputs{first}, nil))
+
+	second := utxo.CreateOutput(utils.RandOutputID(), utils.RandMessageID(), 2, 0, &iotago.ExtendedOutput{
+		Amount: 1_000_000,
+		Conditions: iotago.UnlockConditions{
+			&iotago.AddressUnlockCondition{Address: address},
+		},
+	})
+	require.NoError(t, idx.UpdatedLedger(2, utxo.Outputs{second}, nil))
+
+	result := idx.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address), ExtendedOutputBookedAt(2))
+	require.NoError(t, result.Error)
+	require.Equal(t, iotago.OutputIDs{*second.OutputID()}, result.OutputIDs)
+
+	result = idx.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address), ExtendedOutputBookedAt(3))
+	require.NoError(t, result.Error)
+	require.Empty(t, result.OutputIDs)
+}
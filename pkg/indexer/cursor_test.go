@@ -0,0 +1,112 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestIndexerCursorPinnedToLedgerIndex(t *testing.T) {
+
+	idx, err := NewIndexer(t.TempDir(), WithSpentOutputsRetention(2))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, idx.CloseDatabase()) }()
+
+	address := utils.RandAddress(iotago.AddressEd25519)
+
+	outputs := utxo.Outputs{
+		extendedOutputWithFeatureBlocks(address),
+		extendedOutputWithFeatureBlocks(address),
+		extendedOutputWithFeatureBlocks(address),
+		extendedOutputWithFeatureBlocks(address),
+	}
+	require.NoError(t, idx.UpdatedLedger(1, outputs, nil))
+
+	firstPage := idx.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address), ExtendedOutputPageSize(2))
+	require.NoError(t, firstPage.Error)
+	require.Len(t, firstPage.OutputIDs, 2)
+	require.Equal(t, milestone.Index(1), firstPage.LedgerIndex)
+	require.NotNil(t, firstPage.Cursor)
+	require.Len(t, *firstPage.Cursor, CursorLength)
+
+	// spend an output of the first page and create a new one
+	spent := firstPage.OutputIDs[0]
+	var spentOutput *utxo.Output
+	for _, output := range outputs {
+		if *output.OutputID() == spent {
+			spentOutput = output
+		}
+	}
+	require.NotNil(t, spentOutput)
+
+	newOutput := utxo.CreateOutput(utils.RandOutputID(), utils.RandMessageID(), 2, 0, &iotago.ExtendedOutput{
+		Amount: 1_000_000,
+		Conditions: iotago.UnlockConditions{
+			&iotago.AddressUnlockCondition{Address: address},
+		},
+	})
+	require.NoError(t, idx.UpdatedLedger(2, utxo.Outputs{newOutput}, utxo.Spents{
+		utxo.NewSpent(spentOutput, &iotago.TransactionID{}, 2, 0),
+	}))
+
+	// the following page is answered at the ledger index of the first page
+	secondPage := idx.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address), ExtendedOutputPageSize(2), ExtendedOutputCursor(*firstPage.Cursor))
+	require.NoError(t, secondPage.Error)
+	require.Equal(t, milestone.Index(1), secondPage.LedgerIndex)
+	require.Nil(t, secondPage.Cursor)
+
+	allOutputIDs := append(firstPage.OutputIDs, secondPage.OutputIDs...)
+	require.ElementsMatch(t, iotago.OutputIDs{*outputs[0].OutputID(), *outputs[1].OutputID(), *outputs[2].OutputID(), *outputs[3].OutputID()}, allOutputIDs)
+
+	// new queries don't contain the spent output
+	current := idx.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address))
+	require.NoError(t, current.Error)
+	require.Equal(t, milestone.Index(2), current.LedgerIndex)
+	require.Len(t, current.OutputIDs, 4)
+	require.NotContains(t, current.OutputIDs, spent)
+	require.Contains(t, current.OutputIDs, *newOutput.OutputID())
+
+	// the spent outputs of ledger index 1 get pruned once it is older than the retention
+	require.NoError(t, idx.UpdatedLedger(3, nil, nil))
+	require.NoError(t, idx.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address), ExtendedOutputPageSize(2), ExtendedOutputCursor(*firstPage.Cursor)).Error)
+
+	require.NoError(t, idx.UpdatedLedger(4, nil, nil))
+	expired := idx.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address), ExtendedOutputPageSize(2), ExtendedOutputCursor(*firstPage.Cursor))
+	require.ErrorIs(t, expired.Error, ErrCursorExpired)
+
+	var spentRows int64
+	require.NoError(t, idx.db.Model(&extendedOutput{}).Where("spent_at IS NOT NULL").Count(&spentRows).Error)
+	require.Zero(t, spentRows)
+}
+
+func TestIndexerCursorAfterImport(t *testing.T) {
+
+	idx, err := NewIndexer(t.TempDir())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, idx.CloseDatabase()) }()
+
+	address := utils.RandAddress(iotago.AddressEd25519)
+
+	require.NoError(t, idx.UpdatedLedger(5, utxo.Outputs{
+		extendedOutputWithFeatureBlocks(address),
+		extendedOutputWithFeatureBlocks(address),
+	}, nil))
+
+	firstPage := idx.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address), ExtendedOutputPageSize(1))
+	require.NoError(t, firstPage.Error)
+	require.NotNil(t, firstPage.Cursor)
+
+	// the spent outputs before an import are unknown
+	require.NoError(t, idx.Clear())
+	importer := idx.ImportTransaction()
+	require.NoError(t, importer.AddOutput(extendedOutputWithFeatureBlocks(address)))
+	require.NoError(t, importer.Finalize(6))
+
+	result := idx.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address), ExtendedOutputPageSize(1), ExtendedOutputCursor(*firstPage.Cursor))
+	require.ErrorIs(t, result.Error, ErrCursorExpired)
+}
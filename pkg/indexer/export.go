@@ -0,0 +1,200 @@
+package indexer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/iotaledger/hive.go/kvstore/utils"
+)
+
+var (
+	ErrExportLedgerIndexUnavailable = errors.New("the index can't be exported at the given ledger index")
+	ErrImportLedgerIndexMismatch    = errors.New("the ledger index of the export doesn't match the ledger index of the node")
+	ErrImportSchemaVersionMismatch  = errors.New("the export was created with a different schema version")
+	ErrImportDatabaseExists         = errors.New("indexer database already exists")
+)
+
+// Export writes a copy of the index at the given ledger index to the file at filePath.
+// The ledger index can be any index between the oldest ledger index for which the spent outputs
+// are still contained and the current ledger index, or 0 for the current ledger index.
+// The copy only contains the unspent outputs, so it can be imported with ImportDatabase.
+// It returns the ledger index of the export.
+func (i *Indexer) Export(filePath string, ledgerIndex milestone.Index) (milestone.Index, error) {
+
+	// ledger updates would change the index while it is copied
+	i.writeLock.Lock()
+	defer i.writeLock.Unlock()
+
+	currentStatus, err := i.status()
+	if err != nil {
+		return 0, err
+	}
+
+	if ledgerIndex == 0 {
+		ledgerIndex = currentStatus.LedgerIndex
+	}
+
+	if ledgerIndex < currentStatus.OldestLedgerIndex || ledgerIndex > currentStatus.LedgerIndex {
+		return 0, errors.WithMessagef(ErrExportLedgerIndexUnavailable, "requested: %d, available: %d-%d", ledgerIndex, currentStatus.OldestLedgerIndex, currentStatus.LedgerIndex)
+	}
+
+	if _, err := os.Stat(filePath); err == nil {
+		return 0, fmt.Errorf("export file already exists: %s", filePath)
+	}
+
+	// the copy is consistent because it is created within a single read transaction
+	if err := i.db.Exec("VACUUM INTO ?", filePath).Error; err != nil {
+		return 0, err
+	}
+
+	db, err := gorm.Open(sqlite.Open(filePath), &gorm.Config{})
+	if err != nil {
+		return 0, err
+	}
+	defer closeDB(db)
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return rewindTo(ledgerIndex, tx)
+	}); err != nil {
+		return 0, err
+	}
+
+	// reclaim the space of the removed outputs
+	if err := db.Exec("VACUUM").Error; err != nil {
+		return 0, err
+	}
+
+	return ledgerIndex, nil
+}
+
+// rewindTo removes all changes after the given ledger index and the spent outputs.
+func rewindTo(ledgerIndex milestone.Index, tx *gorm.DB) error {
+	for _, model := range []interface{}{&extendedOutput{}, &alias{}, &nft{}, &foundry{}} {
+		// outputs that were created after the ledger index
+		if err := tx.Where("booked_at > ?", ledgerIndex).Delete(model).Error; err != nil {
+			return err
+		}
+
+		// outputs that were spent after the ledger index are unspent at the ledger index
+		if err := tx.Model(model).Where("spent_at > ?", ledgerIndex).Update("spent_at", nil).Error; err != nil {
+			return err
+		}
+
+		// paginated queries can't be continued on another node, so the spent outputs are not needed
+		if err := tx.Where("spent_at IS NOT NULL").Delete(model).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&status{}).Where("id = ?", 1).Updates(map[string]interface{}{
+		"ledger_index":        ledgerIndex,
+		"oldest_ledger_index": ledgerIndex,
+	}).Error
+}
+
+// ExportLedgerIndex returns the ledger index of the export at filePath.
+func ExportLedgerIndex(filePath string) (milestone.Index, error) {
+	exportStatus, err := readExportStatus(filePath)
+	if err != nil {
+		return 0, err
+	}
+	return exportStatus.LedgerIndex, nil
+}
+
+func readExportStatus(filePath string) (*status, error) {
+	if _, err := os.Stat(filePath); err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(sqlite.Open(filePath), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB(db)
+
+	exportStatus := &status{}
+	if err := db.Take(exportStatus).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return exportStatus, nil
+}
+
+// ImportDatabase copies the export at filePath into the indexer directory at dbPath.
+// The ledger index of the export needs to match the given ledger index of the UTXO ledger,
+// otherwise the index would be rebuilt at the next start anyway.
+// The import is only possible if no indexer database exists yet.
+func ImportDatabase(dbPath string, filePath string, ledgerIndex milestone.Index) error {
+
+	exportStatus, err := readExportStatus(filePath)
+	if err != nil {
+		return err
+	}
+
+	if exportStatus.SchemaVersion != schemaVersion {
+		return errors.WithMessagef(ErrImportSchemaVersionMismatch, "export: %d, node: %d", exportStatus.SchemaVersion, schemaVersion)
+	}
+
+	if exportStatus.LedgerIndex != ledgerIndex {
+		return errors.WithMessagef(ErrImportLedgerIndexMismatch, "export: %d, node: %d", exportStatus.LedgerIndex, ledgerIndex)
+	}
+
+	dbFile := filepath.Join(dbPath, dbFileName)
+	if _, err := os.Stat(dbFile); err == nil {
+		return errors.WithMessage(ErrImportDatabaseExists, dbFile)
+	}
+
+	if err := utils.CreateDirectory(dbPath, 0700); err != nil {
+		return err
+	}
+
+	// copy to a temporary file first, so that an aborted import doesn't leave an incomplete database behind
+	tmpFile := dbFile + ".tmp"
+	if err := copyFile(filePath, tmpFile); err != nil {
+		_ = os.Remove(tmpFile)
+		return err
+	}
+
+	return os.Rename(tmpFile, dbFile)
+}
+
+func copyFile(sourcePath string, targetPath string) error {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = source.Close() }()
+
+	target, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(target, source); err != nil {
+		_ = target.Close()
+		return err
+	}
+
+	if err := target.Sync(); err != nil {
+		_ = target.Close()
+		return err
+	}
+
+	return target.Close()
+}
+
+func closeDB(db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+}
@@ -0,0 +1,78 @@
+package indexer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestIndexerExportImport(t *testing.T) {
+
+	idx, err := NewIndexer(t.TempDir(), WithSpentOutputsRetention(10))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, idx.CloseDatabase()) }()
+
+	address := utils.RandAddress(iotago.AddressEd25519)
+
+	unspent := extendedOutputWithFeatureBlocks(address)
+	spentBefore := extendedOutputWithFeatureBlocks(address)
+	spentAfter := extendedOutputWithFeatureBlocks(address)
+	require.NoError(t, idx.UpdatedLedger(1, utxo.Outputs{unspent, spentBefore, spentAfter}, nil))
+	require.NoError(t, idx.UpdatedLedger(2, nil, utxo.Spents{utxo.NewSpent(spentBefore, &iotago.TransactionID{}, 2, 0)}))
+
+	createdAfter := utxo.CreateOutput(utils.RandOutputID(), utils.RandMessageID(), 4, 0, &iotago.ExtendedOutput{
+		Amount:     1_000_000,
+		Conditions: iotago.UnlockConditions{&iotago.AddressUnlockCondition{Address: address}},
+	})
+	require.NoError(t, idx.UpdatedLedger(4, utxo.Outputs{createdAfter}, utxo.Spents{utxo.NewSpent(spentAfter, &iotago.TransactionID{}, 4, 0)}))
+
+	exportDir := t.TempDir()
+
+	// the index can't be exported at a ledger index it hasn't reached yet
+	_, err = idx.Export(filepath.Join(exportDir, "future.db"), 5)
+	require.ErrorIs(t, err, ErrExportLedgerIndexUnavailable)
+
+	exportFile := filepath.Join(exportDir, "export.db")
+	ledgerIndex, err := idx.Export(exportFile, 3)
+	require.NoError(t, err)
+	require.Equal(t, milestone.Index(3), ledgerIndex)
+
+	// the export doesn't change the index itself
+	result := idx.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address))
+	require.NoError(t, result.Error)
+	require.ElementsMatch(t, iotago.OutputIDs{*unspent.OutputID(), *createdAfter.OutputID()}, result.OutputIDs)
+
+	exportLedgerIndex, err := ExportLedgerIndex(exportFile)
+	require.NoError(t, err)
+	require.Equal(t, milestone.Index(3), exportLedgerIndex)
+
+	importDir := t.TempDir()
+	require.ErrorIs(t, ImportDatabase(importDir, exportFile, 4), ErrImportLedgerIndexMismatch)
+	require.NoError(t, ImportDatabase(importDir, exportFile, 3))
+	require.ErrorIs(t, ImportDatabase(importDir, exportFile, 3), ErrImportDatabaseExists)
+
+	imported, err := NewIndexer(importDir)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, imported.CloseDatabase()) }()
+
+	importedLedgerIndex, err := imported.LedgerIndex()
+	require.NoError(t, err)
+	require.Equal(t, milestone.Index(3), importedLedgerIndex)
+
+	// the imported index contains the unspent outputs at the ledger index of the export
+	result = imported.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address))
+	require.NoError(t, result.Error)
+	require.ElementsMatch(t, iotago.OutputIDs{*unspent.OutputID(), *spentAfter.OutputID()}, result.OutputIDs)
+
+	// and the imported index can be updated from there on
+	require.NoError(t, imported.UpdatedLedger(4, utxo.Outputs{createdAfter}, utxo.Spents{utxo.NewSpent(spentAfter, &iotago.TransactionID{}, 4, 0)}))
+	result = imported.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address))
+	require.NoError(t, result.Error)
+	require.ElementsMatch(t, iotago.OutputIDs{*unspent.OutputID(), *createdAfter.OutputID()}, result.OutputIDs)
+}
@@ -3,6 +3,8 @@ package indexer
 import (
 	"time"
 
+	"gorm.io/gorm"
+
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
@@ -11,8 +13,9 @@ type extendedOutput struct {
 	OutputID                outputIDBytes `gorm:"primaryKey;notnull"`
 	Amount                  uint64        `gorm:"notnull"`
 	Sender                  addressBytes  `gorm:"index:extended_sender_tag"`
-	Tag                     []byte        `gorm:"index:extended_sender_tag"`
-	Address                 addressBytes  `gorm:"notnull;index:extended_address"`
+	Tag                     []byte        `gorm:"index:extended_sender_tag;index:extended_tag"`
+	Metadata                []byte
+	Address                 addressBytes `gorm:"notnull;index:extended_address"`
 	DustReturn              *uint64
 	DustReturnAddress       addressBytes
 	TimelockMilestone       *milestone.Index
@@ -20,11 +23,14 @@ type extendedOutput struct {
 	ExpirationMilestone     *milestone.Index
 	ExpirationTime          *time.Time
 	ExpirationReturnAddress addressBytes
-	CreatedAt               time.Time `gorm:"notnull"`
+	CreatedAt               time.Time        `gorm:"notnull"`
+	BookedAt                milestone.Index  `gorm:"notnull;index:extended_booked_at"`
+	SpentAt                 *milestone.Index `gorm:"index:extended_spent_at"`
 }
 
 type ExtendedOutputFilterOptions struct {
 	unlockableByAddress       *iotago.Address
+	spendableBy               *spendableByFilter
 	hasDustReturnCondition    *bool
 	dustReturnAddress         *iotago.Address
 	hasExpirationCondition    *bool
@@ -40,10 +46,15 @@ type ExtendedOutputFilterOptions struct {
 	timelockedAfterMilestone  *milestone.Index
 	sender                    *iotago.Address
 	tag                       []byte
+	tagPrefix                 []byte
+	metadataContains          []byte
 	pageSize                  int
 	cursor                    *string
+	sorting                   resultSorting
 	createdBefore             *time.Time
 	createdAfter              *time.Time
+	bookedAt                  *milestone.Index
+	anyOf                     [][]*ExtendedOutputFilterOptions
 }
 
 type ExtendedOutputFilterOption func(*ExtendedOutputFilterOptions)
@@ -54,6 +65,19 @@ func ExtendedOutputUnlockableByAddress(address iotago.Address) ExtendedOutputFil
 	}
 }
 
+// ExtendedOutputSpendableByAddress filters for outputs that the address can unlock at the given milestone index and timestamp,
+// taking timelock and expiration unlock conditions into account.
+// Outputs with a dust return unlock condition are included, since the address can unlock them by returning the deposit.
+func ExtendedOutputSpendableByAddress(address iotago.Address, msIndex milestone.Index, timestamp time.Time) ExtendedOutputFilterOption {
+	return func(args *ExtendedOutputFilterOptions) {
+		args.spendableBy = &spendableByFilter{
+			address:   address,
+			msIndex:   msIndex,
+			timestamp: timestamp,
+		}
+	}
+}
+
 func ExtendedOutputHasDustReturnCondition(value bool) ExtendedOutputFilterOption {
 	return func(args *ExtendedOutputFilterOptions) {
 		args.hasDustReturnCondition = &value
@@ -144,6 +168,20 @@ func ExtendedOutputTag(tag []byte) ExtendedOutputFilterOption {
 	}
 }
 
+// ExtendedOutputTagPrefix filters for outputs with a tag feature block that starts with the given prefix.
+func ExtendedOutputTagPrefix(prefix []byte) ExtendedOutputFilterOption {
+	return func(args *ExtendedOutputFilterOptions) {
+		args.tagPrefix = prefix
+	}
+}
+
+// ExtendedOutputMetadataContains filters for outputs with a metadata feature block that contains the given data.
+func ExtendedOutputMetadataContains(data []byte) ExtendedOutputFilterOption {
+	return func(args *ExtendedOutputFilterOptions) {
+		args.metadataContains = data
+	}
+}
+
 func ExtendedOutputPageSize(pageSize int) ExtendedOutputFilterOption {
 	return func(args *ExtendedOutputFilterOptions) {
 		args.pageSize = pageSize
@@ -156,6 +194,27 @@ func ExtendedOutputCursor(cursor string) ExtendedOutputFilterOption {
 	}
 }
 
+// ExtendedOutputBookedAt filters for outputs that were booked at the given milestone index.
+func ExtendedOutputBookedAt(index milestone.Index) ExtendedOutputFilterOption {
+	return func(args *ExtendedOutputFilterOptions) {
+		args.bookedAt = &index
+	}
+}
+
+// ExtendedOutputSortByAmount sorts the results by their amount.
+func ExtendedOutputSortByAmount(order SortOrder) ExtendedOutputFilterOption {
+	return func(args *ExtendedOutputFilterOptions) {
+		args.sorting = resultSorting{column: sortByAmount, order: order}
+	}
+}
+
+// ExtendedOutputSortByCreatedAt sorts the results by their creation time, which is the default.
+func ExtendedOutputSortByCreatedAt(order SortOrder) ExtendedOutputFilterOption {
+	return func(args *ExtendedOutputFilterOptions) {
+		args.sorting = resultSorting{column: sortByCreatedAt, order: order}
+	}
+}
+
 func ExtendedOutputCreatedBefore(time time.Time) ExtendedOutputFilterOption {
 	return func(args *ExtendedOutputFilterOptions) {
 		args.createdBefore = &time
@@ -168,6 +227,19 @@ func ExtendedOutputCreatedAfter(time time.Time) ExtendedOutputFilterOption {
 	}
 }
 
+// ExtendedOutputAnyOf filters for outputs that match at least one of the given alternatives.
+// The filters of an alternative are combined with AND, its page size, cursor and sorting are ignored.
+// Every call adds another group that needs to match in addition to the other filters.
+func ExtendedOutputAnyOf(alternatives ...[]ExtendedOutputFilterOption) ExtendedOutputFilterOption {
+	return func(args *ExtendedOutputFilterOptions) {
+		group := make([]*ExtendedOutputFilterOptions, 0, len(alternatives))
+		for _, alternative := range alternatives {
+			group = append(group, extendedOutputFilterOptions(alternative))
+		}
+		args.anyOf = append(args.anyOf, group)
+	}
+}
+
 func extendedOutputFilterOptions(optionalOptions []ExtendedOutputFilterOption) *ExtendedOutputFilterOptions {
 	result := &ExtendedOutputFilterOptions{}
 
@@ -178,16 +250,45 @@ func extendedOutputFilterOptions(optionalOptions []ExtendedOutputFilterOption) *
 }
 func (i *Indexer) ExtendedOutputsWithFilters(filters ...ExtendedOutputFilterOption) *IndexerResult {
 	opts := extendedOutputFilterOptions(filters)
-	query := i.db.Model(&extendedOutput{})
 
+	query, err := opts.whereConditions(i.db.Model(&extendedOutput{}))
+	if err != nil {
+		return errorResult(err)
+	}
+
+	return i.combineOutputIDFilteredQuery(query, opts.pageSize, opts.cursor, opts.sorting)
+}
+
+// ExtendedOutputsAggregateWithFilters returns the number and the summed amount of the unspent outputs that match the filters.
+// The page size, the cursor and the sorting of the filters are ignored.
+func (i *Indexer) ExtendedOutputsAggregateWithFilters(filters ...ExtendedOutputFilterOption) *AggregateResult {
+	opts := extendedOutputFilterOptions(filters)
+
+	query, err := opts.whereConditions(i.db.Model(&extendedOutput{}))
+	if err != nil {
+		return &AggregateResult{Error: err}
+	}
+
+	return i.combineAggregateQuery(query)
+}
+
+// whereConditions adds the conditions of the filter options to the query.
+func (opts *ExtendedOutputFilterOptions) whereConditions(query *gorm.DB) (*gorm.DB, error) {
 	if opts.unlockableByAddress != nil {
 		addr, err := addressBytesForAddress(*opts.unlockableByAddress)
 		if err != nil {
-			return errorResult(err)
+			return nil, err
 		}
 		query = query.Where("address = ?", addr[:])
 	}
 
+	if opts.spendableBy != nil {
+		var err error
+		if query, err = whereSpendableBy(query, opts.spendableBy); err != nil {
+			return nil, err
+		}
+	}
+
 	if opts.hasDustReturnCondition != nil {
 		if *opts.hasDustReturnCondition {
 			query = query.Where("dust_return IS NOT NULL")
@@ -199,7 +300,7 @@ func (i *Indexer) ExtendedOutputsWithFilters(filters ...ExtendedOutputFilterOpti
 	if opts.dustReturnAddress != nil {
 		addr, err := addressBytesForAddress(*opts.dustReturnAddress)
 		if err != nil {
-			return errorResult(err)
+			return nil, err
 		}
 		query = query.Where("dust_return_address = ?", addr[:])
 	}
@@ -215,7 +316,7 @@ func (i *Indexer) ExtendedOutputsWithFilters(filters ...ExtendedOutputFilterOpti
 	if opts.expirationReturnAddress != nil {
 		addr, err := addressBytesForAddress(*opts.expirationReturnAddress)
 		if err != nil {
-			return errorResult(err)
+			return nil, err
 		}
 		query = query.Where("expiration_return_address = ?", addr[:])
 	}
@@ -263,7 +364,7 @@ func (i *Indexer) ExtendedOutputsWithFilters(filters ...ExtendedOutputFilterOpti
 	if opts.sender != nil {
 		addr, err := addressBytesForAddress(*opts.sender)
 		if err != nil {
-			return errorResult(err)
+			return nil, err
 		}
 		query = query.Where("sender = ?", addr[:])
 	}
@@ -272,6 +373,14 @@ func (i *Indexer) ExtendedOutputsWithFilters(filters ...ExtendedOutputFilterOpti
 		query = query.Where("tag = ?", opts.tag)
 	}
 
+	if len(opts.tagPrefix) > 0 {
+		query = whereBytesPrefix(query, "tag", opts.tagPrefix)
+	}
+
+	if len(opts.metadataContains) > 0 {
+		query = whereBytesContain(query, "metadata", opts.metadataContains)
+	}
+
 	if opts.createdBefore != nil {
 		query = query.Where("created_at < ?", *opts.createdBefore)
 	}
@@ -280,5 +389,21 @@ func (i *Indexer) ExtendedOutputsWithFilters(filters ...ExtendedOutputFilterOpti
 		query = query.Where("created_at > ?", *opts.createdAfter)
 	}
 
-	return i.combineOutputIDFilteredQuery(query, opts.pageSize, opts.cursor)
+	if opts.bookedAt != nil {
+		query = query.Where("booked_at = ?", *opts.bookedAt)
+	}
+
+	for _, group := range opts.anyOf {
+		alternatives := make([]whereConditionsFunc, 0, len(group))
+		for _, alternative := range group {
+			alternatives = append(alternatives, alternative.whereConditions)
+		}
+
+		var err error
+		if query, err = whereAnyOf(query, alternatives); err != nil {
+			return nil, err
+		}
+	}
+
+	return query, nil
 }
@@ -0,0 +1,122 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func extendedOutputWithFeatureBlocks(address iotago.Address, blocks ...iotago.FeatureBlock) *utxo.Output {
+	return utxo.CreateOutput(utils.RandOutputID(), utils.RandMessageID(), 1, 0, &iotago.ExtendedOutput{
+		Amount: 1_000_000,
+		Conditions: iotago.UnlockConditions{
+			&iotago.AddressUnlockCondition{Address: address},
+		},
+		Blocks: blocks,
+	})
+}
+
+func TestIndexerFeatureBlockFilters(t *testing.T) {
+
+	idx, err := NewIndexer(t.TempDir())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, idx.CloseDatabase()) }()
+
+	address := utils.RandAddress(iotago.AddressEd25519)
+
+	appOne := extendedOutputWithFeatureBlocks(address,
+		&iotago.MetadataFeatureBlock{Data: []byte(`{"app":"one","kind":"order"}`)},
+		&iotago.TagFeatureBlock{Tag: []byte("app-one")},
+	)
+	appTwo := extendedOutputWithFeatureBlocks(address,
+		&iotago.MetadataFeatureBlock{Data: []byte(`{"app":"two","kind":"order"}`)},
+		&iotago.TagFeatureBlock{Tag: []byte("app-two")},
+	)
+	other := extendedOutputWithFeatureBlocks(address,
+		&iotago.TagFeatureBlock{Tag: []byte{0xff, 0xff, 0x01}},
+	)
+	plain := extendedOutputWithFeatureBlocks(address)
+
+	nftOutput := utxo.CreateOutput(utils.RandOutputID(), utils.RandMessageID(), 1, 0, &iotago.NFTOutput{
+		Amount: 1_000_000,
+		NFTID:  utils.RandNFTID(),
+		Conditions: iotago.UnlockConditions{
+			&iotago.AddressUnlockCondition{Address: address},
+		},
+		Blocks: iotago.FeatureBlocks{
+			&iotago.MetadataFeatureBlock{Data: []byte("collection:birds")},
+			&iotago.TagFeatureBlock{Tag: []byte("app-nft")},
+		},
+	})
+
+	aliasOutput := utxo.CreateOutput(utils.RandOutputID(), utils.RandMessageID(), 1, 0, &iotago.AliasOutput{
+		Amount:  1_000_000,
+		AliasID: utils.RandAliasID(),
+		Conditions: iotago.UnlockConditions{
+			&iotago.StateControllerAddressUnlockCondition{Address: address},
+			&iotago.GovernorAddressUnlockCondition{Address: address},
+		},
+		Blocks: iotago.FeatureBlocks{
+			&iotago.MetadataFeatureBlock{Data: []byte("registry:v1")},
+		},
+	})
+
+	require.NoError(t, idx.UpdatedLedger(1, utxo.Outputs{appOne, appTwo, other, plain, nftOutput, aliasOutput}, nil))
+
+	outputIDs := func(result *IndexerResult) iotago.OutputIDs {
+		require.NoError(t, result.Error)
+		return result.OutputIDs
+	}
+
+	require.ElementsMatch(t, iotago.OutputIDs{*appOne.OutputID(), *appTwo.OutputID()}, outputIDs(idx.ExtendedOutputsWithFilters(ExtendedOutputTagPrefix([]byte("app-")))))
+	require.ElementsMatch(t, iotago.OutputIDs{*appTwo.OutputID()}, outputIDs(idx.ExtendedOutputsWithFilters(ExtendedOutputTagPrefix([]byte("app-t")))))
+	require.Empty(t, outputIDs(idx.ExtendedOutputsWithFilters(ExtendedOutputTagPrefix([]byte("app-three")))))
+
+	// a prefix without an upper bound
+	require.ElementsMatch(t, iotago.OutputIDs{*other.OutputID()}, outputIDs(idx.ExtendedOutputsWithFilters(ExtendedOutputTagPrefix([]byte{0xff, 0xff}))))
+
+	require.ElementsMatch(t, iotago.OutputIDs{*appOne.OutputID(), *appTwo.OutputID()}, outputIDs(idx.ExtendedOutputsWithFilters(ExtendedOutputMetadataContains([]byte(`"kind":"order"`)))))
+	require.ElementsMatch(t, iotago.OutputIDs{*appOne.OutputID()}, outputIDs(idx.ExtendedOutputsWithFilters(
+		ExtendedOutputTagPrefix([]byte("app-")),
+		ExtendedOutputMetadataContains([]byte(`"app":"one"`)),
+	)))
+
+	require.ElementsMatch(t, iotago.OutputIDs{*nftOutput.OutputID()}, outputIDs(idx.NFTOutputsWithFilters(NFTTagPrefix([]byte("app-")))))
+	require.ElementsMatch(t, iotago.OutputIDs{*nftOutput.OutputID()}, outputIDs(idx.NFTOutputsWithFilters(NFTMetadataContains([]byte("birds")))))
+	require.Empty(t, outputIDs(idx.NFTOutputsWithFilters(NFTMetadataContains([]byte("fish")))))
+
+	require.ElementsMatch(t, iotago.OutputIDs{*aliasOutput.OutputID()}, outputIDs(idx.AliasOutputsWithFilters(AliasMetadataContains([]byte("registry")))))
+}
+
+func TestIndexerClearedOnSchemaChange(t *testing.T) {
+
+	dbPath := t.TempDir()
+
+	idx, err := NewIndexer(dbPath)
+	require.NoError(t, err)
+	require.NoError(t, idx.UpdatedLedger(5, nil, nil))
+	require.NoError(t, idx.CloseDatabase())
+
+	// the current schema is kept
+	idx, err = NewIndexer(dbPath)
+	require.NoError(t, err)
+	ledgerIndex, err := idx.LedgerIndex()
+	require.NoError(t, err)
+	require.Equal(t, milestone.Index(5), ledgerIndex)
+
+	require.NoError(t, idx.db.Model(&status{}).Where("id = ?", 1).Update("schema_version", schemaVersion-1).Error)
+	require.NoError(t, idx.CloseDatabase())
+
+	// an index with an older schema needs to be imported again
+	idx, err = NewIndexer(dbPath)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, idx.CloseDatabase()) }()
+
+	_, err = idx.LedgerIndex()
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -3,23 +3,31 @@ package indexer
 import (
 	"time"
 
+	"gorm.io/gorm"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
 
 type foundry struct {
-	FoundryID foundryIDBytes `gorm:"primaryKey;notnull"`
-	OutputID  outputIDBytes  `gorm:"unique;notnull"`
-	Amount    uint64         `gorm:"notnull"`
-	Address   addressBytes   `gorm:"notnull;index:foundries_address"`
-	CreatedAt time.Time      `gorm:"notnull"`
+	OutputID  outputIDBytes    `gorm:"primaryKey;notnull"`
+	FoundryID foundryIDBytes   `gorm:"notnull;index:foundries_foundry_id"`
+	Amount    uint64           `gorm:"notnull"`
+	Address   addressBytes     `gorm:"notnull;index:foundries_address"`
+	CreatedAt time.Time        `gorm:"notnull"`
+	BookedAt  milestone.Index  `gorm:"notnull;index:foundries_booked_at"`
+	SpentAt   *milestone.Index `gorm:"index:foundries_spent_at"`
 }
 
 type FoundryFilterOptions struct {
 	unlockableByAddress *iotago.Address
 	pageSize            int
 	cursor              *string
+	sorting             resultSorting
 	createdBefore       *time.Time
 	createdAfter        *time.Time
+	bookedAt            *milestone.Index
+	anyOf               [][]*FoundryFilterOptions
 }
 
 type FoundryFilterOption func(*FoundryFilterOptions)
@@ -42,6 +50,27 @@ func FoundryCursor(cursor string) FoundryFilterOption {
 	}
 }
 
+// FoundryBookedAt filters for outputs that were booked at the given milestone index.
+func FoundryBookedAt(index milestone.Index) FoundryFilterOption {
+	return func(args *FoundryFilterOptions) {
+		args.bookedAt = &index
+	}
+}
+
+// FoundrySortByAmount sorts the results by their amount.
+func FoundrySortByAmount(order SortOrder) FoundryFilterOption {
+	return func(args *FoundryFilterOptions) {
+		args.sorting = resultSorting{column: sortByAmount, order: order}
+	}
+}
+
+// FoundrySortByCreatedAt sorts the results by their creation time, which is the default.
+func FoundrySortByCreatedAt(order SortOrder) FoundryFilterOption {
+	return func(args *FoundryFilterOptions) {
+		args.sorting = resultSorting{column: sortByCreatedAt, order: order}
+	}
+}
+
 func FoundryCreatedBefore(time time.Time) FoundryFilterOption {
 	return func(args *FoundryFilterOptions) {
 		args.createdBefore = &time
@@ -54,6 +83,19 @@ func FoundryCreatedAfter(time time.Time) FoundryFilterOption {
 	}
 }
 
+// FoundryAnyOf filters for foundries that match at least one of the given alternatives.
+// The filters of an alternative are combined with AND, its page size, cursor and sorting are ignored.
+// Every call adds another group that needs to match in addition to the other filters.
+func FoundryAnyOf(alternatives ...[]FoundryFilterOption) FoundryFilterOption {
+	return func(args *FoundryFilterOptions) {
+		group := make([]*FoundryFilterOptions, 0, len(alternatives))
+		for _, alternative := range alternatives {
+			group = append(group, foundryFilterOptions(alternative))
+		}
+		args.anyOf = append(args.anyOf, group)
+	}
+}
+
 func foundryFilterOptions(optionalOptions []FoundryFilterOption) *FoundryFilterOptions {
 	result := &FoundryFilterOptions{}
 
@@ -68,17 +110,39 @@ func (i *Indexer) FoundryOutput(foundryID *iotago.FoundryID) *IndexerResult {
 		Where("foundry_id = ?", foundryID[:]).
 		Limit(1)
 
-	return i.combineOutputIDFilteredQuery(query, 0, nil)
+	return i.combineOutputIDFilteredQuery(query, 0, nil, resultSorting{})
 }
 
 func (i *Indexer) FoundryOutputsWithFilters(filters ...FoundryFilterOption) *IndexerResult {
 	opts := foundryFilterOptions(filters)
-	query := i.db.Model(&foundry{})
 
+	query, err := opts.whereConditions(i.db.Model(&foundry{}))
+	if err != nil {
+		return errorResult(err)
+	}
+
+	return i.combineOutputIDFilteredQuery(query, opts.pageSize, opts.cursor, opts.sorting)
+}
+
+// FoundryOutputsAggregateWithFilters returns the number and the summed amount of the unspent outputs that match the filters.
+// The page size, the cursor and the sorting of the filters are ignored.
+func (i *Indexer) FoundryOutputsAggregateWithFilters(filters ...FoundryFilterOption) *AggregateResult {
+	opts := foundryFilterOptions(filters)
+
+	query, err := opts.whereConditions(i.db.Model(&foundry{}))
+	if err != nil {
+		return &AggregateResult{Error: err}
+	}
+
+	return i.combineAggregateQuery(query)
+}
+
+// whereConditions adds the conditions of the filter options to the query.
+func (opts *FoundryFilterOptions) whereConditions(query *gorm.DB) (*gorm.DB, error) {
 	if opts.unlockableByAddress != nil {
 		addr, err := addressBytesForAddress(*opts.unlockableByAddress)
 		if err != nil {
-			return errorResult(err)
+			return nil, err
 		}
 		query = query.Where("address = ?", addr[:])
 	}
@@ -91,5 +155,21 @@ func (i *Indexer) FoundryOutputsWithFilters(filters ...FoundryFilterOption) *Ind
 		query = query.Where("created_at > ?", *opts.createdAfter)
 	}
 
-	return i.combineOutputIDFilteredQuery(query, opts.pageSize, opts.cursor)
+	if opts.bookedAt != nil {
+		query = query.Where("booked_at = ?", *opts.bookedAt)
+	}
+
+	for _, group := range opts.anyOf {
+		alternatives := make([]whereConditionsFunc, 0, len(group))
+		for _, alternative := range group {
+			alternatives = append(alternatives, alternative.whereConditions)
+		}
+
+		var err error
+		if query, err = whereAnyOf(query, alternatives); err != nil {
+			return nil, err
+		}
+	}
+
+	return query, nil
 }
@@ -32,9 +32,12 @@ func (i *ImportTransaction) AddOutput(output *utxo.Output) error {
 
 func (i *ImportTransaction) Finalize(ledgerIndex milestone.Index) error {
 	// Update the ledger index
+	// the spent outputs before the import are unknown, so older queries can't be continued
 	status := &status{
-		ID:          1,
-		LedgerIndex: ledgerIndex,
+		ID:                1,
+		SchemaVersion:     schemaVersion,
+		LedgerIndex:       ledgerIndex,
+		OldestLedgerIndex: ledgerIndex,
 	}
 	i.tx.Clauses(clause.OnConflict{
 		UpdateAll: true,
@@ -1,11 +1,14 @@
 package indexer
 
 import (
+	"path/filepath"
+	"sync"
+
 	"github.com/pkg/errors"
+	"go.uber.org/atomic"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
-	"path/filepath"
 
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/utxo"
@@ -13,6 +16,11 @@ import (
 	iotago "github.com/iotaledger/iota.go/v3"
 )
 
+const (
+	// the name of the database file within the indexer directory.
+	dbFileName = "indexer.db"
+)
+
 var (
 	ErrNotFound = errors.New("output not found for given filter")
 
@@ -25,17 +33,55 @@ var (
 	}
 )
 
+// the default options applied to the indexer.
+var defaultOptions = []Option{
+	WithSpentOutputsRetention(60),
+}
+
+// Options define options for the indexer.
+type Options struct {
+	// the amount of milestones the spent outputs are kept for paginated queries.
+	spentOutputsRetention milestone.Index
+}
+
+// applies the given Option.
+func (so *Options) apply(opts ...Option) {
+	for _, opt := range opts {
+		opt(so)
+	}
+}
+
+// WithSpentOutputsRetention defines the amount of milestones the spent outputs are kept,
+// so that a paginated query can be continued at the ledger index it was started at.
+func WithSpentOutputsRetention(milestones milestone.Index) Option {
+	return func(opts *Options) {
+		opts.spentOutputsRetention = milestones
+	}
+}
+
+// Option is a function setting an indexer option.
+type Option func(opts *Options)
+
 type Indexer struct {
-	db *gorm.DB
+	db         *gorm.DB
+	queryStats *queryStats
+	// writeLock is used to serialize ledger updates and rebuilds.
+	writeLock  sync.Mutex
+	rebuilding atomic.Bool
+	options    *Options
 }
 
-func NewIndexer(dbPath string) (*Indexer, error) {
+func NewIndexer(dbPath string, opts ...Option) (*Indexer, error) {
+
+	options := &Options{}
+	options.apply(defaultOptions...)
+	options.apply(opts...)
 
 	if err := utils.CreateDirectory(dbPath, 0700); err != nil {
 		return nil, err
 	}
 
-	dbFile := filepath.Join(dbPath, "indexer.db")
+	dbFile := filepath.Join(dbPath, dbFileName)
 
 	db, err := gorm.Open(sqlite.Open(dbFile), &gorm.Config{})
 	if err != nil {
@@ -47,21 +93,55 @@ func NewIndexer(dbPath string) (*Indexer, error) {
 		return nil, err
 	}
 
-	return &Indexer{
-		db: db,
-	}, nil
+	// the issuer index of the NFTs was replaced by an index that also covers the creation time
+	if db.Migrator().HasIndex(&nft{}, "nft_issuer") {
+		if err := db.Migrator().DropIndex(&nft{}, "nft_issuer"); err != nil {
+			return nil, err
+		}
+	}
+
+	indexer := &Indexer{
+		db:         db,
+		queryStats: newQueryStats(),
+		options:    options,
+	}
+
+	// drop the outputs indexed with an older schema, so that they get imported again
+	status := &status{}
+	if err := db.Take(&status).Error; err == nil && status.SchemaVersion != schemaVersion {
+		if err := indexer.Clear(); err != nil {
+			return nil, err
+		}
+	}
+
+	return indexer, nil
 }
 
+// processSpent marks the output as spent instead of deleting it,
+// so that queries which are pinned to an older ledger index still contain it.
 func processSpent(spent *utxo.Spent, tx *gorm.DB) error {
+	var model interface{}
 	switch spent.OutputType() {
 	case iotago.OutputExtended:
-		return tx.Where("output_id = ?", spent.OutputID()[:]).Delete(&extendedOutput{}).Error
+		model = &extendedOutput{}
 	case iotago.OutputAlias:
-		return tx.Where("output_id = ?", spent.OutputID()[:]).Delete(&alias{}).Error
+		model = &alias{}
 	case iotago.OutputNFT:
-		return tx.Where("output_id = ?", spent.OutputID()[:]).Delete(&nft{}).Error
+		model = &nft{}
 	case iotago.OutputFoundry:
-		return tx.Where("output_id = ?", spent.OutputID()[:]).Delete(&foundry{}).Error
+		model = &foundry{}
+	default:
+		return nil
+	}
+	return tx.Model(model).Where("output_id = ?", spent.OutputID()[:]).Update("spent_at", spent.MilestoneIndex()).Error
+}
+
+// pruneSpent deletes the outputs that were spent at or before the given ledger index.
+func pruneSpent(ledgerIndex milestone.Index, tx *gorm.DB) error {
+	for _, model := range []interface{}{&extendedOutput{}, &alias{}, &nft{}, &foundry{}} {
+		if err := tx.Where("spent_at <= ?", ledgerIndex).Delete(model).Error; err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -83,6 +163,7 @@ func processOutput(output *utxo.Output, tx *gorm.DB) error {
 			OutputID:  make(outputIDBytes, iotago.OutputIDLength),
 			Amount:    iotaOutput.Amount,
 			CreatedAt: unixTime(output.MilestoneTimestamp()),
+			BookedAt:  output.MilestoneIndex(),
 		}
 		copy(extended.OutputID, output.OutputID()[:])
 
@@ -98,6 +179,11 @@ func processOutput(output *utxo.Output, tx *gorm.DB) error {
 			copy(extended.Tag, tagBlock.Tag)
 		}
 
+		if metadataBlock := features.MetadataFeatureBlock(); metadataBlock != nil {
+			extended.Metadata = make([]byte, len(metadataBlock.Data))
+			copy(extended.Metadata, metadataBlock.Data)
+		}
+
 		if addressUnlock := conditions.Address(); addressUnlock != nil {
 			extended.Address, err = addressBytesForAddress(addressUnlock.Address)
 			if err != nil {
@@ -165,6 +251,7 @@ func processOutput(output *utxo.Output, tx *gorm.DB) error {
 			OutputID:  make(outputIDBytes, iotago.OutputIDLength),
 			Amount:    iotaOutput.Amount,
 			CreatedAt: unixTime(output.MilestoneTimestamp()),
+			BookedAt:  output.MilestoneIndex(),
 		}
 		copy(alias.AliasID, aliasID[:])
 		copy(alias.OutputID, output.OutputID()[:])
@@ -183,6 +270,11 @@ func processOutput(output *utxo.Output, tx *gorm.DB) error {
 			}
 		}
 
+		if metadataBlock := features.MetadataFeatureBlock(); metadataBlock != nil {
+			alias.Metadata = make([]byte, len(metadataBlock.Data))
+			copy(alias.Metadata, metadataBlock.Data)
+		}
+
 		if stateController := conditions.StateControllerAddress(); stateController != nil {
 			alias.StateController, err = addressBytesForAddress(stateController.Address)
 			if err != nil {
@@ -224,6 +316,7 @@ func processOutput(output *utxo.Output, tx *gorm.DB) error {
 			OutputID:  make(outputIDBytes, iotago.OutputIDLength),
 			Amount:    iotaOutput.Amount,
 			CreatedAt: unixTime(output.MilestoneTimestamp()),
+			BookedAt:  output.MilestoneIndex(),
 		}
 		copy(nft.NFTID, nftID[:])
 		copy(nft.OutputID, output.OutputID()[:])
@@ -247,6 +340,11 @@ func processOutput(output *utxo.Output, tx *gorm.DB) error {
 			copy(nft.Tag, tagBlock.Tag)
 		}
 
+		if metadataBlock := features.MetadataFeatureBlock(); metadataBlock != nil {
+			nft.Metadata = make([]byte, len(metadataBlock.Data))
+			copy(nft.Metadata, metadataBlock.Data)
+		}
+
 		if addressUnlock := conditions.Address(); addressUnlock != nil {
 			nft.Address, err = addressBytesForAddress(addressUnlock.Address)
 			if err != nil {
@@ -308,6 +406,7 @@ func processOutput(output *utxo.Output, tx *gorm.DB) error {
 			OutputID:  make(outputIDBytes, iotago.OutputIDLength),
 			Amount:    iotaOutput.Amount,
 			CreatedAt: unixTime(output.MilestoneTimestamp()),
+			BookedAt:  output.MilestoneIndex(),
 		}
 		copy(foundry.OutputID, output.OutputID()[:])
 
@@ -331,6 +430,27 @@ func processOutput(output *utxo.Output, tx *gorm.DB) error {
 
 func (i *Indexer) UpdatedLedger(msIndex milestone.Index, newOutputs utxo.Outputs, newSpents utxo.Spents) error {
 
+	i.writeLock.Lock()
+	defer i.writeLock.Unlock()
+
+	// ledger updates that were queued while the index was rebuilt are already contained in the index
+	currentStatus, err := i.status()
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if err == nil && msIndex <= currentStatus.LedgerIndex {
+		return nil
+	}
+
+	// queries can only be pinned to ledger indexes for which all spent outputs are still contained
+	oldestLedgerIndex := milestone.Index(0)
+	if msIndex > i.options.spentOutputsRetention {
+		oldestLedgerIndex = msIndex - i.options.spentOutputsRetention
+	}
+	if currentStatus != nil && oldestLedgerIndex < currentStatus.OldestLedgerIndex {
+		oldestLedgerIndex = currentStatus.OldestLedgerIndex
+	}
+
 	tx := i.db.Begin()
 	defer func() {
 		if r := recover(); r != nil {
@@ -362,10 +482,17 @@ func (i *Indexer) UpdatedLedger(msIndex milestone.Index, newOutputs utxo.Outputs
 		}
 	}
 
+	if err := pruneSpent(oldestLedgerIndex, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// Update the ledger index
 	status := &status{
-		ID:          1,
-		LedgerIndex: msIndex,
+		ID:                1,
+		SchemaVersion:     schemaVersion,
+		LedgerIndex:       msIndex,
+		OldestLedgerIndex: oldestLedgerIndex,
 	}
 	tx.Clauses(clause.OnConflict{
 		UpdateAll: true,
@@ -374,12 +501,20 @@ func (i *Indexer) UpdatedLedger(msIndex milestone.Index, newOutputs utxo.Outputs
 	return tx.Commit().Error
 }
 
-func (i *Indexer) LedgerIndex() (milestone.Index, error) {
+func (i *Indexer) status() (*status, error) {
 	status := &status{}
 	if err := i.db.Take(&status).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return 0, ErrNotFound
+			return nil, ErrNotFound
 		}
+		return nil, err
+	}
+	return status, nil
+}
+
+func (i *Indexer) LedgerIndex() (milestone.Index, error) {
+	status, err := i.status()
+	if err != nil {
 		return 0, err
 	}
 	return status.LedgerIndex, nil
@@ -3,18 +3,21 @@ package indexer
 import (
 	"time"
 
+	"gorm.io/gorm"
+
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
 
 type nft struct {
-	NFTID                   nftIDBytes    `gorm:"primaryKey;notnull"`
-	OutputID                outputIDBytes `gorm:"unique;notnull"`
+	OutputID                outputIDBytes `gorm:"primaryKey;notnull"`
+	NFTID                   nftIDBytes    `gorm:"notnull;index:nft_nft_id"`
 	Amount                  uint64        `gorm:"notnull"`
-	Issuer                  addressBytes  `gorm:"index:nft_issuer"`
+	Issuer                  addressBytes  `gorm:"index:nft_issuer_created_at"`
 	Sender                  addressBytes  `gorm:"index:nft_sender_tag"`
-	Tag                     []byte        `gorm:"index:nft_sender_tag"`
-	Address                 addressBytes  `gorm:"notnull;index:nft_address"`
+	Tag                     []byte        `gorm:"index:nft_sender_tag;index:nft_tag"`
+	Metadata                []byte
+	Address                 addressBytes `gorm:"notnull;index:nft_address"`
 	DustReturn              *uint64
 	DustReturnAddress       addressBytes
 	TimelockMilestone       *milestone.Index
@@ -22,11 +25,14 @@ type nft struct {
 	ExpirationMilestone     *milestone.Index
 	ExpirationTime          *time.Time
 	ExpirationReturnAddress addressBytes
-	CreatedAt               time.Time `gorm:"notnull"`
+	CreatedAt               time.Time        `gorm:"notnull;index:nft_issuer_created_at"`
+	BookedAt                milestone.Index  `gorm:"notnull;index:nft_booked_at"`
+	SpentAt                 *milestone.Index `gorm:"index:nft_spent_at"`
 }
 
 type NFTFilterOptions struct {
 	unlockableByAddress       *iotago.Address
+	spendableBy               *spendableByFilter
 	hasDustReturnCondition    *bool
 	dustReturnAddress         *iotago.Address
 	hasExpirationCondition    *bool
@@ -43,10 +49,15 @@ type NFTFilterOptions struct {
 	issuer                    *iotago.Address
 	sender                    *iotago.Address
 	tag                       []byte
+	tagPrefix                 []byte
+	metadataContains          []byte
 	pageSize                  int
 	cursor                    *string
+	sorting                   resultSorting
 	createdBefore             *time.Time
 	createdAfter              *time.Time
+	bookedAt                  *milestone.Index
+	anyOf                     [][]*NFTFilterOptions
 }
 
 type NFTFilterOption func(*NFTFilterOptions)
@@ -57,6 +68,19 @@ func NFTUnlockableByAddress(address iotago.Address) NFTFilterOption {
 	}
 }
 
+// NFTSpendableByAddress filters for outputs that the address can unlock at the given milestone index and timestamp,
+// taking timelock and expiration unlock conditions into account.
+// Outputs with a dust return unlock condition are included, since the address can unlock them by returning the deposit.
+func NFTSpendableByAddress(address iotago.Address, msIndex milestone.Index, timestamp time.Time) NFTFilterOption {
+	return func(args *NFTFilterOptions) {
+		args.spendableBy = &spendableByFilter{
+			address:   address,
+			msIndex:   msIndex,
+			timestamp: timestamp,
+		}
+	}
+}
+
 func NFTHasDustReturnCondition(requiresDustReturn bool) NFTFilterOption {
 	return func(args *NFTFilterOptions) {
 		args.hasDustReturnCondition = &requiresDustReturn
@@ -153,6 +177,20 @@ func NFTTag(tag []byte) NFTFilterOption {
 	}
 }
 
+// NFTTagPrefix filters for NFTs with a tag feature block that starts with the given prefix.
+func NFTTagPrefix(prefix []byte) NFTFilterOption {
+	return func(args *NFTFilterOptions) {
+		args.tagPrefix = prefix
+	}
+}
+
+// NFTMetadataContains filters for NFTs with a metadata feature block that contains the given data.
+func NFTMetadataContains(data []byte) NFTFilterOption {
+	return func(args *NFTFilterOptions) {
+		args.metadataContains = data
+	}
+}
+
 func NFTPageSize(pageSize int) NFTFilterOption {
 	return func(args *NFTFilterOptions) {
 		args.pageSize = pageSize
@@ -165,6 +203,27 @@ func NFTCursor(cursor string) NFTFilterOption {
 	}
 }
 
+// NFTBookedAt filters for outputs that were booked at the given milestone index.
+func NFTBookedAt(index milestone.Index) NFTFilterOption {
+	return func(args *NFTFilterOptions) {
+		args.bookedAt = &index
+	}
+}
+
+// NFTSortByAmount sorts the results by their amount.
+func NFTSortByAmount(order SortOrder) NFTFilterOption {
+	return func(args *NFTFilterOptions) {
+		args.sorting = resultSorting{column: sortByAmount, order: order}
+	}
+}
+
+// NFTSortByCreatedAt sorts the results by their creation time, which is the default.
+func NFTSortByCreatedAt(order SortOrder) NFTFilterOption {
+	return func(args *NFTFilterOptions) {
+		args.sorting = resultSorting{column: sortByCreatedAt, order: order}
+	}
+}
+
 func NFTCreatedBefore(time time.Time) NFTFilterOption {
 	return func(args *NFTFilterOptions) {
 		args.createdBefore = &time
@@ -177,6 +236,19 @@ func NFTCreatedAfter(time time.Time) NFTFilterOption {
 	}
 }
 
+// NFTAnyOf filters for NFTs that match at least one of the given alternatives.
+// The filters of an alternative are combined with AND, its page size, cursor and sorting are ignored.
+// Every call adds another group that needs to match in addition to the other filters.
+func NFTAnyOf(alternatives ...[]NFTFilterOption) NFTFilterOption {
+	return func(args *NFTFilterOptions) {
+		group := make([]*NFTFilterOptions, 0, len(alternatives))
+		for _, alternative := range alternatives {
+			group = append(group, nftFilterOptions(alternative))
+		}
+		args.anyOf = append(args.anyOf, group)
+	}
+}
+
 func nftFilterOptions(optionalOptions []NFTFilterOption) *NFTFilterOptions {
 	result := &NFTFilterOptions{}
 
@@ -191,21 +263,50 @@ func (i *Indexer) NFTOutput(nftID *iotago.NFTID) *IndexerResult {
 		Where("nft_id = ?", nftID[:]).
 		Limit(1)
 
-	return i.combineOutputIDFilteredQuery(query, 0, nil)
+	return i.combineOutputIDFilteredQuery(query, 0, nil, resultSorting{})
 }
 
 func (i *Indexer) NFTOutputsWithFilters(filters ...NFTFilterOption) *IndexerResult {
 	opts := nftFilterOptions(filters)
-	query := i.db.Model(&nft{})
 
+	query, err := opts.whereConditions(i.db.Model(&nft{}))
+	if err != nil {
+		return errorResult(err)
+	}
+
+	return i.combineOutputIDFilteredQuery(query, opts.pageSize, opts.cursor, opts.sorting)
+}
+
+// NFTOutputsAggregateWithFilters returns the number and the summed amount of the unspent outputs that match the filters.
+// The page size, the cursor and the sorting of the filters are ignored.
+func (i *Indexer) NFTOutputsAggregateWithFilters(filters ...NFTFilterOption) *AggregateResult {
+	opts := nftFilterOptions(filters)
+
+	query, err := opts.whereConditions(i.db.Model(&nft{}))
+	if err != nil {
+		return &AggregateResult{Error: err}
+	}
+
+	return i.combineAggregateQuery(query)
+}
+
+// whereConditions adds the conditions of the filter options to the query.
+func (opts *NFTFilterOptions) whereConditions(query *gorm.DB) (*gorm.DB, error) {
 	if opts.unlockableByAddress != nil {
 		addr, err := addressBytesForAddress(*opts.unlockableByAddress)
 		if err != nil {
-			return errorResult(err)
+			return nil, err
 		}
 		query = query.Where("address = ?", addr[:])
 	}
 
+	if opts.spendableBy != nil {
+		var err error
+		if query, err = whereSpendableBy(query, opts.spendableBy); err != nil {
+			return nil, err
+		}
+	}
+
 	if opts.hasDustReturnCondition != nil {
 		if *opts.hasDustReturnCondition {
 			query = query.Where("dust_return IS NOT NULL")
@@ -217,7 +318,7 @@ func (i *Indexer) NFTOutputsWithFilters(filters ...NFTFilterOption) *IndexerResu
 	if opts.dustReturnAddress != nil {
 		addr, err := addressBytesForAddress(*opts.dustReturnAddress)
 		if err != nil {
-			return errorResult(err)
+			return nil, err
 		}
 		query = query.Where("dust_return_address = ?", addr[:])
 	}
@@ -233,7 +334,7 @@ func (i *Indexer) NFTOutputsWithFilters(filters ...NFTFilterOption) *IndexerResu
 	if opts.expirationReturnAddress != nil {
 		addr, err := addressBytesForAddress(*opts.expirationReturnAddress)
 		if err != nil {
-			return errorResult(err)
+			return nil, err
 		}
 		query = query.Where("expiration_return_address = ?", addr[:])
 	}
@@ -281,7 +382,7 @@ func (i *Indexer) NFTOutputsWithFilters(filters ...NFTFilterOption) *IndexerResu
 	if opts.issuer != nil {
 		addr, err := addressBytesForAddress(*opts.issuer)
 		if err != nil {
-			return errorResult(err)
+			return nil, err
 		}
 		query = query.Where("issuer = ?", addr[:])
 	}
@@ -289,7 +390,7 @@ func (i *Indexer) NFTOutputsWithFilters(filters ...NFTFilterOption) *IndexerResu
 	if opts.sender != nil {
 		addr, err := addressBytesForAddress(*opts.sender)
 		if err != nil {
-			return errorResult(err)
+			return nil, err
 		}
 		query = query.Where("sender = ?", addr[:])
 	}
@@ -298,6 +399,14 @@ func (i *Indexer) NFTOutputsWithFilters(filters ...NFTFilterOption) *IndexerResu
 		query = query.Where("tag = ?", opts.tag)
 	}
 
+	if len(opts.tagPrefix) > 0 {
+		query = whereBytesPrefix(query, "tag", opts.tagPrefix)
+	}
+
+	if len(opts.metadataContains) > 0 {
+		query = whereBytesContain(query, "metadata", opts.metadataContains)
+	}
+
 	if opts.createdBefore != nil {
 		query = query.Where("created_at < ?", *opts.createdBefore)
 	}
@@ -306,5 +415,21 @@ func (i *Indexer) NFTOutputsWithFilters(filters ...NFTFilterOption) *IndexerResu
 		query = query.Where("created_at > ?", *opts.createdAfter)
 	}
 
-	return i.combineOutputIDFilteredQuery(query, opts.pageSize, opts.cursor)
+	if opts.bookedAt != nil {
+		query = query.Where("booked_at = ?", *opts.bookedAt)
+	}
+
+	for _, group := range opts.anyOf {
+		alternatives := make([]whereConditionsFunc, 0, len(group))
+		for _, alternative := range group {
+			alternatives = append(alternatives, alternative.whereConditions)
+		}
+
+		var err error
+		if query, err = whereAnyOf(query, alternatives); err != nil {
+			return nil, err
+		}
+	}
+
+	return query, nil
 }
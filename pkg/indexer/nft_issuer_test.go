@@ -0,0 +1,86 @@
+package indexer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func nftOutputWithIssuer(issuer iotago.Address, msIndex milestone.Index, msTimestamp uint64) *utxo.Output {
+	return utxo.CreateOutput(utils.RandOutputID(), utils.RandMessageID(), msIndex, msTimestamp, &iotago.NFTOutput{
+		Amount: 1_000_000,
+		NFTID:  utils.RandNFTID(),
+		Conditions: iotago.UnlockConditions{
+			&iotago.AddressUnlockCondition{Address: utils.RandAddress(iotago.AddressEd25519)},
+		},
+		Blocks: iotago.FeatureBlocks{
+			&iotago.IssuerFeatureBlock{Address: issuer},
+		},
+	})
+}
+
+func TestIndexerNFTIssuer(t *testing.T) {
+
+	idx, err := NewIndexer(t.TempDir())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, idx.CloseDatabase()) }()
+
+	require.True(t, idx.db.Migrator().HasIndex(&nft{}, "nft_issuer_created_at"))
+
+	issuer := utils.RandAddress(iotago.AddressEd25519)
+
+	// the NFTs of a collection are minted with the address of the collection NFT as issuer
+	collectionNFTID := utils.RandNFTID()
+	collection := collectionNFTID.ToAddress()
+
+	minted := utxo.Outputs{
+		nftOutputWithIssuer(issuer, 1, 1000),
+		nftOutputWithIssuer(issuer, 1, 2000),
+		nftOutputWithIssuer(issuer, 1, 3000),
+	}
+	collectionItems := utxo.Outputs{
+		nftOutputWithIssuer(collection, 1, 1000),
+		nftOutputWithIssuer(collection, 1, 2000),
+	}
+	other := nftOutputWithIssuer(utils.RandAddress(iotago.AddressEd25519), 1, 1000)
+
+	require.NoError(t, idx.UpdatedLedger(1, append(append(utxo.Outputs{other}, minted...), collectionItems...), nil))
+
+	result := idx.NFTOutputsWithFilters(NFTIssuer(issuer))
+	require.NoError(t, result.Error)
+	require.ElementsMatch(t, iotago.OutputIDs{*minted[0].OutputID(), *minted[1].OutputID(), *minted[2].OutputID()}, result.OutputIDs)
+
+	result = idx.NFTOutputsWithFilters(NFTIssuer(collection))
+	require.NoError(t, result.Error)
+	require.ElementsMatch(t, iotago.OutputIDs{*collectionItems[0].OutputID(), *collectionItems[1].OutputID()}, result.OutputIDs)
+
+	// the pages of an issuer are ordered by creation time
+	var pagedOutputIDs iotago.OutputIDs
+	var cursor *string
+	for {
+		filters := []NFTFilterOption{NFTIssuer(issuer), NFTPageSize(2)}
+		if cursor != nil {
+			filters = append(filters, NFTCursor(*cursor))
+		}
+		page := idx.NFTOutputsWithFilters(filters...)
+		require.NoError(t, page.Error)
+		require.LessOrEqual(t, len(page.OutputIDs), 2)
+		pagedOutputIDs = append(pagedOutputIDs, page.OutputIDs...)
+
+		if page.Cursor == nil {
+			break
+		}
+		cursor = page.Cursor
+	}
+	require.Equal(t, iotago.OutputIDs{*minted[0].OutputID(), *minted[1].OutputID(), *minted[2].OutputID()}, pagedOutputIDs)
+
+	result = idx.NFTOutputsWithFilters(NFTIssuer(issuer), NFTCreatedAfter(time.Unix(1000, 0)), NFTCreatedBefore(time.Unix(3000, 0)))
+	require.NoError(t, result.Error)
+	require.Equal(t, iotago.OutputIDs{*minted[1].OutputID()}, result.OutputIDs)
+}
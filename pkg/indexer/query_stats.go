@@ -0,0 +1,334 @@
+package indexer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// queryStatsLatencySamples is the amount of latest query durations kept per filter combination.
+	queryStatsLatencySamples = 128
+
+	// recommendedIndexPrefix is the name prefix of the indexes created by CreateRecommendedIndexes.
+	recommendedIndexPrefix = "stats"
+
+	// orderColumn is the first column the results of all filtered queries are ordered by.
+	orderColumn = "created_at"
+)
+
+var (
+	// matches the column names and the operators in the where conditions of the filtered queries.
+	whereConditionRegex = regexp.MustCompile(`([a-z_]+)\s*(=|<|>|IS\s)`)
+
+	// the columns that identify the output chains, their lookups are served by dedicated indexes.
+	chainIDColumns = map[string]struct{}{
+		"alias_id":   {},
+		"nft_id":     {},
+		"foundry_id": {},
+	}
+)
+
+// QueryFilterStats holds the statistics of all queries on a table that used the same combination of filters.
+type QueryFilterStats struct {
+	// The table the queries were executed on.
+	Table string
+	// The columns that were compared for equality (or checked for NULL), sorted by name.
+	EqualityColumns []string
+	// The columns that were compared with a range condition, sorted by name.
+	RangeColumns []string
+	// The amount of executed queries.
+	Count uint64
+	// The average duration of the queries.
+	AvgDuration time.Duration
+	// The 95th percentile of the duration of the latest queries.
+	P95Duration time.Duration
+	// The maximum duration of the queries.
+	MaxDuration time.Duration
+}
+
+// IndexRecommendation is a composite index recommended for a frequently used filter combination.
+type IndexRecommendation struct {
+	// The name of the index.
+	Name string
+	// The table of the index.
+	Table string
+	// The columns of the index in index order.
+	Columns []string
+	// The amount of queries that would have used the index.
+	Queries uint64
+	// Whether the index was already created.
+	Exists bool
+}
+
+// queryFilterEntry collects the statistics of a single filter combination.
+type queryFilterEntry struct {
+	table           string
+	equalityColumns []string
+	rangeColumns    []string
+	count           uint64
+	totalDuration   time.Duration
+	maxDuration     time.Duration
+	// ring buffer of the latest query durations.
+	latencies []time.Duration
+	next      int
+}
+
+func (e *queryFilterEntry) stats() *QueryFilterStats {
+	latencies := make([]time.Duration, len(e.latencies))
+	copy(latencies, e.latencies)
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	var p95 time.Duration
+	if len(latencies) > 0 {
+		p95 = latencies[(len(latencies)*95+99)/100-1]
+	}
+
+	return &QueryFilterStats{
+		Table:           e.table,
+		EqualityColumns: append([]string{}, e.equalityColumns...),
+		RangeColumns:    append([]string{}, e.rangeColumns...),
+		Count:           e.count,
+		AvgDuration:     e.totalDuration / time.Duration(e.count),
+		P95Duration:     p95,
+		MaxDuration:     e.maxDuration,
+	}
+}
+
+// queryStats tracks which filter combinations are used by the queries of the indexer.
+type queryStats struct {
+	sync.Mutex
+	entries map[string]*queryFilterEntry
+}
+
+func newQueryStats() *queryStats {
+	return &queryStats{
+		entries: make(map[string]*queryFilterEntry),
+	}
+}
+
+// filterColumns returns the columns used in the where conditions of the given query,
+// split into columns compared for equality and columns compared with a range condition.
+func filterColumns(query *gorm.DB) (equalityColumns []string, rangeColumns []string) {
+	whereClause, exists := query.Statement.Clauses["WHERE"]
+	if !exists {
+		return nil, nil
+	}
+
+	where, ok := whereClause.Expression.(clause.Where)
+	if !ok {
+		return nil, nil
+	}
+
+	equality := make(map[string]struct{})
+	ranges := make(map[string]struct{})
+	for _, expr := range where.Exprs {
+		sqlExpr, ok := expr.(clause.Expr)
+		if !ok {
+			continue
+		}
+
+		for _, match := range whereConditionRegex.FindAllStringSubmatch(sqlExpr.SQL, -1) {
+			if match[2] == "<" || match[2] == ">" {
+				ranges[match[1]] = struct{}{}
+				continue
+			}
+			equality[match[1]] = struct{}{}
+		}
+	}
+
+	sortedKeys := func(m map[string]struct{}) []string {
+		keys := make([]string, 0, len(m))
+		for key := range m {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	// a column compared for equality doesn't need a range index
+	for column := range equality {
+		delete(ranges, column)
+	}
+
+	return sortedKeys(equality), sortedKeys(ranges)
+}
+
+// recordQuery adds the duration of a filtered query on the table of the given model to the statistics.
+// Lookups by primary key or chain ID are ignored, because they can't be improved by another index.
+func (s *queryStats) recordQuery(db *gorm.DB, model interface{}, equalityColumns []string, rangeColumns []string, duration time.Duration) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return
+	}
+
+	for _, column := range equalityColumns {
+		if _, isChainID := chainIDColumns[column]; isChainID {
+			return
+		}
+		for _, primaryKey := range stmt.Schema.PrimaryFieldDBNames {
+			if column == primaryKey {
+				return
+			}
+		}
+	}
+
+	s.record(stmt.Table, equalityColumns, rangeColumns, duration)
+}
+
+// record adds the duration of a query with the given filters to the statistics.
+func (s *queryStats) record(table string, equalityColumns []string, rangeColumns []string, duration time.Duration) {
+	key := fmt.Sprintf("%s|%s|%s", table, strings.Join(equalityColumns, ","), strings.Join(rangeColumns, ","))
+
+	s.Lock()
+	defer s.Unlock()
+
+	entry, exists := s.entries[key]
+	if !exists {
+		entry = &queryFilterEntry{
+			table:           table,
+			equalityColumns: equalityColumns,
+			rangeColumns:    rangeColumns,
+			latencies:       make([]time.Duration, 0, queryStatsLatencySamples),
+		}
+		s.entries[key] = entry
+	}
+
+	entry.count++
+	entry.totalDuration += duration
+	if duration > entry.maxDuration {
+		entry.maxDuration = duration
+	}
+
+	if len(entry.latencies) < queryStatsLatencySamples {
+		entry.latencies = append(entry.latencies, duration)
+		return
+	}
+	entry.latencies[entry.next] = duration
+	entry.next = (entry.next + 1) % queryStatsLatencySamples
+}
+
+// snapshot returns the statistics of all filter combinations, sorted by their query count.
+func (s *queryStats) snapshot() []*QueryFilterStats {
+	s.Lock()
+	defer s.Unlock()
+
+	stats := make([]*QueryFilterStats, 0, len(s.entries))
+	for _, entry := range s.entries {
+		stats = append(stats, entry.stats())
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].Table < stats[j].Table
+	})
+
+	return stats
+}
+
+// reset removes all collected statistics.
+func (s *queryStats) reset() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.entries = make(map[string]*queryFilterEntry)
+}
+
+// recommendedIndexColumns returns the columns of a composite index for the given filters.
+// The equality columns come first, followed by a single range column, because SQLite can't use
+// the index for any column after the first range condition. If there is no range condition,
+// the index ends with the column the results are ordered by, so the sorting is done by the index.
+func recommendedIndexColumns(stats *QueryFilterStats) []string {
+	columns := append([]string{}, stats.EqualityColumns...)
+
+	if len(stats.RangeColumns) > 0 {
+		return append(columns, stats.RangeColumns[0])
+	}
+	return append(columns, orderColumn)
+}
+
+// QueryStats returns the statistics of the filter combinations used since the indexer was started,
+// sorted by their query count.
+func (i *Indexer) QueryStats() []*QueryFilterStats {
+	return i.queryStats.snapshot()
+}
+
+// ResetQueryStats removes all collected query statistics.
+func (i *Indexer) ResetQueryStats() {
+	i.queryStats.reset()
+}
+
+// RecommendedIndexes returns the composite indexes for all filter combinations
+// that were used in at least minQueries queries.
+func (i *Indexer) RecommendedIndexes(minQueries uint64) []*IndexRecommendation {
+
+	recommendations := make([]*IndexRecommendation, 0)
+	seen := make(map[string]*IndexRecommendation)
+
+	for _, stats := range i.QueryStats() {
+		if stats.Count < minQueries {
+			continue
+		}
+
+		columns := recommendedIndexColumns(stats)
+		if len(columns) < 2 {
+			// single column indexes are not tuned by the statistics
+			continue
+		}
+
+		name := fmt.Sprintf("%s_%s_%s", recommendedIndexPrefix, stats.Table, strings.Join(columns, "_"))
+		if recommendation, exists := seen[name]; exists {
+			// different filter combinations can result in the same index
+			recommendation.Queries += stats.Count
+			continue
+		}
+
+		recommendation := &IndexRecommendation{
+			Name:    name,
+			Table:   stats.Table,
+			Columns: columns,
+			Queries: stats.Count,
+			Exists:  i.db.Migrator().HasIndex(stats.Table, name),
+		}
+		seen[name] = recommendation
+		recommendations = append(recommendations, recommendation)
+	}
+
+	return recommendations
+}
+
+// CreateRecommendedIndexes creates the recommended composite indexes for all filter combinations
+// that were used in at least minQueries queries. The indexes are backfilled with the existing rows on creation.
+// Returns the indexes that were created.
+func (i *Indexer) CreateRecommendedIndexes(minQueries uint64) ([]*IndexRecommendation, error) {
+
+	created := make([]*IndexRecommendation, 0)
+	for _, recommendation := range i.RecommendedIndexes(minQueries) {
+		if recommendation.Exists {
+			continue
+		}
+
+		quotedColumns := make([]string, len(recommendation.Columns))
+		for idx, column := range recommendation.Columns {
+			quotedColumns[idx] = fmt.Sprintf("`%s`", column)
+		}
+
+		if err := i.db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS `%s` ON `%s` (%s)", recommendation.Name, recommendation.Table, strings.Join(quotedColumns, ", "))).Error; err != nil {
+			return created, errors.Wrapf(err, "creating index %s failed", recommendation.Name)
+		}
+
+		recommendation.Exists = true
+		created = append(created, recommendation)
+	}
+
+	return created, nil
+}
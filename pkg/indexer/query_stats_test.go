@@ -0,0 +1,82 @@
+package indexer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestQueryStatsRecommendedIndexes(t *testing.T) {
+
+	idx, err := NewIndexer(t.TempDir())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, idx.CloseDatabase()) }()
+
+	addr := &iotago.Ed25519Address{}
+
+	for n := 0; n < 3; n++ {
+		result := idx.ExtendedOutputsWithFilters(
+			ExtendedOutputUnlockableByAddress(addr),
+			ExtendedOutputTag([]byte("tag")),
+			ExtendedOutputCreatedAfter(time.Unix(0, 0)),
+			ExtendedOutputPageSize(10),
+			ExtendedOutputCursor(strings.Repeat("0", CursorLength)),
+		)
+		require.NoError(t, result.Error)
+	}
+
+	result := idx.ExtendedOutputsWithFilters(ExtendedOutputHasTimelockCondition(false))
+	require.NoError(t, result.Error)
+
+	// primary key lookups are not tracked
+	require.NoError(t, idx.NFTOutput(&iotago.NFTID{}).Error)
+
+	stats := idx.QueryStats()
+	require.Len(t, stats, 2)
+
+	require.Equal(t, "extended_outputs", stats[0].Table)
+	require.Equal(t, []string{"address", "tag"}, stats[0].EqualityColumns)
+	require.Equal(t, []string{"created_at"}, stats[0].RangeColumns)
+	require.EqualValues(t, 3, stats[0].Count)
+	require.LessOrEqual(t, stats[0].P95Duration, stats[0].MaxDuration)
+
+	require.Equal(t, []string{"timelock_milestone", "timelock_time"}, stats[1].EqualityColumns)
+	require.Empty(t, stats[1].RangeColumns)
+	require.EqualValues(t, 1, stats[1].Count)
+
+	recommendations := idx.RecommendedIndexes(2)
+	require.Len(t, recommendations, 1)
+	require.Equal(t, "stats_extended_outputs_address_tag_created_at", recommendations[0].Name)
+	require.Equal(t, []string{"address", "tag", "created_at"}, recommendations[0].Columns)
+	require.False(t, recommendations[0].Exists)
+
+	recommendations = idx.RecommendedIndexes(1)
+	require.Len(t, recommendations, 2)
+	require.Equal(t, []string{"timelock_milestone", "timelock_time", "created_at"}, recommendations[1].Columns)
+
+	created, err := idx.CreateRecommendedIndexes(2)
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	require.True(t, idx.db.Migrator().HasIndex("extended_outputs", created[0].Name))
+
+	// existing indexes are not created again
+	created, err = idx.CreateRecommendedIndexes(1)
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	require.Equal(t, "stats_extended_outputs_timelock_milestone_timelock_time_created_at", created[0].Name)
+
+	for _, recommendation := range idx.RecommendedIndexes(1) {
+		require.True(t, recommendation.Exists)
+	}
+
+	// the queries still work with the new indexes
+	result = idx.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(addr), ExtendedOutputTag([]byte("tag")))
+	require.NoError(t, result.Error)
+
+	idx.ResetQueryStats()
+	require.Empty(t, idx.QueryStats())
+}
@@ -0,0 +1,108 @@
+package indexer
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+)
+
+var (
+	ErrRebuildInProgress = errors.New("indexer rebuild already in progress")
+)
+
+// RebuildProgress is the progress of a rebuild of the index.
+type RebuildProgress struct {
+	// LedgerIndex is the ledger index the index gets rebuilt at.
+	LedgerIndex milestone.Index
+	// TotalOutputs is the amount of unspent outputs in the ledger.
+	TotalOutputs int
+	// ImportedOutputs is the amount of unspent outputs that were already imported.
+	ImportedOutputs int
+	// Done is set once all outputs were imported and the rebuild was committed.
+	Done bool
+}
+
+// RebuildProgressFunc is called with the current progress during a rebuild.
+type RebuildProgressFunc func(progress *RebuildProgress)
+
+// IsRebuilding returns whether the index is currently being rebuilt.
+// The tables are dropped during a rebuild, so query results are incomplete until it is done.
+func (i *Indexer) IsRebuilding() bool {
+	return i.rebuilding.Load()
+}
+
+// Rebuild drops all tables and imports the unspent outputs of the given UTXO ledger again.
+// The ledger is read locked during the rebuild, so no milestone can be confirmed in the meantime.
+// onProgress is called after every progressInterval imported outputs and once the rebuild is done.
+func (i *Indexer) Rebuild(utxoManager *utxo.Manager, progressInterval int, onProgress RebuildProgressFunc) error {
+
+	if !i.rebuilding.CAS(false, true) {
+		return ErrRebuildInProgress
+	}
+	defer i.rebuilding.Store(false)
+
+	i.writeLock.Lock()
+	defer i.writeLock.Unlock()
+
+	utxoManager.ReadLockLedger()
+	defer utxoManager.ReadUnlockLedger()
+
+	ledgerIndex, err := utxoManager.ReadLedgerIndexWithoutLocking()
+	if err != nil {
+		return err
+	}
+
+	_, totalOutputs, err := utxoManager.ComputeLedgerBalance(utxo.ReadLockLedger(false))
+	if err != nil {
+		return err
+	}
+
+	progress := &RebuildProgress{
+		LedgerIndex:  ledgerIndex,
+		TotalOutputs: totalOutputs,
+	}
+
+	reportProgress := func() {
+		if onProgress != nil {
+			onProgress(progress)
+		}
+	}
+	reportProgress()
+
+	if err := i.Clear(); err != nil {
+		return err
+	}
+
+	importer := i.ImportTransaction()
+
+	var importErr error
+	if err := utxoManager.ForEachUnspentOutput(func(output *utxo.Output) bool {
+		if err := importer.AddOutput(output); err != nil {
+			importErr = err
+			return false
+		}
+
+		progress.ImportedOutputs++
+		if progressInterval > 0 && progress.ImportedOutputs%progressInterval == 0 {
+			reportProgress()
+		}
+		return true
+	}, utxo.ReadLockLedger(false)); err != nil {
+		_ = importer.Cancel()
+		return err
+	}
+	if importErr != nil {
+		// the transaction was already rolled back by the importer
+		return importErr
+	}
+
+	if err := importer.Finalize(ledgerIndex); err != nil {
+		return err
+	}
+
+	progress.Done = true
+	reportProgress()
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestIndexerRebuild(t *testing.T) {
+
+	idx, err := NewIndexer(t.TempDir())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, idx.CloseDatabase()) }()
+
+	address := utils.RandAddress(iotago.AddressEd25519)
+
+	// an output that only exists in the index, e.g. because of a corruption
+	stale := extendedOutputWithFeatureBlocks(address)
+	require.NoError(t, idx.UpdatedLedger(3, utxo.Outputs{stale}, nil))
+
+	utxoManager := utxo.New(mapdb.NewMapDB())
+	outputs := utxo.Outputs{
+		extendedOutputWithFeatureBlocks(address),
+		extendedOutputWithFeatureBlocks(address),
+		extendedOutputWithFeatureBlocks(address),
+	}
+	for _, output := range outputs {
+		require.NoError(t, utxoManager.AddUnspentOutput(output))
+	}
+	require.NoError(t, utxoManager.StoreLedgerIndex(5))
+
+	var progresses []RebuildProgress
+	require.NoError(t, idx.Rebuild(utxoManager, 2, func(progress *RebuildProgress) {
+		require.True(t, idx.IsRebuilding())
+		progresses = append(progresses, *progress)
+	}))
+	require.False(t, idx.IsRebuilding())
+
+	require.Equal(t, []RebuildProgress{
+		{LedgerIndex: 5, TotalOutputs: 3, ImportedOutputs: 0},
+		{LedgerIndex: 5, TotalOutputs: 3, ImportedOutputs: 2},
+		{LedgerIndex: 5, TotalOutputs: 3, ImportedOutputs: 3, Done: true},
+	}, progresses)
+
+	ledgerIndex, err := idx.LedgerIndex()
+	require.NoError(t, err)
+	require.Equal(t, milestone.Index(5), ledgerIndex)
+
+	result := idx.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address))
+	require.NoError(t, result.Error)
+	require.ElementsMatch(t, iotago.OutputIDs{*outputs[0].OutputID(), *outputs[1].OutputID(), *outputs[2].OutputID()}, result.OutputIDs)
+
+	// ledger updates that were queued during the rebuild are already contained in the index
+	require.NoError(t, idx.UpdatedLedger(5, utxo.Outputs{outputs[0]}, nil))
+
+	newOutput := extendedOutputWithFeatureBlocks(address)
+	require.NoError(t, idx.UpdatedLedger(6, utxo.Outputs{newOutput}, nil))
+
+	result = idx.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address))
+	require.NoError(t, result.Error)
+	require.Len(t, result.OutputIDs, 4)
+}
@@ -0,0 +1,81 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestIndexerSorting(t *testing.T) {
+
+	idx, err := NewIndexer(t.TempDir())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, idx.CloseDatabase()) }()
+
+	address := utils.RandAddress(iotago.AddressEd25519)
+
+	// the outputs are created in this order, with amounts that don't follow the creation time
+	amounts := []uint64{3_000_000, 1_000_000, 5_000_000, 2_000_000, 4_000_000}
+	outputs := make(utxo.Outputs, 0, len(amounts))
+	for i, amount := range amounts {
+		outputs = append(outputs, utxo.CreateOutput(utils.RandOutputID(), utils.RandMessageID(), 1, uint64(1000+i), &iotago.ExtendedOutput{
+			Amount: amount,
+			Conditions: iotago.UnlockConditions{
+				&iotago.AddressUnlockCondition{Address: address},
+			},
+		}))
+	}
+	require.NoError(t, idx.UpdatedLedger(1, outputs, nil))
+
+	outputIDs := func(indexes ...int) iotago.OutputIDs {
+		result := make(iotago.OutputIDs, 0, len(indexes))
+		for _, i := range indexes {
+			result = append(result, *outputs[i].OutputID())
+		}
+		return result
+	}
+
+	// pagedOutputIDs collects all pages of the query
+	pagedOutputIDs := func(sorting ExtendedOutputFilterOption) iotago.OutputIDs {
+		var result iotago.OutputIDs
+		var cursor *string
+		for {
+			filters := []ExtendedOutputFilterOption{ExtendedOutputUnlockableByAddress(address), ExtendedOutputPageSize(2), sorting}
+			if cursor != nil {
+				filters = append(filters, ExtendedOutputCursor(*cursor))
+			}
+
+			page := idx.ExtendedOutputsWithFilters(filters...)
+			require.NoError(t, page.Error)
+			result = append(result, page.OutputIDs...)
+
+			if page.Cursor == nil {
+				return result
+			}
+			require.Len(t, *page.Cursor, CursorLength)
+			cursor = page.Cursor
+		}
+	}
+
+	require.Equal(t, outputIDs(0, 1, 2, 3, 4), pagedOutputIDs(ExtendedOutputSortByCreatedAt(SortAscending)))
+	require.Equal(t, outputIDs(4, 3, 2, 1, 0), pagedOutputIDs(ExtendedOutputSortByCreatedAt(SortDescending)))
+	require.Equal(t, outputIDs(1, 3, 0, 4, 2), pagedOutputIDs(ExtendedOutputSortByAmount(SortAscending)))
+	require.Equal(t, outputIDs(2, 4, 0, 3, 1), pagedOutputIDs(ExtendedOutputSortByAmount(SortDescending)))
+
+	// without paging the results are sorted as well
+	result := idx.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address), ExtendedOutputSortByAmount(SortDescending))
+	require.NoError(t, result.Error)
+	require.Equal(t, outputIDs(2, 4, 0, 3, 1), result.OutputIDs)
+
+	// a cursor can't be used with a different sorting
+	firstPage := idx.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address), ExtendedOutputPageSize(2), ExtendedOutputSortByAmount(SortDescending))
+	require.NoError(t, firstPage.Error)
+	require.NotNil(t, firstPage.Cursor)
+
+	result = idx.ExtendedOutputsWithFilters(ExtendedOutputUnlockableByAddress(address), ExtendedOutputPageSize(2), ExtendedOutputCursor(*firstPage.Cursor))
+	require.Error(t, result.Error)
+}
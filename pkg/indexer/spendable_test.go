@@ -0,0 +1,97 @@
+package indexer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func extendedOutputWithConditions(conditions ...iotago.UnlockCondition) *utxo.Output {
+	return utxo.CreateOutput(utils.RandOutputID(), utils.RandMessageID(), 1, 0, &iotago.ExtendedOutput{
+		Amount:     1_000_000,
+		Conditions: conditions,
+	})
+}
+
+func TestIndexerSpendableByAddress(t *testing.T) {
+
+	idx, err := NewIndexer(t.TempDir())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, idx.CloseDatabase()) }()
+
+	owner := utils.RandAddress(iotago.AddressEd25519)
+	sender := utils.RandAddress(iotago.AddressEd25519)
+	ownerCondition := &iotago.AddressUnlockCondition{Address: owner}
+
+	// the unlock conditions are evaluated at milestone 10 with timestamp 1000
+	msIndex := uint32(10)
+	timestamp := uint32(1000)
+
+	plain := extendedOutputWithConditions(ownerCondition)
+	timelockReached := extendedOutputWithConditions(ownerCondition, &iotago.TimelockUnlockCondition{MilestoneIndex: msIndex, UnixTime: timestamp})
+	timelockedMilestone := extendedOutputWithConditions(ownerCondition, &iotago.TimelockUnlockCondition{MilestoneIndex: msIndex + 1})
+	timelockedTime := extendedOutputWithConditions(ownerCondition, &iotago.TimelockUnlockCondition{UnixTime: timestamp + 1})
+	notExpired := extendedOutputWithConditions(ownerCondition, &iotago.ExpirationUnlockCondition{ReturnAddress: sender, MilestoneIndex: msIndex + 1})
+	expiredMilestone := extendedOutputWithConditions(ownerCondition, &iotago.ExpirationUnlockCondition{ReturnAddress: sender, MilestoneIndex: msIndex})
+	expiredTime := extendedOutputWithConditions(ownerCondition, &iotago.ExpirationUnlockCondition{ReturnAddress: sender, UnixTime: timestamp})
+	// both the milestone index and the unix time need to be reached
+	partiallyExpired := extendedOutputWithConditions(ownerCondition, &iotago.ExpirationUnlockCondition{ReturnAddress: sender, MilestoneIndex: msIndex, UnixTime: timestamp + 1})
+	expiredButTimelocked := extendedOutputWithConditions(ownerCondition,
+		&iotago.TimelockUnlockCondition{MilestoneIndex: msIndex + 1},
+		&iotago.ExpirationUnlockCondition{ReturnAddress: sender, MilestoneIndex: msIndex},
+	)
+	dustReturn := extendedOutputWithConditions(ownerCondition, &iotago.DustDepositReturnUnlockCondition{ReturnAddress: sender, Amount: 500_000})
+	otherOwner := extendedOutputWithConditions(&iotago.AddressUnlockCondition{Address: utils.RandAddress(iotago.AddressEd25519)})
+
+	nftOutput := utxo.CreateOutput(utils.RandOutputID(), utils.RandMessageID(), 1, 0, &iotago.NFTOutput{
+		Amount:     1_000_000,
+		NFTID:      utils.RandNFTID(),
+		Conditions: iotago.UnlockConditions{ownerCondition},
+	})
+	timelockedNFTOutput := utxo.CreateOutput(utils.RandOutputID(), utils.RandMessageID(), 1, 0, &iotago.NFTOutput{
+		Amount:     1_000_000,
+		NFTID:      utils.RandNFTID(),
+		Conditions: iotago.UnlockConditions{ownerCondition, &iotago.TimelockUnlockCondition{MilestoneIndex: msIndex + 1}},
+	})
+
+	require.NoError(t, idx.UpdatedLedger(1, utxo.Outputs{
+		plain, timelockReached, timelockedMilestone, timelockedTime, notExpired, expiredMilestone, expiredTime,
+		partiallyExpired, expiredButTimelocked, dustReturn, otherOwner, nftOutput, timelockedNFTOutput,
+	}, nil))
+
+	outputIDs := func(outputs ...*utxo.Output) iotago.OutputIDs {
+		result := make(iotago.OutputIDs, 0, len(outputs))
+		for _, output := range outputs {
+			result = append(result, *output.OutputID())
+		}
+		return result
+	}
+
+	result := idx.ExtendedOutputsWithFilters(ExtendedOutputSpendableByAddress(owner, 10, unixTime(timestamp)))
+	require.NoError(t, result.Error)
+	require.ElementsMatch(t, outputIDs(plain, timelockReached, notExpired, partiallyExpired, dustReturn), result.OutputIDs)
+
+	// the return address can unlock the expired outputs
+	result = idx.ExtendedOutputsWithFilters(ExtendedOutputSpendableByAddress(sender, 10, unixTime(timestamp)))
+	require.NoError(t, result.Error)
+	require.ElementsMatch(t, outputIDs(expiredMilestone, expiredTime), result.OutputIDs)
+
+	// later on, the timelocks are over and the remaining outputs expire as well
+	result = idx.ExtendedOutputsWithFilters(ExtendedOutputSpendableByAddress(sender, 11, unixTime(timestamp).Add(time.Second)))
+	require.NoError(t, result.Error)
+	require.ElementsMatch(t, outputIDs(notExpired, expiredMilestone, expiredTime, partiallyExpired, expiredButTimelocked), result.OutputIDs)
+
+	// the dust return outputs can be excluded with the existing filter
+	result = idx.ExtendedOutputsWithFilters(ExtendedOutputSpendableByAddress(owner, 10, unixTime(timestamp)), ExtendedOutputHasDustReturnCondition(false))
+	require.NoError(t, result.Error)
+	require.NotContains(t, result.OutputIDs, *dustReturn.OutputID())
+
+	result = idx.NFTOutputsWithFilters(NFTSpendableByAddress(owner, 10, unixTime(timestamp)))
+	require.NoError(t, result.Error)
+	require.ElementsMatch(t, outputIDs(nftOutput), result.OutputIDs)
+}
@@ -1,6 +1,8 @@
 package indexer
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,11 +15,83 @@ import (
 )
 
 const (
-	CursorLength = 76
+	// CursorLength is the length of a cursor, which consists of the position in the results,
+	// the sorting of the results and the ledger index the query was started at.
+	CursorLength = positionCursorLength + sortingCursorLength + ledgerIndexCursorLength
+
+	// positionCursorLength is the length of the hex encoded sort key and output ID of the next result.
+	positionCursorLength = sortKeyCursorLength + iotago.OutputIDLength*2
+	// sortKeyCursorLength is the length of the hex encoded value of the column the results are sorted by.
+	sortKeyCursorLength = 16
+	// sortingCursorLength is the length of the hex encoded sorting of the results.
+	sortingCursorLength = 2
+	// ledgerIndexCursorLength is the length of the hex encoded ledger index the query was started at.
+	ledgerIndexCursorLength = 8
+
+	// schemaVersion is the version of the database schema.
+	// Outputs that were indexed with an older schema lack columns, so the indexer is rebuilt if it changes.
+	schemaVersion = 3
+)
+
+// SortOrder is the direction in which the results of a query are sorted.
+type SortOrder byte
+
+const (
+	// SortAscending sorts the results from the smallest to the largest value.
+	SortAscending SortOrder = iota
+	// SortDescending sorts the results from the largest to the smallest value.
+	SortDescending
+)
+
+// sortColumn is the column the results of a query are sorted by.
+type sortColumn byte
+
+const (
+	sortByCreatedAt sortColumn = iota
+	sortByAmount
 )
 
+// resultSorting defines how the results of a query are sorted.
+// The output ID is always used as the last sort key, so the order of the results is stable.
+// The zero value sorts the results by creation time in ascending order.
+type resultSorting struct {
+	column sortColumn
+	order  SortOrder
+}
+
+// id returns the identifier of the sorting that is part of the cursor.
+func (s resultSorting) id() uint8 {
+	return uint8(s.column)<<1 | uint8(s.order)
+}
+
+// sortKey returns the SQL expression of the hex encoded sort key of a row.
+func (s resultSorting) sortKey() string {
+	if s.column == sortByAmount {
+		return "printf('%016X', `amount`)"
+	}
+	return "printf('%016X', strftime('%s', `created_at`))"
+}
+
+// orderBy returns the SQL order clause of the sorting.
+func (s resultSorting) orderBy() string {
+	column := "created_at"
+	if s.column == sortByAmount {
+		column = "amount"
+	}
+
+	direction := "asc"
+	if s.order == SortDescending {
+		direction = "desc"
+	}
+
+	return fmt.Sprintf("%s %s, output_id %s", column, direction, direction)
+}
+
 var (
 	NullOutputID = iotago.OutputID{}
+
+	// ErrCursorExpired is returned if the spent outputs of the ledger index a cursor was created at were already pruned.
+	ErrCursorExpired = errors.New("cursor expired")
 )
 
 type outputIDBytes []byte
@@ -29,12 +103,16 @@ type foundryIDBytes []byte
 type status struct {
 	ID          uint `gorm:"primaryKey;notnull"`
 	LedgerIndex milestone.Index
+	// OldestLedgerIndex is the oldest ledger index for which all spent outputs are still contained.
+	OldestLedgerIndex milestone.Index
+	SchemaVersion     int
 }
 
 type queryResult struct {
-	OutputID    outputIDBytes
-	Cursor      string
-	LedgerIndex milestone.Index
+	OutputID          outputIDBytes
+	Cursor            string
+	LedgerIndex       milestone.Index
+	OldestLedgerIndex milestone.Index
 }
 
 func (o outputIDBytes) ID() iotago.OutputID {
@@ -71,47 +149,176 @@ func errorResult(err error) *IndexerResult {
 	}
 }
 
+// whereBytesPrefix adds a condition that the given column starts with the prefix.
+// The prefix is matched with a range condition, so that an index on the column can be used.
+func whereBytesPrefix(query *gorm.DB, column string, prefix []byte) *gorm.DB {
+	query = query.Where(column+" >= ?", prefix)
+
+	// the smallest value that is greater than all values with the prefix
+	upperBound := make([]byte, len(prefix))
+	copy(upperBound, prefix)
+	for i := len(upperBound) - 1; i >= 0; i-- {
+		if upperBound[i] < 0xff {
+			upperBound[i]++
+			return query.Where(column+" < ?", upperBound[:i+1])
+		}
+	}
+
+	// all bytes of the prefix are 0xff, so there is no upper bound
+	return query
+}
+
+// whereBytesContain adds a condition that the given column contains the data.
+func whereBytesContain(query *gorm.DB, column string, data []byte) *gorm.DB {
+	return query.Where("instr("+column+", ?) > 0", data)
+}
+
 func unixTime(fromValue uint32) time.Time {
 	return time.Unix(int64(fromValue), 0)
 }
 
-func (i *Indexer) combineOutputIDFilteredQuery(query *gorm.DB, pageSize int, cursor *string) *IndexerResult {
+// whereUnspentAt adds a condition that the outputs were unspent at the given ledger index.
+// If no ledger index is given, the outputs need to be unspent at the current ledger index.
+func whereUnspentAt(query *gorm.DB, ledgerIndex *milestone.Index) *gorm.DB {
+	if ledgerIndex == nil {
+		return query.Where("spent_at IS NULL")
+	}
+	return query.Where("booked_at <= ?", *ledgerIndex).Where("(spent_at IS NULL OR spent_at > ?)", *ledgerIndex)
+}
+
+// spendableByFilter holds the address that needs to be able to unlock the outputs
+// and the milestone at which the unlock conditions are evaluated.
+type spendableByFilter struct {
+	address   iotago.Address
+	msIndex   milestone.Index
+	timestamp time.Time
+}
 
-	query = query.Select("output_id").Order("created_at asc, output_id asc")
+// whereSpendableBy adds a condition that the outputs can be unlocked by the address at the milestone of the filter.
+// Outputs that are still timelocked can't be unlocked by anyone, and expired outputs can only be unlocked by the expiration return address.
+// If an expiration condition contains a milestone index and a unix time, it only expires once both are reached.
+func whereSpendableBy(query *gorm.DB, filter *spendableByFilter) (*gorm.DB, error) {
+	addr, err := addressBytesForAddress(filter.address)
+	if err != nil {
+		return nil, err
+	}
+
+	query = query.Where("(timelock_milestone IS NULL OR timelock_milestone <= ?)", filter.msIndex).
+		Where("(timelock_time IS NULL OR timelock_time <= ?)", filter.timestamp)
+
+	expired := "(expiration_return_address IS NOT NULL AND (expiration_milestone IS NULL OR expiration_milestone <= @msIndex) AND (expiration_time IS NULL OR expiration_time <= @timestamp))"
+	return query.Where("((address = @address AND NOT "+expired+") OR (expiration_return_address = @address AND "+expired+"))", map[string]interface{}{
+		"address":   addr[:],
+		"msIndex":   filter.msIndex,
+		"timestamp": filter.timestamp,
+	}), nil
+}
+
+// parseCursor returns the position and the ledger index of the cursor.
+// The cursor needs to be created by a query with the same sorting.
+func parseCursor(cursor string, sorting resultSorting) (string, milestone.Index, error) {
+	if len(cursor) != CursorLength {
+		return "", 0, errors.Errorf("Invalid cursor length: %d", len(cursor))
+	}
+
+	sortingID, err := strconv.ParseUint(cursor[positionCursorLength:positionCursorLength+sortingCursorLength], 16, 8)
+	if err != nil {
+		return "", 0, errors.Errorf("Invalid cursor sorting: %s", err)
+	}
+	if uint8(sortingID) != sorting.id() {
+		return "", 0, errors.New("Invalid cursor sorting: the cursor was created with a different sorting")
+	}
+
+	ledgerIndex, err := strconv.ParseUint(cursor[positionCursorLength+sortingCursorLength:], 16, 32)
+	if err != nil {
+		return "", 0, errors.Errorf("Invalid cursor ledger index: %s", err)
+	}
+
+	return strings.ToUpper(cursor[:positionCursorLength]), milestone.Index(ledgerIndex), nil
+}
+
+func (i *Indexer) combineOutputIDFilteredQuery(query *gorm.DB, pageSize int, cursor *string, sorting resultSorting) *IndexerResult {
+
+	// the filters need to be collected before the cursor is added to the query
+	equalityColumns, rangeColumns := filterColumns(query)
+
+	// the following pages of a query are answered at the ledger index the first page was answered at,
+	// so that outputs which were spent or created in the meantime don't shift the results.
+	var pinnedLedgerIndex *milestone.Index
+
+	query = query.Select("output_id").Order(sorting.orderBy())
 	if pageSize > 0 {
-		query = query.Select("output_id", "printf('%08X', strftime('%s', `created_at`)) || hex(output_id) as cursor").Limit(pageSize + 1)
+		query = query.Select("output_id", sorting.sortKey()+" || hex(output_id) as cursor").Limit(pageSize + 1)
 
 		if cursor != nil {
-			if len(*cursor) != CursorLength {
-				return errorResult(errors.Errorf("Invalid cursor length: %d", len(*cursor)))
+			position, ledgerIndex, err := parseCursor(*cursor, sorting)
+			if err != nil {
+				return errorResult(err)
+			}
+			pinnedLedgerIndex = &ledgerIndex
+
+			// the cursor is the position of the first result of the page
+			if sorting.order == SortDescending {
+				query = query.Where("cursor <= ?", position)
+			} else {
+				query = query.Where("cursor >= ?", position)
 			}
-			query = query.Where("cursor >= ?", strings.ToUpper(*cursor))
 		}
 	}
+	query = whereUnspentAt(query, pinnedLedgerIndex)
 
 	// This combines the query with a second query that checks for the current ledger_index.
 	// This way we do not need to lock anything and we know the index matches the results.
 	//TODO: measure performance for big datasets
-	ledgerIndexQuery := i.db.Model(&status{}).Select("ledger_index")
+	ledgerIndexQuery := i.db.Model(&status{}).Select("ledger_index", "oldest_ledger_index")
 	joinedQuery := i.db.Table("(?), (?)", query, ledgerIndexQuery)
 
 	var results queryResults
 
+	ts := time.Now()
 	result := joinedQuery.Find(&results)
 	if err := result.Error; err != nil {
 		return errorResult(err)
 	}
+	i.queryStats.recordQuery(i.db, query.Statement.Model, equalityColumns, rangeColumns, time.Since(ts))
 
 	ledgerIndex := milestone.Index(0)
 	if len(results) > 0 {
 		ledgerIndex = results[0].LedgerIndex
 	}
 
+	if pinnedLedgerIndex != nil {
+		// the status is part of the results, unless there are none
+		oldestLedgerIndex := milestone.Index(0)
+		if len(results) > 0 {
+			oldestLedgerIndex = results[0].OldestLedgerIndex
+		} else {
+			status, err := i.status()
+			if err != nil {
+				if errors.Is(err, ErrNotFound) {
+					// nothing was indexed yet
+					return &IndexerResult{OutputIDs: iotago.OutputIDs{}, PageSize: pageSize}
+				}
+				return errorResult(err)
+			}
+			ledgerIndex = status.LedgerIndex
+			oldestLedgerIndex = status.OldestLedgerIndex
+		}
+
+		if *pinnedLedgerIndex < oldestLedgerIndex {
+			return errorResult(errors.WithMessagef(ErrCursorExpired, "ledger index %d is older than %d", *pinnedLedgerIndex, oldestLedgerIndex))
+		}
+		if *pinnedLedgerIndex > ledgerIndex {
+			return errorResult(errors.Errorf("Invalid cursor ledger index: %d", *pinnedLedgerIndex))
+		}
+		ledgerIndex = *pinnedLedgerIndex
+	}
+
 	var nextCursor *string
 	if pageSize > 0 && len(results) > pageSize {
 		lastResult := results[len(results)-1]
 		results = results[:len(results)-1]
-		c := strings.ToLower(lastResult.Cursor)
+		c := fmt.Sprintf("%s%02x%08x", strings.ToLower(lastResult.Cursor), sorting.id(), uint32(ledgerIndex))
 		nextCursor = &c
 	}
 
@@ -123,3 +330,35 @@ func (i *Indexer) combineOutputIDFilteredQuery(query *gorm.DB, pageSize int, cur
 		Error:       nil,
 	}
 }
+
+// whereConditionsFunc adds the conditions of a set of filter options to the query.
+type whereConditionsFunc func(query *gorm.DB) (*gorm.DB, error)
+
+// whereAnyOf adds a condition that at least one of the alternatives matches.
+// An alternative without any condition matches all rows, so the whole group is omitted in that case.
+func whereAnyOf(query *gorm.DB, alternatives []whereConditionsFunc) (*gorm.DB, error) {
+	var group *gorm.DB
+	for _, alternative := range alternatives {
+		// the conditions of the alternatives are built without the conditions of the query
+		condition, err := alternative(query.Session(&gorm.Session{NewDB: true}))
+		if err != nil {
+			return nil, err
+		}
+
+		if _, hasConditions := condition.Statement.Clauses["WHERE"]; !hasConditions {
+			return query, nil
+		}
+
+		if group == nil {
+			group = condition
+			continue
+		}
+		group = group.Or(condition)
+	}
+
+	if group == nil {
+		return query, nil
+	}
+
+	return query.Where(group), nil
+}
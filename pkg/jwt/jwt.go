@@ -17,8 +17,45 @@ import (
 // Errors
 var (
 	ErrJWTInvalidClaims = echo.NewHTTPError(http.StatusUnauthorized, "invalid jwt claims")
+	ErrJWTScope         = echo.NewHTTPError(http.StatusForbidden, "jwt scope does not allow the route")
+	ErrJWTUnknownScope  = errors.New("unknown jwt scope")
 )
 
+// Scopes restrict the routes an API token grants access to.
+// API tokens without scopes grant access to all exposed routes.
+const (
+	// ScopeAPIRead allows to read the ledger, the tangle and the indexer.
+	ScopeAPIRead = "api.read"
+	// ScopeAPISubmit allows to submit messages and faucet requests.
+	ScopeAPISubmit = "api.submit"
+	// ScopePeeringManage allows to manage the peers of the node.
+	ScopePeeringManage = "peering.manage"
+	// ScopeFaucetAdmin allows to manage the faucet.
+	ScopeFaucetAdmin = "faucet.admin"
+	// ScopeNodeControl allows to call the control routes of the node.
+	ScopeNodeControl = "node.control"
+)
+
+// Scopes are all known scopes.
+var Scopes = []string{ScopeAPIRead, ScopeAPISubmit, ScopePeeringManage, ScopeFaucetAdmin, ScopeNodeControl}
+
+// ValidateScopes checks whether all given scopes are known.
+func ValidateScopes(scopes []string) error {
+	for _, scope := range scopes {
+		known := false
+		for _, knownScope := range Scopes {
+			if scope == knownScope {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return errors.Wrapf(ErrJWTUnknownScope, "%s, must be one of %v", scope, Scopes)
+		}
+	}
+	return nil
+}
+
 type JWTAuth struct {
 	subject        string
 	sessionTimeout time.Duration
@@ -47,8 +84,9 @@ func NewJWTAuth(subject string, sessionTimeout time.Duration, nodeID string, sec
 
 type AuthClaims struct {
 	jwt.StandardClaims
-	Dashboard bool `json:"dashboard"`
-	API       bool `json:"api"`
+	Dashboard bool     `json:"dashboard"`
+	API       bool     `json:"api"`
+	Scopes    []string `json:"scopes,omitempty"`
 }
 
 func (c *AuthClaims) compare(field string, expected string) bool {
@@ -66,6 +104,16 @@ func (c *AuthClaims) VerifySubject(expected string) bool {
 	return c.compare(c.Subject, expected)
 }
 
+// HasScope returns whether the claims contain the given scope.
+func (c *AuthClaims) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 func (j *JWTAuth) Middleware(skipper middleware.Skipper, allow func(c echo.Context, subject string, claims *AuthClaims) bool) echo.MiddlewareFunc {
 
 	config := middleware.JWTConfig{
@@ -119,7 +167,27 @@ func (j *JWTAuth) Middleware(skipper middleware.Skipper, allow func(c echo.Conte
 	}
 }
 
-func (j *JWTAuth) IssueJWT(api bool, dashboard bool) (string, error) {
+// ClaimsFromContext returns the claims of the token that was verified by the middleware for the request,
+// or nil if the request was not authorized with a token.
+func ClaimsFromContext(c echo.Context) *AuthClaims {
+	token, ok := c.Get("jwt").(*jwt.Token)
+	if !ok || !token.Valid {
+		return nil
+	}
+
+	claims, ok := token.Claims.(*AuthClaims)
+	if !ok {
+		return nil
+	}
+	return claims
+}
+
+// IssueJWT issues a new token. API tokens can be restricted to the given scopes.
+func (j *JWTAuth) IssueJWT(api bool, dashboard bool, scopes ...string) (string, error) {
+
+	if err := ValidateScopes(scopes); err != nil {
+		return "", err
+	}
 
 	now := time.Now()
 
@@ -141,6 +209,7 @@ func (j *JWTAuth) IssueJWT(api bool, dashboard bool) (string, error) {
 		StandardClaims: stdClaims,
 		Dashboard:      dashboard,
 		API:            api,
+		Scopes:         scopes,
 	}
 
 	// Create token
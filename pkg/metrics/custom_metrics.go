@@ -0,0 +1,200 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/events"
+)
+
+// CustomMetricSeries is a named metric series a plugin publishes to the dashboard.
+type CustomMetricSeries struct {
+	customMetrics *CustomMetrics
+
+	// The name of the plugin that publishes the series.
+	Plugin string
+	// The name of the series, unique per plugin.
+	Name string
+	// The description of the series, shown as title of the chart.
+	Description string
+	// The unit of the values, e.g. "IOTA" or "requests".
+	Unit string
+}
+
+// ID returns the unique ID of the series, "<plugin>/<name>".
+func (s *CustomMetricSeries) ID() string {
+	return s.Plugin + "/" + s.Name
+}
+
+func (s *CustomMetricSeries) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID          string `json:"id"`
+		Plugin      string `json:"plugin"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Unit        string `json:"unit,omitempty"`
+	}{
+		ID:          s.ID(),
+		Plugin:      s.Plugin,
+		Name:        s.Name,
+		Description: s.Description,
+		Unit:        s.Unit,
+	})
+}
+
+// Record records a new value of the series.
+func (s *CustomMetricSeries) Record(value float64) {
+	s.customMetrics.record(s, time.Now(), value)
+}
+
+// CustomMetricPoint is a recorded value of a CustomMetricSeries.
+type CustomMetricPoint struct {
+	// The ID of the series.
+	SeriesID string
+	// The time the value was recorded.
+	Timestamp time.Time
+	// The recorded value.
+	Value float64
+}
+
+func (p *CustomMetricPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		SeriesID  string  `json:"seriesId"`
+		Timestamp int64   `json:"ts"`
+		Value     float64 `json:"value"`
+	}{
+		SeriesID:  p.SeriesID,
+		Timestamp: p.Timestamp.Unix(),
+		Value:     p.Value,
+	})
+}
+
+// CustomMetricsEvents are the events issued by the CustomMetrics.
+type CustomMetricsEvents struct {
+	// Fired when a plugin registered a new series.
+	SeriesRegistered *events.Event
+	// Fired when a value of a series was recorded.
+	PointRecorded *events.Event
+}
+
+// CustomMetricSeriesCaller is used to signal a CustomMetricSeries.
+func CustomMetricSeriesCaller(handler interface{}, params ...interface{}) {
+	handler.(func(*CustomMetricSeries))(params[0].(*CustomMetricSeries))
+}
+
+// CustomMetricPointCaller is used to signal a CustomMetricPoint.
+func CustomMetricPointCaller(handler interface{}, params ...interface{}) {
+	handler.(func(*CustomMetricPoint))(params[0].(*CustomMetricPoint))
+}
+
+// CustomMetrics is the registry of the metric series other plugins publish to the dashboard,
+// so that new charts don't need changes in the dashboard itself.
+// It keeps the latest points of every series, so a new chart doesn't start empty.
+type CustomMetrics struct {
+	// Events are the events issued by the CustomMetrics.
+	Events *CustomMetricsEvents
+
+	// the amount of points that are kept per series.
+	pointsPerSeries int
+
+	sync.RWMutex
+	series map[string]*CustomMetricSeries
+	points map[string][]*CustomMetricPoint
+}
+
+// NewCustomMetrics creates a new CustomMetrics that keeps the given amount of points per series.
+func NewCustomMetrics(pointsPerSeries int) *CustomMetrics {
+	return &CustomMetrics{
+		Events: &CustomMetricsEvents{
+			SeriesRegistered: events.NewEvent(CustomMetricSeriesCaller),
+			PointRecorded:    events.NewEvent(CustomMetricPointCaller),
+		},
+		pointsPerSeries: pointsPerSeries,
+		series:          make(map[string]*CustomMetricSeries),
+		points:          make(map[string][]*CustomMetricPoint),
+	}
+}
+
+// Register registers a new series of the given plugin.
+func (c *CustomMetrics) Register(plugin string, name string, description string, unit string) (*CustomMetricSeries, error) {
+	if plugin == "" || name == "" {
+		return nil, fmt.Errorf("plugin and name of a custom metric series are required")
+	}
+
+	series := &CustomMetricSeries{
+		customMetrics: c,
+		Plugin:        plugin,
+		Name:          name,
+		Description:   description,
+		Unit:          unit,
+	}
+
+	c.Lock()
+	if _, exists := c.series[series.ID()]; exists {
+		c.Unlock()
+		return nil, fmt.Errorf("custom metric series %s already registered", series.ID())
+	}
+	c.series[series.ID()] = series
+	c.Unlock()
+
+	c.Events.SeriesRegistered.Trigger(series)
+
+	return series, nil
+}
+
+func (c *CustomMetrics) record(series *CustomMetricSeries, timestamp time.Time, value float64) {
+	point := &CustomMetricPoint{
+		SeriesID:  series.ID(),
+		Timestamp: timestamp,
+		Value:     value,
+	}
+
+	c.Lock()
+	points := append(c.points[point.SeriesID], point)
+	if len(points) > c.pointsPerSeries {
+		points = points[len(points)-c.pointsPerSeries:]
+	}
+	c.points[point.SeriesID] = points
+	c.Unlock()
+
+	c.Events.PointRecorded.Trigger(point)
+}
+
+// Series returns all registered series, ordered by their ID.
+func (c *CustomMetrics) Series() []*CustomMetricSeries {
+	c.RLock()
+	defer c.RUnlock()
+
+	series := make([]*CustomMetricSeries, 0, len(c.series))
+	for _, s := range c.series {
+		series = append(series, s)
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].ID() < series[j].ID()
+	})
+
+	return series
+}
+
+// Points returns the kept points of all series, the oldest point of every series first.
+func (c *CustomMetrics) Points() []*CustomMetricPoint {
+	c.RLock()
+	defer c.RUnlock()
+
+	seriesIDs := make([]string, 0, len(c.points))
+	for seriesID := range c.points {
+		seriesIDs = append(seriesIDs, seriesID)
+	}
+	sort.Strings(seriesIDs)
+
+	var points []*CustomMetricPoint
+	for _, seriesID := range seriesIDs {
+		points = append(points, c.points[seriesID]...)
+	}
+
+	return points
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/events"
+)
+
+func TestCustomMetrics(t *testing.T) {
+
+	c := NewCustomMetrics(2)
+
+	var registered []*CustomMetricSeries
+	c.Events.SeriesRegistered.Attach(events.NewClosure(func(series *CustomMetricSeries) {
+		registered = append(registered, series)
+	}))
+
+	var recorded []*CustomMetricPoint
+	c.Events.PointRecorded.Attach(events.NewClosure(func(point *CustomMetricPoint) {
+		recorded = append(recorded, point)
+	}))
+
+	balance, err := c.Register("faucet", "balance", "Faucet balance", "IOTA")
+	require.NoError(t, err)
+	requests, err := c.Register("faucet", "pendingRequests", "Pending faucet requests", "")
+	require.NoError(t, err)
+	require.Len(t, registered, 2)
+	require.Equal(t, "faucet/balance", registered[0].ID())
+
+	// the ID of a series is unique
+	_, err = c.Register("faucet", "balance", "Faucet balance", "IOTA")
+	require.Error(t, err)
+	_, err = c.Register("", "balance", "", "")
+	require.Error(t, err)
+
+	series := c.Series()
+	require.Len(t, series, 2)
+	require.Equal(t, "faucet/balance", series[0].ID())
+	require.Equal(t, "faucet/pendingRequests", series[1].ID())
+
+	requests.Record(5)
+	balance.Record(100)
+	balance.Record(90)
+	balance.Record(80)
+	require.Len(t, recorded, 4)
+	require.Equal(t, "faucet/pendingRequests", recorded[0].SeriesID)
+
+	// only the latest points of every series are kept
+	points := c.Points()
+	require.Len(t, points, 3)
+	require.Equal(t, "faucet/balance", points[0].SeriesID)
+	require.Equal(t, 90.0, points[0].Value)
+	require.Equal(t, 80.0, points[1].Value)
+	require.Equal(t, "faucet/pendingRequests", points[2].SeriesID)
+	require.Equal(t, 5.0, points[2].Value)
+}
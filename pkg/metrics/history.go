@@ -0,0 +1,270 @@
+package metrics
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/kvstore"
+)
+
+const (
+	// the serialized size of a HistorySample.
+	historySampleSize = 8 + 5*8 + 2*8 + 2*4
+)
+
+// HistorySample holds the node metrics aggregated over an interval of the metrics history.
+type HistorySample struct {
+	// The end of the interval.
+	Timestamp time.Time
+	// The average amount of received messages per second.
+	IncomingMPS float64
+	// The average amount of received new messages per second.
+	NewMPS float64
+	// The average amount of sent messages per second.
+	OutgoingMPS float64
+	// The average amount of referenced messages per second of the confirmed milestones.
+	ReferencedMPS float64
+	// The average rate of referenced messages of the confirmed milestones in percent.
+	ReferencedRate float64
+	// The memory obtained from the OS at the end of the interval.
+	MemSys uint64
+	// The memory of the heap in use at the end of the interval.
+	HeapInuse uint64
+	// The amount of connected peers at the end of the interval.
+	ConnectedPeers uint32
+	// The amount of known peers at the end of the interval.
+	KnownPeers uint32
+}
+
+func (s *HistorySample) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Timestamp      int64   `json:"ts"`
+		IncomingMPS    float64 `json:"incomingMPS"`
+		NewMPS         float64 `json:"newMPS"`
+		OutgoingMPS    float64 `json:"outgoingMPS"`
+		ReferencedMPS  float64 `json:"referencedMPS"`
+		ReferencedRate float64 `json:"referencedRate"`
+		MemSys         uint64  `json:"memSys"`
+		HeapInuse      uint64  `json:"heapInuse"`
+		ConnectedPeers uint32  `json:"connectedPeers"`
+		KnownPeers     uint32  `json:"knownPeers"`
+	}{
+		Timestamp:      s.Timestamp.Unix(),
+		IncomingMPS:    s.IncomingMPS,
+		NewMPS:         s.NewMPS,
+		OutgoingMPS:    s.OutgoingMPS,
+		ReferencedMPS:  s.ReferencedMPS,
+		ReferencedRate: s.ReferencedRate,
+		MemSys:         s.MemSys,
+		HeapInuse:      s.HeapInuse,
+		ConnectedPeers: s.ConnectedPeers,
+		KnownPeers:     s.KnownPeers,
+	})
+}
+
+func (s *HistorySample) bytes() []byte {
+	b := make([]byte, historySampleSize)
+	binary.LittleEndian.PutUint64(b[0:], uint64(s.Timestamp.Unix()))
+	binary.LittleEndian.PutUint64(b[8:], math.Float64bits(s.IncomingMPS))
+	binary.LittleEndian.PutUint64(b[16:], math.Float64bits(s.NewMPS))
+	binary.LittleEndian.PutUint64(b[24:], math.Float64bits(s.OutgoingMPS))
+	binary.LittleEndian.PutUint64(b[32:], math.Float64bits(s.ReferencedMPS))
+	binary.LittleEndian.PutUint64(b[40:], math.Float64bits(s.ReferencedRate))
+	binary.LittleEndian.PutUint64(b[48:], s.MemSys)
+	binary.LittleEndian.PutUint64(b[56:], s.HeapInuse)
+	binary.LittleEndian.PutUint32(b[64:], s.ConnectedPeers)
+	binary.LittleEndian.PutUint32(b[68:], s.KnownPeers)
+	return b
+}
+
+func historySampleFromBytes(b []byte) (*HistorySample, error) {
+	if len(b) != historySampleSize {
+		return nil, fmt.Errorf("invalid metrics history sample length: %d", len(b))
+	}
+
+	return &HistorySample{
+		Timestamp:      time.Unix(int64(binary.LittleEndian.Uint64(b[0:])), 0),
+		IncomingMPS:    math.Float64frombits(binary.LittleEndian.Uint64(b[8:])),
+		NewMPS:         math.Float64frombits(binary.LittleEndian.Uint64(b[16:])),
+		OutgoingMPS:    math.Float64frombits(binary.LittleEndian.Uint64(b[24:])),
+		ReferencedMPS:  math.Float64frombits(binary.LittleEndian.Uint64(b[32:])),
+		ReferencedRate: math.Float64frombits(binary.LittleEndian.Uint64(b[40:])),
+		MemSys:         binary.LittleEndian.Uint64(b[48:]),
+		HeapInuse:      binary.LittleEndian.Uint64(b[56:]),
+		ConnectedPeers: binary.LittleEndian.Uint32(b[64:]),
+		KnownPeers:     binary.LittleEndian.Uint32(b[68:]),
+	}, nil
+}
+
+// MetricsHistory keeps the node metrics of the last hours in a ring buffer with one sample per interval,
+// which is persisted in the given store, so the history survives restarts of the node.
+type MetricsHistory struct {
+	sync.RWMutex
+
+	store     kvstore.KVStore
+	retention time.Duration
+	capacity  int
+
+	// the samples ordered by time, the oldest sample first.
+	samples []*HistorySample
+	// the slot in the store the next sample is written to.
+	nextSlot int
+
+	// the sums of the metrics of the current interval.
+	mpsCount          int
+	incomingMPSSum    float64
+	newMPSSum         float64
+	outgoingMPSSum    float64
+	milestoneCount    int
+	referencedMPSSum  float64
+	referencedRateSum float64
+}
+
+// NewMetricsHistory creates a metrics history that keeps the samples of the given retention time
+// and loads the samples stored by a previous run.
+func NewMetricsHistory(store kvstore.KVStore, retention time.Duration, interval time.Duration) (*MetricsHistory, error) {
+	if interval <= 0 || retention < interval {
+		return nil, fmt.Errorf("metrics history retention (%v) has to be longer than the interval (%v)", retention, interval)
+	}
+
+	h := &MetricsHistory{
+		store:     store,
+		retention: retention,
+		capacity:  int(retention / interval),
+	}
+
+	if err := h.load(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// load reads the stored samples, drops the ones older than the retention time
+// and rewrites the ring buffer, in case the capacity changed since the last run.
+func (h *MetricsHistory) load(now time.Time) error {
+	var samples []*HistorySample
+	if err := h.store.Iterate(kvstore.EmptyPrefix, func(_ kvstore.Key, value kvstore.Value) bool {
+		sample, err := historySampleFromBytes(value)
+		if err != nil {
+			// skip invalid samples, they are removed when the ring buffer is rewritten
+			return true
+		}
+		if now.Sub(sample.Timestamp) <= h.retention {
+			samples = append(samples, sample)
+		}
+		return true
+	}); err != nil {
+		return errors.Wrap(err, "loading metrics history failed")
+	}
+
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Timestamp.Before(samples[j].Timestamp)
+	})
+	if len(samples) > h.capacity {
+		samples = samples[len(samples)-h.capacity:]
+	}
+
+	if err := h.store.Clear(); err != nil {
+		return errors.Wrap(err, "clearing metrics history failed")
+	}
+
+	for _, sample := range samples {
+		if err := h.storeSample(sample); err != nil {
+			return err
+		}
+	}
+	h.samples = samples
+
+	return nil
+}
+
+func (h *MetricsHistory) storeSample(sample *HistorySample) error {
+	key := make([]byte, 4)
+	binary.BigEndian.PutUint32(key, uint32(h.nextSlot))
+
+	if err := h.store.Set(key, sample.bytes()); err != nil {
+		return errors.Wrap(err, "storing metrics history sample failed")
+	}
+	h.nextSlot = (h.nextSlot + 1) % h.capacity
+
+	return nil
+}
+
+// AddMPSMetric adds the message rates of the last second to the current interval.
+func (h *MetricsHistory) AddMPSMetric(incoming uint32, newMessages uint32, outgoing uint32) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.mpsCount++
+	h.incomingMPSSum += float64(incoming)
+	h.newMPSSum += float64(newMessages)
+	h.outgoingMPSSum += float64(outgoing)
+}
+
+// AddConfirmedMilestoneMetric adds the metrics of a confirmed milestone to the current interval.
+func (h *MetricsHistory) AddConfirmedMilestoneMetric(referencedMPS float64, referencedRate float64) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.milestoneCount++
+	h.referencedMPSSum += referencedMPS
+	h.referencedRateSum += referencedRate
+}
+
+// Record finishes the current interval with the given current values and adds its sample to the history.
+func (h *MetricsHistory) Record(timestamp time.Time, memSys uint64, heapInuse uint64, connectedPeers int, knownPeers int) (*HistorySample, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	sample := &HistorySample{
+		Timestamp:      timestamp,
+		MemSys:         memSys,
+		HeapInuse:      heapInuse,
+		ConnectedPeers: uint32(connectedPeers),
+		KnownPeers:     uint32(knownPeers),
+	}
+
+	if h.mpsCount > 0 {
+		sample.IncomingMPS = h.incomingMPSSum / float64(h.mpsCount)
+		sample.NewMPS = h.newMPSSum / float64(h.mpsCount)
+		sample.OutgoingMPS = h.outgoingMPSSum / float64(h.mpsCount)
+	}
+	if h.milestoneCount > 0 {
+		sample.ReferencedMPS = h.referencedMPSSum / float64(h.milestoneCount)
+		sample.ReferencedRate = h.referencedRateSum / float64(h.milestoneCount)
+	}
+
+	h.mpsCount, h.incomingMPSSum, h.newMPSSum, h.outgoingMPSSum = 0, 0, 0, 0
+	h.milestoneCount, h.referencedMPSSum, h.referencedRateSum = 0, 0, 0
+
+	if err := h.storeSample(sample); err != nil {
+		return nil, err
+	}
+
+	h.samples = append(h.samples, sample)
+	if len(h.samples) > h.capacity {
+		h.samples = h.samples[len(h.samples)-h.capacity:]
+	}
+
+	return sample, nil
+}
+
+// Samples returns the samples of the history that were recorded after the given time, the oldest sample first.
+func (h *MetricsHistory) Samples(since time.Time) []*HistorySample {
+	h.RLock()
+	defer h.RUnlock()
+
+	index := sort.Search(len(h.samples), func(i int) bool {
+		return h.samples[i].Timestamp.After(since)
+	})
+
+	return append([]*HistorySample{}, h.samples[index:]...)
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+)
+
+func TestMetricsHistoryRecord(t *testing.T) {
+
+	h, err := NewMetricsHistory(mapdb.NewMapDB(), time.Hour, time.Minute)
+	require.NoError(t, err)
+
+	h.AddMPSMetric(10, 5, 20)
+	h.AddMPSMetric(20, 15, 40)
+	h.AddConfirmedMilestoneMetric(12, 90)
+	h.AddConfirmedMilestoneMetric(8, 100)
+
+	now := time.Unix(time.Now().Unix(), 0)
+	sample, err := h.Record(now, 2048, 1024, 3, 5)
+	require.NoError(t, err)
+	require.Equal(t, &HistorySample{
+		Timestamp:      now,
+		IncomingMPS:    15,
+		NewMPS:         10,
+		OutgoingMPS:    30,
+		ReferencedMPS:  10,
+		ReferencedRate: 95,
+		MemSys:         2048,
+		HeapInuse:      1024,
+		ConnectedPeers: 3,
+		KnownPeers:     5,
+	}, sample)
+
+	// the sums are reset for the next interval
+	sample, err = h.Record(now.Add(time.Minute), 2048, 1024, 3, 5)
+	require.NoError(t, err)
+	require.Zero(t, sample.IncomingMPS)
+	require.Zero(t, sample.ReferencedRate)
+
+	require.Len(t, h.Samples(time.Time{}), 2)
+	require.Len(t, h.Samples(now), 1)
+}
+
+func TestMetricsHistoryPersistence(t *testing.T) {
+
+	db := mapdb.NewMapDB()
+	otherRealm := db.WithRealm([]byte{1})
+	require.NoError(t, otherRealm.Set([]byte{1}, []byte{1}))
+
+	store := db.WithRealm([]byte{2})
+	h, err := NewMetricsHistory(store, 10*time.Minute, time.Minute)
+	require.NoError(t, err)
+
+	// more samples than the ring buffer holds
+	start := time.Unix(time.Now().Unix(), 0).Add(-20*time.Minute + 30*time.Second)
+	for i := 0; i < 15; i++ {
+		_, err := h.Record(start.Add(time.Duration(i)*time.Minute), uint64(i), 0, i, 0)
+		require.NoError(t, err)
+	}
+	require.Len(t, h.Samples(time.Time{}), 10)
+
+	countStored := func() int {
+		count := 0
+		require.NoError(t, store.IterateKeys(kvstore.EmptyPrefix, func(_ kvstore.Key) bool {
+			count++
+			return true
+		}))
+		return count
+	}
+	require.Equal(t, 10, countStored())
+
+	// the samples older than the retention time are dropped after a restart
+	h, err = NewMetricsHistory(store, 10*time.Minute, time.Minute)
+	require.NoError(t, err)
+
+	samples := h.Samples(time.Time{})
+	require.Len(t, samples, 5)
+	for i, sample := range samples {
+		require.Equal(t, uint64(10+i), sample.MemSys)
+	}
+
+	// a smaller capacity keeps the newest samples
+	h, err = NewMetricsHistory(store, 20*time.Minute, 10*time.Minute)
+	require.NoError(t, err)
+
+	samples = h.Samples(time.Time{})
+	require.Len(t, samples, 2)
+	require.Equal(t, uint64(14), samples[1].MemSys)
+	require.Equal(t, 2, countStored())
+
+	_, err = h.Record(time.Now(), 0, 0, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, countStored())
+
+	// other data in the database is not touched
+	value, err := otherRealm.Get([]byte{1})
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, value)
+
+	_, err = NewMetricsHistory(store, time.Minute, time.Hour)
+	require.Error(t, err)
+}
@@ -12,6 +12,10 @@ type ServerMetrics struct {
 	NewMessages atomic.Uint32
 	// The number of received messages which are already known.
 	KnownMessages atomic.Uint32
+	// The number of received messages which were found in the incoming messages filter.
+	IncomingMessagesFilterHits atomic.Uint32
+	// The number of received messages which were not found in the incoming messages filter.
+	IncomingMessagesFilterMisses atomic.Uint32
 	// The number of referenced messages.
 	ReferencedMessages atomic.Uint32
 	// The number of messages with a transaction payload.
@@ -22,6 +26,14 @@ type ServerMetrics struct {
 	ConflictingTransactionMessages atomic.Uint32
 	// The number of received invalid messages.
 	InvalidMessages atomic.Uint32
+	// The number of received messages which were rejected by the message filters.
+	FilterRejectedMessages atomic.Uint32
+	// The number of received messages which were not broadcasted because of the message filters.
+	FilterDeprioritizedMessages atomic.Uint32
+	// The number of received milestones which were rejected because their timestamp is too far in the future.
+	RejectedFutureMilestones atomic.Uint32
+	// The number of received milestones which were rejected because their timestamp is too far behind the latest milestone.
+	RejectedPastMilestones atomic.Uint32
 	// The number of received invalid requests (both messages and milestones).
 	InvalidRequests atomic.Uint32
 	// The number of received milestone requests.
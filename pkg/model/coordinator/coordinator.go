@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.uber.org/atomic"
 
 	"github.com/gohornet/hornet/pkg/utils"
 
@@ -42,6 +43,8 @@ var (
 	ErrNoTipsGiven = errors.New("no tips given")
 	// ErrNetworkBootstrapped is returned when the flag for bootstrap network was given, but a state file already exists.
 	ErrNetworkBootstrapped = errors.New("network already bootstrapped")
+	// ErrShadowModeBootstrap is returned when the network should be bootstrapped in shadow mode.
+	ErrShadowModeBootstrap = errors.New("network can't be bootstrapped in shadow mode")
 	// ErrInvalidSiblingsTrytesLength is returned when the computed siblings trytes do not fit into the signature message fragment.
 	ErrInvalidSiblingsTrytesLength = errors.New("siblings trytes too long")
 )
@@ -59,6 +62,8 @@ type Events struct {
 	SoftError *events.Event
 	// QuorumFinished is triggered after a coordinator quorum call was finished.
 	QuorumFinished *events.Event
+	// Fired when a milestone was created in shadow mode, it is not sent to the network.
+	IssuedShadowMilestone *events.Event
 }
 
 // PublicKeyRange is a public key of milestones with a valid range.
@@ -105,6 +110,10 @@ type Coordinator struct {
 	state *State
 	// whether the coordinator was bootstrapped.
 	bootstrapped bool
+	// the current interval milestones are issued.
+	milestoneInterval *atomic.Duration
+	// the messages created in shadow mode since the last milestone, they must not be referenced because they were not sent.
+	shadowMessageIDs map[string]struct{}
 	// events of the coordinator.
 	Events *Events
 }
@@ -140,6 +149,10 @@ type Options struct {
 	powWorkerCount int
 	// the optional quorum used by the coordinator to check for correct ledger state calculation.
 	quorum *quorum
+	// the optional config to adapt the milestone interval to the amount of unreferenced messages.
+	adaptiveInterval *AdaptiveIntervalConfig
+	// whether checkpoints and milestones are only created, but not sent to the network.
+	shadowMode bool
 }
 
 // applies the given Option.
@@ -201,14 +214,34 @@ func WithPoWWorkerCount(powWorkerCount int) Option {
 }
 
 // WithQuorum defines a quorum, which is used to check the correct ledger state of the coordinator.
-// If no quorumGroups are given, the quorum is disabled.
-func WithQuorum(quorumEnabled bool, quorumGroups map[string][]*QuorumClientConfig, deSeriParas *iotago.DeSerializationParameters, timeout time.Duration) Option {
+// If the quorum is not enabled, the quorumConfig is ignored.
+func WithQuorum(quorumEnabled bool, quorumConfig *QuorumConfig, deSeriParas *iotago.DeSerializationParameters) Option {
 	return func(opts *Options) {
 		if !quorumEnabled {
 			opts.quorum = nil
 			return
 		}
-		opts.quorum = newQuorum(quorumGroups, deSeriParas, timeout)
+		opts.quorum = newQuorum(quorumConfig, deSeriParas)
+	}
+}
+
+// WithAdaptiveInterval defines the bounds and the target the milestone interval is adapted with.
+// If the adaptive interval is not enabled, the milestone interval stays fixed.
+func WithAdaptiveInterval(adaptiveIntervalEnabled bool, adaptiveIntervalConfig *AdaptiveIntervalConfig) Option {
+	return func(opts *Options) {
+		if !adaptiveIntervalEnabled {
+			opts.adaptiveInterval = nil
+			return
+		}
+		opts.adaptiveInterval = adaptiveIntervalConfig
+	}
+}
+
+// WithShadowMode defines whether checkpoints and milestones are only created, but not sent to the network.
+// In shadow mode the coordinator follows the milestones of the network and doesn't use a state file.
+func WithShadowMode(shadowMode bool) Option {
+	return func(opts *Options) {
+		opts.shadowMode = shadowMode
 	}
 }
 
@@ -232,6 +265,14 @@ func New(
 	options.apply(defaultOptions...)
 	options.apply(opts...)
 
+	milestoneInterval := options.milestoneInterval
+	if options.adaptiveInterval != nil {
+		if err := options.adaptiveInterval.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid adaptive milestone interval: %w", err)
+		}
+		milestoneInterval = options.adaptiveInterval.clamp(milestoneInterval)
+	}
+
 	result := &Coordinator{
 		storage:          dbStorage,
 		syncManager:      syncManager,
@@ -244,11 +285,15 @@ func New(
 		sendMesssageFunc: sendMessageFunc,
 		opts:             options,
 
+		milestoneInterval: atomic.NewDuration(milestoneInterval),
+		shadowMessageIDs:  make(map[string]struct{}),
+
 		Events: &Events{
 			IssuedCheckpointMessage: events.NewEvent(CheckpointCaller),
 			IssuedMilestone:         events.NewEvent(MilestoneCaller),
 			SoftError:               events.NewEvent(events.ErrorCaller),
 			QuorumFinished:          events.NewEvent(QuorumFinishedCaller),
+			IssuedShadowMilestone:   events.NewEvent(ShadowMilestoneCaller),
 		},
 	}
 	result.WrappedLogger = utils.NewWrappedLogger(options.logger)
@@ -260,6 +305,20 @@ func New(
 // All errors are critical.
 func (coo *Coordinator) InitState(bootstrap bool, startIndex milestone.Index) error {
 
+	if coo.opts.shadowMode {
+		if bootstrap {
+			return ErrShadowModeBootstrap
+		}
+
+		// the state is taken from the milestones of the network
+		if err := coo.followNetworkMilestone(coo.storage.SearchLatestMilestoneIndexInStore()); err != nil {
+			return err
+		}
+
+		coo.bootstrapped = true
+		return nil
+	}
+
 	_, err := os.Stat(coo.opts.stateFilePath)
 	stateFileExists := !os.IsNotExist(err)
 
@@ -375,8 +434,9 @@ func (coo *Coordinator) createAndSendMilestone(parents hornet.MessageIDs, newMil
 	}
 
 	// get receipt data in case migrator is enabled
+	// receipts are not created in shadow mode, because they change the state of the migrator
 	var receipt *iotago.Receipt
-	if coo.migratorService != nil {
+	if coo.migratorService != nil && !coo.opts.shadowMode {
 		receipt = coo.migratorService.Receipt()
 		if receipt != nil {
 			if err := coo.migratorService.PersistState(true); err != nil {
@@ -400,9 +460,20 @@ func (coo *Coordinator) createAndSendMilestone(parents hornet.MessageIDs, newMil
 
 	milestoneMsg, err := coo.createMilestone(newMilestoneIndex, uint64(newMilestoneTimestamp.Unix()), parents, receipt, mutations.MerkleTreeHash)
 	if err != nil {
+		if receipt == nil && common.IsSoftError(err) != nil {
+			// not enough signers available => try again at the next interval.
+			// a receipt already updated the state of the migrator, so it can't be issued later.
+			return err
+		}
 		return common.CriticalError(fmt.Errorf("failed to create milestone: %w", err))
 	}
 
+	if coo.opts.shadowMode {
+		// the milestone is only exposed, the state follows the milestones of the network
+		coo.Events.IssuedShadowMilestone.Trigger(newMilestoneIndex, milestoneMsg)
+		return nil
+	}
+
 	if err := coo.sendMesssageFunc(milestoneMsg, newMilestoneIndex); err != nil {
 		return common.CriticalError(fmt.Errorf("failed to send milestone: %w", err))
 	}
@@ -495,7 +566,10 @@ func (coo *Coordinator) IssueCheckpoint(checkpointIndex int, lastCheckpointMessa
 			return nil, common.SoftError(fmt.Errorf("failed to create checkPoint: %w", err))
 		}
 
-		if err := coo.sendMesssageFunc(msg); err != nil {
+		if coo.opts.shadowMode {
+			// the checkpoint is not sent, so it must not be referenced by the milestone
+			coo.shadowMessageIDs[msg.MessageID().ToMapKey()] = struct{}{}
+		} else if err := coo.sendMesssageFunc(msg); err != nil {
 			return nil, common.SoftError(fmt.Errorf("failed to send checkPoint: %w", err))
 		}
 
@@ -525,6 +599,15 @@ func (coo *Coordinator) IssueMilestone(parents hornet.MessageIDs) (hornet.Messag
 		return nil, common.SoftError(common.ErrNodeLoadTooHigh)
 	}
 
+	if coo.opts.shadowMode {
+		// the shadow milestone is created on top of the latest milestone of the network
+		if err := coo.followNetworkMilestone(coo.syncManager.ConfirmedMilestoneIndex()); err != nil {
+			return nil, common.SoftError(err)
+		}
+
+		parents = coo.withoutShadowMessages(append(parents, coo.state.LatestMilestoneMessageID))
+	}
+
 	if err := coo.createAndSendMilestone(parents, coo.state.LatestMilestoneIndex+1); err != nil {
 		// creating milestone failed => non-critical or critical error
 		return nil, err
@@ -533,9 +616,55 @@ func (coo *Coordinator) IssueMilestone(parents hornet.MessageIDs) (hornet.Messag
 	return coo.state.LatestMilestoneMessageID, nil
 }
 
+// followNetworkMilestone sets the state of the coordinator to the milestone of the network with the given index.
+func (coo *Coordinator) followNetworkMilestone(index milestone.Index) error {
+	cachedMilestone := coo.storage.CachedMilestoneOrNil(index) // milestone +1
+	if cachedMilestone == nil {
+		return fmt.Errorf("milestone %d not found in database", index)
+	}
+	defer cachedMilestone.Release(true) // milestone -1
+
+	coo.state = &State{
+		LatestMilestoneIndex:     index,
+		LatestMilestoneMessageID: cachedMilestone.Milestone().MessageID,
+		LatestMilestoneTime:      cachedMilestone.Milestone().Timestamp,
+	}
+
+	return nil
+}
+
+// withoutShadowMessages removes the messages created in shadow mode from the parents.
+func (coo *Coordinator) withoutShadowMessages(parents hornet.MessageIDs) hornet.MessageIDs {
+	filteredParents := make(hornet.MessageIDs, 0, len(parents))
+	for _, parent := range parents {
+		if _, isShadowMessage := coo.shadowMessageIDs[parent.ToMapKey()]; !isShadowMessage {
+			filteredParents = append(filteredParents, parent)
+		}
+	}
+
+	// the next checkpoints are chained to the milestone again
+	coo.shadowMessageIDs = make(map[string]struct{})
+
+	return filteredParents
+}
+
 // Interval returns the interval milestones should be issued.
 func (coo *Coordinator) Interval() time.Duration {
-	return coo.opts.milestoneInterval
+	return coo.milestoneInterval.Load()
+}
+
+// AdaptInterval adapts the milestone interval to the amount of unreferenced messages
+// that arrived during the elapsed time and returns the new interval.
+// If the adaptive interval is disabled, the configured interval is returned.
+func (coo *Coordinator) AdaptInterval(unreferencedMessages int, elapsed time.Duration) time.Duration {
+	if coo.opts.adaptiveInterval == nil {
+		return coo.milestoneInterval.Load()
+	}
+
+	interval := coo.opts.adaptiveInterval.nextInterval(coo.milestoneInterval.Load(), unreferencedMessages, elapsed)
+	coo.milestoneInterval.Store(interval)
+
+	return interval
 }
 
 // State returns the current state of the coordinator.
@@ -567,3 +696,22 @@ func (coo *Coordinator) QuorumStats() []QuorumClientStatistic {
 
 	return coo.opts.quorum.quorumStatsSnapshot()
 }
+
+// QuorumConfig returns a copy of the current config of the quorum.
+func (coo *Coordinator) QuorumConfig() (*QuorumConfig, error) {
+	if coo.opts.quorum == nil {
+		return nil, ErrQuorumDisabled
+	}
+
+	return coo.opts.quorum.config(), nil
+}
+
+// SetQuorumConfig replaces the config of the quorum.
+// The new config is used starting with the next milestone.
+func (coo *Coordinator) SetQuorumConfig(quorumConfig *QuorumConfig) error {
+	if coo.opts.quorum == nil {
+		return ErrQuorumDisabled
+	}
+
+	return coo.opts.quorum.setConfig(quorumConfig)
+}
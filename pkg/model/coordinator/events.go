@@ -3,6 +3,7 @@ package coordinator
 import (
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
 )
 
 // CheckpointCaller is used to signal issued checkpoints.
@@ -19,3 +20,8 @@ func MilestoneCaller(handler interface{}, params ...interface{}) {
 func QuorumFinishedCaller(handler interface{}, params ...interface{}) {
 	handler.(func(result *QuorumFinishedResult))(params[0].(*QuorumFinishedResult))
 }
+
+// ShadowMilestoneCaller is used to signal milestones created in shadow mode.
+func ShadowMilestoneCaller(handler interface{}, params ...interface{}) {
+	handler.(func(index milestone.Index, msg *storage.Message))(params[0].(milestone.Index), params[1].(*storage.Message))
+}
@@ -0,0 +1,53 @@
+package coordinator
+
+import (
+	"fmt"
+	"time"
+)
+
+// AdaptiveIntervalConfig holds the configuration of the load-adaptive milestone interval.
+type AdaptiveIntervalConfig struct {
+	// the lower bound of the milestone interval.
+	MinInterval time.Duration
+	// the upper bound of the milestone interval.
+	MaxInterval time.Duration
+	// the amount of unreferenced messages a milestone should reference.
+	TargetUnreferencedMessages int
+}
+
+// Validate checks the bounds and the target of the config.
+func (c *AdaptiveIntervalConfig) Validate() error {
+	if c.MinInterval <= 0 {
+		return fmt.Errorf("minInterval must be greater than 0: %v", c.MinInterval)
+	}
+	if c.MaxInterval < c.MinInterval {
+		return fmt.Errorf("maxInterval (%v) must not be smaller than minInterval (%v)", c.MaxInterval, c.MinInterval)
+	}
+	if c.TargetUnreferencedMessages < 1 {
+		return fmt.Errorf("targetUnreferencedMessages must be at least 1: %d", c.TargetUnreferencedMessages)
+	}
+	return nil
+}
+
+// clamp returns the interval limited to the bounds of the config.
+func (c *AdaptiveIntervalConfig) clamp(interval time.Duration) time.Duration {
+	if interval < c.MinInterval {
+		return c.MinInterval
+	}
+	if interval > c.MaxInterval {
+		return c.MaxInterval
+	}
+	return interval
+}
+
+// nextInterval calculates the interval in which the target amount of unreferenced messages would arrive,
+// based on the rate of the unreferenced messages observed during the elapsed time.
+// The result is averaged with the current interval, so single bursts don't cause the interval to oscillate.
+func (c *AdaptiveIntervalConfig) nextInterval(currentInterval time.Duration, unreferencedMessages int, elapsed time.Duration) time.Duration {
+	idealInterval := c.MaxInterval
+	if unreferencedMessages > 0 && elapsed > 0 {
+		idealInterval = time.Duration(float64(elapsed) * float64(c.TargetUnreferencedMessages) / float64(unreferencedMessages))
+	}
+
+	return c.clamp(currentInterval/2 + c.clamp(idealInterval)/2)
+}
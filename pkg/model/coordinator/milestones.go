@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/storage"
@@ -20,7 +22,7 @@ func (coo *Coordinator) createCheckpoint(parents hornet.MessageIDs) (*storage.Me
 	}
 
 	// we pass a background context here to not create invalid checkpoints at node shutdown.
-	if err := coo.powHandler.DoPoW(context.Background(), iotaMsg, coo.opts.powWorkerCount); err != nil {
+	if err := coo.powHandler.DoPriorityPoW(context.Background(), iotaMsg, coo.opts.powWorkerCount); err != nil {
 		return nil, err
 	}
 
@@ -33,8 +35,27 @@ func (coo *Coordinator) createCheckpoint(parents hornet.MessageIDs) (*storage.Me
 }
 
 // createMilestone creates a signed milestone message.
+// If co-signers failed to sign the milestone, it is created again with the remaining co-signers
+// until the signer provider runs out of co-signers.
 func (coo *Coordinator) createMilestone(index milestone.Index, timestamp uint64, parents hornet.MessageIDs, receipt *iotago.Receipt, whiteFlagMerkleRootTreeHash [iotago.MilestoneInclusionMerkleProofLength]byte) (*storage.Message, error) {
-	milestoneIndexSigner := coo.signerProvider.MilestoneIndexSigner(index)
+	for {
+		milestoneIndexSigner, err := coo.signerProvider.MilestoneIndexSigner(index)
+		if err != nil {
+			return nil, err
+		}
+
+		msg, err := coo.createMilestoneWithSigner(milestoneIndexSigner, index, timestamp, parents, receipt, whiteFlagMerkleRootTreeHash)
+		if err != nil && errors.Is(err, ErrCoSignersFailed) {
+			coo.LogWarnf("signing milestone %d failed, failing over to the remaining co-signers: %s", index, err)
+			continue
+		}
+
+		return msg, err
+	}
+}
+
+// createMilestoneWithSigner creates a milestone message signed by the given signer.
+func (coo *Coordinator) createMilestoneWithSigner(milestoneIndexSigner MilestoneIndexSigner, index milestone.Index, timestamp uint64, parents hornet.MessageIDs, receipt *iotago.Receipt, whiteFlagMerkleRootTreeHash [iotago.MilestoneInclusionMerkleProofLength]byte) (*storage.Message, error) {
 	pubKeys := milestoneIndexSigner.PublicKeys()
 
 	parentsSliceOfArray := parents.ToSliceOfArrays()
@@ -62,7 +83,7 @@ func (coo *Coordinator) createMilestone(index milestone.Index, timestamp uint64,
 
 	// we pass a background context here to not create invalid milestones at node shutdown.
 	// otherwise the coordinator could panic at shutdown.
-	if err := coo.powHandler.DoPoW(context.Background(), iotaMsg, coo.opts.powWorkerCount); err != nil {
+	if err := coo.powHandler.DoPriorityPoW(context.Background(), iotaMsg, coo.opts.powWorkerCount); err != nil {
 		return nil, err
 	}
 
@@ -83,6 +104,10 @@ func (coo *Coordinator) createSigningFuncWithRetries(signingFunc iotago.Mileston
 		for i := 0; i < coo.opts.signingRetryAmount; i++ {
 			sigs, err = signingFunc(pubKeys, msEssence)
 			if err != nil {
+				if errors.Is(err, ErrCoSignersFailed) {
+					// the failed co-signers are not retried, the milestone is created again with the remaining co-signers
+					return
+				}
 				if i+1 != coo.opts.signingRetryAmount {
 					coo.LogWarnf("signing attempt failed: %s, retrying in %v, retries left %d", err, coo.opts.signingRetryTimeout, coo.opts.signingRetryAmount-(i+1))
 					time.Sleep(coo.opts.signingRetryTimeout)
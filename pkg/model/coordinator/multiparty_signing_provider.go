@@ -0,0 +1,273 @@
+package coordinator
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/keymanager"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrCoSignersFailed is returned if not all selected co-signers produced a valid signature.
+	ErrCoSignersFailed = errors.New("co-signers failed to produce the milestone signatures")
+	// ErrNotEnoughCoSigners is returned if there are less co-signers left for a milestone than the threshold.
+	ErrNotEnoughCoSigners = errors.New("not enough co-signers to reach the threshold")
+)
+
+// CoSignerConfig holds the configuration of a co-signer of the multi-party milestone signing.
+type CoSignerConfig struct {
+	// address of the remote signing service of the co-signer.
+	Address string `json:"address" koanf:"address"`
+	// the public key of the milestone key the co-signer holds.
+	PublicKey string `json:"publicKey" koanf:"publicKey"`
+	// optional path to the CA certificate to verify the co-signer with (empty = insecure connection).
+	CACertPath string `json:"caCertPath" koanf:"caCertPath"`
+	// optional path to the client certificate for mutual TLS authentication.
+	CertPath string `json:"certPath" koanf:"certPath"`
+	// optional path to the private key of the client certificate.
+	KeyPath string `json:"keyPath" koanf:"keyPath"`
+}
+
+// coSigner is the connection to a co-signer and the public key of the milestone key it holds.
+type coSigner struct {
+	*remoteSigner
+	publicKey iotago.MilestonePublicKey
+}
+
+// MultiPartyEd25519MilestoneSignerProvider provides milestone signers that collect the signatures from co-signers.
+// Every co-signer holds a single milestone key on its own machine, so a milestone is only valid
+// if the threshold of co-signers (the amount of public keys in a milestone) signed it.
+type MultiPartyEd25519MilestoneSignerProvider struct {
+	coSigners       []*coSigner
+	timeout         time.Duration
+	keyManger       *keymanager.KeyManager
+	publicKeysCount int
+
+	// the co-signers that failed to sign the milestone with failedIndex.
+	// they are not selected again for that milestone, so the next attempt fails over to the remaining co-signers.
+	failedLock    sync.Mutex
+	failedIndex   milestone.Index
+	failedSigners map[iotago.MilestonePublicKey]struct{}
+}
+
+// NewMultiPartyEd25519MilestoneSignerProvider creates a new MultiPartyEd25519MilestoneSignerProvider.
+// The amount of public keys in a milestone is the threshold of co-signers that need to sign a milestone,
+// timeout is the maximum duration of a signing request.
+func NewMultiPartyEd25519MilestoneSignerProvider(coSignerConfigs []*CoSignerConfig, timeout time.Duration, keyManager *keymanager.KeyManager, publicKeysCount int) (*MultiPartyEd25519MilestoneSignerProvider, error) {
+	if len(coSignerConfigs) < publicKeysCount {
+		return nil, fmt.Errorf("not enough co-signers given for a threshold of %d: %d", publicKeysCount, len(coSignerConfigs))
+	}
+
+	p := &MultiPartyEd25519MilestoneSignerProvider{
+		coSigners:       make([]*coSigner, 0, len(coSignerConfigs)),
+		timeout:         timeout,
+		keyManger:       keyManager,
+		publicKeysCount: publicKeysCount,
+		failedSigners:   make(map[iotago.MilestonePublicKey]struct{}),
+	}
+
+	publicKeys := make(map[iotago.MilestonePublicKey]struct{}, len(coSignerConfigs))
+	for _, coSignerConfig := range coSignerConfigs {
+		publicKeyBytes, err := hex.DecodeString(strings.TrimPrefix(coSignerConfig.PublicKey, "0x"))
+		if err != nil || len(publicKeyBytes) != ed25519.PublicKeySize {
+			p.Close()
+			return nil, fmt.Errorf("invalid public key of co-signer %s: %s", coSignerConfig.Address, coSignerConfig.PublicKey)
+		}
+
+		var publicKey iotago.MilestonePublicKey
+		copy(publicKey[:], publicKeyBytes)
+
+		if _, exists := publicKeys[publicKey]; exists {
+			p.Close()
+			return nil, fmt.Errorf("public key of co-signer %s is used by another co-signer", coSignerConfig.Address)
+		}
+		publicKeys[publicKey] = struct{}{}
+
+		signer, err := newRemoteSigner(&RemoteSignerConfig{
+			Address:    coSignerConfig.Address,
+			CACertPath: coSignerConfig.CACertPath,
+			CertPath:   coSignerConfig.CertPath,
+			KeyPath:    coSignerConfig.KeyPath,
+		})
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+
+		p.coSigners = append(p.coSigners, &coSigner{
+			remoteSigner: signer,
+			publicKey:    publicKey,
+		})
+	}
+
+	return p, nil
+}
+
+// MilestoneIndexSigner returns a new signer for the milestone index.
+// The public keys of the milestone are chosen from the co-signers whose keys are valid for the milestone index,
+// the healthy co-signers are preferred. Co-signers that already failed to sign the milestone are not chosen again.
+// A soft error is returned if less co-signers than the threshold are left.
+func (p *MultiPartyEd25519MilestoneSignerProvider) MilestoneIndexSigner(index milestone.Index) (MilestoneIndexSigner, error) {
+
+	pubKeySet := p.keyManger.PublicKeysSetForMilestoneIndex(index)
+
+	p.failedLock.Lock()
+	if p.failedIndex != index {
+		p.failedIndex = index
+		p.failedSigners = make(map[iotago.MilestonePublicKey]struct{})
+	}
+	failedSigners := len(p.failedSigners)
+
+	candidates := make([]*coSigner, 0, len(p.coSigners))
+	for _, healthy := range []bool{true, false} {
+		for _, signer := range p.coSigners {
+			if _, failed := p.failedSigners[signer.publicKey]; failed {
+				continue
+			}
+			if _, valid := pubKeySet[signer.publicKey]; valid && signer.healthy.Load() == healthy {
+				candidates = append(candidates, signer)
+			}
+		}
+	}
+	p.failedLock.Unlock()
+
+	if len(candidates) < p.publicKeysCount {
+		return nil, common.SoftError(errors.WithMessagef(ErrNotEnoughCoSigners, "milestone: %d, co-signers: %d, failed: %d, threshold: %d", index, len(candidates), failedSigners, p.publicKeysCount))
+	}
+	candidates = candidates[:p.publicKeysCount]
+
+	selectedSigners := make(map[iotago.MilestonePublicKey]*coSigner, len(candidates))
+	pubKeys := make([]iotago.MilestonePublicKey, 0, len(candidates))
+	for _, signer := range candidates {
+		selectedSigners[signer.publicKey] = signer
+		pubKeys = append(pubKeys, signer.publicKey)
+	}
+
+	return &MultiPartyEd25519MilestoneIndexSigner{
+		pubKeys:   pubKeys,
+		pubKeySet: pubKeySet,
+		signingFunc: func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+			return p.sign(index, selectedSigners, pubKeys, msEssence)
+		},
+	}, nil
+}
+
+// PublicKeysCount returns the amount of public keys in a milestone.
+func (p *MultiPartyEd25519MilestoneSignerProvider) PublicKeysCount() int {
+	return p.publicKeysCount
+}
+
+// sign requests the signatures of the milestone from the selected co-signers in parallel.
+// Every signature is verified, a co-signer that fails is marked as unhealthy, so it is not preferred for the next milestones,
+// and it is not selected again for this milestone. The public keys are part of the signed essence, so the milestone
+// has to be created again with a new signer to fail over to the remaining co-signers.
+func (p *MultiPartyEd25519MilestoneSignerProvider) sign(index milestone.Index, selectedSigners map[iotago.MilestonePublicKey]*coSigner, pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+
+	sigs := make([]iotago.MilestoneSignature, len(pubKeys))
+	errs := make([]error, len(pubKeys))
+
+	wg := &sync.WaitGroup{}
+	for i, pubKey := range pubKeys {
+		signer, exists := selectedSigners[pubKey]
+		if !exists {
+			errs[i] = fmt.Errorf("no co-signer for public key %s", hex.EncodeToString(pubKey[:]))
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, signer *coSigner) {
+			defer wg.Done()
+
+			signerSigs, err := signer.sign(p.timeout, []iotago.MilestonePublicKey{signer.publicKey}, msEssence)
+			if err == nil && !ed25519.Verify(signer.publicKey[:], msEssence, signerSigs[0][:]) {
+				err = errors.New("invalid signature")
+			}
+
+			if err != nil {
+				signer.healthy.Store(false)
+				p.markFailed(index, signer.publicKey)
+				errs[i] = fmt.Errorf("co-signer %s failed: %w", signer.address, err)
+				return
+			}
+
+			signer.healthy.Store(true)
+			sigs[i] = signerSigs[0]
+		}(i, signer)
+	}
+	wg.Wait()
+
+	var failures []string
+	for _, err := range errs {
+		if err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		return nil, errors.WithMessage(ErrCoSignersFailed, strings.Join(failures, ", "))
+	}
+
+	return sigs, nil
+}
+
+// markFailed excludes the co-signer from the next signers of the milestone index.
+func (p *MultiPartyEd25519MilestoneSignerProvider) markFailed(index milestone.Index, publicKey iotago.MilestonePublicKey) {
+	p.failedLock.Lock()
+	defer p.failedLock.Unlock()
+
+	if p.failedIndex != index {
+		p.failedIndex = index
+		p.failedSigners = make(map[iotago.MilestonePublicKey]struct{})
+	}
+	p.failedSigners[publicKey] = struct{}{}
+}
+
+// CheckHealth checks the health of all co-signers and returns their status.
+func (p *MultiPartyEd25519MilestoneSignerProvider) CheckHealth(ctx context.Context) []*RemoteSignerStatus {
+
+	statuses := make([]*RemoteSignerStatus, 0, len(p.coSigners))
+	for _, signer := range p.coSigners {
+		statuses = append(statuses, signer.checkHealth(ctx, p.timeout))
+	}
+
+	return statuses
+}
+
+// Close closes the connections to the co-signers.
+func (p *MultiPartyEd25519MilestoneSignerProvider) Close() {
+	for _, signer := range p.coSigners {
+		_ = signer.conn.Close()
+	}
+}
+
+// MultiPartyEd25519MilestoneIndexSigner is a multi-party signer for a particular milestone.
+type MultiPartyEd25519MilestoneIndexSigner struct {
+	pubKeys     []iotago.MilestonePublicKey
+	pubKeySet   iotago.MilestonePublicKeySet
+	signingFunc iotago.MilestoneSigningFunc
+}
+
+// PublicKeys returns a slice of the used public keys.
+func (s *MultiPartyEd25519MilestoneIndexSigner) PublicKeys() []iotago.MilestonePublicKey {
+	return s.pubKeys
+}
+
+// PublicKeysSet returns a map of the used public keys.
+func (s *MultiPartyEd25519MilestoneIndexSigner) PublicKeysSet() iotago.MilestonePublicKeySet {
+	return s.pubKeySet
+}
+
+// SigningFunc returns a function to sign the particular milestone.
+func (s *MultiPartyEd25519MilestoneIndexSigner) SigningFunc() iotago.MilestoneSigningFunc {
+	return s.signingFunc
+}
@@ -0,0 +1,271 @@
+package coordinator
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/keymanager"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/remotesigner"
+)
+
+const testSigningTimeout = time.Second
+
+// fakeSigner is an in-process remote signing service holding milestone keys.
+type fakeSigner struct {
+	remotesigner.UnimplementedSignatureDispatcherServer
+
+	address     string
+	privateKeys map[iotago.MilestonePublicKey]ed25519.PrivateKey
+	health      *health.Server
+
+	// the signing requests fail with an error.
+	fail *atomic.Bool
+	// the signing requests are delayed.
+	delay *atomic.Duration
+	// the signatures don't match the milestone essence.
+	invalidSignatures *atomic.Bool
+	// the amount of signing requests.
+	calls *atomic.Int32
+}
+
+// newFakeSigner starts a remote signing service with a health service that holds the given keys.
+func newFakeSigner(t *testing.T, privateKeys ...ed25519.PrivateKey) *fakeSigner {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &fakeSigner{
+		address:           listener.Addr().String(),
+		privateKeys:       make(map[iotago.MilestonePublicKey]ed25519.PrivateKey, len(privateKeys)),
+		health:            health.NewServer(),
+		fail:              atomic.NewBool(false),
+		delay:             atomic.NewDuration(0),
+		invalidSignatures: atomic.NewBool(false),
+		calls:             atomic.NewInt32(0),
+	}
+
+	for _, privateKey := range privateKeys {
+		s.privateKeys[milestonePublicKey(privateKey)] = privateKey
+	}
+
+	server := grpc.NewServer()
+	remotesigner.RegisterSignatureDispatcherServer(server, s)
+	grpc_health_v1.RegisterHealthServer(server, s.health)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	return s
+}
+
+func (s *fakeSigner) SignMilestone(ctx context.Context, req *remotesigner.SignMilestoneRequest) (*remotesigner.SignMilestoneResponse, error) {
+	s.calls.Inc()
+
+	select {
+	case <-time.After(s.delay.Load()):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if s.fail.Load() {
+		return nil, status.Error(codes.Unavailable, "signer failed")
+	}
+
+	sigs := make([][]byte, 0, len(req.GetPubKeys()))
+	for _, pubKeyBytes := range req.GetPubKeys() {
+		var pubKey iotago.MilestonePublicKey
+		copy(pubKey[:], pubKeyBytes)
+
+		privateKey, exists := s.privateKeys[pubKey]
+		if !exists {
+			return nil, status.Errorf(codes.NotFound, "unknown public key %s", hex.EncodeToString(pubKeyBytes))
+		}
+
+		sig := ed25519.Sign(privateKey, req.GetMsEssence())
+		if s.invalidSignatures.Load() {
+			sig[0] ^= 0xFF
+		}
+		sigs = append(sigs, sig)
+	}
+
+	return &remotesigner.SignMilestoneResponse{Signatures: sigs}, nil
+}
+
+func milestonePublicKey(privateKey ed25519.PrivateKey) iotago.MilestonePublicKey {
+	var pubKey iotago.MilestonePublicKey
+	copy(pubKey[:], privateKey.Public().(ed25519.PublicKey))
+	return pubKey
+}
+
+func generatePrivateKeys(t *testing.T, count int) []ed25519.PrivateKey {
+	privateKeys := make([]ed25519.PrivateKey, count)
+	for i := range privateKeys {
+		_, privateKey, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+		privateKeys[i] = privateKey
+	}
+	return privateKeys
+}
+
+// signTestMilestone creates a milestone with the public keys of the signer, signs and verifies it.
+func signTestMilestone(t *testing.T, signer MilestoneIndexSigner, index milestone.Index, threshold int) error {
+	ms, err := iotago.NewMilestone(uint32(index), uint64(time.Now().Unix()), iotago.MilestoneParentMessageIDs{{}}, iotago.MilestoneInclusionMerkleProof{}, signer.PublicKeys())
+	require.NoError(t, err)
+
+	if err := ms.Sign(signer.SigningFunc()); err != nil {
+		return err
+	}
+
+	return ms.VerifySignatures(threshold, signer.PublicKeysSet())
+}
+
+// newTestMultiPartyProvider starts a co-signer for every private key and creates a provider with the given threshold.
+// The keys are valid from the given start indexes on.
+func newTestMultiPartyProvider(t *testing.T, privateKeys []ed25519.PrivateKey, startIndexes []milestone.Index, threshold int) (*MultiPartyEd25519MilestoneSignerProvider, []*fakeSigner) {
+	keyManager := keymanager.New()
+
+	signers := make([]*fakeSigner, len(privateKeys))
+	coSignerConfigs := make([]*CoSignerConfig, len(privateKeys))
+	for i, privateKey := range privateKeys {
+		signers[i] = newFakeSigner(t, privateKey)
+
+		publicKey := privateKey.Public().(ed25519.PublicKey)
+		coSignerConfigs[i] = &CoSignerConfig{
+			Address:   signers[i].address,
+			PublicKey: hex.EncodeToString(publicKey),
+		}
+		keyManager.AddKeyRange(publicKey, startIndexes[i], startIndexes[i])
+	}
+
+	provider, err := NewMultiPartyEd25519MilestoneSignerProvider(coSignerConfigs, testSigningTimeout, keyManager, threshold)
+	require.NoError(t, err)
+	t.Cleanup(provider.Close)
+
+	return provider, signers
+}
+
+func TestMultiPartySignerThreshold(t *testing.T) {
+	privateKeys := generatePrivateKeys(t, 3)
+	provider, signers := newTestMultiPartyProvider(t, privateKeys, []milestone.Index{1, 1, 1}, 2)
+
+	// the first co-signers up to the threshold are selected
+	signer, err := provider.MilestoneIndexSigner(1)
+	require.NoError(t, err)
+	require.Equal(t, []iotago.MilestonePublicKey{milestonePublicKey(privateKeys[0]), milestonePublicKey(privateKeys[1])}, signer.PublicKeys())
+	require.Len(t, signer.PublicKeysSet(), 3)
+
+	require.NoError(t, signTestMilestone(t, signer, 1, provider.PublicKeysCount()))
+	require.EqualValues(t, 1, signers[0].calls.Load())
+	require.EqualValues(t, 1, signers[1].calls.Load())
+	require.EqualValues(t, 0, signers[2].calls.Load())
+
+	// healthy co-signers are preferred
+	provider.coSigners[0].healthy.Store(false)
+
+	signer, err = provider.MilestoneIndexSigner(2)
+	require.NoError(t, err)
+	require.Equal(t, []iotago.MilestonePublicKey{milestonePublicKey(privateKeys[1]), milestonePublicKey(privateKeys[2])}, signer.PublicKeys())
+
+	require.NoError(t, signTestMilestone(t, signer, 2, provider.PublicKeysCount()))
+	require.EqualValues(t, 1, signers[0].calls.Load())
+	require.EqualValues(t, 1, signers[2].calls.Load())
+}
+
+func TestMultiPartySignerFailover(t *testing.T) {
+	privateKeys := generatePrivateKeys(t, 3)
+	provider, signers := newTestMultiPartyProvider(t, privateKeys, []milestone.Index{1, 1, 1}, 2)
+
+	// the second co-signer is unhealthy, so the first and the third are selected
+	provider.coSigners[1].healthy.Store(false)
+	signers[0].fail.Store(true)
+
+	signer, err := provider.MilestoneIndexSigner(1)
+	require.NoError(t, err)
+	require.Equal(t, []iotago.MilestonePublicKey{milestonePublicKey(privateKeys[0]), milestonePublicKey(privateKeys[2])}, signer.PublicKeys())
+
+	err = signTestMilestone(t, signer, 1, provider.PublicKeysCount())
+	require.True(t, errors.Is(err, ErrCoSignersFailed))
+	require.False(t, provider.coSigners[0].healthy.Load())
+	require.True(t, provider.coSigners[2].healthy.Load())
+
+	// the failed co-signer is excluded, so the unhealthy one is used for the milestone
+	signer, err = provider.MilestoneIndexSigner(1)
+	require.NoError(t, err)
+	require.Equal(t, []iotago.MilestonePublicKey{milestonePublicKey(privateKeys[2]), milestonePublicKey(privateKeys[1])}, signer.PublicKeys())
+
+	require.NoError(t, signTestMilestone(t, signer, 1, provider.PublicKeysCount()))
+	require.True(t, provider.coSigners[1].healthy.Load())
+
+	// the failed co-signer is not preferred for the next milestones until it is healthy again
+	signer, err = provider.MilestoneIndexSigner(2)
+	require.NoError(t, err)
+	require.Equal(t, []iotago.MilestonePublicKey{milestonePublicKey(privateKeys[1]), milestonePublicKey(privateKeys[2])}, signer.PublicKeys())
+}
+
+func TestMultiPartySignerInvalidSignature(t *testing.T) {
+	privateKeys := generatePrivateKeys(t, 3)
+	provider, signers := newTestMultiPartyProvider(t, privateKeys, []milestone.Index{1, 1, 1}, 2)
+
+	signers[1].invalidSignatures.Store(true)
+
+	signer, err := provider.MilestoneIndexSigner(1)
+	require.NoError(t, err)
+
+	err = signTestMilestone(t, signer, 1, provider.PublicKeysCount())
+	require.True(t, errors.Is(err, ErrCoSignersFailed))
+	require.Contains(t, err.Error(), "invalid signature")
+	require.True(t, provider.coSigners[0].healthy.Load())
+	require.False(t, provider.coSigners[1].healthy.Load())
+
+	// the co-signer with the invalid signature is replaced
+	signer, err = provider.MilestoneIndexSigner(1)
+	require.NoError(t, err)
+	require.Equal(t, []iotago.MilestonePublicKey{milestonePublicKey(privateKeys[0]), milestonePublicKey(privateKeys[2])}, signer.PublicKeys())
+	require.NoError(t, signTestMilestone(t, signer, 1, provider.PublicKeysCount()))
+}
+
+func TestMultiPartySignerNotEnoughCoSigners(t *testing.T) {
+	privateKeys := generatePrivateKeys(t, 3)
+
+	// the key of the third co-signer is only valid from milestone 10 on
+	provider, signers := newTestMultiPartyProvider(t, privateKeys, []milestone.Index{1, 1, 10}, 2)
+
+	signers[0].fail.Store(true)
+
+	signer, err := provider.MilestoneIndexSigner(5)
+	require.NoError(t, err)
+	require.True(t, errors.Is(signTestMilestone(t, signer, 5, provider.PublicKeysCount()), ErrCoSignersFailed))
+
+	// only one co-signer is left for the milestone
+	_, err = provider.MilestoneIndexSigner(5)
+	require.Error(t, err)
+	require.NotNil(t, common.IsSoftError(err))
+	require.True(t, errors.Is(err, ErrNotEnoughCoSigners))
+
+	// the third co-signer takes over from milestone 10 on
+	signer, err = provider.MilestoneIndexSigner(10)
+	require.NoError(t, err)
+	require.NoError(t, signTestMilestone(t, signer, 10, provider.PublicKeysCount()))
+
+	// the threshold can't be reached with the keys of the milestone
+	provider.publicKeysCount = 3
+	_, err = provider.MilestoneIndexSigner(5)
+	require.NotNil(t, common.IsSoftError(err))
+}
@@ -2,7 +2,6 @@ package coordinator
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"net/url"
 	"sync"
@@ -21,8 +20,12 @@ import (
 var (
 	// ErrQuorumMerkleTreeHashMismatch is fired when a client in the quorum returns a different merkle tree hash.
 	ErrQuorumMerkleTreeHashMismatch = errors.New("coordinator quorum merkle tree hash mismatch")
-	// ErrQuorumGroupNoAnswer is fired when none of the clients in a quorum group answers.
+	// ErrQuorumGroupNoAnswer is fired when less clients in a quorum group than the threshold answer.
 	ErrQuorumGroupNoAnswer = errors.New("coordinator quorum group did not answer in time")
+	// ErrQuorumDisabled is returned if the quorum config is accessed while the quorum is disabled.
+	ErrQuorumDisabled = errors.New("coordinator quorum is disabled")
+	// ErrInvalidQuorumConfig is returned for an invalid quorum config.
+	ErrInvalidQuorumConfig = errors.New("invalid coordinator quorum config")
 )
 
 // QuorumClientConfig holds the configuration of a quorum client.
@@ -37,6 +40,66 @@ type QuorumClientConfig struct {
 	Password string `json:"password" koanf:"password"`
 }
 
+// QuorumConfig holds the configuration of the quorum.
+type QuorumConfig struct {
+	// the quorum groups used to ask other nodes for correct ledger state of the coordinator.
+	Groups map[string][]*QuorumClientConfig
+	// the minimum amount of clients in every group that need to answer.
+	Threshold int
+	// the timeout until a client in the quorum must have answered.
+	Timeout time.Duration
+}
+
+// Validate checks that every group has enough clients for the threshold and that the clients of a group are unique.
+func (c *QuorumConfig) Validate() error {
+	if len(c.Groups) == 0 {
+		return errors.WithMessage(ErrInvalidQuorumConfig, "no groups given")
+	}
+	if c.Threshold < 1 {
+		return errors.WithMessage(ErrInvalidQuorumConfig, "threshold must be at least 1")
+	}
+	if c.Timeout <= 0 {
+		return errors.WithMessage(ErrInvalidQuorumConfig, "timeout must be greater than 0")
+	}
+
+	for groupName, groupClients := range c.Groups {
+		if len(groupClients) < c.Threshold {
+			return errors.WithMessagef(ErrInvalidQuorumConfig, "group %s has less clients (%d) than the threshold (%d)", groupName, len(groupClients), c.Threshold)
+		}
+
+		baseURLs := make(map[string]struct{}, len(groupClients))
+		for _, client := range groupClients {
+			if _, err := url.ParseRequestURI(client.BaseURL); err != nil {
+				return errors.WithMessagef(ErrInvalidQuorumConfig, "invalid baseURL in group %s: %s", groupName, client.BaseURL)
+			}
+			if _, exists := baseURLs[client.BaseURL]; exists {
+				return errors.WithMessagef(ErrInvalidQuorumConfig, "baseURL %s exists twice in group %s", client.BaseURL, groupName)
+			}
+			baseURLs[client.BaseURL] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// Clone returns a deep copy of the quorum config.
+func (c *QuorumConfig) Clone() *QuorumConfig {
+	groups := make(map[string][]*QuorumClientConfig, len(c.Groups))
+	for groupName, groupClients := range c.Groups {
+		groups[groupName] = make([]*QuorumClientConfig, len(groupClients))
+		for i, client := range groupClients {
+			clientCopy := *client
+			groups[groupName][i] = &clientCopy
+		}
+	}
+
+	return &QuorumConfig{
+		Groups:    groups,
+		Threshold: c.Threshold,
+		Timeout:   c.Timeout,
+	}
+}
+
 // QuorumClientStatistic holds statistics of a quorum client.
 type QuorumClientStatistic struct {
 	// name of the quorum group the client is member of.
@@ -59,33 +122,45 @@ type QuorumFinishedResult struct {
 
 // quorumGroupEntry holds the api and statistics of a quorum client.
 type quorumGroupEntry struct {
-	api   *DebugNodeAPIClient
-	stats *QuorumClientStatistic
+	api    *DebugNodeAPIClient
+	stats  *QuorumClientStatistic
+	config *QuorumClientConfig
 }
 
 // quorum is used to check the correct ledger state of the coordinator.
 type quorum struct {
 	// the different groups of the quorum.
 	Groups map[string][]*quorumGroupEntry
+	// the minimum amount of clients in every group that need to answer.
+	Threshold int
 	// the maximim timeout of a quorum request.
 	Timeout time.Duration
 
+	deSeriParas *iotago.DeSerializationParameters
+
+	// the lock is held while the quorum is asked, so the config can't change during a quorum call.
 	quorumStatsLock syncutils.RWMutex
 }
 
 // newQuorum creates a new quorum, which is used to check the correct ledger state of the coordinator.
-// If no groups are given, nil is returned.
-func newQuorum(quorumGroups map[string][]*QuorumClientConfig, deSeriParas *iotago.DeSerializationParameters, timeout time.Duration) *quorum {
-	if len(quorumGroups) == 0 {
-		panic("coordinator quorum groups not found")
+// It panics if the config is invalid.
+func newQuorum(quorumConfig *QuorumConfig, deSeriParas *iotago.DeSerializationParameters) *quorum {
+	if err := quorumConfig.Validate(); err != nil {
+		panic(err)
 	}
 
-	groups := make(map[string][]*quorumGroupEntry)
-	for groupName, groupNodes := range quorumGroups {
-		if len(groupNodes) == 0 {
-			panic(fmt.Sprintf("invalid coo quorum group: %s, no nodes given", groupName))
-		}
+	q := &quorum{deSeriParas: deSeriParas}
+	q.applyConfig(quorumConfig)
 
+	return q
+}
+
+// applyConfig creates the clients of the quorum for the given config.
+// The statistics of clients that are kept are taken over.
+func (q *quorum) applyConfig(quorumConfig *QuorumConfig) {
+
+	groups := make(map[string][]*quorumGroupEntry)
+	for groupName, groupNodes := range quorumConfig.Groups {
 		groups[groupName] = make([]*quorumGroupEntry, len(groupNodes))
 		for i, client := range groupNodes {
 			var userInfo *url.Userinfo
@@ -93,30 +168,74 @@ func newQuorum(quorumGroups map[string][]*QuorumClientConfig, deSeriParas *iotag
 				userInfo = url.UserPassword(client.UserName, client.Password)
 			}
 
+			stats := &QuorumClientStatistic{
+				Group:   groupName,
+				Alias:   client.Alias,
+				BaseURL: client.BaseURL,
+			}
+			for _, entry := range q.Groups[groupName] {
+				if entry.config.BaseURL == client.BaseURL {
+					stats.ResponseTimeSeconds = entry.stats.ResponseTimeSeconds
+					stats.Error = entry.stats.Error
+				}
+			}
+
+			clientConfig := *client
 			groups[groupName][i] = &quorumGroupEntry{
 				api: NewDebugNodeAPIClient(client.BaseURL,
-					deSeriParas,
-					nodeclient.WithHTTPClient(&http.Client{Timeout: timeout}),
+					q.deSeriParas,
+					nodeclient.WithHTTPClient(&http.Client{Timeout: quorumConfig.Timeout}),
 					nodeclient.WithUserInfo(userInfo),
 				),
-				stats: &QuorumClientStatistic{
-					Group:   groupName,
-					Alias:   client.Alias,
-					BaseURL: client.BaseURL,
-				},
+				stats:  stats,
+				config: &clientConfig,
 			}
 		}
 	}
 
-	return &quorum{
-		Groups:  groups,
-		Timeout: timeout,
+	q.Groups = groups
+	q.Threshold = quorumConfig.Threshold
+	q.Timeout = quorumConfig.Timeout
+}
+
+// config returns a copy of the current config of the quorum.
+func (q *quorum) config() *QuorumConfig {
+	q.quorumStatsLock.RLock()
+	defer q.quorumStatsLock.RUnlock()
+
+	groups := make(map[string][]*QuorumClientConfig, len(q.Groups))
+	for groupName, entries := range q.Groups {
+		groups[groupName] = make([]*QuorumClientConfig, len(entries))
+		for i, entry := range entries {
+			clientConfig := *entry.config
+			groups[groupName][i] = &clientConfig
+		}
+	}
+
+	return &QuorumConfig{
+		Groups:    groups,
+		Threshold: q.Threshold,
+		Timeout:   q.Timeout,
+	}
+}
+
+// setConfig replaces the config of the quorum, a running quorum call is finished first.
+func (q *quorum) setConfig(quorumConfig *QuorumConfig) error {
+	if err := quorumConfig.Validate(); err != nil {
+		return err
 	}
+
+	q.quorumStatsLock.Lock()
+	defer q.quorumStatsLock.Unlock()
+
+	q.applyConfig(quorumConfig)
+
+	return nil
 }
 
 // checkMerkleTreeHashQuorumGroup asks all nodes in a quorum group for their merkle tree hash based on the given parents.
 // Returns non-critical and critical errors.
-// If no node of the group answers, a non-critical error is returned.
+// If less nodes of the group than the threshold answer, a non-critical error is returned.
 // If one of the nodes returns a different hash, a critical error is returned.
 func (q *quorum) checkMerkleTreeHashQuorumGroup(cooMerkleTreeHash MerkleTreeHash, groupName string, quorumGroupEntries []*quorumGroupEntry, wg *sync.WaitGroup, quorumDoneChan chan struct{}, quorumErrChan chan error, index milestone.Index, parents hornet.MessageIDs, onGroupEntryError func(groupName string, entry *quorumGroupEntry, err error)) {
 	// mark the group as done at the end
@@ -179,15 +298,15 @@ QuorumLoop:
 		}
 	}
 
-	if validResults == 0 {
-		// no node of the group answered, return a non-critical error.
-		quorumErrChan <- common.SoftError(ErrQuorumGroupNoAnswer)
+	if validResults < q.Threshold {
+		// not enough nodes of the group answered, return a non-critical error.
+		quorumErrChan <- common.SoftError(errors.WithMessagef(ErrQuorumGroupNoAnswer, "group: %s, answers: %d, threshold: %d", groupName, validResults, q.Threshold))
 	}
 }
 
 // checkMerkleTreeHash asks all nodes in the quorum for their merkle tree hash based on the given parents.
 // Returns non-critical and critical errors.
-// If no node of a certain group answers, a non-critical error is returned.
+// If less nodes of a certain group than the threshold answer, a non-critical error is returned.
 // If one of the nodes returns a different hash, a critical error is returned.
 func (q *quorum) checkMerkleTreeHash(cooMerkleTreeHash MerkleTreeHash, index milestone.Index, parents hornet.MessageIDs, onGroupEntryError func(groupName string, entry *quorumGroupEntry, err error)) error {
 	q.quorumStatsLock.Lock()
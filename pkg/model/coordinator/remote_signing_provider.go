@@ -0,0 +1,324 @@
+package coordinator
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/gohornet/hornet/pkg/keymanager"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/remotesigner"
+)
+
+var (
+	// ErrNoRemoteSignerAnswered is returned if none of the remote signers produced the milestone signatures.
+	ErrNoRemoteSignerAnswered = errors.New("none of the remote signers produced the milestone signatures")
+)
+
+// RemoteSignerConfig holds the configuration of a remote signing service.
+type RemoteSignerConfig struct {
+	// address of the remote signing service.
+	Address string `json:"address" koanf:"address"`
+	// optional path to the CA certificate to verify the remote signing service with (empty = insecure connection).
+	CACertPath string `json:"caCertPath" koanf:"caCertPath"`
+	// optional path to the client certificate for mutual TLS authentication.
+	CertPath string `json:"certPath" koanf:"certPath"`
+	// optional path to the private key of the client certificate.
+	KeyPath string `json:"keyPath" koanf:"keyPath"`
+}
+
+// RemoteSignerHealthChecker checks the health of the remote signing services a signer provider uses.
+type RemoteSignerHealthChecker interface {
+	// CheckHealth checks the health of all remote signing services and returns their status.
+	CheckHealth(ctx context.Context) []*RemoteSignerStatus
+	// Close closes the connections to the remote signing services.
+	Close()
+}
+
+// RemoteSignerStatus holds the result of the last health check of a remote signer.
+type RemoteSignerStatus struct {
+	// address of the remote signing service.
+	Address string
+	// whether the remote signer passed the last health check.
+	Healthy bool
+	// error of the last health check.
+	Error error
+}
+
+// remoteSigner is the connection to a remote signing service.
+type remoteSigner struct {
+	address string
+	conn    *grpc.ClientConn
+	client  remotesigner.SignatureDispatcherClient
+	health  grpc_health_v1.HealthClient
+	healthy *atomic.Bool
+}
+
+// RemoteEd25519MilestoneSignerProvider provides RemoteEd25519MilestoneIndexSigner.
+// The milestones are signed by the first healthy remote signer, if it fails the next one is used.
+type RemoteEd25519MilestoneSignerProvider struct {
+	signers         []*remoteSigner
+	timeout         time.Duration
+	keyManger       *keymanager.KeyManager
+	publicKeysCount int
+}
+
+// NewRemoteEd25519MilestoneSignerProvider creates a new RemoteEd25519MilestoneSignerProvider.
+// The remote signers are used in the given order, timeout is the maximum duration of a signing request.
+func NewRemoteEd25519MilestoneSignerProvider(signerConfigs []*RemoteSignerConfig, timeout time.Duration, keyManager *keymanager.KeyManager, publicKeysCount int) (*RemoteEd25519MilestoneSignerProvider, error) {
+	if len(signerConfigs) == 0 {
+		return nil, errors.New("no remote signers given")
+	}
+
+	p := &RemoteEd25519MilestoneSignerProvider{
+		signers:         make([]*remoteSigner, 0, len(signerConfigs)),
+		timeout:         timeout,
+		keyManger:       keyManager,
+		publicKeysCount: publicKeysCount,
+	}
+
+	for _, signerConfig := range signerConfigs {
+		signer, err := newRemoteSigner(signerConfig)
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		p.signers = append(p.signers, signer)
+	}
+
+	return p, nil
+}
+
+// newRemoteSigner creates a connection to the remote signing service.
+func newRemoteSigner(signerConfig *RemoteSignerConfig) (*remoteSigner, error) {
+	if signerConfig.Address == "" {
+		return nil, errors.New("no address given for remote signer")
+	}
+
+	transportCredentials, err := remoteSignerTransportCredentials(signerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS config of remote signer %s: %w", signerConfig.Address, err)
+	}
+
+	// the connection is established in the background and re-established after failures
+	conn, err := grpc.Dial(signerConfig.Address, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote signer %s: %w", signerConfig.Address, err)
+	}
+
+	return &remoteSigner{
+		address: signerConfig.Address,
+		conn:    conn,
+		client:  remotesigner.NewSignatureDispatcherClient(conn),
+		health:  grpc_health_v1.NewHealthClient(conn),
+		healthy: atomic.NewBool(true),
+	}, nil
+}
+
+// remoteSignerTransportCredentials returns the TLS credentials for the remote signer,
+// or insecure credentials if no CA certificate is configured.
+func remoteSignerTransportCredentials(signerConfig *RemoteSignerConfig) (credentials.TransportCredentials, error) {
+	if signerConfig.CACertPath == "" {
+		// insecure, the remote signer should be reachable on a trusted network only
+		return insecure.NewCredentials(), nil
+	}
+
+	caCert, err := ioutil.ReadFile(signerConfig.CACertPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA certificate failed: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    x509.NewCertPool(),
+	}
+	if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("no valid CA certificate found in %s", signerConfig.CACertPath)
+	}
+
+	if signerConfig.CertPath != "" {
+		cert, err := tls.LoadX509KeyPair(signerConfig.CertPath, signerConfig.KeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate failed: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// MilestoneIndexSigner returns a new signer for the milestone index.
+func (p *RemoteEd25519MilestoneSignerProvider) MilestoneIndexSigner(index milestone.Index) (MilestoneIndexSigner, error) {
+
+	return &RemoteEd25519MilestoneIndexSigner{
+		pubKeys:     p.keyManger.PublicKeysForMilestoneIndex(index),
+		pubKeySet:   p.keyManger.PublicKeysSetForMilestoneIndex(index),
+		signingFunc: p.sign,
+	}, nil
+}
+
+// PublicKeysCount returns the amount of public keys in a milestone.
+func (p *RemoteEd25519MilestoneSignerProvider) PublicKeysCount() int {
+	return p.publicKeysCount
+}
+
+// sign requests the milestone signatures from the remote signers.
+// The healthy signers are tried first, the unhealthy ones only if all healthy signers failed.
+func (p *RemoteEd25519MilestoneSignerProvider) sign(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+
+	signers := make([]*remoteSigner, 0, len(p.signers))
+	for _, signer := range p.signers {
+		if signer.healthy.Load() {
+			signers = append(signers, signer)
+		}
+	}
+	for _, signer := range p.signers {
+		if !signer.healthy.Load() {
+			signers = append(signers, signer)
+		}
+	}
+
+	var signErr error
+	for _, signer := range signers {
+		sigs, err := signer.sign(p.timeout, pubKeys, msEssence)
+		if err != nil {
+			// fail over to the next signer until the next health check marks this one as healthy again
+			signer.healthy.Store(false)
+			signErr = fmt.Errorf("remote signer %s failed: %w", signer.address, err)
+			continue
+		}
+
+		signer.healthy.Store(true)
+		return sigs, nil
+	}
+
+	return nil, errors.WithMessagef(ErrNoRemoteSignerAnswered, "last error: %s", signErr)
+}
+
+// sign requests the signatures for the given public keys from the remote signing service.
+// The signatures are verified against the public keys, so an invalid signature fails the request.
+func (s *remoteSigner) sign(timeout time.Duration, pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	pubKeysUnbound := make([][]byte, len(pubKeys))
+	for i := range pubKeys {
+		pubKeysUnbound[i] = make([]byte, len(pubKeys[i]))
+		copy(pubKeysUnbound[i], pubKeys[i][:])
+	}
+
+	response, err := s.client.SignMilestone(ctx, &remotesigner.SignMilestoneRequest{
+		PubKeys:   pubKeysUnbound,
+		MsEssence: msEssence,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sigs := response.GetSignatures()
+	if len(sigs) != len(pubKeys) {
+		return nil, fmt.Errorf("%w: remote did not provide the correct count of signatures", iotago.ErrMilestoneProducedSignaturesCountMismatch)
+	}
+
+	sigs64 := make([]iotago.MilestoneSignature, len(sigs))
+	for i := range sigs {
+		if len(sigs[i]) != len(sigs64[i]) {
+			return nil, fmt.Errorf("remote provided a signature with invalid length: %d", len(sigs[i]))
+		}
+		copy(sigs64[i][:], sigs[i])
+
+		// a misbehaving or compromised remote signer must not produce an invalid milestone
+		if !ed25519.Verify(pubKeys[i][:], msEssence, sigs64[i][:]) {
+			return nil, fmt.Errorf("remote provided an invalid signature for public key %s", hex.EncodeToString(pubKeys[i][:]))
+		}
+	}
+
+	return sigs64, nil
+}
+
+// CheckHealth checks the health of all remote signers and returns their status.
+// Remote signers that don't implement the gRPC health service are healthy as long as they answer.
+func (p *RemoteEd25519MilestoneSignerProvider) CheckHealth(ctx context.Context) []*RemoteSignerStatus {
+
+	statuses := make([]*RemoteSignerStatus, 0, len(p.signers))
+	for _, signer := range p.signers {
+		statuses = append(statuses, signer.checkHealth(ctx, p.timeout))
+	}
+
+	return statuses
+}
+
+// checkHealth checks the health of the remote signing service and stores the result.
+func (s *remoteSigner) checkHealth(ctx context.Context, timeout time.Duration) *RemoteSignerStatus {
+	err := s.checkServing(ctx, timeout)
+	s.healthy.Store(err == nil)
+
+	return &RemoteSignerStatus{
+		Address: s.address,
+		Healthy: err == nil,
+		Error:   err,
+	}
+}
+
+func (s *remoteSigner) checkServing(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	response, err := s.health.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return nil
+		}
+		return err
+	}
+
+	if response.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("remote signer is not serving: %s", response.GetStatus())
+	}
+
+	return nil
+}
+
+// Close closes the connections to the remote signers.
+func (p *RemoteEd25519MilestoneSignerProvider) Close() {
+	for _, signer := range p.signers {
+		_ = signer.conn.Close()
+	}
+}
+
+// RemoteEd25519MilestoneIndexSigner is a remote signer for a particular milestone.
+type RemoteEd25519MilestoneIndexSigner struct {
+	pubKeys     []iotago.MilestonePublicKey
+	pubKeySet   iotago.MilestonePublicKeySet
+	signingFunc iotago.MilestoneSigningFunc
+}
+
+// PublicKeys returns a slice of the used public keys.
+func (s *RemoteEd25519MilestoneIndexSigner) PublicKeys() []iotago.MilestonePublicKey {
+	return s.pubKeys
+}
+
+// PublicKeysSet returns a map of the used public keys.
+func (s *RemoteEd25519MilestoneIndexSigner) PublicKeysSet() iotago.MilestonePublicKeySet {
+	return s.pubKeySet
+}
+
+// SigningFunc returns a function to sign the particular milestone.
+func (s *RemoteEd25519MilestoneIndexSigner) SigningFunc() iotago.MilestoneSigningFunc {
+	return s.signingFunc
+}
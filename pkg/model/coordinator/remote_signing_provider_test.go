@@ -0,0 +1,246 @@
+package coordinator
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/gohornet/hornet/pkg/keymanager"
+)
+
+// newTestRemoteProvider starts a remote signer holding all private keys for every given signer
+// and creates a provider that uses them in the given order.
+func newTestRemoteProvider(t *testing.T, privateKeys []ed25519.PrivateKey, signerCount int, timeout time.Duration) (*RemoteEd25519MilestoneSignerProvider, []*fakeSigner) {
+	keyManager := keymanager.New()
+	for _, privateKey := range privateKeys {
+		keyManager.AddKeyRange(privateKey.Public().(ed25519.PublicKey), 1, 1)
+	}
+
+	signers := make([]*fakeSigner, signerCount)
+	signerConfigs := make([]*RemoteSignerConfig, signerCount)
+	for i := range signers {
+		signers[i] = newFakeSigner(t, privateKeys...)
+		signerConfigs[i] = &RemoteSignerConfig{Address: signers[i].address}
+	}
+
+	provider, err := NewRemoteEd25519MilestoneSignerProvider(signerConfigs, timeout, keyManager, len(privateKeys))
+	require.NoError(t, err)
+	t.Cleanup(provider.Close)
+
+	return provider, signers
+}
+
+func TestRemoteSignerFirstHealthy(t *testing.T) {
+	privateKeys := generatePrivateKeys(t, 2)
+	provider, signers := newTestRemoteProvider(t, privateKeys, 3, testSigningTimeout)
+
+	signer, err := provider.MilestoneIndexSigner(1)
+	require.NoError(t, err)
+	require.Len(t, signer.PublicKeys(), 2)
+
+	// the first signer is used
+	require.NoError(t, signTestMilestone(t, signer, 1, provider.PublicKeysCount()))
+	require.EqualValues(t, 1, signers[0].calls.Load())
+	require.EqualValues(t, 0, signers[1].calls.Load())
+
+	// the first healthy signer is used
+	provider.signers[0].healthy.Store(false)
+	require.NoError(t, signTestMilestone(t, signer, 1, provider.PublicKeysCount()))
+	require.EqualValues(t, 1, signers[0].calls.Load())
+	require.EqualValues(t, 1, signers[1].calls.Load())
+	require.EqualValues(t, 0, signers[2].calls.Load())
+}
+
+func TestRemoteSignerFailover(t *testing.T) {
+	privateKeys := generatePrivateKeys(t, 2)
+	provider, signers := newTestRemoteProvider(t, privateKeys, 3, 200*time.Millisecond)
+
+	signer, err := provider.MilestoneIndexSigner(1)
+	require.NoError(t, err)
+
+	// the first signer fails and the second one times out
+	signers[0].fail.Store(true)
+	signers[1].delay.Store(time.Second)
+
+	require.NoError(t, signTestMilestone(t, signer, 1, provider.PublicKeysCount()))
+	require.EqualValues(t, 1, signers[0].calls.Load())
+	require.EqualValues(t, 1, signers[1].calls.Load())
+	require.EqualValues(t, 1, signers[2].calls.Load())
+
+	require.False(t, provider.signers[0].healthy.Load())
+	require.False(t, provider.signers[1].healthy.Load())
+	require.True(t, provider.signers[2].healthy.Load())
+
+	// the unhealthy signers are only used if all healthy signers fail
+	signers[2].fail.Store(true)
+	signers[1].delay.Store(0)
+
+	require.NoError(t, signTestMilestone(t, signer, 1, provider.PublicKeysCount()))
+	require.EqualValues(t, 2, signers[2].calls.Load())
+	require.EqualValues(t, 2, signers[0].calls.Load())
+	require.EqualValues(t, 2, signers[1].calls.Load())
+	require.True(t, provider.signers[1].healthy.Load())
+
+	// all signers fail
+	signers[1].fail.Store(true)
+
+	err = signTestMilestone(t, signer, 1, provider.PublicKeysCount())
+	require.True(t, errors.Is(err, ErrNoRemoteSignerAnswered))
+}
+
+func TestRemoteSignerInvalidSignatures(t *testing.T) {
+	privateKeys := generatePrivateKeys(t, 2)
+	provider, signers := newTestRemoteProvider(t, privateKeys, 2, testSigningTimeout)
+
+	signer, err := provider.MilestoneIndexSigner(1)
+	require.NoError(t, err)
+
+	// the invalid signatures of the first signer are detected and the next signer is used
+	signers[0].invalidSignatures.Store(true)
+
+	require.NoError(t, signTestMilestone(t, signer, 1, provider.PublicKeysCount()))
+	require.EqualValues(t, 1, signers[0].calls.Load())
+	require.EqualValues(t, 1, signers[1].calls.Load())
+	require.False(t, provider.signers[0].healthy.Load())
+	require.True(t, provider.signers[1].healthy.Load())
+
+	// no milestone is built if all signers produce invalid signatures
+	signers[1].invalidSignatures.Store(true)
+
+	err = signTestMilestone(t, signer, 1, provider.PublicKeysCount())
+	require.True(t, errors.Is(err, ErrNoRemoteSignerAnswered))
+}
+
+func TestRemoteSignerHealth(t *testing.T) {
+	privateKeys := generatePrivateKeys(t, 2)
+	provider, signers := newTestRemoteProvider(t, privateKeys, 2, testSigningTimeout)
+
+	signers[1].health.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	statuses := provider.CheckHealth(context.Background())
+	require.Len(t, statuses, 2)
+	require.Equal(t, signers[0].address, statuses[0].Address)
+	require.True(t, statuses[0].Healthy)
+	require.NoError(t, statuses[0].Error)
+	require.False(t, statuses[1].Healthy)
+	require.Error(t, statuses[1].Error)
+	require.False(t, provider.signers[1].healthy.Load())
+
+	// a failed signing request marks the signer as unhealthy until the next health check
+	signers[0].fail.Store(true)
+
+	signer, err := provider.MilestoneIndexSigner(1)
+	require.NoError(t, err)
+	require.NoError(t, signTestMilestone(t, signer, 1, provider.PublicKeysCount()))
+	require.False(t, provider.signers[0].healthy.Load())
+	require.True(t, provider.signers[1].healthy.Load())
+
+	// both signers recover
+	signers[0].fail.Store(false)
+	signers[1].health.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	statuses = provider.CheckHealth(context.Background())
+	require.True(t, statuses[0].Healthy)
+	require.True(t, statuses[1].Healthy)
+	require.True(t, provider.signers[0].healthy.Load())
+
+	require.NoError(t, signTestMilestone(t, signer, 1, provider.PublicKeysCount()))
+	require.EqualValues(t, 2, signers[0].calls.Load())
+}
+
+func TestRemoteSignerTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	invalidPEMPath := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, ioutil.WriteFile(invalidPEMPath, []byte("no certificate"), 0600))
+
+	caCertPath := filepath.Join(dir, "ca.pem")
+	require.NoError(t, ioutil.WriteFile(caCertPath, testCACertificate(t), 0600))
+
+	// no CA certificate means an insecure connection
+	signer, err := newRemoteSigner(&RemoteSignerConfig{Address: "127.0.0.1:0"})
+	require.NoError(t, err)
+	require.NoError(t, signer.conn.Close())
+
+	// the server is verified without a client certificate
+	signer, err = newRemoteSigner(&RemoteSignerConfig{
+		Address:    "127.0.0.1:0",
+		CACertPath: caCertPath,
+	})
+	require.NoError(t, err)
+	require.NoError(t, signer.conn.Close())
+
+	_, err = newRemoteSigner(&RemoteSignerConfig{})
+	require.Error(t, err)
+
+	_, err = newRemoteSigner(&RemoteSignerConfig{
+		Address:    "127.0.0.1:0",
+		CACertPath: filepath.Join(dir, "missing.pem"),
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "reading CA certificate failed")
+
+	_, err = newRemoteSigner(&RemoteSignerConfig{
+		Address:    "127.0.0.1:0",
+		CACertPath: invalidPEMPath,
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no valid CA certificate found")
+
+	_, err = newRemoteSigner(&RemoteSignerConfig{
+		Address:    "127.0.0.1:0",
+		CACertPath: caCertPath,
+		CertPath:   filepath.Join(dir, "missing-cert.pem"),
+		KeyPath:    filepath.Join(dir, "missing-key.pem"),
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "loading client certificate failed")
+
+	_, err = newRemoteSigner(&RemoteSignerConfig{
+		Address:    "127.0.0.1:0",
+		CACertPath: caCertPath,
+		CertPath:   caCertPath,
+		KeyPath:    invalidPEMPath,
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "loading client certificate failed")
+
+	_, err = NewRemoteEd25519MilestoneSignerProvider([]*RemoteSignerConfig{{Address: "127.0.0.1:0", CACertPath: invalidPEMPath}}, testSigningTimeout, keymanager.New(), 1)
+	require.Error(t, err)
+
+	_, err = NewRemoteEd25519MilestoneSignerProvider(nil, testSigningTimeout, keymanager.New(), 1)
+	require.Error(t, err)
+}
+
+// testCACertificate returns a PEM encoded self-signed CA certificate.
+func testCACertificate(t *testing.T) []byte {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "remote signer CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, publicKey, privateKey)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+}
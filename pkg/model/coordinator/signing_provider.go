@@ -11,7 +11,7 @@ import (
 // MilestoneSignerProvider provides milestone signers.
 type MilestoneSignerProvider interface {
 	// MilestoneIndexSigner returns a new signer for the milestone index.
-	MilestoneIndexSigner(milestone.Index) MilestoneIndexSigner
+	MilestoneIndexSigner(milestone.Index) (MilestoneIndexSigner, error)
 	// PublicKeysCount returns the amount of public keys in a milestone.
 	PublicKeysCount() int
 }
@@ -44,7 +44,7 @@ func NewInMemoryEd25519MilestoneSignerProvider(privateKeys []ed25519.PrivateKey,
 }
 
 // MilestoneIndexSigner returns a new signer for the milestone index.
-func (p *InMemoryEd25519MilestoneSignerProvider) MilestoneIndexSigner(index milestone.Index) MilestoneIndexSigner {
+func (p *InMemoryEd25519MilestoneSignerProvider) MilestoneIndexSigner(index milestone.Index) (MilestoneIndexSigner, error) {
 
 	pubKeySet := p.keyManger.PublicKeysSetForMilestoneIndex(index)
 
@@ -60,7 +60,7 @@ func (p *InMemoryEd25519MilestoneSignerProvider) MilestoneIndexSigner(index mile
 		pubKeys:     pubKeys,
 		pubKeySet:   pubKeySet,
 		signingFunc: milestoneSignFunc,
-	}
+	}, nil
 }
 
 // PublicKeysCount returns the amount of public keys in a milestone.
@@ -89,57 +89,3 @@ func (s *InMemoryEd25519MilestoneIndexSigner) PublicKeysSet() iotago.MilestonePu
 func (s *InMemoryEd25519MilestoneIndexSigner) SigningFunc() iotago.MilestoneSigningFunc {
 	return s.signingFunc
 }
-
-// InsecureRemoteEd25519MilestoneSignerProvider provides InsecureRemoteEd25519MilestoneIndexSigner.
-type InsecureRemoteEd25519MilestoneSignerProvider struct {
-	signingFunc     iotago.MilestoneSigningFunc
-	keyManger       *keymanager.KeyManager
-	publicKeysCount int
-}
-
-// NewInsecureRemoteEd25519MilestoneSignerProvider creates a new InsecureRemoteEd25519MilestoneSignerProvider.
-func NewInsecureRemoteEd25519MilestoneSignerProvider(remoteEndpoint string, keyManager *keymanager.KeyManager, publicKeysCount int) *InsecureRemoteEd25519MilestoneSignerProvider {
-
-	return &InsecureRemoteEd25519MilestoneSignerProvider{
-		signingFunc:     iotago.InsecureRemoteEd25519MilestoneSigner(remoteEndpoint),
-		keyManger:       keyManager,
-		publicKeysCount: publicKeysCount,
-	}
-}
-
-// MilestoneIndexSigner returns a new signer for the milestone index.
-func (p *InsecureRemoteEd25519MilestoneSignerProvider) MilestoneIndexSigner(index milestone.Index) MilestoneIndexSigner {
-
-	return &InsecureRemoteEd25519MilestoneIndexSigner{
-		pubKeys:     p.keyManger.PublicKeysForMilestoneIndex(index),
-		pubKeySet:   p.keyManger.PublicKeysSetForMilestoneIndex(index),
-		signingFunc: p.signingFunc,
-	}
-}
-
-// PublicKeysCount returns the amount of public keys in a milestone.
-func (p *InsecureRemoteEd25519MilestoneSignerProvider) PublicKeysCount() int {
-	return p.publicKeysCount
-}
-
-// InsecureRemoteEd25519MilestoneIndexSigner is an in memory signer for a particular milestone.
-type InsecureRemoteEd25519MilestoneIndexSigner struct {
-	pubKeys     []iotago.MilestonePublicKey
-	pubKeySet   iotago.MilestonePublicKeySet
-	signingFunc iotago.MilestoneSigningFunc
-}
-
-// PublicKeys returns a slice of the used public keys.
-func (s *InsecureRemoteEd25519MilestoneIndexSigner) PublicKeys() []iotago.MilestonePublicKey {
-	return s.pubKeys
-}
-
-// PublicKeysSet returns a map of the used public keys.
-func (s *InsecureRemoteEd25519MilestoneIndexSigner) PublicKeysSet() iotago.MilestonePublicKeySet {
-	return s.pubKeySet
-}
-
-// SigningFunc returns a function to sign the particular milestone.
-func (s *InsecureRemoteEd25519MilestoneIndexSigner) SigningFunc() iotago.MilestoneSigningFunc {
-	return s.signingFunc
-}
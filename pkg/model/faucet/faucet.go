@@ -24,6 +24,7 @@ import (
 	"github.com/gohornet/hornet/pkg/whiteflag"
 	"github.com/iotaledger/hive.go/daemon"
 	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/serializer/v2"
 	"github.com/iotaledger/hive.go/syncutils"
@@ -71,6 +72,10 @@ type FaucetInfoResponse struct {
 	Address string `json:"address"`
 	// The remaining balance of faucet.
 	Balance uint64 `json:"balance"`
+	// Whether the faucet is in drain mode and doesn't accept new requests.
+	Draining bool `json:"draining"`
+	// The number of accepted requests that were not confirmed yet.
+	PendingRequests int `json:"pendingRequests"`
 }
 
 // FaucetEnqueueResponse defines the response of a POST RouteFaucetEnqueue REST API call.
@@ -136,6 +141,14 @@ type Faucet struct {
 	// the latest unused UTXO output that may not be confirmed yet but can be reused in new transactions.
 	// this is used to issue multiple transactions without waiting for the confirmation by milestones.
 	lastRemainderOutput *utxo.Output
+	// whether the faucet is in drain mode and doesn't accept new requests.
+	draining bool
+	// closed as soon as all accepted requests were confirmed in drain mode.
+	drainedChan chan struct{}
+	// whether drainedChan was already closed.
+	drained bool
+	// the persisted confirmed payouts, nil if no store was given.
+	payoutHistory *payoutHistory
 }
 
 // the default options applied to the faucet.
@@ -148,6 +161,7 @@ var defaultOptions = []Option{
 	WithTagMessage("HORNET FAUCET"),
 	WithBatchTimeout(2 * time.Second),
 	WithPowWorkerCount(0),
+	WithPayoutHistoryRetention(30 * 24 * time.Hour),
 }
 
 // Options define options for the faucet.
@@ -162,6 +176,9 @@ type Options struct {
 	tagMessage        []byte
 	batchTimeout      time.Duration
 	powWorkerCount    int
+	// the store the confirmed payouts are persisted in.
+	payoutHistoryStore     kvstore.KVStore
+	payoutHistoryRetention time.Duration
 }
 
 // applies the given Option.
@@ -251,6 +268,20 @@ func WithPowWorkerCount(powWorkerCount int) Option {
 	}
 }
 
+// WithPayoutHistoryStore sets the store the confirmed payouts are persisted in for the statistics.
+func WithPayoutHistoryStore(store kvstore.KVStore) Option {
+	return func(opts *Options) {
+		opts.payoutHistoryStore = store
+	}
+}
+
+// WithPayoutHistoryRetention defines how long the confirmed payouts are kept for the statistics.
+func WithPayoutHistoryRetention(retention time.Duration) Option {
+	return func(opts *Options) {
+		opts.payoutHistoryRetention = retention
+	}
+}
+
 // Option is a function setting a faucet option.
 type Option func(opts *Options)
 
@@ -299,6 +330,10 @@ func New(
 	faucet.WrappedLogger = utils.NewWrappedLogger(options.logger)
 	faucet.init()
 
+	if options.payoutHistoryStore != nil {
+		faucet.payoutHistory = newPayoutHistory(options.payoutHistoryStore, options.payoutHistoryRetention)
+	}
+
 	return faucet
 }
 
@@ -310,6 +345,9 @@ func (f *Faucet) init() {
 	f.pendingTransactionsMap = make(map[string]*pendingTransaction)
 	f.lastMessageID = nil
 	f.lastRemainderOutput = nil
+	f.draining = false
+	f.drainedChan = make(chan struct{})
+	f.drained = false
 }
 
 // NetworkPrefix returns the used network prefix.
@@ -319,12 +357,96 @@ func (f *Faucet) NetworkPrefix() iotago.NetworkPrefix {
 
 // Info returns the used faucet address and remaining balance.
 func (f *Faucet) Info() (*FaucetInfoResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+
 	return &FaucetInfoResponse{
-		Address: f.address.Bech32(f.opts.hrpNetworkPrefix),
-		Balance: f.faucetBalance,
+		Address:         f.address.Bech32(f.opts.hrpNetworkPrefix),
+		Balance:         f.faucetBalance,
+		Draining:        f.draining,
+		PendingRequests: len(f.queueMap),
 	}, nil
 }
 
+// PayoutHistoryRetention returns how long the confirmed payouts are kept for the statistics.
+func (f *Faucet) PayoutHistoryRetention() time.Duration {
+	return f.opts.payoutHistoryRetention
+}
+
+// Stats returns the confirmed payouts and unique requesters within the window before now, aggregated in buckets of the given size.
+func (f *Faucet) Stats(window time.Duration, bucketSize time.Duration) (*FaucetStatsResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.payoutHistory == nil {
+		return nil, ErrPayoutHistoryDisabled
+	}
+
+	return f.payoutHistory.stats(time.Now(), window, bucketSize)
+}
+
+// CloseDatabase flushes the payout history store and closes the underlying database.
+func (f *Faucet) CloseDatabase() error {
+	if f.opts.payoutHistoryStore == nil {
+		return nil
+	}
+
+	var flushAndCloseError error
+	if err := f.opts.payoutHistoryStore.Flush(); err != nil {
+		flushAndCloseError = err
+	}
+	if err := f.opts.payoutHistoryStore.Close(); err != nil {
+		flushAndCloseError = err
+	}
+	return flushAndCloseError
+}
+
+// Drain stops accepting new requests and flushes the current batch.
+// The requests which were already accepted are still issued and confirmed.
+// The returned channel is closed as soon as all accepted requests were confirmed.
+func (f *Faucet) Drain() <-chan struct{} {
+	f.Lock()
+	defer f.Unlock()
+
+	if !f.draining {
+		f.draining = true
+		f.LogInfof("drain mode started, waiting for %d pending requests", len(f.queueMap))
+
+		// stop the current batching, so the queued requests don't wait for the batch timeout
+		select {
+		case f.flushQueue <- struct{}{}:
+		default:
+		}
+	}
+	f.checkDrainedWithoutLocking()
+
+	return f.drainedChan
+}
+
+// IsDraining returns whether the faucet is in drain mode.
+func (f *Faucet) IsDraining() bool {
+	f.Lock()
+	defer f.Unlock()
+
+	return f.draining
+}
+
+// checkDrainedWithoutLocking signals that the drain is finished if there are no more queued or pending requests.
+// write lock must be acquired outside.
+func (f *Faucet) checkDrainedWithoutLocking() {
+	if !f.draining || f.drained {
+		return
+	}
+
+	if len(f.queueMap) > 0 || len(f.pendingTransactionsMap) > 0 {
+		return
+	}
+
+	f.drained = true
+	close(f.drainedChan)
+	f.LogInfo("drain mode finished, all accepted requests were confirmed")
+}
+
 func (f *Faucet) computeAddressBalance(address iotago.Address) (uint64, error) {
 	result := f.indexer.ExtendedOutputsWithFilters(indexer.ExtendedOutputUnlockableByAddress(address), indexer.ExtendedOutputHasDustReturnCondition(false))
 	if result.Error != nil {
@@ -357,6 +479,10 @@ func (f *Faucet) Enqueue(bech32Addr string) (*FaucetEnqueueResponse, error) {
 	f.Lock()
 	defer f.Unlock()
 
+	if f.draining {
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "Faucet is shutting down. Please try again later!")
+	}
+
 	if _, exists := f.queueMap[bech32Addr]; exists {
 		return nil, errors.WithMessage(restapi.ErrInvalidParameter, "Address is already in the queue.")
 	}
@@ -451,6 +577,19 @@ func (f *Faucet) addPendingTransactionWithoutLocking(pending *pendingTransaction
 	f.pendingTransactionsMap[pending.MessageID.ToMapKey()] = pending
 }
 
+// addPayoutsWithoutLocking persists the payouts of a confirmed transaction.
+// write lock must be acquired outside.
+func (f *Faucet) addPayoutsWithoutLocking(pendingTx *pendingTransaction) {
+	if f.payoutHistory == nil {
+		return
+	}
+
+	if err := f.payoutHistory.addPayouts(time.Now(), pendingTx.MessageID, pendingTx.QueuedItems); err != nil {
+		// the statistics are not critical for the faucet
+		f.LogWarnf("failed to store faucet payouts: %s", err)
+	}
+}
+
 // clearPendingTransactionWithoutLocking removes tracking of a pending transaction.
 // write lock must be acquired outside.
 func (f *Faucet) clearPendingTransactionWithoutLocking(msgID hornet.MessageID) {
@@ -482,7 +621,7 @@ func (f *Faucet) createMessage(ctx context.Context, txPayload iotago.Payload, ti
 		Payload:   txPayload,
 	}
 
-	if err := f.powHandler.DoPoW(ctx, iotaMsg, 1); err != nil {
+	if err := f.powHandler.DoPoW(ctx, iotaMsg, f.opts.powWorkerCount); err != nil {
 		return nil, err
 	}
 
@@ -817,6 +956,9 @@ func (f *Faucet) RunFaucetLoop(ctx context.Context, initDoneCallback func()) err
 
 				processableRequests := f.processRequestsWithoutLocking(len(unspentOutputs), amount, batchedRequests)
 
+				// requests may have been dropped because of insufficient funds
+				f.checkDrainedWithoutLocking()
+
 				return unspentOutputs, processableRequests, tips, nil
 			}
 
@@ -866,6 +1008,7 @@ func (f *Faucet) ApplyConfirmation(confirmation *whiteflag.Confirmation) error {
 	for _, msgID := range confirmation.Mutations.MessagesIncludedWithTransactions {
 		if pendingTx, pending := f.pendingTransactionsMap[msgID.ToMapKey()]; pending {
 			// transaction was confirmed => delete the requests and the pending transaction
+			f.addPayoutsWithoutLocking(pendingTx)
 			f.clearRequestsWithoutLocking(pendingTx.QueuedItems)
 			f.clearPendingTransactionWithoutLocking(msgID)
 
@@ -915,6 +1058,7 @@ func (f *Faucet) ApplyConfirmation(confirmation *whiteflag.Confirmation) error {
 			}
 
 			// transaction was confirmed => delete the requests and the pending transaction
+			f.addPayoutsWithoutLocking(pendingTx)
 			f.clearRequestsWithoutLocking(pendingTx.QueuedItems)
 			f.clearPendingTransactionWithoutLocking(msgID)
 			return
@@ -922,6 +1066,10 @@ func (f *Faucet) ApplyConfirmation(confirmation *whiteflag.Confirmation) error {
 
 		// check if message is "below max depth"
 		_, ocri, err := dag.ConeRootIndexes(f.daemon.ContextStopped(), f.storage, cachedMsgMeta.Retain(), cmi)
+		if errors.Is(err, common.ErrOperationAborted) {
+			// the node is shutting down (e.g. while the faucet is drained) => keep the transaction pending
+			return
+		}
 		if err != nil {
 			// an error occurred => readd the items to the queue and delete the pending transaction
 			conflicting = true
@@ -968,6 +1116,8 @@ func (f *Faucet) ApplyConfirmation(confirmation *whiteflag.Confirmation) error {
 		return common.CriticalError(fmt.Errorf("reading faucet address balance failed: %s, error: %s", f.address.Bech32(f.opts.hrpNetworkPrefix), err))
 	}
 
+	f.checkDrainedWithoutLocking()
+
 	if faucetBalance < pendingRequestsBalance {
 		f.faucetBalance = 0
 		return nil
@@ -2,6 +2,7 @@ package faucet_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -74,6 +75,47 @@ func TestSingleRequest(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestStats(t *testing.T) {
+
+	var faucetBalance uint64 = 1_000_000_000        //  1 Gi
+	var faucetAmount uint64 = 10_000_000            // 10 Mi
+	var faucetSmallAmount uint64 = 1_000_000        //  1 Mi
+	var faucetMaxAddressBalance uint64 = 20_000_000 // 20 Mi
+
+	env := test.NewFaucetTestEnv(t,
+		faucetBalance,
+		0,
+		0,
+		0,
+		faucetAmount,
+		faucetSmallAmount,
+		faucetMaxAddressBalance,
+		false)
+	defer env.Cleanup()
+	require.NotNil(t, env)
+
+	stats, err := env.Faucet.Stats(24*time.Hour, time.Hour)
+	require.NoError(t, err)
+	require.Zero(t, stats.Payouts)
+	require.Len(t, stats.Buckets, 25)
+
+	require.NoError(t, env.RequestFundsAndIssueMilestone(env.Wallet1))
+	require.NoError(t, env.RequestFundsAndIssueMilestone(env.Wallet2))
+	require.NoError(t, env.RequestFundsAndIssueMilestone(env.Wallet1))
+
+	stats, err = env.Faucet.Stats(24*time.Hour, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, 3, stats.Payouts)
+	require.Equal(t, 2*faucetAmount+faucetSmallAmount, stats.Amount)
+	require.Equal(t, 2, stats.UniqueRequesters)
+
+	bucketPayouts := 0
+	for _, bucket := range stats.Buckets {
+		bucketPayouts += bucket.Payouts
+	}
+	require.Equal(t, 3, bucketPayouts)
+}
+
 func TestMultipleRequests(t *testing.T) {
 	// requests to multiple addresses
 
@@ -489,3 +531,64 @@ func TestCollectFaucetFunds(t *testing.T) {
 
 	env.AssertAddressUTXOCount(env.FaucetWallet.Address(), 1)
 }
+
+func TestDrain(t *testing.T) {
+	// already accepted requests are paid out while draining, new requests are rejected
+
+	var faucetBalance uint64 = 1_000_000_000        //  1 Gi
+	var wallet1Balance uint64 = 0                   //  0  i
+	var wallet2Balance uint64 = 0                   //  0  i
+	var wallet3Balance uint64 = 0                   //  0  i
+	var faucetAmount uint64 = 10_000_000            // 10 Mi
+	var faucetSmallAmount uint64 = 1_000_000        //  1 Mi
+	var faucetMaxAddressBalance uint64 = 20_000_000 // 20 Mi
+
+	env := test.NewFaucetTestEnv(t,
+		faucetBalance,
+		wallet1Balance,
+		wallet2Balance,
+		wallet3Balance,
+		faucetAmount,
+		faucetSmallAmount,
+		faucetMaxAddressBalance,
+		false)
+	defer env.Cleanup()
+	require.NotNil(t, env)
+
+	tips, err := env.RequestFunds(env.Wallet1)
+	require.NoError(t, err)
+
+	drained := env.Faucet.Drain()
+	require.True(t, env.Faucet.IsDraining())
+
+	_, err = env.Faucet.Enqueue(env.Wallet2.Address().Bech32(iotago.PrefixTestnet))
+	require.Error(t, err)
+
+	faucetInfo, err := env.Faucet.Info()
+	require.NoError(t, err)
+	require.True(t, faucetInfo.Draining)
+	require.Equal(t, 1, faucetInfo.PendingRequests)
+
+	select {
+	case <-drained:
+		require.FailNow(t, "faucet drained before the pending request was confirmed")
+	default:
+	}
+
+	// confirm the faucet message
+	_, _ = env.IssueMilestone(tips...)
+
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		require.FailNow(t, "faucet was not drained after the pending request was confirmed")
+	}
+
+	faucetInfo, err = env.Faucet.Info()
+	require.NoError(t, err)
+	require.Equal(t, 0, faucetInfo.PendingRequests)
+
+	env.AssertFaucetBalance(faucetBalance - faucetAmount)
+	env.TestEnv.AssertLedgerBalance(env.Wallet1, wallet1Balance+faucetAmount)
+	env.TestEnv.AssertLedgerBalance(env.Wallet2, wallet2Balance)
+}
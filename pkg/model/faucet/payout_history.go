@@ -0,0 +1,199 @@
+package faucet
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/serializer/v2"
+)
+
+const (
+	// the interval in which the payouts older than the retention are deleted.
+	payoutHistoryPruningInterval = time.Hour
+)
+
+var (
+	// ErrPayoutHistoryDisabled is returned if the statistics are requested but no payout history store was given.
+	ErrPayoutHistoryDisabled = errors.New("payout history is disabled")
+)
+
+// FaucetStatsBucket holds the confirmed payouts of a time bucket.
+type FaucetStatsBucket struct {
+	// The unix timestamp of the start of the bucket.
+	Start int64 `json:"start"`
+	// The number of payouts.
+	Payouts int `json:"payouts"`
+	// The paid out amount.
+	Amount uint64 `json:"amount"`
+	// The number of different addresses that received funds.
+	UniqueRequesters int `json:"uniqueRequesters"`
+}
+
+// FaucetStatsResponse defines the response of a GET RouteFaucetStats REST API call.
+type FaucetStatsResponse struct {
+	// The size of the buckets in seconds.
+	BucketSize int64 `json:"bucketSize"`
+	// The unix timestamp of the start of the window.
+	From int64 `json:"from"`
+	// The unix timestamp of the end of the window.
+	To int64 `json:"to"`
+	// The number of payouts in the window.
+	Payouts int `json:"payouts"`
+	// The paid out amount in the window.
+	Amount uint64 `json:"amount"`
+	// The number of different addresses that received funds in the window.
+	UniqueRequesters int `json:"uniqueRequesters"`
+	// The payouts per bucket, ordered by time. Buckets without payouts are included.
+	Buckets []*FaucetStatsBucket `json:"buckets"`
+}
+
+// payoutHistory persists the confirmed payouts of the faucet.
+// The key of a payout consists of the confirmation time, the message ID and the index of the payout in the message,
+// so the payouts are ordered by time in the store.
+type payoutHistory struct {
+	store     kvstore.KVStore
+	retention time.Duration
+	lastPrune time.Time
+}
+
+func newPayoutHistory(store kvstore.KVStore, retention time.Duration) *payoutHistory {
+	return &payoutHistory{
+		store:     store,
+		retention: retention,
+	}
+}
+
+func payoutKey(confirmedAt time.Time, messageID hornet.MessageID, index int) []byte {
+	key := make([]byte, 8, 8+len(messageID)+2)
+	binary.BigEndian.PutUint64(key, uint64(confirmedAt.Unix()))
+	key = append(key, messageID...)
+	return append(key, byte(index>>8), byte(index))
+}
+
+func payoutValue(request *queueItem) ([]byte, error) {
+	addressBytes, err := request.Address.Serialize(serializer.DeSeriModeNoValidation, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	value := make([]byte, 8, 8+len(addressBytes))
+	binary.LittleEndian.PutUint64(value, request.Amount)
+	return append(value, addressBytes...), nil
+}
+
+// addPayouts stores the requests that were paid out in the confirmed message.
+func (h *payoutHistory) addPayouts(confirmedAt time.Time, messageID hornet.MessageID, requests []*queueItem) error {
+
+	batch := h.store.Batched()
+	for i, request := range requests {
+		value, err := payoutValue(request)
+		if err != nil {
+			batch.Cancel()
+			return err
+		}
+
+		if err := batch.Set(payoutKey(confirmedAt, messageID, i), value); err != nil {
+			batch.Cancel()
+			return err
+		}
+	}
+
+	if err := batch.Commit(); err != nil {
+		return err
+	}
+
+	if confirmedAt.Sub(h.lastPrune) < payoutHistoryPruningInterval {
+		return nil
+	}
+	h.lastPrune = confirmedAt
+
+	return h.prune(confirmedAt.Add(-h.retention))
+}
+
+// prune deletes the payouts that were confirmed before the given time.
+func (h *payoutHistory) prune(before time.Time) error {
+
+	var keysToDelete []kvstore.Key
+	if err := h.store.IterateKeys(kvstore.EmptyPrefix, func(key kvstore.Key) bool {
+		if int64(binary.BigEndian.Uint64(key[:8])) < before.Unix() {
+			keysToDelete = append(keysToDelete, append(kvstore.Key{}, key...))
+		}
+		return true
+	}); err != nil {
+		return err
+	}
+
+	for _, key := range keysToDelete {
+		if err := h.store.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// stats aggregates the payouts that were confirmed within the window before now in buckets of the given size.
+func (h *payoutHistory) stats(now time.Time, window time.Duration, bucketSize time.Duration) (*FaucetStatsResponse, error) {
+
+	from := now.Add(-window).Truncate(bucketSize)
+	bucketCount := int(now.Sub(from)/bucketSize) + 1
+
+	response := &FaucetStatsResponse{
+		BucketSize: int64(bucketSize / time.Second),
+		From:       from.Unix(),
+		To:         now.Unix(),
+		Buckets:    make([]*FaucetStatsBucket, bucketCount),
+	}
+
+	bucketRequesters := make([]map[string]struct{}, bucketCount)
+	for i := range response.Buckets {
+		response.Buckets[i] = &FaucetStatsBucket{
+			Start: from.Add(time.Duration(i) * bucketSize).Unix(),
+		}
+		bucketRequesters[i] = make(map[string]struct{})
+	}
+	requesters := make(map[string]struct{})
+
+	var innerErr error
+	if err := h.store.Iterate(kvstore.EmptyPrefix, func(key kvstore.Key, value kvstore.Value) bool {
+		confirmedAt := time.Unix(int64(binary.BigEndian.Uint64(key[:8])), 0)
+		if confirmedAt.Before(from) || confirmedAt.After(now) {
+			return true
+		}
+
+		if len(value) < 8 {
+			innerErr = errors.Errorf("invalid payout history entry: %x", key)
+			return false
+		}
+		amount := binary.LittleEndian.Uint64(value[:8])
+		address := string(value[8:])
+
+		i := int(confirmedAt.Sub(from) / bucketSize)
+		bucket := response.Buckets[i]
+		bucket.Payouts++
+		bucket.Amount += amount
+		bucketRequesters[i][address] = struct{}{}
+
+		response.Payouts++
+		response.Amount += amount
+		requesters[address] = struct{}{}
+
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	if innerErr != nil {
+		return nil, innerErr
+	}
+
+	for i, bucket := range response.Buckets {
+		bucket.UniqueRequesters = len(bucketRequesters[i])
+	}
+	response.UniqueRequesters = len(requesters)
+
+	return response, nil
+}
@@ -0,0 +1,80 @@
+package faucet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestPayoutHistoryStats(t *testing.T) {
+
+	history := newPayoutHistory(mapdb.NewMapDB(), 48*time.Hour)
+
+	address1 := utils.RandAddress(iotago.AddressEd25519)
+	address2 := utils.RandAddress(iotago.AddressEd25519)
+
+	now := time.Date(2022, 1, 10, 12, 30, 0, 0, time.UTC)
+
+	// outside of the window
+	require.NoError(t, history.addPayouts(now.Add(-30*time.Hour), utils.RandMessageID(), []*queueItem{
+		{Address: address1, Amount: 100},
+	}))
+	// two payouts in the first bucket of the window
+	require.NoError(t, history.addPayouts(now.Add(-3*time.Hour), utils.RandMessageID(), []*queueItem{
+		{Address: address1, Amount: 10},
+		{Address: address2, Amount: 20},
+	}))
+	// the same requester in the current bucket twice
+	require.NoError(t, history.addPayouts(now.Add(-10*time.Minute), utils.RandMessageID(), []*queueItem{
+		{Address: address1, Amount: 1},
+	}))
+	require.NoError(t, history.addPayouts(now, utils.RandMessageID(), []*queueItem{
+		{Address: address1, Amount: 2},
+	}))
+
+	stats, err := history.stats(now, 3*time.Hour, time.Hour)
+	require.NoError(t, err)
+
+	require.Equal(t, int64(3600), stats.BucketSize)
+	require.Equal(t, time.Date(2022, 1, 10, 9, 0, 0, 0, time.UTC).Unix(), stats.From)
+	require.Equal(t, now.Unix(), stats.To)
+	require.Equal(t, 4, stats.Payouts)
+	require.Equal(t, uint64(33), stats.Amount)
+	require.Equal(t, 2, stats.UniqueRequesters)
+
+	require.Equal(t, []*FaucetStatsBucket{
+		{Start: time.Date(2022, 1, 10, 9, 0, 0, 0, time.UTC).Unix(), Payouts: 2, Amount: 30, UniqueRequesters: 2},
+		{Start: time.Date(2022, 1, 10, 10, 0, 0, 0, time.UTC).Unix()},
+		{Start: time.Date(2022, 1, 10, 11, 0, 0, 0, time.UTC).Unix()},
+		{Start: time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC).Unix(), Payouts: 2, Amount: 3, UniqueRequesters: 1},
+	}, stats.Buckets)
+
+	stats, err = history.stats(now, 48*time.Hour, 24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, stats.Buckets, 3)
+	require.Equal(t, 5, stats.Payouts)
+	require.Equal(t, 1, stats.Buckets[1].Payouts)
+	require.Equal(t, 4, stats.Buckets[2].Payouts)
+}
+
+func TestPayoutHistoryPruning(t *testing.T) {
+
+	history := newPayoutHistory(mapdb.NewMapDB(), 24*time.Hour)
+
+	address := utils.RandAddress(iotago.AddressEd25519)
+	now := time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, history.addPayouts(now.Add(-48*time.Hour), utils.RandMessageID(), []*queueItem{{Address: address, Amount: 1}}))
+
+	// the payouts older than the retention are deleted when the next payouts are added
+	require.NoError(t, history.addPayouts(now, utils.RandMessageID(), []*queueItem{{Address: address, Amount: 1}}))
+
+	stats, err := history.stats(now, 72*time.Hour, 24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.Payouts)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/gohornet/hornet/pkg/whiteflag"
 	"github.com/iotaledger/hive.go/daemon"
 	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/iota.go/v3/pow"
 )
@@ -60,6 +61,7 @@ type FaucetTestEnv struct {
 	Faucet *faucet.Faucet
 
 	faucetCtxCancel context.CancelFunc
+	faucetLoopDone  chan struct{}
 }
 
 func NewFaucetTestEnv(t *testing.T,
@@ -275,12 +277,15 @@ func NewFaucetTestEnv(t *testing.T,
 		faucet.WithTagMessage(faucetTagMessage),
 		faucet.WithBatchTimeout(faucetBatchTimeout),
 		faucet.WithPowWorkerCount(faucetPowWorkerCount),
+		faucet.WithPayoutHistoryStore(mapdb.NewMapDB()),
 	)
 
 	faucetCtx, faucetCtxCancel := context.WithCancel(context.Background())
 	wg := sync.WaitGroup{}
 	wg.Add(1)
+	faucetLoopDone := make(chan struct{})
 	go func() {
+		defer close(faucetLoopDone)
 		if err := f.RunFaucetLoop(faucetCtx, func() {
 			wg.Done()
 		}); err != nil && common.IsCriticalError(err) != nil {
@@ -315,6 +320,7 @@ func NewFaucetTestEnv(t *testing.T,
 		Wallet3:         seed3Wallet,
 		Faucet:          f,
 		faucetCtxCancel: faucetCtxCancel,
+		faucetLoopDone:  faucetLoopDone,
 	}
 }
 
@@ -325,6 +331,8 @@ func (env *FaucetTestEnv) ConfirmedMilestoneIndex() milestone.Index {
 func (env *FaucetTestEnv) Cleanup() {
 	if env.faucetCtxCancel != nil {
 		env.faucetCtxCancel()
+		// wait until the faucet loop stopped, before the databases are closed
+		<-env.faucetLoopDone
 	}
 	require.NoError(env.t, env.Indexer.CloseDatabase())
 	env.TestEnv.CleanupTestEnvironment(true)
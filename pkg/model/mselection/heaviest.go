@@ -17,23 +17,48 @@ import (
 var (
 	// ErrNoTipsAvailable is returned when no tips are available in the node.
 	ErrNoTipsAvailable = errors.New("no tips available")
+	// ErrInvalidParams is returned when the parameters of the heaviest branch selection are invalid.
+	ErrInvalidParams = errors.New("invalid heaviest branch selection parameters")
 )
 
-// HeaviestSelector implements the heaviest branch selection strategy.
-type HeaviestSelector struct {
-	sync.Mutex
-
+// Params are the parameters of the heaviest branch selection.
+type Params struct {
 	// the minimum threshold of unreferenced messages in the heaviest branch for milestone tipselection
 	// if the value falls below that threshold, no more heaviest branch tips are picked
-	minHeaviestBranchUnreferencedMessagesThreshold int
+	MinHeaviestBranchUnreferencedMessagesThreshold int
 	// the maximum amount of checkpoint messages with heaviest branch tips that are picked
 	// if the heaviest branch is not below "UnreferencedMessagesThreshold" before
-	maxHeaviestBranchTipsPerCheckpoint int
+	MaxHeaviestBranchTipsPerCheckpoint int
 	// the amount of checkpoint messages with random tips that are picked if a checkpoint is issued and at least
 	// one heaviest branch tip was found, otherwise no random tips will be picked
-	randomTipsPerCheckpoint int
+	RandomTipsPerCheckpoint int
 	// the maximum duration to select the heaviest branch tips
-	heaviestBranchSelectionTimeout time.Duration
+	HeaviestBranchSelectionTimeout time.Duration
+}
+
+// Validate checks whether the parameters can be used for the heaviest branch selection.
+func (p *Params) Validate() error {
+	if p.MinHeaviestBranchUnreferencedMessagesThreshold < 0 {
+		return errors.WithMessage(ErrInvalidParams, "minHeaviestBranchUnreferencedMessagesThreshold must not be negative")
+	}
+	if p.MaxHeaviestBranchTipsPerCheckpoint < 1 {
+		return errors.WithMessage(ErrInvalidParams, "maxHeaviestBranchTipsPerCheckpoint must be at least 1")
+	}
+	if p.RandomTipsPerCheckpoint < 0 {
+		return errors.WithMessage(ErrInvalidParams, "randomTipsPerCheckpoint must not be negative")
+	}
+	if p.HeaviestBranchSelectionTimeout <= 0 {
+		return errors.WithMessage(ErrInvalidParams, "heaviestBranchSelectionTimeout must be positive")
+	}
+	return nil
+}
+
+// HeaviestSelector implements the heaviest branch selection strategy.
+type HeaviestSelector struct {
+	sync.Mutex
+
+	// the parameters of the heaviest branch selection, they can be changed at runtime
+	params Params
 	// map of all tracked messages
 	trackedMessages map[string]*trackedMessage
 	// list of available tips
@@ -97,15 +122,39 @@ func (il *trackedMessagesList) removeTip(tip *trackedMessage) {
 // New creates a new HeaviestSelector instance.
 func New(minHeaviestBranchUnreferencedMessagesThreshold int, maxHeaviestBranchTipsPerCheckpoint int, randomTipsPerCheckpoint int, heaviestBranchSelectionTimeout time.Duration) *HeaviestSelector {
 	s := &HeaviestSelector{
-		minHeaviestBranchUnreferencedMessagesThreshold: minHeaviestBranchUnreferencedMessagesThreshold,
-		maxHeaviestBranchTipsPerCheckpoint:             maxHeaviestBranchTipsPerCheckpoint,
-		randomTipsPerCheckpoint:                        randomTipsPerCheckpoint,
-		heaviestBranchSelectionTimeout:                 heaviestBranchSelectionTimeout,
+		params: Params{
+			MinHeaviestBranchUnreferencedMessagesThreshold: minHeaviestBranchUnreferencedMessagesThreshold,
+			MaxHeaviestBranchTipsPerCheckpoint:             maxHeaviestBranchTipsPerCheckpoint,
+			RandomTipsPerCheckpoint:                        randomTipsPerCheckpoint,
+			HeaviestBranchSelectionTimeout:                 heaviestBranchSelectionTimeout,
+		},
 	}
 	s.Reset()
 	return s
 }
 
+// Params returns the current parameters of the heaviest branch selection.
+func (s *HeaviestSelector) Params() Params {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.params
+}
+
+// SetParams replaces the parameters of the heaviest branch selection.
+// The new parameters are used starting with the next call to SelectTips.
+func (s *HeaviestSelector) SetParams(params Params) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.params = params
+	return nil
+}
+
 // Reset resets the tracked messages map and tips list of s.
 func (s *HeaviestSelector) Reset() {
 	s.Lock()
@@ -180,6 +229,9 @@ func (s *HeaviestSelector) SelectTips(minRequiredTips int) (hornet.MessageIDs, e
 	// caution: the tips are not copied, do not mutate!
 	tipsList := s.tipsToList()
 
+	// the parameters could be changed while the tips are selected
+	params := s.Params()
+
 	// tips could be empty after a reset
 	if tipsList.Len() == 0 {
 		return nil, ErrNoTipsAvailable
@@ -187,13 +239,13 @@ func (s *HeaviestSelector) SelectTips(minRequiredTips int) (hornet.MessageIDs, e
 
 	var tips hornet.MessageIDs
 
-	// run the tip selection for at most 0.1s to keep the view on the tangle recent; this should be plenty
-	ctx, cancel := context.WithTimeout(context.Background(), s.heaviestBranchSelectionTimeout)
+	// run the tip selection only until the selection timeout to keep the view on the tangle recent
+	ctx, cancel := context.WithTimeout(context.Background(), params.HeaviestBranchSelectionTimeout)
 	defer cancel()
 
 	deadlineExceeded := false
 
-	for i := 0; i < s.maxHeaviestBranchTipsPerCheckpoint; i++ {
+	for i := 0; i < params.MaxHeaviestBranchTipsPerCheckpoint; i++ {
 		// when the context has been canceled, stop collecting heaviest branch tips
 		select {
 		case <-ctx.Done():
@@ -206,7 +258,7 @@ func (s *HeaviestSelector) SelectTips(minRequiredTips int) (hornet.MessageIDs, e
 			break
 		}
 
-		if (len(tips) > minRequiredTips) && ((count < uint(s.minHeaviestBranchUnreferencedMessagesThreshold)) || deadlineExceeded) {
+		if (len(tips) > minRequiredTips) && ((count < uint(params.MinHeaviestBranchUnreferencedMessagesThreshold)) || deadlineExceeded) {
 			// minimum amount of tips reached and the heaviest tips do not confirm enough messages or the deadline was exceeded
 			// => no need to collect more
 			break
@@ -221,7 +273,7 @@ func (s *HeaviestSelector) SelectTips(minRequiredTips int) (hornet.MessageIDs, e
 	}
 
 	// also pick random tips if at least one heaviest branch tip was found
-	for i := 0; i < s.randomTipsPerCheckpoint; i++ {
+	for i := 0; i < params.RandomTipsPerCheckpoint; i++ {
 		item, err := tipsList.randomTip()
 		if err != nil {
 			break
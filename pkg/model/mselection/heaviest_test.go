@@ -146,6 +146,44 @@ func TestHeaviestSelector_SelectTipsChains(t *testing.T) {
 	assert.Len(t, hps.trackedMessages, 0)
 }
 
+func TestHeaviestSelector_SetParams(t *testing.T) {
+	te, hps := initTest(t)
+	defer te.CleanupTestEnvironment(true)
+
+	params := hps.Params()
+	require.Equal(t, CfgCoordinatorTipselectMaxHeaviestBranchTipsPerCheckpoint, params.MaxHeaviestBranchTipsPerCheckpoint)
+
+	// invalid parameters are rejected and the current ones are kept
+	invalid := params
+	invalid.MaxHeaviestBranchTipsPerCheckpoint = 0
+	require.ErrorIs(t, hps.SetParams(invalid), ErrInvalidParams)
+
+	invalid = params
+	invalid.HeaviestBranchSelectionTimeout = 0
+	require.ErrorIs(t, hps.SetParams(invalid), ErrInvalidParams)
+	require.Equal(t, params, hps.Params())
+
+	numChains := 2
+	for i := 0; i < numChains; i++ {
+		lastMsgID := hornet.NullMessageID()
+		for j := 1; j <= 10; j++ {
+			msgMeta := te.NewTestMessage(i*10+j, hornet.MessageIDs{lastMsgID})
+			hps.OnNewSolidMessage(msgMeta)
+			lastMsgID = msgMeta.MessageID()
+		}
+	}
+
+	// the new parameters are used by the next selection
+	params.MaxHeaviestBranchTipsPerCheckpoint = 1
+	params.RandomTipsPerCheckpoint = 0
+	require.NoError(t, hps.SetParams(params))
+	require.Equal(t, params, hps.Params())
+
+	tips, err := hps.SelectTips(0)
+	require.NoError(t, err)
+	require.Len(t, tips, 1)
+}
+
 func TestHeaviestSelector_SelectTipsCheckThresholds(t *testing.T) {
 	te, hps := initTest(t)
 	defer te.CleanupTestEnvironment(true)
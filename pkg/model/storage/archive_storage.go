@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/utils"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/objectstorage"
+)
+
+var (
+	archiveIndexKey = []byte("archiveIndex")
+)
+
+// ConfigureArchiveStore enables the archive tier of the storage.
+// Confirmed messages can be moved to the archive store, which is transparently
+// used as a fallback if a message or metadata can't be found in the tangle store.
+func (s *Storage) ConfigureArchiveStore(archiveStore kvstore.KVStore) {
+
+	s.archiveStore = archiveStore
+	s.archiveInfoStore = archiveStore.WithRealm([]byte{common.StorePrefixArchiveInfo})
+	s.healthTrackers = append(s.healthTrackers, NewStoreHealthTracker(archiveStore))
+
+	// archived messages are not cached, they are only accessed occasionally.
+	s.archiveMessagesStorage = objectstorage.New(
+		archiveStore.WithRealm([]byte{common.StorePrefixMessages}),
+		messageFactory,
+		objectstorage.CacheTime(0),
+		objectstorage.PersistenceEnabled(true),
+		objectstorage.StoreOnCreation(true),
+	)
+
+	s.archiveMetadataStorage = objectstorage.New(
+		archiveStore.WithRealm([]byte{common.StorePrefixMessageMetadata}),
+		MetadataFactory,
+		objectstorage.CacheTime(0),
+		objectstorage.PersistenceEnabled(true),
+		objectstorage.StoreOnCreation(true),
+	)
+}
+
+// ArchiveEnabled returns whether the archive tier of the storage is configured.
+func (s *Storage) ArchiveEnabled() bool {
+	return s.archiveStore != nil
+}
+
+// ArchiveIndex returns the index of the latest milestone whose cone was moved to the archive store.
+func (s *Storage) ArchiveIndex() (milestone.Index, error) {
+
+	if !s.ArchiveEnabled() {
+		return 0, nil
+	}
+
+	value, err := s.archiveInfoStore.Get(archiveIndexKey)
+	if err != nil {
+		if !errors.Is(err, kvstore.ErrKeyNotFound) {
+			return 0, errors.Wrap(NewDatabaseError(err), "failed to retrieve archive index")
+		}
+		return 0, nil
+	}
+
+	return milestoneIndexFromDatabaseKey(value), nil
+}
+
+// SetArchiveIndex stores the index of the latest milestone whose cone was moved to the archive store.
+func (s *Storage) SetArchiveIndex(msIndex milestone.Index) error {
+
+	if !s.ArchiveEnabled() {
+		return nil
+	}
+
+	if err := s.archiveInfoStore.Set(archiveIndexKey, databaseKeyForMilestoneIndex(msIndex)); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to store archive index")
+	}
+
+	return nil
+}
+
+// ArchiveMilestones moves the cones of all milestones after the archive index up to the target index to the archive store.
+// The cone of a single milestone is moved by the given function. The archive index is only advanced
+// if the cone was archived successfully, so a failed milestone is archived again with the next call.
+func (s *Storage) ArchiveMilestones(ctx context.Context, targetIndex milestone.Index, archiveMilestoneCone func(msIndex milestone.Index) error) error {
+
+	if !s.ArchiveEnabled() {
+		return nil
+	}
+
+	archiveIndex, err := s.ArchiveIndex()
+	if err != nil {
+		return err
+	}
+
+	snapshotInfo := s.SnapshotInfo()
+	if snapshotInfo == nil {
+		return errors.New("no snapshot info found")
+	}
+
+	// the cones of pruned milestones are not available anymore
+	if archiveIndex < snapshotInfo.PruningIndex {
+		archiveIndex = snapshotInfo.PruningIndex
+	}
+
+	for msIndex := archiveIndex + 1; msIndex <= targetIndex; msIndex++ {
+		if err := utils.ReturnErrIfCtxDone(ctx, common.ErrOperationAborted); err != nil {
+			return err
+		}
+
+		if err := archiveMilestoneCone(msIndex); err != nil {
+			if errors.Is(err, common.ErrOperationAborted) {
+				return err
+			}
+			return errors.WithMessagef(err, "archiving milestone (%d) failed", msIndex)
+		}
+
+		if err := s.SetArchiveIndex(msIndex); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ArchiveMessage moves the message and its metadata from the tangle store to the archive store.
+// It returns false if the message was not found in the tangle store.
+func (s *Storage) ArchiveMessage(messageID hornet.MessageID) (bool, error) {
+
+	if !s.ArchiveEnabled() {
+		return false, nil
+	}
+
+	cachedMsg := s.messagesStorage.Load(messageID) // msg +1
+	if !cachedMsg.Exists() {
+		cachedMsg.Release(true) // msg -1
+		return false, nil
+	}
+	msgData := cachedMsg.Get().(*Message).Data()
+	cachedMsg.Release(true) // msg -1
+
+	cachedMeta := s.metadataStorage.Load(messageID) // meta +1
+	if !cachedMeta.Exists() {
+		cachedMeta.Release(true) // meta -1
+		return false, nil
+	}
+	metaData := cachedMeta.Get().(*MessageMetadata).ObjectStorageValue()
+	cachedMeta.Release(true) // meta -1
+
+	archivedMsg, err := messageFactory(messageID, msgData)
+	if err != nil {
+		return false, err
+	}
+
+	archivedMeta, err := MetadataFactory(messageID, metaData)
+	if err != nil {
+		return false, err
+	}
+
+	// the message is stored in the archive before it is deleted from the tangle store,
+	// this way read-through always finds the message in one of the stores.
+	s.archiveMessagesStorage.Store(archivedMsg).Release(true)  // msg +-0
+	s.archiveMetadataStorage.Store(archivedMeta).Release(true) // meta +-0
+
+	// metadata has to be deleted before the msg, otherwise we could run into a data race in the object storage
+	s.metadataStorage.Delete(messageID)
+	s.messagesStorage.Delete(messageID)
+
+	return true, nil
+}
+
+// ShutdownArchiveStorage shuts down the archive storage.
+func (s *Storage) ShutdownArchiveStorage() {
+	if !s.ArchiveEnabled() {
+		return
+	}
+
+	s.archiveMessagesStorage.Shutdown()
+	s.archiveMetadataStorage.Shutdown()
+}
+
+// FlushArchiveStorage flushes the archive storage.
+func (s *Storage) FlushArchiveStorage() {
+	if !s.ArchiveEnabled() {
+		return
+	}
+
+	s.archiveMessagesStorage.Flush()
+	s.archiveMetadataStorage.Flush()
+}
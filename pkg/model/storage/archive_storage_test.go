@@ -0,0 +1,140 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/testsuite"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	BelowMaxDepth = 5
+	MinPoWScore   = 10.0
+)
+
+func TestArchiveMessage(t *testing.T) {
+
+	te := testsuite.SetupTestEnvironment(t, &iotago.Ed25519Address{}, 0, BelowMaxDepth, MinPoWScore, false)
+	defer te.CleanupTestEnvironment(true)
+
+	dbStorage := te.Storage()
+	require.False(t, dbStorage.ArchiveEnabled())
+
+	dbStorage.ConfigureArchiveStore(mapdb.NewMapDB())
+	require.True(t, dbStorage.ArchiveEnabled())
+
+	msg := te.NewMessageBuilder("archive").Parents(hornet.MessageIDs{hornet.NullMessageID()}).BuildTaggedData().Store()
+	messageID := msg.StoredMessageID()
+
+	archived, err := dbStorage.ArchiveMessage(messageID)
+	require.NoError(t, err)
+	require.True(t, archived)
+
+	// archiving the message a second time is a no-op, since it is not part of the tangle store anymore
+	archived, err = dbStorage.ArchiveMessage(messageID)
+	require.NoError(t, err)
+	require.False(t, archived)
+
+	// the existence checks and the lookups without the cache include the archive,
+	// they only access the persistence layer, so the archive has to be flushed first.
+	dbStorage.FlushArchiveStorage()
+	require.True(t, dbStorage.MessageExistsInStore(messageID))
+	require.True(t, dbStorage.MessageMetadataExistsInStore(messageID))
+	storedMeta := dbStorage.StoredMetadataOrNil(messageID)
+	require.NotNil(t, storedMeta)
+	require.Equal(t, messageID, storedMeta.MessageID())
+
+	// an archived message is not stored in the tangle store again if it is received once more
+	cachedMsg, newlyAdded := dbStorage.StoreMessageIfAbsent(msg.StoredMessage()) // msg +1
+	require.False(t, newlyAdded)
+	require.Equal(t, messageID, cachedMsg.Message().MessageID())
+	cachedMsg.Release(true) // msg -1
+
+	archived, err = dbStorage.ArchiveMessage(messageID)
+	require.NoError(t, err)
+	require.False(t, archived)
+
+	// but it can still be read through the archive
+	require.True(t, dbStorage.ContainsMessage(messageID))
+
+	cachedMsg = dbStorage.CachedMessageOrNil(messageID) // msg +1
+	require.NotNil(t, cachedMsg)
+	require.Equal(t, msg.StoredMessage().Data(), cachedMsg.Message().Data())
+	require.Equal(t, messageID, cachedMsg.Metadata().MessageID())
+	cachedMsg.Release(true) // msg -1
+
+	cachedMsgMeta := dbStorage.CachedMessageMetadataOrNil(messageID) // meta +1
+	require.NotNil(t, cachedMsgMeta)
+	cachedMsgMeta.Release(true) // meta -1
+
+	// deleting the message also removes it from the archive
+	dbStorage.DeleteMessage(messageID)
+	require.Nil(t, dbStorage.CachedMessageOrNil(messageID))
+	require.False(t, dbStorage.ContainsMessage(messageID))
+}
+
+func TestArchiveIndex(t *testing.T) {
+
+	te := testsuite.SetupTestEnvironment(t, &iotago.Ed25519Address{}, 0, BelowMaxDepth, MinPoWScore, false)
+	defer te.CleanupTestEnvironment(true)
+
+	dbStorage := te.Storage()
+	dbStorage.ConfigureArchiveStore(mapdb.NewMapDB())
+
+	archiveIndex, err := dbStorage.ArchiveIndex()
+	require.NoError(t, err)
+	require.Equal(t, milestone.Index(0), archiveIndex)
+
+	require.NoError(t, dbStorage.SetArchiveIndex(42))
+
+	archiveIndex, err = dbStorage.ArchiveIndex()
+	require.NoError(t, err)
+	require.Equal(t, milestone.Index(42), archiveIndex)
+}
+
+func TestArchiveMilestones(t *testing.T) {
+
+	te := testsuite.SetupTestEnvironment(t, &iotago.Ed25519Address{}, 0, BelowMaxDepth, MinPoWScore, false)
+	defer te.CleanupTestEnvironment(true)
+
+	dbStorage := te.Storage()
+	dbStorage.ConfigureArchiveStore(mapdb.NewMapDB())
+
+	errArchive := errors.New("archiving failed")
+
+	var archivedIndexes []milestone.Index
+	archiveMilestoneCone := func(msIndex milestone.Index) error {
+		archivedIndexes = append(archivedIndexes, msIndex)
+		return nil
+	}
+
+	// the archive index is not advanced past a milestone which failed to be archived
+	err := dbStorage.ArchiveMilestones(context.Background(), 5, func(msIndex milestone.Index) error {
+		if msIndex == 3 {
+			return errArchive
+		}
+		return archiveMilestoneCone(msIndex)
+	})
+	require.ErrorIs(t, err, errArchive)
+	require.Equal(t, []milestone.Index{1, 2}, archivedIndexes)
+
+	archiveIndex, err := dbStorage.ArchiveIndex()
+	require.NoError(t, err)
+	require.Equal(t, milestone.Index(2), archiveIndex)
+
+	// the failed milestone is archived again with the next run
+	require.NoError(t, dbStorage.ArchiveMilestones(context.Background(), 5, archiveMilestoneCone))
+	require.Equal(t, []milestone.Index{1, 2, 3, 4, 5}, archivedIndexes)
+
+	archiveIndex, err = dbStorage.ArchiveIndex()
+	require.NoError(t, err)
+	require.Equal(t, milestone.Index(5), archiveIndex)
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/iotaledger/hive.go/objectstorage"
+)
+
+// deleteKeysBatched deletes the given keys in the cache/persistence layer of the object storage.
+// Objects that are currently cached are marked as deleted and removed by the cache as usual.
+// All other keys are deleted directly in the persistence layer with batched mutations
+// of at most batchSize keys, which is a lot faster than deleting them one by one.
+// A batchSize <= 0 deletes all keys in a single batch.
+func deleteKeysBatched(objectStorage *objectstorage.ObjectStorage, keys [][]byte, batchSize int) {
+
+	keysToDeleteFromStore := make([][]byte, 0, len(keys))
+
+	for _, key := range keys {
+		cachedObject := objectStorage.Get(key) // cachedObject +1
+		if cachedObject.Exists() {
+			cachedObject.Get().Delete()
+			cachedObject.Release(true) // cachedObject -1
+			continue
+		}
+		cachedObject.Release(true) // cachedObject -1
+
+		keysToDeleteFromStore = append(keysToDeleteFromStore, key)
+	}
+
+	if batchSize <= 0 {
+		batchSize = len(keysToDeleteFromStore)
+	}
+
+	for start := 0; start < len(keysToDeleteFromStore); start += batchSize {
+		end := start + batchSize
+		if end > len(keysToDeleteFromStore) {
+			end = len(keysToDeleteFromStore)
+		}
+
+		objectStorage.DeleteEntriesFromStore(keysToDeleteFromStore[start:end])
+	}
+}
+
+// DeleteMessagesBatched deletes the messages, metadata and annotations in the cache/persistence layer (including the archive).
+// The entries are deleted in batches of at most batchSize keys.
+func (s *Storage) DeleteMessagesBatched(messageIDs hornet.MessageIDs, batchSize int) {
+
+	keys := make([][]byte, len(messageIDs))
+	for i, messageID := range messageIDs {
+		keys[i] = messageID
+	}
+
+	s.deleteMessageAnnotationsBatched(messageIDs, batchSize)
+
+	// metadata has to be deleted before the msg, otherwise we could run into a data race in the object storage
+	deleteKeysBatched(s.metadataStorage, keys, batchSize)
+	deleteKeysBatched(s.messagesStorage, keys, batchSize)
+
+	if s.ArchiveEnabled() {
+		deleteKeysBatched(s.archiveMetadataStorage, keys, batchSize)
+		deleteKeysBatched(s.archiveMessagesStorage, keys, batchSize)
+	}
+}
+
+// DeleteChildrenBatched deletes the given children in the cache/persistence layer.
+// The entries are deleted in batches of at most batchSize keys.
+// child +-0
+func (s *Storage) DeleteChildrenBatched(children []*Child, batchSize int) {
+
+	keys := make([][]byte, len(children))
+	for i, child := range children {
+		keys[i] = child.ObjectStorageKey()
+	}
+
+	deleteKeysBatched(s.childrenStorage, keys, batchSize)
+}
+
+// DeleteUnreferencedMessagesBatched deletes unreferenced message entries in the cache/persistence layer.
+// The entries are deleted in batches of at most batchSize keys.
+func (s *Storage) DeleteUnreferencedMessagesBatched(msIndex milestone.Index, batchSize int, iteratorOptions ...IteratorOption) int {
+
+	keysToDelete := s.unreferencedMessageKeys(msIndex, iteratorOptions...)
+	deleteKeysBatched(s.unreferencedMessagesStorage, keysToDelete, batchSize)
+
+	return len(keysToDelete)
+}
@@ -0,0 +1,81 @@
+package storage_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/testsuite"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestDeleteMessagesBatched(t *testing.T) {
+
+	te := testsuite.SetupTestEnvironment(t, &iotago.Ed25519Address{}, 0, BelowMaxDepth, MinPoWScore, false)
+	defer te.CleanupTestEnvironment(true)
+
+	dbStorage := te.Storage()
+
+	parentMessageID := te.NewMessageBuilder("parent").Parents(hornet.MessageIDs{hornet.NullMessageID()}).BuildTaggedData().Store().StoredMessageID()
+
+	var messageIDs hornet.MessageIDs
+	var children []*storage.Child
+	for i := 0; i < 5; i++ {
+		msg := te.NewMessageBuilder(fmt.Sprintf("batched %d", i)).Parents(hornet.MessageIDs{parentMessageID}).BuildTaggedData().Store()
+		messageIDs = append(messageIDs, msg.StoredMessageID())
+		children = append(children, storage.NewChild(parentMessageID, msg.StoredMessageID()))
+		require.NoError(t, dbStorage.SetMessageAnnotation(msg.StoredMessageID(), "batched", "first", []byte{byte(i)}))
+		require.NoError(t, dbStorage.SetMessageAnnotation(msg.StoredMessageID(), "batched", "second", []byte{byte(i)}))
+	}
+
+	// the annotations of other messages are kept
+	require.NoError(t, dbStorage.SetMessageAnnotation(parentMessageID, "batched", "first", []byte{0}))
+
+	require.Len(t, dbStorage.ChildrenMessageIDs(parentMessageID), len(messageIDs))
+
+	// delete with a batch size that doesn't divide the amount of keys
+	dbStorage.DeleteChildrenBatched(children, 2)
+	dbStorage.DeleteMessagesBatched(messageIDs, 2)
+	dbStorage.FlushChildrenStorage()
+
+	require.Empty(t, dbStorage.ChildrenMessageIDs(parentMessageID))
+
+	// the messages are still cached by the test environment, so they are only marked as deleted
+	for _, messageID := range messageIDs {
+		require.False(t, dbStorage.ContainsMessage(messageID))
+		require.Nil(t, dbStorage.CachedMessageOrNil(messageID))
+		require.Nil(t, dbStorage.CachedMessageMetadataOrNil(messageID))
+
+		annotations, err := dbStorage.MessageAnnotations(messageID, "batched")
+		require.NoError(t, err)
+		require.Empty(t, annotations)
+	}
+
+	annotations, err := dbStorage.MessageAnnotations(parentMessageID, "batched")
+	require.NoError(t, err)
+	require.Len(t, annotations, 1)
+}
+
+func TestDeleteUnreferencedMessagesBatched(t *testing.T) {
+
+	te := testsuite.SetupTestEnvironment(t, &iotago.Ed25519Address{}, 0, BelowMaxDepth, MinPoWScore, false)
+	defer te.CleanupTestEnvironment(true)
+
+	dbStorage := te.Storage()
+
+	for i := 0; i < 5; i++ {
+		msg := te.NewMessageBuilder(fmt.Sprintf("unreferenced %d", i)).Parents(hornet.MessageIDs{hornet.NullMessageID()}).BuildTaggedData().Store()
+		dbStorage.StoreUnreferencedMessage(10, msg.StoredMessageID()).Release(true)
+	}
+
+	require.Len(t, dbStorage.UnreferencedMessageIDs(10), 5)
+
+	// a batch size of zero deletes all keys at once
+	require.Equal(t, 5, dbStorage.DeleteUnreferencedMessagesBatched(10, 0))
+	dbStorage.FlushUnreferencedMessagesStorage()
+
+	require.Empty(t, dbStorage.UnreferencedMessageIDs(10))
+}
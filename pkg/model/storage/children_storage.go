@@ -65,12 +65,17 @@ func (s *Storage) configureChildrenStorage(store kvstore.KVStore, opts *profile.
 		return err
 	}
 
+	partitionKey, err := childrenPartitionKeyLayout.partitionKey(opts)
+	if err != nil {
+		return err
+	}
+
 	s.childrenStorage = objectstorage.New(
 		store.WithRealm([]byte{common.StorePrefixChildren}),
 		childrenFactory,
 		objectstorage.CacheTime(cacheTime),
 		objectstorage.PersistenceEnabled(true),
-		objectstorage.PartitionKey(iotago.MessageIDLength, iotago.MessageIDLength),
+		objectstorage.PartitionKey(partitionKey...),
 		objectstorage.KeysOnly(true),
 		objectstorage.StoreOnCreation(true),
 		objectstorage.ReleaseExecutorWorkerCount(opts.ReleaseExecutorWorkerCount),
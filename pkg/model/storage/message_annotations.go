@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/iotaledger/hive.go/kvstore"
+)
+
+const (
+	// MaxMessageAnnotationNamespaceLength is the maximum length of the namespace of a message annotation.
+	MaxMessageAnnotationNamespaceLength = 64
+	// MaxMessageAnnotationKeyLength is the maximum length of the key of a message annotation.
+	MaxMessageAnnotationKeyLength = 255
+)
+
+var (
+	// ErrInvalidMessageAnnotation is returned if the namespace or the key of a message annotation is invalid.
+	ErrInvalidMessageAnnotation = errors.New("invalid message annotation")
+	// ErrMessageAnnotationNotFound is returned if a message annotation doesn't exist.
+	ErrMessageAnnotationNotFound = errors.New("message annotation not found")
+)
+
+// MessageAnnotationConsumer consumes the given message annotation during looping through all annotations of a message.
+type MessageAnnotationConsumer func(namespace string, key string, value []byte) bool
+
+func (s *Storage) configureMessageAnnotationsStore(store kvstore.KVStore) {
+	s.messageAnnotationsStore = store.WithRealm([]byte{common.StorePrefixMessageAnnotations})
+}
+
+// messageAnnotationsPrefix returns the database prefix of all annotations of the message in the namespace.
+// The length of the namespace is part of the prefix, so a namespace is never the prefix of another one.
+func messageAnnotationsPrefix(messageID hornet.MessageID, namespace string) ([]byte, error) {
+	if len(namespace) == 0 || len(namespace) > MaxMessageAnnotationNamespaceLength {
+		return nil, errors.WithMessagef(ErrInvalidMessageAnnotation, "namespace must be between 1 and %d bytes long", MaxMessageAnnotationNamespaceLength)
+	}
+
+	prefix := make([]byte, 0, len(messageID)+1+len(namespace))
+	prefix = append(prefix, messageID...)
+	prefix = append(prefix, byte(len(namespace)))
+	prefix = append(prefix, namespace...)
+
+	return prefix, nil
+}
+
+func messageAnnotationKey(messageID hornet.MessageID, namespace string, key string) ([]byte, error) {
+	prefix, err := messageAnnotationsPrefix(messageID, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key) == 0 || len(key) > MaxMessageAnnotationKeyLength {
+		return nil, errors.WithMessagef(ErrInvalidMessageAnnotation, "key must be between 1 and %d bytes long", MaxMessageAnnotationKeyLength)
+	}
+
+	return append(prefix, key...), nil
+}
+
+// SetMessageAnnotation stores the value under the key in the namespace of the plugin that annotates the message.
+// The annotations are deleted together with the message.
+func (s *Storage) SetMessageAnnotation(messageID hornet.MessageID, namespace string, key string, value []byte) error {
+	dbKey, err := messageAnnotationKey(messageID, namespace, key)
+	if err != nil {
+		return err
+	}
+
+	if err := s.messageAnnotationsStore.Set(dbKey, value); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to store message annotation")
+	}
+	return nil
+}
+
+// MessageAnnotation returns the value stored under the key in the namespace of the message.
+func (s *Storage) MessageAnnotation(messageID hornet.MessageID, namespace string, key string) ([]byte, error) {
+	dbKey, err := messageAnnotationKey(messageID, namespace, key)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := s.messageAnnotationsStore.Get(dbKey)
+	if err != nil {
+		if errors.Is(err, kvstore.ErrKeyNotFound) {
+			return nil, ErrMessageAnnotationNotFound
+		}
+		return nil, errors.Wrap(NewDatabaseError(err), "failed to read message annotation")
+	}
+	return value, nil
+}
+
+// DeleteMessageAnnotation removes the key from the namespace of the message.
+func (s *Storage) DeleteMessageAnnotation(messageID hornet.MessageID, namespace string, key string) error {
+	dbKey, err := messageAnnotationKey(messageID, namespace, key)
+	if err != nil {
+		return err
+	}
+
+	if err := s.messageAnnotationsStore.Delete(dbKey); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to delete message annotation")
+	}
+	return nil
+}
+
+// MessageAnnotations returns all annotations of the message in the namespace.
+func (s *Storage) MessageAnnotations(messageID hornet.MessageID, namespace string) (map[string][]byte, error) {
+	prefix, err := messageAnnotationsPrefix(messageID, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := make(map[string][]byte)
+	if err := s.messageAnnotationsStore.Iterate(prefix, func(key kvstore.Key, value kvstore.Value) bool {
+		// the key and the value are only valid during the iteration
+		annotations[string(key[len(prefix):])] = append([]byte{}, value...)
+		return true
+	}); err != nil {
+		return nil, errors.Wrap(NewDatabaseError(err), "failed to iterate message annotations")
+	}
+
+	return annotations, nil
+}
+
+// ForEachMessageAnnotation loops over all annotations of the message in all namespaces.
+func (s *Storage) ForEachMessageAnnotation(messageID hornet.MessageID, consumer MessageAnnotationConsumer) error {
+
+	var innerErr error
+	if err := s.messageAnnotationsStore.Iterate(messageID, func(key kvstore.Key, value kvstore.Value) bool {
+		entry := key[len(messageID):]
+		if len(entry) == 0 || len(entry) < 1+int(entry[0]) {
+			innerErr = errors.Errorf("invalid message annotation key: %x", key)
+			return false
+		}
+
+		namespaceLength := int(entry[0])
+		namespace := string(entry[1 : 1+namespaceLength])
+		annotationKey := string(entry[1+namespaceLength:])
+
+		// the value is only valid during the iteration
+		return consumer(namespace, annotationKey, append([]byte{}, value...))
+	}); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to iterate message annotations")
+	}
+
+	return innerErr
+}
+
+// deleteMessageAnnotations removes all annotations of the message.
+func (s *Storage) deleteMessageAnnotations(messageID hornet.MessageID) {
+	// annotations are optional data, so a failed deletion must not stop the pruning
+	_ = s.messageAnnotationsStore.DeletePrefix(messageID)
+}
+
+// deleteMessageAnnotationsBatched removes all annotations of the messages.
+// The keys of the annotations are collected first and deleted with batched mutations
+// of at most batchSize keys, instead of a prefix deletion per message.
+// A batchSize <= 0 deletes all keys in a single batch.
+func (s *Storage) deleteMessageAnnotationsBatched(messageIDs hornet.MessageIDs, batchSize int) {
+
+	var keys [][]byte
+	for _, messageID := range messageIDs {
+		// annotations are optional data, so a failed deletion must not stop the pruning
+		_ = s.messageAnnotationsStore.IterateKeys(messageID, func(key kvstore.Key) bool {
+			// the key is only valid during the iteration
+			keys = append(keys, append([]byte{}, key...))
+			return true
+		})
+	}
+
+	if batchSize <= 0 {
+		batchSize = len(keys)
+	}
+
+	for start := 0; start < len(keys); start += batchSize {
+		end := start + batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		mutations := s.messageAnnotationsStore.Batched()
+		for _, key := range keys[start:end] {
+			_ = mutations.Delete(key)
+		}
+		_ = mutations.Commit()
+	}
+}
@@ -0,0 +1,86 @@
+package storage_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+)
+
+func TestMessageAnnotations(t *testing.T) {
+
+	dbStorage, err := storage.New(mapdb.NewMapDB(), mapdb.NewMapDB())
+	require.NoError(t, err)
+	defer dbStorage.ShutdownStorages()
+
+	messageID1 := utils.RandMessageID()
+	messageID2 := utils.RandMessageID()
+
+	_, err = dbStorage.MessageAnnotation(messageID1, "participation", "event")
+	require.ErrorIs(t, err, storage.ErrMessageAnnotationNotFound)
+
+	require.NoError(t, dbStorage.SetMessageAnnotation(messageID1, "participation", "event", []byte{1}))
+	require.NoError(t, dbStorage.SetMessageAnnotation(messageID1, "participation", "answer", []byte{2}))
+	require.NoError(t, dbStorage.SetMessageAnnotation(messageID1, "tags", "event", []byte{3}))
+	require.NoError(t, dbStorage.SetMessageAnnotation(messageID2, "participation", "event", []byte{4}))
+
+	value, err := dbStorage.MessageAnnotation(messageID1, "participation", "event")
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, value)
+
+	annotations, err := dbStorage.MessageAnnotations(messageID1, "participation")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"event": {1}, "answer": {2}}, annotations)
+
+	// a namespace that is a prefix of another one doesn't contain its annotations
+	annotations, err = dbStorage.MessageAnnotations(messageID1, "tag")
+	require.NoError(t, err)
+	require.Empty(t, annotations)
+
+	all := make(map[string]map[string][]byte)
+	require.NoError(t, dbStorage.ForEachMessageAnnotation(messageID1, func(namespace string, key string, value []byte) bool {
+		if _, exists := all[namespace]; !exists {
+			all[namespace] = make(map[string][]byte)
+		}
+		all[namespace][key] = value
+		return true
+	}))
+	require.Equal(t, map[string]map[string][]byte{
+		"participation": {"event": {1}, "answer": {2}},
+		"tags":          {"event": {3}},
+	}, all)
+
+	require.NoError(t, dbStorage.DeleteMessageAnnotation(messageID1, "participation", "answer"))
+	_, err = dbStorage.MessageAnnotation(messageID1, "participation", "answer")
+	require.ErrorIs(t, err, storage.ErrMessageAnnotationNotFound)
+
+	// the annotations are pruned together with the message
+	dbStorage.DeleteMessagesBatched(hornet.MessageIDs{messageID1}, 10)
+
+	annotations, err = dbStorage.MessageAnnotations(messageID1, "tags")
+	require.NoError(t, err)
+	require.Empty(t, annotations)
+
+	value, err = dbStorage.MessageAnnotation(messageID2, "participation", "event")
+	require.NoError(t, err)
+	require.Equal(t, []byte{4}, value)
+}
+
+func TestMessageAnnotationsInvalid(t *testing.T) {
+
+	dbStorage, err := storage.New(mapdb.NewMapDB(), mapdb.NewMapDB())
+	require.NoError(t, err)
+	defer dbStorage.ShutdownStorages()
+
+	messageID := utils.RandMessageID()
+
+	require.ErrorIs(t, dbStorage.SetMessageAnnotation(messageID, "", "key", nil), storage.ErrInvalidMessageAnnotation)
+	require.ErrorIs(t, dbStorage.SetMessageAnnotation(messageID, strings.Repeat("a", storage.MaxMessageAnnotationNamespaceLength+1), "key", nil), storage.ErrInvalidMessageAnnotation)
+	require.ErrorIs(t, dbStorage.SetMessageAnnotation(messageID, "namespace", "", nil), storage.ErrInvalidMessageAnnotation)
+	require.ErrorIs(t, dbStorage.SetMessageAnnotation(messageID, "namespace", strings.Repeat("a", storage.MaxMessageAnnotationKeyLength+1), nil), storage.ErrInvalidMessageAnnotation)
+}
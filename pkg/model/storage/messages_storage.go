@@ -212,15 +212,30 @@ func (s *Storage) configureMessageStorage(store kvstore.KVStore, opts *profile.C
 }
 
 // CachedMessageOrNil returns a cached message object.
+// If the archive is enabled and the message is not found in the tangle store, the archive store is used as a fallback.
 // msg +1
 func (s *Storage) CachedMessageOrNil(messageID hornet.MessageID) *CachedMessage {
-	cachedMsg := s.messagesStorage.Load(messageID) // msg +1
+	if cachedMsg := cachedMessageOrNil(s.messagesStorage, s.metadataStorage, messageID); cachedMsg != nil {
+		return cachedMsg
+	}
+
+	if !s.ArchiveEnabled() {
+		return nil
+	}
+
+	return cachedMessageOrNil(s.archiveMessagesStorage, s.archiveMetadataStorage, messageID)
+}
+
+// cachedMessageOrNil returns a cached message object from the given storages.
+// msg +1
+func cachedMessageOrNil(messagesStorage *objectstorage.ObjectStorage, metadataStorage *objectstorage.ObjectStorage, messageID hornet.MessageID) *CachedMessage {
+	cachedMsg := messagesStorage.Load(messageID) // msg +1
 	if !cachedMsg.Exists() {
 		cachedMsg.Release(true) // msg -1
 		return nil
 	}
 
-	cachedMeta := s.metadataStorage.Load(messageID) // meta +1
+	cachedMeta := metadataStorage.Load(messageID) // meta +1
 	if !cachedMeta.Exists() {
 		cachedMsg.Release(true)  // msg -1
 		cachedMeta.Release(true) // meta -1
@@ -234,9 +249,20 @@ func (s *Storage) CachedMessageOrNil(messageID hornet.MessageID) *CachedMessage
 }
 
 // CachedMessageMetadataOrNil returns a cached metadata object.
+// If the archive is enabled and the metadata is not found in the tangle store, the archive store is used as a fallback.
 // metadata +1
 func (s *Storage) CachedMessageMetadataOrNil(messageID hornet.MessageID) *CachedMetadata {
 	cachedMeta := s.metadataStorage.Load(messageID) // meta +1
+	if cachedMeta.Exists() {
+		return &CachedMetadata{CachedObject: cachedMeta}
+	}
+	cachedMeta.Release(true) // metadata -1
+
+	if !s.ArchiveEnabled() {
+		return nil
+	}
+
+	cachedMeta = s.archiveMetadataStorage.Load(messageID) // meta +1
 	if !cachedMeta.Exists() {
 		cachedMeta.Release(true) // metadata -1
 		return nil
@@ -245,8 +271,12 @@ func (s *Storage) CachedMessageMetadataOrNil(messageID hornet.MessageID) *Cached
 }
 
 // StoredMetadataOrNil returns a metadata object without accessing the cache layer.
+// If the archive is enabled and the metadata is not found in the tangle store, the archive store is used as a fallback.
 func (s *Storage) StoredMetadataOrNil(messageID hornet.MessageID) *MessageMetadata {
 	storedMeta := s.metadataStorage.LoadObjectFromStore(messageID)
+	if storedMeta == nil && s.ArchiveEnabled() {
+		storedMeta = s.archiveMetadataStorage.LoadObjectFromStore(messageID)
+	}
 	if storedMeta == nil {
 		return nil
 	}
@@ -255,23 +285,41 @@ func (s *Storage) StoredMetadataOrNil(messageID hornet.MessageID) *MessageMetada
 
 // ContainsMessage returns if the given message exists in the cache/persistence layer.
 func (s *Storage) ContainsMessage(messageID hornet.MessageID, readOptions ...ReadOption) bool {
-	return s.messagesStorage.Contains(messageID, readOptions...)
+	if s.messagesStorage.Contains(messageID, readOptions...) {
+		return true
+	}
+	return s.ArchiveEnabled() && s.archiveMessagesStorage.Contains(messageID, readOptions...)
 }
 
 // MessageExistsInStore returns if the given message exists in the persistence layer.
+// If the archive is enabled, the archive store is checked as well.
 func (s *Storage) MessageExistsInStore(messageID hornet.MessageID) bool {
-	return s.messagesStorage.ObjectExistsInStore(messageID)
+	if s.messagesStorage.ObjectExistsInStore(messageID) {
+		return true
+	}
+	return s.ArchiveEnabled() && s.archiveMessagesStorage.ObjectExistsInStore(messageID)
 }
 
 // MessageMetadataExistsInStore returns if the given message metadata exists in the persistence layer.
+// If the archive is enabled, the archive store is checked as well.
 func (s *Storage) MessageMetadataExistsInStore(messageID hornet.MessageID) bool {
-	return s.metadataStorage.ObjectExistsInStore(messageID)
+	if s.metadataStorage.ObjectExistsInStore(messageID) {
+		return true
+	}
+	return s.ArchiveEnabled() && s.archiveMetadataStorage.ObjectExistsInStore(messageID)
 }
 
 // StoreMessageIfAbsent returns a cached object and stores the message in the persistence layer if it was absent.
+// Messages which were already moved to the archive are not stored in the tangle store again.
 // msg +1
 func (s *Storage) StoreMessageIfAbsent(message *Message) (cachedMsg *CachedMessage, newlyAdded bool) {
 
+	if s.ArchiveEnabled() {
+		if cachedMsg := cachedMessageOrNil(s.archiveMessagesStorage, s.archiveMetadataStorage, message.MessageID()); cachedMsg != nil { // msg +1
+			return cachedMsg, false
+		}
+	}
+
 	// Store msg + metadata atomically in the same callback
 	var cachedMeta objectstorage.CachedObject
 
@@ -315,11 +363,18 @@ func (s *Storage) ForEachMessageMetadataMessageID(consumer MessageIDConsumer, it
 	}, iteratorOptions...)
 }
 
-// DeleteMessage deletes the message and metadata in the cache/persistence layer.
+// DeleteMessage deletes the message, metadata and annotations in the cache/persistence layer (including the archive).
 func (s *Storage) DeleteMessage(messageID hornet.MessageID) {
+	s.deleteMessageAnnotations(messageID)
+
 	// metadata has to be deleted before the msg, otherwise we could run into a data race in the object storage
 	s.metadataStorage.Delete(messageID)
 	s.messagesStorage.Delete(messageID)
+
+	if s.ArchiveEnabled() {
+		s.archiveMetadataStorage.Delete(messageID)
+		s.archiveMessagesStorage.Delete(messageID)
+	}
 }
 
 // DeleteMessageMetadata deletes the metadata in the cache/persistence layer.
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/profile"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrInvalidPartitionKey is returned if the partition key layout of a cache is invalid.
+	ErrInvalidPartitionKey = errors.New("invalid partition key")
+)
+
+var (
+	// DefaultChildrenPartitionKey is the default partition key layout of the children cache (parent message ID + child message ID).
+	DefaultChildrenPartitionKey = []int{iotago.MessageIDLength, iotago.MessageIDLength}
+	// DefaultUnreferencedMessagesPartitionKey is the default partition key layout of the unreferenced messages cache (milestone index + message ID).
+	DefaultUnreferencedMessagesPartitionKey = []int{4, iotago.MessageIDLength}
+)
+
+// partitionKeyLayout describes the keys of a realm and the prefixes the realm is iterated with.
+type partitionKeyLayout struct {
+	// the name of the cache in the profile.
+	name string
+	// the length of the keys of the realm.
+	keyLength int
+	// the lengths of the prefixes the realm is iterated with,
+	// which have to end at the boundary of a partition.
+	prefixLengths []int
+	// the layout which is used if the profile doesn't define one.
+	defaultPartitionKey []int
+}
+
+var (
+	childrenPartitionKeyLayout = &partitionKeyLayout{
+		name:                "children",
+		keyLength:           iotago.MessageIDLength + iotago.MessageIDLength,
+		prefixLengths:       []int{iotago.MessageIDLength},
+		defaultPartitionKey: DefaultChildrenPartitionKey,
+	}
+
+	unreferencedMessagesPartitionKeyLayout = &partitionKeyLayout{
+		name:                "unreferencedMessages",
+		keyLength:           4 + iotago.MessageIDLength,
+		prefixLengths:       []int{4},
+		defaultPartitionKey: DefaultUnreferencedMessagesPartitionKey,
+	}
+)
+
+// partitionKey returns the validated partition key layout of the cache,
+// or the default layout if the profile doesn't define one.
+func (l *partitionKeyLayout) partitionKey(opts *profile.CacheOpts) ([]int, error) {
+	if len(opts.PartitionKey) == 0 {
+		return l.defaultPartitionKey, nil
+	}
+
+	if err := l.validate(opts.PartitionKey); err != nil {
+		return nil, errors.WithMessagef(ErrInvalidPartitionKey, "%s cache: %s", l.name, err)
+	}
+
+	return opts.PartitionKey, nil
+}
+
+// validate checks that the partitions cover the whole key and that
+// every prefix the realm is iterated with ends at the boundary of a partition.
+func (l *partitionKeyLayout) validate(partitionKey []int) error {
+	boundaries := make(map[int]struct{}, len(partitionKey))
+
+	var keyLength int
+	for _, partitionLength := range partitionKey {
+		if partitionLength <= 0 {
+			return errors.Errorf("partition lengths must be positive, got %d", partitionLength)
+		}
+		keyLength += partitionLength
+		boundaries[keyLength] = struct{}{}
+	}
+
+	if keyLength != l.keyLength {
+		return errors.Errorf("partitions have to cover the key length of %d bytes, got %d", l.keyLength, keyLength)
+	}
+
+	for _, prefixLength := range l.prefixLengths {
+		if _, exists := boundaries[prefixLength]; !exists {
+			return errors.Errorf("a partition has to end after %d bytes, since the cache is iterated by that prefix", prefixLength)
+		}
+	}
+
+	return nil
+}
+
+// ValidatePartitionKeys checks the partition key layouts of all caches of the profile.
+// Partition keys are only supported by the children and unreferenced messages caches.
+func ValidatePartitionKeys(caches *profile.Caches) error {
+	if _, err := childrenPartitionKeyLayout.partitionKey(caches.Children); err != nil {
+		return err
+	}
+
+	if _, err := unreferencedMessagesPartitionKeyLayout.partitionKey(caches.UnreferencedMessages); err != nil {
+		return err
+	}
+
+	for name, opts := range map[string]*profile.CacheOpts{
+		"addresses":              caches.Addresses,
+		"milestones":             caches.Milestones,
+		"messages":               caches.Messages,
+		"incomingMessagesFilter": caches.IncomingMessagesFilter,
+	} {
+		if opts != nil && len(opts.PartitionKey) > 0 {
+			return errors.WithMessagef(ErrInvalidPartitionKey, "%s cache: partition keys are not supported", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	"github.com/gohornet/hornet/pkg/profile"
+	"github.com/gohornet/hornet/pkg/testsuite"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+)
+
+// returns a copy of the test profile with the given partition keys.
+func cachesWithPartitionKeys(children []int, unreferencedMessages []int, messages []int) *profile.Caches {
+	caches := *testsuite.TestProfileCaches
+
+	childrenOpts := *caches.Children
+	childrenOpts.PartitionKey = children
+	caches.Children = &childrenOpts
+
+	unreferencedMessagesOpts := *caches.UnreferencedMessages
+	unreferencedMessagesOpts.PartitionKey = unreferencedMessages
+	caches.UnreferencedMessages = &unreferencedMessagesOpts
+
+	messagesOpts := *caches.Messages
+	messagesOpts.PartitionKey = messages
+	caches.Messages = &messagesOpts
+
+	return &caches
+}
+
+func TestValidatePartitionKeys(t *testing.T) {
+
+	// the default layouts are used if no partition keys are defined
+	require.NoError(t, storage.ValidatePartitionKeys(cachesWithPartitionKeys(nil, nil, nil)))
+	require.NoError(t, storage.ValidatePartitionKeys(cachesWithPartitionKeys(storage.DefaultChildrenPartitionKey, storage.DefaultUnreferencedMessagesPartitionKey, nil)))
+	require.NoError(t, storage.ValidatePartitionKeys(cachesWithPartitionKeys([]int{16, 16, 32}, []int{2, 2, 32}, nil)))
+
+	for _, invalid := range []*profile.Caches{
+		// the partitions don't cover the whole key
+		cachesWithPartitionKeys([]int{32}, nil, nil),
+		cachesWithPartitionKeys(nil, []int{4, 16}, nil),
+		cachesWithPartitionKeys(nil, []int{4, 40}, nil),
+		// the prefix of the iterations doesn't end at a partition boundary
+		cachesWithPartitionKeys([]int{16, 48}, nil, nil),
+		cachesWithPartitionKeys(nil, []int{36}, nil),
+		// partitions must not be empty
+		cachesWithPartitionKeys(nil, []int{4, 0, 32}, nil),
+		// the messages cache doesn't support partition keys
+		cachesWithPartitionKeys(nil, nil, []int{32}),
+	} {
+		require.ErrorIs(t, storage.ValidatePartitionKeys(invalid), storage.ErrInvalidPartitionKey)
+	}
+}
+
+func TestStorageCustomPartitionKeys(t *testing.T) {
+
+	_, err := storage.New(mapdb.NewMapDB(), mapdb.NewMapDB(), cachesWithPartitionKeys(nil, []int{36}, nil))
+	require.ErrorIs(t, err, storage.ErrInvalidPartitionKey)
+
+	tangleStore := mapdb.NewMapDB()
+
+	dbStorage, err := storage.New(tangleStore, mapdb.NewMapDB(), cachesWithPartitionKeys([]int{8, 24, 32}, []int{2, 2, 16, 16}, nil))
+	require.NoError(t, err)
+
+	messageIDs := hornet.MessageIDs{utils.RandMessageID(), utils.RandMessageID()}
+	for _, messageID := range messageIDs {
+		dbStorage.StoreUnreferencedMessage(milestone.Index(5), messageID).Release(true)
+	}
+	dbStorage.StoreUnreferencedMessage(milestone.Index(6), utils.RandMessageID()).Release(true)
+
+	require.ElementsMatch(t, messageIDs, dbStorage.UnreferencedMessageIDs(milestone.Index(5)))
+
+	dbStorage.ShutdownStorages()
+
+	// the layout only affects the cache, so the persisted entries can be read with another layout
+	dbStorage, err = storage.New(tangleStore, mapdb.NewMapDB())
+	require.NoError(t, err)
+	defer dbStorage.ShutdownStorages()
+
+	require.ElementsMatch(t, messageIDs, dbStorage.UnreferencedMessageIDs(milestone.Index(5)))
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/iotaledger/hive.go/kvstore"
+)
+
+func (s *Storage) configurePinnedMessagesStore(store kvstore.KVStore) {
+	s.pinnedMessagesStore = store.WithRealm([]byte{common.StorePrefixPinnedMessages})
+}
+
+// PinMessage marks the message as exempt from pruning until it is unpinned again.
+func (s *Storage) PinMessage(messageID hornet.MessageID) error {
+	if err := s.pinnedMessagesStore.Set(messageID, []byte{}); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to pin message")
+	}
+	return nil
+}
+
+// UnpinMessage removes the pruning exemption of the message.
+func (s *Storage) UnpinMessage(messageID hornet.MessageID) error {
+	if err := s.pinnedMessagesStore.Delete(messageID); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to unpin message")
+	}
+	return nil
+}
+
+// IsMessagePinned returns whether the message is exempt from pruning.
+func (s *Storage) IsMessagePinned(messageID hornet.MessageID) bool {
+	pinned, err := s.pinnedMessagesStore.Has(messageID)
+	if err != nil {
+		// better keep the message if the state is unknown
+		return true
+	}
+	return pinned
+}
+
+// PinnedMessageIDs returns the message IDs of all pinned messages.
+func (s *Storage) PinnedMessageIDs() (hornet.MessageIDs, error) {
+
+	var messageIDs hornet.MessageIDs
+	if err := s.pinnedMessagesStore.IterateKeys(kvstore.EmptyPrefix, func(key kvstore.Key) bool {
+		// the key is only valid during the iteration
+		messageIDs = append(messageIDs, hornet.MessageIDFromSlice(append([]byte{}, key...)))
+		return true
+	}); err != nil {
+		return nil, errors.Wrap(NewDatabaseError(err), "failed to iterate pinned messages")
+	}
+
+	return messageIDs, nil
+}
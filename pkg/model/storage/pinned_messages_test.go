@@ -0,0 +1,41 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+)
+
+func TestPinnedMessages(t *testing.T) {
+
+	dbStorage, err := storage.New(mapdb.NewMapDB(), mapdb.NewMapDB())
+	require.NoError(t, err)
+	defer dbStorage.ShutdownStorages()
+
+	messageID1 := utils.RandMessageID()
+	messageID2 := utils.RandMessageID()
+
+	require.False(t, dbStorage.IsMessagePinned(messageID1))
+
+	require.NoError(t, dbStorage.PinMessage(messageID1))
+	require.NoError(t, dbStorage.PinMessage(messageID2))
+	require.True(t, dbStorage.IsMessagePinned(messageID1))
+	require.True(t, dbStorage.IsMessagePinned(messageID2))
+
+	pinnedMessageIDs, err := dbStorage.PinnedMessageIDs()
+	require.NoError(t, err)
+	require.ElementsMatch(t, hornet.MessageIDs{messageID1, messageID2}, pinnedMessageIDs)
+
+	require.NoError(t, dbStorage.UnpinMessage(messageID1))
+	require.False(t, dbStorage.IsMessagePinned(messageID1))
+	require.True(t, dbStorage.IsMessagePinned(messageID2))
+
+	pinnedMessageIDs, err = dbStorage.PinnedMessageIDs()
+	require.NoError(t, err)
+	require.Equal(t, hornet.MessageIDs{messageID2}, pinnedMessageIDs)
+}
@@ -24,11 +24,17 @@ type Storage struct {
 	tangleStore kvstore.KVStore
 	utxoStore   kvstore.KVStore
 
+	// optional archive database for historic messages
+	archiveStore kvstore.KVStore
+
 	// healthTrackers
 	healthTrackers []*StoreHealthTracker
 
 	// kv storages
-	snapshotStore kvstore.KVStore
+	snapshotStore           kvstore.KVStore
+	archiveInfoStore        kvstore.KVStore
+	pinnedMessagesStore     kvstore.KVStore
+	messageAnnotationsStore kvstore.KVStore
 
 	// object storages
 	childrenStorage             *objectstorage.ObjectStorage
@@ -36,6 +42,8 @@ type Storage struct {
 	metadataStorage             *objectstorage.ObjectStorage
 	milestoneStorage            *objectstorage.ObjectStorage
 	unreferencedMessagesStorage *objectstorage.ObjectStorage
+	archiveMessagesStorage      *objectstorage.ObjectStorage
+	archiveMetadataStorage      *objectstorage.ObjectStorage
 
 	// solid entry points
 	solidEntryPoints     *SolidEntryPoints
@@ -48,6 +56,10 @@ type Storage struct {
 	// utxo
 	utxoManager *utxo.Manager
 
+	// write locked while a milestone gets confirmed.
+	// readers holding the read lock see the state either before or after a confirmation.
+	confirmationLock syncutils.RWMutex
+
 	// events
 	Events *packageEvents
 }
@@ -86,6 +98,25 @@ func (s *Storage) UTXOManager() *utxo.Manager {
 	return s.utxoManager
 }
 
+// ReadLockConfirmation blocks until no milestone is being confirmed and prevents new confirmations
+// until ReadUnlockConfirmation is called. It must not be acquired by handlers of confirmation events.
+func (s *Storage) ReadLockConfirmation() {
+	s.confirmationLock.RLock()
+}
+
+func (s *Storage) ReadUnlockConfirmation() {
+	s.confirmationLock.RUnlock()
+}
+
+// WriteLockConfirmation has to be acquired before the ledger lock while a milestone gets confirmed.
+func (s *Storage) WriteLockConfirmation() {
+	s.confirmationLock.Lock()
+}
+
+func (s *Storage) WriteUnlockConfirmation() {
+	s.confirmationLock.Unlock()
+}
+
 // profileCachesDisabled returns a Caches profile with caching disabled.
 func (s *Storage) profileCachesDisabled() *profile.Caches {
 	return &profile.Caches{
@@ -153,6 +184,10 @@ func (s *Storage) configureStorages(tangleStore kvstore.KVStore, cachesProfile .
 		cachesOpts = cachesProfile[0]
 	}
 
+	if err := ValidatePartitionKeys(cachesOpts); err != nil {
+		return err
+	}
+
 	if err := s.configureMessageStorage(tangleStore, cachesOpts.Messages); err != nil {
 		return err
 	}
@@ -170,6 +205,8 @@ func (s *Storage) configureStorages(tangleStore kvstore.KVStore, cachesProfile .
 	}
 
 	s.configureSnapshotStore(tangleStore)
+	s.configurePinnedMessagesStore(tangleStore)
+	s.configureMessageAnnotationsStore(tangleStore)
 
 	return nil
 }
@@ -189,6 +226,14 @@ func (s *Storage) FlushAndCloseStores() error {
 	if err := s.utxoStore.Close(); err != nil {
 		flushAndCloseError = err
 	}
+	if s.archiveStore != nil {
+		if err := s.archiveStore.Flush(); err != nil {
+			flushAndCloseError = err
+		}
+		if err := s.archiveStore.Close(); err != nil {
+			flushAndCloseError = err
+		}
+	}
 	return flushAndCloseError
 }
 
@@ -198,6 +243,7 @@ func (s *Storage) FlushStorages() {
 	s.FlushMessagesStorage()
 	s.FlushChildrenStorage()
 	s.FlushUnreferencedMessagesStorage()
+	s.FlushArchiveStorage()
 }
 
 // ShutdownStorages shuts down all storages.
@@ -207,4 +253,5 @@ func (s *Storage) ShutdownStorages() {
 	s.ShutdownMessagesStorage()
 	s.ShutdownChildrenStorage()
 	s.ShutdownUnreferencedMessagesStorage()
+	s.ShutdownArchiveStorage()
 }
@@ -53,12 +53,17 @@ func (s *Storage) configureUnreferencedMessageStorage(store kvstore.KVStore, opt
 		return err
 	}
 
+	partitionKey, err := unreferencedMessagesPartitionKeyLayout.partitionKey(opts)
+	if err != nil {
+		return err
+	}
+
 	s.unreferencedMessagesStorage = objectstorage.New(
 		store.WithRealm([]byte{common.StorePrefixUnreferencedMessages}),
 		unreferencedMessageFactory,
 		objectstorage.CacheTime(cacheTime),
 		objectstorage.PersistenceEnabled(true),
-		objectstorage.PartitionKey(4, 32),
+		objectstorage.PartitionKey(partitionKey...),
 		objectstorage.KeysOnly(true),
 		objectstorage.StoreOnCreation(true),
 		objectstorage.ReleaseExecutorWorkerCount(opts.ReleaseExecutorWorkerCount),
@@ -105,19 +110,26 @@ func (s *Storage) StoreUnreferencedMessage(msIndex milestone.Index, messageID ho
 	return &CachedUnreferencedMessage{CachedObject: s.unreferencedMessagesStorage.Store(unreferencedTx)}
 }
 
-// DeleteUnreferencedMessages deletes unreferenced message entries in the cache/persistence layer.
-func (s *Storage) DeleteUnreferencedMessages(msIndex milestone.Index, iteratorOptions ...IteratorOption) int {
+// unreferencedMessageKeys returns the keys of all unreferenced message entries for that milestone.
+func (s *Storage) unreferencedMessageKeys(msIndex milestone.Index, iteratorOptions ...IteratorOption) [][]byte {
 
 	msIndexBytes := make([]byte, 4)
 	binary.LittleEndian.PutUint32(msIndexBytes, uint32(msIndex))
 
-	var keysToDelete [][]byte
+	var keys [][]byte
 
 	s.unreferencedMessagesStorage.ForEachKeyOnly(func(key []byte) bool {
-		keysToDelete = append(keysToDelete, key)
+		keys = append(keys, key)
 		return true
 	}, append(iteratorOptions, objectstorage.WithIteratorPrefix(msIndexBytes))...)
 
+	return keys
+}
+
+// DeleteUnreferencedMessages deletes unreferenced message entries in the cache/persistence layer.
+func (s *Storage) DeleteUnreferencedMessages(msIndex milestone.Index, iteratorOptions ...IteratorOption) int {
+
+	keysToDelete := s.unreferencedMessageKeys(msIndex, iteratorOptions...)
 	for _, key := range keysToDelete {
 		s.unreferencedMessagesStorage.Delete(key)
 	}
@@ -0,0 +1,85 @@
+package utxo
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrMilestoneIndexAboveLedgerIndex is returned if the requested milestone index was not applied to the ledger yet.
+	ErrMilestoneIndexAboveLedgerIndex = errors.New("milestone index is above the ledger index")
+)
+
+// outputUnlockableByAddress returns whether the output has an address unlock condition for the given address.
+func outputUnlockableByAddress(output *Output, address iotago.Address) bool {
+	unlockConditionOutput, ok := output.Output().(iotago.UnlockConditionOutput)
+	if !ok {
+		return false
+	}
+
+	conditions, err := unlockConditionOutput.UnlockConditions().Set()
+	if err != nil {
+		return false
+	}
+
+	addressCondition := conditions.Address()
+	return addressCondition != nil && address.Equal(addressCondition.Address)
+}
+
+// AddressBalance returns the sum of the deposits and the amount of the outputs with an address unlock condition
+// for the given address at the given milestone index. The balance of past milestones is calculated by
+// reverting the milestone diffs of the following milestones from the current ledger state, so the diffs
+// of all milestones after the given index must not be pruned yet.
+func (u *Manager) AddressBalance(address iotago.Address, msIndex milestone.Index) (balance uint64, count int, ledgerIndex milestone.Index, err error) {
+
+	u.ReadLockLedger()
+	defer u.ReadUnlockLedger()
+
+	ledgerIndex, err = u.ReadLedgerIndexWithoutLocking()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	if msIndex > ledgerIndex {
+		return 0, 0, ledgerIndex, errors.Wrapf(ErrMilestoneIndexAboveLedgerIndex, "%d > %d", msIndex, ledgerIndex)
+	}
+
+	if err := u.ForEachUnspentOutput(func(output *Output) bool {
+		if outputUnlockableByAddress(output, address) {
+			balance += output.Deposit()
+			count++
+		}
+		return true
+	}, ReadLockLedger(false)); err != nil {
+		return 0, 0, ledgerIndex, err
+	}
+
+	// revert the milestone diffs from the newest to the oldest,
+	// so the balance always matches a valid ledger state and never gets negative.
+	for index := ledgerIndex; index > msIndex; index-- {
+		diff, err := u.MilestoneDiffWithoutLocking(index)
+		if err != nil {
+			return 0, 0, ledgerIndex, fmt.Errorf("failed to load milestone diff %d: %w", index, err)
+		}
+
+		for _, spent := range diff.Spents {
+			if outputUnlockableByAddress(spent.Output(), address) {
+				balance += spent.Deposit()
+				count++
+			}
+		}
+
+		for _, output := range diff.Outputs {
+			if outputUnlockableByAddress(output, address) {
+				balance -= output.Deposit()
+				count--
+			}
+		}
+	}
+
+	return balance, count, ledgerIndex, nil
+}
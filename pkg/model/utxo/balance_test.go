@@ -0,0 +1,77 @@
+package utxo
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestAddressBalanceAtPastMilestones(t *testing.T) {
+
+	utxo := New(mapdb.NewMapDB())
+
+	address := utils.RandAddress(iotago.AddressEd25519)
+	otherAddress := utils.RandAddress(iotago.AddressEd25519)
+
+	msIndex := milestone.Index(100)
+	msTimestamp := rand.Uint64()
+
+	// milestone 100: the address receives two outputs
+	outputs100 := Outputs{
+		RandUTXOOutputOnAddressWithAmount(iotago.OutputExtended, address, 1_000_000),
+		RandUTXOOutputOnAddressWithAmount(iotago.OutputExtended, address, 2_000_000),
+		RandUTXOOutputOnAddressWithAmount(iotago.OutputExtended, otherAddress, 5_000_000),
+	}
+	require.NoError(t, utxo.ApplyConfirmationWithoutLocking(msIndex, outputs100, Spents{}, nil, nil))
+
+	// milestone 101: the address spends one output and receives change
+	outputs101 := Outputs{
+		RandUTXOOutputOnAddressWithAmount(iotago.OutputExtended, address, 500_000),
+		RandUTXOOutputOnAddressWithAmount(iotago.OutputExtended, otherAddress, 500_000),
+	}
+	spents101 := Spents{
+		RandUTXOSpent(outputs100[0], msIndex+1, msTimestamp),
+	}
+	require.NoError(t, utxo.ApplyConfirmationWithoutLocking(msIndex+1, outputs101, spents101, nil, nil))
+
+	// milestone 102: the address spends everything
+	spents102 := Spents{
+		RandUTXOSpent(outputs100[1], msIndex+2, msTimestamp),
+		RandUTXOSpent(outputs101[0], msIndex+2, msTimestamp),
+	}
+	require.NoError(t, utxo.ApplyConfirmationWithoutLocking(msIndex+2, Outputs{}, spents102, nil, nil))
+
+	balance, count, ledgerIndex, err := utxo.AddressBalance(address, msIndex+2)
+	require.NoError(t, err)
+	require.Equal(t, msIndex+2, ledgerIndex)
+	require.Equal(t, uint64(0), balance)
+	require.Equal(t, 0, count)
+
+	balance, count, _, err = utxo.AddressBalance(address, msIndex+1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2_500_000), balance)
+	require.Equal(t, 2, count)
+
+	balance, count, _, err = utxo.AddressBalance(address, msIndex)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3_000_000), balance)
+	require.Equal(t, 2, count)
+
+	balance, count, _, err = utxo.AddressBalance(otherAddress, msIndex)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5_000_000), balance)
+	require.Equal(t, 1, count)
+
+	_, _, _, err = utxo.AddressBalance(address, msIndex+3)
+	require.ErrorIs(t, err, ErrMilestoneIndexAboveLedgerIndex)
+
+	// the diff of milestone 100 is needed to calculate the balance before it
+	_, _, _, err = utxo.AddressBalance(address, msIndex-2)
+	require.Error(t, err)
+}
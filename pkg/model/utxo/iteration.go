@@ -153,6 +153,37 @@ func (u *Manager) ForEachUnspentOutput(consumer OutputConsumer, options ...UTXOI
 	return innerErr
 }
 
+// ForEachUnspentOutputID iterates over the IDs of the unspent outputs without loading the outputs.
+func (u *Manager) ForEachUnspentOutputID(consumer OutputIDConsumer, options ...UTXOIterateOption) error {
+	opt := iterateOptions(options)
+
+	if opt.readLockLedger {
+		u.ReadLockLedger()
+		defer u.ReadUnlockLedger()
+	}
+
+	var innerErr error
+	var i int
+	if err := u.utxoStorage.IterateKeys([]byte{UTXOStoreKeyPrefixOutputUnspent}, func(key kvstore.Key) bool {
+		if (opt.maxResultCount > 0) && (i >= opt.maxResultCount) {
+			return false
+		}
+		i++
+
+		outputID, err := outputIDFromDatabaseKey(key)
+		if err != nil {
+			innerErr = err
+			return false
+		}
+
+		return consumer(outputID)
+	}); err != nil {
+		return err
+	}
+
+	return innerErr
+}
+
 func (u *Manager) UnspentOutputs(options ...UTXOIterateOption) (Outputs, error) {
 	var outputs Outputs
 	consumerFunc := func(output *Output) bool {
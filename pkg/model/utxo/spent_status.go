@@ -8,6 +8,8 @@ import (
 
 type OutputConsumer func(output *Output) bool
 
+type OutputIDConsumer func(outputID *iotago.OutputID) bool
+
 type lookupKey []byte
 
 func lookupKeyUnspentOutput(outputID *iotago.OutputID) lookupKey {
@@ -0,0 +1,130 @@
+package mqtt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fhmq/hmq/broker"
+
+	"github.com/gohornet/hornet/pkg/basicauth"
+)
+
+// AuthUser is a client that is allowed to connect to the embedded broker with its username and password.
+type AuthUser struct {
+	// The username of the client.
+	Username string `koanf:"username"`
+	// The hex encoded scrypt hash of the password of the client.
+	PasswordHash string `koanf:"passwordHash"`
+	// The hex encoded salt of the password hash.
+	PasswordSalt string `koanf:"passwordSalt"`
+	// The topic filters the client is allowed to subscribe to (empty = all topics).
+	Topics []string `koanf:"topics"`
+}
+
+// TokenVerifier verifies a token that a client uses as password instead of a configured user.
+type TokenVerifier func(token string) bool
+
+type authUser struct {
+	basicAuth *basicauth.BasicAuth
+	topics    []string
+}
+
+// Auth authenticates the clients of the embedded broker and checks the topics they subscribe to.
+// Clients are only allowed to subscribe, the events are published by the node itself.
+type Auth struct {
+	users       map[string]*authUser
+	verifyToken TokenVerifier
+}
+
+// NewAuth creates a new Auth for the given users.
+// If verifyToken is set, clients that are not a configured user can authenticate with a token as password,
+// those clients are allowed to subscribe to all topics.
+func NewAuth(users []*AuthUser, verifyToken TokenVerifier) (*Auth, error) {
+
+	a := &Auth{
+		users:       make(map[string]*authUser, len(users)),
+		verifyToken: verifyToken,
+	}
+
+	for _, user := range users {
+		if _, exists := a.users[user.Username]; exists {
+			return nil, fmt.Errorf("user %s configured twice", user.Username)
+		}
+
+		basicAuth, err := basicauth.NewBasicAuth(user.Username, user.PasswordHash, user.PasswordSalt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user %s: %w", user.Username, err)
+		}
+
+		for _, topic := range user.Topics {
+			if err := validateTopicFilter(topic); err != nil {
+				return nil, fmt.Errorf("invalid user %s: %w", user.Username, err)
+			}
+		}
+
+		a.users[user.Username] = &authUser{
+			basicAuth: basicAuth,
+			topics:    user.Topics,
+		}
+	}
+
+	return a, nil
+}
+
+// CheckConnect checks whether the client is allowed to connect.
+func (a *Auth) CheckConnect(_ string, username string, password string) bool {
+	if user, exists := a.users[username]; exists {
+		return user.basicAuth.VerifyUsernameAndPassword(username, password)
+	}
+
+	return a.verifyToken != nil && a.verifyToken(password)
+}
+
+// CheckACL checks whether the client is allowed to subscribe to the topic filter.
+func (a *Auth) CheckACL(action string, _ string, username string, _ string, topic string) bool {
+	if action != broker.SUB {
+		return false
+	}
+
+	user, exists := a.users[username]
+	if !exists || len(user.topics) == 0 {
+		// the client authenticated with a token or the user is allowed to subscribe to all topics
+		return true
+	}
+
+	for _, allowedTopic := range user.topics {
+		if coversTopicFilter(allowedTopic, topic) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// coversTopicFilter checks whether all topics matching the topic filter also match the allowed topic filter.
+func coversTopicFilter(allowedFilter string, filter string) bool {
+	allowedLevels := strings.Split(allowedFilter, "/")
+	levels := strings.Split(filter, "/")
+
+	for i, allowedLevel := range allowedLevels {
+		if allowedLevel == "#" {
+			return true
+		}
+		if i >= len(levels) {
+			return false
+		}
+
+		switch {
+		case levels[i] == "#":
+			// the multi level wildcard is only covered by itself
+			return false
+		case allowedLevel == "+":
+			continue
+		case allowedLevel != levels[i]:
+			// a single level wildcard is only covered by wildcards
+			return false
+		}
+	}
+
+	return len(allowedLevels) == len(levels)
+}
@@ -0,0 +1,72 @@
+package mqtt
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/fhmq/hmq/broker"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/basicauth"
+)
+
+func newTestAuthUser(t *testing.T, username string, password string, topics ...string) *AuthUser {
+	salt, err := basicauth.SaltGenerator(32)
+	require.NoError(t, err)
+
+	passwordKey, err := basicauth.DerivePasswordKey([]byte(password), salt)
+	require.NoError(t, err)
+
+	return &AuthUser{
+		Username:     username,
+		PasswordHash: hex.EncodeToString(passwordKey),
+		PasswordSalt: hex.EncodeToString(salt),
+		Topics:       topics,
+	}
+}
+
+func TestAuth(t *testing.T) {
+
+	auth, err := NewAuth([]*AuthUser{
+		newTestAuthUser(t, "explorer", "secret"),
+		newTestAuthUser(t, "wallet", "secret", "milestones/#", "outputs/+"),
+	}, func(token string) bool {
+		return token == "token"
+	})
+	require.NoError(t, err)
+
+	require.True(t, auth.CheckConnect("client", "explorer", "secret"))
+	require.False(t, auth.CheckConnect("client", "explorer", "wrong"))
+	// configured users can't authenticate with a token
+	require.False(t, auth.CheckConnect("client", "wallet", "token"))
+	require.True(t, auth.CheckConnect("client", "", "token"))
+	require.False(t, auth.CheckConnect("client", "", "wrong"))
+
+	// clients are not allowed to publish
+	require.False(t, auth.CheckACL(broker.PUB, "client", "explorer", "", "messages"))
+
+	require.True(t, auth.CheckACL(broker.SUB, "client", "explorer", "", "#"))
+	require.True(t, auth.CheckACL(broker.SUB, "client", "", "", "#"))
+
+	require.True(t, auth.CheckACL(broker.SUB, "client", "wallet", "", "milestones/latest"))
+	require.True(t, auth.CheckACL(broker.SUB, "client", "wallet", "", "milestones/+"))
+	require.True(t, auth.CheckACL(broker.SUB, "client", "wallet", "", "outputs/0x01"))
+	require.True(t, auth.CheckACL(broker.SUB, "client", "wallet", "", "outputs/+"))
+	require.False(t, auth.CheckACL(broker.SUB, "client", "wallet", "", "outputs/#"))
+	require.False(t, auth.CheckACL(broker.SUB, "client", "wallet", "", "messages"))
+	require.False(t, auth.CheckACL(broker.SUB, "client", "wallet", "", "#"))
+}
+
+func TestNewAuthInvalidUsers(t *testing.T) {
+	_, err := NewAuth([]*AuthUser{
+		newTestAuthUser(t, "wallet", "secret"),
+		newTestAuthUser(t, "wallet", "secret"),
+	}, nil)
+	require.Error(t, err)
+
+	_, err = NewAuth([]*AuthUser{newTestAuthUser(t, "wallet", "secret", "outputs/#/spent")}, nil)
+	require.Error(t, err)
+
+	_, err = NewAuth([]*AuthUser{{Username: "wallet", PasswordHash: "00", PasswordSalt: "00"}}, nil)
+	require.Error(t, err)
+}
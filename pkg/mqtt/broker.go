@@ -13,10 +13,25 @@ type Broker struct {
 	broker       *broker.Broker
 	config       *broker.Config
 	topicManager *topicManager
+	topicsOpts   TopicsOptions
+}
+
+// TLSConfig is the config of the TLS listener of the broker.
+type TLSConfig struct {
+	// The bind address on which the TLS listener listens on.
+	BindAddress string
+	// The path to the certificate of the broker.
+	CertPath string
+	// The path to the private key of the certificate.
+	KeyPath string
+	// The path to the CA certificate the client certificates are verified with.
+	// If set, the clients need to present a valid certificate.
+	CACertPath string
 }
 
 // NewBroker creates a new broker.
-func NewBroker(bindAddress string, wsPort int, wsPath string, workerCount int, onSubscribe OnSubscribeHandler, onUnsubscribe OnUnsubscribeHandler, cleanupThreshold int) (*Broker, error) {
+// tlsConfig and auth are optional, without them the broker doesn't listen for TLS connections and every client is allowed to connect.
+func NewBroker(bindAddress string, wsPort int, wsPath string, workerCount int, onSubscribe OnSubscribeHandler, onUnsubscribe OnUnsubscribeHandler, cleanupThreshold int, topicsOpts TopicsOptions, tlsConfig *TLSConfig, auth *Auth) (*Broker, error) {
 
 	host, port, err := net.SplitHostPort(bindAddress)
 	if err != nil {
@@ -36,6 +51,30 @@ func NewBroker(bindAddress string, wsPort int, wsPath string, workerCount int, o
 		return nil, fmt.Errorf("configure broker config error: %w", err)
 	}
 
+	if tlsConfig != nil {
+		tlsHost, tlsPort, err := net.SplitHostPort(tlsConfig.BindAddress)
+		if err != nil {
+			return nil, fmt.Errorf("configure broker TLS config error: %w", err)
+		}
+
+		if tlsConfig.CertPath == "" || tlsConfig.KeyPath == "" {
+			return nil, fmt.Errorf("configure broker TLS config error: certificate and private key are required")
+		}
+
+		c.TlsHost = tlsHost
+		c.TlsPort = tlsPort
+		c.TlsInfo = broker.TLSInfo{
+			Verify:   tlsConfig.CACertPath != "",
+			CaFile:   tlsConfig.CACertPath,
+			CertFile: tlsConfig.CertPath,
+			KeyFile:  tlsConfig.KeyPath,
+		}
+	}
+
+	if auth != nil {
+		c.Plugin.Auth = auth
+	}
+
 	t := newTopicManager(onSubscribe, onUnsubscribe, cleanupThreshold)
 
 	b, err := broker.NewBroker(c)
@@ -47,6 +86,7 @@ func NewBroker(bindAddress string, wsPort int, wsPath string, workerCount int, o
 		broker:       b,
 		config:       c,
 		topicManager: t,
+		topicsOpts:   topicsOpts,
 	}, nil
 }
 
@@ -60,11 +100,17 @@ func (b *Broker) Config() *broker.Config {
 	return b.config
 }
 
+// HasSubscribers returns whether the given topic has subscribers.
+// Retained topics always have subscribers, so that the retained message is kept up to date for new subscribers.
 func (b *Broker) HasSubscribers(topic string) bool {
+	if _, retain := b.topicsOpts.Options(topic); retain {
+		return true
+	}
 	return b.topicManager.hasSubscribers(topic)
 }
 
 // Send publishes a message.
+// The embedded broker sends all messages with QoS 0, only the retain option of the topic is applied.
 func (b *Broker) Send(topic string, payload []byte) {
 
 	packet := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
@@ -72,6 +118,18 @@ func (b *Broker) Send(topic string, payload []byte) {
 	packet.Qos = 0
 	packet.Payload = payload
 
+	if _, retain := b.topicsOpts.Options(topic); retain {
+		// the retained message is sent with the retain flag to new subscribers only
+		retainedPacket := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+		retainedPacket.TopicName = topic
+		retainedPacket.Qos = 0
+		retainedPacket.Retain = true
+		retainedPacket.Payload = payload
+
+		// errors can be ignored, the memory topic provider only fails for invalid topics
+		_ = b.topicManager.Retain(retainedPacket)
+	}
+
 	b.broker.PublishMessage(packet)
 }
 
@@ -0,0 +1,86 @@
+package mqtt
+
+import (
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+const (
+	// the interval in which the connection to the external broker is retried.
+	externalBrokerConnectRetryInterval = 10 * time.Second
+	// the time to wait for outstanding messages to be sent before disconnecting.
+	externalBrokerDisconnectQuiesce = 250
+)
+
+// Publisher publishes messages to the subscribers of the topics.
+type Publisher interface {
+	// HasSubscribers returns whether the given topic has subscribers.
+	HasSubscribers(topic string) bool
+	// Send publishes a message.
+	Send(topic string, payload []byte)
+}
+
+// ExternalBroker publishes messages to an existing external MQTT broker instead of running an embedded one.
+type ExternalBroker struct {
+	client     mqtt.Client
+	topicsOpts TopicsOptions
+}
+
+// NewExternalBroker creates a new client for the external broker with the given URI (e.g. "tcp://host:1883" or "ssl://host:8883").
+// tlsConfig is optional and only used for secured connections.
+func NewExternalBroker(brokerURI string, clientID string, username string, password string, tlsConfig *tls.Config, topicsOpts TopicsOptions) (*ExternalBroker, error) {
+	if brokerURI == "" {
+		return nil, fmt.Errorf("URI of the external broker is missing")
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(brokerURI).
+		SetClientID(clientID).
+		SetUsername(username).
+		SetPassword(password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(externalBrokerConnectRetryInterval).
+		SetOrderMatters(false)
+
+	if tlsConfig != nil {
+		opts.SetTLSConfig(tlsConfig)
+	}
+
+	return &ExternalBroker{
+		client:     mqtt.NewClient(opts),
+		topicsOpts: topicsOpts,
+	}, nil
+}
+
+// Connect connects to the external broker.
+// It blocks until the connection was established, failed connection attempts are retried.
+func (b *ExternalBroker) Connect() error {
+	token := b.client.Connect()
+	token.Wait()
+	return token.Error()
+}
+
+// Disconnect disconnects from the external broker.
+func (b *ExternalBroker) Disconnect() {
+	b.client.Disconnect(externalBrokerDisconnectQuiesce)
+}
+
+// HasSubscribers always returns true, because the subscribers of the external broker are unknown.
+func (b *ExternalBroker) HasSubscribers(_ string) bool {
+	return true
+}
+
+// Send publishes a message with the QoS level and the retain flag of the topic.
+// Messages are dropped as long as the client is not connected.
+func (b *ExternalBroker) Send(topic string, payload []byte) {
+	if !b.client.IsConnectionOpen() {
+		return
+	}
+
+	qos, retain := b.topicsOpts.Options(topic)
+	b.client.Publish(topic, qos, retain, payload)
+}
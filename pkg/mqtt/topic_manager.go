@@ -1,6 +1,7 @@
 package mqtt
 
 import (
+	"strings"
 	"sync"
 
 	"github.com/eclipse/paho.mqtt.golang/packets"
@@ -18,6 +19,8 @@ type topicManager struct {
 	subscribedTopics        map[string]int
 	subscribedTopicsLock    sync.RWMutex
 	subscribedTopicsDeleted int
+	// the subscribed topic filters that contain wildcards, they are also part of subscribedTopics.
+	subscribedWildcardTopics map[string]struct{}
 
 	cleanupThreshold int
 
@@ -38,6 +41,9 @@ func (t *topicManager) Subscribe(topic []byte, qos byte, subscriber interface{})
 			t.subscribedTopics[topicName] = count + 1
 		} else {
 			t.subscribedTopics[topicName] = 1
+			if strings.ContainsAny(topicName, "+#") {
+				t.subscribedWildcardTopics[topicName] = struct{}{}
+			}
 		}
 
 		t.onSubscribe(topic)
@@ -93,27 +99,44 @@ func (t *topicManager) Size() int {
 	return len(t.subscribedTopics)
 }
 
+// hasSubscribers returns whether the topic is subscribed directly or matches a subscribed topic filter with wildcards.
 func (t *topicManager) hasSubscribers(topicName string) bool {
 	t.subscribedTopicsLock.RLock()
 	defer t.subscribedTopicsLock.RUnlock()
 
-	count, has := t.subscribedTopics[topicName]
-	return has && count > 0
+	if count, has := t.subscribedTopics[topicName]; has && count > 0 {
+		return true
+	}
+
+	for filter := range t.subscribedWildcardTopics {
+		if matchesTopicFilter(filter, topicName) {
+			return true
+		}
+	}
+
+	return false
 }
 
-// cleanupWithoutLocking recreates the subscribedTopics map to release memory for the garbage collector.
+// cleanupWithoutLocking recreates the maps of the subscribed topics to release memory for the garbage collector.
 func (t *topicManager) cleanupWithoutLocking() {
 	subscribedTopics := make(map[string]int)
 	for topicName, count := range t.subscribedTopics {
 		subscribedTopics[topicName] = count
 	}
 	t.subscribedTopics = subscribedTopics
+
+	subscribedWildcardTopics := make(map[string]struct{})
+	for topicName := range t.subscribedWildcardTopics {
+		subscribedWildcardTopics[topicName] = struct{}{}
+	}
+	t.subscribedWildcardTopics = subscribedWildcardTopics
 	t.subscribedTopicsDeleted = 0
 }
 
 // deleteTopic deletes a topic from the manager.
 func (t *topicManager) deleteTopic(topicName string) {
 	delete(t.subscribedTopics, topicName)
+	delete(t.subscribedWildcardTopics, topicName)
 
 	// increase the deletion counter to trigger garbage collection
 	t.subscribedTopicsDeleted++
@@ -125,11 +148,12 @@ func (t *topicManager) deleteTopic(topicName string) {
 func newTopicManager(onSubscribe OnSubscribeHandler, onUnsubscribe OnUnsubscribeHandler, cleanupThreshold int) *topicManager {
 
 	mgr := &topicManager{
-		mem:              topics.NewMemProvider(),
-		subscribedTopics: make(map[string]int),
-		onSubscribe:      onSubscribe,
-		onUnsubscribe:    onUnsubscribe,
-		cleanupThreshold: cleanupThreshold,
+		mem:                      topics.NewMemProvider(),
+		subscribedTopics:         make(map[string]int),
+		subscribedWildcardTopics: make(map[string]struct{}),
+		onSubscribe:              onSubscribe,
+		onUnsubscribe:            onUnsubscribe,
+		cleanupThreshold:         cleanupThreshold,
 	}
 
 	// The normal MQTT broker uses the `mem` topic manager internally, so first unregister the default one.
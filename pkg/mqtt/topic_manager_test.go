@@ -0,0 +1,29 @@
+package mqtt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicManagerWildcardSubscribers(t *testing.T) {
+	mgr := newTopicManager(func(_ []byte) {}, func(_ []byte) {}, 10)
+
+	_, err := mgr.Subscribe([]byte("addresses/+/outputs"), 0, "client")
+	require.NoError(t, err)
+
+	require.True(t, mgr.hasSubscribers("addresses/atoi1qz/outputs"))
+	require.False(t, mgr.hasSubscribers("addresses/atoi1qz/expirations"))
+	require.False(t, mgr.hasSubscribers("addresses/ed25519/00/outputs"))
+
+	_, err = mgr.Subscribe([]byte("addresses/#"), 0, "client")
+	require.NoError(t, err)
+	require.True(t, mgr.hasSubscribers("addresses/ed25519/00/outputs"))
+
+	require.NoError(t, mgr.Unsubscribe([]byte("addresses/#"), "client"))
+	require.False(t, mgr.hasSubscribers("addresses/ed25519/00/outputs"))
+	require.True(t, mgr.hasSubscribers("addresses/atoi1qz/outputs"))
+
+	require.NoError(t, mgr.Unsubscribe([]byte("addresses/+/outputs"), "client"))
+	require.False(t, mgr.hasSubscribers("addresses/atoi1qz/outputs"))
+}
@@ -0,0 +1,85 @@
+package mqtt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TopicOptions are the options the messages of the topics matching the topic filter are published with.
+type TopicOptions struct {
+	// The topic filter, it may contain the wildcards "+" (single level) and "#" (multi level).
+	Topic string `koanf:"topic"`
+	// The QoS level of the messages.
+	QoS byte `koanf:"qos"`
+	// Whether the latest message is retained by the broker and sent to new subscribers.
+	Retain bool `koanf:"retain"`
+}
+
+// TopicsOptions are the options of all configured topic filters.
+type TopicsOptions []*TopicOptions
+
+// NewTopicsOptions validates the given options of the topic filters.
+func NewTopicsOptions(options []*TopicOptions) (TopicsOptions, error) {
+	for _, opts := range options {
+		if err := validateTopicFilter(opts.Topic); err != nil {
+			return nil, err
+		}
+
+		if opts.QoS > 2 {
+			return nil, fmt.Errorf("invalid QoS level %d for topic %s", opts.QoS, opts.Topic)
+		}
+	}
+
+	return options, nil
+}
+
+// Options returns the QoS level and the retain flag for the given topic.
+// The first matching topic filter wins, messages of topics without a matching filter are sent with QoS 0 and not retained.
+func (t TopicsOptions) Options(topic string) (qos byte, retain bool) {
+	for _, opts := range t {
+		if matchesTopicFilter(opts.Topic, topic) {
+			return opts.QoS, opts.Retain
+		}
+	}
+	return 0, false
+}
+
+// validateTopicFilter checks that the wildcards of the topic filter occupy a whole level
+// and that the multi level wildcard is the last level.
+func validateTopicFilter(filter string) error {
+	if filter == "" {
+		return fmt.Errorf("topic filter is missing")
+	}
+
+	levels := strings.Split(filter, "/")
+	for i, level := range levels {
+		if strings.ContainsAny(level, "+#") && len(level) > 1 {
+			return fmt.Errorf("invalid wildcard in topic filter %s", filter)
+		}
+		if level == "#" && i != len(levels)-1 {
+			return fmt.Errorf("multi level wildcard is not the last level in topic filter %s", filter)
+		}
+	}
+
+	return nil
+}
+
+// matchesTopicFilter checks whether the topic matches the topic filter.
+func matchesTopicFilter(filter string, topic string) bool {
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+
+	for i, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+		if i >= len(topicLevels) {
+			return false
+		}
+		if level != "+" && level != topicLevels[i] {
+			return false
+		}
+	}
+
+	return len(filterLevels) == len(topicLevels)
+}
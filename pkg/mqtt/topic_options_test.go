@@ -0,0 +1,49 @@
+package mqtt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchesTopicFilter(t *testing.T) {
+	require.True(t, matchesTopicFilter("milestones/latest", "milestones/latest"))
+	require.False(t, matchesTopicFilter("milestones/latest", "milestones/confirmed"))
+	require.False(t, matchesTopicFilter("milestones", "milestones/latest"))
+
+	require.True(t, matchesTopicFilter("outputs/+", "outputs/0x01"))
+	require.False(t, matchesTopicFilter("outputs/+", "outputs/expirations/0x01"))
+	require.True(t, matchesTopicFilter("addresses/+/expirations", "addresses/iota1/expirations"))
+
+	require.True(t, matchesTopicFilter("milestones/#", "milestones/latest"))
+	require.True(t, matchesTopicFilter("#", "messages"))
+	require.False(t, matchesTopicFilter("transactions/#", "messages"))
+}
+
+func TestTopicsOptions(t *testing.T) {
+	_, err := NewTopicsOptions([]*TopicOptions{{Topic: "milestones/#/latest"}})
+	require.Error(t, err)
+	_, err = NewTopicsOptions([]*TopicOptions{{Topic: "outputs/+abc"}})
+	require.Error(t, err)
+	_, err = NewTopicsOptions([]*TopicOptions{{Topic: "milestones/latest", QoS: 3}})
+	require.Error(t, err)
+
+	options, err := NewTopicsOptions([]*TopicOptions{
+		{Topic: "milestones/latest", QoS: 1, Retain: true},
+		{Topic: "milestones/#", QoS: 2},
+	})
+	require.NoError(t, err)
+
+	// the first matching topic filter wins
+	qos, retain := options.Options("milestones/latest")
+	require.Equal(t, byte(1), qos)
+	require.True(t, retain)
+
+	qos, retain = options.Options("milestones/confirmed")
+	require.Equal(t, byte(2), qos)
+	require.False(t, retain)
+
+	qos, retain = options.Options("messages")
+	require.Equal(t, byte(0), qos)
+	require.False(t, retain)
+}
@@ -0,0 +1,181 @@
+package mqtt
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/gorilla/websocket"
+	"go.uber.org/atomic"
+)
+
+const (
+	// the timeout for writing a message to a websocket client.
+	webSocketWriteTimeout = 10 * time.Second
+)
+
+// SlowClientPolicy defines how the WebSocketBridge handles clients whose outbound queue is full.
+type SlowClientPolicy string
+
+const (
+	// SlowClientPolicyDrop drops new messages until the client caught up.
+	SlowClientPolicyDrop SlowClientPolicy = "drop"
+	// SlowClientPolicyDisconnect disconnects the client.
+	SlowClientPolicyDisconnect SlowClientPolicy = "disconnect"
+)
+
+// WebSocketBridge connects MQTT over WebSocket clients to the TCP listener of the broker.
+// The broker writes to its clients synchronously, so every client gets a bounded outbound queue
+// to prevent slow consumers from stalling the broker or growing the memory without limits.
+type WebSocketBridge struct {
+	brokerAddress string
+	queueSize     int
+	policy        SlowClientPolicy
+	upgrader      *websocket.Upgrader
+
+	droppedMessages     atomic.Uint64
+	disconnectedClients atomic.Uint64
+}
+
+// NewWebSocketBridge creates a new bridge to the broker listening on the given TCP address.
+func NewWebSocketBridge(brokerAddress string, queueSize int, policy SlowClientPolicy) (*WebSocketBridge, error) {
+	if queueSize <= 0 {
+		return nil, fmt.Errorf("invalid queue size: %d", queueSize)
+	}
+
+	switch policy {
+	case SlowClientPolicyDrop, SlowClientPolicyDisconnect:
+	default:
+		return nil, fmt.Errorf("unknown slow client policy: %s", policy)
+	}
+
+	return &WebSocketBridge{
+		brokerAddress: brokerAddress,
+		queueSize:     queueSize,
+		policy:        policy,
+		upgrader: &websocket.Upgrader{
+			HandshakeTimeout: webSocketWriteTimeout,
+			Subprotocols:     []string{"mqtt", "mqttv3.1"},
+			CheckOrigin:      func(r *http.Request) bool { return true }, // allow any origin for websocket connections
+		},
+	}, nil
+}
+
+// DroppedMessages returns the number of messages that were dropped because the queue of a client was full.
+func (b *WebSocketBridge) DroppedMessages() uint64 {
+	return b.droppedMessages.Load()
+}
+
+// DisconnectedClients returns the number of clients that were disconnected because their queue was full.
+func (b *WebSocketBridge) DisconnectedClients() uint64 {
+	return b.disconnectedClients.Load()
+}
+
+// ServeWebSocket upgrades the request to a websocket connection and bridges it to the broker until one side disconnects.
+func (b *WebSocketBridge) ServeWebSocket(w http.ResponseWriter, r *http.Request) error {
+	wsConn, err := b.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return err
+	}
+
+	brokerConn, err := net.Dial("tcp", b.brokerAddress)
+	if err != nil {
+		_ = wsConn.Close()
+		return fmt.Errorf("connecting to the broker failed: %w", err)
+	}
+
+	var closeOnce sync.Once
+	closeConns := func() {
+		closeOnce.Do(func() {
+			_ = wsConn.Close()
+			_ = brokerConn.Close()
+		})
+	}
+	defer closeConns()
+
+	queue := make(chan packets.ControlPacket, b.queueSize)
+	writerDone := make(chan struct{})
+
+	// forward the packets of the broker to the queue of the client
+	go func() {
+		defer close(queue)
+
+		for {
+			packet, err := packets.ReadPacket(brokerConn)
+			if err != nil {
+				closeConns()
+				return
+			}
+
+			if !b.enqueue(queue, writerDone, packet) {
+				closeConns()
+				return
+			}
+		}
+	}()
+
+	// write the queued packets to the client
+	go func() {
+		defer close(writerDone)
+
+		var buf bytes.Buffer
+		for packet := range queue {
+			buf.Reset()
+			if err := packet.Write(&buf); err != nil {
+				closeConns()
+				return
+			}
+
+			_ = wsConn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+			if err := wsConn.WriteMessage(websocket.BinaryMessage, buf.Bytes()); err != nil {
+				closeConns()
+				return
+			}
+		}
+	}()
+
+	// forward the packets of the client to the broker
+	for {
+		_, data, err := wsConn.ReadMessage()
+		if err != nil {
+			return nil
+		}
+
+		if _, err := brokerConn.Write(data); err != nil {
+			return nil
+		}
+	}
+}
+
+// enqueue adds the packet to the outbound queue of a client and applies the slow client policy if the queue is full.
+// It returns false if the client should be disconnected.
+func (b *WebSocketBridge) enqueue(queue chan<- packets.ControlPacket, writerDone <-chan struct{}, packet packets.ControlPacket) bool {
+	select {
+	case queue <- packet:
+		return true
+	default:
+	}
+
+	if b.policy == SlowClientPolicyDisconnect {
+		b.droppedMessages.Inc()
+		b.disconnectedClients.Inc()
+		return false
+	}
+
+	if publishPacket, ok := packet.(*packets.PublishPacket); ok && publishPacket.Qos == 0 {
+		b.droppedMessages.Inc()
+		return true
+	}
+
+	// control packets and acknowledged messages are not dropped, they wait for free space in the queue
+	select {
+	case queue <- packet:
+		return true
+	case <-writerDone:
+		return false
+	}
+}
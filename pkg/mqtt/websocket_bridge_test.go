@@ -0,0 +1,59 @@
+package mqtt
+
+import (
+	"testing"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPublishPacket(qos byte) *packets.PublishPacket {
+	packet := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+	packet.TopicName = "milestones/latest"
+	packet.Qos = qos
+	return packet
+}
+
+func TestWebSocketBridgeDropPolicy(t *testing.T) {
+	bridge, err := NewWebSocketBridge("localhost:1883", 1, SlowClientPolicyDrop)
+	require.NoError(t, err)
+
+	queue := make(chan packets.ControlPacket, 1)
+	writerDone := make(chan struct{})
+
+	require.True(t, bridge.enqueue(queue, writerDone, newTestPublishPacket(0)))
+	require.Equal(t, uint64(0), bridge.DroppedMessages())
+
+	// the queue is full, new messages are dropped
+	require.True(t, bridge.enqueue(queue, writerDone, newTestPublishPacket(0)))
+	require.True(t, bridge.enqueue(queue, writerDone, newTestPublishPacket(0)))
+	require.Equal(t, uint64(2), bridge.DroppedMessages())
+	require.Len(t, queue, 1)
+
+	// control packets wait for free space in the queue, unless the writer stopped
+	close(writerDone)
+	require.False(t, bridge.enqueue(queue, writerDone, packets.NewControlPacket(packets.Pingresp)))
+	require.Equal(t, uint64(2), bridge.DroppedMessages())
+	require.Equal(t, uint64(0), bridge.DisconnectedClients())
+}
+
+func TestWebSocketBridgeDisconnectPolicy(t *testing.T) {
+	bridge, err := NewWebSocketBridge("localhost:1883", 1, SlowClientPolicyDisconnect)
+	require.NoError(t, err)
+
+	queue := make(chan packets.ControlPacket, 1)
+	writerDone := make(chan struct{})
+
+	require.True(t, bridge.enqueue(queue, writerDone, newTestPublishPacket(0)))
+	require.False(t, bridge.enqueue(queue, writerDone, newTestPublishPacket(0)))
+	require.Equal(t, uint64(1), bridge.DroppedMessages())
+	require.Equal(t, uint64(1), bridge.DisconnectedClients())
+}
+
+func TestNewWebSocketBridgeInvalidConfig(t *testing.T) {
+	_, err := NewWebSocketBridge("localhost:1883", 0, SlowClientPolicyDrop)
+	require.Error(t, err)
+
+	_, err = NewWebSocketBridge("localhost:1883", 1, "block")
+	require.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 
@@ -20,9 +21,11 @@ import (
 	"github.com/iotaledger/hive.go/autopeering/discover"
 	"github.com/iotaledger/hive.go/autopeering/peer"
 	"github.com/iotaledger/hive.go/autopeering/peer/service"
+	"github.com/iotaledger/hive.go/autopeering/salt"
 	"github.com/iotaledger/hive.go/autopeering/selection"
 	"github.com/iotaledger/hive.go/autopeering/server"
 	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/identity"
 	"github.com/iotaledger/hive.go/iputils"
 	"github.com/iotaledger/hive.go/logger"
@@ -45,8 +48,27 @@ var (
 	ErrInvalidMultiAddrPubKeyAutopeering = errors.New("invalid multi address autopeering public key")
 	// ErrMultiAddrNoHost gets returned if a multi address does not contain any host, meaning it neither has a /ip4, /ip6 or /dns portion.
 	ErrMultiAddrNoHost = errors.New("multi address contains no host")
+	// ErrSelectionDisabled gets returned if the peer selection is not enabled.
+	ErrSelectionDisabled = errors.New("autopeering peer selection is disabled")
+	// ErrSelectionNotRunning gets returned if the peer selection was not started yet or is already stopped.
+	ErrSelectionNotRunning = errors.New("autopeering peer selection is not running")
+	// ErrInvalidSelectionParameters gets returned if the given peer selection parameters are invalid.
+	ErrInvalidSelectionParameters = errors.New("invalid autopeering peer selection parameters")
 )
 
+// SelectionResetCaller is used to signal that the peer selection protocol was replaced.
+func SelectionResetCaller(handler interface{}, params ...interface{}) {
+	handler.(func(oldSelection *selection.Protocol, newSelection *selection.Protocol))(params[0].(*selection.Protocol), params[1].(*selection.Protocol))
+}
+
+// Events are the events fired by the AutopeeringManager.
+type Events struct {
+	// Fired when the peer selection protocol was replaced by a new one.
+	// The old selection protocol is already closed, the new one is not started yet.
+	// Handlers must not block, since the new selection protocol is started afterwards.
+	SelectionReset *events.Event
+}
+
 // RegisterAutopeeringProtocolInMultiAddresses registers the autopeering protocol for multi addresses.
 // The 'autopeering' protocol value is the base58 encoded ed25519 public key and must be always 44 in length.
 func RegisterAutopeeringProtocolInMultiAddresses() error {
@@ -287,6 +309,9 @@ type AutopeeringManager struct {
 	// the logger used to log events.
 	*utils.WrappedLogger
 
+	// Events are the events fired by the AutopeeringManager.
+	Events *Events
+
 	// bindAddress is the bind address for autopeering.
 	bindAddress string
 	// entryNodes are the entry nodes for autopeering.
@@ -299,21 +324,37 @@ type AutopeeringManager struct {
 	localPeerContainer *LocalPeerContainer
 	// discoveryProtocol is the peer discovery protocol.
 	discoveryProtocol *discover.Protocol
+	// server is the server running the discovery and the peer selection.
+	server *server.Server
+	// resetLock is used to serialize resets of the peer selection protocol.
+	resetLock sync.Mutex
+	// selectionLock is used to replace the peer selection protocol at runtime.
+	selectionLock sync.RWMutex
+	// selectionParameters are the parameters of the current peer selection protocol.
+	selectionParameters selection.Parameters
+	// selectionOptions are the options used to create a peer selection protocol.
+	selectionOptions []selection.Option
 	// selectionProtocol is the peer selection protocol.
 	selectionProtocol *selection.Protocol
 }
 
-func NewAutopeeringManager(log *logger.Logger, bindAddress string, entryNodes []string, preferIPv6 bool, p2pServiceKey service.Key) *AutopeeringManager {
+func NewAutopeeringManager(log *logger.Logger, bindAddress string, entryNodes []string, preferIPv6 bool, p2pServiceKey service.Key, selectionParameters selection.Parameters) *AutopeeringManager {
 
 	return &AutopeeringManager{
-		WrappedLogger:      utils.NewWrappedLogger(log),
-		bindAddress:        bindAddress,
-		entryNodes:         entryNodes,
-		preferIPv6:         preferIPv6,
-		p2pServiceKey:      p2pServiceKey,
-		localPeerContainer: nil,
-		discoveryProtocol:  nil,
-		selectionProtocol:  nil,
+		WrappedLogger: utils.NewWrappedLogger(log),
+		Events: &Events{
+			SelectionReset: events.NewEvent(SelectionResetCaller),
+		},
+		bindAddress:         bindAddress,
+		entryNodes:          entryNodes,
+		preferIPv6:          preferIPv6,
+		p2pServiceKey:       p2pServiceKey,
+		localPeerContainer:  nil,
+		discoveryProtocol:   nil,
+		server:              nil,
+		selectionParameters: selectionParameters,
+		selectionOptions:    nil,
+		selectionProtocol:   nil,
 	}
 
 }
@@ -329,10 +370,22 @@ func (a *AutopeeringManager) LocalPeerContainer() *LocalPeerContainer {
 }
 
 // Selection returns the peer selection protocol.
+// The protocol might be replaced at runtime by ResetSelection.
 func (a *AutopeeringManager) Selection() *selection.Protocol {
+	a.selectionLock.RLock()
+	defer a.selectionLock.RUnlock()
+
 	return a.selectionProtocol
 }
 
+// SelectionParameters returns the parameters of the current peer selection protocol.
+func (a *AutopeeringManager) SelectionParameters() selection.Parameters {
+	a.selectionLock.RLock()
+	defer a.selectionLock.RUnlock()
+
+	return a.selectionParameters
+}
+
 // Discovery returns the peer discovery protocol.
 func (a *AutopeeringManager) Discovery() *discover.Protocol {
 	return a.discoveryProtocol
@@ -386,7 +439,84 @@ func (a *AutopeeringManager) Init(localPeerContainer *LocalPeerContainer, initSe
 		return true
 	}
 
-	a.selectionProtocol = selection.New(localPeerContainer.Local(), a.discoveryProtocol, selection.Logger(a.LoggerNamed("sel")), selection.NeighborValidator(selection.ValidatorFunc(isValidPeer)))
+	a.selectionOptions = []selection.Option{selection.Logger(a.LoggerNamed("sel")), selection.NeighborValidator(selection.ValidatorFunc(isValidPeer))}
+
+	selection.SetParameters(a.selectionParameters)
+	a.selectionProtocol = selection.New(localPeerContainer.Local(), a.discoveryProtocol, a.selectionOptions...)
+}
+
+// handleSelectionMessage passes incoming messages to the current peer selection protocol.
+func (a *AutopeeringManager) handleSelectionMessage(s *server.Server, fromAddr *net.UDPAddr, from *identity.Identity, data []byte) (bool, error) {
+	selectionProtocol := a.Selection()
+	if selectionProtocol == nil {
+		return false, nil
+	}
+	return selectionProtocol.HandleMessage(s, fromAddr, from, data)
+}
+
+// ResetSelection replaces the running peer selection protocol by a new one using the given parameters.
+// The local salts are renewed, so the neighborhood is evaluated from scratch.
+// The neighbors of the old selection protocol are returned, they are not dropped by the protocol itself.
+func (a *AutopeeringManager) ResetSelection(params selection.Parameters) ([]*peer.Peer, error) {
+	if params.InboundNeighborSize < 0 || params.OutboundNeighborSize < 0 {
+		return nil, errors.WithMessage(ErrInvalidSelectionParameters, "neighbor sizes must not be negative")
+	}
+	if params.SaltLifetime <= 0 {
+		return nil, errors.WithMessage(ErrInvalidSelectionParameters, "salt lifetime must be greater than zero")
+	}
+
+	publicSalt, err := salt.NewSalt(params.SaltLifetime)
+	if err != nil {
+		return nil, err
+	}
+	privateSalt, err := salt.NewSalt(params.SaltLifetime)
+	if err != nil {
+		return nil, err
+	}
+
+	// only one reset at a time, and not while the autopeering is shutting down
+	a.resetLock.Lock()
+	defer a.resetLock.Unlock()
+
+	oldSelectionProtocol, newSelectionProtocol, srv, err := func() (*selection.Protocol, *selection.Protocol, *server.Server, error) {
+		a.selectionLock.Lock()
+		defer a.selectionLock.Unlock()
+
+		if a.selectionProtocol == nil {
+			return nil, nil, nil, ErrSelectionDisabled
+		}
+		if a.server == nil {
+			return nil, nil, nil, ErrSelectionNotRunning
+		}
+
+		oldSelectionProtocol := a.selectionProtocol
+		oldSelectionProtocol.Close()
+
+		// the parameters are only read by the selection protocol on creation and on salt updates,
+		// so they can safely be changed while no selection protocol is running.
+		selection.SetParameters(params)
+		a.selectionParameters = params
+
+		// a new selection protocol only creates salts if there are none yet.
+		lPeer := a.localPeerContainer.Local()
+		lPeer.SetPublicSalt(publicSalt)
+		lPeer.SetPrivateSalt(privateSalt)
+
+		a.selectionProtocol = selection.New(lPeer, a.discoveryProtocol, a.selectionOptions...)
+
+		return oldSelectionProtocol, a.selectionProtocol, a.server, nil
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	// the handlers move their closures to the new selection protocol before it is started.
+	a.Events.SelectionReset.Trigger(oldSelectionProtocol, newSelectionProtocol)
+	newSelectionProtocol.Start(srv)
+
+	a.LogInfof("peer selection reset: inbound=%d, outbound=%d, saltLifetime=%s", params.InboundNeighborSize, params.OutboundNeighborSize, params.SaltLifetime)
+
+	return oldSelectionProtocol.GetNeighbors(), nil
 }
 
 func (a *AutopeeringManager) Run(ctx context.Context) {
@@ -408,7 +538,8 @@ func (a *AutopeeringManager) Run(ctx context.Context) {
 
 	handlers := []server.Handler{a.discoveryProtocol}
 	if a.selectionProtocol != nil {
-		handlers = append(handlers, a.selectionProtocol)
+		// the selection protocol can be replaced at runtime
+		handlers = append(handlers, server.HandlerFunc(a.handleSelectionMessage))
 	}
 
 	// start a server doing discovery and peering
@@ -417,19 +548,28 @@ func (a *AutopeeringManager) Run(ctx context.Context) {
 	// start the discovery on that connection
 	a.discoveryProtocol.Start(srv)
 
+	a.selectionLock.Lock()
+	a.server = srv
 	if a.selectionProtocol != nil {
 		// start the peering on that connection
 		a.selectionProtocol.Start(srv)
 	}
+	a.selectionLock.Unlock()
 
 	a.LogInfof("started: Address=%s/%s PublicKey=%s", localAddr.String(), localAddr.Network(), lPeer.PublicKey().String())
 
 	<-ctx.Done()
 	a.LogInfo("Stopping Autopeering ...")
 
+	a.resetLock.Lock()
+	a.selectionLock.Lock()
 	if a.selectionProtocol != nil {
 		a.selectionProtocol.Close()
 	}
+	a.server = nil
+	a.selectionLock.Unlock()
+	a.resetLock.Unlock()
+
 	a.discoveryProtocol.Close()
 
 	// underlying connection is closed by the server
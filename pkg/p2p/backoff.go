@@ -0,0 +1,105 @@
+package p2p
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidReconnectBackoff is returned if the parameters of a reconnect backoff policy are invalid.
+	ErrInvalidReconnectBackoff = errors.New("invalid reconnect backoff")
+)
+
+// ReconnectBackoff defines the exponential backoff policy for reconnect attempts to a known peer.
+type ReconnectBackoff struct {
+	// The delay before the first reconnect attempt.
+	InitialInterval time.Duration
+	// The factor the delay is multiplied with after every failed attempt.
+	Multiplier float64
+	// The maximum delay between two reconnect attempts.
+	MaxInterval time.Duration
+	// The maximum random fraction of the delay that is added to it (0-1).
+	Jitter float64
+}
+
+// Validate checks whether the parameters of the backoff policy are valid.
+func (b *ReconnectBackoff) Validate() error {
+	if b.InitialInterval <= 0 {
+		return errors.WithMessage(ErrInvalidReconnectBackoff, "initial interval must be greater than zero")
+	}
+	if b.Multiplier < 1 {
+		return errors.WithMessage(ErrInvalidReconnectBackoff, "multiplier must be at least 1")
+	}
+	if b.MaxInterval < b.InitialInterval {
+		return errors.WithMessage(ErrInvalidReconnectBackoff, "max interval must not be smaller than the initial interval")
+	}
+	if b.Jitter < 0 || b.Jitter > 1 {
+		return errors.WithMessage(ErrInvalidReconnectBackoff, "jitter must be between 0 and 1")
+	}
+	return nil
+}
+
+// Delay returns the delay before the reconnect attempt with the given number (starting at 0).
+func (b *ReconnectBackoff) Delay(attempt int) time.Duration {
+	delay := float64(b.InitialInterval) * math.Pow(b.Multiplier, float64(attempt))
+	if delay > float64(b.MaxInterval) || math.IsInf(delay, 0) || math.IsNaN(delay) {
+		delay = float64(b.MaxInterval)
+	}
+
+	if b.Jitter > 0 {
+		delay += rand.Float64() * b.Jitter * delay
+	}
+
+	return time.Duration(delay)
+}
+
+// PeerReconnectConfig overrides the reconnect backoff policy of the node for a single peer.
+// Fields which are not set keep the value of the node's policy.
+type PeerReconnectConfig struct {
+	// The delay before the first reconnect attempt (e.g. "5s").
+	InitialInterval string `json:"initialInterval,omitempty" koanf:"initialInterval"`
+	// The factor the delay is multiplied with after every failed attempt.
+	Multiplier float64 `json:"multiplier,omitempty" koanf:"multiplier"`
+	// The maximum delay between two reconnect attempts (e.g. "10m").
+	MaxInterval string `json:"maxInterval,omitempty" koanf:"maxInterval"`
+	// The maximum random fraction of the delay that is added to it (0-1).
+	Jitter *float64 `json:"jitter,omitempty" koanf:"jitter"`
+}
+
+// Backoff returns the backoff policy of the peer, based on the given policy of the node.
+func (c *PeerReconnectConfig) Backoff(defaultBackoff ReconnectBackoff) (*ReconnectBackoff, error) {
+	backoff := defaultBackoff
+
+	if c.InitialInterval != "" {
+		initialInterval, err := time.ParseDuration(c.InitialInterval)
+		if err != nil {
+			return nil, errors.WithMessagef(ErrInvalidReconnectBackoff, "invalid initial interval: %s", err)
+		}
+		backoff.InitialInterval = initialInterval
+	}
+
+	if c.Multiplier != 0 {
+		backoff.Multiplier = c.Multiplier
+	}
+
+	if c.MaxInterval != "" {
+		maxInterval, err := time.ParseDuration(c.MaxInterval)
+		if err != nil {
+			return nil, errors.WithMessagef(ErrInvalidReconnectBackoff, "invalid max interval: %s", err)
+		}
+		backoff.MaxInterval = maxInterval
+	}
+
+	if c.Jitter != nil {
+		backoff.Jitter = *c.Jitter
+	}
+
+	if err := backoff.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &backoff, nil
+}
@@ -0,0 +1,82 @@
+package p2p_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/p2p"
+)
+
+func TestReconnectBackoffDelay(t *testing.T) {
+	backoff := &p2p.ReconnectBackoff{
+		InitialInterval: time.Second,
+		Multiplier:      2,
+		MaxInterval:     10 * time.Second,
+	}
+	require.NoError(t, backoff.Validate())
+
+	require.Equal(t, time.Second, backoff.Delay(0))
+	require.Equal(t, 2*time.Second, backoff.Delay(1))
+	require.Equal(t, 8*time.Second, backoff.Delay(3))
+	require.Equal(t, 10*time.Second, backoff.Delay(4))
+	require.Equal(t, 10*time.Second, backoff.Delay(10000))
+
+	backoff.Jitter = 0.5
+	for attempt := 0; attempt < 10; attempt++ {
+		delay := backoff.Delay(attempt)
+		require.GreaterOrEqual(t, delay, (&p2p.ReconnectBackoff{InitialInterval: time.Second, Multiplier: 2, MaxInterval: 10 * time.Second}).Delay(attempt))
+		require.LessOrEqual(t, delay, 15*time.Second)
+	}
+}
+
+func TestReconnectBackoffValidate(t *testing.T) {
+	valid := p2p.ReconnectBackoff{InitialInterval: time.Second, Multiplier: 1, MaxInterval: time.Second, Jitter: 1}
+	require.NoError(t, valid.Validate())
+
+	for _, backoff := range []p2p.ReconnectBackoff{
+		{InitialInterval: 0, Multiplier: 1, MaxInterval: time.Second},
+		{InitialInterval: time.Second, Multiplier: 0.5, MaxInterval: time.Second},
+		{InitialInterval: time.Minute, Multiplier: 1, MaxInterval: time.Second},
+		{InitialInterval: time.Second, Multiplier: 1, MaxInterval: time.Second, Jitter: 1.5},
+		{InitialInterval: time.Second, Multiplier: 1, MaxInterval: time.Second, Jitter: -0.1},
+	} {
+		require.ErrorIs(t, backoff.Validate(), p2p.ErrInvalidReconnectBackoff)
+	}
+}
+
+func TestPeerReconnectConfigBackoff(t *testing.T) {
+	defaultBackoff := p2p.ReconnectBackoff{
+		InitialInterval: 5 * time.Second,
+		Multiplier:      2,
+		MaxInterval:     5 * time.Minute,
+		Jitter:          0.1,
+	}
+
+	backoff, err := (&p2p.PeerReconnectConfig{}).Backoff(defaultBackoff)
+	require.NoError(t, err)
+	require.Equal(t, defaultBackoff, *backoff)
+
+	noJitter := 0.0
+	backoff, err = (&p2p.PeerReconnectConfig{
+		InitialInterval: "1m",
+		Multiplier:      3,
+		MaxInterval:     "1h",
+		Jitter:          &noJitter,
+	}).Backoff(defaultBackoff)
+	require.NoError(t, err)
+	require.Equal(t, p2p.ReconnectBackoff{
+		InitialInterval: time.Minute,
+		Multiplier:      3,
+		MaxInterval:     time.Hour,
+		Jitter:          0,
+	}, *backoff)
+
+	_, err = (&p2p.PeerReconnectConfig{InitialInterval: "soon"}).Backoff(defaultBackoff)
+	require.ErrorIs(t, err, p2p.ErrInvalidReconnectBackoff)
+
+	// the initial interval must not exceed the max interval of the node
+	_, err = (&p2p.PeerReconnectConfig{InitialInterval: "10m"}).Backoff(defaultBackoff)
+	require.ErrorIs(t, err, p2p.ErrInvalidReconnectBackoff)
+}
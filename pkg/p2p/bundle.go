@@ -0,0 +1,240 @@
+package p2p
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	// PeeringBundleVersion is the version of the peering bundle format.
+	PeeringBundleVersion = 1
+
+	// PeeringBundleMaxClockDrift is the maximum time the timestamp of a peering bundle may be ahead of the local clock.
+	PeeringBundleMaxClockDrift = time.Minute
+)
+
+var (
+	// ErrPeeringBundleInvalidVersion is returned if the version of a peering bundle is not supported.
+	ErrPeeringBundleInvalidVersion = errors.New("unsupported peering bundle version")
+	// ErrPeeringBundleInvalidSignature is returned if the signature of a peering bundle is invalid.
+	ErrPeeringBundleInvalidSignature = errors.New("invalid peering bundle signature")
+	// ErrPeeringBundleUntrustedIssuer is returned if a peering bundle was issued by an untrusted node.
+	ErrPeeringBundleUntrustedIssuer = errors.New("untrusted peering bundle issuer")
+	// ErrPeeringBundleOutdated is returned if a peering bundle is not newer than the last imported one of the issuer or too old.
+	ErrPeeringBundleOutdated = errors.New("outdated peering bundle")
+	// ErrPeeringBundleInvalidTimestamp is returned if the timestamp of a peering bundle is in the future.
+	ErrPeeringBundleInvalidTimestamp = errors.New("invalid peering bundle timestamp")
+)
+
+// PeeringBundleIPFilter holds the subnets of the IP filter in a peering bundle.
+type PeeringBundleIPFilter struct {
+	// The subnets from which incoming connections are accepted.
+	Allow []string `json:"allow"`
+	// The subnets from which incoming connections are rejected.
+	Deny []string `json:"deny"`
+}
+
+// PeeringBundle is a signed export of the peering configuration of a node,
+// which can be imported by other nodes.
+type PeeringBundle struct {
+	// The version of the bundle format.
+	Version int `json:"version"`
+	// The peer ID of the node which issued the bundle.
+	Issuer string `json:"issuer"`
+	// The unix timestamp at which the bundle was issued.
+	Timestamp int64 `json:"timestamp"`
+	// The statically configured peers.
+	Peers []*PeerConfig `json:"peers"`
+	// The subnets of the IP filter.
+	IPFilter *PeeringBundleIPFilter `json:"ipFilter"`
+	// The hex encoded signature of the issuer over the bundle without the signature.
+	Signature string `json:"signature,omitempty"`
+}
+
+// NewPeeringBundle creates a new peering bundle and signs it with the given private key.
+func NewPeeringBundle(privKey crypto.PrivKey, peers []*PeerConfig, allowSubnets []string, denySubnets []string) (*PeeringBundle, error) {
+	issuer, err := peer.IDFromPrivateKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if peers == nil {
+		peers = []*PeerConfig{}
+	}
+	if allowSubnets == nil {
+		allowSubnets = []string{}
+	}
+	if denySubnets == nil {
+		denySubnets = []string{}
+	}
+
+	bundle := &PeeringBundle{
+		Version:   PeeringBundleVersion,
+		Issuer:    issuer.String(),
+		Timestamp: time.Now().Unix(),
+		Peers:     peers,
+		IPFilter: &PeeringBundleIPFilter{
+			Allow: allowSubnets,
+			Deny:  denySubnets,
+		},
+	}
+
+	signingMessage, err := bundle.signingMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := privKey.Sign(signingMessage)
+	if err != nil {
+		return nil, err
+	}
+	bundle.Signature = hex.EncodeToString(signature)
+
+	return bundle, nil
+}
+
+// signingMessage returns the serialized bundle without the signature.
+func (b *PeeringBundle) signingMessage() ([]byte, error) {
+	unsigned := *b
+	unsigned.Signature = ""
+	return json.Marshal(&unsigned)
+}
+
+// Verify checks the version and the signature of the bundle and
+// whether the issuer is one of the given trusted peers.
+// The timestamp is not checked, use a PeeringBundleVerifier to reject replayed bundles.
+func (b *PeeringBundle) Verify(trustedIssuers ...peer.ID) error {
+	if b.Version != PeeringBundleVersion {
+		return errors.WithMessagef(ErrPeeringBundleInvalidVersion, "%d", b.Version)
+	}
+
+	issuer, err := peer.Decode(b.Issuer)
+	if err != nil {
+		return errors.WithMessagef(ErrPeeringBundleInvalidSignature, "invalid issuer: %s", err)
+	}
+
+	trusted := false
+	for _, trustedIssuer := range trustedIssuers {
+		if issuer == trustedIssuer {
+			trusted = true
+			break
+		}
+	}
+	if !trusted {
+		return errors.WithMessagef(ErrPeeringBundleUntrustedIssuer, "%s", issuer)
+	}
+
+	pubKey, err := issuer.ExtractPublicKey()
+	if err != nil {
+		return errors.WithMessagef(ErrPeeringBundleInvalidSignature, "unable to extract public key of issuer: %s", err)
+	}
+
+	signature, err := hex.DecodeString(b.Signature)
+	if err != nil {
+		return errors.WithMessagef(ErrPeeringBundleInvalidSignature, "invalid encoding: %s", err)
+	}
+
+	signingMessage, err := b.signingMessage()
+	if err != nil {
+		return err
+	}
+
+	valid, err := pubKey.Verify(signingMessage, signature)
+	if err != nil {
+		return errors.WithMessage(ErrPeeringBundleInvalidSignature, err.Error())
+	}
+	if !valid {
+		return ErrPeeringBundleInvalidSignature
+	}
+
+	return nil
+}
+
+// PeeringBundleVerifier verifies peering bundles of trusted issuers
+// and rejects bundles which are replayed or older than the maximum age.
+type PeeringBundleVerifier struct {
+	// the peer IDs of the nodes whose peering bundles are accepted.
+	trustedIssuers []peer.ID
+	// the maximum age of accepted peering bundles.
+	maxAge time.Duration
+	// the timestamps of the last imported peering bundles per issuer.
+	lastTimestamps map[peer.ID]int64
+	// mutex to secure lastTimestamps and to serialize the imports.
+	lastTimestampsLock sync.Mutex
+}
+
+// NewPeeringBundleVerifier creates a new PeeringBundleVerifier which accepts bundles of the given trusted issuers.
+// Bundles older than maxAge are rejected, 0 disables the check.
+func NewPeeringBundleVerifier(maxAge time.Duration, trustedIssuers ...peer.ID) *PeeringBundleVerifier {
+	return &PeeringBundleVerifier{
+		trustedIssuers: trustedIssuers,
+		maxAge:         maxAge,
+		lastTimestamps: make(map[peer.ID]int64),
+	}
+}
+
+// Verify verifies the bundle and checks whether it is newer than the last imported bundle of the issuer.
+func (v *PeeringBundleVerifier) Verify(b *PeeringBundle) error {
+	v.lastTimestampsLock.Lock()
+	defer v.lastTimestampsLock.Unlock()
+
+	_, err := v.verify(b)
+	return err
+}
+
+// Import verifies the bundle and calls importFunc to apply it.
+// The timestamp of the bundle is only remembered if importFunc succeeded, so the same bundle
+// or an older one can't be imported again, but a bundle whose import failed can be retried.
+// The imports are serialized, so a bundle can't be imported twice concurrently.
+// The timestamps are not persisted, so after a restart only the maximum age prevents replays.
+func (v *PeeringBundleVerifier) Import(b *PeeringBundle, importFunc func() error) error {
+	v.lastTimestampsLock.Lock()
+	defer v.lastTimestampsLock.Unlock()
+
+	issuer, err := v.verify(b)
+	if err != nil {
+		return err
+	}
+
+	if err := importFunc(); err != nil {
+		return err
+	}
+	v.lastTimestamps[issuer] = b.Timestamp
+
+	return nil
+}
+
+// verify verifies the bundle and returns its issuer.
+// lastTimestampsLock has to be held by the caller.
+func (v *PeeringBundleVerifier) verify(b *PeeringBundle) (peer.ID, error) {
+	if err := b.Verify(v.trustedIssuers...); err != nil {
+		return "", err
+	}
+
+	// the issuer was already decoded successfully by the verification of the bundle
+	issuer, err := peer.Decode(b.Issuer)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	issued := time.Unix(b.Timestamp, 0)
+	if issued.After(now.Add(PeeringBundleMaxClockDrift)) {
+		return "", errors.WithMessagef(ErrPeeringBundleInvalidTimestamp, "issued in the future: %s", issued.Format(time.RFC3339))
+	}
+	if v.maxAge > 0 && now.Sub(issued) > v.maxAge {
+		return "", errors.WithMessagef(ErrPeeringBundleOutdated, "issued at %s, older than %s", issued.Format(time.RFC3339), v.maxAge)
+	}
+
+	if lastTimestamp, exists := v.lastTimestamps[issuer]; exists && b.Timestamp <= lastTimestamp {
+		return "", errors.WithMessagef(ErrPeeringBundleOutdated, "issued at %s, not newer than the last imported bundle issued at %s", issued.Format(time.RFC3339), time.Unix(lastTimestamp, 0).Format(time.RFC3339))
+	}
+
+	return issuer, nil
+}
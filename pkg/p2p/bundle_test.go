@@ -0,0 +1,133 @@
+package p2p_test
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/p2p"
+)
+
+func TestPeeringBundle(t *testing.T) {
+
+	privKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	issuer, err := peer.IDFromPrivateKey(privKey)
+	require.NoError(t, err)
+
+	otherPrivKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	otherIssuer, err := peer.IDFromPrivateKey(otherPrivKey)
+	require.NoError(t, err)
+
+	peers := []*p2p.PeerConfig{
+		{MultiAddress: "/ip4/127.0.0.1/tcp/15600/p2p/12D3KooWCKwcTWevoRKa2kEBputeGASvEBuDfRDSbe8t1DWugUmL", Alias: "node1"},
+	}
+
+	bundle, err := p2p.NewPeeringBundle(privKey, peers, []string{"192.168.0.0/16"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, issuer.String(), bundle.Issuer)
+	require.NotNil(t, bundle.IPFilter.Deny)
+
+	require.NoError(t, bundle.Verify(otherIssuer, issuer))
+	require.ErrorIs(t, bundle.Verify(otherIssuer), p2p.ErrPeeringBundleUntrustedIssuer)
+
+	// modifications invalidate the signature
+	bundle.IPFilter.Deny = []string{"10.0.0.0/8"}
+	require.ErrorIs(t, bundle.Verify(issuer), p2p.ErrPeeringBundleInvalidSignature)
+
+	// a bundle re-signed by another node doesn't verify with the original issuer
+	forged, err := p2p.NewPeeringBundle(otherPrivKey, peers, nil, nil)
+	require.NoError(t, err)
+	forged.Issuer = issuer.String()
+	require.ErrorIs(t, forged.Verify(issuer), p2p.ErrPeeringBundleInvalidSignature)
+
+	bundle.Version = 2
+	require.ErrorIs(t, bundle.Verify(issuer), p2p.ErrPeeringBundleInvalidVersion)
+}
+
+// signPeeringBundle sets the timestamp of the bundle and signs it again.
+func signPeeringBundle(t *testing.T, privKey crypto.PrivKey, bundle *p2p.PeeringBundle, timestamp time.Time) *p2p.PeeringBundle {
+	signed := *bundle
+	signed.Timestamp = timestamp.Unix()
+	signed.Signature = ""
+
+	signingMessage, err := json.Marshal(&signed)
+	require.NoError(t, err)
+
+	signature, err := privKey.Sign(signingMessage)
+	require.NoError(t, err)
+	signed.Signature = hex.EncodeToString(signature)
+
+	return &signed
+}
+
+func TestPeeringBundleVerifier(t *testing.T) {
+
+	privKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	issuer, err := peer.IDFromPrivateKey(privKey)
+	require.NoError(t, err)
+
+	otherPrivKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	otherIssuer, err := peer.IDFromPrivateKey(otherPrivKey)
+	require.NoError(t, err)
+
+	verifier := p2p.NewPeeringBundleVerifier(time.Hour, issuer, otherIssuer)
+
+	bundle, err := p2p.NewPeeringBundle(privKey, nil, nil, nil)
+	require.NoError(t, err)
+	now := time.Unix(bundle.Timestamp, 0)
+
+	noop := func() error { return nil }
+
+	// a bundle is only remembered once it was imported
+	require.NoError(t, verifier.Verify(bundle))
+	require.NoError(t, verifier.Verify(bundle))
+
+	// a failed import doesn't block a retry
+	errImport := errors.New("import failed")
+	require.ErrorIs(t, verifier.Import(bundle, func() error { return errImport }), errImport)
+	require.NoError(t, verifier.Import(bundle, noop))
+
+	// the same bundle can't be imported twice
+	require.ErrorIs(t, verifier.Verify(bundle), p2p.ErrPeeringBundleOutdated)
+	require.ErrorIs(t, verifier.Import(bundle, func() error {
+		require.FailNow(t, "a replayed bundle must not be imported")
+		return nil
+	}), p2p.ErrPeeringBundleOutdated)
+
+	// older bundles of the issuer are rejected
+	require.ErrorIs(t, verifier.Import(signPeeringBundle(t, privKey, bundle, now.Add(-time.Minute)), noop), p2p.ErrPeeringBundleOutdated)
+
+	// newer bundles of the issuer are accepted
+	require.NoError(t, verifier.Import(signPeeringBundle(t, privKey, bundle, now.Add(time.Second)), noop))
+
+	// the timestamps are tracked per issuer
+	otherBundle, err := p2p.NewPeeringBundle(otherPrivKey, nil, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, verifier.Import(signPeeringBundle(t, otherPrivKey, otherBundle, now.Add(-time.Minute)), noop))
+
+	// bundles older than the maximum age are rejected
+	require.ErrorIs(t, verifier.Verify(signPeeringBundle(t, otherPrivKey, otherBundle, now.Add(-2*time.Hour))), p2p.ErrPeeringBundleOutdated)
+
+	// bundles from the future would block all later imports
+	require.ErrorIs(t, verifier.Verify(signPeeringBundle(t, otherPrivKey, otherBundle, now.Add(time.Hour))), p2p.ErrPeeringBundleInvalidTimestamp)
+
+	// the timestamp can't be changed without invalidating the signature
+	tampered := signPeeringBundle(t, privKey, bundle, now.Add(time.Minute))
+	tampered.Timestamp++
+	require.ErrorIs(t, verifier.Verify(tampered), p2p.ErrPeeringBundleInvalidSignature)
+
+	// the maximum age can be disabled
+	verifier = p2p.NewPeeringBundleVerifier(0, issuer)
+	require.NoError(t, verifier.Verify(signPeeringBundle(t, privKey, bundle, now.Add(-48*time.Hour))))
+}
@@ -0,0 +1,216 @@
+package p2p
+
+import (
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// ProtocolViolation is a kind of misbehavior of a peer in the gossip protocol.
+type ProtocolViolation string
+
+const (
+	// ViolationMalformedPacket is a packet that couldn't be parsed, e.g. because of an unknown message type.
+	ViolationMalformedPacket ProtocolViolation = "malformedPacket"
+	// ViolationOversizedPacket is a packet that advertises an invalid length for its message type, e.g. above the maximum.
+	ViolationOversizedPacket ProtocolViolation = "oversizedPacket"
+	// ViolationInvalidNetworkID is a message of a different network.
+	ViolationInvalidNetworkID ProtocolViolation = "invalidNetworkId"
+	// ViolationInvalidMessage is a message that failed the syntactic validation.
+	ViolationInvalidMessage ProtocolViolation = "invalidMessage"
+	// ViolationInsufficientPoW is a message with a PoW score below the minimum.
+	ViolationInsufficientPoW ProtocolViolation = "insufficientPoW"
+)
+
+// PeerBan holds the information about a peer which was banned by the CircuitBreaker.
+type PeerBan struct {
+	// The ID of the banned peer.
+	ID peer.ID
+	// The IP addresses the peer was connected from.
+	IPs []net.IP
+	// The violations within the window that led to the ban, by kind.
+	Violations map[ProtocolViolation]int
+	// The time the peer was banned.
+	BannedAt time.Time
+	// The time until the peer is banned.
+	Until time.Time
+}
+
+// the time a violation of a peer was reported.
+type violationRecord struct {
+	violation ProtocolViolation
+	time      time.Time
+}
+
+// CircuitBreaker counts the protocol violations of peers and bans the identity and
+// the IP addresses of a peer for a while if it exceeds the threshold within the window.
+type CircuitBreaker struct {
+	// the amount of violations within the window after which a peer is banned.
+	threshold int
+	// the duration in which the violations of a peer are counted.
+	window time.Duration
+	// the duration a peer is banned.
+	banDuration time.Duration
+
+	sync.RWMutex
+	// the recent violations of peers which are not banned.
+	violations map[peer.ID][]*violationRecord
+	// the active bans.
+	bans map[peer.ID]*PeerBan
+}
+
+// NewCircuitBreaker creates a new CircuitBreaker which bans a peer for banDuration
+// if it caused at least threshold violations within the window.
+func NewCircuitBreaker(threshold int, window time.Duration, banDuration time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	return &CircuitBreaker{
+		threshold:   threshold,
+		window:      window,
+		banDuration: banDuration,
+		violations:  make(map[peer.ID][]*violationRecord),
+		bans:        make(map[peer.ID]*PeerBan),
+	}
+}
+
+// ReportViolation adds a violation of the given peer which is connected from the given IP addresses.
+// It returns the ban if the peer got banned because of this violation, or nil otherwise.
+func (cb *CircuitBreaker) ReportViolation(peerID peer.ID, ips []net.IP, violation ProtocolViolation) *PeerBan {
+	cb.Lock()
+	defer cb.Unlock()
+
+	now := time.Now()
+	cb.removeExpiredWithoutLocking(now)
+
+	if _, banned := cb.bans[peerID]; banned {
+		// the connection was not closed yet
+		return nil
+	}
+
+	// only keep the violations within the window
+	records := cb.violations[peerID]
+	recent := records[:0]
+	for _, record := range records {
+		if now.Sub(record.time) < cb.window {
+			recent = append(recent, record)
+		}
+	}
+	recent = append(recent, &violationRecord{violation: violation, time: now})
+
+	if len(recent) < cb.threshold {
+		cb.violations[peerID] = recent
+		return nil
+	}
+	delete(cb.violations, peerID)
+
+	ban := &PeerBan{
+		ID:         peerID,
+		IPs:        ips,
+		Violations: make(map[ProtocolViolation]int),
+		BannedAt:   now,
+		Until:      now.Add(cb.banDuration),
+	}
+	for _, record := range recent {
+		ban.Violations[record.violation]++
+	}
+	cb.bans[peerID] = ban
+
+	return ban
+}
+
+// removeExpiredWithoutLocking removes the bans which are expired and the violations which are outside of the window.
+// write lock must be acquired outside.
+func (cb *CircuitBreaker) removeExpiredWithoutLocking(now time.Time) {
+	for peerID, ban := range cb.bans {
+		if !now.Before(ban.Until) {
+			delete(cb.bans, peerID)
+		}
+	}
+
+	for peerID, records := range cb.violations {
+		if now.Sub(records[len(records)-1].time) >= cb.window {
+			delete(cb.violations, peerID)
+		}
+	}
+}
+
+// IsPeerBanned tells whether the given peer is banned.
+func (cb *CircuitBreaker) IsPeerBanned(peerID peer.ID) bool {
+	cb.RLock()
+	defer cb.RUnlock()
+
+	ban, exists := cb.bans[peerID]
+	return exists && time.Now().Before(ban.Until)
+}
+
+// IsIPBanned tells whether a banned peer was connected from the given IP address.
+func (cb *CircuitBreaker) IsIPBanned(ip net.IP) bool {
+	cb.RLock()
+	defer cb.RUnlock()
+
+	now := time.Now()
+	for _, ban := range cb.bans {
+		if !now.Before(ban.Until) {
+			continue
+		}
+
+		for _, bannedIP := range ban.IPs {
+			if bannedIP.Equal(ip) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Bans returns the active bans, ordered by the time the peers were banned.
+func (cb *CircuitBreaker) Bans() []*PeerBan {
+	cb.Lock()
+	defer cb.Unlock()
+
+	cb.removeExpiredWithoutLocking(time.Now())
+
+	bans := make([]*PeerBan, 0, len(cb.bans))
+	for _, ban := range cb.bans {
+		bans = append(bans, ban)
+	}
+
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].BannedAt.Before(bans[j].BannedAt)
+	})
+
+	return bans
+}
+
+// Unban removes the ban of the given peer.
+// It returns whether the peer was banned.
+func (cb *CircuitBreaker) Unban(peerID peer.ID) bool {
+	cb.Lock()
+	defer cb.Unlock()
+
+	_, exists := cb.bans[peerID]
+	delete(cb.bans, peerID)
+
+	return exists
+}
+
+// ClearBans removes all bans and the recorded violations.
+// It returns the amount of removed bans.
+func (cb *CircuitBreaker) ClearBans() int {
+	cb.Lock()
+	defer cb.Unlock()
+
+	cb.removeExpiredWithoutLocking(time.Now())
+	count := len(cb.bans)
+
+	cb.bans = make(map[peer.ID]*PeerBan)
+	cb.violations = make(map[peer.ID][]*violationRecord)
+
+	return count
+}
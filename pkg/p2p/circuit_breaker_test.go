@@ -0,0 +1,80 @@
+package p2p_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/p2p"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+
+	peerID1, err := peer.Decode("12D3KooWCKwcTWevoRKa2kEBputeGASvEBuDfRDSbe8t1DWugUmL")
+	require.NoError(t, err)
+	peerID2, err := peer.Decode("12D3KooWHjtwngHqXDFBw4iLUKmfbdEFeLKsXPuZe7yA2tUVUqFg")
+	require.NoError(t, err)
+
+	ip1 := net.ParseIP("192.0.2.1")
+	ip2 := net.ParseIP("192.0.2.2")
+
+	circuitBreaker := p2p.NewCircuitBreaker(3, time.Minute, time.Hour)
+
+	require.Nil(t, circuitBreaker.ReportViolation(peerID1, []net.IP{ip1}, p2p.ViolationMalformedPacket))
+	require.Nil(t, circuitBreaker.ReportViolation(peerID1, []net.IP{ip1}, p2p.ViolationInvalidNetworkID))
+	require.Nil(t, circuitBreaker.ReportViolation(peerID2, []net.IP{ip2}, p2p.ViolationMalformedPacket))
+	require.False(t, circuitBreaker.IsPeerBanned(peerID1))
+
+	ban := circuitBreaker.ReportViolation(peerID1, []net.IP{ip1}, p2p.ViolationInvalidNetworkID)
+	require.NotNil(t, ban)
+	require.Equal(t, peerID1, ban.ID)
+	require.Equal(t, map[p2p.ProtocolViolation]int{
+		p2p.ViolationMalformedPacket:  1,
+		p2p.ViolationInvalidNetworkID: 2,
+	}, ban.Violations)
+	require.Equal(t, time.Hour, ban.Until.Sub(ban.BannedAt))
+
+	// further violations of a banned peer don't create a new ban
+	require.Nil(t, circuitBreaker.ReportViolation(peerID1, []net.IP{ip1}, p2p.ViolationMalformedPacket))
+
+	require.True(t, circuitBreaker.IsPeerBanned(peerID1))
+	require.True(t, circuitBreaker.IsIPBanned(net.ParseIP("192.0.2.1")))
+	require.False(t, circuitBreaker.IsPeerBanned(peerID2))
+	require.False(t, circuitBreaker.IsIPBanned(ip2))
+	require.Len(t, circuitBreaker.Bans(), 1)
+
+	require.True(t, circuitBreaker.Unban(peerID1))
+	require.False(t, circuitBreaker.Unban(peerID1))
+	require.False(t, circuitBreaker.IsIPBanned(ip1))
+
+	// the violations which led to the first ban are not counted again
+	require.Nil(t, circuitBreaker.ReportViolation(peerID1, []net.IP{ip1}, p2p.ViolationMalformedPacket))
+
+	require.Nil(t, circuitBreaker.ReportViolation(peerID2, []net.IP{ip2}, p2p.ViolationOversizedPacket))
+	require.NotNil(t, circuitBreaker.ReportViolation(peerID2, []net.IP{ip2}, p2p.ViolationOversizedPacket))
+	require.Equal(t, 1, circuitBreaker.ClearBans())
+	require.Empty(t, circuitBreaker.Bans())
+}
+
+func TestCircuitBreakerExpiry(t *testing.T) {
+
+	peerID, err := peer.Decode("12D3KooWCKwcTWevoRKa2kEBputeGASvEBuDfRDSbe8t1DWugUmL")
+	require.NoError(t, err)
+
+	circuitBreaker := p2p.NewCircuitBreaker(2, 50*time.Millisecond, 50*time.Millisecond)
+
+	// violations outside of the window are not counted
+	require.Nil(t, circuitBreaker.ReportViolation(peerID, nil, p2p.ViolationMalformedPacket))
+	time.Sleep(60 * time.Millisecond)
+	require.Nil(t, circuitBreaker.ReportViolation(peerID, nil, p2p.ViolationMalformedPacket))
+
+	require.NotNil(t, circuitBreaker.ReportViolation(peerID, nil, p2p.ViolationMalformedPacket))
+	require.True(t, circuitBreaker.IsPeerBanned(peerID))
+
+	time.Sleep(60 * time.Millisecond)
+	require.False(t, circuitBreaker.IsPeerBanned(peerID))
+	require.Empty(t, circuitBreaker.Bans())
+}
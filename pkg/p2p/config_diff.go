@@ -0,0 +1,126 @@
+package p2p
+
+import (
+	"math"
+	"reflect"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidPeerConfig is returned if an entry of a peering config is invalid.
+	ErrInvalidPeerConfig = errors.New("invalid peer config")
+)
+
+// PeerConfigChange describes a peer whose configuration differs between two peering configs.
+type PeerConfigChange struct {
+	// The ID of the peer.
+	ID peer.ID
+	// The current configuration of the peer.
+	Old *PeerConfig
+	// The candidate configuration of the peer.
+	New *PeerConfig
+}
+
+// PeerConfigDiff holds the changes a candidate peering config would apply to the current one.
+type PeerConfigDiff struct {
+	// The peers which only exist in the candidate config.
+	Added []*PeerConfig
+	// The peers which exist in both configs, but with different settings.
+	Modified []*PeerConfigChange
+	// The peers which only exist in the current config.
+	Removed []*PeerConfig
+	// The amount of peers which are equal in both configs.
+	Unchanged int
+}
+
+// IsEmpty tells whether the candidate config doesn't change anything.
+func (d *PeerConfigDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Modified) == 0 && len(d.Removed) == 0
+}
+
+// validatePeerReconnectConfig checks the values of the reconnect config which are set.
+func validatePeerReconnectConfig(reconnect *PeerReconnectConfig) error {
+	if reconnect == nil {
+		return nil
+	}
+
+	// the values which are not set are validated with a policy that is always valid
+	_, err := reconnect.Backoff(ReconnectBackoff{InitialInterval: 1, Multiplier: 1, MaxInterval: math.MaxInt64})
+	return err
+}
+
+// peerConfigsEqual tells whether both configs result in the same peering.
+func peerConfigsEqual(a *PeerConfig, b *PeerConfig) bool {
+	transport := func(p *PeerConfig) string {
+		if p.Transport == "" {
+			return PeerTransportTCP
+		}
+		return p.Transport
+	}
+
+	return a.MultiAddress == b.MultiAddress &&
+		a.Alias == b.Alias &&
+		transport(a) == transport(b) &&
+		reflect.DeepEqual(a.Reconnect, b.Reconnect)
+}
+
+// Diff computes the changes the candidate peers would apply to the peers of the config manager, without applying them.
+// Peers are matched by their peer ID. The candidate peers are validated, entries of the
+// current config which are invalid are ignored, the same way as on startup.
+func (pm *ConfigManager) Diff(candidatePeers []*PeerConfig) (*PeerConfigDiff, error) {
+
+	candidates := make(map[peer.ID]*PeerConfig, len(candidatePeers))
+	for i, peerConfig := range candidatePeers {
+		addrInfo, err := peerConfig.AddrInfo()
+		if err != nil {
+			return nil, errors.WithMessagef(ErrInvalidPeerConfig, "peer %d (%s): %s", i, peerConfig.MultiAddress, err)
+		}
+
+		if err := validatePeerReconnectConfig(peerConfig.Reconnect); err != nil {
+			return nil, errors.WithMessagef(ErrInvalidPeerConfig, "peer %d (%s): %s", i, peerConfig.MultiAddress, err)
+		}
+
+		if _, exists := candidates[addrInfo.ID]; exists {
+			return nil, errors.WithMessagef(ErrInvalidPeerConfig, "peer %d (%s): duplicate peer ID %s", i, peerConfig.MultiAddress, addrInfo.ID)
+		}
+		candidates[addrInfo.ID] = peerConfig
+	}
+
+	diff := &PeerConfigDiff{
+		Added:    []*PeerConfig{},
+		Modified: []*PeerConfigChange{},
+		Removed:  []*PeerConfig{},
+	}
+
+	current := make(map[peer.ID]struct{})
+	for _, peerConfig := range pm.Peers() {
+		addrInfo, err := peerConfig.AddrInfo()
+		if err != nil {
+			// ignore wrong values in the config file
+			continue
+		}
+		current[addrInfo.ID] = struct{}{}
+
+		candidate, exists := candidates[addrInfo.ID]
+		switch {
+		case !exists:
+			diff.Removed = append(diff.Removed, peerConfig)
+		case peerConfigsEqual(peerConfig, candidate):
+			diff.Unchanged++
+		default:
+			diff.Modified = append(diff.Modified, &PeerConfigChange{ID: addrInfo.ID, Old: peerConfig, New: candidate})
+		}
+	}
+
+	// keep the order of the candidate config
+	for _, peerConfig := range candidatePeers {
+		addrInfo, _ := peerConfig.AddrInfo()
+		if _, exists := current[addrInfo.ID]; !exists {
+			diff.Added = append(diff.Added, peerConfig)
+		}
+	}
+
+	return diff, nil
+}
@@ -0,0 +1,67 @@
+package p2p_test
+
+import (
+	"testing"
+
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/p2p"
+)
+
+func TestConfigManagerDiff(t *testing.T) {
+
+	const (
+		peerID1 = "12D3KooWCKwcTWevoRKa2kEBputeGASvEBuDfRDSbe8t1DWugUmL"
+		peerID2 = "12D3KooWHjtwngHqXDFBw4iLUKmfbdEFeLKsXPuZe7yA2tUVUqFg"
+		peerID3 = "12D3KooWFJ8Nq6gHLLvigTpPSbyMmLk35k1TcpJof8Y4y8yFAB32"
+	)
+
+	configManager := p2p.NewConfigManager(func([]*p2p.PeerConfig) error { return nil })
+	require.NoError(t, configManager.AddPeer(multiaddr.StringCast("/ip4/192.0.2.1/tcp/15600/p2p/"+peerID1), "peer1", "", nil))
+	require.NoError(t, configManager.AddPeer(multiaddr.StringCast("/ip4/192.0.2.2/tcp/15600/p2p/"+peerID2), "peer2", "", nil))
+
+	diff, err := configManager.Diff(configManager.Peers())
+	require.NoError(t, err)
+	require.True(t, diff.IsEmpty())
+	require.Equal(t, 2, diff.Unchanged)
+
+	diff, err = configManager.Diff([]*p2p.PeerConfig{
+		// an explicit TCP transport equals the default transport
+		{MultiAddress: "/ip4/192.0.2.1/tcp/15600/p2p/" + peerID1, Alias: "peer1", Transport: p2p.PeerTransportTCP},
+		{MultiAddress: "/ip4/192.0.2.3/tcp/15600/p2p/" + peerID3, Alias: "peer3"},
+		{MultiAddress: "/ip4/192.0.2.2/tcp/443/p2p/" + peerID2, Alias: "peer2", Transport: p2p.PeerTransportWebSocket},
+	})
+	require.NoError(t, err)
+	require.False(t, diff.IsEmpty())
+	require.Equal(t, 1, diff.Unchanged)
+	require.Empty(t, diff.Removed)
+
+	require.Len(t, diff.Added, 1)
+	require.Equal(t, "peer3", diff.Added[0].Alias)
+
+	require.Len(t, diff.Modified, 1)
+	require.Equal(t, peerID2, diff.Modified[0].ID.String())
+	require.Equal(t, "/ip4/192.0.2.2/tcp/15600/p2p/"+peerID2, diff.Modified[0].Old.MultiAddress)
+	require.Equal(t, p2p.PeerTransportWebSocket, diff.Modified[0].New.Transport)
+
+	diff, err = configManager.Diff([]*p2p.PeerConfig{})
+	require.NoError(t, err)
+	require.Len(t, diff.Removed, 2)
+
+	// the candidate config is not applied
+	require.Len(t, configManager.Peers(), 2)
+
+	for _, invalid := range [][]*p2p.PeerConfig{
+		{{MultiAddress: "/ip4/192.0.2.1/tcp/15600"}},
+		{{MultiAddress: "/ip4/192.0.2.1/tcp/15600/p2p/" + peerID1, Transport: "udp"}},
+		{{MultiAddress: "/ip4/192.0.2.1/tcp/15600/p2p/" + peerID1, Reconnect: &p2p.PeerReconnectConfig{Multiplier: 0.5}}},
+		{
+			{MultiAddress: "/ip4/192.0.2.1/tcp/15600/p2p/" + peerID1},
+			{MultiAddress: "/ip4/192.0.2.4/tcp/15600/p2p/" + peerID1},
+		},
+	} {
+		_, err := configManager.Diff(invalid)
+		require.ErrorIs(t, err, p2p.ErrInvalidPeerConfig)
+	}
+}
@@ -37,10 +37,20 @@ func (pm *ConfigManager) Peers() []*PeerConfig {
 }
 
 // AddPeer adds a peer to the config manager.
-func (pm *ConfigManager) AddPeer(multiAddress multiaddr.Multiaddr, alias string) error {
+// The transport defines how the node connects to the peer, an empty transport defaults to TCP.
+// The reconnect config is optional and overrides the reconnect backoff policy of the node for this peer.
+func (pm *ConfigManager) AddPeer(multiAddress multiaddr.Multiaddr, alias string, transport string, reconnect *PeerReconnectConfig) error {
 	pm.peersLock.Lock()
 	defer pm.peersLock.Unlock()
 
+	if err := ValidatePeerTransport(transport); err != nil {
+		return err
+	}
+
+	if err := validatePeerReconnectConfig(reconnect); err != nil {
+		return err
+	}
+
 	newPeerAddrInfo, err := peer.AddrInfoFromP2pAddr(multiAddress)
 	if err != nil {
 		return err
@@ -68,6 +78,8 @@ func (pm *ConfigManager) AddPeer(multiAddress multiaddr.Multiaddr, alias string)
 	pm.peers = append(pm.peers, &PeerConfig{
 		MultiAddress: multiAddress.String(),
 		Alias:        alias,
+		Transport:    transport,
+		Reconnect:    reconnect,
 	})
 
 	return pm.store()
@@ -103,6 +115,75 @@ func (pm *ConfigManager) RemovePeer(peerID peer.ID) error {
 	return errors.New("peer not found")
 }
 
+// PeerConfig returns the config of the given peer, or nil if the peer is not in the config.
+func (pm *ConfigManager) PeerConfig(peerID peer.ID) *PeerConfig {
+	pm.peersLock.RLock()
+	defer pm.peersLock.RUnlock()
+
+	for _, p := range pm.peers {
+		multiAddr, err := multiaddr.NewMultiaddr(p.MultiAddress)
+		if err != nil {
+			// ignore wrong values in the config file
+			continue
+		}
+
+		addrInfo, err := peer.AddrInfoFromP2pAddr(multiAddr)
+		if err != nil {
+			// ignore wrong values in the config file
+			continue
+		}
+
+		if addrInfo.ID == peerID {
+			peerConfig := *p
+			return &peerConfig
+		}
+	}
+
+	return nil
+}
+
+// ReplacePeerID replaces the identity of a peer which rotated its identity in the config manager.
+// The address, alias, transport and reconnect config of the peer are kept.
+func (pm *ConfigManager) ReplacePeerID(oldID peer.ID, newID peer.ID) error {
+	pm.peersLock.Lock()
+	defer pm.peersLock.Unlock()
+
+	index := -1
+	for i, p := range pm.peers {
+		multiAddr, err := multiaddr.NewMultiaddr(p.MultiAddress)
+		if err != nil {
+			// ignore wrong values in the config file
+			continue
+		}
+
+		addrInfo, err := peer.AddrInfoFromP2pAddr(multiAddr)
+		if err != nil {
+			// ignore wrong values in the config file
+			continue
+		}
+
+		if addrInfo.ID == newID {
+			return errors.New("peer already exists")
+		}
+
+		if addrInfo.ID == oldID {
+			index = i
+		}
+	}
+
+	if index == -1 {
+		return errors.New("peer not found")
+	}
+
+	rotatedPeer, err := rotatePeerConfig(pm.peers[index], oldID, newID)
+	if err != nil {
+		return err
+	}
+	pm.peers[index] = rotatedPeer
+
+	return pm.store()
+}
+
 // StoreOnChange sets whether storing changes to the config is active or not.
 func (pm *ConfigManager) StoreOnChange(store bool) {
 	pm.storeOnChange = store
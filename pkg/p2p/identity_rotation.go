@@ -0,0 +1,312 @@
+package p2p
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/utils"
+)
+
+const (
+	// NextPrivKeyFileName is the name of the file holding the private key of a staged identity rotation.
+	NextPrivKeyFileName = "identity.key.next"
+	// PreviousPrivKeyFileName is the name of the file holding the private key which was replaced by the last identity rotation.
+	PreviousPrivKeyFileName = "identity.key.previous"
+	// IdentityRotationFileName is the name of the file holding the signed announcement of a staged identity rotation.
+	IdentityRotationFileName = "identity_rotation.json"
+)
+
+var (
+	// ErrIdentityRotationInvalidSignature is returned if a signature of an identity rotation is invalid.
+	ErrIdentityRotationInvalidSignature = errors.New("invalid identity rotation signature")
+	// ErrIdentityRotationAlreadyStaged is returned if an identity rotation is staged while another one is pending.
+	ErrIdentityRotationAlreadyStaged = errors.New("identity rotation already staged")
+	// ErrNoIdentityRotation is returned if no identity rotation is staged.
+	ErrNoIdentityRotation = errors.New("no identity rotation staged")
+)
+
+// IdentityRotation announces that a node replaces its identity.
+// It is signed by both identities, so that peers can trust the new identity
+// with the same confidence as the old one.
+type IdentityRotation struct {
+	// The peer ID which is replaced.
+	OldID string `json:"oldId"`
+	// The peer ID which replaces the old one.
+	NewID string `json:"newId"`
+	// The unix timestamp at which the rotation was staged.
+	Timestamp int64 `json:"timestamp"`
+	// The unix timestamp after which the new identity is activated on the next start of the node.
+	ActivateAfter int64 `json:"activateAfter"`
+	// The hex encoded signature of the old identity over the rotation without the signatures.
+	OldSignature string `json:"oldSignature,omitempty"`
+	// The hex encoded signature of the new identity over the rotation without the signatures.
+	NewSignature string `json:"newSignature,omitempty"`
+}
+
+// NewIdentityRotation creates a new identity rotation from the old to the new private key,
+// which is activated after the grace period, and signs it with both keys.
+func NewIdentityRotation(oldPrivKey crypto.PrivKey, newPrivKey crypto.PrivKey, gracePeriod time.Duration) (*IdentityRotation, error) {
+	oldID, err := peer.IDFromPrivateKey(oldPrivKey)
+	if err != nil {
+		return nil, err
+	}
+
+	newID, err := peer.IDFromPrivateKey(newPrivKey)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	rotation := &IdentityRotation{
+		OldID:         oldID.String(),
+		NewID:         newID.String(),
+		Timestamp:     now.Unix(),
+		ActivateAfter: now.Add(gracePeriod).Unix(),
+	}
+
+	signingMessage, err := rotation.signingMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	oldSignature, err := oldPrivKey.Sign(signingMessage)
+	if err != nil {
+		return nil, err
+	}
+	rotation.OldSignature = hex.EncodeToString(oldSignature)
+
+	newSignature, err := newPrivKey.Sign(signingMessage)
+	if err != nil {
+		return nil, err
+	}
+	rotation.NewSignature = hex.EncodeToString(newSignature)
+
+	return rotation, nil
+}
+
+// signingMessage returns the serialized rotation without the signatures.
+func (r *IdentityRotation) signingMessage() ([]byte, error) {
+	unsigned := *r
+	unsigned.OldSignature = ""
+	unsigned.NewSignature = ""
+	return json.Marshal(&unsigned)
+}
+
+// IDs returns the decoded old and new peer IDs of the rotation.
+func (r *IdentityRotation) IDs() (peer.ID, peer.ID, error) {
+	oldID, err := peer.Decode(r.OldID)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid old peer ID: %w", err)
+	}
+
+	newID, err := peer.Decode(r.NewID)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid new peer ID: %w", err)
+	}
+
+	return oldID, newID, nil
+}
+
+// ActivationTime returns the time after which the new identity is activated.
+func (r *IdentityRotation) ActivationTime() time.Time {
+	return time.Unix(r.ActivateAfter, 0)
+}
+
+// Verify checks the signatures of both identities.
+func (r *IdentityRotation) Verify() error {
+	oldID, newID, err := r.IDs()
+	if err != nil {
+		return errors.WithMessage(ErrIdentityRotationInvalidSignature, err.Error())
+	}
+
+	if oldID == newID {
+		return errors.WithMessage(ErrIdentityRotationInvalidSignature, "old and new peer ID are equal")
+	}
+
+	signingMessage, err := r.signingMessage()
+	if err != nil {
+		return err
+	}
+
+	verify := func(id peer.ID, signatureHex string) error {
+		pubKey, err := id.ExtractPublicKey()
+		if err != nil {
+			return errors.WithMessagef(ErrIdentityRotationInvalidSignature, "unable to extract public key of %s: %s", id, err)
+		}
+
+		signature, err := hex.DecodeString(signatureHex)
+		if err != nil {
+			return errors.WithMessagef(ErrIdentityRotationInvalidSignature, "invalid encoding: %s", err)
+		}
+
+		valid, err := pubKey.Verify(signingMessage, signature)
+		if err != nil {
+			return errors.WithMessage(ErrIdentityRotationInvalidSignature, err.Error())
+		}
+		if !valid {
+			return errors.WithMessagef(ErrIdentityRotationInvalidSignature, "signature of %s", id)
+		}
+
+		return nil
+	}
+
+	if err := verify(oldID, r.OldSignature); err != nil {
+		return err
+	}
+
+	return verify(newID, r.NewSignature)
+}
+
+// LoadIdentityRotation loads the staged identity rotation from the p2p store folder.
+// It returns ErrNoIdentityRotation if no rotation is staged.
+func LoadIdentityRotation(p2pStorePath string) (*IdentityRotation, error) {
+	data, err := os.ReadFile(filepath.Join(p2pStorePath, IdentityRotationFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoIdentityRotation
+		}
+		return nil, fmt.Errorf("unable to read identity rotation: %w", err)
+	}
+
+	rotation := &IdentityRotation{}
+	if err := json.Unmarshal(data, rotation); err != nil {
+		return nil, fmt.Errorf("unable to parse identity rotation: %w", err)
+	}
+
+	return rotation, nil
+}
+
+// StageIdentityRotation generates a new identity private key and stores it next to the current one
+// in the p2p store folder, together with the signed announcement of the rotation.
+// The new identity is activated by ActivateIdentityRotation once the grace period is over.
+func StageIdentityRotation(p2pStorePath string, currentPrivKey crypto.PrivKey, gracePeriod time.Duration) (*IdentityRotation, error) {
+	if _, err := LoadIdentityRotation(p2pStorePath); !errors.Is(err, ErrNoIdentityRotation) {
+		if err != nil {
+			return nil, err
+		}
+		return nil, ErrIdentityRotationAlreadyStaged
+	}
+
+	newPrivKey, _, err := crypto.GenerateKeyPair(crypto.Ed25519, -1)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate Ed25519 private key for peer identity: %w", err)
+	}
+
+	rotation, err := NewIdentityRotation(currentPrivKey, newPrivKey, gracePeriod)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign identity rotation: %w", err)
+	}
+
+	if err := WriteEd25519PrivateKeyToPEMFile(filepath.Join(p2pStorePath, NextPrivKeyFileName), newPrivKey); err != nil {
+		return nil, fmt.Errorf("unable to store private key file for next peer identity: %w", err)
+	}
+
+	data, err := json.MarshalIndent(rotation, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal identity rotation: %w", err)
+	}
+
+	if err := utils.WriteToFile(filepath.Join(p2pStorePath, IdentityRotationFileName), data, 0660); err != nil {
+		return nil, fmt.Errorf("unable to store identity rotation: %w", err)
+	}
+
+	return rotation, nil
+}
+
+// CancelIdentityRotation removes the staged identity rotation and the private key of the next identity.
+func CancelIdentityRotation(p2pStorePath string) error {
+	if _, err := LoadIdentityRotation(p2pStorePath); err != nil {
+		return err
+	}
+
+	if err := os.Remove(filepath.Join(p2pStorePath, NextPrivKeyFileName)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove private key file for next peer identity: %w", err)
+	}
+
+	if err := os.Remove(filepath.Join(p2pStorePath, IdentityRotationFileName)); err != nil {
+		return fmt.Errorf("unable to remove identity rotation: %w", err)
+	}
+
+	return nil
+}
+
+// ActivateIdentityRotation replaces the identity private key with the private key of the staged
+// identity rotation, if its grace period is over. The replaced private key is kept as a backup.
+// It returns the activated rotation, or nil if no rotation is staged or the grace period is not over yet.
+func ActivateIdentityRotation(p2pStorePath string) (*IdentityRotation, error) {
+	rotation, err := LoadIdentityRotation(p2pStorePath)
+	if err != nil {
+		if errors.Is(err, ErrNoIdentityRotation) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if time.Now().Before(rotation.ActivationTime()) {
+		return nil, nil
+	}
+
+	privKeyFilePath := filepath.Join(p2pStorePath, PrivKeyFileName)
+	nextPrivKeyFilePath := filepath.Join(p2pStorePath, NextPrivKeyFileName)
+
+	// make sure the staged key belongs to the announced identity before replacing the current one
+	nextPrivKey, err := ReadEd25519PrivateKeyFromPEMFile(nextPrivKeyFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load private key for next peer identity: %w", err)
+	}
+
+	nextID, err := peer.IDFromPrivateKey(nextPrivKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if nextID.String() != rotation.NewID {
+		return nil, fmt.Errorf("private key for next peer identity (%s) doesn't match the identity rotation (%s)", nextID, rotation.NewID)
+	}
+
+	if err := os.Rename(privKeyFilePath, filepath.Join(p2pStorePath, PreviousPrivKeyFileName)); err != nil {
+		return nil, fmt.Errorf("unable to back up private key for peer identity: %w", err)
+	}
+
+	if err := os.Rename(nextPrivKeyFilePath, privKeyFilePath); err != nil {
+		return nil, fmt.Errorf("unable to activate private key for next peer identity: %w", err)
+	}
+
+	if err := os.Remove(filepath.Join(p2pStorePath, IdentityRotationFileName)); err != nil {
+		return nil, fmt.Errorf("unable to remove identity rotation: %w", err)
+	}
+
+	return rotation, nil
+}
+
+// rotatePeerConfig returns a copy of the given peer config with the peer ID in the multi address replaced.
+func rotatePeerConfig(peerConfig *PeerConfig, oldID peer.ID, newID peer.ID) (*PeerConfig, error) {
+	multiAddr, err := multiaddr.NewMultiaddr(peerConfig.MultiAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	oldP2PComponent, err := multiaddr.NewComponent(multiaddr.ProtocolWithCode(multiaddr.P_P2P).Name, oldID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	newP2PComponent, err := multiaddr.NewComponent(multiaddr.ProtocolWithCode(multiaddr.P_P2P).Name, newID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	rotated := *peerConfig
+	rotated.MultiAddress = multiAddr.Decapsulate(oldP2PComponent).Encapsulate(newP2PComponent).String()
+
+	return &rotated, nil
+}
@@ -0,0 +1,151 @@
+package p2p_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/p2p"
+)
+
+func TestIdentityRotationVerify(t *testing.T) {
+	oldPrivKey, _, err := crypto.GenerateKeyPair(crypto.Ed25519, -1)
+	require.NoError(t, err)
+	newPrivKey, _, err := crypto.GenerateKeyPair(crypto.Ed25519, -1)
+	require.NoError(t, err)
+
+	rotation, err := p2p.NewIdentityRotation(oldPrivKey, newPrivKey, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, rotation.Verify())
+
+	oldID, newID, err := rotation.IDs()
+	require.NoError(t, err)
+	require.True(t, oldID.MatchesPrivateKey(oldPrivKey))
+	require.True(t, newID.MatchesPrivateKey(newPrivKey))
+
+	// the rotation can't be redirected to another identity
+	otherPrivKey, _, err := crypto.GenerateKeyPair(crypto.Ed25519, -1)
+	require.NoError(t, err)
+	otherID, err := peer.IDFromPrivateKey(otherPrivKey)
+	require.NoError(t, err)
+
+	tampered := *rotation
+	tampered.NewID = otherID.String()
+	require.ErrorIs(t, tampered.Verify(), p2p.ErrIdentityRotationInvalidSignature)
+
+	// the activation can't be moved
+	tampered = *rotation
+	tampered.ActivateAfter++
+	require.ErrorIs(t, tampered.Verify(), p2p.ErrIdentityRotationInvalidSignature)
+
+	// both identities have to sign
+	tampered = *rotation
+	tampered.NewSignature = ""
+	require.ErrorIs(t, tampered.Verify(), p2p.ErrIdentityRotationInvalidSignature)
+}
+
+func TestIdentityRotationStageAndActivate(t *testing.T) {
+	p2pStorePath := t.TempDir()
+
+	privKey, newlyCreated, err := p2p.LoadOrCreateIdentityPrivateKey(p2pStorePath, "")
+	require.NoError(t, err)
+	require.True(t, newlyCreated)
+
+	_, err = p2p.LoadIdentityRotation(p2pStorePath)
+	require.ErrorIs(t, err, p2p.ErrNoIdentityRotation)
+
+	rotation, err := p2p.StageIdentityRotation(p2pStorePath, privKey, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, rotation.Verify())
+
+	_, err = p2p.StageIdentityRotation(p2pStorePath, privKey, time.Hour)
+	require.ErrorIs(t, err, p2p.ErrIdentityRotationAlreadyStaged)
+
+	loaded, err := p2p.LoadIdentityRotation(p2pStorePath)
+	require.NoError(t, err)
+	require.Equal(t, rotation, loaded)
+
+	// the grace period is not over yet
+	activated, err := p2p.ActivateIdentityRotation(p2pStorePath)
+	require.NoError(t, err)
+	require.Nil(t, activated)
+
+	require.NoError(t, p2p.CancelIdentityRotation(p2pStorePath))
+	require.ErrorIs(t, p2p.CancelIdentityRotation(p2pStorePath), p2p.ErrNoIdentityRotation)
+	_, err = os.Stat(filepath.Join(p2pStorePath, p2p.NextPrivKeyFileName))
+	require.True(t, os.IsNotExist(err))
+
+	rotation, err = p2p.StageIdentityRotation(p2pStorePath, privKey, 0)
+	require.NoError(t, err)
+
+	activated, err = p2p.ActivateIdentityRotation(p2pStorePath)
+	require.NoError(t, err)
+	require.Equal(t, rotation, activated)
+
+	_, err = p2p.LoadIdentityRotation(p2pStorePath)
+	require.ErrorIs(t, err, p2p.ErrNoIdentityRotation)
+
+	// the new identity is loaded on startup
+	newPrivKey, newlyCreated, err := p2p.LoadOrCreateIdentityPrivateKey(p2pStorePath, "")
+	require.NoError(t, err)
+	require.False(t, newlyCreated)
+
+	newID, err := peer.IDFromPrivateKey(newPrivKey)
+	require.NoError(t, err)
+	require.Equal(t, rotation.NewID, newID.String())
+
+	// the old identity is kept as a backup
+	previousPrivKey, err := p2p.ReadEd25519PrivateKeyFromPEMFile(filepath.Join(p2pStorePath, p2p.PreviousPrivKeyFileName))
+	require.NoError(t, err)
+	require.True(t, previousPrivKey.Equals(privKey))
+}
+
+func TestConfigManagerReplacePeerID(t *testing.T) {
+
+	const (
+		peerID1 = "12D3KooWCKwcTWevoRKa2kEBputeGASvEBuDfRDSbe8t1DWugUmL"
+		peerID2 = "12D3KooWHjtwngHqXDFBw4iLUKmfbdEFeLKsXPuZe7yA2tUVUqFg"
+		peerID3 = "12D3KooWFJ8Nq6gHLLvigTpPSbyMmLk35k1TcpJof8Y4y8yFAB32"
+	)
+
+	var stored []*p2p.PeerConfig
+	configManager := p2p.NewConfigManager(func(peers []*p2p.PeerConfig) error {
+		stored = peers
+		return nil
+	})
+	configManager.StoreOnChange(true)
+
+	reconnect := &p2p.PeerReconnectConfig{Multiplier: 1.5}
+	require.NoError(t, configManager.AddPeer(multiaddr.StringCast("/dns/node1.example.com/tcp/443/p2p/"+peerID1), "peer1", p2p.PeerTransportWebSocket, reconnect))
+	require.NoError(t, configManager.AddPeer(multiaddr.StringCast("/ip4/192.0.2.2/tcp/15600/p2p/"+peerID2), "peer2", "", nil))
+
+	oldID, err := peer.Decode(peerID1)
+	require.NoError(t, err)
+	newID, err := peer.Decode(peerID3)
+	require.NoError(t, err)
+	existingID, err := peer.Decode(peerID2)
+	require.NoError(t, err)
+
+	require.Error(t, configManager.ReplacePeerID(oldID, existingID))
+	require.Error(t, configManager.ReplacePeerID(newID, oldID))
+
+	require.NoError(t, configManager.ReplacePeerID(oldID, newID))
+	require.Nil(t, configManager.PeerConfig(oldID))
+	require.Equal(t, &p2p.PeerConfig{
+		MultiAddress: "/dns/node1.example.com/tcp/443/p2p/" + peerID3,
+		Alias:        "peer1",
+		Transport:    p2p.PeerTransportWebSocket,
+		Reconnect:    reconnect,
+	}, configManager.PeerConfig(newID))
+
+	// the order of the peers is kept
+	require.Len(t, stored, 2)
+	require.Equal(t, "peer1", stored[0].Alias)
+	require.Equal(t, "peer2", stored[1].Alias)
+}
@@ -0,0 +1,307 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/utils"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/logger"
+)
+
+const (
+	// IdentityRotationProtocolID is the protocol used to announce identity rotations to peers.
+	IdentityRotationProtocolID = protocol.ID("/iota/identity-rotation/1.0.0")
+
+	// the timeout for sending or receiving an identity rotation announcement.
+	identityRotationStreamTimeout = 10 * time.Second
+	// the maximum size of an identity rotation announcement.
+	identityRotationMaxSize = 4096
+)
+
+// IdentityRotator stages identity rotations of the node and announces them to its peers
+// during the grace period. It also migrates the static peers which announce a rotation of their identity.
+type IdentityRotator struct {
+	// the logger used to log events.
+	*utils.WrappedLogger
+
+	host          host.Host
+	manager       *Manager
+	configManager *ConfigManager
+	p2pStorePath  string
+	privKey       crypto.PrivKey
+	// the default time a staged identity rotation is announced before it is activated.
+	gracePeriod time.Duration
+
+	// the staged identity rotation of the node.
+	rotation     *IdentityRotation
+	rotationLock sync.RWMutex
+
+	// the old identities of static peers which announced a rotation, by their new identity.
+	rotatedPeers     map[peer.ID]peer.ID
+	rotatedPeersLock sync.Mutex
+}
+
+// NewIdentityRotator creates a new IdentityRotator and loads the identity rotation
+// which is staged in the p2p store folder, if any.
+func NewIdentityRotator(host host.Host, manager *Manager, configManager *ConfigManager, p2pStorePath string, privKey crypto.PrivKey, gracePeriod time.Duration, log *logger.Logger) (*IdentityRotator, error) {
+	rotation, err := LoadIdentityRotation(p2pStorePath)
+	if err != nil && !errors.Is(err, ErrNoIdentityRotation) {
+		return nil, err
+	}
+
+	return &IdentityRotator{
+		WrappedLogger: utils.NewWrappedLogger(log),
+		host:          host,
+		manager:       manager,
+		configManager: configManager,
+		p2pStorePath:  p2pStorePath,
+		privKey:       privKey,
+		gracePeriod:   gracePeriod,
+		rotation:      rotation,
+		rotatedPeers:  make(map[peer.ID]peer.ID),
+	}, nil
+}
+
+// GracePeriod returns the default time a staged identity rotation is announced before it is activated.
+func (r *IdentityRotator) GracePeriod() time.Duration {
+	return r.gracePeriod
+}
+
+// Rotation returns the staged identity rotation of the node, or nil if none is staged.
+func (r *IdentityRotator) Rotation() *IdentityRotation {
+	r.rotationLock.RLock()
+	defer r.rotationLock.RUnlock()
+
+	return r.rotation
+}
+
+// Stage generates a new identity for the node which replaces the current one
+// on the next start of the node after the grace period, and announces it to the connected peers.
+func (r *IdentityRotator) Stage(gracePeriod time.Duration) (*IdentityRotation, error) {
+	r.rotationLock.Lock()
+	defer r.rotationLock.Unlock()
+
+	if r.rotation != nil {
+		return nil, ErrIdentityRotationAlreadyStaged
+	}
+
+	rotation, err := StageIdentityRotation(r.p2pStorePath, r.privKey, gracePeriod)
+	if err != nil {
+		return nil, err
+	}
+	r.rotation = rotation
+
+	go r.announceToConnectedPeers(context.Background())
+
+	return rotation, nil
+}
+
+// Cancel removes the staged identity rotation of the node.
+// Peers which already received the announcement keep their old peering,
+// since they only replace the identity once the new one connects.
+func (r *IdentityRotator) Cancel() error {
+	r.rotationLock.Lock()
+	defer r.rotationLock.Unlock()
+
+	if r.rotation == nil {
+		return ErrNoIdentityRotation
+	}
+
+	if err := CancelIdentityRotation(r.p2pStorePath); err != nil {
+		return err
+	}
+	r.rotation = nil
+
+	return nil
+}
+
+// Run handles the identity rotation announcements of peers and announces the staged
+// identity rotation of the node to every peer that connects.
+// This method blocks until the given context is done.
+func (r *IdentityRotator) Run(ctx context.Context) {
+	r.host.SetStreamHandler(IdentityRotationProtocolID, r.handleStream)
+	defer r.host.RemoveStreamHandler(IdentityRotationProtocolID)
+
+	// no methods on the Manager must be called from within its event handlers
+	onConnected := events.NewClosure(func(p *Peer, _ network.Conn) {
+		go r.onPeerConnected(ctx, p.ID)
+	})
+	r.manager.Events.Connected.Attach(onConnected)
+	defer r.manager.Events.Connected.Detach(onConnected)
+
+	r.announceToConnectedPeers(ctx)
+
+	<-ctx.Done()
+}
+
+// completes the rotation of a peer whose new identity connected,
+// or announces the staged identity rotation to the peer.
+func (r *IdentityRotator) onPeerConnected(ctx context.Context, peerID peer.ID) {
+	r.rotatedPeersLock.Lock()
+	oldID, rotated := r.rotatedPeers[peerID]
+	delete(r.rotatedPeers, peerID)
+	r.rotatedPeersLock.Unlock()
+
+	if rotated {
+		r.completePeerRotation(oldID, peerID)
+		return
+	}
+
+	if r.Rotation() != nil {
+		r.announce(ctx, peerID)
+	}
+}
+
+// announces the staged identity rotation to all connected peers.
+func (r *IdentityRotator) announceToConnectedPeers(ctx context.Context) {
+	if r.Rotation() == nil {
+		return
+	}
+
+	var peerIDs []peer.ID
+	r.manager.ForEach(func(p *Peer) bool {
+		if r.host.Network().Connectedness(p.ID) == network.Connected {
+			peerIDs = append(peerIDs, p.ID)
+		}
+		return true
+	})
+
+	for _, peerID := range peerIDs {
+		go r.announce(ctx, peerID)
+	}
+}
+
+// sends the staged identity rotation to the given peer.
+func (r *IdentityRotator) announce(ctx context.Context, peerID peer.ID) {
+	rotation := r.Rotation()
+	if rotation == nil {
+		return
+	}
+
+	data, err := json.Marshal(rotation)
+	if err != nil {
+		r.LogWarnf("unable to marshal identity rotation: %s", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, identityRotationStreamTimeout)
+	defer cancel()
+
+	stream, err := r.host.NewStream(ctx, peerID, IdentityRotationProtocolID)
+	if err != nil {
+		// the peer may not support identity rotations
+		r.LogDebugf("unable to announce identity rotation to %s: %s", peerID.ShortString(), err)
+		return
+	}
+
+	_ = stream.SetWriteDeadline(time.Now().Add(identityRotationStreamTimeout))
+	if _, err := stream.Write(data); err != nil {
+		_ = stream.Reset()
+		r.LogDebugf("unable to announce identity rotation to %s: %s", peerID.ShortString(), err)
+		return
+	}
+	_ = stream.Close()
+
+	r.LogInfof("announced identity rotation to %s", peerID.ShortString())
+}
+
+// handles an identity rotation announcement of a peer.
+func (r *IdentityRotator) handleStream(stream network.Stream) {
+	defer func() { _ = stream.Close() }()
+
+	remotePeer := stream.Conn().RemotePeer()
+
+	_ = stream.SetReadDeadline(time.Now().Add(identityRotationStreamTimeout))
+	data, err := io.ReadAll(io.LimitReader(stream, identityRotationMaxSize))
+	if err != nil {
+		r.LogDebugf("unable to read identity rotation of %s: %s", remotePeer.ShortString(), err)
+		return
+	}
+
+	rotation := &IdentityRotation{}
+	if err := json.Unmarshal(data, rotation); err != nil {
+		r.LogWarnf("received invalid identity rotation from %s: %s", remotePeer.ShortString(), err)
+		return
+	}
+
+	if err := rotation.Verify(); err != nil {
+		r.LogWarnf("received invalid identity rotation from %s: %s", remotePeer.ShortString(), err)
+		return
+	}
+
+	oldID, newID, _ := rotation.IDs()
+	if oldID != remotePeer {
+		r.LogWarnf("received identity rotation of %s from %s", oldID.ShortString(), remotePeer.ShortString())
+		return
+	}
+
+	r.prepareRotatedPeer(oldID, newID, rotation)
+}
+
+// adds the new identity of a static peer which announced an identity rotation as known peer,
+// so that the node connects to it as soon as the peer activated the new identity.
+// The peering config is only changed once the new identity connected.
+func (r *IdentityRotator) prepareRotatedPeer(oldID peer.ID, newID peer.ID, rotation *IdentityRotation) {
+	peerConfig := r.configManager.PeerConfig(oldID)
+	if peerConfig == nil {
+		// only the identities of static peers are migrated
+		return
+	}
+
+	rotatedPeerConfig, err := rotatePeerConfig(peerConfig, oldID, newID)
+	if err != nil {
+		r.LogWarnf("unable to migrate peer %s to its new identity %s: %s", oldID.ShortString(), newID.ShortString(), err)
+		return
+	}
+
+	addrInfo, err := rotatedPeerConfig.AddrInfo()
+	if err != nil {
+		r.LogWarnf("unable to migrate peer %s to its new identity %s: %s", oldID.ShortString(), newID.ShortString(), err)
+		return
+	}
+
+	r.rotatedPeersLock.Lock()
+	r.rotatedPeers[newID] = oldID
+	r.rotatedPeersLock.Unlock()
+
+	// take over the addresses the old identity is known under
+	r.host.Peerstore().AddAddrs(newID, r.host.Peerstore().Addrs(oldID), peerstore.PermanentAddrTTL)
+
+	if err := r.manager.ApplyPeerReconnectConfig(newID, rotatedPeerConfig.Reconnect); err != nil {
+		r.LogWarnf("invalid reconnect config for peer (%s), using the default: %s", rotatedPeerConfig.MultiAddress, err)
+	}
+
+	r.LogInfof("peer %s announced its new identity %s, which is activated after %s", oldID.ShortString(), newID.ShortString(), rotation.ActivationTime().Truncate(time.Second))
+
+	// the connection attempts fail until the peer activated its new identity
+	if err := r.manager.ConnectPeer(addrInfo, PeerRelationKnown, rotatedPeerConfig.Alias); err != nil && !errors.Is(err, ErrPeerInManagerAlready) {
+		r.LogDebugf("can't connect to new identity of peer %s yet: %s", oldID.ShortString(), err)
+	}
+}
+
+// replaces the old identity of a static peer in the peering config
+// after its new identity connected and removes the old identity.
+func (r *IdentityRotator) completePeerRotation(oldID peer.ID, newID peer.ID) {
+	if err := r.configManager.ReplacePeerID(oldID, newID); err != nil {
+		r.LogWarnf("unable to replace identity %s of peer with %s in the peering config: %s", oldID.ShortString(), newID.ShortString(), err)
+	}
+
+	if err := r.manager.DisconnectPeer(oldID); err != nil {
+		r.LogWarnf("unable to remove old identity %s of peer %s: %s", oldID.ShortString(), newID.ShortString(), err)
+	}
+	r.manager.SetReconnectBackoff(oldID, nil)
+
+	r.LogInfof("migrated peer %s to its new identity %s", oldID.ShortString(), newID.ShortString())
+}
@@ -0,0 +1,235 @@
+package p2p
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/libp2p/go-libp2p-core/connmgr"
+	"github.com/libp2p/go-libp2p-core/control"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidSubnet is returned if a subnet of the IP filter can't be parsed.
+	ErrInvalidSubnet = errors.New("invalid subnet")
+)
+
+// IPFilter filters incoming connections by the IP address of the remote peer
+// before any handshake is performed. The subnets can be changed at runtime.
+type IPFilter struct {
+	subnetsLock sync.RWMutex
+	// if not empty, only connections from these subnets are accepted.
+	allow []*net.IPNet
+	// connections from these subnets are always rejected.
+	deny []*net.IPNet
+	// if set, connections from and to banned peers are rejected.
+	circuitBreaker *CircuitBreaker
+}
+
+// the IPFilter is used as a connection gater of the libp2p host.
+var _ connmgr.ConnectionGater = &IPFilter{}
+
+// NewIPFilter creates a new IPFilter with the given allowed and denied subnets in CIDR notation.
+// Single IP addresses are treated as subnets containing only that address.
+func NewIPFilter(allow []string, deny []string) (*IPFilter, error) {
+	f := &IPFilter{}
+	if err := f.SetSubnets(allow, deny); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// parses the given subnets in CIDR notation or single IP addresses.
+func parseSubnets(subnets []string) ([]*net.IPNet, error) {
+	ipNets := make([]*net.IPNet, 0, len(subnets))
+	for _, subnet := range subnets {
+		subnet = strings.TrimSpace(subnet)
+		if len(subnet) == 0 {
+			continue
+		}
+
+		if !strings.Contains(subnet, "/") {
+			ip := net.ParseIP(subnet)
+			if ip == nil {
+				return nil, errors.WithMessagef(ErrInvalidSubnet, "%s", subnet)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				bits = 8 * net.IPv4len
+			}
+			ipNets = append(ipNets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return nil, errors.WithMessagef(ErrInvalidSubnet, "%s: %s", subnet, err)
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+	return ipNets, nil
+}
+
+// ValidateSubnets checks whether the given subnets in CIDR notation or single IP addresses can be used by the filter.
+func ValidateSubnets(subnets []string) error {
+	_, err := parseSubnets(subnets)
+	return err
+}
+
+// SetSubnets replaces the allowed and denied subnets of the filter.
+// Subnets which are not part of the given ones are removed, use AddSubnets to keep them.
+// The filter is left unchanged if any of the subnets is invalid.
+func (f *IPFilter) SetSubnets(allow []string, deny []string) error {
+	allowNets, err := parseSubnets(allow)
+	if err != nil {
+		return err
+	}
+
+	denyNets, err := parseSubnets(deny)
+	if err != nil {
+		return err
+	}
+
+	f.subnetsLock.Lock()
+	defer f.subnetsLock.Unlock()
+
+	f.allow = allowNets
+	f.deny = denyNets
+
+	return nil
+}
+
+// AddSubnets adds the allowed and denied subnets to the current ones of the filter.
+// Subnets which are already part of the filter are not added again.
+// The filter is left unchanged if any of the subnets is invalid.
+func (f *IPFilter) AddSubnets(allow []string, deny []string) error {
+	allowNets, err := parseSubnets(allow)
+	if err != nil {
+		return err
+	}
+
+	denyNets, err := parseSubnets(deny)
+	if err != nil {
+		return err
+	}
+
+	merge := func(ipNets []*net.IPNet, additionalNets []*net.IPNet) []*net.IPNet {
+		known := make(map[string]struct{}, len(ipNets))
+		merged := make([]*net.IPNet, 0, len(ipNets)+len(additionalNets))
+		for _, nets := range [][]*net.IPNet{ipNets, additionalNets} {
+			for _, ipNet := range nets {
+				if _, exists := known[ipNet.String()]; exists {
+					continue
+				}
+				known[ipNet.String()] = struct{}{}
+				merged = append(merged, ipNet)
+			}
+		}
+		return merged
+	}
+
+	f.subnetsLock.Lock()
+	defer f.subnetsLock.Unlock()
+
+	f.allow = merge(f.allow, allowNets)
+	f.deny = merge(f.deny, denyNets)
+
+	return nil
+}
+
+// Subnets returns the allowed and denied subnets of the filter in CIDR notation.
+func (f *IPFilter) Subnets() (allow []string, deny []string) {
+	f.subnetsLock.RLock()
+	defer f.subnetsLock.RUnlock()
+
+	toStrings := func(ipNets []*net.IPNet) []string {
+		subnets := make([]string, len(ipNets))
+		for i, ipNet := range ipNets {
+			subnets[i] = ipNet.String()
+		}
+		return subnets
+	}
+
+	return toStrings(f.allow), toStrings(f.deny)
+}
+
+// SetCircuitBreaker sets the CircuitBreaker whose banned peers and IP addresses are rejected.
+// It must be set before the filter is used by the host.
+func (f *IPFilter) SetCircuitBreaker(circuitBreaker *CircuitBreaker) {
+	f.circuitBreaker = circuitBreaker
+}
+
+// IsAllowed tells whether connections from the given IP address are accepted.
+// Denied subnets take precedence over allowed subnets.
+func (f *IPFilter) IsAllowed(ip net.IP) bool {
+	if f.circuitBreaker != nil && f.circuitBreaker.IsIPBanned(ip) {
+		return false
+	}
+
+	f.subnetsLock.RLock()
+	defer f.subnetsLock.RUnlock()
+
+	for _, ipNet := range f.deny {
+		if ipNet.Contains(ip) {
+			return false
+		}
+	}
+
+	if len(f.allow) == 0 {
+		return true
+	}
+
+	for _, ipNet := range f.allow {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isMultiaddrAllowed tells whether connections from the given multiaddress are accepted.
+// Multiaddresses without an IP address are always accepted.
+func (f *IPFilter) isMultiaddrAllowed(multiAddr multiaddr.Multiaddr) bool {
+	ip, err := manet.ToIP(multiAddr)
+	if err != nil {
+		return true
+	}
+	return f.IsAllowed(ip)
+}
+
+// isPeerBanned tells whether the given peer was banned by the CircuitBreaker.
+func (f *IPFilter) isPeerBanned(peerID peer.ID) bool {
+	return f.circuitBreaker != nil && f.circuitBreaker.IsPeerBanned(peerID)
+}
+
+// InterceptPeerDial allows all outgoing dials to peers which are not banned.
+func (f *IPFilter) InterceptPeerDial(peerID peer.ID) bool {
+	return !f.isPeerBanned(peerID)
+}
+
+// InterceptAddrDial allows all outgoing dials.
+func (f *IPFilter) InterceptAddrDial(_ peer.ID, _ multiaddr.Multiaddr) bool {
+	return true
+}
+
+// InterceptAccept checks the IP address of an incoming connection before the handshake.
+func (f *IPFilter) InterceptAccept(connMultiaddrs network.ConnMultiaddrs) bool {
+	return f.isMultiaddrAllowed(connMultiaddrs.RemoteMultiaddr())
+}
+
+// InterceptSecured rejects connections of banned peers once their identity is known.
+func (f *IPFilter) InterceptSecured(_ network.Direction, peerID peer.ID, _ network.ConnMultiaddrs) bool {
+	return !f.isPeerBanned(peerID)
+}
+
+// InterceptUpgraded allows all connections which passed InterceptAccept.
+func (f *IPFilter) InterceptUpgraded(_ network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
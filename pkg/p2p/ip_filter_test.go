@@ -0,0 +1,57 @@
+package p2p_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/p2p"
+)
+
+func TestIPFilter(t *testing.T) {
+
+	filter, err := p2p.NewIPFilter(nil, nil)
+	require.NoError(t, err)
+
+	// without subnets, all connections are accepted
+	require.True(t, filter.IsAllowed(net.ParseIP("192.168.1.1")))
+	require.True(t, filter.IsAllowed(net.ParseIP("2001:db8::1")))
+
+	// denied subnets take precedence over allowed subnets
+	require.NoError(t, filter.SetSubnets([]string{"192.168.0.0/16", "2001:db8::/32"}, []string{"192.168.1.0/24", "10.0.0.1"}))
+	require.True(t, filter.IsAllowed(net.ParseIP("192.168.2.1")))
+	require.True(t, filter.IsAllowed(net.ParseIP("2001:db8::1")))
+	require.False(t, filter.IsAllowed(net.ParseIP("192.168.1.1")))
+	require.False(t, filter.IsAllowed(net.ParseIP("10.0.0.1")))
+	require.False(t, filter.IsAllowed(net.ParseIP("172.16.0.1")))
+
+	// only denied subnets
+	require.NoError(t, filter.SetSubnets(nil, []string{"10.0.0.0/8"}))
+	require.True(t, filter.IsAllowed(net.ParseIP("192.168.1.1")))
+	require.False(t, filter.IsAllowed(net.ParseIP("10.1.2.3")))
+
+	// invalid subnets leave the filter unchanged
+	require.ErrorIs(t, filter.SetSubnets([]string{"10.0.0.0/33"}, nil), p2p.ErrInvalidSubnet)
+	require.ErrorIs(t, filter.SetSubnets(nil, []string{"not an ip"}), p2p.ErrInvalidSubnet)
+	require.False(t, filter.IsAllowed(net.ParseIP("10.1.2.3")))
+
+	// added subnets are merged with the current ones
+	require.NoError(t, filter.AddSubnets([]string{"192.168.0.0/16"}, []string{"10.0.0.0/8", "172.16.0.0/12"}))
+	allow, deny := filter.Subnets()
+	require.Equal(t, []string{"192.168.0.0/16"}, allow)
+	require.Equal(t, []string{"10.0.0.0/8", "172.16.0.0/12"}, deny)
+	require.False(t, filter.IsAllowed(net.ParseIP("10.1.2.3")))
+	require.False(t, filter.IsAllowed(net.ParseIP("172.16.0.1")))
+	require.True(t, filter.IsAllowed(net.ParseIP("192.168.1.1")))
+
+	require.ErrorIs(t, filter.AddSubnets([]string{"10.0.0.0/33"}, nil), p2p.ErrInvalidSubnet)
+	allow, _ = filter.Subnets()
+	require.Equal(t, []string{"192.168.0.0/16"}, allow)
+
+	_, err = p2p.NewIPFilter([]string{"300.0.0.1"}, nil)
+	require.ErrorIs(t, err, p2p.ErrInvalidSubnet)
+
+	require.NoError(t, p2p.ValidateSubnets([]string{"10.0.0.0/8", "192.168.1.1", "::1"}))
+	require.ErrorIs(t, p2p.ValidateSubnets([]string{"10.0.0.0/33"}), p2p.ErrInvalidSubnet)
+}
@@ -3,13 +3,15 @@ package p2p
 import (
 	"context"
 	"fmt"
-	"math/rand"
+	"net"
+	"sync"
 	"time"
 
 	"github.com/libp2p/go-libp2p-core/host"
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
 	"github.com/pkg/errors"
 
 	"github.com/gohornet/hornet/pkg/utils"
@@ -38,6 +40,10 @@ var (
 	ErrCantAllowItself = errors.New("the host can't allow itself")
 	// ErrPeerInManagerAlreadyAllowed gets returned if a peer is tried to be allowed in the manager which is already allowed.
 	ErrPeerInManagerAlreadyAllowed = errors.New("peer is already allowed in manager")
+	// ErrPeerNotFound gets returned if a peer is not in the manager.
+	ErrPeerNotFound = errors.New("peer not found")
+	// ErrPeerNotKnown gets returned if an action is only supported for peers with relation PeerRelationKnown.
+	ErrPeerNotKnown = errors.New("peer is not a known peer")
 	// ErrManagerShutdown gets returned if the manager is shutting down.
 	ErrManagerShutdown = errors.New("manager is shutting down")
 )
@@ -142,17 +148,22 @@ func PeerRelationCaller(handler interface{}, params ...interface{}) {
 
 // the default options applied to the Manager.
 var defaultManagerOptions = []ManagerOption{
-	WithManagerReconnectInterval(30*time.Second, 1*time.Second),
+	WithManagerReconnectBackoff(ReconnectBackoff{
+		InitialInterval: 5 * time.Second,
+		Multiplier:      2,
+		MaxInterval:     5 * time.Minute,
+		Jitter:          0.1,
+	}),
 }
 
 // ManagerOptions define options for a Manager.
 type ManagerOptions struct {
 	// The logger to use to logger events.
 	logger *logger.Logger
-	// The static reconnect interval.
-	reconnectInterval time.Duration
-	// The randomized jitter applied to the reconnect interval.
-	reconnectIntervalJitter time.Duration
+	// The backoff policy for reconnect attempts to known peers.
+	reconnectBackoff ReconnectBackoff
+	// The circuit breaker which bans peers because of protocol violations.
+	circuitBreaker *CircuitBreaker
 }
 
 // ManagerOption is a function setting a ManagerOptions option.
@@ -165,12 +176,34 @@ func WithManagerLogger(logger *logger.Logger) ManagerOption {
 	}
 }
 
-// WithManagerReconnectInterval defines the re-connect interval for peers
+// WithManagerReconnectInterval defines a static re-connect interval for peers
 // to which the Manager wants to keep a connection open to.
 func WithManagerReconnectInterval(interval time.Duration, jitter time.Duration) ManagerOption {
 	return func(opts *ManagerOptions) {
-		opts.reconnectInterval = interval
-		opts.reconnectIntervalJitter = jitter
+		opts.reconnectBackoff = ReconnectBackoff{
+			InitialInterval: interval,
+			Multiplier:      1,
+			MaxInterval:     interval,
+		}
+		if interval > 0 {
+			opts.reconnectBackoff.Jitter = float64(jitter) / float64(interval)
+		}
+	}
+}
+
+// WithManagerReconnectBackoff defines the backoff policy for re-connect attempts to peers
+// to which the Manager wants to keep a connection open to.
+func WithManagerReconnectBackoff(backoff ReconnectBackoff) ManagerOption {
+	return func(opts *ManagerOptions) {
+		opts.reconnectBackoff = backoff
+	}
+}
+
+// WithManagerCircuitBreaker defines the CircuitBreaker to which the protocol violations of peers are reported.
+// Peers are disconnected when they get banned.
+func WithManagerCircuitBreaker(circuitBreaker *CircuitBreaker) ManagerOption {
+	return func(opts *ManagerOptions) {
+		opts.circuitBreaker = circuitBreaker
 	}
 }
 
@@ -181,14 +214,6 @@ func (mo *ManagerOptions) apply(opts ...ManagerOption) {
 	}
 }
 
-// computes the next reconnect delay.
-func (mo *ManagerOptions) reconnectDelay() time.Duration {
-	recInter := mo.reconnectInterval
-	jitter := mo.reconnectIntervalJitter
-	delayJitter := rand.Int63n(int64(jitter))
-	return recInter + time.Duration(delayJitter)
-}
-
 // NewManager creates a new Manager.
 func NewManager(host host.Host, opts ...ManagerOption) *Manager {
 	mngOpts := &ManagerOptions{}
@@ -212,6 +237,7 @@ func NewManager(host host.Host, opts ...ManagerOption) *Manager {
 		},
 		host:               host,
 		peers:              map[peer.ID]*Peer{},
+		reconnectBackoffs:  map[peer.ID]*ReconnectBackoff{},
 		allowedPeers:       map[peer.ID]struct{}{},
 		opts:               mngOpts,
 		stopped:            typeutils.NewAtomicBool(),
@@ -224,6 +250,7 @@ func NewManager(host host.Host, opts ...ManagerOption) *Manager {
 		connectedChan:      make(chan *connectionmsg, 10),
 		disconnectedChan:   make(chan *disconnectmsg, 10),
 		reconnectChan:      make(chan *reconnectmsg, 100),
+		reconnectPeerChan:  make(chan *reconnectpeermsg, 10),
 		forEachChan:        make(chan *foreachmsg, 10),
 		callChan:           make(chan *callmsg, 10),
 	}
@@ -246,6 +273,9 @@ type Manager struct {
 	peers map[peer.ID]*Peer
 	// holds the set of allowed peers (autopeering).
 	allowedPeers map[peer.ID]struct{}
+	// holds the reconnect backoff policies of peers which don't use the default policy.
+	reconnectBackoffs     map[peer.ID]*ReconnectBackoff
+	reconnectBackoffsLock sync.RWMutex
 	// holds the manager options.
 	opts *ManagerOptions
 	// tells whether the manager was shut down.
@@ -260,6 +290,7 @@ type Manager struct {
 	connectedChan      chan *connectionmsg
 	disconnectedChan   chan *disconnectmsg
 	reconnectChan      chan *reconnectmsg
+	reconnectPeerChan  chan *reconnectpeermsg
 	forEachChan        chan *foreachmsg
 	callChan           chan *callmsg
 }
@@ -303,6 +334,13 @@ drainLoop:
 		case disconnectPeerMsg := <-m.disconnectPeerChan:
 			disconnectPeerMsg.back <- ErrManagerShutdown
 
+		case reconnectPeerMsg := <-m.reconnectPeerChan:
+			err := m.reconnectPeerNow(reconnectPeerMsg.peerID)
+			if err != nil {
+				m.Events.Error.Trigger(fmt.Errorf("error reconnect %s: %w", reconnectPeerMsg.peerID.ShortString(), err))
+			}
+			reconnectPeerMsg.back <- err
+
 		case isConnectedReqMsg := <-m.isConnectedReqChan:
 			isConnectedReqMsg.back <- false
 
@@ -350,6 +388,42 @@ func (m *Manager) ConnectPeer(addrInfo *peer.AddrInfo, peerRelation PeerRelation
 	return <-back
 }
 
+// DefaultReconnectBackoff returns the reconnect backoff policy used for peers without an own policy.
+func (m *Manager) DefaultReconnectBackoff() ReconnectBackoff {
+	return m.opts.reconnectBackoff
+}
+
+// SetReconnectBackoff sets the reconnect backoff policy for the given peer.
+// Passing nil resets the peer to the default policy.
+// The policy is applied to the next scheduled reconnect.
+func (m *Manager) SetReconnectBackoff(peerID peer.ID, backoff *ReconnectBackoff) {
+	m.reconnectBackoffsLock.Lock()
+	defer m.reconnectBackoffsLock.Unlock()
+
+	if backoff == nil {
+		delete(m.reconnectBackoffs, peerID)
+		return
+	}
+	m.reconnectBackoffs[peerID] = backoff
+}
+
+// ApplyPeerReconnectConfig sets the reconnect backoff policy for the given peer from its peering config.
+// Passing nil resets the peer to the default policy.
+func (m *Manager) ApplyPeerReconnectConfig(peerID peer.ID, reconnect *PeerReconnectConfig) error {
+	if reconnect == nil {
+		m.SetReconnectBackoff(peerID, nil)
+		return nil
+	}
+
+	backoff, err := reconnect.Backoff(m.DefaultReconnectBackoff())
+	if err != nil {
+		return err
+	}
+
+	m.SetReconnectBackoff(peerID, backoff)
+	return nil
+}
+
 // DisconnectPeer disconnects the given peer.
 // If the peer is considered "known", then its connection is unprotected from future trimming.
 func (m *Manager) DisconnectPeer(peerID peer.ID, disconnectReason ...error) error {
@@ -366,6 +440,41 @@ func (m *Manager) DisconnectPeer(peerID peer.ID, disconnectReason ...error) erro
 	return <-back
 }
 
+// ReconnectPeer closes the connections to the given peer and immediately connects to it again.
+// The pending reconnect attempts of the peer are reset. Only peers with relation PeerRelationKnown can be reconnected.
+func (m *Manager) ReconnectPeer(peerID peer.ID) error {
+	if m.stopped.IsSet() {
+		return ErrManagerShutdown
+	}
+
+	back := make(chan error)
+	m.reconnectPeerChan <- &reconnectpeermsg{peerID: peerID, back: back}
+	return <-back
+}
+
+// ReportViolation reports a protocol violation of the given peer to the CircuitBreaker.
+// The peer is disconnected if it gets banned because of the violation.
+func (m *Manager) ReportViolation(peerID peer.ID, violation ProtocolViolation) {
+	if m.opts.circuitBreaker == nil {
+		return
+	}
+
+	var ips []net.IP
+	for _, conn := range m.host.Network().ConnsToPeer(peerID) {
+		if ip, err := manet.ToIP(conn.RemoteMultiaddr()); err == nil {
+			ips = append(ips, ip)
+		}
+	}
+
+	ban := m.opts.circuitBreaker.ReportViolation(peerID, ips, violation)
+	if ban == nil {
+		return
+	}
+
+	m.LogWarnf("banned peer %s until %s because of protocol violations: %v", peerID.ShortString(), ban.Until.Format(time.RFC3339), ban.Violations)
+	_ = m.DisconnectPeer(peerID, errors.Errorf("peer was banned because of protocol violations (last: %s)", violation))
+}
+
 // IsConnected tells whether there is a connection to the given peer.
 func (m *Manager) IsConnected(peerID peer.ID) bool {
 	if m.stopped.IsSet() {
@@ -526,6 +635,11 @@ type reconnectmsg struct {
 	peerID peer.ID
 }
 
+type reconnectpeermsg struct {
+	peerID peer.ID
+	back   chan error
+}
+
 type foreachmsg struct {
 	f      PeerForEachFunc
 	back   chan struct{}
@@ -755,7 +869,8 @@ func (m *Manager) scheduleReconnectIfKnown(peerID peer.ID) {
 	}
 	p.connectedEventCalled = false
 
-	delay := m.opts.reconnectDelay()
+	delay := m.reconnectBackoff(peerID).Delay(p.reconnectAttempts)
+	p.reconnectAttempts++
 	p.reconnectTimer = time.AfterFunc(delay, func() {
 		if m.stopped.IsSet() {
 			return
@@ -772,6 +887,7 @@ func (m *Manager) resetReconnect(peerID peer.ID) {
 	if !has {
 		return
 	}
+	p.reconnectAttempts = 0
 	if p.reconnectTimer != nil {
 		p.reconnectTimer.Stop()
 		p.reconnectTimer = nil
@@ -780,6 +896,17 @@ func (m *Manager) resetReconnect(peerID peer.ID) {
 	}
 }
 
+// returns the reconnect backoff policy for the given peer.
+func (m *Manager) reconnectBackoff(peerID peer.ID) *ReconnectBackoff {
+	m.reconnectBackoffsLock.RLock()
+	defer m.reconnectBackoffsLock.RUnlock()
+
+	if backoff, has := m.reconnectBackoffs[peerID]; has {
+		return backoff
+	}
+	return &m.opts.reconnectBackoff
+}
+
 // reconnect peer does a connection attempt to the given peer but only
 // if its relation is PeerRelationKnown.
 func (m *Manager) reconnectPeer(peerID peer.ID) (bool, error) {
@@ -796,6 +923,37 @@ func (m *Manager) reconnectPeer(peerID peer.ID) (bool, error) {
 	return true, m.connect(peer.AddrInfo{ID: peerID, Addrs: p.Addrs})
 }
 
+// closes the connections to the given peer and does a connection attempt right away,
+// instead of waiting for the reconnect timer.
+func (m *Manager) reconnectPeerNow(peerID peer.ID) error {
+	p, has := m.peers[peerID]
+	if !has {
+		return ErrPeerNotFound
+	}
+
+	if p.Relation != PeerRelationKnown {
+		return ErrPeerNotKnown
+	}
+
+	if p.reconnectTimer != nil {
+		p.reconnectTimer.Stop()
+		p.reconnectTimer = nil
+	}
+	p.reconnectAttempts = 0
+
+	if m.host.Network().Connectedness(peerID) == network.Connected {
+		if err := m.host.Network().ClosePeer(peerID); err != nil {
+			return err
+		}
+		// the disconnect notification of the network is ignored if the peer is connected again until it is handled
+		p.connectedEventCalled = false
+		m.Events.Disconnected.Trigger(&PeerOptError{Peer: p, Error: errors.New("peer reconnect was requested")})
+	}
+
+	m.Events.Reconnecting.Trigger(p)
+	return m.connect(peer.AddrInfo{ID: peerID, Addrs: p.Addrs})
+}
+
 // connect does an actual connection attempt to the given peer.
 // if the connection fails, the peer is either cleared from the Manager if its relation is PeerRelationUnknown
 // or a reconnect attempt is scheduled if it is PeerRelationKnown.
@@ -890,7 +1048,7 @@ func (m *Manager) registerLoggerOnEvents() {
 		m.LogInfof(msg)
 	}))
 	m.Events.ScheduledReconnect.Attach(events.NewClosure(func(p *Peer, dur time.Duration) {
-		m.LogInfof("scheduled reconnect in %v to %s", dur, p.ID.ShortString())
+		m.LogInfof("scheduled reconnect in %v to %s (attempt %d)", dur.Truncate(time.Millisecond), p.ID.ShortString(), p.reconnectAttempts)
 	}))
 	m.Events.Reconnecting.Attach(events.NewClosure(func(p *Peer) {
 		m.LogInfof("reconnecting %s", p.ID.ShortString())
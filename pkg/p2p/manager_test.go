@@ -171,6 +171,54 @@ func TestManager(t *testing.T) {
 	}, p2p.PeerRelationUnknown)
 }
 
+func TestManagerReconnectPeer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := configuration.New()
+	err := cfg.Set("logger.disableStacktrace", true)
+	require.NoError(t, err)
+
+	// no need to check the error, since the global logger could already be initialized
+	_ = logger.InitGlobalLogger(cfg)
+
+	node1 := newNode(t)
+	node1Logger := logger.NewLogger(fmt.Sprintf("node1/%s", node1.ID().ShortString()))
+	node1Manager := p2p.NewManager(node1, p2p.WithManagerLogger(node1Logger))
+	go node1Manager.Start(ctx)
+
+	node2 := newNode(t)
+	node2Logger := logger.NewLogger(fmt.Sprintf("node2/%s", node2.ID().ShortString()))
+	node2Manager := p2p.NewManager(node2, p2p.WithManagerLogger(node2Logger))
+	go node2Manager.Start(ctx)
+	node2AddrInfo := &peer.AddrInfo{ID: node2.ID(), Addrs: node2.Addrs()[:1]}
+
+	require.True(t, errors.Is(node1Manager.ReconnectPeer(node2.ID()), p2p.ErrPeerNotFound))
+
+	require.NoError(t, node1Manager.ConnectPeer(node2AddrInfo, p2p.PeerRelationKnown))
+	connectivity(t, node1Manager, node2.ID(), false)
+	connectivity(t, node2Manager, node1.ID(), false)
+
+	// node 1 is an unknown peer for node 2
+	require.True(t, errors.Is(node2Manager.ReconnectPeer(node1.ID()), p2p.ErrPeerNotKnown))
+
+	var disconnected, reconnecting int
+	node1Manager.Events.Disconnected.Attach(events.NewClosure(func(_ *p2p.PeerOptError) {
+		disconnected++
+	}))
+	node1Manager.Events.Reconnecting.Attach(events.NewClosure(func(_ *p2p.Peer) {
+		reconnecting++
+	}))
+
+	require.NoError(t, node1Manager.ReconnectPeer(node2.ID()))
+	require.Equal(t, 1, disconnected)
+	require.Equal(t, 1, reconnecting)
+
+	connectivity(t, node1Manager, node2.ID(), false)
+	connectivity(t, node2Manager, node1.ID(), false)
+	require.True(t, node1.ConnManager().IsProtected(node2.ID(), p2p.PeerConnectivityProtectionTag))
+}
+
 func connectivity(t *testing.T, source *p2p.Manager, target peer.ID, disconnected bool, overrideCheckDuration ...time.Duration) {
 	dur := 6 * time.Second
 	if len(overrideCheckDuration) > 0 {
@@ -21,6 +21,10 @@ func NewPeer(peerID peer.ID, relation PeerRelation, addrs []multiaddr.Multiaddr,
 type PeerConfig struct {
 	MultiAddress string `json:"multiAddress" koanf:"multiAddress"`
 	Alias        string `json:"alias" koanf:"alias"`
+	// The transport used to connect to the peer ("tcp" or "ws", default: "tcp").
+	Transport string `json:"transport,omitempty" koanf:"transport"`
+	// The reconnect backoff policy for the peer (optional, defaults to the policy of the node).
+	Reconnect *PeerReconnectConfig `json:"reconnect,omitempty" koanf:"reconnect"`
 }
 
 // Peer is a remote peer in the network.
@@ -36,6 +40,8 @@ type Peer struct {
 
 	connectedEventCalled bool
 	reconnectTimer       *time.Timer
+	// the amount of reconnect attempts since the peer was last connected.
+	reconnectAttempts int
 }
 
 // InfoSnapshot returns a snapshot of the peer in time of calling Info().
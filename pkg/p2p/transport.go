@@ -0,0 +1,85 @@
+package p2p
+
+import (
+	"fmt"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+const (
+	// PeerTransportTCP connects to a peer via plain TCP.
+	PeerTransportTCP = "tcp"
+	// PeerTransportWebSocket connects to a peer via WebSocket, which passes firewalls that only allow HTTP(S) ports.
+	PeerTransportWebSocket = "ws"
+)
+
+var (
+	// ErrUnknownPeerTransport is returned if an unknown transport was configured for a peer.
+	ErrUnknownPeerTransport = errors.New("unknown peer transport")
+)
+
+// ValidatePeerTransport checks whether the given transport is supported.
+// An empty transport is valid and defaults to TCP.
+func ValidatePeerTransport(transport string) error {
+	switch transport {
+	case "", PeerTransportTCP, PeerTransportWebSocket:
+		return nil
+	default:
+		return errors.WithMessagef(ErrUnknownPeerTransport, "%s", transport)
+	}
+}
+
+// PeerTransportMultiAddress returns the multi address which is used to connect to a peer via the given transport.
+// For WebSocket, the "/ws" protocol is appended to the TCP part of the address if it is not already present.
+func PeerTransportMultiAddress(multiAddress multiaddr.Multiaddr, transport string) (multiaddr.Multiaddr, error) {
+	if err := ValidatePeerTransport(transport); err != nil {
+		return nil, err
+	}
+
+	if transport != PeerTransportWebSocket {
+		return multiAddress, nil
+	}
+
+	if _, err := multiAddress.ValueForProtocol(multiaddr.P_WS); err == nil {
+		// the address already uses WebSocket
+		return multiAddress, nil
+	}
+
+	if _, err := multiAddress.ValueForProtocol(multiaddr.P_TCP); err != nil {
+		return nil, fmt.Errorf("WebSocket transport needs a TCP address: %s", multiAddress)
+	}
+
+	transportAddr, peerID := peer.SplitAddr(multiAddress)
+	if transportAddr == nil {
+		return nil, fmt.Errorf("invalid peer address: %s", multiAddress)
+	}
+
+	wsAddr := transportAddr.Encapsulate(multiaddr.StringCast("/ws"))
+	if peerID == "" {
+		return wsAddr, nil
+	}
+
+	p2pAddr, err := multiaddr.NewComponent(multiaddr.ProtocolWithCode(multiaddr.P_P2P).Name, peerID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return wsAddr.Encapsulate(p2pAddr), nil
+}
+
+// AddrInfo returns the address info which is used to connect to the peer with its configured transport.
+func (p *PeerConfig) AddrInfo() (*peer.AddrInfo, error) {
+	multiAddress, err := multiaddr.NewMultiaddr(p.MultiAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	dialAddress, err := PeerTransportMultiAddress(multiAddress, p.Transport)
+	if err != nil {
+		return nil, err
+	}
+
+	return peer.AddrInfoFromP2pAddr(dialAddress)
+}
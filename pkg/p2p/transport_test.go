@@ -0,0 +1,45 @@
+package p2p_test
+
+import (
+	"testing"
+
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/p2p"
+)
+
+func TestPeerTransportMultiAddress(t *testing.T) {
+
+	const peerID = "12D3KooWCKwcTWevoRKa2kEBputeGASvEBuDfRDSbe8t1DWugUmL"
+	tcpAddr := multiaddr.StringCast("/ip4/192.0.2.1/tcp/443/p2p/" + peerID)
+
+	addr, err := p2p.PeerTransportMultiAddress(tcpAddr, "")
+	require.NoError(t, err)
+	require.Equal(t, tcpAddr.String(), addr.String())
+
+	addr, err = p2p.PeerTransportMultiAddress(tcpAddr, p2p.PeerTransportTCP)
+	require.NoError(t, err)
+	require.Equal(t, tcpAddr.String(), addr.String())
+
+	addr, err = p2p.PeerTransportMultiAddress(tcpAddr, p2p.PeerTransportWebSocket)
+	require.NoError(t, err)
+	require.Equal(t, "/ip4/192.0.2.1/tcp/443/ws/p2p/"+peerID, addr.String())
+
+	// addresses which already use WebSocket are not changed
+	wsAddr := multiaddr.StringCast("/dns/example.com/tcp/443/ws/p2p/" + peerID)
+	addr, err = p2p.PeerTransportMultiAddress(wsAddr, p2p.PeerTransportWebSocket)
+	require.NoError(t, err)
+	require.Equal(t, wsAddr.String(), addr.String())
+
+	_, err = p2p.PeerTransportMultiAddress(multiaddr.StringCast("/ip4/192.0.2.1/udp/443/quic/p2p/"+peerID), p2p.PeerTransportWebSocket)
+	require.Error(t, err)
+
+	_, err = p2p.PeerTransportMultiAddress(tcpAddr, "quic")
+	require.ErrorIs(t, err, p2p.ErrUnknownPeerTransport)
+
+	addrInfo, err := (&p2p.PeerConfig{MultiAddress: tcpAddr.String(), Transport: p2p.PeerTransportWebSocket}).AddrInfo()
+	require.NoError(t, err)
+	require.Equal(t, peerID, addrInfo.ID.String())
+	require.Equal(t, "/ip4/192.0.2.1/tcp/443/ws", addrInfo.Addrs[0].String())
+}
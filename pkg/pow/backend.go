@@ -0,0 +1,128 @@
+package pow
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/iotaledger/iota.go/v3/pow"
+)
+
+const (
+	// BackendAuto selects the available backend with the highest priority.
+	BackendAuto = "auto"
+	// BackendCPU is the batched Curl implementation of iota.go, which is available on every platform.
+	BackendCPU = "cpu"
+)
+
+// Worker computes the nonce of the PoW.
+type Worker interface {
+	// Mine returns a nonce that appended to data results in a PoW score of at least targetScore.
+	// It uses the given amount of parallel workers and returns pow.ErrCancelled if the context is done.
+	Mine(ctx context.Context, data []byte, targetScore float64, parallelism int) (uint64, error)
+}
+
+// Backend is an implementation of the PoW that can be used for local PoW.
+// Accelerated implementations register themselves in the init function of a file with the matching build tags.
+type Backend struct {
+	// The name of the backend, which is used to select it in the config.
+	Name string
+	// Backends with a higher priority are preferred if multiple backends are available.
+	Priority int
+	// Available returns whether the backend can be used on this machine, e.g. if the CPU supports the needed instructions.
+	Available func() bool
+	// New creates the worker of the backend.
+	New func() (Worker, error)
+}
+
+var (
+	backendsLock sync.RWMutex
+	backends     = make(map[string]*Backend)
+)
+
+func init() {
+	RegisterBackend(&Backend{
+		Name:      BackendCPU,
+		Priority:  0,
+		Available: func() bool { return true },
+		New:       func() (Worker, error) { return &cpuWorker{}, nil },
+	})
+}
+
+// RegisterBackend registers a PoW backend.
+func RegisterBackend(backend *Backend) {
+	backendsLock.Lock()
+	defer backendsLock.Unlock()
+
+	if _, exists := backends[backend.Name]; exists {
+		panic(fmt.Sprintf("PoW backend %s registered twice", backend.Name))
+	}
+	backends[backend.Name] = backend
+}
+
+// AvailableBackends returns the names of the backends available on this machine, ordered by priority.
+func AvailableBackends() []string {
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+
+	available := make([]*Backend, 0, len(backends))
+	for _, backend := range backends {
+		if backend.Available() {
+			available = append(available, backend)
+		}
+	}
+
+	sort.Slice(available, func(i, j int) bool {
+		if available[i].Priority != available[j].Priority {
+			return available[i].Priority > available[j].Priority
+		}
+		return available[i].Name < available[j].Name
+	})
+
+	names := make([]string, len(available))
+	for i, backend := range available {
+		names[i] = backend.Name
+	}
+
+	return names
+}
+
+// NewBackendWorker creates the worker of the backend with the given name.
+// BackendAuto creates the worker of the available backend with the highest priority.
+// It returns the name of the used backend.
+func NewBackendWorker(name string) (Worker, string, error) {
+
+	if name == BackendAuto {
+		available := AvailableBackends()
+		if len(available) == 0 {
+			return nil, "", fmt.Errorf("no PoW backend available")
+		}
+		name = available[0]
+	}
+
+	backendsLock.RLock()
+	backend, exists := backends[name]
+	backendsLock.RUnlock()
+
+	if !exists {
+		return nil, "", fmt.Errorf("unknown PoW backend: %s", name)
+	}
+	if !backend.Available() {
+		return nil, "", fmt.Errorf("PoW backend %s is not available on this machine", name)
+	}
+
+	worker, err := backend.New()
+	if err != nil {
+		return nil, "", fmt.Errorf("creating PoW backend %s failed: %w", name, err)
+	}
+
+	return worker, name, nil
+}
+
+// cpuWorker is the worker of BackendCPU.
+type cpuWorker struct{}
+
+func (w *cpuWorker) Mine(ctx context.Context, data []byte, targetScore float64, parallelism int) (uint64, error) {
+	return pow.New(parallelism).Mine(ctx, data, targetScore)
+}
@@ -0,0 +1,61 @@
+package pow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testWorker struct {
+	cpuWorker
+}
+
+func TestNewBackendWorker(t *testing.T) {
+
+	require.Contains(t, AvailableBackends(), BackendCPU)
+
+	_, backend, err := NewBackendWorker(BackendCPU)
+	require.NoError(t, err)
+	require.Equal(t, BackendCPU, backend)
+
+	_, _, err = NewBackendWorker("unknown")
+	require.Error(t, err)
+
+	RegisterBackend(&Backend{
+		Name:      "test-accelerated",
+		Priority:  10,
+		Available: func() bool { return true },
+		New:       func() (Worker, error) { return &testWorker{}, nil },
+	})
+	RegisterBackend(&Backend{
+		Name:      "test-unsupported",
+		Priority:  20,
+		Available: func() bool { return false },
+		New:       func() (Worker, error) { return &testWorker{}, nil },
+	})
+
+	// the unavailable backend is skipped even though it has the highest priority
+	require.Equal(t, []string{"test-accelerated", BackendCPU}, AvailableBackends())
+
+	worker, backend, err := NewBackendWorker(BackendAuto)
+	require.NoError(t, err)
+	require.Equal(t, "test-accelerated", backend)
+	require.IsType(t, &testWorker{}, worker)
+
+	_, _, err = NewBackendWorker("test-unsupported")
+	require.Error(t, err)
+
+	require.Panics(t, func() {
+		RegisterBackend(&Backend{Name: BackendCPU})
+	})
+
+	// the handler uses the worker of the backend for local PoW
+	handler := New(testTargetScore, 0, WithLocalWorker(backend, worker))
+	require.Equal(t, "local-test-accelerated", handler.PoWType())
+
+	data := make([]byte, 100)
+	nonce, err := handler.proofOfWork(context.Background(), data, 1, false)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, score(data, nonce), testTargetScore)
+}
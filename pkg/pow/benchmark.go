@@ -0,0 +1,126 @@
+package pow
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/iotaledger/iota.go/v3/pow"
+)
+
+const (
+	// benchmarkSuggestionRatio is the share of the highest hash rate that the suggested thread count has to reach,
+	// so additional threads that barely increase the hash rate are not suggested.
+	benchmarkSuggestionRatio = 0.95
+)
+
+// BenchmarkResult is the result of the PoW benchmark of a thread count.
+type BenchmarkResult struct {
+	// The amount of threads used for every message.
+	Threads int
+	// The amount of messages which hit the target score.
+	Messages int
+	// The duration of the benchmark.
+	Duration time.Duration
+	// The messages per second which hit the target score.
+	MessagesPerSecond float64
+	// The hashes per second, estimated by the expected amount of hashes needed to hit the target score.
+	HashesPerSecond float64
+}
+
+// BenchmarkThreadCounts returns the thread counts up to maxThreads that are worth benchmarking:
+// the powers of two and maxThreads itself.
+func BenchmarkThreadCounts(maxThreads int) []int {
+	var threadCounts []int
+	for threads := 1; threads < maxThreads; threads *= 2 {
+		threadCounts = append(threadCounts, threads)
+	}
+	if maxThreads >= 1 {
+		threadCounts = append(threadCounts, maxThreads)
+	}
+	return threadCounts
+}
+
+// expectedHashes returns the expected amount of hashes to hit the target score with a message of the given size.
+func expectedHashes(messageSize int, targetScore float64) float64 {
+	// the same amount of trailing zeros the worker mines for
+	targetZeros := math.Ceil(math.Log(float64(messageSize)*targetScore) / math.Log(3))
+	return math.Pow(3, targetZeros)
+}
+
+// Benchmark mines random messages of the given size with the worker for every thread count for the given duration.
+// The amount of hashes cannot be counted for every backend, so the hash rate is estimated by the amount of mined messages.
+func Benchmark(ctx context.Context, worker Worker, targetScore float64, messageSize int, threadCounts []int, duration time.Duration) ([]*BenchmarkResult, error) {
+
+	if messageSize <= nonceBytes {
+		return nil, errors.New("message size too small")
+	}
+
+	data := make([]byte, messageSize-nonceBytes)
+	results := make([]*BenchmarkResult, 0, len(threadCounts))
+
+	for _, threads := range threadCounts {
+		benchmarkCtx, benchmarkCancel := context.WithTimeout(ctx, duration)
+
+		ts := time.Now()
+		messages := 0
+		for {
+			if _, err := rand.Read(data); err != nil {
+				benchmarkCancel()
+				return nil, err
+			}
+
+			if _, err := worker.Mine(benchmarkCtx, data, targetScore, threads); err != nil {
+				if errors.Is(err, pow.ErrCancelled) {
+					break
+				}
+				benchmarkCancel()
+				return nil, err
+			}
+			messages++
+		}
+		elapsed := time.Since(ts)
+		benchmarkCancel()
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		messagesPerSecond := float64(messages) / elapsed.Seconds()
+		results = append(results, &BenchmarkResult{
+			Threads:           threads,
+			Messages:          messages,
+			Duration:          elapsed,
+			MessagesPerSecond: messagesPerSecond,
+			HashesPerSecond:   messagesPerSecond * expectedHashes(messageSize, targetScore),
+		})
+	}
+
+	return results, nil
+}
+
+// SuggestedThreads returns the result with the lowest thread count that reaches almost the highest hash rate of the benchmark.
+// It returns nil if there are no results.
+func SuggestedThreads(results []*BenchmarkResult) *BenchmarkResult {
+
+	var best *BenchmarkResult
+	for _, result := range results {
+		if best == nil || result.HashesPerSecond > best.HashesPerSecond {
+			best = result
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	suggested := best
+	for _, result := range results {
+		if result.HashesPerSecond >= best.HashesPerSecond*benchmarkSuggestionRatio && result.Threads < suggested.Threads {
+			suggested = result
+		}
+	}
+
+	return suggested
+}
@@ -0,0 +1,46 @@
+package pow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBenchmarkThreadCounts(t *testing.T) {
+	require.Empty(t, BenchmarkThreadCounts(0))
+	require.Equal(t, []int{1}, BenchmarkThreadCounts(1))
+	require.Equal(t, []int{1, 2, 4, 6}, BenchmarkThreadCounts(6))
+	require.Equal(t, []int{1, 2, 4, 8}, BenchmarkThreadCounts(8))
+}
+
+func TestBenchmark(t *testing.T) {
+
+	results, err := Benchmark(context.Background(), &cpuWorker{}, testTargetScore, 100, []int{1, 2}, 50*time.Millisecond)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	for i, result := range results {
+		require.Equal(t, i+1, result.Threads)
+		require.Greater(t, result.Messages, 0)
+		require.Greater(t, result.HashesPerSecond, result.MessagesPerSecond)
+	}
+
+	_, err = Benchmark(context.Background(), &cpuWorker{}, testTargetScore, nonceBytes, []int{1}, time.Millisecond)
+	require.Error(t, err)
+}
+
+func TestSuggestedThreads(t *testing.T) {
+
+	require.Nil(t, SuggestedThreads(nil))
+
+	suggested := SuggestedThreads([]*BenchmarkResult{
+		{Threads: 1, HashesPerSecond: 100},
+		{Threads: 2, HashesPerSecond: 196},
+		{Threads: 4, HashesPerSecond: 380},
+		{Threads: 8, HashesPerSecond: 390},
+	})
+	// 4 threads reach 97% of the hash rate of 8 threads
+	require.Equal(t, 4, suggested.Threads)
+}
@@ -0,0 +1,96 @@
+package pow
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/iotaledger/iota.go/v3/pow"
+)
+
+// cpuBudget distributes a maximum amount of threads among the concurrent local PoW jobs.
+type cpuBudget struct {
+	sync.Mutex
+
+	maxThreads  int
+	usedThreads int
+	// the amount of jobs that are running or waiting for threads.
+	jobs int
+	// closed and replaced every time threads are released.
+	released chan struct{}
+}
+
+// newCPUBudget creates a budget of the given percentage of the CPU cores, but at least one thread.
+func newCPUBudget(percentage int) *cpuBudget {
+	maxThreads := runtime.NumCPU() * percentage / 100
+	if maxThreads < 1 {
+		maxThreads = 1
+	}
+
+	return &cpuBudget{
+		maxThreads: maxThreads,
+		released:   make(chan struct{}),
+	}
+}
+
+// acquire waits until threads are free and returns the amount of threads the job may use.
+// Every job gets an equal share of the budget depending on the amount of queued jobs, but not more than the given parallelism (0 = no limit).
+// It returns pow.ErrCancelled if the context is done before threads were free.
+func (b *cpuBudget) acquire(ctx context.Context, parallelism int) (int, error) {
+	b.Lock()
+	b.jobs++
+
+	for {
+		if free := b.maxThreads - b.usedThreads; free > 0 {
+			threads := b.maxThreads / b.jobs
+			if threads < 1 {
+				threads = 1
+			}
+			if threads > free {
+				threads = free
+			}
+			if parallelism > 0 && threads > parallelism {
+				threads = parallelism
+			}
+
+			b.usedThreads += threads
+			b.Unlock()
+
+			return threads, nil
+		}
+
+		released := b.released
+		b.Unlock()
+
+		select {
+		case <-ctx.Done():
+			b.Lock()
+			b.jobs--
+			b.Unlock()
+			return 0, pow.ErrCancelled
+		case <-released:
+		}
+
+		b.Lock()
+	}
+}
+
+// release returns the threads of a finished job to the budget.
+func (b *cpuBudget) release(threads int) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.usedThreads -= threads
+	b.jobs--
+
+	close(b.released)
+	b.released = make(chan struct{})
+}
+
+// usage returns the amount of used threads, the maximum amount of threads and the amount of queued jobs.
+func (b *cpuBudget) usage() (int, int, int) {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.usedThreads, b.maxThreads, b.jobs
+}
@@ -0,0 +1,92 @@
+package pow
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/iota.go/v3/pow"
+)
+
+func TestCPUBudget(t *testing.T) {
+
+	budget := newCPUBudget(0)
+	require.Equal(t, 1, budget.maxThreads)
+
+	budget = newCPUBudget(100)
+	require.Equal(t, runtime.NumCPU(), budget.maxThreads)
+
+	budget = &cpuBudget{maxThreads: 8, released: make(chan struct{})}
+
+	// a single job gets the whole budget, limited by its parallelism
+	threads, err := budget.acquire(context.Background(), 0)
+	require.NoError(t, err)
+	require.Equal(t, 8, threads)
+	budget.release(threads)
+
+	threads, err = budget.acquire(context.Background(), 3)
+	require.NoError(t, err)
+	require.Equal(t, 3, threads)
+
+	// the second job gets its share of the remaining threads
+	threads2, err := budget.acquire(context.Background(), 0)
+	require.NoError(t, err)
+	require.Equal(t, 4, threads2)
+
+	// the third job gets the last thread
+	threads3, err := budget.acquire(context.Background(), 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, threads3)
+
+	used, maxThreads, jobs := budget.usage()
+	require.Equal(t, 8, used)
+	require.Equal(t, 8, maxThreads)
+	require.Equal(t, 3, jobs)
+
+	// the budget is exhausted, so the fourth job waits until it is canceled
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = budget.acquire(ctx, 0)
+	require.ErrorIs(t, err, pow.ErrCancelled)
+
+	// the fourth job gets the threads of a released job
+	acquired := make(chan int)
+	go func() {
+		threads4, err := budget.acquire(context.Background(), 0)
+		require.NoError(t, err)
+		acquired <- threads4
+	}()
+
+	budget.release(threads2)
+	require.Equal(t, 2, <-acquired)
+
+	used, _, jobs = budget.usage()
+	require.Equal(t, 6, used)
+	require.Equal(t, 3, jobs)
+}
+
+func TestPriorityPoWIgnoresCPUBudget(t *testing.T) {
+
+	handler := New(testTargetScore, 0, WithCPUBudget(0))
+
+	// exhaust the budget
+	threads, err := handler.budget.acquire(context.Background(), 0)
+	require.NoError(t, err)
+	defer handler.budget.release(threads)
+
+	data := make([]byte, 100)
+
+	// regular jobs wait for the budget
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = handler.proofOfWork(ctx, data, 1, false)
+	require.ErrorIs(t, err, pow.ErrCancelled)
+
+	// priority jobs are not delayed by the budget
+	nonce, err := handler.proofOfWork(context.Background(), data, 1, true)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, score(data, nonce), testTargetScore)
+}
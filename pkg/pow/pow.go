@@ -3,6 +3,7 @@ package pow
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -10,6 +11,7 @@ import (
 	"github.com/gohornet/hornet/pkg/common"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/utils"
+	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/serializer/v2"
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/iota.go/v3/pow"
@@ -19,45 +21,276 @@ const (
 	nonceBytes = 8 // len(uint64)
 )
 
-type proofOfWorkFunc func(ctx context.Context, data []byte, parallelism ...int) (uint64, error)
-
 // RefreshTipsFunc refreshes tips of the message if PoW takes longer than a configured duration.
 type RefreshTipsFunc = func() (tips hornet.MessageIDs, err error)
 
-// Handler handles PoW requests of the node and uses local PoW.
+var defaultOptions = []Option{
+	WithLocalWorker(BackendCPU, &cpuWorker{}),
+	WithCPUBudget(60),
+	WithRemoteJobTimeout(30 * time.Second),
+	WithRemoteHealthCheckInterval(10 * time.Second),
+}
+
+// Options define options for the PoW handler.
+type Options struct {
+	// the logger used to log events.
+	logger *logger.Logger
+	// the name of the backend used for local PoW.
+	localBackend string
+	// the worker used for local PoW.
+	localWorker Worker
+	// returns the target score of the PoW, overrides the target score of the handler if set.
+	targetScoreFunc func() float64
+	// the percentage of the CPU cores that may be used for local PoW.
+	cpuBudget int
+	// the endpoints of the remote workers.
+	remoteEndpoints []string
+	// the maximum duration of a PoW job on a remote worker.
+	remoteJobTimeout time.Duration
+	// the interval for checking the health of the remote workers.
+	remoteHealthCheckInterval time.Duration
+}
+
+// applies the given Option.
+func (so *Options) apply(opts ...Option) {
+	for _, opt := range opts {
+		opt(so)
+	}
+}
+
+// WithLogger enables logging within the PoW handler.
+func WithLogger(logger *logger.Logger) Option {
+	return func(opts *Options) {
+		opts.logger = logger
+	}
+}
+
+// WithLocalWorker defines the worker of the backend with the given name that is used for local PoW.
+func WithLocalWorker(backend string, worker Worker) Option {
+	return func(opts *Options) {
+		opts.localBackend = backend
+		opts.localWorker = worker
+	}
+}
+
+// WithTargetScoreFunc defines the function that returns the target score of the PoW,
+// e.g. the minimum PoW score of the scoring version that is currently active in the network.
+func WithTargetScoreFunc(targetScoreFunc func() float64) Option {
+	return func(opts *Options) {
+		opts.targetScoreFunc = targetScoreFunc
+	}
+}
+
+// WithCPUBudget defines the percentage of the CPU cores that may be used for local PoW by all concurrent PoW requests together.
+func WithCPUBudget(percentage int) Option {
+	return func(opts *Options) {
+		opts.cpuBudget = percentage
+	}
+}
+
+// WithRemoteEndpoints defines the endpoints of the remote workers the PoW is offloaded to.
+func WithRemoteEndpoints(endpoints ...string) Option {
+	return func(opts *Options) {
+		opts.remoteEndpoints = endpoints
+	}
+}
+
+// WithRemoteJobTimeout defines the maximum duration of a PoW job on a remote worker before falling back to the next worker.
+func WithRemoteJobTimeout(timeout time.Duration) Option {
+	return func(opts *Options) {
+		opts.remoteJobTimeout = timeout
+	}
+}
+
+// WithRemoteHealthCheckInterval defines the interval for checking the health of the remote workers.
+func WithRemoteHealthCheckInterval(interval time.Duration) Option {
+	return func(opts *Options) {
+		opts.remoteHealthCheckInterval = interval
+	}
+}
+
+// Option is a function setting a PoW handler option.
+type Option func(opts *Options)
+
+// Handler handles PoW requests of the node and uses remote workers if available, otherwise local PoW.
 // It refreshes the tips of messages during PoW.
 type Handler struct {
-	targetScore         float64
+	// the logger used to log events.
+	*utils.WrappedLogger
+
+	targetScoreFunc     func() float64
 	refreshTipsInterval time.Duration
 
-	localPoWFunc proofOfWorkFunc
+	localWorker  Worker
 	localPoWType string
+	// the threads available for local PoW.
+	budget *cpuBudget
+
+	remoteWorkers []*remoteWorker
+	// the index of the remote worker that gets the next job.
+	remoteWorkerIndex uint32
+
+	opts *Options
 }
 
 // New creates a new PoW handler instance.
-func New(targetScore float64, refreshTipsInterval time.Duration) *Handler {
+func New(targetScore float64, refreshTipsInterval time.Duration, opts ...Option) *Handler {
 
-	localPoWType := "local"
-	localPoWFunc := func(ctx context.Context, data []byte, parallelism ...int) (uint64, error) {
-		return pow.New(parallelism...).Mine(ctx, data, targetScore)
+	options := &Options{}
+	options.apply(defaultOptions...)
+	options.apply(opts...)
+
+	targetScoreFunc := options.targetScoreFunc
+	if targetScoreFunc == nil {
+		targetScoreFunc = func() float64 { return targetScore }
+	}
+
+	remoteWorkers := make([]*remoteWorker, len(options.remoteEndpoints))
+	for i, endpoint := range options.remoteEndpoints {
+		remoteWorkers[i] = newRemoteWorker(endpoint)
 	}
 
 	return &Handler{
-		targetScore:         targetScore,
+		WrappedLogger:       utils.NewWrappedLogger(options.logger),
+		targetScoreFunc:     targetScoreFunc,
 		refreshTipsInterval: refreshTipsInterval,
-		localPoWFunc:        localPoWFunc,
-		localPoWType:        localPoWType,
+		localWorker:         options.localWorker,
+		localPoWType:        "local-" + options.localBackend,
+		budget:              newCPUBudget(options.cpuBudget),
+		remoteWorkers:       remoteWorkers,
+		opts:                options,
 	}
 }
 
 // PoWType returns the fastest available PoW type which gets used for PoW requests
 func (h *Handler) PoWType() string {
+	for _, worker := range h.remoteWorkers {
+		if worker.Healthy() {
+			return "remote"
+		}
+	}
 	return h.localPoWType
 }
 
+// TargetScore returns the PoW score the nonces of the handler hit.
+func (h *Handler) TargetScore() float64 {
+	return h.targetScoreFunc()
+}
+
+// Benchmark runs the PoW benchmark with the worker used for local PoW and the target score of the handler.
+// The benchmark doesn't respect the CPU budget, so it competes with the other tasks of the node.
+func (h *Handler) Benchmark(ctx context.Context, messageSize int, threadCounts []int, duration time.Duration) ([]*BenchmarkResult, error) {
+	return Benchmark(ctx, h.localWorker, h.TargetScore(), messageSize, threadCounts, duration)
+}
+
+// Run checks the health of the remote workers in the configured interval until the context is done.
+// Remote workers are only used for PoW after they passed a health check.
+func (h *Handler) Run(ctx context.Context) {
+	if len(h.remoteWorkers) == 0 {
+		<-ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(h.opts.remoteHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		h.checkRemoteWorkersHealth(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkRemoteWorkersHealth checks the health of all remote workers.
+func (h *Handler) checkRemoteWorkersHealth(ctx context.Context) {
+	for _, worker := range h.remoteWorkers {
+		checkCtx, checkCancel := context.WithTimeout(ctx, h.opts.remoteHealthCheckInterval)
+		err := worker.checkHealth(checkCtx)
+		checkCancel()
+
+		if ctx.Err() != nil {
+			return
+		}
+		h.setRemoteWorkerHealthy(worker, err)
+	}
+}
+
+// setRemoteWorkerHealthy updates the health state of the remote worker based on the error of its last request.
+func (h *Handler) setRemoteWorkerHealthy(worker *remoteWorker, err error) {
+	if !worker.setHealthy(err == nil) {
+		return
+	}
+
+	if err != nil {
+		h.LogWarnf("remote PoW worker %s is unhealthy: %s", worker.endpoint, err)
+		return
+	}
+	h.LogInfof("remote PoW worker %s is healthy", worker.endpoint)
+}
+
+// proofOfWork offloads the PoW to the healthy remote workers one after another and falls back to local PoW if all of them fail.
+// The local PoW of priority jobs is not limited by the CPU budget, so they never wait for the threads of other jobs.
+func (h *Handler) proofOfWork(ctx context.Context, data []byte, parallelism int, priority bool) (uint64, error) {
+
+	targetScore := h.TargetScore()
+
+	if len(h.remoteWorkers) > 0 {
+		// distribute the jobs among the workers
+		start := int(atomic.AddUint32(&h.remoteWorkerIndex, 1))
+		for i := range h.remoteWorkers {
+			worker := h.remoteWorkers[(start+i)%len(h.remoteWorkers)]
+			if !worker.Healthy() {
+				continue
+			}
+
+			jobCtx, jobCancel := context.WithTimeout(ctx, h.opts.remoteJobTimeout)
+			nonce, err := worker.mine(jobCtx, data, targetScore)
+			jobCancel()
+
+			if err == nil {
+				return nonce, nil
+			}
+
+			if ctx.Err() != nil {
+				// the PoW itself was canceled, e.g. to refresh the tips
+				return 0, pow.ErrCancelled
+			}
+			h.setRemoteWorkerHealthy(worker, err)
+		}
+	}
+
+	if priority {
+		return h.localWorker.Mine(ctx, data, targetScore, parallelism)
+	}
+
+	threads, err := h.budget.acquire(ctx, parallelism)
+	if err != nil {
+		return 0, err
+	}
+	defer h.budget.release(threads)
+
+	return h.localWorker.Mine(ctx, data, targetScore, threads)
+}
+
 // DoPoW does the proof-of-work required to hit the target score configured on this Handler.
 // The given iota.Message's nonce is automatically updated.
+// Local PoW uses at most parallelism threads (0 = as many as the CPU budget allows), and the threads are
+// shared among the concurrent requests, so a request may have to wait until the threads of others are released.
 func (h *Handler) DoPoW(ctx context.Context, msg *iotago.Message, parallelism int, refreshTipsFunc ...RefreshTipsFunc) (err error) {
+	return h.doPoW(ctx, msg, parallelism, false, refreshTipsFunc...)
+}
+
+// DoPriorityPoW does the proof-of-work like DoPoW, but the local PoW uses parallelism threads without waiting for the CPU budget.
+// It is used for the checkpoints and milestones of the coordinator, which must not be delayed by the PoW of API requests.
+func (h *Handler) DoPriorityPoW(ctx context.Context, msg *iotago.Message, parallelism int) error {
+	return h.doPoW(ctx, msg, parallelism, true)
+}
+
+func (h *Handler) doPoW(ctx context.Context, msg *iotago.Message, parallelism int, priority bool, refreshTipsFunc ...RefreshTipsFunc) (err error) {
 
 	if err := utils.ReturnErrIfCtxDone(ctx, common.ErrOperationAborted); err != nil {
 		return err
@@ -84,7 +317,7 @@ func (h *Handler) DoPoW(ctx context.Context, msg *iotago.Message, parallelism in
 			powCtx, powCancel = context.WithTimeout(powCtx, h.refreshTipsInterval)
 		}
 
-		nonce, err := h.localPoWFunc(powCtx, powData, parallelism)
+		nonce, err := h.proofOfWork(powCtx, powData, parallelism, priority)
 		powCancel()
 
 		if err != nil {
@@ -0,0 +1,204 @@
+package pow
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/iota.go/v3/pow"
+)
+
+const (
+	// RemoteWorkerRoutePoW is the route of a remote PoW worker that computes the nonce of a PoW job.
+	// POST with a remoteJobRequest returns a remoteJobResponse.
+	RemoteWorkerRoutePoW = "/pow"
+	// RemoteWorkerRouteHealth is the route of a remote PoW worker that returns 200 if the worker accepts jobs.
+	RemoteWorkerRouteHealth = "/health"
+)
+
+var (
+	// ErrInvalidRemoteNonce is returned if the nonce computed by a remote worker doesn't hit the target score.
+	ErrInvalidRemoteNonce = errors.New("nonce of remote worker doesn't hit the target score")
+)
+
+// remoteJobRequest is the request of a PoW job sent to a remote worker.
+type remoteJobRequest struct {
+	// The hex encoded serialized message without the nonce.
+	PoWData string `json:"powData"`
+	// The minimum PoW score the nonce has to hit.
+	TargetScore float64 `json:"targetScore"`
+}
+
+// remoteJobResponse is the response of a remote worker to a PoW job.
+type remoteJobResponse struct {
+	// The nonce as decimal string, because JSON numbers can't represent every uint64.
+	Nonce string `json:"nonce"`
+}
+
+// remoteWorker is a remote PoW worker reachable via HTTP.
+type remoteWorker struct {
+	endpoint string
+	client   *http.Client
+
+	healthyLock sync.RWMutex
+	healthy     bool
+}
+
+func newRemoteWorker(endpoint string) *remoteWorker {
+	return &remoteWorker{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   &http.Client{},
+	}
+}
+
+// Healthy returns whether the last health check or job of the worker succeeded.
+func (w *remoteWorker) Healthy() bool {
+	w.healthyLock.RLock()
+	defer w.healthyLock.RUnlock()
+
+	return w.healthy
+}
+
+// setHealthy sets the health state of the worker and returns whether it changed.
+func (w *remoteWorker) setHealthy(healthy bool) bool {
+	w.healthyLock.Lock()
+	defer w.healthyLock.Unlock()
+
+	changed := w.healthy != healthy
+	w.healthy = healthy
+
+	return changed
+}
+
+// checkHealth queries the health route of the worker.
+func (w *remoteWorker) checkHealth(ctx context.Context) error {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.endpoint+RemoteWorkerRouteHealth, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check of remote worker %s failed: %s", w.endpoint, res.Status)
+	}
+
+	return nil
+}
+
+// mine sends the PoW job to the worker and checks that the returned nonce hits the target score,
+// so a faulty or malicious worker can't make the node issue messages with an invalid PoW.
+func (w *remoteWorker) mine(ctx context.Context, data []byte, targetScore float64) (uint64, error) {
+
+	jobRequest, err := json.Marshal(&remoteJobRequest{
+		PoWData:     hex.EncodeToString(data),
+		TargetScore: targetScore,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.endpoint+RemoteWorkerRoutePoW, bytes.NewReader(jobRequest))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("PoW job of remote worker %s failed: %s", w.endpoint, res.Status)
+	}
+
+	jobResponse := &remoteJobResponse{}
+	if err := json.NewDecoder(res.Body).Decode(jobResponse); err != nil {
+		return 0, fmt.Errorf("invalid response of remote worker %s: %w", w.endpoint, err)
+	}
+
+	nonce, err := strconv.ParseUint(jobResponse.Nonce, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid nonce of remote worker %s: %w", w.endpoint, err)
+	}
+
+	if score(data, nonce) < targetScore {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidRemoteNonce, w.endpoint)
+	}
+
+	return nonce, nil
+}
+
+// score returns the PoW score of the data with the given nonce.
+func score(data []byte, nonce uint64) float64 {
+	msgData := make([]byte, len(data)+nonceBytes)
+	copy(msgData, data)
+	binary.LittleEndian.PutUint64(msgData[len(data):], nonce)
+
+	return pow.Score(msgData)
+}
+
+// RemoteWorkerServer serves PoW jobs of nodes using remote PoW on the routes of a remote worker.
+type RemoteWorkerServer struct {
+	worker      Worker
+	parallelism int
+}
+
+// NewRemoteWorkerServer creates a new remote worker server that computes the jobs with the given worker and parallelism.
+func NewRemoteWorkerServer(worker Worker, parallelism int) *RemoteWorkerServer {
+	return &RemoteWorkerServer{
+		worker:      worker,
+		parallelism: parallelism,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *RemoteWorkerServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	switch {
+	case r.URL.Path == RemoteWorkerRouteHealth && r.Method == http.MethodGet:
+		w.WriteHeader(http.StatusOK)
+
+	case r.URL.Path == RemoteWorkerRoutePoW && r.Method == http.MethodPost:
+		jobRequest := &remoteJobRequest{}
+		if err := json.NewDecoder(r.Body).Decode(jobRequest); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		data, err := hex.DecodeString(jobRequest.PoWData)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid PoW data: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		// the job is canceled if the node closes the connection, e.g. after the job timeout
+		nonce, err := s.worker.Mine(r.Context(), data, jobRequest.TargetScore, s.parallelism)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("PoW failed: %s", err), http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&remoteJobResponse{Nonce: strconv.FormatUint(nonce, 10)})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
@@ -0,0 +1,100 @@
+package pow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	"github.com/iotaledger/iota.go/v3/pow"
+)
+
+const (
+	testTargetScore = 10.0
+)
+
+func TestRemoteWorkerServer(t *testing.T) {
+
+	var jobs uint32
+	server := NewRemoteWorkerServer(&cpuWorker{}, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == RemoteWorkerRoutePoW {
+			atomic.AddUint32(&jobs, 1)
+		}
+		server.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	handler := New(testTargetScore, time.Minute, WithRemoteEndpoints(ts.URL))
+	require.Equal(t, "local-cpu", handler.PoWType())
+
+	handler.checkRemoteWorkersHealth(context.Background())
+	require.Equal(t, "remote", handler.PoWType())
+
+	data := utils.RandBytes(100)
+	nonce, err := handler.proofOfWork(context.Background(), data, 1, false)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, score(data, nonce), testTargetScore)
+	require.Equal(t, uint32(1), atomic.LoadUint32(&jobs))
+}
+
+func TestRemoteWorkerFallback(t *testing.T) {
+
+	// the worker is healthy, but returns nonces that don't hit the target score
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == RemoteWorkerRoutePoW {
+			_ = json.NewEncoder(w).Encode(&remoteJobResponse{Nonce: "0"})
+		}
+	}))
+	defer ts.Close()
+
+	handler := New(testTargetScore, time.Minute, WithRemoteEndpoints(ts.URL, "http://127.0.0.1:0"))
+	handler.checkRemoteWorkersHealth(context.Background())
+	require.True(t, handler.remoteWorkers[0].Healthy())
+	require.False(t, handler.remoteWorkers[1].Healthy())
+
+	data := utils.RandBytes(100)
+	for score(data, 0) >= testTargetScore {
+		data = utils.RandBytes(100)
+	}
+
+	_, err := handler.remoteWorkers[0].mine(context.Background(), data, testTargetScore)
+	require.ErrorIs(t, err, ErrInvalidRemoteNonce)
+
+	// the invalid nonce is rejected and the PoW is done locally
+	nonce, err := handler.proofOfWork(context.Background(), data, 1, false)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, score(data, nonce), testTargetScore)
+	require.False(t, handler.remoteWorkers[0].Healthy())
+	require.Equal(t, "local-cpu", handler.PoWType())
+}
+
+func TestRemoteWorkerCanceled(t *testing.T) {
+
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == RemoteWorkerRoutePoW {
+			// don't answer the job until the test is done
+			<-release
+		}
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	handler := New(testTargetScore, time.Minute, WithRemoteEndpoints(ts.URL))
+	handler.checkRemoteWorkersHealth(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// canceling the PoW doesn't mark the worker as unhealthy
+	_, err := handler.proofOfWork(ctx, utils.RandBytes(100), 1, false)
+	require.ErrorIs(t, err, pow.ErrCancelled)
+	require.True(t, handler.remoteWorkers[0].Healthy())
+}
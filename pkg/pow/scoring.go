@@ -0,0 +1,141 @@
+package pow
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/iotaledger/iota.go/v3/pow"
+)
+
+const (
+	// ScorerCurlP81 is the scoring of the IOTA protocol: the trailing zeros of the Curl-P-81 hash
+	// of the BLAKE2b-256 digest of the message and the nonce, divided by the message size.
+	ScorerCurlP81 = "curl-p-81"
+)
+
+// ScoreFunc returns the PoW score of the serialized message including the nonce.
+type ScoreFunc func(msgData []byte) float64
+
+var (
+	scorersLock sync.RWMutex
+	scorers     = map[string]ScoreFunc{
+		ScorerCurlP81: pow.Score,
+	}
+)
+
+// RegisterScorer registers a PoW scoring function with the given name, so it can be used in the scoring ranges.
+func RegisterScorer(name string, scoreFunc ScoreFunc) {
+	scorersLock.Lock()
+	defer scorersLock.Unlock()
+
+	if _, exists := scorers[name]; exists {
+		panic(fmt.Sprintf("PoW scorer %s registered twice", name))
+	}
+	scorers[name] = scoreFunc
+}
+
+func scorer(name string) (ScoreFunc, bool) {
+	scorersLock.RLock()
+	defer scorersLock.RUnlock()
+
+	scoreFunc, exists := scorers[name]
+	return scoreFunc, exists
+}
+
+// ScoringRange defines the scorer and the minimum PoW score required by the network from a milestone index on.
+type ScoringRange struct {
+	Scorer      string          `json:"scorer" koanf:"scorer"`
+	MinPoWScore float64         `json:"minPoWScore" koanf:"minPoWScore"`
+	StartIndex  milestone.Index `json:"start" koanf:"start"`
+}
+
+// ScoringRanges are the PoW scoring versions of a network with their start indexes.
+type ScoringRanges []*ScoringRange
+
+// scoringVersion is a ScoringRange with the resolved scoring function.
+type scoringVersion struct {
+	scorer      string
+	score       ScoreFunc
+	minPoWScore float64
+	startIndex  milestone.Index
+}
+
+// Scoring validates the PoW of messages with the scoring version that is active at a milestone index.
+type Scoring struct {
+	// the scoring versions ordered by their start index.
+	versions []*scoringVersion
+}
+
+// NewScoring creates a new Scoring from the given ranges.
+// One of the ranges must start at index 0 and the start indexes must be unique.
+func NewScoring(ranges ScoringRanges) (*Scoring, error) {
+
+	versions := make([]*scoringVersion, 0, len(ranges))
+	for _, scoringRange := range ranges {
+		scoreFunc, exists := scorer(scoringRange.Scorer)
+		if !exists {
+			return nil, fmt.Errorf("unknown PoW scorer: %s", scoringRange.Scorer)
+		}
+
+		if scoringRange.MinPoWScore < 0 {
+			return nil, fmt.Errorf("invalid minimum PoW score of scoring range starting at %d: %0.2f", scoringRange.StartIndex, scoringRange.MinPoWScore)
+		}
+
+		versions = append(versions, &scoringVersion{
+			scorer:      scoringRange.Scorer,
+			score:       scoreFunc,
+			minPoWScore: scoringRange.MinPoWScore,
+			startIndex:  scoringRange.StartIndex,
+		})
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].startIndex < versions[j].startIndex
+	})
+
+	if len(versions) == 0 || versions[0].startIndex != 0 {
+		return nil, fmt.Errorf("no PoW scoring range starting at index 0")
+	}
+
+	for i := 1; i < len(versions); i++ {
+		if versions[i].startIndex == versions[i-1].startIndex {
+			return nil, fmt.Errorf("multiple PoW scoring ranges starting at index %d", versions[i].startIndex)
+		}
+	}
+
+	return &Scoring{versions: versions}, nil
+}
+
+// version returns the scoring version that is active at the given milestone index.
+func (s *Scoring) version(index milestone.Index) *scoringVersion {
+	// the first version always starts at index 0
+	i := sort.Search(len(s.versions), func(i int) bool {
+		return s.versions[i].startIndex > index
+	})
+	return s.versions[i-1]
+}
+
+// Scorer returns the name of the scorer that is active at the given milestone index.
+func (s *Scoring) Scorer(index milestone.Index) string {
+	return s.version(index).scorer
+}
+
+// MinPoWScore returns the minimum PoW score that is required at the given milestone index.
+func (s *Scoring) MinPoWScore(index milestone.Index) float64 {
+	return s.version(index).minPoWScore
+}
+
+// Score returns the PoW score of the serialized message including the nonce with the scorer that is active at the given milestone index.
+func (s *Scoring) Score(msgData []byte, index milestone.Index) float64 {
+	return s.version(index).score(msgData)
+}
+
+// Sufficient returns the PoW score of the serialized message including the nonce and whether it reaches
+// the minimum PoW score with the scoring version that is active at the given milestone index.
+func (s *Scoring) Sufficient(msgData []byte, index milestone.Index) (float64, bool) {
+	version := s.version(index)
+	score := version.score(msgData)
+	return score, score >= version.minPoWScore
+}
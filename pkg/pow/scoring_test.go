@@ -0,0 +1,72 @@
+package pow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/iotaledger/iota.go/v3/pow"
+)
+
+func TestScoring(t *testing.T) {
+
+	// always returns the size of the message as score
+	RegisterScorer("test-size", func(msgData []byte) float64 {
+		return float64(len(msgData))
+	})
+
+	require.Panics(t, func() {
+		RegisterScorer(ScorerCurlP81, pow.Score)
+	})
+
+	_, err := NewScoring(nil)
+	require.Error(t, err)
+
+	_, err = NewScoring(ScoringRanges{{Scorer: "unknown", MinPoWScore: 100}})
+	require.Error(t, err)
+
+	// no range starting at 0
+	_, err = NewScoring(ScoringRanges{{Scorer: ScorerCurlP81, MinPoWScore: 100, StartIndex: 10}})
+	require.Error(t, err)
+
+	_, err = NewScoring(ScoringRanges{
+		{Scorer: ScorerCurlP81, MinPoWScore: 100},
+		{Scorer: "test-size", MinPoWScore: 100},
+	})
+	require.Error(t, err)
+
+	scoring, err := NewScoring(ScoringRanges{
+		{Scorer: "test-size", MinPoWScore: 50, StartIndex: 20},
+		{Scorer: ScorerCurlP81, MinPoWScore: 100, StartIndex: 0},
+		{Scorer: ScorerCurlP81, MinPoWScore: 200, StartIndex: 10},
+	})
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		index       milestone.Index
+		scorer      string
+		minPoWScore float64
+	}{
+		{0, ScorerCurlP81, 100},
+		{9, ScorerCurlP81, 100},
+		{10, ScorerCurlP81, 200},
+		{19, ScorerCurlP81, 200},
+		{20, "test-size", 50},
+		{1000, "test-size", 50},
+	} {
+		require.Equal(t, test.scorer, scoring.Scorer(test.index))
+		require.Equal(t, test.minPoWScore, scoring.MinPoWScore(test.index))
+	}
+
+	msgData := make([]byte, 100)
+	require.Equal(t, pow.Score(msgData), scoring.Score(msgData, 0))
+
+	score, sufficient := scoring.Sufficient(msgData, 20)
+	require.Equal(t, 100.0, score)
+	require.True(t, sufficient)
+
+	score, sufficient = scoring.Sufficient(msgData[:40], 20)
+	require.Equal(t, 40.0, score)
+	require.False(t, sufficient)
+}
@@ -6,20 +6,36 @@ type Profile struct {
 }
 
 type Caches struct {
-	Addresses              *CacheOpts `koanf:"addresses"`
-	Children               *CacheOpts `koanf:"children"`
-	Milestones             *CacheOpts `koanf:"milestones"`
-	Messages               *CacheOpts `koanf:"messages"`
-	IncomingMessagesFilter *CacheOpts `koanf:"incomingMessagesFilter"`
-	UnreferencedMessages   *CacheOpts `koanf:"unreferencedMessages"`
+	Addresses              *CacheOpts        `koanf:"addresses"`
+	Children               *CacheOpts        `koanf:"children"`
+	Milestones             *CacheOpts        `koanf:"milestones"`
+	Messages               *CacheOpts        `koanf:"messages"`
+	IncomingMessagesFilter *CacheOpts        `koanf:"incomingMessagesFilter"`
+	UnreferencedMessages   *CacheOpts        `koanf:"unreferencedMessages"`
+	RequestQueue           *RequestQueueOpts `koanf:"requestQueue"`
 }
 
 type CacheOpts struct {
-	CacheTime                  string             `koanf:"cacheTime"`
+	CacheTime string `koanf:"cacheTime"`
+	// MaxSize is the maximum amount of released objects kept in the cache for CacheTime.
+	// If the cache is full, the oldest objects are evicted. 0 means unlimited.
+	// Only used by the incoming messages filter.
+	MaxSize int `koanf:"maxSize"`
+	// PartitionKey defines the lengths in bytes of the partitions the keys of the cache are split into.
+	// More partitions speed up iterations by a prefix, fewer partitions speed up lookups of single objects.
+	// Only used by the children and unreferenced messages caches, the default layout is used if empty.
+	PartitionKey               []int              `koanf:"partitionKey"`
 	ReleaseExecutorWorkerCount int                `koanf:"releaseExecutorWorkerCount"`
 	LeakDetectionOptions       *LeakDetectionOpts `koanf:"leakDetection"`
 }
 
+// RequestQueueOpts defines the size of the queue of requested messages.
+// The requests are dropped after the time defined by "requests.discardOlderThan".
+type RequestQueueOpts struct {
+	// MaxSize is the maximum amount of queued and pending requests. 0 means unlimited.
+	MaxSize int `koanf:"maxSize"`
+}
+
 type LeakDetectionOpts struct {
 	Enabled               bool   `koanf:"enabled"`
 	MaxConsumersPerObject int    `koanf:"maxConsumersPerObject"`
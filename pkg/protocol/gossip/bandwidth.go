@@ -0,0 +1,68 @@
+package gossip
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+
+	"github.com/iotaledger/hive.go/protocol/tlv"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// the maximum size of a gossip packet including its header.
+const maxGossipMessageSize = tlv.HeaderBytesLength + iotago.MessageBinSerializedMaxSize
+
+// newBandwidthLimiter creates a token bucket which limits the bandwidth to the given bytes per second.
+// The burst is at least as big as the biggest gossip message, so that every message can pass the limiter.
+// Returns nil if bytesPerSecond is zero, which means the bandwidth is unlimited.
+func newBandwidthLimiter(bytesPerSecond int) *rate.Limiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+
+	burst := bytesPerSecond
+	if burst < maxGossipMessageSize {
+		burst = maxGossipMessageSize
+	}
+
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), burst)
+}
+
+// readerFunc is an io.Reader which reads with the given function.
+type readerFunc func(buf []byte) (int, error)
+
+func (f readerFunc) Read(buf []byte) (int, error) {
+	return f(buf)
+}
+
+// writerFunc is an io.Writer which writes with the given function.
+type writerFunc func(data []byte) (int, error)
+
+func (f writerFunc) Write(data []byte) (int, error) {
+	return f(data)
+}
+
+// waitForBandwidth blocks until all given limiters allow n bytes to pass.
+// nil limiters are ignored.
+func waitForBandwidth(ctx context.Context, n int, limiters ...*rate.Limiter) error {
+	for _, limiter := range limiters {
+		if limiter == nil {
+			continue
+		}
+
+		// n might exceed the burst of the limiter if several messages were read at once
+		for remaining := n; remaining > 0; {
+			chunk := remaining
+			if chunk > limiter.Burst() {
+				chunk = limiter.Burst()
+			}
+
+			if err := limiter.WaitN(ctx, chunk); err != nil {
+				return err
+			}
+			remaining -= chunk
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package gossip
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestBandwidthLimiterUnlimited(t *testing.T) {
+	require.Nil(t, newBandwidthLimiter(0))
+	require.Nil(t, newBandwidthLimiter(-1))
+
+	// nil limiters don't block, even if the context is already done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, waitForBandwidth(ctx, 10*maxGossipMessageSize, nil, nil))
+}
+
+func TestBandwidthLimiterBurst(t *testing.T) {
+	// every message has to pass the limiter, even if the limit is lower than the biggest message
+	limiter := newBandwidthLimiter(1024)
+	require.Equal(t, rate.Limit(1024), limiter.Limit())
+	require.Equal(t, maxGossipMessageSize, limiter.Burst())
+
+	limiter = newBandwidthLimiter(10 * maxGossipMessageSize)
+	require.Equal(t, 10*maxGossipMessageSize, limiter.Burst())
+}
+
+func TestWaitForBandwidthLimit(t *testing.T) {
+	const bytesPerSecond = 100 * 1024
+	limiter := newBandwidthLimiter(bytesPerSecond)
+
+	// the burst passes immediately
+	ts := time.Now()
+	require.NoError(t, waitForBandwidth(context.Background(), limiter.Burst(), limiter))
+	require.Less(t, time.Since(ts), 100*time.Millisecond)
+
+	// more bytes than the burst are waited for in chunks at the given rate
+	ts = time.Now()
+	require.NoError(t, waitForBandwidth(context.Background(), bytesPerSecond/2, limiter))
+	require.GreaterOrEqual(t, time.Since(ts), 400*time.Millisecond)
+}
+
+func TestWaitForBandwidthAllLimiters(t *testing.T) {
+	fast := newBandwidthLimiter(10 * 1024 * 1024)
+	slow := newBandwidthLimiter(100 * 1024)
+
+	// the slowest limiter determines the wait
+	ts := time.Now()
+	require.NoError(t, waitForBandwidth(context.Background(), slow.Burst()+50*1024, fast, nil, slow))
+	require.GreaterOrEqual(t, time.Since(ts), 400*time.Millisecond)
+}
+
+func TestWaitForBandwidthCancel(t *testing.T) {
+	limiter := newBandwidthLimiter(1024)
+	require.NoError(t, waitForBandwidth(context.Background(), limiter.Burst(), limiter))
+
+	// the limiter is exhausted, so the wait is aborted by the context
+	ctx, cancel := context.WithCancel(context.Background())
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- waitForBandwidth(ctx, limiter.Burst(), limiter)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errChan:
+		require.Error(t, err)
+	case <-time.After(time.Second):
+		require.FailNow(t, "waiting for bandwidth was not cancelled")
+	}
+}
@@ -0,0 +1,175 @@
+package gossip
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/iotaledger/hive.go/protocol/message"
+	"github.com/iotaledger/hive.go/protocol/tlv"
+)
+
+// PacketDirection tells whether a packet was received from or sent to a peer.
+type PacketDirection string
+
+const (
+	// PacketDirectionIn is a packet which was received from the peer.
+	PacketDirectionIn PacketDirection = "in"
+	// PacketDirectionOut is a packet which was sent to the peer.
+	PacketDirectionOut PacketDirection = "out"
+)
+
+// CapturedPacket holds the information about a gossip packet recorded by a PacketCapture.
+type CapturedPacket struct {
+	// Whether the packet was received or sent.
+	Direction PacketDirection
+	// The message type of the packet.
+	Type message.Type
+	// The size of the packet without the TLV header.
+	Size int
+	// The time the packet was received or sent.
+	Time time.Time
+	// The ID of the message of a message packet, or the requested message ID of a message request.
+	MessageID hornet.MessageID
+	// The requested milestone index of a milestone request.
+	MilestoneIndex milestone.Index
+}
+
+// PacketCapture records the next gossip packets exchanged with a peer until its limit is reached.
+type PacketCapture struct {
+	sync.RWMutex
+	// the amount of packets after which the capture stops.
+	limit int
+	// the time the capture was started.
+	startedAt time.Time
+	// the recorded packets.
+	packets []*CapturedPacket
+}
+
+// NewPacketCapture creates a new PacketCapture which records up to limit packets.
+func NewPacketCapture(limit int) *PacketCapture {
+	if limit < 1 {
+		limit = 1
+	}
+
+	return &PacketCapture{
+		limit:     limit,
+		startedAt: time.Now(),
+		packets:   make([]*CapturedPacket, 0, limit),
+	}
+}
+
+// record adds the packet of the given type with the given data (without the TLV header) to the capture.
+// Packets are dropped once the limit is reached.
+func (c *PacketCapture) record(direction PacketDirection, msgType message.Type, data []byte) {
+	c.Lock()
+	defer c.Unlock()
+
+	if len(c.packets) >= c.limit {
+		return
+	}
+
+	packet := &CapturedPacket{
+		Direction: direction,
+		Type:      msgType,
+		Size:      len(data),
+		Time:      time.Now(),
+	}
+
+	switch msgType {
+	case MessageTypeMessage:
+		messageID := blake2b.Sum256(data)
+		packet.MessageID = hornet.MessageIDFromArray(messageID)
+
+	case MessageTypeMessageRequest:
+		if len(data) == RequestedMessageIDMsgBytesLength {
+			packet.MessageID = hornet.MessageIDFromSlice(data)
+		}
+
+	case MessageTypeMilestoneRequest:
+		if len(data) == RequestedMilestoneIndexMsgBytesLength {
+			packet.MilestoneIndex = milestone.Index(binary.LittleEndian.Uint32(data))
+		}
+	}
+
+	c.packets = append(c.packets, packet)
+}
+
+// Limit returns the amount of packets after which the capture stops.
+func (c *PacketCapture) Limit() int {
+	return c.limit
+}
+
+// StartedAt returns the time the capture was started.
+func (c *PacketCapture) StartedAt() time.Time {
+	return c.startedAt
+}
+
+// Done tells whether the limit of the capture was reached.
+func (c *PacketCapture) Done() bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return len(c.packets) >= c.limit
+}
+
+// Packets returns the recorded packets in the order they were received or sent.
+func (c *PacketCapture) Packets() []*CapturedPacket {
+	c.RLock()
+	defer c.RUnlock()
+
+	packets := make([]*CapturedPacket, len(c.packets))
+	copy(packets, c.packets)
+
+	return packets
+}
+
+// StartCapture starts recording the next packets exchanged with the peer, up to the given limit.
+// A previous capture of the protocol is replaced.
+func (p *Protocol) StartCapture(limit int) *PacketCapture {
+	capture := NewPacketCapture(limit)
+
+	p.captureLock.Lock()
+	defer p.captureLock.Unlock()
+	p.capture = capture
+
+	return capture
+}
+
+// StopCapture stops the capture of the protocol and returns it, nil if there was none.
+func (p *Protocol) StopCapture() *PacketCapture {
+	p.captureLock.Lock()
+	defer p.captureLock.Unlock()
+
+	capture := p.capture
+	p.capture = nil
+
+	return capture
+}
+
+// Capture returns the current capture of the protocol, nil if there is none.
+func (p *Protocol) Capture() *PacketCapture {
+	p.captureLock.RLock()
+	defer p.captureLock.RUnlock()
+
+	return p.capture
+}
+
+// adds the given packet to the current capture of the protocol, if any.
+func (p *Protocol) capturePacket(direction PacketDirection, msgType message.Type, data []byte) {
+	if capture := p.Capture(); capture != nil {
+		capture.record(direction, msgType, data)
+	}
+}
+
+// adds the given packet including its TLV header to the current capture of the protocol, if any.
+func (p *Protocol) captureSentPacket(packet []byte) {
+	if len(packet) < tlv.HeaderBytesLength {
+		return
+	}
+	p.capturePacket(PacketDirectionOut, message.Type(packet[0]), packet[tlv.HeaderBytesLength:])
+}
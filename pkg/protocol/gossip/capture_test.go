@@ -0,0 +1,53 @@
+package gossip_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	"github.com/gohornet/hornet/pkg/protocol/gossip"
+)
+
+func TestProtocolCapture(t *testing.T) {
+	proto := newTestProtocol(t)
+
+	parsePacket := func(packet []byte, err error) {
+		require.NoError(t, err)
+		_, err = proto.Parse(packet)
+		require.NoError(t, err)
+	}
+
+	// packets are only recorded while a capture is running
+	parsePacket(gossip.NewHeartbeatMsg(1, 0, 1, 1, 1))
+	require.Nil(t, proto.Capture())
+
+	capture := proto.StartCapture(2)
+	require.Equal(t, capture, proto.Capture())
+
+	requestedMessageID := utils.RandMessageID()
+	parsePacket(gossip.NewMessageRequestMsg(requestedMessageID))
+	require.False(t, capture.Done())
+	parsePacket(gossip.NewMilestoneRequestMsg(42))
+	require.True(t, capture.Done())
+
+	// the limit was reached
+	parsePacket(gossip.NewHeartbeatMsg(1, 0, 1, 1, 1))
+
+	packets := capture.Packets()
+	require.Len(t, packets, 2)
+
+	require.Equal(t, gossip.PacketDirectionIn, packets[0].Direction)
+	require.Equal(t, gossip.MessageTypeMessageRequest, packets[0].Type)
+	require.Equal(t, gossip.RequestedMessageIDMsgBytesLength, packets[0].Size)
+	require.Equal(t, requestedMessageID, packets[0].MessageID)
+
+	require.Equal(t, gossip.MessageTypeMilestoneRequest, packets[1].Type)
+	require.Equal(t, milestone.Index(42), packets[1].MilestoneIndex)
+	require.Nil(t, packets[1].MessageID)
+
+	require.Equal(t, capture, proto.StopCapture())
+	require.Nil(t, proto.Capture())
+	require.Nil(t, proto.StopCapture())
+}
@@ -0,0 +1,82 @@
+package gossip
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/golang/snappy"
+	"github.com/libp2p/go-libp2p-core/protocol"
+)
+
+// Compression is a compression mode applied to a gossip stream.
+type Compression string
+
+const (
+	// CompressionNone sends the gossip messages uncompressed.
+	CompressionNone Compression = "none"
+	// CompressionSnappy compresses the gossip messages using the snappy framing format.
+	CompressionSnappy Compression = "snappy"
+)
+
+// ParseCompression parses the given compression mode.
+func ParseCompression(compression string) (Compression, error) {
+	switch Compression(compression) {
+	case "", CompressionNone:
+		return CompressionNone, nil
+	case CompressionSnappy:
+		return CompressionSnappy, nil
+	default:
+		return "", fmt.Errorf("unknown gossip compression: %s", compression)
+	}
+}
+
+// returns the protocol ID which is used to negotiate the given compression with a peer.
+func compressedProtocolID(protocolID protocol.ID, compression Compression) protocol.ID {
+	if compression == CompressionNone {
+		return protocolID
+	}
+	return protocol.ID(fmt.Sprintf("%s/%s", protocolID, compression))
+}
+
+// returns the supported protocol IDs in the order of preference and the compression of each protocol ID.
+// the compressed protocol is preferred, the uncompressed one is always supported as a fallback.
+func supportedProtocols(protocolID protocol.ID, compression Compression) ([]protocol.ID, map[protocol.ID]Compression) {
+	protocols := []protocol.ID{}
+	compressions := map[protocol.ID]Compression{protocolID: CompressionNone}
+
+	if compression != CompressionNone {
+		compressedProtocolID := compressedProtocolID(protocolID, compression)
+		protocols = append(protocols, compressedProtocolID)
+		compressions[compressedProtocolID] = compression
+	}
+
+	return append(protocols, protocolID), compressions
+}
+
+// wraps the given reader to decompress data read from it.
+func newCompressionReader(r io.Reader, compression Compression) io.Reader {
+	switch compression {
+	case CompressionSnappy:
+		return snappy.NewReader(r)
+	default:
+		return r
+	}
+}
+
+// compressionWriter is an io.Writer which compresses all data written to it.
+type compressionWriter interface {
+	io.Writer
+	// Flush writes all pending compressed data to the underlying writer.
+	Flush() error
+}
+
+// wraps the given writer to compress data written to it.
+// returns nil if the compression mode doesn't compress.
+func newCompressionWriter(w io.Writer, compression Compression) compressionWriter {
+	switch compression {
+	case CompressionSnappy:
+		return snappy.NewBufferedWriter(w)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,213 @@
+package gossip
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/storage"
+)
+
+const (
+	// EmitQueueSize is the maximum amount of queued emissions per priority.
+	EmitQueueSize = 10000
+)
+
+var (
+	// ErrEmitQueueFull is returned if a message can't be emitted because the queue of its priority is full.
+	ErrEmitQueueFull = errors.New("emit queue is full")
+	// ErrEmitQueueClosed is returned if a message can't be emitted because the message processor was shut down.
+	ErrEmitQueueClosed = errors.New("emit queue is closed")
+)
+
+// EmitPriority is the priority of a message emitted by the node itself.
+// Emissions with a higher priority are processed before all queued emissions with a lower priority.
+type EmitPriority int
+
+const (
+	// EmitPriorityLow is used for bulk emissions like spam.
+	EmitPriorityLow EmitPriority = iota
+	// EmitPriorityNormal is used for messages submitted via the API.
+	EmitPriorityNormal
+	// EmitPriorityHigh is used for critical emissions like milestones, checkpoints or faucet transactions.
+	EmitPriorityHigh
+)
+
+// String returns the name of the priority.
+func (p EmitPriority) String() string {
+	switch p {
+	case EmitPriorityLow:
+		return "low"
+	case EmitPriorityNormal:
+		return "normal"
+	case EmitPriorityHigh:
+		return "high"
+	default:
+		return fmt.Sprintf("unknown (%d)", p)
+	}
+}
+
+// emitPriorities are all priorities, sorted from the highest to the lowest.
+var emitPriorities = []EmitPriority{EmitPriorityHigh, EmitPriorityNormal, EmitPriorityLow}
+
+// EmitQueueStats holds the statistics of the queued emissions of a priority.
+type EmitQueueStats struct {
+	// The priority of the emissions.
+	Priority EmitPriority
+	// The amount of queued emissions.
+	Count int
+	// The time the oldest emission is already queued.
+	OldestAge time.Duration
+}
+
+// emission is a message queued to be emitted.
+type emission struct {
+	msg        *storage.Message
+	queuedTime time.Time
+}
+
+// emitQueue holds validated messages until their events are triggered, ordered by priority.
+type emitQueue struct {
+	sync.Mutex
+	// the queued emissions per priority in FIFO order.
+	queues map[EmitPriority][]*emission
+	// used to signal new emissions to the running queue.
+	signal chan struct{}
+	// indicates that the queue doesn't accept emissions anymore.
+	closed bool
+}
+
+func newEmitQueue() *emitQueue {
+	queues := make(map[EmitPriority][]*emission, len(emitPriorities))
+	for _, priority := range emitPriorities {
+		queues[priority] = make([]*emission, 0)
+	}
+
+	return &emitQueue{
+		queues: queues,
+		signal: make(chan struct{}, 1),
+	}
+}
+
+// push adds the given message to the queue of the given priority.
+func (q *emitQueue) push(msg *storage.Message, priority EmitPriority) error {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.closed {
+		return ErrEmitQueueClosed
+	}
+
+	queue, exists := q.queues[priority]
+	if !exists {
+		return fmt.Errorf("unknown emit priority %d", priority)
+	}
+
+	if len(queue) >= EmitQueueSize {
+		return errors.WithMessagef(ErrEmitQueueFull, "priority: %s", priority)
+	}
+
+	q.queues[priority] = append(queue, &emission{msg: msg, queuedTime: time.Now()})
+
+	select {
+	case q.signal <- struct{}{}:
+	default:
+		// the queue was already signaled
+	}
+
+	return nil
+}
+
+// pop returns the oldest emission with the highest priority, or nil if the queue is empty.
+func (q *emitQueue) pop() *emission {
+	q.Lock()
+	defer q.Unlock()
+
+	for _, priority := range emitPriorities {
+		queue := q.queues[priority]
+		if len(queue) == 0 {
+			continue
+		}
+
+		e := queue[0]
+		queue[0] = nil
+		q.queues[priority] = queue[1:]
+
+		return e
+	}
+
+	return nil
+}
+
+// stats returns the statistics of the queued emissions, sorted from the highest to the lowest priority.
+func (q *emitQueue) stats() []*EmitQueueStats {
+	q.Lock()
+	defer q.Unlock()
+
+	now := time.Now()
+
+	stats := make([]*EmitQueueStats, 0, len(emitPriorities))
+	for _, priority := range emitPriorities {
+		queue := q.queues[priority]
+
+		var oldestAge time.Duration
+		if len(queue) > 0 {
+			oldestAge = now.Sub(queue[0].queuedTime)
+		}
+
+		stats = append(stats, &EmitQueueStats{
+			Priority:  priority,
+			Count:     len(queue),
+			OldestAge: oldestAge,
+		})
+	}
+
+	return stats
+}
+
+// close closes the queue and returns the remaining emissions, sorted from the highest to the lowest priority.
+func (q *emitQueue) close() []*emission {
+	q.Lock()
+	defer q.Unlock()
+
+	q.closed = true
+
+	var remaining []*emission
+	for _, priority := range emitPriorities {
+		remaining = append(remaining, q.queues[priority]...)
+		q.queues[priority] = nil
+	}
+
+	return remaining
+}
+
+// run passes the queued emissions to the given emit function until the context is done.
+// Afterwards the queue is closed and the remaining emissions are passed to the given drop function,
+// so that callers waiting for the emitted messages can be notified.
+func (q *emitQueue) run(ctx context.Context, emitFunc func(msg *storage.Message), dropFunc func(msg *storage.Message)) {
+	defer func() {
+		for _, e := range q.close() {
+			dropFunc(e.msg)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.signal:
+		}
+
+		for e := q.pop(); e != nil; e = q.pop() {
+			if ctx.Err() != nil {
+				// the emission was already taken from the queue
+				dropFunc(e.msg)
+				return
+			}
+			emitFunc(e.msg)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+//go:build gofuzz
+// +build gofuzz
+
+package gossip
+
+// This file contains the fuzzing entry points for the gossip protocol, which are only built with the "gofuzz" tag.
+// They can be run with go-fuzz:
+//
+//	go-fuzz-build -func FuzzPacketParser github.com/gohornet/hornet/pkg/protocol/gossip
+//	go-fuzz -bin gossip-fuzz.zip -workdir fuzz/packet_parser
+//
+// or built as a libFuzzer archive by passing "-libfuzzer" to go-fuzz-build.
+//
+// The entry points call the unguarded parsing functions, so that the fuzzer sees the panics
+// which are recovered in the node.
+
+import (
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/protocol"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/pow"
+)
+
+var fuzzDeSeriParas = &iotago.DeSerializationParameters{
+	RentStructure: &iotago.RentStructure{},
+}
+
+// FuzzPacketParser feeds the data as a stream of packets into a parser with the handlers of all packet types.
+func FuzzPacketParser(data []byte) int {
+	parser := protocol.New(gossipMessageRegistry)
+
+	received := 0
+	parser.Events.Received[MessageTypeMessage].Attach(events.NewClosure(func(data []byte) {
+		received++
+		fuzzMessage(data)
+	}))
+	parser.Events.Received[MessageTypeMessageRequest].Attach(events.NewClosure(func(data []byte) {
+		received++
+		if len(data) == iotago.MessageIDLength {
+			_ = hornet.MessageIDFromSlice(data)
+		}
+	}))
+	parser.Events.Received[MessageTypeMilestoneRequest].Attach(events.NewClosure(func(data []byte) {
+		received++
+		_, _ = ExtractRequestedMilestoneIndex(data)
+	}))
+	parser.Events.Received[MessageTypeHeartbeat].Attach(events.NewClosure(func(data []byte) {
+		received++
+		_, _ = ParseHeartbeat(data)
+	}))
+
+	if _, err := parser.Read(data); err != nil {
+		// the error must be classified
+		_ = classifyParseError(err)
+		return 0
+	}
+
+	if received == 0 {
+		return 0
+	}
+	return 1
+}
+
+// FuzzMessage runs the checks of the message processor which don't need a storage on the data of a message packet.
+func FuzzMessage(data []byte) int {
+	if !fuzzMessage(data) {
+		return 0
+	}
+	return 1
+}
+
+// fuzzMessage deserializes and checks the given message data and tells whether the message is valid.
+func fuzzMessage(data []byte) bool {
+	msg, err := storage.MessageFromBytes(data, serializer.DeSeriModePerformValidation, fuzzDeSeriParas)
+	if err != nil {
+		return false
+	}
+
+	_ = msg.MessageID()
+	_ = msg.NetworkID()
+	_ = pow.Score(data)
+	if msg.IsMilestone() {
+		_ = msg.Milestone().Index
+	}
+
+	return true
+}
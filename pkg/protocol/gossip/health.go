@@ -0,0 +1,171 @@
+package gossip
+
+import (
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// Health is the health of a gossip protocol stream with a neighbor,
+// evaluated over the time since the last health check.
+type Health struct {
+	// The score of the neighbor in the range of 0 (dead weight) to 1 (healthy).
+	Score float64 `json:"score"`
+	// The ratio of new messages to all messages received from the neighbor.
+	NewMessagesRatio float64 `json:"newMessagesRatio"`
+	// The ratio of already known messages to all messages received from the neighbor.
+	KnownMessagesRatio float64 `json:"knownMessagesRatio"`
+	// The amount of invalid messages received from the neighbor.
+	InvalidMessages uint32 `json:"invalidMessages"`
+	// The age of the latest heartbeat of the neighbor.
+	HeartbeatAge time.Duration `json:"heartbeatAge"`
+	// Whether the score of the neighbor is below the threshold since longer than the configured time.
+	Unhealthy bool `json:"unhealthy"`
+}
+
+// the state of a neighbor tracked between two health checks.
+type healthState struct {
+	lastMetrics MetricsSnapshot
+	belowSince  time.Time
+}
+
+// HealthTracker scores the gossip protocol streams with neighbors based on the
+// usefulness of the received messages, the share of already known messages,
+// invalid messages and the liveness of their heartbeats.
+type HealthTracker struct {
+	// the score below which a neighbor is considered unhealthy.
+	minScore float64
+	// the time the score of a neighbor has to stay below minScore until it is marked as unhealthy.
+	dropAfter time.Duration
+	// the interval in which heartbeats are expected.
+	heartbeatInterval time.Duration
+	// the time after which a neighbor without heartbeats is considered dead.
+	heartbeatTimeout time.Duration
+
+	statesLock sync.Mutex
+	states     map[peer.ID]*healthState
+}
+
+// NewHealthTracker creates a new HealthTracker.
+// Neighbors are marked as unhealthy if their score stays below minScore for longer than dropAfter.
+func NewHealthTracker(minScore float64, dropAfter time.Duration, heartbeatInterval time.Duration, heartbeatTimeout time.Duration) *HealthTracker {
+	return &HealthTracker{
+		minScore:          minScore,
+		dropAfter:         dropAfter,
+		heartbeatInterval: heartbeatInterval,
+		heartbeatTimeout:  heartbeatTimeout,
+		states:            make(map[peer.ID]*healthState),
+	}
+}
+
+// Update evaluates the health of the given protocols since the last call to Update
+// and stores the result, which is returned by Protocol.Health. States of neighbors which are not part
+// of the given protocols anymore are removed.
+func (t *HealthTracker) Update(protos []*Protocol, now time.Time) {
+	t.statesLock.Lock()
+	defer t.statesLock.Unlock()
+
+	deltas := make(map[peer.ID]MetricsSnapshot, len(protos))
+
+	// the amount of new messages received from all neighbors, used to determine the fair share of every neighbor
+	var totalNewMessages uint32
+	for _, proto := range protos {
+		metrics := proto.Metrics.Snapshot()
+
+		state, has := t.states[proto.PeerID]
+		if !has {
+			state = &healthState{}
+			t.states[proto.PeerID] = state
+		}
+
+		deltas[proto.PeerID] = MetricsSnapshot{
+			NewMessages:      metrics.NewMessages - state.lastMetrics.NewMessages,
+			KnownMessages:    metrics.KnownMessages - state.lastMetrics.KnownMessages,
+			ReceivedMessages: metrics.ReceivedMessages - state.lastMetrics.ReceivedMessages,
+			InvalidMessages:  metrics.InvalidMessages - state.lastMetrics.InvalidMessages,
+		}
+		totalNewMessages += deltas[proto.PeerID].NewMessages
+		state.lastMetrics = metrics
+	}
+
+	for peerID := range t.states {
+		if _, has := deltas[peerID]; !has {
+			delete(t.states, peerID)
+		}
+	}
+
+	for _, proto := range protos {
+		state := t.states[proto.PeerID]
+		health := t.score(deltas[proto.PeerID], totalNewMessages, len(protos), t.heartbeatAge(proto, now))
+
+		switch {
+		case health.Score >= t.minScore:
+			state.belowSince = time.Time{}
+		case state.belowSince.IsZero():
+			state.belowSince = now
+		default:
+			health.Unhealthy = now.Sub(state.belowSince) >= t.dropAfter
+		}
+
+		proto.setHealth(health)
+	}
+}
+
+// returns the age of the latest heartbeat of the given protocol.
+// if no heartbeat was received yet, the age of the stream is returned.
+func (t *HealthTracker) heartbeatAge(proto *Protocol, now time.Time) time.Duration {
+	if proto.HeartbeatReceivedTime.IsZero() {
+		return now.Sub(proto.Stream.Stat().Opened)
+	}
+	return now.Sub(proto.HeartbeatReceivedTime)
+}
+
+// computes the health of a neighbor given the metric changes since the last check.
+func (t *HealthTracker) score(delta MetricsSnapshot, totalNewMessages uint32, neighborsCount int, heartbeatAge time.Duration) *Health {
+	health := &Health{
+		InvalidMessages: delta.InvalidMessages,
+		HeartbeatAge:    heartbeatAge,
+	}
+
+	if delta.ReceivedMessages > 0 {
+		health.NewMessagesRatio = float64(delta.NewMessages) / float64(delta.ReceivedMessages)
+		health.KnownMessagesRatio = float64(delta.KnownMessages) / float64(delta.ReceivedMessages)
+	}
+
+	// the usefulness compares the new messages of the neighbor to its fair share of all new messages.
+	// if no new messages were received at all, the network is idle and no neighbor is penalized.
+	usefulness := 1.0
+	if totalNewMessages > 0 {
+		usefulness = float64(delta.NewMessages) * float64(neighborsCount) / float64(totalNewMessages)
+		if usefulness > 1.0 {
+			usefulness = 1.0
+		}
+	}
+
+	// the efficiency penalizes neighbors which send more already known messages than expected.
+	// every message is received from all neighbors, so a share of 1-1/neighborsCount known messages is normal.
+	efficiency := 1.0
+	if neighborsCount > 0 {
+		efficiency = (1.0 - health.KnownMessagesRatio) * float64(neighborsCount)
+		if efficiency > 1.0 {
+			efficiency = 1.0
+		}
+	}
+
+	// the liveness decreases linearly as soon as heartbeats are overdue and reaches zero at the heartbeat timeout.
+	liveness := 1.0
+	if heartbeatAge > t.heartbeatInterval {
+		liveness = 1.0 - float64(heartbeatAge-t.heartbeatInterval)/float64(t.heartbeatTimeout-t.heartbeatInterval)
+		if liveness < 0 {
+			liveness = 0
+		}
+	}
+
+	health.Score = usefulness * efficiency * liveness
+	if delta.InvalidMessages > 0 {
+		health.Score = 0
+	}
+
+	return health
+}
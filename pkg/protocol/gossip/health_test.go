@@ -0,0 +1,146 @@
+package gossip_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/metrics"
+	"github.com/gohornet/hornet/pkg/protocol/gossip"
+)
+
+func TestHealthTracker(t *testing.T) {
+
+	const (
+		heartbeatInterval = 30 * time.Second
+		heartbeatTimeout  = 100 * time.Second
+		dropAfter         = time.Minute
+	)
+
+	now := time.Now()
+
+	newProto := func(id string) *gossip.Protocol {
+		proto := gossip.NewProtocol(peer.ID(id), nil, 10, time.Second, time.Second, &metrics.ServerMetrics{})
+		proto.HeartbeatReceivedTime = now
+		return proto
+	}
+
+	useful := newProto("useful")
+	duplicates := newProto("duplicates")
+	invalid := newProto("invalid")
+	protos := []*gossip.Protocol{useful, duplicates, invalid}
+
+	tracker := gossip.NewHealthTracker(0.5, dropAfter, heartbeatInterval, heartbeatTimeout)
+
+	receive := func() {
+		useful.Metrics.ReceivedMessages.Add(10)
+		useful.Metrics.NewMessages.Add(10)
+		duplicates.Metrics.ReceivedMessages.Add(10)
+		duplicates.Metrics.KnownMessages.Add(10)
+		invalid.Metrics.ReceivedMessages.Add(10)
+		invalid.Metrics.NewMessages.Add(5)
+		invalid.Metrics.InvalidMessages.Inc()
+		for _, proto := range protos {
+			proto.HeartbeatReceivedTime = now
+		}
+	}
+
+	receive()
+	tracker.Update(protos, now)
+
+	require.Equal(t, 1.0, useful.Health().Score)
+	require.Equal(t, 1.0, useful.Health().NewMessagesRatio)
+	require.Equal(t, 0.0, duplicates.Health().Score)
+	require.Equal(t, 1.0, duplicates.Health().KnownMessagesRatio)
+	require.Equal(t, 0.0, invalid.Health().Score)
+	require.EqualValues(t, 1, invalid.Health().InvalidMessages)
+
+	// the score has to stay below the minimum for longer than dropAfter
+	for _, proto := range protos {
+		require.False(t, proto.Health().Unhealthy)
+	}
+
+	now = now.Add(dropAfter)
+	receive()
+	tracker.Update(protos, now)
+
+	require.False(t, useful.Health().Unhealthy)
+	require.True(t, duplicates.Health().Unhealthy)
+	require.True(t, invalid.Health().Unhealthy)
+
+	// an idle network doesn't penalize neighbors, but missing heartbeats do
+	now = now.Add(heartbeatInterval + (heartbeatTimeout-heartbeatInterval)/2)
+	useful.HeartbeatReceivedTime = now
+	tracker.Update(protos, now)
+
+	require.Equal(t, 1.0, useful.Health().Score)
+	require.InDelta(t, 0.5, duplicates.Health().Score, 0.0001)
+	require.InDelta(t, 0.5, invalid.Health().Score, 0.0001)
+	require.Equal(t, heartbeatInterval+(heartbeatTimeout-heartbeatInterval)/2, duplicates.Health().HeartbeatAge)
+
+	// a recovered neighbor is healthy again
+	require.False(t, duplicates.Health().Unhealthy)
+}
+
+func TestHealthTrackerKnownMessages(t *testing.T) {
+
+	now := time.Now()
+
+	newProto := func(id string) *gossip.Protocol {
+		proto := gossip.NewProtocol(peer.ID(id), nil, 10, time.Second, time.Second, &metrics.ServerMetrics{})
+		proto.HeartbeatReceivedTime = now
+		return proto
+	}
+
+	useful := newProto("useful")
+	flooding := newProto("flooding")
+	protos := []*gossip.Protocol{useful, flooding}
+
+	tracker := gossip.NewHealthTracker(0.5, time.Minute, 30*time.Second, 100*time.Second)
+
+	// both neighbors deliver their fair share of new messages,
+	// half of the messages are expected to be known already with two neighbors
+	useful.Metrics.ReceivedMessages.Add(20)
+	useful.Metrics.NewMessages.Add(10)
+	useful.Metrics.KnownMessages.Add(10)
+
+	// but the flooding neighbor sends lots of duplicates in addition
+	flooding.Metrics.ReceivedMessages.Add(100)
+	flooding.Metrics.NewMessages.Add(10)
+	flooding.Metrics.KnownMessages.Add(90)
+
+	tracker.Update(protos, now)
+
+	require.Equal(t, 1.0, useful.Health().Score)
+	require.Equal(t, 0.5, useful.Health().KnownMessagesRatio)
+	require.InDelta(t, 0.2, flooding.Health().Score, 0.0001)
+	require.InDelta(t, 0.9, flooding.Health().KnownMessagesRatio, 0.0001)
+}
+
+func TestHealthTrackerConcurrentInfo(t *testing.T) {
+
+	now := time.Now()
+
+	proto := gossip.NewProtocol(peer.ID("peer"), nil, 10, time.Second, time.Second, &metrics.ServerMetrics{})
+	proto.HeartbeatReceivedTime = now
+
+	tracker := gossip.NewHealthTracker(0.5, time.Minute, 30*time.Second, 100*time.Second)
+
+	// the health is updated by the health check while the REST API reads it
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			tracker.Update([]*gossip.Protocol{proto}, now)
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		_ = proto.Info().Health
+	}
+	<-done
+
+	require.NotNil(t, proto.Health())
+}
@@ -0,0 +1,234 @@
+package gossip
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/time/rate"
+
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/iotaledger/hive.go/serializer/v2"
+)
+
+var (
+	// ErrMessageFilterAlreadyRegistered is returned if a message filter with the same name was already registered.
+	ErrMessageFilterAlreadyRegistered = errors.New("message filter already registered")
+)
+
+// MessageFilterResult is the verdict of a MessageFilter about an incoming message.
+type MessageFilterResult int
+
+const (
+	// MessageFilterAccept lets the message pass to the next filter.
+	MessageFilterAccept MessageFilterResult = iota
+	// MessageFilterDeprioritize processes and stores the message, but doesn't broadcast it to other peers.
+	MessageFilterDeprioritize
+	// MessageFilterReject drops the message before the PoW check and before it is stored.
+	MessageFilterReject
+)
+
+// String returns the name of the result.
+func (r MessageFilterResult) String() string {
+	switch r {
+	case MessageFilterAccept:
+		return "accept"
+	case MessageFilterDeprioritize:
+		return "deprioritize"
+	case MessageFilterReject:
+		return "reject"
+	default:
+		return fmt.Sprintf("unknown (%d)", r)
+	}
+}
+
+// MessageFilter decides about messages received via gossip before they are processed further.
+// Filters are only applied to messages which were not requested and which don't contain a milestone,
+// so they can't prevent the node from becoming synced.
+// Filters are called concurrently and must be safe for concurrent use.
+type MessageFilter interface {
+	// Filter returns the verdict about the given message received from the given peer.
+	Filter(msg *storage.Message, peerID peer.ID) MessageFilterResult
+}
+
+// MessageFilterFunc is a function which implements MessageFilter.
+type MessageFilterFunc func(msg *storage.Message, peerID peer.ID) MessageFilterResult
+
+// Filter calls the function itself.
+func (f MessageFilterFunc) Filter(msg *storage.Message, peerID peer.ID) MessageFilterResult {
+	return f(msg, peerID)
+}
+
+// namedMessageFilter is a MessageFilter registered under a name.
+type namedMessageFilter struct {
+	name   string
+	filter MessageFilter
+}
+
+// messageFilterChain applies the registered filters in the order of their registration.
+type messageFilterChain struct {
+	sync.RWMutex
+	filters []*namedMessageFilter
+}
+
+// register adds the filter to the end of the chain.
+func (c *messageFilterChain) register(name string, filter MessageFilter) error {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, f := range c.filters {
+		if f.name == name {
+			return errors.WithMessagef(ErrMessageFilterAlreadyRegistered, "name: %s", name)
+		}
+	}
+
+	c.filters = append(c.filters, &namedMessageFilter{name: name, filter: filter})
+	return nil
+}
+
+// deregister removes the filter with the given name from the chain.
+func (c *messageFilterChain) deregister(name string) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	for i, f := range c.filters {
+		if f.name == name {
+			c.filters = append(c.filters[:i], c.filters[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// apply runs the filters until one of them rejects the message.
+// The result is the most restrictive verdict of all filters.
+func (c *messageFilterChain) apply(msg *storage.Message, peerID peer.ID) MessageFilterResult {
+	c.RLock()
+	defer c.RUnlock()
+
+	result := MessageFilterAccept
+	for _, f := range c.filters {
+		if verdict := f.filter.Filter(msg, peerID); verdict > result {
+			result = verdict
+		}
+		if result == MessageFilterReject {
+			break
+		}
+	}
+
+	return result
+}
+
+// NewPeerRateFilter creates a filter which rejects messages of peers
+// that send more than messagesPerSecond messages (with the given burst).
+func NewPeerRateFilter(messagesPerSecond float64, burst int) MessageFilter {
+	type peerLimiter struct {
+		limiter  *rate.Limiter
+		lastSeen time.Time
+	}
+
+	var limitersLock sync.Mutex
+	limiters := make(map[peer.ID]*peerLimiter)
+	lastCleanup := time.Now()
+
+	return MessageFilterFunc(func(_ *storage.Message, peerID peer.ID) MessageFilterResult {
+		limitersLock.Lock()
+		defer limitersLock.Unlock()
+
+		now := time.Now()
+
+		// remove the limiters of peers which didn't send messages for a while
+		if now.Sub(lastCleanup) > time.Minute {
+			for id, l := range limiters {
+				if now.Sub(l.lastSeen) > time.Minute {
+					delete(limiters, id)
+				}
+			}
+			lastCleanup = now
+		}
+
+		l, exists := limiters[peerID]
+		if !exists {
+			l = &peerLimiter{limiter: rate.NewLimiter(rate.Limit(messagesPerSecond), burst)}
+			limiters[peerID] = l
+		}
+		l.lastSeen = now
+
+		if !l.limiter.AllowN(now, 1) {
+			return MessageFilterReject
+		}
+
+		return MessageFilterAccept
+	})
+}
+
+// NewDuplicatePayloadFilter creates a filter which deprioritizes messages
+// whose payload equals the payload of another message seen within the given window.
+// Messages without a payload are accepted.
+func NewDuplicatePayloadFilter(window time.Duration) MessageFilter {
+	var seenLock sync.Mutex
+	seen := make(map[[blake2b.Size256]byte]time.Time)
+	lastCleanup := time.Now()
+
+	return MessageFilterFunc(func(msg *storage.Message, _ peer.ID) MessageFilterResult {
+		payload := msg.Message().Payload
+		if payload == nil {
+			return MessageFilterAccept
+		}
+
+		payloadBytes, err := payload.Serialize(serializer.DeSeriModeNoValidation, nil)
+		if err != nil {
+			return MessageFilterAccept
+		}
+		payloadHash := blake2b.Sum256(payloadBytes)
+
+		seenLock.Lock()
+		defer seenLock.Unlock()
+
+		now := time.Now()
+
+		if now.Sub(lastCleanup) > window {
+			for hash, seenTime := range seen {
+				if now.Sub(seenTime) > window {
+					delete(seen, hash)
+				}
+			}
+			lastCleanup = now
+		}
+
+		seenTime, exists := seen[payloadHash]
+		seen[payloadHash] = now
+		if exists && now.Sub(seenTime) <= window {
+			return MessageFilterDeprioritize
+		}
+
+		return MessageFilterAccept
+	})
+}
+
+// NewTagFilter creates a filter which rejects messages with a tagged data payload
+// (also within a transaction) whose tag starts with one of the given prefixes.
+func NewTagFilter(rejectedTagPrefixes [][]byte) MessageFilter {
+	return MessageFilterFunc(func(msg *storage.Message, _ peer.ID) MessageFilterResult {
+		taggedData := msg.TaggedData()
+		if taggedData == nil {
+			taggedData = msg.TransactionEssenceTaggedData()
+		}
+		if taggedData == nil {
+			return MessageFilterAccept
+		}
+
+		for _, prefix := range rejectedTagPrefixes {
+			if bytes.HasPrefix(taggedData.Tag, prefix) {
+				return MessageFilterReject
+			}
+		}
+
+		return MessageFilterAccept
+	})
+}
@@ -0,0 +1,59 @@
+package gossip_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/protocol/gossip"
+	"github.com/gohornet/hornet/pkg/testsuite"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func newTaggedDataMessage(t *testing.T, tag string, data string, nonce uint64) *storage.Message {
+	msg, err := storage.NewMessage(&iotago.Message{
+		Parents: iotago.MessageIDs{iotago.MessageID{}},
+		Payload: &iotago.TaggedData{Tag: []byte(tag), Data: []byte(data)},
+		Nonce:   nonce,
+	}, serializer.DeSeriModeNoValidation, testsuite.DeSerializationParameters)
+	require.NoError(t, err)
+	return msg
+}
+
+func TestPeerRateFilter(t *testing.T) {
+	filter := gossip.NewPeerRateFilter(1, 2)
+	msg := newTaggedDataMessage(t, "tag", "data", 0)
+
+	peerA := peer.ID("peerA")
+	peerB := peer.ID("peerB")
+
+	require.Equal(t, gossip.MessageFilterAccept, filter.Filter(msg, peerA))
+	require.Equal(t, gossip.MessageFilterAccept, filter.Filter(msg, peerA))
+	require.Equal(t, gossip.MessageFilterReject, filter.Filter(msg, peerA))
+
+	// the rate is limited per peer
+	require.Equal(t, gossip.MessageFilterAccept, filter.Filter(msg, peerB))
+}
+
+func TestDuplicatePayloadFilter(t *testing.T) {
+	filter := gossip.NewDuplicatePayloadFilter(time.Minute)
+	peerA := peer.ID("peerA")
+
+	require.Equal(t, gossip.MessageFilterAccept, filter.Filter(newTaggedDataMessage(t, "tag", "data", 0), peerA))
+	require.Equal(t, gossip.MessageFilterAccept, filter.Filter(newTaggedDataMessage(t, "tag", "other data", 0), peerA))
+
+	// same payload in a different message
+	require.Equal(t, gossip.MessageFilterDeprioritize, filter.Filter(newTaggedDataMessage(t, "tag", "data", 1), peerA))
+}
+
+func TestTagFilter(t *testing.T) {
+	filter := gossip.NewTagFilter([][]byte{[]byte("spam")})
+	peerA := peer.ID("peerA")
+
+	require.Equal(t, gossip.MessageFilterReject, filter.Filter(newTaggedDataMessage(t, "spammer", "data", 0), peerA))
+	require.Equal(t, gossip.MessageFilterAccept, filter.Filter(newTaggedDataMessage(t, "no spam", "data", 0), peerA))
+}
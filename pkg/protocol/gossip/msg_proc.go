@@ -15,6 +15,7 @@ import (
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/model/syncmanager"
 	"github.com/gohornet/hornet/pkg/p2p"
+	"github.com/gohornet/hornet/pkg/pow"
 	"github.com/gohornet/hornet/pkg/profile"
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/objectstorage"
@@ -23,7 +24,6 @@ import (
 	"github.com/iotaledger/hive.go/syncutils"
 	"github.com/iotaledger/hive.go/workerpool"
 	iotago "github.com/iotaledger/iota.go/v3"
-	"github.com/iotaledger/iota.go/v3/pow"
 )
 
 const (
@@ -41,6 +41,10 @@ func MessageProcessedCaller(handler interface{}, params ...interface{}) {
 	handler.(func(msg *storage.Message, requests Requests, proto *Protocol))(params[0].(*storage.Message), params[1].(Requests), params[2].(*Protocol))
 }
 
+func MessageValidatedCaller(handler interface{}, params ...interface{}) {
+	handler.(func(messageID hornet.MessageID, receivedTime time.Time))(params[0].(hornet.MessageID), params[1].(time.Time))
+}
+
 // Broadcast defines a message which should be broadcasted.
 type Broadcast struct {
 	// The message data to broadcast.
@@ -59,14 +63,23 @@ type MessageProcessorEvents struct {
 	MessageProcessed *events.Event
 	// Fired when a message is meant to be broadcasted.
 	BroadcastMessage *events.Event
+	// Fired when a received or emitted message passed the validation including the PoW check.
+	MessageValidated *events.Event
+	// Fired when a queued emission was dropped because the processor was shut down.
+	MessageEmitDropped *events.Event
 }
 
 // The Options for the MessageProcessor.
 type Options struct {
-	MinPoWScore       float64
+	// the scoring versions used to validate the PoW of messages.
+	PoWScoring        *pow.Scoring
 	NetworkID         uint64
 	BelowMaxDepth     milestone.Index
 	WorkUnitCacheOpts *profile.CacheOpts
+	// the maximum time the timestamp of a gossiped milestone may be ahead of the local clock (0 = disabled).
+	MilestoneTimestampMaxFuture time.Duration
+	// the maximum time the timestamp of a gossiped milestone may be behind the latest known milestone (0 = disabled).
+	MilestoneTimestampMaxPast time.Duration
 }
 
 // MessageProcessor processes submitted messages in parallel and fires appropriate completion events.
@@ -90,8 +103,19 @@ type MessageProcessor struct {
 	Events MessageProcessorEvents
 	// cache that holds processed incomming messages.
 	workUnits *objectstorage.ObjectStorage
+	// the keys of the newly added WorkUnits in the order they were added.
+	// used to evict the oldest WorkUnits if the cache is full.
+	workUnitKeys [][]byte
+	// the position in workUnitKeys the next key is stored at.
+	workUnitKeysNext int
+	// mutex to secure workUnitKeys.
+	workUnitKeysLock syncutils.Mutex
 	// worker pool for incomming messages.
 	wp *workerpool.WorkerPool
+	// queue for messages emitted by the node itself.
+	emitQueue *emitQueue
+	// filters applied to incoming messages before they are processed.
+	filters messageFilterChain
 
 	// mutex to secure the shutdown flag.
 	shutdownMutex syncutils.RWMutex
@@ -117,9 +141,12 @@ func NewMessageProcessor(
 		serverMetrics:  serverMetrics,
 		deSeriParas:    deSeriParas,
 		opts:           *opts,
+		emitQueue:      newEmitQueue(),
 		Events: MessageProcessorEvents{
-			MessageProcessed: events.NewEvent(MessageProcessedCaller),
-			BroadcastMessage: events.NewEvent(BroadcastCaller),
+			MessageProcessed:   events.NewEvent(MessageProcessedCaller),
+			BroadcastMessage:   events.NewEvent(BroadcastCaller),
+			MessageValidated:   events.NewEvent(MessageValidatedCaller),
+			MessageEmitDropped: events.NewEvent(storage.MessageIDCaller),
 		},
 	}
 
@@ -135,6 +162,10 @@ func NewMessageProcessor(
 		return nil, err
 	}
 
+	if wuCacheOpts.MaxSize > 0 {
+		proc.workUnitKeys = make([][]byte, wuCacheOpts.MaxSize)
+	}
+
 	proc.workUnits = objectstorage.New(
 		nil,
 		// defines the factory function for WorkUnits.
@@ -175,7 +206,9 @@ func NewMessageProcessor(
 // Run runs the processor and blocks until the shutdown signal is triggered.
 func (proc *MessageProcessor) Run(ctx context.Context) {
 	proc.wp.Start()
-	<-ctx.Done()
+	proc.emitQueue.run(ctx, proc.emit, func(msg *storage.Message) {
+		proc.Events.MessageEmitDropped.Trigger(msg.MessageID())
+	})
 	proc.Shutdown()
 }
 
@@ -190,25 +223,90 @@ func (proc *MessageProcessor) Shutdown() {
 	proc.workUnits.Shutdown()
 }
 
+// RegisterFilter adds a filter with the given name to the end of the filter chain for incoming messages.
+func (proc *MessageProcessor) RegisterFilter(name string, filter MessageFilter) error {
+	return proc.filters.register(name, filter)
+}
+
+// DeregisterFilter removes the filter with the given name from the filter chain.
+// Returns false if no filter with the given name was registered.
+func (proc *MessageProcessor) DeregisterFilter(name string) bool {
+	return proc.filters.deregister(name)
+}
+
 // Process submits the given message to the processor for processing.
 func (proc *MessageProcessor) Process(p *Protocol, msgType message.Type, data []byte) {
 	proc.wp.Submit(p, msgType, data)
 }
 
-// Emit triggers MessageProcessed and BroadcastMessage events for the given message.
-// All messages passed to this function must be checked with "DeSeriModePerformValidation" before.
-// We also check if the parents are solid and not BMD before we broadcast the message, otherwise
-// this message would be seen as invalid gossip by other peers.
+// Emit queues the given message with normal priority to trigger MessageProcessed and BroadcastMessage events.
+// See EmitWithPriority for details.
 func (proc *MessageProcessor) Emit(msg *storage.Message) error {
+	return proc.EmitWithPriority(msg, EmitPriorityNormal)
+}
+
+// EmitWithPriority queues the given message to trigger MessageProcessed and BroadcastMessage events.
+// Queued messages with a higher priority are emitted first.
+// All messages passed to this function must be checked with "DeSeriModePerformValidation" before.
+// The message is checked with Validate before it is queued.
+func (proc *MessageProcessor) EmitWithPriority(msg *storage.Message, priority EmitPriority) error {
+	receivedTime := time.Now()
+
+	if err := proc.Validate(msg); err != nil {
+		return err
+	}
+
+	proc.Events.MessageValidated.Trigger(msg.MessageID(), receivedTime)
+
+	return proc.emitQueue.push(msg, priority)
+}
+
+// Validate checks the network ID and the PoW score of the given message and
+// if the parents are solid and not BMD, otherwise the message would be seen as invalid gossip by other peers.
+// The message must be checked with "DeSeriModePerformValidation" before.
+func (proc *MessageProcessor) Validate(msg *storage.Message) error {
+
+	if err := proc.ValidateNetworkID(msg); err != nil {
+		return err
+	}
 
+	if err := proc.ValidatePoW(msg); err != nil {
+		return err
+	}
+
+	return proc.ValidateParents(msg)
+}
+
+// ValidateNetworkID checks if the given message was issued for the network of the node.
+func (proc *MessageProcessor) ValidateNetworkID(msg *storage.Message) error {
 	if msg.NetworkID() != proc.opts.NetworkID {
 		return fmt.Errorf("msg has invalid network ID %d instead of %d", msg.NetworkID(), proc.opts.NetworkID)
 	}
+	return nil
+}
+
+// powScoringIndex returns the milestone index that defines the active PoW scoring version,
+// which is the index of the next milestone that may reference new messages.
+func (proc *MessageProcessor) powScoringIndex() milestone.Index {
+	return proc.syncManager.ConfirmedMilestoneIndex() + 1
+}
+
+// powSufficient checks if the PoW score of the serialized message reaches the minimum PoW score of the active scoring version.
+func (proc *MessageProcessor) powSufficient(msgData []byte) bool {
+	_, sufficient := proc.opts.PoWScoring.Sufficient(msgData, proc.powScoringIndex())
+	return sufficient
+}
 
-	score := pow.Score(msg.Data())
-	if score < proc.opts.MinPoWScore {
+// ValidatePoW checks if the PoW score of the given message reaches the minimum PoW score of the active scoring version.
+func (proc *MessageProcessor) ValidatePoW(msg *storage.Message) error {
+	if score, sufficient := proc.opts.PoWScoring.Sufficient(msg.Data(), proc.powScoringIndex()); !sufficient {
 		return fmt.Errorf("msg has insufficient PoW score %0.2f", score)
 	}
+	return nil
+}
+
+// ValidateParents checks if the parents of the given message are solid and not BMD.
+func (proc *MessageProcessor) ValidateParents(msg *storage.Message) error {
 
 	cmi := proc.syncManager.ConfirmedMilestoneIndex()
 
@@ -257,10 +355,18 @@ func (proc *MessageProcessor) Emit(msg *storage.Message) error {
 		}
 	}
 
+	return nil
+}
+
+// EmitQueueStats returns the statistics of the queued emissions, sorted from the highest to the lowest priority.
+func (proc *MessageProcessor) EmitQueueStats() []*EmitQueueStats {
+	return proc.emitQueue.stats()
+}
+
+// emit triggers MessageProcessed and BroadcastMessage events for the given message.
+func (proc *MessageProcessor) emit(msg *storage.Message) {
 	proc.Events.MessageProcessed.Trigger(msg, (Requests)(nil), (*Protocol)(nil))
 	proc.Events.BroadcastMessage.Trigger(&Broadcast{MsgData: msg.Data()})
-
-	return nil
 }
 
 // WorkUnitsSize returns the size of WorkUnits currently cached.
@@ -268,6 +374,24 @@ func (proc *MessageProcessor) WorkUnitsSize() int {
 	return proc.workUnits.GetSize()
 }
 
+// remembers the key of a newly added WorkUnit and evicts the oldest WorkUnit if the cache is full.
+func (proc *MessageProcessor) trackWorkUnit(key []byte) {
+	if len(proc.workUnitKeys) == 0 {
+		// the cache size is unlimited
+		return
+	}
+
+	proc.workUnitKeysLock.Lock()
+	oldestKey := proc.workUnitKeys[proc.workUnitKeysNext]
+	proc.workUnitKeys[proc.workUnitKeysNext] = key
+	proc.workUnitKeysNext = (proc.workUnitKeysNext + 1) % len(proc.workUnitKeys)
+	proc.workUnitKeysLock.Unlock()
+
+	if oldestKey != nil {
+		proc.workUnits.DeleteIfPresent(oldestKey)
+	}
+}
+
 // gets a CachedWorkUnit or creates a new one if it not existent.
 func (proc *MessageProcessor) workUnitFor(receivedTxBytes []byte) (cachedWorkUnit *CachedWorkUnit, newlyAdded bool) {
 	return &CachedWorkUnit{
@@ -348,9 +472,20 @@ func (proc *MessageProcessor) processMessageRequest(p *Protocol, data []byte) {
 func (proc *MessageProcessor) processMessage(p *Protocol, data []byte) {
 	cachedWorkUnit, newlyAdded := proc.workUnitFor(data) // workUnit +1
 
+	if newlyAdded {
+		proc.serverMetrics.IncomingMessagesFilterMisses.Inc()
+	} else {
+		proc.serverMetrics.IncomingMessagesFilterHits.Inc()
+	}
+
 	// force release if not newly added, so the cache time is only active the first time the message is received.
 	defer cachedWorkUnit.Release(!newlyAdded) // workUnit -1
 
+	if newlyAdded {
+		// if the cache is full, the oldest WorkUnit is evicted to limit the memory usage.
+		proc.trackWorkUnit(data)
+	}
+
 	workUnit := cachedWorkUnit.WorkUnit()
 	workUnit.addReceivedFrom(p)
 	proc.processWorkUnit(workUnit, p)
@@ -380,6 +515,11 @@ func (proc *MessageProcessor) processWorkUnit(wu *WorkUnit, p *Protocol) {
 			}
 		}
 
+		// the peer which delivered the message first answered the requests
+		for _, r := range requests {
+			p.Metrics.addRequestLatency(time.Since(r.EnqueueTime))
+		}
+
 		wu.requested = requests.HasRequest()
 		return requests
 	}
@@ -395,6 +535,7 @@ func (proc *MessageProcessor) processWorkUnit(wu *WorkUnit, p *Protocol) {
 		wu.processingLock.Unlock()
 
 		proc.serverMetrics.InvalidMessages.Inc()
+		p.Metrics.InvalidMessages.Inc()
 
 		// drop the connection to the peer
 		_ = proc.peeringManager.DisconnectPeer(p.PeerID, errors.New("peer sent an invalid message"))
@@ -423,17 +564,17 @@ func (proc *MessageProcessor) processWorkUnit(wu *WorkUnit, p *Protocol) {
 	wu.processingLock.Unlock()
 
 	// build HORNET representation of the message
-	msg, err := storage.MessageFromBytes(wu.receivedMsgBytes, serializer.DeSeriModePerformValidation, proc.deSeriParas)
+	msg, err := parseMessage(wu.receivedMsgBytes, proc.deSeriParas)
 	if err != nil {
 		wu.UpdateState(Invalid)
-		wu.punish(errors.WithMessagef(err, "peer sent an invalid message"))
+		wu.punish(p2p.ViolationInvalidMessage, errors.WithMessagef(err, "peer sent an invalid message"))
 		return
 	}
 
 	// check the network ID of the message
 	if msg.NetworkID() != proc.opts.NetworkID {
 		wu.UpdateState(Invalid)
-		wu.punish(errors.New("peer sent a message with an invalid network ID"))
+		wu.punish(p2p.ViolationInvalidNetworkID, errors.New("peer sent a message with an invalid network ID"))
 		return
 	}
 
@@ -442,13 +583,43 @@ func (proc *MessageProcessor) processWorkUnit(wu *WorkUnit, p *Protocol) {
 	// mark the message as received
 	requests := processRequests(wu, msg, isMilestonePayload)
 
+	// apply the message filters to gossiped messages
+	if !wu.requested && !isMilestonePayload {
+		switch proc.filters.apply(msg, p.PeerID) {
+		case MessageFilterReject:
+			proc.serverMetrics.FilterRejectedMessages.Inc()
+			p.Metrics.FilterRejectedMessages.Inc()
+
+			// the state is reset, so the message is processed again if it is requested later.
+			wu.UpdateState(0)
+			return
+
+		case MessageFilterDeprioritize:
+			proc.serverMetrics.FilterDeprioritizedMessages.Inc()
+			wu.deprioritized = true
+		}
+	}
+
 	// validate PoW score
-	if !wu.requested && pow.Score(wu.receivedMsgBytes) < proc.opts.MinPoWScore {
+	if !wu.requested && !proc.powSufficient(wu.receivedMsgBytes) {
 		wu.UpdateState(Invalid)
-		wu.punish(errors.New("peer sent a message with insufficient PoW score"))
+		wu.punish(p2p.ViolationInsufficientPoW, errors.New("peer sent a message with insufficient PoW score"))
 		return
 	}
 
+	// validate the timestamp of gossiped milestones.
+	// requested milestones are not checked, because they are needed to become synced.
+	if !wu.requested && isMilestonePayload {
+		if err := proc.checkMilestoneTimestamp(msg.Milestone()); err != nil {
+			// the peer is not punished, because the local clock could be off.
+			// the state is reset, so the milestone is accepted if it gets requested later.
+			wu.UpdateState(0)
+			return
+		}
+	}
+
+	proc.Events.MessageValidated.Trigger(msg.MessageID(), wu.receivedTime)
+
 	// safe to set the msg here, because it is protected by the state "Hashing"
 	wu.msg = msg
 	wu.UpdateState(Hashed)
@@ -467,6 +638,34 @@ func (proc *MessageProcessor) processWorkUnit(wu *WorkUnit, p *Protocol) {
 	proc.Events.MessageProcessed.Trigger(msg, requests, p)
 }
 
+// checks whether the timestamp of the given milestone is within the configured tolerances.
+func (proc *MessageProcessor) checkMilestoneTimestamp(ms *iotago.Milestone) error {
+	timestamp := time.Unix(int64(ms.Timestamp), 0)
+
+	if proc.opts.MilestoneTimestampMaxFuture > 0 {
+		if maxTimestamp := time.Now().Add(proc.opts.MilestoneTimestampMaxFuture); timestamp.After(maxTimestamp) {
+			proc.serverMetrics.RejectedFutureMilestones.Inc()
+			return errors.WithMessagef(ErrInvalidTimestamp, "milestone %d is %v ahead of the local clock", ms.Index, time.Until(timestamp).Truncate(time.Second))
+		}
+	}
+
+	if proc.opts.MilestoneTimestampMaxPast > 0 {
+		cachedLatestMilestone := proc.storage.CachedMilestoneOrNil(proc.syncManager.LatestMilestoneIndex()) // milestone +1
+		if cachedLatestMilestone == nil {
+			return nil
+		}
+		defer cachedLatestMilestone.Release(true) // milestone -1
+
+		latestTimestamp := cachedLatestMilestone.Milestone().Timestamp
+		if minTimestamp := latestTimestamp.Add(-proc.opts.MilestoneTimestampMaxPast); timestamp.Before(minTimestamp) {
+			proc.serverMetrics.RejectedPastMilestones.Inc()
+			return errors.WithMessagef(ErrInvalidTimestamp, "milestone %d is %v behind the latest milestone", ms.Index, latestTimestamp.Sub(timestamp))
+		}
+	}
+
+	return nil
+}
+
 func (proc *MessageProcessor) Broadcast(cachedMsgMeta *storage.CachedMetadata) {
 	proc.shutdownMutex.RLock()
 	defer proc.shutdownMutex.RUnlock()
@@ -508,6 +707,11 @@ func (proc *MessageProcessor) Broadcast(cachedMsgMeta *storage.CachedMetadata) {
 		return
 	}
 
+	if wu.deprioritized {
+		// the message filters decided that the message should not be spread further
+		return
+	}
+
 	// if the workunit was already evicted, it may happen that
 	// we send the message back to peers which already sent us the same message.
 	// we should never access the "msg", because it may not be set in this context.
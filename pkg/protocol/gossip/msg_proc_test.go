@@ -12,10 +12,13 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/gohornet/hornet/pkg/metrics"
+	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/p2p"
+	"github.com/gohornet/hornet/pkg/pow"
 	"github.com/gohornet/hornet/pkg/protocol/gossip"
 	"github.com/gohornet/hornet/pkg/testsuite"
+	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/serializer/v2"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
@@ -50,8 +53,11 @@ func TestMsgProcessorEmit(t *testing.T) {
 
 	networkID := iotago.NetworkIDFromString("testnet4")
 
+	scoring, err := pow.NewScoring(pow.ScoringRanges{{Scorer: pow.ScorerCurlP81, MinPoWScore: MinPoWScore}})
+	require.NoError(t, err)
+
 	processor, err := gossip.NewMessageProcessor(te.Storage(), te.SyncManager(), gossip.NewRequestQueue(), manager, serverMetrics, testsuite.DeSerializationParameters, &gossip.Options{
-		MinPoWScore:       MinPoWScore,
+		PoWScoring:        scoring,
 		NetworkID:         networkID,
 		BelowMaxDepth:     BelowMaxDepth,
 		WorkUnitCacheOpts: testsuite.TestProfileCaches.IncomingMessagesFilter,
@@ -140,4 +146,29 @@ func TestMsgProcessorEmit(t *testing.T) {
 	// should fail because of wrong score
 	err = processor.Emit(message)
 	assert.Error(t, err)
+
+	// the valid messages are queued until the processor runs
+	stats := processor.EmitQueueStats()
+	require.Len(t, stats, 3)
+	require.Equal(t, gossip.EmitPriorityHigh, stats[0].Priority)
+	require.Equal(t, 0, stats[0].Count)
+	require.Equal(t, gossip.EmitPriorityNormal, stats[1].Priority)
+	require.Equal(t, 2, stats[1].Count)
+	require.Equal(t, gossip.EmitPriorityLow, stats[2].Priority)
+	require.Equal(t, 0, stats[2].Count)
+
+	// the queued messages are reported as dropped if the processor is shut down before they were emitted
+	var droppedMessageIDs hornet.MessageIDs
+	processor.Events.MessageEmitDropped.Attach(events.NewClosure(func(messageID hornet.MessageID) {
+		droppedMessageIDs = append(droppedMessageIDs, messageID)
+	}))
+
+	runCtx, runCancel := context.WithCancel(context.Background())
+	runCancel()
+	processor.Run(runCtx)
+
+	require.Len(t, droppedMessageIDs, 2)
+	for _, stats := range processor.EmitQueueStats() {
+		require.Equal(t, 0, stats.Count)
+	}
 }
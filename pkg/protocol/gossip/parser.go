@@ -0,0 +1,59 @@
+package gossip
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/iotaledger/hive.go/protocol"
+	"github.com/iotaledger/hive.go/protocol/tlv"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrMalformedPacket is returned if a received packet can't be parsed, e.g. because of an unknown message type.
+	ErrMalformedPacket = errors.New("malformed packet")
+	// ErrOversizedPacket is returned if a received packet advertises an invalid length for its message type.
+	ErrOversizedPacket = errors.New("oversized packet")
+)
+
+// classifyParseError wraps an error of the packet parser into ErrMalformedPacket or ErrOversizedPacket.
+func classifyParseError(err error) error {
+	// the TLV parser doesn't wrap its errors, so only the message can be compared
+	if strings.HasPrefix(err.Error(), tlv.ErrInvalidMessageLength.Error()) {
+		return errors.WithMessage(ErrOversizedPacket, err.Error())
+	}
+	return errors.WithMessage(ErrMalformedPacket, err.Error())
+}
+
+// parsePackets feeds the given data into the parser, which triggers the received events of all completed packets.
+// A panic while parsing is recovered and returned as ErrMalformedPacket.
+func parsePackets(parser *protocol.Protocol, data []byte) (n int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.WithMessagef(ErrMalformedPacket, "parsing the packet panicked: %v", r)
+		}
+	}()
+
+	n, err = parser.Read(data)
+	if err != nil {
+		return n, classifyParseError(err)
+	}
+
+	return n, nil
+}
+
+// parseMessage deserializes and syntactically validates a message received from a peer.
+// A panic while deserializing is recovered and returned as an error.
+func parseMessage(data []byte, deSeriParas *iotago.DeSerializationParameters) (msg *storage.Message, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = nil
+			err = errors.Errorf("deserializing the message panicked: %v", r)
+		}
+	}()
+
+	return storage.MessageFromBytes(data, serializer.DeSeriModePerformValidation, deSeriParas)
+}
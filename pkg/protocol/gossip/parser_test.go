@@ -0,0 +1,76 @@
+package gossip_test
+
+import (
+	"testing"
+
+	"github.com/iotaledger/hive.go/events"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/protocol/gossip"
+)
+
+func newTestProtocol(t *testing.T) *gossip.Protocol {
+	peerID, err := peer.Decode("12D3KooWCKwcTWevoRKa2kEBputeGASvEBuDfRDSbe8t1DWugUmL")
+	require.NoError(t, err)
+
+	return gossip.NewProtocol(peerID, nil, 10, 0, 0, nil)
+}
+
+func TestProtocolParse(t *testing.T) {
+	proto := newTestProtocol(t)
+
+	var heartbeat *gossip.Heartbeat
+	proto.Parser.Events.Received[gossip.MessageTypeHeartbeat].Attach(events.NewClosure(func(data []byte) {
+		var err error
+		heartbeat, err = gossip.ParseHeartbeat(data)
+		require.NoError(t, err)
+	}))
+
+	packet, err := gossip.NewHeartbeatMsg(10, 5, 12, 3, 2)
+	require.NoError(t, err)
+
+	// the packet is split over two reads
+	_, err = proto.Parse(packet[:4])
+	require.NoError(t, err)
+	require.Nil(t, heartbeat)
+	_, err = proto.Parse(packet[4:])
+	require.NoError(t, err)
+	require.Equal(t, &gossip.Heartbeat{
+		SolidMilestoneIndex:  10,
+		PrunedMilestoneIndex: 5,
+		LatestMilestoneIndex: 12,
+		ConnectedNeighbors:   3,
+		SyncedNeighbors:      2,
+	}, heartbeat)
+}
+
+func TestProtocolParseMalformed(t *testing.T) {
+
+	// unknown message type
+	_, err := newTestProtocol(t).Parse([]byte{0xff, 0x01, 0x00, 0x00})
+	require.ErrorIs(t, err, gossip.ErrMalformedPacket)
+
+	// a heartbeat exceeding its fixed length
+	_, err = newTestProtocol(t).Parse([]byte{byte(gossip.MessageTypeHeartbeat), 0xff, 0x00})
+	require.ErrorIs(t, err, gossip.ErrOversizedPacket)
+
+	// a message request shorter than a message ID
+	_, err = newTestProtocol(t).Parse([]byte{byte(gossip.MessageTypeMessageRequest), 0x01, 0x00, 0x00})
+	require.ErrorIs(t, err, gossip.ErrOversizedPacket)
+
+	// a panic in a handler doesn't crash the node
+	proto := newTestProtocol(t)
+	proto.Parser.Events.Received[gossip.MessageTypeMilestoneRequest].Attach(events.NewClosure(func(data []byte) {
+		panic("handler failed")
+	}))
+	packet, err := gossip.NewMilestoneRequestMsg(1)
+	require.NoError(t, err)
+	_, err = proto.Parse(packet)
+	require.ErrorIs(t, err, gossip.ErrMalformedPacket)
+}
+
+func TestParseHeartbeatInvalidLength(t *testing.T) {
+	_, err := gossip.ParseHeartbeat([]byte{0x01, 0x02})
+	require.ErrorIs(t, err, gossip.ErrInvalidSourceLength)
+}
@@ -1,19 +1,23 @@
 package gossip
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"sync"
 	"time"
 
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"go.uber.org/atomic"
+	"golang.org/x/time/rate"
 
 	"github.com/gohornet/hornet/pkg/metrics"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/protocol"
+	"github.com/iotaledger/hive.go/protocol/tlv"
 )
 
 const (
@@ -47,7 +51,7 @@ func NewProtocol(peerID peer.ID, stream network.Stream, sendQueueSize int, readT
 		sentEvents[i] = events.NewEvent(events.VoidCaller)
 	}
 
-	return &Protocol{
+	proto := &Protocol{
 		Parser: protocol.New(gossipMessageRegistry),
 		PeerID: peerID,
 		Events: ProtocolEvents{
@@ -63,7 +67,22 @@ func NewProtocol(peerID peer.ID, stream network.Stream, sendQueueSize int, readT
 		readTimeout:   readTimeout,
 		writeTimeout:  writeTimeout,
 		ServerMetrics: serverMetrics,
+		ConnectedTime: time.Now(),
 	}
+	proto.bindContext(context.Background())
+
+	// record the received packets if a capture is running
+	for _, def := range defs {
+		if def == nil || def.ID == tlv.MessageTypeHeader {
+			continue
+		}
+		msgType := def.ID
+		proto.Parser.Events.Received[msgType].Attach(events.NewClosure(func(data []byte) {
+			proto.capturePacket(PacketDirectionIn, msgType, data)
+		}))
+	}
+
+	return proto
 }
 
 // Protocol represents an instance of the gossip protocol.
@@ -76,6 +95,8 @@ type Protocol struct {
 	Stream network.Stream
 	// The events surrounding a Protocol.
 	Events ProtocolEvents
+	// The time the protocol stream to the peer was established.
+	ConnectedTime time.Time
 	// The peer's latest heartbeat message.
 	LatestHeartbeat *Heartbeat
 	// Time the last heartbeat was received.
@@ -85,12 +106,49 @@ type Protocol struct {
 	// The send queue into which to enqueue messages to send.
 	SendQueue chan []byte
 	// The metrics around this protocol instance.
-	Metrics      Metrics
+	Metrics Metrics
+	// the health of the peer evaluated during the latest health check.
+	health     *Health
+	healthLock sync.RWMutex
+	// The compression negotiated with the peer.
+	Compression Compression
+	// decompresses the data read from the stream, nil if the stream is not compressed.
+	reader io.Reader
+	// compresses the data written to the stream, nil if the stream is not compressed.
+	writer       compressionWriter
 	sendMu       sync.Mutex
 	readTimeout  time.Duration
 	writeTimeout time.Duration
+	// done if the stream is closed or the service is shut down, cancels pending waits for bandwidth.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// limits the bandwidth of reads from the stream (per stream and global).
+	downloadLimiters []*rate.Limiter
+	// limits the bandwidth of writes to the stream (per stream and global).
+	uploadLimiters []*rate.Limiter
 	// The shared server metrics instance.
 	ServerMetrics *metrics.ServerMetrics
+	// records the next packets exchanged with the peer, nil if no capture is running.
+	capture     *PacketCapture
+	captureLock sync.RWMutex
+}
+
+// binds the lifetime of the protocol to the given context.
+func (p *Protocol) bindContext(ctx context.Context) {
+	p.ctx, p.cancel = context.WithCancel(ctx)
+}
+
+// sets the compression used to read from and write to the stream.
+// the compression wraps the bandwidth limited stream, so the limits apply to the compressed bytes on the wire.
+func (p *Protocol) setCompression(compression Compression) {
+	p.Compression = compression
+	p.reader = nil
+	p.writer = nil
+	if compression == CompressionNone {
+		return
+	}
+	p.reader = newCompressionReader(readerFunc(p.readStream), compression)
+	p.writer = newCompressionWriter(writerFunc(p.writeStream), compression)
 }
 
 // Enqueue enqueues the given gossip protocol message to be sent to the peer.
@@ -106,11 +164,52 @@ func (p *Protocol) Enqueue(data []byte) {
 
 // Read reads from the stream into the given buffer.
 func (p *Protocol) Read(buf []byte) (int, error) {
+	var r int
+	var err error
+	if p.reader != nil {
+		r, err = p.reader.Read(buf)
+	} else {
+		r, err = p.readStream(buf)
+	}
+	p.Metrics.ReceivedBytes.Add(uint64(r))
+	return r, err
+}
+
+// reads the raw bytes from the stream and waits for the download bandwidth they used.
+func (p *Protocol) readStream(buf []byte) (int, error) {
 	if err := p.Stream.SetReadDeadline(time.Now().Add(p.readTimeout)); err != nil {
 		return 0, fmt.Errorf("unable to set read deadline: %w", err)
 	}
+
 	r, err := p.Stream.Read(buf)
-	return r, err
+	if err != nil {
+		return r, err
+	}
+
+	// delaying further reads applies backpressure on the sender
+	if err := waitForBandwidth(p.ctx, r, p.downloadLimiters...); err != nil {
+		return r, fmt.Errorf("unable to wait for download bandwidth: %w", err)
+	}
+	return r, nil
+}
+
+// waits for the upload bandwidth and writes the raw bytes to the stream.
+func (p *Protocol) writeStream(data []byte) (int, error) {
+	if err := waitForBandwidth(p.ctx, len(data), p.uploadLimiters...); err != nil {
+		return 0, fmt.Errorf("unable to wait for upload bandwidth: %w", err)
+	}
+
+	if err := p.Stream.SetWriteDeadline(time.Now().Add(p.writeTimeout)); err != nil {
+		return 0, fmt.Errorf("unable to set write deadline: %w", err)
+	}
+
+	return p.Stream.Write(data)
+}
+
+// Parse feeds the data read from the stream into the Parser.
+// Errors wrap either ErrMalformedPacket or ErrOversizedPacket.
+func (p *Protocol) Parse(data []byte) (int, error) {
+	return parsePackets(p.Parser, data)
 }
 
 // Send sends the given gossip message on the underlying Protocol.Stream.
@@ -118,15 +217,21 @@ func (p *Protocol) Send(message []byte) error {
 	p.sendMu.Lock()
 	defer p.sendMu.Unlock()
 
-	if err := p.Stream.SetWriteDeadline(time.Now().Add(p.writeTimeout)); err != nil {
-		return fmt.Errorf("unable to set write deadline: %w", err)
-	}
-
 	// write message
-	if _, err := p.Stream.Write(message); err != nil {
+	if p.writer != nil {
+		if _, err := p.writer.Write(message); err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		if err := p.writer.Flush(); err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+	} else if _, err := p.writeStream(message); err != nil {
 		return fmt.Errorf("failed to send message: %w", err)
 	}
 
+	p.Metrics.SentBytes.Add(uint64(len(message)))
+	p.captureSentPacket(message)
+
 	// fire event handler for sent message
 	p.Events.Sent[message[0]].Trigger()
 	return nil
@@ -212,11 +317,27 @@ func (p *Protocol) IsSynced(cmi milestone.Index) bool {
 // Info returns
 func (p *Protocol) Info() *Info {
 	return &Info{
-		Heartbeat: p.LatestHeartbeat,
-		Metrics:   p.Metrics.Snapshot(),
+		Heartbeat:   p.LatestHeartbeat,
+		Metrics:     p.Metrics.Snapshot(),
+		Health:      p.Health(),
+		Compression: p.Compression,
 	}
 }
 
+// Health returns the health of the peer evaluated during the latest health check, nil if it wasn't checked yet.
+func (p *Protocol) Health() *Health {
+	p.healthLock.RLock()
+	defer p.healthLock.RUnlock()
+	return p.health
+}
+
+// sets the health of the peer evaluated during a health check.
+func (p *Protocol) setHealth(health *Health) {
+	p.healthLock.Lock()
+	defer p.healthLock.Unlock()
+	p.health = health
+}
+
 // Metrics defines a set of metrics regarding a gossip protocol instance.
 type Metrics struct {
 	// The number of received messages which are new.
@@ -243,10 +364,43 @@ type Metrics struct {
 	SentHeartbeats atomic.Uint32
 	// The number of dropped packets.
 	DroppedPackets atomic.Uint32
+	// The number of received messages which were invalid.
+	InvalidMessages atomic.Uint32
+	// The number of received messages which were rejected by the message filters.
+	FilterRejectedMessages atomic.Uint32
+	// The number of bytes received (before decompression).
+	ReceivedBytes atomic.Uint64
+	// The number of bytes sent (before compression).
+	SentBytes atomic.Uint64
+	// The number of requests which were answered by the peer.
+	AnsweredRequests atomic.Uint32
+	// The sum of the latencies of the requests answered by the peer.
+	RequestLatencySum atomic.Duration
+	// The highest latency of a request answered by the peer.
+	RequestLatencyMax atomic.Duration
+}
+
+// addRequestLatency records the latency of a request which was answered by the peer.
+func (m *Metrics) addRequestLatency(latency time.Duration) {
+	m.AnsweredRequests.Inc()
+	m.RequestLatencySum.Add(latency)
+	for {
+		max := m.RequestLatencyMax.Load()
+		if latency <= max || m.RequestLatencyMax.CAS(max, latency) {
+			return
+		}
+	}
 }
 
 // Snapshot returns MetricsSnapshot of the Metrics.
 func (m *Metrics) Snapshot() MetricsSnapshot {
+	answeredRequests := m.AnsweredRequests.Load()
+
+	var avgRequestLatency time.Duration
+	if answeredRequests > 0 {
+		avgRequestLatency = m.RequestLatencySum.Load() / time.Duration(answeredRequests)
+	}
+
 	return MetricsSnapshot{
 		ReceivedMessages:     m.ReceivedMessages.Load(),
 		NewMessages:          m.NewMessages.Load(),
@@ -259,6 +413,13 @@ func (m *Metrics) Snapshot() MetricsSnapshot {
 		SentMilestoneReq:     m.SentMilestoneRequests.Load(),
 		SentHeartbeats:       m.SentHeartbeats.Load(),
 		DroppedPackets:       m.DroppedPackets.Load(),
+		InvalidMessages:      m.InvalidMessages.Load(),
+		FilteredMessages:     m.FilterRejectedMessages.Load(),
+		ReceivedBytes:        m.ReceivedBytes.Load(),
+		SentBytes:            m.SentBytes.Load(),
+		AnsweredRequests:     answeredRequests,
+		AvgRequestLatency:    avgRequestLatency.Milliseconds(),
+		MaxRequestLatency:    m.RequestLatencyMax.Load().Milliseconds(),
 	}
 }
 
@@ -275,10 +436,21 @@ type MetricsSnapshot struct {
 	SentMilestoneReq     uint32 `json:"sentMilestoneRequests"`
 	SentHeartbeats       uint32 `json:"sentHeartbeats"`
 	DroppedPackets       uint32 `json:"droppedPackets"`
+	InvalidMessages      uint32 `json:"invalidMessages"`
+	FilteredMessages     uint32 `json:"filteredMessages"`
+	ReceivedBytes        uint64 `json:"receivedBytes"`
+	SentBytes            uint64 `json:"sentBytes"`
+	AnsweredRequests     uint32 `json:"answeredRequests"`
+	// The average latency of the answered requests in milliseconds.
+	AvgRequestLatency int64 `json:"avgRequestLatency"`
+	// The highest latency of the answered requests in milliseconds.
+	MaxRequestLatency int64 `json:"maxRequestLatency"`
 }
 
 // Info represents information about an ongoing gossip protocol.
 type Info struct {
-	Heartbeat *Heartbeat      `json:"heartbeat"`
-	Metrics   MetricsSnapshot `json:"metrics"`
+	Heartbeat   *Heartbeat      `json:"heartbeat"`
+	Metrics     MetricsSnapshot `json:"metrics"`
+	Health      *Health         `json:"health,omitempty"`
+	Compression Compression     `json:"compression,omitempty"`
 }
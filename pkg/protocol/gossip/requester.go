@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"github.com/libp2p/go-libp2p-core/peer"
+
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/storage"
@@ -15,6 +17,8 @@ type RequesterOptions struct {
 	PendingRequestReEnqueueInterval time.Duration
 	// Defines the max age for requests.
 	DiscardRequestsOlderThan time.Duration
+	// Defines the time requests are only sent to their preferred peer.
+	StickyRequestTimeout time.Duration
 }
 
 // applies the given RequesterOption.
@@ -31,6 +35,7 @@ type RequestBackPressureFunc func() bool
 var defaultRequesterOpts = []RequesterOption{
 	WithRequesterDiscardRequestsOlderThan(10 * time.Second),
 	WithRequesterPendingRequestReEnqueueInterval(5 * time.Second),
+	WithRequesterStickyRequestTimeout(3 * time.Second),
 }
 
 // RequesterOption is a function which sets an option on a RequesterOptions instance.
@@ -50,6 +55,14 @@ func WithRequesterPendingRequestReEnqueueInterval(dur time.Duration) RequesterOp
 	}
 }
 
+// WithRequesterStickyRequestTimeout sets the time requests are only sent to their preferred peer,
+// before they are sent to all peers that could have the data.
+func WithRequesterStickyRequestTimeout(dur time.Duration) RequesterOption {
+	return func(options *RequesterOptions) {
+		options.StickyRequestTimeout = dur
+	}
+}
+
 // Requester handles requesting packets.
 type Requester struct {
 	storage *storage.Storage
@@ -105,6 +118,16 @@ func (r *Requester) RunRequestQueueDrainer(ctx context.Context) {
 					}
 				}
 
+				if r.isSticky(request) {
+					// the peer which sent a child of the requested message most likely has the data,
+					// so we don't bother the other peers until the sticky request timeout is reached.
+					if proto := r.service.Protocol(request.PreferredPeer); proto != nil && proto.CouldHaveDataForMilestone(request.MilestoneIndex) {
+						sendRequest(request, proto)
+						r.scheduleStickyFallback(request)
+						continue
+					}
+				}
+
 				requested := false
 				r.service.ForEach(func(proto *Protocol) bool {
 					// we only send a request message if the peer actually has the data
@@ -165,19 +188,45 @@ reEnqueueLoop:
 	}
 }
 
-// adds the request to the request queue and signals the request drainer to drain it.
-func (r *Requester) enqueueAndSignal(request *Request) bool {
-	if !r.rQueue.Enqueue(request) {
-		return false
+// checks whether the request should only be sent to its preferred peer.
+func (r *Requester) isSticky(request *Request) bool {
+	return request.PreferredPeer != "" && time.Since(request.EnqueueTime) < r.opts.StickyRequestTimeout
+}
+
+// schedules the fallback of a request which was sent to its preferred peer.
+// If the preferred peer didn't answer until the sticky request timeout is reached,
+// the request is enqueued again right away, so it is sent to the other peers
+// without waiting for the re-enqueue interval of the pending requests.
+func (r *Requester) scheduleStickyFallback(request *Request) {
+	if request.stickyFallbackScheduled {
+		return
 	}
+	request.stickyFallbackScheduled = true
 
-	// signal requester
+	time.AfterFunc(r.opts.StickyRequestTimeout-time.Since(request.EnqueueTime), func() {
+		if r.rQueue.EnqueuePendingRequest(request) {
+			r.signalDrain()
+		}
+	})
+}
+
+// signals the request drainer to drain the request queue.
+func (r *Requester) signalDrain() {
 	select {
 	case r.drainSignal <- struct{}{}:
 	default:
 		// if the signal queue is full, there's no need to block until it becomes empty
 		// as the requester will drain everything present in the queue
 	}
+}
+
+// adds the request to the request queue and signals the request drainer to drain it.
+func (r *Requester) enqueueAndSignal(request *Request) bool {
+	if !r.rQueue.Enqueue(request) {
+		return false
+	}
+
+	r.signalDrain()
 	return true
 }
 
@@ -204,6 +253,12 @@ func (r *Requester) AddBackPressureFunc(pressureFunc RequestBackPressureFunc) {
 // Request enqueues a request to the request queue for the given message if it isn't a solid entry point
 // and is not contained in the database already.
 func (r *Requester) Request(data interface{}, msIndex milestone.Index, preventDiscard ...bool) bool {
+	return r.RequestFromPeer(data, msIndex, "", preventDiscard...)
+}
+
+// RequestFromPeer works like Request, but the request is sent to the given peer
+// until the sticky request timeout is reached, before it is sent to other peers.
+func (r *Requester) RequestFromPeer(data interface{}, msIndex milestone.Index, preferredPeer peer.ID, preventDiscard ...bool) bool {
 
 	var request *Request
 
@@ -232,6 +287,7 @@ func (r *Requester) Request(data interface{}, msIndex milestone.Index, preventDi
 	if len(preventDiscard) > 0 {
 		request.PreventDiscard = preventDiscard[0]
 	}
+	request.PreferredPeer = preferredPeer
 
 	return r.enqueueAndSignal(request)
 }
@@ -249,7 +305,8 @@ func (r *Requester) RequestMultiple(messageIDs hornet.MessageIDs, msIndex milest
 
 // RequestParents enqueues requests for the parents of the given message to the request queue, if the
 // given message is not a solid entry point and neither its parents are and also not in the database.
-func (r *Requester) RequestParents(cachedMsg *storage.CachedMessage, msIndex milestone.Index, preventDiscard ...bool) {
+// If a preferred peer is given (e.g. the peer which sent the message), the requests are sent to it first.
+func (r *Requester) RequestParents(cachedMsg *storage.CachedMessage, msIndex milestone.Index, preferredPeer peer.ID, preventDiscard ...bool) {
 	cachedMsg.ConsumeMetadata(func(metadata *storage.MessageMetadata) {
 		messageID := metadata.MessageID()
 
@@ -258,7 +315,7 @@ func (r *Requester) RequestParents(cachedMsg *storage.CachedMessage, msIndex mil
 		}
 
 		for _, parent := range metadata.Parents() {
-			r.Request(parent, msIndex, preventDiscard...)
+			r.RequestFromPeer(parent, msIndex, preferredPeer, preventDiscard...)
 		}
 	})
 }
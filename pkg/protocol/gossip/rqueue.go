@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/libp2p/go-libp2p-core/peer"
 	"go.uber.org/atomic"
 
 	"github.com/pkg/errors"
@@ -66,6 +67,10 @@ type RequestQueue interface {
 	// It also discards requests in the pending set of which their enqueue time is over the given delta threshold.
 	// If discardOlderThan is zero, no requests are discarded.
 	EnqueuePending(discardOlderThan time.Duration) (queued int)
+	// EnqueuePendingRequest enqueues the pending request for the given data back into the queue,
+	// e.g. because the peer it was sent to didn't answer in time.
+	// Returns false if the request is not pending anymore.
+	EnqueuePendingRequest(data interface{}) bool
 	// Size returns the size of currently queued, requested/pending and processing requests.
 	Size() (queued int, pending int, processing int)
 	// Empty tells whether the queue has no queued and pending requests.
@@ -74,6 +79,8 @@ type RequestQueue interface {
 	Requests() (queued []*Request, pending []*Request, processing []*Request)
 	// AvgLatency returns the average latency of enqueueing and then receiving a request.
 	AvgLatency() int64
+	// Stats returns the amount of fulfilled, unanswered and dropped requests.
+	Stats() RequestQueueStats
 	// Filter adds the given filter function to the queue. Passing nil resets the current one.
 	// Setting a filter automatically clears all queued and pending requests which do not fulfill
 	// the filter criteria.
@@ -85,16 +92,79 @@ type FilterFunc func(r *Request) bool
 
 const DefaultLatencyResolution = 100
 
+// RequestQueueStats holds the statistics of a RequestQueue.
+// Only requests which were sent to peers count as hits or misses.
+type RequestQueueStats struct {
+	// The amount of requests whose data was received.
+	Hits uint32
+	// The amount of requests which were discarded before their data was received.
+	Misses uint32
+	// The amount of requests which were dropped before they were sent, because the queue was full or their time to live expired.
+	Dropped uint32
+}
+
+// the default options applied to the RequestQueue.
+var defaultRequestQueueOptions = []RequestQueueOption{
+	WithRequestQueueLatencyResolution(DefaultLatencyResolution),
+}
+
+// RequestQueueOptions define options for the RequestQueue.
+type RequestQueueOptions struct {
+	// The amount of received requests over which the average latency is computed.
+	LatencyResolution int64
+	// The maximum amount of queued and pending requests. 0 means unlimited.
+	MaxSize int
+	// The maximum time a request stays in the queue before it is dropped. 0 means unlimited.
+	TTL time.Duration
+}
+
+// applies the given RequestQueueOption.
+func (rqo *RequestQueueOptions) apply(opts ...RequestQueueOption) {
+	for _, opt := range opts {
+		opt(rqo)
+	}
+}
+
+// RequestQueueOption is a function setting a RequestQueueOptions option.
+type RequestQueueOption func(opts *RequestQueueOptions)
+
+// WithRequestQueueLatencyResolution sets the amount of received requests over which the average latency is computed.
+func WithRequestQueueLatencyResolution(latencyResolution int64) RequestQueueOption {
+	return func(opts *RequestQueueOptions) {
+		opts.LatencyResolution = latencyResolution
+	}
+}
+
+// WithRequestQueueMaxSize sets the maximum amount of queued and pending requests.
+// Requests which can't be discarded are enqueued nevertheless.
+func WithRequestQueueMaxSize(maxSize int) RequestQueueOption {
+	return func(opts *RequestQueueOptions) {
+		opts.MaxSize = maxSize
+	}
+}
+
+// WithRequestQueueTTL sets the maximum time a request stays in the queue before it is dropped.
+// Requests which can't be discarded are kept nevertheless.
+func WithRequestQueueTTL(ttl time.Duration) RequestQueueOption {
+	return func(opts *RequestQueueOptions) {
+		opts.TTL = ttl
+	}
+}
+
 // NewRequestQueue creates a new RequestQueue where request are prioritized over their milestone index (lower = higher priority).
-func NewRequestQueue(latencyResolution ...int32) RequestQueue {
+func NewRequestQueue(opts ...RequestQueueOption) RequestQueue {
+	options := &RequestQueueOptions{}
+	options.apply(defaultRequestQueueOptions...)
+	options.apply(opts...)
+
 	q := &priorityqueue{
-		queue:      make([]*Request, 0),
-		queued:     make(map[string]*Request),
-		pending:    make(map[string]*Request),
-		processing: make(map[string]*Request),
-	}
-	if len(latencyResolution) == 0 {
-		q.latencyResolution = DefaultLatencyResolution
+		queue:             make([]*Request, 0),
+		queued:            make(map[string]*Request),
+		pending:           make(map[string]*Request),
+		processing:        make(map[string]*Request),
+		latencyResolution: options.LatencyResolution,
+		maxSize:           options.MaxSize,
+		ttl:               options.TTL,
 	}
 	heap.Init(q)
 	return q
@@ -108,6 +178,9 @@ type Request struct {
 	MessageID hornet.MessageID
 	// The milestone index under which this request is linked.
 	MilestoneIndex milestone.Index
+	// The peer which sent a child of the requested message.
+	// The request is only sent to this peer until the sticky request timeout is reached.
+	PreferredPeer peer.ID
 	// internal to the priority queue
 	index int
 	// Tells the request queue to not remove this request if the enqueue time is
@@ -116,6 +189,10 @@ type Request struct {
 	// the time at which this request was first enqueued.
 	// do not modify this time
 	EnqueueTime time.Time
+	// whether the request was already sent to a peer.
+	requested bool
+	// whether the fallback to other peers after the sticky request timeout was already scheduled.
+	stickyFallbackScheduled bool
 }
 
 // NewMessageIDRequest creates a new message request for a specific messageID.
@@ -152,6 +229,9 @@ type priorityqueue struct {
 	// otherwise it crashes under 32-bit ARM systems
 	// see: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
 	avgLatency        atomic.Int64
+	hits              atomic.Uint32
+	misses            atomic.Uint32
+	dropped           atomic.Uint32
 	queue             []*Request
 	queued            map[string]*Request
 	pending           map[string]*Request
@@ -159,6 +239,8 @@ type priorityqueue struct {
 	latencyResolution int64
 	latencySum        int64
 	latencyEntries    int64
+	maxSize           int
+	ttl               time.Duration
 	filter            FilterFunc
 	sync.RWMutex
 }
@@ -167,11 +249,35 @@ func (pq *priorityqueue) Next() (r *Request) {
 	pq.Lock()
 	defer pq.Unlock()
 
+	now := time.Now()
+
 	// Pop() doesn't gracefully handle empty queues, so we check it ourselves
-	if len(pq.queued) == 0 {
-		return nil
+	for len(pq.queued) > 0 {
+		r := heap.Pop(pq).(*Request)
+		if !pq.expired(r, now) {
+			r.requested = true
+			return r
+		}
+
+		// the request was in the queue for too long => drop it
+		delete(pq.pending, r.MapKey())
+		pq.countDiscarded(r)
+	}
+	return nil
+}
+
+// countDiscarded counts a discarded request as miss if it was already sent to a peer, otherwise as dropped.
+func (pq *priorityqueue) countDiscarded(r *Request) {
+	if r.requested {
+		pq.misses.Inc()
+		return
 	}
-	return heap.Pop(pq).(*Request)
+	pq.dropped.Inc()
+}
+
+// expired tells whether the request was in the queue longer than the time to live.
+func (pq *priorityqueue) expired(r *Request, now time.Time) bool {
+	return pq.ttl > 0 && !r.PreventDiscard && now.Sub(r.EnqueueTime) > pq.ttl
 }
 
 func (pq *priorityqueue) Enqueue(r *Request) bool {
@@ -192,6 +298,11 @@ func (pq *priorityqueue) Enqueue(r *Request) bool {
 	if pq.filter != nil && !pq.filter(r) {
 		return false
 	}
+	if pq.maxSize > 0 && !r.PreventDiscard && len(pq.queued)+len(pq.pending) >= pq.maxSize {
+		// the queue is full => drop the request
+		pq.dropped.Inc()
+		return false
+	}
 	r.EnqueueTime = time.Now()
 	heap.Push(pq, r)
 	return true
@@ -243,6 +354,7 @@ func (pq *priorityqueue) Received(data interface{}) *Request {
 
 		// add the request to processing
 		pq.processing[requestMapKey] = req
+		pq.hits.Inc()
 
 		return req
 	}
@@ -254,10 +366,12 @@ func (pq *priorityqueue) Received(data interface{}) *Request {
 
 		// add the request to processing
 		pq.processing[requestMapKey] = req
+		pq.hits.Inc()
 
 		return req
 	}
 
+	// the data was not requested
 	return nil
 }
 
@@ -296,11 +410,25 @@ func (pq *priorityqueue) EnqueuePending(discardOlderThan time.Duration) int {
 		}
 		// discard request from the queue
 		delete(pq.pending, k)
+		pq.countDiscarded(v)
 		enqueued--
 	}
 	return enqueued
 }
 
+func (pq *priorityqueue) EnqueuePendingRequest(data interface{}) bool {
+	pq.Lock()
+	defer pq.Unlock()
+
+	r, pending := pq.pending[getRequestMapKey(data)]
+	if !pending {
+		return false
+	}
+
+	heap.Push(pq, r)
+	return true
+}
+
 func (pq *priorityqueue) Size() (int, int, int) {
 	pq.RLock()
 	defer pq.RUnlock()
@@ -323,6 +451,14 @@ func (pq *priorityqueue) AvgLatency() int64 {
 	return pq.avgLatency.Load()
 }
 
+func (pq *priorityqueue) Stats() RequestQueueStats {
+	return RequestQueueStats{
+		Hits:    pq.hits.Load(),
+		Misses:  pq.misses.Load(),
+		Dropped: pq.dropped.Load(),
+	}
+}
+
 func (pq *priorityqueue) Requests() (queued []*Request, pending []*Request, processing []*Request) {
 	pq.RLock()
 	defer pq.RUnlock()
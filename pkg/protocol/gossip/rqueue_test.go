@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"math/rand"
 	"testing"
+	"time"
 
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/gohornet/hornet/pkg/model/hornet"
@@ -178,3 +180,138 @@ func TestRequestQueue(t *testing.T) {
 		assert.Equal(t, req, r)
 	}
 }
+
+func TestRequestQueuePreferredPeer(t *testing.T) {
+	q := gossip.NewRequestQueue()
+
+	request := &gossip.Request{
+		MessageID:      randMessageID(),
+		MilestoneIndex: 10,
+		PreferredPeer:  peer.ID("peerA"),
+	}
+	assert.True(t, q.Enqueue(request))
+
+	r := q.Next()
+	assert.Equal(t, peer.ID("peerA"), r.PreferredPeer)
+	enqueueTime := r.EnqueueTime
+
+	// re-enqueued pending requests keep the preferred peer and the first enqueue time,
+	// so the sticky request timeout is measured from the first request on
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 1, q.EnqueuePending(0))
+
+	r = q.Next()
+	assert.Equal(t, peer.ID("peerA"), r.PreferredPeer)
+	assert.Equal(t, enqueueTime, r.EnqueueTime)
+}
+
+func TestRequestQueueEnqueuePendingRequest(t *testing.T) {
+	q := gossip.NewRequestQueue()
+
+	pendingID := randMessageID()
+	receivedID := randMessageID()
+	assert.True(t, q.Enqueue(gossip.NewMessageIDRequest(pendingID, 10)))
+	assert.True(t, q.Enqueue(gossip.NewMessageIDRequest(receivedID, 11)))
+	assert.NotNil(t, q.Next())
+	assert.NotNil(t, q.Next())
+
+	// only the request which wasn't answered is enqueued again
+	assert.NotNil(t, q.Received(receivedID))
+	assert.False(t, q.EnqueuePendingRequest(receivedID))
+	assert.True(t, q.EnqueuePendingRequest(pendingID))
+	assert.True(t, q.IsQueued(pendingID))
+
+	// a queued request is not pending anymore
+	assert.False(t, q.EnqueuePendingRequest(pendingID))
+
+	r := q.Next()
+	assert.Equal(t, pendingID, r.MessageID)
+	assert.Nil(t, q.Next())
+}
+
+func TestRequestQueueMaxSize(t *testing.T) {
+	q := gossip.NewRequestQueue(gossip.WithRequestQueueMaxSize(2))
+
+	assert.True(t, q.Enqueue(gossip.NewMessageIDRequest(randMessageID(), 1)))
+	assert.True(t, q.Enqueue(gossip.NewMessageIDRequest(randMessageID(), 2)))
+
+	// pending requests count towards the size
+	assert.NotNil(t, q.Next())
+
+	// the queue is full
+	assert.False(t, q.Enqueue(gossip.NewMessageIDRequest(randMessageID(), 3)))
+	assert.EqualValues(t, 1, q.Stats().Dropped)
+
+	// requests which can't be discarded are enqueued nevertheless
+	request := gossip.NewMessageIDRequest(randMessageID(), 4)
+	request.PreventDiscard = true
+	assert.True(t, q.Enqueue(request))
+
+	queued, pending, _ := q.Size()
+	assert.Equal(t, 2, queued)
+	assert.Equal(t, 1, pending)
+}
+
+func TestRequestQueueTTL(t *testing.T) {
+	q := gossip.NewRequestQueue(gossip.WithRequestQueueTTL(20 * time.Millisecond))
+
+	expiredRequest := gossip.NewMessageIDRequest(randMessageID(), 1)
+	assert.True(t, q.Enqueue(expiredRequest))
+
+	keptRequest := gossip.NewMessageIDRequest(randMessageID(), 2)
+	keptRequest.PreventDiscard = true
+	assert.True(t, q.Enqueue(keptRequest))
+
+	time.Sleep(30 * time.Millisecond)
+
+	freshRequest := gossip.NewMessageIDRequest(randMessageID(), 3)
+	assert.True(t, q.Enqueue(freshRequest))
+
+	// the expired request is dropped instead of being sent
+	assert.Equal(t, keptRequest, q.Next())
+	assert.Equal(t, freshRequest, q.Next())
+	assert.Nil(t, q.Next())
+
+	assert.False(t, q.IsQueued(expiredRequest.MessageID))
+	assert.False(t, q.IsPending(expiredRequest.MessageID))
+	assert.EqualValues(t, 1, q.Stats().Dropped)
+}
+
+func TestRequestQueueStats(t *testing.T) {
+	q := gossip.NewRequestQueue()
+
+	request := gossip.NewMessageIDRequest(randMessageID(), 1)
+	assert.True(t, q.Enqueue(request))
+	assert.NotNil(t, q.Next())
+
+	assert.Equal(t, request, q.Received(request.MessageID))
+
+	// received data which was not requested is not counted
+	assert.Nil(t, q.Received(randMessageID()))
+	assert.Nil(t, q.Received(randMessageID()))
+
+	// requests which were sent but not answered in time are counted as misses
+	unansweredRequest := gossip.NewMessageIDRequest(randMessageID(), 2)
+	assert.True(t, q.Enqueue(unansweredRequest))
+	assert.Equal(t, unansweredRequest, q.Next())
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 0, q.EnqueuePending(time.Millisecond))
+
+	assert.Equal(t, gossip.RequestQueueStats{Hits: 1, Misses: 1}, q.Stats())
+}
+
+func TestRequestQueueTTLMisses(t *testing.T) {
+	q := gossip.NewRequestQueue(gossip.WithRequestQueueTTL(20 * time.Millisecond))
+
+	request := gossip.NewMessageIDRequest(randMessageID(), 1)
+	assert.True(t, q.Enqueue(request))
+	assert.Equal(t, request, q.Next())
+
+	// the sent request is re-enqueued, but its time to live expires before it is sent again
+	assert.Equal(t, 1, q.EnqueuePending(0))
+	time.Sleep(30 * time.Millisecond)
+	assert.Nil(t, q.Next())
+
+	assert.Equal(t, gossip.RequestQueueStats{Misses: 1}, q.Stats())
+}
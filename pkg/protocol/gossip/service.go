@@ -3,6 +3,7 @@ package gossip
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/libp2p/go-libp2p-core/host"
@@ -10,6 +11,7 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/protocol"
 	"github.com/multiformats/go-multiaddr"
+	"golang.org/x/time/rate"
 
 	"github.com/gohornet/hornet/pkg/metrics"
 	"github.com/gohornet/hornet/pkg/model/milestone"
@@ -63,6 +65,9 @@ const (
 	// StreamCancelReasonHostShutdown defines a stream cancellation
 	// because the host is shutting down.
 	StreamCancelReasonHostShutdown StreamCancelReason = "host shutdown"
+	// StreamCancelReasonBlacklisted defines a stream cancellation
+	// because the peer is blacklisted.
+	StreamCancelReasonBlacklisted StreamCancelReason = "peer blacklisted"
 )
 
 const (
@@ -77,6 +82,7 @@ var defaultServiceOptions = []ServiceOption{
 	WithStreamReadTimeout(1 * time.Minute),
 	WithStreamWriteTimeout(10 * time.Second),
 	WithUnknownPeersLimit(0),
+	WithCompression(CompressionNone),
 }
 
 // ServiceOptions define options for a Service.
@@ -93,6 +99,16 @@ type ServiceOptions struct {
 	streamWriteTimeout time.Duration
 	// The amount of unknown peers to allow to have a gossip stream with.
 	unknownPeersLimit int
+	// The upload limit of a single stream in bytes per second.
+	streamUploadLimit int
+	// The download limit of a single stream in bytes per second.
+	streamDownloadLimit int
+	// The upload limit of all streams in bytes per second.
+	uploadLimit int
+	// The download limit of all streams in bytes per second.
+	downloadLimit int
+	// The compression to use with peers which support it.
+	compression Compression
 }
 
 // applies the given ServiceOption.
@@ -145,6 +161,32 @@ func WithUnknownPeersLimit(limit int) ServiceOption {
 	}
 }
 
+// WithStreamBandwidthLimits defines the upload and download limits in bytes per second for every single stream.
+// A limit of zero disables the limitation.
+func WithStreamBandwidthLimits(uploadLimit int, downloadLimit int) ServiceOption {
+	return func(opts *ServiceOptions) {
+		opts.streamUploadLimit = uploadLimit
+		opts.streamDownloadLimit = downloadLimit
+	}
+}
+
+// WithBandwidthLimits defines the upload and download limits in bytes per second shared by all streams.
+// A limit of zero disables the limitation.
+func WithBandwidthLimits(uploadLimit int, downloadLimit int) ServiceOption {
+	return func(opts *ServiceOptions) {
+		opts.uploadLimit = uploadLimit
+		opts.downloadLimit = downloadLimit
+	}
+}
+
+// WithCompression defines the compression which is negotiated with peers that support it.
+// Peers without support for the compression fall back to uncompressed streams.
+func WithCompression(compression Compression) ServiceOption {
+	return func(opts *ServiceOptions) {
+		opts.compression = compression
+	}
+}
+
 // ServiceOption is a function setting a ServiceOptions option.
 type ServiceOption func(opts *ServiceOptions)
 
@@ -158,6 +200,9 @@ type Service struct {
 	// the libp2p host instance from which to work with.
 	host     host.Host
 	protocol protocol.ID
+	// the supported protocol IDs in the order of preference, mapped to their compression.
+	protocols            []protocol.ID
+	protocolCompressions map[protocol.ID]Compression
 	// holds the set of protocols.
 	streams map[peer.ID]*Protocol
 	// the instance of the peeringManager to work with.
@@ -166,10 +211,19 @@ type Service struct {
 	serverMetrics *metrics.ServerMetrics
 	// holds the service options.
 	opts *ServiceOptions
+	// done if the service is shut down.
+	ctx context.Context
 	// tells whether the service was shut down.
 	stopped *typeutils.AtomicBool
 	// the amount of unknown peers with which a gossip stream is ongoing.
 	unknownPeers map[peer.ID]struct{}
+	// limits the upload bandwidth shared by all streams.
+	uploadLimiter *rate.Limiter
+	// limits the download bandwidth shared by all streams.
+	downloadLimiter *rate.Limiter
+	// holds the blacklisted peers and the time until they are blacklisted.
+	blacklist     map[peer.ID]time.Time
+	blacklistLock sync.RWMutex
 	// event loop channels
 	inboundStreamChan   chan network.Stream
 	connectedChan       chan *connectionmsg
@@ -205,7 +259,11 @@ func NewService(
 		serverMetrics:       serverMetrics,
 		opts:                srvOpts,
 		stopped:             typeutils.NewAtomicBool(),
+		ctx:                 context.Background(),
 		unknownPeers:        map[peer.ID]struct{}{},
+		uploadLimiter:       newBandwidthLimiter(srvOpts.uploadLimit),
+		downloadLimiter:     newBandwidthLimiter(srvOpts.downloadLimit),
+		blacklist:           make(map[peer.ID]time.Time),
 		inboundStreamChan:   make(chan network.Stream, 10),
 		connectedChan:       make(chan *connectionmsg, 10),
 		disconnectedChan:    make(chan *connectionmsg, 10),
@@ -214,6 +272,7 @@ func NewService(
 		streamReqChan:       make(chan *streamreqmsg, 10),
 		forEachChan:         make(chan *foreachmsg, 10),
 	}
+	gossipService.protocols, gossipService.protocolCompressions = supportedProtocols(protocol, srvOpts.compression)
 	gossipService.WrappedLogger = utils.NewWrappedLogger(gossipService.opts.logger)
 	gossipService.registerLoggerOnEvents()
 	return gossipService
@@ -259,14 +318,51 @@ func (s *Service) SynchronizedCount(latestMilestoneIndex milestone.Index) int {
 	return count
 }
 
+// BlacklistPeer prevents gossip protocol streams with the given peer for the given duration.
+// Ongoing streams are not affected.
+func (s *Service) BlacklistPeer(peerID peer.ID, duration time.Duration) {
+	s.blacklistLock.Lock()
+	defer s.blacklistLock.Unlock()
+
+	s.blacklist[peerID] = time.Now().Add(duration)
+}
+
+// IsBlacklisted tells whether gossip protocol streams with the given peer are prevented.
+func (s *Service) IsBlacklisted(peerID peer.ID) bool {
+	s.blacklistLock.RLock()
+	until, has := s.blacklist[peerID]
+	s.blacklistLock.RUnlock()
+
+	if !has {
+		return false
+	}
+
+	if time.Now().Before(until) {
+		return true
+	}
+
+	// clean up expired entries
+	s.blacklistLock.Lock()
+	defer s.blacklistLock.Unlock()
+
+	if until, has := s.blacklist[peerID]; has && !time.Now().Before(until) {
+		delete(s.blacklist, peerID)
+	}
+
+	return false
+}
+
 // Start starts the Service's event loop.
 func (s *Service) Start(ctx context.Context) {
-	s.host.SetStreamHandler(s.protocol, func(stream network.Stream) {
-		if s.stopped.IsSet() {
-			return
-		}
-		s.inboundStreamChan <- stream
-	})
+	s.ctx = ctx
+	for _, protocolID := range s.protocols {
+		s.host.SetStreamHandler(protocolID, func(stream network.Stream) {
+			if s.stopped.IsSet() {
+				return
+			}
+			s.inboundStreamChan <- stream
+		})
+	}
 	s.peeringManager.Events.Connected.Attach(events.NewClosure(func(peer *p2p.Peer, conn network.Conn) {
 		if s.stopped.IsSet() {
 			return
@@ -422,7 +518,11 @@ func (s *Service) handleInboundStream(stream network.Stream) *Protocol {
 	})
 
 	var cancelReason StreamCancelReason
-	if hasUnknownRelation {
+	if s.IsBlacklisted(remotePeerID) {
+		cancelReason = StreamCancelReasonBlacklisted
+	}
+
+	if len(cancelReason) == 0 && hasUnknownRelation {
 		switch {
 		case s.opts.unknownPeersLimit == 0:
 			cancelReason = StreamCancelReasonInsufficientPeerRelation
@@ -468,6 +568,10 @@ func (s *Service) handleConnected(peer *p2p.Peer, conn network.Conn) (*Protocol,
 		return nil, nil
 	}
 
+	if s.IsBlacklisted(peer.ID) {
+		return nil, nil
+	}
+
 	if peer.Relation == p2p.PeerRelationUnknown {
 		if len(s.unknownPeers) >= s.opts.unknownPeersLimit {
 			return nil, nil
@@ -487,7 +591,7 @@ func (s *Service) openStream(peerID peer.ID) (network.Stream, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), s.opts.streamConnectTimeout)
 	defer cancel()
 
-	stream, err := s.host.NewStream(ctx, peerID, s.protocol)
+	stream, err := s.host.NewStream(ctx, peerID, s.protocols...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create gossip stream to %s: %w", peerID, err)
 	}
@@ -500,6 +604,10 @@ func (s *Service) openStream(peerID peer.ID) (network.Stream, error) {
 // registers a protocol instance for the given peer and stream.
 func (s *Service) registerProtocol(peerID peer.ID, stream network.Stream) *Protocol {
 	proto := NewProtocol(peerID, stream, s.opts.sendQueueSize, s.opts.streamReadTimeout, s.opts.streamWriteTimeout, s.serverMetrics)
+	proto.uploadLimiters = []*rate.Limiter{newBandwidthLimiter(s.opts.streamUploadLimit), s.uploadLimiter}
+	proto.downloadLimiters = []*rate.Limiter{newBandwidthLimiter(s.opts.streamDownloadLimit), s.downloadLimiter}
+	proto.bindContext(s.ctx)
+	proto.setCompression(s.protocolCompressions[stream.Protocol()])
 	s.streams[peerID] = proto
 	return proto
 }
@@ -511,6 +619,8 @@ func (s *Service) deregisterProtocol(peerID peer.ID) (bool, error) {
 	}
 	proto := s.streams[peerID]
 	delete(s.streams, peerID)
+	// cancel pending waits for bandwidth
+	proto.cancel()
 	if err := proto.Stream.Reset(); err != nil {
 		return true, fmt.Errorf("unable to cleanly reset stream to %s: %w", peerID, err)
 	}
@@ -556,6 +666,10 @@ func (s *Service) handleRelationUpdated(peer *p2p.Peer, oldRel p2p.PeerRelation)
 		return nil, nil
 	}
 
+	if s.IsBlacklisted(peer.ID) {
+		return nil, nil
+	}
+
 	// here we might open a stream even if the connection is inbound:
 	// the service should however take care of duplicated streams
 	stream, err := s.openStream(peer.ID)
@@ -607,7 +721,7 @@ func (m *netNotifiee) Connected(net network.Network, conn network.Conn)
 func (m *netNotifiee) Disconnected(net network.Network, conn network.Conn)            {}
 func (m *netNotifiee) OpenedStream(net network.Network, stream network.Stream)        {}
 func (m *netNotifiee) ClosedStream(net network.Network, stream network.Stream) {
-	if stream.Protocol() != m.protocol {
+	if _, supported := m.protocolCompressions[stream.Protocol()]; !supported {
 		return
 	}
 	if m.stopped.IsSet() {
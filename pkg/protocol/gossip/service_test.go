@@ -253,3 +253,59 @@ func TestWithUnknownPeersLimit(t *testing.T) {
 		return node3ProtocolTerminated == 2
 	}, 4*time.Second, 10*time.Millisecond)
 }
+
+func TestWithCompression(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := configuration.New()
+	err := cfg.Set("logger.disableStacktrace", true)
+	require.NoError(t, err)
+
+	// no need to check the error, since the global logger could already be initialized
+	_ = logger.InitGlobalLogger(cfg)
+
+	mngOpts := []p2p.ManagerOption{
+		p2p.WithManagerReconnectInterval(1*time.Second, 500*time.Millisecond),
+	}
+	srvOpts := []gossip.ServiceOption{
+		gossip.WithCompression(gossip.CompressionSnappy),
+	}
+
+	node1, node1Manager, node1Service, node1AddrInfo := newNode("node1", ctx, t, mngOpts, srvOpts)
+	node2, node2Manager, node2Service, node2AddrInfo := newNode("node2", ctx, t, mngOpts, srvOpts)
+	node3, node3Manager, node3Service, node3AddrInfo := newNode("node3", ctx, t, mngOpts, nil)
+
+	// node 1 knows node 2 and 3
+	go func() {
+		_ = node1Manager.ConnectPeer(&node2AddrInfo, p2p.PeerRelationKnown)
+	}()
+	go func() {
+		_ = node1Manager.ConnectPeer(&node3AddrInfo, p2p.PeerRelationKnown)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	go func() {
+		_ = node2Manager.ConnectPeer(&node1AddrInfo, p2p.PeerRelationKnown)
+	}()
+	go func() {
+		_ = node3Manager.ConnectPeer(&node1AddrInfo, p2p.PeerRelationKnown)
+	}()
+
+	require.Eventually(t, func() bool {
+		return node1Service.Protocol(node2.ID()) != nil && node2Service.Protocol(node1.ID()) != nil
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return node1Service.Protocol(node3.ID()) != nil && node3Service.Protocol(node1.ID()) != nil
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// node 1 and 2 both support compression
+	require.Equal(t, gossip.CompressionSnappy, node1Service.Protocol(node2.ID()).Compression)
+	require.Equal(t, gossip.CompressionSnappy, node2Service.Protocol(node1.ID()).Compression)
+
+	// node 3 doesn't, so node 1 falls back to an uncompressed stream
+	require.Equal(t, gossip.CompressionNone, node1Service.Protocol(node3.ID()).Compression)
+	require.Equal(t, gossip.CompressionNone, node3Service.Protocol(node1.ID()).Compression)
+
+	_, err = gossip.ParseCompression("gzip")
+	require.Error(t, err)
+}
@@ -173,14 +173,18 @@ type Heartbeat struct {
 }
 
 // ParseHeartbeat parses the given message into a heartbeat.
-func ParseHeartbeat(data []byte) *Heartbeat {
+func ParseHeartbeat(data []byte) (*Heartbeat, error) {
+	if len(data) != int(HeartbeatMessageDefinition.MaxBytesLength) {
+		return nil, ErrInvalidSourceLength
+	}
+
 	return &Heartbeat{
 		SolidMilestoneIndex:  milestone.Index(binary.LittleEndian.Uint32(data[:4])),
 		PrunedMilestoneIndex: milestone.Index(binary.LittleEndian.Uint32(data[4:8])),
 		LatestMilestoneIndex: milestone.Index(binary.LittleEndian.Uint32(data[8:12])),
 		ConnectedNeighbors:   int(data[12]),
 		SyncedNeighbors:      int(data[13]),
-	}
+	}, nil
 }
 
 func HeartbeatCaller(handler interface{}, params ...interface{}) {
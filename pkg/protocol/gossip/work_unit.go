@@ -1,10 +1,13 @@
 package gossip
 
 import (
+	"time"
+
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/pkg/errors"
 
 	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/p2p"
 	"github.com/iotaledger/hive.go/objectstorage"
 	"github.com/iotaledger/hive.go/syncutils"
 )
@@ -24,6 +27,7 @@ func newWorkUnit(key []byte, messageProcessor *MessageProcessor) *WorkUnit {
 		receivedMsgBytes: make([]byte, len(key)),
 		receivedFrom:     make([]*Protocol, 0),
 		messageProcessor: messageProcessor,
+		receivedTime:     time.Now(),
 	}
 	copy(wu.receivedMsgBytes, key)
 	return wu
@@ -50,8 +54,12 @@ type WorkUnit struct {
 
 	// data
 	receivedMsgBytes []byte
-	msg              *storage.Message
-	requested        bool
+	// the time the message bytes were received first.
+	receivedTime time.Time
+	msg          *storage.Message
+	requested    bool
+	// the message filters decided not to broadcast the message.
+	deprioritized bool
 
 	// status
 	stateLock syncutils.RWMutex
@@ -99,11 +107,15 @@ func (wu *WorkUnit) addReceivedFrom(p *Protocol) {
 // punishes, respectively increases the invalid message metric of all peers
 // which sent the given underlying message of this WorkUnit.
 // it also closes the connection to these peers.
-func (wu *WorkUnit) punish(reason error) {
+func (wu *WorkUnit) punish(violation p2p.ProtocolViolation, reason error) {
 	wu.receivedFromLock.Lock()
 	defer wu.receivedFromLock.Unlock()
 	for _, p := range wu.receivedFrom {
 		wu.messageProcessor.serverMetrics.InvalidMessages.Inc()
+		p.Metrics.InvalidMessages.Inc()
+
+		// count the violation, the peer may get banned
+		wu.messageProcessor.peeringManager.ReportViolation(p.PeerID, violation)
 
 		// drop the connection to the peer
 		_ = wu.messageProcessor.peeringManager.DisconnectPeer(p.PeerID, errors.WithMessagef(reason, "peer was punished"))
@@ -0,0 +1,99 @@
+package restapi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// acceptedMediaRange is a media range of an Accept header with its quality value.
+type acceptedMediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+// parseAccept parses the media ranges of an Accept header.
+// Media ranges with an invalid quality value are ignored.
+func parseAccept(header string) []acceptedMediaRange {
+	var mediaRanges []acceptedMediaRange
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" {
+			continue
+		}
+
+		quality := 1.0
+		valid := true
+		for _, param := range params[1:] {
+			keyValue := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(keyValue) != 2 || strings.ToLower(strings.TrimSpace(keyValue[0])) != "q" {
+				continue
+			}
+
+			q, err := strconv.ParseFloat(strings.TrimSpace(keyValue[1]), 64)
+			if err != nil || q < 0 || q > 1 {
+				valid = false
+				break
+			}
+			quality = q
+		}
+
+		if valid {
+			mediaRanges = append(mediaRanges, acceptedMediaRange{mediaType: mediaType, quality: quality})
+		}
+	}
+	return mediaRanges
+}
+
+// acceptQuality returns the quality value of the most specific media range matching the media type.
+// Returns 0 if no media range matches.
+func acceptQuality(mediaRanges []acceptedMediaRange, mediaType string) float64 {
+	typeWildcard := mediaType[:strings.Index(mediaType, "/")+1] + "*"
+
+	quality := 0.0
+	specificity := -1
+	for _, mediaRange := range mediaRanges {
+		var rangeSpecificity int
+		switch mediaRange.mediaType {
+		case mediaType:
+			rangeSpecificity = 2
+		case typeWildcard:
+			rangeSpecificity = 1
+		case "*/*":
+			rangeSpecificity = 0
+		default:
+			continue
+		}
+
+		if rangeSpecificity > specificity {
+			quality = mediaRange.quality
+			specificity = rangeSpecificity
+		}
+	}
+	return quality
+}
+
+// NegotiateMediaType returns the offered media type the client prefers according to its Accept header.
+// If several media types are accepted with the same quality, the first offered one is returned.
+// The first offered media type is also returned if the client doesn't accept any of them.
+func NegotiateMediaType(c echo.Context, offers ...string) string {
+	header := c.Request().Header.Get(echo.HeaderAccept)
+	if header == "" {
+		return offers[0]
+	}
+
+	mediaRanges := parseAccept(header)
+
+	preferred := offers[0]
+	preferredQuality := 0.0
+	for _, offer := range offers {
+		if quality := acceptQuality(mediaRanges, offer); quality > preferredQuality {
+			preferred = offer
+			preferredQuality = quality
+		}
+	}
+	return preferred
+}
@@ -0,0 +1,240 @@
+package restapi
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/jwt"
+	"github.com/iotaledger/hive.go/kvstore"
+)
+
+const (
+	// the interval in which the entries older than the retention are deleted.
+	auditLogPruningInterval = time.Hour
+)
+
+// AuditLogEntry is a recorded API call.
+type AuditLogEntry struct {
+	// The unix timestamp of the call.
+	Timestamp int64 `json:"timestamp"`
+	// The subject of the JWT the call was authorized with.
+	Subject string `json:"subject,omitempty"`
+	// The ID of the JWT the call was authorized with.
+	TokenID string `json:"tokenId,omitempty"`
+	// The IP address of the client.
+	RemoteAddress string `json:"remoteAddress"`
+	// The HTTP method of the call.
+	Method string `json:"method"`
+	// The route of the call, e.g. "/api/v2/peers/:peerID".
+	Route string `json:"route"`
+	// The requested path.
+	Path string `json:"path"`
+	// The HTTP status code of the response.
+	StatusCode int `json:"statusCode"`
+	// The error the call failed with.
+	Error string `json:"error,omitempty"`
+}
+
+// AuditLog persists the mutating calls of privileged routes.
+// The key of an entry consists of the time of the call and a sequence number,
+// so the entries are ordered by time in the store.
+type AuditLog struct {
+	store     kvstore.KVStore
+	retention time.Duration
+	routes    []*regexp.Regexp
+	sequence  uint32
+
+	pruneLock sync.Mutex
+	lastPrune time.Time
+}
+
+// NewAuditLog creates a new AuditLog that records the calls of the given routes and keeps them for the given retention.
+func NewAuditLog(store kvstore.KVStore, retention time.Duration, routes []string) (*AuditLog, error) {
+
+	regexes := make([]*regexp.Regexp, 0, len(routes))
+	for _, route := range routes {
+		reg := CompileRouteAsRegex(strings.ToLower(route))
+		if reg == nil {
+			return nil, fmt.Errorf("invalid audit log route: %s", route)
+		}
+		regexes = append(regexes, reg)
+	}
+
+	return &AuditLog{
+		store:     store,
+		retention: retention,
+		routes:    regexes,
+	}, nil
+}
+
+func (l *AuditLog) entryKey(timestamp time.Time) []byte {
+	key := make([]byte, 12)
+	binary.BigEndian.PutUint64(key[:8], uint64(timestamp.UnixNano()))
+	binary.BigEndian.PutUint32(key[8:], atomic.AddUint32(&l.sequence, 1))
+	return key
+}
+
+// audited returns whether calls of the request's route are recorded.
+// Only calls that may change the state of the node are recorded.
+func (l *AuditLog) audited(c echo.Context) bool {
+	switch c.Request().Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+
+	path := strings.ToLower(c.Path())
+	for _, reg := range l.routes {
+		if reg.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Record stores the given entry.
+func (l *AuditLog) Record(entry *AuditLogEntry) error {
+
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Unix(entry.Timestamp, 0)
+	if err := l.store.Set(l.entryKey(timestamp), value); err != nil {
+		return err
+	}
+
+	l.pruneLock.Lock()
+	defer l.pruneLock.Unlock()
+
+	if timestamp.Sub(l.lastPrune) < auditLogPruningInterval {
+		return nil
+	}
+	l.lastPrune = timestamp
+
+	return l.prune(timestamp.Add(-l.retention))
+}
+
+// prune deletes the entries that were recorded before the given time.
+func (l *AuditLog) prune(before time.Time) error {
+
+	var keysToDelete []kvstore.Key
+	if err := l.store.IterateKeys(kvstore.EmptyPrefix, func(key kvstore.Key) bool {
+		if int64(binary.BigEndian.Uint64(key[:8])) < before.UnixNano() {
+			keysToDelete = append(keysToDelete, append(kvstore.Key{}, key...))
+		}
+		return true
+	}); err != nil {
+		return err
+	}
+
+	for _, key := range keysToDelete {
+		if err := l.store.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Entries returns the entries of the given page that were recorded since the given time, ordered by time.
+func (l *AuditLog) Entries(since time.Time, page *Page) ([]*AuditLogEntry, error) {
+
+	entries := make([]*AuditLogEntry, 0)
+
+	var innerErr error
+	if err := l.store.Iterate(kvstore.EmptyPrefix, func(key kvstore.Key, value kvstore.Value) bool {
+		if int64(binary.BigEndian.Uint64(key[:8])) < since.UnixNano() {
+			return true
+		}
+
+		inPage, next := page.Next()
+		if !inPage {
+			return next
+		}
+
+		entry := &AuditLogEntry{}
+		if err := json.Unmarshal(value, entry); err != nil {
+			innerErr = errors.Wrapf(err, "invalid audit log entry: %x", key)
+			return false
+		}
+		entries = append(entries, entry)
+
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	if innerErr != nil {
+		return nil, innerErr
+	}
+
+	return entries, nil
+}
+
+// Middleware returns a middleware that records the calls of the audited routes after they were handled.
+// The request is not affected if the entry can't be recorded, the error is passed to onError instead.
+func (l *AuditLog) Middleware(onError func(err error)) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !l.audited(c) {
+				return next(c)
+			}
+
+			timestamp := time.Now()
+			handlerErr := next(c)
+
+			entry := &AuditLogEntry{
+				Timestamp:     timestamp.Unix(),
+				RemoteAddress: c.RealIP(),
+				Method:        c.Request().Method,
+				Route:         c.Path(),
+				Path:          c.Request().URL.Path,
+				StatusCode:    c.Response().Status,
+			}
+
+			if claims := jwt.ClaimsFromContext(c); claims != nil {
+				entry.Subject = claims.Subject
+				entry.TokenID = claims.Id
+			}
+
+			if handlerErr != nil {
+				// the error response is only written by the error handler after the middlewares returned
+				entry.StatusCode = http.StatusInternalServerError
+				var httpErr *echo.HTTPError
+				if errors.As(handlerErr, &httpErr) {
+					entry.StatusCode = httpErr.Code
+				}
+				entry.Error = handlerErr.Error()
+			}
+
+			if err := l.Record(entry); err != nil {
+				onError(err)
+			}
+
+			return handlerErr
+		}
+	}
+}
+
+// CloseDatabase flushes the store and closes the underlying database.
+func (l *AuditLog) CloseDatabase() error {
+
+	var flushAndCloseError error
+	if err := l.store.Flush(); err != nil {
+		flushAndCloseError = err
+	}
+	if err := l.store.Close(); err != nil {
+		flushAndCloseError = err
+	}
+	return flushAndCloseError
+}
@@ -0,0 +1,79 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// MIMEApplicationCBOR is the content type of CBOR encoded responses.
+	MIMEApplicationCBOR = "application/cbor"
+)
+
+// IsCBORRequested returns whether the client prefers the response encoded as CBOR over JSON.
+func IsCBORRequested(c echo.Context) bool {
+	return NegotiateMediaType(c, echo.MIMEApplicationJSON, MIMEApplicationCBOR) == MIMEApplicationCBOR
+}
+
+// CBORResponse sends the result encoded as CBOR with status code.
+// Many results define their representation with a custom JSON marshaler, so the result is
+// encoded as JSON first and the decoded values are encoded as CBOR. This keeps the structure
+// and field names of both encodings identical.
+func CBORResponse(c echo.Context, statusCode int, result interface{}) error {
+	jsonResult, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonResult))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+
+	cborResult, err := cbor.Marshal(cborValue(value))
+	if err != nil {
+		return err
+	}
+
+	return c.Blob(statusCode, MIMEApplicationCBOR, cborResult)
+}
+
+// cborValue converts the numbers of decoded JSON values to integers where possible,
+// so they are not encoded as floating point numbers.
+func cborValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = cborValue(item)
+		}
+		return v
+
+	case []interface{}:
+		for i, item := range v {
+			v[i] = cborValue(item)
+		}
+		return v
+
+	case json.Number:
+		if i, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return u
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+
+	default:
+		return v
+	}
+}
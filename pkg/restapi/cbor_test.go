@@ -0,0 +1,121 @@
+package restapi
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func newTestContext(accept string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if accept != "" {
+		req.Header.Set(echo.HeaderAccept, accept)
+	}
+	rec := httptest.NewRecorder()
+	return echo.New().NewContext(req, rec), rec
+}
+
+// cborRoundTrip encodes the result with JSONResponse as CBOR and returns the decoded values encoded as JSON.
+func cborRoundTrip(t *testing.T, result interface{}) []byte {
+	c, rec := newTestContext(MIMEApplicationCBOR)
+	require.NoError(t, JSONResponse(c, http.StatusOK, result))
+	require.Equal(t, MIMEApplicationCBOR, rec.Header().Get(echo.HeaderContentType))
+
+	decMode, err := cbor.DecOptions{DefaultMapType: reflect.TypeOf(map[string]interface{}(nil))}.DecMode()
+	require.NoError(t, err)
+
+	var value interface{}
+	require.NoError(t, decMode.Unmarshal(rec.Body.Bytes(), &value))
+
+	jsonResult, err := json.Marshal(value)
+	require.NoError(t, err)
+	return jsonResult
+}
+
+func TestIsCBORRequested(t *testing.T) {
+	tests := []struct {
+		accept string
+		cbor   bool
+	}{
+		{"", false},
+		{"application/json", false},
+		{"application/cbor", true},
+		{"Application/CBOR", true},
+		{"*/*", false},
+		{"application/*", false},
+		{"application/cbor, */*;q=0.1", true},
+		{"application/json, application/cbor", false},
+		{"application/json;q=0.5, application/cbor", true},
+		{"application/json, application/cbor;q=0.9", false},
+		{"application/cbor;q=0", false},
+		{"application/cbor;q=0, */*", false},
+		{"application/cbor;q=invalid", false},
+		{"text/html, application/cbor;q=0.8, */*;q=0.5", true},
+		{"application/cbor-seq", false},
+	}
+
+	for _, test := range tests {
+		c, _ := newTestContext(test.accept)
+		require.Equal(t, test.cbor, IsCBORRequested(c), "Accept: %s", test.accept)
+	}
+}
+
+func TestCBORResponseMessage(t *testing.T) {
+	msg := &iotago.Message{
+		NetworkID: iotago.NetworkIDFromString("testnet"),
+		Parents:   iotago.MessageIDs{{1}, {2}},
+		Payload: &iotago.TaggedData{
+			Tag:  []byte("cbor"),
+			Data: []byte("round trip"),
+		},
+		Nonce: math.MaxUint64,
+	}
+
+	jsonMsg, err := json.Marshal(msg)
+	require.NoError(t, err)
+
+	roundTripped := &iotago.Message{}
+	require.NoError(t, json.Unmarshal(cborRoundTrip(t, msg), roundTripped))
+	require.Equal(t, msg, roundTripped)
+
+	jsonRoundTripped, err := json.Marshal(roundTripped)
+	require.NoError(t, err)
+	require.JSONEq(t, string(jsonMsg), string(jsonRoundTripped))
+}
+
+func TestCBORResponseUint64(t *testing.T) {
+	type amounts struct {
+		Max    uint64 `json:"max"`
+		Int64  uint64 `json:"int64"`
+		Zero   uint64 `json:"zero"`
+		Signed int64  `json:"signed"`
+	}
+
+	result := &amounts{
+		Max:    math.MaxUint64,
+		Int64:  math.MaxInt64 + 1,
+		Zero:   0,
+		Signed: math.MinInt64,
+	}
+
+	c, rec := newTestContext(MIMEApplicationCBOR)
+	require.NoError(t, JSONResponse(c, http.StatusOK, result))
+
+	// the amounts are encoded as integers, not as floating point numbers
+	decoded := &amounts{}
+	require.NoError(t, cbor.Unmarshal(rec.Body.Bytes(), decoded))
+	require.Equal(t, result, decoded)
+
+	roundTripped := &amounts{}
+	require.NoError(t, json.Unmarshal(cborRoundTrip(t, result), roundTripped))
+	require.Equal(t, result, roundTripped)
+}
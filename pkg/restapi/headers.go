@@ -0,0 +1,140 @@
+package restapi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// CORSConfig defines the cross-origin resource sharing policy of a route group.
+// Empty lists fall back to the defaults of echo, which allow all origins and the common methods.
+type CORSConfig struct {
+	// The origins that may access the routes, e.g. "https://faucet.example.com".
+	AllowOrigins []string `json:"allowOrigins" koanf:"allowOrigins"`
+	// The methods that are allowed when accessing the routes.
+	AllowMethods []string `json:"allowMethods" koanf:"allowMethods"`
+	// The request headers that can be used when making the actual request.
+	AllowHeaders []string `json:"allowHeaders" koanf:"allowHeaders"`
+	// Whether the response can be exposed when the credentials flag is true.
+	AllowCredentials bool `json:"allowCredentials" koanf:"allowCredentials"`
+	// How long (in seconds) the results of a preflight request can be cached.
+	MaxAge int `json:"maxAge" koanf:"maxAge"`
+}
+
+// SecurityHeadersConfig defines the security headers that are added to the responses of a route group.
+// Headers with an empty value are not added.
+type SecurityHeadersConfig struct {
+	// The value of the "X-XSS-Protection" header, e.g. "1; mode=block".
+	XSSProtection string `json:"xssProtection" koanf:"xssProtection"`
+	// The value of the "X-Content-Type-Options" header, e.g. "nosniff".
+	ContentTypeNosniff string `json:"contentTypeNosniff" koanf:"contentTypeNosniff"`
+	// The value of the "X-Frame-Options" header, e.g. "DENY".
+	XFrameOptions string `json:"xFrameOptions" koanf:"xFrameOptions"`
+	// The max age (in seconds) of the "Strict-Transport-Security" header, which is only added to TLS requests.
+	HSTSMaxAge int `json:"hstsMaxAge" koanf:"hstsMaxAge"`
+	// The value of the "Content-Security-Policy" header.
+	ContentSecurityPolicy string `json:"contentSecurityPolicy" koanf:"contentSecurityPolicy"`
+	// The value of the "Referrer-Policy" header, e.g. "no-referrer".
+	ReferrerPolicy string `json:"referrerPolicy" koanf:"referrerPolicy"`
+}
+
+// RouteGroup defines the CORS policy and the security headers of the routes matching one of the route patterns.
+type RouteGroup struct {
+	// The name of the route group, e.g. "faucet".
+	Name string `json:"name" koanf:"name"`
+	// The route patterns of the group, e.g. "/api/plugins/faucet/*".
+	Routes []string `json:"routes" koanf:"routes"`
+	// The CORS policy of the group.
+	CORS CORSConfig `json:"cors" koanf:"cors"`
+	// The security headers of the group.
+	SecurityHeaders SecurityHeadersConfig `json:"securityHeaders" koanf:"securityHeaders"`
+}
+
+// corsMiddleware returns the CORS middleware for the given policy.
+func corsMiddleware(config CORSConfig) echo.MiddlewareFunc {
+	corsConfig := middleware.DefaultCORSConfig
+	if len(config.AllowOrigins) > 0 {
+		corsConfig.AllowOrigins = config.AllowOrigins
+	}
+	if len(config.AllowMethods) > 0 {
+		corsConfig.AllowMethods = config.AllowMethods
+	}
+	corsConfig.AllowHeaders = config.AllowHeaders
+	corsConfig.AllowCredentials = config.AllowCredentials
+	corsConfig.MaxAge = config.MaxAge
+
+	return middleware.CORSWithConfig(corsConfig)
+}
+
+// securityHeadersMiddleware returns the middleware that adds the given security headers.
+func securityHeadersMiddleware(config SecurityHeadersConfig) echo.MiddlewareFunc {
+	return middleware.SecureWithConfig(middleware.SecureConfig{
+		XSSProtection:         config.XSSProtection,
+		ContentTypeNosniff:    config.ContentTypeNosniff,
+		XFrameOptions:         config.XFrameOptions,
+		HSTSMaxAge:            config.HSTSMaxAge,
+		ContentSecurityPolicy: config.ContentSecurityPolicy,
+		ReferrerPolicy:        config.ReferrerPolicy,
+	})
+}
+
+// RouteGroupHeaders returns a middleware that applies the CORS policy and the security headers of the first route group
+// with a route pattern matching the path of a request. Requests that don't match any of the groups get the default CORS policy.
+// The request path is matched instead of the route, because CORS preflight requests don't match a registered route.
+func RouteGroupHeaders(routeGroups []*RouteGroup) (echo.MiddlewareFunc, error) {
+
+	type routeGroupHeaders struct {
+		routes  []*regexp.Regexp
+		cors    echo.MiddlewareFunc
+		headers echo.MiddlewareFunc
+	}
+
+	groups := make([]*routeGroupHeaders, 0, len(routeGroups))
+	for _, routeGroup := range routeGroups {
+		if len(routeGroup.Routes) == 0 {
+			return nil, fmt.Errorf("route group %s has no routes", routeGroup.Name)
+		}
+
+		routes := make([]*regexp.Regexp, 0, len(routeGroup.Routes))
+		for _, route := range routeGroup.Routes {
+			reg := CompileRouteAsRegex(strings.ToLower(route))
+			if reg == nil {
+				return nil, fmt.Errorf("invalid route of route group %s: %s", routeGroup.Name, route)
+			}
+			routes = append(routes, reg)
+		}
+
+		groups = append(groups, &routeGroupHeaders{
+			routes:  routes,
+			cors:    corsMiddleware(routeGroup.CORS),
+			headers: securityHeadersMiddleware(routeGroup.SecurityHeaders),
+		})
+	}
+
+	defaultCORS := middleware.CORS()
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+
+		handlers := make([]echo.HandlerFunc, len(groups))
+		for i, g := range groups {
+			// the security headers are also added to the responses of preflight requests
+			handlers[i] = g.headers(g.cors(next))
+		}
+		defaultHandler := defaultCORS(next)
+
+		return func(c echo.Context) error {
+			path := strings.ToLower(c.Request().URL.Path)
+			for i, g := range groups {
+				for _, route := range g.routes {
+					if route.MatchString(path) {
+						return handlers[i](c)
+					}
+				}
+			}
+			return defaultHandler(c)
+		}
+	}, nil
+}
@@ -0,0 +1,118 @@
+package restapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	// QueryParameterPageSize is used to define the page size of a list.
+	QueryParameterPageSize = "pageSize"
+
+	// QueryParameterCursor is used to pass the cursor of the next page of a list, as returned by the previous page.
+	QueryParameterCursor = "cursor"
+)
+
+// Page selects a part of a list that is returned by an endpoint.
+// The items of the list are passed to Next in the order of the list, so the lists don't need to be loaded as a whole.
+type Page struct {
+	// the position of the first item of the page in the list.
+	offset int
+	// the maximum amount of items of the page.
+	pageSize int
+	// the position of the next item in the list.
+	position int
+	// whether the list contains items after the page.
+	hasMore bool
+}
+
+// ParsePage parses the "cursor" and "pageSize" query parameters of the request.
+// The page size defaults to and is capped at maxPageSize. A cursor contains the page size of the first page.
+func ParsePage(c echo.Context, maxPageSize int) (*Page, error) {
+	page := &Page{pageSize: maxPageSize}
+
+	if pageSizeParam := c.QueryParam(QueryParameterPageSize); len(pageSizeParam) > 0 {
+		pageSize, err := strconv.ParseUint(pageSizeParam, 10, 32)
+		if err != nil || pageSize == 0 {
+			return nil, errors.WithMessagef(ErrInvalidParameter, "invalid %s: %s", QueryParameterPageSize, pageSizeParam)
+		}
+		page.pageSize = int(pageSize)
+	}
+
+	if cursorParam := c.QueryParam(QueryParameterCursor); len(cursorParam) > 0 {
+		components := strings.Split(cursorParam, ".")
+		if len(components) != 2 {
+			return nil, errors.WithMessagef(ErrInvalidParameter, "query parameter %s has wrong format", QueryParameterCursor)
+		}
+
+		offset, err := strconv.ParseUint(components[0], 10, 32)
+		if err != nil {
+			return nil, errors.WithMessagef(ErrInvalidParameter, "query parameter %s has wrong format", QueryParameterCursor)
+		}
+
+		pageSize, err := strconv.ParseUint(components[1], 10, 32)
+		if err != nil || pageSize == 0 {
+			return nil, errors.WithMessagef(ErrInvalidParameter, "query parameter %s has wrong format", QueryParameterCursor)
+		}
+
+		page.offset = int(offset)
+		page.pageSize = int(pageSize)
+	}
+
+	if page.pageSize > maxPageSize {
+		page.pageSize = maxPageSize
+	}
+
+	return page, nil
+}
+
+// Next moves on to the next item of the list. It returns whether the item is part of the page
+// and whether the iteration over the list needs to be continued.
+func (p *Page) Next() (inPage bool, next bool) {
+	position := p.position
+	p.position++
+
+	switch {
+	case position < p.offset:
+		return false, true
+	case position < p.offset+p.pageSize:
+		return true, true
+	default:
+		// the list contains more items, there is no need to iterate further
+		p.hasMore = true
+		return false, false
+	}
+}
+
+// Bounds returns the part of a list with the given length that belongs to the page.
+func (p *Page) Bounds(length int) (start int, end int) {
+	start, end = p.offset, p.offset+p.pageSize
+	if start > length {
+		start = length
+	}
+	if end > length {
+		end = length
+	}
+	p.hasMore = end < length
+
+	return start, end
+}
+
+// PageSize returns the maximum amount of items of the page.
+func (p *Page) PageSize() uint32 {
+	return uint32(p.pageSize)
+}
+
+// NextCursor returns the cursor of the next page, or nil if the page is the last one.
+func (p *Page) NextCursor() *string {
+	if !p.hasMore {
+		return nil
+	}
+
+	cursor := fmt.Sprintf("%d.%d", p.offset+p.pageSize, p.pageSize)
+	return &cursor
+}
@@ -0,0 +1,151 @@
+package restapi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// RateLimitIdentifierIP limits the requests per client IP.
+	RateLimitIdentifierIP = "ip"
+	// RateLimitIdentifierJWT limits the requests per JWT.
+	// Requests without a verified JWT are limited per client IP.
+	RateLimitIdentifierJWT = "jwt"
+)
+
+// RateLimit limits the requests to the routes matching a route pattern.
+type RateLimit struct {
+	// The route pattern, e.g. "/api/v2/messages*".
+	Route string `json:"route" koanf:"route"`
+	// The period in which a client is allowed to send one request.
+	Period time.Duration `json:"period" koanf:"period"`
+	// The additional amount of requests a client is allowed to send at once.
+	Burst int `json:"burst" koanf:"burst"`
+	// Whether the requests are counted per client IP ("ip") or per JWT ("jwt").
+	Identifier string `json:"identifier" koanf:"identifier"`
+}
+
+// CompileRouteAsRegex compiles a route pattern, in which "*" matches any characters, to a regular expression.
+func CompileRouteAsRegex(route string) *regexp.Regexp {
+
+	r := regexp.QuoteMeta(route)
+	r = strings.Replace(r, `\*`, "(.*?)", -1)
+	r = r + "$"
+
+	reg, err := regexp.Compile(r)
+	if err != nil {
+		return nil
+	}
+	return reg
+}
+
+// rateLimitIdentifierExtractor returns the function that extracts the identifier of the client of a request.
+func rateLimitIdentifierExtractor(identifier string) (middleware.Extractor, error) {
+	switch identifier {
+	case RateLimitIdentifierIP, "":
+		return func(c echo.Context) (string, error) {
+			return c.RealIP(), nil
+		}, nil
+
+	case RateLimitIdentifierJWT:
+		return func(c echo.Context) (string, error) {
+			// the token is only set on the context if it was verified by the JWT middleware,
+			// so clients can't bypass the limit by sending made up tokens.
+			// All API tokens share the same subject, so the tokens are distinguished by their signature.
+			if token, ok := c.Get("jwt").(*jwt.Token); ok && token.Valid {
+				return "jwt:" + token.Signature, nil
+			}
+			return c.RealIP(), nil
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown rate limit identifier: %s", identifier)
+	}
+}
+
+// RateLimiter returns a middleware that allows one request per client in the given period, plus burst additional requests at once.
+// Routes for which the skipper returns true are not limited.
+func RateLimiter(skipper middleware.Skipper, period time.Duration, burst int, identifier string) (echo.MiddlewareFunc, error) {
+
+	if period <= 0 || burst < 0 {
+		return nil, fmt.Errorf("invalid rate limit: period %v, burst %d", period, burst)
+	}
+
+	identifierExtractor, err := rateLimitIdentifierExtractor(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if skipper == nil {
+		skipper = middleware.DefaultSkipper
+	}
+
+	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Skipper: skipper,
+		Store: middleware.NewRateLimiterMemoryStoreWithConfig(
+			middleware.RateLimiterMemoryStoreConfig{
+				Rate:      rate.Every(period),
+				Burst:     burst,
+				ExpiresIn: period,
+			},
+		),
+		IdentifierExtractor: identifierExtractor,
+		ErrorHandler: func(c echo.Context, err error) error {
+			return errors.WithMessagef(echo.ErrForbidden, "unable to identify the client: %s", err)
+		},
+		DenyHandler: func(c echo.Context, identifier string, err error) error {
+			return errors.WithMessage(echo.ErrTooManyRequests, "rate limit exceeded")
+		},
+	}), nil
+}
+
+// RouteRateLimiter returns a middleware that applies the first of the rate limits whose route pattern matches the route of a request.
+// Routes that don't match any of the patterns are not limited.
+func RouteRateLimiter(rateLimits []*RateLimit) (echo.MiddlewareFunc, error) {
+
+	type routeRateLimiter struct {
+		route   *regexp.Regexp
+		limiter echo.MiddlewareFunc
+	}
+
+	limiters := make([]*routeRateLimiter, 0, len(rateLimits))
+	for _, rateLimit := range rateLimits {
+		route := CompileRouteAsRegex(strings.ToLower(rateLimit.Route))
+		if route == nil {
+			return nil, fmt.Errorf("invalid rate limit route: %s", rateLimit.Route)
+		}
+
+		limiter, err := RateLimiter(nil, rateLimit.Period, rateLimit.Burst, rateLimit.Identifier)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit for %s: %w", rateLimit.Route, err)
+		}
+
+		limiters = append(limiters, &routeRateLimiter{route: route, limiter: limiter})
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+
+		handlers := make([]echo.HandlerFunc, len(limiters))
+		for i, l := range limiters {
+			handlers[i] = l.limiter(next)
+		}
+
+		return func(c echo.Context) error {
+			path := strings.ToLower(c.Path())
+			for i, l := range limiters {
+				if l.route.MatchString(path) {
+					return handlers[i](c)
+				}
+			}
+			return next(c)
+		}
+	}, nil
+}
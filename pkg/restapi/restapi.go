@@ -57,8 +57,12 @@ var (
 	ErrServiceNotImplemented = echo.NewHTTPError(http.StatusNotImplemented, "service not implemented")
 )
 
-// JSONResponse wraps the result into a "data" field and sends the JSON response with status code.
+// JSONResponse sends the JSON response with status code.
+// The response is encoded as CBOR instead if the client accepts "application/cbor".
 func JSONResponse(c echo.Context, statusCode int, result interface{}) error {
+	if IsCBORRequested(c) {
+		return CBORResponse(c, statusCode, result)
+	}
 	return c.JSON(statusCode, result)
 }
 
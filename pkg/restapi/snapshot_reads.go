@@ -0,0 +1,74 @@
+package restapi
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// bufferedResponseWriter collects the response of a handler,
+// so it can be written to the client after the handler returned.
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// Flush is a no-op, the response is written to the client as a whole after the handler returned.
+func (w *bufferedResponseWriter) Flush() {}
+
+// writeTo writes the collected response to the given writer.
+func (w *bufferedResponseWriter) writeTo(writer http.ResponseWriter) error {
+	if w.statusCode == 0 {
+		// nothing was written by the handler, e.g. because it returned an error
+		return nil
+	}
+
+	writer.WriteHeader(w.statusCode)
+	_, err := writer.Write(w.body.Bytes())
+	return err
+}
+
+// SnapshotReadsMiddleware returns a middleware that holds the read lock while GET requests are handled,
+// so the response reflects the state either before or after a write, but never a mix of both.
+// The response is collected in memory and only written to the client after the lock was released,
+// so slow clients can't block the writers. Streamed responses are written while reading, so they are skipped.
+func SnapshotReadsMiddleware(readLock sync.Locker, skipper middleware.Skipper) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Method != http.MethodGet || IsStreamRequested(c) || (skipper != nil && skipper(c)) {
+				return next(c)
+			}
+
+			response := c.Response()
+			writer := response.Writer
+			bufferedWriter := &bufferedResponseWriter{ResponseWriter: writer}
+
+			err := func() error {
+				response.Writer = bufferedWriter
+				defer func() { response.Writer = writer }()
+
+				readLock.Lock()
+				defer readLock.Unlock()
+
+				return next(c)
+			}()
+
+			if writeErr := bufferedWriter.writeTo(writer); writeErr != nil {
+				return writeErr
+			}
+			return err
+		}
+	}
+}
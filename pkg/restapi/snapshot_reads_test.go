@@ -0,0 +1,162 @@
+package restapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+// blockingResponseWriter blocks all writes until it is released.
+type blockingResponseWriter struct {
+	*httptest.ResponseRecorder
+	writing chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func newBlockingResponseWriter() *blockingResponseWriter {
+	return &blockingResponseWriter{
+		ResponseRecorder: httptest.NewRecorder(),
+		writing:          make(chan struct{}),
+		release:          make(chan struct{}),
+	}
+}
+
+func (w *blockingResponseWriter) Write(b []byte) (int, error) {
+	w.once.Do(func() { close(w.writing) })
+	<-w.release
+	return w.ResponseRecorder.Write(b)
+}
+
+// newSnapshotReadsTestServer creates a server with a GET route protected by the lock
+// which returns the children of a message, either as JSON or streamed as newline delimited JSON.
+func newSnapshotReadsTestServer(lock *sync.RWMutex, handlerStarted chan struct{}, handlerRelease chan struct{}) *echo.Echo {
+	e := echo.New()
+	e.Use(SnapshotReadsMiddleware(lock.RLocker(), nil))
+
+	e.GET("/children", func(c echo.Context) error {
+		if handlerStarted != nil {
+			close(handlerStarted)
+			<-handlerRelease
+		}
+
+		if IsStreamRequested(c) {
+			stream := NewNDJSONStream(c, http.StatusOK)
+			for i := 0; i < 10; i++ {
+				if err := stream.Write(map[string]int{"child": i}); err != nil {
+					return err
+				}
+			}
+			return stream.Close()
+		}
+
+		return JSONResponse(c, http.StatusOK, map[string][]int{"children": {1, 2, 3}})
+	})
+
+	return e
+}
+
+// requireLockAcquired checks that the lock can be acquired by a writer within a second.
+func requireLockAcquired(t *testing.T, lock *sync.RWMutex) {
+	locked := make(chan struct{})
+	go func() {
+		lock.Lock()
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		lock.Unlock()
+	case <-time.After(time.Second):
+		require.FailNow(t, "the lock is held while the response is written")
+	}
+}
+
+func TestSnapshotReadsLockHeldWhileReading(t *testing.T) {
+	lock := &sync.RWMutex{}
+	handlerStarted := make(chan struct{})
+	handlerRelease := make(chan struct{})
+	e := newSnapshotReadsTestServer(lock, handlerStarted, handlerRelease)
+
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/children", nil))
+		close(done)
+	}()
+	<-handlerStarted
+
+	// a confirmation waits until the state was read
+	confirmed := atomic.NewBool(false)
+	go func() {
+		lock.Lock()
+		confirmed.Store(true)
+		lock.Unlock()
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	require.False(t, confirmed.Load())
+
+	close(handlerRelease)
+	<-done
+	require.Eventually(t, confirmed.Load, time.Second, 10*time.Millisecond)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"children":[1,2,3]}`, rec.Body.String())
+}
+
+func TestSnapshotReadsSlowClient(t *testing.T) {
+	lock := &sync.RWMutex{}
+	e := newSnapshotReadsTestServer(lock, nil, nil)
+
+	writer := newBlockingResponseWriter()
+	done := make(chan struct{})
+	go func() {
+		e.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, "/children", nil))
+		close(done)
+	}()
+	<-writer.writing
+
+	// the client doesn't read the response, but the confirmation is not blocked
+	requireLockAcquired(t, lock)
+
+	close(writer.release)
+	<-done
+
+	require.Equal(t, http.StatusOK, writer.Code)
+	require.Equal(t, echo.MIMEApplicationJSONCharsetUTF8, writer.Header().Get(echo.HeaderContentType))
+	require.JSONEq(t, `{"children":[1,2,3]}`, writer.Body.String())
+}
+
+func TestSnapshotReadsStreamDuringConfirmation(t *testing.T) {
+	lock := &sync.RWMutex{}
+	e := newSnapshotReadsTestServer(lock, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/children", nil)
+	req.Header.Set(echo.HeaderAccept, MIMEApplicationNDJSON)
+
+	writer := newBlockingResponseWriter()
+	done := make(chan struct{})
+	go func() {
+		e.ServeHTTP(writer, req)
+		close(done)
+	}()
+	<-writer.writing
+
+	// the stream is written while a confirmation runs
+	lock.Lock()
+	close(writer.release)
+	<-done
+	lock.Unlock()
+
+	require.Equal(t, http.StatusOK, writer.Code)
+	require.Equal(t, MIMEApplicationNDJSON, writer.Header().Get(echo.HeaderContentType))
+	require.Equal(t, 10, strings.Count(writer.Body.String(), "\n"))
+}
@@ -0,0 +1,80 @@
+package restapi
+
+import (
+	"encoding/json"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// MIMEApplicationNDJSON is the content type of newline delimited JSON.
+	MIMEApplicationNDJSON = "application/x-ndjson"
+
+	// streamFlushInterval is the amount of written items after which a stream is flushed to the client.
+	streamFlushInterval = 100
+)
+
+// IsStreamRequested returns whether the client prefers the response as newline delimited JSON over JSON.
+func IsStreamRequested(c echo.Context) bool {
+	return NegotiateMediaType(c, echo.MIMEApplicationJSON, MIMEApplicationNDJSON) == MIMEApplicationNDJSON
+}
+
+// streamError is the last line of a stream that was aborted.
+type streamError struct {
+	Error string `json:"error"`
+}
+
+// NDJSONStream writes the items of a response as newline delimited JSON,
+// so that the response doesn't need to be held in memory as a whole.
+type NDJSONStream struct {
+	c       echo.Context
+	encoder *json.Encoder
+	written int
+}
+
+// NewNDJSONStream writes the header of the response and returns a stream for its items.
+func NewNDJSONStream(c echo.Context, statusCode int) *NDJSONStream {
+	c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+	c.Response().WriteHeader(statusCode)
+
+	return &NDJSONStream{
+		c:       c,
+		encoder: json.NewEncoder(c.Response()),
+	}
+}
+
+// Write writes the item as a single line.
+// An error is returned if the client is gone, the stream should be aborted in that case.
+func (s *NDJSONStream) Write(item interface{}) error {
+	if err := s.encoder.Encode(item); err != nil {
+		return err
+	}
+
+	s.written++
+	if s.written%streamFlushInterval == 0 {
+		s.c.Response().Flush()
+	}
+
+	return nil
+}
+
+// Written returns the amount of items written to the stream.
+func (s *NDJSONStream) Written() int {
+	return s.written
+}
+
+// Abort writes the error as the last line of the stream.
+// The status code was already sent, so the client needs to check the last line for an "error" field.
+func (s *NDJSONStream) Abort(err error) error {
+	if err := s.encoder.Encode(&streamError{Error: err.Error()}); err != nil {
+		return err
+	}
+	s.c.Response().Flush()
+	return nil
+}
+
+// Close flushes the remaining items to the client.
+func (s *NDJSONStream) Close() error {
+	s.c.Response().Flush()
+	return nil
+}
@@ -20,10 +20,13 @@ const (
 	PriorityBroadcastQueue    // depends on PriorityGossipService
 	PriorityP2PManager
 	PriorityAutopeering
-	PriorityHeartbeats // depends on PriorityGossipService
+	PriorityIdentityRotation // depends on PriorityP2PManager
+	PriorityHeartbeats       // depends on PriorityGossipService
 	PriorityWarpSync
 	PrioritySnapshots
+	PriorityArchiver // depends on PriorityFlushToDatabase
 	PriorityMetricsUpdater
+	PriorityDebug
 	PriorityDashboard
 	PriorityPoWHandler
 	PriorityRestAPI // depends on PriorityPoWHandler
@@ -31,8 +34,12 @@ const (
 	PrioritySpammer // depends on PriorityPoWHandler
 	PriorityFaucet  // depends on PriorityPoWHandler
 	PriorityIndexer
+	PriorityExpirationWatcher
 	PriorityParticipation
 	PriorityStatusReport
+	PrioritySLAMonitor
+	PriorityAlerting
+	PriorityStandby
 	PriorityMigrator
 	PriorityCoordinator // depends on PriorityPoWHandler
 	PriorityUpdateCheck
@@ -0,0 +1,18 @@
+package slamonitor
+
+import (
+	"github.com/iotaledger/hive.go/events"
+)
+
+// Events are the events issued by the SLA monitor.
+type Events struct {
+	// Fired when at least one of the SLA thresholds got breached.
+	SLABreached *events.Event
+	// Fired when all SLA thresholds are met again after a breach.
+	SLARecovered *events.Event
+}
+
+// StatusCaller is used to signal a Status.
+func StatusCaller(handler interface{}, params ...interface{}) {
+	handler.(func(*Status))(params[0].(*Status))
+}
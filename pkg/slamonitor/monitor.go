@@ -0,0 +1,192 @@
+package slamonitor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/iotaledger/hive.go/events"
+)
+
+// Status is the state of the confirmation SLA over the sliding window.
+type Status struct {
+	// The index of the latest milestone in the window.
+	MilestoneIndex milestone.Index `json:"milestoneIndex"`
+	// The amount of milestones in the window.
+	Milestones int `json:"milestones"`
+	// The average referenced rate of the milestones in the window in percent.
+	ReferencedRate float64 `json:"referencedRate"`
+	// The average time it took for messages in the window to get referenced by a milestone.
+	AvgTimeToReference time.Duration `json:"avgTimeToReference"`
+	// Whether the referenced rate is below the configured minimum.
+	ReferencedRateBreached bool `json:"referencedRateBreached"`
+	// Whether the average time to reference is above the configured maximum.
+	TimeToReferenceBreached bool `json:"timeToReferenceBreached"`
+}
+
+// Breached tells whether at least one of the SLA thresholds is breached.
+func (s *Status) Breached() bool {
+	return s.ReferencedRateBreached || s.TimeToReferenceBreached
+}
+
+// the metrics of a single confirmed milestone.
+type sample struct {
+	index          milestone.Index
+	referencedRate float64
+	// the sum of the time to reference of all messages referenced by the milestone.
+	timeToReferenceSum time.Duration
+	referencedMessages int
+}
+
+// Monitor computes the referenced rate and the average time to reference of messages
+// over a sliding window of confirmed milestones and fires events if the SLA thresholds get breached.
+type Monitor struct {
+	// Events are the events issued by the monitor.
+	Events *Events
+
+	// the amount of milestones in the sliding window.
+	windowSize int
+	// the minimum referenced rate in percent (0 disables the check).
+	minReferencedRate float64
+	// the maximum average time to reference (0 disables the check).
+	maxTimeToReference time.Duration
+
+	sync.RWMutex
+	// the samples of the milestones in the window, the latest milestone is the last one.
+	window []*sample
+	// the samples of milestones which are not confirmed yet.
+	pending map[milestone.Index]*sample
+	// the latest computed status.
+	status *Status
+}
+
+// New creates a new Monitor.
+// minReferencedRate is given in percent. A threshold of zero disables the respective check.
+func New(windowSize int, minReferencedRate float64, maxTimeToReference time.Duration) *Monitor {
+	if windowSize < 1 {
+		windowSize = 1
+	}
+
+	return &Monitor{
+		Events: &Events{
+			SLABreached:  events.NewEvent(StatusCaller),
+			SLARecovered: events.NewEvent(StatusCaller),
+		},
+		windowSize:         windowSize,
+		minReferencedRate:  minReferencedRate,
+		maxTimeToReference: maxTimeToReference,
+		pending:            make(map[milestone.Index]*sample),
+		status:             &Status{},
+	}
+}
+
+// returns the pending sample for the given milestone index.
+// write lock must be acquired outside.
+func (m *Monitor) pendingSample(index milestone.Index) *sample {
+	s, has := m.pending[index]
+	if !has {
+		s = &sample{index: index}
+		m.pending[index] = s
+	}
+	return s
+}
+
+// AddReferencedMessage adds a message which got referenced by the milestone with the given index.
+func (m *Monitor) AddReferencedMessage(index milestone.Index, timeToReference time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+
+	if timeToReference < 0 {
+		timeToReference = 0
+	}
+
+	s := m.pendingSample(index)
+	s.timeToReferenceSum += timeToReference
+	s.referencedMessages++
+}
+
+// AddMilestone adds the confirmed milestone with the given index and its referenced rate in percent
+// to the sliding window and evaluates the SLA thresholds.
+// SLABreached is fired if the SLA gets breached, SLARecovered if all thresholds are met again.
+// The thresholds are only evaluated once the window is filled.
+func (m *Monitor) AddMilestone(index milestone.Index, referencedRate float64) {
+	status, breached, recovered := m.addMilestone(index, referencedRate)
+
+	switch {
+	case breached:
+		m.Events.SLABreached.Trigger(status)
+	case recovered:
+		m.Events.SLARecovered.Trigger(status)
+	}
+}
+
+func (m *Monitor) addMilestone(index milestone.Index, referencedRate float64) (status *Status, breached bool, recovered bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	s := m.pendingSample(index)
+	s.referencedRate = referencedRate
+
+	// drop all pending samples up to the confirmed milestone
+	for pendingIndex := range m.pending {
+		if pendingIndex <= index {
+			delete(m.pending, pendingIndex)
+		}
+	}
+
+	m.window = append(m.window, s)
+	if len(m.window) > m.windowSize {
+		m.window = m.window[len(m.window)-m.windowSize:]
+	}
+
+	wasBreached := m.status.Breached()
+	m.status = m.computeStatus()
+
+	return m.status, !wasBreached && m.status.Breached(), wasBreached && !m.status.Breached()
+}
+
+// computes the status of the current window.
+// read lock must be acquired outside.
+func (m *Monitor) computeStatus() *Status {
+	status := &Status{
+		Milestones: len(m.window),
+	}
+
+	if len(m.window) == 0 {
+		return status
+	}
+	status.MilestoneIndex = m.window[len(m.window)-1].index
+
+	var referencedRateSum float64
+	var timeToReferenceSum time.Duration
+	var referencedMessages int
+	for _, s := range m.window {
+		referencedRateSum += s.referencedRate
+		timeToReferenceSum += s.timeToReferenceSum
+		referencedMessages += s.referencedMessages
+	}
+
+	status.ReferencedRate = referencedRateSum / float64(len(m.window))
+	if referencedMessages > 0 {
+		status.AvgTimeToReference = timeToReferenceSum / time.Duration(referencedMessages)
+	}
+
+	if len(m.window) < m.windowSize {
+		// not enough data yet to evaluate the SLA
+		return status
+	}
+
+	status.ReferencedRateBreached = m.minReferencedRate > 0 && status.ReferencedRate < m.minReferencedRate
+	status.TimeToReferenceBreached = m.maxTimeToReference > 0 && status.AvgTimeToReference > m.maxTimeToReference
+
+	return status
+}
+
+// Status returns the status of the latest evaluation of the sliding window.
+func (m *Monitor) Status() *Status {
+	m.RLock()
+	defer m.RUnlock()
+
+	status := *m.status
+	return &status
+}
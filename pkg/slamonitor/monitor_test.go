@@ -0,0 +1,72 @@
+package slamonitor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/slamonitor"
+	"github.com/iotaledger/hive.go/events"
+)
+
+func TestMonitor(t *testing.T) {
+
+	monitor := slamonitor.New(3, 50.0, 10*time.Second)
+
+	var breached, recovered []*slamonitor.Status
+	monitor.Events.SLABreached.Attach(events.NewClosure(func(status *slamonitor.Status) {
+		breached = append(breached, status)
+	}))
+	monitor.Events.SLARecovered.Attach(events.NewClosure(func(status *slamonitor.Status) {
+		recovered = append(recovered, status)
+	}))
+
+	addMilestone := func(index milestone.Index, referencedRate float64, timesToReference ...time.Duration) {
+		for _, timeToReference := range timesToReference {
+			monitor.AddReferencedMessage(index, timeToReference)
+		}
+		monitor.AddMilestone(index, referencedRate)
+	}
+
+	// the SLA is not evaluated until the window is filled
+	addMilestone(1, 10.0, time.Minute)
+	addMilestone(2, 10.0, time.Minute)
+	require.Empty(t, breached)
+	require.Equal(t, 2, monitor.Status().Milestones)
+	require.False(t, monitor.Status().Breached())
+
+	addMilestone(3, 100.0, time.Minute)
+	require.Len(t, breached, 1)
+	require.EqualValues(t, 3, breached[0].MilestoneIndex)
+	require.InDelta(t, 40.0, breached[0].ReferencedRate, 0.0001)
+	require.True(t, breached[0].ReferencedRateBreached)
+	require.True(t, breached[0].TimeToReferenceBreached)
+	require.Equal(t, time.Minute, breached[0].AvgTimeToReference)
+
+	// the breach is only signaled once
+	addMilestone(4, 10.0, time.Second)
+	require.Len(t, breached, 1)
+	require.Empty(t, recovered)
+
+	// the milestones with the long time to reference are slid out of the window
+	addMilestone(5, 100.0, time.Second, 3*time.Second)
+	addMilestone(6, 100.0, time.Second)
+	require.Len(t, recovered, 1)
+	status := monitor.Status()
+	require.False(t, status.Breached())
+	require.Equal(t, 3, status.Milestones)
+	require.InDelta(t, 70.0, status.ReferencedRate, 0.0001)
+	require.Equal(t, 1500*time.Millisecond, status.AvgTimeToReference)
+}
+
+func TestMonitorDisabledThresholds(t *testing.T) {
+
+	monitor := slamonitor.New(1, 0, 0)
+
+	monitor.AddReferencedMessage(1, time.Hour)
+	monitor.AddMilestone(1, 0)
+
+	require.False(t, monitor.Status().Breached())
+}
@@ -0,0 +1,180 @@
+package snapshot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// the maximum amount of years searched for the next activation of a cron expression.
+	cronMaxSearchYears = 5
+)
+
+// cronField is the set of allowed values of a single field of a cron expression.
+type cronField struct {
+	values map[int]struct{}
+	// whether the field starts with "*", which is relevant for matching the day.
+	wildcard bool
+}
+
+func (f *cronField) matches(value int) bool {
+	_, exists := f.values[value]
+	return exists
+}
+
+// cronSchedule is a parsed standard 5-field cron expression ("minute hour day-of-month month day-of-week").
+type cronSchedule struct {
+	minute     *cronField
+	hour       *cronField
+	dayOfMonth *cronField
+	month      *cronField
+	dayOfWeek  *cronField
+}
+
+// parseCron parses a standard 5-field cron expression.
+// Every field supports "*", single values, ranges ("1-5"), lists ("1,15") and steps ("*/15", "0-30/5").
+// The day-of-week field accepts 0-7, where 0 and 7 are Sunday.
+func parseCron(expression string) (*cronSchedule, error) {
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression \"%s\": expected 5 fields, got %d", expression, len(fields))
+	}
+
+	bounds := []struct {
+		name     string
+		min, max int
+	}{
+		{"minute", 0, 59},
+		{"hour", 0, 23},
+		{"day of month", 1, 31},
+		{"month", 1, 12},
+		{"day of week", 0, 7},
+	}
+
+	parsed := make([]*cronField, len(fields))
+	for i, field := range fields {
+		cronField, err := parseCronField(field, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression \"%s\": %s: %w", expression, bounds[i].name, err)
+		}
+		parsed[i] = cronField
+	}
+
+	// Sunday can be written as 0 or 7
+	if parsed[4].matches(7) {
+		parsed[4].values[0] = struct{}{}
+	}
+
+	schedule := &cronSchedule{
+		minute:     parsed[0],
+		hour:       parsed[1],
+		dayOfMonth: parsed[2],
+		month:      parsed[3],
+		dayOfWeek:  parsed[4],
+	}
+
+	if schedule.next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("invalid cron expression \"%s\": never activated", expression)
+	}
+
+	return schedule, nil
+}
+
+func parseCronField(field string, min int, max int) (*cronField, error) {
+
+	result := &cronField{
+		values:   make(map[int]struct{}),
+		wildcard: strings.HasPrefix(field, "*"),
+	}
+
+	for _, part := range strings.Split(field, ",") {
+		valueRange := part
+		hasStep := false
+
+		step := 1
+		if parts := strings.SplitN(part, "/", 2); len(parts) == 2 {
+			valueRange = parts[0]
+			hasStep = true
+
+			var err error
+			if step, err = strconv.Atoi(parts[1]); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step \"%s\"", parts[1])
+			}
+		}
+
+		start, end := min, max
+		switch {
+		case valueRange == "*":
+		case strings.Contains(valueRange, "-"):
+			bounds := strings.SplitN(valueRange, "-", 2)
+			var err error
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("invalid value \"%s\"", bounds[0])
+			}
+			if end, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("invalid value \"%s\"", bounds[1])
+			}
+		default:
+			value, err := strconv.Atoi(valueRange)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value \"%s\"", valueRange)
+			}
+			start = value
+			end = value
+			if hasStep {
+				// "5/15" means every 15 starting at 5
+				end = max
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return nil, fmt.Errorf("value \"%s\" out of range %d-%d", valueRange, min, max)
+		}
+
+		for value := start; value <= end; value += step {
+			result.values[value] = struct{}{}
+		}
+	}
+
+	return result, nil
+}
+
+// matchesDay returns whether the day of the given time is activated.
+// Like in the standard cron, the day matches either field if both day-of-month and day-of-week are restricted.
+func (c *cronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := c.dayOfMonth.matches(t.Day())
+	dayOfWeek := c.dayOfWeek.matches(int(t.Weekday()))
+
+	if !c.dayOfMonth.wildcard && !c.dayOfWeek.wildcard {
+		return dayOfMonth || dayOfWeek
+	}
+	return dayOfMonth && dayOfWeek
+}
+
+// next returns the first activation of the cron expression after the given time.
+// It returns the zero time if the expression is not activated within the next years (e.g. "0 0 30 2 *").
+func (c *cronSchedule) next(after time.Time) time.Time {
+
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	end := t.AddDate(cronMaxSearchYears, 0, 0)
+
+	for t.Before(end) {
+		switch {
+		case !c.month.matches(int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !c.hour.matches(t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !c.minute.matches(t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
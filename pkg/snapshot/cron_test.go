@@ -0,0 +1,50 @@
+package snapshot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+
+	for _, expression := range []string{"* * * * *", "0 3 * * *", "*/15 0-6 1,15 * 1-5", "5/10 * * 1-12/2 7"} {
+		_, err := parseCron(expression)
+		require.NoError(t, err, expression)
+	}
+
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *", "0 0 30 2 *"} {
+		_, err := parseCron(expression)
+		require.Error(t, err, expression)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+
+	// Wednesday
+	now := time.Date(2022, 3, 16, 10, 30, 20, 0, time.UTC)
+
+	tests := []struct {
+		expression string
+		next       time.Time
+	}{
+		{"* * * * *", time.Date(2022, 3, 16, 10, 31, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2022, 3, 17, 10, 30, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2022, 3, 17, 3, 0, 0, 0, time.UTC)},
+		{"*/20 * * * *", time.Date(2022, 3, 16, 10, 40, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2022, 3, 20, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2022, 3, 20, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * 1 *", time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// day of month or day of week if both are restricted
+		{"0 0 31 * 5", time.Date(2022, 3, 18, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		cron, err := parseCron(test.expression)
+		require.NoError(t, err, test.expression)
+		require.Equal(t, test.next, cron.next(now), test.expression)
+	}
+}
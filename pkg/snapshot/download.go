@@ -2,11 +2,14 @@ package snapshot
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -21,6 +24,11 @@ import (
 const (
 	timeoutDownloadSnapshotHeader = 5 * time.Second
 	timeoutDownloadSnapshotFile   = 10 * time.Minute
+
+	// the extension of the published checksum files next to the snapshot files.
+	checksumFileExtension = ".sha256"
+	// the extension of the file that stores the state of a partial download.
+	downloadStateFileExtension = ".state"
 )
 
 // WriteCounter counts the number of bytes written to it. It implements to the io.Writer interface
@@ -80,6 +88,24 @@ type DownloadTarget struct {
 	Full string `json:"full"`
 	// URL of the delta snapshot file.
 	Delta string `json:"delta"`
+	// URLs of mirrors of the same full snapshot file, used to resume the download if the previous URL fails.
+	FullMirrors []string `json:"fullMirrors"`
+	// URLs of mirrors of the same delta snapshot file, used to resume the download if the previous URL fails.
+	DeltaMirrors []string `json:"deltaMirrors"`
+	// URL of the published SHA-256 checksum of the full snapshot file (defaults to the file URL + ".sha256").
+	FullChecksum string `json:"fullChecksum"`
+	// URL of the published SHA-256 checksum of the delta snapshot file (defaults to the file URL + ".sha256").
+	DeltaChecksum string `json:"deltaChecksum"`
+}
+
+// fullURLs returns the URL and the mirrors of the full snapshot file.
+func (t *DownloadTarget) fullURLs() []string {
+	return append([]string{t.Full}, t.FullMirrors...)
+}
+
+// deltaURLs returns the URL and the mirrors of the delta snapshot file.
+func (t *DownloadTarget) deltaURLs() []string {
+	return append([]string{t.Delta}, t.DeltaMirrors...)
 }
 
 func (s *SnapshotManager) filterTargets(wantedNetworkID uint64, targets []*DownloadTarget) []*DownloadTarget {
@@ -123,9 +149,7 @@ func (s *SnapshotManager) filterTargets(wantedNetworkID uint64, targets []*Downl
 
 	// search the latest snapshot by scanning all target headers
 	for _, target := range targets {
-		s.LogDebugf("downloading full snapshot header from %s", target.Full)
-
-		fullHeader, err := s.downloadHeader(target.Full)
+		fullHeader, err := s.downloadHeaderFromMirrors(target.fullURLs())
 		if err != nil {
 			// as the full snapshot URL failed to download, we commence further with our targets
 			s.LogDebugf("downloading full snapshot header from %s failed: %s", target.Full, err)
@@ -134,8 +158,7 @@ func (s *SnapshotManager) filterTargets(wantedNetworkID uint64, targets []*Downl
 
 		var deltaHeader *ReadFileHeader
 		if len(target.Delta) > 0 {
-			s.LogDebugf("downloading delta snapshot header from %s", target.Delta)
-			deltaHeader, err = s.downloadHeader(target.Delta)
+			deltaHeader, err = s.downloadHeaderFromMirrors(target.deltaURLs())
 			if err != nil {
 				// it is valid that no delta snapshot file is available on the target.
 				s.LogDebugf("downloading delta snapshot header from %s failed: %s", target.Delta, err)
@@ -173,7 +196,10 @@ func (s *SnapshotManager) DownloadSnapshotFiles(ctx context.Context, wantedNetwo
 	for _, target := range s.filterTargets(wantedNetworkID, targets) {
 
 		s.LogInfof("downloading full snapshot file from %s", target.Full)
-		if err := s.downloadFile(ctx, fullPath, target.Full); err != nil {
+		if err := s.downloadVerifiedFile(ctx, wantedNetworkID, fullPath, target.fullURLs(), target.FullChecksum); err != nil {
+			if errors.Is(err, ErrSnapshotDownloadWasAborted) {
+				return err
+			}
 			s.LogWarn(err)
 			// as the full snapshot URL failed to download, we commence further with our targets
 			continue
@@ -181,7 +207,10 @@ func (s *SnapshotManager) DownloadSnapshotFiles(ctx context.Context, wantedNetwo
 
 		if len(target.Delta) > 0 {
 			s.LogInfof("downloading delta snapshot file from %s", target.Delta)
-			if err := s.downloadFile(ctx, deltaPath, target.Delta); err != nil {
+			if err := s.downloadVerifiedFile(ctx, wantedNetworkID, deltaPath, target.deltaURLs(), target.DeltaChecksum); err != nil {
+				if errors.Is(err, ErrSnapshotDownloadWasAborted) {
+					return err
+				}
 				// it is valid that no delta snapshot file is available on the target.
 				s.LogWarn(err)
 			}
@@ -192,6 +221,58 @@ func (s *SnapshotManager) DownloadSnapshotFiles(ctx context.Context, wantedNetwo
 	return ErrSnapshotDownloadNoValidSource
 }
 
+// downloadVerifiedFile downloads a snapshot file from the given URLs, verifies its published checksum
+// and checks that the file is a valid snapshot file of the wanted network before it is moved to the path.
+func (s *SnapshotManager) downloadVerifiedFile(ctx context.Context, wantedNetworkID uint64, path string, urls []string, checksumURL string) error {
+
+	checksum, err := s.downloadChecksum(urls, checksumURL)
+	if err != nil {
+		return err
+	}
+	if checksum == "" {
+		s.LogWarnf("no SHA-256 checksum published for %s, the snapshot file can't be verified", urls[0])
+	}
+
+	tempFileName := path + ".tmp"
+	if err := s.downloadFile(ctx, tempFileName, urls, checksum); err != nil {
+		return err
+	}
+
+	header, err := ReadSnapshotHeaderFromFile(tempFileName)
+	if err != nil {
+		_ = os.Remove(tempFileName)
+		return fmt.Errorf("downloaded file is not a valid snapshot file: %w", err)
+	}
+
+	if header.NetworkID != wantedNetworkID {
+		_ = os.Remove(tempFileName)
+		return fmt.Errorf("downloaded snapshot networkID does not match (%d != %d)", header.NetworkID, wantedNetworkID)
+	}
+
+	if err = os.Rename(tempFileName, path); err != nil {
+		return fmt.Errorf("unable to rename downloaded snapshot file: %w", err)
+	}
+
+	return nil
+}
+
+// downloadHeaderFromMirrors downloads a snapshot header from the first of the given URLs that is reachable.
+func (s *SnapshotManager) downloadHeaderFromMirrors(urls []string) (*ReadFileHeader, error) {
+	var lastErr error
+	for _, url := range urls {
+		s.LogDebugf("downloading snapshot header from %s", url)
+
+		header, err := s.downloadHeader(url)
+		if err == nil {
+			return header, nil
+		}
+		s.LogDebugf("downloading snapshot header from %s failed: %s", url, err)
+		lastErr = err
+	}
+
+	return nil, lastErr
+}
+
 // downloads a snapshot header from the given url.
 func (s *SnapshotManager) downloadHeader(url string) (*ReadFileHeader, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeoutDownloadSnapshotHeader)
@@ -212,58 +293,259 @@ func (s *SnapshotManager) downloadHeader(url string) (*ReadFileHeader, error) {
 		return nil, fmt.Errorf("download failed, server returned status code %d", resp.StatusCode)
 	}
 
-	return ReadSnapshotHeader(resp.Body)
+	reader, err := newSnapshotStreamReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadSnapshotHeader(reader)
 }
 
-// downloads a snapshot file from the given url to the specified path.
-func (s *SnapshotManager) downloadFile(ctx context.Context, path string, url string) error {
-	downloadCtx, downloadCtxCancel := context.WithTimeout(context.Background(), timeoutDownloadSnapshotFile)
-	defer downloadCtxCancel()
+// downloadChecksum downloads the published SHA-256 checksum of a snapshot file.
+// If no checksum URL is configured, the checksum is searched next to the file on all mirrors,
+// and an empty checksum is returned if none of them publishes one.
+func (s *SnapshotManager) downloadChecksum(urls []string, checksumURL string) (string, error) {
 
-	req, err := http.NewRequestWithContext(downloadCtx, http.MethodGet, url, nil)
+	if checksumURL != "" {
+		checksum, err := s.downloadChecksumFile(checksumURL)
+		if err != nil {
+			return "", fmt.Errorf("downloading checksum from %s failed: %w", checksumURL, err)
+		}
+		return checksum, nil
+	}
+
+	for _, url := range urls {
+		checksum, err := s.downloadChecksumFile(url + checksumFileExtension)
+		if err != nil {
+			s.LogDebugf("downloading checksum from %s failed: %s", url+checksumFileExtension, err)
+			continue
+		}
+		return checksum, nil
+	}
+
+	return "", nil
+}
+
+// downloadChecksumFile downloads a checksum file in the format of "sha256sum" and returns the hex encoded checksum.
+func (s *SnapshotManager) downloadChecksumFile(url string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutDownloadSnapshotHeader)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return fmt.Errorf("download failed: %w", err)
+		return "", fmt.Errorf("download failed: %w", err)
 	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("download failed: %w", err)
+		return "", fmt.Errorf("download failed: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("download failed, server returned status code %d", resp.StatusCode)
+		return "", fmt.Errorf("download failed, server returned status code %d", resp.StatusCode)
 	}
 
-	tempFileName := path + ".tmp"
-	out, err := os.Create(tempFileName)
+	// the checksum is followed by the file name
+	content, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", fmt.Errorf("download failed: %w", err)
+	}
+
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return "", errors.New("empty checksum file")
+	}
+
+	checksum := strings.ToLower(fields[0])
+	if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+		return "", fmt.Errorf("invalid SHA-256 checksum: %s", fields[0])
+	}
+
+	return checksum, nil
+}
+
+// downloadState is stored next to a partially downloaded file, so the download is only resumed with the same file.
+type downloadState struct {
+	// The total size of the file.
+	Size int64 `json:"size"`
+	// The published SHA-256 checksum of the file, if known.
+	Checksum string `json:"checksum"`
+}
+
+// downloadFile downloads a file from the given URLs to the specified path.
+// If a download fails, it is resumed from the next mirror via HTTP range requests.
+// A partially downloaded file is kept, so the download is also resumed after a restart of the node.
+// If a checksum is given, the downloaded file is verified and deleted on a mismatch.
+func (s *SnapshotManager) downloadFile(ctx context.Context, path string, urls []string, checksum string) error {
+
+	var lastErr error
+	for _, url := range urls {
+		if err := s.downloadFileWithResume(ctx, path, url, checksum); err != nil {
+			if errors.Is(err, ErrSnapshotDownloadWasAborted) {
+				return err
+			}
+			s.LogWarnf("downloading %s failed: %s", url, err)
+			lastErr = err
+			continue
+		}
+
+		if checksum == "" {
+			_ = os.Remove(path + downloadStateFileExtension)
+			return nil
+		}
+
+		if err := verifyFileChecksum(path, checksum); err != nil {
+			// the partial file might have been mixed up, start from scratch with the next mirror
+			s.LogWarnf("verifying %s failed: %s", url, err)
+			_ = os.Remove(path)
+			_ = os.Remove(path + downloadStateFileExtension)
+			lastErr = err
+			continue
+		}
+
+		_ = os.Remove(path + downloadStateFileExtension)
+		return nil
+	}
+
+	return fmt.Errorf("download failed: %w", lastErr)
+}
+
+// readDownloadState returns the size of the partially downloaded file at the path
+// if the download can be resumed with a file of the given checksum.
+func readDownloadState(path string, checksum string) (int64, *downloadState) {
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, nil
+	}
+
+	state := &downloadState{}
+	if err := utils.ReadJSONFromFile(path+downloadStateFileExtension, state); err != nil {
+		return 0, nil
+	}
+
+	if state.Checksum != checksum || info.Size() > state.Size {
+		return 0, nil
+	}
+
+	return info.Size(), state
+}
+
+// parseContentRangeTotal returns the total size of a "bytes start-end/total" Content-Range header.
+func parseContentRangeTotal(contentRange string) (int64, error) {
+	parts := strings.Split(contentRange, "/")
+	if len(parts) != 2 || parts[1] == "*" {
+		return 0, fmt.Errorf("invalid Content-Range: %s", contentRange)
+	}
+	return strconv.ParseInt(parts[1], 10, 64)
+}
+
+// downloadFileWithResume downloads a file from the url to the path
+// and resumes a partially downloaded file if the server supports range requests.
+func (s *SnapshotManager) downloadFileWithResume(ctx context.Context, path string, url string, checksum string) error {
+	downloadCtx, downloadCtxCancel := context.WithTimeout(context.Background(), timeoutDownloadSnapshotFile)
+	defer downloadCtxCancel()
+
+	offset, state := readDownloadState(path, checksum)
+	if state != nil && offset == state.Size {
+		// the file was already downloaded completely
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(downloadCtx, http.MethodGet, url, nil)
 	if err != nil {
 		return err
 	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
 
-	var ok bool
-	defer func() {
-		if !ok {
-			// we don't need to check the error, maybe the file doesn't exist
-			_ = os.Remove(tempFileName)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var size int64
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// the server doesn't support range requests or no partial file exists
+		offset = 0
+		size = resp.ContentLength
+
+	case http.StatusPartialContent:
+		if size, err = parseContentRangeTotal(resp.Header.Get("Content-Range")); err != nil {
+			return err
 		}
-	}()
+		if size != state.Size {
+			// the file on the mirror differs from the partially downloaded one, start from scratch with the next mirror
+			_ = os.Remove(path)
+			_ = os.Remove(path + downloadStateFileExtension)
+			return fmt.Errorf("file size does not match the partial download (%d != %d)", size, state.Size)
+		}
+		s.LogInfof("resuming download at %s", humanize.Bytes(uint64(offset)))
 
-	// create our progress reporter and pass it to be used alongside our writer
-	counter := NewWriteCounter(ctx, uint64(resp.ContentLength))
-	if _, err = io.Copy(out, io.TeeReader(resp.Body, counter)); err != nil {
-		_ = out.Close()
-		return fmt.Errorf("download failed: %w", err)
+	default:
+		return fmt.Errorf("server returned status code %d", resp.StatusCode)
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if offset == 0 {
+		flags |= os.O_TRUNC
+		if size > 0 {
+			// without the size the download can't be resumed
+			if err := utils.WriteJSONToFile(path+downloadStateFileExtension, &downloadState{Size: size, Checksum: checksum}, 0660); err != nil {
+				return err
+			}
+		}
 	}
 
+	out, err := os.OpenFile(path, flags, 0660)
+	if err != nil {
+		return err
+	}
+
+	// create our progress reporter and pass it to be used alongside our writer
+	counter := NewWriteCounter(ctx, uint64(size))
+	counter.total = uint64(offset)
+	counter.last = uint64(offset)
+
+	written, err := io.Copy(out, io.TeeReader(resp.Body, counter))
+
 	// the progress indicator uses the same line so print a new line once it's finished downloading
 	fmt.Print("\n")
 
-	_ = out.Close()
-	if err = os.Rename(tempFileName, path); err != nil {
-		return fmt.Errorf("unable to rename downloaded snapshot file: %w", err)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if size > 0 && offset+written != size {
+		return fmt.Errorf("download incomplete (%d/%d bytes)", offset+written, size)
+	}
+
+	return nil
+}
+
+// verifyFileChecksum checks the SHA-256 checksum of the file at the path.
+func verifyFileChecksum(path string, checksum string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return err
+	}
+
+	if fileChecksum := hex.EncodeToString(hash.Sum(nil)); fileChecksum != checksum {
+		return fmt.Errorf("SHA-256 checksum does not match (%s != %s)", fileChecksum, checksum)
 	}
 
-	ok = true
 	return nil
 }
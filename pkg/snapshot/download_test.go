@@ -0,0 +1,131 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	hornetutils "github.com/gohornet/hornet/pkg/utils"
+)
+
+const (
+	testDownloadFileSize = 1024 * 1024
+)
+
+func newTestDownloadSnapshotManager() *SnapshotManager {
+	return &SnapshotManager{WrappedLogger: hornetutils.NewWrappedLogger(nil)}
+}
+
+// newTestDownloadServer serves the data and its checksum.
+// If flaky is set, the connection is aborted after half of the data was sent.
+// The Range headers of the requests are sent to the returned channel.
+func newTestDownloadServer(t *testing.T, data []byte, checksum string, flaky bool) (*httptest.Server, chan string) {
+	ranges := make(chan string, 10)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, checksumFileExtension):
+			_, _ = fmt.Fprintf(w, "%s  snapshot.bin\n", checksum)
+
+		case flaky:
+			ranges <- r.Header.Get("Range")
+			w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+			_, _ = w.Write(data[:len(data)/2])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+
+		default:
+			ranges <- r.Header.Get("Range")
+			http.ServeContent(w, r, "snapshot.bin", time.Time{}, bytes.NewReader(data))
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts, ranges
+}
+
+func TestDownloadFileResumeFromMirror(t *testing.T) {
+
+	data := utils.RandBytes(testDownloadFileSize)
+	hash := sha256.Sum256(data)
+	checksum := hex.EncodeToString(hash[:])
+
+	flakyServer, flakyRanges := newTestDownloadServer(t, data, checksum, true)
+	mirrorServer, mirrorRanges := newTestDownloadServer(t, data, checksum, false)
+
+	urls := []string{flakyServer.URL + "/snapshot.bin", mirrorServer.URL + "/snapshot.bin"}
+	path := filepath.Join(t.TempDir(), "snapshot.bin")
+
+	s := newTestDownloadSnapshotManager()
+
+	resolvedChecksum, err := s.downloadChecksum(urls, "")
+	require.NoError(t, err)
+	require.Equal(t, checksum, resolvedChecksum)
+
+	require.NoError(t, s.downloadFile(context.Background(), path, urls, resolvedChecksum))
+
+	downloaded, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, data, downloaded)
+
+	// the flaky server was tried first without range, the mirror resumed the download
+	require.Equal(t, "", <-flakyRanges)
+	mirrorRange := <-mirrorRanges
+	require.True(t, strings.HasPrefix(mirrorRange, "bytes="), mirrorRange)
+	require.NotEqual(t, "bytes=0-", mirrorRange)
+
+	// the state of the partial download was removed
+	_, err = os.Stat(path + downloadStateFileExtension)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestDownloadFileChecksumMismatch(t *testing.T) {
+
+	data := utils.RandBytes(testDownloadFileSize)
+	wrongChecksum := hex.EncodeToString(make([]byte, sha256.Size))
+
+	server, _ := newTestDownloadServer(t, data, wrongChecksum, false)
+	urls := []string{server.URL + "/snapshot.bin"}
+	path := filepath.Join(t.TempDir(), "snapshot.bin")
+
+	s := newTestDownloadSnapshotManager()
+
+	resolvedChecksum, err := s.downloadChecksum(urls, "")
+	require.NoError(t, err)
+	require.Equal(t, wrongChecksum, resolvedChecksum)
+
+	require.Error(t, s.downloadFile(context.Background(), path, urls, resolvedChecksum))
+
+	// the corrupt file is not kept for a resume
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestDownloadChecksumInvalid(t *testing.T) {
+
+	server, _ := newTestDownloadServer(t, nil, "no-checksum", false)
+
+	s := newTestDownloadSnapshotManager()
+
+	// a configured checksum URL is mandatory
+	_, err := s.downloadChecksum([]string{server.URL + "/snapshot.bin"}, server.URL+"/snapshot.bin"+checksumFileExtension)
+	require.Error(t, err)
+
+	// a checksum next to the file is optional
+	checksum, err := s.downloadChecksum([]string{server.URL + "/snapshot.bin"}, "")
+	require.NoError(t, err)
+	require.Empty(t, checksum)
+}
@@ -0,0 +1,336 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/gohornet/hornet/pkg/utils"
+)
+
+const (
+	// EnvSnapshotEncryptionKey is the environment variable that contains the passphrase
+	// encrypted snapshot files are written and read with.
+	EnvSnapshotEncryptionKey = "SNAPSHOT_ENCRYPTION_KEY"
+
+	// the version of the encrypted snapshot file format.
+	encryptionFormatVersion byte = 1
+	// the size of the salt the key is derived with.
+	encryptionSaltSize = 16
+	// the amount of plaintext that is sealed in a single chunk.
+	encryptionChunkSize = 64 * 1024
+)
+
+var (
+	// ErrSnapshotEncryptionKeyMissing is returned if an encrypted snapshot file should be read or written without a key.
+	ErrSnapshotEncryptionKeyMissing = errors.New("snapshot encryption key missing")
+	// ErrSnapshotDecryptionFailed is returned if an encrypted snapshot file can't be decrypted,
+	// either because the key is wrong or the file was modified or truncated.
+	ErrSnapshotDecryptionFailed = errors.New("snapshot decryption failed")
+
+	// the magic bytes at the beginning of an encrypted snapshot file.
+	encryptionMagic = []byte("HSNPENC")
+)
+
+// LoadEncryptionKeyFromEnvironment loads the passphrase for encrypted snapshot files from the environment.
+func LoadEncryptionKeyFromEnvironment() (string, error) {
+	key, err := utils.LoadStringFromEnvironment(EnvSnapshotEncryptionKey)
+	if err != nil {
+		return "", errors.Wrap(ErrSnapshotEncryptionKeyMissing, err.Error())
+	}
+	return key, nil
+}
+
+// newChunkCipher derives the key of an encrypted snapshot file from the passphrase and the salt of the file.
+// every file uses a random salt, so the nonces of the chunks only need to be unique within a single file.
+func newChunkCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce returns the nonce of the chunk with the given counter.
+// the last byte marks the last chunk, so a file that was truncated at a chunk boundary can't be decrypted.
+func chunkNonce(aead cipher.AEAD, counter uint64, last bool) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-9:], counter)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+// encryptingWriter encrypts the written data in chunks of AES-GCM.
+// Close has to be called to write the last chunk.
+type encryptingWriter struct {
+	writer  io.Writer
+	aead    cipher.AEAD
+	counter uint64
+	chunk   []byte
+}
+
+// newEncryptingWriter writes the header of an encrypted snapshot file to the writer
+// and returns a writer that encrypts the snapshot data with the passphrase.
+func newEncryptingWriter(writer io.Writer, passphrase string) (*encryptingWriter, error) {
+	if passphrase == "" {
+		return nil, ErrSnapshotEncryptionKeyMissing
+	}
+
+	salt := make([]byte, encryptionSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := newChunkCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	header := append(append(append([]byte{}, encryptionMagic...), encryptionFormatVersion), salt...)
+	if _, err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &encryptingWriter{
+		writer: writer,
+		aead:   aead,
+		chunk:  make([]byte, 0, encryptionChunkSize),
+	}, nil
+}
+
+// sealChunk encrypts and writes the buffered chunk.
+func (w *encryptingWriter) sealChunk(last bool) error {
+	sealed := w.aead.Seal(nil, chunkNonce(w.aead, w.counter, last), w.chunk, nil)
+	if _, err := w.writer.Write(sealed); err != nil {
+		return err
+	}
+	w.counter++
+	w.chunk = w.chunk[:0]
+	return nil
+}
+
+func (w *encryptingWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		// a full chunk is only sealed if more data follows, because the last chunk is sealed differently
+		if len(w.chunk) == encryptionChunkSize {
+			if err := w.sealChunk(false); err != nil {
+				return written, err
+			}
+		}
+
+		n := copy(w.chunk[len(w.chunk):encryptionChunkSize], p)
+		w.chunk = w.chunk[:len(w.chunk)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// Close seals the last chunk. It doesn't close the underlying writer.
+func (w *encryptingWriter) Close() error {
+	return w.sealChunk(true)
+}
+
+// decryptingReader decrypts an encrypted snapshot file.
+// It keeps the previous and the current chunk, so the snapshot data can be read
+// with io.SeekCurrent seeks back of up to one chunk, like the outputs of the ledger are read.
+type decryptingReader struct {
+	reader  *bufio.Reader
+	aead    cipher.AEAD
+	counter uint64
+	// the decrypted previous and current chunk.
+	plain []byte
+	// the length of the current chunk at the end of plain.
+	currentChunkLength int
+	// the position of the first byte of plain in the decrypted stream.
+	plainOffset int64
+	// the read position in plain.
+	position int
+	// whether the last chunk was read.
+	finished bool
+}
+
+// newDecryptingReader reads the header of an encrypted snapshot file from the reader,
+// the magic bytes were already consumed.
+func newDecryptingReader(reader *bufio.Reader) (*decryptingReader, error) {
+	header := make([]byte, 1+encryptionSaltSize)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, errors.Wrapf(ErrSnapshotDecryptionFailed, "unable to read header: %s", err)
+	}
+
+	if header[0] != encryptionFormatVersion {
+		return nil, errors.Wrapf(ErrUnsupportedSnapshot, "encrypted snapshot file version is %d but this HORNET version only supports %d", header[0], encryptionFormatVersion)
+	}
+
+	passphrase, err := LoadEncryptionKeyFromEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newChunkCipher(passphrase, header[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptingReader{reader: reader, aead: aead}, nil
+}
+
+// readChunk decrypts the next chunk and drops the previous one.
+func (r *decryptingReader) readChunk() error {
+	sealed := make([]byte, encryptionChunkSize+r.aead.Overhead())
+	n, err := io.ReadFull(r.reader, sealed)
+	switch {
+	case err == io.EOF:
+		return errors.Wrap(ErrSnapshotDecryptionFailed, "file is truncated")
+	case err == io.ErrUnexpectedEOF:
+		// a chunk shorter than the chunk size is the last one
+	case err != nil:
+		return err
+	}
+
+	last := n < len(sealed)
+	if !last {
+		// a full chunk is the last one if no data follows
+		if _, err := r.reader.Peek(1); err == io.EOF {
+			last = true
+		}
+	}
+
+	chunk, err := r.aead.Open(nil, chunkNonce(r.aead, r.counter, last), sealed[:n], nil)
+	if err != nil {
+		return errors.Wrapf(ErrSnapshotDecryptionFailed, "chunk %d: wrong key or modified file", r.counter)
+	}
+	r.counter++
+	r.finished = last
+
+	if previous := r.plain; len(previous) > 0 {
+		// keep only the current chunk as the previous one
+		currentStart := len(previous) - r.currentChunkLength
+		r.plainOffset += int64(currentStart)
+		r.position -= currentStart
+		r.plain = append(previous[currentStart:len(previous):len(previous)], chunk...)
+	} else {
+		r.plain = chunk
+	}
+	r.currentChunkLength = len(chunk)
+
+	return nil
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	read := 0
+	// fill the buffer like a file does, the outputs are deserialized from the data of a single read
+	for read < len(p) {
+		if r.position == len(r.plain) {
+			if r.finished {
+				break
+			}
+			if err := r.readChunk(); err != nil {
+				return read, err
+			}
+			continue
+		}
+
+		n := copy(p[read:], r.plain[r.position:])
+		r.position += n
+		read += n
+	}
+
+	if read == 0 && len(p) > 0 {
+		return 0, io.EOF
+	}
+	return read, nil
+}
+
+// Seek only supports seeking within the decrypted previous and current chunk.
+func (r *decryptingReader) Seek(offset int64, whence int) (int64, error) {
+	var target int64
+	switch whence {
+	case io.SeekStart:
+		target = offset
+	case io.SeekCurrent:
+		target = r.plainOffset + int64(r.position) + offset
+	default:
+		return 0, fmt.Errorf("seeking in encrypted snapshot files relative to %d is not supported", whence)
+	}
+
+	if target < r.plainOffset || target > r.plainOffset+int64(len(r.plain)) {
+		return 0, fmt.Errorf("seeking in encrypted snapshot files is only supported within %d bytes", len(r.plain))
+	}
+	r.position = int(target - r.plainOffset)
+
+	return target, nil
+}
+
+// newSnapshotStreamReader returns a reader for the snapshot data of the given stream,
+// which decrypts the data if it is encrypted.
+func newSnapshotStreamReader(reader io.Reader) (io.Reader, error) {
+	bufReader := bufio.NewReader(reader)
+
+	magic, err := bufReader.Peek(len(encryptionMagic))
+	if err != nil || !bytes.Equal(magic, encryptionMagic) {
+		// not encrypted, the error is returned by reading the snapshot data
+		return bufReader, nil
+	}
+
+	if _, err := bufReader.Discard(len(encryptionMagic)); err != nil {
+		return nil, err
+	}
+
+	return newDecryptingReader(bufReader)
+}
+
+// snapshotFile is an opened snapshot file whose snapshot data is read from the reader.
+type snapshotFile struct {
+	io.ReadSeeker
+	file *os.File
+}
+
+func (f *snapshotFile) Close() error {
+	return f.file.Close()
+}
+
+// openSnapshotFile opens the snapshot file at the given path.
+// If the file is encrypted, the snapshot data is decrypted with the key from the environment.
+func openSnapshotFile(filePath string) (io.ReadSeekCloser, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	magic := make([]byte, len(encryptionMagic))
+	if _, err := io.ReadFull(file, magic); err != nil || !bytes.Equal(magic, encryptionMagic) {
+		// not encrypted, the error is returned by reading the snapshot data
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		return file, nil
+	}
+
+	reader, err := newDecryptingReader(bufio.NewReader(file))
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return &snapshotFile{ReadSeeker: reader, file: file}, nil
+}
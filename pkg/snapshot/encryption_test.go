@@ -0,0 +1,223 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	hornetUtils "github.com/gohornet/hornet/pkg/utils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const encryptionTestKey = "correct horse battery staple"
+
+func writeEncryptedFile(t *testing.T, data []byte) string {
+	filePath := filepath.Join(t.TempDir(), "encrypted.bin")
+
+	file, err := os.Create(filePath)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	writer, err := newEncryptingWriter(file, encryptionTestKey)
+	require.NoError(t, err)
+
+	// write in odd sizes to cross the chunk boundaries
+	for len(data) > 0 {
+		n := 1000
+		if n > len(data) {
+			n = len(data)
+		}
+		_, err := writer.Write(data[:n])
+		require.NoError(t, err)
+		data = data[n:]
+	}
+	require.NoError(t, writer.Close())
+
+	return filePath
+}
+
+func randBytes(t *testing.T, length int) []byte {
+	data := make([]byte, length)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	return data
+}
+
+func TestEncryptionRoundTrip(t *testing.T) {
+	t.Setenv(EnvSnapshotEncryptionKey, encryptionTestKey)
+
+	for _, length := range []int{0, 1, encryptionChunkSize - 1, encryptionChunkSize, encryptionChunkSize + 1, 3*encryptionChunkSize + 17} {
+		data := randBytes(t, length)
+		filePath := writeEncryptedFile(t, data)
+
+		reader, err := openSnapshotFile(filePath)
+		require.NoError(t, err)
+
+		decrypted, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+		require.True(t, bytes.Equal(data, decrypted), "length %d", length)
+	}
+}
+
+func TestEncryptionSeekBack(t *testing.T) {
+	t.Setenv(EnvSnapshotEncryptionKey, encryptionTestKey)
+
+	data := randBytes(t, 3*encryptionChunkSize)
+	reader, err := openSnapshotFile(writeEncryptedFile(t, data))
+	require.NoError(t, err)
+	defer func() { _ = reader.Close() }()
+
+	// read over a chunk boundary and give back the unconsumed bytes, like the outputs are read
+	buffer := make([]byte, iotago.MessageBinSerializedMaxSize)
+	var position int64
+	for position < int64(len(data)) {
+		n, err := reader.Read(buffer)
+		require.NoError(t, err)
+		require.True(t, bytes.Equal(data[position:position+int64(n)], buffer[:n]))
+
+		consumed := int64(n) / 3
+		if consumed == 0 {
+			consumed = int64(n)
+		}
+
+		newPosition, err := reader.Seek(consumed-int64(n), io.SeekCurrent)
+		require.NoError(t, err)
+		require.Equal(t, position+consumed, newPosition)
+		position = newPosition
+	}
+
+	// seeking further back than the buffered chunks is not supported
+	_, err = reader.Seek(-2*encryptionChunkSize-1, io.SeekCurrent)
+	require.Error(t, err)
+}
+
+func TestEncryptionInvalid(t *testing.T) {
+	data := randBytes(t, 2*encryptionChunkSize)
+	filePath := writeEncryptedFile(t, data)
+
+	readAll := func(filePath string) error {
+		reader, err := openSnapshotFile(filePath)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = reader.Close() }()
+
+		_, err = io.ReadAll(reader)
+		return err
+	}
+
+	t.Setenv(EnvSnapshotEncryptionKey, "")
+	require.ErrorIs(t, readAll(filePath), ErrSnapshotEncryptionKeyMissing)
+
+	t.Setenv(EnvSnapshotEncryptionKey, "wrong key")
+	require.ErrorIs(t, readAll(filePath), ErrSnapshotDecryptionFailed)
+
+	t.Setenv(EnvSnapshotEncryptionKey, encryptionTestKey)
+	require.NoError(t, readAll(filePath))
+
+	encrypted, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+
+	headerSize := len(encryptionMagic) + 1 + encryptionSaltSize
+	sealedChunkSize := encryptionChunkSize + 16
+
+	// the file is truncated at a chunk boundary
+	truncatedPath := filepath.Join(t.TempDir(), "truncated.bin")
+	require.NoError(t, os.WriteFile(truncatedPath, encrypted[:headerSize+sealedChunkSize], 0666))
+	require.ErrorIs(t, readAll(truncatedPath), ErrSnapshotDecryptionFailed)
+
+	// a byte of the data was modified
+	modified := append([]byte{}, encrypted...)
+	modified[headerSize+sealedChunkSize+10] ^= 0xFF
+	modifiedPath := filepath.Join(t.TempDir(), "modified.bin")
+	require.NoError(t, os.WriteFile(modifiedPath, modified, 0666))
+	require.ErrorIs(t, readAll(modifiedPath), ErrSnapshotDecryptionFailed)
+}
+
+func TestEncryptedSnapshotWriter(t *testing.T) {
+	t.Setenv(EnvSnapshotEncryptionKey, encryptionTestKey)
+
+	s := &SnapshotManager{
+		WrappedLogger: hornetUtils.NewWrappedLogger(nil),
+		encryptionKey: encryptionTestKey,
+	}
+
+	filePath := filepath.Join(t.TempDir(), "full_snapshot.bin")
+	writer, err := s.newSnapshotWriter(context.Background(), &snapshotDestination{filePath: filePath})
+	require.NoError(t, err)
+
+	header := &FileHeader{
+		Version:              SupportedFormatVersion,
+		Type:                 Full,
+		NetworkID:            1337,
+		SEPMilestoneIndex:    10,
+		LedgerMilestoneIndex: 10,
+		TreasuryOutput:       &utxo.TreasuryOutput{Amount: 1000},
+	}
+
+	// enough outputs to span several chunks
+	var outputs utxo.Outputs
+	for i := 0; i < 2000; i++ {
+		outputs = append(outputs, utxo.CreateOutput(utils.RandOutputID(), utils.RandMessageID(), utils.RandMilestoneIndex(), 0, utils.RandOutputOnAddressWithAmount(iotago.OutputExtended, utils.RandAddress(iotago.AddressEd25519), 1000)))
+	}
+
+	producedOutputs := outputs
+	_, err = StreamSnapshotDataTo(writer, 0, header,
+		func() (hornet.MessageID, error) { return nil, nil },
+		func() (*utxo.Output, error) {
+			if len(producedOutputs) == 0 {
+				return nil, nil
+			}
+			output := producedOutputs[0]
+			producedOutputs = producedOutputs[1:]
+			return output, nil
+		},
+		func() (*MilestoneDiff, error) { return nil, nil },
+	)
+	require.NoError(t, err)
+	require.NoError(t, writer.commit())
+
+	// only the encrypted snapshot file is left
+	files, err := os.ReadDir(filepath.Dir(filePath))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(content, encryptionMagic))
+
+	readHeader, err := ReadSnapshotHeaderFromFile(filePath)
+	require.NoError(t, err)
+	require.Equal(t, uint64(len(outputs)), readHeader.OutputCount)
+
+	reader, err := openSnapshotFile(filePath)
+	require.NoError(t, err)
+	defer func() { _ = reader.Close() }()
+
+	var readOutputs utxo.Outputs
+	require.NoError(t, StreamSnapshotDataFrom(reader, nil,
+		func(*ReadFileHeader) error { return nil },
+		func(hornet.MessageID) error { return nil },
+		func(output *utxo.Output) error {
+			readOutputs = append(readOutputs, output)
+			return nil
+		},
+		func(*utxo.TreasuryOutput) error { return nil },
+		func(*MilestoneDiff) error { return nil },
+	))
+
+	require.Len(t, readOutputs, len(outputs))
+	for i := range outputs {
+		require.Equal(t, outputs[i].SnapshotBytes(), readOutputs[i].SnapshotBytes())
+	}
+}
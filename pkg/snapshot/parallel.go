@@ -0,0 +1,83 @@
+package snapshot
+
+const (
+	// the amount of items per worker that are processed ahead of the consumer.
+	parallelQueueSizePerWorker = 64
+)
+
+// parallelProcessFunc processes an item in a worker.
+type parallelProcessFunc func(item interface{}) (interface{}, error)
+
+// parallelResult is the result of a processed item.
+type parallelResult struct {
+	value interface{}
+	err   error
+}
+
+// parallelTask is an item that is processed by a worker.
+type parallelTask struct {
+	item   interface{}
+	result chan *parallelResult
+}
+
+// newOrderedParallelProducer returns a producer which processes the items of the given producer with the given amount of workers,
+// but returns the processed items in the same order as they were produced. The producer has to return nil after the last item.
+// An error of the producer or of processing an item is returned in place of the item.
+func newOrderedParallelProducer(workerCount int, producer func() (interface{}, error), process parallelProcessFunc) func() (interface{}, error) {
+
+	if workerCount <= 1 {
+		return func() (interface{}, error) {
+			item, err := producer()
+			if item == nil || err != nil {
+				return nil, err
+			}
+			return process(item)
+		}
+	}
+
+	tasks := make(chan *parallelTask, workerCount)
+	// the results in the order of the items, this also limits the amount of items held in memory
+	pending := make(chan chan *parallelResult, workerCount*parallelQueueSizePerWorker)
+
+	for i := 0; i < workerCount; i++ {
+		go func() {
+			for task := range tasks {
+				value, err := process(task.item)
+				task.result <- &parallelResult{value: value, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(pending)
+		defer close(tasks)
+
+		for {
+			item, err := producer()
+			if err != nil {
+				result := make(chan *parallelResult, 1)
+				result <- &parallelResult{err: err}
+				pending <- result
+				return
+			}
+
+			if item == nil {
+				return
+			}
+
+			result := make(chan *parallelResult, 1)
+			pending <- result
+			tasks <- &parallelTask{item: item, result: result}
+		}
+	}()
+
+	return func() (interface{}, error) {
+		result, ok := <-pending
+		if !ok {
+			return nil, nil
+		}
+
+		processed := <-result
+		return processed.value, processed.err
+	}
+}
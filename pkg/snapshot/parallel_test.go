@@ -0,0 +1,56 @@
+package snapshot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedParallelProducer(t *testing.T) {
+
+	errProcess := errors.New("process failed")
+
+	for _, workerCount := range []int{0, 1, 8} {
+		next := 0
+		producer := newOrderedParallelProducer(workerCount, func() (interface{}, error) {
+			if next == 10000 {
+				return nil, nil
+			}
+			next++
+			return next, nil
+		}, func(item interface{}) (interface{}, error) {
+			return item.(int) * 2, nil
+		})
+
+		for i := 1; i <= 10000; i++ {
+			value, err := producer()
+			require.NoError(t, err)
+			require.Equal(t, i*2, value, "worker count %d", workerCount)
+		}
+
+		value, err := producer()
+		require.NoError(t, err)
+		require.Nil(t, value)
+
+		// the error of an item is returned in its place
+		next = 0
+		producer = newOrderedParallelProducer(workerCount, func() (interface{}, error) {
+			next++
+			return next, nil
+		}, func(item interface{}) (interface{}, error) {
+			if item.(int) == 100 {
+				return nil, errProcess
+			}
+			return item, nil
+		})
+
+		for i := 1; i < 100; i++ {
+			value, err := producer()
+			require.NoError(t, err)
+			require.Equal(t, i, value)
+		}
+		_, err = producer()
+		require.ErrorIs(t, err, errProcess)
+	}
+}
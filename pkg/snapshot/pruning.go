@@ -23,6 +23,12 @@ func (s *SnapshotManager) setIsPruning(value bool) {
 	s.statusLock.Unlock()
 }
 
+func (s *SnapshotManager) isPruningRunning() bool {
+	s.statusLock.RLock()
+	defer s.statusLock.RUnlock()
+	return s.isPruning
+}
+
 func (s *SnapshotManager) calcTargetIndexBySize(targetSizeBytes ...int64) (milestone.Index, error) {
 
 	if !s.pruningSizeEnabled && len(targetSizeBytes) == 0 {
@@ -73,7 +79,7 @@ func (s *SnapshotManager) calcTargetIndexBySize(targetSizeBytes ...int64) (miles
 }
 
 // pruneUnreferencedMessages prunes all unreferenced messages from the database for the given milestone
-func (s *SnapshotManager) pruneUnreferencedMessages(targetIndex milestone.Index) (msgCountDeleted int, msgCountChecked int) {
+func (s *SnapshotManager) pruneUnreferencedMessages(targetIndex milestone.Index) (msgCountDeleted int, msgCountChecked int, bytesDeleted int64) {
 
 	messageIDsToDeleteMap := make(map[string]struct{})
 
@@ -100,10 +106,10 @@ func (s *SnapshotManager) pruneUnreferencedMessages(targetIndex milestone.Index)
 		messageIDsToDeleteMap[messageIDMapKey] = struct{}{}
 	}
 
-	msgCountDeleted = s.pruneMessages(messageIDsToDeleteMap)
-	s.storage.DeleteUnreferencedMessages(targetIndex)
+	msgCountDeleted, bytesDeleted = s.pruneMessages(messageIDsToDeleteMap)
+	s.storage.DeleteUnreferencedMessagesBatched(targetIndex, s.pruningBatchSize)
 
-	return msgCountDeleted, len(messageIDsToDeleteMap)
+	return msgCountDeleted, len(messageIDsToDeleteMap), bytesDeleted
 }
 
 // pruneMilestone prunes the milestone metadata and the ledger diffs from the database for the given milestone
@@ -118,22 +124,34 @@ func (s *SnapshotManager) pruneMilestone(milestoneIndex milestone.Index, receipt
 	return nil
 }
 
-// pruneMessages removes all the associated data of the given message IDs from the database
-func (s *SnapshotManager) pruneMessages(messageIDsToDeleteMap map[string]struct{}) int {
+// pruneMessages removes all the associated data of the given message IDs from the database.
+// It returns the amount of deleted messages and the size of their data.
+func (s *SnapshotManager) pruneMessages(messageIDsToDeleteMap map[string]struct{}) (int, int64) {
+
+	messageIDsToDelete := make(hornet.MessageIDs, 0, len(messageIDsToDeleteMap))
+	var childrenToDelete []*storage.Child
+	var bytesDeleted int64
 
 	for messageIDToDelete := range messageIDsToDeleteMap {
 
 		msgID := hornet.MessageIDFromMapKey(messageIDToDelete)
 
+		if s.storage.IsMessagePinned(msgID) {
+			// pinned messages are exempt from pruning
+			continue
+		}
+
 		cachedMsg := s.storage.CachedMessageOrNil(msgID) // msg +1
 		if cachedMsg == nil {
 			continue
 		}
 
 		cachedMsg.ConsumeMessage(func(msg *storage.Message) { // msg -1
+			bytesDeleted += int64(len(msg.Data()))
+
 			// Delete the reference in the parents
 			for _, parent := range msg.Parents() {
-				s.storage.DeleteChild(parent, msgID)
+				childrenToDelete = append(childrenToDelete, storage.NewChild(parent, msgID))
 			}
 
 			// We don't need to iterate through the children that reference this message,
@@ -141,29 +159,24 @@ func (s *SnapshotManager) pruneMessages(messageIDsToDeleteMap map[string]struct{
 			// and the references will be deleted together with the children messages when they are pruned.
 		})
 
-		s.storage.DeleteMessage(msgID)
+		messageIDsToDelete = append(messageIDsToDelete, msgID)
 	}
 
-	return len(messageIDsToDeleteMap)
-}
+	// the entries are deleted in batches, which is a lot faster than deleting them one by one
+	s.storage.DeleteChildrenBatched(childrenToDelete, s.pruningBatchSize)
+	s.storage.DeleteMessagesBatched(messageIDsToDelete, s.pruningBatchSize)
 
-func (s *SnapshotManager) pruneDatabase(ctx context.Context, targetIndex milestone.Index) (milestone.Index, error) {
+	return len(messageIDsToDelete), bytesDeleted
+}
 
-	if err := utils.ReturnErrIfCtxDone(ctx, common.ErrOperationAborted); err != nil {
-		// do not prune the database if the node was shut down
-		return 0, err
-	}
+// pruningTargetIndex checks whether the database can be pruned up to the given target index.
+// It returns the target index capped to the highest index that keeps enough history for the solid entry points.
+func (s *SnapshotManager) pruningTargetIndex(targetIndex milestone.Index, snapshotInfo *storage.SnapshotInfo) (milestone.Index, error) {
 
 	if s.tangleDatabase.CompactionRunning() || s.utxoDatabase.CompactionRunning() {
 		return 0, ErrDatabaseCompactionRunning
 	}
 
-	snapshotInfo := s.storage.SnapshotInfo()
-	if snapshotInfo == nil {
-		s.LogPanic("No snapshotInfo found!")
-	}
-
-	//lint:ignore SA5011 nil pointer is already checked before with a panic
 	if snapshotInfo.SnapshotIndex < s.solidEntryPointCheckThresholdPast+s.additionalPruningThreshold+1 {
 		// Not enough history
 		return 0, errors.Wrapf(ErrNotEnoughHistory, "minimum index: %d, target index: %d", s.solidEntryPointCheckThresholdPast+s.additionalPruningThreshold+1, targetIndex)
@@ -184,12 +197,53 @@ func (s *SnapshotManager) pruneDatabase(ctx context.Context, targetIndex milesto
 		return 0, errors.Wrapf(ErrNotEnoughHistory, "minimum index: %d, target index: %d", snapshotInfo.EntryPointIndex+s.additionalPruningThreshold+1, targetIndex)
 	}
 
+	return targetIndex, nil
+}
+
+// CheckPruningTargetIndex checks whether the database can currently be pruned up to the given target index,
+// and returns the index the database would be pruned to.
+func (s *SnapshotManager) CheckPruningTargetIndex(targetIndex milestone.Index) (milestone.Index, error) {
+
+	snapshotInfo := s.storage.SnapshotInfo()
+	if snapshotInfo == nil {
+		return 0, errors.Wrap(ErrCritical, "no snapshot info found")
+	}
+
+	return s.pruningTargetIndex(targetIndex, snapshotInfo)
+}
+
+func (s *SnapshotManager) pruneDatabase(ctx context.Context, targetIndex milestone.Index) (milestone.Index, error) {
+
+	if err := utils.ReturnErrIfCtxDone(ctx, common.ErrOperationAborted); err != nil {
+		// do not prune the database if the node was shut down
+		return 0, err
+	}
+
+	if err := s.checkPruningPaused(); err != nil {
+		return 0, err
+	}
+
+	if s.isPruningRunning() {
+		// the pruning was started while another pruning waits for the throttle
+		return 0, ErrPruningRunning
+	}
+
+	snapshotInfo := s.storage.SnapshotInfo()
+	if snapshotInfo == nil {
+		s.LogPanic("No snapshotInfo found!")
+	}
+
+	targetIndex, err := s.pruningTargetIndex(targetIndex, snapshotInfo)
+	if err != nil {
+		return 0, err
+	}
+
 	s.setIsPruning(true)
 	defer s.setIsPruning(false)
 
 	// calculate solid entry points for the new end of the tangle history
 	var solidEntryPoints []*storage.SolidEntryPoint
-	err := s.forEachSolidEntryPoint(
+	err = s.forEachSolidEntryPoint(
 		ctx,
 		targetIndex,
 		func(sep *storage.SolidEntryPoint) bool {
@@ -231,7 +285,7 @@ func (s *SnapshotManager) pruneDatabase(ctx context.Context, targetIndex milesto
 		s.LogInfof("Pruning milestone (%d)...", milestoneIndex)
 
 		timeStart := time.Now()
-		txCountDeleted, msgCountChecked := s.pruneUnreferencedMessages(milestoneIndex)
+		txCountDeleted, msgCountChecked, bytesDeleted := s.pruneUnreferencedMessages(milestoneIndex)
 		timePruneUnreferencedMessages := time.Now()
 
 		cachedMs := s.storage.CachedMilestoneOrNil(milestoneIndex) // milestone +1
@@ -296,7 +350,9 @@ func (s *SnapshotManager) pruneDatabase(ctx context.Context, targetIndex milesto
 		cachedMsMsg.Release(true) // milestone msg -1
 
 		msgCountChecked += len(messageIDsToDeleteMap)
-		txCountDeleted += s.pruneMessages(messageIDsToDeleteMap)
+		msgCountDeleted, msgBytesDeleted := s.pruneMessages(messageIDsToDeleteMap)
+		txCountDeleted += msgCountDeleted
+		bytesDeleted += msgBytesDeleted
 		timePruneMessages := time.Now()
 
 		snapshotInfo.PruningIndex = milestoneIndex
@@ -319,6 +375,17 @@ func (s *SnapshotManager) pruneDatabase(ctx context.Context, targetIndex milesto
 			DurationPruningMilestoneIndexChanged: timePruningMilestoneIndexChanged.Sub(timeSetSnapshotInfo),
 			DurationTotal:                        time.Since(timeStart),
 		})
+
+		if milestoneIndex < targetIndex {
+			// slow down the pruning if a throttle is set, and stop it if it was paused.
+			// the pruning can be continued later, since the new solid entry points were already stored.
+			if err := s.waitPruningThrottleWithoutSnapshotLock(ctx, bytesDeleted, timeStart); err != nil {
+				return 0, err
+			}
+
+			// a snapshot may have been created while waiting
+			snapshotInfo = s.storage.SnapshotInfo()
+		}
 	}
 
 	// finally set the new solid entry points and remove the old ones
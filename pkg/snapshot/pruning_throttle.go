@@ -0,0 +1,166 @@
+package snapshot
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrPruningPaused is returned if the pruning was paused.
+	ErrPruningPaused = errors.New("pruning is paused")
+	// ErrPruningRunning is returned if the pruning was started while another pruning is running.
+	ErrPruningRunning = errors.New("pruning is already running")
+)
+
+// PruningThrottle limits the speed of the pruning, so that catching up with a large pruning backlog
+// in the background doesn't compete with the synchronization of the node.
+type PruningThrottle struct {
+	// the maximum amount of milestones that are pruned per minute (0 = unlimited).
+	MilestonesPerMinute int
+	// the maximum amount of message bytes that are deleted per second (0 = unlimited).
+	MaxBytesPerSecond int64
+}
+
+// PruningThrottleStatus contains the active pruning throttle and whether the pruning is paused.
+type PruningThrottleStatus struct {
+	PruningThrottle
+	// whether the pruning is paused.
+	Paused bool
+}
+
+// delay returns the time to wait after a milestone was pruned, before the next milestone is pruned.
+func (t PruningThrottle) delay(bytesDeleted int64, elapsed time.Duration) time.Duration {
+	var duration time.Duration
+
+	if t.MilestonesPerMinute > 0 {
+		duration = time.Minute / time.Duration(t.MilestonesPerMinute)
+	}
+
+	if t.MaxBytesPerSecond > 0 {
+		if bytesDuration := time.Duration(float64(bytesDeleted) / float64(t.MaxBytesPerSecond) * float64(time.Second)); bytesDuration > duration {
+			duration = bytesDuration
+		}
+	}
+
+	if duration <= elapsed {
+		return 0
+	}
+
+	return duration - elapsed
+}
+
+// SetPruningThrottle replaces the throttle of the pruning.
+// A running pruning applies the new throttle before the next milestone is pruned.
+func (s *SnapshotManager) SetPruningThrottle(throttle PruningThrottle) error {
+	if throttle.MilestonesPerMinute < 0 {
+		return errors.New("milestones per minute must not be negative")
+	}
+	if throttle.MaxBytesPerSecond < 0 {
+		return errors.New("max bytes per second must not be negative")
+	}
+
+	s.pruningThrottleLock.Lock()
+	defer s.pruningThrottleLock.Unlock()
+
+	s.pruningThrottle = throttle
+	s.pruningThrottleChangedWithoutLocking()
+
+	return nil
+}
+
+// PausePruning pauses the pruning of the database.
+// A running pruning stops before the next milestone is pruned and can be continued after the pruning was resumed.
+func (s *SnapshotManager) PausePruning() {
+	s.pruningThrottleLock.Lock()
+	defer s.pruningThrottleLock.Unlock()
+
+	s.pruningPaused = true
+	s.pruningThrottleChangedWithoutLocking()
+}
+
+// ResumePruning resumes the pruning of the database.
+// The pruning is continued with the next confirmed milestone, or if it is started via the API.
+func (s *SnapshotManager) ResumePruning() {
+	s.pruningThrottleLock.Lock()
+	defer s.pruningThrottleLock.Unlock()
+
+	s.pruningPaused = false
+	s.pruningThrottleChangedWithoutLocking()
+}
+
+// PruningThrottleStatus returns the active pruning throttle and whether the pruning is paused.
+func (s *SnapshotManager) PruningThrottleStatus() *PruningThrottleStatus {
+	s.pruningThrottleLock.RLock()
+	defer s.pruningThrottleLock.RUnlock()
+
+	return &PruningThrottleStatus{
+		PruningThrottle: s.pruningThrottle,
+		Paused:          s.pruningPaused,
+	}
+}
+
+// pruningThrottleChangedWithoutLocking wakes up a pruning that waits for the throttle.
+// pruningThrottleLock must be held while calling this function.
+func (s *SnapshotManager) pruningThrottleChangedWithoutLocking() {
+	if s.pruningThrottleChanged != nil {
+		close(s.pruningThrottleChanged)
+	}
+	s.pruningThrottleChanged = make(chan struct{})
+}
+
+// checkPruningPaused returns ErrPruningPaused if the pruning is paused.
+func (s *SnapshotManager) checkPruningPaused() error {
+	s.pruningThrottleLock.RLock()
+	defer s.pruningThrottleLock.RUnlock()
+
+	if s.pruningPaused {
+		return ErrPruningPaused
+	}
+
+	return nil
+}
+
+// waitPruningThrottle waits until the next milestone may be pruned,
+// after a milestone that deleted the given amount of bytes was pruned since pruningStart.
+// It returns early if the pruning was paused or the context was canceled.
+func (s *SnapshotManager) waitPruningThrottle(ctx context.Context, bytesDeleted int64, pruningStart time.Time) error {
+	for {
+		s.pruningThrottleLock.RLock()
+		paused := s.pruningPaused
+		delay := s.pruningThrottle.delay(bytesDeleted, time.Since(pruningStart))
+		changed := s.pruningThrottleChanged
+		s.pruningThrottleLock.RUnlock()
+
+		if paused {
+			return ErrPruningPaused
+		}
+
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ErrPruningAborted
+		case <-changed:
+			// the throttle was changed, calculate the delay again
+			timer.Stop()
+		case <-timer.C:
+			return nil
+		}
+	}
+}
+
+// waitPruningThrottleWithoutSnapshotLock releases the snapshot lock while waiting for the pruning throttle,
+// so snapshots can be created while a throttled pruning is running.
+// snapshotLock must be held while calling this function, it is held again when the function returns.
+func (s *SnapshotManager) waitPruningThrottleWithoutSnapshotLock(ctx context.Context, bytesDeleted int64, pruningStart time.Time) error {
+	s.snapshotLock.Unlock()
+	defer s.snapshotLock.Lock()
+
+	return s.waitPruningThrottle(ctx, bytesDeleted, pruningStart)
+}
@@ -0,0 +1,126 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/utils"
+)
+
+func TestPruningThrottleDelay(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		throttle     PruningThrottle
+		bytesDeleted int64
+		elapsed      time.Duration
+		delay        time.Duration
+	}{
+		{"unlimited", PruningThrottle{}, 1024, 0, 0},
+		{"milestones", PruningThrottle{MilestonesPerMinute: 30}, 1024, 0, 2 * time.Second},
+		{"milestones elapsed", PruningThrottle{MilestonesPerMinute: 30}, 1024, 500 * time.Millisecond, 1500 * time.Millisecond},
+		{"milestones slower", PruningThrottle{MilestonesPerMinute: 30}, 1024, 3 * time.Second, 0},
+		{"bytes", PruningThrottle{MaxBytesPerSecond: 1024}, 4096, 0, 4 * time.Second},
+		{"bytes and milestones", PruningThrottle{MilestonesPerMinute: 30, MaxBytesPerSecond: 1024}, 4096, time.Second, 3 * time.Second},
+		{"milestones and bytes", PruningThrottle{MilestonesPerMinute: 6, MaxBytesPerSecond: 1024}, 4096, time.Second, 9 * time.Second},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.delay, test.throttle.delay(test.bytesDeleted, test.elapsed), test.name)
+	}
+}
+
+func TestPruningThrottlePause(t *testing.T) {
+
+	s := &SnapshotManager{WrappedLogger: utils.NewWrappedLogger(nil)}
+	require.NoError(t, s.SetPruningThrottle(PruningThrottle{MilestonesPerMinute: 1}))
+	require.Error(t, s.SetPruningThrottle(PruningThrottle{MaxBytesPerSecond: -1}))
+
+	// a waiting pruning is stopped if it gets paused
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- s.waitPruningThrottle(context.Background(), 0, time.Now())
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	s.PausePruning()
+
+	select {
+	case err := <-waitErr:
+		require.ErrorIs(t, err, ErrPruningPaused)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "pruning was not paused")
+	}
+	require.ErrorIs(t, s.checkPruningPaused(), ErrPruningPaused)
+	require.True(t, s.PruningThrottleStatus().Paused)
+
+	// a waiting pruning continues immediately if the throttle is removed
+	s.ResumePruning()
+	require.NoError(t, s.checkPruningPaused())
+
+	go func() {
+		waitErr <- s.waitPruningThrottle(context.Background(), 0, time.Now())
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, s.SetPruningThrottle(PruningThrottle{}))
+
+	select {
+	case err := <-waitErr:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "throttle change was not applied")
+	}
+}
+
+func TestPruningThrottleReleasesSnapshotLock(t *testing.T) {
+
+	s := &SnapshotManager{WrappedLogger: utils.NewWrappedLogger(nil)}
+	require.NoError(t, s.SetPruningThrottle(PruningThrottle{MilestonesPerMinute: 1}))
+
+	// a throttled pruning waits before the next milestone is pruned
+	pruningWaiting := make(chan struct{})
+	waitErr := make(chan error, 1)
+	go func() {
+		s.snapshotLock.Lock()
+		defer s.snapshotLock.Unlock()
+
+		s.isPruning = true
+		close(pruningWaiting)
+		waitErr <- s.waitPruningThrottleWithoutSnapshotLock(context.Background(), 0, time.Now())
+	}()
+	<-pruningWaiting
+
+	// a snapshot can be created while the pruning waits
+	snapshotCreated := make(chan struct{})
+	go func() {
+		s.snapshotLock.Lock()
+		defer s.snapshotLock.Unlock()
+
+		close(snapshotCreated)
+	}()
+
+	select {
+	case <-snapshotCreated:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "snapshot lock is held while the pruning waits")
+	}
+
+	// another pruning is not started while the pruning waits
+	s.snapshotLock.Lock()
+	_, err := s.pruneDatabase(context.Background(), 100)
+	s.snapshotLock.Unlock()
+	require.ErrorIs(t, err, ErrPruningRunning)
+
+	require.NoError(t, s.SetPruningThrottle(PruningThrottle{}))
+
+	select {
+	case err := <-waitErr:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "throttle change was not applied")
+	}
+}
@@ -0,0 +1,176 @@
+package snapshot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+const (
+	// SchedulePolicyInterval creates a snapshot every configured amount of milestones.
+	SchedulePolicyInterval = "interval"
+	// SchedulePolicyCron creates a snapshot at the wall-clock times of a cron expression.
+	SchedulePolicyCron = "cron"
+	// SchedulePolicyLedgerGrowth creates a snapshot every time the ledger database grew by the configured amount of bytes.
+	SchedulePolicyLedgerGrowth = "ledgerGrowth"
+)
+
+// Schedule defines when snapshot files are created.
+type Schedule struct {
+	// The policy that triggers the creation of a snapshot.
+	Policy string
+	// The interval in milestones, used by SchedulePolicyInterval.
+	Interval milestone.Index
+	// The cron expression in the local time of the node, used by SchedulePolicyCron.
+	Cron string
+	// The growth of the ledger database in bytes, used by SchedulePolicyLedgerGrowth.
+	LedgerGrowthBytes int64
+}
+
+// ScheduleStatus is the active schedule and the progress towards the next snapshot.
+type ScheduleStatus struct {
+	Schedule
+	// The time at which the next snapshot is created, only known with SchedulePolicyCron.
+	NextSnapshotTime time.Time
+	// The growth of the ledger database in bytes since the last snapshot, only known with SchedulePolicyLedgerGrowth.
+	CurrentLedgerGrowthBytes int64
+}
+
+// snapshotSchedule is a validated Schedule and the state of the last scheduled snapshot.
+type snapshotSchedule struct {
+	*Schedule
+	cron *cronSchedule
+
+	// the time of the last scheduled snapshot, or when the schedule was set.
+	lastSnapshotTime time.Time
+	// the size of the ledger database at the last scheduled snapshot, or when the schedule was set.
+	lastSnapshotLedgerSize int64
+}
+
+// newSnapshotSchedule validates the schedule.
+func newSnapshotSchedule(schedule *Schedule) (*snapshotSchedule, error) {
+
+	// copy the schedule so it can't be changed from outside
+	scheduleCopy := *schedule
+	result := &snapshotSchedule{Schedule: &scheduleCopy}
+
+	switch schedule.Policy {
+	case SchedulePolicyInterval:
+		if schedule.Interval == 0 {
+			return nil, fmt.Errorf("snapshot interval has to be greater than 0")
+		}
+
+	case SchedulePolicyCron:
+		cron, err := parseCron(schedule.Cron)
+		if err != nil {
+			return nil, err
+		}
+		result.cron = cron
+
+	case SchedulePolicyLedgerGrowth:
+		if schedule.LedgerGrowthBytes <= 0 {
+			return nil, fmt.Errorf("ledger growth has to be greater than 0")
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown snapshot schedule policy: %s", schedule.Policy)
+	}
+
+	return result, nil
+}
+
+// ledgerSize returns the size of the ledger database.
+func (s *SnapshotManager) ledgerSize() (int64, error) {
+	return s.utxoDatabase.Size()
+}
+
+// SetSchedule validates and activates the schedule of the snapshot creation.
+// The progress of the cron and ledger growth policies starts from the time the schedule is set.
+func (s *SnapshotManager) SetSchedule(schedule *Schedule) error {
+
+	newSchedule, err := newSnapshotSchedule(schedule)
+	if err != nil {
+		return err
+	}
+
+	newSchedule.lastSnapshotTime = time.Now()
+	if newSchedule.Policy == SchedulePolicyLedgerGrowth {
+		if newSchedule.lastSnapshotLedgerSize, err = s.ledgerSize(); err != nil {
+			return fmt.Errorf("reading ledger database size failed: %w", err)
+		}
+	}
+
+	s.scheduleLock.Lock()
+	defer s.scheduleLock.Unlock()
+
+	s.schedule = newSchedule
+
+	return nil
+}
+
+// ScheduleStatus returns the active schedule of the snapshot creation and the progress towards the next snapshot.
+func (s *SnapshotManager) ScheduleStatus() (*ScheduleStatus, error) {
+
+	s.scheduleLock.RLock()
+	defer s.scheduleLock.RUnlock()
+
+	status := &ScheduleStatus{Schedule: *s.schedule.Schedule}
+
+	switch s.schedule.Policy {
+	case SchedulePolicyCron:
+		status.NextSnapshotTime = s.schedule.cron.next(s.schedule.lastSnapshotTime)
+
+	case SchedulePolicyLedgerGrowth:
+		ledgerSize, err := s.ledgerSize()
+		if err != nil {
+			return nil, fmt.Errorf("reading ledger database size failed: %w", err)
+		}
+		status.CurrentLedgerGrowthBytes = ledgerSize - s.schedule.lastSnapshotLedgerSize
+	}
+
+	return status, nil
+}
+
+// scheduleDue returns whether the active schedule triggers a snapshot.
+func (s *SnapshotManager) scheduleDue(confirmedMilestoneIndex milestone.Index, snapshotIndex milestone.Index) bool {
+
+	s.scheduleLock.RLock()
+	defer s.scheduleLock.RUnlock()
+
+	switch s.schedule.Policy {
+	case SchedulePolicyInterval:
+		return confirmedMilestoneIndex >= s.snapshotDepth+s.schedule.Interval &&
+			confirmedMilestoneIndex-(s.snapshotDepth+s.schedule.Interval) >= snapshotIndex
+
+	case SchedulePolicyCron:
+		return !s.schedule.cron.next(s.schedule.lastSnapshotTime).After(time.Now())
+
+	case SchedulePolicyLedgerGrowth:
+		ledgerSize, err := s.ledgerSize()
+		if err != nil {
+			s.LogWarnf("reading ledger database size failed: %s", err)
+			return false
+		}
+		return ledgerSize-s.schedule.lastSnapshotLedgerSize >= s.schedule.LedgerGrowthBytes
+	}
+
+	return false
+}
+
+// scheduledSnapshotCreated resets the progress of the schedule after a snapshot was created.
+func (s *SnapshotManager) scheduledSnapshotCreated() {
+
+	s.scheduleLock.Lock()
+	defer s.scheduleLock.Unlock()
+
+	s.schedule.lastSnapshotTime = time.Now()
+	if s.schedule.Policy == SchedulePolicyLedgerGrowth {
+		ledgerSize, err := s.ledgerSize()
+		if err != nil {
+			s.LogWarnf("reading ledger database size failed: %s", err)
+			return
+		}
+		s.schedule.lastSnapshotLedgerSize = ledgerSize
+	}
+}
@@ -80,21 +80,31 @@ type SnapshotManager struct {
 	snapshotFullPath                     string
 	snapshotDeltaPath                    string
 	deltaSnapshotSizeThresholdPercentage float64
+	encryptionKey                        string
+	workerCount                          int
 	downloadTargets                      []*DownloadTarget
 	solidEntryPointCheckThresholdPast    milestone.Index
 	solidEntryPointCheckThresholdFuture  milestone.Index
 	additionalPruningThreshold           milestone.Index
 	snapshotDepth                        milestone.Index
-	snapshotInterval                     milestone.Index
 	pruningMilestonesEnabled             bool
 	pruningMilestonesMaxMilestonesToKeep milestone.Index
 	pruningSizeEnabled                   bool
 	pruningSizeTargetSizeBytes           int64
 	pruningSizeThresholdPercentage       float64
 	pruningSizeCooldownTime              time.Duration
+	pruningThrottleLock                  syncutils.RWMutex
+	pruningThrottle                      PruningThrottle
+	pruningThrottleChanged               chan struct{}
+	pruningPaused                        bool
 	pruneReceipts                        bool
+	pruningBatchSize                     int
 
 	snapshotLock          syncutils.Mutex
+	scheduleLock          syncutils.RWMutex
+	schedule              *snapshotSchedule
+	targetsLock           syncutils.RWMutex
+	targets               map[string]*registeredTarget
 	statusLock            syncutils.RWMutex
 	isSnapshotting        bool
 	isPruning             bool
@@ -117,21 +127,25 @@ func NewSnapshotManager(
 	snapshotFullPath string,
 	snapshotDeltaPath string,
 	deltaSnapshotSizeThresholdPercentage float64,
+	encryptionKey string,
+	workerCount int,
 	downloadTargets []*DownloadTarget,
 	solidEntryPointCheckThresholdPast milestone.Index,
 	solidEntryPointCheckThresholdFuture milestone.Index,
 	additionalPruningThreshold milestone.Index,
 	snapshotDepth milestone.Index,
-	snapshotInterval milestone.Index,
+	schedule *Schedule,
 	pruningMilestonesEnabled bool,
 	pruningMilestonesMaxMilestonesToKeep milestone.Index,
 	pruningSizeEnabled bool,
 	pruningSizeTargetSizeBytes int64,
 	pruningSizeThresholdPercentage float64,
 	pruningSizeCooldownTime time.Duration,
-	pruneReceipts bool) *SnapshotManager {
+	pruningThrottle PruningThrottle,
+	pruneReceipts bool,
+	pruningBatchSize int) (*SnapshotManager, error) {
 
-	return &SnapshotManager{
+	snapshotManager := &SnapshotManager{
 		WrappedLogger:                        utils.NewWrappedLogger(log),
 		tangleDatabase:                       tangleDatabase,
 		utxoDatabase:                         utxoDatabase,
@@ -144,12 +158,13 @@ func NewSnapshotManager(
 		snapshotFullPath:                     snapshotFullPath,
 		snapshotDeltaPath:                    snapshotDeltaPath,
 		deltaSnapshotSizeThresholdPercentage: deltaSnapshotSizeThresholdPercentage,
+		encryptionKey:                        encryptionKey,
+		workerCount:                          workerCount,
 		downloadTargets:                      downloadTargets,
 		solidEntryPointCheckThresholdPast:    solidEntryPointCheckThresholdPast,
 		solidEntryPointCheckThresholdFuture:  solidEntryPointCheckThresholdFuture,
 		additionalPruningThreshold:           additionalPruningThreshold,
 		snapshotDepth:                        snapshotDepth,
-		snapshotInterval:                     snapshotInterval,
 		pruningMilestonesEnabled:             pruningMilestonesEnabled,
 		pruningMilestonesMaxMilestonesToKeep: pruningMilestonesMaxMilestonesToKeep,
 		pruningSizeEnabled:                   pruningSizeEnabled,
@@ -157,6 +172,7 @@ func NewSnapshotManager(
 		pruningSizeThresholdPercentage:       pruningSizeThresholdPercentage,
 		pruningSizeCooldownTime:              pruningSizeCooldownTime,
 		pruneReceipts:                        pruneReceipts,
+		pruningBatchSize:                     pruningBatchSize,
 		Events: &Events{
 			SnapshotMilestoneIndexChanged: events.NewEvent(milestone.IndexCaller),
 			SnapshotMetricsUpdated:        events.NewEvent(SnapshotMetricsCaller),
@@ -164,6 +180,16 @@ func NewSnapshotManager(
 			PruningMetricsUpdated:         events.NewEvent(PruningMetricsCaller),
 		},
 	}
+
+	if err := snapshotManager.SetSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	if err := snapshotManager.SetPruningThrottle(pruningThrottle); err != nil {
+		return nil, err
+	}
+
+	return snapshotManager, nil
 }
 
 func (s *SnapshotManager) IsSnapshottingOrPruning() bool {
@@ -179,12 +205,17 @@ func (s *SnapshotManager) shouldTakeSnapshot(confirmedMilestoneIndex milestone.I
 		s.LogPanic("No snapshotInfo found!")
 	}
 
-	if (confirmedMilestoneIndex < s.snapshotDepth+s.snapshotInterval) || (confirmedMilestoneIndex-s.snapshotDepth) < snapshotInfo.PruningIndex+1+s.solidEntryPointCheckThresholdPast {
+	if (confirmedMilestoneIndex <= s.snapshotDepth) || (confirmedMilestoneIndex-s.snapshotDepth) < snapshotInfo.PruningIndex+1+s.solidEntryPointCheckThresholdPast {
 		// Not enough history to calculate solid entry points
 		return false
 	}
 
-	return confirmedMilestoneIndex-(s.snapshotDepth+s.snapshotInterval) >= snapshotInfo.SnapshotIndex
+	if confirmedMilestoneIndex-s.snapshotDepth <= snapshotInfo.SnapshotIndex {
+		// the new snapshot index must be greater than the last snapshot index
+		return false
+	}
+
+	return s.scheduleDue(confirmedMilestoneIndex, snapshotInfo.SnapshotIndex)
 }
 
 func (s *SnapshotManager) forEachSolidEntryPoint(ctx context.Context, targetIndex milestone.Index, solidEntryPointConsumer func(sep *storage.SolidEntryPoint) bool) error {
@@ -326,6 +357,18 @@ func (s *SnapshotManager) checkSnapshotLimits(targetIndex milestone.Index, snaps
 	return nil
 }
 
+// CheckSnapshotTargetIndex checks whether a snapshot file can currently be created for the given target milestone index.
+// The snapshot state is not written to the database.
+func (s *SnapshotManager) CheckSnapshotTargetIndex(targetIndex milestone.Index) error {
+
+	snapshotInfo := s.storage.SnapshotInfo()
+	if snapshotInfo == nil {
+		return errors.Wrap(ErrCritical, "no snapshot info found")
+	}
+
+	return s.checkSnapshotLimits(targetIndex, snapshotInfo, false)
+}
+
 func (s *SnapshotManager) setIsSnapshotting(value bool) {
 	s.statusLock.Lock()
 	s.isSnapshotting = value
@@ -339,6 +382,22 @@ func (s *SnapshotManager) CreateFullSnapshot(ctx context.Context, targetIndex mi
 	return s.createSnapshotWithoutLocking(ctx, Full, targetIndex, filePath, writeToDatabase)
 }
 
+// CreateFullSnapshotInTarget creates a full snapshot for the given target milestone index
+// and streams it to the snapshot target with the given name without writing a local file.
+func (s *SnapshotManager) CreateFullSnapshotInTarget(ctx context.Context, targetIndex milestone.Index, targetName string, fileName string) error {
+	target, err := s.target(targetName)
+	if err != nil {
+		return err
+	}
+
+	s.snapshotLock.Lock()
+	defer s.snapshotLock.Unlock()
+	return s.createSnapshotToDestination(ctx, Full, targetIndex, &snapshotDestination{
+		targets:        []Target{target},
+		targetFileName: fileName,
+	}, false)
+}
+
 // CreateDeltaSnapshot creates a delta snapshot for the given target milestone index.
 func (s *SnapshotManager) CreateDeltaSnapshot(ctx context.Context, targetIndex milestone.Index, filePath string, writeToDatabase bool, snapshotFullPath ...string) error {
 	s.snapshotLock.Lock()
@@ -346,6 +405,24 @@ func (s *SnapshotManager) CreateDeltaSnapshot(ctx context.Context, targetIndex m
 	return s.createSnapshotWithoutLocking(ctx, Delta, targetIndex, filePath, writeToDatabase, snapshotFullPath...)
 }
 
+// CreateDeltaSnapshotInTarget creates a delta snapshot for the given target milestone index
+// and streams it to the snapshot target with the given name without writing a local file.
+// The delta snapshot is based on the full snapshot with the given index (0 = the full snapshot file of the node).
+func (s *SnapshotManager) CreateDeltaSnapshotInTarget(ctx context.Context, targetIndex milestone.Index, targetName string, fileName string, fullSnapshotIndex milestone.Index) error {
+	target, err := s.target(targetName)
+	if err != nil {
+		return err
+	}
+
+	s.snapshotLock.Lock()
+	defer s.snapshotLock.Unlock()
+	return s.createSnapshotToDestination(ctx, Delta, targetIndex, &snapshotDestination{
+		targets:           []Target{target},
+		targetFileName:    fileName,
+		fullSnapshotIndex: fullSnapshotIndex,
+	}, false)
+}
+
 // LoadSnapshotFromFile loads a snapshot file from the given file path into the storage.
 func (s *SnapshotManager) LoadSnapshotFromFile(ctx context.Context, snapshotType Type, filePath string) (err error) {
 	s.LogInfof("importing %s snapshot file...", snapshotNames[snapshotType])
@@ -430,10 +507,10 @@ func (s *SnapshotManager) snapshotTypeFilePath(snapshotType Type) string {
 	}
 }
 
-// HandleNewConfirmedMilestoneEvent handles new confirmed milestone events which may trigger a delta snapshot creation and pruning.
+// HandleNewConfirmedMilestoneEvent handles new confirmed milestone events which may trigger a delta snapshot creation.
 func (s *SnapshotManager) HandleNewConfirmedMilestoneEvent(ctx context.Context, confirmedMilestoneIndex milestone.Index) {
 	if !s.syncManager.IsNodeSynced() {
-		// do not prune or create snapshots while we are not synced
+		// do not create snapshots while we are not synced
 		return
 	}
 
@@ -452,14 +529,23 @@ func (s *SnapshotManager) HandleNewConfirmedMilestoneEvent(ctx context.Context,
 				s.LogPanicf("%s: %s", ErrSnapshotCreationFailed, err)
 			}
 			s.LogWarnf("%s: %s", ErrSnapshotCreationFailed, err)
+		} else {
+			s.scheduledSnapshotCreated()
 		}
+	}
+}
 
-		if !s.syncManager.IsNodeSynced() {
-			// do not prune while we are not synced
-			return
-		}
+// PruneDatabaseForConfirmedMilestone handles new confirmed milestone events which may trigger the pruning of the database.
+// The pruning runs independently of the snapshot creation, so a throttled pruning doesn't delay the snapshots.
+func (s *SnapshotManager) PruneDatabaseForConfirmedMilestone(ctx context.Context, confirmedMilestoneIndex milestone.Index) {
+	if !s.syncManager.IsNodeSynced() {
+		// do not prune while we are not synced
+		return
 	}
 
+	s.snapshotLock.Lock()
+	defer s.snapshotLock.Unlock()
+
 	var targetIndex milestone.Index = 0
 	if s.pruningMilestonesEnabled && confirmedMilestoneIndex > s.pruningMilestonesMaxMilestonesToKeep {
 		targetIndex = confirmedMilestoneIndex - s.pruningMilestonesMaxMilestonesToKeep
@@ -480,7 +566,13 @@ func (s *SnapshotManager) HandleNewConfirmedMilestoneEvent(ctx context.Context,
 	}
 
 	if _, err := s.pruneDatabase(ctx, targetIndex); err != nil {
-		s.LogDebugf("pruning aborted: %v", err)
+		if pruningBySize && (errors.Is(err, ErrNotEnoughHistory) || errors.Is(err, ErrNoPruningNeeded)) {
+			// the remaining history is needed for the solid entry points of the next snapshot,
+			// the warning is repeated after the cooldown as long as the target size can't be reached
+			s.LogWarnf("database exceeds the pruning target size, but no more milestones can be pruned: %v", err)
+		} else {
+			s.LogDebugf("pruning aborted: %v", err)
+		}
 	}
 
 	if pruningBySize {
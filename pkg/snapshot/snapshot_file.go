@@ -5,7 +5,6 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
-	"os"
 	"time"
 
 	"github.com/pkg/errors"
@@ -232,11 +231,41 @@ func getSnapshotFilesLedgerIndex(fullHeader *ReadFileHeader, deltaHeader *ReadFi
 	return deltaHeader.SEPMilestoneIndex
 }
 
+// StreamOptions define options for streaming snapshot data.
+type StreamOptions struct {
+	workerCount int
+}
+
+// StreamOption is a function setting a StreamOptions option.
+type StreamOption func(*StreamOptions)
+
+// WorkerCount sets the amount of workers that serialize the outputs and milestone diffs.
+// The data is written in the order it was produced, independent of the amount of workers.
+func WorkerCount(workerCount int) StreamOption {
+	return func(opts *StreamOptions) {
+		opts.workerCount = workerCount
+	}
+}
+
+func streamOptions(optionalOptions []StreamOption) *StreamOptions {
+	result := &StreamOptions{
+		workerCount: 1,
+	}
+
+	for _, optionalOption := range optionalOptions {
+		optionalOption(result)
+	}
+
+	return result
+}
+
 // StreamSnapshotDataTo streams a snapshot data into the given io.WriteSeeker.
 // FileHeader.Type is used to determine whether to write a full or delta snapshot.
 // If the type of the snapshot is Full, then OutputProducerFunc must be provided.
 func StreamSnapshotDataTo(writeSeeker io.WriteSeeker, timestamp uint64, header *FileHeader,
-	sepProd SEPProducerFunc, outputProd OutputProducerFunc, msDiffProd MilestoneDiffProducerFunc) (*SnapshotMetrics, error) {
+	sepProd SEPProducerFunc, outputProd OutputProducerFunc, msDiffProd MilestoneDiffProducerFunc, options ...StreamOption) (*SnapshotMetrics, error) {
+
+	opts := streamOptions(options)
 
 	if header.Type == Full {
 		switch {
@@ -311,19 +340,28 @@ func StreamSnapshotDataTo(writeSeeker io.WriteSeeker, timestamp uint64, header *
 	timeSolidEntryPoints := time.Now()
 
 	if header.Type == Full {
-		for {
+		outputBytesProd := newOrderedParallelProducer(opts.workerCount, func() (interface{}, error) {
 			output, err := outputProd()
+			if output == nil || err != nil {
+				return nil, err
+			}
+			return output, nil
+		}, func(item interface{}) (interface{}, error) {
+			return item.(*utxo.Output).SnapshotBytes(), nil
+		})
+
+		for {
+			outputBytes, err := outputBytesProd()
 			if err != nil {
 				return nil, fmt.Errorf("unable to get next LS output #%d: %w", outputCount+1, err)
 			}
 
-			if output == nil {
+			if outputBytes == nil {
 				break
 			}
 
 			outputCount++
-			outputBytes := output.SnapshotBytes()
-			if _, err := writeSeeker.Write(outputBytes); err != nil {
+			if _, err := writeSeeker.Write(outputBytes.([]byte)); err != nil {
 				return nil, fmt.Errorf("unable to write LS output #%d: %w", outputCount, err)
 			}
 		}
@@ -331,22 +369,33 @@ func StreamSnapshotDataTo(writeSeeker io.WriteSeeker, timestamp uint64, header *
 
 	timeOutputs := time.Now()
 
-	for {
+	msDiffBytesProd := newOrderedParallelProducer(opts.workerCount, func() (interface{}, error) {
 		msDiff, err := msDiffProd()
+		if msDiff == nil || err != nil {
+			return nil, err
+		}
+		return msDiff, nil
+	}, func(item interface{}) (interface{}, error) {
+		msDiff := item.(*MilestoneDiff)
+		msDiffBytes, err := msDiff.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("unable to serialize LS milestone diff %d: %w", msDiff.Milestone.Index, err)
+		}
+		return msDiffBytes, nil
+	})
+
+	for {
+		msDiffBytes, err := msDiffBytesProd()
 		if err != nil {
 			return nil, fmt.Errorf("unable to get next LS milestone diff #%d: %w", msDiffCount+1, err)
 		}
 
-		if msDiff == nil {
+		if msDiffBytes == nil {
 			break
 		}
 
 		msDiffCount++
-		msDiffBytes, err := msDiff.MarshalBinary()
-		if err != nil {
-			return nil, fmt.Errorf("unable to serialize LS milestone diff #%d: %w", msDiffCount, err)
-		}
-		if _, err := writeSeeker.Write(msDiffBytes); err != nil {
+		if _, err := writeSeeker.Write(msDiffBytes.([]byte)); err != nil {
 			return nil, fmt.Errorf("unable to write LS milestone diff #%d: %w", msDiffCount, err)
 		}
 	}
@@ -578,8 +627,9 @@ func readSpent(reader io.ReadSeeker, deSeriParas *iotago.DeSerializationParamete
 }
 
 // ReadSnapshotHeaderFromFile reads the header of the given snapshot file.
+// Encrypted snapshot files are decrypted with the key from the environment.
 func ReadSnapshotHeaderFromFile(filePath string) (*ReadFileHeader, error) {
-	file, err := os.Open(filePath)
+	file, err := openSnapshotFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("unable to open snapshot file to read header: %w", err)
 	}
@@ -42,6 +42,7 @@ type test struct {
 	unspentTreasuryOutputConsumer snapshot.UnspentTreasuryOutputConsumerFunc
 	msDiffConsumer                snapshot.MilestoneDiffConsumerFunc
 	msDiffConRetriever            msDiffRetrieverFunc
+	workerCount                   int
 }
 
 func TestStreamLocalSnapshotDataToAndFrom(t *testing.T) {
@@ -95,6 +96,51 @@ func TestStreamLocalSnapshotDataToAndFrom(t *testing.T) {
 			}
 			return t
 		}(),
+		func() test {
+			originHeader := &snapshot.FileHeader{
+				Type:                 snapshot.Full,
+				Version:              snapshot.SupportedFormatVersion,
+				NetworkID:            1337133713371337,
+				SEPMilestoneIndex:    milestone.Index(rand.Intn(10000)),
+				LedgerMilestoneIndex: milestone.Index(rand.Intn(10000)),
+				TreasuryOutput:       &utxo.TreasuryOutput{MilestoneID: iotago.MilestoneID{}, Amount: 13337},
+			}
+
+			originTimestamp := uint64(time.Now().Unix())
+
+			// create generators and consumers
+			sepIterFunc, sepGenRetriever := newSEPGenerator(150)
+			sepConsumerFunc, sepsCollRetriever := newSEPCollector()
+
+			outputIterFunc, outputGenRetriever := newOutputsGenerator(100000)
+			outputConsumerFunc, outputCollRetriever := newOutputCollector()
+
+			msDiffIterFunc, msDiffGenRetriever := newMsDiffGenerator(50)
+			msDiffConsumerFunc, msDiffCollRetriever := newMsDiffCollector()
+
+			t := test{
+				name:                          "full parallel: 150 seps, 100k outputs, 50 ms diffs",
+				snapshotFileName:              "full_snapshot_parallel.bin",
+				originHeader:                  originHeader,
+				originTimestamp:               originTimestamp,
+				sepGenerator:                  sepIterFunc,
+				sepGenRetriever:               sepGenRetriever,
+				outputGenerator:               outputIterFunc,
+				outputGenRetriever:            outputGenRetriever,
+				msDiffGenerator:               msDiffIterFunc,
+				msDiffGenRetriever:            msDiffGenRetriever,
+				headerConsumer:                headerEqualFunc(t, originHeader),
+				sepConsumer:                   sepConsumerFunc,
+				sepConRetriever:               sepsCollRetriever,
+				outputConsumer:                outputConsumerFunc,
+				outputConRetriever:            outputCollRetriever,
+				unspentTreasuryOutputConsumer: unspentTreasuryOutputEqualFunc(t, originHeader.TreasuryOutput),
+				msDiffConsumer:                msDiffConsumerFunc,
+				msDiffConRetriever:            msDiffCollRetriever,
+				workerCount:                   4,
+			}
+			return t
+		}(),
 		func() test {
 			originHeader := &snapshot.FileHeader{
 				Type:                 snapshot.Delta,
@@ -139,7 +185,7 @@ func TestStreamLocalSnapshotDataToAndFrom(t *testing.T) {
 			snapshotFileWrite, err := fs.OpenFile(filePath, os.O_CREATE|os.O_RDWR, 0666)
 			require.NoError(t, err)
 
-			_, err = snapshot.StreamSnapshotDataTo(snapshotFileWrite, tt.originTimestamp, tt.originHeader, tt.sepGenerator, tt.outputGenerator, tt.msDiffGenerator)
+			_, err = snapshot.StreamSnapshotDataTo(snapshotFileWrite, tt.originTimestamp, tt.originHeader, tt.sepGenerator, tt.outputGenerator, tt.msDiffGenerator, snapshot.WorkerCount(tt.workerCount))
 			require.NoError(t, err)
 			require.NoError(t, snapshotFileWrite.Close())
 
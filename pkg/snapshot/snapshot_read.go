@@ -3,7 +3,7 @@ package snapshot
 import (
 	"context"
 	"fmt"
-	"os"
+	"io"
 	"time"
 
 	"github.com/pkg/errors"
@@ -125,8 +125,8 @@ func loadSnapshotFileToStorage(
 		dbStorage.WriteUnlockSolidEntryPoints()
 	}()
 
-	var lsFile *os.File
-	lsFile, err = os.Open(filePath)
+	var lsFile io.ReadSeekCloser
+	lsFile, err = openSnapshotFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("unable to open %s snapshot file for import: %w", snapshotNames[snapshotType], err)
 	}
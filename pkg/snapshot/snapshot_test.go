@@ -162,7 +162,7 @@ func TestSnapshotOutputProducerAndConsumer(t *testing.T) {
 	require.Equal(t, count, aliasCount)
 
 	// Pass all outputs from u1 to u2 over the snapshot serialization functions
-	producer := newCMIUTXOProducer(u1)
+	producer := newCMIUTXOProducer(u1, 4)
 	consumer := newOutputConsumer(u2)
 
 	for {
@@ -293,7 +293,7 @@ func TestSnapshotMsDiffProducerAndConsumer(t *testing.T) {
 
 	producerU1 := newMsDiffsProducer(func(index milestone.Index) (*iotago.Milestone, error) {
 		return &iotago.Milestone{Index: uint32(index)}, nil
-	}, u1, MsDiffDirectionOnwards, startIndex, targetIndex)
+	}, u1, MsDiffDirectionOnwards, startIndex, targetIndex, 4)
 	consumerU2 := newMsDiffConsumer(u2)
 
 	err := u2.StoreLedgerIndex(startIndex)
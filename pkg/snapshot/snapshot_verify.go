@@ -0,0 +1,200 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/utils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrSnapshotVerificationFailed is returned if a snapshot file is inconsistent.
+	ErrSnapshotVerificationFailed = errors.New("snapshot verification failed")
+)
+
+// VerificationResult contains the information about a verified snapshot file.
+type VerificationResult struct {
+	// the header of the snapshot file.
+	Header *ReadFileHeader
+	// the sum of the deposits of the unspent outputs (full snapshots only).
+	OutputsBalance uint64
+	// the amount of outputs created by the milestone diffs.
+	CreatedOutputsCount uint64
+	// the amount of outputs consumed by the milestone diffs.
+	ConsumedOutputsCount uint64
+	// the sum of the deposits of the outputs created by the milestone diffs.
+	CreatedOutputsBalance uint64
+	// the sum of the deposits of the outputs consumed by the milestone diffs.
+	ConsumedOutputsBalance uint64
+	// the amount of milestone diffs that contain a receipt.
+	ReceiptsCount uint64
+}
+
+// VerifySnapshotFile fully parses the snapshot file at the given path and checks its consistency:
+//	- the file format version and the network ID (optional).
+//	- the solid entry points and outputs are unique.
+//	- the unspent outputs and the treasury of a full snapshot sum up to the total supply.
+//	- the milestone diffs lead from the ledger index to the snapshot index without gaps.
+//	- every milestone diff creates and consumes the same amount of tokens, apart from migrated funds.
+func VerifySnapshotFile(ctx context.Context, filePath string, deSeriParas *iotago.DeSerializationParameters, wantedNetworkID ...uint64) (*VerificationResult, error) {
+
+	file, err := openSnapshotFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open snapshot file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	result := &VerificationResult{}
+	var treasuryOutput *utxo.TreasuryOutput
+
+	// the amount of milestone diffs that were read.
+	var msDiffCount milestone.Index
+	// the index of the milestone diff that is expected next.
+	// the milestone diffs of a full snapshot roll back the ledger to the snapshot index,
+	// the ones of a delta snapshot apply the milestones from the ledger index up to the snapshot index.
+	nextMsDiffIndex := func() milestone.Index {
+		if result.Header.Type == Full {
+			return result.Header.LedgerMilestoneIndex - msDiffCount
+		}
+		return result.Header.LedgerMilestoneIndex + 1 + msDiffCount
+	}
+
+	headerConsumer := func(header *ReadFileHeader) error {
+		if header.Version != SupportedFormatVersion {
+			return errors.Wrapf(ErrUnsupportedSnapshot, "snapshot file version is %d but this HORNET version only supports %v", header.Version, SupportedFormatVersion)
+		}
+
+		if header.Type != Full && header.Type != Delta {
+			return errors.Wrapf(ErrUnsupportedSnapshot, "unknown snapshot type %d", header.Type)
+		}
+
+		if len(wantedNetworkID) > 0 && header.NetworkID != wantedNetworkID[0] {
+			return errors.Wrapf(ErrSnapshotVerificationFailed, "snapshot file network ID is %d but expected was %d", header.NetworkID, wantedNetworkID[0])
+		}
+
+		switch header.Type {
+		case Full:
+			if header.LedgerMilestoneIndex < header.SEPMilestoneIndex {
+				return errors.Wrapf(ErrSnapshotVerificationFailed, "ledger index %d is older than snapshot index %d", header.LedgerMilestoneIndex, header.SEPMilestoneIndex)
+			}
+		default:
+			if header.LedgerMilestoneIndex > header.SEPMilestoneIndex {
+				return errors.Wrapf(ErrSnapshotVerificationFailed, "ledger index %d is newer than snapshot index %d", header.LedgerMilestoneIndex, header.SEPMilestoneIndex)
+			}
+		}
+
+		result.Header = header
+		return nil
+	}
+
+	seps := make(map[string]struct{})
+	sepConsumer := func(messageID hornet.MessageID) error {
+		if _, exists := seps[messageID.ToMapKey()]; exists {
+			return errors.Wrapf(ErrSnapshotVerificationFailed, "duplicate solid entry point %s", messageID.ToHex())
+		}
+		seps[messageID.ToMapKey()] = struct{}{}
+		return nil
+	}
+
+	outputIDs := make(map[iotago.OutputID]struct{})
+	outputConsumer := func(output *utxo.Output) error {
+		if err := utils.ReturnErrIfCtxDone(ctx, common.ErrOperationAborted); err != nil {
+			return err
+		}
+
+		if _, exists := outputIDs[*output.OutputID()]; exists {
+			return errors.Wrapf(ErrSnapshotVerificationFailed, "duplicate output %s", output.OutputID().ToHex())
+		}
+		outputIDs[*output.OutputID()] = struct{}{}
+
+		result.OutputsBalance += output.Deposit()
+		return nil
+	}
+
+	treasuryOutputConsumer := func(output *utxo.TreasuryOutput) error {
+		treasuryOutput = output
+		return nil
+	}
+
+	msDiffConsumer := func(msDiff *MilestoneDiff) error {
+		if err := utils.ReturnErrIfCtxDone(ctx, common.ErrOperationAborted); err != nil {
+			return err
+		}
+
+		msIndex := milestone.Index(msDiff.Milestone.Index)
+		if msIndex != nextMsDiffIndex() {
+			return errors.Wrapf(ErrSnapshotVerificationFailed, "milestone diff %d found, but expected was %d", msIndex, nextMsDiffIndex())
+		}
+		msDiffCount++
+
+		var createdBalance, consumedBalance uint64
+		for _, output := range msDiff.Created {
+			createdBalance += output.Deposit()
+		}
+		for _, spent := range msDiff.Consumed {
+			consumedBalance += spent.Deposit()
+		}
+
+		result.CreatedOutputsCount += uint64(len(msDiff.Created))
+		result.ConsumedOutputsCount += uint64(len(msDiff.Consumed))
+		result.CreatedOutputsBalance += createdBalance
+		result.ConsumedOutputsBalance += consumedBalance
+
+		// migrated funds are created out of the treasury
+		if newTreasuryOutput := msDiff.TreasuryOutput(); newTreasuryOutput != nil {
+			if msDiff.SpentTreasuryOutput == nil {
+				return errors.Wrapf(ErrSnapshotVerificationFailed, "milestone diff %d contains a receipt without a spent treasury output", msIndex)
+			}
+			createdBalance += newTreasuryOutput.Amount
+			consumedBalance += msDiff.SpentTreasuryOutput.Amount
+			result.ReceiptsCount++
+		}
+
+		if createdBalance != consumedBalance {
+			return errors.Wrapf(ErrSnapshotVerificationFailed, "milestone diff %d creates %d tokens but consumes %d", msIndex, createdBalance, consumedBalance)
+		}
+
+		return nil
+	}
+
+	if err := StreamSnapshotDataFrom(file, deSeriParas, headerConsumer, sepConsumer, outputConsumer, treasuryOutputConsumer, msDiffConsumer); err != nil {
+		return nil, err
+	}
+
+	// the reader stops after the announced amount of milestone diffs.
+	// reading the rest also authenticates the last chunk of encrypted files.
+	trailingBytes, err := io.Copy(io.Discard, file)
+	if err != nil {
+		return nil, err
+	}
+	if trailingBytes > 0 {
+		return nil, errors.Wrapf(ErrSnapshotVerificationFailed, "%d bytes of unexpected data at the end of the file", trailingBytes)
+	}
+
+	header := result.Header
+
+	// the milestone diffs have to reach the snapshot index
+	neededMsDiffCount := header.LedgerMilestoneIndex - header.SEPMilestoneIndex
+	if header.Type == Delta {
+		neededMsDiffCount = header.SEPMilestoneIndex - header.LedgerMilestoneIndex
+	}
+	if msDiffCount != neededMsDiffCount {
+		return nil, errors.Wrapf(ErrSnapshotVerificationFailed, "snapshot contains %d milestone diffs, but %d are needed between ledger index %d and snapshot index %d", msDiffCount, neededMsDiffCount, header.LedgerMilestoneIndex, header.SEPMilestoneIndex)
+	}
+
+	if header.Type == Full {
+		if total := result.OutputsBalance + treasuryOutput.Amount; total != iotago.TokenSupply {
+			return nil, errors.Wrapf(ErrSnapshotVerificationFailed, "unspent outputs (%d) and treasury (%d) sum up to %d instead of the total supply %d", result.OutputsBalance, treasuryOutput.Amount, total, iotago.TokenSupply)
+		}
+	}
+
+	return result, nil
+}
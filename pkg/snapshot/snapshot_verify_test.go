@@ -0,0 +1,150 @@
+package snapshot_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	"github.com/gohornet/hornet/pkg/snapshot"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	verifyTestNetworkID uint64 = 1337
+	verifyTestTreasury  uint64 = 1_000_000
+)
+
+func randOutputWithAmount(amount uint64) *utxo.Output {
+	return utxo.CreateOutput(utils.RandOutputID(), utils.RandMessageID(), utils.RandMilestoneIndex(), 0, utils.RandOutputOnAddressWithAmount(iotago.OutputExtended, utils.RandAddress(iotago.AddressEd25519), amount))
+}
+
+// newBalancedMsDiff creates a signed milestone diff that consumes an output and creates two outputs with the same amount of tokens.
+func newBalancedMsDiff(t *testing.T, msIndex milestone.Index, amount uint64) *snapshot.MilestoneDiff {
+	pub, prv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	var pubKey iotago.MilestonePublicKey
+	copy(pubKey[:], pub)
+
+	ms, err := iotago.NewMilestone(uint32(msIndex), 0, iotago.MilestoneParentMessageIDs{utils.RandMessageID().ToArray()}, utils.Rand32ByteHash(), []iotago.MilestonePublicKey{pubKey})
+	require.NoError(t, err)
+	require.NoError(t, ms.Sign(iotago.InMemoryEd25519MilestoneSigner(iotago.MilestonePublicKeyMapping{pubKey: prv})))
+
+	return &snapshot.MilestoneDiff{
+		Milestone: ms,
+		Created:   utxo.Outputs{randOutputWithAmount(amount / 2), randOutputWithAmount(amount - amount/2)},
+		Consumed:  utxo.Spents{utxo.NewSpent(randOutputWithAmount(amount), utils.RandTransactionID(), msIndex, 0)},
+	}
+}
+
+// writeVerifyTestSnapshot writes a full snapshot with the given milestone diffs, whose outputs sum up to the total supply.
+func writeVerifyTestSnapshot(t *testing.T, header *snapshot.FileHeader, outputAmounts []uint64, msDiffs []*snapshot.MilestoneDiff) string {
+	filePath := filepath.Join(t.TempDir(), "full_snapshot.bin")
+
+	file, err := os.Create(filePath)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	sepsCount := 2
+	sepProducer := func() (hornet.MessageID, error) {
+		if sepsCount == 0 {
+			return nil, nil
+		}
+		sepsCount--
+		return utils.RandMessageID(), nil
+	}
+
+	outputProducer := func() (*utxo.Output, error) {
+		if len(outputAmounts) == 0 {
+			return nil, nil
+		}
+		output := randOutputWithAmount(outputAmounts[0])
+		outputAmounts = outputAmounts[1:]
+		return output, nil
+	}
+
+	msDiffProducer := func() (*snapshot.MilestoneDiff, error) {
+		if len(msDiffs) == 0 {
+			return nil, nil
+		}
+		msDiff := msDiffs[0]
+		msDiffs = msDiffs[1:]
+		return msDiff, nil
+	}
+
+	_, err = snapshot.StreamSnapshotDataTo(file, 0, header, sepProducer, outputProducer, msDiffProducer)
+	require.NoError(t, err)
+
+	return filePath
+}
+
+func newVerifyTestHeader() *snapshot.FileHeader {
+	return &snapshot.FileHeader{
+		Version:              snapshot.SupportedFormatVersion,
+		Type:                 snapshot.Full,
+		NetworkID:            verifyTestNetworkID,
+		SEPMilestoneIndex:    8,
+		LedgerMilestoneIndex: 10,
+		TreasuryOutput:       &utxo.TreasuryOutput{Amount: verifyTestTreasury},
+	}
+}
+
+func TestVerifySnapshotFile(t *testing.T) {
+
+	supply := iotago.TokenSupply - verifyTestTreasury
+	filePath := writeVerifyTestSnapshot(t, newVerifyTestHeader(), []uint64{supply / 2, supply - supply/2}, []*snapshot.MilestoneDiff{
+		newBalancedMsDiff(t, 10, 1000),
+		newBalancedMsDiff(t, 9, 500),
+	})
+
+	result, err := snapshot.VerifySnapshotFile(context.Background(), filePath, nil, verifyTestNetworkID)
+	require.NoError(t, err)
+	require.Equal(t, supply, result.OutputsBalance)
+	require.Equal(t, uint64(2), result.Header.OutputCount)
+	require.Equal(t, uint64(2), result.Header.SEPCount)
+	require.Equal(t, uint64(4), result.CreatedOutputsCount)
+	require.Equal(t, uint64(2), result.ConsumedOutputsCount)
+	require.Equal(t, uint64(1500), result.CreatedOutputsBalance)
+	require.Equal(t, uint64(1500), result.ConsumedOutputsBalance)
+
+	// the network ID is only checked if it was given
+	_, err = snapshot.VerifySnapshotFile(context.Background(), filePath, nil, verifyTestNetworkID+1)
+	require.ErrorIs(t, err, snapshot.ErrSnapshotVerificationFailed)
+
+	_, err = snapshot.VerifySnapshotFile(context.Background(), filePath, nil)
+	require.NoError(t, err)
+}
+
+func TestVerifySnapshotFileInconsistent(t *testing.T) {
+
+	supply := iotago.TokenSupply - verifyTestTreasury
+
+	unbalancedMsDiff := newBalancedMsDiff(t, 9, 500)
+	unbalancedMsDiff.Created = append(unbalancedMsDiff.Created, randOutputWithAmount(1))
+
+	tests := []struct {
+		name          string
+		outputAmounts []uint64
+		msDiffs       []*snapshot.MilestoneDiff
+	}{
+		{"supply", []uint64{supply, 1}, []*snapshot.MilestoneDiff{newBalancedMsDiff(t, 10, 1000), newBalancedMsDiff(t, 9, 500)}},
+		{"missing milestone diff", []uint64{supply}, []*snapshot.MilestoneDiff{newBalancedMsDiff(t, 10, 1000)}},
+		{"milestone diff gap", []uint64{supply}, []*snapshot.MilestoneDiff{newBalancedMsDiff(t, 10, 1000), newBalancedMsDiff(t, 8, 500)}},
+		{"unbalanced milestone diff", []uint64{supply}, []*snapshot.MilestoneDiff{newBalancedMsDiff(t, 10, 1000), unbalancedMsDiff}},
+	}
+
+	for _, test := range tests {
+		filePath := writeVerifyTestSnapshot(t, newVerifyTestHeader(), test.outputAmounts, test.msDiffs)
+
+		_, err := snapshot.VerifySnapshotFile(context.Background(), filePath, nil)
+		require.ErrorIs(t, err, snapshot.ErrSnapshotVerificationFailed, test.name)
+	}
+}
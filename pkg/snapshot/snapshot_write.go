@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
 	"time"
 
 	"github.com/pkg/errors"
@@ -94,13 +95,14 @@ func newSEPsProducer(ctx context.Context, s *SnapshotManager, targetIndex milest
 }
 
 // returns a producer which produces unspent outputs which exist for the current confirmed milestone.
-func newCMIUTXOProducer(utxoManager *utxo.Manager) OutputProducerFunc {
+// the outputs are loaded from the database by the given amount of workers.
+func newCMIUTXOProducer(utxoManager *utxo.Manager, workerCount int) OutputProducerFunc {
 	prodChan := make(chan interface{})
 	errChan := make(chan error)
 
 	go func() {
-		if err := utxoManager.ForEachUnspentOutput(func(output *utxo.Output) bool {
-			prodChan <- output
+		if err := utxoManager.ForEachUnspentOutputID(func(outputID *iotago.OutputID) bool {
+			prodChan <- outputID
 			return true
 		}, utxo.ReadLockLedger(false)); err != nil {
 			errChan <- err
@@ -110,7 +112,14 @@ func newCMIUTXOProducer(utxoManager *utxo.Manager) OutputProducerFunc {
 		close(errChan)
 	}()
 
-	binder := producerFromChannels(prodChan, errChan)
+	binder := newOrderedParallelProducer(workerCount, producerFromChannels(prodChan, errChan), func(item interface{}) (interface{}, error) {
+		output, err := utxoManager.ReadOutputByOutputIDWithoutLocking(item.(*iotago.OutputID))
+		if err != nil {
+			return nil, err
+		}
+		return output, nil
+	})
+
 	return func() (*utxo.Output, error) {
 		obj, err := binder()
 		if obj == nil || err != nil {
@@ -160,7 +169,7 @@ func newMsIndexIterator(direction MsDiffDirection, ledgerIndex milestone.Index,
 
 // returns a milestone diff producer which first reads out milestone diffs from an existing delta
 // snapshot file and then the remaining diffs from the database up to the target index.
-func newMsDiffsProducerDeltaFileAndDatabase(snapshotDeltaPath string, dbStorage *storage.Storage, utxoManager *utxo.Manager, ledgerIndex milestone.Index, targetIndex milestone.Index, deSeriParas *iotago.DeSerializationParameters) (MilestoneDiffProducerFunc, error) {
+func newMsDiffsProducerDeltaFileAndDatabase(snapshotDeltaPath string, dbStorage *storage.Storage, utxoManager *utxo.Manager, ledgerIndex milestone.Index, targetIndex milestone.Index, deSeriParas *iotago.DeSerializationParameters, workerCount int) (MilestoneDiffProducerFunc, error) {
 	prevDeltaFileMsDiffsProducer, err := newMsDiffsFromPreviousDeltaSnapshot(snapshotDeltaPath, ledgerIndex, deSeriParas)
 	if err != nil {
 		return nil, err
@@ -189,7 +198,7 @@ func newMsDiffsProducerDeltaFileAndDatabase(snapshotDeltaPath string, dbStorage
 		// TODO: check whether previous snapshot already hit the target index?
 
 		prevDeltaMsDiffProducerFinished = true
-		dbMsDiffProducer = newMsDiffsProducer(mrf, utxoManager, MsDiffDirectionOnwards, prevDeltaUpToIndex, targetIndex)
+		dbMsDiffProducer = newMsDiffsProducer(mrf, utxoManager, MsDiffDirectionOnwards, prevDeltaUpToIndex, targetIndex, workerCount)
 		return dbMsDiffProducer()
 	}, nil
 }
@@ -197,7 +206,7 @@ func newMsDiffsProducerDeltaFileAndDatabase(snapshotDeltaPath string, dbStorage
 // returns a milestone diff producer which reads out the milestone diffs from an existing delta snapshot file.
 // the existing delta snapshot file is closed as soon as its milestone diffs are read.
 func newMsDiffsFromPreviousDeltaSnapshot(snapshotDeltaPath string, originLedgerIndex milestone.Index, deSeriParas *iotago.DeSerializationParameters) (MilestoneDiffProducerFunc, error) {
-	existingDeltaFile, err := os.OpenFile(snapshotDeltaPath, os.O_RDONLY, 0666)
+	existingDeltaFile, err := openSnapshotFile(snapshotDeltaPath)
 	if err != nil {
 		return nil, fmt.Errorf("unable to read previous delta snapshot file for milestone diffs: %w", err)
 	}
@@ -257,52 +266,40 @@ func MilestoneRetrieverFromStorage(dbStorage *storage.Storage) MilestoneRetrieve
 }
 
 // returns a producer which produces milestone diffs from/to with the given direction.
-func newMsDiffsProducer(mrf MilestoneRetrieverFunc, utxoManager *utxo.Manager, direction MsDiffDirection, ledgerMilestoneIndex milestone.Index, targetIndex milestone.Index) MilestoneDiffProducerFunc {
-	prodChan := make(chan interface{})
-	errChan := make(chan error)
-
-	go func() {
-		msIndexIterator := newMsIndexIterator(direction, ledgerMilestoneIndex, targetIndex)
-
-		var done bool
-		var msIndex milestone.Index
-
-		for msIndex, done = msIndexIterator(); !done; msIndex, done = msIndexIterator() {
-			diff, err := utxoManager.MilestoneDiffWithoutLocking(msIndex)
-			if err != nil {
-				errChan <- err
-				close(prodChan)
-				close(errChan)
-				return
-			}
+// the milestone diffs are loaded from the database by the given amount of workers.
+func newMsDiffsProducer(mrf MilestoneRetrieverFunc, utxoManager *utxo.Manager, direction MsDiffDirection, ledgerMilestoneIndex milestone.Index, targetIndex milestone.Index, workerCount int) MilestoneDiffProducerFunc {
+	msIndexIterator := newMsIndexIterator(direction, ledgerMilestoneIndex, targetIndex)
+
+	binder := newOrderedParallelProducer(workerCount, func() (interface{}, error) {
+		msIndex, done := msIndexIterator()
+		if done {
+			return nil, nil
+		}
+		return msIndex, nil
+	}, func(item interface{}) (interface{}, error) {
+		msIndex := item.(milestone.Index)
 
-			ms, err := mrf(msIndex)
-			if err != nil {
-				errChan <- fmt.Errorf("message for milestone with index %d could not be retrieved: %w", msIndex, err)
-				close(prodChan)
-				close(errChan)
-				return
-			}
-			if ms == nil {
-				errChan <- fmt.Errorf("message for milestone with index %d could not be retrieved", msIndex)
-				close(prodChan)
-				close(errChan)
-				return
-			}
+		diff, err := utxoManager.MilestoneDiffWithoutLocking(msIndex)
+		if err != nil {
+			return nil, err
+		}
 
-			prodChan <- &MilestoneDiff{
-				Milestone:           ms,
-				Created:             diff.Outputs,
-				Consumed:            diff.Spents,
-				SpentTreasuryOutput: diff.SpentTreasuryOutput,
-			}
+		ms, err := mrf(msIndex)
+		if err != nil {
+			return nil, fmt.Errorf("message for milestone with index %d could not be retrieved: %w", msIndex, err)
+		}
+		if ms == nil {
+			return nil, fmt.Errorf("message for milestone with index %d could not be retrieved", msIndex)
 		}
 
-		close(prodChan)
-		close(errChan)
-	}()
+		return &MilestoneDiff{
+			Milestone:           ms,
+			Created:             diff.Outputs,
+			Consumed:            diff.Spents,
+			SpentTreasuryOutput: diff.SpentTreasuryOutput,
+		}, nil
+	})
 
-	binder := producerFromChannels(prodChan, errChan)
 	return func() (*MilestoneDiff, error) {
 		obj, err := binder()
 		if obj == nil || err != nil {
@@ -358,6 +355,7 @@ func (s *SnapshotManager) readTargetMilestoneTimestamp(targetIndex milestone.Ind
 }
 
 // creates a snapshot file by streaming data from the database into a snapshot file.
+// if the snapshot state is written to the database, the snapshot is also streamed to the automatic snapshot targets.
 func (s *SnapshotManager) createSnapshotWithoutLocking(
 	ctx context.Context,
 	snapshotType Type,
@@ -366,6 +364,26 @@ func (s *SnapshotManager) createSnapshotWithoutLocking(
 	writeToDatabase bool,
 	snapshotFullPath ...string) error {
 
+	destination := &snapshotDestination{filePath: filePath}
+	if len(snapshotFullPath) > 0 {
+		destination.fullSnapshotPath = snapshotFullPath[0]
+	}
+	if writeToDatabase {
+		destination.targets = s.automaticTargets()
+		destination.targetFileName = filepath.Base(filePath)
+	}
+
+	return s.createSnapshotToDestination(ctx, snapshotType, targetIndex, destination, writeToDatabase)
+}
+
+// creates a snapshot by streaming data from the database into a snapshot file and/or to snapshot targets.
+func (s *SnapshotManager) createSnapshotToDestination(
+	ctx context.Context,
+	snapshotType Type,
+	targetIndex milestone.Index,
+	destination *snapshotDestination,
+	writeToDatabase bool) error {
+
 	s.LogInfof("creating %s snapshot for targetIndex %d", snapshotNames[snapshotType], targetIndex)
 	ts := time.Now()
 
@@ -424,15 +442,18 @@ func (s *SnapshotManager) createSnapshotWithoutLocking(
 
 		// a full snapshot contains the ledger UTXOs as of the CMI
 		// and the milestone diffs from the CMI back to the target index (excluding the target index)
-		utxoProducer = newCMIUTXOProducer(s.utxoManager)
-		milestoneDiffProducer = newMsDiffsProducer(MilestoneRetrieverFromStorage(s.storage), s.utxoManager, MsDiffDirectionBackwards, header.LedgerMilestoneIndex, targetIndex)
+		utxoProducer = newCMIUTXOProducer(s.utxoManager, s.workerCount)
+		milestoneDiffProducer = newMsDiffsProducer(MilestoneRetrieverFromStorage(s.storage), s.utxoManager, MsDiffDirectionBackwards, header.LedgerMilestoneIndex, targetIndex, s.workerCount)
 
 	case Delta:
 		// ledger index corresponds to the origin snapshot snapshot ledger.
 		// this will return an error if the full snapshot file is not available
-		header.LedgerMilestoneIndex, err = s.readSnapshotIndexFromFullSnapshotFile(snapshotFullPath...)
-		if err != nil {
-			return err
+		header.LedgerMilestoneIndex = destination.fullSnapshotIndex
+		if header.LedgerMilestoneIndex == 0 {
+			header.LedgerMilestoneIndex, err = s.readSnapshotIndexFromFullSnapshotFile(destination.fullSnapshotPath)
+			if err != nil {
+				return err
+			}
 		}
 
 		// a delta snapshot contains the milestone diffs from a full snapshot's snapshot index onwards
@@ -450,11 +471,11 @@ func (s *SnapshotManager) createSnapshotWithoutLocking(
 			fallthrough
 		case snapshotInfo.PruningIndex < header.LedgerMilestoneIndex:
 			// we have the needed milestone diffs in the database
-			milestoneDiffProducer = newMsDiffsProducer(MilestoneRetrieverFromStorage(s.storage), s.utxoManager, MsDiffDirectionOnwards, header.LedgerMilestoneIndex, targetIndex)
+			milestoneDiffProducer = newMsDiffsProducer(MilestoneRetrieverFromStorage(s.storage), s.utxoManager, MsDiffDirectionOnwards, header.LedgerMilestoneIndex, targetIndex, s.workerCount)
 		default:
 			// as the needed milestone diffs are pruned from the database, we need to use
 			// the previous delta snapshot file to extract those in conjunction with what the database has available
-			milestoneDiffProducer, err = newMsDiffsProducerDeltaFileAndDatabase(s.snapshotDeltaPath, s.storage, s.utxoManager, header.LedgerMilestoneIndex, targetIndex, s.deSeriParas)
+			milestoneDiffProducer, err = newMsDiffsProducerDeltaFileAndDatabase(s.snapshotDeltaPath, s.storage, s.utxoManager, header.LedgerMilestoneIndex, targetIndex, s.deSeriParas, s.workerCount)
 			if err != nil {
 				return err
 			}
@@ -463,26 +484,26 @@ func (s *SnapshotManager) createSnapshotWithoutLocking(
 
 	timeInit := time.Now()
 
-	snapshotFile, tempFilePath, err := utils.CreateTempFile(filePath)
+	snapshotWriter, err := s.newSnapshotWriter(ctx, destination)
 	if err != nil {
 		return err
 	}
 
-	// stream data into snapshot file
-	snapshotMetrics, err := StreamSnapshotDataTo(snapshotFile, uint64(targetMsTimestamp.Unix()), header, newSEPsProducer(ctx, s, targetIndex), utxoProducer, milestoneDiffProducer)
+	// stream data into snapshot file and targets
+	snapshotMetrics, err := StreamSnapshotDataTo(snapshotWriter, uint64(targetMsTimestamp.Unix()), header, newSEPsProducer(ctx, s, targetIndex), utxoProducer, milestoneDiffProducer, WorkerCount(s.workerCount))
 	if err != nil {
-		_ = snapshotFile.Close()
+		snapshotWriter.abort()
 		return fmt.Errorf("couldn't generate %s snapshot file: %w", snapshotNames[snapshotType], err)
 	}
 
 	timeStreamSnapshotData := time.Now()
 
-	// finalize file
-	if err := utils.CloseFileAndRename(snapshotFile, tempFilePath, filePath); err != nil {
+	// finalize file and targets
+	if err := snapshotWriter.commit(); err != nil {
 		return err
 	}
 
-	if (snapshotType == Full) && (filePath == s.snapshotFullPath) {
+	if (snapshotType == Full) && (destination.filePath == s.snapshotFullPath) {
 		// if the old full snapshot file is overwritten
 		// we need to remove the old delta snapshot file since it
 		// isn't compatible to the full snapshot file anymore.
@@ -582,7 +603,7 @@ func createSnapshotFromCurrentStorageState(dbStorage *storage.Storage, filePath
 
 	// create a prepped output producer which counts how many went through
 	unspentOutputsCount := 0
-	cmiUTXOProducer := newCMIUTXOProducer(dbStorage.UTXOManager())
+	cmiUTXOProducer := newCMIUTXOProducer(dbStorage.UTXOManager(), runtime.NumCPU())
 	countingOutputProducer := func() (*utxo.Output, error) {
 		output, err := cmiUTXOProducer()
 		if output != nil {
@@ -608,7 +629,8 @@ func createSnapshotFromCurrentStorageState(dbStorage *storage.Storage, filePath
 		snapshotFileHeader,
 		sepProducer,
 		countingOutputProducer,
-		milestoneDiffProducer); err != nil {
+		milestoneDiffProducer,
+		WorkerCount(runtime.NumCPU())); err != nil {
 		_ = snapshotFile.Close()
 		return nil, fmt.Errorf("couldn't generate %s snapshot file: %w", snapshotNames[Full], err)
 	}
@@ -0,0 +1,354 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/utils"
+)
+
+// TargetWriter receives the data of a snapshot that is streamed to a target.
+// Seek is only used to update the counters in the header at the beginning of the snapshot.
+type TargetWriter interface {
+	io.WriteSeeker
+	// Commit finishes the snapshot after all data was written.
+	Commit() error
+	// Abort discards the snapshot.
+	Abort() error
+}
+
+// Target is a sink snapshots are streamed to instead of, or in addition to, a local file, e.g. an object storage.
+type Target interface {
+	// Name returns the name of the target that is used to select it.
+	Name() string
+	// Create returns a writer for the snapshot with the given file name.
+	Create(ctx context.Context, fileName string) (TargetWriter, error)
+}
+
+// registeredTarget is a target added to the snapshot manager.
+type registeredTarget struct {
+	Target
+	// whether the snapshots created automatically by the node are streamed to the target.
+	automatic bool
+}
+
+// AddTarget adds a target snapshots can be streamed to.
+// If automatic is set, the snapshots created automatically by the node are also streamed to the target.
+func (s *SnapshotManager) AddTarget(target Target, automatic bool) error {
+	s.targetsLock.Lock()
+	defer s.targetsLock.Unlock()
+
+	if s.targets == nil {
+		s.targets = make(map[string]*registeredTarget)
+	}
+
+	if _, exists := s.targets[target.Name()]; exists {
+		return fmt.Errorf("snapshot target %s added twice", target.Name())
+	}
+	s.targets[target.Name()] = &registeredTarget{Target: target, automatic: automatic}
+
+	return nil
+}
+
+// TargetNames returns the names of the added snapshot targets.
+func (s *SnapshotManager) TargetNames() []string {
+	s.targetsLock.RLock()
+	defer s.targetsLock.RUnlock()
+
+	names := make([]string, 0, len(s.targets))
+	for name := range s.targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// target returns the snapshot target with the given name.
+func (s *SnapshotManager) target(name string) (Target, error) {
+	s.targetsLock.RLock()
+	defer s.targetsLock.RUnlock()
+
+	target, exists := s.targets[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown snapshot target: %s", name)
+	}
+
+	return target, nil
+}
+
+// automaticTargets returns the targets the snapshots created automatically by the node are streamed to.
+func (s *SnapshotManager) automaticTargets() []Target {
+	s.targetsLock.RLock()
+	defer s.targetsLock.RUnlock()
+
+	var targets []Target
+	for _, target := range s.targets {
+		if target.automatic {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
+
+// snapshotDestination defines where a created snapshot is written to.
+type snapshotDestination struct {
+	// the path of the local snapshot file, empty if the snapshot is only streamed to targets.
+	filePath string
+	// the targets the snapshot is streamed to.
+	targets []Target
+	// the file name of the snapshot in the targets.
+	targetFileName string
+	// the path of the full snapshot file a delta snapshot is based on (empty = the full snapshot file of the node).
+	fullSnapshotPath string
+	// the index of the full snapshot a delta snapshot is based on, if the full snapshot was only streamed to a target.
+	fullSnapshotIndex milestone.Index
+}
+
+// targetWriter is a writer of a named target.
+type targetWriter struct {
+	name string
+	TargetWriter
+}
+
+// snapshotWriter streams a snapshot into a local file and to the snapshot targets.
+// If a local file is written, a failing target is logged and skipped instead of failing the snapshot,
+// so the node keeps its own snapshot files even if an object storage is unavailable.
+type snapshotWriter struct {
+	*utils.WrappedLogger
+
+	file         *os.File
+	tempFilePath string
+	filePath     string
+	targets      []*targetWriter
+
+	// the passphrase the snapshot is encrypted with, empty if the snapshot is not encrypted.
+	encryptionKey string
+	// the unencrypted snapshot, which is encrypted into the local file and the targets on commit.
+	// the header of the snapshot is updated at the end, so the data can't be encrypted while it is streamed.
+	plainFile *os.File
+}
+
+// newSnapshotWriter opens the local file and the writers of the targets of the destination.
+func (s *SnapshotManager) newSnapshotWriter(ctx context.Context, destination *snapshotDestination) (*snapshotWriter, error) {
+
+	writer := &snapshotWriter{
+		WrappedLogger: s.WrappedLogger,
+		filePath:      destination.filePath,
+		encryptionKey: s.encryptionKey,
+	}
+
+	for _, target := range destination.targets {
+		created, err := target.Create(ctx, destination.targetFileName)
+		if err != nil {
+			if destination.filePath == "" {
+				writer.abort()
+				return nil, fmt.Errorf("creating snapshot in target %s failed: %w", target.Name(), err)
+			}
+			s.LogWarnf("creating snapshot in target %s failed: %s", target.Name(), err)
+			continue
+		}
+		writer.targets = append(writer.targets, &targetWriter{name: target.Name(), TargetWriter: created})
+	}
+
+	if destination.filePath != "" {
+		file, tempFilePath, err := utils.CreateTempFile(destination.filePath)
+		if err != nil {
+			writer.abort()
+			return nil, err
+		}
+		writer.file = file
+		writer.tempFilePath = tempFilePath
+	}
+
+	if writer.encryptionKey != "" {
+		dir := ""
+		if destination.filePath != "" {
+			dir = filepath.Dir(destination.filePath)
+		}
+
+		plainFile, err := os.CreateTemp(dir, "snapshot_plain_*.tmp")
+		if err != nil {
+			writer.abort()
+			return nil, fmt.Errorf("unable to create temp file: %w", err)
+		}
+		writer.plainFile = plainFile
+	}
+
+	return writer, nil
+}
+
+// targetFailed handles the error of a target. It returns the error if the snapshot is only streamed to targets,
+// otherwise the target is aborted and skipped.
+func (w *snapshotWriter) targetFailed(target *targetWriter, err error) error {
+	if w.filePath == "" {
+		return fmt.Errorf("streaming snapshot to target %s failed: %w", target.name, err)
+	}
+
+	w.LogWarnf("streaming snapshot to target %s failed: %s", target.name, err)
+	if err := target.Abort(); err != nil {
+		w.LogWarnf("aborting snapshot in target %s failed: %s", target.name, err)
+	}
+
+	for i, t := range w.targets {
+		if t == target {
+			w.targets = append(w.targets[:i], w.targets[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// forEachTarget calls the function for every target and handles the errors.
+func (w *snapshotWriter) forEachTarget(f func(target *targetWriter) error) error {
+	// copy the slice, failed targets are removed while iterating
+	for _, target := range append([]*targetWriter{}, w.targets...) {
+		if err := f(target); err != nil {
+			if err := w.targetFailed(target, err); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *snapshotWriter) Write(p []byte) (int, error) {
+	if w.plainFile != nil {
+		return w.plainFile.Write(p)
+	}
+
+	if w.file != nil {
+		if _, err := w.file.Write(p); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := w.forEachTarget(func(target *targetWriter) error {
+		_, err := target.Write(p)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (w *snapshotWriter) Seek(offset int64, whence int) (int64, error) {
+	if w.plainFile != nil {
+		return w.plainFile.Seek(offset, whence)
+	}
+
+	var position int64
+
+	if w.file != nil {
+		var err error
+		if position, err = w.file.Seek(offset, whence); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := w.forEachTarget(func(target *targetWriter) error {
+		var err error
+		position, err = target.Seek(offset, whence)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	return position, nil
+}
+
+// encrypt encrypts the unencrypted snapshot into the local file and the targets.
+func (w *snapshotWriter) encrypt() error {
+	plainFile := w.plainFile
+	defer func() {
+		_ = plainFile.Close()
+		_ = os.Remove(plainFile.Name())
+	}()
+
+	if _, err := plainFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	// from now on the data is written to the local file and the targets
+	w.plainFile = nil
+
+	encryptingWriter, err := newEncryptingWriter(w, w.encryptionKey)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(encryptingWriter, plainFile); err != nil {
+		return fmt.Errorf("encrypting snapshot failed: %w", err)
+	}
+
+	if err := encryptingWriter.Close(); err != nil {
+		return fmt.Errorf("encrypting snapshot failed: %w", err)
+	}
+
+	return nil
+}
+
+// commit finishes the local file and the snapshots in the targets.
+func (w *snapshotWriter) commit() error {
+	if w.plainFile != nil {
+		if err := w.encrypt(); err != nil {
+			w.abort()
+			return err
+		}
+	}
+
+	if w.file != nil {
+		if err := utils.CloseFileAndRename(w.file, w.tempFilePath, w.filePath); err != nil {
+			_ = os.Remove(w.tempFilePath)
+			w.file = nil
+			w.abort()
+			return err
+		}
+		w.file = nil
+	}
+
+	for i, target := range w.targets {
+		if err := target.Commit(); err != nil {
+			if w.filePath == "" {
+				// the snapshot was only streamed to targets, so it failed
+				w.targets = w.targets[i+1:]
+				w.abort()
+				return fmt.Errorf("finishing snapshot in target %s failed: %w", target.name, err)
+			}
+			w.LogWarnf("finishing snapshot in target %s failed: %s", target.name, err)
+			continue
+		}
+		w.LogInfof("streamed snapshot to target %s", target.name)
+	}
+
+	return nil
+}
+
+// abort discards the local file and the snapshots in the targets.
+func (w *snapshotWriter) abort() {
+	if w.plainFile != nil {
+		_ = w.plainFile.Close()
+		_ = os.Remove(w.plainFile.Name())
+		w.plainFile = nil
+	}
+
+	if w.file != nil {
+		_ = w.file.Close()
+		// we don't need to check the error, maybe the file doesn't exist
+		_ = os.Remove(w.tempFilePath)
+	}
+
+	for _, target := range w.targets {
+		if err := target.Abort(); err != nil {
+			w.LogWarnf("aborting snapshot in target %s failed: %s", target.name, err)
+		}
+	}
+}
@@ -0,0 +1,472 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+)
+
+const (
+	// the minimum size of a part of a multipart upload, except for the last part.
+	s3MinPartSize = 5 * 1024 * 1024
+	// the maximum amount of parts of a multipart upload.
+	s3MaxParts = 10000
+	// the time format of the signature.
+	s3TimeFormat = "20060102T150405Z"
+	// the date format of the credential scope.
+	s3DateFormat = "20060102"
+	// the default size of a part of a multipart upload.
+	s3DefaultPartSize = 32 * 1024 * 1024
+	// the default amount of retries of a failed request.
+	s3DefaultMaxRetries = 3
+)
+
+var (
+	// ErrS3SeekNotSupported is returned if the snapshot seeks to data that was already uploaded.
+	ErrS3SeekNotSupported = errors.New("seeking to data that was already uploaded is not supported")
+)
+
+// S3TargetConfig is the config of a snapshot target that uploads the snapshots to an S3-compatible object storage.
+type S3TargetConfig struct {
+	// The name of the target.
+	Name string `json:"name" koanf:"name"`
+	// The URL of the S3 endpoint, e.g. "https://s3.eu-central-1.amazonaws.com".
+	Endpoint string `json:"endpoint" koanf:"endpoint"`
+	// The region of the bucket.
+	Region string `json:"region" koanf:"region"`
+	// The bucket the snapshots are uploaded to.
+	Bucket string `json:"bucket" koanf:"bucket"`
+	// The prefix of the object keys, e.g. "snapshots/mainnet/".
+	Prefix string `json:"prefix" koanf:"prefix"`
+	// The access key ID, read from the environment variable AWS_ACCESS_KEY_ID if empty.
+	AccessKeyID string `json:"accessKeyID" koanf:"accessKeyID"`
+	// The secret access key, read from the environment variable AWS_SECRET_ACCESS_KEY if empty.
+	SecretAccessKey string `json:"secretAccessKey" koanf:"secretAccessKey"`
+	// The size of the parts of the multipart upload, e.g. "32MB". Two parts are held in memory while uploading.
+	PartSize string `json:"partSize" koanf:"partSize"`
+	// The amount of retries of a failed request (0 = default).
+	MaxRetries int `json:"maxRetries" koanf:"maxRetries"`
+	// Whether the snapshots created automatically by the node are also uploaded.
+	Automatic bool `json:"automatic" koanf:"automatic"`
+}
+
+// S3Target uploads snapshots to an S3-compatible object storage with multipart uploads.
+type S3Target struct {
+	name            string
+	endpoint        *url.URL
+	region          string
+	bucket          string
+	prefix          string
+	accessKeyID     string
+	secretAccessKey string
+	partSize        int64
+	maxRetries      int
+	client          *http.Client
+}
+
+// NewS3Target creates a new S3 snapshot target.
+func NewS3Target(config *S3TargetConfig) (*S3Target, error) {
+
+	if config.Name == "" {
+		return nil, errors.New("name of the S3 snapshot target is missing")
+	}
+
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint of the S3 snapshot target %s: %s", config.Name, config.Endpoint)
+	}
+
+	if config.Region == "" || config.Bucket == "" {
+		return nil, fmt.Errorf("region and bucket of the S3 snapshot target %s are required", config.Name)
+	}
+
+	partSize := int64(s3DefaultPartSize)
+	if config.PartSize != "" {
+		parsedPartSize, err := humanize.ParseBytes(config.PartSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid part size of the S3 snapshot target %s: %w", config.Name, err)
+		}
+		partSize = int64(parsedPartSize)
+	}
+	if partSize < s3MinPartSize {
+		return nil, fmt.Errorf("part size of the S3 snapshot target %s has to be at least %s", config.Name, humanize.IBytes(s3MinPartSize))
+	}
+
+	maxRetries := config.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = s3DefaultMaxRetries
+	}
+
+	accessKeyID := config.AccessKeyID
+	if accessKeyID == "" {
+		accessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	secretAccessKey := config.SecretAccessKey
+	if secretAccessKey == "" {
+		secretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, fmt.Errorf("credentials of the S3 snapshot target %s are missing", config.Name)
+	}
+
+	return &S3Target{
+		name:            config.Name,
+		endpoint:        endpoint,
+		region:          config.Region,
+		bucket:          config.Bucket,
+		prefix:          config.Prefix,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		partSize:        partSize,
+		maxRetries:      maxRetries,
+		client:          &http.Client{},
+	}, nil
+}
+
+// Name returns the name of the target.
+func (t *S3Target) Name() string {
+	return t.name
+}
+
+// Create starts a multipart upload of the snapshot with the given file name.
+func (t *S3Target) Create(ctx context.Context, fileName string) (TargetWriter, error) {
+
+	return &s3Writer{
+		ctx:    ctx,
+		target: t,
+		key:    t.prefix + fileName,
+		first:  make([]byte, 0, t.partSize),
+	}, nil
+}
+
+// objectURL returns the path-style URL of the object with the given key.
+func (t *S3Target) objectURL(key string, query url.Values) *url.URL {
+	objectURL := *t.endpoint
+	objectURL.Path = strings.TrimSuffix(objectURL.Path, "/") + "/" + t.bucket + "/" + key
+	objectURL.RawQuery = query.Encode()
+	return &objectURL
+}
+
+// s3Escape escapes a string like the canonical request of the signature version 4 requires.
+func s3Escape(s string, escapeSlash bool) string {
+	var escaped strings.Builder
+	for _, b := range []byte(s) {
+		switch {
+		case (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') || b == '-' || b == '_' || b == '.' || b == '~':
+			escaped.WriteByte(b)
+		case b == '/' && !escapeSlash:
+			escaped.WriteByte(b)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// sign adds the signature version 4 of AWS to the request.
+func (t *S3Target) sign(req *http.Request, payload []byte, now time.Time) {
+
+	payloadHash := sha256.Sum256(payload)
+	payloadHashHex := hex.EncodeToString(payloadHash[:])
+	amzDate := now.UTC().Format(s3TimeFormat)
+	date := now.UTC().Format(s3DateFormat)
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHashHex)
+
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	canonicalQuery := make([]string, 0, len(keys))
+	for _, key := range keys {
+		canonicalQuery = append(canonicalQuery, s3Escape(key, true)+"="+s3Escape(query.Get(key), true))
+	}
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3Escape(req.URL.Path, false),
+		strings.Join(canonicalQuery, "&"),
+		"host:" + req.URL.Host + "\n" + "x-amz-content-sha256:" + payloadHashHex + "\n" + "x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHashHex,
+	}, "\n")
+
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	scope := date + "/" + t.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalRequestHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+t.secretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, t.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		t.accessKeyID, scope, signedHeaders, hex.EncodeToString(hmacSHA256(signingKey, stringToSign))))
+}
+
+// s3Error is the error response of S3.
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// do sends a signed request and retries it if it fails. It returns the response headers and body.
+func (t *S3Target) do(ctx context.Context, method string, key string, query url.Values, payload []byte) (http.Header, []byte, error) {
+
+	var lastErr error
+	for attempt := 0; attempt <= t.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, nil, ErrSnapshotCreationWasAborted
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+
+		header, body, err := t.doOnce(ctx, method, key, query, payload)
+		if err == nil {
+			return header, body, nil
+		}
+		if ctx.Err() != nil {
+			return nil, nil, ErrSnapshotCreationWasAborted
+		}
+		lastErr = err
+	}
+
+	return nil, nil, lastErr
+}
+
+func (t *S3Target) doOnce(ctx context.Context, method string, key string, query url.Values, payload []byte) (http.Header, []byte, error) {
+
+	req, err := http.NewRequestWithContext(ctx, method, t.objectURL(key, query).String(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, nil, err
+	}
+	t.sign(req, payload, time.Now())
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// S3 may return an error in the body of a successful response to complete a multipart upload
+	s3Err := &s3Error{}
+	if res.StatusCode < 200 || res.StatusCode >= 300 || (xml.Unmarshal(body, s3Err) == nil && s3Err.Code != "") {
+		if s3Err.Code != "" {
+			return nil, nil, fmt.Errorf("%s %s failed: %s (%s)", method, key, s3Err.Code, s3Err.Message)
+		}
+		return nil, nil, fmt.Errorf("%s %s failed: %s", method, key, res.Status)
+	}
+
+	return res.Header, body, nil
+}
+
+// s3CompletedPart is a part of a multipart upload.
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// s3Writer streams a snapshot to S3 with a multipart upload.
+// The first part is kept in memory until the snapshot is committed, so the header can still be updated.
+type s3Writer struct {
+	ctx    context.Context
+	target *S3Target
+	key    string
+
+	uploadID string
+	parts    []s3CompletedPart
+
+	// the first part, uploaded on commit.
+	first []byte
+	// the data of the current part after the first part.
+	current []byte
+	// the amount of written bytes.
+	size int64
+	// the current write position.
+	position int64
+}
+
+// startUpload starts the multipart upload if it wasn't started yet.
+func (w *s3Writer) startUpload() error {
+	if w.uploadID != "" {
+		return nil
+	}
+
+	_, body, err := w.target.do(w.ctx, http.MethodPost, w.key, url.Values{"uploads": {""}}, nil)
+	if err != nil {
+		return err
+	}
+
+	result := &struct {
+		UploadID string `xml:"UploadId"`
+	}{}
+	if err := xml.Unmarshal(body, result); err != nil || result.UploadID == "" {
+		return fmt.Errorf("invalid response to start the multipart upload of %s", w.key)
+	}
+	w.uploadID = result.UploadID
+
+	return nil
+}
+
+// uploadPart uploads a part of the multipart upload.
+func (w *s3Writer) uploadPart(partNumber int, data []byte) error {
+	if partNumber > s3MaxParts {
+		return fmt.Errorf("snapshot exceeds the maximum of %d parts, increase the part size", s3MaxParts)
+	}
+
+	if err := w.startUpload(); err != nil {
+		return err
+	}
+
+	header, _, err := w.target.do(w.ctx, http.MethodPut, w.key, url.Values{
+		"partNumber": {fmt.Sprint(partNumber)},
+		"uploadId":   {w.uploadID},
+	}, data)
+	if err != nil {
+		return err
+	}
+
+	w.parts = append(w.parts, s3CompletedPart{PartNumber: partNumber, ETag: header.Get("ETag")})
+	return nil
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+
+	if w.position < w.size {
+		// overwrite data of the first part, e.g. the header
+		if w.position+int64(len(p)) > int64(len(w.first)) {
+			return 0, ErrS3SeekNotSupported
+		}
+		copy(w.first[w.position:], p)
+		w.position += int64(len(p))
+		return len(p), nil
+	}
+
+	written := len(p)
+
+	if free := cap(w.first) - len(w.first); free > 0 {
+		n := free
+		if n > len(p) {
+			n = len(p)
+		}
+		w.first = append(w.first, p[:n]...)
+		p = p[n:]
+	}
+
+	w.current = append(w.current, p...)
+	for int64(len(w.current)) >= w.target.partSize {
+		// the first part is uploaded on commit
+		if err := w.uploadPart(len(w.parts)+2, w.current[:w.target.partSize]); err != nil {
+			return 0, err
+		}
+		w.current = append(w.current[:0], w.current[w.target.partSize:]...)
+	}
+
+	w.size += int64(written)
+	w.position = w.size
+
+	return written, nil
+}
+
+func (w *s3Writer) Seek(offset int64, whence int) (int64, error) {
+	var position int64
+	switch whence {
+	case io.SeekStart:
+		position = offset
+	case io.SeekCurrent:
+		position = w.position + offset
+	case io.SeekEnd:
+		position = w.size + offset
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+
+	if position < 0 || position > w.size || (position < w.size && position >= int64(len(w.first))) {
+		return 0, ErrS3SeekNotSupported
+	}
+
+	w.position = position
+	return position, nil
+}
+
+// Commit uploads the remaining data and the first part and completes the multipart upload.
+func (w *s3Writer) Commit() error {
+
+	if len(w.current) > 0 {
+		if err := w.uploadPart(len(w.parts)+2, w.current); err != nil {
+			return err
+		}
+	}
+
+	if err := w.uploadPart(1, w.first); err != nil {
+		return err
+	}
+
+	sort.Slice(w.parts, func(i, j int) bool {
+		return w.parts[i].PartNumber < w.parts[j].PartNumber
+	})
+
+	completeRequest, err := xml.Marshal(&struct {
+		XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+		Parts   []s3CompletedPart `xml:"Part"`
+	}{Parts: w.parts})
+	if err != nil {
+		return err
+	}
+
+	if _, _, err := w.target.do(w.ctx, http.MethodPost, w.key, url.Values{"uploadId": {w.uploadID}}, completeRequest); err != nil {
+		return err
+	}
+
+	w.uploadID = ""
+	return nil
+}
+
+// Abort aborts the multipart upload, so the uploaded parts are deleted.
+func (w *s3Writer) Abort() error {
+	if w.uploadID == "" {
+		return nil
+	}
+
+	// the snapshot creation might have been aborted, the upload is aborted anyway
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, _, err := w.target.do(ctx, http.MethodDelete, w.key, url.Values{"uploadId": {w.uploadID}}, nil)
+	w.uploadID = ""
+
+	return err
+}
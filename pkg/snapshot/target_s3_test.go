@@ -0,0 +1,181 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+)
+
+// fakeS3 is a minimal S3 server that supports multipart uploads.
+type fakeS3 struct {
+	sync.Mutex
+
+	// the objects by key.
+	objects map[string][]byte
+	// the parts of the running uploads by upload ID and part number.
+	uploads map[string]map[int][]byte
+	// the amount of part uploads which fail before a part upload succeeds.
+	failParts int
+	aborted   int
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	s3 := &fakeS3{
+		objects: make(map[string][]byte),
+		uploads: make(map[string]map[int][]byte),
+	}
+
+	ts := httptest.NewServer(s3)
+	t.Cleanup(ts.Close)
+
+	return s3, ts
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	query := r.URL.Query()
+	body, _ := ioutil.ReadAll(r.Body)
+
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		uploadID := fmt.Sprintf("upload-%d", len(s.uploads))
+		s.uploads[uploadID] = make(map[int][]byte)
+		_, _ = fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", uploadID)
+
+	case r.Method == http.MethodPut:
+		if s.failParts > 0 {
+			s.failParts--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		s.uploads[query.Get("uploadId")][partNumber] = body
+		w.Header().Set("ETag", fmt.Sprintf("\"etag-%d\"", partNumber))
+
+	case r.Method == http.MethodPost:
+		parts := s.uploads[query.Get("uploadId")]
+		partNumbers := make([]int, 0, len(parts))
+		for partNumber := range parts {
+			partNumbers = append(partNumbers, partNumber)
+		}
+		sort.Ints(partNumbers)
+
+		var object []byte
+		for _, partNumber := range partNumbers {
+			object = append(object, parts[partNumber]...)
+		}
+		s.objects[key] = object
+		delete(s.uploads, query.Get("uploadId"))
+
+	case r.Method == http.MethodDelete:
+		s.aborted++
+		delete(s.uploads, query.Get("uploadId"))
+
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func newTestS3Target(t *testing.T, endpoint string) *S3Target {
+	target, err := NewS3Target(&S3TargetConfig{
+		Name:            "test",
+		Endpoint:        endpoint,
+		Region:          "eu-central-1",
+		Bucket:          "bucket",
+		Prefix:          "snapshots/",
+		AccessKeyID:     "access",
+		SecretAccessKey: "secret",
+		PartSize:        "5MiB",
+	})
+	require.NoError(t, err)
+	return target
+}
+
+func TestS3TargetMultipartUpload(t *testing.T) {
+
+	s3, ts := newFakeS3(t)
+	s3.failParts = 1
+
+	target := newTestS3Target(t, ts.URL)
+	writer, err := target.Create(context.Background(), "full_snapshot.bin")
+	require.NoError(t, err)
+
+	data := utils.RandBytes(12 * 1024 * 1024)
+	for chunk := bytes.NewReader(data); ; {
+		buf := make([]byte, 100*1024)
+		n, err := chunk.Read(buf)
+		if err == io.EOF {
+			break
+		}
+		_, err = writer.Write(buf[:n])
+		require.NoError(t, err)
+	}
+
+	// seeking to data that was already uploaded is not possible
+	_, err = writer.Seek(int64(len(data)-1), io.SeekStart)
+	require.ErrorIs(t, err, ErrS3SeekNotSupported)
+
+	// the header in the first part can be updated
+	_, err = writer.Seek(10, io.SeekStart)
+	require.NoError(t, err)
+	_, err = writer.Write([]byte{1, 2, 3, 4})
+	require.NoError(t, err)
+	copy(data[10:], []byte{1, 2, 3, 4})
+
+	require.NoError(t, writer.Commit())
+	require.Equal(t, data, s3.objects["snapshots/full_snapshot.bin"])
+	require.Empty(t, s3.uploads)
+}
+
+func TestS3TargetAbort(t *testing.T) {
+
+	s3, ts := newFakeS3(t)
+
+	target := newTestS3Target(t, ts.URL)
+	writer, err := target.Create(context.Background(), "delta_snapshot.bin")
+	require.NoError(t, err)
+
+	// more than two parts, so the upload was started
+	_, err = writer.Write(utils.RandBytes(11 * 1024 * 1024))
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Abort())
+	require.Equal(t, 1, s3.aborted)
+	require.Empty(t, s3.uploads)
+	require.Empty(t, s3.objects)
+}
+
+func TestS3TargetConfig(t *testing.T) {
+
+	_, err := NewS3Target(&S3TargetConfig{Name: "test", Endpoint: "https://s3.example.com", Region: "eu", Bucket: "bucket", AccessKeyID: "a", SecretAccessKey: "s", PartSize: "1MB"})
+	require.Error(t, err)
+
+	_, err = NewS3Target(&S3TargetConfig{Name: "test", Endpoint: "https://s3.example.com", Bucket: "bucket", AccessKeyID: "a", SecretAccessKey: "s"})
+	require.Error(t, err)
+
+	target, err := NewS3Target(&S3TargetConfig{Name: "test", Endpoint: "https://s3.example.com", Region: "eu", Bucket: "bucket", AccessKeyID: "a", SecretAccessKey: "s"})
+	require.NoError(t, err)
+	require.Equal(t, int64(s3DefaultPartSize), target.partSize)
+	require.Equal(t, s3DefaultMaxRetries, target.maxRetries)
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/gohornet/hornet/pkg/metrics"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/storage"
@@ -31,9 +33,12 @@ type Spammer struct {
 	powHandler      *pow.Handler
 	sendMessageFunc SendMessageFunc
 	serverMetrics   *metrics.ServerMetrics
+	// the optional wallet to issue value transactions instead of tagged data messages.
+	valueSpamWallet *ValueSpamWallet
 }
 
 // New creates a new spammer instance.
+// If a ValueSpamWallet is given, the spammer issues value transactions as long as the wallet has unspent outputs available.
 func New(networkID uint64,
 	deSeriParas *iotago.DeSerializationParameters,
 	message string,
@@ -42,7 +47,8 @@ func New(networkID uint64,
 	tipselFunc SpammerTipselFunc,
 	powHandler *pow.Handler,
 	sendMessageFunc SendMessageFunc,
-	serverMetrics *metrics.ServerMetrics) *Spammer {
+	serverMetrics *metrics.ServerMetrics,
+	valueSpamWallet *ValueSpamWallet) *Spammer {
 
 	return &Spammer{
 		networkID:       networkID,
@@ -54,6 +60,7 @@ func New(networkID uint64,
 		powHandler:      powHandler,
 		sendMessageFunc: sendMessageFunc,
 		serverMetrics:   serverMetrics,
+		valueSpamWallet: valueSpamWallet,
 	}
 }
 
@@ -84,30 +91,79 @@ func (s *Spammer) DoSpam(ctx context.Context) (time.Duration, time.Duration, err
 	messageString += fmt.Sprintf("\nTimestamp: %s", now.Format(time.RFC3339))
 	messageString += fmt.Sprintf("\nTipselection: %v", durationGTTA.Truncate(time.Microsecond))
 
+	var payload iotago.Payload = &iotago.TaggedData{Tag: tagBytes, Data: []byte(messageString)}
+
+	var valueTx *valueSpamTransaction
+	if s.valueSpamWallet != nil {
+		valueTx, err = s.valueSpamWallet.newTransaction(tagBytes, []byte(messageString))
+		if err != nil && !errors.Is(err, ErrNoValueSpamOutputs) {
+			return time.Duration(0), time.Duration(0), err
+		}
+
+		// all outputs of the wallet are used by other transactions at the moment => send a tagged data message instead
+		if valueTx != nil {
+			payload = valueTx.transaction
+			tips = valueTx.parents(tips)
+		}
+	}
+
+	msg, durationPOW, err := s.createMessage(ctx, payload, tips, valueTx)
+	if err != nil {
+		if valueTx != nil {
+			s.valueSpamWallet.release(valueTx)
+		}
+		return time.Duration(0), time.Duration(0), err
+	}
+
+	if valueTx != nil {
+		// the outputs are booked before the message is sent, so they are known if the message gets confirmed
+		if err := s.valueSpamWallet.book(valueTx, msg.MessageID()); err != nil {
+			s.valueSpamWallet.release(valueTx)
+			return time.Duration(0), time.Duration(0), err
+		}
+	}
+
+	if err := s.sendMessageFunc(msg); err != nil {
+		if valueTx != nil {
+			s.valueSpamWallet.unbook(valueTx, msg.MessageID())
+		}
+		return time.Duration(0), time.Duration(0), err
+	}
+
+	return durationGTTA, durationPOW, nil
+}
+
+// createMessage creates the message with the given payload and does the PoW.
+// The messages that created the inputs of a value transaction stay parents of the message if the tips are refreshed.
+func (s *Spammer) createMessage(ctx context.Context, payload iotago.Payload, tips hornet.MessageIDs, valueTx *valueSpamTransaction) (*storage.Message, time.Duration, error) {
+
 	iotaMsg := &iotago.Message{
 		NetworkID: s.networkID,
 		Parents:   tips.ToSliceOfArrays(),
-		Payload:   &iotago.TaggedData{Tag: tagBytes, Data: []byte(messageString)},
+		Payload:   payload,
 	}
 
-	timeStart = time.Now()
+	timeStart := time.Now()
 	if err := s.powHandler.DoPoW(ctx, iotaMsg, 1, func() (tips hornet.MessageIDs, err error) {
 		// refresh tips of the spammer if PoW takes longer than a configured duration.
 		_, refreshedTips, err := s.tipselFunc()
-		return refreshedTips, err
+		if err != nil {
+			return nil, err
+		}
+
+		if valueTx != nil {
+			return valueTx.parents(refreshedTips), nil
+		}
+		return refreshedTips, nil
 	}); err != nil {
-		return time.Duration(0), time.Duration(0), err
+		return nil, time.Duration(0), err
 	}
 	durationPOW := time.Since(timeStart)
 
 	msg, err := storage.NewMessage(iotaMsg, serializer.DeSeriModePerformValidation, s.deSeriParas)
 	if err != nil {
-		return time.Duration(0), time.Duration(0), err
-	}
-
-	if err := s.sendMessageFunc(msg); err != nil {
-		return time.Duration(0), time.Duration(0), err
+		return nil, time.Duration(0), err
 	}
 
-	return durationGTTA, durationPOW, nil
+	return msg, durationPOW, nil
 }
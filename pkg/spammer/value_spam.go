@@ -0,0 +1,412 @@
+package spammer
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/wollac/iota-crypto-demo/pkg/bip32path"
+	"github.com/wollac/iota-crypto-demo/pkg/slip10"
+
+	"github.com/gohornet/hornet/pkg/indexer"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/syncmanager"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/builder"
+)
+
+const (
+	// the BIP32 path of the addresses of the value spam wallet, the last element is the address index.
+	valueSpamAddressPath = "44'/4218'/0'/0'/%d'"
+	// the amount of outputs the funds of the wallet are split into,
+	// afterwards every transaction consumes as many outputs as it creates.
+	valueSpamMaxWalletOutputs = 1000
+	// the maximum amount of unconfirmed messages a value transaction is chained to,
+	// the remaining parents of the message are filled with tips.
+	valueSpamMaxChainedMessages = iotago.MaxParentsInAMessage / 2
+)
+
+var (
+	// ErrNoValueSpamOutputs is returned if the wallet has no unspent outputs left to issue a value transaction.
+	ErrNoValueSpamOutputs = errors.New("no unspent outputs available for value spam")
+)
+
+// valueSpamOutput is an unspent output of the value spam wallet.
+type valueSpamOutput struct {
+	*utxo.Output
+	address *iotago.Ed25519Address
+}
+
+// valueSpamTransaction is a value transaction of the wallet that was not booked yet.
+type valueSpamTransaction struct {
+	transaction *iotago.Transaction
+	inputs      []*valueSpamOutput
+	addresses   []*iotago.Ed25519Address
+	// the unconfirmed messages which created the inputs, they need to be referenced by the message of the transaction.
+	chainedMessageIDs hornet.MessageIDs
+	// the generation of the wallet the inputs were taken from.
+	generation uint64
+}
+
+// parents returns the parents for the message of the transaction.
+// The unconfirmed messages which created the inputs are always referenced, so the transaction is applied after them.
+func (tx *valueSpamTransaction) parents(tips hornet.MessageIDs) hornet.MessageIDs {
+	parents := make(hornet.MessageIDs, 0, iotago.MaxParentsInAMessage)
+	parents = append(parents, tx.chainedMessageIDs...)
+	for _, tip := range tips {
+		if len(parents) >= iotago.MaxParentsInAMessage {
+			break
+		}
+		parents = append(parents, tip)
+	}
+
+	return parents.RemoveDupsAndSortByLexicalOrder()
+}
+
+// ValueSpamWallet holds the funds the spammer cycles between its own addresses with value transactions.
+// The outputs of sent transactions are reused before they are confirmed, so the transactions are chained.
+// If a transaction of the wallet is conflicting or below max depth, the wallet collects its outputs from the ledger again.
+type ValueSpamWallet struct {
+	sync.Mutex
+
+	addresses     []*iotago.Ed25519Address
+	signer        iotago.AddressSigner
+	outputCount   int
+	belowMaxDepth milestone.Index
+	deSeriParas   *iotago.DeSerializationParameters
+	syncManager   *syncmanager.SyncManager
+	utxoManager   *utxo.Manager
+	indexer       *indexer.Indexer
+
+	// the index of the address the next output is sent to.
+	nextAddressIndex int
+	// the unspent outputs of the wallet that are not used by a transaction in progress.
+	outputs []*valueSpamOutput
+	// the messages of the unconfirmed transactions of the wallet, mapped to the confirmed milestone index at the time they were sent.
+	pendingMessages map[string]milestone.Index
+	// whether the outputs of the wallet need to be collected from the ledger.
+	collectOutputs bool
+	// the generation of the wallet is increased on every reset, so transactions taken from former outputs are not booked.
+	generation uint64
+}
+
+// NewValueSpamWallet creates a new ValueSpamWallet with addressCount addresses derived from the seed.
+// Every value transaction creates outputCount outputs, as long as the funds of the inputs cover the storage deposit of the outputs.
+func NewValueSpamWallet(seed []byte,
+	addressCount int,
+	outputCount int,
+	belowMaxDepth int,
+	deSeriParas *iotago.DeSerializationParameters,
+	syncManager *syncmanager.SyncManager,
+	utxoManager *utxo.Manager,
+	indexer *indexer.Indexer) (*ValueSpamWallet, error) {
+
+	if addressCount < 1 {
+		return nil, fmt.Errorf("invalid address count: %d", addressCount)
+	}
+
+	if outputCount < 1 || outputCount > iotago.MaxOutputsCount {
+		return nil, fmt.Errorf("invalid output count: %d, must be between 1 and %d", outputCount, iotago.MaxOutputsCount)
+	}
+
+	addresses := make([]*iotago.Ed25519Address, addressCount)
+	addressKeys := make([]iotago.AddressKeys, addressCount)
+	for i := 0; i < addressCount; i++ {
+		path, err := bip32path.ParsePath(fmt.Sprintf(valueSpamAddressPath, i))
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := slip10.DeriveKeyFromPath(seed, slip10.Ed25519(), path)
+		if err != nil {
+			return nil, fmt.Errorf("deriving address %d from the seed failed: %w", i, err)
+		}
+
+		pubKey, prvKey := slip10.Ed25519Key(key)
+		address := iotago.Ed25519AddressFromPubKey(ed25519.PublicKey(pubKey))
+		addresses[i] = &address
+		addressKeys[i] = iotago.NewAddressKeysForEd25519Address(&address, ed25519.PrivateKey(prvKey))
+	}
+
+	return &ValueSpamWallet{
+		addresses:       addresses,
+		signer:          iotago.NewInMemoryAddressSigner(addressKeys...),
+		outputCount:     outputCount,
+		belowMaxDepth:   milestone.Index(belowMaxDepth),
+		deSeriParas:     deSeriParas,
+		syncManager:     syncManager,
+		utxoManager:     utxoManager,
+		indexer:         indexer,
+		pendingMessages: make(map[string]milestone.Index),
+		collectOutputs:  true,
+	}, nil
+}
+
+// Addresses returns the addresses of the wallet.
+func (w *ValueSpamWallet) Addresses() []*iotago.Ed25519Address {
+	return w.addresses
+}
+
+// collectOutputsIfNeeded collects the unspent outputs of the wallet addresses from the ledger after a reset.
+func (w *ValueSpamWallet) collectOutputsIfNeeded() error {
+	w.Lock()
+	collectOutputs := w.collectOutputs
+	w.Unlock()
+
+	if !collectOutputs {
+		return nil
+	}
+
+	// the ledger needs to be locked before the wallet, so there is no confirmation ongoing
+	w.utxoManager.ReadLockLedger()
+	defer w.utxoManager.ReadUnlockLedger()
+
+	w.Lock()
+	defer w.Unlock()
+
+	if !w.collectOutputs {
+		// another worker already collected the outputs
+		return nil
+	}
+
+	var outputs []*valueSpamOutput
+	for _, address := range w.addresses {
+		result := w.indexer.ExtendedOutputsWithFilters(
+			indexer.ExtendedOutputUnlockableByAddress(address),
+			indexer.ExtendedOutputHasDustReturnCondition(false),
+			indexer.ExtendedOutputHasExpirationCondition(false),
+			indexer.ExtendedOutputHasTimelockCondition(false),
+		)
+		if result.Error != nil {
+			return fmt.Errorf("reading unspent outputs failed: %w", result.Error)
+		}
+
+		for i := range result.OutputIDs {
+			output, err := w.utxoManager.ReadOutputByOutputIDWithoutLocking(&result.OutputIDs[i])
+			if err != nil {
+				return fmt.Errorf("reading unspent output failed: %w", err)
+			}
+
+			if extendedOutput, ok := output.Output().(*iotago.ExtendedOutput); !ok || len(extendedOutput.NativeTokens) > 0 {
+				// native tokens are not moved by the spammer
+				continue
+			}
+
+			outputs = append(outputs, &valueSpamOutput{Output: output, address: address})
+		}
+	}
+
+	w.outputs = outputs
+	w.collectOutputs = false
+
+	return nil
+}
+
+// minDeposit returns the storage deposit of an output of the wallet.
+func (w *ValueSpamWallet) minDeposit() uint64 {
+	rentStructure := w.deSeriParas.RentStructure
+
+	output := &iotago.ExtendedOutput{
+		Conditions: iotago.UnlockConditions{
+			&iotago.AddressUnlockCondition{Address: w.addresses[0]},
+		},
+	}
+
+	return rentStructure.VByteCost * output.VByteCost(rentStructure, nil)
+}
+
+// newTransaction takes unspent outputs of the wallet and builds a signed transaction that sends their funds to the next addresses of the wallet.
+// The taken outputs need to be booked or released afterwards.
+func (w *ValueSpamWallet) newTransaction(tag []byte, data []byte) (*valueSpamTransaction, error) {
+	if err := w.collectOutputsIfNeeded(); err != nil {
+		return nil, err
+	}
+
+	w.Lock()
+	defer w.Unlock()
+
+	if len(w.outputs) == 0 {
+		return nil, ErrNoValueSpamOutputs
+	}
+
+	// split the funds into more outputs until the wallet holds enough outputs for parallel transactions
+	inputCount := 1
+	if len(w.outputs) >= valueSpamMaxWalletOutputs {
+		inputCount = w.outputCount
+	}
+
+	tx := &valueSpamTransaction{
+		generation: w.generation,
+	}
+
+	chainedMessages := make(map[string]struct{})
+	var amount uint64
+	for len(tx.inputs) < inputCount && len(w.outputs) > 0 {
+		input := w.outputs[len(w.outputs)-1]
+
+		messageKey := input.MessageID().ToMapKey()
+		if _, pending := w.pendingMessages[messageKey]; pending {
+			if _, chained := chainedMessages[messageKey]; !chained {
+				if len(chainedMessages) >= valueSpamMaxChainedMessages {
+					break
+				}
+				chainedMessages[messageKey] = struct{}{}
+				tx.chainedMessageIDs = append(tx.chainedMessageIDs, input.MessageID())
+			}
+		}
+
+		w.outputs = w.outputs[:len(w.outputs)-1]
+		tx.inputs = append(tx.inputs, input)
+		amount += input.Deposit()
+	}
+
+	outputCount := uint64(w.outputCount)
+	if minDeposit := w.minDeposit(); minDeposit > 0 && amount/minDeposit < outputCount {
+		// the funds don't cover the storage deposit of all outputs
+		outputCount = amount / minDeposit
+		if outputCount == 0 {
+			outputCount = 1
+		}
+	}
+
+	txBuilder := builder.NewTransactionBuilder()
+	txBuilder.AddTaggedDataPayload(&iotago.TaggedData{Tag: tag, Data: data})
+
+	for _, input := range tx.inputs {
+		txBuilder.AddInput(&builder.ToBeSignedUTXOInput{Address: input.address, Input: input.OutputID().UTXOInput()})
+	}
+
+	for i := uint64(0); i < outputCount; i++ {
+		outputAmount := amount / outputCount
+		if i == outputCount-1 {
+			// the last output gets the rest of the funds
+			outputAmount = amount - outputAmount*(outputCount-1)
+		}
+
+		address := w.addresses[w.nextAddressIndex]
+		w.nextAddressIndex = (w.nextAddressIndex + 1) % len(w.addresses)
+
+		tx.addresses = append(tx.addresses, address)
+		txBuilder.AddOutput(&iotago.ExtendedOutput{
+			Amount: outputAmount,
+			Conditions: iotago.UnlockConditions{
+				&iotago.AddressUnlockCondition{Address: address},
+			},
+		})
+	}
+
+	transaction, err := txBuilder.Build(w.deSeriParas, w.signer)
+	if err != nil {
+		w.releaseWithoutLocking(tx)
+		return nil, fmt.Errorf("building value transaction failed: %w", err)
+	}
+	tx.transaction = transaction
+
+	return tx, nil
+}
+
+// book adds the outputs of the transaction to the wallet, before the message of the transaction is sent.
+func (w *ValueSpamWallet) book(tx *valueSpamTransaction, messageID hornet.MessageID) error {
+	w.Lock()
+	defer w.Unlock()
+
+	if tx.generation != w.generation {
+		// the wallet was reset in the meantime, the outputs are collected from the ledger
+		return nil
+	}
+
+	for i, address := range tx.addresses {
+		output, err := utxo.NewOutput(messageID, 0, 0, tx.transaction, uint16(i))
+		if err != nil {
+			return err
+		}
+		w.outputs = append(w.outputs, &valueSpamOutput{Output: output, address: address})
+	}
+
+	w.pendingMessages[messageID.ToMapKey()] = w.syncManager.ConfirmedMilestoneIndex()
+
+	return nil
+}
+
+// unbook removes the outputs of a transaction whose message could not be sent and releases its inputs.
+func (w *ValueSpamWallet) unbook(tx *valueSpamTransaction, messageID hornet.MessageID) {
+	w.Lock()
+	defer w.Unlock()
+
+	if tx.generation != w.generation {
+		return
+	}
+
+	outputs := make([]*valueSpamOutput, 0, len(w.outputs))
+	for _, output := range w.outputs {
+		if !bytes.Equal(output.MessageID(), messageID) {
+			outputs = append(outputs, output)
+		}
+	}
+	w.outputs = outputs
+
+	delete(w.pendingMessages, messageID.ToMapKey())
+
+	w.releaseWithoutLocking(tx)
+}
+
+// release gives the inputs of a transaction that was not sent back to the wallet.
+func (w *ValueSpamWallet) release(tx *valueSpamTransaction) {
+	w.Lock()
+	defer w.Unlock()
+
+	w.releaseWithoutLocking(tx)
+}
+
+func (w *ValueSpamWallet) releaseWithoutLocking(tx *valueSpamTransaction) {
+	if tx.generation != w.generation {
+		return
+	}
+
+	w.outputs = append(w.outputs, tx.inputs...)
+}
+
+// resetWithoutLocking drops all outputs and pending messages, the outputs are collected from the ledger for the next transaction.
+func (w *ValueSpamWallet) resetWithoutLocking() {
+	w.outputs = nil
+	w.pendingMessages = make(map[string]milestone.Index)
+	w.collectOutputs = true
+	w.generation++
+}
+
+// ApplyConfirmation applies new milestone confirmations to the wallet.
+// If a transaction of the wallet was conflicting or is below max depth, the wallet is reset.
+// no need to ReadLockLedger, because this function should be called from milestone confirmation event anyway.
+func (w *ValueSpamWallet) ApplyConfirmation(confirmation *whiteflag.Confirmation) {
+	if confirmation == nil {
+		return
+	}
+
+	w.Lock()
+	defer w.Unlock()
+
+	for _, messageID := range confirmation.Mutations.MessagesIncludedWithTransactions {
+		delete(w.pendingMessages, messageID.ToMapKey())
+	}
+
+	for _, conflict := range confirmation.Mutations.MessagesExcludedWithConflictingTransactions {
+		if _, pending := w.pendingMessages[conflict.MessageID.ToMapKey()]; pending {
+			// the chain of the wallet is broken
+			w.resetWithoutLocking()
+			return
+		}
+	}
+
+	for _, sentIndex := range w.pendingMessages {
+		if confirmation.MilestoneIndex > sentIndex+w.belowMaxDepth {
+			// the message can't be referenced anymore
+			w.resetWithoutLocking()
+			return
+		}
+	}
+}
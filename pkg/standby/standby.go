@@ -0,0 +1,99 @@
+package standby
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/events"
+)
+
+var (
+	// ErrAlreadyPromoted is returned if a node is promoted which does not run in standby mode anymore.
+	ErrAlreadyPromoted = errors.New("node was already promoted")
+)
+
+// Events are the events issued by the standby mode.
+type Events struct {
+	// Fired when the node got promoted.
+	Promoted *events.Event
+}
+
+// StatusCaller is used to signal a Status.
+func StatusCaller(handler interface{}, params ...interface{}) {
+	handler.(func(*Status))(params[0].(*Status))
+}
+
+// Status is the state of the standby mode.
+type Status struct {
+	// Whether the node still runs in standby mode.
+	Standby bool `json:"standby"`
+	// The time the node started in standby mode.
+	StandbySince time.Time `json:"standbySince"`
+	// The time the node got promoted, zero while it is in standby mode.
+	PromotedAt time.Time `json:"promotedAt,omitempty"`
+}
+
+// Standby keeps track whether a node runs as a warm standby replica of a primary node.
+// A node in standby mode follows the network and keeps its database up to date,
+// but does not serve public APIs until it gets promoted.
+type Standby struct {
+	// Events are the events issued by the standby mode.
+	Events *Events
+
+	sync.RWMutex
+	standbySince time.Time
+	promotedAt   time.Time
+}
+
+// New creates a new Standby for a node which starts in standby mode.
+func New() *Standby {
+	return &Standby{
+		Events: &Events{
+			Promoted: events.NewEvent(StatusCaller),
+		},
+		standbySince: time.Now(),
+	}
+}
+
+// IsStandby tells whether the node still runs in standby mode.
+func (s *Standby) IsStandby() bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.promotedAt.IsZero()
+}
+
+// Status returns the state of the standby mode.
+func (s *Standby) Status() *Status {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.statusWithoutLocking()
+}
+
+func (s *Standby) statusWithoutLocking() *Status {
+	return &Status{
+		Standby:      s.promotedAt.IsZero(),
+		StandbySince: s.standbySince,
+		PromotedAt:   s.promotedAt,
+	}
+}
+
+// Promote ends the standby mode, so that the node starts to serve public APIs.
+// The Promoted event is fired after the state changed.
+func (s *Standby) Promote() (*Status, error) {
+	s.Lock()
+	if !s.promotedAt.IsZero() {
+		s.Unlock()
+		return nil, ErrAlreadyPromoted
+	}
+	s.promotedAt = time.Now()
+	status := s.statusWithoutLocking()
+	s.Unlock()
+
+	s.Events.Promoted.Trigger(status)
+
+	return status, nil
+}
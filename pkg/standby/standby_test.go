@@ -0,0 +1,38 @@
+package standby_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/standby"
+	"github.com/iotaledger/hive.go/events"
+)
+
+func TestStandbyPromote(t *testing.T) {
+
+	s := standby.New()
+	require.True(t, s.IsStandby())
+	require.True(t, s.Status().Standby)
+	require.True(t, s.Status().PromotedAt.IsZero())
+
+	var promoted []*standby.Status
+	s.Events.Promoted.Attach(events.NewClosure(func(status *standby.Status) {
+		promoted = append(promoted, status)
+	}))
+
+	status, err := s.Promote()
+	require.NoError(t, err)
+	require.False(t, status.Standby)
+	require.False(t, status.PromotedAt.Before(status.StandbySince))
+	require.False(t, s.IsStandby())
+	require.Equal(t, status, s.Status())
+
+	require.Len(t, promoted, 1)
+	require.Equal(t, status, promoted[0])
+
+	// the node can only be promoted once
+	_, err = s.Promote()
+	require.ErrorIs(t, err, standby.ErrAlreadyPromoted)
+	require.Len(t, promoted, 1)
+}
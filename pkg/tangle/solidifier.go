@@ -28,6 +28,8 @@ type ConfirmedMilestoneMetric struct {
 	RMPS                   float64         `json:"rmps"`
 	ReferencedRate         float64         `json:"referenced_rate"`
 	TimeSinceLastMilestone float64         `json:"time_since_last_ms"`
+	TangleWidth            float64         `json:"tangle_width"`
+	ConeOverlap            float64         `json:"cone_overlap"`
 }
 
 // TriggerSolidifier can be used to manually trigger the solidifier from other plugins.
@@ -286,6 +288,7 @@ func (t *Tangle) solidifyMilestone(newMilestoneIndex milestone.Index, force bool
 	timeStart := time.Now()
 	confirmedMilestoneStats, confirmationMetrics, err := whiteflag.ConfirmMilestone(t.storage, t.serverMetrics, messagesMemcache, metadataMemcache, cachedMsToSolidify.Milestone().MessageID,
 		func(msgMeta *storage.CachedMetadata, index milestone.Index, confTime uint64) {
+			t.messageReferencedSyncEvent.Trigger(msgMeta.Metadata().MessageID().ToMapKey())
 			t.Events.MessageReferenced.Trigger(msgMeta, index, confTime)
 		},
 		func(confirmation *whiteflag.Confirmation) {
@@ -343,6 +346,10 @@ func (t *Tangle) solidifyMilestone(newMilestoneIndex milestone.Index, force bool
 		t.LogPanic(err)
 	}
 
+	if confirmedMilestoneStats.ConeShapeErr != nil {
+		t.LogWarnf("computing the cone shape of milestone %d failed: %s", confirmedMilestoneStats.Index, confirmedMilestoneStats.ConeShapeErr)
+	}
+
 	t.LogInfof("Milestone confirmed (%d): txsReferenced: %v, txsValue: %v, txsZeroValue: %v, txsConflicting: %v, collect: %v, total: %v",
 		confirmedMilestoneStats.Index,
 		confirmedMilestoneStats.MessagesReferenced,
@@ -364,7 +371,7 @@ func (t *Tangle) solidifyMilestone(newMilestoneIndex milestone.Index, force bool
 	t.Events.ConfirmationMetricsUpdated.Trigger(confirmationMetrics)
 
 	var rmpsMessage string
-	if metric, err := t.calcConfirmedMilestoneMetric(cachedMsToSolidify.Retain(), confirmedMilestoneStats.Index); err == nil {
+	if metric, err := t.calcConfirmedMilestoneMetric(cachedMsToSolidify.Retain(), confirmedMilestoneStats); err == nil {
 		if t.syncManager.IsNodeSynced() {
 			// Only trigger the metrics event if the node is sync (otherwise the MPS and conf.rate is wrong)
 			if t.firstSyncedMilestone == 0 {
@@ -401,9 +408,11 @@ func (t *Tangle) solidifyMilestone(newMilestoneIndex milestone.Index, force bool
 	t.milestoneSolidifierWorkerPool.TrySubmit(milestone.Index(0), false)
 }
 
-func (t *Tangle) calcConfirmedMilestoneMetric(cachedMilestone *storage.CachedMilestone, milestoneIndexToSolidify milestone.Index) (*ConfirmedMilestoneMetric, error) {
+func (t *Tangle) calcConfirmedMilestoneMetric(cachedMilestone *storage.CachedMilestone, confirmedMilestoneStats *whiteflag.ConfirmedMilestoneStats) (*ConfirmedMilestoneMetric, error) {
 	defer cachedMilestone.Release(true)
 
+	milestoneIndexToSolidify := confirmedMilestoneStats.Index
+
 	oldMilestone := t.storage.CachedMilestoneOrNil(milestoneIndexToSolidify - 1) // milestone +1
 	if oldMilestone == nil {
 		return nil, ErrMilestoneNotFound
@@ -436,6 +445,11 @@ func (t *Tangle) calcConfirmedMilestoneMetric(cachedMilestone *storage.CachedMil
 		TimeSinceLastMilestone: timeDiff,
 	}
 
+	if confirmedMilestoneStats.ConeShape != nil {
+		metric.TangleWidth = confirmedMilestoneStats.ConeShape.Width
+		metric.ConeOverlap = confirmedMilestoneStats.ConeShape.Overlap
+	}
+
 	return metric, nil
 }
 
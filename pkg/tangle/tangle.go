@@ -86,6 +86,7 @@ type Tangle struct {
 
 	messageProcessedSyncEvent   *utils.SyncEvent
 	messageSolidSyncEvent       *utils.SyncEvent
+	messageReferencedSyncEvent  *utils.SyncEvent
 	milestoneConfirmedSyncEvent *utils.SyncEvent
 
 	milestoneSolidificationCtxLock    syncutils.Mutex
@@ -154,6 +155,7 @@ func New(
 		milestoneSolidifierQueueSize:     2,
 		messageProcessedSyncEvent:        utils.NewSyncEvent(),
 		messageSolidSyncEvent:            utils.NewSyncEvent(),
+		messageReferencedSyncEvent:       utils.NewSyncEvent(),
 		milestoneConfirmedSyncEvent:      utils.NewSyncEvent(),
 		Events: &Events{
 			MPSMetricsUpdated:              events.NewEvent(MPSMetricsCaller),
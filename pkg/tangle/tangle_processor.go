@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/libp2p/go-libp2p-core/peer"
+
 	"github.com/gohornet/hornet/pkg/common"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
@@ -207,12 +209,18 @@ func (t *Tangle) processIncomingTx(incomingMsg *storage.Message, requests gossip
 			proto.Metrics.NewMessages.Inc()
 		}
 
+		// the peer which sent the message most likely also has its parents
+		var sourcePeer peer.ID
+		if proto != nil {
+			sourcePeer = proto.PeerID
+		}
+
 		// since we only add the parents if there was a source request, we only
 		// request them for messages which should be part of milestone cones
 		for _, request := range requests {
 			// add this newly received message's parents to the request queue
 			if request.RequestType == gossip.RequestTypeMessageID {
-				t.requester.RequestParents(cachedMsg.Retain(), request.MilestoneIndex, true)
+				t.requester.RequestParents(cachedMsg.Retain(), request.MilestoneIndex, sourcePeer, true)
 			}
 		}
 
@@ -278,6 +286,16 @@ func (t *Tangle) DeregisterMessageSolidEvent(messageID hornet.MessageID) {
 	t.messageSolidSyncEvent.DeregisterEvent(messageID.ToMapKey())
 }
 
+// RegisterMessageReferencedEvent returns a channel that gets closed when the message is referenced by a milestone.
+func (t *Tangle) RegisterMessageReferencedEvent(messageID hornet.MessageID) chan struct{} {
+	return t.messageReferencedSyncEvent.RegisterEvent(messageID.ToMapKey())
+}
+
+// DeregisterMessageReferencedEvent removes a registered event to free the memory if not used.
+func (t *Tangle) DeregisterMessageReferencedEvent(messageID hornet.MessageID) {
+	t.messageReferencedSyncEvent.DeregisterEvent(messageID.ToMapKey())
+}
+
 // RegisterMilestoneConfirmedEvent returns a channel that gets closed when the milestone is confirmed.
 func (t *Tangle) RegisterMilestoneConfirmedEvent(msIndex milestone.Index) chan struct{} {
 	return t.milestoneConfirmedSyncEvent.RegisterEvent(msIndex)
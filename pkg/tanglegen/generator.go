@@ -0,0 +1,620 @@
+package tanglegen
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/keymanager"
+	"github.com/gohornet/hornet/pkg/metrics"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/milestonemanager"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/model/syncmanager"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/pow"
+	"github.com/gohornet/hornet/pkg/tangle"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/builder"
+)
+
+const (
+	// the tag used for all generated messages.
+	generatorTag = "tanglegen"
+	// the time between two generated milestones.
+	milestoneTimestampInterval = 10 * time.Second
+	// the below max depth used for the sync manager of the generator.
+	belowMaxDepth = 15
+	// the minimum amount of an output that is split into two outputs by a generated transaction.
+	minSplitAmount = 2_000_000
+)
+
+var (
+	// ErrStorageNotEmpty is returned if the storage of the generator already contains milestones.
+	ErrStorageNotEmpty = errors.New("storage already contains milestones")
+	// ErrInvalidWidth is returned if the width of the tangle is out of range.
+	ErrInvalidWidth = fmt.Errorf("width must be between 1 and %d", iotago.MaxParentsInAMessage-1)
+	// ErrInvalidMilestoneInterval is returned if the milestone interval is out of range.
+	ErrInvalidMilestoneInterval = errors.New("milestone interval must be at least 1")
+	// ErrInvalidRate is returned if a rate is not between 0 and 1.
+	ErrInvalidRate = errors.New("rates must be between 0 and 1")
+)
+
+// MessageFunc is called for every generated message (milestones included) in the order of creation.
+type MessageFunc func(msg *storage.Message) error
+
+// MilestoneConfirmedFunc is called after a generated milestone was confirmed.
+type MilestoneConfirmedFunc func(confirmedMilestoneStats *whiteflag.ConfirmedMilestoneStats)
+
+// Stats holds statistics about a generated tangle.
+type Stats struct {
+	// The amount of generated milestones.
+	Milestones int
+	// The amount of generated messages (milestones excluded).
+	Messages int
+	// The amount of messages that contain a transaction.
+	Transactions int
+	// The amount of messages that contain a conflicting transaction.
+	Conflicts int
+}
+
+// the default options applied to the Generator.
+var defaultOptions = []Option{
+	WithSeed(0),
+	WithWidth(3),
+	WithMilestoneInterval(5),
+	WithTransactionRate(0.5),
+	WithConflictRate(0.05),
+	WithNetworkID("tanglegen"),
+	WithGenesisTimestamp(time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)),
+	WithDeSerializationParameters(&iotago.DeSerializationParameters{
+		RentStructure: &iotago.RentStructure{
+			VByteCost:    0,
+			VBFactorData: 0,
+			VBFactorKey:  0,
+		},
+	}),
+}
+
+// Options define options for the Generator.
+type Options struct {
+	// the seed of the pseudo random generator.
+	seed int64
+	// the amount of messages per layer of the tangle.
+	width int
+	// the amount of layers between two milestones.
+	milestoneInterval int
+	// the probability of a message to contain a transaction.
+	transactionRate float64
+	// the probability of a transaction to double spend an already spent output.
+	conflictRate float64
+	// the network ID of the generated messages.
+	networkID string
+	// the timestamp of the genesis of the generated tangle.
+	genesisTimestamp time.Time
+	// the deserialization parameters used to validate the generated messages.
+	deSeriParas *iotago.DeSerializationParameters
+	// the optional PoW handler used to do PoW for the generated messages.
+	powHandler *pow.Handler
+	// called for every generated message.
+	messageFunc MessageFunc
+	// called after a generated milestone was confirmed.
+	milestoneConfirmedFunc MilestoneConfirmedFunc
+}
+
+// applies the given Option.
+func (so *Options) apply(opts ...Option) {
+	for _, opt := range opts {
+		opt(so)
+	}
+}
+
+// Option is a function setting a generator option.
+type Option func(opts *Options)
+
+// WithSeed defines the seed of the pseudo random generator.
+// Tangles generated with the same seed and options are identical.
+func WithSeed(seed int64) Option {
+	return func(opts *Options) {
+		opts.seed = seed
+	}
+}
+
+// WithWidth defines the amount of messages per layer of the tangle.
+func WithWidth(width int) Option {
+	return func(opts *Options) {
+		opts.width = width
+	}
+}
+
+// WithMilestoneInterval defines the amount of layers between two milestones.
+func WithMilestoneInterval(milestoneInterval int) Option {
+	return func(opts *Options) {
+		opts.milestoneInterval = milestoneInterval
+	}
+}
+
+// WithTransactionRate defines the probability of a message to contain a transaction.
+func WithTransactionRate(transactionRate float64) Option {
+	return func(opts *Options) {
+		opts.transactionRate = transactionRate
+	}
+}
+
+// WithConflictRate defines the probability of a transaction to double spend an already spent output.
+func WithConflictRate(conflictRate float64) Option {
+	return func(opts *Options) {
+		opts.conflictRate = conflictRate
+	}
+}
+
+// WithNetworkID defines the network ID of the generated messages.
+func WithNetworkID(networkID string) Option {
+	return func(opts *Options) {
+		opts.networkID = networkID
+	}
+}
+
+// WithGenesisTimestamp defines the timestamp of the genesis of the generated tangle.
+// The timestamps of the milestones are derived from it.
+func WithGenesisTimestamp(genesisTimestamp time.Time) Option {
+	return func(opts *Options) {
+		opts.genesisTimestamp = genesisTimestamp
+	}
+}
+
+// WithDeSerializationParameters defines the deserialization parameters used to validate the generated messages.
+func WithDeSerializationParameters(deSeriParas *iotago.DeSerializationParameters) Option {
+	return func(opts *Options) {
+		opts.deSeriParas = deSeriParas
+	}
+}
+
+// WithPoWHandler defines the PoW handler used to do PoW for the generated messages.
+// If no handler is given, the nonce of the messages is left empty.
+func WithPoWHandler(powHandler *pow.Handler) Option {
+	return func(opts *Options) {
+		opts.powHandler = powHandler
+	}
+}
+
+// WithMessageFunc defines a function that is called for every generated message.
+func WithMessageFunc(messageFunc MessageFunc) Option {
+	return func(opts *Options) {
+		opts.messageFunc = messageFunc
+	}
+}
+
+// WithMilestoneConfirmedFunc defines a function that is called after a generated milestone was confirmed.
+func WithMilestoneConfirmedFunc(milestoneConfirmedFunc MilestoneConfirmedFunc) Option {
+	return func(opts *Options) {
+		opts.milestoneConfirmedFunc = milestoneConfirmedFunc
+	}
+}
+
+// Generator generates deterministic test tangles into a storage.
+// All randomness is derived from the seed, so the same options always result in the same messages.
+type Generator struct {
+	// used to access the node storage.
+	storage *storage.Storage
+	// used to track the latest and confirmed milestone index.
+	syncManager *syncmanager.SyncManager
+	// used to verify and store the generated milestones.
+	milestoneManager *milestonemanager.MilestoneManager
+	// holds metrics about the tangle.
+	serverMetrics *metrics.ServerMetrics
+	// holds the generator options.
+	opts *Options
+
+	// the pseudo random generator derived from the seed.
+	rand *rand.Rand
+	// the private key used to sign the milestones.
+	cooPrivateKey ed25519.PrivateKey
+	// the private key of the wallet holding all funds.
+	walletPrivateKey ed25519.PrivateKey
+	// the address of the wallet holding all funds.
+	walletAddress *iotago.Ed25519Address
+
+	// the confirmed unspent outputs of the wallet, which were not consumed by a generated transaction yet.
+	unspentOutputs []*utxo.Output
+	// the confirmed spent outputs of the wallet, which are used to generate conflicts.
+	spentOutputs []*utxo.Output
+	// the messages of the last generated layer.
+	tips hornet.MessageIDs
+	// the message ID of the last generated milestone.
+	lastMilestoneMessageID hornet.MessageID
+	// statistics about the generated tangle.
+	stats *Stats
+}
+
+// New creates a new Generator that writes the generated tangle into the given storage.
+// The storage must not contain any milestones yet.
+func New(dbStorage *storage.Storage, opts ...Option) (*Generator, error) {
+
+	options := &Options{}
+	options.apply(defaultOptions...)
+	options.apply(opts...)
+
+	if options.width < 1 || options.width > iotago.MaxParentsInAMessage-1 {
+		return nil, ErrInvalidWidth
+	}
+	if options.milestoneInterval < 1 {
+		return nil, ErrInvalidMilestoneInterval
+	}
+	if options.transactionRate < 0 || options.transactionRate > 1 || options.conflictRate < 0 || options.conflictRate > 1 {
+		return nil, ErrInvalidRate
+	}
+
+	if dbStorage.SearchLatestMilestoneIndexInStore() != 0 {
+		return nil, ErrStorageNotEmpty
+	}
+
+	random := rand.New(rand.NewSource(options.seed))
+
+	// the keys are derived from the seed as well
+	newKeyFromRand := func() ed25519.PrivateKey {
+		keySeed := make([]byte, ed25519.SeedSize)
+		random.Read(keySeed)
+		return ed25519.NewKeyFromSeed(keySeed)
+	}
+	cooPrivateKey := newKeyFromRand()
+	walletPrivateKey := newKeyFromRand()
+	walletAddress := iotago.Ed25519AddressFromPubKey(walletPrivateKey.Public().(ed25519.PublicKey))
+
+	syncManager, err := syncmanager.New(dbStorage.UTXOManager(), belowMaxDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	keyManager := keymanager.New()
+	keyManager.AddKeyRange(cooPrivateKey.Public().(ed25519.PublicKey), 0, 0)
+
+	return &Generator{
+		storage:          dbStorage,
+		syncManager:      syncManager,
+		milestoneManager: milestonemanager.New(dbStorage, syncManager, keyManager, 1),
+		serverMetrics:    &metrics.ServerMetrics{},
+		opts:             options,
+		rand:             random,
+		cooPrivateKey:    cooPrivateKey,
+		walletPrivateKey: walletPrivateKey,
+		walletAddress:    &walletAddress,
+		stats:            &Stats{},
+	}, nil
+}
+
+// CooPublicKey returns the public key of the coordinator that signed the generated milestones.
+// It has to be configured on nodes that should accept the generated tangle.
+func (g *Generator) CooPublicKey() ed25519.PublicKey {
+	return g.cooPrivateKey.Public().(ed25519.PublicKey)
+}
+
+// WalletAddress returns the address that holds the total supply at genesis.
+func (g *Generator) WalletAddress() *iotago.Ed25519Address {
+	return g.walletAddress
+}
+
+// NetworkID returns the network ID of the generated tangle.
+func (g *Generator) NetworkID() uint64 {
+	return iotago.NetworkIDFromString(g.opts.networkID)
+}
+
+// Generate initializes the ledger with the genesis output and
+// generates and confirms the given amount of milestones with their cones.
+func (g *Generator) Generate(ctx context.Context, milestonesCount int) (*Stats, error) {
+
+	if err := g.bootstrap(); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < milestonesCount; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if g.lastMilestoneMessageID != nil {
+			// the first milestone bootstraps the network and has no cone
+			for layer := 0; layer < g.opts.milestoneInterval; layer++ {
+				if err := g.generateLayer(); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if err := g.generateMilestone(); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.stats, nil
+}
+
+// bootstrap stores the initial snapshot information and the genesis output in the storage.
+func (g *Generator) bootstrap() error {
+
+	if err := g.storage.SetSnapshotMilestone(g.NetworkID(), 0, 0, 0, g.opts.genesisTimestamp); err != nil {
+		return err
+	}
+
+	g.storage.WriteLockSolidEntryPoints()
+	g.storage.ResetSolidEntryPointsWithoutLocking()
+	g.storage.SolidEntryPointsAddWithoutLocking(hornet.NullMessageID(), 0)
+	err := g.storage.StoreSolidEntryPointsWithoutLocking()
+	g.storage.WriteUnlockSolidEntryPoints()
+	if err != nil {
+		return err
+	}
+
+	genesisOutput := utxo.CreateOutput(&iotago.OutputID{}, hornet.NullMessageID(), 0, 0, &iotago.ExtendedOutput{
+		Amount: iotago.TokenSupply,
+		Conditions: iotago.UnlockConditions{
+			&iotago.AddressUnlockCondition{Address: g.walletAddress},
+		},
+	})
+	if err := g.storage.UTXOManager().AddUnspentOutput(genesisOutput); err != nil {
+		return err
+	}
+
+	if err := g.storage.UTXOManager().StoreUnspentTreasuryOutput(&utxo.TreasuryOutput{MilestoneID: iotago.MilestoneID{}, Amount: 0}); err != nil {
+		return err
+	}
+
+	if err := g.storage.UTXOManager().StoreLedgerIndex(0); err != nil {
+		return err
+	}
+
+	g.unspentOutputs = []*utxo.Output{genesisOutput}
+
+	return nil
+}
+
+// generateLayer generates "width" messages on top of the current tips.
+// Every tip is referenced by at least one message of the new layer, so all messages end up in the next milestone cone.
+func (g *Generator) generateLayer() error {
+
+	layer := make(hornet.MessageIDs, 0, g.opts.width)
+	for i := 0; i < g.opts.width; i++ {
+		parents := hornet.MessageIDs{g.tips[i%len(g.tips)]}
+
+		// add up to two additional random parents of the last layer
+		for j := g.rand.Intn(3); j > 0; j-- {
+			parents = append(parents, g.tips[g.rand.Intn(len(g.tips))])
+		}
+
+		var payload iotago.Payload
+		if g.rand.Float64() < g.opts.transactionRate {
+			transaction, err := g.generateTransaction()
+			if err != nil {
+				return err
+			}
+			if transaction != nil {
+				payload = transaction
+			}
+		}
+
+		if payload == nil {
+			data := make([]byte, 8)
+			binary.LittleEndian.PutUint64(data, uint64(g.stats.Messages))
+			payload = &iotago.TaggedData{Tag: []byte(generatorTag), Data: data}
+		}
+
+		msg, err := g.createMessage(parents.RemoveDupsAndSortByLexicalOrder(), payload)
+		if err != nil {
+			return err
+		}
+
+		if err := g.storeMessage(msg); err != nil {
+			return err
+		}
+
+		layer = append(layer, msg.MessageID())
+		g.stats.Messages++
+	}
+
+	g.tips = layer
+
+	return nil
+}
+
+// generateTransaction generates a transaction that consumes an unspent output of the wallet.
+// Depending on the conflict rate, an already spent output is consumed instead, which results in a conflict.
+// Returns nil if there are no outputs available to create a transaction.
+func (g *Generator) generateTransaction() (*iotago.Transaction, error) {
+
+	var input *utxo.Output
+	switch {
+	case len(g.spentOutputs) > 0 && g.rand.Float64() < g.opts.conflictRate:
+		input = g.spentOutputs[g.rand.Intn(len(g.spentOutputs))]
+
+	case len(g.unspentOutputs) > 0:
+		idx := g.rand.Intn(len(g.unspentOutputs))
+		input = g.unspentOutputs[idx]
+
+		// the output must not be consumed twice before the next milestone
+		g.unspentOutputs = append(g.unspentOutputs[:idx], g.unspentOutputs[idx+1:]...)
+
+	default:
+		return nil, nil
+	}
+
+	txBuilder := builder.NewTransactionBuilder()
+	txBuilder.AddInput(&builder.ToBeSignedUTXOInput{Address: g.walletAddress, Input: input.OutputID().UTXOInput()})
+
+	amounts := []uint64{input.Deposit()}
+	if input.Deposit() >= minSplitAmount {
+		split := input.Deposit()/4 + uint64(g.rand.Int63n(int64(input.Deposit()/2)))
+		amounts = []uint64{split, input.Deposit() - split}
+	}
+
+	for _, amount := range amounts {
+		txBuilder.AddOutput(&iotago.ExtendedOutput{
+			Amount:     amount,
+			Conditions: iotago.UnlockConditions{&iotago.AddressUnlockCondition{Address: g.walletAddress}},
+		})
+	}
+
+	signer := iotago.NewInMemoryAddressSigner(iotago.AddressKeys{Address: g.walletAddress, Keys: g.walletPrivateKey})
+
+	transaction, err := txBuilder.Build(g.opts.deSeriParas, signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build transaction: %w", err)
+	}
+
+	g.stats.Transactions++
+
+	return transaction, nil
+}
+
+// generateMilestone generates a milestone on top of the current tips and confirms its cone.
+func (g *Generator) generateMilestone() error {
+
+	index := g.syncManager.LatestMilestoneIndex() + 1
+	timestamp := uint64(g.opts.genesisTimestamp.Add(time.Duration(index) * milestoneTimestampInterval).Unix())
+
+	parents := hornet.MessageIDs{hornet.NullMessageID()}
+	if g.lastMilestoneMessageID != nil {
+		parents = append(hornet.MessageIDs{g.lastMilestoneMessageID}, g.tips...)
+	}
+	parents = parents.RemoveDupsAndSortByLexicalOrder()
+
+	messagesMemcache := storage.NewMessagesMemcache(g.storage)
+	metadataMemcache := storage.NewMetadataMemcache(g.storage)
+
+	defer func() {
+		// all releases are forced since the cone is referenced and not needed anymore
+
+		// release all messages at the end
+		messagesMemcache.Cleanup(true)
+
+		// Release all message metadata at the end
+		metadataMemcache.Cleanup(true)
+	}()
+
+	mutations, err := whiteflag.ComputeWhiteFlagMutations(context.Background(), g.storage, index, timestamp, metadataMemcache, messagesMemcache, parents)
+	if err != nil {
+		return fmt.Errorf("failed to compute white flag mutations: %w", err)
+	}
+
+	msPayload, err := iotago.NewMilestone(uint32(index), timestamp, parents.ToSliceOfArrays(), mutations.MerkleTreeHash, []iotago.MilestonePublicKey{g.milestonePublicKey()})
+	if err != nil {
+		return err
+	}
+
+	if err := msPayload.Sign(iotago.InMemoryEd25519MilestoneSigner(iotago.MilestonePublicKeyMapping{g.milestonePublicKey(): g.cooPrivateKey})); err != nil {
+		return fmt.Errorf("failed to sign milestone: %w", err)
+	}
+
+	msg, err := g.createMessage(parents, msPayload)
+	if err != nil {
+		return err
+	}
+
+	if err := g.storeMessage(msg); err != nil {
+		return err
+	}
+
+	if !g.storage.ContainsMilestone(index) {
+		return fmt.Errorf("milestone %d was not stored", index)
+	}
+	g.syncManager.SetLatestMilestoneIndex(index)
+
+	var newOutputs []*utxo.Output
+	var newSpents []*utxo.Output
+
+	confirmedMilestoneStats, _, err := whiteflag.ConfirmMilestone(g.storage, g.serverMetrics, messagesMemcache, metadataMemcache, msg.MessageID(),
+		func(txMeta *storage.CachedMetadata, index milestone.Index, confTime uint64) {},
+		func(confirmation *whiteflag.Confirmation) {},
+		func(index milestone.Index, newOutputs utxo.Outputs, newSpents utxo.Spents) {},
+		func(index milestone.Index, output *utxo.Output) {
+			newOutputs = append(newOutputs, output)
+		},
+		func(index milestone.Index, spent *utxo.Spent) {
+			newSpents = append(newSpents, spent.Output())
+		},
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to confirm milestone %d: %w", index, err)
+	}
+
+	if err := g.syncManager.SetConfirmedMilestoneIndex(index, true); err != nil {
+		return err
+	}
+
+	// the ledger callbacks are not ordered, so the outputs are sorted to keep the generator deterministic
+	g.unspentOutputs = sortOutputs(append(g.unspentOutputs, newOutputs...))
+	g.spentOutputs = sortOutputs(append(g.spentOutputs, newSpents...))
+
+	g.stats.Milestones++
+	g.stats.Conflicts += confirmedMilestoneStats.MessagesExcludedWithConflictingTransactions
+
+	g.lastMilestoneMessageID = msg.MessageID()
+	g.tips = hornet.MessageIDs{g.lastMilestoneMessageID}
+
+	if g.opts.milestoneConfirmedFunc != nil {
+		g.opts.milestoneConfirmedFunc(confirmedMilestoneStats)
+	}
+
+	return nil
+}
+
+// milestonePublicKey returns the public key of the coordinator as milestone public key.
+func (g *Generator) milestonePublicKey() iotago.MilestonePublicKey {
+	var pubKey iotago.MilestonePublicKey
+	copy(pubKey[:], g.CooPublicKey())
+	return pubKey
+}
+
+// createMessage creates a message with the given parents and payload.
+func (g *Generator) createMessage(parents hornet.MessageIDs, payload iotago.Payload) (*storage.Message, error) {
+
+	iotaMsg := &iotago.Message{
+		NetworkID: g.NetworkID(),
+		Parents:   parents.ToSliceOfArrays(),
+		Payload:   payload,
+	}
+
+	if g.opts.powHandler != nil {
+		if err := g.opts.powHandler.DoPoW(context.Background(), iotaMsg, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	return storage.NewMessage(iotaMsg, serializer.DeSeriModePerformValidation, g.opts.deSeriParas)
+}
+
+// storeMessage adds the message to the storage as solid message and passes it to the MessageFunc.
+func (g *Generator) storeMessage(msg *storage.Message) error {
+
+	cachedMsg, alreadyAdded := tangle.AddMessageToStorage(g.storage, g.milestoneManager, msg, g.syncManager.LatestMilestoneIndex(), false, true) // msg +1
+	if alreadyAdded {
+		cachedMsg.Release(true) // msg -1
+		return fmt.Errorf("message %s was already generated", msg.MessageID().ToHex())
+	}
+	cachedMsg.Metadata().SetSolid(true)
+	cachedMsg.Release(true) // msg -1
+
+	if g.opts.messageFunc != nil {
+		return g.opts.messageFunc(msg)
+	}
+
+	return nil
+}
+
+// sortOutputs sorts the outputs lexically by their output ID.
+func sortOutputs(outputs []*utxo.Output) []*utxo.Output {
+	sort.Slice(outputs, func(i, j int) bool {
+		return bytes.Compare(outputs[i].OutputID()[:], outputs[j].OutputID()[:]) < 0
+	})
+	return outputs
+}
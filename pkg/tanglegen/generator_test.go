@@ -0,0 +1,134 @@
+package tanglegen_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/tanglegen"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var deSeriParas = &iotago.DeSerializationParameters{
+	RentStructure: &iotago.RentStructure{
+		VByteCost:    0,
+		VBFactorData: 0,
+		VBFactorKey:  0,
+	},
+}
+
+// generateTangle generates a tangle into a new in-memory storage and returns the generated messages.
+func generateTangle(t *testing.T, milestonesCount int, opts ...tanglegen.Option) (*tanglegen.Stats, hornet.MessageIDs, *bytes.Buffer) {
+
+	dbStorage, err := storage.New(mapdb.NewMapDB(), mapdb.NewMapDB())
+	require.NoError(t, err)
+	defer dbStorage.ShutdownStorages()
+
+	var messageIDs hornet.MessageIDs
+	stream := &bytes.Buffer{}
+
+	opts = append(opts, tanglegen.WithMessageFunc(func(msg *storage.Message) error {
+		messageIDs = append(messageIDs, msg.MessageID())
+		return tanglegen.WriteMessage(stream, msg)
+	}))
+
+	generator, err := tanglegen.New(dbStorage, opts...)
+	require.NoError(t, err)
+
+	stats, err := generator.Generate(context.Background(), milestonesCount)
+	require.NoError(t, err)
+
+	ledgerIndex, err := dbStorage.UTXOManager().ReadLedgerIndex()
+	require.NoError(t, err)
+	require.EqualValues(t, milestonesCount, ledgerIndex)
+
+	// the total supply must not change
+	balance, _, err := dbStorage.UTXOManager().ComputeLedgerBalance()
+	require.NoError(t, err)
+	require.Equal(t, uint64(iotago.TokenSupply), balance)
+
+	return stats, messageIDs, stream
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+
+	opts := []tanglegen.Option{
+		tanglegen.WithSeed(42),
+		tanglegen.WithWidth(4),
+		tanglegen.WithMilestoneInterval(3),
+		tanglegen.WithTransactionRate(0.8),
+		tanglegen.WithConflictRate(0.2),
+	}
+
+	stats1, messageIDs1, _ := generateTangle(t, 10, opts...)
+	stats2, messageIDs2, _ := generateTangle(t, 10, opts...)
+
+	require.Equal(t, 10, stats1.Milestones)
+	require.Equal(t, 9*3*4, stats1.Messages)
+	require.Equal(t, stats1, stats2)
+	require.Equal(t, messageIDs1, messageIDs2)
+
+	// a different seed results in a different tangle
+	_, messageIDs3, _ := generateTangle(t, 10, append(opts, tanglegen.WithSeed(43))...)
+	require.Len(t, messageIDs3, len(messageIDs1))
+	require.NotEqual(t, messageIDs1, messageIDs3)
+}
+
+func TestGenerateConflicts(t *testing.T) {
+
+	var conflicts int
+	stats, _, _ := generateTangle(t, 10,
+		tanglegen.WithTransactionRate(1.0),
+		tanglegen.WithConflictRate(0.5),
+		tanglegen.WithMilestoneConfirmedFunc(func(confirmedMilestoneStats *whiteflag.ConfirmedMilestoneStats) {
+			conflicts += confirmedMilestoneStats.MessagesExcludedWithConflictingTransactions
+		}),
+	)
+
+	require.Greater(t, stats.Conflicts, 0)
+	require.Equal(t, conflicts, stats.Conflicts)
+
+	// without conflict rate, all transactions are applied
+	stats, _, _ = generateTangle(t, 10, tanglegen.WithTransactionRate(1.0), tanglegen.WithConflictRate(0))
+	require.Equal(t, 0, stats.Conflicts)
+}
+
+func TestGenerateInvalidOptions(t *testing.T) {
+
+	dbStorage, err := storage.New(mapdb.NewMapDB(), mapdb.NewMapDB())
+	require.NoError(t, err)
+	defer dbStorage.ShutdownStorages()
+
+	_, err = tanglegen.New(dbStorage, tanglegen.WithWidth(iotago.MaxParentsInAMessage))
+	require.ErrorIs(t, err, tanglegen.ErrInvalidWidth)
+
+	_, err = tanglegen.New(dbStorage, tanglegen.WithMilestoneInterval(0))
+	require.ErrorIs(t, err, tanglegen.ErrInvalidMilestoneInterval)
+
+	_, err = tanglegen.New(dbStorage, tanglegen.WithConflictRate(1.5))
+	require.ErrorIs(t, err, tanglegen.ErrInvalidRate)
+}
+
+func TestMessageStream(t *testing.T) {
+
+	_, messageIDs, stream := generateTangle(t, 3, tanglegen.WithSeed(1))
+
+	var readMessageIDs hornet.MessageIDs
+	for {
+		msg, err := tanglegen.ReadMessage(stream, deSeriParas)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		readMessageIDs = append(readMessageIDs, msg.MessageID())
+	}
+
+	require.Equal(t, messageIDs, readMessageIDs)
+}
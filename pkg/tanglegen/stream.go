@@ -0,0 +1,49 @@
+package tanglegen
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// WriteMessage writes the serialized message prefixed with its length to the writer.
+func WriteMessage(writer io.Writer, msg *storage.Message) error {
+
+	if err := binary.Write(writer, binary.LittleEndian, uint32(len(msg.Data()))); err != nil {
+		return fmt.Errorf("unable to write message length: %w", err)
+	}
+
+	if _, err := writer.Write(msg.Data()); err != nil {
+		return fmt.Errorf("unable to write message: %w", err)
+	}
+
+	return nil
+}
+
+// ReadMessage reads a message that was written with WriteMessage from the reader.
+// Returns io.EOF if there are no more messages in the stream.
+func ReadMessage(reader io.Reader, deSeriParas *iotago.DeSerializationParameters) (*storage.Message, error) {
+
+	var msgLength uint32
+	if err := binary.Read(reader, binary.LittleEndian, &msgLength); err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("unable to read message length: %w", err)
+	}
+
+	if msgLength > iotago.MessageBinSerializedMaxSize {
+		return nil, fmt.Errorf("message length too big: %d", msgLength)
+	}
+
+	data := make([]byte, msgLength)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return nil, fmt.Errorf("unable to read message: %w", err)
+	}
+
+	return storage.MessageFromBytes(data, serializer.DeSeriModePerformValidation, deSeriParas)
+}
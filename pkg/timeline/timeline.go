@@ -0,0 +1,154 @@
+package timeline
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+// Stage is a processing stage a message passes through.
+type Stage int
+
+const (
+	// StageReceived is the time the message was received from a peer or submitted to the node.
+	StageReceived Stage = iota
+	// StagePoWChecked is the time the message passed the validation including the PoW check.
+	StagePoWChecked
+	// StageStored is the time the message was stored in the database.
+	StageStored
+	// StageSolid is the time the message became solid.
+	StageSolid
+	// StageReferenced is the time the message was referenced by a milestone.
+	StageReferenced
+
+	stageCount
+)
+
+// String returns the name of the stage.
+func (s Stage) String() string {
+	switch s {
+	case StageReceived:
+		return "received"
+	case StagePoWChecked:
+		return "powChecked"
+	case StageStored:
+		return "stored"
+	case StageSolid:
+		return "solid"
+	case StageReferenced:
+		return "referenced"
+	default:
+		return fmt.Sprintf("unknown (%d)", s)
+	}
+}
+
+// StageTime is the time a message reached a stage.
+type StageTime struct {
+	Stage Stage
+	Time  time.Time
+}
+
+// Timeline holds the times a message reached the recorded stages.
+type Timeline struct {
+	MessageID hornet.MessageID
+	// the stages ordered by their definition, stages that were not recorded are omitted.
+	Stages []*StageTime
+}
+
+// the recorded times of a single message, a zero time means the stage was not recorded.
+type entry [stageCount]time.Time
+
+// Store keeps the timelines of the latest messages in a bounded ring.
+// If the store is full, the timeline of the oldest message is evicted.
+type Store struct {
+	sync.Mutex
+	entries map[string]*entry
+	// the keys of the entries in the order they were added.
+	ring []string
+	next int
+}
+
+// NewStore creates a store which keeps the timelines of up to capacity messages.
+func NewStore(capacity int) *Store {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &Store{
+		entries: make(map[string]*entry, capacity),
+		ring:    make([]string, 0, capacity),
+	}
+}
+
+// Record stores the time the message reached the given stage.
+// Only the first time of every stage is kept, e.g. if a message is received from several peers.
+func (s *Store) Record(messageID hornet.MessageID, stage Stage, ts time.Time) {
+	if stage < 0 || stage >= stageCount {
+		return
+	}
+
+	key := messageID.ToMapKey()
+
+	s.Lock()
+	defer s.Unlock()
+
+	e, exists := s.entries[key]
+	if !exists {
+		e = &entry{}
+		s.add(key, e)
+	}
+
+	if e[stage].IsZero() {
+		e[stage] = ts
+	}
+}
+
+// add inserts the entry and evicts the oldest entry if the store is full.
+// write lock must be acquired outside.
+func (s *Store) add(key string, e *entry) {
+	s.entries[key] = e
+
+	if len(s.ring) < cap(s.ring) {
+		s.ring = append(s.ring, key)
+		return
+	}
+
+	delete(s.entries, s.ring[s.next])
+	s.ring[s.next] = key
+	s.next = (s.next + 1) % len(s.ring)
+}
+
+// Timeline returns the recorded timeline of the given message.
+func (s *Store) Timeline(messageID hornet.MessageID) (*Timeline, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	e, exists := s.entries[messageID.ToMapKey()]
+	if !exists {
+		return nil, false
+	}
+
+	timeline := &Timeline{
+		MessageID: messageID,
+		Stages:    make([]*StageTime, 0, stageCount),
+	}
+
+	for stage, ts := range e {
+		if ts.IsZero() {
+			continue
+		}
+		timeline.Stages = append(timeline.Stages, &StageTime{Stage: Stage(stage), Time: ts})
+	}
+
+	return timeline, true
+}
+
+// Len returns the amount of messages in the store.
+func (s *Store) Len() int {
+	s.Lock()
+	defer s.Unlock()
+
+	return len(s.entries)
+}
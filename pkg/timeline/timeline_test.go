@@ -0,0 +1,63 @@
+package timeline_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
+	"github.com/gohornet/hornet/pkg/timeline"
+)
+
+func TestStoreRecord(t *testing.T) {
+	store := timeline.NewStore(10)
+
+	messageID := utils.RandMessageID()
+	now := time.Now()
+
+	store.Record(messageID, timeline.StageReceived, now)
+	store.Record(messageID, timeline.StageSolid, now.Add(2*time.Second))
+	store.Record(messageID, timeline.StagePoWChecked, now.Add(time.Second))
+
+	// only the first time of a stage is kept
+	store.Record(messageID, timeline.StageReceived, now.Add(time.Minute))
+
+	tl, exists := store.Timeline(messageID)
+	require.True(t, exists)
+	require.Equal(t, messageID, tl.MessageID)
+	require.Equal(t, []*timeline.StageTime{
+		{Stage: timeline.StageReceived, Time: now},
+		{Stage: timeline.StagePoWChecked, Time: now.Add(time.Second)},
+		{Stage: timeline.StageSolid, Time: now.Add(2 * time.Second)},
+	}, tl.Stages)
+
+	_, exists = store.Timeline(utils.RandMessageID())
+	require.False(t, exists)
+}
+
+func TestStoreEviction(t *testing.T) {
+	store := timeline.NewStore(3)
+
+	messageIDs := hornet.MessageIDs{}
+	for i := 0; i < 5; i++ {
+		messageID := utils.RandMessageID()
+		messageIDs = append(messageIDs, messageID)
+		store.Record(messageID, timeline.StageReceived, time.Now())
+	}
+
+	require.Equal(t, 3, store.Len())
+
+	// the oldest messages were evicted
+	for i, messageID := range messageIDs {
+		_, exists := store.Timeline(messageID)
+		require.Equal(t, i >= 2, exists)
+	}
+
+	// updating an existing message doesn't evict another one
+	store.Record(messageIDs[4], timeline.StageStored, time.Now())
+	require.Equal(t, 3, store.Len())
+	_, exists := store.Timeline(messageIDs[2])
+	require.True(t, exists)
+}
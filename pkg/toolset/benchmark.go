@@ -16,6 +16,7 @@ import (
 	flag "github.com/spf13/pflag"
 
 	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/pow"
 	"github.com/gohornet/hornet/pkg/utils"
 	"github.com/iotaledger/hive.go/kvstore"
 )
@@ -197,3 +198,68 @@ func benchmarkCPU(args []string) error {
 
 	return nil
 }
+
+func benchmarkPoW(args []string) error {
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	maxThreadsFlag := fs.Int(FlagToolBenchmarkThreads, runtime.NumCPU(), "the highest thread count to benchmark")
+	durationFlag := fs.Duration(FlagToolBenchmarkDuration, 10*time.Second, "the duration of the benchmark of every thread count")
+	messageSizeFlag := fs.Int(FlagToolBenchmarkSize, 300, "the size of the mined messages in bytes")
+	targetScoreFlag := fs.Float64(FlagToolPoWBenchmarkTargetScore, 4000, "the minimum PoW score required by the network")
+	backendFlag := fs.String(FlagToolPoWWorkerBackend, pow.BackendAuto, fmt.Sprintf("the PoW backend (available: %s)", strings.Join(pow.AvailableBackends(), ", ")))
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", ToolBenchmarkPoW)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s --%s %d --%s 10s --%s %d",
+			ToolBenchmarkPoW,
+			FlagToolBenchmarkThreads,
+			4,
+			FlagToolBenchmarkDuration,
+			FlagToolPoWBenchmarkTargetScore,
+			4000))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	if *maxThreadsFlag < 1 {
+		return fmt.Errorf("'%s' must be at least 1", FlagToolBenchmarkThreads)
+	}
+
+	worker, backend, err := pow.NewBackendWorker(*backendFlag)
+	if err != nil {
+		return err
+	}
+
+	threadCounts := pow.BenchmarkThreadCounts(*maxThreadsFlag)
+	fmt.Printf("Benchmarking PoW backend %s with %d byte messages and target score %0.0f (%v per thread count, takes %v)...\n",
+		backend, *messageSizeFlag, *targetScoreFlag, *durationFlag, time.Duration(len(threadCounts))*(*durationFlag))
+
+	results := make([]*pow.BenchmarkResult, 0, len(threadCounts))
+	for _, threads := range threadCounts {
+		threadResults, err := pow.Benchmark(context.Background(), worker, *targetScoreFlag, *messageSizeFlag, []int{threads}, *durationFlag)
+		if err != nil {
+			return err
+		}
+		result := threadResults[0]
+		results = append(results, result)
+
+		fmt.Printf("%3d thread(s): %0.2fMH/s, %0.2f messages/s (%d messages, took %v)\n",
+			result.Threads, result.HashesPerSecond/1000000, result.MessagesPerSecond, result.Messages, result.Duration.Truncate(time.Millisecond))
+	}
+
+	suggested := pow.SuggestedThreads(results)
+	if suggested == nil || suggested.Messages == 0 {
+		fmt.Println("\nNo message was mined, increase the duration to get a suggestion.")
+		return nil
+	}
+
+	// every spammer worker mines its messages with a single thread, and the faucet mines one message at a time with all of its workers.
+	fmt.Printf("\nSuggested settings (%0.2f messages/s):\n", suggested.MessagesPerSecond)
+	fmt.Printf("  spammer.workers: %d\n", suggested.Threads)
+	fmt.Printf("  faucet.powWorkerCount: %d\n", suggested.Threads)
+
+	return nil
+}
@@ -0,0 +1,442 @@
+package toolset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	flag "github.com/spf13/pflag"
+
+	"github.com/gohornet/hornet/pkg/p2p"
+	"github.com/gohornet/hornet/pkg/utils"
+)
+
+// configKeyMigration describes a config key that was renamed or removed in a later HORNET version.
+type configKeyMigration struct {
+	key string
+	// newKey is empty if the key was removed.
+	newKey string
+	// reason explains why the key was removed.
+	reason string
+}
+
+// configKeyMigrations are applied to the node config in the given order.
+var configKeyMigrations = []*configKeyMigration{
+	{key: "restAPI.permittedRoutes", newKey: "restAPI.publicRoutes"},
+	{key: "restAPI.whitelistedAddresses", reason: "routes are protected by JWT only, see \"restAPI.protectedRoutes\""},
+	{key: "restAPI.excludeHealthCheckFromAuth", reason: "the health route is part of \"restAPI.publicRoutes\""},
+	{key: "restAPI.jwtAuth.enabled", reason: "JWT auth is always enabled for \"restAPI.protectedRoutes\""},
+	{key: "p2p.peerStore.path", newKey: "p2p.db.path"},
+	{key: "p2p.dirPath", newKey: "p2p.db.path"},
+	{key: "p2p.autopeering.db.path", reason: "the autopeering database is stored in \"p2p.db.path\""},
+	{key: "p2p.gossipUnknownPeersLimit", newKey: "p2p.gossip.unknownPeersLimit"},
+	{key: "receipts.backup.folder", newKey: "receipts.backup.path"},
+	{key: "pruning.enabled", newKey: "pruning.milestones.enabled"},
+	{key: "pruning.delay", newKey: "pruning.milestones.maxMilestonesToKeep"},
+	{key: "faucet.indexationMessage", newKey: "faucet.tagMessage"},
+	{key: "p2p.peers", reason: "static peers are moved to the peering config"},
+	{key: "p2p.peerAliases", reason: "static peers are moved to the peering config"},
+}
+
+// legacyPeeringKeys are the keys of the legacy peering and neighbors configs which have no successor.
+var legacyPeeringKeys = map[string]string{
+	"acceptAnyConnection":  "unknown peers are limited by \"p2p.gossip.unknownPeersLimit\"",
+	"autotetheringenabled": "unknown peers are limited by \"p2p.gossip.unknownPeersLimit\"",
+	"maxPeers":             "connections are limited by \"p2p.connectionManager.highWatermark\"",
+	"maxneighbors":         "connections are limited by \"p2p.connectionManager.highWatermark\"",
+}
+
+// renamedConfigKey is a config key that was moved to a new key.
+type renamedConfigKey struct {
+	OldKey string `json:"oldKey"`
+	NewKey string `json:"newKey"`
+}
+
+// removedConfigKey is a config key that was dropped.
+type removedConfigKey struct {
+	Key    string `json:"key"`
+	Reason string `json:"reason"`
+}
+
+// droppedPeer is a peer of the source configs that could not be migrated.
+type droppedPeer struct {
+	Identity string `json:"identity"`
+	Alias    string `json:"alias,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+// configMigrationReport contains all changes of a config migration.
+type configMigrationReport struct {
+	Renamed       []*renamedConfigKey `json:"renamed"`
+	Removed       []*removedConfigKey `json:"removed"`
+	MigratedPeers int                 `json:"migratedPeers"`
+	DroppedPeers  []*droppedPeer      `json:"droppedPeers"`
+}
+
+func newConfigMigrationReport() *configMigrationReport {
+	return &configMigrationReport{
+		Renamed:      []*renamedConfigKey{},
+		Removed:      []*removedConfigKey{},
+		DroppedPeers: []*droppedPeer{},
+	}
+}
+
+// findConfigKey returns the map that contains the last segment of the dotted key and the name of the entry.
+// The keys are compared case-insensitive, like the node does when it loads the config.
+func findConfigKey(config map[string]interface{}, key string) (map[string]interface{}, string, bool) {
+	segments := strings.Split(key, ".")
+
+	current := config
+	for i, segment := range segments {
+		var name string
+		for k := range current {
+			if strings.EqualFold(k, segment) {
+				name = k
+				break
+			}
+		}
+		if name == "" {
+			return nil, "", false
+		}
+
+		if i == len(segments)-1 {
+			return current, name, true
+		}
+
+		next, ok := current[name].(map[string]interface{})
+		if !ok {
+			return nil, "", false
+		}
+		current = next
+	}
+
+	return nil, "", false
+}
+
+// removeConfigKey removes the dotted key and all parents that are empty afterwards.
+func removeConfigKey(config map[string]interface{}, key string) (interface{}, bool) {
+	parent, name, found := findConfigKey(config, key)
+	if !found {
+		return nil, false
+	}
+
+	value := parent[name]
+	delete(parent, name)
+
+	if len(parent) == 0 {
+		if idx := strings.LastIndex(key, "."); idx != -1 {
+			removeConfigKey(config, key[:idx])
+		}
+	}
+
+	return value, true
+}
+
+// setConfigKey sets the value of the dotted key and creates the missing parents.
+func setConfigKey(config map[string]interface{}, key string, value interface{}) {
+	segments := strings.Split(key, ".")
+
+	current := config
+	for _, segment := range segments[:len(segments)-1] {
+		for k := range current {
+			if strings.EqualFold(k, segment) {
+				segment = k
+				break
+			}
+		}
+
+		next, ok := current[segment].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[segment] = next
+		}
+		current = next
+	}
+
+	current[segments[len(segments)-1]] = value
+}
+
+// migrateConfigKeys renames and removes the outdated keys of the node config.
+func migrateConfigKeys(config map[string]interface{}, report *configMigrationReport) {
+	for _, migration := range configKeyMigrations {
+		value, found := removeConfigKey(config, migration.key)
+		if !found {
+			continue
+		}
+
+		if migration.newKey == "" {
+			report.Removed = append(report.Removed, &removedConfigKey{Key: migration.key, Reason: migration.reason})
+			continue
+		}
+
+		if _, _, exists := findConfigKey(config, migration.newKey); exists {
+			report.Removed = append(report.Removed, &removedConfigKey{Key: migration.key, Reason: fmt.Sprintf("superseded by the already existing key %q", migration.newKey)})
+			continue
+		}
+
+		setConfigKey(config, migration.newKey, value)
+		report.Renamed = append(report.Renamed, &renamedConfigKey{OldKey: migration.key, NewKey: migration.newKey})
+	}
+}
+
+// peerConfigFromIdentity converts the identity of a peer to a multiaddress.
+// Legacy identities in the form of "host:port" can't be converted, since they don't contain the peer ID.
+func peerConfigFromIdentity(identity string, alias string) (*p2p.PeerConfig, error) {
+	multiAddress, err := multiaddr.NewMultiaddr(identity)
+	if err != nil {
+		return nil, fmt.Errorf("not a multiaddress, the libp2p peer ID of the peer is needed")
+	}
+
+	if _, err := peer.AddrInfoFromP2pAddr(multiAddress); err != nil {
+		return nil, fmt.Errorf("the multiaddress doesn't contain the libp2p peer ID: %w", err)
+	}
+
+	return &p2p.PeerConfig{
+		MultiAddress: multiAddress.String(),
+		Alias:        alias,
+	}, nil
+}
+
+func stringFromConfigValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return ""
+}
+
+func stringsFromConfigValue(value interface{}) []string {
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		result = append(result, stringFromConfigValue(v))
+	}
+	return result
+}
+
+// migratePeers collects the static peers of the current and legacy peering configs,
+// the legacy neighbors config and the peers that were defined in the node config.
+func migratePeers(config map[string]interface{}, peering map[string]interface{}, report *configMigrationReport) []*p2p.PeerConfig {
+
+	peers := []*p2p.PeerConfig{}
+	seen := make(map[string]struct{})
+
+	addPeer := func(identity string, alias string, transport string, reconnect interface{}) {
+		peerConfig, err := peerConfigFromIdentity(identity, alias)
+		if err != nil {
+			report.DroppedPeers = append(report.DroppedPeers, &droppedPeer{Identity: identity, Alias: alias, Reason: err.Error()})
+			return
+		}
+
+		if _, exists := seen[peerConfig.MultiAddress]; exists {
+			return
+		}
+		seen[peerConfig.MultiAddress] = struct{}{}
+
+		peerConfig.Transport = transport
+		if reconnect != nil {
+			// the reconnect config didn't change since it was introduced
+			reconnectConfig := &p2p.PeerReconnectConfig{}
+			if data, err := json.Marshal(reconnect); err == nil && json.Unmarshal(data, reconnectConfig) == nil {
+				peerConfig.Reconnect = reconnectConfig
+			}
+		}
+
+		peers = append(peers, peerConfig)
+	}
+
+	addPeerEntries := func(entries interface{}) {
+		list, ok := entries.([]interface{})
+		if !ok {
+			return
+		}
+
+		for _, entry := range list {
+			fields, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			var identity, alias, transport string
+			var reconnect interface{}
+			for k, v := range fields {
+				switch strings.ToLower(k) {
+				case "multiaddress", "identity":
+					identity = stringFromConfigValue(v)
+				case "alias":
+					alias = stringFromConfigValue(v)
+				case "transport":
+					transport = stringFromConfigValue(v)
+				case "reconnect":
+					reconnect = v
+				}
+			}
+			addPeer(identity, alias, transport, reconnect)
+		}
+	}
+
+	addPeerList := func(source map[string]interface{}) {
+		peersParent, peersKey, found := findConfigKey(source, "p2p.peers")
+		if !found {
+			return
+		}
+		identities := stringsFromConfigValue(peersParent[peersKey])
+
+		var aliases []string
+		if aliasParent, aliasesKey, found := findConfigKey(source, "p2p.peerAliases"); found {
+			aliases = stringsFromConfigValue(aliasParent[aliasesKey])
+		}
+
+		for i, identity := range identities {
+			var alias string
+			if len(aliases) == len(identities) {
+				alias = aliases[i]
+			}
+			addPeer(identity, alias, "", nil)
+		}
+	}
+
+	if peering != nil {
+		for _, key := range []string{"peers", "neighbors"} {
+			if parent, name, found := findConfigKey(peering, key); found {
+				addPeerEntries(parent[name])
+			}
+		}
+		addPeerList(peering)
+
+		for key, reason := range legacyPeeringKeys {
+			if _, _, found := findConfigKey(peering, key); found {
+				report.Removed = append(report.Removed, &removedConfigKey{Key: key, Reason: reason})
+			}
+		}
+	}
+
+	// peers in the node config need to be collected before the keys get removed
+	addPeerList(config)
+
+	report.MigratedPeers = len(peers)
+
+	return peers
+}
+
+// readConfigFile reads a JSON config file without converting the numbers to floats.
+func readConfigFile(filePath string) (map[string]interface{}, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file %s: %w", filePath, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	config := make(map[string]interface{})
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("unable to parse config file %s: %w", filePath, err)
+	}
+
+	return config, nil
+}
+
+func migrateConfig(args []string) error {
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	configPathFlag := fs.String(FlagToolConfigPath, DefaultValueConfigPath, "the path to the config file of the previous version")
+	peeringConfigPathFlag := fs.String(FlagToolPeeringConfigPath, DefaultValuePeeringConfigPath, "the path to the peering or legacy neighbors config file of the previous version (optional)")
+	configPathTargetFlag := fs.String(FlagToolConfigPathTarget, DefaultValueConfigPathTarget, "the path to the migrated config file")
+	peeringConfigPathTargetFlag := fs.String(FlagToolPeeringConfigPathTarget, DefaultValuePeeringConfigPathTarget, "the path to the migrated peering config file")
+	outputJSONFlag := fs.Bool(FlagToolOutputJSON, false, FlagToolDescriptionOutputJSON)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", ToolConfigMigrate)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s --%s %s --%s %s --%s %s --%s %s",
+			ToolConfigMigrate,
+			FlagToolConfigPath,
+			DefaultValueConfigPath,
+			FlagToolPeeringConfigPath,
+			DefaultValuePeeringConfigPath,
+			FlagToolConfigPathTarget,
+			DefaultValueConfigPathTarget,
+			FlagToolPeeringConfigPathTarget,
+			DefaultValuePeeringConfigPathTarget))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	if len(*configPathFlag) == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolConfigPath)
+	}
+	if len(*configPathTargetFlag) == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolConfigPathTarget)
+	}
+	if len(*peeringConfigPathTargetFlag) == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolPeeringConfigPathTarget)
+	}
+
+	for _, targetPath := range []string{*configPathTargetFlag, *peeringConfigPathTargetFlag} {
+		if _, err := os.Stat(targetPath); err == nil || !os.IsNotExist(err) {
+			return fmt.Errorf("'%s' already exists", targetPath)
+		}
+	}
+
+	config, err := readConfigFile(*configPathFlag)
+	if err != nil {
+		return err
+	}
+
+	var peering map[string]interface{}
+	if len(*peeringConfigPathFlag) > 0 {
+		if _, err := os.Stat(*peeringConfigPathFlag); err == nil {
+			if peering, err = readConfigFile(*peeringConfigPathFlag); err != nil {
+				return err
+			}
+		}
+	}
+
+	report := newConfigMigrationReport()
+	peers := migratePeers(config, peering, report)
+	migrateConfigKeys(config, report)
+
+	if err := utils.WriteJSONToFile(*configPathTargetFlag, config, 0600); err != nil {
+		return err
+	}
+
+	if err := utils.WriteJSONToFile(*peeringConfigPathTargetFlag, &struct {
+		Peers []*p2p.PeerConfig `json:"peers"`
+	}{Peers: peers}, 0600); err != nil {
+		return err
+	}
+
+	if *outputJSONFlag {
+		return printJSON(report)
+	}
+
+	fmt.Printf("migrated config written to %s, peering config written to %s\n", *configPathTargetFlag, *peeringConfigPathTargetFlag)
+	for _, renamed := range report.Renamed {
+		fmt.Printf("    > renamed: %s -> %s\n", renamed.OldKey, renamed.NewKey)
+	}
+	for _, removed := range report.Removed {
+		fmt.Printf("    > removed: %s (%s)\n", removed.Key, removed.Reason)
+	}
+	fmt.Printf("    > migrated peers: %d\n", report.MigratedPeers)
+	for _, dropped := range report.DroppedPeers {
+		fmt.Printf("    > dropped peer: %s %s(%s)\n", dropped.Identity, func() string {
+			if dropped.Alias == "" {
+				return ""
+			}
+			return fmt.Sprintf("'%s' ", dropped.Alias)
+		}(), dropped.Reason)
+	}
+
+	return nil
+}
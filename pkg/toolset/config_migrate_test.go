@@ -0,0 +1,103 @@
+package toolset
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/p2p"
+)
+
+const (
+	migrateTestPeer = "/ip4/127.0.0.1/tcp/15600/p2p/12D3KooWCKwcTWevoRKa2kEBputeGASvEBuDfRDSbe8t1DWugUmL"
+)
+
+func parseMigrateTestConfig(t *testing.T, data string) map[string]interface{} {
+	config := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal([]byte(data), &config))
+	return config
+}
+
+func TestMigrateConfigKeys(t *testing.T) {
+
+	config := parseMigrateTestConfig(t, `{
+		"restAPI": {
+			"jwtAuth": {"enabled": true, "salt": "HORNET"},
+			"permittedRoutes": ["/health"],
+			"whitelistedAddresses": ["127.0.0.1"]
+		},
+		"p2p": {
+			"peerStore": {"path": "./p2pstore"},
+			"gossipUnknownPeersLimit": 4
+		},
+		"pruning": {
+			"enabled": true,
+			"delay": 60480,
+			"milestones": {"maxMilestonesToKeep": 100}
+		}
+	}`)
+
+	report := newConfigMigrationReport()
+	migrateConfigKeys(config, report)
+
+	require.Equal(t, parseMigrateTestConfig(t, `{
+		"restAPI": {
+			"jwtAuth": {"salt": "HORNET"},
+			"publicRoutes": ["/health"]
+		},
+		"p2p": {
+			"db": {"path": "./p2pstore"},
+			"gossip": {"unknownPeersLimit": 4}
+		},
+		"pruning": {
+			"milestones": {"enabled": true, "maxMilestonesToKeep": 100}
+		}
+	}`), config)
+
+	require.ElementsMatch(t, []*renamedConfigKey{
+		{OldKey: "restAPI.permittedRoutes", NewKey: "restAPI.publicRoutes"},
+		{OldKey: "p2p.peerStore.path", NewKey: "p2p.db.path"},
+		{OldKey: "p2p.gossipUnknownPeersLimit", NewKey: "p2p.gossip.unknownPeersLimit"},
+		{OldKey: "pruning.enabled", NewKey: "pruning.milestones.enabled"},
+	}, report.Renamed)
+
+	removedKeys := make([]string, 0, len(report.Removed))
+	for _, removed := range report.Removed {
+		removedKeys = append(removedKeys, removed.Key)
+	}
+	require.ElementsMatch(t, []string{"restAPI.whitelistedAddresses", "restAPI.jwtAuth.enabled", "pruning.delay"}, removedKeys)
+}
+
+func TestMigratePeers(t *testing.T) {
+
+	config := parseMigrateTestConfig(t, `{
+		"p2p": {
+			"peers": ["`+migrateTestPeer+`"],
+			"peerAliases": ["duplicate"]
+		}
+	}`)
+
+	legacyPeering := parseMigrateTestConfig(t, `{
+		"acceptAnyConnection": false,
+		"maxPeers": 5,
+		"peers": [
+			{"identity": "example.neighbor.com:15600", "alias": "legacy", "preferIPv6": false},
+			{"identity": "`+migrateTestPeer+`", "alias": "node"}
+		]
+	}`)
+
+	report := newConfigMigrationReport()
+	peers := migratePeers(config, legacyPeering, report)
+
+	require.Equal(t, []*p2p.PeerConfig{{MultiAddress: migrateTestPeer, Alias: "node"}}, peers)
+	require.Equal(t, 1, report.MigratedPeers)
+	require.Len(t, report.DroppedPeers, 1)
+	require.Equal(t, "example.neighbor.com:15600", report.DroppedPeers[0].Identity)
+	require.Equal(t, "legacy", report.DroppedPeers[0].Alias)
+	require.Len(t, report.Removed, 2)
+
+	// the peers are removed from the node config afterwards
+	migrateConfigKeys(config, report)
+	require.Empty(t, config)
+}
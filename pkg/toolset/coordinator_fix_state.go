@@ -90,27 +90,8 @@ func coordinatorFixStateFile(args []string) error {
 	}
 	defer cachedMs.Release(true) // milestone -1
 
-	_, err = os.Stat(coordinatorStateFilePath)
-	if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("unable to check '%s' (%s), error: %w", FlagToolCoordinatorFixStateCooStateFilePath, coordinatorStateFilePath, err)
-	}
-
-	if err == nil {
-		// coordinator state file exists => rename it to not overwrite the original
-		backupFilePath := fmt.Sprintf("%s_backup", coordinatorStateFilePath)
-
-		_, err = os.Stat(backupFilePath)
-		if err != nil && !os.IsNotExist(err) {
-			return fmt.Errorf("unable to check backup file path (%s), error: %w", backupFilePath, err)
-		}
-
-		if err == nil {
-			return fmt.Errorf("backup file path already exists (%s), will not proceed to overwrite old backup file", backupFilePath)
-		}
-
-		if err := os.Rename(coordinatorStateFilePath, backupFilePath); err != nil {
-			return fmt.Errorf("unable to rename coordinator state file, error: %w", err)
-		}
+	if err := backupCoordinatorStateFile(coordinatorStateFilePath); err != nil {
+		return err
 	}
 
 	// state of the coordinator holds information about the last issued milestones.
@@ -0,0 +1,319 @@
+package toolset
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gohornet/hornet/pkg/model/coordinator"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/utils"
+)
+
+const (
+	// the version of the coordinator state backup format.
+	cooStateBackupVersion = 1
+)
+
+// cooStateBackup is the content of a coordinator state backup.
+type cooStateBackup struct {
+	// the version of the backup format.
+	Version int `json:"version"`
+	// the state of the coordinator.
+	State *coordinator.State `json:"state"`
+	// the network ID of the database the backup was validated with (0 = not validated).
+	NetworkID uint64 `json:"networkID,omitempty"`
+	// the parents of the latest milestone message, they contain the latest checkpoint of the coordinator.
+	LatestMilestoneParents []string `json:"latestMilestoneParents,omitempty"`
+	// the sha256 checksum of the backup without the checksum.
+	Checksum string `json:"checksum"`
+}
+
+// calculateChecksum returns the sha256 checksum of the backup without the checksum.
+func (b *cooStateBackup) calculateChecksum() (string, error) {
+	backupWithoutChecksum := *b
+	backupWithoutChecksum.Checksum = ""
+
+	backupBytes, err := json.Marshal(&backupWithoutChecksum)
+	if err != nil {
+		return "", err
+	}
+
+	checksum := sha256.Sum256(backupBytes)
+	return hex.EncodeToString(checksum[:]), nil
+}
+
+// latestMilestoneInfo returns the latest milestone message of the database and its parents.
+// The ledger of the database needs to be at the latest milestone, otherwise the node is not synchronized.
+func latestMilestoneInfo(dbStorage *storage.Storage) (*coordinator.State, hornet.MessageIDs, error) {
+
+	ledgerIndex, err := dbStorage.UTXOManager().ReadLedgerIndex()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	latestMilestoneFromDatabase := dbStorage.SearchLatestMilestoneIndexInStore()
+
+	if ledgerIndex != latestMilestoneFromDatabase {
+		return nil, nil, fmt.Errorf("node is not synchronized (solid milestone index: %d, latest milestone index: %d)", ledgerIndex, latestMilestoneFromDatabase)
+	}
+
+	cachedMs := dbStorage.CachedMilestoneOrNil(ledgerIndex) // milestone +1
+	if cachedMs == nil {
+		return nil, nil, fmt.Errorf("milestone %d not found", ledgerIndex)
+	}
+	defer cachedMs.Release(true) // milestone -1
+
+	cachedMsg := dbStorage.CachedMessageOrNil(cachedMs.Milestone().MessageID) // message +1
+	if cachedMsg == nil {
+		return nil, nil, fmt.Errorf("message of milestone %d not found: %s", ledgerIndex, cachedMs.Milestone().MessageID.ToHex())
+	}
+	defer cachedMsg.Release(true) // message -1
+
+	return &coordinator.State{
+		LatestMilestoneIndex:     ledgerIndex,
+		LatestMilestoneMessageID: cachedMs.Milestone().MessageID,
+		LatestMilestoneTime:      cachedMs.Milestone().Timestamp,
+	}, cachedMsg.Message().Parents(), nil
+}
+
+// backupCoordinatorStateFile renames an existing coordinator state file to not overwrite the original.
+func backupCoordinatorStateFile(coordinatorStateFilePath string) error {
+
+	_, err := os.Stat(coordinatorStateFilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to check '%s' (%s), error: %w", FlagToolCoordinatorFixStateCooStateFilePath, coordinatorStateFilePath, err)
+	}
+
+	if err != nil {
+		// coordinator state file doesn't exist
+		return nil
+	}
+
+	backupFilePath := fmt.Sprintf("%s_backup", coordinatorStateFilePath)
+
+	_, err = os.Stat(backupFilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to check backup file path (%s), error: %w", backupFilePath, err)
+	}
+
+	if err == nil {
+		return fmt.Errorf("backup file path already exists (%s), will not proceed to overwrite old backup file", backupFilePath)
+	}
+
+	if err := os.Rename(coordinatorStateFilePath, backupFilePath); err != nil {
+		return fmt.Errorf("unable to rename coordinator state file, error: %w", err)
+	}
+
+	return nil
+}
+
+func coordinatorStateExport(args []string) error {
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	databasePathFlag := fs.String(FlagToolDatabasePath, "", "the path to the database the state is validated with (optional, the node must not be running)")
+	cooStateFilePathFlag := fs.String(FlagToolCoordinatorFixStateCooStateFilePath, DefaultValueCoordinatorStateFilePath, "the path to the coordinator state file")
+	backupPathFlag := fs.String(FlagToolCoordinatorStateBackupPath, DefaultValueCoordinatorStateBackupPath, "the file path of the backup")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", ToolCoordinatorStateExport)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s --%s %s --%s %s --%s %s",
+			ToolCoordinatorStateExport,
+			FlagToolDatabasePath,
+			DefaultValueMainnetDatabasePath,
+			FlagToolCoordinatorFixStateCooStateFilePath,
+			DefaultValueCoordinatorStateFilePath,
+			FlagToolCoordinatorStateBackupPath,
+			DefaultValueCoordinatorStateBackupPath))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	if len(*cooStateFilePathFlag) == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolCoordinatorFixStateCooStateFilePath)
+	}
+
+	if len(*backupPathFlag) == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolCoordinatorStateBackupPath)
+	}
+
+	state := &coordinator.State{}
+	if err := utils.ReadJSONFromFile(*cooStateFilePathFlag, state); err != nil {
+		return fmt.Errorf("failed to read coordinator state file (%s), error: %w", *cooStateFilePathFlag, err)
+	}
+
+	backup := &cooStateBackup{
+		Version: cooStateBackupVersion,
+		State:   state,
+	}
+
+	if len(*databasePathFlag) > 0 {
+		databasePath := *databasePathFlag
+		if _, err := os.Stat(databasePath); err != nil || os.IsNotExist(err) {
+			return fmt.Errorf("'%s' (%s) does not exist", FlagToolDatabasePath, databasePath)
+		}
+
+		dbStorage, closeStorage, err := openLedgerStorage(databasePath)
+		if err != nil {
+			return err
+		}
+		defer closeStorage()
+
+		databaseState, parents, err := latestMilestoneInfo(dbStorage)
+		if err != nil {
+			return err
+		}
+
+		if databaseState.LatestMilestoneIndex != state.LatestMilestoneIndex || !bytes.Equal(databaseState.LatestMilestoneMessageID, state.LatestMilestoneMessageID) {
+			return fmt.Errorf("coordinator state (milestone %d, message %s) doesn't match the latest milestone in the database (milestone %d, message %s)",
+				state.LatestMilestoneIndex, state.LatestMilestoneMessageID.ToHex(), databaseState.LatestMilestoneIndex, databaseState.LatestMilestoneMessageID.ToHex())
+		}
+
+		backup.NetworkID = dbStorage.SnapshotInfo().NetworkID
+		backup.LatestMilestoneParents = parents.ToHex()
+	}
+
+	checksum, err := backup.calculateChecksum()
+	if err != nil {
+		return fmt.Errorf("failed to calculate checksum, error: %w", err)
+	}
+	backup.Checksum = checksum
+
+	if err := utils.WriteJSONToFile(*backupPathFlag, backup, 0660); err != nil {
+		return fmt.Errorf("failed to write coordinator state backup (%s), error: %w", *backupPathFlag, err)
+	}
+
+	fmt.Printf("successfully exported coordinator state at milestone %d to %s (validated with database: %s)\n", state.LatestMilestoneIndex, *backupPathFlag, yesOrNo(backup.NetworkID != 0))
+
+	return nil
+}
+
+func coordinatorStateImport(args []string) error {
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	databasePathFlag := fs.String(FlagToolDatabasePath, DefaultValueMainnetDatabasePath, "the path to the database of the standby coordinator")
+	cooStateFilePathFlag := fs.String(FlagToolCoordinatorFixStateCooStateFilePath, DefaultValueCoordinatorStateFilePath, "the path to the coordinator state file")
+	backupPathFlag := fs.String(FlagToolCoordinatorStateBackupPath, DefaultValueCoordinatorStateBackupPath, "the file path of the backup")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", ToolCoordinatorStateImport)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s --%s %s --%s %s --%s %s",
+			ToolCoordinatorStateImport,
+			FlagToolDatabasePath,
+			DefaultValueMainnetDatabasePath,
+			FlagToolCoordinatorFixStateCooStateFilePath,
+			DefaultValueCoordinatorStateFilePath,
+			FlagToolCoordinatorStateBackupPath,
+			DefaultValueCoordinatorStateBackupPath))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	if len(*databasePathFlag) == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolDatabasePath)
+	}
+
+	if len(*cooStateFilePathFlag) == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolCoordinatorFixStateCooStateFilePath)
+	}
+
+	databasePath := *databasePathFlag
+	if _, err := os.Stat(databasePath); err != nil || os.IsNotExist(err) {
+		return fmt.Errorf("'%s' (%s) does not exist", FlagToolDatabasePath, databasePath)
+	}
+
+	backup := &cooStateBackup{}
+	if err := utils.ReadJSONFromFile(*backupPathFlag, backup); err != nil {
+		return fmt.Errorf("failed to read coordinator state backup (%s), error: %w", *backupPathFlag, err)
+	}
+
+	if backup.Version != cooStateBackupVersion {
+		return fmt.Errorf("unsupported coordinator state backup version: %d", backup.Version)
+	}
+
+	if backup.State == nil {
+		return fmt.Errorf("coordinator state backup (%s) contains no state", *backupPathFlag)
+	}
+
+	checksum, err := backup.calculateChecksum()
+	if err != nil {
+		return fmt.Errorf("failed to calculate checksum, error: %w", err)
+	}
+
+	if checksum != backup.Checksum {
+		return fmt.Errorf("checksum mismatch of coordinator state backup (%s), expected: %s, actual: %s", *backupPathFlag, backup.Checksum, checksum)
+	}
+
+	dbStorage, closeStorage, err := openLedgerStorage(databasePath)
+	if err != nil {
+		return err
+	}
+	defer closeStorage()
+
+	if backup.NetworkID != 0 && backup.NetworkID != dbStorage.SnapshotInfo().NetworkID {
+		return fmt.Errorf("network ID of the backup (%d) doesn't match the network ID of the database (%d)", backup.NetworkID, dbStorage.SnapshotInfo().NetworkID)
+	}
+
+	databaseState, parents, err := latestMilestoneInfo(dbStorage)
+	if err != nil {
+		return err
+	}
+
+	// the coordinator must never issue a milestone index twice, so the backup needs to be
+	// exactly at the latest milestone the standby node knows about.
+	switch {
+	case backup.State.LatestMilestoneIndex < databaseState.LatestMilestoneIndex:
+		return fmt.Errorf("coordinator state backup is outdated (backup milestone index: %d, latest milestone index in database: %d)", backup.State.LatestMilestoneIndex, databaseState.LatestMilestoneIndex)
+	case backup.State.LatestMilestoneIndex > databaseState.LatestMilestoneIndex:
+		return fmt.Errorf("database is not synchronized to the coordinator state backup (backup milestone index: %d, latest milestone index in database: %d)", backup.State.LatestMilestoneIndex, databaseState.LatestMilestoneIndex)
+	}
+
+	if !bytes.Equal(backup.State.LatestMilestoneMessageID, databaseState.LatestMilestoneMessageID) {
+		return fmt.Errorf("message of milestone %d in the backup (%s) doesn't match the database (%s)", databaseState.LatestMilestoneIndex, backup.State.LatestMilestoneMessageID.ToHex(), databaseState.LatestMilestoneMessageID.ToHex())
+	}
+
+	if backup.State.LatestMilestoneTime.Unix() != databaseState.LatestMilestoneTime.Unix() {
+		return fmt.Errorf("timestamp of milestone %d in the backup (%v) doesn't match the database (%v)", databaseState.LatestMilestoneIndex, backup.State.LatestMilestoneTime, databaseState.LatestMilestoneTime)
+	}
+
+	if len(backup.LatestMilestoneParents) > 0 {
+		backupParents, err := hornet.MessageIDsFromHex(backup.LatestMilestoneParents)
+		if err != nil {
+			return fmt.Errorf("invalid parents of the latest milestone in the backup, error: %w", err)
+		}
+
+		if len(backupParents) != len(parents) {
+			return fmt.Errorf("parents of milestone %d in the backup don't match the database", databaseState.LatestMilestoneIndex)
+		}
+
+		for i := range parents {
+			if !bytes.Equal(backupParents[i], parents[i]) {
+				return fmt.Errorf("parents of milestone %d in the backup don't match the database", databaseState.LatestMilestoneIndex)
+			}
+		}
+	}
+
+	if err := backupCoordinatorStateFile(*cooStateFilePathFlag); err != nil {
+		return err
+	}
+
+	if err := utils.WriteJSONToFile(*cooStateFilePathFlag, backup.State, 0660); err != nil {
+		return fmt.Errorf("failed to write coordinator state file (%s), error: %w", *cooStateFilePathFlag, err)
+	}
+
+	fmt.Printf("successfully imported coordinator state at milestone %d to %s\n", backup.State.LatestMilestoneIndex, *cooStateFilePathFlag)
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package toolset
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gohornet/hornet/pkg/indexer"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+func indexerExport(args []string) error {
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	databasePathFlag := fs.String(FlagToolDatabasePath, DefaultValueMainnetDatabasePath, "the path to the database")
+	indexerPathFlag := fs.String(FlagToolIndexerPath, "", "the path to the indexer database (default: '<databasePath>/indexer')")
+	exportPathFlag := fs.String(FlagToolIndexerExportPath, "", "the file path of the export")
+	ledgerIndexFlag := fs.Uint32(FlagToolIndexerLedgerIndex, 0, "the ledger index the index is exported at (default: the current ledger index of the index)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", ToolIndexerExport)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s --%s %s --%s %s",
+			ToolIndexerExport,
+			FlagToolDatabasePath,
+			DefaultValueMainnetDatabasePath,
+			FlagToolIndexerExportPath,
+			"indexer_export.db"))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	if len(*exportPathFlag) == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolIndexerExportPath)
+	}
+
+	indexerPath := *indexerPathFlag
+	if len(indexerPath) == 0 {
+		if len(*databasePathFlag) == 0 {
+			return fmt.Errorf("'%s' not specified", FlagToolDatabasePath)
+		}
+		indexerPath = filepath.Join(*databasePathFlag, "indexer")
+	}
+
+	if _, err := os.Stat(indexerPath); err != nil || os.IsNotExist(err) {
+		return fmt.Errorf("'%s' (%s) does not exist", FlagToolIndexerPath, indexerPath)
+	}
+
+	idx, err := indexer.NewIndexer(indexerPath)
+	if err != nil {
+		return fmt.Errorf("indexer database initialization failed: %w", err)
+	}
+
+	// clean up indexer
+	defer func() {
+		_ = idx.CloseDatabase()
+	}()
+
+	ts := time.Now()
+
+	fmt.Printf("exporting indexer in %s to %s...\n", indexerPath, *exportPathFlag)
+
+	ledgerIndex, err := idx.Export(*exportPathFlag, milestone.Index(*ledgerIndexFlag))
+	if err != nil {
+		return fmt.Errorf("exporting indexer failed: %w", err)
+	}
+
+	fmt.Printf("successfully exported indexer at ledger index %d, took %v\n", ledgerIndex, time.Since(ts).Truncate(time.Millisecond))
+
+	return nil
+}
+
+func indexerImport(args []string) error {
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	databasePathFlag := fs.String(FlagToolDatabasePath, DefaultValueMainnetDatabasePath, "the path to the database")
+	indexerPathFlag := fs.String(FlagToolIndexerPath, "", "the path to the indexer database (default: '<databasePath>/indexer')")
+	exportPathFlag := fs.String(FlagToolIndexerExportPath, "", "the file path of the export")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", ToolIndexerImport)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s --%s %s --%s %s",
+			ToolIndexerImport,
+			FlagToolDatabasePath,
+			DefaultValueMainnetDatabasePath,
+			FlagToolIndexerExportPath,
+			"indexer_export.db"))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	if len(*databasePathFlag) == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolDatabasePath)
+	}
+
+	if len(*exportPathFlag) == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolIndexerExportPath)
+	}
+
+	databasePath := *databasePathFlag
+	if _, err := os.Stat(databasePath); err != nil || os.IsNotExist(err) {
+		return fmt.Errorf("'%s' (%s) does not exist", FlagToolDatabasePath, databasePath)
+	}
+
+	indexerPath := *indexerPathFlag
+	if len(indexerPath) == 0 {
+		indexerPath = filepath.Join(databasePath, "indexer")
+	}
+
+	dbStorage, closeStorage, err := openLedgerStorage(databasePath)
+	if err != nil {
+		return err
+	}
+	defer closeStorage()
+
+	// the export needs to match the UTXO ledger, otherwise the node would rebuild the index at startup
+	ledgerIndex, err := dbStorage.UTXOManager().ReadLedgerIndex()
+	if err != nil {
+		return err
+	}
+
+	ts := time.Now()
+
+	fmt.Printf("importing indexer from %s to %s at ledger index %d...\n", *exportPathFlag, indexerPath, ledgerIndex)
+
+	if err := indexer.ImportDatabase(indexerPath, *exportPathFlag, ledgerIndex); err != nil {
+		return fmt.Errorf("importing indexer failed: %w", err)
+	}
+
+	fmt.Printf("successfully imported indexer at ledger index %d, took %v\n", ledgerIndex, time.Since(ts).Truncate(time.Millisecond))
+
+	return nil
+}
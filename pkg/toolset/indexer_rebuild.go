@@ -0,0 +1,140 @@
+package toolset
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	coreDatabase "github.com/gohornet/hornet/core/database"
+	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/indexer"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/utils"
+)
+
+const (
+	// indexerRebuildProgressInterval is the amount of imported outputs after which the progress is checked.
+	indexerRebuildProgressInterval = 1000
+)
+
+func indexerRebuild(args []string) error {
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	databasePathFlag := fs.String(FlagToolDatabasePath, DefaultValueMainnetDatabasePath, "the path to the database")
+	indexerPathFlag := fs.String(FlagToolIndexerPath, "", "the path to the indexer database (default: '<databasePath>/indexer')")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", ToolIndexerRebuild)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s --%s %s",
+			ToolIndexerRebuild,
+			FlagToolDatabasePath,
+			DefaultValueMainnetDatabasePath))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	if len(*databasePathFlag) == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolDatabasePath)
+	}
+
+	databasePath := *databasePathFlag
+	if _, err := os.Stat(databasePath); err != nil || os.IsNotExist(err) {
+		return fmt.Errorf("'%s' (%s) does not exist", FlagToolDatabasePath, databasePath)
+	}
+
+	indexerPath := *indexerPathFlag
+	if len(indexerPath) == 0 {
+		indexerPath = filepath.Join(databasePath, "indexer")
+	}
+
+	dbStorage, closeStorage, err := openLedgerStorage(databasePath)
+	if err != nil {
+		return err
+	}
+	defer closeStorage()
+
+	idx, err := indexer.NewIndexer(indexerPath)
+	if err != nil {
+		return fmt.Errorf("indexer database initialization failed: %w", err)
+	}
+
+	// clean up indexer
+	defer func() {
+		_ = idx.CloseDatabase()
+	}()
+
+	ts := time.Now()
+	lastStatusTime := time.Now()
+
+	fmt.Printf("rebuilding indexer in %s...\n", indexerPath)
+
+	if err := idx.Rebuild(dbStorage.UTXOManager(), indexerRebuildProgressInterval, func(progress *indexer.RebuildProgress) {
+		if progress.Done || time.Since(lastStatusTime) < printStatusInterval {
+			return
+		}
+		lastStatusTime = time.Now()
+
+		percentage, remaining := utils.EstimateRemainingTime(ts, int64(progress.ImportedOutputs), int64(progress.TotalOutputs))
+		fmt.Printf("Imported %d/%d outputs at ledger index %d, %0.2f%%. %v elapsed, %v left...\n", progress.ImportedOutputs, progress.TotalOutputs, progress.LedgerIndex, percentage, time.Since(ts).Truncate(time.Second), remaining.Truncate(time.Second))
+	}); err != nil {
+		return fmt.Errorf("rebuilding indexer failed: %w", err)
+	}
+
+	ledgerIndex, err := idx.LedgerIndex()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("successfully rebuilt indexer at ledger index %d, took %v\n", ledgerIndex, time.Since(ts).Truncate(time.Millisecond))
+
+	return nil
+}
+
+// openLedgerStorage opens the tangle and UTXO databases at databasePath and checks their version.
+// The returned function closes the databases again.
+func openLedgerStorage(databasePath string) (*storage.Storage, func(), error) {
+
+	tangleStore, err := database.StoreWithDefaultSettings(filepath.Join(databasePath, coreDatabase.TangleDatabaseDirectoryName), false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s database initialization failed: %w", coreDatabase.TangleDatabaseDirectoryName, err)
+	}
+
+	utxoStore, err := database.StoreWithDefaultSettings(filepath.Join(databasePath, coreDatabase.UTXODatabaseDirectoryName), false)
+	if err != nil {
+		tangleStore.Shutdown()
+		_ = tangleStore.Close()
+		return nil, nil, fmt.Errorf("%s database initialization failed: %w", coreDatabase.UTXODatabaseDirectoryName, err)
+	}
+
+	closeStores := func() {
+		tangleStore.Shutdown()
+		_ = tangleStore.Close()
+		utxoStore.Shutdown()
+		_ = utxoStore.Close()
+	}
+
+	dbStorage, err := storage.New(tangleStore, utxoStore)
+	if err != nil {
+		closeStores()
+		return nil, nil, err
+	}
+
+	correctVersion, err := dbStorage.CheckCorrectDatabasesVersion()
+	if err != nil {
+		closeStores()
+		return nil, nil, err
+	}
+
+	if !correctVersion {
+		closeStores()
+		return nil, nil, fmt.Errorf("database version outdated")
+	}
+
+	return dbStorage, closeStores, nil
+}
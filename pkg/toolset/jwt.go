@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/libp2p/go-libp2p-core/peer"
 	flag "github.com/spf13/pflag"
@@ -17,17 +18,20 @@ func generateJWTApiToken(args []string) error {
 	fs := flag.NewFlagSet("", flag.ContinueOnError)
 	databasePathFlag := fs.String(FlagToolDatabasePath, DefaultValueP2PDatabasePath, "the path to the p2p database folder")
 	apiJWTSaltFlag := fs.String(FlagToolSalt, DefaultValueAPIJWTTokenSalt, "salt used inside the JWT tokens for the REST API")
+	scopesFlag := fs.StringSlice(FlagToolScopes, []string{}, fmt.Sprintf("the scopes the JWT token is restricted to (%s), all routes are allowed if none are given", strings.Join(jwt.Scopes, ", ")))
 	outputJSONFlag := fs.Bool(FlagToolOutputJSON, false, FlagToolDescriptionOutputJSON)
 
 	fs.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage of %s:\n", ToolJWTApi)
 		fs.PrintDefaults()
-		println(fmt.Sprintf("\nexample: %s --%s %s --%s %s",
+		println(fmt.Sprintf("\nexample: %s --%s %s --%s %s --%s %s",
 			ToolJWTApi,
 			FlagToolDatabasePath,
 			DefaultValueP2PDatabasePath,
 			FlagToolSalt,
-			DefaultValueAPIJWTTokenSalt))
+			DefaultValueAPIJWTTokenSalt,
+			FlagToolScopes,
+			strings.Join([]string{jwt.ScopeAPIRead, jwt.ScopeAPISubmit}, ",")))
 	}
 
 	if err := parseFlagSet(fs, args); err != nil {
@@ -40,6 +44,9 @@ func generateJWTApiToken(args []string) error {
 	if len(*apiJWTSaltFlag) == 0 {
 		return fmt.Errorf("'%s' not specified", FlagToolSalt)
 	}
+	if err := jwt.ValidateScopes(*scopesFlag); err != nil {
+		return fmt.Errorf("'%s' contains an invalid scope: %w", FlagToolScopes, err)
+	}
 
 	databasePath := *databasePathFlag
 	privKeyFilePath := filepath.Join(databasePath, p2p.PrivKeyFileName)
@@ -79,7 +86,7 @@ func generateJWTApiToken(args []string) error {
 		return fmt.Errorf("JWT auth initialization failed: %w", err)
 	}
 
-	jwtToken, err := jwtAuth.IssueJWT(true, false)
+	jwtToken, err := jwtAuth.IssueJWT(true, false, *scopesFlag...)
 	if err != nil {
 		return fmt.Errorf("issuing JWT token failed: %w", err)
 	}
@@ -87,9 +94,11 @@ func generateJWTApiToken(args []string) error {
 	if *outputJSONFlag {
 
 		result := struct {
-			JWT string `json:"jwt"`
+			JWT    string   `json:"jwt"`
+			Scopes []string `json:"scopes,omitempty"`
 		}{
-			JWT: jwtToken,
+			JWT:    jwtToken,
+			Scopes: *scopesFlag,
 		}
 
 		return printJSON(result)
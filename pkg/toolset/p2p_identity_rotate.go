@@ -0,0 +1,78 @@
+package toolset
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gohornet/hornet/pkg/p2p"
+)
+
+func rotateP2PIdentity(args []string) error {
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	databasePathFlag := fs.String(FlagToolDatabasePath, DefaultValueP2PDatabasePath, "the path to the p2p database folder")
+	gracePeriodFlag := fs.Duration(FlagToolGracePeriod, 24*time.Hour, "the time the new identity is announced to the peers before it is activated")
+	outputJSONFlag := fs.Bool(FlagToolOutputJSON, false, FlagToolDescriptionOutputJSON)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", ToolP2PIdentityRotate)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s --%s %s --%s %s",
+			ToolP2PIdentityRotate,
+			FlagToolDatabasePath,
+			DefaultValueP2PDatabasePath,
+			FlagToolGracePeriod,
+			"24h"))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	if len(*databasePathFlag) == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolDatabasePath)
+	}
+
+	if *gracePeriodFlag < 0 {
+		return fmt.Errorf("'%s' must not be negative", FlagToolGracePeriod)
+	}
+
+	databasePath := *databasePathFlag
+	privKeyFilePath := filepath.Join(databasePath, p2p.PrivKeyFileName)
+
+	_, err := os.Stat(privKeyFilePath)
+	switch {
+	case os.IsNotExist(err):
+		// private key does not exist
+		return fmt.Errorf("private key file (%s) does not exist", privKeyFilePath)
+
+	case err == nil || os.IsExist(err):
+		// private key file exists
+
+	default:
+		return fmt.Errorf("unable to check private key file (%s): %w", privKeyFilePath, err)
+	}
+
+	privKey, err := p2p.ReadEd25519PrivateKeyFromPEMFile(privKeyFilePath)
+	if err != nil {
+		return fmt.Errorf("reading private key file for peer identity failed: %w", err)
+	}
+
+	rotation, err := p2p.StageIdentityRotation(databasePath, privKey, *gracePeriodFlag)
+	if err != nil {
+		return fmt.Errorf("staging identity rotation failed: %w", err)
+	}
+
+	if *outputJSONFlag {
+		return printJSON(rotation)
+	}
+
+	fmt.Println("Your current p2p PeerID:      ", rotation.OldID)
+	fmt.Println("Your next p2p PeerID:         ", rotation.NewID)
+	fmt.Println("Activated on restart after:   ", rotation.ActivationTime().Format(time.RFC1123))
+	return nil
+}
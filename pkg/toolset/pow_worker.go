@@ -0,0 +1,68 @@
+package toolset
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	flag "github.com/spf13/pflag"
+
+	"github.com/gohornet/hornet/pkg/pow"
+)
+
+func powWorker(args []string) error {
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	bindAddressFlag := fs.String(FlagToolPoWWorkerBindAddress, "localhost:14300", "the bind address of the remote PoW worker")
+	cpuThreadsFlag := fs.Int(FlagToolBenchmarkThreads, runtime.NumCPU(), "thread count")
+	backendFlag := fs.String(FlagToolPoWWorkerBackend, pow.BackendAuto, fmt.Sprintf("the PoW backend (available: %s)", strings.Join(pow.AvailableBackends(), ", ")))
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", ToolPoWWorker)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s --%s %s --%s %d",
+			ToolPoWWorker,
+			FlagToolPoWWorkerBindAddress,
+			"0.0.0.0:14300",
+			FlagToolBenchmarkThreads,
+			2))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	if *cpuThreadsFlag < 1 {
+		return fmt.Errorf("'%s' must be at least 1", FlagToolBenchmarkThreads)
+	}
+
+	worker, backend, err := pow.NewBackendWorker(*backendFlag)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:    *bindAddressFlag,
+		Handler: pow.NewRemoteWorkerServer(worker, *cpuThreadsFlag),
+	}
+
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signalChan
+		_ = server.Shutdown(context.Background())
+	}()
+
+	fmt.Printf("remote PoW worker listening on %s with %d threads using PoW backend %s\n", *bindAddressFlag, *cpuThreadsFlag, backend)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package toolset
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	coreSnapshot "github.com/gohornet/hornet/core/snapshot"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/syncmanager"
+	"github.com/gohornet/hornet/pkg/snapshot"
+)
+
+func snapshotExport(args []string) error {
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	databasePathFlag := fs.String(FlagToolDatabasePath, DefaultValueMainnetDatabasePath, "the path to the database")
+	targetIndexFlag := fs.Uint32(FlagToolSnapExportTargetIndex, 0, "the milestone index the full snapshot is created at")
+	outputPathFlag := fs.String(FlagToolOutputPath, "", "the path to the created full snapshot file")
+	belowMaxDepthFlag := fs.Int(FlagToolSnapExportBelowMaxDepth, 15, "the below max depth of the network, used to calculate the solid entry points")
+	encryptFlag := fs.Bool(FlagToolSnapExportEncrypt, false, fmt.Sprintf("whether the snapshot file is encrypted with the key from the %s environment variable", snapshot.EnvSnapshotEncryptionKey))
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", ToolSnapExport)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s --%s %s --%s %d --%s %s",
+			ToolSnapExport,
+			FlagToolDatabasePath,
+			DefaultValueMainnetDatabasePath,
+			FlagToolSnapExportTargetIndex,
+			1000,
+			FlagToolOutputPath,
+			"full_snapshot_1000.bin"))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	if len(*databasePathFlag) == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolDatabasePath)
+	}
+	if *targetIndexFlag == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolSnapExportTargetIndex)
+	}
+	if len(*outputPathFlag) == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolOutputPath)
+	}
+
+	databasePath := *databasePathFlag
+	if _, err := os.Stat(databasePath); err != nil || os.IsNotExist(err) {
+		return fmt.Errorf("'%s' (%s) does not exist", FlagToolDatabasePath, databasePath)
+	}
+
+	outputPath := *outputPathFlag
+	if _, err := os.Stat(outputPath); err == nil || !os.IsNotExist(err) {
+		return fmt.Errorf("'%s' (%s) already exists", FlagToolOutputPath, outputPath)
+	}
+
+	var encryptionKey string
+	if *encryptFlag {
+		key, err := snapshot.LoadEncryptionKeyFromEnvironment()
+		if err != nil {
+			return err
+		}
+		encryptionKey = key
+	}
+
+	dbStorage, closeStorage, err := openLedgerStorage(databasePath)
+	if err != nil {
+		return err
+	}
+	defer closeStorage()
+
+	snapshotInfo := dbStorage.SnapshotInfo()
+	if snapshotInfo == nil {
+		return fmt.Errorf("no snapshot info found in the database")
+	}
+
+	// the confirmed milestone index of a stopped node is its ledger index
+	syncManager, err := syncmanager.New(dbStorage.UTXOManager(), *belowMaxDepthFlag)
+	if err != nil {
+		return err
+	}
+
+	// the snapshot manager is only used to create the snapshot file, so pruning and the automatic snapshots are disabled.
+	snapshotManager, err := snapshot.NewSnapshotManager(
+		nil,
+		nil,
+		nil,
+		dbStorage,
+		syncManager,
+		dbStorage.UTXOManager(),
+		snapshotInfo.NetworkID,
+		"",
+		nil,
+		"",
+		"",
+		0,
+		encryptionKey,
+		runtime.NumCPU(),
+		nil,
+		milestone.Index(*belowMaxDepthFlag+coreSnapshot.SolidEntryPointCheckAdditionalThresholdPast),
+		milestone.Index(*belowMaxDepthFlag+coreSnapshot.SolidEntryPointCheckAdditionalThresholdFuture),
+		milestone.Index(*belowMaxDepthFlag+coreSnapshot.AdditionalPruningThreshold),
+		0,
+		&snapshot.Schedule{Policy: snapshot.SchedulePolicyInterval, Interval: 1},
+		false,
+		0,
+		false,
+		0,
+		0,
+		0,
+		snapshot.PruningThrottle{},
+		false,
+		0,
+	)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("creating full snapshot at milestone %d from %s (pruning index: %d, ledger index: %d)...\n", *targetIndexFlag, databasePath, snapshotInfo.PruningIndex, syncManager.ConfirmedMilestoneIndex())
+
+	ts := time.Now()
+
+	if err := snapshotManager.CreateFullSnapshot(context.Background(), milestone.Index(*targetIndexFlag), outputPath, false); err != nil {
+		return fmt.Errorf("creating full snapshot failed: %w", err)
+	}
+
+	fmt.Printf("successfully created full snapshot '%s', took %v\n", outputPath, time.Since(ts).Truncate(time.Millisecond))
+
+	return nil
+}
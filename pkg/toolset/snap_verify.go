@@ -0,0 +1,119 @@
+package toolset
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gohornet/hornet/pkg/snapshot"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func snapshotVerify(args []string) error {
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	snapshotPathFlag := fs.String(FlagToolSnapshotPath, "", "the path to the snapshot file")
+	networkIDFlag := fs.String(FlagToolNetworkID, "", "the network ID the snapshot is expected to be meant for (optional)")
+	outputJSONFlag := fs.Bool(FlagToolOutputJSON, false, FlagToolDescriptionOutputJSON)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", ToolSnapVerify)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s --%s %s --%s %s",
+			ToolSnapVerify,
+			FlagToolSnapshotPath,
+			"snapshots/mainnet/full_snapshot.bin",
+			FlagToolNetworkID,
+			"chrysalis-mainnet"))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	if len(*snapshotPathFlag) == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolSnapshotPath)
+	}
+
+	var wantedNetworkID []uint64
+	if len(*networkIDFlag) > 0 {
+		wantedNetworkID = append(wantedNetworkID, iotago.NetworkIDFromString(*networkIDFlag))
+	}
+
+	if !*outputJSONFlag {
+		fmt.Println("verifying snapshot file...")
+	}
+
+	ts := time.Now()
+
+	filePath := *snapshotPathFlag
+	result, err := snapshot.VerifySnapshotFile(context.Background(), filePath, nil, wantedNetworkID...)
+	if err != nil {
+		return err
+	}
+
+	if *outputJSONFlag {
+		return printJSON(struct {
+			FilePath               string `json:"filePath"`
+			NetworkID              uint64 `json:"networkID"`
+			LedgerIndex            uint32 `json:"ledgerIndex"`
+			SnapshotIndex          uint32 `json:"snapshotIndex"`
+			TreasuryTokens         uint64 `json:"treasuryTokens"`
+			UTXOsCount             uint64 `json:"UTXOsCount"`
+			UTXOsTokens            uint64 `json:"UTXOsTokens"`
+			SEPsCount              uint64 `json:"SEPsCount"`
+			MilestoneDiffsCount    uint64 `json:"milestoneDiffsCount"`
+			CreatedOutputsCount    uint64 `json:"createdOutputsCount"`
+			CreatedOutputsTokens   uint64 `json:"createdOutputsTokens"`
+			ConsumedOutputsCount   uint64 `json:"consumedOutputsCount"`
+			ConsumedOutputsTokens  uint64 `json:"consumedOutputsTokens"`
+			ReceiptsCount          uint64 `json:"receiptsCount"`
+			VerificationDurationMs int64  `json:"verificationDurationMs"`
+		}{
+			FilePath:      filePath,
+			NetworkID:     result.Header.NetworkID,
+			LedgerIndex:   uint32(result.Header.LedgerMilestoneIndex),
+			SnapshotIndex: uint32(result.Header.SEPMilestoneIndex),
+			TreasuryTokens: func() uint64 {
+				if result.Header.TreasuryOutput == nil {
+					return 0
+				}
+				return result.Header.TreasuryOutput.Amount
+			}(),
+			UTXOsCount:             result.Header.OutputCount,
+			UTXOsTokens:            result.OutputsBalance,
+			SEPsCount:              result.Header.SEPCount,
+			MilestoneDiffsCount:    result.Header.MilestoneDiffCount,
+			CreatedOutputsCount:    result.CreatedOutputsCount,
+			CreatedOutputsTokens:   result.CreatedOutputsBalance,
+			ConsumedOutputsCount:   result.ConsumedOutputsCount,
+			ConsumedOutputsTokens:  result.ConsumedOutputsBalance,
+			ReceiptsCount:          result.ReceiptsCount,
+			VerificationDurationMs: time.Since(ts).Milliseconds(),
+		})
+	}
+
+	if err := printSnapshotHeaderInfo("", filePath, result.Header, false); err != nil {
+		return err
+	}
+
+	fmt.Printf(`    > verification:
+        - UTXOs tokens:     %d
+        - Created outputs:  %d (tokens %d)
+        - Consumed outputs: %d (tokens %d)
+        - Receipts count:   %d`+"\n",
+		result.OutputsBalance,
+		result.CreatedOutputsCount,
+		result.CreatedOutputsBalance,
+		result.ConsumedOutputsCount,
+		result.ConsumedOutputsBalance,
+		result.ReceiptsCount,
+	)
+
+	fmt.Printf("successfully verified snapshot file '%s', took %v\n", filePath, time.Since(ts).Truncate(time.Millisecond))
+
+	return nil
+}
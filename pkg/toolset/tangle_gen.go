@@ -0,0 +1,221 @@
+package toolset
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	coreDatabase "github.com/gohornet/hornet/core/database"
+	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/pow"
+	"github.com/gohornet/hornet/pkg/tanglegen"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func tangleGen(args []string) error {
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	databasePathFlag := fs.String(FlagToolDatabasePath, "", "the path to the database that should be created (optional)")
+	databaseEngineFlag := fs.String(FlagToolDatabaseEngine, DefaultValueDatabaseEngine, "the engine of the created database (values: pebble, rocksdb)")
+	outputFilePathFlag := fs.String(FlagToolOutputPath, "", "the file path to the generated message stream (optional)")
+	networkIDFlag := fs.String(FlagToolNetworkID, "tanglegen", "the network ID of the generated messages")
+	seedFlag := fs.Int64(FlagToolTangleGenSeed, 0, "the seed of the pseudo random generator")
+	milestonesFlag := fs.Int(FlagToolTangleGenMilestones, 100, "the amount of milestones to generate")
+	widthFlag := fs.Int(FlagToolTangleGenWidth, 3, fmt.Sprintf("the amount of messages per layer of the tangle (1-%d)", iotago.MaxParentsInAMessage-1))
+	milestoneIntervalFlag := fs.Int(FlagToolTangleGenMilestoneInterval, 5, "the amount of layers between two milestones")
+	transactionRateFlag := fs.Float64(FlagToolTangleGenTransactionRate, 0.5, "the probability of a message to contain a transaction")
+	conflictRateFlag := fs.Float64(FlagToolTangleGenConflictRate, 0.05, "the probability of a transaction to double spend an already spent output")
+	targetScoreFlag := fs.Float64(FlagToolTangleGenTargetScore, 0, "the PoW target score of the generated messages (0 disables PoW)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", ToolTangleGen)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s --%s %s --%s %d --%s %d --%s %d --%s %.2f",
+			ToolTangleGen,
+			FlagToolDatabasePath,
+			"tanglegendb",
+			FlagToolTangleGenSeed,
+			42,
+			FlagToolTangleGenMilestones,
+			1000,
+			FlagToolTangleGenWidth,
+			5,
+			FlagToolTangleGenConflictRate,
+			0.1))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	if len(*databasePathFlag) == 0 && len(*outputFilePathFlag) == 0 {
+		return fmt.Errorf("either '%s' or '%s' has to be specified", FlagToolDatabasePath, FlagToolOutputPath)
+	}
+	if len(*networkIDFlag) == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolNetworkID)
+	}
+	if *milestonesFlag < 1 {
+		return fmt.Errorf("'%s' must be at least 1", FlagToolTangleGenMilestones)
+	}
+
+	databasePath := *databasePathFlag
+	if len(databasePath) > 0 {
+		if _, err := os.Stat(databasePath); err == nil || !os.IsNotExist(err) {
+			return fmt.Errorf("'%s' (%s) already exist", FlagToolDatabasePath, databasePath)
+		}
+	}
+
+	outputFilePath := *outputFilePathFlag
+	if len(outputFilePath) > 0 {
+		if _, err := os.Stat(outputFilePath); err == nil || !os.IsNotExist(err) {
+			return fmt.Errorf("'%s' (%s) already exists", FlagToolOutputPath, outputFilePath)
+		}
+	}
+
+	engine, err := database.DatabaseEngine(strings.ToLower(*databaseEngineFlag))
+	if err != nil {
+		return err
+	}
+
+	if len(databasePath) == 0 {
+		// the tangle is only streamed to the output file, but the ledger is needed to generate it
+		tempDir, err := ioutil.TempDir("", "tangleGen")
+		if err != nil {
+			return fmt.Errorf("can't create temp dir: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(tempDir) }()
+
+		databasePath = tempDir
+		engine = database.EnginePebble
+	}
+
+	tangleStore, err := database.StoreWithDefaultSettings(filepath.Join(databasePath, coreDatabase.TangleDatabaseDirectoryName), true, engine)
+	if err != nil {
+		return fmt.Errorf("%s database initialization failed: %w", coreDatabase.TangleDatabaseDirectoryName, err)
+	}
+
+	utxoStore, err := database.StoreWithDefaultSettings(filepath.Join(databasePath, coreDatabase.UTXODatabaseDirectoryName), true, engine)
+	if err != nil {
+		return fmt.Errorf("%s database initialization failed: %w", coreDatabase.UTXODatabaseDirectoryName, err)
+	}
+
+	// clean up store
+	defer func() {
+		tangleStore.Shutdown()
+		_ = tangleStore.Close()
+
+		utxoStore.Shutdown()
+		_ = utxoStore.Close()
+	}()
+
+	dbStorage, err := storage.New(tangleStore, utxoStore)
+	if err != nil {
+		return err
+	}
+
+	lastStatusTime := time.Now()
+	opts := []tanglegen.Option{
+		tanglegen.WithSeed(*seedFlag),
+		tanglegen.WithNetworkID(*networkIDFlag),
+		tanglegen.WithWidth(*widthFlag),
+		tanglegen.WithMilestoneInterval(*milestoneIntervalFlag),
+		tanglegen.WithTransactionRate(*transactionRateFlag),
+		tanglegen.WithConflictRate(*conflictRateFlag),
+		tanglegen.WithMilestoneConfirmedFunc(func(confirmedMilestoneStats *whiteflag.ConfirmedMilestoneStats) {
+			if time.Since(lastStatusTime) < printStatusInterval {
+				return
+			}
+			lastStatusTime = time.Now()
+
+			fmt.Printf("generated milestone %d/%d\n", confirmedMilestoneStats.Index, *milestonesFlag)
+		}),
+	}
+
+	if *targetScoreFlag > 0 {
+		opts = append(opts, tanglegen.WithPoWHandler(pow.New(*targetScoreFlag, 5*time.Second)))
+	}
+
+	var outputFile *os.File
+	var outputWriter *bufio.Writer
+	if len(outputFilePath) > 0 {
+		// build temp file path
+		outputFilePathTmp := outputFilePath + "_tmp"
+
+		// we don't need to check the error, maybe the file doesn't exist
+		_ = os.Remove(outputFilePathTmp)
+
+		outputFile, err = os.OpenFile(outputFilePathTmp, os.O_RDWR|os.O_CREATE, 0666)
+		if err != nil {
+			return fmt.Errorf("unable to create message stream file: %w", err)
+		}
+		outputWriter = bufio.NewWriter(outputFile)
+
+		opts = append(opts, tanglegen.WithMessageFunc(func(msg *storage.Message) error {
+			return tanglegen.WriteMessage(outputWriter, msg)
+		}))
+	}
+
+	generator, err := tanglegen.New(dbStorage, opts...)
+	if err != nil {
+		return err
+	}
+
+	ts := time.Now()
+
+	stats, err := generator.Generate(context.Background(), *milestonesFlag)
+	if err != nil {
+		if outputFile != nil {
+			_ = outputFile.Close()
+		}
+		return fmt.Errorf("generating tangle failed: %w", err)
+	}
+
+	dbStorage.ShutdownStorages()
+
+	if outputFile != nil {
+		if err := outputWriter.Flush(); err != nil {
+			_ = outputFile.Close()
+			return fmt.Errorf("unable to write message stream file: %w", err)
+		}
+
+		if err := outputFile.Close(); err != nil {
+			return fmt.Errorf("unable to close message stream file: %w", err)
+		}
+
+		// rename tmp file to final file name
+		if err := os.Rename(outputFile.Name(), outputFilePath); err != nil {
+			return fmt.Errorf("unable to rename temp message stream file: %w", err)
+		}
+	}
+
+	fmt.Printf(`    >
+        - Network ID:             %s
+        - Coordinator public key: %s
+        - Genesis address:        %s
+        - Milestones:             %d
+        - Messages:               %d
+        - Transactions:           %d
+        - Conflicts:              %d`+"\n\n",
+		*networkIDFlag,
+		hex.EncodeToString(generator.CooPublicKey()),
+		hex.EncodeToString(generator.WalletAddress()[:]),
+		stats.Milestones,
+		stats.Messages,
+		stats.Transactions,
+		stats.Conflicts,
+	)
+
+	fmt.Printf("successfully generated tangle, took %v\n", time.Since(ts).Truncate(time.Millisecond))
+
+	return nil
+}
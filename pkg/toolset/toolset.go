@@ -35,25 +35,56 @@ const (
 	FlagToolNetworkID = "networkID"
 	FlagToolPassword  = "password"
 	FlagToolSalt      = "salt"
+	FlagToolScopes    = "scopes"
 
 	FlagToolOutputJSON            = "json"
 	FlagToolDescriptionOutputJSON = "format output as JSON"
 
+	FlagToolGracePeriod = "gracePeriod"
+
+	FlagToolIndexerPath        = "indexerPath"
+	FlagToolIndexerExportPath  = "exportPath"
+	FlagToolIndexerLedgerIndex = "ledgerIndex"
+
+	FlagToolConfigPath              = "configPath"
+	FlagToolConfigPathTarget        = "targetConfigPath"
+	FlagToolPeeringConfigPath       = "peeringConfigPath"
+	FlagToolPeeringConfigPathTarget = "targetPeeringConfigPath"
+
 	FlagToolBenchmarkCount    = "count"
 	FlagToolBenchmarkSize     = "size"
 	FlagToolBenchmarkThreads  = "threads"
 	FlagToolBenchmarkDuration = "duration"
 
 	FlagToolCoordinatorFixStateCooStateFilePath = "stateFilePath"
+	FlagToolCoordinatorStateBackupPath          = "backupPath"
 
 	FlagToolSnapGenMintAddress        = "mintAddress"
 	FlagToolSnapGenTreasuryAllocation = "treasuryAllocation"
+
+	FlagToolSnapExportTargetIndex   = "targetIndex"
+	FlagToolSnapExportBelowMaxDepth = "belowMaxDepth"
+	FlagToolSnapExportEncrypt       = "encrypt"
+
+	FlagToolTangleGenSeed              = "seed"
+	FlagToolTangleGenMilestones        = "milestones"
+	FlagToolTangleGenWidth             = "width"
+	FlagToolTangleGenMilestoneInterval = "milestoneInterval"
+	FlagToolTangleGenTransactionRate   = "transactionRate"
+	FlagToolTangleGenConflictRate      = "conflictRate"
+	FlagToolTangleGenTargetScore       = "targetScore"
+
+	FlagToolPoWWorkerBindAddress = "bindAddress"
+	FlagToolPoWWorkerBackend     = "backend"
+
+	FlagToolPoWBenchmarkTargetScore = "targetScore"
 )
 
 const (
 	ToolPwdHash                 = "pwd-hash"
 	ToolP2PIdentityGen          = "p2pidentity-gen"
 	ToolP2PExtractIdentity      = "p2pidentity-extract"
+	ToolP2PIdentityRotate       = "p2pidentity-rotate"
 	ToolEd25519Key              = "ed25519-key"
 	ToolEd25519Addr             = "ed25519-addr"
 	ToolJWTApi                  = "jwt-api"
@@ -61,21 +92,37 @@ const (
 	ToolSnapMerge               = "snap-merge"
 	ToolSnapInfo                = "snap-info"
 	ToolSnapHash                = "snap-hash"
+	ToolSnapVerify              = "snap-verify"
+	ToolSnapExport              = "snap-export"
 	ToolBenchmarkIO             = "bench-io"
 	ToolBenchmarkCPU            = "bench-cpu"
+	ToolBenchmarkPoW            = "pow-bench"
 	ToolDatabaseMigration       = "db-migration"
 	ToolDatabaseLedgerHash      = "db-hash"
 	ToolDatabaseHealth          = "db-health"
 	ToolDatabaseSplit           = "db-split"
 	ToolCoordinatorFixStateFile = "coo-fix-state"
+	ToolCoordinatorStateExport  = "coo-state-export"
+	ToolCoordinatorStateImport  = "coo-state-import"
+	ToolTangleGen               = "gen-tangle"
+	ToolIndexerRebuild          = "indexer-rebuild"
+	ToolIndexerExport           = "indexer-export"
+	ToolIndexerImport           = "indexer-import"
+	ToolConfigMigrate           = "migrate-config"
+	ToolPoWWorker               = "pow-worker"
 )
 
 const (
-	DefaultValueAPIJWTTokenSalt          = "HORNET"
-	DefaultValueMainnetDatabasePath      = "mainnetdb"
-	DefaultValueP2PDatabasePath          = "p2pstore"
-	DefaultValueCoordinatorStateFilePath = "coordinator.state"
-	DefaultValueDatabaseEngine           = database.EngineRocksDB
+	DefaultValueAPIJWTTokenSalt            = "HORNET"
+	DefaultValueMainnetDatabasePath        = "mainnetdb"
+	DefaultValueP2PDatabasePath            = "p2pstore"
+	DefaultValueCoordinatorStateFilePath   = "coordinator.state"
+	DefaultValueCoordinatorStateBackupPath = "coordinator_state_backup.json"
+	DefaultValueDatabaseEngine             = database.EngineRocksDB
+	DefaultValueConfigPath                 = "config.json"
+	DefaultValueConfigPathTarget           = "config_migrated.json"
+	DefaultValuePeeringConfigPath          = "peering.json"
+	DefaultValuePeeringConfigPathTarget    = "peering_migrated.json"
 )
 
 const (
@@ -110,6 +157,7 @@ func HandleTools() {
 		ToolPwdHash:                 hashPasswordAndSalt,
 		ToolP2PIdentityGen:          generateP2PIdentity,
 		ToolP2PExtractIdentity:      extractP2PIdentity,
+		ToolP2PIdentityRotate:       rotateP2PIdentity,
 		ToolEd25519Key:              generateEd25519Key,
 		ToolEd25519Addr:             generateEd25519Address,
 		ToolJWTApi:                  generateJWTApiToken,
@@ -117,13 +165,24 @@ func HandleTools() {
 		ToolSnapMerge:               snapshotMerge,
 		ToolSnapInfo:                snapshotInfo,
 		ToolSnapHash:                snapshotHash,
+		ToolSnapVerify:              snapshotVerify,
+		ToolSnapExport:              snapshotExport,
 		ToolBenchmarkIO:             benchmarkIO,
 		ToolBenchmarkCPU:            benchmarkCPU,
+		ToolBenchmarkPoW:            benchmarkPoW,
 		ToolDatabaseMigration:       databaseMigration,
 		ToolDatabaseLedgerHash:      databaseLedgerHash,
 		ToolDatabaseHealth:          databaseHealth,
 		ToolDatabaseSplit:           databaseSplit,
 		ToolCoordinatorFixStateFile: coordinatorFixStateFile,
+		ToolCoordinatorStateExport:  coordinatorStateExport,
+		ToolCoordinatorStateImport:  coordinatorStateImport,
+		ToolTangleGen:               tangleGen,
+		ToolIndexerRebuild:          indexerRebuild,
+		ToolIndexerExport:           indexerExport,
+		ToolIndexerImport:           indexerImport,
+		ToolConfigMigrate:           migrateConfig,
+		ToolPoWWorker:               powWorker,
 	}
 
 	tool, exists := tools[strings.ToLower(args[1])]
@@ -150,6 +209,7 @@ func listTools() {
 	fmt.Printf("%-20s generates a scrypt hash from your password and salt\n", fmt.Sprintf("%s:", ToolPwdHash))
 	fmt.Printf("%-20s generates a p2p identity private key file\n", fmt.Sprintf("%s:", ToolP2PIdentityGen))
 	fmt.Printf("%-20s extracts the p2p identity from the private key file\n", fmt.Sprintf("%s:", ToolP2PExtractIdentity))
+	fmt.Printf("%-20s stages a new p2p identity which replaces the current one after a grace period\n", fmt.Sprintf("%s:", ToolP2PIdentityRotate))
 	fmt.Printf("%-20s generates an ed25519 key pair\n", fmt.Sprintf("%s:", ToolEd25519Key))
 	fmt.Printf("%-20s generates an ed25519 address from a public key\n", fmt.Sprintf("%s:", ToolEd25519Addr))
 	fmt.Printf("%-20s generates a JWT token for REST-API access\n", fmt.Sprintf("%s:", ToolJWTApi))
@@ -157,13 +217,24 @@ func listTools() {
 	fmt.Printf("%-20s merges a full and delta snapshot into an updated full snapshot\n", fmt.Sprintf("%s:", ToolSnapMerge))
 	fmt.Printf("%-20s outputs information about a snapshot file\n", fmt.Sprintf("%s:", ToolSnapInfo))
 	fmt.Printf("%-20s calculates the sha256 hash of the ledger state inside a snapshot file\n", fmt.Sprintf("%s:", ToolSnapHash))
+	fmt.Printf("%-20s parses a snapshot file completely and checks its consistency\n", fmt.Sprintf("%s:", ToolSnapVerify))
+	fmt.Printf("%-20s creates a full snapshot file at a milestone that is not pruned in a database\n", fmt.Sprintf("%s:", ToolSnapExport))
 	fmt.Printf("%-20s benchmarks the IO throughput\n", fmt.Sprintf("%s:", ToolBenchmarkIO))
 	fmt.Printf("%-20s benchmarks the CPU performance\n", fmt.Sprintf("%s:", ToolBenchmarkCPU))
+	fmt.Printf("%-20s benchmarks the PoW hash rate per thread count and suggests the spammer and faucet workers\n", fmt.Sprintf("%s:", ToolBenchmarkPoW))
 	fmt.Printf("%-20s migrates the database to another engine\n", fmt.Sprintf("%s:", ToolDatabaseMigration))
 	fmt.Printf("%-20s calculates the sha256 hash of the ledger state of a database\n", fmt.Sprintf("%s:", ToolDatabaseLedgerHash))
 	fmt.Printf("%-20s checks the health status of the database\n", fmt.Sprintf("%s:", ToolDatabaseHealth))
 	fmt.Printf("%-20s split a legacy database into `tangle` and `utxo`\n", fmt.Sprintf("%s:", ToolDatabaseSplit))
 	fmt.Printf("%-20s applies the latest milestone in the database to the coordinator state file\n", fmt.Sprintf("%s:", ToolCoordinatorFixStateFile))
+	fmt.Printf("%-20s exports the coordinator state file to a backup with a checksum\n", fmt.Sprintf("%s:", ToolCoordinatorStateExport))
+	fmt.Printf("%-20s validates a coordinator state backup against the database and restores the state file\n", fmt.Sprintf("%s:", ToolCoordinatorStateImport))
+	fmt.Printf("%-20s generates a deterministic test tangle into a database or a message stream\n", fmt.Sprintf("%s:", ToolTangleGen))
+	fmt.Printf("%-20s drops the indexer tables and rebuilds the index from the UTXO ledger of a database\n", fmt.Sprintf("%s:", ToolIndexerRebuild))
+	fmt.Printf("%-20s exports the index at a given ledger index to a file\n", fmt.Sprintf("%s:", ToolIndexerExport))
+	fmt.Printf("%-20s imports an exported index into a database without an index\n", fmt.Sprintf("%s:", ToolIndexerImport))
+	fmt.Printf("%-20s migrates the config and peering files of a previous version and reports the renamed and removed keys\n", fmt.Sprintf("%s:", ToolConfigMigrate))
+	fmt.Printf("%-20s runs a remote PoW worker the PoW of nodes can be offloaded to\n", fmt.Sprintf("%s:", ToolPoWWorker))
 }
 
 func yesOrNo(value bool) string {
@@ -0,0 +1,70 @@
+package whiteflag
+
+import (
+	"fmt"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/storage"
+)
+
+// ConeShape holds estimations about the shape of the cone referenced by a milestone.
+type ConeShape struct {
+	// The length of the longest path of messages inside the cone (milestone included).
+	Depth int
+	// The average amount of messages per depth level of the cone.
+	Width float64
+	// The ratio of parent references pointing to messages outside of the cone,
+	// which were already referenced by older milestones (or are solid entry points).
+	Overlap float64
+}
+
+// computeConeShape estimates the shape of the cone referenced by a milestone.
+// The message IDs have to be passed in post-order DFS of the white-flag traversal,
+// so that the parents inside of the cone are always walked before the message itself.
+// The metadata of the messages is expected to be in the metadataMemcache already, so this is cheap.
+func computeConeShape(metadataMemcache *storage.MetadataMemcache, messageIDs hornet.MessageIDs) (*ConeShape, error) {
+
+	coneShape := &ConeShape{}
+	if len(messageIDs) == 0 {
+		return coneShape, nil
+	}
+
+	depthLevels := make(map[string]int, len(messageIDs))
+
+	parentsTotal := 0
+	parentsOutsideCone := 0
+
+	for _, messageID := range messageIDs {
+		cachedMsgMeta := metadataMemcache.CachedMetadataOrNil(messageID)
+		if cachedMsgMeta == nil {
+			return nil, fmt.Errorf("computeConeShape: Message not found: %v", messageID.ToHex())
+		}
+
+		depth := 1
+		for _, parent := range cachedMsgMeta.Metadata().Parents() {
+			parentsTotal++
+
+			parentDepth, insideCone := depthLevels[parent.ToMapKey()]
+			if !insideCone {
+				parentsOutsideCone++
+				continue
+			}
+
+			if parentDepth+1 > depth {
+				depth = parentDepth + 1
+			}
+		}
+
+		depthLevels[messageID.ToMapKey()] = depth
+		if depth > coneShape.Depth {
+			coneShape.Depth = depth
+		}
+	}
+
+	coneShape.Width = float64(len(messageIDs)) / float64(coneShape.Depth)
+	if parentsTotal > 0 {
+		coneShape.Overlap = float64(parentsOutsideCone) / float64(parentsTotal)
+	}
+
+	return coneShape, nil
+}
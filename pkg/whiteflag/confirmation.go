@@ -21,6 +21,9 @@ type ConfirmedMilestoneStats struct {
 	MessagesExcludedWithConflictingTransactions int
 	MessagesIncludedWithTransactions            int
 	MessagesExcludedWithoutTransactions         int
+	ConeShape                                   *ConeShape
+	// ConeShapeErr is set if the shape of the cone could not be computed, the ConeShape is empty in that case.
+	ConeShapeErr error
 }
 
 // ConfirmationMetrics holds metrics about a confirmation run.
@@ -68,6 +71,10 @@ func ConfirmMilestone(
 		return nil, nil, fmt.Errorf("milestone message not found: %v", milestoneMessageID.ToHex())
 	}
 
+	// readers holding the confirmation read lock see either the state before or after this milestone.
+	dbStorage.WriteLockConfirmation()
+	defer dbStorage.WriteUnlockConfirmation()
+
 	dbStorage.UTXOManager().WriteLockLedger()
 	defer dbStorage.UTXOManager().WriteUnlockLedger()
 	message := cachedMilestoneMessage.Message()
@@ -181,8 +188,22 @@ func ConfirmMilestone(
 		return nil
 	}
 
+	// the milestone message itself is the top level of its cone
+	coneMessageIDs := make(hornet.MessageIDs, 0, len(mutations.MessagesReferenced)+1)
+	coneMessageIDs = append(coneMessageIDs, mutations.MessagesReferenced...)
+	coneMessageIDs = append(coneMessageIDs, milestoneMessageID)
+
+	// the cone shape is only used for metrics, so the milestone is confirmed even if it could not be computed,
+	// the ledger changes were already applied at this point.
+	coneShape, coneShapeErr := computeConeShape(metadataMemcache, coneMessageIDs)
+	if coneShapeErr != nil {
+		coneShape = &ConeShape{}
+	}
+
 	confirmedMilestoneStats := &ConfirmedMilestoneStats{
-		Index: milestoneIndex,
+		Index:        milestoneIndex,
+		ConeShape:    coneShape,
+		ConeShapeErr: coneShapeErr,
 	}
 	confirmationTime := ms.Timestamp
 
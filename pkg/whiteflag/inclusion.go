@@ -0,0 +1,96 @@
+package whiteflag
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/dag"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrMessageNotIncluded is returned if a message is not part of the messages which mutated the ledger in a milestone.
+	ErrMessageNotIncluded = errors.New("message was not included in the ledger by the milestone")
+)
+
+// IncludedMessagesOfMilestone returns the messages which mutated the ledger in the confirmation of the milestone with the given index,
+// in the order in which they were applied by ComputeWhiteFlagMutations, which is also the order of the leaves of the inclusion Merkle proof.
+// The parents must be the parents of the milestone, and the messages referenced by the milestone must not be pruned yet.
+func IncludedMessagesOfMilestone(ctx context.Context, dbStorage *storage.Storage, msIndex milestone.Index, parents hornet.MessageIDs) (hornet.MessageIDs, error) {
+
+	includedMessages := make(hornet.MessageIDs, 0)
+
+	// the milestone references exactly the messages of its past cone that were not referenced by an older milestone,
+	// so the post-order traversal of these messages yields the same order as the confirmation.
+	condition := func(cachedMetadata *storage.CachedMetadata) (bool, error) { // meta +1
+		defer cachedMetadata.Release(true) // meta -1
+
+		referenced, referencedIndex := cachedMetadata.Metadata().ReferencedWithIndex()
+		return referenced && referencedIndex == msIndex, nil
+	}
+
+	consumer := func(cachedMetadata *storage.CachedMetadata) error { // meta +1
+		defer cachedMetadata.Release(true) // meta -1
+
+		if cachedMetadata.Metadata().IsIncludedTxInLedger() {
+			includedMessages = append(includedMessages, cachedMetadata.Metadata().MessageID())
+		}
+		return nil
+	}
+
+	if err := dag.TraverseParents(
+		ctx,
+		dbStorage,
+		parents,
+		condition,
+		consumer,
+		// called on missing parents
+		// return error on missing parents
+		nil,
+		// called on solid entry points
+		// Ignore solid entry points (snapshot milestone included)
+		nil,
+		false); err != nil {
+		return nil, err
+	}
+
+	return includedMessages, nil
+}
+
+// InclusionAuditPath returns the position of the message within the messages which mutated the ledger in a milestone,
+// and the Merkle audit path that proves its inclusion in the inclusion Merkle proof of the milestone.
+func InclusionAuditPath(includedMessages hornet.MessageIDs, messageID hornet.MessageID) (int, [][]byte, error) {
+
+	index := -1
+	marshalers := make([]encoding.BinaryMarshaler, len(includedMessages))
+	for i := range includedMessages {
+		marshalers[i] = includedMessages[i]
+		if index == -1 && bytes.Equal(includedMessages[i], messageID) {
+			index = i
+		}
+	}
+
+	if index == -1 {
+		return 0, nil, ErrMessageNotIncluded
+	}
+
+	path, err := NewHasher(crypto.BLAKE2b_256).AuditPath(marshalers, index)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return index, path, nil
+}
+
+// VerifyInclusionAuditPath checks whether the audit path proves that the message at the given position of the
+// messages which mutated the ledger in a milestone is part of the inclusion Merkle proof of the milestone.
+func VerifyInclusionAuditPath(messageID hornet.MessageID, index int, count int, path [][]byte, inclusionMerkleProof iotago.MilestoneInclusionMerkleProof) (bool, error) {
+	return NewHasher(crypto.BLAKE2b_256).VerifyAuditPath(messageID, index, count, path, inclusionMerkleProof[:])
+}
@@ -0,0 +1,47 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/testsuite"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestWhiteFlagConeShape(t *testing.T) {
+
+	te := testsuite.SetupTestEnvironment(t, &iotago.Ed25519Address{}, 2, BelowMaxDepth, MinPoWScore, showConfirmationGraphs)
+	defer te.CleanupTestEnvironment(!showConfirmationGraphs)
+
+	lastMilestoneMessageID := te.Milestones[len(te.Milestones)-1].Milestone().MessageID
+
+	// build a chain of messages A <- B <- C on top of the last milestone
+	messageA := te.NewMessageBuilder("A").Parents(hornet.MessageIDs{lastMilestoneMessageID}).BuildTaggedData().Store()
+	messageB := te.NewMessageBuilder("B").Parents(hornet.MessageIDs{messageA.StoredMessageID()}).BuildTaggedData().Store()
+	messageC := te.NewMessageBuilder("C").Parents(hornet.MessageIDs{messageB.StoredMessageID()}).BuildTaggedData().Store()
+
+	// Confirming milestone at message C
+	_, confStats := te.IssueAndConfirmMilestoneOnTips(hornet.MessageIDs{messageC.StoredMessageID()}, false)
+	require.Equal(t, 3+1, confStats.MessagesReferenced) // 3 + milestone itself
+
+	require.NoError(t, confStats.ConeShapeErr)
+	require.NotNil(t, confStats.ConeShape)
+	require.Equal(t, 4, confStats.ConeShape.Depth)
+	require.Equal(t, 1.0, confStats.ConeShape.Width)
+
+	// message A and the new milestone reference the last milestone, all other parents are inside of the cone
+	require.InDelta(t, 2.0/5.0, confStats.ConeShape.Overlap, 0.0001)
+
+	// Confirming milestone with two independent messages D and E
+	messageD := te.NewMessageBuilder("D").Parents(hornet.MessageIDs{te.LastMilestoneMessageID}).BuildTaggedData().Store()
+	messageE := te.NewMessageBuilder("E").Parents(hornet.MessageIDs{te.LastMilestoneMessageID}).BuildTaggedData().Store()
+
+	_, confStats = te.IssueAndConfirmMilestoneOnTips(hornet.MessageIDs{messageD.StoredMessageID(), messageE.StoredMessageID()}.RemoveDupsAndSortByLexicalOrder(), false)
+	require.Equal(t, 2+1, confStats.MessagesReferenced) // 2 + milestone itself
+
+	require.NotNil(t, confStats.ConeShape)
+	require.Equal(t, 2, confStats.ConeShape.Depth)
+	require.Equal(t, 1.5, confStats.ConeShape.Width)
+}
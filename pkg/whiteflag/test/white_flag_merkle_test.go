@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/utxo/utils"
 	"github.com/gohornet/hornet/pkg/whiteflag"
 
 	// import implementation
@@ -45,3 +46,41 @@ func TestWhiteFlagMerkleTreeHash(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, bytes.Equal(hash, expectedHash))
 }
+
+func TestWhiteFlagMerkleTreeAuditPath(t *testing.T) {
+
+	hasher := whiteflag.NewHasher(crypto.BLAKE2b_256)
+
+	var includedMessages []encoding.BinaryMarshaler
+	for i := 0; i < 7; i++ {
+		includedMessages = append(includedMessages, utils.RandMessageID())
+
+		root, err := hasher.Hash(includedMessages)
+		require.NoError(t, err)
+
+		size := len(includedMessages)
+		for index := 0; index < size; index++ {
+			path, err := hasher.AuditPath(includedMessages, index)
+			require.NoError(t, err)
+
+			valid, err := hasher.VerifyAuditPath(includedMessages[index], index, size, path, root)
+			require.NoError(t, err)
+			require.True(t, valid)
+
+			if size > 1 {
+				// the path doesn't prove the leaf at another index
+				valid, err = hasher.VerifyAuditPath(includedMessages[index], (index+1)%size, size, path, root)
+				require.NoError(t, err)
+				require.False(t, valid)
+
+				// the path doesn't prove another leaf
+				valid, err = hasher.VerifyAuditPath(includedMessages[(index+1)%size], index, size, path, root)
+				require.NoError(t, err)
+				require.False(t, valid)
+			}
+		}
+
+		_, err = hasher.AuditPath(includedMessages, size)
+		require.ErrorIs(t, err, whiteflag.ErrLeafIndexOutOfRange)
+	}
+}
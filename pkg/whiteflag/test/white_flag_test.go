@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"encoding/hex"
 	"testing"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/testsuite"
 	"github.com/gohornet/hornet/pkg/testsuite/utils"
+	"github.com/gohornet/hornet/pkg/whiteflag"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
 
@@ -88,6 +90,55 @@ func TestWhiteFlagSendAllCoins(t *testing.T) {
 	te.AssertWalletBalance(seed2Wallet, 0)
 }
 
+func TestWhiteFlagCheckTransaction(t *testing.T) {
+
+	seed1Wallet := utils.NewHDWallet("Seed1", seed1, 0)
+	seed2Wallet := utils.NewHDWallet("Seed2", seed2, 0)
+
+	genesisAddress := seed1Wallet.Address()
+
+	te := testsuite.SetupTestEnvironment(t, genesisAddress, 2, BelowMaxDepth, MinPoWScore, showConfirmationGraphs)
+	defer te.CleanupTestEnvironment(!showConfirmationGraphs)
+
+	//Add token supply to our local HDWallet
+	seed1Wallet.BookOutput(te.GenesisOutput)
+
+	messageA := te.NewMessageBuilder("A").
+		Parents(hornet.MessageIDs{te.Milestones[0].Milestone().MessageID, te.Milestones[1].Milestone().MessageID}).
+		FromWallet(seed1Wallet).
+		ToWallet(seed2Wallet).
+		Amount(iotago.TokenSupply).
+		Build()
+
+	conflict, err := whiteflag.CheckTransaction(te.UTXOManager(), messageA.StoredMessage(), te.LastMilestoneIndex()+1, 0)
+	require.NoError(t, err)
+	require.Equal(t, storage.ConflictNone, conflict)
+
+	messageA.Store().BookOnWallets()
+
+	// Confirming milestone at message A
+	_, confStats := te.IssueAndConfirmMilestoneOnTips(hornet.MessageIDs{messageA.StoredMessageID()}, true)
+	require.Equal(t, 1, confStats.MessagesIncludedWithTransactions)
+
+	// the input of message A is spent now
+	conflict, err = whiteflag.CheckTransaction(te.UTXOManager(), messageA.StoredMessage(), te.LastMilestoneIndex()+1, 0)
+	require.Error(t, err)
+	require.Equal(t, storage.Conflict(storage.ConflictInputUTXOAlreadySpent), conflict)
+
+	// the inputs of messages with fake inputs don't exist
+	messageB := te.NewMessageBuilder("B").
+		Parents(hornet.MessageIDs{messageA.StoredMessageID()}).
+		FromWallet(seed2Wallet).
+		ToWallet(seed1Wallet).
+		FakeInputs().
+		Amount(iotago.TokenSupply).
+		Build()
+
+	conflict, err = whiteflag.CheckTransaction(te.UTXOManager(), messageB.StoredMessage(), te.LastMilestoneIndex()+1, 0)
+	require.Error(t, err)
+	require.Equal(t, storage.Conflict(storage.ConflictInputUTXONotFound), conflict)
+}
+
 func TestWhiteFlagWithMultipleConflicting(t *testing.T) {
 
 	seed1Wallet := utils.NewHDWallet("Seed1", seed1, 0)
@@ -301,3 +352,155 @@ func TestWhiteFlagWithOnlyZeroTx(t *testing.T) {
 	require.Equal(t, 0, confStats.MessagesExcludedWithConflictingTransactions)
 	require.Equal(t, 3+1, confStats.MessagesExcludedWithoutTransactions) // 1 is for the milestone itself
 }
+
+func TestWhiteFlagInclusionAuditPath(t *testing.T) {
+
+	seed1Wallet := utils.NewHDWallet("Seed1", seed1, 0)
+	seed2Wallet := utils.NewHDWallet("Seed2", seed2, 0)
+	seed3Wallet := utils.NewHDWallet("Seed3", seed3, 0)
+
+	genesisAddress := seed1Wallet.Address()
+
+	te := testsuite.SetupTestEnvironment(t, genesisAddress, 2, BelowMaxDepth, MinPoWScore, showConfirmationGraphs)
+	defer te.CleanupTestEnvironment(!showConfirmationGraphs)
+
+	//Add token supply to our local HDWallet
+	seed1Wallet.BookOutput(te.GenesisOutput)
+
+	messageA := te.NewMessageBuilder("A").
+		Parents(hornet.MessageIDs{te.Milestones[0].Milestone().MessageID, te.Milestones[1].Milestone().MessageID}).
+		FromWallet(seed1Wallet).
+		ToWallet(seed2Wallet).
+		Amount(1_000_000).
+		Build().
+		Store().
+		BookOnWallets()
+
+	messageB := te.NewMessageBuilder("B").
+		Parents(hornet.MessageIDs{messageA.StoredMessageID(), te.Milestones[0].Milestone().MessageID}).
+		FromWallet(seed1Wallet).
+		ToWallet(seed2Wallet).
+		Amount(2_000_000).
+		Build().
+		Store().
+		BookOnWallets()
+
+	messageC := te.NewMessageBuilder("C").
+		Parents(hornet.MessageIDs{te.Milestones[2].Milestone().MessageID, messageB.StoredMessageID()}).
+		FromWallet(seed3Wallet).
+		ToWallet(seed2Wallet).
+		Amount(100_000).
+		FakeInputs().
+		Build().
+		Store()
+
+	conf, confStats := te.IssueAndConfirmMilestoneOnTips(hornet.MessageIDs{messageC.StoredMessageID()}, true)
+	require.Equal(t, 2, confStats.MessagesIncludedWithTransactions)
+
+	cachedMilestoneMessage := te.Storage().CachedMessageOrNil(conf.MilestoneMessageID)
+	require.NotNil(t, cachedMilestoneMessage)
+	defer cachedMilestoneMessage.Release(true)
+
+	ms := cachedMilestoneMessage.Message().Milestone()
+	require.NotNil(t, ms)
+
+	// the order of the included messages is restored after the confirmation
+	includedMessages, err := whiteflag.IncludedMessagesOfMilestone(context.Background(), te.Storage(), conf.MilestoneIndex, cachedMilestoneMessage.Message().Parents())
+	require.NoError(t, err)
+	require.Equal(t, conf.Mutations.MessagesIncludedWithTransactions, includedMessages)
+
+	for _, messageID := range (hornet.MessageIDs{messageA.StoredMessageID(), messageB.StoredMessageID()}) {
+		index, path, err := whiteflag.InclusionAuditPath(includedMessages, messageID)
+		require.NoError(t, err)
+
+		valid, err := whiteflag.VerifyInclusionAuditPath(messageID, index, len(includedMessages), path, ms.InclusionMerkleProof)
+		require.NoError(t, err)
+		require.True(t, valid)
+	}
+
+	// the conflicting message was not included
+	_, _, err = whiteflag.InclusionAuditPath(includedMessages, messageC.StoredMessageID())
+	require.ErrorIs(t, err, whiteflag.ErrMessageNotIncluded)
+}
+
+func TestWhiteFlagRecomputeMutations(t *testing.T) {
+
+	seed1Wallet := utils.NewHDWallet("Seed1", seed1, 0)
+	seed2Wallet := utils.NewHDWallet("Seed2", seed2, 0)
+	seed3Wallet := utils.NewHDWallet("Seed3", seed3, 0)
+	seed4Wallet := utils.NewHDWallet("Seed4", seed4, 0)
+
+	genesisAddress := seed1Wallet.Address()
+
+	te := testsuite.SetupTestEnvironment(t, genesisAddress, 2, BelowMaxDepth, MinPoWScore, showConfirmationGraphs)
+	defer te.CleanupTestEnvironment(!showConfirmationGraphs)
+
+	//Add token supply to our local HDWallet
+	seed1Wallet.BookOutput(te.GenesisOutput)
+
+	messageA := te.NewMessageBuilder("A").
+		Parents(hornet.MessageIDs{te.Milestones[0].Milestone().MessageID, te.Milestones[1].Milestone().MessageID}).
+		FromWallet(seed1Wallet).
+		ToWallet(seed2Wallet).
+		Amount(1_000_000).
+		Build().
+		Store().
+		BookOnWallets()
+
+	messageB := te.NewMessageBuilder("B").
+		Parents(hornet.MessageIDs{messageA.StoredMessageID(), te.Milestones[0].Milestone().MessageID}).
+		FromWallet(seed1Wallet).
+		ToWallet(seed2Wallet).
+		Amount(2_000_000).
+		Build().
+		Store().
+		BookOnWallets()
+
+	messageC := te.NewMessageBuilder("C").
+		Parents(hornet.MessageIDs{te.Milestones[2].Milestone().MessageID, messageB.StoredMessageID()}).
+		FromWallet(seed3Wallet).
+		ToWallet(seed2Wallet).
+		Amount(100_000).
+		FakeInputs().
+		Build().
+		Store()
+
+	conf, _ := te.IssueAndConfirmMilestoneOnTips(hornet.MessageIDs{messageC.StoredMessageID()}, true)
+
+	// the outputs created by the first milestone are spent by the second one
+	messageD := te.NewMessageBuilder("D").
+		Parents(hornet.MessageIDs{messageC.StoredMessageID()}).
+		FromWallet(seed2Wallet).
+		ToWallet(seed4Wallet).
+		Amount(3_000_000).
+		Build().
+		Store().
+		BookOnWallets()
+
+	_, confStats := te.IssueAndConfirmMilestoneOnTips(hornet.MessageIDs{messageD.StoredMessageID()}, true)
+	require.Equal(t, 1, confStats.MessagesIncludedWithTransactions)
+	te.AssertWalletBalance(seed4Wallet, 3_000_000)
+
+	cachedMilestoneMessage := te.Storage().CachedMessageOrNil(conf.MilestoneMessageID)
+	require.NotNil(t, cachedMilestoneMessage)
+	defer cachedMilestoneMessage.Release(true)
+
+	ms := cachedMilestoneMessage.Message().Milestone()
+	require.NotNil(t, ms)
+
+	te.UTXOManager().ReadLockLedger()
+	defer te.UTXOManager().ReadUnlockLedger()
+
+	// the first milestone is applied to the ledger state before it again
+	mutations, err := whiteflag.RecomputeWhiteFlagMutations(context.Background(), te.Storage(), conf.MilestoneIndex, ms.Timestamp, cachedMilestoneMessage.Message().Parents())
+	require.NoError(t, err)
+
+	require.Equal(t, conf.Mutations.MerkleTreeHash, mutations.MerkleTreeHash)
+	require.Equal(t, ms.InclusionMerkleProof, iotago.MilestoneInclusionMerkleProof(mutations.MerkleTreeHash))
+	require.Equal(t, conf.Mutations.MessagesReferenced, mutations.MessagesReferenced)
+	require.Equal(t, conf.Mutations.MessagesIncludedWithTransactions, mutations.MessagesIncludedWithTransactions)
+	require.Equal(t, conf.Mutations.MessagesExcludedWithConflictingTransactions, mutations.MessagesExcludedWithConflictingTransactions)
+	require.Equal(t, conf.Mutations.MessagesExcludedWithoutTransactions, mutations.MessagesExcludedWithoutTransactions)
+	require.Len(t, mutations.NewSpents, len(conf.Mutations.NewSpents))
+	require.Len(t, mutations.NewOutputs, len(conf.Mutations.NewOutputs))
+}
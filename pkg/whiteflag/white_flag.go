@@ -68,6 +68,84 @@ type WhiteFlagMutations struct {
 // The ledger state must be write locked while this function is getting called in order to ensure consistency.
 // metadataMemcache has to be cleaned up outside.
 func ComputeWhiteFlagMutations(ctx context.Context, dbStorage *storage.Storage, msIndex milestone.Index, msTimestamp uint64, metadataMemcache *storage.MetadataMemcache, messagesMemcache *storage.MessagesMemcache, parents hornet.MessageIDs) (*WhiteFlagMutations, error) {
+
+	// only traverse and process the messages that were not referenced yet
+	notReferenced := func(metadata *storage.MessageMetadata) bool {
+		return !metadata.IsReferenced()
+	}
+
+	// the inputs are checked against the current ledger state
+	currentLedger := func(outputID *iotago.OutputID) (*utxo.Output, bool, error) {
+		output, err := dbStorage.UTXOManager().ReadOutputByOutputIDWithoutLocking(outputID)
+		if err != nil {
+			return nil, false, err
+		}
+
+		unspent, err := dbStorage.UTXOManager().IsOutputUnspentWithoutLocking(output)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return output, unspent, nil
+	}
+
+	return computeWhiteFlagMutations(ctx, dbStorage, msIndex, msTimestamp, metadataMemcache, messagesMemcache, parents, notReferenced, currentLedger)
+}
+
+// RecomputeWhiteFlagMutations computes the ledger changes of the already confirmed milestone with the given index again,
+// by applying the messages referenced by the milestone to the ledger state before the milestone.
+// The result can be compared to the stored metadata of the messages and the inclusion Merkle proof of the milestone.
+// Outputs that were spent before the milestone and were already pruned are treated as not found.
+// The ledger state must be read locked while this function is getting called in order to ensure consistency.
+func RecomputeWhiteFlagMutations(ctx context.Context, dbStorage *storage.Storage, msIndex milestone.Index, msTimestamp uint64, parents hornet.MessageIDs) (*WhiteFlagMutations, error) {
+
+	messagesMemcache := storage.NewMessagesMemcache(dbStorage)
+	metadataMemcache := storage.NewMetadataMemcache(dbStorage)
+
+	defer func() {
+		// release all messages at the end
+		messagesMemcache.Cleanup(true)
+
+		// Release all message metadata at the end
+		metadataMemcache.Cleanup(true)
+	}()
+
+	// the cone of the parents that was not referenced by an older milestone
+	referencedByMilestone := func(metadata *storage.MessageMetadata) bool {
+		referenced, referencedIndex := metadata.ReferencedWithIndex()
+		return referenced && referencedIndex == msIndex
+	}
+
+	// the inputs are checked against the ledger state before the milestone was applied
+	ledgerBeforeMilestone := func(outputID *iotago.OutputID) (*utxo.Output, bool, error) {
+		output, err := dbStorage.UTXOManager().ReadOutputByOutputIDWithoutLocking(outputID)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if output.MilestoneIndex() >= msIndex {
+			// the output was created by this or a younger milestone
+			return nil, false, kvstore.ErrKeyNotFound
+		}
+
+		spent, err := dbStorage.UTXOManager().ReadSpentForOutputIDWithoutLocking(outputID)
+		if err != nil {
+			if errors.Is(err, kvstore.ErrKeyNotFound) {
+				return output, true, nil
+			}
+			return nil, false, err
+		}
+
+		return output, spent.MilestoneIndex() >= msIndex, nil
+	}
+
+	return computeWhiteFlagMutations(ctx, dbStorage, msIndex, msTimestamp, metadataMemcache, messagesMemcache, parents, referencedByMilestone, ledgerBeforeMilestone)
+}
+
+// computeWhiteFlagMutations computes the ledger changes for the messages of the cone referenced by the parents that pass the given filter.
+// The inputs of the transactions are looked up in the ledger state given by ledgerOutput,
+// which returns the output and whether it is unspent, or kvstore.ErrKeyNotFound if the output doesn't exist.
+func computeWhiteFlagMutations(ctx context.Context, dbStorage *storage.Storage, msIndex milestone.Index, msTimestamp uint64, metadataMemcache *storage.MetadataMemcache, messagesMemcache *storage.MessagesMemcache, parents hornet.MessageIDs, filter func(metadata *storage.MessageMetadata) bool, ledgerOutput func(outputID *iotago.OutputID) (*utxo.Output, bool, error)) (*WhiteFlagMutations, error) {
 	wfConf := &WhiteFlagMutations{
 		MessagesIncludedWithTransactions:            make(hornet.MessageIDs, 0),
 		MessagesExcludedWithConflictingTransactions: make([]MessageWithConflict, 0),
@@ -89,8 +167,7 @@ func ComputeWhiteFlagMutations(ctx context.Context, dbStorage *storage.Storage,
 	condition := func(cachedMetadata *storage.CachedMetadata) (bool, error) { // meta +1
 		defer cachedMetadata.Release(true) // meta -1
 
-		// only traverse and process the message if it was not referenced yet
-		return !cachedMetadata.Metadata().IsReferenced(), nil
+		return filter(cachedMetadata.Metadata()), nil
 	}
 
 	// consumer
@@ -142,8 +219,8 @@ func ComputeWhiteFlagMutations(ctx context.Context, dbStorage *storage.Storage,
 				continue
 			}
 
-			// check current ledger for this input
-			output, err = dbStorage.UTXOManager().ReadOutputByOutputIDWithoutLocking(input)
+			// check the ledger for this input
+			output, unspent, err := ledgerOutput(input)
 			if err != nil {
 				if errors.Is(err, kvstore.ErrKeyNotFound) {
 					// input not found, so mark as invalid tx
@@ -153,12 +230,6 @@ func ComputeWhiteFlagMutations(ctx context.Context, dbStorage *storage.Storage,
 				return err
 			}
 
-			// check if this output is unspent
-			unspent, err := dbStorage.UTXOManager().IsOutputUnspentWithoutLocking(output)
-			if err != nil {
-				return err
-			}
-
 			if !unspent {
 				// output is already spent, so mark as conflict
 				conflict = storage.ConflictInputUTXOAlreadySpent
@@ -171,15 +242,7 @@ func ComputeWhiteFlagMutations(ctx context.Context, dbStorage *storage.Storage,
 		if conflict == storage.ConflictNone {
 			// Verify that all outputs consume all inputs and have valid signatures. Also verify that the amounts match.
 			if err := transaction.SemanticallyValidate(semValCtx, inputOutputs.ToOutputSet()); err != nil {
-				if errors.Is(err, iotago.ErrMissingUTXO) {
-					conflict = storage.ConflictInputUTXONotFound
-				} else if errors.Is(err, iotago.ErrInputOutputSumMismatch) {
-					conflict = storage.ConflictInputOutputSumMismatch
-				} else if errors.Is(err, iotago.ErrEd25519SignatureInvalid) || errors.Is(err, iotago.ErrEd25519PubKeyAndAddrMismatch) {
-					conflict = storage.ConflictInvalidSignature
-				} else {
-					conflict = storage.ConflictSemanticValidationFailed
-				}
+				conflict = semanticValidationConflict(err)
 			}
 		}
 
@@ -270,3 +333,66 @@ func ComputeWhiteFlagMutations(ctx context.Context, dbStorage *storage.Storage,
 
 	return wfConf, nil
 }
+
+// semanticValidationConflict returns the conflict reason for the error of the semantic validation of a transaction.
+func semanticValidationConflict(err error) storage.Conflict {
+	switch {
+	case errors.Is(err, iotago.ErrMissingUTXO):
+		return storage.ConflictInputUTXONotFound
+	case errors.Is(err, iotago.ErrInputOutputSumMismatch):
+		return storage.ConflictInputOutputSumMismatch
+	case errors.Is(err, iotago.ErrEd25519SignatureInvalid) || errors.Is(err, iotago.ErrEd25519PubKeyAndAddrMismatch):
+		return storage.ConflictInvalidSignature
+	default:
+		return storage.ConflictSemanticValidationFailed
+	}
+}
+
+// CheckTransaction checks whether the transaction of the given message could be applied to the current ledger state
+// if the message was referenced by a milestone with the given index and timestamp.
+// If the transaction would be conflicting, the conflict reason and its cause are returned, otherwise ConflictNone.
+// An error with ConflictNone is returned if the check itself failed.
+// The ledger state must be read locked while this function is getting called in order to ensure consistency.
+func CheckTransaction(utxoManager *utxo.Manager, message *storage.Message, msIndex milestone.Index, msTimestamp uint64) (storage.Conflict, error) {
+
+	transaction := message.Transaction()
+	if transaction == nil {
+		return storage.ConflictNone, nil
+	}
+
+	inputOutputs := utxo.Outputs{}
+	for _, input := range message.TransactionEssenceUTXOInputs() {
+
+		output, err := utxoManager.ReadOutputByOutputIDWithoutLocking(input)
+		if err != nil {
+			if errors.Is(err, kvstore.ErrKeyNotFound) {
+				return storage.ConflictInputUTXONotFound, fmt.Errorf("input %s not found", input.ToHex())
+			}
+			return storage.ConflictNone, err
+		}
+
+		unspent, err := utxoManager.IsOutputUnspentWithoutLocking(output)
+		if err != nil {
+			return storage.ConflictNone, err
+		}
+
+		if !unspent {
+			return storage.ConflictInputUTXOAlreadySpent, fmt.Errorf("input %s already spent", input.ToHex())
+		}
+
+		inputOutputs = append(inputOutputs, output)
+	}
+
+	semValCtx := &iotago.SemanticValidationContext{
+		ExtParas: &iotago.ExternalUnlockParameters{
+			ConfMsIndex: uint32(msIndex),
+			ConfUnix:    uint32(msTimestamp),
+		},
+	}
+
+	if err := transaction.SemanticallyValidate(semValCtx, inputOutputs.ToOutputSet()); err != nil {
+		return semanticValidationConflict(err), err
+	}
+
+	return storage.ConflictNone, nil
+}
@@ -1,11 +1,18 @@
 package whiteflag
 
 import (
+	"bytes"
 	"crypto"
 	"encoding"
+	"errors"
 	"math/bits"
 )
 
+var (
+	// ErrLeafIndexOutOfRange is returned if the index of a leaf is not within the size of the tree.
+	ErrLeafIndexOutOfRange = errors.New("leaf index is out of range")
+)
+
 // Domain separation prefixes
 const (
 	LeafHashPrefix = 0
@@ -54,6 +61,77 @@ func (t *Hasher) Hash(data []encoding.BinaryMarshaler) ([]byte, error) {
 	return t.hashNode(l, r), nil
 }
 
+// AuditPath computes the Merkle audit path of the leaf at the given index of the provided data encodings.
+// The path contains the hashes of the sibling nodes needed to compute the Merkle tree hash from the leaf, ordered from the leaf to the root.
+func (t *Hasher) AuditPath(data []encoding.BinaryMarshaler, index int) ([][]byte, error) {
+	if index < 0 || index >= len(data) {
+		return nil, ErrLeafIndexOutOfRange
+	}
+	if len(data) == 1 {
+		return [][]byte{}, nil
+	}
+
+	k := largestPowerOfTwo(len(data))
+	if index < k {
+		path, err := t.AuditPath(data[:k], index)
+		if err != nil {
+			return nil, err
+		}
+		r, err := t.Hash(data[k:])
+		if err != nil {
+			return nil, err
+		}
+		return append(path, r), nil
+	}
+
+	path, err := t.AuditPath(data[k:], index-k)
+	if err != nil {
+		return nil, err
+	}
+	l, err := t.Hash(data[:k])
+	if err != nil {
+		return nil, err
+	}
+	return append(path, l), nil
+}
+
+// VerifyAuditPath checks whether the given audit path proves that the leaf at the given index is part of
+// the tree of the given size with the given Merkle tree hash.
+func (t *Hasher) VerifyAuditPath(leaf encoding.BinaryMarshaler, index int, size int, path [][]byte, root []byte) (bool, error) {
+	if index < 0 || index >= size {
+		return false, ErrLeafIndexOutOfRange
+	}
+
+	r, err := t.hashLeaf(leaf)
+	if err != nil {
+		return false, err
+	}
+
+	// the node indexes of the current node and the last node of the current level of the tree
+	fn, sn := index, size-1
+	for _, p := range path {
+		if sn == 0 {
+			// the path is longer than the height of the tree
+			return false, nil
+		}
+
+		if fn&1 == 1 || fn == sn {
+			r = t.hashNode(p, r)
+			// skip the levels in which the node has no right sibling
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = t.hashNode(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+
+	return sn == 0 && bytes.Equal(r, root), nil
+}
+
 // hashLeaf returns the Merkle tree leaf hash of data.
 func (t *Hasher) hashLeaf(data encoding.BinaryMarshaler) ([]byte, error) {
 	b, err := data.MarshalBinary()
@@ -0,0 +1,121 @@
+package alerting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/alerting"
+)
+
+// alertEvent is the type of an alert notification.
+type alertEvent string
+
+const (
+	alertEventFired    alertEvent = "fired"
+	alertEventResolved alertEvent = "resolved"
+)
+
+// webhookPayload is the JSON payload posted to the webhooks.
+type webhookPayload struct {
+	// The type of the event.
+	Event alertEvent `json:"event"`
+	// The alert at the time of the event.
+	Alert *alerting.Alert `json:"alert"`
+}
+
+// notifier sends the alert notifications to the configured webhooks and email recipients.
+type notifier struct {
+	webhookURLs []string
+	client      *http.Client
+
+	smtpAddress string
+	smtpAuth    smtp.Auth
+	emailFrom   string
+	emailTo     []string
+}
+
+func newNotifier(webhookURLs []string, webhookTimeout time.Duration, smtpAddress string, username string, password string, from string, to []string) (*notifier, error) {
+	n := &notifier{
+		webhookURLs: webhookURLs,
+		client:      &http.Client{Timeout: webhookTimeout},
+		smtpAddress: smtpAddress,
+		emailFrom:   from,
+		emailTo:     to,
+	}
+
+	if smtpAddress == "" {
+		return n, nil
+	}
+
+	host, _, err := net.SplitHostPort(smtpAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP address %s: %w", smtpAddress, err)
+	}
+	if from == "" || len(to) == 0 {
+		return nil, fmt.Errorf("the sender and the recipients of the alert emails are required")
+	}
+	if username != "" {
+		n.smtpAuth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return n, nil
+}
+
+// notify posts the event to all webhooks and sends it to the email recipients.
+// Failures are logged and not retried.
+func (n *notifier) notify(event alertEvent, alert *alerting.Alert) {
+	n.postWebhooks(event, alert)
+	n.sendEmail(event, alert)
+}
+
+func (n *notifier) postWebhooks(event alertEvent, alert *alerting.Alert) {
+	if len(n.webhookURLs) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(&webhookPayload{Event: event, Alert: alert})
+	if err != nil {
+		Plugin.LogWarnf("failed to marshal alert: %s", err)
+		return
+	}
+
+	for _, url := range n.webhookURLs {
+		res, err := n.client.Post(url, "application/json", bytes.NewReader(payload))
+		if err != nil {
+			Plugin.LogWarnf("failed to post alert to %s: %s", url, err)
+			continue
+		}
+		_ = res.Body.Close()
+
+		if res.StatusCode < 200 || res.StatusCode >= 300 {
+			Plugin.LogWarnf("failed to post alert to %s: status code %d", url, res.StatusCode)
+		}
+	}
+}
+
+func (n *notifier) sendEmail(event alertEvent, alert *alerting.Alert) {
+	if n.smtpAddress == "" {
+		return
+	}
+
+	subject := fmt.Sprintf("[%s] %s", strings.ToUpper(string(event)), alert.Rule.Name)
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Rule: %s %s %g for %v\r\n", alert.Rule.Metric, alert.Rule.Operator, alert.Rule.Threshold, alert.Rule.For)
+	fmt.Fprintf(&body, "Value: %g\r\n", alert.Value)
+	fmt.Fprintf(&body, "Since: %s\r\n", alert.Since.Format(time.RFC3339))
+	if event == alertEventResolved {
+		fmt.Fprintf(&body, "Resolved: %s\r\n", alert.ResolvedAt.Format(time.RFC3339))
+	}
+
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s", n.emailFrom, strings.Join(n.emailTo, ", "), subject, body.String())
+	if err := smtp.SendMail(n.smtpAddress, n.smtpAuth, n.emailFrom, n.emailTo, []byte(msg)); err != nil {
+		Plugin.LogWarnf("failed to send alert email: %s", err)
+	}
+}
@@ -0,0 +1,49 @@
+package alerting
+
+import (
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gohornet/hornet/pkg/node"
+)
+
+const (
+	// the interval in which the alert rules are evaluated.
+	CfgAlertingInterval = "alerting.interval"
+	// the alert rules, every rule consists of a "name", a "metric", an "operator" ("<" or ">"), a "threshold"
+	// and the duration "for" which the condition has to hold before the alert fires.
+	CfgAlertingRules = "alerting.rules"
+	// the URLs the alerts are posted to.
+	CfgAlertingWebhookURLs = "alerting.webhooks.urls"
+	// the timeout for posting an alert to a webhook.
+	CfgAlertingWebhookTimeout = "alerting.webhooks.timeout"
+	// the address of the SMTP server the alert emails are sent with (empty = disabled).
+	CfgAlertingEmailSMTPAddress = "alerting.email.smtpAddress"
+	// the username for the SMTP server (empty = no auth).
+	CfgAlertingEmailUsername = "alerting.email.username"
+	// the password for the SMTP server.
+	CfgAlertingEmailPassword = "alerting.email.password"
+	// the sender address of the alert emails.
+	CfgAlertingEmailFrom = "alerting.email.from"
+	// the recipient addresses of the alert emails.
+	CfgAlertingEmailTo = "alerting.email.to"
+)
+
+var params = &node.PluginParams{
+	Params: map[string]*flag.FlagSet{
+		"nodeConfig": func() *flag.FlagSet {
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.Duration(CfgAlertingInterval, 10*time.Second, "the interval in which the alert rules are evaluated")
+			fs.StringSlice(CfgAlertingWebhookURLs, nil, "the URLs the alerts are posted to")
+			fs.Duration(CfgAlertingWebhookTimeout, 5*time.Second, "the timeout for posting an alert to a webhook")
+			fs.String(CfgAlertingEmailSMTPAddress, "", "the address of the SMTP server the alert emails are sent with (empty = disabled)")
+			fs.String(CfgAlertingEmailUsername, "", "the username for the SMTP server (empty = no auth)")
+			fs.String(CfgAlertingEmailPassword, "", "the password for the SMTP server")
+			fs.String(CfgAlertingEmailFrom, "", "the sender address of the alert emails")
+			fs.StringSlice(CfgAlertingEmailTo, nil, "the recipient addresses of the alert emails")
+			return fs
+		}(),
+	},
+	Masked: []string{CfgAlertingEmailPassword},
+}
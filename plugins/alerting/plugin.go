@@ -0,0 +1,198 @@
+package alerting
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/dig"
+
+	"github.com/gohornet/hornet/pkg/alerting"
+	"github.com/gohornet/hornet/pkg/model/syncmanager"
+	"github.com/gohornet/hornet/pkg/node"
+	"github.com/gohornet/hornet/pkg/p2p"
+	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/gohornet/hornet/pkg/tangle"
+	"github.com/gohornet/hornet/plugins/restapi"
+	restapiv2 "github.com/gohornet/hornet/plugins/restapi/v2"
+	"github.com/iotaledger/hive.go/configuration"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/timeutil"
+	"github.com/iotaledger/hive.go/workerpool"
+)
+
+const (
+	// the amount of alert notifications which are queued to be sent.
+	notificationQueueSize = 100
+)
+
+func init() {
+	Plugin = &node.Plugin{
+		Status: node.StatusDisabled,
+		Pluggable: node.Pluggable{
+			Name:      "Alerting",
+			DepsFunc:  func(cDeps dependencies) { deps = cDeps },
+			Params:    params,
+			Provide:   provide,
+			Configure: configure,
+			Run:       run,
+		},
+	}
+}
+
+var (
+	Plugin *node.Plugin
+	deps   dependencies
+
+	notificationWorkerPool *workerpool.WorkerPool
+
+	// the referenced rate of the latest confirmed milestone, nil until the first milestone was confirmed.
+	referencedRate     *float64
+	referencedRateLock sync.RWMutex
+
+	// Closures
+	onNewConfirmedMilestoneMetric *events.Closure
+	onAlertFired                  *events.Closure
+	onAlertResolved               *events.Closure
+)
+
+type dependencies struct {
+	dig.In
+	NodeConfig     *configuration.Configuration `name:"nodeConfig"`
+	Tangle         *tangle.Tangle
+	SyncManager    *syncmanager.SyncManager
+	PeeringManager *p2p.Manager
+	AlertManager   *alerting.Manager
+}
+
+func provide(c *dig.Container) {
+
+	type managerDeps struct {
+		dig.In
+		NodeConfig *configuration.Configuration `name:"nodeConfig"`
+	}
+
+	if err := c.Provide(func(deps managerDeps) *alerting.Manager {
+		var rules []*alerting.Rule
+		if err := deps.NodeConfig.Unmarshal(CfgAlertingRules, &rules); err != nil {
+			Plugin.LogPanicf("invalid alert rules: %s", err)
+		}
+
+		manager, err := alerting.NewManager(rules)
+		if err != nil {
+			Plugin.LogPanicf("invalid alert rules: %s", err)
+		}
+		return manager
+	}); err != nil {
+		Plugin.LogPanic(err)
+	}
+}
+
+func configure() {
+	// check if RestAPI plugin is disabled
+	if Plugin.Node.IsSkipped(restapi.Plugin) {
+		Plugin.LogPanic("RestAPI plugin needs to be enabled to use the Alerting plugin")
+	}
+
+	notifier, err := newNotifier(
+		deps.NodeConfig.Strings(CfgAlertingWebhookURLs),
+		deps.NodeConfig.Duration(CfgAlertingWebhookTimeout),
+		deps.NodeConfig.String(CfgAlertingEmailSMTPAddress),
+		deps.NodeConfig.String(CfgAlertingEmailUsername),
+		deps.NodeConfig.String(CfgAlertingEmailPassword),
+		deps.NodeConfig.String(CfgAlertingEmailFrom),
+		deps.NodeConfig.Strings(CfgAlertingEmailTo),
+	)
+	if err != nil {
+		Plugin.LogPanicf("invalid alert notification settings: %s", err)
+	}
+
+	notificationWorkerPool = workerpool.New(func(task workerpool.Task) {
+		notifier.notify(task.Param(0).(alertEvent), task.Param(1).(*alerting.Alert))
+		task.Return(nil)
+	}, workerpool.WorkerCount(1), workerpool.QueueSize(notificationQueueSize))
+
+	setupRoutes(restapiv2.AddPlugin("alerting/v1"))
+
+	configureEvents()
+}
+
+func run() {
+	if err := Plugin.Daemon().BackgroundWorker("Alerting", func(ctx context.Context) {
+		Plugin.LogInfo("Starting Alerting ... done")
+		attachEvents()
+		notificationWorkerPool.Start()
+
+		ticker := timeutil.NewTicker(evaluateRules, deps.NodeConfig.Duration(CfgAlertingInterval), ctx)
+		ticker.WaitForGracefulShutdown()
+
+		Plugin.LogInfo("Stopping Alerting ...")
+		detachEvents()
+		notificationWorkerPool.StopAndWait()
+		Plugin.LogInfo("Stopping Alerting ... done")
+	}, shutdown.PriorityAlerting); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+}
+
+// currentMetrics collects the values of the metrics the alert rules are evaluated for.
+func currentMetrics() map[string]float64 {
+	values := map[string]float64{
+		alerting.MetricSynced:         0,
+		alerting.MetricConnectedPeers: float64(deps.PeeringManager.ConnectedCount()),
+		alerting.MetricMilestoneLag:   0,
+	}
+
+	if cmi, lmi := deps.SyncManager.ConfirmedMilestoneIndex(), deps.SyncManager.LatestMilestoneIndex(); lmi > cmi {
+		values[alerting.MetricMilestoneLag] = float64(lmi - cmi)
+	}
+
+	if deps.SyncManager.IsNodeSynced() {
+		values[alerting.MetricSynced] = 1
+	}
+
+	referencedRateLock.RLock()
+	defer referencedRateLock.RUnlock()
+
+	if referencedRate != nil {
+		values[alerting.MetricReferencedRate] = *referencedRate
+	}
+
+	return values
+}
+
+func evaluateRules() {
+	deps.AlertManager.Evaluate(time.Now(), currentMetrics())
+}
+
+func configureEvents() {
+	onNewConfirmedMilestoneMetric = events.NewClosure(func(metric *tangle.ConfirmedMilestoneMetric) {
+		rate := metric.ReferencedRate
+
+		referencedRateLock.Lock()
+		defer referencedRateLock.Unlock()
+		referencedRate = &rate
+	})
+
+	onAlertFired = events.NewClosure(func(alert *alerting.Alert) {
+		Plugin.LogWarnf("alert %s fired: %s %s %g for %v, value: %g", alert.Rule.Name, alert.Rule.Metric, alert.Rule.Operator, alert.Rule.Threshold, alert.Rule.For, alert.Value)
+		notificationWorkerPool.TrySubmit(alertEventFired, alert)
+	})
+
+	onAlertResolved = events.NewClosure(func(alert *alerting.Alert) {
+		Plugin.LogInfof("alert %s resolved after %v, value: %g", alert.Rule.Name, alert.ResolvedAt.Sub(alert.Since).Truncate(time.Second), alert.Value)
+		notificationWorkerPool.TrySubmit(alertEventResolved, alert)
+	})
+}
+
+func attachEvents() {
+	deps.Tangle.Events.NewConfirmedMilestoneMetric.Attach(onNewConfirmedMilestoneMetric)
+	deps.AlertManager.Events.AlertFired.Attach(onAlertFired)
+	deps.AlertManager.Events.AlertResolved.Attach(onAlertResolved)
+}
+
+func detachEvents() {
+	deps.Tangle.Events.NewConfirmedMilestoneMetric.Detach(onNewConfirmedMilestoneMetric)
+	deps.AlertManager.Events.AlertFired.Detach(onAlertFired)
+	deps.AlertManager.Events.AlertResolved.Detach(onAlertResolved)
+}
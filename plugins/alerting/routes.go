@@ -0,0 +1,39 @@
+package alerting
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gohornet/hornet/pkg/alerting"
+	restapipkg "github.com/gohornet/hornet/pkg/restapi"
+)
+
+const (
+	// RouteAlerts is the route to get the active alerts.
+	// GET returns the alerts that fired and are not resolved yet and the configured rules.
+	RouteAlerts = "/alerts"
+)
+
+// alertsResponse defines the response of a GET alerts REST API call.
+type alertsResponse struct {
+	// The alerts that fired and are not resolved yet.
+	Alerts []*alerting.Alert `json:"alerts"`
+	// The configured rules.
+	Rules []*alerting.Rule `json:"rules"`
+}
+
+func setupRoutes(g *echo.Group) {
+
+	g.GET(RouteAlerts, func(c echo.Context) error {
+		alerts := deps.AlertManager.ActiveAlerts()
+		if alerts == nil {
+			alerts = []*alerting.Alert{}
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, &alertsResponse{
+			Alerts: alerts,
+			Rules:  deps.AlertManager.Rules(),
+		})
+	})
+}
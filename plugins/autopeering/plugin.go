@@ -71,6 +71,7 @@ var (
 	onSelectionOutgoingPeering *events.Closure
 	onSelectionIncomingPeering *events.Closure
 	onSelectionDropped         *events.Closure
+	onSelectionReset           *events.Closure
 	onPeerDisconnected         *events.Closure
 	onAutopeerBecameKnown      *events.Closure
 )
@@ -177,6 +178,11 @@ func provide(c *dig.Container) {
 			deps.NodeConfig.Strings(CfgNetAutopeeringEntryNodes),
 			deps.NodeConfig.Bool(CfgNetAutopeeringEntryNodesPreferIPv6),
 			service.Key(deps.NetworkIDName),
+			selection.Parameters{
+				InboundNeighborSize:  deps.NodeConfig.Int(CfgNetAutopeeringInboundPeers),
+				OutboundNeighborSize: deps.NodeConfig.Int(CfgNetAutopeeringOutboundPeers),
+				SaltLifetime:         deps.NodeConfig.Duration(CfgNetAutopeeringSaltLifetime),
+			},
 		)
 	}); err != nil {
 		Plugin.LogPanic(err)
@@ -184,12 +190,6 @@ func provide(c *dig.Container) {
 }
 
 func configure() {
-	selection.SetParameters(selection.Parameters{
-		InboundNeighborSize:  deps.NodeConfig.Int(CfgNetAutopeeringInboundPeers),
-		OutboundNeighborSize: deps.NodeConfig.Int(CfgNetAutopeeringOutboundPeers),
-		SaltLifetime:         deps.NodeConfig.Duration(CfgNetAutopeeringSaltLifetime),
-	})
-
 	if err := autopeering.RegisterAutopeeringProtocolInMultiAddresses(); err != nil {
 		Plugin.LogPanicf("unable to register autopeering protocol for multi addresses: %s", err)
 	}
@@ -228,6 +228,11 @@ func configure() {
 
 	deps.AutopeeringManager.Init(localPeerContainer, initSelection)
 	configureEvents()
+
+	// the peer selection can only be changed at runtime if the RestAPI is available
+	if initSelection && !Plugin.Node.IsSkipped(restapiv2.Plugin) {
+		setupRoutes(restapiv2.AddPlugin("autopeering/v1"))
+	}
 }
 
 func run() {
@@ -340,30 +345,39 @@ func configureEvents() {
 		}
 
 		Plugin.LogInfof("[dropped event] disconnecting %s / %s", ev.Peer.Address(), peerID.ShortString())
+		disconnectAutopeer(peerID, errors.New("removed via autopeering selection"))
+	})
 
-		if err := deps.PeeringManager.DisallowPeer(peerID); err != nil {
-			Plugin.LogWarnf("couldn't disallow autopeering peer %s: %s", peerID.ShortString(), err)
-		}
+	onSelectionReset = events.NewClosure(func(oldSelection *selection.Protocol, newSelection *selection.Protocol) {
+		detachSelectionEvents(oldSelection)
+		attachSelectionEvents(newSelection)
+	})
+}
 
-		var peerRelation p2p.PeerRelation
-		deps.PeeringManager.Call(peerID, func(p *p2p.Peer) {
-			peerRelation = p.Relation
-		})
+// disallows the given peer and disconnects it, if it is still autopeered.
+func disconnectAutopeer(peerID libp2p.ID, reason error) {
+	if err := deps.PeeringManager.DisallowPeer(peerID); err != nil {
+		Plugin.LogWarnf("couldn't disallow autopeering peer %s: %s", peerID.ShortString(), err)
+	}
 
-		if len(peerRelation) == 0 {
-			Plugin.LogWarnf("didn't find autopeered peer %s for disconnecting", peerID.ShortString())
-			return
-		}
+	var peerRelation p2p.PeerRelation
+	deps.PeeringManager.Call(peerID, func(p *p2p.Peer) {
+		peerRelation = p.Relation
+	})
 
-		if peerRelation != p2p.PeerRelationAutopeered {
-			Plugin.LogWarnf("won't disconnect %s as its relation is not '%s' but '%s'", peerID.ShortString(), p2p.PeerRelationAutopeered, peerRelation)
-			return
-		}
+	if len(peerRelation) == 0 {
+		Plugin.LogWarnf("didn't find autopeered peer %s for disconnecting", peerID.ShortString())
+		return
+	}
 
-		if err := deps.PeeringManager.DisconnectPeer(peerID, errors.New("removed via autopeering selection")); err != nil {
-			Plugin.LogWarnf("couldn't disconnect selection dropped autopeer %s: %s", peerID.ShortString(), err)
-		}
-	})
+	if peerRelation != p2p.PeerRelationAutopeered {
+		Plugin.LogWarnf("won't disconnect %s as its relation is not '%s' but '%s'", peerID.ShortString(), p2p.PeerRelationAutopeered, peerRelation)
+		return
+	}
+
+	if err := deps.PeeringManager.DisconnectPeer(peerID, reason); err != nil {
+		Plugin.LogWarnf("couldn't disconnect selection dropped autopeer %s: %s", peerID.ShortString(), err)
+	}
 }
 
 // handles a peer gotten from the autopeering selection according to its existing relation.
@@ -409,6 +423,20 @@ func clearFromAutopeeringSelector(ev *selection.PeeringEvent) {
 	}
 }
 
+func attachSelectionEvents(selectionProtocol *selection.Protocol) {
+	selectionProtocol.Events().SaltUpdated.Attach(onSelectionSaltUpdated)
+	selectionProtocol.Events().OutgoingPeering.Attach(onSelectionOutgoingPeering)
+	selectionProtocol.Events().IncomingPeering.Attach(onSelectionIncomingPeering)
+	selectionProtocol.Events().Dropped.Attach(onSelectionDropped)
+}
+
+func detachSelectionEvents(selectionProtocol *selection.Protocol) {
+	selectionProtocol.Events().SaltUpdated.Detach(onSelectionSaltUpdated)
+	selectionProtocol.Events().OutgoingPeering.Detach(onSelectionOutgoingPeering)
+	selectionProtocol.Events().IncomingPeering.Detach(onSelectionIncomingPeering)
+	selectionProtocol.Events().Dropped.Detach(onSelectionDropped)
+}
+
 func attachEvents() {
 
 	if deps.AutopeeringManager.Discovery() != nil {
@@ -416,14 +444,12 @@ func attachEvents() {
 		deps.AutopeeringManager.Discovery().Events().PeerDeleted.Attach(onDiscoveryPeerDeleted)
 	}
 
-	if deps.AutopeeringManager.Selection() != nil {
+	if selectionProtocol := deps.AutopeeringManager.Selection(); selectionProtocol != nil {
 		// notify the selection when a connection is closed or failed.
 		deps.PeeringManager.Events.Disconnected.Attach(onPeerDisconnected)
 		deps.PeeringManager.Events.RelationUpdated.Attach(onAutopeerBecameKnown)
-		deps.AutopeeringManager.Selection().Events().SaltUpdated.Attach(onSelectionSaltUpdated)
-		deps.AutopeeringManager.Selection().Events().OutgoingPeering.Attach(onSelectionOutgoingPeering)
-		deps.AutopeeringManager.Selection().Events().IncomingPeering.Attach(onSelectionIncomingPeering)
-		deps.AutopeeringManager.Selection().Events().Dropped.Attach(onSelectionDropped)
+		deps.AutopeeringManager.Events.SelectionReset.Attach(onSelectionReset)
+		attachSelectionEvents(selectionProtocol)
 	}
 }
 
@@ -434,12 +460,10 @@ func detachEvents() {
 		deps.AutopeeringManager.Discovery().Events().PeerDeleted.Detach(onDiscoveryPeerDeleted)
 	}
 
-	if deps.AutopeeringManager.Selection() != nil {
+	if selectionProtocol := deps.AutopeeringManager.Selection(); selectionProtocol != nil {
 		deps.PeeringManager.Events.Disconnected.Detach(onPeerDisconnected)
 		deps.PeeringManager.Events.RelationUpdated.Detach(onAutopeerBecameKnown)
-		deps.AutopeeringManager.Selection().Events().SaltUpdated.Detach(onSelectionSaltUpdated)
-		deps.AutopeeringManager.Selection().Events().OutgoingPeering.Detach(onSelectionOutgoingPeering)
-		deps.AutopeeringManager.Selection().Events().IncomingPeering.Detach(onSelectionIncomingPeering)
-		deps.AutopeeringManager.Selection().Events().Dropped.Detach(onSelectionDropped)
+		deps.AutopeeringManager.Events.SelectionReset.Detach(onSelectionReset)
+		detachSelectionEvents(selectionProtocol)
 	}
 }
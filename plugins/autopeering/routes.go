@@ -0,0 +1,139 @@
+package autopeering
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/p2p/autopeering"
+	"github.com/gohornet/hornet/pkg/restapi"
+	"github.com/iotaledger/hive.go/autopeering/peer"
+	"github.com/iotaledger/hive.go/autopeering/selection"
+)
+
+const (
+	// RouteAutopeeringSelection is the route to get or change the parameters of the peer selection.
+	// GET returns the current parameters and neighbors of the peer selection.
+	// POST changes the given parameters and resets the peer selection.
+	RouteAutopeeringSelection = "/selection"
+
+	// RouteAutopeeringSelectionReset is the route to reset the peer selection.
+	// POST renews the local salts and drops all autopeered neighbors.
+	RouteAutopeeringSelectionReset = "/selection/reset"
+)
+
+func setupRoutes(routeGroup *echo.Group) {
+
+	routeGroup.GET(RouteAutopeeringSelection, func(c echo.Context) error {
+		return restapi.JSONResponse(c, http.StatusOK, getSelection())
+	})
+
+	routeGroup.POST(RouteAutopeeringSelection, func(c echo.Context) error {
+		resp, err := changeSelection(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RouteAutopeeringSelectionReset, func(c echo.Context) error {
+		if err := resetSelection(deps.AutopeeringManager.SelectionParameters()); err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, getSelection())
+	})
+}
+
+func getSelection() *selectionResponse {
+
+	peerIDs := func(peers []*peer.Peer) []string {
+		result := make([]string, 0, len(peers))
+		for _, p := range peers {
+			peerID, err := autopeering.HivePeerToPeerID(p)
+			if err != nil {
+				continue
+			}
+			result = append(result, peerID.String())
+		}
+		return result
+	}
+
+	params := deps.AutopeeringManager.SelectionParameters()
+	selectionProtocol := deps.AutopeeringManager.Selection()
+
+	var saltExpiration int64
+	if publicSalt := localPeerContainer.Local().GetPublicSalt(); publicSalt != nil {
+		saltExpiration = publicSalt.GetExpiration().Unix()
+	}
+
+	return &selectionResponse{
+		InboundPeers:      params.InboundNeighborSize,
+		OutboundPeers:     params.OutboundNeighborSize,
+		SaltLifetime:      params.SaltLifetime.String(),
+		SaltExpiration:    saltExpiration,
+		InboundNeighbors:  peerIDs(selectionProtocol.GetIncomingNeighbors()),
+		OutboundNeighbors: peerIDs(selectionProtocol.GetOutgoingNeighbors()),
+	}
+}
+
+func changeSelection(c echo.Context) (*selectionResponse, error) {
+
+	request := &selectionRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	params := deps.AutopeeringManager.SelectionParameters()
+	if request.InboundPeers != nil {
+		params.InboundNeighborSize = *request.InboundPeers
+	}
+	if request.OutboundPeers != nil {
+		params.OutboundNeighborSize = *request.OutboundPeers
+	}
+	if request.SaltLifetime != nil {
+		saltLifetime, err := time.ParseDuration(*request.SaltLifetime)
+		if err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid salt lifetime: %s, error: %s", *request.SaltLifetime, err)
+		}
+		params.SaltLifetime = saltLifetime
+	}
+
+	if err := resetSelection(params); err != nil {
+		return nil, err
+	}
+
+	return getSelection(), nil
+}
+
+// resets the peer selection with the given parameters and disconnects the former autopeers.
+func resetSelection(params selection.Parameters) error {
+
+	oldNeighbors, err := deps.AutopeeringManager.ResetSelection(params)
+	if err != nil {
+		switch {
+		case errors.Is(err, autopeering.ErrInvalidSelectionParameters):
+			return errors.WithMessage(restapi.ErrInvalidParameter, err.Error())
+		case errors.Is(err, autopeering.ErrSelectionNotRunning):
+			return errors.WithMessage(echo.ErrServiceUnavailable, err.Error())
+		default:
+			return errors.WithMessagef(echo.ErrInternalServerError, "resetting the peer selection failed: %s", err)
+		}
+	}
+
+	for _, oldNeighbor := range oldNeighbors {
+		peerID, err := autopeering.HivePeerToPeerID(oldNeighbor)
+		if err != nil {
+			Plugin.LogWarnf("unable to convert former autopeering neighbor to peerID: %s", err)
+			continue
+		}
+
+		Plugin.LogInfof("[selection reset] disconnecting %s / %s", oldNeighbor.Address(), peerID.ShortString())
+		disconnectAutopeer(peerID, errors.New("removed via autopeering selection reset"))
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package autopeering
+
+// selectionResponse defines the response of a GET autopeering selection REST API call.
+type selectionResponse struct {
+	// The number of inbound autopeers.
+	InboundPeers int `json:"inboundPeers"`
+	// The number of outbound autopeers.
+	OutboundPeers int `json:"outboundPeers"`
+	// The lifetime of the private and public local salt.
+	SaltLifetime string `json:"saltLifetime"`
+	// The unix timestamp at which the current public salt expires.
+	SaltExpiration int64 `json:"saltExpiration"`
+	// The peer IDs of the current inbound autopeers.
+	InboundNeighbors []string `json:"inboundNeighbors"`
+	// The peer IDs of the current outbound autopeers.
+	OutboundNeighbors []string `json:"outboundNeighbors"`
+}
+
+// selectionRequest defines the request of a POST autopeering selection REST API call.
+// Fields which are not set keep their current value.
+type selectionRequest struct {
+	// The number of inbound autopeers.
+	InboundPeers *int `json:"inboundPeers,omitempty"`
+	// The number of outbound autopeers.
+	OutboundPeers *int `json:"outboundPeers,omitempty"`
+	// The lifetime of the private and public local salt (e.g. "2h").
+	SaltLifetime *string `json:"saltLifetime,omitempty"`
+}
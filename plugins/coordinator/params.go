@@ -12,16 +12,36 @@ import (
 const (
 	// CfgCoordinatorStateFilePath is the path to the state file of the coordinator.
 	CfgCoordinatorStateFilePath = "coordinator.stateFilePath"
+	// CfgCoordinatorShadowMode defines whether checkpoints and milestones are only created and exposed, but not sent to the network.
+	CfgCoordinatorShadowMode = "coordinator.shadowMode"
 	// CfgCoordinatorInterval is the interval at which milestones are issued.
 	CfgCoordinatorInterval = "coordinator.interval"
-	// CfgCoordinatorSigningProvider the signing provider the coordinator uses to sign a milestone (local/remote).
+	// CfgCoordinatorAdaptiveIntervalEnabled defines whether the milestone interval is adapted to the amount of unreferenced messages.
+	CfgCoordinatorAdaptiveIntervalEnabled = "coordinator.adaptiveInterval.enabled"
+	// CfgCoordinatorAdaptiveIntervalMinInterval defines the lower bound of the adaptive milestone interval.
+	CfgCoordinatorAdaptiveIntervalMinInterval = "coordinator.adaptiveInterval.minInterval"
+	// CfgCoordinatorAdaptiveIntervalMaxInterval defines the upper bound of the adaptive milestone interval.
+	CfgCoordinatorAdaptiveIntervalMaxInterval = "coordinator.adaptiveInterval.maxInterval"
+	// CfgCoordinatorAdaptiveIntervalTargetUnreferencedMessages defines the amount of unreferenced messages a milestone should reference.
+	// The interval is shortened if more messages arrive and lengthened if less messages arrive.
+	CfgCoordinatorAdaptiveIntervalTargetUnreferencedMessages = "coordinator.adaptiveInterval.targetUnreferencedMessages"
+	// CfgCoordinatorSigningProvider the signing provider the coordinator uses to sign a milestone (local/remote/multiparty).
 	CfgCoordinatorSigningProvider = "coordinator.signing.provider"
 	// CfgCoordinatorSigningRetryAmount defines the number of signing retries to perform before shutting down the node.
 	CfgCoordinatorSigningRetryAmount = "coordinator.signing.retryAmount"
 	// CfgCoordinatorSigningRetryTimeout defines the timeout between signing retries.
 	CfgCoordinatorSigningRetryTimeout = "coordinator.signing.retryTimeout"
-	// CfgCoordinatorSigningRemoteAddress the address of the remote signing provider (insecure connection!).
+	// CfgCoordinatorSigningRemoteAddress the address of the remote signing provider if no remote signers are configured (insecure connection!).
 	CfgCoordinatorSigningRemoteAddress = "coordinator.signing.remoteAddress"
+	// CfgCoordinatorSigningRemoteSigners defines the remote signing services, which are used in the given order if a signer fails.
+	CfgCoordinatorSigningRemoteSigners = "coordinator.signing.remoteSigners"
+	// CfgCoordinatorSigningCoSigners defines the co-signers of the multi-party signing, each holding one of the milestone keys.
+	// The amount of public keys in a milestone is the threshold of co-signers that need to sign a milestone.
+	CfgCoordinatorSigningCoSigners = "coordinator.signing.coSigners"
+	// CfgCoordinatorSigningRemoteTimeout defines the timeout of a request to a remote signing service.
+	CfgCoordinatorSigningRemoteTimeout = "coordinator.signing.remoteTimeout"
+	// CfgCoordinatorSigningHealthCheckInterval defines the interval in which the health of the remote signing services is checked.
+	CfgCoordinatorSigningHealthCheckInterval = "coordinator.signing.healthCheckInterval"
 	// CfgCoordinatorPoWWorkerCount the amount of workers used for calculating PoW when issuing checkpoints and milestones.
 	CfgCoordinatorPoWWorkerCount = "coordinator.powWorkerCount"
 	// CfgCoordinatorQuorumEnabled defines whether the coordinator quorum is enabled.
@@ -30,6 +50,11 @@ const (
 	CfgCoordinatorQuorumGroups = "coordinator.quorum.groups"
 	// CfgCoordinatorQuorumTimeout defines the timeout until a node in the quorum must have answered.
 	CfgCoordinatorQuorumTimeout = "coordinator.quorum.timeout"
+	// CfgCoordinatorQuorumThreshold defines the minimum amount of nodes in every quorum group that need to answer.
+	CfgCoordinatorQuorumThreshold = "coordinator.quorum.threshold"
+	// CfgCoordinatorQuorumFilePath defines the path to the file the quorum changes of the API are stored in.
+	// If the file exists, its groups, threshold and timeout are used instead of the configured ones.
+	CfgCoordinatorQuorumFilePath = "coordinator.quorum.filePath"
 	// CfgCoordinatorCheckpointsMaxTrackedMessages defines the maximum amount of known messages for milestone tipselection
 	// if this limit is exceeded, a new checkpoint is issued.
 	CfgCoordinatorCheckpointsMaxTrackedMessages = "coordinator.checkpoints.maxTrackedMessages"
@@ -51,14 +76,23 @@ var params = &node.PluginParams{
 		"nodeConfig": func() *flag.FlagSet {
 			fs := flag.NewFlagSet("", flag.ContinueOnError)
 			fs.String(CfgCoordinatorStateFilePath, "coordinator.state", "the path to the state file of the coordinator")
+			fs.Bool(CfgCoordinatorShadowMode, false, "whether checkpoints and milestones are only created and exposed, but not sent to the network")
 			fs.Duration(CfgCoordinatorInterval, 10*time.Second, "the interval milestones are issued")
+			fs.Bool(CfgCoordinatorAdaptiveIntervalEnabled, false, "whether the milestone interval is adapted to the amount of unreferenced messages")
+			fs.Duration(CfgCoordinatorAdaptiveIntervalMinInterval, 1*time.Second, "the lower bound of the adaptive milestone interval")
+			fs.Duration(CfgCoordinatorAdaptiveIntervalMaxInterval, 30*time.Second, "the upper bound of the adaptive milestone interval")
+			fs.Int(CfgCoordinatorAdaptiveIntervalTargetUnreferencedMessages, 1000, "the amount of unreferenced messages a milestone should reference")
 			fs.Duration(CfgCoordinatorSigningRetryTimeout, 2*time.Second, "defines the timeout between signing retries")
 			fs.Int(CfgCoordinatorSigningRetryAmount, 10, "defines the number of signing retries to perform before shutting down the node")
-			fs.String(CfgCoordinatorSigningProvider, "local", "the signing provider the coordinator uses to sign a milestone (local/remote)")
-			fs.String(CfgCoordinatorSigningRemoteAddress, "localhost:12345", "the address of the remote signing provider (insecure connection!)")
+			fs.String(CfgCoordinatorSigningProvider, "local", "the signing provider the coordinator uses to sign a milestone (local/remote/multiparty)")
+			fs.String(CfgCoordinatorSigningRemoteAddress, "localhost:12345", "the address of the remote signing provider if no remote signers are configured (insecure connection!)")
+			fs.Duration(CfgCoordinatorSigningRemoteTimeout, 5*time.Second, "the timeout of a request to a remote signing service")
+			fs.Duration(CfgCoordinatorSigningHealthCheckInterval, 10*time.Second, "the interval in which the health of the remote signing services is checked")
 			fs.Int(CfgCoordinatorPoWWorkerCount, runtime.NumCPU()-1, "the amount of workers used for calculating PoW when issuing checkpoints and milestones")
 			fs.Bool(CfgCoordinatorQuorumEnabled, false, "whether the coordinator quorum is enabled")
 			fs.Duration(CfgCoordinatorQuorumTimeout, 2*time.Second, "the timeout until a node in the quorum must have answered")
+			fs.Int(CfgCoordinatorQuorumThreshold, 1, "the minimum amount of nodes in every quorum group that need to answer")
+			fs.String(CfgCoordinatorQuorumFilePath, "coordinator_quorum.json", "the path to the file the quorum changes of the API are stored in")
 			fs.Int(CfgCoordinatorCheckpointsMaxTrackedMessages, 10000, "maximum amount of known messages for milestone tipselection")
 			fs.Int(CfgCoordinatorTipselectMinHeaviestBranchUnreferencedMessagesThreshold, 20, "minimum threshold of unreferenced messages in the heaviest branch")
 			fs.Int(CfgCoordinatorTipselectMaxHeaviestBranchTipsPerCheckpoint, 10, "maximum amount of checkpoint messages with heaviest branch tips")
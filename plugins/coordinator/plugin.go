@@ -1,11 +1,15 @@
 package coordinator
 
 import (
+	"bytes"
 	"crypto/ed25519"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/pkg/errors"
 	flag "github.com/spf13/pflag"
+	"go.uber.org/atomic"
 	"go.uber.org/dig"
 	"golang.org/x/net/context"
 
@@ -26,6 +30,7 @@ import (
 	"github.com/gohornet/hornet/pkg/shutdown"
 	"github.com/gohornet/hornet/pkg/tangle"
 	"github.com/gohornet/hornet/pkg/utils"
+	restapiv2 "github.com/gohornet/hornet/plugins/restapi/v2"
 	"github.com/iotaledger/hive.go/configuration"
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/syncutils"
@@ -70,7 +75,8 @@ var (
 	bootstrap  = flag.Bool(CfgCoordinatorBootstrap, false, "bootstrap the network")
 	startIndex = flag.Uint32(CfgCoordinatorStartIndex, 0, "index of the first milestone at bootstrap")
 
-	maxTrackedMessages int
+	// the maximum amount of known messages for milestone tipselection, can be changed at runtime
+	maxTrackedMessages atomic.Int64
 
 	nextCheckpointSignal chan struct{}
 	nextMilestoneSignal  chan struct{}
@@ -81,11 +87,15 @@ var (
 	lastCheckpointMessageID hornet.MessageID
 	lastMilestoneMessageID  hornet.MessageID
 
+	// the remote signing provider, if the milestones are signed by remote signing services
+	remoteSignerProvider coordinator.RemoteSignerHealthChecker
+
 	// Closures
 	onMessageSolid                   *events.Closure
 	onConfirmedMilestoneIndexChanged *events.Closure
 	onIssuedCheckpoint               *events.Closure
 	onIssuedMilestone                *events.Closure
+	onIssuedShadowMilestone          *events.Closure
 )
 
 type dependencies struct {
@@ -140,8 +150,7 @@ func provide(c *dig.Container) {
 		initCoordinator := func() (*coordinator.Coordinator, error) {
 
 			signingProvider, err := initSigningProvider(
-				deps.NodeConfig.String(CfgCoordinatorSigningProvider),
-				deps.NodeConfig.String(CfgCoordinatorSigningRemoteAddress),
+				deps.NodeConfig,
 				deps.KeyManager,
 				deps.MilestonePublicKeyCount,
 			)
@@ -149,12 +158,15 @@ func provide(c *dig.Container) {
 				return nil, fmt.Errorf("failed to initialize signing provider: %s", err)
 			}
 
-			quorumGroups, err := initQuorumGroups(deps.NodeConfig)
+			quorumConfig, err := initQuorumConfig(deps.NodeConfig)
 			if err != nil {
 				return nil, fmt.Errorf("failed to initialize coordinator quorum: %s", err)
 			}
 
 			if deps.NodeConfig.Bool(CfgCoordinatorQuorumEnabled) {
+				if err := quorumConfig.Validate(); err != nil {
+					return nil, fmt.Errorf("failed to initialize coordinator quorum: %s", err)
+				}
 				Plugin.LogInfo("running Coordinator with quorum enabled")
 			}
 
@@ -162,6 +174,14 @@ func provide(c *dig.Container) {
 				Plugin.LogInfo("running Coordinator without migration enabled")
 			}
 
+			if deps.NodeConfig.Bool(CfgCoordinatorShadowMode) {
+				Plugin.LogInfo("running Coordinator in shadow mode, checkpoints and milestones are not sent to the network")
+			}
+
+			if deps.NodeConfig.Bool(CfgCoordinatorAdaptiveIntervalEnabled) {
+				Plugin.LogInfof("running Coordinator with adaptive milestone interval between %v and %v", deps.NodeConfig.Duration(CfgCoordinatorAdaptiveIntervalMinInterval), deps.NodeConfig.Duration(CfgCoordinatorAdaptiveIntervalMaxInterval))
+			}
+
 			coo, err := coordinator.New(
 				deps.Storage,
 				deps.SyncManager,
@@ -176,9 +196,15 @@ func provide(c *dig.Container) {
 				coordinator.WithStateFilePath(deps.NodeConfig.String(CfgCoordinatorStateFilePath)),
 				coordinator.WithMilestoneInterval(deps.NodeConfig.Duration(CfgCoordinatorInterval)),
 				coordinator.WithPoWWorkerCount(deps.NodeConfig.Int(CfgCoordinatorPoWWorkerCount)),
-				coordinator.WithQuorum(deps.NodeConfig.Bool(CfgCoordinatorQuorumEnabled), quorumGroups, deps.DeserializationParamters, deps.NodeConfig.Duration(CfgCoordinatorQuorumTimeout)),
+				coordinator.WithQuorum(deps.NodeConfig.Bool(CfgCoordinatorQuorumEnabled), quorumConfig, deps.DeserializationParamters),
+				coordinator.WithAdaptiveInterval(deps.NodeConfig.Bool(CfgCoordinatorAdaptiveIntervalEnabled), &coordinator.AdaptiveIntervalConfig{
+					MinInterval:                deps.NodeConfig.Duration(CfgCoordinatorAdaptiveIntervalMinInterval),
+					MaxInterval:                deps.NodeConfig.Duration(CfgCoordinatorAdaptiveIntervalMaxInterval),
+					TargetUnreferencedMessages: deps.NodeConfig.Int(CfgCoordinatorAdaptiveIntervalTargetUnreferencedMessages),
+				}),
 				coordinator.WithSigningRetryAmount(deps.NodeConfig.Int(CfgCoordinatorSigningRetryAmount)),
 				coordinator.WithSigningRetryTimeout(deps.NodeConfig.Duration(CfgCoordinatorSigningRetryTimeout)),
+				coordinator.WithShadowMode(deps.NodeConfig.Bool(CfgCoordinatorShadowMode)),
 			)
 			if err != nil {
 				return nil, err
@@ -221,10 +247,18 @@ func configure() {
 	// lost if checkpoint is generated at the same time
 	nextMilestoneSignal = make(chan struct{}, 1)
 
-	maxTrackedMessages = deps.NodeConfig.Int(CfgCoordinatorCheckpointsMaxTrackedMessages)
+	maxTrackedMessages.Store(int64(deps.NodeConfig.Int(CfgCoordinatorCheckpointsMaxTrackedMessages)))
+
+	// the tipselection and the quorum can be changed at runtime if the RestAPI is enabled
+	if !Plugin.Node.IsSkipped(restapiv2.Plugin) {
+		setupRoutes(restapiv2.AddPlugin("coordinator/v1"))
+	}
 
-	// set the node as synced at startup, so the coo plugin can select tips
-	deps.Tangle.SetUpdateSyncedAtStartup(true)
+	// set the node as synced at startup, so the coo plugin can select tips.
+	// in shadow mode the node needs to sync with the milestones of the network instead.
+	if !deps.NodeConfig.Bool(CfgCoordinatorShadowMode) {
+		deps.Tangle.SetUpdateSyncedAtStartup(true)
+	}
 
 	configureEvents()
 }
@@ -253,18 +287,61 @@ func handleError(err error) bool {
 
 func run() {
 
+	if remoteSignerProvider != nil {
+		// create a background worker that checks the health of the remote signers
+		if err := Plugin.Daemon().BackgroundWorker("Coordinator[RemoteSignerHealth]", func(ctx context.Context) {
+			healthy := make(map[string]bool)
+
+			checkHealth := func() {
+				for _, status := range remoteSignerProvider.CheckHealth(ctx) {
+					wasHealthy, known := healthy[status.Address]
+					switch {
+					case !status.Healthy && (wasHealthy || !known):
+						Plugin.LogWarnf("remote signer %s is unhealthy: %s", status.Address, status.Error)
+					case status.Healthy && !wasHealthy && known:
+						Plugin.LogInfof("remote signer %s is healthy again", status.Address)
+					}
+					healthy[status.Address] = status.Healthy
+				}
+			}
+
+			checkHealth()
+			ticker := timeutil.NewTicker(checkHealth, deps.NodeConfig.Duration(CfgCoordinatorSigningHealthCheckInterval), ctx)
+			ticker.WaitForGracefulShutdown()
+
+			remoteSignerProvider.Close()
+		}, shutdown.PriorityCoordinator); err != nil {
+			Plugin.LogPanicf("failed to start worker: %s", err)
+		}
+	}
+
 	// create a background worker that signals to issue new milestones
 	if err := Plugin.Daemon().BackgroundWorker("Coordinator[MilestoneTicker]", func(ctx context.Context) {
 
-		ticker := timeutil.NewTicker(func() {
-			// issue next milestone
+		lastTick := time.Now()
+		timer := time.NewTimer(deps.Coordinator.Interval())
+		defer timer.Stop()
+
+		for {
 			select {
-			case nextMilestoneSignal <- struct{}{}:
-			default:
-				// do not block if already another signal is waiting
+			case <-timer.C:
+				// the tracked messages are reset after every milestone,
+				// so they are the messages that arrived since the last milestone.
+				interval := deps.Coordinator.AdaptInterval(deps.Selector.TrackedMessagesCount(), time.Since(lastTick))
+				lastTick = time.Now()
+				timer.Reset(interval)
+
+				// issue next milestone
+				select {
+				case nextMilestoneSignal <- struct{}{}:
+				default:
+					// do not block if already another signal is waiting
+				}
+
+			case <-ctx.Done():
+				return
 			}
-		}, deps.Coordinator.Interval(), ctx)
-		ticker.WaitForGracefulShutdown()
+		}
 	}, shutdown.PriorityCoordinator); err != nil {
 		Plugin.LogPanicf("failed to start worker: %s", err)
 	}
@@ -296,7 +373,7 @@ func run() {
 			select {
 			case <-nextCheckpointSignal:
 				// check the thresholds again, because a new milestone could have been issued in the meantime
-				if trackedMessagesCount := deps.Selector.TrackedMessagesCount(); trackedMessagesCount < maxTrackedMessages {
+				if trackedMessagesCount := deps.Selector.TrackedMessagesCount(); trackedMessagesCount < int(maxTrackedMessages.Load()) {
 					continue
 				}
 
@@ -397,7 +474,9 @@ func run() {
 
 }
 
-func initSigningProvider(signingProviderType string, remoteEndpoint string, keyManager *keymanager.KeyManager, milestonePublicKeyCount int) (coordinator.MilestoneSignerProvider, error) {
+func initSigningProvider(nodeConfig *configuration.Configuration, keyManager *keymanager.KeyManager, milestonePublicKeyCount int) (coordinator.MilestoneSignerProvider, error) {
+
+	signingProviderType := nodeConfig.String(CfgCoordinatorSigningProvider)
 
 	switch signingProviderType {
 	case "local":
@@ -419,17 +498,73 @@ func initSigningProvider(signingProviderType string, remoteEndpoint string, keyM
 		return coordinator.NewInMemoryEd25519MilestoneSignerProvider(privateKeys, keyManager, milestonePublicKeyCount), nil
 
 	case "remote":
-		if remoteEndpoint == "" {
-			return nil, errors.New("no address given for remote signing provider")
+		signerConfigs := []*coordinator.RemoteSignerConfig{}
+		if err := nodeConfig.Unmarshal(CfgCoordinatorSigningRemoteSigners, &signerConfigs); err != nil {
+			return nil, fmt.Errorf("failed to parse remote signers: %s", err)
+		}
+
+		if len(signerConfigs) == 0 {
+			// fall back to the single remote signing provider without TLS
+			remoteEndpoint := nodeConfig.String(CfgCoordinatorSigningRemoteAddress)
+			if remoteEndpoint == "" {
+				return nil, errors.New("no address given for remote signing provider")
+			}
+			signerConfigs = append(signerConfigs, &coordinator.RemoteSignerConfig{Address: remoteEndpoint})
 		}
 
-		return coordinator.NewInsecureRemoteEd25519MilestoneSignerProvider(remoteEndpoint, keyManager, milestonePublicKeyCount), nil
+		provider, err := coordinator.NewRemoteEd25519MilestoneSignerProvider(signerConfigs, nodeConfig.Duration(CfgCoordinatorSigningRemoteTimeout), keyManager, milestonePublicKeyCount)
+		if err != nil {
+			return nil, err
+		}
+		remoteSignerProvider = provider
+
+		return provider, nil
+
+	case "multiparty":
+		coSignerConfigs := []*coordinator.CoSignerConfig{}
+		if err := nodeConfig.Unmarshal(CfgCoordinatorSigningCoSigners, &coSignerConfigs); err != nil {
+			return nil, fmt.Errorf("failed to parse co-signers: %s", err)
+		}
+
+		provider, err := coordinator.NewMultiPartyEd25519MilestoneSignerProvider(coSignerConfigs, nodeConfig.Duration(CfgCoordinatorSigningRemoteTimeout), keyManager, milestonePublicKeyCount)
+		if err != nil {
+			return nil, err
+		}
+		remoteSignerProvider = provider
+
+		return provider, nil
 
 	default:
 		return nil, fmt.Errorf("unknown milestone signing provider: %s", signingProviderType)
 	}
 }
 
+// initQuorumConfig loads the quorum config from the quorum file if it exists, otherwise from the node config.
+func initQuorumConfig(nodeConfig *configuration.Configuration) (*coordinator.QuorumConfig, error) {
+	quorumFilePath := nodeConfig.String(CfgCoordinatorQuorumFilePath)
+
+	if _, err := os.Stat(quorumFilePath); !os.IsNotExist(err) {
+		quorumConfig, err := loadQuorumConfig(quorumFilePath)
+		if err != nil {
+			return nil, err
+		}
+		Plugin.LogInfof("loaded coordinator quorum from %s", quorumFilePath)
+
+		return quorumConfig, nil
+	}
+
+	quorumGroups, err := initQuorumGroups(nodeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &coordinator.QuorumConfig{
+		Groups:    quorumGroups,
+		Threshold: nodeConfig.Int(CfgCoordinatorQuorumThreshold),
+		Timeout:   nodeConfig.Duration(CfgCoordinatorQuorumTimeout),
+	}, nil
+}
+
 func initQuorumGroups(nodeConfig *configuration.Configuration) (map[string][]*coordinator.QuorumClientConfig, error) {
 	// parse quorum groups config
 	quorumGroups := make(map[string][]*coordinator.QuorumClientConfig)
@@ -478,25 +613,47 @@ func sendMessage(msg *storage.Message, msIndex ...milestone.Index) error {
 		}
 	}()
 
-	if err = deps.MessageProcessor.Emit(msg); err != nil {
+	// the waits are canceled if the node is shut down or the queued emission was dropped
+	ctx, cancel := context.WithCancel(Plugin.Daemon().ContextStopped())
+	defer cancel()
+
+	emitDropped := atomic.NewBool(false)
+	onMessageEmitDropped := events.NewClosure(func(messageID hornet.MessageID) {
+		if bytes.Equal(messageID, msg.MessageID()) {
+			emitDropped.Store(true)
+			cancel()
+		}
+	})
+	deps.MessageProcessor.Events.MessageEmitDropped.Attach(onMessageEmitDropped)
+	defer deps.MessageProcessor.Events.MessageEmitDropped.Detach(onMessageEmitDropped)
+
+	if err = deps.MessageProcessor.EmitWithPriority(msg, gossip.EmitPriorityHigh); err != nil {
 		return err
 	}
 
 	// wait until the message is solid
-	if err = utils.WaitForChannelClosed(context.Background(), msgSolidEventChan); err != nil {
-		return err
+	if err = utils.WaitForChannelClosed(ctx, msgSolidEventChan); err != nil {
+		return emitWaitError(err, emitDropped.Load())
 	}
 
 	if len(msIndex) > 0 {
 		// if it was a milestone, also wait until the milestone was confirmed
-		if err = utils.WaitForChannelClosed(context.Background(), milestoneConfirmedEventChan); err != nil {
-			return err
+		if err = utils.WaitForChannelClosed(ctx, milestoneConfirmedEventChan); err != nil {
+			return emitWaitError(err, emitDropped.Load())
 		}
 	}
 
 	return nil
 }
 
+// emitWaitError returns the reason why the wait for an emitted message was canceled.
+func emitWaitError(err error, emitDropped bool) error {
+	if emitDropped {
+		return errors.WithMessage(gossip.ErrEmitQueueClosed, "message was dropped before it was emitted")
+	}
+	return errors.WithMessagef(common.ErrOperationAborted, "waiting for the emitted message failed: %s", err)
+}
+
 // isBelowMaxDepth checks the below max depth criteria for the given message.
 func isBelowMaxDepth(cachedMsgMeta *storage.CachedMetadata) (bool, error) {
 	defer cachedMsgMeta.Release(true)
@@ -541,7 +698,7 @@ func configureEvents() {
 		}
 
 		// add tips to the heaviest branch selector
-		if trackedMessagesCount := deps.Selector.OnNewSolidMessage(cachedMsgMeta.Metadata()); trackedMessagesCount >= maxTrackedMessages {
+		if trackedMessagesCount := deps.Selector.OnNewSolidMessage(cachedMsgMeta.Metadata()); trackedMessagesCount >= int(maxTrackedMessages.Load()) {
 			Plugin.LogDebugf("Coordinator Tipselector: trackedMessagesCount: %d", trackedMessagesCount)
 
 			// issue next checkpoint
@@ -569,12 +726,21 @@ func configureEvents() {
 	})
 
 	onIssuedCheckpoint = events.NewClosure(func(checkpointIndex int, tipIndex int, tipsTotal int, messageID hornet.MessageID) {
+		if deps.NodeConfig.Bool(CfgCoordinatorShadowMode) {
+			Plugin.LogInfof("shadow checkpoint (%d) message created (%d/%d): %v", checkpointIndex+1, tipIndex+1, tipsTotal, messageID.ToHex())
+			return
+		}
 		Plugin.LogInfof("checkpoint (%d) message issued (%d/%d): %v", checkpointIndex+1, tipIndex+1, tipsTotal, messageID.ToHex())
 	})
 
 	onIssuedMilestone = events.NewClosure(func(index milestone.Index, messageID hornet.MessageID) {
 		Plugin.LogInfof("milestone issued (%d): %v", index, messageID.ToHex())
 	})
+
+	onIssuedShadowMilestone = events.NewClosure(func(index milestone.Index, msg *storage.Message) {
+		Plugin.LogInfof("shadow milestone created (%d): %v, parents: %v", index, msg.MessageID().ToHex(), msg.Parents().ToHex())
+		addShadowMilestone(index, msg)
+	})
 }
 
 func attachEvents() {
@@ -582,10 +748,12 @@ func attachEvents() {
 	deps.Tangle.Events.ConfirmedMilestoneIndexChanged.Attach(onConfirmedMilestoneIndexChanged)
 	deps.Coordinator.Events.IssuedCheckpointMessage.Attach(onIssuedCheckpoint)
 	deps.Coordinator.Events.IssuedMilestone.Attach(onIssuedMilestone)
+	deps.Coordinator.Events.IssuedShadowMilestone.Attach(onIssuedShadowMilestone)
 }
 
 func detachEvents() {
 	deps.Tangle.Events.MessageSolid.Detach(onMessageSolid)
 	deps.Tangle.Events.ConfirmedMilestoneIndexChanged.Detach(onConfirmedMilestoneIndexChanged)
 	deps.Coordinator.Events.IssuedMilestone.Detach(onIssuedMilestone)
+	deps.Coordinator.Events.IssuedShadowMilestone.Detach(onIssuedShadowMilestone)
 }
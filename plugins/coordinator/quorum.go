@@ -0,0 +1,82 @@
+package coordinator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/model/coordinator"
+	"github.com/gohornet/hornet/pkg/utils"
+)
+
+var (
+	// serializes the changes of the quorum config via the API.
+	quorumConfigLock sync.Mutex
+)
+
+// quorumFile is the content of the file the quorum changes of the API are stored in.
+type quorumFile struct {
+	Groups    map[string][]*coordinator.QuorumClientConfig `json:"groups"`
+	Threshold int                                          `json:"threshold"`
+	Timeout   string                                       `json:"timeout"`
+}
+
+// loadQuorumConfig loads the quorum config from the given quorum file.
+func loadQuorumConfig(quorumFilePath string) (*coordinator.QuorumConfig, error) {
+	file := &quorumFile{}
+	if err := utils.ReadJSONFromFile(quorumFilePath, file); err != nil {
+		return nil, fmt.Errorf("failed to read quorum file %s: %w", quorumFilePath, err)
+	}
+
+	timeout, err := time.ParseDuration(file.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeout in quorum file %s: %w", quorumFilePath, err)
+	}
+
+	return &coordinator.QuorumConfig{
+		Groups:    file.Groups,
+		Threshold: file.Threshold,
+		Timeout:   timeout,
+	}, nil
+}
+
+// storeQuorumConfig stores the quorum config in the given quorum file.
+// The file contains the credentials of the quorum clients, so it is only readable by the owner.
+func storeQuorumConfig(quorumFilePath string, quorumConfig *coordinator.QuorumConfig) error {
+	return utils.WriteJSONToFile(quorumFilePath, &quorumFile{
+		Groups:    quorumConfig.Groups,
+		Threshold: quorumConfig.Threshold,
+		Timeout:   quorumConfig.Timeout.String(),
+	}, 0600)
+}
+
+// changeQuorumConfig applies the change to a copy of the current quorum config,
+// stores the changed config in the quorum file and activates it.
+func changeQuorumConfig(change func(quorumConfig *coordinator.QuorumConfig) error) (*coordinator.QuorumConfig, error) {
+	quorumConfigLock.Lock()
+	defer quorumConfigLock.Unlock()
+
+	quorumConfig, err := deps.Coordinator.QuorumConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := change(quorumConfig); err != nil {
+		return nil, err
+	}
+
+	if err := quorumConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	// store the config first, so the active quorum never differs from the one used after a restart
+	if err := storeQuorumConfig(deps.NodeConfig.String(CfgCoordinatorQuorumFilePath), quorumConfig); err != nil {
+		return nil, fmt.Errorf("failed to store quorum file: %w", err)
+	}
+
+	if err := deps.Coordinator.SetQuorumConfig(quorumConfig); err != nil {
+		return nil, err
+	}
+
+	return quorumConfig, nil
+}
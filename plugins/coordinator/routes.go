@@ -0,0 +1,318 @@
+package coordinator
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/coordinator"
+	"github.com/gohornet/hornet/pkg/model/mselection"
+	"github.com/gohornet/hornet/pkg/restapi"
+)
+
+const (
+	// RouteCoordinatorTipsel is the route to get or change the parameters of the milestone tipselection.
+	// GET returns the current parameters of the milestone tipselection.
+	// POST changes the given parameters, they are used starting with the next checkpoint or milestone.
+	RouteCoordinatorTipsel = "/tipsel"
+
+	// RouteCoordinatorQuorum is the route to get or change the threshold and timeout of the coordinator quorum.
+	// GET returns the current quorum groups, threshold and timeout.
+	// POST changes the given parameters, they are used starting with the next milestone and stored in the quorum file.
+	RouteCoordinatorQuorum = "/quorum"
+
+	// RouteCoordinatorQuorumGroupClients is the route to manage the clients of a coordinator quorum group.
+	// POST adds a client to the group, the group is created if it doesn't exist.
+	// DELETE removes the client with the baseURL given as query parameter, the group is removed if it is empty afterwards.
+	RouteCoordinatorQuorumGroupClients = "/quorum/groups/:" + ParameterGroupName + "/clients"
+
+	// RouteCoordinatorShadowMilestones is the route to get the latest milestones created in shadow mode.
+	// GET returns the milestones with their parents and the milestone message, the newest first.
+	RouteCoordinatorShadowMilestones = "/shadow/milestones"
+)
+
+const (
+	// ParameterGroupName is used to identify a quorum group by its name.
+	ParameterGroupName = "groupName"
+
+	// QueryParameterBaseURL is used to identify a quorum client by its baseURL.
+	QueryParameterBaseURL = "baseURL"
+)
+
+func setupRoutes(routeGroup *echo.Group) {
+
+	routeGroup.GET(RouteCoordinatorTipsel, func(c echo.Context) error {
+		return restapi.JSONResponse(c, http.StatusOK, getTipsel())
+	})
+
+	routeGroup.POST(RouteCoordinatorTipsel, func(c echo.Context) error {
+		resp, err := changeTipsel(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteCoordinatorQuorum, func(c echo.Context) error {
+		resp, err := getQuorum()
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RouteCoordinatorQuorum, func(c echo.Context) error {
+		resp, err := changeQuorum(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RouteCoordinatorQuorumGroupClients, func(c echo.Context) error {
+		resp, err := addQuorumClient(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusCreated, resp)
+	})
+
+	routeGroup.DELETE(RouteCoordinatorQuorumGroupClients, func(c echo.Context) error {
+		resp, err := removeQuorumClient(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteCoordinatorShadowMilestones, func(c echo.Context) error {
+		resp, err := getShadowMilestones()
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+}
+
+func getTipsel() *tipselResponse {
+	params := deps.Selector.Params()
+
+	return &tipselResponse{
+		MaxTrackedMessages: int(maxTrackedMessages.Load()),
+		MinHeaviestBranchUnreferencedMessagesThreshold: params.MinHeaviestBranchUnreferencedMessagesThreshold,
+		MaxHeaviestBranchTipsPerCheckpoint:             params.MaxHeaviestBranchTipsPerCheckpoint,
+		RandomTipsPerCheckpoint:                        params.RandomTipsPerCheckpoint,
+		HeaviestBranchSelectionTimeout:                 params.HeaviestBranchSelectionTimeout.String(),
+	}
+}
+
+func changeTipsel(c echo.Context) (*tipselResponse, error) {
+
+	request := &tipselRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	params := deps.Selector.Params()
+	if request.MinHeaviestBranchUnreferencedMessagesThreshold != nil {
+		params.MinHeaviestBranchUnreferencedMessagesThreshold = *request.MinHeaviestBranchUnreferencedMessagesThreshold
+	}
+	if request.MaxHeaviestBranchTipsPerCheckpoint != nil {
+		params.MaxHeaviestBranchTipsPerCheckpoint = *request.MaxHeaviestBranchTipsPerCheckpoint
+	}
+	if request.RandomTipsPerCheckpoint != nil {
+		params.RandomTipsPerCheckpoint = *request.RandomTipsPerCheckpoint
+	}
+	if request.HeaviestBranchSelectionTimeout != nil {
+		timeout, err := time.ParseDuration(*request.HeaviestBranchSelectionTimeout)
+		if err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid heaviest branch selection timeout: %s, error: %s", *request.HeaviestBranchSelectionTimeout, err)
+		}
+		params.HeaviestBranchSelectionTimeout = timeout
+	}
+
+	// validate everything before applying anything, so a request is either applied completely or not at all
+	if request.MaxTrackedMessages != nil && *request.MaxTrackedMessages < 1 {
+		return nil, errors.WithMessage(restapi.ErrInvalidParameter, "maxTrackedMessages must be at least 1")
+	}
+	if err := params.Validate(); err != nil {
+		return nil, errors.WithMessage(restapi.ErrInvalidParameter, err.Error())
+	}
+
+	if err := deps.Selector.SetParams(params); err != nil {
+		if errors.Is(err, mselection.ErrInvalidParams) {
+			return nil, errors.WithMessage(restapi.ErrInvalidParameter, err.Error())
+		}
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "changing the tipselection parameters failed: %s", err)
+	}
+	if request.MaxTrackedMessages != nil {
+		maxTrackedMessages.Store(int64(*request.MaxTrackedMessages))
+	}
+
+	resp := getTipsel()
+	Plugin.LogInfof("milestone tipselection parameters changed: maxTrackedMessages: %d, minHeaviestBranchUnreferencedMessagesThreshold: %d, maxHeaviestBranchTipsPerCheckpoint: %d, randomTipsPerCheckpoint: %d, heaviestBranchSelectionTimeout: %s",
+		resp.MaxTrackedMessages, resp.MinHeaviestBranchUnreferencedMessagesThreshold, resp.MaxHeaviestBranchTipsPerCheckpoint, resp.RandomTipsPerCheckpoint, resp.HeaviestBranchSelectionTimeout)
+
+	return resp, nil
+}
+
+func newQuorumResponse(quorumConfig *coordinator.QuorumConfig) *quorumResponse {
+	groups := make(map[string][]*quorumClient, len(quorumConfig.Groups))
+	for groupName, groupClients := range quorumConfig.Groups {
+		groups[groupName] = make([]*quorumClient, len(groupClients))
+		for i, client := range groupClients {
+			groups[groupName][i] = &quorumClient{
+				Alias:   client.Alias,
+				BaseURL: client.BaseURL,
+			}
+		}
+	}
+
+	return &quorumResponse{
+		Groups:    groups,
+		Threshold: quorumConfig.Threshold,
+		Timeout:   quorumConfig.Timeout.String(),
+	}
+}
+
+// quorumError maps the errors of a quorum change to the errors of the REST API.
+func quorumError(err error) error {
+	switch {
+	case errors.Is(err, restapi.ErrInvalidParameter):
+		return err
+	case errors.Is(err, coordinator.ErrQuorumDisabled):
+		return errors.WithMessage(echo.ErrServiceUnavailable, err.Error())
+	case errors.Is(err, coordinator.ErrInvalidQuorumConfig):
+		return errors.WithMessage(restapi.ErrInvalidParameter, err.Error())
+	default:
+		return errors.WithMessagef(echo.ErrInternalServerError, "changing the coordinator quorum failed: %s", err)
+	}
+}
+
+func getQuorum() (*quorumResponse, error) {
+	quorumConfig, err := deps.Coordinator.QuorumConfig()
+	if err != nil {
+		return nil, quorumError(err)
+	}
+
+	return newQuorumResponse(quorumConfig), nil
+}
+
+func changeQuorum(c echo.Context) (*quorumResponse, error) {
+
+	request := &quorumRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	quorumConfig, err := changeQuorumConfig(func(quorumConfig *coordinator.QuorumConfig) error {
+		if request.Threshold != nil {
+			quorumConfig.Threshold = *request.Threshold
+		}
+		if request.Timeout != nil {
+			timeout, err := time.ParseDuration(*request.Timeout)
+			if err != nil {
+				return errors.WithMessagef(restapi.ErrInvalidParameter, "invalid timeout: %s, error: %s", *request.Timeout, err)
+			}
+			quorumConfig.Timeout = timeout
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, quorumError(err)
+	}
+
+	Plugin.LogInfof("coordinator quorum changed: threshold: %d, timeout: %s", quorumConfig.Threshold, quorumConfig.Timeout)
+
+	return newQuorumResponse(quorumConfig), nil
+}
+
+func addQuorumClient(c echo.Context) (*quorumResponse, error) {
+
+	groupName := c.Param(ParameterGroupName)
+
+	request := &addQuorumClientRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	if request.BaseURL == "" {
+		return nil, errors.WithMessage(restapi.ErrInvalidParameter, "no baseURL given")
+	}
+
+	quorumConfig, err := changeQuorumConfig(func(quorumConfig *coordinator.QuorumConfig) error {
+		quorumConfig.Groups[groupName] = append(quorumConfig.Groups[groupName], &coordinator.QuorumClientConfig{
+			Alias:    request.Alias,
+			BaseURL:  request.BaseURL,
+			UserName: request.UserName,
+			Password: request.Password,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, quorumError(err)
+	}
+
+	Plugin.LogInfof("coordinator quorum client added, group: %s, baseURL: %s", groupName, request.BaseURL)
+
+	return newQuorumResponse(quorumConfig), nil
+}
+
+func removeQuorumClient(c echo.Context) (*quorumResponse, error) {
+
+	groupName := c.Param(ParameterGroupName)
+
+	baseURL := c.QueryParam(QueryParameterBaseURL)
+	if baseURL == "" {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "no %s given", QueryParameterBaseURL)
+	}
+
+	quorumConfig, err := changeQuorumConfig(func(quorumConfig *coordinator.QuorumConfig) error {
+		groupClients := make([]*coordinator.QuorumClientConfig, 0, len(quorumConfig.Groups[groupName]))
+		for _, client := range quorumConfig.Groups[groupName] {
+			if client.BaseURL != baseURL {
+				groupClients = append(groupClients, client)
+			}
+		}
+
+		if len(groupClients) == len(quorumConfig.Groups[groupName]) {
+			return errors.WithMessagef(echo.ErrNotFound, "client not found in group %s: %s", groupName, baseURL)
+		}
+
+		if len(groupClients) == 0 {
+			delete(quorumConfig.Groups, groupName)
+			return nil
+		}
+		quorumConfig.Groups[groupName] = groupClients
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, echo.ErrNotFound) {
+			return nil, err
+		}
+		return nil, quorumError(err)
+	}
+
+	Plugin.LogInfof("coordinator quorum client removed, group: %s, baseURL: %s", groupName, baseURL)
+
+	return newQuorumResponse(quorumConfig), nil
+}
+
+func getShadowMilestones() (*shadowMilestonesResponse, error) {
+	if !deps.NodeConfig.Bool(CfgCoordinatorShadowMode) {
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "coordinator is not running in shadow mode")
+	}
+
+	return &shadowMilestonesResponse{
+		Milestones: latestShadowMilestones(),
+	}, nil
+}
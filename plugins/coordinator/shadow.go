@@ -0,0 +1,56 @@
+package coordinator
+
+import (
+	"encoding/hex"
+	"sync"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+)
+
+const (
+	// the amount of milestones created in shadow mode that are kept for the API.
+	shadowMilestonesHistorySize = 100
+)
+
+var (
+	shadowMilestonesLock sync.RWMutex
+	// the latest milestones created in shadow mode, the oldest first.
+	shadowMilestones []*shadowMilestone
+)
+
+// addShadowMilestone adds a milestone created in shadow mode to the history.
+func addShadowMilestone(index milestone.Index, msg *storage.Message) {
+	ms := &shadowMilestone{
+		Index:     uint32(index),
+		MessageID: msg.MessageID().ToHex(),
+		Parents:   msg.Parents().ToHex(),
+		Message:   msg.Message(),
+	}
+
+	if milestonePayload := msg.Milestone(); milestonePayload != nil {
+		ms.Timestamp = milestonePayload.Timestamp
+		ms.InclusionMerkleProof = hex.EncodeToString(milestonePayload.InclusionMerkleProof[:])
+	}
+
+	shadowMilestonesLock.Lock()
+	defer shadowMilestonesLock.Unlock()
+
+	shadowMilestones = append(shadowMilestones, ms)
+	if len(shadowMilestones) > shadowMilestonesHistorySize {
+		shadowMilestones = shadowMilestones[len(shadowMilestones)-shadowMilestonesHistorySize:]
+	}
+}
+
+// latestShadowMilestones returns the latest milestones created in shadow mode, the newest first.
+func latestShadowMilestones() []*shadowMilestone {
+	shadowMilestonesLock.RLock()
+	defer shadowMilestonesLock.RUnlock()
+
+	result := make([]*shadowMilestone, 0, len(shadowMilestones))
+	for i := len(shadowMilestones) - 1; i >= 0; i-- {
+		result = append(result, shadowMilestones[i])
+	}
+
+	return result
+}
@@ -0,0 +1,96 @@
+package coordinator
+
+import (
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// tipselResponse defines the response of a GET and POST coordinator tipsel REST API call.
+type tipselResponse struct {
+	// The maximum amount of known messages for milestone tipselection before a checkpoint is issued.
+	MaxTrackedMessages int `json:"maxTrackedMessages"`
+	// The minimum threshold of unreferenced messages in the heaviest branch.
+	MinHeaviestBranchUnreferencedMessagesThreshold int `json:"minHeaviestBranchUnreferencedMessagesThreshold"`
+	// The maximum amount of checkpoint messages with heaviest branch tips.
+	MaxHeaviestBranchTipsPerCheckpoint int `json:"maxHeaviestBranchTipsPerCheckpoint"`
+	// The amount of checkpoint messages with random tips.
+	RandomTipsPerCheckpoint int `json:"randomTipsPerCheckpoint"`
+	// The maximum duration to select the heaviest branch tips.
+	HeaviestBranchSelectionTimeout string `json:"heaviestBranchSelectionTimeout"`
+}
+
+// tipselRequest defines the request of a POST coordinator tipsel REST API call.
+// Fields which are not set keep their current value.
+type tipselRequest struct {
+	// The maximum amount of known messages for milestone tipselection before a checkpoint is issued.
+	MaxTrackedMessages *int `json:"maxTrackedMessages,omitempty"`
+	// The minimum threshold of unreferenced messages in the heaviest branch.
+	MinHeaviestBranchUnreferencedMessagesThreshold *int `json:"minHeaviestBranchUnreferencedMessagesThreshold,omitempty"`
+	// The maximum amount of checkpoint messages with heaviest branch tips.
+	MaxHeaviestBranchTipsPerCheckpoint *int `json:"maxHeaviestBranchTipsPerCheckpoint,omitempty"`
+	// The amount of checkpoint messages with random tips.
+	RandomTipsPerCheckpoint *int `json:"randomTipsPerCheckpoint,omitempty"`
+	// The maximum duration to select the heaviest branch tips (e.g. "100ms").
+	HeaviestBranchSelectionTimeout *string `json:"heaviestBranchSelectionTimeout,omitempty"`
+}
+
+// quorumClient defines a client of a quorum group in the coordinator quorum REST API calls.
+type quorumClient struct {
+	// The optional alias of the client.
+	Alias string `json:"alias,omitempty"`
+	// The baseURL of the client.
+	BaseURL string `json:"baseURL"`
+}
+
+// quorumResponse defines the response of the coordinator quorum REST API calls.
+// The credentials of the clients are not part of the response.
+type quorumResponse struct {
+	// The quorum groups with their clients.
+	Groups map[string][]*quorumClient `json:"groups"`
+	// The minimum amount of clients in every group that need to answer.
+	Threshold int `json:"threshold"`
+	// The timeout until a client in the quorum must have answered.
+	Timeout string `json:"timeout"`
+}
+
+// quorumRequest defines the request of a POST coordinator quorum REST API call.
+// Fields which are not set keep their current value.
+type quorumRequest struct {
+	// The minimum amount of clients in every group that need to answer.
+	Threshold *int `json:"threshold,omitempty"`
+	// The timeout until a client in the quorum must have answered (e.g. "2s").
+	Timeout *string `json:"timeout,omitempty"`
+}
+
+// addQuorumClientRequest defines the request of a POST coordinator quorum group clients REST API call.
+type addQuorumClientRequest struct {
+	// The optional alias of the client.
+	Alias string `json:"alias,omitempty"`
+	// The baseURL of the client.
+	BaseURL string `json:"baseURL"`
+	// The optional username for basic auth.
+	UserName string `json:"userName,omitempty"`
+	// The optional password for basic auth.
+	Password string `json:"password,omitempty"`
+}
+
+// shadowMilestone defines a milestone created in shadow mode in the coordinator shadow milestones REST API calls.
+type shadowMilestone struct {
+	// The index of the milestone.
+	Index uint32 `json:"index"`
+	// The message ID of the milestone.
+	MessageID string `json:"messageId"`
+	// The timestamp of the milestone.
+	Timestamp uint64 `json:"timestamp"`
+	// The parents of the milestone.
+	Parents []string `json:"parents"`
+	// The merkle tree hash of the messages referenced by the milestone.
+	InclusionMerkleProof string `json:"inclusionMerkleProof"`
+	// The milestone message.
+	Message *iotago.Message `json:"message"`
+}
+
+// shadowMilestonesResponse defines the response of a GET coordinator shadow milestones REST API call.
+type shadowMilestonesResponse struct {
+	// The latest milestones created in shadow mode, the newest first.
+	Milestones []*shadowMilestone `json:"milestones"`
+}
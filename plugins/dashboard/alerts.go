@@ -0,0 +1,38 @@
+package dashboard
+
+import (
+	"context"
+
+	"github.com/gohornet/hornet/pkg/alerting"
+	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/iotaledger/hive.go/events"
+)
+
+// activeAlerts returns the alerts that fired and are not resolved yet.
+func activeAlerts() []*alerting.Alert {
+	alerts := deps.AlertManager.ActiveAlerts()
+	if alerts == nil {
+		return []*alerting.Alert{}
+	}
+	return alerts
+}
+
+func runAlertsWorker() {
+
+	// the dashboard always shows all active alerts, so they are sent again on every change
+	onAlertsChanged := events.NewClosure(func(_ *alerting.Alert) {
+		hub.BroadcastMsg(&Msg{Type: MsgTypeAlerts, Data: activeAlerts()})
+	})
+
+	if err := Plugin.Daemon().BackgroundWorker("Dashboard[AlertsUpdater]", func(ctx context.Context) {
+		deps.AlertManager.Events.AlertFired.Attach(onAlertsChanged)
+		deps.AlertManager.Events.AlertResolved.Attach(onAlertsChanged)
+		<-ctx.Done()
+		Plugin.LogInfo("Stopping Dashboard[AlertsUpdater] ...")
+		deps.AlertManager.Events.AlertFired.Detach(onAlertsChanged)
+		deps.AlertManager.Events.AlertResolved.Detach(onAlertsChanged)
+		Plugin.LogInfo("Stopping Dashboard[AlertsUpdater] ... done")
+	}, shutdown.PriorityDashboard); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+}
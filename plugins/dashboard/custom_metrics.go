@@ -0,0 +1,48 @@
+package dashboard
+
+import (
+	"context"
+
+	"github.com/gohornet/hornet/pkg/metrics"
+	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/iotaledger/hive.go/events"
+)
+
+// CustomMetrics holds the series other plugins registered and their points.
+type CustomMetrics struct {
+	// The series that were registered, only set if they changed.
+	Series []*metrics.CustomMetricSeries `json:"series,omitempty"`
+	// The recorded points, the oldest point of every series first.
+	Points []*metrics.CustomMetricPoint `json:"points,omitempty"`
+}
+
+// currentCustomMetrics returns all series and their kept points.
+func currentCustomMetrics() *CustomMetrics {
+	return &CustomMetrics{
+		Series: deps.CustomMetrics.Series(),
+		Points: deps.CustomMetrics.Points(),
+	}
+}
+
+func runCustomMetricsWorker() {
+
+	onSeriesRegistered := events.NewClosure(func(series *metrics.CustomMetricSeries) {
+		hub.BroadcastMsg(&Msg{Type: MsgTypeCustomMetrics, Data: &CustomMetrics{Series: []*metrics.CustomMetricSeries{series}}})
+	})
+
+	onPointRecorded := events.NewClosure(func(point *metrics.CustomMetricPoint) {
+		hub.BroadcastMsg(&Msg{Type: MsgTypeCustomMetrics, Data: &CustomMetrics{Points: []*metrics.CustomMetricPoint{point}}})
+	})
+
+	if err := Plugin.Daemon().BackgroundWorker("Dashboard[CustomMetricsUpdater]", func(ctx context.Context) {
+		deps.CustomMetrics.Events.SeriesRegistered.Attach(onSeriesRegistered)
+		deps.CustomMetrics.Events.PointRecorded.Attach(onPointRecorded)
+		<-ctx.Done()
+		Plugin.LogInfo("Stopping Dashboard[CustomMetricsUpdater] ...")
+		deps.CustomMetrics.Events.SeriesRegistered.Detach(onSeriesRegistered)
+		deps.CustomMetrics.Events.PointRecorded.Detach(onPointRecorded)
+		Plugin.LogInfo("Stopping Dashboard[CustomMetricsUpdater] ... done")
+	}, shutdown.PriorityDashboard); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+}
@@ -0,0 +1,155 @@
+package dashboard
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/indexer"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// QueryParameterTag is used to search for a tag, either as hex string with a "0x" prefix or as text.
+	QueryParameterTag = "tag"
+	// QueryParameterPageSize is used to define the page size of the search results.
+	QueryParameterPageSize = "pageSize"
+	// QueryParameterCursor is used to continue a search at the cursor of the previous page.
+	QueryParameterCursor = "cursor"
+
+	// the prefix of a tag that is searched as hex string.
+	tagHexPrefix = "0x"
+)
+
+// ExplorerTagSearchResult is a message found by a tag search.
+type ExplorerTagSearchResult struct {
+	// The hex encoded ID of the message.
+	MessageID string `json:"messageId"`
+	// The hex encoded ID of the output with the tag.
+	OutputID string `json:"outputId"`
+	// The milestone index the output was booked at.
+	MilestoneIndexBooked milestone.Index `json:"milestoneIndexBooked"`
+	// The timestamp of the milestone the output was booked at.
+	MilestoneTimestampBooked uint32 `json:"milestoneTimestampBooked"`
+}
+
+// ExplorerTagSearchResponse defines the response of a tag search of the explorer.
+type ExplorerTagSearchResponse struct {
+	// The hex encoded tag that was searched for.
+	Tag string `json:"tag"`
+	// The found messages, the most recent first.
+	Items []*ExplorerTagSearchResult `json:"items"`
+	// The cursor of the next page, if there are more results.
+	Cursor *string `json:"cursor,omitempty"`
+}
+
+func parseTagQueryParam(c echo.Context) ([]byte, error) {
+	tagParam := c.QueryParam(QueryParameterTag)
+	if tagParam == "" {
+		return nil, errors.WithMessagef(ErrInvalidParameter, "parameter %s missing", QueryParameterTag)
+	}
+
+	return parseTag(tagParam)
+}
+
+// parseTag parses a tag, either given as hex string with a "0x" prefix or as text.
+func parseTag(tagParam string) ([]byte, error) {
+	tag := []byte(tagParam)
+	if strings.HasPrefix(tagParam, tagHexPrefix) {
+		var err error
+		tag, err = hex.DecodeString(strings.TrimPrefix(tagParam, tagHexPrefix))
+		if err != nil {
+			return nil, errors.WithMessagef(ErrInvalidParameter, "invalid %s: %s, error: %s", QueryParameterTag, tagParam, err)
+		}
+	}
+
+	if len(tag) == 0 || len(tag) > iotago.MaxTagLength {
+		return nil, errors.WithMessagef(ErrInvalidParameter, "invalid %s length: %d, max. %d bytes", QueryParameterTag, len(tag), iotago.MaxTagLength)
+	}
+
+	return tag, nil
+}
+
+func searchPageSize(c echo.Context) (int, error) {
+	pageSize := deps.RestAPILimitsMaxResults
+
+	if pageSizeParam := c.QueryParam(QueryParameterPageSize); pageSizeParam != "" {
+		size, err := strconv.Atoi(pageSizeParam)
+		if err != nil || size <= 0 {
+			return 0, errors.WithMessagef(ErrInvalidParameter, "invalid %s: %s", QueryParameterPageSize, pageSizeParam)
+		}
+		if size < pageSize {
+			pageSize = size
+		}
+	}
+
+	return pageSize, nil
+}
+
+// explorerSearchTagRoute resolves a tag to the messages that created outputs with this tag,
+// using the outputs of the indexer.
+func explorerSearchTagRoute(c echo.Context) error {
+	if deps.Indexer == nil {
+		return errors.WithMessage(ErrNotFound, "the tag search needs the indexer plugin to be enabled")
+	}
+
+	tag, err := parseTagQueryParam(c)
+	if err != nil {
+		return err
+	}
+
+	pageSize, err := searchPageSize(c)
+	if err != nil {
+		return err
+	}
+
+	filters := []indexer.ExtendedOutputFilterOption{
+		indexer.ExtendedOutputTag(tag),
+		indexer.ExtendedOutputSortByCreatedAt(indexer.SortDescending),
+		indexer.ExtendedOutputPageSize(pageSize),
+	}
+
+	if cursor := c.QueryParam(QueryParameterCursor); cursor != "" {
+		if len(cursor) != indexer.CursorLength {
+			return errors.WithMessagef(ErrInvalidParameter, "invalid %s: %s", QueryParameterCursor, cursor)
+		}
+		filters = append(filters, indexer.ExtendedOutputCursor(cursor))
+	}
+
+	result := deps.Indexer.ExtendedOutputsWithFilters(filters...)
+	if result.Error != nil {
+		if errors.Is(result.Error, indexer.ErrCursorExpired) {
+			return errors.WithMessagef(echo.NewHTTPError(http.StatusGone), "the search needs to be started again: %s", result.Error)
+		}
+		return errors.WithMessagef(ErrInternalError, "searching tag failed: %s", result.Error)
+	}
+
+	items := make([]*ExplorerTagSearchResult, 0, len(result.OutputIDs))
+	for _, outputID := range result.OutputIDs {
+		outputID := outputID
+
+		output, err := deps.UTXOManager.ReadOutputByOutputID(&outputID)
+		if err != nil {
+			// the output was pruned from the ledger since it was indexed
+			continue
+		}
+
+		items = append(items, &ExplorerTagSearchResult{
+			MessageID:                output.MessageID().ToHex(),
+			OutputID:                 outputID.ToHex(),
+			MilestoneIndexBooked:     output.MilestoneIndex(),
+			MilestoneTimestampBooked: output.MilestoneTimestamp(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, &ExplorerTagSearchResponse{
+		Tag:    hex.EncodeToString(tag),
+		Items:  items,
+		Cursor: result.Cursor,
+	})
+}
@@ -0,0 +1,119 @@
+package dashboard
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/metrics"
+	"github.com/gohornet/hornet/pkg/p2p"
+	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/gohornet/hornet/pkg/tangle"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/timeutil"
+)
+
+var (
+	// the metrics history of the dashboard charts, nil if disabled.
+	metricsHistory *metrics.MetricsHistory
+)
+
+// MetricsHistoryResponse defines the response of a GET metrics history REST API call.
+type MetricsHistoryResponse struct {
+	// The interval of the samples in seconds.
+	Interval int64 `json:"interval"`
+	// The samples of the history, the oldest sample first.
+	Samples []*metrics.HistorySample `json:"samples"`
+}
+
+func configureMetricsHistory() {
+	retention := deps.NodeConfig.Duration(CfgDashboardMetricsHistoryRetention)
+	if retention == 0 {
+		return
+	}
+
+	var err error
+	metricsHistory, err = metrics.NewMetricsHistory(
+		deps.TangleDatabase.KVStore().WithRealm([]byte{common.StorePrefixMetricsHistory}),
+		retention,
+		deps.NodeConfig.Duration(CfgDashboardMetricsHistoryInterval),
+	)
+	if err != nil {
+		Plugin.LogPanicf("metrics history initialization failed: %s", err)
+	}
+}
+
+func knownPeersCount() int {
+	var count int
+	deps.PeeringManager.ForEach(func(_ *p2p.Peer) bool {
+		count++
+		return true
+	}, p2p.PeerRelationKnown)
+	return count
+}
+
+func recordMetricsHistorySample() {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	sample, err := metricsHistory.Record(time.Now(), m.Sys, m.HeapInuse, deps.PeeringManager.ConnectedCount(), knownPeersCount())
+	if err != nil {
+		Plugin.LogWarnf("recording metrics history sample failed: %s", err)
+		return
+	}
+
+	hub.BroadcastMsg(&Msg{Type: MsgTypeMetricsHistory, Data: []*metrics.HistorySample{sample}})
+}
+
+func runMetricsHistoryCollector() {
+	if metricsHistory == nil {
+		return
+	}
+
+	onMPSMetricsUpdated := events.NewClosure(func(mpsMetrics *tangle.MPSMetrics) {
+		metricsHistory.AddMPSMetric(mpsMetrics.Incoming, mpsMetrics.New, mpsMetrics.Outgoing)
+	})
+
+	onNewConfirmedMilestoneMetric := events.NewClosure(func(metric *tangle.ConfirmedMilestoneMetric) {
+		metricsHistory.AddConfirmedMilestoneMetric(metric.RMPS, metric.ReferencedRate)
+	})
+
+	if err := Plugin.Daemon().BackgroundWorker("Dashboard[MetricsHistory]", func(ctx context.Context) {
+		deps.Tangle.Events.MPSMetricsUpdated.Attach(onMPSMetricsUpdated)
+		defer deps.Tangle.Events.MPSMetricsUpdated.Detach(onMPSMetricsUpdated)
+
+		deps.Tangle.Events.NewConfirmedMilestoneMetric.Attach(onNewConfirmedMilestoneMetric)
+		defer deps.Tangle.Events.NewConfirmedMilestoneMetric.Detach(onNewConfirmedMilestoneMetric)
+
+		ticker := timeutil.NewTicker(recordMetricsHistorySample, deps.NodeConfig.Duration(CfgDashboardMetricsHistoryInterval), ctx)
+		ticker.WaitForGracefulShutdown()
+	}, shutdown.PriorityDashboard); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+}
+
+func metricsHistoryRoute(c echo.Context) error {
+	if metricsHistory == nil {
+		return errors.WithMessage(ErrNotFound, "metrics history is disabled")
+	}
+
+	var since time.Time
+	if sinceParam := c.QueryParam("since"); sinceParam != "" {
+		sinceUnix, err := strconv.ParseInt(sinceParam, 10, 64)
+		if err != nil {
+			return errors.WithMessagef(ErrInvalidParameter, "invalid since parameter: %s, error: %s", sinceParam, err)
+		}
+		since = time.Unix(sinceUnix, 0)
+	}
+
+	return c.JSON(http.StatusOK, &MetricsHistoryResponse{
+		Interval: int64(deps.NodeConfig.Duration(CfgDashboardMetricsHistoryInterval).Seconds()),
+		Samples:  metricsHistory.Samples(since),
+	})
+}
@@ -24,6 +24,14 @@ const (
 	CfgDashboardAuthPasswordHash = "dashboard.auth.passwordHash"
 	// the auth salt used for hashing the password
 	CfgDashboardAuthPasswordSalt = "dashboard.auth.passwordSalt"
+	// whether to serve the public read-only dashboard
+	CfgDashboardPublicEnabled = "dashboard.public.enabled"
+	// the bind address on which the public read-only dashboard can be accessed from
+	CfgDashboardPublicBindAddress = "dashboard.public.bindAddress"
+	// how long the metrics history of the dashboard charts is kept (0 to disable)
+	CfgDashboardMetricsHistoryRetention = "dashboard.metricsHistory.retention"
+	// the interval in which the metrics are aggregated to a sample of the metrics history
+	CfgDashboardMetricsHistoryInterval = "dashboard.metricsHistory.interval"
 
 	maxDashboardAuthUsernameSize = 25
 )
@@ -39,6 +47,10 @@ var params = &node.PluginParams{
 			fs.String(CfgDashboardAuthUsername, "admin", fmt.Sprintf("the auth username (max %d chars)", maxDashboardAuthUsernameSize))
 			fs.String(CfgDashboardAuthPasswordHash, "0000000000000000000000000000000000000000000000000000000000000000", "the auth password+salt as a scrypt hash")
 			fs.String(CfgDashboardAuthPasswordSalt, "0000000000000000000000000000000000000000000000000000000000000000", "the auth salt used for hashing the password")
+			fs.Bool(CfgDashboardPublicEnabled, false, "whether to serve the public read-only dashboard")
+			fs.String(CfgDashboardPublicBindAddress, "localhost:8082", "the bind address on which the public read-only dashboard can be accessed from")
+			fs.Duration(CfgDashboardMetricsHistoryRetention, 24*time.Hour, "how long the metrics history of the dashboard charts is kept (0 to disable)")
+			fs.Duration(CfgDashboardMetricsHistoryInterval, 1*time.Minute, "the interval in which the metrics are aggregated to a sample of the metrics history")
 			return fs
 		}(),
 	},
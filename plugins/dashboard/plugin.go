@@ -15,15 +15,18 @@ import (
 	"github.com/libp2p/go-libp2p-core/host"
 	"go.uber.org/dig"
 
+	"github.com/gohornet/hornet/pkg/alerting"
 	"github.com/gohornet/hornet/pkg/app"
 	"github.com/gohornet/hornet/pkg/basicauth"
 	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/indexer"
 	"github.com/gohornet/hornet/pkg/jwt"
 	"github.com/gohornet/hornet/pkg/metrics"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/model/syncmanager"
+	"github.com/gohornet/hornet/pkg/model/utxo"
 	"github.com/gohornet/hornet/pkg/node"
 	"github.com/gohornet/hornet/pkg/p2p"
 	"github.com/gohornet/hornet/pkg/protocol/gossip"
@@ -84,6 +87,7 @@ type dependencies struct {
 	SyncManager              *syncmanager.SyncManager
 	Tangle                   *tangle.Tangle
 	ServerMetrics            *metrics.ServerMetrics
+	CustomMetrics            *metrics.CustomMetrics
 	RequestQueue             gossip.RequestQueue
 	PeeringManager           *p2p.Manager
 	MessageProcessor         *gossip.MessageProcessor
@@ -94,6 +98,10 @@ type dependencies struct {
 	Host                     host.Host
 	NodePrivateKey           crypto.PrivKey          `name:"nodePrivateKey"`
 	DashboardAllowedAPIRoute restapipkg.AllowedRoute `name:"dashboardAllowedAPIRoute" optional:"true"`
+	RestAPILimitsMaxResults  int                     `name:"restAPILimitsMaxResults"`
+	UTXOManager              *utxo.Manager
+	Indexer                  *indexer.Indexer  `optional:"true"`
+	AlertManager             *alerting.Manager `optional:"true"`
 }
 
 func initConfigPars(c *dig.Container) {
@@ -166,6 +174,8 @@ func configure() {
 	if err != nil {
 		Plugin.LogPanicf("JWT auth initialization failed: %w", err)
 	}
+
+	configureMetricsHistory()
 }
 
 func run() {
@@ -185,6 +195,10 @@ func run() {
 		}
 	}()
 
+	if deps.NodeConfig.Bool(CfgDashboardPublicEnabled) {
+		runPublicDashboard()
+	}
+
 	onMPSMetricsUpdated := events.NewClosure(func(mpsMetrics *tangle.MPSMetrics) {
 		hub.BroadcastMsg(&Msg{Type: MsgTypeMPSMetric, Data: mpsMetrics})
 		hub.BroadcastMsg(&Msg{Type: MsgTypePublicNodeStatus, Data: currentPublicNodeStatus()})
@@ -240,6 +254,34 @@ func run() {
 	runDatabaseSizeCollector()
 	// run the spammer feed
 	runSpammerMetricWorker()
+	// run the metrics history collector
+	runMetricsHistoryCollector()
+
+	if deps.AlertManager != nil {
+		// run the alerts feed
+		runAlertsWorker()
+	}
+
+	// run the custom metrics feed
+	runCustomMetricsWorker()
+}
+
+func runPublicDashboard() {
+
+	e := echo.New()
+	e.HideBanner = true
+	e.Use(middleware.Recover())
+
+	setupPublicRoutes(e)
+	bindAddr := deps.NodeConfig.String(CfgDashboardPublicBindAddress)
+
+	go func() {
+		Plugin.LogInfof("You can now access the public read-only dashboard using: http://%s", bindAddr)
+
+		if err := e.Start(bindAddr); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			Plugin.LogWarnf("Stopped public dashboard server due to an error (%s)", err)
+		}
+	}()
 }
 
 func getMilestoneMessageID(index milestone.Index) hornet.MessageID {
@@ -341,7 +383,9 @@ type CachesMetric struct {
 
 // Cache represents metrics about a cache.
 type Cache struct {
-	Size int `json:"size"`
+	Size   int    `json:"size"`
+	Hits   uint32 `json:"hits,omitempty"`
+	Misses uint32 `json:"misses,omitempty"`
 }
 
 func peerMetrics() []*restapiv2.PeerResponse {
@@ -399,12 +443,15 @@ func currentNodeStatus() *NodeStatus {
 	status.RequestQueueAvgLatency = deps.RequestQueue.AvgLatency()
 
 	// cache metrics
+	requestQueueStats := deps.RequestQueue.Stats()
 	status.Caches = &CachesMetric{
 		Children: Cache{
 			Size: deps.Storage.ChildrenStorageSize(),
 		},
 		RequestQueue: Cache{
-			Size: queued + pending,
+			Size:   queued + pending,
+			Hits:   requestQueueStats.Hits,
+			Misses: requestQueueStats.Misses,
 		},
 		Milestones: Cache{
 			Size: deps.Storage.MilestoneStorageSize(),
@@ -413,7 +460,9 @@ func currentNodeStatus() *NodeStatus {
 			Size: deps.Storage.MessageStorageSize(),
 		},
 		IncomingMessageWorkUnits: Cache{
-			Size: deps.MessageProcessor.WorkUnitsSize(),
+			Size:   deps.MessageProcessor.WorkUnitsSize(),
+			Hits:   deps.ServerMetrics.IncomingMessagesFilterHits.Load(),
+			Misses: deps.ServerMetrics.IncomingMessagesFilterMisses.Load(),
 		},
 	}
 
@@ -20,6 +20,8 @@ import (
 const (
 	WebsocketCmdRegister   = 0
 	WebsocketCmdUnregister = 1
+	// WebsocketCmdRegisterWithFilter registers a topic with a JSON encoded TopicFilterRequest after the topic byte.
+	WebsocketCmdRegisterWithFilter = 2
 )
 
 var (
@@ -123,20 +125,20 @@ func apiMiddlewares() []echo.MiddlewareFunc {
 		return true
 	}
 
-	// Only allow JWT created for the dashboard
-	jwtAuthAllow := func(_ echo.Context, subject string, claims *jwt.AuthClaims) bool {
-		if claims.Dashboard {
-			return claims.VerifySubject(subject)
-		}
-		return false
-	}
-
 	return []echo.MiddlewareFunc{
 		jwtAuth.Middleware(jwtAuthSkipper, jwtAuthAllow),
 		middleware.ProxyWithConfig(config),
 	}
 }
 
+// jwtAuthAllow only allows JWT created for the dashboard.
+func jwtAuthAllow(_ echo.Context, subject string, claims *jwt.AuthClaims) bool {
+	if claims.Dashboard {
+		return claims.VerifySubject(subject)
+	}
+	return false
+}
+
 func calculateMimeType(e echo.Context) string {
 	url := e.Request().URL.String()
 
@@ -202,6 +204,21 @@ func authRoute(c echo.Context) error {
 	})
 }
 
+// setupPublicRoutes sets up the routes of the public read-only dashboard.
+// It only serves the frontend and the websocket with the public topics, without the API, the auth and any controls.
+func setupPublicRoutes(e *echo.Echo) {
+
+	e.Pre(enforceMaxOneDotPerURL)
+
+	mw := appBoxMiddleware()
+	if deps.NodeConfig.Bool(CfgDashboardDevMode) {
+		mw = devModeReverseProxyMiddleware()
+	}
+	e.Group("/*").Use(mw)
+
+	e.GET("/ws", publicWebsocketRoute)
+}
+
 func setupRoutes(e *echo.Echo) {
 
 	e.Pre(enforceMaxOneDotPerURL)
@@ -217,6 +234,8 @@ func setupRoutes(e *echo.Echo) {
 	e.Group("/api", apiMiddlewares()...)
 
 	e.GET("/ws", websocketRoute)
+	e.GET("/explorer/search/tag", explorerSearchTagRoute)
+	e.GET("/metrics/history", metricsHistoryRoute, jwtAuth.Middleware(middleware.DefaultSkipper, jwtAuthAllow))
 
 	// Rate-limit the auth endpoint
 	rateLimiterConfig := middleware.RateLimiterConfig{
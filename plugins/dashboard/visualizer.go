@@ -25,11 +25,21 @@ type vertex struct {
 	IsReferenced bool     `json:"is_referenced"`
 	IsMilestone  bool     `json:"is_milestone"`
 	IsTip        bool     `json:"is_tip"`
+
+	// the tag of the message, used by the topic filters.
+	tag []byte
+	// the key of the message for sampling.
+	sampleKey uint32
+	// whether the vertex is sent because the message was referenced.
+	onReferenced bool
 }
 
 // metainfo signals that metadata of a given message changed.
 type metainfo struct {
 	ID string `json:"id"`
+
+	// the key of the message for sampling.
+	sampleKey uint32
 }
 
 // confirmationinfo signals confirmation of a milestone msg with a list of exluded msgs in the past cone.
@@ -42,6 +52,41 @@ type confirmationinfo struct {
 type tipinfo struct {
 	ID    string `json:"id"`
 	IsTip bool   `json:"is_tip"`
+
+	// the key of the message for sampling.
+	sampleKey uint32
+}
+
+// messageTag returns the tag of the tagged data payload of the message or its transaction, nil if there is none.
+func messageTag(msg *storage.Message) []byte {
+	taggedData := msg.TaggedData()
+	if taggedData == nil {
+		taggedData = msg.TransactionEssenceTaggedData()
+	}
+	if taggedData == nil {
+		return nil
+	}
+	return taggedData.Tag
+}
+
+// newVertex creates the vertex of the given message.
+func newVertex(msg *storage.Message, metadata *storage.MessageMetadata, onReferenced bool) *vertex {
+	parentsHex := make([]string, len(msg.Parents()))
+	for i, parent := range msg.Parents() {
+		parentsHex[i] = parent.ToHex()[:VisualizerIDLength]
+	}
+
+	return &vertex{
+		ID:           msg.MessageID().ToHex(),
+		Parents:      parentsHex,
+		IsSolid:      metadata.IsSolid(),
+		IsReferenced: metadata.IsReferenced(),
+		IsMilestone:  false,
+		IsTip:        false,
+		tag:          messageTag(msg),
+		sampleKey:    sampleKey(msg.MessageID()),
+		onReferenced: onReferenced,
+	}
 }
 
 func runVisualizer() {
@@ -52,24 +97,25 @@ func runVisualizer() {
 				return
 			}
 
-			parentsHex := make([]string, len(msg.Parents()))
-			for i, parent := range msg.Parents() {
-				parentsHex[i] = parent.ToHex()[:VisualizerIDLength]
-			}
+			hub.BroadcastMsg(&Msg{Type: MsgTypeVertex, Data: newVertex(msg, metadata, false)})
+		})
+	})
 
-			hub.BroadcastMsg(
-				&Msg{
-					Type: MsgTypeVertex,
-					Data: &vertex{
-						ID:           msg.MessageID().ToHex(),
-						Parents:      parentsHex,
-						IsSolid:      metadata.IsSolid(),
-						IsReferenced: metadata.IsReferenced(),
-						IsMilestone:  false,
-						IsTip:        false,
-					},
-				},
-			)
+	// the vertices of referenced messages are only needed by clients that filter for them
+	onMessageReferenced := events.NewClosure(func(cachedMetadata *storage.CachedMetadata, _ milestone.Index, _ uint64) {
+		defer cachedMetadata.Release(true) // meta -1
+
+		if referencedVertexSubscriptions.Load() == 0 || !deps.SyncManager.IsNodeAlmostSynced() {
+			return
+		}
+
+		cachedMsg := deps.Storage.CachedMessageOrNil(cachedMetadata.Metadata().MessageID()) // msg +1
+		if cachedMsg == nil {
+			return
+		}
+
+		cachedMsg.ConsumeMessageAndMetadata(func(msg *storage.Message, metadata *storage.MessageMetadata) { // msg -1
+			hub.BroadcastMsg(&Msg{Type: MsgTypeVertex, Data: newVertex(msg, metadata, true)})
 		})
 	})
 
@@ -84,7 +130,8 @@ func runVisualizer() {
 				&Msg{
 					Type: MsgTypeSolidInfo,
 					Data: &metainfo{
-						ID: metadata.MessageID().ToHex()[:VisualizerIDLength],
+						ID:        metadata.MessageID().ToHex()[:VisualizerIDLength],
+						sampleKey: sampleKey(metadata.MessageID()),
 					},
 				},
 			)
@@ -154,8 +201,9 @@ func runVisualizer() {
 			&Msg{
 				Type: MsgTypeTipInfo,
 				Data: &tipinfo{
-					ID:    tip.MessageID.ToHex()[:VisualizerIDLength],
-					IsTip: true,
+					ID:        tip.MessageID.ToHex()[:VisualizerIDLength],
+					IsTip:     true,
+					sampleKey: sampleKey(tip.MessageID),
 				},
 			},
 		)
@@ -170,8 +218,9 @@ func runVisualizer() {
 			&Msg{
 				Type: MsgTypeTipInfo,
 				Data: &tipinfo{
-					ID:    tip.MessageID.ToHex()[:VisualizerIDLength],
-					IsTip: false,
+					ID:        tip.MessageID.ToHex()[:VisualizerIDLength],
+					IsTip:     false,
+					sampleKey: sampleKey(tip.MessageID),
 				},
 			},
 		)
@@ -180,6 +229,8 @@ func runVisualizer() {
 	if err := Plugin.Daemon().BackgroundWorker("Dashboard[Visualizer]", func(ctx context.Context) {
 		deps.Tangle.Events.ReceivedNewMessage.Attach(onReceivedNewMessage)
 		defer deps.Tangle.Events.ReceivedNewMessage.Detach(onReceivedNewMessage)
+		deps.Tangle.Events.MessageReferenced.Attach(onMessageReferenced)
+		defer deps.Tangle.Events.MessageReferenced.Detach(onMessageReferenced)
 		deps.Tangle.Events.MessageSolid.Attach(onMessageSolid)
 		defer deps.Tangle.Events.MessageSolid.Detach(onMessageSolid)
 		deps.Tangle.Events.ReceivedNewMilestone.Attach(onReceivedNewMilestone)
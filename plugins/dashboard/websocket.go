@@ -1,6 +1,8 @@
 package dashboard
 
 import (
+	"time"
+
 	"github.com/labstack/echo/v4"
 
 	"github.com/gohornet/hornet/pkg/jwt"
@@ -43,9 +45,27 @@ const (
 	MsgTypeSpamMetrics = 15
 	// MsgTypeAvgSpamMetrics is the type of the AvgSpamMetric message.
 	MsgTypeAvgSpamMetrics = 16
+	// MsgTypeMetricsHistory is the type of the metrics history samples message.
+	MsgTypeMetricsHistory = 17
+	// MsgTypeAlerts is the type of the active alerts message.
+	MsgTypeAlerts = 18
+	// MsgTypeCustomMetrics is the type of the message with the metric series published by other plugins.
+	MsgTypeCustomMetrics = 19
 )
 
 func websocketRoute(ctx echo.Context) error {
+	return serveWebsocket(ctx, true)
+}
+
+// publicWebsocketRoute serves the websocket of the public read-only dashboard,
+// which only allows to subscribe to the public topics.
+func publicWebsocketRoute(ctx echo.Context) error {
+	return serveWebsocket(ctx, false)
+}
+
+// serveWebsocket serves a websocket client of the dashboard.
+// Protected topics can only be subscribed with a valid JWT and only if they are allowed at all.
+func serveWebsocket(ctx echo.Context, protectedTopicsAllowed bool) error {
 	defer func() {
 		if r := recover(); r != nil {
 			Plugin.LogErrorf("recovered from panic within WS handle func: %s", r)
@@ -100,6 +120,19 @@ func websocketRoute(ctx echo.Context) error {
 		case MsgTypeDatabaseCleanupEvent:
 			client.Send(&Msg{Type: MsgTypeDatabaseCleanupEvent, Data: lastDBCleanup})
 
+		case MsgTypeMetricsHistory:
+			if metricsHistory != nil {
+				client.Send(&Msg{Type: MsgTypeMetricsHistory, Data: metricsHistory.Samples(time.Time{})})
+			}
+
+		case MsgTypeAlerts:
+			if deps.AlertManager != nil {
+				client.Send(&Msg{Type: MsgTypeAlerts, Data: activeAlerts()})
+			}
+
+		case MsgTypeCustomMetrics:
+			client.Send(&Msg{Type: MsgTypeCustomMetrics, Data: currentCustomMetrics()})
+
 		case MsgTypeMs:
 			start := deps.SyncManager.LatestMilestoneIndex()
 			for i := start - 10; i <= start; i++ {
@@ -113,9 +146,29 @@ func websocketRoute(ctx echo.Context) error {
 	}
 
 	topicsLock := syncutils.RWMutex{}
-	registeredTopics := make(map[byte]struct{})
+	registeredTopics := make(map[byte]*topicFilter)
 	initValuesSent := make(map[byte]struct{})
 
+	// setTopicFilter sets the filter of a topic for this client, nil unregisters the topic
+	setTopicFilter := func(topic byte, filter *topicFilter) {
+		topicsLock.Lock()
+		defer topicsLock.Unlock()
+
+		if previous, registered := registeredTopics[topic]; registered && previous.referencedOnly {
+			referencedVertexSubscriptions.Dec()
+		}
+
+		if filter == nil {
+			delete(registeredTopics, topic)
+			return
+		}
+
+		if filter.referencedOnly {
+			referencedVertexSubscriptions.Inc()
+		}
+		registeredTopics[topic] = filter
+	}
+
 	hub.ServeWebsocket(ctx.Response(), ctx.Request(),
 		// onCreate gets called when the client is created
 		func(client *websockethub.Client) {
@@ -126,13 +179,27 @@ func websocketRoute(ctx echo.Context) error {
 				}
 
 				topicsLock.RLock()
-				_, registered := registeredTopics[msg.Type]
+				filter, registered := registeredTopics[msg.Type]
 				topicsLock.RUnlock()
-				return registered
+				return registered && filter.matches(msg)
 			}
 			client.ReceiveChan = make(chan *websockethub.WebsocketMsg, 100)
 
 			go func() {
+				defer func() {
+					// remove the filters of the client, so the subscriptions are not counted anymore
+					topicsLock.RLock()
+					topics := make([]byte, 0, len(registeredTopics))
+					for topic := range registeredTopics {
+						topics = append(topics, topic)
+					}
+					topicsLock.RUnlock()
+
+					for _, topic := range topics {
+						setTopicFilter(topic, nil)
+					}
+				}()
+
 				for {
 					select {
 					case <-client.ExitSignal:
@@ -153,15 +220,32 @@ func websocketRoute(ctx echo.Context) error {
 							cmd := msg.Data[0]
 							topic := msg.Data[1]
 
-							if cmd == WebsocketCmdRegister {
+							switch cmd {
+							case WebsocketCmdRegister, WebsocketCmdRegisterWithFilter:
+								filter := noTopicFilter
+								token := string(msg.Data[2:])
+
+								if cmd == WebsocketCmdRegisterWithFilter {
+									request, topicFilter, err := parseTopicFilterRequest(topic, msg.Data[2:])
+									if err != nil {
+										// Do not register topics with invalid filters
+										continue
+									}
+									filter = topicFilter
+									token = request.JWT
+								}
 
 								if isProtectedTopic(topic) {
+									if !protectedTopicsAllowed {
+										// Do not allow subscriptions to protected topics on the public dashboard
+										continue
+									}
+
 									// Check for the presence of a JWT and verify it
-									if len(msg.Data) < 3 {
+									if len(token) == 0 {
 										// Dot not allow unsecure subscriptions to protected topics
 										continue
 									}
-									token := string(msg.Data[2:])
 									if !jwtAuth.VerifyJWT(token, func(claims *jwt.AuthClaims) bool {
 										return claims.Dashboard
 									}) {
@@ -171,17 +255,13 @@ func websocketRoute(ctx echo.Context) error {
 								}
 
 								// register topic fo this client
-								topicsLock.Lock()
-								registeredTopics[topic] = struct{}{}
-								topicsLock.Unlock()
+								setTopicFilter(topic, filter)
 
 								sendInitValue(client, initValuesSent, topic)
 
-							} else if cmd == WebsocketCmdUnregister {
+							case WebsocketCmdUnregister:
 								// unregister topic fo this client
-								topicsLock.Lock()
-								delete(registeredTopics, topic)
-								topicsLock.Unlock()
+								setTopicFilter(topic, nil)
 							}
 						}
 					}
@@ -0,0 +1,116 @@
+package dashboard
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/atomic"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+var (
+	// the amount of subscriptions of the vertex topic which only want referenced messages.
+	// the vertices of referenced messages are only broadcasted if there are such subscriptions.
+	referencedVertexSubscriptions = atomic.NewInt32(0)
+
+	// the filter of topics that were registered without a filter.
+	noTopicFilter = &topicFilter{}
+)
+
+// TopicFilterRequest is the JSON payload of a WebsocketCmdRegisterWithFilter command.
+type TopicFilterRequest struct {
+	// The JWT, needed to register protected topics.
+	JWT string `json:"jwt,omitempty"`
+	// Only vertices of messages with this tag, either as hex string with a "0x" prefix or as text.
+	Tag string `json:"tag,omitempty"`
+	// Only vertices of messages that were referenced by a milestone.
+	// The vertices are sent when the message gets referenced instead of when it is received.
+	ReferencedOnly bool `json:"referencedOnly,omitempty"`
+	// Only every n-th message of the visualizer topics, chosen by the message ID,
+	// so that the vertex, solid and tip infos of the same messages are sent.
+	SampleRate uint32 `json:"sampleRate,omitempty"`
+}
+
+// topicFilter filters the messages of a topic for a single client.
+type topicFilter struct {
+	tag            []byte
+	referencedOnly bool
+	sampleRate     uint32
+}
+
+// filterableTopics are the topics that can be registered with a filter.
+var filterableTopics = map[byte]struct{}{
+	MsgTypeVertex:    {},
+	MsgTypeSolidInfo: {},
+	MsgTypeTipInfo:   {},
+}
+
+// parseTopicFilterRequest parses the filter of a WebsocketCmdRegisterWithFilter command for the given topic.
+func parseTopicFilterRequest(topic byte, data []byte) (*TopicFilterRequest, *topicFilter, error) {
+	if _, filterable := filterableTopics[topic]; !filterable {
+		return nil, nil, fmt.Errorf("topic %d can't be filtered", topic)
+	}
+
+	request := &TopicFilterRequest{}
+	if err := json.Unmarshal(data, request); err != nil {
+		return nil, nil, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	filter := &topicFilter{
+		referencedOnly: request.ReferencedOnly,
+		sampleRate:     request.SampleRate,
+	}
+
+	if request.Tag != "" {
+		tag, err := parseTag(request.Tag)
+		if err != nil {
+			return nil, nil, err
+		}
+		filter.tag = tag
+	}
+
+	if (filter.tag != nil || filter.referencedOnly) && topic != MsgTypeVertex {
+		return nil, nil, fmt.Errorf("only the vertex topic can be filtered by tag or referenced state")
+	}
+
+	return request, filter, nil
+}
+
+// sampleKey returns the key of a message that is used for sampling.
+func sampleKey(messageID hornet.MessageID) uint32 {
+	return binary.LittleEndian.Uint32(messageID[:4])
+}
+
+// sampled tells whether a message with the given sample key is part of the sample.
+func (f *topicFilter) sampled(key uint32) bool {
+	return f.sampleRate <= 1 || key%f.sampleRate == 0
+}
+
+// matches tells whether the message should be sent to the client.
+func (f *topicFilter) matches(msg *Msg) bool {
+	switch data := msg.Data.(type) {
+	case *vertex:
+		if data.onReferenced != f.referencedOnly {
+			return false
+		}
+		if f.tag != nil && !bytes.Equal(f.tag, data.tag) {
+			return false
+		}
+		return f.sampled(data.sampleKey)
+
+	case *metainfo:
+		if msg.Type != MsgTypeSolidInfo {
+			return true
+		}
+		return f.sampled(data.sampleKey)
+
+	case *tipinfo:
+		return f.sampled(data.sampleKey)
+
+	default:
+		return true
+	}
+}
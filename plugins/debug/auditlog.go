@@ -0,0 +1,41 @@
+package debug
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/restapi"
+)
+
+func auditLog(c echo.Context) (*auditLogResponse, error) {
+
+	if deps.AuditLog == nil {
+		return nil, errors.WithMessage(echo.ErrNotFound, "audit log is disabled")
+	}
+
+	page, err := restapi.ParsePage(c, deps.RestAPILimitsMaxResults)
+	if err != nil {
+		return nil, err
+	}
+
+	var since time.Time
+	if len(c.QueryParam(QueryParameterSince)) > 0 {
+		since, err = restapi.ParseUnixTimestampQueryParam(c, QueryParameterSince)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := deps.AuditLog.Entries(since, page)
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "reading audit log failed, error: %s", err)
+	}
+
+	return &auditLogResponse{
+		PageSize: page.PageSize(),
+		Cursor:   page.NextCursor(),
+		Entries:  entries,
+	}, nil
+}
@@ -0,0 +1,129 @@
+package debug
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/protocol/gossip"
+	"github.com/gohornet/hornet/pkg/restapi"
+	"github.com/iotaledger/hive.go/protocol/message"
+)
+
+// the names of the gossip packet types in the capture responses.
+var packetTypeNames = map[message.Type]string{
+	gossip.MessageTypeMilestoneRequest: "milestoneRequest",
+	gossip.MessageTypeMessage:          "message",
+	gossip.MessageTypeMessageRequest:   "messageRequest",
+	gossip.MessageTypeHeartbeat:        "heartbeat",
+}
+
+func packetTypeName(msgType message.Type) string {
+	if name, exists := packetTypeNames[msgType]; exists {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", msgType)
+}
+
+// returns the gossip protocol of the peer given in the request.
+func gossipProtocolFromParam(c echo.Context) (*gossip.Protocol, error) {
+	peerID, err := restapi.ParsePeerIDParam(c)
+	if err != nil {
+		return nil, err
+	}
+
+	proto := deps.GossipService.Protocol(peerID)
+	if proto == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "no gossip protocol running with peer: %s", peerID)
+	}
+
+	return proto, nil
+}
+
+func packetCaptureResponseFor(proto *gossip.Protocol, capture *gossip.PacketCapture) *packetCaptureResponse {
+	packets := capture.Packets()
+
+	resp := &packetCaptureResponse{
+		PeerID:    proto.PeerID.String(),
+		Limit:     capture.Limit(),
+		StartedAt: capture.StartedAt().Format(time.RFC3339Nano),
+		Done:      capture.Done(),
+		Packets:   make([]*capturedPacketResponse, 0, len(packets)),
+	}
+
+	for _, packet := range packets {
+		packetResp := &capturedPacketResponse{
+			Direction:      string(packet.Direction),
+			Type:           packetTypeName(packet.Type),
+			Size:           packet.Size,
+			Timestamp:      packet.Time.Format(time.RFC3339Nano),
+			MilestoneIndex: uint32(packet.MilestoneIndex),
+		}
+		if packet.MessageID != nil {
+			packetResp.MessageID = packet.MessageID.ToHex()
+		}
+		resp.Packets = append(resp.Packets, packetResp)
+	}
+
+	return resp
+}
+
+func startPacketCapture(c echo.Context) (*packetCaptureResponse, error) {
+
+	proto, err := gossipProtocolFromParam(c)
+	if err != nil {
+		return nil, err
+	}
+
+	maxPackets := deps.NodeConfig.Int(CfgDebugPacketCaptureMaxPackets)
+	if maxPackets <= 0 {
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "packet capture is disabled")
+	}
+
+	request := &packetCaptureRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	packets := maxPackets
+	if request.Packets != nil {
+		if *request.Packets <= 0 || *request.Packets > maxPackets {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid packets, must be between 1 and %d", maxPackets)
+		}
+		packets = *request.Packets
+	}
+
+	return packetCaptureResponseFor(proto, proto.StartCapture(packets)), nil
+}
+
+func packetCapture(c echo.Context) (*packetCaptureResponse, error) {
+
+	proto, err := gossipProtocolFromParam(c)
+	if err != nil {
+		return nil, err
+	}
+
+	capture := proto.Capture()
+	if capture == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "no packet capture running for peer: %s", proto.PeerID)
+	}
+
+	return packetCaptureResponseFor(proto, capture), nil
+}
+
+func stopPacketCapture(c echo.Context) (*packetCaptureResponse, error) {
+
+	proto, err := gossipProtocolFromParam(c)
+	if err != nil {
+		return nil, err
+	}
+
+	capture := proto.StopCapture()
+	if capture == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "no packet capture running for peer: %s", proto.PeerID)
+	}
+
+	return packetCaptureResponseFor(proto, capture), nil
+}
@@ -119,16 +119,132 @@ func computeWhiteFlagMutations(c echo.Context) (*computeWhiteFlagMutationsRespon
 	}, nil
 }
 
+// whiteFlagMessageState returns the ledger inclusion state of a message and the reason if it is conflicting.
+func whiteFlagMessageState(included bool, conflict storage.Conflict) (string, *storage.Conflict) {
+	switch {
+	case conflict != storage.ConflictNone:
+		return "conflicting", &conflict
+	case included:
+		return "included", nil
+	default:
+		return "noTransaction", nil
+	}
+}
+
+func milestoneWhiteFlag(c echo.Context) (*milestoneWhiteFlagResponse, error) {
+
+	msIndex, err := restapi.ParseMilestoneIndexParam(c, restapi.ParameterMilestoneIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshotInfo := deps.Storage.SnapshotInfo(); snapshotInfo != nil && msIndex <= snapshotInfo.PruningIndex {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "milestone index %d is not above the pruning index %d", msIndex, snapshotInfo.PruningIndex)
+	}
+
+	cachedMilestone := deps.Storage.CachedMilestoneOrNil(msIndex) // milestone +1
+	if cachedMilestone == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "milestone not found: %d", msIndex)
+	}
+	milestoneMessageID := cachedMilestone.Milestone().MessageID
+	cachedMilestone.Release(true) // milestone -1
+
+	cachedMilestoneMsg := deps.Storage.CachedMessageOrNil(milestoneMessageID) // message +1
+	if cachedMilestoneMsg == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "milestone message not found: %s", milestoneMessageID.ToHex())
+	}
+	defer cachedMilestoneMsg.Release(true) // message -1
+
+	ms := cachedMilestoneMsg.Message().Milestone()
+	if ms == nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "milestone message contains no milestone payload: %s", milestoneMessageID.ToHex())
+	}
+
+	deps.UTXOManager.ReadLockLedger()
+	defer deps.UTXOManager.ReadUnlockLedger()
+
+	ledgerIndex, err := deps.UTXOManager.ReadLedgerIndexWithoutLocking()
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "reading ledger index failed, error: %s", err)
+	}
+
+	if msIndex > ledgerIndex {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "milestone %d was not applied to the ledger yet", msIndex)
+	}
+
+	mutations, err := whiteflag.RecomputeWhiteFlagMutations(Plugin.Daemon().ContextStopped(), deps.Storage, msIndex, ms.Timestamp, cachedMilestoneMsg.Message().Parents())
+	if err != nil {
+		if errors.Is(err, common.ErrOperationAborted) {
+			return nil, errors.WithMessagef(echo.ErrServiceUnavailable, "failed to compute white flag mutations: %s", err)
+		}
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "failed to compute white flag mutations: %s", err)
+	}
+
+	included := make(map[string]struct{}, len(mutations.MessagesIncludedWithTransactions))
+	for _, messageID := range mutations.MessagesIncludedWithTransactions {
+		included[messageID.ToMapKey()] = struct{}{}
+	}
+
+	conflicts := make(map[string]storage.Conflict, len(mutations.MessagesExcludedWithConflictingTransactions))
+	for _, conflict := range mutations.MessagesExcludedWithConflictingTransactions {
+		conflicts[conflict.MessageID.ToMapKey()] = conflict.Conflict
+	}
+
+	messages := make([]*whiteFlagMessage, 0, len(mutations.MessagesReferenced))
+	for _, messageID := range mutations.MessagesReferenced {
+		_, isIncluded := included[messageID.ToMapKey()]
+		state, conflictReason := whiteFlagMessageState(isIncluded, conflicts[messageID.ToMapKey()])
+
+		message := &whiteFlagMessage{
+			MessageID:            messageID.ToHex(),
+			LedgerInclusionState: state,
+			ConflictReason:       conflictReason,
+		}
+
+		cachedMsgMeta := deps.Storage.CachedMessageMetadataOrNil(messageID) // meta +1
+		if cachedMsgMeta != nil {
+			metadata := cachedMsgMeta.Metadata()
+			storedState, storedConflictReason := whiteFlagMessageState(metadata.IsIncludedTxInLedger(), metadata.Conflict())
+			cachedMsgMeta.Release(true) // meta -1
+
+			if storedState != state || (storedConflictReason != nil && *storedConflictReason != conflicts[messageID.ToMapKey()]) {
+				message.StoredLedgerInclusionState = storedState
+				message.StoredConflictReason = storedConflictReason
+			}
+		}
+
+		messages = append(messages, message)
+	}
+
+	return &milestoneWhiteFlagResponse{
+		Index:                msIndex,
+		MessageID:            milestoneMessageID.ToHex(),
+		Timestamp:            ms.Timestamp,
+		Messages:             messages,
+		MerkleTreeHash:       hex.EncodeToString(mutations.MerkleTreeHash[:]),
+		InclusionMerkleProof: hex.EncodeToString(ms.InclusionMerkleProof[:]),
+		MerkleTreeHashValid:  mutations.MerkleTreeHash == ms.InclusionMerkleProof,
+	}, nil
+}
+
 func outputsIDs(c echo.Context) (*outputIDsResponse, error) {
 	filterType, err := restapi.ParseOutputTypeQueryParam(c)
 	if err != nil {
 		return nil, err
 	}
 
+	page, err := restapi.ParsePage(c, deps.RestAPILimitsMaxResults)
+	if err != nil {
+		return nil, err
+	}
+
 	outputIDs := []string{}
 	appendConsumerFunc := func(output *utxo.Output) bool {
-		outputIDs = append(outputIDs, output.OutputID().ToHex())
-		return true
+		inPage, next := page.Next()
+		if inPage {
+			outputIDs = append(outputIDs, output.OutputID().ToHex())
+		}
+		return next
 	}
 
 	outputConsumerFunc := appendConsumerFunc
@@ -148,6 +264,8 @@ func outputsIDs(c echo.Context) (*outputIDsResponse, error) {
 	}
 
 	return &outputIDsResponse{
+		PageSize:  page.PageSize(),
+		Cursor:    page.NextCursor(),
 		OutputIDs: outputIDs,
 	}, nil
 }
@@ -158,10 +276,18 @@ func unspentOutputsIDs(c echo.Context) (*outputIDsResponse, error) {
 		return nil, err
 	}
 
+	page, err := restapi.ParsePage(c, deps.RestAPILimitsMaxResults)
+	if err != nil {
+		return nil, err
+	}
+
 	outputIDs := []string{}
 	appendConsumerFunc := func(output *utxo.Output) bool {
-		outputIDs = append(outputIDs, output.OutputID().ToHex())
-		return true
+		inPage, next := page.Next()
+		if inPage {
+			outputIDs = append(outputIDs, output.OutputID().ToHex())
+		}
+		return next
 	}
 
 	outputConsumerFunc := appendConsumerFunc
@@ -181,6 +307,8 @@ func unspentOutputsIDs(c echo.Context) (*outputIDsResponse, error) {
 	}
 
 	return &outputIDsResponse{
+		PageSize:  page.PageSize(),
+		Cursor:    page.NextCursor(),
 		OutputIDs: outputIDs,
 	}, nil
 }
@@ -191,10 +319,18 @@ func spentOutputsIDs(c echo.Context) (*outputIDsResponse, error) {
 		return nil, err
 	}
 
+	page, err := restapi.ParsePage(c, deps.RestAPILimitsMaxResults)
+	if err != nil {
+		return nil, err
+	}
+
 	outputIDs := []string{}
 	appendConsumerFunc := func(spent *utxo.Spent) bool {
-		outputIDs = append(outputIDs, spent.OutputID().ToHex())
-		return true
+		inPage, next := page.Next()
+		if inPage {
+			outputIDs = append(outputIDs, spent.OutputID().ToHex())
+		}
+		return next
 	}
 
 	spentConsumerFunc := appendConsumerFunc
@@ -214,6 +350,8 @@ func spentOutputsIDs(c echo.Context) (*outputIDsResponse, error) {
 	}
 
 	return &outputIDsResponse{
+		PageSize:  page.PageSize(),
+		Cursor:    page.NextCursor(),
 		OutputIDs: outputIDs,
 	}, nil
 }
@@ -260,7 +398,12 @@ func milestoneDiff(c echo.Context) (*milestoneDiffResponse, error) {
 }
 
 //nolint:unparam // even if the error is never used, the structure of all routes should be the same
-func requests(_ echo.Context) (*requestsResponse, error) {
+func requests(c echo.Context) (*requestsResponse, error) {
+
+	page, err := restapi.ParsePage(c, deps.RestAPILimitsMaxResults)
+	if err != nil {
+		return nil, err
+	}
 
 	queued, pending, processing := deps.RequestQueue.Requests()
 	debugReqs := make([]*request, 0, len(queued)+len(pending)+len(processing))
@@ -295,11 +438,32 @@ func requests(_ echo.Context) (*requestsResponse, error) {
 		})
 	}
 
+	start, end := page.Bounds(len(debugReqs))
+
 	return &requestsResponse{
-		Requests: debugReqs,
+		PageSize: page.PageSize(),
+		Cursor:   page.NextCursor(),
+		Requests: debugReqs[start:end],
 	}, nil
 }
 
+func emitQueue() *emitQueueResponse {
+
+	stats := deps.MessageProcessor.EmitQueueStats()
+	entries := make([]*emitQueueEntry, 0, len(stats))
+	for _, s := range stats {
+		entries = append(entries, &emitQueueEntry{
+			Priority:  s.Priority.String(),
+			Count:     s.Count,
+			OldestAge: s.OldestAge.Milliseconds(),
+		})
+	}
+
+	return &emitQueueResponse{
+		Priorities: entries,
+	}
+}
+
 func messageCone(c echo.Context) (*messageConeResponse, error) {
 
 	messageID, err := restapi.ParseMessageIDParam(c)
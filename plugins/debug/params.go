@@ -11,6 +11,10 @@ import (
 const (
 	// the maximum duration for the parents to become solid during white flag confirmation API call.
 	CfgDebugWhiteFlagParentsSolidTimeout = "debug.whiteFlagParentsSolidTimeout"
+	// the amount of messages for which the processing timeline is kept (0 = disabled).
+	CfgDebugMessageTimelineCapacity = "debug.messageTimelineCapacity"
+	// the maximum amount of gossip packets a packet capture of a peer may record (0 = disabled).
+	CfgDebugPacketCaptureMaxPackets = "debug.packetCaptureMaxPackets"
 )
 
 var params = &node.PluginParams{
@@ -18,6 +22,8 @@ var params = &node.PluginParams{
 		"nodeConfig": func() *flag.FlagSet {
 			fs := flag.NewFlagSet("", flag.ContinueOnError)
 			fs.Duration(CfgDebugWhiteFlagParentsSolidTimeout, 2*time.Second, "defines the the maximum duration for the parents to become solid during white flag confirmation API call")
+			fs.Int(CfgDebugMessageTimelineCapacity, 10000, "defines the amount of messages for which the processing timeline is kept (0 = disabled)")
+			fs.Int(CfgDebugPacketCaptureMaxPackets, 1000, "defines the maximum amount of gossip packets a packet capture of a peer may record (0 = disabled)")
 			return fs
 		}(),
 	},
@@ -1,6 +1,7 @@
 package debug
 
 import (
+	"context"
 	"net/http"
 	"time"
 
@@ -11,8 +12,10 @@ import (
 	"github.com/gohornet/hornet/pkg/model/syncmanager"
 	"github.com/gohornet/hornet/pkg/model/utxo"
 	"github.com/gohornet/hornet/pkg/node"
+	"github.com/gohornet/hornet/pkg/pow"
 	"github.com/gohornet/hornet/pkg/protocol/gossip"
 	restapipkg "github.com/gohornet/hornet/pkg/restapi"
+	"github.com/gohornet/hornet/pkg/shutdown"
 	"github.com/gohornet/hornet/pkg/tangle"
 	"github.com/gohornet/hornet/plugins/restapi"
 	restapiv2 "github.com/gohornet/hornet/plugins/restapi/v2"
@@ -24,6 +27,10 @@ const (
 	// POST computes the white flag confirmation.
 	RouteDebugComputeWhiteFlag = "/whiteflag"
 
+	// RouteDebugMilestoneWhiteFlag is the debug route to compute the white flag confirmation of an already confirmed milestone again.
+	// GET returns the referenced messages in white flag order with their computed ledger inclusion state, and the merkle tree hash.
+	RouteDebugMilestoneWhiteFlag = "/milestones/:" + restapipkg.ParameterMilestoneIndex + "/whiteflag"
+
 	// RouteDebugSolidifier is the debug route to manually trigger the solidifier.
 	// POST triggers the solidifier.
 	RouteDebugSolidifier = "/solidifier"
@@ -60,6 +67,31 @@ const (
 	// it traverses the parents of a message until they reference an older milestone than the start message.
 	// GET returns the path of this traversal and the "entry points".
 	RouteDebugMessageCone = "/message-cones/:" + restapipkg.ParameterMessageID
+
+	// RouteDebugEmitQueue is the debug route for getting the queued emissions of the node's own messages.
+	// GET returns the amount and the age of the oldest queued emission per priority.
+	RouteDebugEmitQueue = "/emit-queue"
+
+	// RouteDebugMessageTimeline is the debug route for getting the processing timeline of a message.
+	// GET returns the times the message was received, PoW checked, stored, solid and referenced.
+	RouteDebugMessageTimeline = "/messages/:" + restapipkg.ParameterMessageID + "/timeline"
+
+	// RouteDebugPeerCapture is the debug route for capturing the gossip packets exchanged with a peer.
+	// POST starts recording the next packets, replacing a previous capture of the peer.
+	// GET returns the recorded packets.
+	// DELETE stops the capture and returns the recorded packets.
+	RouteDebugPeerCapture = "/peers/:" + restapipkg.ParameterPeerID + "/capture"
+
+	// RouteDebugAuditLog is the debug route for getting the recorded calls of the audited REST API routes.
+	// GET returns the calls recorded since the unix timestamp given by the "since" query parameter.
+	RouteDebugAuditLog = "/audit-log"
+
+	// RouteDebugPoWBenchmark is the debug route for measuring the PoW hash rate of the node per thread count.
+	// POST runs the benchmark and returns the results and the suggested worker settings.
+	RouteDebugPoWBenchmark = "/pow-benchmark"
+
+	// QueryParameterSince is used to define the unix timestamp since which the recorded calls are returned.
+	QueryParameterSince = "since"
 )
 
 func init() {
@@ -70,6 +102,7 @@ func init() {
 			DepsFunc:  func(cDeps dependencies) { deps = cDeps },
 			Params:    params,
 			Configure: configure,
+			Run:       run,
 		},
 	}
 }
@@ -83,12 +116,17 @@ var (
 
 type dependencies struct {
 	dig.In
-	Storage      *storage.Storage
-	SyncManager  *syncmanager.SyncManager
-	Tangle       *tangle.Tangle
-	RequestQueue gossip.RequestQueue
-	UTXOManager  *utxo.Manager
-	NodeConfig   *configuration.Configuration `name:"nodeConfig"`
+	Storage                 *storage.Storage
+	SyncManager             *syncmanager.SyncManager
+	Tangle                  *tangle.Tangle
+	RequestQueue            gossip.RequestQueue
+	MessageProcessor        *gossip.MessageProcessor
+	GossipService           *gossip.Service
+	UTXOManager             *utxo.Manager
+	NodeConfig              *configuration.Configuration `name:"nodeConfig"`
+	RestAPILimitsMaxResults int                          `name:"restAPILimitsMaxResults"`
+	AuditLog                *restapipkg.AuditLog         `optional:"true"`
+	PoWHandler              *pow.Handler
 }
 
 func configure() {
@@ -98,6 +136,7 @@ func configure() {
 	}
 
 	whiteflagParentsSolidTimeout = deps.NodeConfig.Duration(CfgDebugWhiteFlagParentsSolidTimeout)
+	configureTimeline(deps.NodeConfig.Int(CfgDebugMessageTimelineCapacity))
 
 	routeGroup := restapiv2.AddPlugin("debug/v1")
 
@@ -110,6 +149,15 @@ func configure() {
 		return restapipkg.JSONResponse(c, http.StatusOK, resp)
 	})
 
+	routeGroup.GET(RouteDebugMilestoneWhiteFlag, func(c echo.Context) error {
+		resp, err := milestoneWhiteFlag(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
 	routeGroup.POST(RouteDebugSolidifier, func(c echo.Context) error {
 		deps.Tangle.TriggerSolidifier()
 
@@ -161,6 +209,28 @@ func configure() {
 		return restapipkg.JSONResponse(c, http.StatusOK, resp)
 	})
 
+	routeGroup.GET(RouteDebugAuditLog, func(c echo.Context) error {
+		resp, err := auditLog(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RouteDebugPoWBenchmark, func(c echo.Context) error {
+		resp, err := powBenchmark(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteDebugEmitQueue, func(c echo.Context) error {
+		return restapipkg.JSONResponse(c, http.StatusOK, emitQueue())
+	})
+
 	routeGroup.GET(RouteDebugMessageCone, func(c echo.Context) error {
 		resp, err := messageCone(c)
 		if err != nil {
@@ -169,4 +239,54 @@ func configure() {
 
 		return restapipkg.JSONResponse(c, http.StatusOK, resp)
 	})
+
+	routeGroup.GET(RouteDebugMessageTimeline, func(c echo.Context) error {
+		resp, err := messageTimeline(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RouteDebugPeerCapture, func(c echo.Context) error {
+		resp, err := startPacketCapture(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteDebugPeerCapture, func(c echo.Context) error {
+		resp, err := packetCapture(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.DELETE(RouteDebugPeerCapture, func(c echo.Context) error {
+		resp, err := stopPacketCapture(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+}
+
+func run() {
+	if timelineStore == nil {
+		return
+	}
+
+	if err := Plugin.Daemon().BackgroundWorker("Debug[MessageTimeline]", func(ctx context.Context) {
+		attachTimelineEvents()
+		<-ctx.Done()
+		detachTimelineEvents()
+	}, shutdown.PriorityDebug); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
 }
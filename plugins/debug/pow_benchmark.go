@@ -0,0 +1,102 @@
+package debug
+
+import (
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/pow"
+	"github.com/gohornet/hornet/pkg/restapi"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	powBenchmarkDefaultDuration    = 5 * time.Second
+	powBenchmarkMaxDuration        = time.Minute
+	powBenchmarkDefaultMessageSize = 300
+	// the network ID, the parents count, a single parent, an empty payload and the nonce.
+	powBenchmarkMinMessageSize = 8 + 1 + iotago.MessageIDLength + 4 + 8
+)
+
+var (
+	// whether a PoW benchmark is running, only one is allowed at a time to not distort the results.
+	powBenchmarkRunning uint32
+)
+
+func powBenchmark(c echo.Context) (*powBenchmarkResponse, error) {
+
+	request := &powBenchmarkRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	maxThreads := runtime.NumCPU()
+	if request.MaxThreads != nil {
+		if *request.MaxThreads < 1 || *request.MaxThreads > runtime.NumCPU() {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid maxThreads: %d, must be between 1 and %d", *request.MaxThreads, runtime.NumCPU())
+		}
+		maxThreads = *request.MaxThreads
+	}
+
+	duration := powBenchmarkDefaultDuration
+	if request.Duration != nil {
+		var err error
+		duration, err = time.ParseDuration(*request.Duration)
+		if err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid duration: %s, error: %s", *request.Duration, err)
+		}
+		if duration <= 0 || duration > powBenchmarkMaxDuration {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid duration: %s, must be between 0 and %v", *request.Duration, powBenchmarkMaxDuration)
+		}
+	}
+
+	messageSize := powBenchmarkDefaultMessageSize
+	if request.MessageSize != nil {
+		if *request.MessageSize < powBenchmarkMinMessageSize || *request.MessageSize > iotago.MessageBinSerializedMaxSize {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid messageSize: %d, must be between %d and %d", *request.MessageSize, powBenchmarkMinMessageSize, iotago.MessageBinSerializedMaxSize)
+		}
+		messageSize = *request.MessageSize
+	}
+
+	if !atomic.CompareAndSwapUint32(&powBenchmarkRunning, 0, 1) {
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "PoW benchmark already running")
+	}
+	defer atomic.StoreUint32(&powBenchmarkRunning, 0)
+
+	results, err := deps.PoWHandler.Benchmark(c.Request().Context(), messageSize, pow.BenchmarkThreadCounts(maxThreads), duration)
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "PoW benchmark failed, error: %s", err)
+	}
+
+	resp := &powBenchmarkResponse{
+		PoWType:     deps.PoWHandler.PoWType(),
+		TargetScore: deps.PoWHandler.TargetScore(),
+		MessageSize: messageSize,
+		Results:     make([]*powBenchmarkResult, len(results)),
+	}
+
+	for i, result := range results {
+		resp.Results[i] = &powBenchmarkResult{
+			Threads:           result.Threads,
+			Messages:          result.Messages,
+			Duration:          result.Duration.Truncate(time.Millisecond).String(),
+			MessagesPerSecond: result.MessagesPerSecond,
+			HashesPerSecond:   result.HashesPerSecond,
+		}
+	}
+
+	// every spammer worker mines its messages with a single thread, and the faucet mines one message at a time with all of its workers,
+	// so the thread count with the best hash rate is suggested for both.
+	if suggested := pow.SuggestedThreads(results); suggested != nil {
+		resp.Suggestion = &powBenchmarkSuggestion{
+			SpammerWorkers:       suggested.Threads,
+			FaucetPoWWorkerCount: suggested.Threads,
+			MessagesPerSecond:    suggested.MessagesPerSecond,
+		}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,109 @@
+package debug
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/restapi"
+	"github.com/gohornet/hornet/pkg/timeline"
+	"github.com/iotaledger/hive.go/events"
+)
+
+var (
+	// the recorded processing stages of the latest messages, nil if disabled.
+	timelineStore *timeline.Store
+
+	// Closures
+	onMessageValidated   *events.Closure
+	onReceivedNewMessage *events.Closure
+	onMessageSolid       *events.Closure
+	onMessageReferenced  *events.Closure
+)
+
+func configureTimeline(capacity int) {
+	if capacity <= 0 {
+		return
+	}
+
+	timelineStore = timeline.NewStore(capacity)
+
+	onMessageValidated = events.NewClosure(func(messageID hornet.MessageID, receivedTime time.Time) {
+		timelineStore.Record(messageID, timeline.StageReceived, receivedTime)
+		timelineStore.Record(messageID, timeline.StagePoWChecked, time.Now())
+	})
+
+	onReceivedNewMessage = events.NewClosure(func(cachedMsg *storage.CachedMessage, _ milestone.Index, _ milestone.Index) {
+		defer cachedMsg.Release(true) // message -1
+		timelineStore.Record(cachedMsg.Message().MessageID(), timeline.StageStored, time.Now())
+	})
+
+	onMessageSolid = events.NewClosure(func(cachedMsgMeta *storage.CachedMetadata) {
+		defer cachedMsgMeta.Release(true) // meta -1
+		timelineStore.Record(cachedMsgMeta.Metadata().MessageID(), timeline.StageSolid, time.Now())
+	})
+
+	onMessageReferenced = events.NewClosure(func(cachedMsgMeta *storage.CachedMetadata, _ milestone.Index, _ uint64) {
+		defer cachedMsgMeta.Release(true) // meta -1
+		timelineStore.Record(cachedMsgMeta.Metadata().MessageID(), timeline.StageReferenced, time.Now())
+	})
+}
+
+func attachTimelineEvents() {
+	deps.MessageProcessor.Events.MessageValidated.Attach(onMessageValidated)
+	deps.Tangle.Events.ReceivedNewMessage.Attach(onReceivedNewMessage)
+	deps.Tangle.Events.MessageSolid.Attach(onMessageSolid)
+	deps.Tangle.Events.MessageReferenced.Attach(onMessageReferenced)
+}
+
+func detachTimelineEvents() {
+	deps.MessageProcessor.Events.MessageValidated.Detach(onMessageValidated)
+	deps.Tangle.Events.ReceivedNewMessage.Detach(onReceivedNewMessage)
+	deps.Tangle.Events.MessageSolid.Detach(onMessageSolid)
+	deps.Tangle.Events.MessageReferenced.Detach(onMessageReferenced)
+}
+
+func messageTimeline(c echo.Context) (*messageTimelineResponse, error) {
+
+	if timelineStore == nil {
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "message timeline is disabled")
+	}
+
+	messageID, err := restapi.ParseMessageIDParam(c)
+	if err != nil {
+		return nil, err
+	}
+
+	tl, exists := timelineStore.Timeline(messageID)
+	if !exists {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "no timeline recorded for message: %s", messageID.ToHex())
+	}
+
+	resp := &messageTimelineResponse{
+		MessageID: messageID.ToHex(),
+		Stages:    make([]*messageTimelineStage, 0, len(tl.Stages)),
+	}
+
+	for i, stage := range tl.Stages {
+		var sincePrevious time.Duration
+		if i > 0 {
+			sincePrevious = stage.Time.Sub(tl.Stages[i-1].Time)
+		}
+
+		resp.Stages = append(resp.Stages, &messageTimelineStage{
+			Stage:         stage.Stage.String(),
+			Timestamp:     stage.Time.Format(time.RFC3339Nano),
+			SincePrevious: float64(sincePrevious.Microseconds()) / 1000,
+		})
+	}
+
+	if len(tl.Stages) > 1 {
+		resp.Total = float64(tl.Stages[len(tl.Stages)-1].Time.Sub(tl.Stages[0].Time).Microseconds()) / 1000
+	}
+
+	return resp, nil
+}
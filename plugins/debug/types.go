@@ -2,6 +2,8 @@ package debug
 
 import (
 	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/restapi"
 	restapiv2 "github.com/gohornet/hornet/plugins/restapi/v2"
 )
 
@@ -21,8 +23,44 @@ type computeWhiteFlagMutationsResponse struct {
 	MerkleTreeHash string `json:"merkleTreeHash"`
 }
 
+// whiteFlagMessage defines a message referenced by a milestone in the response of a GET debug milestone white flag REST API call.
+type whiteFlagMessage struct {
+	// The hex encoded message ID of the message.
+	MessageID string `json:"messageId"`
+	// The computed ledger inclusion state of the message ("included", "conflicting" or "noTransaction").
+	LedgerInclusionState string `json:"ledgerInclusionState"`
+	// The computed reason why the transaction of the message is conflicting.
+	ConflictReason *storage.Conflict `json:"conflictReason,omitempty"`
+	// The ledger inclusion state stored in the metadata of the message, if it differs from the computed one.
+	StoredLedgerInclusionState string `json:"storedLedgerInclusionState,omitempty"`
+	// The conflict reason stored in the metadata of the message, if it differs from the computed one.
+	StoredConflictReason *storage.Conflict `json:"storedConflictReason,omitempty"`
+}
+
+// milestoneWhiteFlagResponse defines the response of a GET debug milestone white flag REST API call.
+type milestoneWhiteFlagResponse struct {
+	// The index of the milestone.
+	Index milestone.Index `json:"index"`
+	// The hex encoded ID of the message containing the milestone.
+	MessageID string `json:"messageId"`
+	// The timestamp of the milestone.
+	Timestamp uint64 `json:"timestamp"`
+	// The messages referenced by the milestone in white flag order.
+	Messages []*whiteFlagMessage `json:"messages"`
+	// The hex encoded merkle tree hash as a result of the white flag computation.
+	MerkleTreeHash string `json:"merkleTreeHash"`
+	// The hex encoded inclusion merkle proof of the milestone payload.
+	InclusionMerkleProof string `json:"inclusionMerkleProof"`
+	// Whether the computed merkle tree hash matches the inclusion merkle proof of the milestone.
+	MerkleTreeHashValid bool `json:"merkleTreeHashValid"`
+}
+
 // outputIDsResponse defines the response of a GET debug outputs REST API call.
 type outputIDsResponse struct {
+	// The maximum count of results that are returned by the node.
+	PageSize uint32 `json:"pageSize"`
+	// The cursor to use for getting the next results.
+	Cursor *string `json:"cursor,omitempty"`
 	// The output IDs (transaction hash + output index) of the outputs.
 	OutputIDs []string `json:"outputIds"`
 }
@@ -69,10 +107,40 @@ type request struct {
 
 // requestsResponse defines the response of a GET debug requests REST API call.
 type requestsResponse struct {
+	// The maximum count of results that are returned by the node.
+	PageSize uint32 `json:"pageSize"`
+	// The cursor to use for getting the next results.
+	Cursor *string `json:"cursor,omitempty"`
 	// The pending requests of the node.
 	Requests []*request `json:"requests"`
 }
 
+// auditLogResponse defines the response of a GET debug audit log REST API call.
+type auditLogResponse struct {
+	// The maximum count of results that are returned by the node.
+	PageSize uint32 `json:"pageSize"`
+	// The cursor to use for getting the next results.
+	Cursor *string `json:"cursor,omitempty"`
+	// The recorded calls, ordered by time.
+	Entries []*restapi.AuditLogEntry `json:"entries"`
+}
+
+// emitQueueEntry defines the queued emissions of a priority.
+type emitQueueEntry struct {
+	// The priority of the emissions.
+	Priority string `json:"priority"`
+	// The amount of queued emissions.
+	Count int `json:"count"`
+	// The age of the oldest queued emission in milliseconds.
+	OldestAge int64 `json:"oldestAge"`
+}
+
+// emitQueueResponse defines the response of a GET debug emit queue REST API call.
+type emitQueueResponse struct {
+	// The queued emissions per priority, sorted from the highest to the lowest priority.
+	Priorities []*emitQueueEntry `json:"priorities"`
+}
+
 // entryPoint defines an entryPoint with information about the milestone index of the cone it references.
 type entryPoint struct {
 	// The hex encoded message ID of the message.
@@ -99,3 +167,107 @@ type messageConeResponse struct {
 	// The entry points of the cone of this message.
 	EntryPoints []*entryPoint `json:"entryPoints"`
 }
+
+// messageTimelineStage defines the time a message reached a processing stage.
+type messageTimelineStage struct {
+	// The name of the stage.
+	Stage string `json:"stage"`
+	// The time the message reached the stage.
+	Timestamp string `json:"timestamp"`
+	// The milliseconds since the previous recorded stage.
+	SincePrevious float64 `json:"sincePrevious"`
+}
+
+// messageTimelineResponse defines the response of a GET debug message timeline REST API call.
+type messageTimelineResponse struct {
+	// The hex encoded message ID of the message.
+	MessageID string `json:"messageId"`
+	// The recorded stages in processing order.
+	Stages []*messageTimelineStage `json:"stages"`
+	// The milliseconds between the first and the last recorded stage.
+	Total float64 `json:"total"`
+}
+
+// packetCaptureRequest defines the request for a POST debug packet capture REST API call.
+type packetCaptureRequest struct {
+	// The amount of packets to record (default: the configured maximum).
+	Packets *int `json:"packets,omitempty"`
+}
+
+// capturedPacketResponse defines a gossip packet recorded by a packet capture.
+type capturedPacketResponse struct {
+	// Whether the packet was received from ("in") or sent to ("out") the peer.
+	Direction string `json:"direction"`
+	// The type of the packet.
+	Type string `json:"type"`
+	// The size of the packet in bytes, without the header.
+	Size int `json:"size"`
+	// The time the packet was received or sent.
+	Timestamp string `json:"timestamp"`
+	// The hex encoded message ID of a message packet, or the requested message ID of a message request.
+	MessageID string `json:"messageId,omitempty"`
+	// The requested milestone index of a milestone request (0 = latest).
+	MilestoneIndex uint32 `json:"milestoneIndex,omitempty"`
+}
+
+// packetCaptureResponse defines the response of the debug packet capture REST API calls.
+type packetCaptureResponse struct {
+	// The libp2p identifier of the peer.
+	PeerID string `json:"peerId"`
+	// The amount of packets after which the capture stops.
+	Limit int `json:"limit"`
+	// The time the capture was started.
+	StartedAt string `json:"startedAt"`
+	// Whether the limit was reached.
+	Done bool `json:"done"`
+	// The recorded packets in the order they were received or sent.
+	Packets []*capturedPacketResponse `json:"packets"`
+}
+
+// powBenchmarkRequest defines the request for a POST debug PoW benchmark REST API call.
+type powBenchmarkRequest struct {
+	// The highest thread count to benchmark (default: the amount of CPU cores).
+	MaxThreads *int `json:"maxThreads,omitempty"`
+	// The duration of the benchmark of every thread count (default: 5s).
+	Duration *string `json:"duration,omitempty"`
+	// The size of the mined messages in bytes (default: 300).
+	MessageSize *int `json:"messageSize,omitempty"`
+}
+
+// powBenchmarkResult defines the result of the PoW benchmark of a thread count.
+type powBenchmarkResult struct {
+	// The amount of threads used for every message.
+	Threads int `json:"threads"`
+	// The amount of messages which hit the target score.
+	Messages int `json:"messages"`
+	// The duration of the benchmark.
+	Duration string `json:"duration"`
+	// The messages per second which hit the target score.
+	MessagesPerSecond float64 `json:"messagesPerSecond"`
+	// The estimated hashes per second.
+	HashesPerSecond float64 `json:"hashesPerSecond"`
+}
+
+// powBenchmarkSuggestion defines the worker settings suggested by the PoW benchmark.
+type powBenchmarkSuggestion struct {
+	// The suggested amount of parallel running spammers.
+	SpammerWorkers int `json:"spammerWorkers"`
+	// The suggested amount of workers used for calculating PoW when issuing faucet messages.
+	FaucetPoWWorkerCount int `json:"faucetPowWorkerCount"`
+	// The expected messages per second with the suggested settings.
+	MessagesPerSecond float64 `json:"messagesPerSecond"`
+}
+
+// powBenchmarkResponse defines the response of a POST debug PoW benchmark REST API call.
+type powBenchmarkResponse struct {
+	// The PoW type used for local PoW.
+	PoWType string `json:"powType"`
+	// The target score of the mined messages.
+	TargetScore float64 `json:"targetScore"`
+	// The size of the mined messages in bytes.
+	MessageSize int `json:"messageSize"`
+	// The results of the benchmarked thread counts.
+	Results []*powBenchmarkResult `json:"results"`
+	// The suggested worker settings.
+	Suggestion *powBenchmarkSuggestion `json:"suggestion,omitempty"`
+}
@@ -0,0 +1,34 @@
+package expirationwatcher
+
+import (
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gohornet/hornet/pkg/node"
+)
+
+const (
+	// the amount of milestones before the expiration milestone index at which an output is reported as expiring soon.
+	CfgExpirationWatcherLeadMilestones = "expirationWatcher.leadMilestones"
+	// the duration before the expiration unix time at which an output is reported as expiring soon.
+	CfgExpirationWatcherLeadTime = "expirationWatcher.leadTime"
+	// the URLs the expiration events are posted to.
+	CfgExpirationWatcherWebhookURLs = "expirationWatcher.webhooks.urls"
+	// the timeout for posting an expiration event to a webhook.
+	CfgExpirationWatcherWebhookTimeout = "expirationWatcher.webhooks.timeout"
+)
+
+var params = &node.PluginParams{
+	Params: map[string]*flag.FlagSet{
+		"nodeConfig": func() *flag.FlagSet {
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.Int(CfgExpirationWatcherLeadMilestones, 60, "the amount of milestones before the expiration milestone index at which an output is reported as expiring soon")
+			fs.Duration(CfgExpirationWatcherLeadTime, 10*time.Minute, "the duration before the expiration unix time at which an output is reported as expiring soon")
+			fs.StringSlice(CfgExpirationWatcherWebhookURLs, nil, "the URLs the expiration events are posted to")
+			fs.Duration(CfgExpirationWatcherWebhookTimeout, 5*time.Second, "the timeout for posting an expiration event to a webhook")
+			return fs
+		}(),
+	},
+	Masked: nil,
+}
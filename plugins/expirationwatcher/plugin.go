@@ -0,0 +1,175 @@
+package expirationwatcher
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/dig"
+
+	"github.com/gohornet/hornet/pkg/expiration"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/model/syncmanager"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/node"
+	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/gohornet/hornet/pkg/tangle"
+	"github.com/iotaledger/hive.go/configuration"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/workerpool"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// the amount of expiration events which are queued to be posted to the webhooks.
+	webhookQueueSize = 10000
+)
+
+func init() {
+	Plugin = &node.Plugin{
+		Status: node.StatusDisabled,
+		Pluggable: node.Pluggable{
+			Name:      "ExpirationWatcher",
+			DepsFunc:  func(cDeps dependencies) { deps = cDeps },
+			Params:    params,
+			Provide:   provide,
+			Configure: configure,
+			Run:       run,
+		},
+	}
+}
+
+var (
+	Plugin *node.Plugin
+	deps   dependencies
+
+	webhookWorkerPool *workerpool.WorkerPool
+
+	// Closures
+	onLedgerUpdated             *events.Closure
+	onConfirmedMilestoneChanged *events.Closure
+	onExpiringSoon              *events.Closure
+	onExpired                   *events.Closure
+)
+
+type dependencies struct {
+	dig.In
+	NodeConfig        *configuration.Configuration `name:"nodeConfig"`
+	Storage           *storage.Storage
+	SyncManager       *syncmanager.SyncManager
+	UTXOManager       *utxo.Manager
+	Tangle            *tangle.Tangle
+	ExpirationWatcher *expiration.Watcher
+	Bech32HRP         iotago.NetworkPrefix `name:"bech32HRP"`
+}
+
+func provide(c *dig.Container) {
+
+	type watcherDeps struct {
+		dig.In
+		NodeConfig *configuration.Configuration `name:"nodeConfig"`
+	}
+
+	if err := c.Provide(func(deps watcherDeps) *expiration.Watcher {
+		return expiration.NewWatcher(
+			milestone.Index(deps.NodeConfig.Int(CfgExpirationWatcherLeadMilestones)),
+			deps.NodeConfig.Duration(CfgExpirationWatcherLeadTime),
+		)
+	}); err != nil {
+		Plugin.LogPanic(err)
+	}
+}
+
+func configure() {
+
+	loadUnspentOutputs()
+
+	webhooks := newWebhooks(deps.NodeConfig.Strings(CfgExpirationWatcherWebhookURLs), deps.NodeConfig.Duration(CfgExpirationWatcherWebhookTimeout))
+
+	webhookWorkerPool = workerpool.New(func(task workerpool.Task) {
+		webhooks.post(task.Param(0).(*expiration.Notification))
+		task.Return(nil)
+	}, workerpool.WorkerCount(1), workerpool.QueueSize(webhookQueueSize))
+
+	configureEvents()
+}
+
+func run() {
+	if err := Plugin.Daemon().BackgroundWorker("ExpirationWatcher", func(ctx context.Context) {
+		Plugin.LogInfo("Starting ExpirationWatcher ... done")
+		attachEvents()
+		webhookWorkerPool.Start()
+		<-ctx.Done()
+		Plugin.LogInfo("Stopping ExpirationWatcher ...")
+		detachEvents()
+		webhookWorkerPool.StopAndWait()
+		Plugin.LogInfo("Stopping ExpirationWatcher ... done")
+	}, shutdown.PriorityExpirationWatcher); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+}
+
+// loadUnspentOutputs adds the unspent outputs with an expiration unlock condition to the watcher.
+// Outputs which already expired while the node was offline are not reported.
+func loadUnspentOutputs() {
+	deps.UTXOManager.ReadLockLedger()
+	defer deps.UTXOManager.ReadUnlockLedger()
+
+	ts := time.Now()
+
+	if err := deps.UTXOManager.ForEachUnspentOutput(func(output *utxo.Output) bool {
+		deps.ExpirationWatcher.AddOutput(output)
+		return true
+	}); err != nil {
+		Plugin.LogPanicf("loading unspent outputs failed: %s", err)
+	}
+
+	confirmedMilestoneIndex := deps.SyncManager.ConfirmedMilestoneIndex()
+	if cachedMilestone := deps.Storage.CachedMilestoneOrNil(confirmedMilestoneIndex); cachedMilestone != nil { // milestone +1
+		expired := deps.ExpirationWatcher.RemoveExpired(confirmedMilestoneIndex, uint32(cachedMilestone.Milestone().Timestamp.Unix()))
+		cachedMilestone.Release(true) // milestone -1
+
+		if expired > 0 {
+			Plugin.LogInfof("skipped %d outputs which already expired at milestone %d", expired, confirmedMilestoneIndex)
+		}
+	}
+
+	Plugin.LogInfof("watching %d outputs with an expiration unlock condition, took %v", deps.ExpirationWatcher.Count(), time.Since(ts).Truncate(time.Millisecond))
+}
+
+func configureEvents() {
+	onLedgerUpdated = events.NewClosure(func(_ milestone.Index, newOutputs utxo.Outputs, newSpents utxo.Spents) {
+		deps.ExpirationWatcher.ApplyLedgerUpdate(newOutputs, newSpents)
+	})
+
+	onConfirmedMilestoneChanged = events.NewClosure(func(cachedMilestone *storage.CachedMilestone) {
+		defer cachedMilestone.Release(true) // milestone -1
+
+		ms := cachedMilestone.Milestone()
+		deps.ExpirationWatcher.CheckMilestone(ms.Index, uint32(ms.Timestamp.Unix()))
+	})
+
+	onExpiringSoon = events.NewClosure(func(notification *expiration.Notification) {
+		Plugin.LogDebugf("output %s is expiring soon", notification.JSON(deps.Bech32HRP).OutputID)
+		webhookWorkerPool.TrySubmit(notification)
+	})
+
+	onExpired = events.NewClosure(func(notification *expiration.Notification) {
+		Plugin.LogDebugf("output %s expired at milestone %d", notification.JSON(deps.Bech32HRP).OutputID, notification.ConfirmedMilestoneIndex)
+		webhookWorkerPool.TrySubmit(notification)
+	})
+}
+
+func attachEvents() {
+	deps.Tangle.Events.LedgerUpdated.Attach(onLedgerUpdated)
+	deps.Tangle.Events.ConfirmedMilestoneChanged.Attach(onConfirmedMilestoneChanged)
+	deps.ExpirationWatcher.Events.ExpiringSoon.Attach(onExpiringSoon)
+	deps.ExpirationWatcher.Events.Expired.Attach(onExpired)
+}
+
+func detachEvents() {
+	deps.Tangle.Events.LedgerUpdated.Detach(onLedgerUpdated)
+	deps.Tangle.Events.ConfirmedMilestoneChanged.Detach(onConfirmedMilestoneChanged)
+	deps.ExpirationWatcher.Events.ExpiringSoon.Detach(onExpiringSoon)
+	deps.ExpirationWatcher.Events.Expired.Detach(onExpired)
+}
@@ -0,0 +1,49 @@
+package expirationwatcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/expiration"
+)
+
+// webhooks posts expiration events to the configured URLs.
+type webhooks struct {
+	urls   []string
+	client *http.Client
+}
+
+func newWebhooks(urls []string, timeout time.Duration) *webhooks {
+	return &webhooks{
+		urls:   urls,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// post posts the given notification to all webhooks. Failures are logged and not retried.
+func (w *webhooks) post(notification *expiration.Notification) {
+	if len(w.urls) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(notification.JSON(deps.Bech32HRP))
+	if err != nil {
+		Plugin.LogWarnf("failed to marshal expiration event: %s", err)
+		return
+	}
+
+	for _, url := range w.urls {
+		res, err := w.client.Post(url, "application/json", bytes.NewReader(payload))
+		if err != nil {
+			Plugin.LogWarnf("failed to post expiration event to %s: %s", url, err)
+			continue
+		}
+		_ = res.Body.Close()
+
+		if res.StatusCode < 200 || res.StatusCode >= 300 {
+			Plugin.LogWarnf("failed to post expiration event to %s: status code %d", url, res.StatusCode)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package faucet
 
 import (
+	"time"
+
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 
@@ -12,6 +14,11 @@ func getFaucetInfo(_ echo.Context) (*faucet.FaucetInfoResponse, error) {
 	return deps.Faucet.Info()
 }
 
+func drainFaucet(_ echo.Context) (*faucet.FaucetInfoResponse, error) {
+	deps.Faucet.Drain()
+	return deps.Faucet.Info()
+}
+
 func addFaucetOutputToQueue(c echo.Context) (*faucet.FaucetEnqueueResponse, error) {
 
 	request := &faucetEnqueueRequest{}
@@ -26,3 +33,46 @@ func addFaucetOutputToQueue(c echo.Context) (*faucet.FaucetEnqueueResponse, erro
 
 	return response, nil
 }
+
+func getFaucetStats(c echo.Context) (*faucet.FaucetStatsResponse, error) {
+
+	bucketSize := time.Hour
+	window := 24 * time.Hour
+
+	switch bucket := c.QueryParam(QueryParameterBucket); bucket {
+	case "", "hour":
+	case "day":
+		bucketSize = 24 * time.Hour
+		window = 7 * 24 * time.Hour
+	default:
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid bucket: %s, must be \"hour\" or \"day\"", bucket)
+	}
+
+	if windowParam := c.QueryParam(QueryParameterWindow); len(windowParam) > 0 {
+		var err error
+		window, err = time.ParseDuration(windowParam)
+		if err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid window: %s, error: %s", windowParam, err)
+		}
+		if window <= 0 {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid window: %s, must be positive", windowParam)
+		}
+	}
+
+	if retention := deps.Faucet.PayoutHistoryRetention(); window > retention {
+		if len(c.QueryParam(QueryParameterWindow)) > 0 {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid window: %v, the payouts are only kept for %v", window, retention)
+		}
+		window = retention
+	}
+
+	stats, err := deps.Faucet.Stats(window, bucketSize)
+	if err != nil {
+		if errors.Is(err, faucet.ErrPayoutHistoryDisabled) {
+			return nil, errors.WithMessage(echo.ErrServiceUnavailable, err.Error())
+		}
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "computing faucet statistics failed: %s", err)
+	}
+
+	return stats, nil
+}
@@ -24,10 +24,23 @@ const (
 	CfgFaucetBatchTimeout = "faucet.batchTimeout"
 	// the amount of workers used for calculating PoW when issuing faucet messages.
 	CfgFaucetPoWWorkerCount = "faucet.powWorkerCount"
+	// the period in which a requester is allowed to send one request.
+	CfgFaucetRateLimitPeriod = "faucet.rateLimit.period"
+	// the additional amount of requests a requester is allowed to send at once.
+	CfgFaucetRateLimitBurst = "faucet.rateLimit.burst"
+	// the per network faucet settings which override the global settings.
+	// the profile is selected by the network ID name of the node.
+	CfgFaucetProfiles = "faucet.profiles"
 	// the bind address on which the faucet website can be accessed from
 	CfgFaucetWebsiteBindAddress = "faucet.website.bindAddress"
 	// whether to host the faucet website
 	CfgFaucetWebsiteEnabled = "faucet.website.enabled"
+	// whether the faucet stops accepting new requests and pays out the accepted requests before the node shuts down.
+	CfgFaucetDrainOnShutdown = "faucet.drain.onShutdown"
+	// the maximum duration to wait for the accepted requests to be confirmed on shutdown.
+	CfgFaucetDrainTimeout = "faucet.drain.timeout"
+	// how long the confirmed payouts are kept for the statistics, which is also the maximum window of the statistics.
+	CfgFaucetStatsRetention = "faucet.stats.retention"
 )
 
 var params = &node.PluginParams{
@@ -41,8 +54,13 @@ var params = &node.PluginParams{
 			fs.String(CfgFaucetTagMessage, "HORNET FAUCET", "the faucet transaction tag payload")
 			fs.Duration(CfgFaucetBatchTimeout, 2*time.Second, "the maximum duration for collecting faucet batches")
 			fs.Int(CfgFaucetPoWWorkerCount, 0, "the amount of workers used for calculating PoW when issuing faucet messages")
+			fs.Duration(CfgFaucetRateLimitPeriod, 5*time.Minute, "the period in which a requester is allowed to send one request")
+			fs.Int(CfgFaucetRateLimitBurst, 10, "the additional amount of requests a requester is allowed to send at once")
 			fs.String(CfgFaucetWebsiteBindAddress, "localhost:8091", "the bind address on which the faucet website can be accessed from")
 			fs.Bool(CfgFaucetWebsiteEnabled, false, "whether to host the faucet website")
+			fs.Bool(CfgFaucetDrainOnShutdown, true, "whether the faucet stops accepting new requests and pays out the accepted requests before the node shuts down")
+			fs.Duration(CfgFaucetDrainTimeout, 1*time.Minute, "the maximum duration to wait for the accepted requests to be confirmed on shutdown")
+			fs.Duration(CfgFaucetStatsRetention, 30*24*time.Hour, "how long the confirmed payouts are kept for the statistics, which is also the maximum window of the statistics")
 			return fs
 		}(),
 	},
@@ -5,6 +5,7 @@ import (
 	"crypto/ed25519"
 	"fmt"
 	"net/http"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -13,10 +14,11 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/pkg/errors"
 	"go.uber.org/dig"
-	"golang.org/x/time/rate"
 
 	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/database"
 	"github.com/gohornet/hornet/pkg/indexer"
+	"github.com/gohornet/hornet/pkg/metrics"
 	"github.com/gohornet/hornet/pkg/model/faucet"
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/model/syncmanager"
@@ -46,6 +48,28 @@ const (
 	// RouteFaucetEnqueue is the route to tell the faucet to pay out some funds to the given address.
 	// POST enqueues a new request.
 	RouteFaucetEnqueue = "/enqueue"
+
+	// RouteFaucetDrain is the route to put the faucet into drain mode.
+	// POST stops accepting new requests, the already accepted requests are still paid out.
+	RouteFaucetDrain = "/drain"
+
+	// RouteFaucetStats is the route to get statistics about the confirmed payouts.
+	// GET returns the payouts and unique requesters within the window, bucketed by hour or day.
+	// Query parameters: "bucket" ("hour" or "day", default "hour"), "window" (e.g. "48h", default "24h" for hourly and "168h" for daily buckets).
+	RouteFaucetStats = "/stats"
+)
+
+const (
+	// QueryParameterBucket is used to define the size of the buckets of the statistics ("hour" or "day").
+	QueryParameterBucket = "bucket"
+
+	// QueryParameterWindow is used to define the duration before now the statistics are computed for (e.g. "24h").
+	QueryParameterWindow = "window"
+)
+
+const (
+	// the maximum duration to wait for the drain on shutdown, so the faucet is not killed by the shutdown handler.
+	maxDrainTimeout = 4 * time.Minute
 )
 
 func init() {
@@ -66,6 +90,10 @@ var (
 	Plugin *node.Plugin
 	deps   dependencies
 
+	// the metric series of the faucet on the dashboard
+	balanceSeries         *metrics.CustomMetricSeries
+	pendingRequestsSeries *metrics.CustomMetricSeries
+
 	// Closures
 	onMilestoneConfirmed *events.Closure
 )
@@ -73,11 +101,14 @@ var (
 type dependencies struct {
 	dig.In
 	NodeConfig            *configuration.Configuration `name:"nodeConfig"`
+	NetworkIDName         string                       `name:"networkIdName"`
 	RestAPIBindAddress    string                       `name:"restAPIBindAddress"`
 	FaucetAllowedAPIRoute restapi.AllowedRoute         `name:"faucetAllowedAPIRoute"`
+	FaucetSettings        *settings
 	Faucet                *faucet.Faucet
 	Tangle                *tangle.Tangle
 	ShutdownHandler       *shutdown.ShutdownHandler
+	CustomMetrics         *metrics.CustomMetrics
 }
 
 func provide(c *dig.Container) {
@@ -103,6 +134,23 @@ func provide(c *dig.Container) {
 	faucetAddress := iotago.Ed25519AddressFromPubKey(privateKey.Public().(ed25519.PublicKey))
 	faucetSigner := iotago.NewInMemoryAddressSigner(iotago.NewAddressKeysForEd25519Address(&faucetAddress, privateKey))
 
+	type settingsDeps struct {
+		dig.In
+		NodeConfig    *configuration.Configuration `name:"nodeConfig"`
+		NetworkIDName string                       `name:"networkIdName"`
+	}
+
+	// the settings are loaded once and shared by the faucet and the routes
+	if err := c.Provide(func(deps settingsDeps) *settings {
+		faucetSettings, err := loadSettings(deps.NodeConfig, deps.NetworkIDName)
+		if err != nil {
+			Plugin.LogPanic(err)
+		}
+		return faucetSettings
+	}); err != nil {
+		Plugin.LogPanic(err)
+	}
+
 	type faucetDeps struct {
 		dig.In
 		Storage                   *storage.Storage
@@ -111,15 +159,26 @@ func provide(c *dig.Container) {
 		UTXOManager               *utxo.Manager
 		Indexer                   *indexer.Indexer
 		NodeConfig                *configuration.Configuration `name:"nodeConfig"`
+		DatabasePath              string                       `name:"databasePath"`
+		DatabaseEngine            database.Engine              `name:"databaseEngine"`
 		NetworkID                 uint64                       `name:"networkId"`
+		NetworkIDName             string                       `name:"networkIdName"`
 		DeSerializationParameters *iotago.DeSerializationParameters
 		BelowMaxDepth             int                  `name:"belowMaxDepth"`
 		Bech32HRP                 iotago.NetworkPrefix `name:"bech32HRP"`
 		TipSelector               *tipselect.TipSelector
 		MessageProcessor          *gossip.MessageProcessor
+		FaucetSettings            *settings
 	}
 
 	if err := c.Provide(func(deps faucetDeps) *faucet.Faucet {
+		faucetSettings := deps.FaucetSettings
+
+		payoutHistoryStore, err := database.StoreWithDefaultSettings(filepath.Join(deps.DatabasePath, "faucet"), true, deps.DatabaseEngine)
+		if err != nil {
+			Plugin.LogPanic(err)
+		}
+
 		return faucet.New(
 			Plugin.Daemon(),
 			deps.Storage,
@@ -133,16 +192,21 @@ func provide(c *dig.Container) {
 			faucetSigner,
 			deps.TipSelector.SelectNonLazyTips,
 			deps.PowHandler,
-			deps.MessageProcessor.Emit,
+			func(msg *storage.Message) error {
+				// faucet transactions should not be delayed by bulk emissions
+				return deps.MessageProcessor.EmitWithPriority(msg, gossip.EmitPriorityHigh)
+			},
 			faucet.WithLogger(Plugin.Logger()),
 			faucet.WithHRPNetworkPrefix(deps.Bech32HRP),
-			faucet.WithAmount(uint64(deps.NodeConfig.Int64(CfgFaucetAmount))),
-			faucet.WithSmallAmount(uint64(deps.NodeConfig.Int64(CfgFaucetSmallAmount))),
-			faucet.WithMaxAddressBalance(uint64(deps.NodeConfig.Int64(CfgFaucetMaxAddressBalance))),
+			faucet.WithAmount(faucetSettings.amount),
+			faucet.WithSmallAmount(faucetSettings.smallAmount),
+			faucet.WithMaxAddressBalance(faucetSettings.maxAddressBalance),
 			faucet.WithMaxOutputCount(deps.NodeConfig.Int(CfgFaucetMaxOutputCount)),
-			faucet.WithTagMessage(deps.NodeConfig.String(CfgFaucetTagMessage)),
+			faucet.WithTagMessage(faucetSettings.tagMessage),
 			faucet.WithBatchTimeout(deps.NodeConfig.Duration(CfgFaucetBatchTimeout)),
 			faucet.WithPowWorkerCount(deps.NodeConfig.Int(CfgFaucetPoWWorkerCount)),
+			faucet.WithPayoutHistoryStore(payoutHistoryStore),
+			faucet.WithPayoutHistoryRetention(deps.NodeConfig.Duration(CfgFaucetStatsRetention)),
 		)
 	}); err != nil {
 		Plugin.LogPanic(err)
@@ -160,11 +224,20 @@ func configure() {
 		Plugin.LogPanic("Indexer plugin needs to be enabled to use the Faucet plugin")
 	}
 
+	faucetSettings := deps.FaucetSettings
+	if faucetSettings.profileName != "" {
+		Plugin.LogInfof("using faucet profile for network \"%s\"", faucetSettings.profileName)
+	}
+
 	routeGroup := restapiv2.AddPlugin("faucet/v1")
 
 	allowedRoutes := map[string][]string{
 		http.MethodGet: {
 			"/api/plugins/faucet/v1/info",
+			"/api/plugins/faucet/v1/stats",
+		},
+		http.MethodPost: {
+			"/api/plugins/faucet/v1/drain",
 		},
 	}
 
@@ -185,27 +258,11 @@ func configure() {
 		return false
 	}
 
-	rateLimiterConfig := middleware.RateLimiterConfig{
-		Skipper: rateLimiterSkipper,
-		Store: middleware.NewRateLimiterMemoryStoreWithConfig(
-			middleware.RateLimiterMemoryStoreConfig{
-				Rate:      rate.Limit(1 / 300.0), // 1 request every 5 minutes
-				Burst:     10,                    // additional burst of 10 requests
-				ExpiresIn: 5 * time.Minute,
-			},
-		),
-		IdentifierExtractor: func(ctx echo.Context) (string, error) {
-			id := ctx.RealIP()
-			return id, nil
-		},
-		ErrorHandler: func(context echo.Context, err error) error {
-			return context.JSON(http.StatusForbidden, nil)
-		},
-		DenyHandler: func(context echo.Context, identifier string, err error) error {
-			return context.JSON(http.StatusTooManyRequests, nil)
-		},
+	rateLimiter, err := restapi.RateLimiter(rateLimiterSkipper, faucetSettings.rateLimitPeriod, faucetSettings.rateLimitBurst, restapi.RateLimitIdentifierIP)
+	if err != nil {
+		Plugin.LogPanicf("invalid faucet rate limit: %s", err)
 	}
-	routeGroup.Use(middleware.RateLimiterWithConfig(rateLimiterConfig))
+	routeGroup.Use(rateLimiter)
 
 	routeGroup.GET(RouteFaucetInfo, func(c echo.Context) error {
 		resp, err := getFaucetInfo(c)
@@ -242,17 +299,98 @@ func configure() {
 		return restapi.JSONResponse(c, http.StatusAccepted, resp)
 	})
 
+	routeGroup.POST(RouteFaucetDrain, func(c echo.Context) error {
+		resp, err := drainFaucet(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteFaucetStats, func(c echo.Context) error {
+		resp, err := getFaucetStats(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	if err := Plugin.Daemon().BackgroundWorker("Close Faucet database", func(ctx context.Context) {
+		<-ctx.Done()
+
+		Plugin.LogInfo("Syncing Faucet database to disk...")
+		if err := deps.Faucet.CloseDatabase(); err != nil {
+			Plugin.LogPanicf("Syncing Faucet database to disk... failed: %s", err)
+		}
+		Plugin.LogInfo("Syncing Faucet database to disk... done")
+	}, shutdown.PriorityCloseDatabase); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+
+	configureCustomMetrics()
 	configureEvents()
 }
 
+func configureCustomMetrics() {
+	var err error
+	if balanceSeries, err = deps.CustomMetrics.Register("faucet", "balance", "Faucet Balance", "IOTA"); err != nil {
+		Plugin.LogPanic(err)
+	}
+	if pendingRequestsSeries, err = deps.CustomMetrics.Register("faucet", "pendingRequests", "Pending Faucet Requests", "requests"); err != nil {
+		Plugin.LogPanic(err)
+	}
+}
+
+// recordCustomMetrics publishes the current balance and the pending requests of the faucet to the dashboard.
+func recordCustomMetrics() {
+	info, err := deps.Faucet.Info()
+	if err != nil {
+		return
+	}
+
+	balanceSeries.Record(float64(info.Balance))
+	pendingRequestsSeries.Record(float64(info.PendingRequests))
+}
+
 func run() {
+	drainOnShutdown := deps.NodeConfig.Bool(CfgFaucetDrainOnShutdown)
+	drainTimeout := deps.NodeConfig.Duration(CfgFaucetDrainTimeout)
+	if drainTimeout > maxDrainTimeout {
+		Plugin.LogWarnf("%s is limited to %v", CfgFaucetDrainTimeout, maxDrainTimeout)
+		drainTimeout = maxDrainTimeout
+	}
+
 	// create a background worker that handles the enqueued faucet requests
 	if err := Plugin.Daemon().BackgroundWorker("Faucet", func(ctx context.Context) {
 		attachEvents()
-		if err := deps.Faucet.RunFaucetLoop(ctx, nil); err != nil && common.IsCriticalError(err) != nil {
-			deps.ShutdownHandler.SelfShutdown(fmt.Sprintf("faucet plugin hit a critical error: %s", err.Error()))
+		defer detachEvents()
+
+		// the faucet loop has its own context, so it keeps issuing the accepted requests while draining
+		loopCtx, loopCancel := context.WithCancel(context.Background())
+		defer loopCancel()
+
+		loopDone := make(chan struct{})
+		go func() {
+			defer close(loopDone)
+			if err := deps.Faucet.RunFaucetLoop(loopCtx, nil); err != nil && common.IsCriticalError(err) != nil {
+				deps.ShutdownHandler.SelfShutdown(fmt.Sprintf("faucet plugin hit a critical error: %s", err.Error()))
+			}
+		}()
+
+		select {
+		case <-loopDone:
+			return
+		case <-ctx.Done():
 		}
-		detachEvents()
+
+		if drainOnShutdown {
+			drainOnShutdownAndWait(drainTimeout, loopDone)
+		}
+
+		loopCancel()
+		<-loopDone
 	}, shutdown.PriorityFaucet); err != nil {
 		Plugin.LogPanicf("failed to start worker: %s", err)
 	}
@@ -278,11 +416,47 @@ func run() {
 	}
 }
 
+// drainOnShutdownAndWait puts the faucet into drain mode and waits until all accepted requests
+// were confirmed, the timeout was reached or the faucet loop stopped.
+func drainOnShutdownAndWait(timeout time.Duration, loopDone <-chan struct{}) {
+	drained := deps.Faucet.Drain()
+
+	timeoutTimer := time.NewTimer(timeout)
+	defer timeoutTimer.Stop()
+
+	progressTicker := time.NewTicker(10 * time.Second)
+	defer progressTicker.Stop()
+
+	for {
+		select {
+		case <-drained:
+			return
+
+		case <-loopDone:
+			Plugin.LogWarn("faucet stopped before all accepted requests were confirmed")
+			return
+
+		case <-timeoutTimer.C:
+			if info, err := deps.Faucet.Info(); err == nil {
+				Plugin.LogWarnf("draining the faucet timed out, %d accepted requests were not confirmed", info.PendingRequests)
+			}
+			return
+
+		case <-progressTicker.C:
+			if info, err := deps.Faucet.Info(); err == nil {
+				Plugin.LogInfof("draining the faucet, waiting for %d accepted requests...", info.PendingRequests)
+			}
+		}
+	}
+}
+
 func configureEvents() {
 	onMilestoneConfirmed = events.NewClosure(func(confirmation *whiteflag.Confirmation) {
 		if err := deps.Faucet.ApplyConfirmation(confirmation); err != nil && common.IsCriticalError(err) != nil {
 			deps.ShutdownHandler.SelfShutdown(fmt.Sprintf("faucet plugin hit a critical error: %s", err.Error()))
+			return
 		}
+		recordCustomMetrics()
 	})
 }
 
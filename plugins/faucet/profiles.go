@@ -0,0 +1,101 @@
+package faucet
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/configuration"
+)
+
+// profile holds faucet settings for a specific network.
+// Settings which are not set in the profile fall back to the global faucet settings.
+type profile struct {
+	Amount            *int64         `json:"amount" koanf:"amount"`
+	SmallAmount       *int64         `json:"smallAmount" koanf:"smallAmount"`
+	MaxAddressBalance *int64         `json:"maxAddressBalance" koanf:"maxAddressBalance"`
+	TagMessage        *string        `json:"tagMessage" koanf:"tagMessage"`
+	RateLimitPeriod   *time.Duration `json:"rateLimitPeriod" koanf:"rateLimitPeriod"`
+	RateLimitBurst    *int           `json:"rateLimitBurst" koanf:"rateLimitBurst"`
+}
+
+// settings are the effective faucet settings after applying the profile of the network.
+type settings struct {
+	// the name of the applied profile, empty if no profile matched the network.
+	profileName       string
+	amount            uint64
+	smallAmount       uint64
+	maxAddressBalance uint64
+	tagMessage        string
+	rateLimitPeriod   time.Duration
+	rateLimitBurst    int
+}
+
+// loadSettings reads the faucet settings from the config and applies the profile
+// that matches the given network ID name, if any.
+func loadSettings(nodeConfig *configuration.Configuration, networkIDName string) (*settings, error) {
+
+	// the amounts are checked before they are converted, so negative values are not wrapped around
+	amount := nodeConfig.Int64(CfgFaucetAmount)
+	smallAmount := nodeConfig.Int64(CfgFaucetSmallAmount)
+	maxAddressBalance := nodeConfig.Int64(CfgFaucetMaxAddressBalance)
+
+	s := &settings{
+		tagMessage:      nodeConfig.String(CfgFaucetTagMessage),
+		rateLimitPeriod: nodeConfig.Duration(CfgFaucetRateLimitPeriod),
+		rateLimitBurst:  nodeConfig.Int(CfgFaucetRateLimitBurst),
+	}
+
+	profiles := make(map[string]*profile)
+	if err := nodeConfig.Unmarshal(CfgFaucetProfiles, &profiles); err != nil {
+		return nil, errors.Wrap(err, "parsing faucet profiles failed")
+	}
+
+	// the config keys are case-insensitive
+	for name, p := range profiles {
+		if !strings.EqualFold(name, networkIDName) {
+			continue
+		}
+
+		s.profileName = networkIDName
+		if p.Amount != nil {
+			amount = *p.Amount
+		}
+		if p.SmallAmount != nil {
+			smallAmount = *p.SmallAmount
+		}
+		if p.MaxAddressBalance != nil {
+			maxAddressBalance = *p.MaxAddressBalance
+		}
+		if p.TagMessage != nil {
+			s.tagMessage = *p.TagMessage
+		}
+		if p.RateLimitPeriod != nil {
+			s.rateLimitPeriod = *p.RateLimitPeriod
+		}
+		if p.RateLimitBurst != nil {
+			s.rateLimitBurst = *p.RateLimitBurst
+		}
+		break
+	}
+
+	if amount <= 0 {
+		return nil, errors.New("faucet amount must be greater than zero")
+	}
+	if smallAmount <= 0 {
+		return nil, errors.New("faucet small amount must be greater than zero")
+	}
+	if maxAddressBalance <= 0 {
+		return nil, errors.New("faucet max address balance must be greater than zero")
+	}
+	if s.rateLimitPeriod <= 0 {
+		return nil, errors.New("faucet rate limit period must be greater than zero")
+	}
+
+	s.amount = uint64(amount)
+	s.smallAmount = uint64(smallAmount)
+	s.maxAddressBalance = uint64(maxAddressBalance)
+
+	return s, nil
+}
@@ -0,0 +1,63 @@
+package faucet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/configuration"
+)
+
+func newTestConfig(t *testing.T, values map[string]interface{}) *configuration.Configuration {
+	cfg := configuration.New()
+	require.NoError(t, cfg.LoadFlagSet(params.Params["nodeConfig"]))
+	for key, value := range values {
+		require.NoError(t, cfg.Set(key, value))
+	}
+	return cfg
+}
+
+func TestLoadSettingsProfile(t *testing.T) {
+	cfg := newTestConfig(t, map[string]interface{}{
+		"faucet.profiles.alphanet.amount":          int64(500),
+		"faucet.profiles.alphanet.tagMessage":      "ALPHANET FAUCET",
+		"faucet.profiles.alphanet.rateLimitPeriod": "1m",
+		"faucet.profiles.betanet.amount":           int64(700),
+	})
+
+	// the profile is selected case-insensitive and only overrides the given settings
+	s, err := loadSettings(cfg, "AlphaNet")
+	require.NoError(t, err)
+	require.Equal(t, "AlphaNet", s.profileName)
+	require.Equal(t, uint64(500), s.amount)
+	require.Equal(t, uint64(1000000), s.smallAmount)
+	require.Equal(t, uint64(20000000), s.maxAddressBalance)
+	require.Equal(t, "ALPHANET FAUCET", s.tagMessage)
+	require.Equal(t, time.Minute, s.rateLimitPeriod)
+	require.Equal(t, 10, s.rateLimitBurst)
+
+	// the global settings are used if no profile matches the network
+	s, err = loadSettings(cfg, "mainnet")
+	require.NoError(t, err)
+	require.Empty(t, s.profileName)
+	require.Equal(t, uint64(10000000), s.amount)
+	require.Equal(t, "HORNET FAUCET", s.tagMessage)
+	require.Equal(t, 5*time.Minute, s.rateLimitPeriod)
+}
+
+func TestLoadSettingsInvalid(t *testing.T) {
+	for name, values := range map[string]map[string]interface{}{
+		"negative amount":              {"faucet.amount": int64(-1)},
+		"zero small amount":            {"faucet.smallAmount": int64(0)},
+		"negative max address balance": {"faucet.maxAddressBalance": int64(-20000000)},
+		"negative profile amount":      {"faucet.profiles.alphanet.amount": int64(-500)},
+		"zero profile rate limit":      {"faucet.profiles.alphanet.rateLimitPeriod": "0s"},
+		"invalid profile":              {"faucet.profiles.alphanet.amount": "many"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := loadSettings(newTestConfig(t, values), "alphanet")
+			require.Error(t, err)
+		})
+	}
+}
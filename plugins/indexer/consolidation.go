@@ -0,0 +1,206 @@
+package indexer
+
+import (
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/indexer"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/restapi"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// consolidationGroup is a set of outputs that can be consolidated within a single transaction.
+type consolidationGroup struct {
+	outputs      []*utxo.Output
+	amount       uint64
+	nativeTokens iotago.NativeTokenSum
+	// the amount of native tokens of all inputs, used to respect the native tokens limit of a transaction.
+	inputNativeTokensCount int
+}
+
+// returns the native tokens of the given output or nil if the output can't hold native tokens.
+func nativeTokensOfOutput(output iotago.Output) iotago.NativeTokens {
+	nativeTokenOutput, ok := output.(iotago.NativeTokenOutput)
+	if !ok {
+		return nil
+	}
+	return nativeTokenOutput.NativeTokenSet()
+}
+
+// nativeTokensCountWith returns the amount of native tokens on the input and output side
+// of the consolidation transaction if the given output was added to the group.
+func (g *consolidationGroup) nativeTokensCountWith(output iotago.Output) (int, int) {
+	nativeTokens := nativeTokensOfOutput(output)
+
+	outputNativeTokensCount := len(g.nativeTokens)
+	for _, nativeToken := range nativeTokens {
+		if _, has := g.nativeTokens[nativeToken.ID]; !has {
+			outputNativeTokensCount++
+		}
+	}
+
+	return g.inputNativeTokensCount + len(nativeTokens), outputNativeTokensCount
+}
+
+// add adds the given output to the group.
+func (g *consolidationGroup) add(output *utxo.Output) {
+	g.outputs = append(g.outputs, output)
+	g.amount += output.Deposit()
+
+	for _, nativeToken := range nativeTokensOfOutput(output.Output()) {
+		g.inputNativeTokensCount++
+
+		sum, has := g.nativeTokens[nativeToken.ID]
+		if !has {
+			sum = new(big.Int)
+			g.nativeTokens[nativeToken.ID] = sum
+		}
+		sum.Add(sum, nativeToken.Amount)
+	}
+}
+
+// consolidatedOutput builds the output that results from consolidating the group to the given address.
+func (g *consolidationGroup) consolidatedOutput(address iotago.Address) *iotago.ExtendedOutput {
+	output := &iotago.ExtendedOutput{
+		Amount: g.amount,
+		Conditions: iotago.UnlockConditions{
+			&iotago.AddressUnlockCondition{Address: address},
+		},
+	}
+
+	for nativeTokenID, amount := range g.nativeTokens {
+		output.NativeTokens = append(output.NativeTokens, &iotago.NativeToken{ID: nativeTokenID, Amount: amount})
+	}
+
+	return output
+}
+
+// planConsolidation groups the given outputs into consolidation groups of at most maxInputs outputs,
+// starting with the smallest outputs. Groups with less than two outputs are omitted.
+func planConsolidation(outputs []*utxo.Output, maxInputs int) []*consolidationGroup {
+
+	sort.SliceStable(outputs, func(i, j int) bool {
+		return outputs[i].Deposit() < outputs[j].Deposit()
+	})
+
+	var groups []*consolidationGroup
+	var group *consolidationGroup
+
+	for _, output := range outputs {
+		if group != nil {
+			inputNativeTokensCount, outputNativeTokensCount := group.nativeTokensCountWith(output.Output())
+			if len(group.outputs) >= maxInputs || inputNativeTokensCount+outputNativeTokensCount > iotago.MaxNativeTokensCount {
+				groups = append(groups, group)
+				group = nil
+			}
+		}
+
+		if group == nil {
+			group = &consolidationGroup{nativeTokens: make(iotago.NativeTokenSum)}
+		}
+		group.add(output)
+	}
+
+	if group != nil {
+		groups = append(groups, group)
+	}
+
+	// a single output doesn't need to be consolidated
+	result := make([]*consolidationGroup, 0, len(groups))
+	for _, g := range groups {
+		if len(g.outputs) < 2 {
+			continue
+		}
+		result = append(result, g)
+	}
+
+	return result
+}
+
+func outputsConsolidation(c echo.Context) (*consolidationResponse, error) {
+
+	address, err := restapi.ParseBech32AddressQueryParam(c, deps.Bech32HRP, QueryParameterAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	maxInputs := iotago.MaxInputsCount
+	if len(c.QueryParam(QueryParameterMaxInputs)) > 0 {
+		value, err := strconv.Atoi(c.QueryParam(QueryParameterMaxInputs))
+		if err != nil || value < 2 || value > iotago.MaxInputsCount {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid %s, must be between 2 and %d", QueryParameterMaxInputs, iotago.MaxInputsCount)
+		}
+		maxInputs = value
+	}
+
+	// only outputs without additional unlock conditions can be consolidated by the owner alone
+	result := deps.Indexer.ExtendedOutputsWithFilters(
+		indexer.ExtendedOutputUnlockableByAddress(address),
+		indexer.ExtendedOutputHasDustReturnCondition(false),
+		indexer.ExtendedOutputHasExpirationCondition(false),
+		indexer.ExtendedOutputHasTimelockCondition(false),
+		indexer.ExtendedOutputPageSize(deps.RestAPILimitsMaxResults),
+	)
+	if result.Error != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "reading outputIDs failed: %s", result.Error)
+	}
+
+	// we need to lock the ledger here to only consider outputs that are still unspent.
+	deps.UTXOManager.ReadLockLedger()
+	defer deps.UTXOManager.ReadUnlockLedger()
+
+	ledgerIndex, err := deps.UTXOManager.ReadLedgerIndexWithoutLocking()
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "reading ledger index failed: %s", err)
+	}
+
+	outputs := make([]*utxo.Output, 0, len(result.OutputIDs))
+	for i := range result.OutputIDs {
+		outputID := result.OutputIDs[i]
+
+		unspent, err := deps.UTXOManager.IsOutputIDUnspentWithoutLocking(&outputID)
+		if err != nil {
+			return nil, errors.WithMessagef(echo.ErrInternalServerError, "reading output spent status failed: %s, error: %s", outputID.ToHex(), err)
+		}
+		if !unspent {
+			// the indexer is not in sync with the ledger yet
+			continue
+		}
+
+		output, err := deps.UTXOManager.ReadOutputByOutputIDWithoutLocking(&outputID)
+		if err != nil {
+			return nil, errors.WithMessagef(echo.ErrInternalServerError, "reading output failed: %s, error: %s", outputID.ToHex(), err)
+		}
+		outputs = append(outputs, output)
+	}
+
+	response := &consolidationResponse{
+		LedgerIndex: ledgerIndex,
+		Items:       make([]string, len(outputs)),
+		Groups:      []*consolidationGroupResponse{},
+	}
+	for i, output := range outputs {
+		response.Items[i] = output.OutputID().ToHex()
+	}
+
+	rentStructure := deps.DeserializationParameters.RentStructure
+	for _, group := range planConsolidation(outputs, maxInputs) {
+		groupResponse := &consolidationGroupResponse{
+			Items:          make([]string, len(group.outputs)),
+			Amount:         group.amount,
+			NativeTokens:   len(group.nativeTokens),
+			StorageDeposit: rentStructure.VByteCost * group.consolidatedOutput(address).VByteCost(rentStructure, nil),
+		}
+		for i, output := range group.outputs {
+			groupResponse.Items[i] = output.OutputID().ToHex()
+		}
+		response.Groups = append(response.Groups, groupResponse)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,322 @@
+package indexer
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/restapi"
+)
+
+const (
+	// graphqlMaxDepth is the maximum nesting depth of the fields in a query.
+	graphqlMaxDepth = 10
+)
+
+// the filters of the GraphQL input types have the same names and formats as the query parameters of the REST routes.
+const graphqlSchemaString = `
+schema {
+	query: Query
+}
+
+type Query {
+	# The outputs with address unlock condition, filtered like the "outputs" route.
+	outputs(filter: OutputFilter, pageSize: Int, cursor: String, sortBy: SortBy, sortOrder: SortOrder): OutputsPage!
+	# The alias outputs, filtered like the "aliases" route.
+	aliases(filter: AliasFilter, pageSize: Int, cursor: String, sortBy: SortBy, sortOrder: SortOrder): OutputsPage!
+	# The NFT outputs, filtered like the "nfts" route.
+	nfts(filter: NFTFilter, pageSize: Int, cursor: String, sortBy: SortBy, sortOrder: SortOrder): OutputsPage!
+	# The foundry outputs, filtered like the "foundries" route.
+	foundries(filter: FoundryFilter, pageSize: Int, cursor: String, sortBy: SortBy, sortOrder: SortOrder): OutputsPage!
+}
+
+enum SortBy {
+	CREATED_AT
+	AMOUNT
+}
+
+enum SortOrder {
+	ASC
+	DESC
+}
+
+type OutputsPage {
+	# The ledger index at which the outputs were available at.
+	ledgerIndex: Int!
+	# The maximum count of results that are returned by the node.
+	pageSize: Int!
+	# The cursor to use for getting the next results.
+	cursor: String
+	# The output IDs of the outputs.
+	items: [String!]!
+}
+
+input OutputFilter {
+	address: String
+	spendableBy: String
+	spendableAt: Int
+	spendableAtMilestone: Int
+	hasDustReturnCondition: Boolean
+	dustReturnAddress: String
+	hasExpirationCondition: Boolean
+	expirationReturnAddress: String
+	expiresBefore: Int
+	expiresAfter: Int
+	expiresBeforeMilestone: Int
+	expiresAfterMilestone: Int
+	hasTimelockCondition: Boolean
+	timelockedBefore: Int
+	timelockedAfter: Int
+	timelockedBeforeMilestone: Int
+	timelockedAfterMilestone: Int
+	sender: String
+	tag: String
+	tagPrefix: String
+	metadataContains: String
+	createdBefore: Int
+	createdAfter: Int
+	# At least one of the alternative filters needs to match.
+	anyOf: [OutputFilter!]
+}
+
+input AliasFilter {
+	stateController: String
+	governor: String
+	issuer: String
+	sender: String
+	metadataContains: String
+	createdBefore: Int
+	createdAfter: Int
+	# At least one of the alternative filters needs to match.
+	anyOf: [AliasFilter!]
+}
+
+input NFTFilter {
+	address: String
+	spendableBy: String
+	spendableAt: Int
+	spendableAtMilestone: Int
+	hasDustReturnCondition: Boolean
+	dustReturnAddress: String
+	hasExpirationCondition: Boolean
+	expirationReturnAddress: String
+	expiresBefore: Int
+	expiresAfter: Int
+	expiresBeforeMilestone: Int
+	expiresAfterMilestone: Int
+	hasTimelockCondition: Boolean
+	timelockedBefore: Int
+	timelockedAfter: Int
+	timelockedBeforeMilestone: Int
+	timelockedAfterMilestone: Int
+	issuer: String
+	sender: String
+	tag: String
+	tagPrefix: String
+	metadataContains: String
+	createdBefore: Int
+	createdAfter: Int
+	# At least one of the alternative filters needs to match.
+	anyOf: [NFTFilter!]
+}
+
+input FoundryFilter {
+	address: String
+	createdBefore: Int
+	createdAfter: Int
+	# At least one of the alternative filters needs to match.
+	anyOf: [FoundryFilter!]
+}
+`
+
+// graphqlFilter backs all filter input types of the schema, the fields that are not part of a type stay nil.
+// The query tags are the names of the corresponding REST query parameters.
+type graphqlFilter struct {
+	Address                   *string          `query:"address"`
+	SpendableBy               *string          `query:"spendableBy"`
+	SpendableAt               *int32           `query:"spendableAt"`
+	SpendableAtMilestone      *int32           `query:"spendableAtMilestone"`
+	HasDustReturnCondition    *bool            `query:"hasDustReturnCondition"`
+	DustReturnAddress         *string          `query:"dustReturnAddress"`
+	HasExpirationCondition    *bool            `query:"hasExpirationCondition"`
+	ExpirationReturnAddress   *string          `query:"expirationReturnAddress"`
+	ExpiresBefore             *int32           `query:"expiresBefore"`
+	ExpiresAfter              *int32           `query:"expiresAfter"`
+	ExpiresBeforeMilestone    *int32           `query:"expiresBeforeMilestone"`
+	ExpiresAfterMilestone     *int32           `query:"expiresAfterMilestone"`
+	HasTimelockCondition      *bool            `query:"hasTimelockCondition"`
+	TimelockedBefore          *int32           `query:"timelockedBefore"`
+	TimelockedAfter           *int32           `query:"timelockedAfter"`
+	TimelockedBeforeMilestone *int32           `query:"timelockedBeforeMilestone"`
+	TimelockedAfterMilestone  *int32           `query:"timelockedAfterMilestone"`
+	StateController           *string          `query:"stateController"`
+	Governor                  *string          `query:"governor"`
+	Issuer                    *string          `query:"issuer"`
+	Sender                    *string          `query:"sender"`
+	Tag                       *string          `query:"tag"`
+	TagPrefix                 *string          `query:"tagPrefix"`
+	MetadataContains          *string          `query:"metadataContains"`
+	CreatedBefore             *int32           `query:"createdBefore"`
+	CreatedAfter              *int32           `query:"createdAfter"`
+	AnyOf                     *[]graphqlFilter `query:"anyOf"`
+}
+
+// values returns the filter as REST query parameters.
+func (f *graphqlFilter) values() url.Values {
+	values := url.Values{}
+	if f == nil {
+		return values
+	}
+
+	filterValue := reflect.ValueOf(f).Elem()
+	for i := 0; i < filterValue.NumField(); i++ {
+		field := filterValue.Field(i)
+		if field.IsNil() {
+			continue
+		}
+
+		name := filterValue.Type().Field(i).Tag.Get("query")
+		switch value := field.Interface().(type) {
+		case *string:
+			values.Set(name, *value)
+		case *int32:
+			values.Set(name, strconv.FormatInt(int64(*value), 10))
+		case *bool:
+			values.Set(name, strconv.FormatBool(*value))
+		case *[]graphqlFilter:
+			for j := range *value {
+				values.Add(name, (*value)[j].values().Encode())
+			}
+		}
+	}
+
+	return values
+}
+
+// graphqlListArgs are the arguments of the list fields of the schema.
+type graphqlListArgs struct {
+	Filter    *graphqlFilter
+	PageSize  *int32
+	Cursor    *string
+	SortBy    *string
+	SortOrder *string
+}
+
+// context returns a context with the arguments as REST query parameters, so they are handled exactly like a request of the route.
+func (args *graphqlListArgs) context() echo.Context {
+	values := args.Filter.values()
+
+	if args.PageSize != nil {
+		values.Set(QueryParameterPageSize, strconv.FormatInt(int64(*args.PageSize), 10))
+	}
+	if args.Cursor != nil {
+		values.Set(QueryParameterCursor, *args.Cursor)
+	}
+	if args.SortBy != nil {
+		switch *args.SortBy {
+		case "AMOUNT":
+			values.Set(QueryParameterSortBy, "amount")
+		default:
+			values.Set(QueryParameterSortBy, "createdAt")
+		}
+	}
+	if args.SortOrder != nil {
+		switch *args.SortOrder {
+		case "DESC":
+			values.Set(QueryParameterSortOrder, "desc")
+		default:
+			values.Set(QueryParameterSortOrder, "asc")
+		}
+	}
+
+	return queryContext(values.Encode())
+}
+
+// graphqlOutputsPage resolves the OutputsPage type.
+type graphqlOutputsPage struct {
+	resp *outputsResponse
+}
+
+func (p *graphqlOutputsPage) LedgerIndex() int32 {
+	return int32(p.resp.LedgerIndex)
+}
+
+func (p *graphqlOutputsPage) PageSize() int32 {
+	return int32(p.resp.PageSize)
+}
+
+func (p *graphqlOutputsPage) Cursor() *string {
+	return p.resp.Cursor
+}
+
+func (p *graphqlOutputsPage) Items() []string {
+	return p.resp.Items
+}
+
+func newGraphqlOutputsPage(resp *outputsResponse, err error) (*graphqlOutputsPage, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &graphqlOutputsPage{resp: resp}, nil
+}
+
+// graphqlResolver resolves the Query type.
+type graphqlResolver struct{}
+
+func (r *graphqlResolver) Outputs(args graphqlListArgs) (*graphqlOutputsPage, error) {
+	return newGraphqlOutputsPage(outputsWithFilter(args.context()))
+}
+
+func (r *graphqlResolver) Aliases(args graphqlListArgs) (*graphqlOutputsPage, error) {
+	return newGraphqlOutputsPage(aliasesWithFilter(args.context()))
+}
+
+func (r *graphqlResolver) Nfts(args graphqlListArgs) (*graphqlOutputsPage, error) {
+	return newGraphqlOutputsPage(nftsWithFilter(args.context()))
+}
+
+func (r *graphqlResolver) Foundries(args graphqlListArgs) (*graphqlOutputsPage, error) {
+	return newGraphqlOutputsPage(foundriesWithFilter(args.context()))
+}
+
+// graphqlRequest defines the request of a POST graphql REST API call.
+type graphqlRequest struct {
+	// The GraphQL query.
+	Query string `json:"query"`
+	// The name of the operation to execute if the query contains several operations.
+	OperationName string `json:"operationName"`
+	// The values of the variables used in the query.
+	Variables map[string]interface{} `json:"variables"`
+}
+
+var graphqlSchema *graphql.Schema
+
+func configureGraphQL() {
+	graphqlSchema = graphql.MustParseSchema(graphqlSchemaString, &graphqlResolver{}, graphql.MaxDepth(graphqlMaxDepth))
+}
+
+func graphqlQuery(c echo.Context) (*graphql.Response, error) {
+	request := &graphqlRequest{}
+
+	switch c.Request().Method {
+	case http.MethodGet:
+		request.Query = c.QueryParam("query")
+		request.OperationName = c.QueryParam("operationName")
+
+	default:
+		if err := c.Bind(request); err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
+		}
+	}
+
+	if len(request.Query) == 0 {
+		return nil, errors.WithMessage(restapi.ErrInvalidParameter, "query is missing")
+	}
+
+	return graphqlSchema.Exec(c.Request().Context(), request.Query, request.OperationName, request.Variables), nil
+}
@@ -0,0 +1,31 @@
+package indexer
+
+import (
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gohornet/hornet/pkg/node"
+)
+
+const (
+	// CfgIndexerCursorRetentionMilestones defines the amount of milestones a paginated query can be continued after it was started.
+	CfgIndexerCursorRetentionMilestones = "indexer.cursorRetentionMilestones"
+	// CfgIndexerWebhooks defines the webhooks that get notified about newly booked outputs matching their filters.
+	// Every webhook consists of a "url", a "route" ("outputs", "aliases", "nfts" or "foundries") and a "query" with the filters of that route.
+	CfgIndexerWebhooks = "indexer.webhooks"
+	// CfgIndexerWebhookTimeout defines the timeout for posting to a webhook.
+	CfgIndexerWebhookTimeout = "indexer.webhookTimeout"
+)
+
+var params = &node.PluginParams{
+	Params: map[string]*flag.FlagSet{
+		"nodeConfig": func() *flag.FlagSet {
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.Int(CfgIndexerCursorRetentionMilestones, 60, "the amount of milestones a paginated query can be continued after it was started")
+			fs.Duration(CfgIndexerWebhookTimeout, 5*time.Second, "the timeout for posting to a webhook")
+			return fs
+		}(),
+	},
+	Masked: nil,
+}
@@ -30,6 +30,9 @@ const (
 
 	workerCount     = 1
 	workerQueueSize = 10000
+
+	webhookWorkerCount     = 4
+	webhookWorkerQueueSize = 1000
 )
 
 func init() {
@@ -38,6 +41,7 @@ func init() {
 		Pluggable: node.Pluggable{
 			Name:      "Indexer",
 			DepsFunc:  func(cDeps dependencies) { deps = cDeps },
+			Params:    params,
 			Provide:   provide,
 			Configure: configure,
 			Run:       run,
@@ -51,18 +55,23 @@ var (
 
 	onLedgerUpdated           *events.Closure
 	onLedgerUpdatedWorkerPool *workerpool.WorkerPool
+
+	hooks             *webhooks
+	webhookWorkerPool *workerpool.WorkerPool
 )
 
 type dependencies struct {
 	dig.In
-	NodeConfig              *configuration.Configuration `name:"nodeConfig"`
-	Indexer                 *indexer.Indexer
-	SyncManager             *syncmanager.SyncManager
-	UTXOManager             *utxo.Manager
-	Tangle                  *tangle.Tangle
-	Bech32HRP               iotago.NetworkPrefix `name:"bech32HRP"`
-	RestAPILimitsMaxResults int                  `name:"restAPILimitsMaxResults"`
-	ShutdownHandler         *shutdown.ShutdownHandler
+	NodeConfig                *configuration.Configuration `name:"nodeConfig"`
+	Indexer                   *indexer.Indexer
+	Storage                   *storage.Storage
+	SyncManager               *syncmanager.SyncManager
+	UTXOManager               *utxo.Manager
+	Tangle                    *tangle.Tangle
+	Bech32HRP                 iotago.NetworkPrefix `name:"bech32HRP"`
+	RestAPILimitsMaxResults   int                  `name:"restAPILimitsMaxResults"`
+	ShutdownHandler           *shutdown.ShutdownHandler
+	DeserializationParameters *iotago.DeSerializationParameters
 }
 
 func provide(c *dig.Container) {
@@ -78,7 +87,9 @@ func provide(c *dig.Container) {
 
 	if err := c.Provide(func(deps indexerDeps) *indexer.Indexer {
 		dbPath := filepath.Join(deps.DatabasePath, "indexer")
-		idx, err := indexer.NewIndexer(dbPath)
+		idx, err := indexer.NewIndexer(dbPath,
+			indexer.WithSpentOutputsRetention(milestone.Index(deps.NodeConfig.Int(CfgIndexerCursorRetentionMilestones))),
+		)
 		if err != nil {
 			Plugin.LogPanic(err)
 		}
@@ -94,6 +105,7 @@ func configure() {
 	configureRoutes(routeGroup)
 
 	initializeIndexer()
+	configureWebhooks()
 
 	if err := Plugin.Node.Daemon().BackgroundWorker("Close Indexer database", func(ctx context.Context) {
 		<-ctx.Done()
@@ -122,10 +134,12 @@ func run() {
 	if err := Plugin.Daemon().BackgroundWorker("Indexer", func(ctx context.Context) {
 		Plugin.LogInfo("Starting Indexer ... done")
 		attachEvents()
+		webhookWorkerPool.Start()
 		onLedgerUpdatedWorkerPool.Start()
 		<-ctx.Done()
 		detachEvents()
 		onLedgerUpdatedWorkerPool.StopAndWait()
+		webhookWorkerPool.StopAndWait()
 		Plugin.LogInfo("Stopping Indexer ... done")
 	}, shutdown.PriorityIndexer); err != nil {
 		Plugin.LogPanicf("failed to start worker: %s", err)
@@ -143,46 +157,54 @@ func detachEvents() {
 func updateIndexer(index milestone.Index, newOutputs utxo.Outputs, newSpents utxo.Spents) {
 	if err := deps.Indexer.UpdatedLedger(index, newOutputs, newSpents); err != nil {
 		deps.ShutdownHandler.SelfShutdown(fmt.Sprintf("indexer plugin hit a critical error while updating ledger: %s", err.Error()))
+		return
+	}
+
+	if len(newOutputs) > 0 {
+		hooks.notify(index)
 	}
 }
 
-func initializeIndexer() {
-	// compare Indexer ledgerIndex with UTXO ledgerIndex and if it does not match, drop tables and import unspent outputs
-	needsInitialImport := false
-	deps.UTXOManager.ReadLockLedger()
-	defer deps.UTXOManager.ReadUnlockLedger()
+func configureWebhooks() {
+	hooks = newWebhooks(deps.NodeConfig.Duration(CfgIndexerWebhookTimeout))
+
+	webhookWorkerPool = workerpool.New(func(task workerpool.Task) {
+		hooks.post(task.Param(0).(*webhook), task.Param(1).(*webhookPayload))
+		task.Return(nil)
+	}, workerpool.WorkerCount(webhookWorkerCount), workerpool.QueueSize(webhookWorkerQueueSize), workerpool.FlushTasksAtShutdown(true))
+
+	var configs []webhookConfig
+	if err := deps.NodeConfig.Unmarshal(CfgIndexerWebhooks, &configs); err != nil {
+		Plugin.LogPanicf("failed to parse the webhooks of the config: %s", err)
+	}
 
-	utxoLedgerIndex, err := deps.UTXOManager.ReadLedgerIndexWithoutLocking()
+	for _, config := range configs {
+		hook := &webhook{URL: config.URL, Route: config.Route, Query: config.Query, FromConfig: true}
+		if err := hooks.add(hook); err != nil {
+			Plugin.LogPanicf("invalid webhook in the config (%s): %s", config.URL, err)
+		}
+		Plugin.LogInfof("registered webhook %s for %s: %s?%s", hook.ID, hook.URL, hook.Route, hook.Query)
+	}
+}
+
+func initializeIndexer() {
+	// compare Indexer ledgerIndex with UTXO ledgerIndex and if it does not match, rebuild the index
+	utxoLedgerIndex, err := deps.UTXOManager.ReadLedgerIndex()
 	if err != nil {
 		Plugin.LogPanicf("Reading UTXO ledger index failed: %s", err)
 	}
+
 	indexerLedgerIndex, err := deps.Indexer.LedgerIndex()
 	if err != nil {
-		if errors.Is(err, indexer.ErrNotFound) {
-			needsInitialImport = true
-		} else {
+		if !errors.Is(err, indexer.ErrNotFound) {
 			Plugin.LogPanicf("Reading Indexer ledger index failed: %s", err)
 		}
-	} else {
-		if utxoLedgerIndex != indexerLedgerIndex {
-			Plugin.LogInfof("Re-indexing UTXO ledger with index: %d", utxoLedgerIndex)
-			deps.Indexer.Clear()
-			needsInitialImport = true
-		}
+	} else if utxoLedgerIndex == indexerLedgerIndex {
+		return
 	}
 
-	if needsInitialImport {
-		importer := deps.Indexer.ImportTransaction()
-		if err := deps.UTXOManager.ForEachUnspentOutput(func(output *utxo.Output) bool {
-			if err := importer.AddOutput(output); err != nil {
-				Plugin.LogPanicf("Importing Indexer data failed: %s", err)
-			}
-			return true
-		}); err != nil {
-			Plugin.LogPanicf("Importing Indexer data failed: %s", err)
-		}
-		if err := importer.Finalize(utxoLedgerIndex); err != nil {
-			Plugin.LogPanicf("Importing Indexer data failed: %s", err)
-		}
+	Plugin.LogInfof("Re-indexing UTXO ledger with index: %d", utxoLedgerIndex)
+	if _, err := rebuildIndexer(nil); err != nil {
+		Plugin.LogPanicf("Importing Indexer data failed: %s", err)
 	}
 }
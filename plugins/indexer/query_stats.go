@@ -0,0 +1,97 @@
+package indexer
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/indexer"
+	"github.com/gohornet/hornet/pkg/restapi"
+)
+
+const (
+	// defaultRecommendedIndexMinQueries is the minimum amount of queries a filter combination
+	// needs to get an index recommended, if not given by the request.
+	defaultRecommendedIndexMinQueries = 1000
+)
+
+func minQueriesFromContext(c echo.Context) (uint64, error) {
+	if len(c.QueryParam(QueryParameterMinQueries)) == 0 {
+		return defaultRecommendedIndexMinQueries, nil
+	}
+
+	minQueries, err := strconv.ParseUint(c.QueryParam(QueryParameterMinQueries), 10, 64)
+	if err != nil || minQueries == 0 {
+		return 0, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid %s, must be a positive number", QueryParameterMinQueries)
+	}
+
+	return minQueries, nil
+}
+
+func durationMilliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func indexRecommendationsResponse(recommendations []*indexer.IndexRecommendation) []*indexRecommendationResponse {
+	result := make([]*indexRecommendationResponse, 0, len(recommendations))
+	for _, recommendation := range recommendations {
+		result = append(result, &indexRecommendationResponse{
+			Name:    recommendation.Name,
+			Table:   recommendation.Table,
+			Columns: recommendation.Columns,
+			Queries: recommendation.Queries,
+			Exists:  recommendation.Exists,
+		})
+	}
+	return result
+}
+
+func queryStats(c echo.Context) (*queryStatsResponse, error) {
+	minQueries, err := minQueriesFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := deps.Indexer.QueryStats()
+
+	queries := make([]*queryFilterStatsResponse, 0, len(stats))
+	for _, s := range stats {
+		queries = append(queries, &queryFilterStatsResponse{
+			Table:           s.Table,
+			EqualityColumns: s.EqualityColumns,
+			RangeColumns:    s.RangeColumns,
+			Count:           s.Count,
+			AvgDuration:     durationMilliseconds(s.AvgDuration),
+			P95Duration:     durationMilliseconds(s.P95Duration),
+			MaxDuration:     durationMilliseconds(s.MaxDuration),
+		})
+	}
+
+	return &queryStatsResponse{
+		Queries:            queries,
+		RecommendedIndexes: indexRecommendationsResponse(deps.Indexer.RecommendedIndexes(minQueries)),
+	}, nil
+}
+
+func createRecommendedIndexes(c echo.Context) (*createIndexesResponse, error) {
+	minQueries, err := minQueriesFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	Plugin.LogInfo("Creating recommended indexes ...")
+	created, err := deps.Indexer.CreateRecommendedIndexes(minQueries)
+	for _, index := range created {
+		Plugin.LogInfof("Created index %s on %s %v", index.Name, index.Table, index.Columns)
+	}
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "creating recommended indexes failed: %s", err)
+	}
+	Plugin.LogInfof("Creating recommended indexes ... done, created %d indexes", len(created))
+
+	return &createIndexesResponse{
+		Created: indexRecommendationsResponse(created),
+	}, nil
+}
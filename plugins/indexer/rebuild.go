@@ -0,0 +1,114 @@
+package indexer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/indexer"
+)
+
+const (
+	// rebuildProgressInterval is the amount of imported outputs after which the progress of a rebuild is reported.
+	rebuildProgressInterval = 10000
+
+	// rebuildProgressQueueSize is the amount of progress events that are buffered for a slow client.
+	// Intermediate events are dropped if the queue is full, so that the client never delays the rebuild,
+	// which holds the ledger lock.
+	rebuildProgressQueueSize = 100
+)
+
+func rebuildProgressEvent(progress *indexer.RebuildProgress) *rebuildProgressResponse {
+	return &rebuildProgressResponse{
+		LedgerIndex:     progress.LedgerIndex,
+		TotalOutputs:    progress.TotalOutputs,
+		ImportedOutputs: progress.ImportedOutputs,
+		Done:            progress.Done,
+	}
+}
+
+// rebuildIndexer rebuilds the index from the current UTXO ledger and passes the progress to onProgress.
+func rebuildIndexer(onProgress indexer.RebuildProgressFunc) (time.Duration, error) {
+	ts := time.Now()
+
+	if err := deps.Indexer.Rebuild(deps.UTXOManager, rebuildProgressInterval, func(progress *indexer.RebuildProgress) {
+		if !progress.Done {
+			Plugin.LogInfof("Rebuilding Indexer at ledger index %d... %d/%d outputs", progress.LedgerIndex, progress.ImportedOutputs, progress.TotalOutputs)
+		}
+		if onProgress != nil {
+			onProgress(progress)
+		}
+	}); err != nil {
+		return 0, err
+	}
+
+	took := time.Since(ts).Truncate(time.Millisecond)
+	Plugin.LogInfof("Rebuilding Indexer... done, took %v", took)
+
+	return took, nil
+}
+
+func rebuildIndex(c echo.Context) error {
+
+	if deps.Indexer.IsRebuilding() {
+		return errors.WithMessage(echo.ErrServiceUnavailable, "indexer rebuild in progress")
+	}
+
+	progressChan := make(chan *rebuildProgressResponse, rebuildProgressQueueSize)
+	resultChan := make(chan *rebuildProgressResponse, 1)
+
+	go func() {
+		defer close(progressChan)
+
+		var lastProgress *indexer.RebuildProgress
+		took, err := rebuildIndexer(func(progress *indexer.RebuildProgress) {
+			lastProgress = progress
+			if progress.Done {
+				return
+			}
+
+			select {
+			case progressChan <- rebuildProgressEvent(progress):
+			default:
+			}
+		})
+
+		result := &rebuildProgressResponse{}
+		if lastProgress != nil {
+			result = rebuildProgressEvent(lastProgress)
+		}
+		if err != nil {
+			result.Error = err.Error()
+			if !errors.Is(err, indexer.ErrRebuildInProgress) {
+				// the index is left incomplete, it is imported again at the next startup because it has no ledger index
+				deps.ShutdownHandler.SelfShutdown(fmt.Sprintf("indexer plugin hit a critical error while rebuilding the index: %s", err.Error()))
+			}
+		} else {
+			result.Duration = durationMilliseconds(took)
+		}
+		resultChan <- result
+	}()
+
+	c.Response().Header().Set(echo.HeaderContentType, "application/x-ndjson")
+	c.Response().WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Response())
+	writeEvent := func(event *rebuildProgressResponse) {
+		if err := encoder.Encode(event); err != nil {
+			// the client is gone, the rebuild continues anyway
+			return
+		}
+		c.Response().Flush()
+	}
+
+	for event := range progressChan {
+		writeEvent(event)
+	}
+	writeEvent(<-resultChan)
+
+	return nil
+}
@@ -3,13 +3,16 @@ package indexer
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 
 	"github.com/gohornet/hornet/pkg/indexer"
+	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/restapi"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
@@ -18,16 +21,28 @@ const (
 
 	// RouteOutputs is the route for getting outputs filtered by the given parameters.
 	// GET with query parameter returns all outputIDs that fit these filter criteria.
-	// Query parameters: "address", "hasDustReturnCondition", "dustReturnAddress", "hasExpirationCondition",
+	// Query parameters: "address", "spendableBy", "spendableAt", "spendableAtMilestone",
+	//					 "hasDustReturnCondition", "dustReturnAddress", "hasExpirationCondition",
 	//					 "expiresBefore", "expiresAfter", "expiresBeforeMilestone", "expiresAfterMilestone",
 	//					 "hasTimelockCondition", "timelockedBefore", "timelockedAfter", "timelockedBeforeMilestone",
-	//					 "timelockedAfterMilestone", "sender", "tag", "createdBefore", "createdAfter"
+	//					 "timelockedAfterMilestone", "sender", "tag", "tagPrefix", "metadataContains", "createdBefore", "createdAfter", "anyOf",
+	//					 "sortBy", "sortOrder"
 	// Returns an empty list if no results are found.
 	RouteOutputs = "/outputs"
 
+	// RouteOutputsConsolidation is the route for getting the unspent outputs of an address together with a consolidation plan.
+	// GET returns the outputIDs and groups of outputIDs that can be consolidated within a single transaction.
+	// Query parameters: "address" (required), "maxInputs" (optional).
+	RouteOutputsConsolidation = "/outputs/consolidation"
+
+	// RouteOutputsAggregate is the route for getting the number and the summed amount of the outputs filtered by the given parameters.
+	// GET accepts the same filters as RouteOutputs and returns the count and amount of all matching outputs without listing them.
+	RouteOutputsAggregate = "/outputs/aggregate"
+
 	// RouteAliases is the route for getting aliases filtered by the given parameters.
 	// GET with query parameter returns all outputIDs that fit these filter criteria.
-	// Query parameters: "stateController", "governor", "issuer", "sender", "createdBefore", "createdAfter"
+	// Query parameters: "stateController", "governor", "issuer", "sender", "metadataContains", "createdBefore", "createdAfter", "anyOf",
+	//					 "sortBy", "sortOrder"
 	// Returns an empty list if no results are found.
 	RouteAliases = "/aliases"
 
@@ -35,11 +50,17 @@ const (
 	// GET returns the outputIDs or 404 if no record is found.
 	RouteAliasByID = "/aliases/:" + restapi.ParameterAliasID
 
+	// RouteAliasesAggregate is the route for getting the number and the summed amount of the aliases filtered by the given parameters.
+	// GET accepts the same filters as RouteAliases and returns the count and amount of all matching outputs without listing them.
+	RouteAliasesAggregate = "/aliases/aggregate"
+
 	// RouteNFTs is the route for getting NFT filtered by the given parameters.
-	// Query parameters: "address", "hasDustReturnCondition", "dustReturnAddress", "hasExpirationCondition",
+	// Query parameters: "address", "spendableBy", "spendableAt", "spendableAtMilestone",
+	//					 "hasDustReturnCondition", "dustReturnAddress", "hasExpirationCondition",
 	//					 "expiresBefore", "expiresAfter", "expiresBeforeMilestone", "expiresAfterMilestone",
 	//					 "hasTimelockCondition", "timelockedBefore", "timelockedAfter", "timelockedBeforeMilestone",
-	//					 "timelockedAfterMilestone", "issuer", "sender", "tag", "createdBefore", "createdAfter"
+	//					 "timelockedAfterMilestone", "issuer", "sender", "tag", "tagPrefix", "metadataContains", "createdBefore", "createdAfter", "anyOf",
+	//					 "sortBy", "sortOrder"
 	// Returns an empty list if no results are found.
 	RouteNFTs = "/nfts"
 
@@ -47,15 +68,60 @@ const (
 	// GET returns the outputIDs or 404 if no record is found.
 	RouteNFTByID = "/nfts/:" + restapi.ParameterNFTID
 
+	// RouteNFTsAggregate is the route for getting the number and the summed amount of the NFTs filtered by the given parameters.
+	// GET accepts the same filters as RouteNFTs and returns the count and amount of all matching outputs without listing them.
+	RouteNFTsAggregate = "/nfts/aggregate"
+
 	// RouteFoundries is the route for getting foundries filtered by the given parameters.
 	// GET with query parameter returns all outputIDs that fit these filter criteria.
-	// Query parameters: "address", "createdBefore", "createdAfter"
+	// Query parameters: "address", "createdBefore", "createdAfter", "anyOf",
+	//					 "sortBy", "sortOrder"
 	// Returns an empty list if no results are found.
 	RouteFoundries = "/foundries"
 
 	// RouteFoundryByID is the route for getting foundries by their foundryID.
 	// GET returns the outputIDs or 404 if no record is found.
 	RouteFoundryByID = "/foundries/:" + restapi.ParameterFoundryID
+
+	// RouteFoundriesAggregate is the route for getting the number and the summed amount of the foundries filtered by the given parameters.
+	// GET accepts the same filters as RouteFoundries and returns the count and amount of all matching outputs without listing them.
+	RouteFoundriesAggregate = "/foundries/aggregate"
+
+	// RouteQueryStats is the route for getting the statistics of the filter combinations used in the queries.
+	// GET returns the statistics together with the composite indexes recommended for the most frequent filter combinations.
+	// DELETE resets the statistics.
+	// Query parameters: "minQueries" (optional).
+	RouteQueryStats = "/stats/queries"
+
+	// RouteMaintenanceIndexes is the route for creating the recommended composite indexes.
+	// POST creates and backfills the indexes recommended by the query statistics and returns the created indexes.
+	// Query parameters: "minQueries" (optional).
+	RouteMaintenanceIndexes = "/maintenance/indexes"
+
+	// RouteMaintenanceRebuild is the route for rebuilding the index from the current UTXO ledger.
+	// POST drops all tables, imports the unspent outputs again and streams the progress as newline delimited JSON.
+	// All other routes return 503 while the rebuild is in progress.
+	RouteMaintenanceRebuild = "/maintenance/rebuild"
+
+	// RouteGraphQL is the route for querying the indexer with GraphQL.
+	// GET takes the query as "query" parameter, POST takes a JSON body with "query", "operationName" and "variables".
+	// The schema offers the list routes of the outputs, aliases, NFTs and foundries with the same filters and pagination.
+	RouteGraphQL = "/graphql"
+
+	// RouteWebhooks is the route for managing the webhooks that get notified about newly booked outputs.
+	// GET returns all webhooks.
+	// POST registers a webhook for the outputs that match the filters of the given route and returns it.
+	// Webhooks registered via the API are not persisted and need to be registered again after a restart.
+	RouteWebhooks = "/webhooks"
+
+	// RouteWebhook is the route for removing a webhook.
+	// DELETE removes the webhook, webhooks of the config can't be removed.
+	RouteWebhook = "/webhooks/:" + ParameterWebhookID
+)
+
+const (
+	// ParameterWebhookID is used to identify a webhook.
+	ParameterWebhookID = "webhookID"
 )
 
 const (
@@ -63,6 +129,18 @@ const (
 	// QueryParameterAddress is used to filter for a certain address.
 	QueryParameterAddress = "address"
 
+	// QueryParameterSpendableBy is used to filter for outputs that a certain address can unlock,
+	// taking timelock and expiration unlock conditions into account.
+	QueryParameterSpendableBy = "spendableBy"
+
+	// QueryParameterSpendableAt is the unix time at which the outputs need to be spendable.
+	// It defaults to the timestamp of the confirmed milestone.
+	QueryParameterSpendableAt = "spendableAt"
+
+	// QueryParameterSpendableAtMilestone is the milestone index at which the outputs need to be spendable.
+	// It defaults to the confirmed milestone index.
+	QueryParameterSpendableAtMilestone = "spendableAtMilestone"
+
 	// QueryParameterIssuer is used to filter for a certain issuer.
 	QueryParameterIssuer = "issuer"
 
@@ -72,6 +150,12 @@ const (
 	// QueryParameterTag is used to filter for a certain tag.
 	QueryParameterTag = "tag"
 
+	// QueryParameterTagPrefix is used to filter for tags that start with a certain prefix.
+	QueryParameterTagPrefix = "tagPrefix"
+
+	// QueryParameterMetadataContains is used to filter for metadata that contains certain data.
+	QueryParameterMetadataContains = "metadataContains"
+
 	// QueryParameterHasDustReturnCondition is used to filter for outputs having a dust return unlock condition.
 	QueryParameterHasDustReturnCondition = "hasDustReturnCondition"
 
@@ -117,10 +201,21 @@ const (
 	// QueryParameterGovernor is used to filter for a certain governance controller address.
 	QueryParameterGovernor = "governor"
 
+	// QueryParameterMaxInputs is used to define the maximum amount of inputs of a consolidation transaction.
+	QueryParameterMaxInputs = "maxInputs"
+
 	// QueryParameterPageSize is used to define the page size for the results.
 	QueryParameterPageSize = "pageSize"
 
+	// QueryParameterSortBy is used to define the value the results are sorted by ("createdAt" (default) or "amount").
+	QueryParameterSortBy = "sortBy"
+
+	// QueryParameterSortOrder is used to define the direction the results are sorted in ("asc" (default) or "desc").
+	// A cursor can only be used with the sorting of the query it was returned by.
+	QueryParameterSortOrder = "sortOrder"
+
 	// QueryParameterCursor is used to pass the offset we want to start the next results from.
+	// The following pages are answered at the ledger index of the first page, until the cursor expires.
 	QueryParameterCursor = "cursor"
 
 	// QueryParameterCreatedBefore is used to filter for outputs that were created before the given time.
@@ -128,6 +223,22 @@ const (
 
 	// QueryParameterCreatedAfter is used to filter for outputs that were created after the given time.
 	QueryParameterCreatedAfter = "createdAfter"
+
+	// QueryParameterMinQueries is used to define the minimum amount of queries a filter combination needs to get an index recommended.
+	QueryParameterMinQueries = "minQueries"
+
+	// QueryParameterAnyOf is used to define an alternative set of filters, of which at least one needs to match.
+	// Every value is an URL encoded query string with the filter parameters of the route, e.g. "address=...&tag=...".
+	// All "anyOf" parameters of a request form a single group, multiple groups can be combined by nesting them.
+	QueryParameterAnyOf = "anyOf"
+)
+
+const (
+	// maxAnyOfAlternatives is the maximum amount of alternatives in a group.
+	maxAnyOfAlternatives = 10
+
+	// maxAnyOfDepth is the maximum nesting depth of groups.
+	maxAnyOfDepth = 2
 )
 
 func nodeSyncedMiddleware() echo.MiddlewareFunc {
@@ -141,8 +252,20 @@ func nodeSyncedMiddleware() echo.MiddlewareFunc {
 	}
 }
 
+func rebuildingMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			if deps.Indexer.IsRebuilding() {
+				return errors.WithMessage(echo.ErrServiceUnavailable, "indexer rebuild in progress")
+			}
+			return next(c)
+		}
+	}
+}
+
 func configureRoutes(routeGroup *echo.Group) {
 	routeGroup.Use(nodeSyncedMiddleware())
+	routeGroup.Use(rebuildingMiddleware())
 
 	routeGroup.GET(RouteOutputs, func(c echo.Context) error {
 		resp, err := outputsWithFilter(c)
@@ -150,7 +273,25 @@ func configureRoutes(routeGroup *echo.Group) {
 			return err
 		}
 
-		return c.JSON(http.StatusOK, resp)
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteOutputsConsolidation, func(c echo.Context) error {
+		resp, err := outputsConsolidation(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteOutputsAggregate, func(c echo.Context) error {
+		resp, err := outputsAggregate(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
 	})
 
 	routeGroup.GET(RouteAliases, func(c echo.Context) error {
@@ -159,7 +300,7 @@ func configureRoutes(routeGroup *echo.Group) {
 			return err
 		}
 
-		return c.JSON(http.StatusOK, resp)
+		return restapi.JSONResponse(c, http.StatusOK, resp)
 	})
 
 	routeGroup.GET(RouteAliasByID, func(c echo.Context) error {
@@ -168,7 +309,16 @@ func configureRoutes(routeGroup *echo.Group) {
 			return err
 		}
 
-		return c.JSON(http.StatusOK, resp)
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteAliasesAggregate, func(c echo.Context) error {
+		resp, err := aliasesAggregate(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
 	})
 
 	routeGroup.GET(RouteNFTs, func(c echo.Context) error {
@@ -177,7 +327,7 @@ func configureRoutes(routeGroup *echo.Group) {
 			return err
 		}
 
-		return c.JSON(http.StatusOK, resp)
+		return restapi.JSONResponse(c, http.StatusOK, resp)
 	})
 
 	routeGroup.GET(RouteNFTByID, func(c echo.Context) error {
@@ -186,7 +336,16 @@ func configureRoutes(routeGroup *echo.Group) {
 			return err
 		}
 
-		return c.JSON(http.StatusOK, resp)
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteNFTsAggregate, func(c echo.Context) error {
+		resp, err := nftsAggregate(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
 	})
 
 	routeGroup.GET(RouteFoundries, func(c echo.Context) error {
@@ -195,7 +354,7 @@ func configureRoutes(routeGroup *echo.Group) {
 			return err
 		}
 
-		return c.JSON(http.StatusOK, resp)
+		return restapi.JSONResponse(c, http.StatusOK, resp)
 	})
 
 	routeGroup.GET(RouteFoundryByID, func(c echo.Context) error {
@@ -204,12 +363,117 @@ func configureRoutes(routeGroup *echo.Group) {
 			return err
 		}
 
-		return c.JSON(http.StatusOK, resp)
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteFoundriesAggregate, func(c echo.Context) error {
+		resp, err := foundriesAggregate(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteQueryStats, func(c echo.Context) error {
+		resp, err := queryStats(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.DELETE(RouteQueryStats, func(c echo.Context) error {
+		deps.Indexer.ResetQueryStats()
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	routeGroup.POST(RouteMaintenanceIndexes, func(c echo.Context) error {
+		resp, err := createRecommendedIndexes(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RouteMaintenanceRebuild, func(c echo.Context) error {
+		return rebuildIndex(c)
+	})
+
+	configureGraphQL()
+
+	routeGroup.GET(RouteGraphQL, func(c echo.Context) error {
+		resp, err := graphqlQuery(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RouteGraphQL, func(c echo.Context) error {
+		resp, err := graphqlQuery(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteWebhooks, func(c echo.Context) error {
+		return restapi.JSONResponse(c, http.StatusOK, &webhooksResponse{Webhooks: hooks.list()})
+	})
+
+	routeGroup.POST(RouteWebhooks, func(c echo.Context) error {
+		resp, err := registerWebhook(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusCreated, resp)
+	})
+
+	routeGroup.DELETE(RouteWebhook, func(c echo.Context) error {
+		if err := removeWebhook(c); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
 	})
 }
 
 func outputsWithFilter(c echo.Context) (*outputsResponse, error) {
-	filters := []indexer.ExtendedOutputFilterOption{indexer.ExtendedOutputPageSize(pageSizeFromContext(c))}
+	filters, err := extendedOutputFilters(c, 0)
+	if err != nil {
+		return nil, err
+	}
+	filters = append(filters, indexer.ExtendedOutputPageSize(pageSizeFromContext(c)))
+
+	sortByAmount, sortOrder, err := sortingFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+	if sortByAmount {
+		filters = append(filters, indexer.ExtendedOutputSortByAmount(sortOrder))
+	} else {
+		filters = append(filters, indexer.ExtendedOutputSortByCreatedAt(sortOrder))
+	}
+
+	if len(c.QueryParam(QueryParameterCursor)) > 0 {
+		cursor, pageSize, err := parseCursorQueryParameter(c)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, indexer.ExtendedOutputCursor(cursor), indexer.ExtendedOutputPageSize(pageSize))
+	}
+
+	return outputsResponseFromResult(deps.Indexer.ExtendedOutputsWithFilters(filters...))
+}
+
+func extendedOutputFilters(c echo.Context, depth int) ([]indexer.ExtendedOutputFilterOption, error) {
+	var filters []indexer.ExtendedOutputFilterOption
 
 	if len(c.QueryParam(QueryParameterAddress)) > 0 {
 		addr, err := restapi.ParseBech32AddressQueryParam(c, deps.Bech32HRP, QueryParameterAddress)
@@ -219,6 +483,18 @@ func outputsWithFilter(c echo.Context) (*outputsResponse, error) {
 		filters = append(filters, indexer.ExtendedOutputUnlockableByAddress(addr))
 	}
 
+	if len(c.QueryParam(QueryParameterSpendableBy)) > 0 {
+		addr, err := restapi.ParseBech32AddressQueryParam(c, deps.Bech32HRP, QueryParameterSpendableBy)
+		if err != nil {
+			return nil, err
+		}
+		msIndex, timestamp, err := spendableReference(c)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, indexer.ExtendedOutputSpendableByAddress(addr, msIndex, timestamp))
+	}
+
 	if len(c.QueryParam(QueryParameterHasDustReturnCondition)) > 0 {
 		value, err := restapi.ParseBoolQueryParam(c, QueryParameterHasDustReturnCondition)
 		if err != nil {
@@ -339,12 +615,20 @@ func outputsWithFilter(c echo.Context) (*outputsResponse, error) {
 		filters = append(filters, indexer.ExtendedOutputTag(tagBytes))
 	}
 
-	if len(c.QueryParam(QueryParameterCursor)) > 0 {
-		cursor, pageSize, err := parseCursorQueryParameter(c)
+	if len(c.QueryParam(QueryParameterTagPrefix)) > 0 {
+		value, err := restapi.ParseHexQueryParam(c, QueryParameterTagPrefix, iotago.MaxTagLength)
 		if err != nil {
 			return nil, err
 		}
-		filters = append(filters, indexer.ExtendedOutputCursor(cursor), indexer.ExtendedOutputPageSize(pageSize))
+		filters = append(filters, indexer.ExtendedOutputTagPrefix(value))
+	}
+
+	if len(c.QueryParam(QueryParameterMetadataContains)) > 0 {
+		value, err := restapi.ParseHexQueryParam(c, QueryParameterMetadataContains, iotago.MaxMetadataLength)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, indexer.ExtendedOutputMetadataContains(value))
 	}
 
 	if len(c.QueryParam(QueryParameterCreatedBefore)) > 0 {
@@ -363,7 +647,65 @@ func outputsWithFilter(c echo.Context) (*outputsResponse, error) {
 		filters = append(filters, indexer.ExtendedOutputCreatedAfter(timestamp))
 	}
 
-	return outputsResponseFromResult(deps.Indexer.ExtendedOutputsWithFilters(filters...))
+	alternatives, err := anyOfAlternatives(c, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(alternatives) > 0 {
+		group := make([][]indexer.ExtendedOutputFilterOption, 0, len(alternatives))
+		for _, alternative := range alternatives {
+			alternativeFilters, err := extendedOutputFilters(alternative, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			group = append(group, alternativeFilters)
+		}
+		filters = append(filters, indexer.ExtendedOutputAnyOf(group...))
+	}
+
+	return filters, nil
+}
+
+// spendableReference returns the milestone index and the time at which the unlock conditions of the outputs are evaluated.
+// Both default to the confirmed milestone.
+func spendableReference(c echo.Context) (milestone.Index, time.Time, error) {
+	var msIndex milestone.Index
+	var timestamp time.Time
+
+	if len(c.QueryParam(QueryParameterSpendableAtMilestone)) > 0 {
+		var err error
+		if msIndex, err = restapi.ParseMilestoneIndexQueryParam(c, QueryParameterSpendableAtMilestone); err != nil {
+			return 0, time.Time{}, err
+		}
+	}
+
+	if len(c.QueryParam(QueryParameterSpendableAt)) > 0 {
+		var err error
+		if timestamp, err = restapi.ParseUnixTimestampQueryParam(c, QueryParameterSpendableAt); err != nil {
+			return 0, time.Time{}, err
+		}
+	}
+
+	if msIndex != 0 && !timestamp.IsZero() {
+		return msIndex, timestamp, nil
+	}
+
+	confirmedMilestoneIndex := deps.SyncManager.ConfirmedMilestoneIndex()
+	cachedMilestone := deps.Storage.CachedMilestoneOrNil(confirmedMilestoneIndex) // milestone +1
+	if cachedMilestone == nil {
+		return 0, time.Time{}, errors.WithMessagef(echo.ErrServiceUnavailable, "confirmed milestone %d not found", confirmedMilestoneIndex)
+	}
+	defer cachedMilestone.Release(true) // milestone -1
+
+	if msIndex == 0 {
+		msIndex = confirmedMilestoneIndex
+	}
+	if timestamp.IsZero() {
+		timestamp = cachedMilestone.Milestone().Timestamp
+	}
+
+	return msIndex, timestamp, nil
 }
 
 func aliasByID(c echo.Context) (*outputsResponse, error) {
@@ -375,7 +717,35 @@ func aliasByID(c echo.Context) (*outputsResponse, error) {
 }
 
 func aliasesWithFilter(c echo.Context) (*outputsResponse, error) {
-	filters := []indexer.AliasFilterOption{indexer.AliasPageSize(pageSizeFromContext(c))}
+	filters, err := aliasFilters(c, 0)
+	if err != nil {
+		return nil, err
+	}
+	filters = append(filters, indexer.AliasPageSize(pageSizeFromContext(c)))
+
+	sortByAmount, sortOrder, err := sortingFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+	if sortByAmount {
+		filters = append(filters, indexer.AliasSortByAmount(sortOrder))
+	} else {
+		filters = append(filters, indexer.AliasSortByCreatedAt(sortOrder))
+	}
+
+	if len(c.QueryParam(QueryParameterCursor)) > 0 {
+		cursor, pageSize, err := parseCursorQueryParameter(c)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, indexer.AliasCursor(cursor), indexer.AliasPageSize(pageSize))
+	}
+
+	return outputsResponseFromResult(deps.Indexer.AliasOutputsWithFilters(filters...))
+}
+
+func aliasFilters(c echo.Context, depth int) ([]indexer.AliasFilterOption, error) {
+	var filters []indexer.AliasFilterOption
 
 	if len(c.QueryParam(QueryParameterStateController)) > 0 {
 		stateController, err := restapi.ParseBech32AddressQueryParam(c, deps.Bech32HRP, QueryParameterStateController)
@@ -409,12 +779,12 @@ func aliasesWithFilter(c echo.Context) (*outputsResponse, error) {
 		filters = append(filters, indexer.AliasSender(sender))
 	}
 
-	if len(c.QueryParam(QueryParameterCursor)) > 0 {
-		cursor, pageSize, err := parseCursorQueryParameter(c)
+	if len(c.QueryParam(QueryParameterMetadataContains)) > 0 {
+		value, err := restapi.ParseHexQueryParam(c, QueryParameterMetadataContains, iotago.MaxMetadataLength)
 		if err != nil {
 			return nil, err
 		}
-		filters = append(filters, indexer.AliasCursor(cursor), indexer.AliasPageSize(pageSize))
+		filters = append(filters, indexer.AliasMetadataContains(value))
 	}
 
 	if len(c.QueryParam(QueryParameterCreatedBefore)) > 0 {
@@ -433,7 +803,24 @@ func aliasesWithFilter(c echo.Context) (*outputsResponse, error) {
 		filters = append(filters, indexer.AliasCreatedAfter(timestamp))
 	}
 
-	return outputsResponseFromResult(deps.Indexer.AliasOutputsWithFilters(filters...))
+	alternatives, err := anyOfAlternatives(c, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(alternatives) > 0 {
+		group := make([][]indexer.AliasFilterOption, 0, len(alternatives))
+		for _, alternative := range alternatives {
+			alternativeFilters, err := aliasFilters(alternative, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			group = append(group, alternativeFilters)
+		}
+		filters = append(filters, indexer.AliasAnyOf(group...))
+	}
+
+	return filters, nil
 }
 
 func nftByID(c echo.Context) (*outputsResponse, error) {
@@ -445,7 +832,35 @@ func nftByID(c echo.Context) (*outputsResponse, error) {
 }
 
 func nftsWithFilter(c echo.Context) (*outputsResponse, error) {
-	filters := []indexer.NFTFilterOption{indexer.NFTPageSize(pageSizeFromContext(c))}
+	filters, err := nftFilters(c, 0)
+	if err != nil {
+		return nil, err
+	}
+	filters = append(filters, indexer.NFTPageSize(pageSizeFromContext(c)))
+
+	sortByAmount, sortOrder, err := sortingFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+	if sortByAmount {
+		filters = append(filters, indexer.NFTSortByAmount(sortOrder))
+	} else {
+		filters = append(filters, indexer.NFTSortByCreatedAt(sortOrder))
+	}
+
+	if len(c.QueryParam(QueryParameterCursor)) > 0 {
+		cursor, pageSize, err := parseCursorQueryParameter(c)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, indexer.NFTCursor(cursor), indexer.NFTPageSize(pageSize))
+	}
+
+	return outputsResponseFromResult(deps.Indexer.NFTOutputsWithFilters(filters...))
+}
+
+func nftFilters(c echo.Context, depth int) ([]indexer.NFTFilterOption, error) {
+	var filters []indexer.NFTFilterOption
 
 	if len(c.QueryParam(QueryParameterAddress)) > 0 {
 		addr, err := restapi.ParseBech32AddressQueryParam(c, deps.Bech32HRP, QueryParameterAddress)
@@ -455,6 +870,18 @@ func nftsWithFilter(c echo.Context) (*outputsResponse, error) {
 		filters = append(filters, indexer.NFTUnlockableByAddress(addr))
 	}
 
+	if len(c.QueryParam(QueryParameterSpendableBy)) > 0 {
+		addr, err := restapi.ParseBech32AddressQueryParam(c, deps.Bech32HRP, QueryParameterSpendableBy)
+		if err != nil {
+			return nil, err
+		}
+		msIndex, timestamp, err := spendableReference(c)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, indexer.NFTSpendableByAddress(addr, msIndex, timestamp))
+	}
+
 	if len(c.QueryParam(QueryParameterHasDustReturnCondition)) > 0 {
 		value, err := restapi.ParseBoolQueryParam(c, QueryParameterHasDustReturnCondition)
 		if err != nil {
@@ -583,12 +1010,20 @@ func nftsWithFilter(c echo.Context) (*outputsResponse, error) {
 		filters = append(filters, indexer.NFTTag(tagBytes))
 	}
 
-	if len(c.QueryParam(QueryParameterCursor)) > 0 {
-		cursor, pageSize, err := parseCursorQueryParameter(c)
+	if len(c.QueryParam(QueryParameterTagPrefix)) > 0 {
+		value, err := restapi.ParseHexQueryParam(c, QueryParameterTagPrefix, iotago.MaxTagLength)
 		if err != nil {
 			return nil, err
 		}
-		filters = append(filters, indexer.NFTCursor(cursor), indexer.NFTPageSize(pageSize))
+		filters = append(filters, indexer.NFTTagPrefix(value))
+	}
+
+	if len(c.QueryParam(QueryParameterMetadataContains)) > 0 {
+		value, err := restapi.ParseHexQueryParam(c, QueryParameterMetadataContains, iotago.MaxMetadataLength)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, indexer.NFTMetadataContains(value))
 	}
 
 	if len(c.QueryParam(QueryParameterCreatedBefore)) > 0 {
@@ -607,7 +1042,24 @@ func nftsWithFilter(c echo.Context) (*outputsResponse, error) {
 		filters = append(filters, indexer.NFTCreatedAfter(timestamp))
 	}
 
-	return outputsResponseFromResult(deps.Indexer.NFTOutputsWithFilters(filters...))
+	alternatives, err := anyOfAlternatives(c, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(alternatives) > 0 {
+		group := make([][]indexer.NFTFilterOption, 0, len(alternatives))
+		for _, alternative := range alternatives {
+			alternativeFilters, err := nftFilters(alternative, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			group = append(group, alternativeFilters)
+		}
+		filters = append(filters, indexer.NFTAnyOf(group...))
+	}
+
+	return filters, nil
 }
 
 func foundryByID(c echo.Context) (*outputsResponse, error) {
@@ -619,14 +1071,20 @@ func foundryByID(c echo.Context) (*outputsResponse, error) {
 }
 
 func foundriesWithFilter(c echo.Context) (*outputsResponse, error) {
-	filters := []indexer.FoundryFilterOption{indexer.FoundryPageSize(pageSizeFromContext(c))}
+	filters, err := foundryFilters(c, 0)
+	if err != nil {
+		return nil, err
+	}
+	filters = append(filters, indexer.FoundryPageSize(pageSizeFromContext(c)))
 
-	if len(c.QueryParam(QueryParameterAddress)) > 0 {
-		address, err := restapi.ParseBech32AddressQueryParam(c, deps.Bech32HRP, QueryParameterAddress)
-		if err != nil {
-			return nil, err
-		}
-		filters = append(filters, indexer.FoundryUnlockableByAddress(address))
+	sortByAmount, sortOrder, err := sortingFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+	if sortByAmount {
+		filters = append(filters, indexer.FoundrySortByAmount(sortOrder))
+	} else {
+		filters = append(filters, indexer.FoundrySortByCreatedAt(sortOrder))
 	}
 
 	if len(c.QueryParam(QueryParameterCursor)) > 0 {
@@ -637,6 +1095,20 @@ func foundriesWithFilter(c echo.Context) (*outputsResponse, error) {
 		filters = append(filters, indexer.FoundryCursor(cursor), indexer.FoundryPageSize(pageSize))
 	}
 
+	return outputsResponseFromResult(deps.Indexer.FoundryOutputsWithFilters(filters...))
+}
+
+func foundryFilters(c echo.Context, depth int) ([]indexer.FoundryFilterOption, error) {
+	var filters []indexer.FoundryFilterOption
+
+	if len(c.QueryParam(QueryParameterAddress)) > 0 {
+		address, err := restapi.ParseBech32AddressQueryParam(c, deps.Bech32HRP, QueryParameterAddress)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, indexer.FoundryUnlockableByAddress(address))
+	}
+
 	if len(c.QueryParam(QueryParameterCreatedBefore)) > 0 {
 		timestamp, err := restapi.ParseUnixTimestampQueryParam(c, QueryParameterCreatedBefore)
 		if err != nil {
@@ -653,7 +1125,24 @@ func foundriesWithFilter(c echo.Context) (*outputsResponse, error) {
 		filters = append(filters, indexer.FoundryCreatedAfter(timestamp))
 	}
 
-	return outputsResponseFromResult(deps.Indexer.FoundryOutputsWithFilters(filters...))
+	alternatives, err := anyOfAlternatives(c, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(alternatives) > 0 {
+		group := make([][]indexer.FoundryFilterOption, 0, len(alternatives))
+		for _, alternative := range alternatives {
+			alternativeFilters, err := foundryFilters(alternative, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			group = append(group, alternativeFilters)
+		}
+		filters = append(filters, indexer.FoundryAnyOf(group...))
+	}
+
+	return filters, nil
 }
 
 func singleOutputResponseFromResult(result *indexer.IndexerResult) (*outputsResponse, error) {
@@ -666,8 +1155,59 @@ func singleOutputResponseFromResult(result *indexer.IndexerResult) (*outputsResp
 	return outputsResponseFromResult(result)
 }
 
+func outputsAggregate(c echo.Context) (*aggregateResponse, error) {
+	filters, err := extendedOutputFilters(c, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return aggregateResponseFromResult(deps.Indexer.ExtendedOutputsAggregateWithFilters(filters...))
+}
+
+func aliasesAggregate(c echo.Context) (*aggregateResponse, error) {
+	filters, err := aliasFilters(c, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return aggregateResponseFromResult(deps.Indexer.AliasOutputsAggregateWithFilters(filters...))
+}
+
+func nftsAggregate(c echo.Context) (*aggregateResponse, error) {
+	filters, err := nftFilters(c, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return aggregateResponseFromResult(deps.Indexer.NFTOutputsAggregateWithFilters(filters...))
+}
+
+func foundriesAggregate(c echo.Context) (*aggregateResponse, error) {
+	filters, err := foundryFilters(c, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return aggregateResponseFromResult(deps.Indexer.FoundryOutputsAggregateWithFilters(filters...))
+}
+
+func aggregateResponseFromResult(result *indexer.AggregateResult) (*aggregateResponse, error) {
+	if result.Error != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "aggregating outputs failed: %s", result.Error)
+	}
+
+	return &aggregateResponse{
+		LedgerIndex: result.LedgerIndex,
+		Count:       result.Count,
+		Amount:      result.Amount,
+	}, nil
+}
+
 func outputsResponseFromResult(result *indexer.IndexerResult) (*outputsResponse, error) {
 	if result.Error != nil {
+		if errors.Is(result.Error, indexer.ErrCursorExpired) {
+			return nil, errors.WithMessagef(echo.NewHTTPError(http.StatusGone), "the query needs to be started again: %s", result.Error)
+		}
 		return nil, errors.WithMessagef(echo.ErrInternalServerError, "reading outputIDs failed: %s", result.Error)
 	}
 
@@ -711,6 +1251,73 @@ func parseCursorQueryParameter(c echo.Context) (string, int, error) {
 	return components[0], pageSize, nil
 }
 
+// anyOfAlternatives returns a context for every alternative set of filters of the request.
+func anyOfAlternatives(c echo.Context, depth int) ([]echo.Context, error) {
+	values := c.QueryParams()[QueryParameterAnyOf]
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	if depth >= maxAnyOfDepth {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "query parameter %s can only be nested %d times", QueryParameterAnyOf, maxAnyOfDepth-1)
+	}
+
+	if len(values) > maxAnyOfAlternatives {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "query parameter %s can only be given %d times", QueryParameterAnyOf, maxAnyOfAlternatives)
+	}
+
+	alternatives := make([]echo.Context, 0, len(values))
+	for _, value := range values {
+		query, err := url.ParseQuery(value)
+		if err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "query parameter %s has wrong format: %s", QueryParameterAnyOf, err)
+		}
+
+		for _, param := range []string{QueryParameterCursor, QueryParameterPageSize} {
+			if _, exists := query[param]; exists {
+				return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "query parameter %s must not contain %s", QueryParameterAnyOf, param)
+			}
+		}
+
+		// the filters of the alternative are parsed like the ones of the request
+		req := c.Request().Clone(c.Request().Context())
+		req.URL.RawQuery = query.Encode()
+		alternatives = append(alternatives, c.Echo().NewContext(req, c.Response()))
+	}
+
+	return alternatives, nil
+}
+
+// queryEcho is used to create the contexts for filters that are not given as query parameters of a request.
+var queryEcho = echo.New()
+
+// queryContext returns a context for the URL encoded query, so the filters can be parsed like the ones of a request.
+func queryContext(query string) echo.Context {
+	req := &http.Request{Method: http.MethodGet, URL: &url.URL{RawQuery: query}, Header: http.Header{}}
+	return queryEcho.NewContext(req, nil)
+}
+
+// sortingFromContext returns whether the results should be sorted by amount instead of creation time, and the sort order.
+func sortingFromContext(c echo.Context) (bool, indexer.SortOrder, error) {
+	var sortByAmount bool
+	switch c.QueryParam(QueryParameterSortBy) {
+	case "", "createdAt":
+	case "amount":
+		sortByAmount = true
+	default:
+		return false, 0, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid %s: %s, must be \"createdAt\" or \"amount\"", QueryParameterSortBy, c.QueryParam(QueryParameterSortBy))
+	}
+
+	switch c.QueryParam(QueryParameterSortOrder) {
+	case "", "asc":
+		return sortByAmount, indexer.SortAscending, nil
+	case "desc":
+		return sortByAmount, indexer.SortDescending, nil
+	default:
+		return false, 0, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid %s: %s, must be \"asc\" or \"desc\"", QueryParameterSortOrder, c.QueryParam(QueryParameterSortOrder))
+	}
+}
+
 func pageSizeFromContext(c echo.Context) int {
 	pageSize := deps.RestAPILimitsMaxResults
 	if len(c.QueryParam(QueryParameterPageSize)) > 0 {
@@ -15,3 +15,113 @@ type outputsResponse struct {
 	// The output IDs (transaction hash + output index) of the outputs on this address.
 	Items []string `json:"items"`
 }
+
+// aggregateResponse defines the response of a GET aggregate REST API call.
+type aggregateResponse struct {
+	// The ledger index at which the outputs were counted.
+	LedgerIndex milestone.Index `json:"ledgerIndex"`
+	// The number of unspent outputs that match the filters.
+	Count uint64 `json:"count"`
+	// The sum of the IOTA tokens of the outputs.
+	Amount uint64 `json:"amount"`
+}
+
+// consolidationGroupResponse defines a group of outputs that can be consolidated within a single transaction.
+type consolidationGroupResponse struct {
+	// The output IDs (transaction hash + output index) of the outputs to consolidate.
+	Items []string `json:"items"`
+	// The sum of the IOTA tokens of the outputs.
+	Amount uint64 `json:"amount"`
+	// The amount of distinct native tokens held by the outputs.
+	NativeTokens int `json:"nativeTokens"`
+	// The estimated storage deposit of the consolidated output.
+	StorageDeposit uint64 `json:"storageDeposit"`
+}
+
+// consolidationResponse defines the response of a GET outputs consolidation REST API call.
+type consolidationResponse struct {
+	// The ledger index at which these outputs where available at.
+	LedgerIndex milestone.Index `json:"ledgerIndex"`
+	// The output IDs (transaction hash + output index) of the unspent outputs on this address.
+	Items []string `json:"items"`
+	// The suggested consolidation groups, starting with the smallest outputs.
+	Groups []*consolidationGroupResponse `json:"groups"`
+}
+
+// queryFilterStatsResponse defines the statistics of the queries with the same combination of filters.
+type queryFilterStatsResponse struct {
+	// The table the queries were executed on.
+	Table string `json:"table"`
+	// The columns that were compared for equality.
+	EqualityColumns []string `json:"equalityColumns"`
+	// The columns that were compared with a range condition.
+	RangeColumns []string `json:"rangeColumns"`
+	// The amount of executed queries.
+	Count uint64 `json:"count"`
+	// The average duration of the queries in milliseconds.
+	AvgDuration float64 `json:"avgDuration"`
+	// The 95th percentile of the duration of the latest queries in milliseconds.
+	P95Duration float64 `json:"p95Duration"`
+	// The maximum duration of the queries in milliseconds.
+	MaxDuration float64 `json:"maxDuration"`
+}
+
+// indexRecommendationResponse defines a composite index recommended by the query statistics.
+type indexRecommendationResponse struct {
+	// The name of the index.
+	Name string `json:"name"`
+	// The table of the index.
+	Table string `json:"table"`
+	// The columns of the index in index order.
+	Columns []string `json:"columns"`
+	// The amount of queries that would have used the index.
+	Queries uint64 `json:"queries"`
+	// Whether the index already exists.
+	Exists bool `json:"exists"`
+}
+
+// queryStatsResponse defines the response of a GET query stats REST API call.
+type queryStatsResponse struct {
+	// The statistics of the filter combinations, sorted by their query count.
+	Queries []*queryFilterStatsResponse `json:"queries"`
+	// The composite indexes recommended for the most frequent filter combinations.
+	RecommendedIndexes []*indexRecommendationResponse `json:"recommendedIndexes"`
+}
+
+// createIndexesResponse defines the response of a POST maintenance indexes REST API call.
+type createIndexesResponse struct {
+	// The indexes that were created.
+	Created []*indexRecommendationResponse `json:"created"`
+}
+
+// rebuildProgressResponse defines a progress event streamed by a POST maintenance rebuild REST API call.
+type rebuildProgressResponse struct {
+	// The ledger index the index gets rebuilt at.
+	LedgerIndex milestone.Index `json:"ledgerIndex"`
+	// The amount of unspent outputs in the ledger.
+	TotalOutputs int `json:"totalOutputs"`
+	// The amount of unspent outputs that were already imported.
+	ImportedOutputs int `json:"importedOutputs"`
+	// Whether the rebuild is finished.
+	Done bool `json:"done"`
+	// The duration of the rebuild in milliseconds, once it is finished.
+	Duration float64 `json:"duration,omitempty"`
+	// The error that caused the rebuild to fail.
+	Error string `json:"error,omitempty"`
+}
+
+// webhookRequest defines the request of a POST webhooks REST API call.
+type webhookRequest struct {
+	// The URL the matching outputs are posted to.
+	URL string `json:"url"`
+	// The route whose filters are used ("outputs", "aliases", "nfts" or "foundries").
+	Route string `json:"route"`
+	// The filters of the route as URL query, e.g. "address=atoi1...&hasExpirationCondition=false".
+	Query string `json:"query"`
+}
+
+// webhooksResponse defines the response of a GET webhooks REST API call.
+type webhooksResponse struct {
+	// The registered webhooks.
+	Webhooks []*webhook `json:"webhooks"`
+}
@@ -0,0 +1,290 @@
+package indexer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/indexer"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/restapi"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// the routes whose filters can be used by a webhook.
+	webhookRouteOutputs   = "outputs"
+	webhookRouteAliases   = "aliases"
+	webhookRouteNFTs      = "nfts"
+	webhookRouteFoundries = "foundries"
+
+	// the maximum amount of webhooks that can be registered via the API.
+	maxRegisteredWebhooks = 100
+)
+
+var (
+	// ErrWebhookNotFound is returned if a webhook with the given ID doesn't exist.
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrWebhookFromConfig is returned if a webhook of the config should be removed via the API.
+	ErrWebhookFromConfig = errors.New("webhooks of the config can't be removed")
+)
+
+// webhookConfig defines a webhook in the config.
+type webhookConfig struct {
+	// The URL the matching outputs are posted to.
+	URL string `json:"url"`
+	// The route whose filters are used ("outputs", "aliases", "nfts" or "foundries").
+	Route string `json:"route"`
+	// The filters as URL query, e.g. "address=atoi1...&hasExpirationCondition=false".
+	Query string `json:"query"`
+}
+
+// webhook posts the newly booked outputs that match its filters to its URL.
+type webhook struct {
+	// The ID of the webhook.
+	ID string `json:"id"`
+	// The URL the matching outputs are posted to.
+	URL string `json:"url"`
+	// The route whose filters are used.
+	Route string `json:"route"`
+	// The filters as URL query.
+	Query string `json:"query"`
+	// Whether the webhook was defined in the config.
+	FromConfig bool `json:"fromConfig"`
+}
+
+// webhookPayload is the JSON payload posted to a webhook.
+type webhookPayload struct {
+	// The ID of the webhook.
+	WebhookID string `json:"webhookId"`
+	// The ledger index at which the outputs were booked.
+	LedgerIndex milestone.Index `json:"ledgerIndex"`
+	// The output IDs of the newly booked outputs that match the filters.
+	Items []string `json:"items"`
+}
+
+// webhooks holds the registered webhooks and posts the matching outputs to them.
+type webhooks struct {
+	sync.RWMutex
+
+	hooks  map[string]*webhook
+	client *http.Client
+}
+
+func newWebhooks(timeout time.Duration) *webhooks {
+	return &webhooks{
+		hooks:  make(map[string]*webhook),
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// validate checks the URL and the filters of the webhook.
+func (w *webhooks) validate(hook *webhook) error {
+	parsedURL, err := url.Parse(hook.URL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return errors.WithMessagef(restapi.ErrInvalidParameter, "invalid webhook URL: %s", hook.URL)
+	}
+
+	query, err := url.ParseQuery(hook.Query)
+	if err != nil {
+		return errors.WithMessagef(restapi.ErrInvalidParameter, "invalid webhook query: %s", err)
+	}
+	for _, param := range []string{QueryParameterCursor, QueryParameterPageSize, QueryParameterSortBy, QueryParameterSortOrder} {
+		if _, exists := query[param]; exists {
+			return errors.WithMessagef(restapi.ErrInvalidParameter, "webhook query must not contain %s", param)
+		}
+	}
+
+	_, err = w.matchingOutputs(hook, 0)
+	return err
+}
+
+// add registers the webhook with a new ID.
+func (w *webhooks) add(hook *webhook) error {
+	if err := w.validate(hook); err != nil {
+		return err
+	}
+
+	idBytes := make([]byte, 8)
+	if _, err := rand.Read(idBytes); err != nil {
+		return err
+	}
+	hook.ID = hex.EncodeToString(idBytes)
+
+	w.Lock()
+	defer w.Unlock()
+
+	if !hook.FromConfig {
+		registered := 0
+		for _, existing := range w.hooks {
+			if !existing.FromConfig {
+				registered++
+			}
+		}
+		if registered >= maxRegisteredWebhooks {
+			return errors.WithMessagef(restapi.ErrInvalidParameter, "only %d webhooks can be registered", maxRegisteredWebhooks)
+		}
+	}
+
+	w.hooks[hook.ID] = hook
+	return nil
+}
+
+// remove deregisters the webhook with the given ID.
+func (w *webhooks) remove(id string) error {
+	w.Lock()
+	defer w.Unlock()
+
+	hook, exists := w.hooks[id]
+	if !exists {
+		return ErrWebhookNotFound
+	}
+	if hook.FromConfig {
+		return ErrWebhookFromConfig
+	}
+
+	delete(w.hooks, id)
+	return nil
+}
+
+// list returns all webhooks ordered by their ID.
+func (w *webhooks) list() []*webhook {
+	w.RLock()
+	defer w.RUnlock()
+
+	hooks := make([]*webhook, 0, len(w.hooks))
+	for _, hook := range w.hooks {
+		hooks = append(hooks, hook)
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].ID < hooks[j].ID })
+
+	return hooks
+}
+
+// matchingOutputs returns the outputs that were booked at the ledger index and match the filters of the webhook.
+func (w *webhooks) matchingOutputs(hook *webhook, ledgerIndex milestone.Index) (iotago.OutputIDs, error) {
+	c := queryContext(hook.Query)
+
+	var result *indexer.IndexerResult
+	switch hook.Route {
+	case webhookRouteOutputs:
+		filters, err := extendedOutputFilters(c, 0)
+		if err != nil {
+			return nil, err
+		}
+		result = deps.Indexer.ExtendedOutputsWithFilters(append(filters, indexer.ExtendedOutputBookedAt(ledgerIndex))...)
+
+	case webhookRouteAliases:
+		filters, err := aliasFilters(c, 0)
+		if err != nil {
+			return nil, err
+		}
+		result = deps.Indexer.AliasOutputsWithFilters(append(filters, indexer.AliasBookedAt(ledgerIndex))...)
+
+	case webhookRouteNFTs:
+		filters, err := nftFilters(c, 0)
+		if err != nil {
+			return nil, err
+		}
+		result = deps.Indexer.NFTOutputsWithFilters(append(filters, indexer.NFTBookedAt(ledgerIndex))...)
+
+	case webhookRouteFoundries:
+		filters, err := foundryFilters(c, 0)
+		if err != nil {
+			return nil, err
+		}
+		result = deps.Indexer.FoundryOutputsWithFilters(append(filters, indexer.FoundryBookedAt(ledgerIndex))...)
+
+	default:
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid webhook route: %s, must be one of \"%s\", \"%s\", \"%s\" or \"%s\"", hook.Route, webhookRouteOutputs, webhookRouteAliases, webhookRouteNFTs, webhookRouteFoundries)
+	}
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.OutputIDs, nil
+}
+
+// notify queries the outputs booked at the ledger index for every webhook and queues the posts of the matching ones.
+// It is called after the ledger update was applied to the indexer.
+func (w *webhooks) notify(ledgerIndex milestone.Index) {
+	for _, hook := range w.list() {
+		outputIDs, err := w.matchingOutputs(hook, ledgerIndex)
+		if err != nil {
+			Plugin.LogWarnf("failed to query the outputs of webhook %s: %s", hook.ID, err)
+			continue
+		}
+
+		if len(outputIDs) == 0 {
+			continue
+		}
+
+		webhookWorkerPool.Submit(hook, &webhookPayload{
+			WebhookID:   hook.ID,
+			LedgerIndex: ledgerIndex,
+			Items:       outputIDs.ToHex(),
+		})
+	}
+}
+
+// post posts the payload to the webhook. Failures are logged and not retried.
+func (w *webhooks) post(hook *webhook, payload *webhookPayload) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		Plugin.LogWarnf("failed to marshal the outputs of webhook %s: %s", hook.ID, err)
+		return
+	}
+
+	res, err := w.client.Post(hook.URL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		Plugin.LogWarnf("failed to post the outputs of webhook %s to %s: %s", hook.ID, hook.URL, err)
+		return
+	}
+	_ = res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		Plugin.LogWarnf("failed to post the outputs of webhook %s to %s: status code %d", hook.ID, hook.URL, res.StatusCode)
+	}
+}
+
+func registerWebhook(c echo.Context) (*webhook, error) {
+	request := &webhookRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	hook := &webhook{URL: request.URL, Route: request.Route, Query: request.Query}
+	if err := hooks.add(hook); err != nil {
+		return nil, err
+	}
+
+	Plugin.LogInfof("registered webhook %s for %s: %s?%s", hook.ID, hook.URL, hook.Route, hook.Query)
+	return hook, nil
+}
+
+func removeWebhook(c echo.Context) error {
+	id := c.Param(ParameterWebhookID)
+
+	if err := hooks.remove(id); err != nil {
+		switch {
+		case errors.Is(err, ErrWebhookNotFound):
+			return errors.WithMessagef(echo.ErrNotFound, "webhook not found: %s", id)
+		case errors.Is(err, ErrWebhookFromConfig):
+			return errors.WithMessagef(echo.ErrForbidden, "webhook %s is defined in the config", id)
+		default:
+			return err
+		}
+	}
+
+	Plugin.LogInfof("removed webhook %s", id)
+	return nil
+}
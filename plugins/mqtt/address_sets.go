@@ -0,0 +1,188 @@
+package mqtt
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrAddressSetsLimitReached is returned if no more address sets can be registered.
+	ErrAddressSetsLimitReached = errors.New("address sets limit reached")
+)
+
+// addressSet is a set of addresses whose outputs are published on an aggregated topic.
+type addressSet struct {
+	// the bech32 addresses of the set.
+	addresses []string
+	// the client which registered the set.
+	clientID string
+	// the last time the set was registered or its topic had subscribers.
+	lastActive time.Time
+}
+
+// addressSets keeps the address sets registered by the clients.
+// A set is removed as soon as the last client unsubscribed from its topic,
+// or if its topic had no subscribers for the subscribe timeout.
+type addressSets struct {
+	sync.RWMutex
+
+	subscribeTimeout time.Duration
+	// the maximum amount of registered sets (0 = unlimited).
+	maxSets int
+	// the maximum amount of registered sets per client (0 = unlimited).
+	maxSetsPerClient int
+	// tells whether the topic of the set with the given ID has subscribers.
+	hasSubscribers func(setID string) bool
+
+	// the registered sets by their ID.
+	sets map[string]*addressSet
+	// the IDs of the sets by the bech32 addresses they contain.
+	setIDsByAddress map[string]map[string]struct{}
+	// the amount of registered sets by the clients which registered them.
+	setCountByClient map[string]int
+}
+
+func newAddressSets(subscribeTimeout time.Duration, maxSets int, maxSetsPerClient int, hasSubscribers func(setID string) bool) *addressSets {
+	return &addressSets{
+		subscribeTimeout: subscribeTimeout,
+		maxSets:          maxSets,
+		maxSetsPerClient: maxSetsPerClient,
+		hasSubscribers:   hasSubscribers,
+		sets:             make(map[string]*addressSet),
+		setIDsByAddress:  make(map[string]map[string]struct{}),
+		setCountByClient: make(map[string]int),
+	}
+}
+
+// register registers a new set of the given client with the given bech32 addresses and returns its ID.
+func (s *addressSets) register(clientID string, addresses []string) (string, error) {
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", err
+	}
+	setID := hex.EncodeToString(idBytes)
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.removeExpiredWithoutLocking()
+
+	if s.maxSets > 0 && len(s.sets) >= s.maxSets {
+		return "", errors.WithMessagef(ErrAddressSetsLimitReached, "max: %d", s.maxSets)
+	}
+	if s.maxSetsPerClient > 0 && s.setCountByClient[clientID] >= s.maxSetsPerClient {
+		return "", errors.WithMessagef(ErrAddressSetsLimitReached, "max per client: %d", s.maxSetsPerClient)
+	}
+
+	s.sets[setID] = &addressSet{
+		addresses:  addresses,
+		clientID:   clientID,
+		lastActive: time.Now(),
+	}
+	s.setCountByClient[clientID]++
+
+	for _, address := range addresses {
+		setIDs, exists := s.setIDsByAddress[address]
+		if !exists {
+			setIDs = make(map[string]struct{})
+			s.setIDsByAddress[address] = setIDs
+		}
+		setIDs[setID] = struct{}{}
+	}
+
+	return setID, nil
+}
+
+// subscribed marks the set as active, so that it doesn't expire while its topic is subscribed.
+func (s *addressSets) subscribed(setID string) {
+	s.Lock()
+	defer s.Unlock()
+
+	if set, exists := s.sets[setID]; exists {
+		set.lastActive = time.Now()
+	}
+}
+
+// removeExpired removes the sets whose topic had no subscribers for the subscribe timeout.
+func (s *addressSets) removeExpired() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.removeExpiredWithoutLocking()
+}
+
+// remove removes the set with the given ID.
+func (s *addressSets) remove(setID string) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.removeWithoutLocking(setID)
+}
+
+// setIDs returns the IDs of the sets that contain the given bech32 address.
+func (s *addressSets) setIDs(address string) []string {
+	s.RLock()
+	defer s.RUnlock()
+
+	setIDs := make([]string, 0, len(s.setIDsByAddress[address]))
+	for setID := range s.setIDsByAddress[address] {
+		setIDs = append(setIDs, setID)
+	}
+
+	return setIDs
+}
+
+func (s *addressSets) removeWithoutLocking(setID string) {
+	set, exists := s.sets[setID]
+	if !exists {
+		return
+	}
+
+	for _, address := range set.addresses {
+		delete(s.setIDsByAddress[address], setID)
+		if len(s.setIDsByAddress[address]) == 0 {
+			delete(s.setIDsByAddress, address)
+		}
+	}
+
+	s.setCountByClient[set.clientID]--
+	if s.setCountByClient[set.clientID] <= 0 {
+		delete(s.setCountByClient, set.clientID)
+	}
+
+	delete(s.sets, setID)
+}
+
+// removeExpiredWithoutLocking removes the sets whose topic had no subscribers for the subscribe timeout.
+func (s *addressSets) removeExpiredWithoutLocking() {
+	now := time.Now()
+	for setID, set := range s.sets {
+		if s.hasSubscribers != nil && s.hasSubscribers(setID) {
+			set.lastActive = now
+			continue
+		}
+
+		if now.Sub(set.lastActive) > s.subscribeTimeout {
+			s.removeWithoutLocking(setID)
+		}
+	}
+}
+
+// addressSetIDFromTopic returns the ID of the address set of the topic, or an empty string if it is no address set topic.
+func addressSetIDFromTopic(topicName string) string {
+	if strings.HasPrefix(topicName, "address-sets/") && strings.HasSuffix(topicName, "/outputs") {
+		setID := strings.Replace(topicName, "address-sets/", "", 1)
+		setID = strings.Replace(setID, "/outputs", "", 1)
+
+		if _, err := hex.DecodeString(setID); err != nil || len(setID) != 32 {
+			return ""
+		}
+		return setID
+	}
+	return ""
+}
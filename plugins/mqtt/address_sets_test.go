@@ -0,0 +1,68 @@
+package mqtt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestAddressSetsLimits(t *testing.T) {
+	sets := newAddressSets(time.Minute, 3, 2, nil)
+
+	setID1, err := sets.register("client1", []string{"address1"})
+	require.NoError(t, err)
+	_, err = sets.register("client1", []string{"address2"})
+	require.NoError(t, err)
+
+	// the client reached its limit
+	_, err = sets.register("client1", []string{"address3"})
+	require.ErrorIs(t, err, ErrAddressSetsLimitReached)
+
+	// other clients can still register sets until the global limit is reached
+	_, err = sets.register("client2", []string{"address3"})
+	require.NoError(t, err)
+	_, err = sets.register("client3", []string{"address4"})
+	require.ErrorIs(t, err, ErrAddressSetsLimitReached)
+
+	// removed sets free the limits
+	sets.remove(setID1)
+	_, err = sets.register("client1", []string{"address1"})
+	require.NoError(t, err)
+	require.Len(t, sets.setIDs("address1"), 1)
+}
+
+func TestAddressSetsExpiry(t *testing.T) {
+	subscribedSetID := atomic.NewString("")
+	sets := newAddressSets(50*time.Millisecond, 0, 1, func(setID string) bool {
+		return setID == subscribedSetID.Load()
+	})
+
+	unsubscribedSetID, err := sets.register("client1", []string{"address1"})
+	require.NoError(t, err)
+
+	setID, err := sets.register("client2", []string{"address1"})
+	require.NoError(t, err)
+	subscribedSetID.Store(setID)
+
+	time.Sleep(100 * time.Millisecond)
+	sets.removeExpired()
+
+	// only the set whose topic has subscribers is kept
+	require.Equal(t, []string{setID}, sets.setIDs("address1"))
+	require.NotContains(t, sets.sets, unsubscribedSetID)
+
+	// the client of the expired set can register a new one
+	_, err = sets.register("client1", []string{"address2"})
+	require.NoError(t, err)
+
+	// the set expires once its topic has no subscribers anymore
+	subscribedSetID.Store("")
+	time.Sleep(100 * time.Millisecond)
+	sets.removeExpired()
+
+	require.Empty(t, sets.setIDs("address1"))
+	require.Empty(t, sets.sets)
+	require.Empty(t, sets.setCountByClient)
+}
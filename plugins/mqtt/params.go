@@ -1,8 +1,11 @@
 package mqtt
 
 import (
+	"time"
+
 	flag "github.com/spf13/pflag"
 
+	mqttpkg "github.com/gohornet/hornet/pkg/mqtt"
 	"github.com/gohornet/hornet/pkg/node"
 )
 
@@ -15,6 +18,51 @@ const (
 	CfgMQTTWorkerCount = "mqtt.workerCount"
 	// the number of deleted topics that trigger a garbage collection of the topic manager.
 	CfgMQTTTopicCleanupThreshold = "mqtt.topicCleanupThreshold"
+	// the maximum number of messages that are queued for a client of the MQTT over WebSocket route.
+	CfgMQTTWebSocketClientQueueSize = "mqtt.websocket.clientQueueSize"
+	// what to do if the queue of a client of the MQTT over WebSocket route is full ("drop" = drop new messages, "disconnect" = disconnect the client).
+	CfgMQTTWebSocketSlowClientPolicy = "mqtt.websocket.slowClientPolicy"
+	// the QoS level and retain options of the topics, every entry consists of a "topic" filter, a "qos" level and a "retain" flag.
+	CfgMQTTTopics = "mqtt.topics"
+	// the bind address on which the MQTT broker listens for TLS connections (empty = disabled).
+	CfgMQTTTLSBindAddress = "mqtt.tls.bindAddress"
+	// the path to the certificate of the MQTT broker.
+	CfgMQTTTLSCertPath = "mqtt.tls.certPath"
+	// the path to the private key of the certificate of the MQTT broker.
+	CfgMQTTTLSKeyPath = "mqtt.tls.keyPath"
+	// the path to the CA certificate the client certificates are verified with (empty = no client certificates).
+	CfgMQTTTLSCACertPath = "mqtt.tls.caCertPath"
+	// whether the clients of the MQTT broker need to authenticate.
+	CfgMQTTAuthEnabled = "mqtt.auth.enabled"
+	// the clients that are allowed to connect, every user consists of a "username", a "passwordHash", a "passwordSalt"
+	// and the "topics" filters the client is allowed to subscribe to (empty = all topics).
+	CfgMQTTAuthUsers = "mqtt.auth.users"
+	// whether the clients can authenticate with a JWT of the REST API as password.
+	CfgMQTTAuthJWTEnabled = "mqtt.auth.jwtEnabled"
+	// the maximum number of addresses a client can register in an address set.
+	CfgMQTTAddressSetsMaxAddresses = "mqtt.addressSets.maxAddresses"
+	// the time after which a registered address set is removed if its topic has no subscribers.
+	CfgMQTTAddressSetsSubscribeTimeout = "mqtt.addressSets.subscribeTimeout"
+	// the maximum number of address sets that can be registered (0 = unlimited).
+	CfgMQTTAddressSetsMaxSets = "mqtt.addressSets.maxSets"
+	// the maximum number of address sets a single client can register (0 = unlimited).
+	CfgMQTTAddressSetsMaxSetsPerClient = "mqtt.addressSets.maxSetsPerClient"
+	// the URI of an external MQTT broker the events are published to instead of running the embedded broker (empty = disabled).
+	CfgMQTTExternalBrokerURI = "mqtt.external.brokerURI"
+	// the client ID that is used to connect to the external MQTT broker.
+	CfgMQTTExternalClientID = "mqtt.external.clientID"
+	// the username for the external MQTT broker (empty = no auth).
+	CfgMQTTExternalUsername = "mqtt.external.username"
+	// the password for the external MQTT broker.
+	CfgMQTTExternalPassword = "mqtt.external.password"
+	// the path to the CA certificate to verify the external MQTT broker with (empty = system CAs).
+	CfgMQTTExternalTLSCACertPath = "mqtt.external.tls.caCertPath"
+	// the path to the client certificate for the external MQTT broker (empty = no client certificate).
+	CfgMQTTExternalTLSCertPath = "mqtt.external.tls.certPath"
+	// the path to the private key of the client certificate for the external MQTT broker.
+	CfgMQTTExternalTLSKeyPath = "mqtt.external.tls.keyPath"
+	// whether to skip the verification of the certificate of the external MQTT broker.
+	CfgMQTTExternalTLSInsecureSkipVerify = "mqtt.external.tls.insecureSkipVerify"
 )
 
 var params = &node.PluginParams{
@@ -25,8 +73,28 @@ var params = &node.PluginParams{
 			fs.Int(CfgMQTTWSPort, 1888, "port of the WebSocket MQTT broker")
 			fs.Int(CfgMQTTWorkerCount, 100, "number of parallel workers the MQTT broker uses to publish messages")
 			fs.Int(CfgMQTTTopicCleanupThreshold, 10000, "number of deleted topics that trigger a garbage collection of the topic manager")
+			fs.Int(CfgMQTTWebSocketClientQueueSize, 1000, "maximum number of messages that are queued for a client of the MQTT over WebSocket route")
+			fs.String(CfgMQTTWebSocketSlowClientPolicy, string(mqttpkg.SlowClientPolicyDrop), "what to do if the queue of a client of the MQTT over WebSocket route is full (\"drop\" = drop new messages, \"disconnect\" = disconnect the client)")
+			fs.String(CfgMQTTTLSBindAddress, "", "bind address on which the MQTT broker listens for TLS connections (empty = disabled)")
+			fs.String(CfgMQTTTLSCertPath, "", "path to the certificate of the MQTT broker")
+			fs.String(CfgMQTTTLSKeyPath, "", "path to the private key of the certificate of the MQTT broker")
+			fs.String(CfgMQTTTLSCACertPath, "", "path to the CA certificate the client certificates are verified with (empty = no client certificates)")
+			fs.Bool(CfgMQTTAuthEnabled, false, "whether the clients of the MQTT broker need to authenticate")
+			fs.Bool(CfgMQTTAuthJWTEnabled, true, "whether the clients can authenticate with a JWT of the REST API as password")
+			fs.Int(CfgMQTTAddressSetsMaxAddresses, 1000, "maximum number of addresses a client can register in an address set")
+			fs.Duration(CfgMQTTAddressSetsSubscribeTimeout, 1*time.Minute, "time after which a registered address set is removed if its topic has no subscribers")
+			fs.Int(CfgMQTTAddressSetsMaxSets, 1000, "maximum number of address sets that can be registered (0 = unlimited)")
+			fs.Int(CfgMQTTAddressSetsMaxSetsPerClient, 10, "maximum number of address sets a single client can register (0 = unlimited)")
+			fs.String(CfgMQTTExternalBrokerURI, "", "URI of an external MQTT broker the events are published to instead of running the embedded broker (empty = disabled)")
+			fs.String(CfgMQTTExternalClientID, "hornet", "client ID that is used to connect to the external MQTT broker")
+			fs.String(CfgMQTTExternalUsername, "", "username for the external MQTT broker (empty = no auth)")
+			fs.String(CfgMQTTExternalPassword, "", "password for the external MQTT broker")
+			fs.String(CfgMQTTExternalTLSCACertPath, "", "path to the CA certificate to verify the external MQTT broker with (empty = system CAs)")
+			fs.String(CfgMQTTExternalTLSCertPath, "", "path to the client certificate for the external MQTT broker (empty = no client certificate)")
+			fs.String(CfgMQTTExternalTLSKeyPath, "", "path to the private key of the client certificate for the external MQTT broker")
+			fs.Bool(CfgMQTTExternalTLSInsecureSkipVerify, false, "whether to skip the verification of the certificate of the external MQTT broker")
 			return fs
 		}(),
 	},
-	Masked: nil,
+	Masked: []string{CfgMQTTExternalPassword},
 }
@@ -2,13 +2,19 @@ package mqtt
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
-	"net/url"
+	"io/ioutil"
+	"strings"
 
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
 	"go.uber.org/dig"
 
+	"github.com/gohornet/hornet/pkg/expiration"
+	"github.com/gohornet/hornet/pkg/jwt"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/model/syncmanager"
@@ -17,9 +23,12 @@ import (
 	"github.com/gohornet/hornet/pkg/node"
 	"github.com/gohornet/hornet/pkg/shutdown"
 	"github.com/gohornet/hornet/pkg/tangle"
+	"github.com/gohornet/hornet/pkg/whiteflag"
 	"github.com/gohornet/hornet/plugins/restapi"
+	restapiv2 "github.com/gohornet/hornet/plugins/restapi/v2"
 	"github.com/iotaledger/hive.go/configuration"
 	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/timeutil"
 	"github.com/iotaledger/hive.go/workerpool"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
@@ -50,6 +59,10 @@ var (
 	Plugin *node.Plugin
 	deps   dependencies
 
+	// the publisher of the events, either the embedded or the external broker.
+	publisher      mqttpkg.Publisher
+	externalBroker *mqttpkg.ExternalBroker
+
 	newLatestMilestoneWorkerPool    *workerpool.WorkerPool
 	newConfirmedMilestoneWorkerPool *workerpool.WorkerPool
 
@@ -57,8 +70,14 @@ var (
 	messageMetadataWorkerPool *workerpool.WorkerPool
 	utxoOutputWorkerPool      *workerpool.WorkerPool
 	receiptWorkerPool         *workerpool.WorkerPool
+	expirationWorkerPool      *workerpool.WorkerPool
+	confirmationWorkerPool    *workerpool.WorkerPool
+
+	topicSubscriptionWorkerPool   *workerpool.WorkerPool
+	topicUnsubscriptionWorkerPool *workerpool.WorkerPool
 
-	topicSubscriptionWorkerPool *workerpool.WorkerPool
+	// the address sets registered by the clients of the embedded broker.
+	registeredAddressSets *addressSets
 
 	wasSyncBefore = false
 )
@@ -75,22 +94,32 @@ type dependencies struct {
 	Bech32HRP                             iotago.NetworkPrefix         `name:"bech32HRP"`
 	Echo                                  *echo.Echo                   `optional:"true"`
 	MQTTBroker                            *mqttpkg.Broker
+	MQTTWebSocketBridge                   *mqttpkg.WebSocketBridge
+	ExpirationWatcher                     *expiration.Watcher `optional:"true"`
 }
 
 func provide(c *dig.Container) {
 
 	type brokerDeps struct {
 		dig.In
-		NodeConfig *configuration.Configuration `name:"nodeConfig"`
+		NodeConfig     *configuration.Configuration `name:"nodeConfig"`
+		Host           host.Host
+		NodePrivateKey crypto.PrivKey `name:"nodePrivateKey"`
 	}
 
 	if err := c.Provide(func(deps brokerDeps) *mqttpkg.Broker {
+		if deps.NodeConfig.String(CfgMQTTExternalBrokerURI) != "" {
+			// the events are published to the external broker, the embedded broker is not needed
+			return nil
+		}
+
 		mqttBroker, err := mqttpkg.NewBroker(deps.NodeConfig.String(CfgMQTTBindAddress), deps.NodeConfig.Int(CfgMQTTWSPort), "/ws", deps.NodeConfig.Int(CfgMQTTWorkerCount), func(topic []byte) {
 			Plugin.LogDebugf("Subscribe to topic: %s", string(topic))
 			topicSubscriptionWorkerPool.TrySubmit(topic)
 		}, func(topic []byte) {
 			Plugin.LogDebugf("Unsubscribe from topic: %s", string(topic))
-		}, deps.NodeConfig.Int(CfgMQTTTopicCleanupThreshold))
+			topicUnsubscriptionWorkerPool.TrySubmit(topic)
+		}, deps.NodeConfig.Int(CfgMQTTTopicCleanupThreshold), loadTopicsOptions(deps.NodeConfig), brokerTLSConfig(deps.NodeConfig), brokerAuth(deps.NodeConfig, deps.Host, deps.NodePrivateKey))
 		if err != nil {
 			Plugin.LogFatalf("MQTT broker init failed! %s", err)
 		}
@@ -98,6 +127,30 @@ func provide(c *dig.Container) {
 	}); err != nil {
 		Plugin.LogPanic(err)
 	}
+
+	type bridgeDeps struct {
+		dig.In
+		NodeConfig *configuration.Configuration `name:"nodeConfig"`
+		MQTTBroker *mqttpkg.Broker
+	}
+
+	if err := c.Provide(func(deps bridgeDeps) *mqttpkg.WebSocketBridge {
+		if deps.MQTTBroker == nil {
+			return nil
+		}
+
+		bridge, err := mqttpkg.NewWebSocketBridge(
+			deps.NodeConfig.String(CfgMQTTBindAddress),
+			deps.NodeConfig.Int(CfgMQTTWebSocketClientQueueSize),
+			mqttpkg.SlowClientPolicy(deps.NodeConfig.String(CfgMQTTWebSocketSlowClientPolicy)),
+		)
+		if err != nil {
+			Plugin.LogFatalf("MQTT WebSocket init failed! %s", err)
+		}
+		return bridge
+	}); err != nil {
+		Plugin.LogPanic(err)
+	}
 }
 
 func configure() {
@@ -106,6 +159,13 @@ func configure() {
 		Plugin.LogPanic("RestAPI plugin needs to be enabled to use the MQTT plugin")
 	}
 
+	if deps.MQTTBroker != nil {
+		publisher = deps.MQTTBroker
+	} else {
+		configureExternalBroker()
+		publisher = externalBroker
+	}
+
 	newLatestMilestoneWorkerPool = workerpool.New(func(task workerpool.Task) {
 		publishLatestMilestone(task.Param(0).(*storage.CachedMilestone)) // milestone pass +1
 		task.Return(nil)
@@ -136,12 +196,29 @@ func configure() {
 		task.Return(nil)
 	}, workerpool.WorkerCount(workerCount), workerpool.QueueSize(workerQueueSize))
 
+	expirationWorkerPool = workerpool.New(func(task workerpool.Task) {
+		publishExpiration(task.Param(0).(*expiration.Notification))
+		task.Return(nil)
+	}, workerpool.WorkerCount(workerCount), workerpool.QueueSize(workerQueueSize))
+
+	confirmationWorkerPool = workerpool.New(func(task workerpool.Task) {
+		confirmation := task.Param(0).(*whiteflag.Confirmation)
+		publishConflictingTransactions(confirmation)
+		publishUnreferencedMessages(confirmation.MilestoneIndex)
+		task.Return(nil)
+	}, workerpool.WorkerCount(workerCount), workerpool.QueueSize(workerQueueSize))
+
 	topicSubscriptionWorkerPool = workerpool.New(func(task workerpool.Task) {
 		defer task.Return(nil)
 
 		topic := task.Param(0).([]byte)
 		topicName := string(topic)
 
+		if setID := addressSetIDFromTopic(topicName); setID != "" {
+			registeredAddressSets.subscribed(setID)
+			return
+		}
+
 		if messageID := messageIDFromTopic(topicName); messageID != nil {
 			if cachedMsgMeta := deps.Storage.CachedMessageMetadataOrNil(messageID); cachedMsgMeta != nil {
 				if _, added := messageMetadataWorkerPool.TrySubmit(cachedMsgMeta); added {
@@ -208,37 +285,171 @@ func configure() {
 
 	}, workerpool.WorkerCount(workerCount), workerpool.QueueSize(workerQueueSize), workerpool.FlushTasksAtShutdown(true))
 
-	setupWebSocketRoute()
+	// the topic manager of the broker holds its lock while calling the unsubscribe handler,
+	// so the subscribers of the topic are checked asynchronously.
+	topicUnsubscriptionWorkerPool = workerpool.New(func(task workerpool.Task) {
+		defer task.Return(nil)
+
+		topicName := string(task.Param(0).([]byte))
+
+		if setID := addressSetIDFromTopic(topicName); setID != "" {
+			if !deps.MQTTBroker.HasSubscribers(topicName) {
+				// the last client unsubscribed from the topic of the set
+				registeredAddressSets.remove(setID)
+			}
+		}
+	}, workerpool.WorkerCount(workerCount), workerpool.QueueSize(workerQueueSize), workerpool.FlushTasksAtShutdown(true))
+
+	if deps.MQTTBroker != nil {
+		registeredAddressSets = newAddressSets(
+			deps.NodeConfig.Duration(CfgMQTTAddressSetsSubscribeTimeout),
+			deps.NodeConfig.Int(CfgMQTTAddressSetsMaxSets),
+			deps.NodeConfig.Int(CfgMQTTAddressSetsMaxSetsPerClient),
+			func(setID string) bool {
+				return deps.MQTTBroker.HasSubscribers(strings.ReplaceAll(topicAddressSetOutputs, "{addressSetId}", setID))
+			},
+		)
+
+		setupWebSocketRoute()
+		setupRoutes(restapiv2.AddPlugin("mqtt/v1"))
+	}
 }
 
-func setupWebSocketRoute() {
+// loadTopicsOptions loads the QoS level and retain options of the topics.
+func loadTopicsOptions(nodeConfig *configuration.Configuration) mqttpkg.TopicsOptions {
+	var options []*mqttpkg.TopicOptions
+	if err := nodeConfig.Unmarshal(CfgMQTTTopics, &options); err != nil {
+		Plugin.LogPanicf("invalid MQTT topic options: %s", err)
+	}
 
-	// Configure MQTT WebSocket route
-	mqttWSUrl, err := url.Parse(fmt.Sprintf("http://%s:%s", deps.MQTTBroker.Config().Host, deps.MQTTBroker.Config().WsPort))
+	topicsOpts, err := mqttpkg.NewTopicsOptions(options)
 	if err != nil {
-		Plugin.LogFatalf("MQTT WebSocket init failed! %s", err)
+		Plugin.LogPanicf("invalid MQTT topic options: %s", err)
 	}
+	return topicsOpts
+}
 
-	wsGroup := deps.Echo.Group(RouteMQTT)
-	proxyConfig := middleware.ProxyConfig{
-		Skipper: middleware.DefaultSkipper,
-		Balancer: middleware.NewRoundRobinBalancer([]*middleware.ProxyTarget{
-			{
-				URL: mqttWSUrl,
-			},
-		}),
-		// We need to forward any calls to the MQTT route to the ws endpoint of our broker
-		Rewrite: map[string]string{
-			RouteMQTT: deps.MQTTBroker.Config().WsPath,
-		},
+// brokerTLSConfig returns the config of the TLS listener of the embedded broker, or nil if it is disabled.
+func brokerTLSConfig(nodeConfig *configuration.Configuration) *mqttpkg.TLSConfig {
+	if nodeConfig.String(CfgMQTTTLSBindAddress) == "" {
+		return nil
 	}
 
-	wsGroup.Use(middleware.ProxyWithConfig(proxyConfig))
+	return &mqttpkg.TLSConfig{
+		BindAddress: nodeConfig.String(CfgMQTTTLSBindAddress),
+		CertPath:    nodeConfig.String(CfgMQTTTLSCertPath),
+		KeyPath:     nodeConfig.String(CfgMQTTTLSKeyPath),
+		CACertPath:  nodeConfig.String(CfgMQTTTLSCACertPath),
+	}
 }
 
-func run() {
+// brokerAuth returns the authentication of the clients of the embedded broker, or nil if it is disabled.
+func brokerAuth(nodeConfig *configuration.Configuration, nodeHost host.Host, nodePrivateKey crypto.PrivKey) *mqttpkg.Auth {
+	if !nodeConfig.Bool(CfgMQTTAuthEnabled) {
+		return nil
+	}
 
-	Plugin.LogInfof("Starting MQTT Broker (port %s) ...", deps.MQTTBroker.Config().Port)
+	var users []*mqttpkg.AuthUser
+	if err := nodeConfig.Unmarshal(CfgMQTTAuthUsers, &users); err != nil {
+		Plugin.LogPanicf("invalid MQTT users: %s", err)
+	}
+
+	var verifyToken mqttpkg.TokenVerifier
+	if nodeConfig.Bool(CfgMQTTAuthJWTEnabled) {
+		// the clients use the same tokens as for the REST API
+		salt := nodeConfig.String(restapi.CfgRestAPIJWTAuthSalt)
+
+		jwtAuth, err := jwt.NewJWTAuth(salt, 0, nodeHost.ID().String(), nodePrivateKey)
+		if err != nil {
+			Plugin.LogPanicf("JWT auth initialization failed: %s", err)
+		}
+
+		verifyToken = func(token string) bool {
+			return jwtAuth.VerifyJWT(token, func(claims *jwt.AuthClaims) bool {
+				return claims.API && claims.VerifySubject(salt) && (len(claims.Scopes) == 0 || claims.HasScope(jwt.ScopeAPIRead))
+			})
+		}
+	}
+
+	auth, err := mqttpkg.NewAuth(users, verifyToken)
+	if err != nil {
+		Plugin.LogPanicf("invalid MQTT users: %s", err)
+	}
+	return auth
+}
+
+func configureExternalBroker() {
+	tlsConfig, err := externalBrokerTLSConfig()
+	if err != nil {
+		Plugin.LogPanicf("loading TLS config of the external MQTT broker failed: %s", err)
+	}
+
+	externalBroker, err = mqttpkg.NewExternalBroker(
+		deps.NodeConfig.String(CfgMQTTExternalBrokerURI),
+		deps.NodeConfig.String(CfgMQTTExternalClientID),
+		deps.NodeConfig.String(CfgMQTTExternalUsername),
+		deps.NodeConfig.String(CfgMQTTExternalPassword),
+		tlsConfig,
+		loadTopicsOptions(deps.NodeConfig),
+	)
+	if err != nil {
+		Plugin.LogPanicf("external MQTT broker init failed! %s", err)
+	}
+}
+
+// externalBrokerTLSConfig returns the TLS config for the connection to the external broker,
+// or nil if the default config should be used.
+func externalBrokerTLSConfig() (*tls.Config, error) {
+	caCertPath := deps.NodeConfig.String(CfgMQTTExternalTLSCACertPath)
+	certPath := deps.NodeConfig.String(CfgMQTTExternalTLSCertPath)
+	keyPath := deps.NodeConfig.String(CfgMQTTExternalTLSKeyPath)
+	insecureSkipVerify := deps.NodeConfig.Bool(CfgMQTTExternalTLSInsecureSkipVerify)
+
+	if caCertPath == "" && certPath == "" && !insecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecureSkipVerify, //nolint:gosec // explicitly configured by the user
+	}
+
+	if caCertPath != "" {
+		caCert, err := ioutil.ReadFile(caCertPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA certificate failed: %w", err)
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no valid CA certificate found in %s", caCertPath)
+		}
+	}
+
+	if certPath != "" {
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate failed: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func setupWebSocketRoute() {
+
+	// The broker writes to its clients synchronously, so the MQTT over WebSocket clients are connected
+	// via the bridge, which queues the messages for every client to handle slow consumers.
+	deps.Echo.GET(RouteMQTT, func(c echo.Context) error {
+		if err := deps.MQTTWebSocketBridge.ServeWebSocket(c.Response(), c.Request()); err != nil {
+			Plugin.LogDebugf("MQTT WebSocket connection failed: %s", err)
+		}
+		return nil
+	})
+}
+
+func run() {
 
 	onLatestMilestoneChanged := events.NewClosure(func(cachedMs *storage.CachedMilestone) {
 		if !wasSyncBefore {
@@ -307,25 +518,23 @@ func run() {
 		receiptWorkerPool.TrySubmit(receipt)
 	})
 
-	if err := Plugin.Daemon().BackgroundWorker("MQTT Broker", func(ctx context.Context) {
-		go func() {
-			deps.MQTTBroker.Start()
-			Plugin.LogInfof("Starting MQTT Broker (port %s) ... done", deps.MQTTBroker.Config().Port)
-		}()
+	onExpiration := events.NewClosure(func(notification *expiration.Notification) {
+		expirationWorkerPool.TrySubmit(notification)
+	})
 
-		if deps.MQTTBroker.Config().Port != "" {
-			Plugin.LogInfof("You can now listen to MQTT via: http://%s:%s", deps.MQTTBroker.Config().Host, deps.MQTTBroker.Config().Port)
+	onMilestoneConfirmed := events.NewClosure(func(confirmation *whiteflag.Confirmation) {
+		if !wasSyncBefore {
+			// Not sync
+			return
 		}
 
-		if deps.MQTTBroker.Config().TlsPort != "" {
-			Plugin.LogInfof("You can now listen to MQTT via: https://%s:%s", deps.MQTTBroker.Config().TlsHost, deps.MQTTBroker.Config().TlsPort)
-		}
+		confirmationWorkerPool.TrySubmit(confirmation)
+	})
 
-		<-ctx.Done()
-		Plugin.LogInfo("Stopping MQTT Broker ...")
-		Plugin.LogInfo("Stopping MQTT Broker ... done")
-	}, shutdown.PriorityMetricsPublishers); err != nil {
-		Plugin.LogPanicf("failed to start worker: %s", err)
+	if deps.MQTTBroker != nil {
+		runBroker()
+	} else {
+		runExternalBroker()
 	}
 
 	if err := Plugin.Daemon().BackgroundWorker("MQTT Events", func(ctx context.Context) {
@@ -342,14 +551,23 @@ func run() {
 		deps.Tangle.Events.NewUTXOSpent.Attach(onUTXOSpent)
 
 		deps.Tangle.Events.NewReceipt.Attach(onReceipt)
+		deps.Tangle.Events.MilestoneConfirmed.Attach(onMilestoneConfirmed)
+
+		if deps.ExpirationWatcher != nil {
+			deps.ExpirationWatcher.Events.ExpiringSoon.Attach(onExpiration)
+			deps.ExpirationWatcher.Events.Expired.Attach(onExpiration)
+		}
 
 		messagesWorkerPool.Start()
 		newLatestMilestoneWorkerPool.Start()
 		newConfirmedMilestoneWorkerPool.Start()
 		messageMetadataWorkerPool.Start()
 		topicSubscriptionWorkerPool.Start()
+		topicUnsubscriptionWorkerPool.Start()
 		utxoOutputWorkerPool.Start()
 		receiptWorkerPool.Start()
+		expirationWorkerPool.Start()
+		confirmationWorkerPool.Start()
 
 		<-ctx.Done()
 
@@ -364,17 +582,85 @@ func run() {
 		deps.Tangle.Events.NewUTXOSpent.Detach(onUTXOSpent)
 
 		deps.Tangle.Events.NewReceipt.Detach(onReceipt)
+		deps.Tangle.Events.MilestoneConfirmed.Detach(onMilestoneConfirmed)
+
+		if deps.ExpirationWatcher != nil {
+			deps.ExpirationWatcher.Events.ExpiringSoon.Detach(onExpiration)
+			deps.ExpirationWatcher.Events.Expired.Detach(onExpiration)
+		}
 
 		messagesWorkerPool.StopAndWait()
 		newLatestMilestoneWorkerPool.StopAndWait()
 		newConfirmedMilestoneWorkerPool.StopAndWait()
 		messageMetadataWorkerPool.StopAndWait()
 		topicSubscriptionWorkerPool.StopAndWait()
+		topicUnsubscriptionWorkerPool.StopAndWait()
 		utxoOutputWorkerPool.StopAndWait()
 		receiptWorkerPool.StopAndWait()
+		expirationWorkerPool.StopAndWait()
+		confirmationWorkerPool.StopAndWait()
 
 		Plugin.LogInfo("Stopping MQTT Events ... done")
 	}, shutdown.PriorityMetricsPublishers); err != nil {
 		Plugin.LogPanicf("failed to start worker: %s", err)
 	}
 }
+
+func runBroker() {
+
+	Plugin.LogInfof("Starting MQTT Broker (port %s) ...", deps.MQTTBroker.Config().Port)
+
+	if err := Plugin.Daemon().BackgroundWorker("MQTT Broker", func(ctx context.Context) {
+		go func() {
+			deps.MQTTBroker.Start()
+			Plugin.LogInfof("Starting MQTT Broker (port %s) ... done", deps.MQTTBroker.Config().Port)
+		}()
+
+		if deps.MQTTBroker.Config().Port != "" {
+			Plugin.LogInfof("You can now listen to MQTT via: http://%s:%s", deps.MQTTBroker.Config().Host, deps.MQTTBroker.Config().Port)
+		}
+
+		if deps.MQTTBroker.Config().TlsPort != "" {
+			Plugin.LogInfof("You can now listen to MQTT via: https://%s:%s", deps.MQTTBroker.Config().TlsHost, deps.MQTTBroker.Config().TlsPort)
+		}
+
+		<-ctx.Done()
+		Plugin.LogInfo("Stopping MQTT Broker ...")
+		Plugin.LogInfo("Stopping MQTT Broker ... done")
+	}, shutdown.PriorityMetricsPublishers); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+
+	if err := Plugin.Daemon().BackgroundWorker("MQTT Address Sets", func(ctx context.Context) {
+		// clients may disconnect without unsubscribing, so the sets without subscribers are removed periodically
+		ticker := timeutil.NewTicker(registeredAddressSets.removeExpired, deps.NodeConfig.Duration(CfgMQTTAddressSetsSubscribeTimeout), ctx)
+		ticker.WaitForGracefulShutdown()
+	}, shutdown.PriorityMetricsPublishers); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+}
+
+func runExternalBroker() {
+
+	brokerURI := deps.NodeConfig.String(CfgMQTTExternalBrokerURI)
+
+	Plugin.LogInfof("Connecting to external MQTT Broker (%s) ...", brokerURI)
+
+	if err := Plugin.Daemon().BackgroundWorker("MQTT Broker", func(ctx context.Context) {
+		go func() {
+			// failed connection attempts are retried until the node shuts down
+			if err := externalBroker.Connect(); err != nil {
+				Plugin.LogWarnf("Connecting to external MQTT Broker (%s) failed: %s", brokerURI, err)
+				return
+			}
+			Plugin.LogInfof("Connecting to external MQTT Broker (%s) ... done", brokerURI)
+		}()
+
+		<-ctx.Done()
+		Plugin.LogInfo("Disconnecting from external MQTT Broker ...")
+		externalBroker.Disconnect()
+		Plugin.LogInfo("Disconnecting from external MQTT Broker ... done")
+	}, shutdown.PriorityMetricsPublishers); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+}
@@ -0,0 +1,88 @@
+package mqtt
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/restapi"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// RouteAddressSets is the route for registering a set of addresses whose outputs are published on an aggregated topic.
+	// POST registers the bech32 addresses of the request and returns the topic of the set.
+	// The set is removed as soon as the last client unsubscribed from its topic, or if its topic has no subscribers for the subscribe timeout.
+	RouteAddressSets = "/address-sets"
+)
+
+// addressSetRequest defines the request of a POST address sets REST API call.
+type addressSetRequest struct {
+	// The bech32 addresses whose outputs are published on the topic of the set.
+	Addresses []string `json:"addresses"`
+}
+
+// addressSetResponse defines the response of a POST address sets REST API call.
+type addressSetResponse struct {
+	// The ID of the address set.
+	AddressSetID string `json:"addressSetId"`
+	// The topic the outputs of the addresses are published on.
+	Topic string `json:"topic"`
+}
+
+func setupRoutes(g *echo.Group) {
+
+	g.POST(RouteAddressSets, func(c echo.Context) error {
+		resp, err := registerAddressSet(c)
+		if err != nil {
+			return err
+		}
+
+		return restapi.JSONResponse(c, http.StatusCreated, resp)
+	})
+}
+
+func registerAddressSet(c echo.Context) (*addressSetResponse, error) {
+	request := &addressSetRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	if len(request.Addresses) == 0 {
+		return nil, errors.WithMessage(restapi.ErrInvalidParameter, "no addresses given")
+	}
+
+	maxAddresses := deps.NodeConfig.Int(CfgMQTTAddressSetsMaxAddresses)
+	if len(request.Addresses) > maxAddresses {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "too many addresses, max: %d", maxAddresses)
+	}
+
+	addresses := make([]string, 0, len(request.Addresses))
+	for _, addressParam := range request.Addresses {
+		hrp, address, err := iotago.ParseBech32(strings.ToLower(addressParam))
+		if err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid address: %s, error: %s", addressParam, err)
+		}
+
+		if hrp != deps.Bech32HRP {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid bech32 address, expected prefix: %s", deps.Bech32HRP)
+		}
+
+		addresses = append(addresses, address.Bech32(deps.Bech32HRP))
+	}
+
+	setID, err := registeredAddressSets.register(c.RealIP(), addresses)
+	if err != nil {
+		if errors.Is(err, ErrAddressSetsLimitReached) {
+			return nil, errors.WithMessage(echo.ErrTooManyRequests, err.Error())
+		}
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "registering address set failed, error: %s", err)
+	}
+
+	return &addressSetResponse{
+		AddressSetID: setID,
+		Topic:        strings.ReplaceAll(topicAddressSetOutputs, "{addressSetId}", setID),
+	}, nil
+}
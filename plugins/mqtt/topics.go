@@ -5,17 +5,24 @@ const (
 	topicMilestonesLatest    = "milestones/latest"
 	topicMilestonesConfirmed = "milestones/confirmed"
 
-	topicMessages           = "messages"
-	topicMessagesReferenced = "messages/referenced"
-	topicMessagesTaggedData = "messages/data/{tag}"
-	topicMessagesMetadata   = "messages/{messageId}/metadata"
+	topicMessages             = "messages"
+	topicMessagesReferenced   = "messages/referenced"
+	topicMessagesTaggedData   = "messages/data/{tag}"
+	topicMessagesMetadata     = "messages/{messageId}/metadata"
+	topicMessagesUnreferenced = "messages/unreferenced"
 
 	topicTransactionsIncludedMessage = "transactions/{transactionId}/included-message"
+	topicTransactionsConflicting     = "transactions/conflicting"
+	topicTransactionConflicting      = "transactions/{transactionId}/conflicting"
 
-	topicOutputs = "outputs/{outputId}"
+	topicOutputs            = "outputs/{outputId}"
+	topicOutputsExpirations = "outputs/expirations"
 
 	topicReceipts = "receipts"
 
 	topicAddressesOutput        = "addresses/{address}/outputs"
 	topicAddressesEd25519Output = "addresses/ed25519/{address}/outputs"
+	topicAddressesExpirations   = "addresses/{address}/expirations"
+
+	topicAddressSetOutputs = "address-sets/{addressSetId}/outputs"
 )
@@ -37,6 +37,26 @@ type messageMetadataPayload struct {
 	ShouldReattach *bool `json:"shouldReattach,omitempty"`
 }
 
+// conflictingTransactionPayload defines the payload of the conflicting transaction topics
+type conflictingTransactionPayload struct {
+	// The hex encoded message ID of the message that contains the transaction.
+	MessageID string `json:"messageId"`
+	// The hex encoded ID of the transaction.
+	TransactionID string `json:"transactionId"`
+	// The index of the milestone that marked the transaction as conflicting.
+	MilestoneIndex milestone.Index `json:"milestoneIndex"`
+	// The reason why the transaction is conflicting.
+	ConflictReason storage.Conflict `json:"conflictReason"`
+}
+
+// unreferencedMessagesPayload defines the payload of the unreferenced messages topic
+type unreferencedMessagesPayload struct {
+	// The index of the milestone that did not reference the messages.
+	MilestoneIndex milestone.Index `json:"milestoneIndex"`
+	// The hex encoded message IDs of the messages that were received before the milestone, but were not referenced by it.
+	MessageIDs []string `json:"messageIds"`
+}
+
 // outputPayload defines the payload of the output topics
 type outputPayload struct {
 	// The hex encoded message ID of the message.
@@ -10,10 +10,12 @@ import (
 
 	"github.com/gohornet/hornet/pkg/common"
 	"github.com/gohornet/hornet/pkg/dag"
+	"github.com/gohornet/hornet/pkg/expiration"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/whiteflag"
 	"github.com/iotaledger/hive.go/serializer/v2"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
@@ -25,7 +27,7 @@ func publishOnTopic(topic string, payload interface{}) {
 		return
 	}
 
-	deps.MQTTBroker.Send(topic, jsonPayload)
+	publisher.Send(topic, jsonPayload)
 }
 
 func publishConfirmedMilestone(cachedMs *storage.CachedMilestone) {
@@ -39,7 +41,7 @@ func publishLatestMilestone(cachedMs *storage.CachedMilestone) {
 }
 
 func publishMilestoneOnTopic(topic string, milestone *storage.Milestone) {
-	if deps.MQTTBroker.HasSubscribers(topic) {
+	if publisher.HasSubscribers(topic) {
 		publishOnTopic(topic, &milestonePayload{
 			Index: uint32(milestone.Index),
 			Time:  milestone.Timestamp.Unix(),
@@ -48,7 +50,7 @@ func publishMilestoneOnTopic(topic string, milestone *storage.Milestone) {
 }
 
 func publishReceipt(r *iotago.Receipt) {
-	if deps.MQTTBroker.HasSubscribers(topicReceipts) {
+	if publisher.HasSubscribers(topicReceipts) {
 		publishOnTopic(topicReceipts, r)
 	}
 }
@@ -56,25 +58,25 @@ func publishReceipt(r *iotago.Receipt) {
 func publishMessage(cachedMessage *storage.CachedMessage) {
 	defer cachedMessage.Release(true)
 
-	if deps.MQTTBroker.HasSubscribers(topicMessages) {
-		deps.MQTTBroker.Send(topicMessages, cachedMessage.Message().Data())
+	if publisher.HasSubscribers(topicMessages) {
+		publisher.Send(topicMessages, cachedMessage.Message().Data())
 	}
 
 	taggedData := cachedMessage.Message().TaggedData()
 	if taggedData != nil && len(taggedData.Tag) > 0 {
 		taggedDataTopic := strings.ReplaceAll(topicMessagesTaggedData, "{tag}", hex.EncodeToString(taggedData.Tag))
-		if deps.MQTTBroker.HasSubscribers(taggedDataTopic) {
-			deps.MQTTBroker.Send(taggedDataTopic, cachedMessage.Message().Data())
+		if publisher.HasSubscribers(taggedDataTopic) {
+			publisher.Send(taggedDataTopic, cachedMessage.Message().Data())
 		}
 	}
 }
 
 func publishTransactionIncludedMessage(transactionID *iotago.TransactionID, messageID hornet.MessageID) {
 	transactionTopic := strings.ReplaceAll(topicTransactionsIncludedMessage, "{transactionId}", hex.EncodeToString(transactionID[:]))
-	if deps.MQTTBroker.HasSubscribers(transactionTopic) {
+	if publisher.HasSubscribers(transactionTopic) {
 		cachedMessage := deps.Storage.CachedMessageOrNil(messageID)
 		if cachedMessage != nil {
-			deps.MQTTBroker.Send(transactionTopic, cachedMessage.Message().Data())
+			publisher.Send(transactionTopic, cachedMessage.Message().Data())
 			cachedMessage.Release(true)
 		}
 	}
@@ -87,9 +89,9 @@ func publishMessageMetadata(cachedMetadata *storage.CachedMetadata) {
 
 	messageID := metadata.MessageID().ToHex()
 	singleMessageTopic := strings.ReplaceAll(topicMessagesMetadata, "{messageId}", messageID)
-	hasSingleMessageTopicSubscriber := deps.MQTTBroker.HasSubscribers(singleMessageTopic)
+	hasSingleMessageTopicSubscriber := publisher.HasSubscribers(singleMessageTopic)
 
-	hasAllMessagesTopicSubscriber := deps.MQTTBroker.HasSubscribers(topicMessagesReferenced)
+	hasAllMessagesTopicSubscriber := publisher.HasSubscribers(topicMessagesReferenced)
 
 	if hasSingleMessageTopicSubscriber || hasAllMessagesTopicSubscriber {
 
@@ -171,14 +173,97 @@ func publishMessageMetadata(cachedMetadata *storage.CachedMetadata) {
 		}
 
 		if hasSingleMessageTopicSubscriber {
-			deps.MQTTBroker.Send(singleMessageTopic, jsonPayload)
+			publisher.Send(singleMessageTopic, jsonPayload)
 		}
 		if hasAllMessagesTopicSubscriber {
-			deps.MQTTBroker.Send(topicMessagesReferenced, jsonPayload)
+			publisher.Send(topicMessagesReferenced, jsonPayload)
 		}
 	}
 }
 
+// publishConflictingTransactions publishes the transactions that were marked as conflicting by the confirmed milestone.
+func publishConflictingTransactions(confirmation *whiteflag.Confirmation) {
+
+	hasAllTransactionsTopicSubscriber := publisher.HasSubscribers(topicTransactionsConflicting)
+
+	for _, conflictingMessage := range confirmation.Mutations.MessagesExcludedWithConflictingTransactions {
+		cachedMessage := deps.Storage.CachedMessageOrNil(conflictingMessage.MessageID) // message +1
+		if cachedMessage == nil {
+			continue
+		}
+
+		transaction := cachedMessage.Message().Transaction()
+		cachedMessage.Release(true) // message -1
+
+		if transaction == nil {
+			continue
+		}
+
+		transactionID, err := transaction.ID()
+		if err != nil {
+			Plugin.LogWarn(err)
+			continue
+		}
+
+		transactionIDHex := hex.EncodeToString(transactionID[:])
+		singleTransactionTopic := strings.ReplaceAll(topicTransactionConflicting, "{transactionId}", transactionIDHex)
+		hasSingleTransactionTopicSubscriber := publisher.HasSubscribers(singleTransactionTopic)
+
+		if !hasSingleTransactionTopicSubscriber && !hasAllTransactionsTopicSubscriber {
+			continue
+		}
+
+		jsonPayload, err := json.Marshal(&conflictingTransactionPayload{
+			MessageID:      conflictingMessage.MessageID.ToHex(),
+			TransactionID:  transactionIDHex,
+			MilestoneIndex: confirmation.MilestoneIndex,
+			ConflictReason: conflictingMessage.Conflict,
+		})
+		if err != nil {
+			Plugin.LogWarn(err)
+			continue
+		}
+
+		if hasSingleTransactionTopicSubscriber {
+			publisher.Send(singleTransactionTopic, jsonPayload)
+		}
+		if hasAllTransactionsTopicSubscriber {
+			publisher.Send(topicTransactionsConflicting, jsonPayload)
+		}
+	}
+}
+
+// publishUnreferencedMessages publishes the messages that were received before the confirmed milestone was issued,
+// but were not referenced by it.
+func publishUnreferencedMessages(msIndex milestone.Index) {
+	if !publisher.HasSubscribers(topicMessagesUnreferenced) {
+		return
+	}
+
+	// the unreferenced messages are stored with the latest milestone index at the time they were received
+	unreferencedMessageIDs := make([]string, 0)
+	for _, messageID := range deps.Storage.UnreferencedMessageIDs(msIndex - 1) {
+		cachedMsgMeta := deps.Storage.CachedMessageMetadataOrNil(messageID) // meta +1
+		if cachedMsgMeta == nil {
+			continue
+		}
+
+		if !cachedMsgMeta.Metadata().IsReferenced() {
+			unreferencedMessageIDs = append(unreferencedMessageIDs, messageID.ToHex())
+		}
+		cachedMsgMeta.Release(true) // meta -1
+	}
+
+	if len(unreferencedMessageIDs) == 0 {
+		return
+	}
+
+	publishOnTopic(topicMessagesUnreferenced, &unreferencedMessagesPayload{
+		MilestoneIndex: msIndex,
+		MessageIDs:     unreferencedMessageIDs,
+	})
+}
+
 func payloadForOutput(ledgerIndex milestone.Index, output *utxo.Output, spent bool) *outputPayload {
 	rawOutputJSON, err := output.Output().MarshalJSON()
 	if err != nil {
@@ -197,19 +282,52 @@ func payloadForOutput(ledgerIndex milestone.Index, output *utxo.Output, spent bo
 	}
 }
 
+// outputAddress returns the address of the address unlock condition of the output, or nil if the output has none.
+func outputAddress(output *utxo.Output) iotago.Address {
+	unlockConditionOutput, ok := output.Output().(iotago.UnlockConditionOutput)
+	if !ok {
+		return nil
+	}
+
+	conditions, err := unlockConditionOutput.UnlockConditions().Set()
+	if err != nil {
+		return nil
+	}
+
+	addressUnlock := conditions.Address()
+	if addressUnlock == nil {
+		return nil
+	}
+	return addressUnlock.Address
+}
+
 func publishOutput(ledgerIndex milestone.Index, output *utxo.Output, spent bool) {
 
-	outputsTopic := strings.ReplaceAll(topicOutputs, "{outputId}", output.OutputID().ToHex())
-	outputsTopicHasSubscribers := deps.MQTTBroker.HasSubscribers(outputsTopic)
+	topics := []string{strings.ReplaceAll(topicOutputs, "{outputId}", output.OutputID().ToHex())}
 
-	// TODO: Re-Add address topics if Indexer is enabled
-	//addressBech32Topic := strings.ReplaceAll(topicAddressesOutput, "{address}", output.Address().Bech32(deps.Bech32HRP))
-	//addressBech32TopicHasSubscribers := deps.MQTTBroker.HasSubscribers(addressBech32Topic)
-	//
-	//addressEd25519Topic := strings.ReplaceAll(topicAddressesEd25519Output, "{address}", output.Address().String())
-	//addressEd25519TopicHasSubscribers := deps.MQTTBroker.HasSubscribers(addressEd25519Topic)
+	if address := outputAddress(output); address != nil {
+		addressBech32 := address.Bech32(deps.Bech32HRP)
+		topics = append(topics, strings.ReplaceAll(topicAddressesOutput, "{address}", addressBech32))
+
+		if address.Type() == iotago.AddressEd25519 {
+			topics = append(topics, strings.ReplaceAll(topicAddressesEd25519Output, "{address}", address.String()))
+		}
+
+		if registeredAddressSets != nil {
+			for _, setID := range registeredAddressSets.setIDs(addressBech32) {
+				topics = append(topics, strings.ReplaceAll(topicAddressSetOutputs, "{addressSetId}", setID))
+			}
+		}
+	}
 
-	if outputsTopicHasSubscribers { //} || addressEd25519TopicHasSubscribers || addressBech32TopicHasSubscribers {
+	var subscribedTopics []string
+	for _, topic := range topics {
+		if publisher.HasSubscribers(topic) {
+			subscribedTopics = append(subscribedTopics, topic)
+		}
+	}
+
+	if len(subscribedTopics) > 0 {
 		if payload := payloadForOutput(ledgerIndex, output, spent); payload != nil {
 
 			// Serialize here instead of using publishOnTopic to avoid double JSON marshaling
@@ -219,17 +337,9 @@ func publishOutput(ledgerIndex milestone.Index, output *utxo.Output, spent bool)
 				return
 			}
 
-			if outputsTopicHasSubscribers {
-				deps.MQTTBroker.Send(outputsTopic, jsonPayload)
+			for _, topic := range subscribedTopics {
+				publisher.Send(topic, jsonPayload)
 			}
-
-			//if addressBech32TopicHasSubscribers {
-			//	deps.MQTTBroker.Send(addressBech32Topic, jsonPayload)
-			//}
-			//
-			//if addressEd25519TopicHasSubscribers {
-			//	deps.MQTTBroker.Send(addressEd25519Topic, jsonPayload)
-			//}
 		}
 	}
 
@@ -273,6 +383,34 @@ func transactionIDFromTopic(topicName string) *iotago.TransactionID {
 	return nil
 }
 
+// publishExpiration publishes the notification on the expirations topic and
+// on the expirations topics of the owner and the return address.
+func publishExpiration(notification *expiration.Notification) {
+
+	topics := []string{topicOutputsExpirations}
+	if notification.Address != nil {
+		topics = append(topics, strings.ReplaceAll(topicAddressesExpirations, "{address}", notification.Address.Bech32(deps.Bech32HRP)))
+	}
+	topics = append(topics, strings.ReplaceAll(topicAddressesExpirations, "{address}", notification.ReturnAddress.Bech32(deps.Bech32HRP)))
+
+	var jsonPayload []byte
+	for _, topic := range topics {
+		if !publisher.HasSubscribers(topic) {
+			continue
+		}
+
+		if jsonPayload == nil {
+			var err error
+			if jsonPayload, err = json.Marshal(notification.JSON(deps.Bech32HRP)); err != nil {
+				Plugin.LogWarn(err)
+				return
+			}
+		}
+
+		publisher.Send(topic, jsonPayload)
+	}
+}
+
 func outputIDFromTopic(topicName string) *iotago.OutputID {
 	if strings.HasPrefix(topicName, "outputs/") {
 		outputIDHex := strings.Replace(topicName, "outputs/", "", 1)
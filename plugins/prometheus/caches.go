@@ -5,7 +5,10 @@ import (
 )
 
 var (
-	cacheSizes *prometheus.GaugeVec
+	cacheSizes                    *prometheus.GaugeVec
+	incomingMessagesFilterLookups *prometheus.GaugeVec
+	requestQueueLookups           *prometheus.GaugeVec
+	requestQueueDropped           prometheus.Gauge
 )
 
 func configureCaches() {
@@ -19,7 +22,39 @@ func configureCaches() {
 		[]string{"type"},
 	)
 
+	incomingMessagesFilterLookups = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "caches",
+			Name:      "incoming_messages_filter_lookups",
+			Help:      "Number of lookups in the incoming messages filter (hit, miss).",
+		},
+		[]string{"result"},
+	)
+
+	requestQueueLookups = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "caches",
+			Name:      "request_queue_lookups",
+			Help:      "Number of sent requests whose message was received (hit) or which were discarded before (miss).",
+		},
+		[]string{"result"},
+	)
+
+	requestQueueDropped = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "caches",
+			Name:      "request_queue_dropped",
+			Help:      "Number of requests dropped before they were sent, because the request queue was full or they were too old.",
+		},
+	)
+
 	registry.MustRegister(cacheSizes)
+	registry.MustRegister(incomingMessagesFilterLookups)
+	registry.MustRegister(requestQueueLookups)
+	registry.MustRegister(requestQueueDropped)
 
 	addCollect(collectCaches)
 }
@@ -31,4 +66,11 @@ func collectCaches() {
 	cacheSizes.WithLabelValues("milestones").Set(float64(deps.Storage.MilestoneStorageSize()))
 	cacheSizes.WithLabelValues("unreferenced_messages").Set(float64(deps.Storage.UnreferencedMessageStorageSize()))
 	cacheSizes.WithLabelValues("message_processor_work_units").Set(float64(deps.MessageProcessor.WorkUnitsSize()))
+	incomingMessagesFilterLookups.WithLabelValues("hit").Set(float64(deps.ServerMetrics.IncomingMessagesFilterHits.Load()))
+	incomingMessagesFilterLookups.WithLabelValues("miss").Set(float64(deps.ServerMetrics.IncomingMessagesFilterMisses.Load()))
+
+	requestQueueStats := deps.RequestQueue.Stats()
+	requestQueueLookups.WithLabelValues("hit").Set(float64(requestQueueStats.Hits))
+	requestQueueLookups.WithLabelValues("miss").Set(float64(requestQueueStats.Misses))
+	requestQueueDropped.Set(float64(requestQueueStats.Dropped))
 }
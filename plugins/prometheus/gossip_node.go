@@ -5,10 +5,11 @@ import (
 )
 
 var (
-	gossipMessages       *prometheus.GaugeVec
-	gossipRequests       *prometheus.GaugeVec
-	gossipHeartbeats     *prometheus.GaugeVec
-	gossipDroppedPackets *prometheus.GaugeVec
+	gossipMessages           *prometheus.GaugeVec
+	gossipRequests           *prometheus.GaugeVec
+	gossipHeartbeats         *prometheus.GaugeVec
+	gossipDroppedPackets     *prometheus.GaugeVec
+	gossipRejectedMilestones *prometheus.GaugeVec
 )
 
 func configureGossipNode() {
@@ -52,10 +53,21 @@ func configureGossipNode() {
 		[]string{"type"},
 	)
 
+	gossipRejectedMilestones = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "gossip_node",
+			Name:      "rejected_milestone_count",
+			Help:      "Number of received milestones rejected because of their timestamp.",
+		},
+		[]string{"reason"},
+	)
+
 	registry.MustRegister(gossipMessages)
 	registry.MustRegister(gossipRequests)
 	registry.MustRegister(gossipHeartbeats)
 	registry.MustRegister(gossipDroppedPackets)
+	registry.MustRegister(gossipRejectedMilestones)
 
 	addCollect(collectServer)
 }
@@ -69,6 +81,8 @@ func collectServer() {
 	gossipMessages.WithLabelValues("sent").Set(float64(deps.ServerMetrics.SentMessages.Load()))
 	gossipMessages.WithLabelValues("sent_spam").Set(float64(deps.ServerMetrics.SentSpamMessages.Load()))
 	gossipMessages.WithLabelValues("validated").Set(float64(deps.ServerMetrics.ValidatedMessages.Load()))
+	gossipMessages.WithLabelValues("filter_rejected").Set(float64(deps.ServerMetrics.FilterRejectedMessages.Load()))
+	gossipMessages.WithLabelValues("filter_deprioritized").Set(float64(deps.ServerMetrics.FilterDeprioritizedMessages.Load()))
 
 	gossipRequests.WithLabelValues("invalid").Set(float64(deps.ServerMetrics.InvalidRequests.Load()))
 	gossipRequests.WithLabelValues("received_message").Set(float64(deps.ServerMetrics.ReceivedMessageRequests.Load()))
@@ -80,4 +94,7 @@ func collectServer() {
 	gossipHeartbeats.WithLabelValues("sent").Set(float64(deps.ServerMetrics.SentHeartbeats.Load()))
 
 	gossipDroppedPackets.WithLabelValues("sent").Set(float64(deps.ServerMetrics.DroppedMessages.Load()))
+
+	gossipRejectedMilestones.WithLabelValues("future").Set(float64(deps.ServerMetrics.RejectedFutureMilestones.Load()))
+	gossipRejectedMilestones.WithLabelValues("past").Set(float64(deps.ServerMetrics.RejectedPastMilestones.Load()))
 }
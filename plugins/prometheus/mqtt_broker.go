@@ -5,7 +5,9 @@ import (
 )
 
 var (
-	mqttBrokerTopicsManagerSize prometheus.Gauge
+	mqttBrokerTopicsManagerSize            prometheus.Gauge
+	mqttBrokerWebSocketDroppedMessages     prometheus.Gauge
+	mqttBrokerWebSocketDisconnectedClients prometheus.Gauge
 )
 
 func configureMQTTBroker() {
@@ -18,11 +20,34 @@ func configureMQTTBroker() {
 			Help:      "Number of active topics in the topics manager.",
 		})
 
+	mqttBrokerWebSocketDroppedMessages = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "mqtt_broker",
+			Name:      "websocket_dropped_messages",
+			Help:      "Number of messages that were dropped because the queue of a WebSocket client was full.",
+		})
+
+	mqttBrokerWebSocketDisconnectedClients = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "mqtt_broker",
+			Name:      "websocket_disconnected_clients",
+			Help:      "Number of WebSocket clients that were disconnected because their queue was full.",
+		})
+
 	registry.MustRegister(mqttBrokerTopicsManagerSize)
+	registry.MustRegister(mqttBrokerWebSocketDroppedMessages)
+	registry.MustRegister(mqttBrokerWebSocketDisconnectedClients)
 
 	addCollect(collectMQTTBroker)
 }
 
 func collectMQTTBroker() {
 	mqttBrokerTopicsManagerSize.Set(float64(deps.MQTTBroker.TopicsManagerSize()))
+
+	if deps.MQTTWebSocketBridge != nil {
+		mqttBrokerWebSocketDroppedMessages.Set(float64(deps.MQTTWebSocketBridge.DroppedMessages()))
+		mqttBrokerWebSocketDisconnectedClients.Set(float64(deps.MQTTWebSocketBridge.DisconnectedClients()))
+	}
 }
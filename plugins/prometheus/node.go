@@ -10,6 +10,8 @@ var (
 	messagesPerSecond           prometheus.Gauge
 	referencedMessagesPerSecond prometheus.Gauge
 	referencedRate              prometheus.Gauge
+	tangleWidth                 prometheus.Gauge
+	coneOverlap                 prometheus.Gauge
 	milestones                  *prometheus.GaugeVec
 	tips                        *prometheus.GaugeVec
 	requests                    *prometheus.GaugeVec
@@ -59,6 +61,22 @@ func configureNode() {
 			Help:      "Ratio of referenced messages in relation to new messages of the last confirmed milestone.",
 		})
 
+	tangleWidth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "node",
+			Name:      "tangle_width",
+			Help:      "Estimated width of the tangle (average messages per depth level) in the cone of the last confirmed milestone.",
+		})
+
+	coneOverlap = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "node",
+			Name:      "cone_overlap",
+			Help:      "Ratio of parent references in the cone of the last confirmed milestone pointing to cones of older milestones.",
+		})
+
 	milestones = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "iota",
@@ -96,6 +114,8 @@ func configureNode() {
 	registry.MustRegister(messagesPerSecond)
 	registry.MustRegister(referencedMessagesPerSecond)
 	registry.MustRegister(referencedRate)
+	registry.MustRegister(tangleWidth)
+	registry.MustRegister(coneOverlap)
 	registry.MustRegister(milestones)
 
 	if deps.TipSelector != nil {
@@ -116,12 +136,16 @@ func collectInfo() {
 	messagesPerSecond.Set(0)
 	referencedMessagesPerSecond.Set(0)
 	referencedRate.Set(0)
+	tangleWidth.Set(0)
+	coneOverlap.Set(0)
 
 	lastConfirmedMilestoneMetric := deps.Tangle.LastConfirmedMilestoneMetric()
 	if lastConfirmedMilestoneMetric != nil {
 		messagesPerSecond.Set(lastConfirmedMilestoneMetric.MPS)
 		referencedMessagesPerSecond.Set(lastConfirmedMilestoneMetric.RMPS)
 		referencedRate.Set(lastConfirmedMilestoneMetric.ReferencedRate)
+		tangleWidth.Set(lastConfirmedMilestoneMetric.TangleWidth)
+		coneOverlap.Set(lastConfirmedMilestoneMetric.ConeOverlap)
 	}
 
 	milestones.WithLabelValues("latest").Set(float64(deps.SyncManager.LatestMilestoneIndex()))
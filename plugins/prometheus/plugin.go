@@ -88,6 +88,7 @@ type dependencies struct {
 	SnapshotManager       *snapshot.SnapshotManager
 	Coordinator           *coordinator.Coordinator `optional:"true"`
 	MQTTBroker            *mqtt.Broker             `optional:"true"`
+	MQTTWebSocketBridge   *mqtt.WebSocketBridge    `optional:"true"`
 }
 
 func configure() {
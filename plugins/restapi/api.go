@@ -6,27 +6,17 @@ import (
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
 
 	"github.com/gohornet/hornet/pkg/jwt"
+	"github.com/gohornet/hornet/pkg/restapi"
+	"github.com/iotaledger/hive.go/configuration"
 )
 
-func compileRouteAsRegex(route string) *regexp.Regexp {
-
-	r := regexp.QuoteMeta(route)
-	r = strings.Replace(r, `\*`, "(.*?)", -1)
-	r = r + "$"
-
-	reg, err := regexp.Compile(r)
-	if err != nil {
-		return nil
-	}
-	return reg
-}
-
 func compileRoutesAsRegexes(routes []string) []*regexp.Regexp {
 	var regexes []*regexp.Regexp
 	for _, route := range routes {
-		reg := compileRouteAsRegex(route)
+		reg := restapi.CompileRouteAsRegex(route)
 		if reg == nil {
 			Plugin.LogFatalf("Invalid route in config: %s", route)
 			continue
@@ -36,28 +26,17 @@ func compileRoutesAsRegexes(routes []string) []*regexp.Regexp {
 	return regexes
 }
 
-func apiMiddleware() echo.MiddlewareFunc {
-
-	publicRoutes := compileRoutesAsRegexes(deps.NodeConfig.Strings(CfgRestAPIPublicRoutes))
-	protectedRoutes := compileRoutesAsRegexes(deps.NodeConfig.Strings(CfgRestAPIProtectedRoutes))
-
-	matchPublic := func(c echo.Context) bool {
-		for _, reg := range publicRoutes {
-			if reg.MatchString(strings.ToLower(c.Path())) {
-				return true
-			}
+// IsPublicRoute returns whether the route can be called without authorization.
+func IsPublicRoute(path string) bool {
+	for _, reg := range publicRoutes {
+		if reg.MatchString(strings.ToLower(path)) {
+			return true
 		}
-		return false
 	}
+	return false
+}
 
-	matchExposed := func(c echo.Context) bool {
-		for _, reg := range append(publicRoutes, protectedRoutes...) {
-			if reg.MatchString(strings.ToLower(c.Path())) {
-				return true
-			}
-		}
-		return false
-	}
+func apiMiddleware() echo.MiddlewareFunc {
 
 	// configure JWT auth
 	salt := deps.NodeConfig.String(CfgRestAPIJWTAuthSalt)
@@ -76,8 +55,50 @@ func apiMiddleware() echo.MiddlewareFunc {
 		Plugin.LogPanicf("JWT auth initialization failed: %w", err)
 	}
 
+	return newAPIMiddleware(jwtAuth, deps.NodeConfig.Strings(CfgRestAPIPublicRoutes), deps.NodeConfig.Strings(CfgRestAPIProtectedRoutes))
+}
+
+// newAPIMiddleware creates the middleware that exposes the public routes and protects the protected routes with the JWT auth.
+func newAPIMiddleware(auth *jwt.JWTAuth, publicRoutesCfg []string, protectedRoutesCfg []string) echo.MiddlewareFunc {
+
+	publicRoutes = compileRoutesAsRegexes(publicRoutesCfg)
+	protectedRoutes := compileRoutesAsRegexes(protectedRoutesCfg)
+
+	matchPublic := func(c echo.Context) bool {
+		return IsPublicRoute(c.Path())
+	}
+
+	matchExposed := func(c echo.Context) bool {
+		for _, reg := range append(publicRoutes, protectedRoutes...) {
+			if reg.MatchString(strings.ToLower(c.Path())) {
+				return true
+			}
+		}
+		return false
+	}
+
+	scopeRoutes := compileScopeRoutes()
+
+	// matchScopes checks whether one of the scopes of a token allows to call the route.
+	// Tokens without scopes are allowed to call all exposed routes.
+	matchScopes := func(c echo.Context, scopes []string) bool {
+		if len(scopes) == 0 {
+			return true
+		}
+
+		for _, scope := range scopes {
+			for _, reg := range scopeRoutes[scope][c.Request().Method] {
+				if reg.MatchString(strings.ToLower(c.Path())) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
 	jwtAllow := func(c echo.Context, subject string, claims *jwt.AuthClaims) bool {
-		// Allow all JWT created for the API if the endpoints are exposed
+		// Allow all JWT created for the API if the endpoints are exposed,
+		// the scopes of the token are checked afterwards to reject it as forbidden.
 		if matchExposed(c) && claims.API {
 			return claims.VerifySubject(subject)
 		}
@@ -100,10 +121,29 @@ func apiMiddleware() echo.MiddlewareFunc {
 			return matchPublic(c)
 		}
 
-		jwtMiddlewareHandler := jwtAuth.Middleware(publicSkipper, jwtAllow)(next)
+		// Reject valid API tokens whose scopes don't allow to call the route
+		scopeHandler := func(c echo.Context) error {
+			if claims := jwt.ClaimsFromContext(c); claims != nil && claims.API && matchExposed(c) && !matchScopes(c, claims.Scopes) {
+				return jwt.ErrJWTScope
+			}
+			return next(c)
+		}
+
+		jwtMiddlewareHandler := auth.Middleware(publicSkipper, jwtAllow)(scopeHandler)
+
+		// A standby node only serves the public routes to authorized requests,
+		// so that load balancers and clients don't use it before it got promoted.
+		jwtStandbyMiddlewareHandler := auth.Middleware(func(c echo.Context) bool { return false }, jwtAllow)(scopeHandler)
 
 		return func(c echo.Context) error {
 
+			if deps.Standby != nil && deps.Standby.IsStandby() && matchPublic(c) {
+				if c.Request().Header.Get(echo.HeaderAuthorization) == "" {
+					return errors.WithMessage(echo.ErrServiceUnavailable, "node is in standby mode")
+				}
+				return jwtStandbyMiddlewareHandler(c)
+			}
+
 			// Check if the route should be exposed (public or protected) or is required by the dashboard
 			if matchExposed(c) || dashboardAllowedAPIRoute(c) {
 				// Apply JWT middleware
@@ -115,6 +155,70 @@ func apiMiddleware() echo.MiddlewareFunc {
 	}
 }
 
+// scopeAllowedRoutes are the routes per method that can be called with a token of the scope.
+// The routes are only reachable if they are exposed by the public or protected routes.
+var scopeAllowedRoutes = map[string]map[string][]string{
+	jwt.ScopeAPIRead: {
+		http.MethodGet: {
+			"/api/routes/spec",
+			"/api/v2/info",
+			"/api/v2/tips",
+			"/api/v2/messages*",
+			"/api/v2/transactions*",
+			"/api/v2/milestones*",
+			"/api/v2/outputs*",
+			"/api/v2/addresses*",
+			"/api/v2/treasury",
+			"/api/v2/receipts*",
+			"/api/v2/ws",
+			"/api/plugins/participation/v1/events*",
+			"/api/plugins/participation/v1/outputs*",
+			"/api/plugins/participation/v1/addresses*",
+			"/api/plugins/indexer/v1/outputs*",
+			"/api/plugins/indexer/v1/aliases*",
+			"/api/plugins/indexer/v1/nfts*",
+			"/api/plugins/indexer/v1/foundries*",
+			"/api/plugins/indexer/v1/graphql",
+		},
+		http.MethodPost: {
+			"/api/v2/messages/proof/verify",
+			"/api/plugins/indexer/v1/graphql",
+		},
+	},
+	jwt.ScopeAPISubmit: {
+		http.MethodPost: {
+			"/api/v2/messages",
+			"/api/v2/messages/validate",
+			"/api/plugins/faucet/v1/enqueue",
+		},
+	},
+	jwt.ScopePeeringManage: {
+		http.MethodGet:    {"/api/v2/peers*", "/api/plugins/autopeering/*"},
+		http.MethodPost:   {"/api/v2/peers*", "/api/plugins/autopeering/*"},
+		http.MethodDelete: {"/api/v2/peers*"},
+	},
+	jwt.ScopeFaucetAdmin: {
+		http.MethodGet:  {"/api/plugins/faucet/*"},
+		http.MethodPost: {"/api/plugins/faucet/*"},
+	},
+	jwt.ScopeNodeControl: {
+		http.MethodGet:    {"/api/v2/control/*"},
+		http.MethodPost:   {"/api/v2/control/*"},
+		http.MethodDelete: {"/api/v2/control/*"},
+	},
+}
+
+func compileScopeRoutes() map[string]map[string][]*regexp.Regexp {
+	scopeRoutes := make(map[string]map[string][]*regexp.Regexp, len(scopeAllowedRoutes))
+	for scope, routesPerMethod := range scopeAllowedRoutes {
+		scopeRoutes[scope] = make(map[string][]*regexp.Regexp, len(routesPerMethod))
+		for method, routes := range routesPerMethod {
+			scopeRoutes[scope][method] = compileRoutesAsRegexes(routes)
+		}
+	}
+	return scopeRoutes
+}
+
 var dashboardAllowedRoutes = map[string][]string{
 	http.MethodGet: {
 		"/api/v2/addresses",
@@ -126,6 +230,7 @@ var dashboardAllowedRoutes = map[string][]string{
 		"/api/v2/transactions",
 		"/api/plugins/spammer/v1",
 		"/api/plugins/participation/v1/events",
+		"/api/plugins/alerting/v1",
 	},
 	http.MethodPost: {
 		"/api/v2/peers",
@@ -141,6 +246,7 @@ var dashboardAllowedRoutes = map[string][]string{
 var faucetAllowedRoutes = map[string][]string{
 	http.MethodGet: {
 		"/api/plugins/faucet/v1/info",
+		"/api/plugins/faucet/v1/stats",
 	},
 	http.MethodPost: {
 		"/api/plugins/faucet/v1/enqueue",
@@ -172,3 +278,44 @@ func dashboardAllowedAPIRoute(context echo.Context) bool {
 func faucetAllowedAPIRoute(context echo.Context) bool {
 	return checkAllowedAPIRoute(context, faucetAllowedRoutes)
 }
+
+// rateLimitMiddleware limits the requests to the routes of the rate limits in the config.
+// It is applied after the API middleware, so the JWT of a request is already verified.
+func rateLimitMiddleware() echo.MiddlewareFunc {
+
+	var rateLimits []*restapi.RateLimit
+	if err := deps.NodeConfig.Unmarshal(CfgRestAPIRateLimits, &rateLimits); err != nil {
+		Plugin.LogPanicf("failed to parse the rate limits of the config: %s", err)
+	}
+
+	middleware, err := restapi.RouteRateLimiter(rateLimits)
+	if err != nil {
+		Plugin.LogPanicf("invalid rate limit in the config: %s", err)
+	}
+
+	for _, rateLimit := range rateLimits {
+		Plugin.LogInfof("rate limiting %s to one request per %v (burst: %d, identifier: %s)", rateLimit.Route, rateLimit.Period, rateLimit.Burst, rateLimit.Identifier)
+	}
+
+	return middleware
+}
+
+// routeGroupHeadersMiddleware applies the CORS policies and security headers of the route groups in the config.
+func routeGroupHeadersMiddleware(nodeConfig *configuration.Configuration) echo.MiddlewareFunc {
+
+	var routeGroups []*restapi.RouteGroup
+	if err := nodeConfig.Unmarshal(CfgRestAPIRouteGroups, &routeGroups); err != nil {
+		Plugin.LogPanicf("failed to parse the route groups of the config: %s", err)
+	}
+
+	middleware, err := restapi.RouteGroupHeaders(routeGroups)
+	if err != nil {
+		Plugin.LogPanicf("invalid route group in the config: %s", err)
+	}
+
+	for _, routeGroup := range routeGroups {
+		Plugin.LogInfof("applying the CORS policy and security headers of route group %s to %s", routeGroup.Name, strings.Join(routeGroup.Routes, ", "))
+	}
+
+	return middleware
+}
@@ -0,0 +1,105 @@
+package restapi
+
+import (
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/jwt"
+)
+
+func newTestAPIServer(t *testing.T) (*echo.Echo, *jwt.JWTAuth) {
+	privKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	nodeID, err := peer.IDFromPrivateKey(privKey)
+	require.NoError(t, err)
+
+	auth, err := jwt.NewJWTAuth("HORNET", 0, nodeID.String(), privKey)
+	require.NoError(t, err)
+
+	fs := params.Params["nodeConfig"]
+	publicRoutesCfg, err := fs.GetStringSlice(CfgRestAPIPublicRoutes)
+	require.NoError(t, err)
+	protectedRoutesCfg, err := fs.GetStringSlice(CfgRestAPIProtectedRoutes)
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.Use(newAPIMiddleware(auth, publicRoutesCfg, protectedRoutesCfg))
+
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/api/v2/messages/:messageID", ok)
+	e.POST("/api/v2/control/messages/:messageID/pin", ok)
+	e.DELETE("/api/v2/control/messages/:messageID/pin", ok)
+	e.GET("/api/v2/control/database/prune", ok)
+	e.GET("/api/v2/peers/bundle", ok)
+	e.GET("/api/plugins/debug/v1/audit-log", ok)
+	e.GET("/api/plugins/indexer/v1/outputs", ok)
+
+	return e, auth
+}
+
+func newTestAPIRequest(e *echo.Echo) func(method string, path string, token string) int {
+	return func(method string, path string, token string) int {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+}
+
+func TestAPIMiddlewareMessagePin(t *testing.T) {
+	e, auth := newTestAPIServer(t)
+	request := newTestAPIRequest(e)
+
+	const messageID = "0x0000000000000000000000000000000000000000000000000000000000000000"
+
+	// messages are public
+	require.Equal(t, http.StatusOK, request(http.MethodGet, "/api/v2/messages/"+messageID, ""))
+
+	// pinning blocks the pruning, so it needs to be authorized (the JWT middleware rejects a missing token as bad request)
+	require.Equal(t, http.StatusBadRequest, request(http.MethodPost, "/api/v2/control/messages/"+messageID+"/pin", ""))
+	require.Equal(t, http.StatusBadRequest, request(http.MethodDelete, "/api/v2/control/messages/"+messageID+"/pin", ""))
+
+	token, err := auth.IssueJWT(true, false)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, request(http.MethodPost, "/api/v2/control/messages/"+messageID+"/pin", token))
+
+	// a token of another scope is not allowed to pin messages
+	readToken, err := auth.IssueJWT(true, false, jwt.ScopeAPIRead)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusForbidden, request(http.MethodPost, "/api/v2/control/messages/"+messageID+"/pin", readToken))
+}
+
+func TestAPIMiddlewareReadScope(t *testing.T) {
+	e, auth := newTestAPIServer(t)
+	request := newTestAPIRequest(e)
+
+	readToken, err := auth.IssueJWT(true, false, jwt.ScopeAPIRead)
+	require.NoError(t, err)
+
+	// the read scope allows to read the ledger and the indexer
+	require.Equal(t, http.StatusOK, request(http.MethodGet, "/api/plugins/indexer/v1/outputs", readToken))
+
+	// but not the control, peering and debug routes
+	require.Equal(t, http.StatusForbidden, request(http.MethodGet, "/api/v2/control/database/prune", readToken))
+	require.Equal(t, http.StatusForbidden, request(http.MethodGet, "/api/v2/peers/bundle", readToken))
+	require.Equal(t, http.StatusForbidden, request(http.MethodGet, "/api/plugins/debug/v1/audit-log", readToken))
+
+	// tokens with the matching scope or without scopes are allowed
+	controlToken, err := auth.IssueJWT(true, false, jwt.ScopeNodeControl)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, request(http.MethodGet, "/api/v2/control/database/prune", controlToken))
+
+	token, err := auth.IssueJWT(true, false)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, request(http.MethodGet, "/api/v2/peers/bundle", token))
+}
@@ -1,6 +1,8 @@
 package restapi
 
 import (
+	"time"
+
 	flag "github.com/spf13/pflag"
 
 	"github.com/gohornet/hornet/pkg/node"
@@ -17,12 +19,36 @@ const (
 	CfgRestAPIJWTAuthSalt = "restAPI.jwtAuth.salt"
 	// whether the node does PoW if messages are received via API
 	CfgRestAPIPoWEnabled = "restAPI.powEnabled"
-	// the amount of workers used for calculating PoW when issuing messages via API
+	// the maximum amount of workers used for calculating PoW when issuing messages via API (0 = scale with the PoW CPU budget)
 	CfgRestAPIPoWWorkerCount = "restAPI.powWorkerCount"
 	// the maximum number of characters that the body of an API call may contain
 	CfgRestAPILimitsMaxBodyLength = "restAPI.limits.bodyLength"
 	// the maximum number of results that may be returned by an endpoint
 	CfgRestAPILimitsMaxResults = "restAPI.limits.maxResults"
+	// the maximum number of results that may be returned by an endpoint if the response is streamed as newline delimited JSON
+	CfgRestAPILimitsMaxStreamedResults = "restAPI.limits.maxStreamedResults"
+	// whether GET requests wait for running milestone confirmations to see a consistent state
+	CfgRestAPISnapshotReads = "restAPI.snapshotReads"
+	// the peer IDs of the nodes whose peering bundles can be imported (the own node is always trusted)
+	CfgRestAPIPeeringBundleTrustedIssuers = "restAPI.peeringBundle.trustedIssuers"
+	// the maximum age of peering bundles which can be imported (0 = unlimited)
+	CfgRestAPIPeeringBundleMaxAge = "restAPI.peeringBundle.maxAge"
+	// the maximum time a long-polling request waits for a message to reach the requested state
+	CfgRestAPILongPollingMaxTimeout = "restAPI.longPolling.maxTimeout"
+	// the maximum amount of long-polling requests a single client may have waiting at the same time (0 = disabled)
+	CfgRestAPILongPollingMaxRequestsPerClient = "restAPI.longPolling.maxRequestsPerClient"
+	// the maximum amount of topics a single websocket connection may be subscribed to at the same time (0 = disabled)
+	CfgRestAPIWebsocketMaxSubscriptionsPerClient = "restAPI.websocket.maxSubscriptionsPerClient"
+	// the rate limits of the routes (route, period, burst and identifier), the first matching rate limit applies
+	CfgRestAPIRateLimits = "restAPI.rateLimits"
+	// the CORS policies and security headers of the route groups (name, routes, cors and securityHeaders), the first matching route group applies
+	CfgRestAPIRouteGroups = "restAPI.routeGroups"
+	// whether the mutating calls of the audited routes are recorded
+	CfgRestAPIAuditLogEnabled = "restAPI.auditLog.enabled"
+	// the routes whose mutating calls are recorded
+	CfgRestAPIAuditLogRoutes = "restAPI.auditLog.routes"
+	// the time the recorded calls are kept
+	CfgRestAPIAuditLogRetention = "restAPI.auditLog.retention"
 )
 
 var params = &node.PluginParams{
@@ -34,6 +60,7 @@ var params = &node.PluginParams{
 				[]string{
 					"/health",
 					"/mqtt",
+					"/api/routes/spec",
 					"/api/v2/info",
 					"/api/v2/tips",
 					"/api/v2/messages*",
@@ -43,9 +70,11 @@ var params = &node.PluginParams{
 					"/api/v2/addresses*",
 					"/api/v2/treasury",
 					"/api/v2/receipts*",
+					"/api/v2/ws",
 					"/api/plugins/participation/v1/events*",
 					"/api/plugins/participation/v1/outputs*",
 					"/api/plugins/participation/v1/addresses*",
+					"/api/plugins/mqtt/v1/address-sets",
 				}, "the HTTP REST routes which can be called without authorization. Wildcards using * are allowed")
 			fs.StringSlice(CfgRestAPIProtectedRoutes,
 				[]string{
@@ -54,9 +83,27 @@ var params = &node.PluginParams{
 				}, "the HTTP REST routes which need to be called with authorization. Wildcards using * are allowed")
 			fs.String(CfgRestAPIJWTAuthSalt, "HORNET", "salt used inside the JWT tokens for the REST API. Change this to a different value to invalidate JWT tokens not matching this new value")
 			fs.Bool(CfgRestAPIPoWEnabled, false, "whether the node does PoW if messages are received via API")
-			fs.Int(CfgRestAPIPoWWorkerCount, 1, "the amount of workers used for calculating PoW when issuing messages via API")
+			fs.Int(CfgRestAPIPoWWorkerCount, 1, "the maximum amount of workers used for calculating PoW when issuing messages via API (0 = scale with the PoW CPU budget)")
 			fs.String(CfgRestAPILimitsMaxBodyLength, "1M", "the maximum number of characters that the body of an API call may contain")
 			fs.Int(CfgRestAPILimitsMaxResults, 1000, "the maximum number of results that may be returned by an endpoint")
+			fs.Int(CfgRestAPILimitsMaxStreamedResults, 100000, "the maximum number of results that may be returned by an endpoint if the response is streamed as newline delimited JSON")
+			fs.Bool(CfgRestAPISnapshotReads, false, "whether GET requests wait for running milestone confirmations to see a consistent state")
+			fs.StringSlice(CfgRestAPIPeeringBundleTrustedIssuers, []string{}, "the peer IDs of the nodes whose peering bundles can be imported (the own node is always trusted)")
+			fs.Duration(CfgRestAPIPeeringBundleMaxAge, 24*time.Hour, "the maximum age of peering bundles which can be imported (0 = unlimited)")
+			fs.Duration(CfgRestAPILongPollingMaxTimeout, time.Minute, "the maximum time a long-polling request waits for a message to reach the requested state")
+			fs.Int(CfgRestAPILongPollingMaxRequestsPerClient, 10, "the maximum amount of long-polling requests a single client may have waiting at the same time (0 = disabled)")
+			fs.Int(CfgRestAPIWebsocketMaxSubscriptionsPerClient, 100, "the maximum amount of topics a single websocket connection may be subscribed to at the same time (0 = disabled)")
+			fs.Bool(CfgRestAPIAuditLogEnabled, false, "whether the mutating calls of the audited routes are recorded")
+			fs.StringSlice(CfgRestAPIAuditLogRoutes,
+				[]string{
+					"/api/v2/peers*",
+					"/api/v2/control/*",
+					"/api/plugins/faucet/*",
+					"/api/plugins/participation/v1/admin/*",
+					"/api/plugins/indexer/v1/webhooks*",
+					"/api/plugins/indexer/v1/maintenance/*",
+				}, "the HTTP REST routes whose mutating calls are recorded. Wildcards using * are allowed")
+			fs.Duration(CfgRestAPIAuditLogRetention, 30*24*time.Hour, "the time the recorded calls are kept")
 			return fs
 		}(),
 	},
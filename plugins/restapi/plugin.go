@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"time"
 
@@ -14,11 +16,13 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/dig"
 
+	"github.com/gohornet/hornet/pkg/database"
 	"github.com/gohornet/hornet/pkg/jwt"
 	"github.com/gohornet/hornet/pkg/metrics"
 	"github.com/gohornet/hornet/pkg/node"
 	"github.com/gohornet/hornet/pkg/restapi"
 	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/gohornet/hornet/pkg/standby"
 	"github.com/gohornet/hornet/pkg/tangle"
 	"github.com/iotaledger/hive.go/configuration"
 )
@@ -44,6 +48,8 @@ var (
 	nodeAPIHealthRoute = "/health"
 
 	jwtAuth *jwt.JWTAuth
+	// the routes which can be called without authorization.
+	publicRoutes []*regexp.Regexp
 )
 
 type dependencies struct {
@@ -53,9 +59,11 @@ type dependencies struct {
 	Echo                  *echo.Echo
 	RestAPIMetrics        *metrics.RestAPIMetrics
 	Host                  host.Host
-	RestAPIBindAddress    string         `name:"restAPIBindAddress"`
-	NodePrivateKey        crypto.PrivKey `name:"nodePrivateKey"`
-	DashboardAuthUsername string         `name:"dashboardAuthUsername" optional:"true"`
+	RestAPIBindAddress    string            `name:"restAPIBindAddress"`
+	NodePrivateKey        crypto.PrivKey    `name:"nodePrivateKey"`
+	DashboardAuthUsername string            `name:"dashboardAuthUsername" optional:"true"`
+	Standby               *standby.Standby  `optional:"true"`
+	AuditLog              *restapi.AuditLog `optional:"true"`
 }
 
 func initConfigPars(c *dig.Container) {
@@ -67,14 +75,16 @@ func initConfigPars(c *dig.Container) {
 
 	type cfgResult struct {
 		dig.Out
-		RestAPIBindAddress      string `name:"restAPIBindAddress"`
-		RestAPILimitsMaxResults int    `name:"restAPILimitsMaxResults"`
+		RestAPIBindAddress              string `name:"restAPIBindAddress"`
+		RestAPILimitsMaxResults         int    `name:"restAPILimitsMaxResults"`
+		RestAPILimitsMaxStreamedResults int    `name:"restAPILimitsMaxStreamedResults"`
 	}
 
 	if err := c.Provide(func(deps cfgDeps) cfgResult {
 		return cfgResult{
-			RestAPIBindAddress:      deps.NodeConfig.String(CfgRestAPIBindAddress),
-			RestAPILimitsMaxResults: deps.NodeConfig.Int(CfgRestAPILimitsMaxResults),
+			RestAPIBindAddress:              deps.NodeConfig.String(CfgRestAPIBindAddress),
+			RestAPILimitsMaxResults:         deps.NodeConfig.Int(CfgRestAPILimitsMaxResults),
+			RestAPILimitsMaxStreamedResults: deps.NodeConfig.Int(CfgRestAPILimitsMaxStreamedResults),
 		}
 	}); err != nil {
 		Plugin.LogPanic(err)
@@ -105,7 +115,7 @@ func provide(c *dig.Container) {
 		e := echo.New()
 		e.HideBanner = true
 		e.Use(middleware.Recover())
-		e.Use(middleware.CORS())
+		e.Use(routeGroupHeadersMiddleware(deps.NodeConfig))
 		e.Use(middleware.Gzip())
 		e.Use(middleware.BodyLimit(deps.NodeConfig.String(CfgRestAPILimitsMaxBodyLength)))
 
@@ -117,10 +127,44 @@ func provide(c *dig.Container) {
 	}); err != nil {
 		Plugin.LogPanic(err)
 	}
+
+	type auditLogDeps struct {
+		dig.In
+		NodeConfig     *configuration.Configuration `name:"nodeConfig"`
+		DatabasePath   string                       `name:"databasePath"`
+		DatabaseEngine database.Engine              `name:"databaseEngine"`
+	}
+
+	if err := c.Provide(func(deps auditLogDeps) *restapi.AuditLog {
+		if !deps.NodeConfig.Bool(CfgRestAPIAuditLogEnabled) {
+			return nil
+		}
+
+		auditLogStore, err := database.StoreWithDefaultSettings(filepath.Join(deps.DatabasePath, "auditlog"), true, deps.DatabaseEngine)
+		if err != nil {
+			Plugin.LogPanic(err)
+		}
+
+		auditLog, err := restapi.NewAuditLog(auditLogStore, deps.NodeConfig.Duration(CfgRestAPIAuditLogRetention), deps.NodeConfig.Strings(CfgRestAPIAuditLogRoutes))
+		if err != nil {
+			Plugin.LogPanic(err)
+		}
+
+		return auditLog
+	}); err != nil {
+		Plugin.LogPanic(err)
+	}
 }
 
 func configure() {
 	deps.Echo.Use(apiMiddleware())
+	deps.Echo.Use(rateLimitMiddleware())
+	if deps.AuditLog != nil {
+		// the audit log is applied after the API middleware, so the JWT of a request is already verified
+		deps.Echo.Use(deps.AuditLog.Middleware(func(err error) {
+			Plugin.LogWarnf("recording API call in the audit log failed: %s", err)
+		}))
+	}
 	setupRoutes()
 }
 
@@ -155,6 +199,22 @@ func run() {
 	}, shutdown.PriorityRestAPI); err != nil {
 		Plugin.LogPanicf("failed to start worker: %s", err)
 	}
+
+	if deps.AuditLog == nil {
+		return
+	}
+
+	if err := Plugin.Daemon().BackgroundWorker("Close audit log database", func(ctx context.Context) {
+		<-ctx.Done()
+
+		Plugin.LogInfo("Syncing audit log database to disk...")
+		if err := deps.AuditLog.CloseDatabase(); err != nil {
+			Plugin.LogPanicf("Syncing audit log database to disk... failed: %s", err)
+		}
+		Plugin.LogInfo("Syncing audit log database to disk... done")
+	}, shutdown.PriorityCloseDatabase); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
 }
 
 func setupRoutes() {
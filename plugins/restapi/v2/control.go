@@ -1,22 +1,81 @@
 package v2
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/gommon/bytes"
 	"github.com/pkg/errors"
 
 	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/p2p"
 	"github.com/gohornet/hornet/pkg/restapi"
+	"github.com/gohornet/hornet/pkg/snapshot"
+	"github.com/gohornet/hornet/pkg/standby"
 )
 
-func pruneDatabase(c echo.Context) (*pruneDatabaseResponse, error) {
+const (
+	// the operation started via a control route is still running.
+	controlOperationStateRunning = "running"
+	// the operation started via a control route has finished.
+	controlOperationStateSucceeded = "succeeded"
+	// the operation started via a control route has failed.
+	controlOperationStateFailed = "failed"
+)
+
+var (
+	// the status of the last pruning and snapshot operations started via the control routes.
+	controlOperationsLock sync.Mutex
+	pruningStatus         *pruneDatabaseResponse
+	snapshotsStatus       *createSnapshotsResponse
+)
+
+func newControlOperationStatus() controlOperationStatus {
+	return controlOperationStatus{
+		State:     controlOperationStateRunning,
+		StartedAt: time.Now().Unix(),
+	}
+}
+
+// finishControlOperation sets the final state of the operation.
+// controlOperationsLock must be held while calling this function.
+func finishControlOperation(status *controlOperationStatus, err error) {
+	status.EndedAt = time.Now().Unix()
+	if err != nil {
+		status.State = controlOperationStateFailed
+		status.Error = err.Error()
+		return
+	}
+	status.State = controlOperationStateSucceeded
+}
+
+// checkNoControlOperationRunning returns an error if the node is creating a snapshot or pruning the database.
+// controlOperationsLock must be held while calling this function.
+func checkNoControlOperationRunning() error {
+	if deps.SnapshotManager.IsSnapshottingOrPruning() ||
+		(pruningStatus != nil && pruningStatus.State == controlOperationStateRunning) ||
+		(snapshotsStatus != nil && snapshotsStatus.State == controlOperationStateRunning) {
+		return errors.WithMessage(echo.ErrServiceUnavailable, "node is already creating a snapshot or pruning is running")
+	}
+	return nil
+}
 
-	if deps.SnapshotManager.IsSnapshottingOrPruning() {
-		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "node is already creating a snapshot or pruning is running")
+// pruningStatusResponse returns a copy of the pruning status with the current pruning index.
+// controlOperationsLock must be held while calling this function.
+func pruningStatusResponse() *pruneDatabaseResponse {
+	status := *pruningStatus
+	if snapshotInfo := deps.Storage.SnapshotInfo(); snapshotInfo != nil {
+		status.PruningIndex = snapshotInfo.PruningIndex
 	}
+	return &status
+}
+
+// pruneDatabase checks whether the database can be pruned to the requested target and starts the pruning in the background.
+func pruneDatabase(c echo.Context) (*pruneDatabaseResponse, error) {
 
 	request := &pruneDatabaseRequest{}
 	if err := c.Bind(request); err != nil {
@@ -30,46 +89,91 @@ func pruneDatabase(c echo.Context) (*pruneDatabaseResponse, error) {
 		return nil, errors.WithMessage(restapi.ErrInvalidParameter, "either index, depth or size has to be specified")
 	}
 
+	controlOperationsLock.Lock()
+	defer controlOperationsLock.Unlock()
+
+	if err := checkNoControlOperationRunning(); err != nil {
+		return nil, err
+	}
+
+	if deps.SnapshotManager.PruningThrottleStatus().Paused {
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "pruning is paused")
+	}
+
 	var err error
 	var targetIndex milestone.Index
+	var prune func(ctx context.Context) (milestone.Index, error)
 
-	if request.Index != nil {
-		targetIndex, err = deps.SnapshotManager.PruneDatabaseByTargetIndex(Plugin.Daemon().ContextStopped(), *request.Index)
-		if err != nil {
-			return nil, errors.WithMessagef(echo.ErrInternalServerError, "pruning database failed: %s", err)
+	switch {
+	case request.Index != nil, request.Depth != nil:
+		if request.Index != nil {
+			targetIndex = *request.Index
+		} else {
+			confirmedMilestoneIndex := deps.SyncManager.ConfirmedMilestoneIndex()
+			if confirmedMilestoneIndex <= *request.Depth {
+				return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "pruning database not possible: %s", snapshot.ErrNotEnoughHistory)
+			}
+			targetIndex = confirmedMilestoneIndex - *request.Depth
 		}
-	}
 
-	if request.Depth != nil {
-		targetIndex, err = deps.SnapshotManager.PruneDatabaseByDepth(Plugin.Daemon().ContextStopped(), *request.Depth)
+		// the target index is capped to the highest index that can be pruned
+		targetIndex, err = deps.SnapshotManager.CheckPruningTargetIndex(targetIndex)
 		if err != nil {
-			return nil, errors.WithMessagef(echo.ErrInternalServerError, "pruning database failed: %s", err)
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "pruning database not possible: %s", err)
+		}
+
+		prune = func(ctx context.Context) (milestone.Index, error) {
+			return deps.SnapshotManager.PruneDatabaseByTargetIndex(ctx, targetIndex)
 		}
-	}
 
-	if request.TargetDatabaseSize != nil {
+	default:
 		pruningTargetDatabaseSizeBytes, err := bytes.Parse(*request.TargetDatabaseSize)
 		if err != nil {
-			return nil, errors.WithMessagef(echo.ErrInternalServerError, "pruning database failed: %s", err)
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid targetDatabaseSize, error: %s", err)
 		}
 
-		targetIndex, err = deps.SnapshotManager.PruneDatabaseBySize(Plugin.Daemon().ContextStopped(), pruningTargetDatabaseSizeBytes)
-		if err != nil {
-			return nil, errors.WithMessagef(echo.ErrInternalServerError, "pruning database failed: %s", err)
+		// the target index is determined by the pruning
+		prune = func(ctx context.Context) (milestone.Index, error) {
+			return deps.SnapshotManager.PruneDatabaseBySize(ctx, pruningTargetDatabaseSizeBytes)
 		}
 	}
 
-	return &pruneDatabaseResponse{
-		Index: targetIndex,
-	}, nil
+	status := &pruneDatabaseResponse{
+		Index:                  targetIndex,
+		controlOperationStatus: newControlOperationStatus(),
+	}
+	pruningStatus = status
+
+	go func() {
+		index, err := prune(Plugin.Daemon().ContextStopped())
+
+		controlOperationsLock.Lock()
+		defer controlOperationsLock.Unlock()
+
+		if err == nil {
+			status.Index = index
+		}
+		finishControlOperation(&status.controlOperationStatus, err)
+	}()
+
+	return pruningStatusResponse(), nil
 }
 
-func createSnapshots(c echo.Context) (*createSnapshotsResponse, error) {
+func pruneDatabaseStatus(_ echo.Context) (*pruneDatabaseResponse, error) {
+
+	controlOperationsLock.Lock()
+	defer controlOperationsLock.Unlock()
 
-	if deps.SnapshotManager.IsSnapshottingOrPruning() {
-		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "node is already creating a snapshot or pruning is running")
+	if pruningStatus == nil {
+		return nil, errors.WithMessage(echo.ErrNotFound, "no pruning was started")
 	}
 
+	return pruningStatusResponse(), nil
+}
+
+// createSnapshots checks whether the requested snapshots can be created and creates them in the background.
+func createSnapshots(c echo.Context) (*createSnapshotsResponse, error) {
+
 	request := &createSnapshotsRequest{}
 	if err := c.Bind(request); err != nil {
 		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
@@ -79,32 +183,339 @@ func createSnapshots(c echo.Context) (*createSnapshotsResponse, error) {
 		return nil, errors.WithMessage(restapi.ErrInvalidParameter, "at least fullIndex or deltaIndex has to be specified")
 	}
 
-	var fullIndex, deltaIndex milestone.Index
-	var fullSnapshotFilePath, deltaSnapshotFilePath string
+	controlOperationsLock.Lock()
+	defer controlOperationsLock.Unlock()
 
-	if request.FullIndex != nil {
-		fullIndex = milestone.Index(*request.FullIndex)
-		fullSnapshotFilePath = filepath.Join(filepath.Dir(deps.SnapshotsFullPath), fmt.Sprintf("full_snapshot_%d.bin", fullIndex))
+	if err := checkNoControlOperationRunning(); err != nil {
+		return nil, err
+	}
+
+	if request.Target != "" && !snapshotTargetExists(request.Target) {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "unknown snapshot target: %s", request.Target)
+	}
 
-		if err := deps.SnapshotManager.CreateFullSnapshot(Plugin.Daemon().ContextStopped(), fullIndex, fullSnapshotFilePath, false); err != nil {
-			return nil, errors.WithMessagef(echo.ErrInternalServerError, "creating full snapshot failed: %s", err)
+	status := &createSnapshotsResponse{
+		controlOperationStatus: newControlOperationStatus(),
+		Target:                 request.Target,
+	}
+
+	// snapshots streamed to a target are only identified by their file name
+	snapshotFilePath := func(nodeFilePath string, fileName string) string {
+		if request.Target != "" {
+			return fileName
+		}
+		return filepath.Join(filepath.Dir(nodeFilePath), fileName)
+	}
+
+	if request.FullIndex != nil {
+		if err := deps.SnapshotManager.CheckSnapshotTargetIndex(*request.FullIndex); err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "creating full snapshot not possible: %s", err)
 		}
+		status.FullIndex = *request.FullIndex
+		status.FullFilePath = snapshotFilePath(deps.SnapshotsFullPath, fmt.Sprintf("full_snapshot_%d.bin", status.FullIndex))
 	}
 
 	if request.DeltaIndex != nil {
-		deltaIndex = milestone.Index(*request.DeltaIndex)
-		deltaSnapshotFilePath = filepath.Join(filepath.Dir(deps.SnapshotsDeltaPath), fmt.Sprintf("delta_snapshot_%d.bin", deltaIndex))
+		if err := deps.SnapshotManager.CheckSnapshotTargetIndex(*request.DeltaIndex); err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "creating delta snapshot not possible: %s", err)
+		}
+		status.DeltaIndex = *request.DeltaIndex
+		status.DeltaFilePath = snapshotFilePath(deps.SnapshotsDeltaPath, fmt.Sprintf("delta_snapshot_%d.bin", status.DeltaIndex))
+	}
+
+	snapshotsStatus = status
+	target := status.Target
+	fullIndex, fullSnapshotFilePath := status.FullIndex, status.FullFilePath
+	deltaIndex, deltaSnapshotFilePath := status.DeltaIndex, status.DeltaFilePath
+
+	create := func(ctx context.Context) error {
+		if fullSnapshotFilePath != "" {
+			var err error
+			if target != "" {
+				err = deps.SnapshotManager.CreateFullSnapshotInTarget(ctx, fullIndex, target, fullSnapshotFilePath)
+			} else {
+				err = deps.SnapshotManager.CreateFullSnapshot(ctx, fullIndex, fullSnapshotFilePath, false)
+			}
+			if err != nil {
+				return fmt.Errorf("creating full snapshot failed: %w", err)
+			}
+		}
+
+		if deltaSnapshotFilePath != "" {
+			// if no full snapshot was created, the last existing full snapshot will be used
+			var err error
+			if target != "" {
+				err = deps.SnapshotManager.CreateDeltaSnapshotInTarget(ctx, deltaIndex, target, deltaSnapshotFilePath, fullIndex)
+			} else {
+				err = deps.SnapshotManager.CreateDeltaSnapshot(ctx, deltaIndex, deltaSnapshotFilePath, false, fullSnapshotFilePath)
+			}
+			if err != nil {
+				return fmt.Errorf("creating delta snapshot failed: %w", err)
+			}
+		}
+
+		return nil
+	}
+
+	go func() {
+		err := create(Plugin.Daemon().ContextStopped())
+
+		controlOperationsLock.Lock()
+		defer controlOperationsLock.Unlock()
+
+		finishControlOperation(&status.controlOperationStatus, err)
+	}()
+
+	resp := *status
+	return &resp, nil
+}
+
+// snapshotTargetExists returns whether a snapshot target with the given name was configured.
+func snapshotTargetExists(name string) bool {
+	for _, targetName := range deps.SnapshotManager.TargetNames() {
+		if targetName == name {
+			return true
+		}
+	}
+	return false
+}
+
+func createSnapshotsStatus(_ echo.Context) (*createSnapshotsResponse, error) {
+
+	controlOperationsLock.Lock()
+	defer controlOperationsLock.Unlock()
+
+	if snapshotsStatus == nil {
+		return nil, errors.WithMessage(echo.ErrNotFound, "no snapshot creation was started")
+	}
+
+	resp := *snapshotsStatus
+	return &resp, nil
+}
+
+func pruningThrottle(_ echo.Context) *pruningThrottleResponse {
+	status := deps.SnapshotManager.PruningThrottleStatus()
+
+	resp := &pruningThrottleResponse{
+		MilestonesPerMinute: status.MilestonesPerMinute,
+		Paused:              status.Paused,
+	}
+	if status.MaxBytesPerSecond > 0 {
+		resp.MaxBytesPerSecond = bytes.Format(status.MaxBytesPerSecond)
+	}
+	return resp
+}
+
+// setPruningThrottle changes the throttle of the pruning, and pauses or resumes it.
+// The change is not written to the config, so the configured throttle is active again after a restart.
+func setPruningThrottle(c echo.Context) (*pruningThrottleResponse, error) {
+
+	request := &pruningThrottleRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	throttle := deps.SnapshotManager.PruningThrottleStatus().PruningThrottle
+	if request.MilestonesPerMinute != nil {
+		throttle.MilestonesPerMinute = *request.MilestonesPerMinute
+	}
+	if request.MaxBytesPerSecond != "" {
+		maxBytesPerSecond, err := bytes.Parse(request.MaxBytesPerSecond)
+		if err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid maxBytesPerSecond, error: %s", err)
+		}
+		throttle.MaxBytesPerSecond = maxBytesPerSecond
+	}
+
+	if err := deps.SnapshotManager.SetPruningThrottle(throttle); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid pruning throttle: %s", err)
+	}
+
+	if request.Paused != nil {
+		if *request.Paused {
+			deps.SnapshotManager.PausePruning()
+			Plugin.LogInfo("pruning paused")
+		} else {
+			deps.SnapshotManager.ResumePruning()
+			Plugin.LogInfo("pruning resumed")
+		}
+	}
+
+	return pruningThrottle(c), nil
+}
+
+func newSnapshotsScheduleResponse(status *snapshot.ScheduleStatus) *snapshotsScheduleResponse {
+	resp := &snapshotsScheduleResponse{
+		Policy:   status.Policy,
+		Interval: status.Interval,
+		Cron:     status.Cron,
+	}
+	if status.LedgerGrowthBytes > 0 {
+		resp.LedgerGrowth = bytes.Format(status.LedgerGrowthBytes)
+	}
+	if !status.NextSnapshotTime.IsZero() {
+		resp.NextSnapshotAt = status.NextSnapshotTime.Unix()
+	}
+	if status.Policy == snapshot.SchedulePolicyLedgerGrowth {
+		resp.CurrentLedgerGrowth = bytes.Format(status.CurrentLedgerGrowthBytes)
+	}
+	return resp
+}
+
+func snapshotsSchedule(_ echo.Context) (*snapshotsScheduleResponse, error) {
+
+	status, err := deps.SnapshotManager.ScheduleStatus()
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "reading snapshot schedule failed: %s", err)
+	}
+
+	return newSnapshotsScheduleResponse(status), nil
+}
+
+// setSnapshotsSchedule replaces the schedule of the automatic snapshot creation.
+// The change is not written to the config, so the configured schedule is active again after a restart.
+func setSnapshotsSchedule(c echo.Context) (*snapshotsScheduleResponse, error) {
+
+	request := &snapshotsScheduleRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	status, err := deps.SnapshotManager.ScheduleStatus()
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "reading snapshot schedule failed: %s", err)
+	}
+
+	schedule := status.Schedule
+	if request.Policy != "" {
+		schedule.Policy = request.Policy
+	}
+	if request.Interval != nil {
+		schedule.Interval = *request.Interval
+	}
+	if request.Cron != "" {
+		schedule.Cron = request.Cron
+	}
+	if request.LedgerGrowth != "" {
+		ledgerGrowthBytes, err := bytes.Parse(request.LedgerGrowth)
+		if err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid ledgerGrowth, error: %s", err)
+		}
+		schedule.LedgerGrowthBytes = ledgerGrowthBytes
+	}
+
+	if err := deps.SnapshotManager.SetSchedule(&schedule); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid snapshot schedule: %s", err)
+	}
+
+	Plugin.LogInfof("snapshot schedule changed, policy: %s", schedule.Policy)
+
+	return snapshotsSchedule(c)
+}
+
+func newIdentityRotationResponse(rotation *p2p.IdentityRotation) *identityRotationResponse {
+	return &identityRotationResponse{
+		OldID:         rotation.OldID,
+		NewID:         rotation.NewID,
+		StagedAt:      rotation.Timestamp,
+		ActivateAfter: rotation.ActivateAfter,
+	}
+}
+
+func identityRotation(_ echo.Context) (*identityRotationResponse, error) {
+
+	if deps.IdentityRotator == nil {
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "identity rotation is not available")
+	}
+
+	rotation := deps.IdentityRotator.Rotation()
+	if rotation == nil {
+		return nil, errors.WithMessage(echo.ErrNotFound, "no identity rotation staged")
+	}
+
+	return newIdentityRotationResponse(rotation), nil
+}
+
+func stageIdentityRotation(c echo.Context) (*identityRotationResponse, error) {
+
+	if deps.IdentityRotator == nil {
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "identity rotation is not available")
+	}
+
+	request := &stageIdentityRotationRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	gracePeriod := deps.IdentityRotator.GracePeriod()
+	if request.GracePeriod != "" {
+		var err error
+		gracePeriod, err = time.ParseDuration(request.GracePeriod)
+		if err != nil || gracePeriod < 0 {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid grace period: %s", request.GracePeriod)
+		}
+	}
+
+	rotation, err := deps.IdentityRotator.Stage(gracePeriod)
+	if err != nil {
+		if errors.Is(err, p2p.ErrIdentityRotationAlreadyStaged) {
+			return nil, errors.WithMessage(echo.ErrBadRequest, err.Error())
+		}
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "staging identity rotation failed: %s", err)
+	}
+
+	return newIdentityRotationResponse(rotation), nil
+}
+
+func cancelIdentityRotation(_ echo.Context) error {
+
+	if deps.IdentityRotator == nil {
+		return errors.WithMessage(echo.ErrServiceUnavailable, "identity rotation is not available")
+	}
+
+	if err := deps.IdentityRotator.Cancel(); err != nil {
+		if errors.Is(err, p2p.ErrNoIdentityRotation) {
+			return errors.WithMessage(echo.ErrNotFound, err.Error())
+		}
+		return errors.WithMessagef(echo.ErrInternalServerError, "canceling identity rotation failed: %s", err)
+	}
+
+	return nil
+}
+
+func newStandbyResponse(status *standby.Status) *standbyResponse {
+	resp := &standbyResponse{
+		Standby:      status.Standby,
+		StandbySince: status.StandbySince.Unix(),
+	}
+	if !status.PromotedAt.IsZero() {
+		resp.PromotedAt = status.PromotedAt.Unix()
+	}
+	return resp
+}
+
+func standbyStatus(_ echo.Context) (*standbyResponse, error) {
+
+	if deps.Standby == nil {
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "standby plugin is disabled")
+	}
+
+	return newStandbyResponse(deps.Standby.Status()), nil
+}
+
+func promoteStandby(_ echo.Context) (*standbyResponse, error) {
+
+	if deps.Standby == nil {
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "standby plugin is disabled")
+	}
 
-		// if no full snapshot was created, the last existing full snapshot will be used
-		if err := deps.SnapshotManager.CreateDeltaSnapshot(Plugin.Daemon().ContextStopped(), deltaIndex, deltaSnapshotFilePath, false, fullSnapshotFilePath); err != nil {
-			return nil, errors.WithMessagef(echo.ErrInternalServerError, "creating delta snapshot failed: %s", err)
+	status, err := deps.Standby.Promote()
+	if err != nil {
+		if errors.Is(err, standby.ErrAlreadyPromoted) {
+			return nil, errors.WithMessage(echo.ErrBadRequest, err.Error())
 		}
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "promoting node failed: %s", err)
 	}
 
-	return &createSnapshotsResponse{
-		FullIndex:     fullIndex,
-		FullFilePath:  fullSnapshotFilePath,
-		DeltaIndex:    deltaIndex,
-		DeltaFilePath: deltaSnapshotFilePath,
-	}, nil
+	return newStandbyResponse(status), nil
 }
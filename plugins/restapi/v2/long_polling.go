@@ -0,0 +1,165 @@
+package v2
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/restapi"
+	"github.com/gohornet/hornet/pkg/utils"
+)
+
+const (
+	// waitForSolid waits until the message is solid.
+	waitForSolid = "solid"
+	// waitForReferenced waits until the message is referenced by a milestone.
+	waitForReferenced = "referenced"
+)
+
+// longPollingLimiter limits the amount of long-polling requests which are waiting at the same time per client.
+type longPollingLimiter struct {
+	sync.Mutex
+	// the maximum amount of waiting requests per client, 0 disables long-polling.
+	maxRequestsPerClient int
+	// the amount of waiting requests per client.
+	waiting map[string]int
+}
+
+func newLongPollingLimiter(maxRequestsPerClient int) *longPollingLimiter {
+	return &longPollingLimiter{
+		maxRequestsPerClient: maxRequestsPerClient,
+		waiting:              make(map[string]int),
+	}
+}
+
+// acquire reserves a waiting slot for the client and tells whether the client is below its limit.
+func (l *longPollingLimiter) acquire(client string) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.waiting[client] >= l.maxRequestsPerClient {
+		return false
+	}
+	l.waiting[client]++
+
+	return true
+}
+
+// release frees a waiting slot of the client.
+func (l *longPollingLimiter) release(client string) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.waiting[client]--
+	if l.waiting[client] <= 0 {
+		delete(l.waiting, client)
+	}
+}
+
+// parses the long-polling timeout of the request, which defaults to and is capped at longPollingMaxTimeout.
+func parseLongPollingTimeout(c echo.Context) (time.Duration, error) {
+	timeoutParam := c.QueryParam(QueryParameterTimeout)
+	if len(timeoutParam) == 0 {
+		return longPollingMaxTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(timeoutParam)
+	if err != nil || timeout <= 0 {
+		return 0, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid %s: %s", QueryParameterTimeout, timeoutParam)
+	}
+
+	if timeout > longPollingMaxTimeout {
+		timeout = longPollingMaxTimeout
+	}
+
+	return timeout, nil
+}
+
+// waitForMessageMetadata blocks until the message reached the state given in the "waitFor" query parameter,
+// the timeout expired or the client closed the connection.
+func waitForMessageMetadata(c echo.Context, messageID hornet.MessageID) error {
+
+	waitFor := c.QueryParam(QueryParameterWaitFor)
+	if waitFor != waitForSolid && waitFor != waitForReferenced {
+		return errors.WithMessagef(restapi.ErrInvalidParameter, "invalid %s: %s, must be \"%s\" or \"%s\"", QueryParameterWaitFor, waitFor, waitForSolid, waitForReferenced)
+	}
+
+	timeout, err := parseLongPollingTimeout(c)
+	if err != nil {
+		return err
+	}
+
+	if longPollingRequests.maxRequestsPerClient <= 0 {
+		return errors.WithMessage(echo.ErrServiceUnavailable, "long-polling is disabled")
+	}
+
+	client := c.RealIP()
+	if !longPollingRequests.acquire(client) {
+		return errors.WithMessagef(echo.ErrTooManyRequests, "too many waiting requests, the limit is %d per client", longPollingRequests.maxRequestsPerClient)
+	}
+	defer longPollingRequests.release(client)
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+	defer cancel()
+
+	mergedCtx, mergedCtxCancel := utils.MergeContexts(ctx, Plugin.Daemon().ContextStopped())
+	defer mergedCtxCancel()
+
+	waitForMessageState(mergedCtx, messageID, waitFor)
+
+	return nil
+}
+
+// messageStateEvent returns a channel that gets closed when the message reaches the next state on the way
+// to the wanted state, and the function to deregister the event. It returns a nil channel if the wanted state is reached.
+// The event is registered before the state is checked, so a state change in between can't be missed.
+func messageStateEvent(messageID hornet.MessageID, waitFor string) (chan struct{}, func(hornet.MessageID)) {
+
+	processedChan := deps.Tangle.RegisterMessageProcessedEvent(messageID)
+	if !deps.Storage.ContainsMessage(messageID) {
+		return processedChan, deps.Tangle.DeregisterMessageProcessedEvent
+	}
+	deps.Tangle.DeregisterMessageProcessedEvent(messageID)
+
+	eventChan, deregister := deps.Tangle.RegisterMessageSolidEvent(messageID), deps.Tangle.DeregisterMessageSolidEvent
+	if waitFor == waitForReferenced {
+		eventChan, deregister = deps.Tangle.RegisterMessageReferencedEvent(messageID), deps.Tangle.DeregisterMessageReferencedEvent
+	}
+
+	cachedMsgMeta := deps.Storage.CachedMessageMetadataOrNil(messageID) // meta +1
+	if cachedMsgMeta == nil {
+		// the message was pruned in the meantime
+		deregister(messageID)
+		return nil, nil
+	}
+	defer cachedMsgMeta.Release(true) // meta -1
+
+	metadata := cachedMsgMeta.Metadata()
+	if (waitFor == waitForSolid && metadata.IsSolid()) || (waitFor == waitForReferenced && metadata.IsReferenced()) {
+		deregister(messageID)
+		return nil, nil
+	}
+
+	return eventChan, deregister
+}
+
+// waitForMessageState blocks until the message reached the wanted state or the context is done.
+func waitForMessageState(ctx context.Context, messageID hornet.MessageID, waitFor string) {
+	for {
+		eventChan, deregister := messageStateEvent(messageID, waitFor)
+		if eventChan == nil {
+			return
+		}
+
+		if err := utils.WaitForChannelClosed(ctx, eventChan); err != nil {
+			// deregistering also wakes up other requests waiting for the same message,
+			// they check the state again and register a new event.
+			deregister(messageID)
+			return
+		}
+	}
+}
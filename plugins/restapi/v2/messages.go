@@ -2,7 +2,10 @@ package v2
 
 import (
 	"context"
+	"encoding/hex"
 	"io/ioutil"
+	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,12 +14,14 @@ import (
 
 	"github.com/gohornet/hornet/pkg/common"
 	"github.com/gohornet/hornet/pkg/dag"
+	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/pow"
 	"github.com/gohornet/hornet/pkg/restapi"
 	"github.com/gohornet/hornet/pkg/tipselect"
 	"github.com/gohornet/hornet/pkg/utils"
+	"github.com/gohornet/hornet/pkg/whiteflag"
 	"github.com/iotaledger/hive.go/objectstorage"
 	"github.com/iotaledger/hive.go/serializer/v2"
 	iotago "github.com/iotaledger/iota.go/v3"
@@ -37,6 +42,52 @@ func messageMetadataByID(c echo.Context) (*messageMetadataResponse, error) {
 		return nil, err
 	}
 
+	if len(c.QueryParam(QueryParameterWaitFor)) > 0 {
+		if err := waitForMessageMetadata(c, messageID); err != nil {
+			return nil, err
+		}
+
+		if snapshotReads {
+			// the snapshot reads middleware skips long-polling requests
+			deps.Storage.ReadLockConfirmation()
+			defer deps.Storage.ReadUnlockConfirmation()
+		}
+	}
+
+	messageMetadataResponse, err := messageMetadataByMessageID(messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if namespacesParam := c.QueryParam(QueryParameterAnnotations); len(namespacesParam) > 0 {
+		annotations, err := messageAnnotations(messageID, namespacesParam)
+		if err != nil {
+			return nil, err
+		}
+		messageMetadataResponse.Annotations = annotations
+	}
+
+	return messageMetadataResponse, nil
+}
+
+// messageMetadataByMessageID returns the current metadata of the message.
+// ledgerInclusionState returns the ledger inclusion state of a referenced message and the reason if it is conflicting.
+func ledgerInclusionState(metadata *storage.MessageMetadata) (string, *storage.Conflict) {
+
+	conflict := metadata.Conflict()
+
+	switch {
+	case conflict != storage.ConflictNone:
+		return ledgerInclusionStateConflicting, &conflict
+	case metadata.IsIncludedTxInLedger():
+		return ledgerInclusionStateIncluded, nil
+	default:
+		return ledgerInclusionStateNoTransaction, nil
+	}
+}
+
+func messageMetadataByMessageID(messageID hornet.MessageID) (*messageMetadataResponse, error) {
+
 	cachedMsgMeta := deps.Storage.CachedMessageMetadataOrNil(messageID)
 	if cachedMsgMeta == nil {
 		return nil, errors.WithMessagef(echo.ErrNotFound, "message not found: %s", messageID.ToHex())
@@ -63,18 +114,9 @@ func messageMetadataByID(c echo.Context) (*messageMetadataResponse, error) {
 	}
 
 	if referenced {
-		inclusionState := "noTransaction"
-
-		conflict := metadata.Conflict()
-
-		if conflict != storage.ConflictNone {
-			inclusionState = "conflicting"
-			messageMetadataResponse.ConflictReason = &conflict
-		} else if metadata.IsIncludedTxInLedger() {
-			inclusionState = "included"
-		}
-
+		inclusionState, conflictReason := ledgerInclusionState(metadata)
 		messageMetadataResponse.LedgerInclusionState = &inclusionState
+		messageMetadataResponse.ConflictReason = conflictReason
 	} else if metadata.IsSolid() {
 		// determine info about the quality of the tip if not referenced
 		cmi := deps.SyncManager.ConfirmedMilestoneIndex()
@@ -111,6 +153,44 @@ func messageMetadataByID(c echo.Context) (*messageMetadataResponse, error) {
 	return messageMetadataResponse, nil
 }
 
+// messageAnnotations returns the hex encoded annotations of the message in the comma separated namespaces ("*" for all).
+func messageAnnotations(messageID hornet.MessageID, namespacesParam string) (map[string]map[string]string, error) {
+
+	annotations := make(map[string]map[string]string)
+	addAnnotation := func(namespace string, key string, value []byte) {
+		if _, exists := annotations[namespace]; !exists {
+			annotations[namespace] = make(map[string]string)
+		}
+		annotations[namespace][key] = hex.EncodeToString(value)
+	}
+
+	if namespacesParam == "*" {
+		if err := deps.Storage.ForEachMessageAnnotation(messageID, func(namespace string, key string, value []byte) bool {
+			addAnnotation(namespace, key, value)
+			return true
+		}); err != nil {
+			return nil, errors.WithMessagef(echo.ErrInternalServerError, "reading message annotations failed: %s", err)
+		}
+		return annotations, nil
+	}
+
+	for _, namespace := range strings.Split(namespacesParam, ",") {
+		namespaceAnnotations, err := deps.Storage.MessageAnnotations(messageID, strings.TrimSpace(namespace))
+		if err != nil {
+			if errors.Is(err, storage.ErrInvalidMessageAnnotation) {
+				return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid annotation namespace: %s, error: %s", namespace, err)
+			}
+			return nil, errors.WithMessagef(echo.ErrInternalServerError, "reading message annotations failed: %s", err)
+		}
+
+		for key, value := range namespaceAnnotations {
+			addAnnotation(strings.TrimSpace(namespace), key, value)
+		}
+	}
+
+	return annotations, nil
+}
+
 func messageByID(c echo.Context) (*iotago.Message, error) {
 	messageID, err := restapi.ParseMessageIDParam(c)
 	if err != nil {
@@ -145,26 +225,54 @@ func childrenIDsByID(c echo.Context) (*childrenResponse, error) {
 
 	messageID, err := restapi.ParseMessageIDParam(c)
 	if err != nil {
-		return nil, nil
+		return nil, err
+	}
+
+	page, err := restapi.ParsePage(c, deps.RestAPILimitsMaxResults)
+	if err != nil {
+		return nil, err
 	}
 
-	maxResults := deps.RestAPILimitsMaxResults
-	childrenMessageIDs := deps.Storage.ChildrenMessageIDs(messageID, objectstorage.WithIteratorMaxIterations(maxResults))
+	childrenMessageIDs := hornet.MessageIDs{}
+	deps.Storage.ForEachChild(func(_ hornet.MessageID, childMessageID hornet.MessageID) bool {
+		inPage, next := page.Next()
+		if inPage {
+			childrenMessageIDs = append(childrenMessageIDs, childMessageID)
+		}
+		return next
+	}, objectstorage.WithIteratorPrefix(messageID))
 
 	return &childrenResponse{
 		MessageID:  messageID.ToHex(),
-		MaxResults: uint32(maxResults),
+		MaxResults: uint32(deps.RestAPILimitsMaxResults),
 		Count:      uint32(len(childrenMessageIDs)),
+		PageSize:   page.PageSize(),
+		Cursor:     page.NextCursor(),
 		Children:   childrenMessageIDs.ToHex(),
 	}, nil
 }
 
-func sendMessage(c echo.Context) (*messageCreatedResponse, error) {
+func streamChildrenIDsByID(c echo.Context) error {
 
-	if !deps.SyncManager.IsNodeAlmostSynced() {
-		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "node is not synced")
+	messageID, err := restapi.ParseMessageIDParam(c)
+	if err != nil {
+		return err
 	}
 
+	stream := restapi.NewNDJSONStream(c, http.StatusOK)
+
+	// the children are written while iterating, so they are never collected in memory
+	deps.Storage.ForEachChild(func(_ hornet.MessageID, childMessageID hornet.MessageID) bool {
+		return stream.Write(&childResponse{MessageID: childMessageID.ToHex()}) == nil
+	}, objectstorage.WithIteratorPrefix(messageID), objectstorage.WithIteratorMaxIterations(deps.RestAPILimitsMaxStreamedResults))
+
+	return stream.Close()
+}
+
+// parseMessage parses the message in the body of the request, either JSON or binary encoded.
+// The network ID is set to the one of the node if it is missing.
+func parseMessage(c echo.Context) (*iotago.Message, error) {
+
 	msg := &iotago.Message{}
 
 	contentType := c.Request().Header.Get(echo.HeaderContentType)
@@ -199,34 +307,58 @@ func sendMessage(c echo.Context) (*messageCreatedResponse, error) {
 		msg.NetworkID = deps.NetworkID
 	}
 
-	var refreshTipsFunc pow.RefreshTipsFunc
+	return msg, nil
+}
+
+// attachParents selects tips as parents of the message if it has none.
+// It returns the function to refresh the tips if they were selected by the node.
+func attachParents(msg *iotago.Message) (pow.RefreshTipsFunc, error) {
 
-	if len(msg.Parents) == 0 {
-		if deps.TipSelector == nil {
-			return nil, errors.WithMessage(restapi.ErrInvalidParameter, "invalid message, error: no parents given and node tipselection disabled")
-		}
+	if len(msg.Parents) > 0 {
+		return nil, nil
+	}
 
-		tips, err := deps.TipSelector.SelectNonLazyTips()
-		if err != nil {
-			if errors.Is(err, common.ErrNodeNotSynced) || errors.Is(err, tipselect.ErrNoTipsAvailable) {
-				return nil, errors.WithMessage(echo.ErrServiceUnavailable, err.Error())
-			}
-			return nil, errors.WithMessage(echo.ErrInternalServerError, err.Error())
+	if deps.TipSelector == nil {
+		return nil, errors.WithMessage(restapi.ErrInvalidParameter, "invalid message, error: no parents given and node tipselection disabled")
+	}
+
+	tips, err := deps.TipSelector.SelectNonLazyTips()
+	if err != nil {
+		if errors.Is(err, common.ErrNodeNotSynced) || errors.Is(err, tipselect.ErrNoTipsAvailable) {
+			return nil, errors.WithMessage(echo.ErrServiceUnavailable, err.Error())
 		}
-		msg.Parents = tips.ToSliceOfArrays()
+		return nil, errors.WithMessage(echo.ErrInternalServerError, err.Error())
+	}
+	msg.Parents = tips.ToSliceOfArrays()
 
-		// this function pointer is used to refresh the tips of a message
-		// if no parents were given and the PoW takes longer than a configured duration.
-		refreshTipsFunc = deps.TipSelector.SelectNonLazyTips
+	// this function pointer is used to refresh the tips of a message
+	// if no parents were given and the PoW takes longer than a configured duration.
+	return deps.TipSelector.SelectNonLazyTips, nil
+}
+
+func sendMessage(c echo.Context) (*messageCreatedResponse, error) {
+
+	if !deps.SyncManager.IsNodeAlmostSynced() {
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "node is not synced")
+	}
+
+	msg, err := parseMessage(c)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshTipsFunc, err := attachParents(msg)
+	if err != nil {
+		return nil, err
 	}
 
 	if msg.Nonce == 0 {
-		score, err := msg.POW()
+		msgData, err := msg.Serialize(serializer.DeSeriModeNoValidation, nil)
 		if err != nil {
 			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid message, error: %s", err)
 		}
 
-		if score < deps.MinPoWScore {
+		if _, sufficient := deps.PoWScoring.Sufficient(msgData, deps.SyncManager.ConfirmedMilestoneIndex()+1); !sufficient {
 			if !powEnabled {
 				return nil, errors.WithMessage(restapi.ErrInvalidParameter, "proof of work is not enabled on this node")
 			}
@@ -264,3 +396,235 @@ func sendMessage(c echo.Context) (*messageCreatedResponse, error) {
 		MessageID: message.MessageID().ToHex(),
 	}, nil
 }
+
+// validateMessage runs the checks of sendMessage and the ledger checks of the milestone confirmation
+// against the current ledger state, without storing or broadcasting the message.
+func validateMessage(c echo.Context) (*messageValidationResponse, error) {
+
+	if !deps.SyncManager.IsNodeAlmostSynced() {
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "node is not synced")
+	}
+
+	msg, err := parseMessage(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := attachParents(msg); err != nil {
+		return nil, err
+	}
+
+	invalid := func(check string, err error) *messageValidationResponse {
+		return &messageValidationResponse{
+			Valid:       false,
+			FailedCheck: check,
+			Error:       err.Error(),
+		}
+	}
+
+	// the node only does the PoW of messages without nonce if it is enabled
+	powDoneByNode := msg.Nonce == 0 && powEnabled
+
+	message, err := storage.NewMessage(msg, serializer.DeSeriModePerformValidation, deps.DeserializationParameters)
+	if err != nil {
+		return invalid(messageValidationCheckSyntactic, err), nil
+	}
+
+	if err := deps.MessageProcessor.ValidateNetworkID(message); err != nil {
+		return invalid(messageValidationCheckNetwork, err), nil
+	}
+
+	if !powDoneByNode {
+		if err := deps.MessageProcessor.ValidatePoW(message); err != nil {
+			return invalid(messageValidationCheckPoW, err), nil
+		}
+	}
+
+	if err := deps.MessageProcessor.ValidateParents(message); err != nil {
+		return invalid(messageValidationCheckParents, err), nil
+	}
+
+	deps.UTXOManager.ReadLockLedger()
+	defer deps.UTXOManager.ReadUnlockLedger()
+
+	ledgerIndex, err := deps.UTXOManager.ReadLedgerIndexWithoutLocking()
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "reading ledger index failed, error: %s", err)
+	}
+
+	// the message could be referenced by the next milestone at the earliest
+	conflict, err := whiteflag.CheckTransaction(deps.UTXOManager, message, ledgerIndex+1, uint64(time.Now().Unix()))
+	if conflict != storage.ConflictNone {
+		resp := invalid(messageValidationCheckLedger, err)
+		resp.ConflictReason = &conflict
+		return resp, nil
+	}
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "checking the transaction failed, error: %s", err)
+	}
+
+	resp := &messageValidationResponse{Valid: true}
+	if !powDoneByNode {
+		// the message ID changes if the node does the PoW
+		resp.MessageID = message.MessageID().ToHex()
+	}
+
+	return resp, nil
+}
+
+// walkPastCone walks the past cone of the message breadth-first up to the given depth, a negative depth walks the whole cone.
+// Parents for which include returns false are neither part of the cone nor walked further.
+// Pruned parents and solid entry points are skipped. The message itself is always the first message of the cone.
+func walkPastCone(messageID hornet.MessageID, depth int, include func(metadata *storage.MessageMetadata) bool) (hornet.MessageIDs, error) {
+
+	cone := hornet.MessageIDs{messageID}
+	seen := map[string]struct{}{messageID.ToMapKey(): {}}
+	layer := hornet.MessageIDs{messageID}
+
+	for i := 0; (depth < 0 || i < depth) && len(layer) > 0; i++ {
+		var nextLayer hornet.MessageIDs
+		for _, layerMessageID := range layer {
+			cachedMsgMeta := deps.Storage.CachedMessageMetadataOrNil(layerMessageID) // meta +1
+			if cachedMsgMeta == nil {
+				// the parent was already pruned or is a solid entry point
+				continue
+			}
+
+			for _, parent := range cachedMsgMeta.Metadata().Parents() {
+				if _, exists := seen[parent.ToMapKey()]; exists {
+					continue
+				}
+				seen[parent.ToMapKey()] = struct{}{}
+
+				cachedParentMeta := deps.Storage.CachedMessageMetadataOrNil(parent) // meta +1
+				if cachedParentMeta == nil {
+					continue
+				}
+				included := include == nil || include(cachedParentMeta.Metadata())
+				cachedParentMeta.Release(true) // meta -1
+
+				if !included {
+					continue
+				}
+
+				if len(cone) >= deps.RestAPILimitsMaxResults {
+					cachedMsgMeta.Release(true) // meta -1
+					return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "past cone exceeds the maximum amount of %d messages", deps.RestAPILimitsMaxResults)
+				}
+
+				cone = append(cone, parent)
+				nextLayer = append(nextLayer, parent)
+			}
+			cachedMsgMeta.Release(true) // meta -1
+		}
+		layer = nextLayer
+	}
+
+	return cone, nil
+}
+
+func messageCone(c echo.Context) (*messageConeResponse, error) {
+	messageID, err := restapi.ParseMessageIDParam(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// the whole past cone is returned by default
+	depth := -1
+	if len(c.QueryParam(QueryParameterDepth)) > 0 {
+		depth, err = strconv.Atoi(c.QueryParam(QueryParameterDepth))
+		if err != nil || depth < 0 {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid %s: %s", QueryParameterDepth, c.QueryParam(QueryParameterDepth))
+		}
+	}
+
+	var untilReferenced bool
+	if len(c.QueryParam(QueryParameterUntilReferenced)) > 0 {
+		untilReferenced, err = restapi.ParseBoolQueryParam(c, QueryParameterUntilReferenced)
+		if err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid %s: %s", QueryParameterUntilReferenced, c.QueryParam(QueryParameterUntilReferenced))
+		}
+	}
+
+	if !deps.Storage.ContainsMessage(messageID) {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "message not found: %s", messageID.ToHex())
+	}
+
+	var include func(metadata *storage.MessageMetadata) bool
+	if untilReferenced {
+		include = func(metadata *storage.MessageMetadata) bool {
+			return !metadata.IsReferenced()
+		}
+	}
+
+	coneMessageIDs, err := walkPastCone(messageID, depth, include)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*messageMetadataResponse, 0, len(coneMessageIDs))
+	for _, coneMessageID := range coneMessageIDs {
+		metadata, err := messageMetadataByMessageID(coneMessageID)
+		if err != nil {
+			if errors.Is(err, echo.ErrNotFound) {
+				// the message was pruned in the meantime
+				continue
+			}
+			return nil, err
+		}
+		messages = append(messages, metadata)
+	}
+
+	return &messageConeResponse{
+		MessageID: messageID.ToHex(),
+		Messages:  messages,
+	}, nil
+}
+
+func pinMessage(c echo.Context) (*pinMessageResponse, error) {
+	messageID, err := restapi.ParseMessageIDParam(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var depth int
+	if len(c.QueryParam(QueryParameterDepth)) > 0 {
+		depth, err = strconv.Atoi(c.QueryParam(QueryParameterDepth))
+		if err != nil || depth < 0 {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid %s: %s", QueryParameterDepth, c.QueryParam(QueryParameterDepth))
+		}
+	}
+
+	if !deps.Storage.ContainsMessage(messageID) {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "message not found: %s", messageID.ToHex())
+	}
+
+	messageIDsToPin, err := walkPastCone(messageID, depth, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, messageIDToPin := range messageIDsToPin {
+		if err := deps.Storage.PinMessage(messageIDToPin); err != nil {
+			return nil, errors.WithMessagef(echo.ErrInternalServerError, "pinning message failed: %s, error: %s", messageIDToPin.ToHex(), err)
+		}
+	}
+
+	return &pinMessageResponse{
+		MessageID:        messageID.ToHex(),
+		PinnedMessageIDs: messageIDsToPin.ToHex(),
+	}, nil
+}
+
+func unpinMessage(c echo.Context) error {
+	messageID, err := restapi.ParseMessageIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	if err := deps.Storage.UnpinMessage(messageID); err != nil {
+		return errors.WithMessagef(echo.ErrInternalServerError, "unpinning message failed: %s, error: %s", messageID.ToHex(), err)
+	}
+
+	return nil
+}
@@ -57,7 +57,8 @@ func info() (*infoResponse, error) {
 		Protocol: protocolParameters{
 			NetworkName:   deps.NetworkIDName,
 			Bech32HRP:     string(deps.Bech32HRP),
-			MinPoWScore:   deps.MinPoWScore,
+			MinPoWScore:   deps.PoWScoring.MinPoWScore(confirmedMilestoneIndex + 1),
+			PoWScorer:     deps.PoWScoring.Scorer(confirmedMilestoneIndex + 1),
 			RentStructure: deps.DeserializationParameters.RentStructure,
 		},
 		Metrics: nodeMetrics{
@@ -1,6 +1,8 @@
 package v2
 
 import (
+	"time"
+
 	"github.com/labstack/echo/v4"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/multiformats/go-multiaddr"
@@ -60,10 +62,53 @@ func removePeer(c echo.Context) error {
 		return err
 	}
 
-	// error is ignored because we don't care about the config here
-	_ = deps.PeeringConfigManager.RemovePeer(peerID)
+	persist := true
+	if len(c.QueryParam(QueryParameterPersist)) > 0 {
+		persist, err = restapi.ParseBoolQueryParam(c, QueryParameterPersist)
+		if err != nil {
+			return errors.WithMessagef(restapi.ErrInvalidParameter, "invalid %s, error: %s", QueryParameterPersist, err)
+		}
+	}
+
+	if persist {
+		// error is ignored because we don't care about the config here
+		_ = deps.PeeringConfigManager.RemovePeer(peerID)
+	}
+
+	if err := deps.PeeringManager.DisconnectPeer(peerID, errors.New("peer was removed via API")); err != nil {
+		return err
+	}
+
+	// the reconnect policy of the peer is not needed anymore
+	deps.PeeringManager.SetReconnectBackoff(peerID, nil)
+
+	return nil
+}
+
+func reconnectPeer(c echo.Context) (*PeerResponse, error) {
+	peerID, err := restapi.ParsePeerIDParam(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := deps.PeeringManager.ReconnectPeer(peerID); err != nil {
+		switch {
+		case errors.Is(err, p2p.ErrPeerNotFound):
+			return nil, errors.WithMessagef(echo.ErrNotFound, "peer not found, peerID: %s", peerID.String())
+		case errors.Is(err, p2p.ErrPeerNotKnown):
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "only known peers can be reconnected, peerID: %s", peerID.String())
+		case errors.Is(err, p2p.ErrManagerShutdown):
+			return nil, errors.WithMessage(echo.ErrServiceUnavailable, err.Error())
+		}
+		// a failed connection attempt is ignored, because the reconnect of the peer is scheduled
+	}
+
+	info := deps.PeeringManager.PeerInfoSnapshot(peerID)
+	if info == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "peer not found, peerID: %s", peerID.String())
+	}
 
-	return deps.PeeringManager.DisconnectPeer(peerID, errors.New("peer was removed via API"))
+	return WrapInfoSnapshot(info), nil
 }
 
 //nolint:unparam // even if the error is never used, the structure of all routes should be the same
@@ -89,7 +134,17 @@ func addPeer(c echo.Context) (*PeerResponse, error) {
 		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid multiAddress, error: %s", err)
 	}
 
-	addrInfo, err := peer.AddrInfoFromP2pAddr(multiAddr)
+	var transport string
+	if request.Transport != nil {
+		transport = *request.Transport
+	}
+
+	dialAddr, err := p2p.PeerTransportMultiAddress(multiAddr, transport)
+	if err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid transport, error: %s", err)
+	}
+
+	addrInfo, err := peer.AddrInfoFromP2pAddr(dialAddr)
 	if err != nil {
 		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid multiAddress, error: %s", err)
 	}
@@ -99,6 +154,10 @@ func addPeer(c echo.Context) (*PeerResponse, error) {
 		alias = *request.Alias
 	}
 
+	if err := deps.PeeringManager.ApplyPeerReconnectConfig(addrInfo.ID, request.Reconnect); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid reconnect config, error: %s", err)
+	}
+
 	// error is ignored because the peer is added to the known peers and protected from trimming
 	_ = deps.PeeringManager.ConnectPeer(addrInfo, p2p.PeerRelationKnown, alias)
 
@@ -107,8 +166,303 @@ func addPeer(c echo.Context) (*PeerResponse, error) {
 		return nil, errors.WithMessagef(echo.ErrNotFound, "peer not found, peerID: %s", addrInfo.ID.String())
 	}
 
-	// error is ignored because we don't care about the config here
-	_ = deps.PeeringConfigManager.AddPeer(multiAddr, alias)
+	if request.Persist == nil || *request.Persist {
+		// error is ignored because we don't care about the config here
+		_ = deps.PeeringConfigManager.AddPeer(multiAddr, alias, transport, request.Reconnect)
+	}
 
 	return WrapInfoSnapshot(info), nil
 }
+
+//nolint:unparam // even if the error is never used, the structure of all routes should be the same
+func exportPeeringBundle(_ echo.Context) (*p2p.PeeringBundle, error) {
+	allowSubnets, denySubnets := deps.IPFilter.Subnets()
+
+	bundle, err := p2p.NewPeeringBundle(deps.NodePrivateKey, deps.PeeringConfigManager.Peers(), allowSubnets, denySubnets)
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "creating peering bundle failed, error: %s", err)
+	}
+
+	return bundle, nil
+}
+
+func importPeeringBundle(c echo.Context) (*importPeeringBundleResponse, error) {
+
+	bundle := &p2p.PeeringBundle{}
+	if err := c.Bind(bundle); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid peering bundle, error: %s", err)
+	}
+
+	persist := true
+	if len(c.QueryParam(QueryParameterPersist)) > 0 {
+		var err error
+		persist, err = restapi.ParseBoolQueryParam(c, QueryParameterPersist)
+		if err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid %s, error: %s", QueryParameterPersist, err)
+		}
+	}
+
+	mergeIPFilter := false
+	if len(c.QueryParam(QueryParameterMergeIPFilter)) > 0 {
+		var err error
+		mergeIPFilter, err = restapi.ParseBoolQueryParam(c, QueryParameterMergeIPFilter)
+		if err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid %s, error: %s", QueryParameterMergeIPFilter, err)
+		}
+	}
+
+	ownID, err := peer.IDFromPrivateKey(deps.NodePrivateKey)
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "unable to derive own peer ID, error: %s", err)
+	}
+
+	resp := &importPeeringBundleResponse{
+		Peers:        []*PeerResponse{},
+		SkippedPeers: []string{},
+	}
+
+	// everything is validated before the peers and the subnets are applied,
+	// so an invalid bundle doesn't change anything.
+	importFunc := func() error {
+		if bundle.IPFilter != nil {
+			for _, subnets := range [][]string{bundle.IPFilter.Allow, bundle.IPFilter.Deny} {
+				if err := p2p.ValidateSubnets(subnets); err != nil {
+					return errors.WithMessage(restapi.ErrInvalidParameter, err.Error())
+				}
+			}
+		}
+
+		type importedPeer struct {
+			config    *p2p.PeerConfig
+			multiAddr multiaddr.Multiaddr
+			addrInfo  *peer.AddrInfo
+		}
+
+		importedPeers := make([]*importedPeer, 0, len(bundle.Peers))
+		for _, peerConfig := range bundle.Peers {
+			multiAddr, err := multiaddr.NewMultiaddr(peerConfig.MultiAddress)
+			if err != nil {
+				resp.SkippedPeers = append(resp.SkippedPeers, peerConfig.MultiAddress)
+				continue
+			}
+
+			addrInfo, err := peerConfig.AddrInfo()
+			if err != nil || addrInfo.ID == ownID {
+				resp.SkippedPeers = append(resp.SkippedPeers, peerConfig.MultiAddress)
+				continue
+			}
+
+			if peerConfig.Reconnect != nil {
+				if _, err := peerConfig.Reconnect.Backoff(deps.PeeringManager.DefaultReconnectBackoff()); err != nil {
+					resp.SkippedPeers = append(resp.SkippedPeers, peerConfig.MultiAddress)
+					continue
+				}
+			}
+
+			importedPeers = append(importedPeers, &importedPeer{config: peerConfig, multiAddr: multiAddr, addrInfo: addrInfo})
+		}
+
+		if bundle.IPFilter != nil {
+			setSubnets := deps.IPFilter.SetSubnets
+			if mergeIPFilter {
+				setSubnets = deps.IPFilter.AddSubnets
+			}
+			if err := setSubnets(bundle.IPFilter.Allow, bundle.IPFilter.Deny); err != nil {
+				return errors.WithMessagef(echo.ErrInternalServerError, "applying the subnets failed, error: %s", err)
+			}
+		}
+
+		for _, imported := range importedPeers {
+			if err := deps.PeeringManager.ApplyPeerReconnectConfig(imported.addrInfo.ID, imported.config.Reconnect); err != nil {
+				return errors.WithMessagef(echo.ErrInternalServerError, "applying the reconnect config of peer %s failed, error: %s", imported.addrInfo.ID, err)
+			}
+
+			// error is ignored because the peer is added to the known peers and protected from trimming
+			_ = deps.PeeringManager.ConnectPeer(imported.addrInfo, p2p.PeerRelationKnown, imported.config.Alias)
+
+			if persist {
+				// error is ignored because the peer may already exist in the config
+				_ = deps.PeeringConfigManager.AddPeer(imported.multiAddr, imported.config.Alias, imported.config.Transport, imported.config.Reconnect)
+			}
+
+			if info := deps.PeeringManager.PeerInfoSnapshot(imported.addrInfo.ID); info != nil {
+				resp.Peers = append(resp.Peers, WrapInfoSnapshot(info))
+			}
+		}
+
+		return nil
+	}
+
+	// the bundle is only remembered as imported if it was applied
+	if err := peeringBundleVerifier.Import(bundle, importFunc); err != nil {
+		if errors.Is(err, p2p.ErrPeeringBundleUntrustedIssuer) {
+			return nil, errors.WithMessage(echo.ErrForbidden, err.Error())
+		}
+		if errors.Is(err, p2p.ErrPeeringBundleInvalidVersion) ||
+			errors.Is(err, p2p.ErrPeeringBundleInvalidSignature) ||
+			errors.Is(err, p2p.ErrPeeringBundleOutdated) ||
+			errors.Is(err, p2p.ErrPeeringBundleInvalidTimestamp) {
+			return nil, errors.WithMessage(restapi.ErrInvalidParameter, err.Error())
+		}
+		return nil, err
+	}
+
+	allowSubnets, denySubnets := deps.IPFilter.Subnets()
+	resp.IPFilter = &p2p.PeeringBundleIPFilter{Allow: allowSubnets, Deny: denySubnets}
+
+	return resp, nil
+}
+
+//nolint:unparam // even if the error is never used, the structure of all routes should be the same
+func getIPFilter(_ echo.Context) (*p2p.PeeringBundleIPFilter, error) {
+	allowSubnets, denySubnets := deps.IPFilter.Subnets()
+	return &p2p.PeeringBundleIPFilter{Allow: allowSubnets, Deny: denySubnets}, nil
+}
+
+func setIPFilter(c echo.Context) (*p2p.PeeringBundleIPFilter, error) {
+
+	request := &p2p.PeeringBundleIPFilter{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	mergeIPFilter := false
+	if len(c.QueryParam(QueryParameterMergeIPFilter)) > 0 {
+		var err error
+		mergeIPFilter, err = restapi.ParseBoolQueryParam(c, QueryParameterMergeIPFilter)
+		if err != nil {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid %s, error: %s", QueryParameterMergeIPFilter, err)
+		}
+	}
+
+	// the filter is left unchanged if any of the subnets is invalid
+	setSubnets := deps.IPFilter.SetSubnets
+	if mergeIPFilter {
+		setSubnets = deps.IPFilter.AddSubnets
+	}
+	if err := setSubnets(request.Allow, request.Deny); err != nil {
+		return nil, errors.WithMessage(restapi.ErrInvalidParameter, err.Error())
+	}
+
+	return getIPFilter(c)
+}
+
+func peeringConfigDiff(c echo.Context) (*peeringConfigDiffResponse, error) {
+
+	request := &peeringConfigDiffRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	diff, err := deps.PeeringConfigManager.Diff(request.Peers)
+	if err != nil {
+		return nil, errors.WithMessage(restapi.ErrInvalidParameter, err.Error())
+	}
+
+	resp := &peeringConfigDiffResponse{
+		Added:     diff.Added,
+		Modified:  make([]*peerConfigChangeResponse, 0, len(diff.Modified)),
+		Removed:   diff.Removed,
+		Unchanged: diff.Unchanged,
+	}
+
+	for _, change := range diff.Modified {
+		resp.Modified = append(resp.Modified, &peerConfigChangeResponse{
+			ID:  change.ID.String(),
+			Old: change.Old,
+			New: change.New,
+		})
+	}
+
+	return resp, nil
+}
+
+func listPeerBans(_ echo.Context) (*peerBansResponse, error) {
+
+	if deps.CircuitBreaker == nil {
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "circuit breaker is disabled")
+	}
+
+	bans := deps.CircuitBreaker.Bans()
+
+	resp := &peerBansResponse{
+		Bans: make([]*peerBanResponse, 0, len(bans)),
+	}
+
+	for _, ban := range bans {
+		ips := make([]string, len(ban.IPs))
+		for i, ip := range ban.IPs {
+			ips[i] = ip.String()
+		}
+
+		resp.Bans = append(resp.Bans, &peerBanResponse{
+			ID:         ban.ID.String(),
+			IPs:        ips,
+			Violations: ban.Violations,
+			BannedAt:   ban.BannedAt.Unix(),
+			Until:      ban.Until.Unix(),
+		})
+	}
+
+	return resp, nil
+}
+
+func clearPeerBans(_ echo.Context) (*clearPeerBansResponse, error) {
+
+	if deps.CircuitBreaker == nil {
+		return nil, errors.WithMessage(echo.ErrServiceUnavailable, "circuit breaker is disabled")
+	}
+
+	return &clearPeerBansResponse{Removed: deps.CircuitBreaker.ClearBans()}, nil
+}
+
+func removePeerBan(c echo.Context) error {
+
+	if deps.CircuitBreaker == nil {
+		return errors.WithMessage(echo.ErrServiceUnavailable, "circuit breaker is disabled")
+	}
+
+	peerID, err := restapi.ParsePeerIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	if !deps.CircuitBreaker.Unban(peerID) {
+		return errors.WithMessagef(echo.ErrNotFound, "peer is not banned: %s", peerID)
+	}
+
+	return nil
+}
+
+//nolint:unparam // even if the error is never used, the structure of all routes should be the same
+func peersMetrics(_ echo.Context) ([]*peerMetricsResponse, error) {
+
+	// the peering manager must not be called inside of the gossip service loop
+	var protos []*gossip.Protocol
+	deps.GossipService.ForEach(func(proto *gossip.Protocol) bool {
+		protos = append(protos, proto)
+		return true
+	})
+
+	results := make([]*peerMetricsResponse, 0, len(protos))
+	for _, proto := range protos {
+		result := &peerMetricsResponse{
+			ID:             proto.PeerID.String(),
+			ConnectedSince: proto.ConnectedTime.Unix(),
+			Uptime:         int64(time.Since(proto.ConnectedTime).Seconds()),
+			Metrics:        proto.Metrics.Snapshot(),
+		}
+
+		if info := deps.PeeringManager.PeerInfoSnapshot(proto.PeerID); info != nil {
+			if info.Alias != "" {
+				alias := info.Alias
+				result.Alias = &alias
+			}
+			result.Relation = info.Relation
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
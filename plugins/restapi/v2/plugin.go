@@ -1,14 +1,19 @@
 package v2
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/pkg/errors"
 	"go.uber.org/dig"
 
 	"github.com/gohornet/hornet/pkg/app"
+	"github.com/gohornet/hornet/pkg/model/milestonemanager"
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/model/syncmanager"
 	"github.com/gohornet/hornet/pkg/model/utxo"
@@ -17,7 +22,9 @@ import (
 	"github.com/gohornet/hornet/pkg/pow"
 	"github.com/gohornet/hornet/pkg/protocol/gossip"
 	restapipkg "github.com/gohornet/hornet/pkg/restapi"
+	"github.com/gohornet/hornet/pkg/shutdown"
 	"github.com/gohornet/hornet/pkg/snapshot"
+	"github.com/gohornet/hornet/pkg/standby"
 	"github.com/gohornet/hornet/pkg/tangle"
 	"github.com/gohornet/hornet/pkg/tipselect"
 	"github.com/gohornet/hornet/plugins/restapi"
@@ -40,6 +47,8 @@ const (
 
 	// RouteMessageMetadata is the route for getting message metadata by its messageID.
 	// GET returns message metadata (including info about "promotion/reattachment needed").
+	// If the "waitFor" query parameter is set, the request waits until the message reached the given state or the "timeout" expired.
+	// If the "annotations" query parameter is set, the annotations of the given namespaces are included.
 	RouteMessageMetadata = "/messages/:" + restapipkg.ParameterMessageID + "/metadata"
 
 	// RouteMessageBytes is the route for getting message raw data by it's messageID.
@@ -47,13 +56,30 @@ const (
 	RouteMessageBytes = "/messages/:" + restapipkg.ParameterMessageID + "/raw"
 
 	// RouteMessageChildren is the route for getting message IDs of the children of a message, identified by its messageID.
-	// GET returns the message IDs of all children.
+	// GET returns the message IDs of the children, paginated with the "cursor" and "pageSize" query parameters.
+	// If the client accepts "application/x-ndjson", the message IDs are streamed one per line with a higher limit.
 	RouteMessageChildren = "/messages/:" + restapipkg.ParameterMessageID + "/children"
 
+	// RouteMessageCone is the route for getting the past cone of a message, identified by its messageID.
+	// GET returns the metadata of the messages of the past cone, optionally limited by the "depth" and "untilReferenced" query parameters.
+	RouteMessageCone = "/messages/:" + restapipkg.ParameterMessageID + "/cone"
+
+	// RouteMessageProof is the route for getting the proof that a message was referenced by a milestone, identified by its messageID.
+	// GET returns the milestone and the Merkle audit path of the message, or the messages that reference it if it was not included in the ledger.
+	RouteMessageProof = "/messages/:" + restapipkg.ParameterMessageID + "/proof"
+
+	// RouteMessagesProofVerify is the route for verifying proofs returned by RouteMessageProof.
+	// POST verifies the proof against the milestone public keys without using the tangle of the node and returns the result.
+	RouteMessagesProofVerify = "/messages/proof/verify"
+
 	// RouteMessages is the route for getting message IDs or creating new messages.
 	// POST creates a single new message and returns the new message ID.
 	RouteMessages = "/messages"
 
+	// RouteMessagesValidate is the route for validating messages without submitting them.
+	// POST validates the message like it would be validated on submission and against the current ledger state, and returns the result.
+	RouteMessagesValidate = "/messages/validate"
+
 	// RouteTransactionsIncludedMessage is the route for getting the message that was included in the ledger for a given transaction ID.
 	// GET returns message data (json).
 	RouteTransactionsIncludedMessage = "/transactions/:" + restapipkg.ParameterTransactionID + "/included-message"
@@ -70,32 +96,137 @@ const (
 	// GET returns the output.
 	RouteOutput = "/outputs/:" + restapipkg.ParameterOutputID
 
+	// RouteAddressBalance is the route for getting the balance of a bech32 address.
+	// GET returns the balance at the milestone index given by the "milestoneIndex" query parameter, or the current balance.
+	RouteAddressBalance = "/addresses/:" + restapipkg.ParameterAddress + "/balance"
+
 	// RouteTreasury is the route for getting the current treasury output.
 	RouteTreasury = "/treasury"
 
 	// RouteReceipts is the route for getting all stored receipts.
+	// The receipts are paginated with the "cursor" and "pageSize" query parameters.
+	// If the client accepts "application/x-ndjson", the receipts are streamed one per line.
 	RouteReceipts = "/receipts"
 
 	// RouteReceiptsMigratedAtIndex is the route for getting all receipts for a given migrated at index.
+	// The receipts are paginated with the "cursor" and "pageSize" query parameters.
+	// If the client accepts "application/x-ndjson", the receipts are streamed one per line.
 	RouteReceiptsMigratedAtIndex = "/receipts/:" + restapipkg.ParameterMilestoneIndex
 
 	// RoutePeer is the route for getting peers by their peerID.
 	// GET returns the peer
-	// DELETE deletes the peer.
+	// DELETE deletes the peer. The peering config is only changed if the "persist" query parameter is not set to false.
 	RoutePeer = "/peers/:" + restapipkg.ParameterPeerID
 
+	// RoutePeerReconnect is the route for reconnecting a known peer.
+	// POST closes the connections to the peer and connects to it again right away. Returns the peer.
+	RoutePeerReconnect = "/peers/:" + restapipkg.ParameterPeerID + "/reconnect"
+
 	// RoutePeers is the route for getting all peers of the node.
 	// GET returns a list of all peers.
-	// POST adds a new peer.
+	// POST adds a new peer. The peering config is only changed if "persist" is not set to false.
 	RoutePeers = "/peers"
 
+	// RoutePeersMetrics is the route for getting the gossip metrics of all connected peers.
+	// GET returns the message, request and traffic counters, the request latencies and the uptime of the connection per peer.
+	RoutePeersMetrics = "/peers/metrics"
+
+	// RoutePeersBundle is the route for exporting and importing the peering configuration of the node.
+	// GET returns the static peers and the IP filter subnets as a bundle signed by the node identity.
+	// POST imports a bundle of a trusted node. The peering config is only changed if the "persist" query parameter is not set to false.
+	// The subnets of the bundle replace the current ones, unless the "mergeIPFilter" query parameter is set to true.
+	RoutePeersBundle = "/peers/bundle"
+
+	// RoutePeersIPFilter is the route for the subnets of the IP filter which checks incoming connections.
+	// GET returns the allowed and denied subnets.
+	// POST replaces the subnets, unless the "mergeIPFilter" query parameter is set to true. The config file is not changed.
+	RoutePeersIPFilter = "/peers/ipfilter"
+
+	// RoutePeersConfigDiff is the route for validating a candidate peering config.
+	// POST returns the peers that would be added, modified and removed, without applying the config.
+	RoutePeersConfigDiff = "/peers/config/diff"
+
+	// RoutePeersBans is the route for the peers which were banned because of protocol violations.
+	// GET returns the active bans.
+	// DELETE removes all bans.
+	RoutePeersBans = "/peers/bans"
+
+	// RoutePeerBan is the route for the ban of a peer.
+	// DELETE removes the ban of the peer.
+	RoutePeerBan = "/peers/bans/:" + restapipkg.ParameterPeerID
+
+	// RouteWebsocket is the route for subscribing to events of the node via a websocket connection.
+	// The topics are subscribed and unsubscribed with JSON commands, the events are pushed as JSON messages.
+	RouteWebsocket = "/ws"
+
+	// RouteRoutesSpec is the route for getting the OpenAPI description of all registered routes.
+	// GET returns the routes of the core API and the enabled plugins, tagged by plugin.
+	// The route is not part of the "/api/v2" group, since it describes the routes of all API versions.
+	RouteRoutesSpec = "/api/routes/spec"
+
+	// QueryParameterDepth is used to define the depth of the past cone of a message that is pinned or returned.
+	QueryParameterDepth = "depth"
+
+	// QueryParameterUntilReferenced is used to define whether the past cone of a message stops at messages that are already referenced by a milestone.
+	QueryParameterUntilReferenced = "untilReferenced"
+
+	// QueryParameterMilestoneIndex is used to define the milestone index at which the balance of an address is calculated.
+	QueryParameterMilestoneIndex = "milestoneIndex"
+
+	// QueryParameterPersist is used to define whether peer changes are written to the peering config.
+	QueryParameterPersist = "persist"
+
+	// QueryParameterMergeIPFilter is used to define whether the given subnets are added to the current ones of the IP filter instead of replacing them.
+	QueryParameterMergeIPFilter = "mergeIPFilter"
+
+	// QueryParameterWaitFor is used to define the state of a message a long-polling request waits for ("solid" or "referenced").
+	QueryParameterWaitFor = "waitFor"
+
+	// QueryParameterTimeout is used to define the maximum time a long-polling request waits (e.g. "30s").
+	QueryParameterTimeout = "timeout"
+
+	// QueryParameterAnnotations is used to define the comma separated namespaces of the message annotations that are included ("*" for all).
+	QueryParameterAnnotations = "annotations"
+
 	// RouteControlDatabasePrune is the control route to manually prune the database.
-	// POST prunes the database.
+	// GET returns the status of the last started pruning.
+	// POST checks the pruning target and starts to prune the database in the background.
 	RouteControlDatabasePrune = "/control/database/prune"
 
+	// RouteControlDatabasePruneThrottle is the control route for the throttle of the pruning.
+	// GET returns the active throttle and whether the pruning is paused.
+	// POST changes the throttle or pauses and resumes the pruning until the node is restarted.
+	RouteControlDatabasePruneThrottle = "/control/database/prune/throttle"
+
 	// RouteControlSnapshotsCreate is the control route to manually create a snapshot files.
-	// POST creates a snapshot (full, delta or both).
+	// GET returns the status of the last started snapshot creation.
+	// POST checks the target indexes and starts to create the snapshots (full, delta or both) in the background.
 	RouteControlSnapshotsCreate = "/control/snapshots/create"
+
+	// RouteControlSnapshotsSchedule is the control route for the schedule of the automatic snapshot creation.
+	// GET returns the active schedule and the progress towards the next snapshot.
+	// POST replaces the active schedule until the node is restarted.
+	RouteControlSnapshotsSchedule = "/control/snapshots/schedule"
+
+	// RouteControlIdentityRotation is the control route to rotate the p2p identity of the node.
+	// GET returns the staged identity rotation.
+	// POST stages a new identity, which is announced to the peers and activated on restart after the grace period.
+	// DELETE cancels the staged identity rotation.
+	RouteControlIdentityRotation = "/control/identity/rotation"
+
+	// RouteControlMessagePin is the control route for pinning a message, identified by its messageID.
+	// Pinned messages are exempt from pruning.
+	// POST pins the message and optionally its past cone up to the depth given by the "depth" query parameter.
+	// DELETE unpins the message.
+	RouteControlMessagePin = "/control/messages/:" + restapipkg.ParameterMessageID + "/pin"
+
+	// RouteControlStandby is the control route to get the state of the standby mode.
+	// GET returns whether the node runs as a warm standby replica.
+	RouteControlStandby = "/control/standby"
+
+	// RouteControlStandbyPromote is the control route to promote a standby node.
+	// POST ends the standby mode, so that the node starts to serve the public routes.
+	RouteControlStandbyPromote = "/control/standby/promote"
 )
 
 func init() {
@@ -105,6 +236,7 @@ func init() {
 			Name:      "RestAPIV2",
 			DepsFunc:  func(cDeps dependencies) { deps = cDeps },
 			Configure: configure,
+			Run:       run,
 		},
 	}
 }
@@ -113,9 +245,18 @@ var (
 	Plugin         *node.Plugin
 	powEnabled     bool
 	powWorkerCount int
+	snapshotReads  bool
 	features       = []string{}
 	plugins        = []string{}
 
+	// verifies the imported peering bundles.
+	peeringBundleVerifier *p2p.PeeringBundleVerifier
+
+	// the maximum time a long-polling request waits.
+	longPollingMaxTimeout time.Duration
+	// limits the waiting long-polling requests per client.
+	longPollingRequests *longPollingLimiter
+
 	// ErrNodeNotSync is returned when the node was not synced.
 	ErrNodeNotSync = errors.New("node not synced")
 
@@ -130,25 +271,32 @@ type dependencies struct {
 	PeeringManager                        *p2p.Manager
 	GossipService                         *gossip.Service
 	UTXOManager                           *utxo.Manager
+	MilestoneManager                      *milestonemanager.MilestoneManager
 	PoWHandler                            *pow.Handler
 	MessageProcessor                      *gossip.MessageProcessor
 	SnapshotManager                       *snapshot.SnapshotManager
 	AppInfo                               *app.AppInfo
 	NodeConfig                            *configuration.Configuration `name:"nodeConfig"`
 	PeeringConfigManager                  *p2p.ConfigManager
-	NetworkID                             uint64 `name:"networkId"`
-	NetworkIDName                         string `name:"networkIdName"`
+	IPFilter                              *p2p.IPFilter
+	CircuitBreaker                        *p2p.CircuitBreaker
+	IdentityRotator                       *p2p.IdentityRotator
+	NodePrivateKey                        crypto.PrivKey `name:"nodePrivateKey"`
+	NetworkID                             uint64         `name:"networkId"`
+	NetworkIDName                         string         `name:"networkIdName"`
 	DeserializationParameters             *iotago.DeSerializationParameters
-	MaxDeltaMsgYoungestConeRootIndexToCMI int                    `name:"maxDeltaMsgYoungestConeRootIndexToCMI"`
-	MaxDeltaMsgOldestConeRootIndexToCMI   int                    `name:"maxDeltaMsgOldestConeRootIndexToCMI"`
-	BelowMaxDepth                         int                    `name:"belowMaxDepth"`
-	MinPoWScore                           float64                `name:"minPoWScore"`
+	MaxDeltaMsgYoungestConeRootIndexToCMI int `name:"maxDeltaMsgYoungestConeRootIndexToCMI"`
+	MaxDeltaMsgOldestConeRootIndexToCMI   int `name:"maxDeltaMsgOldestConeRootIndexToCMI"`
+	BelowMaxDepth                         int `name:"belowMaxDepth"`
+	PoWScoring                            *pow.Scoring
 	Bech32HRP                             iotago.NetworkPrefix   `name:"bech32HRP"`
 	RestAPILimitsMaxResults               int                    `name:"restAPILimitsMaxResults"`
+	RestAPILimitsMaxStreamedResults       int                    `name:"restAPILimitsMaxStreamedResults"`
 	SnapshotsFullPath                     string                 `name:"snapshotsFullPath"`
 	SnapshotsDeltaPath                    string                 `name:"snapshotsDeltaPath"`
 	TipSelector                           *tipselect.TipSelector `optional:"true"`
 	Echo                                  *echo.Echo             `optional:"true"`
+	Standby                               *standby.Standby       `optional:"true"`
 }
 
 func configure() {
@@ -157,10 +305,28 @@ func configure() {
 		Plugin.LogPanic("RestAPI plugin needs to be enabled to use the RestAPIV2 plugin")
 	}
 
-	routeGroup := deps.Echo.Group("/api/v2")
+	routeGroup := deps.Echo.Group("/api/v2", snapshotReadsMiddleware)
 
 	powEnabled = deps.NodeConfig.Bool(restapi.CfgRestAPIPoWEnabled)
 	powWorkerCount = deps.NodeConfig.Int(restapi.CfgRestAPIPoWWorkerCount)
+	snapshotReads = deps.NodeConfig.Bool(restapi.CfgRestAPISnapshotReads)
+	longPollingMaxTimeout = deps.NodeConfig.Duration(restapi.CfgRestAPILongPollingMaxTimeout)
+	longPollingRequests = newLongPollingLimiter(deps.NodeConfig.Int(restapi.CfgRestAPILongPollingMaxRequestsPerClient))
+	configureWebsocket(deps.NodeConfig.Int(restapi.CfgRestAPIWebsocketMaxSubscriptionsPerClient))
+
+	ownID, err := peer.IDFromPrivateKey(deps.NodePrivateKey)
+	if err != nil {
+		Plugin.LogPanicf("unable to derive peer ID from node private key: %s", err)
+	}
+	peeringBundleTrustedIssuers := []peer.ID{ownID}
+	for _, trustedIssuer := range deps.NodeConfig.Strings(restapi.CfgRestAPIPeeringBundleTrustedIssuers) {
+		issuerID, err := peer.Decode(trustedIssuer)
+		if err != nil {
+			Plugin.LogPanicf("invalid peer ID in %s: %s, error: %s", restapi.CfgRestAPIPeeringBundleTrustedIssuers, trustedIssuer, err)
+		}
+		peeringBundleTrustedIssuers = append(peeringBundleTrustedIssuers, issuerID)
+	}
+	peeringBundleVerifier = p2p.NewPeeringBundleVerifier(deps.NodeConfig.Duration(restapi.CfgRestAPIPeeringBundleMaxAge), peeringBundleTrustedIssuers...)
 
 	// Check for features
 	if powEnabled {
@@ -212,6 +378,10 @@ func configure() {
 	})
 
 	routeGroup.GET(RouteMessageChildren, func(c echo.Context) error {
+		if restapipkg.IsStreamRequested(c) {
+			return streamChildrenIDsByID(c)
+		}
+
 		resp, err := childrenIDsByID(c)
 		if err != nil {
 			return err
@@ -220,6 +390,33 @@ func configure() {
 		return restapipkg.JSONResponse(c, http.StatusOK, resp)
 	})
 
+	routeGroup.GET(RouteMessageCone, func(c echo.Context) error {
+		resp, err := messageCone(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteMessageProof, func(c echo.Context) error {
+		resp, err := messageProof(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RouteMessagesProofVerify, func(c echo.Context) error {
+		resp, err := verifyMessageProof(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
 	routeGroup.POST(RouteMessages, func(c echo.Context) error {
 		resp, err := sendMessage(c)
 		if err != nil {
@@ -229,6 +426,15 @@ func configure() {
 		return restapipkg.JSONResponse(c, http.StatusCreated, resp)
 	})
 
+	routeGroup.POST(RouteMessagesValidate, func(c echo.Context) error {
+		resp, err := validateMessage(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
 	routeGroup.GET(RouteTransactionsIncludedMessage, func(c echo.Context) error {
 		resp, err := messageByTransactionID(c)
 		if err != nil {
@@ -265,6 +471,15 @@ func configure() {
 		return restapipkg.JSONResponse(c, http.StatusOK, resp)
 	})
 
+	routeGroup.GET(RouteAddressBalance, func(c echo.Context) error {
+		resp, err := addressBalance(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
 	routeGroup.GET(RouteTreasury, func(c echo.Context) error {
 		resp, err := treasury(c)
 		if err != nil {
@@ -275,6 +490,10 @@ func configure() {
 	})
 
 	routeGroup.GET(RouteReceipts, func(c echo.Context) error {
+		if restapipkg.IsStreamRequested(c) {
+			return streamReceipts(c)
+		}
+
 		resp, err := receipts(c)
 		if err != nil {
 			return err
@@ -284,6 +503,10 @@ func configure() {
 	})
 
 	routeGroup.GET(RouteReceiptsMigratedAtIndex, func(c echo.Context) error {
+		if restapipkg.IsStreamRequested(c) {
+			return streamReceiptsByMigratedAtIndex(c)
+		}
+
 		resp, err := receiptsByMigratedAtIndex(c)
 		if err != nil {
 			return err
@@ -309,6 +532,15 @@ func configure() {
 		return c.NoContent(http.StatusNoContent)
 	})
 
+	routeGroup.POST(RoutePeerReconnect, func(c echo.Context) error {
+		resp, err := reconnectPeer(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
 	routeGroup.GET(RoutePeers, func(c echo.Context) error {
 		resp, err := listPeers(c)
 		if err != nil {
@@ -327,12 +559,123 @@ func configure() {
 		return restapipkg.JSONResponse(c, http.StatusOK, resp)
 	})
 
+	routeGroup.GET(RoutePeersMetrics, func(c echo.Context) error {
+		resp, err := peersMetrics(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RoutePeersBundle, func(c echo.Context) error {
+		resp, err := exportPeeringBundle(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RoutePeersBundle, func(c echo.Context) error {
+		resp, err := importPeeringBundle(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RoutePeersIPFilter, func(c echo.Context) error {
+		resp, err := getIPFilter(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RoutePeersIPFilter, func(c echo.Context) error {
+		resp, err := setIPFilter(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RoutePeersConfigDiff, func(c echo.Context) error {
+		resp, err := peeringConfigDiff(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RoutePeersBans, func(c echo.Context) error {
+		resp, err := listPeerBans(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.DELETE(RoutePeersBans, func(c echo.Context) error {
+		resp, err := clearPeerBans(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.DELETE(RoutePeerBan, func(c echo.Context) error {
+		if err := removePeerBan(c); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	routeGroup.GET(RouteControlDatabasePrune, func(c echo.Context) error {
+		resp, err := pruneDatabaseStatus(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
 	routeGroup.POST(RouteControlDatabasePrune, func(c echo.Context) error {
 		resp, err := pruneDatabase(c)
 		if err != nil {
 			return err
 		}
 
+		return restapipkg.JSONResponse(c, http.StatusAccepted, resp)
+	})
+
+	routeGroup.GET(RouteControlDatabasePruneThrottle, func(c echo.Context) error {
+		return restapipkg.JSONResponse(c, http.StatusOK, pruningThrottle(c))
+	})
+
+	routeGroup.POST(RouteControlDatabasePruneThrottle, func(c echo.Context) error {
+		resp, err := setPruningThrottle(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteControlSnapshotsCreate, func(c echo.Context) error {
+		resp, err := createSnapshotsStatus(c)
+		if err != nil {
+			return err
+		}
+
 		return restapipkg.JSONResponse(c, http.StatusOK, resp)
 	})
 
@@ -342,10 +685,109 @@ func configure() {
 			return err
 		}
 
+		return restapipkg.JSONResponse(c, http.StatusAccepted, resp)
+	})
+
+	routeGroup.GET(RouteControlSnapshotsSchedule, func(c echo.Context) error {
+		resp, err := snapshotsSchedule(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RouteControlSnapshotsSchedule, func(c echo.Context) error {
+		resp, err := setSnapshotsSchedule(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteControlIdentityRotation, func(c echo.Context) error {
+		resp, err := identityRotation(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RouteControlIdentityRotation, func(c echo.Context) error {
+		resp, err := stageIdentityRotation(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.DELETE(RouteControlIdentityRotation, func(c echo.Context) error {
+		if err := cancelIdentityRotation(c); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	routeGroup.POST(RouteControlMessagePin, func(c echo.Context) error {
+		resp, err := pinMessage(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.DELETE(RouteControlMessagePin, func(c echo.Context) error {
+		if err := unpinMessage(c); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	routeGroup.GET(RouteControlStandby, func(c echo.Context) error {
+		resp, err := standbyStatus(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.POST(RouteControlStandbyPromote, func(c echo.Context) error {
+		resp, err := promoteStandby(c)
+		if err != nil {
+			return err
+		}
+
+		return restapipkg.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	routeGroup.GET(RouteWebsocket, websocketRoute)
+
+	deps.Echo.GET(RouteRoutesSpec, func(c echo.Context) error {
+		resp, err := routesSpec(c)
+		if err != nil {
+			return err
+		}
+
 		return restapipkg.JSONResponse(c, http.StatusOK, resp)
 	})
 }
 
+func run() {
+	if err := Plugin.Daemon().BackgroundWorker("RestAPIV2[WSSend]", func(ctx context.Context) {
+		runWebsocket(ctx)
+		Plugin.LogInfo("Stopping RestAPIV2[WSSend] ... done")
+	}, shutdown.PriorityRestAPI); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+}
+
 // AddFeature adds a feature to the RouteInfo endpoint.
 func AddFeature(feature string) {
 	features = append(features, feature)
@@ -354,5 +796,27 @@ func AddFeature(feature string) {
 // AddPlugin adds a plugin route to the RouteInfo endpoint and returns the route for this plugin.
 func AddPlugin(pluginRoute string) *echo.Group {
 	plugins = append(plugins, pluginRoute)
-	return deps.Echo.Group(fmt.Sprintf("/api/plugins/%s", pluginRoute))
+	return deps.Echo.Group(fmt.Sprintf("/api/plugins/%s", pluginRoute), snapshotReadsMiddleware)
+}
+
+// confirmationReadLocker acquires the confirmation read lock of the storage.
+type confirmationReadLocker struct{}
+
+func (confirmationReadLocker) Lock() {
+	deps.Storage.ReadLockConfirmation()
+}
+
+func (confirmationReadLocker) Unlock() {
+	deps.Storage.ReadUnlockConfirmation()
+}
+
+// snapshotReadsMiddleware holds the confirmation read lock while GET requests are handled,
+// so the response reflects the state either before or after a milestone confirmation, but never a mix of both.
+// The lock is released before the response is written to the client.
+func snapshotReadsMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return restapipkg.SnapshotReadsMiddleware(confirmationReadLocker{}, func(c echo.Context) bool {
+		// long-polling requests must not block milestone confirmations while they wait,
+		// they acquire the lock themselves after waiting.
+		return !snapshotReads || c.Path() == "/api/v2"+RouteWebsocket || len(c.QueryParam(QueryParameterWaitFor)) > 0
+	})(next)
 }
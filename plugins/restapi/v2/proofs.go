@@ -0,0 +1,274 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/restapi"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// referencePath returns the messages on the shortest path from a parent of the milestone to a child of the message.
+// Only messages referenced by the milestone are walked, because the message is part of the cone of the milestone.
+func referencePath(milestoneMessage *storage.Message, msIndex milestone.Index, messageID hornet.MessageID) ([]*iotago.Message, error) {
+
+	// the child of every walked message on the path to the milestone
+	children := make(map[string]hornet.MessageID)
+	layer := hornet.MessageIDs{}
+
+	for _, parent := range milestoneMessage.Parents() {
+		if bytes.Equal(parent, messageID) {
+			// the message is a parent of the milestone
+			return []*iotago.Message{}, nil
+		}
+		if _, exists := children[parent.ToMapKey()]; exists {
+			continue
+		}
+		children[parent.ToMapKey()] = nil
+		layer = append(layer, parent)
+	}
+
+	for len(layer) > 0 {
+		var nextLayer hornet.MessageIDs
+		for _, layerMessageID := range layer {
+			cachedMsgMeta := deps.Storage.CachedMessageMetadataOrNil(layerMessageID) // meta +1
+			if cachedMsgMeta == nil {
+				continue
+			}
+
+			referenced, referencedIndex := cachedMsgMeta.Metadata().ReferencedWithIndex()
+			parents := cachedMsgMeta.Metadata().Parents()
+			cachedMsgMeta.Release(true) // meta -1
+
+			if !referenced || referencedIndex != msIndex {
+				continue
+			}
+
+			for _, parent := range parents {
+				if bytes.Equal(parent, messageID) {
+					return referencePathMessages(children, layerMessageID)
+				}
+				if _, exists := children[parent.ToMapKey()]; exists {
+					continue
+				}
+				children[parent.ToMapKey()] = layerMessageID
+				nextLayer = append(nextLayer, parent)
+			}
+		}
+		layer = nextLayer
+	}
+
+	return nil, errors.WithMessagef(echo.ErrNotFound, "message %s not found in the cone of milestone %d", messageID.ToHex(), msIndex)
+}
+
+// referencePathMessages loads the messages from a parent of the milestone to the given last message of the path.
+func referencePathMessages(children map[string]hornet.MessageID, lastMessageID hornet.MessageID) ([]*iotago.Message, error) {
+
+	var path []*iotago.Message
+	for messageID := lastMessageID; messageID != nil; messageID = children[messageID.ToMapKey()] {
+		cachedMsg := deps.Storage.CachedMessageOrNil(messageID) // message +1
+		if cachedMsg == nil {
+			return nil, errors.WithMessagef(echo.ErrNotFound, "message not found: %s", messageID.ToHex())
+		}
+		path = append([]*iotago.Message{cachedMsg.Message().Message()}, path...)
+		cachedMsg.Release(true) // message -1
+	}
+
+	return path, nil
+}
+
+func messageProof(c echo.Context) (*messageProofResponse, error) {
+	messageID, err := restapi.ParseMessageIDParam(c)
+	if err != nil {
+		return nil, err
+	}
+
+	cachedMsgMeta := deps.Storage.CachedMessageMetadataOrNil(messageID) // meta +1
+	if cachedMsgMeta == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "message not found: %s", messageID.ToHex())
+	}
+	metadata := cachedMsgMeta.Metadata()
+	referenced, msIndex := metadata.ReferencedWithIndex()
+	inclusionState, conflictReason := ledgerInclusionState(metadata)
+	cachedMsgMeta.Release(true) // meta -1
+
+	if !referenced {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "message not referenced by a milestone yet: %s", messageID.ToHex())
+	}
+
+	cachedMilestone := deps.Storage.CachedMilestoneOrNil(msIndex) // milestone +1
+	if cachedMilestone == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "milestone not found: %d", msIndex)
+	}
+	milestoneMessageID := cachedMilestone.Milestone().MessageID
+	cachedMilestone.Release(true) // milestone -1
+
+	cachedMilestoneMsg := deps.Storage.CachedMessageOrNil(milestoneMessageID) // message +1
+	if cachedMilestoneMsg == nil {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "milestone message not found: %s", milestoneMessageID.ToHex())
+	}
+	defer cachedMilestoneMsg.Release(true) // message -1
+
+	milestoneMessage := cachedMilestoneMsg.Message()
+
+	msID, err := milestoneMessage.Milestone().ID()
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "computing milestone ID failed, error: %s", err)
+	}
+
+	proof := &messageProofResponse{
+		MessageID:            messageID.ToHex(),
+		MilestoneIndex:       msIndex,
+		MilestoneID:          hex.EncodeToString(msID[:]),
+		Milestone:            milestoneMessage.Message(),
+		LedgerInclusionState: inclusionState,
+		ConflictReason:       conflictReason,
+	}
+
+	if inclusionState != ledgerInclusionStateIncluded {
+		// messages that didn't mutate the ledger are not part of the inclusion Merkle proof,
+		// so their past cone membership is proven by the messages that reference them.
+		path, err := referencePath(milestoneMessage, msIndex, messageID)
+		if err != nil {
+			return nil, err
+		}
+		proof.ReferencePath = path
+
+		return proof, nil
+	}
+
+	includedMessages, err := whiteflag.IncludedMessagesOfMilestone(Plugin.Daemon().ContextStopped(), deps.Storage, msIndex, milestoneMessage.Parents())
+	if err != nil {
+		if errors.Is(err, common.ErrOperationAborted) {
+			return nil, errors.WithMessage(echo.ErrServiceUnavailable, err.Error())
+		}
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "restoring the included messages of milestone %d failed, error: %s", msIndex, err)
+	}
+
+	index, auditPath, err := whiteflag.InclusionAuditPath(includedMessages, messageID)
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "computing the audit path failed, error: %s", err)
+	}
+
+	count := len(includedMessages)
+	proof.InclusionIndex = &index
+	proof.InclusionCount = &count
+	proof.AuditPath = make([]string, len(auditPath))
+	for i, hash := range auditPath {
+		proof.AuditPath[i] = hex.EncodeToString(hash)
+	}
+
+	return proof, nil
+}
+
+func containsMessageID(messageIDs hornet.MessageIDs, messageID hornet.MessageID) bool {
+	for _, id := range messageIDs {
+		if bytes.Equal(id, messageID) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyProof checks the proof only against the milestone public keys of the node, so it does not depend on the tangle of the node.
+func verifyProof(proof *messageProofResponse) error {
+
+	messageID, err := hornet.MessageIDFromHex(proof.MessageID)
+	if err != nil {
+		return fmt.Errorf("invalid message ID: %w", err)
+	}
+
+	if proof.Milestone == nil {
+		return errors.New("milestone missing")
+	}
+
+	milestoneMessage, err := storage.NewMessage(proof.Milestone, serializer.DeSeriModePerformValidation, deps.DeserializationParameters)
+	if err != nil {
+		return fmt.Errorf("invalid milestone message: %w", err)
+	}
+
+	ms := deps.MilestoneManager.VerifyMilestone(milestoneMessage)
+	if ms == nil {
+		return errors.New("milestone payload missing or signatures invalid")
+	}
+
+	if milestone.Index(ms.Index) != proof.MilestoneIndex {
+		return fmt.Errorf("milestone index mismatch: %d != %d", ms.Index, proof.MilestoneIndex)
+	}
+
+	msID, err := ms.ID()
+	if err != nil {
+		return fmt.Errorf("computing milestone ID failed: %w", err)
+	}
+	if hex.EncodeToString(msID[:]) != proof.MilestoneID {
+		return fmt.Errorf("milestone ID mismatch: %s != %s", hex.EncodeToString(msID[:]), proof.MilestoneID)
+	}
+
+	if proof.LedgerInclusionState == ledgerInclusionStateIncluded {
+		if proof.InclusionIndex == nil || proof.InclusionCount == nil {
+			return errors.New("inclusion index or count missing")
+		}
+
+		auditPath := make([][]byte, len(proof.AuditPath))
+		for i, hash := range proof.AuditPath {
+			if auditPath[i], err = hex.DecodeString(hash); err != nil {
+				return fmt.Errorf("invalid audit path: %w", err)
+			}
+		}
+
+		valid, err := whiteflag.VerifyInclusionAuditPath(messageID, *proof.InclusionIndex, *proof.InclusionCount, auditPath, ms.InclusionMerkleProof)
+		if err != nil {
+			return fmt.Errorf("invalid audit path: %w", err)
+		}
+		if !valid {
+			return errors.New("audit path doesn't match the inclusion Merkle proof of the milestone")
+		}
+		return nil
+	}
+
+	// every message of the reference path must be a parent of its predecessor, starting at the milestone
+	parents := milestoneMessage.Parents()
+	for _, msg := range proof.ReferencePath {
+		message, err := storage.NewMessage(msg, serializer.DeSeriModePerformValidation, deps.DeserializationParameters)
+		if err != nil {
+			return fmt.Errorf("invalid message in reference path: %w", err)
+		}
+		if !containsMessageID(parents, message.MessageID()) {
+			return fmt.Errorf("message %s in reference path is not a parent of its predecessor", message.MessageID().ToHex())
+		}
+		parents = message.Parents()
+	}
+
+	if !containsMessageID(parents, messageID) {
+		return errors.New("reference path doesn't end at a child of the message")
+	}
+
+	return nil
+}
+
+func verifyMessageProof(c echo.Context) (*messageProofVerificationResponse, error) {
+
+	proof := &messageProofResponse{}
+	if err := c.Bind(proof); err != nil {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "invalid proof, error: %s", err)
+	}
+
+	if err := verifyProof(proof); err != nil {
+		return &messageProofVerificationResponse{
+			Valid: false,
+			Error: err.Error(),
+		}, nil
+	}
+
+	return &messageProofVerificationResponse{Valid: true}, nil
+}
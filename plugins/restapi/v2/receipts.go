@@ -1,6 +1,8 @@
 package v2
 
 import (
+	"net/http"
+
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 
@@ -8,16 +10,28 @@ import (
 	"github.com/gohornet/hornet/pkg/restapi"
 )
 
-func receipts(_ echo.Context) (*receiptsResponse, error) {
+func receipts(c echo.Context) (*receiptsResponse, error) {
+	page, err := restapi.ParsePage(c, deps.RestAPILimitsMaxResults)
+	if err != nil {
+		return nil, err
+	}
+
 	receipts := make([]*utxo.ReceiptTuple, 0)
 	if err := deps.UTXOManager.ForEachReceiptTuple(func(rt *utxo.ReceiptTuple) bool {
-		receipts = append(receipts, rt)
-		return true
+		inPage, next := page.Next()
+		if inPage {
+			receipts = append(receipts, rt)
+		}
+		return next
 	}, utxo.ReadLockLedger(false)); err != nil {
 		return nil, errors.WithMessagef(echo.ErrInternalServerError, "unable to retrieve receipts: %s", err)
 	}
 
-	return &receiptsResponse{Receipts: receipts}, nil
+	return &receiptsResponse{
+		PageSize: page.PageSize(),
+		Cursor:   page.NextCursor(),
+		Receipts: receipts,
+	}, nil
 }
 
 func receiptsByMigratedAtIndex(c echo.Context) (*receiptsResponse, error) {
@@ -26,13 +40,54 @@ func receiptsByMigratedAtIndex(c echo.Context) (*receiptsResponse, error) {
 		return nil, err
 	}
 
+	page, err := restapi.ParsePage(c, deps.RestAPILimitsMaxResults)
+	if err != nil {
+		return nil, err
+	}
+
 	receipts := make([]*utxo.ReceiptTuple, 0)
 	if err := deps.UTXOManager.ForEachReceiptTupleMigratedAt(migratedAt, func(rt *utxo.ReceiptTuple) bool {
-		receipts = append(receipts, rt)
-		return true
+		inPage, next := page.Next()
+		if inPage {
+			receipts = append(receipts, rt)
+		}
+		return next
 	}, utxo.ReadLockLedger(false)); err != nil {
 		return nil, errors.WithMessagef(echo.ErrInternalServerError, "unable to retrieve receipts for migrated at index %d: %s", migratedAt, err)
 	}
 
-	return &receiptsResponse{Receipts: receipts}, nil
+	return &receiptsResponse{
+		PageSize: page.PageSize(),
+		Cursor:   page.NextCursor(),
+		Receipts: receipts,
+	}, nil
+}
+
+func streamReceipts(c echo.Context) error {
+	stream := restapi.NewNDJSONStream(c, http.StatusOK)
+
+	if err := deps.UTXOManager.ForEachReceiptTuple(func(rt *utxo.ReceiptTuple) bool {
+		return stream.Write(rt) == nil
+	}, utxo.ReadLockLedger(false)); err != nil {
+		return stream.Abort(errors.Errorf("unable to retrieve receipts: %s", err))
+	}
+
+	return stream.Close()
+}
+
+func streamReceiptsByMigratedAtIndex(c echo.Context) error {
+	migratedAt, err := restapi.ParseMilestoneIndexParam(c, restapi.ParameterMilestoneIndex)
+	if err != nil {
+		return err
+	}
+
+	stream := restapi.NewNDJSONStream(c, http.StatusOK)
+
+	if err := deps.UTXOManager.ForEachReceiptTupleMigratedAt(migratedAt, func(rt *utxo.ReceiptTuple) bool {
+		return stream.Write(rt) == nil
+	}, utxo.ReadLockLedger(false)); err != nil {
+		return stream.Abort(errors.Errorf("unable to retrieve receipts for migrated at index %d: %s", migratedAt, err))
+	}
+
+	return stream.Close()
 }
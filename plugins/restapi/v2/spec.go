@@ -0,0 +1,146 @@
+package v2
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gohornet/hornet/plugins/restapi"
+)
+
+const (
+	// openAPIVersion is the version of the OpenAPI specification the description follows.
+	openAPIVersion = "3.0.3"
+	// specTagCore is the tag of the routes of the core API.
+	specTagCore = "core"
+	// specSecurityScheme is the name of the JWT security scheme.
+	specSecurityScheme = "jwt"
+)
+
+// openAPISpec is the OpenAPI description of the registered routes.
+type openAPISpec struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Tags       []*openAPITag                           `json:"tags"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPITag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type openAPIOperation struct {
+	Tags       []string                    `json:"tags"`
+	Parameters []*openAPIParameter         `json:"parameters,omitempty"`
+	Responses  map[string]*openAPIResponse `json:"responses"`
+	Security   []map[string][]string       `json:"security"`
+}
+
+type openAPIParameter struct {
+	Name     string            `json:"name"`
+	In       string            `json:"in"`
+	Required bool              `json:"required"`
+	Schema   map[string]string `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Description string `json:"description"`
+}
+
+type openAPIComponents struct {
+	SecuritySchemes map[string]*openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat"`
+}
+
+// routeTag returns the tag of the route, which is the plugin route for routes of a plugin.
+func routeTag(path string) string {
+	for _, pluginRoute := range plugins {
+		if strings.HasPrefix(path, "/api/plugins/"+pluginRoute+"/") {
+			return pluginRoute
+		}
+	}
+	return specTagCore
+}
+
+// routesSpec describes all routes registered at the echo instance of the REST API.
+func routesSpec(_ echo.Context) (*openAPISpec, error) {
+
+	// groups with middlewares register catch-all routes, which are not part of the API.
+	notFoundHandlerName := runtime.FuncForPC(reflect.ValueOf(echo.NotFoundHandler).Pointer()).Name()
+
+	spec := &openAPISpec{
+		OpenAPI: openAPIVersion,
+		Info: openAPIInfo{
+			Title:   deps.AppInfo.Name + " REST API",
+			Version: deps.AppInfo.Version,
+		},
+		Tags:  []*openAPITag{{Name: specTagCore, Description: "The core API of the node"}},
+		Paths: make(map[string]map[string]*openAPIOperation),
+		Components: openAPIComponents{
+			SecuritySchemes: map[string]*openAPISecurityScheme{
+				specSecurityScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	for _, pluginRoute := range plugins {
+		spec.Tags = append(spec.Tags, &openAPITag{Name: pluginRoute, Description: "The routes of the " + pluginRoute + " plugin"})
+	}
+
+	for _, route := range deps.Echo.Routes() {
+		if route.Name == notFoundHandlerName || strings.Contains(route.Path, "*") {
+			continue
+		}
+
+		operation := &openAPIOperation{
+			Tags:      []string{routeTag(route.Path)},
+			Responses: map[string]*openAPIResponse{"default": {Description: http.StatusText(http.StatusOK)}},
+			Security:  []map[string][]string{{specSecurityScheme: {}}},
+		}
+		if restapi.IsPublicRoute(route.Path) {
+			// an empty requirement marks the route as callable without authorization.
+			operation.Security = []map[string][]string{{}}
+		}
+
+		segments := strings.Split(route.Path, "/")
+		for i, segment := range segments {
+			if !strings.HasPrefix(segment, ":") {
+				continue
+			}
+			name := strings.TrimPrefix(segment, ":")
+			segments[i] = "{" + name + "}"
+			operation.Parameters = append(operation.Parameters, &openAPIParameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   map[string]string{"type": "string"},
+			})
+		}
+		path := strings.Join(segments, "/")
+
+		if _, exists := spec.Paths[path]; !exists {
+			spec.Paths[path] = make(map[string]*openAPIOperation)
+		}
+		spec.Paths[path][strings.ToLower(route.Method)] = operation
+	}
+
+	sort.Slice(spec.Tags[1:], func(i, j int) bool { return spec.Tags[i+1].Name < spec.Tags[j+1].Name })
+
+	return spec, nil
+}
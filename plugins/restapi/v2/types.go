@@ -6,6 +6,7 @@ import (
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/p2p"
 	"github.com/gohornet/hornet/pkg/protocol/gossip"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
@@ -17,6 +18,8 @@ type protocolParameters struct {
 	Bech32HRP string `json:"bech32HRP"`
 	// The minimum pow score of the network.
 	MinPoWScore float64 `json:"minPoWScore"`
+	// The scorer used to compute the pow score.
+	PoWScorer string `json:"powScorer"`
 	// The rent structure according to TIP-19
 	RentStructure *iotago.RentStructure `json:"rentStructure"`
 }
@@ -69,6 +72,11 @@ type tipsResponse struct {
 
 // receiptsResponse defines the response of a receipts REST API call.
 type receiptsResponse struct {
+	// The maximum count of results that are returned by the node.
+	PageSize uint32 `json:"pageSize"`
+	// The cursor to use for getting the next results.
+	Cursor *string `json:"cursor,omitempty"`
+	// The receipts with the milestone index they were included in.
 	Receipts []*utxo.ReceiptTuple `json:"receipts"`
 }
 
@@ -92,6 +100,8 @@ type messageMetadataResponse struct {
 	ShouldPromote *bool `json:"shouldPromote,omitempty"`
 	// Whether the message should be reattached.
 	ShouldReattach *bool `json:"shouldReattach,omitempty"`
+	// The hex encoded values of the requested message annotations, grouped by namespace.
+	Annotations map[string]map[string]string `json:"annotations,omitempty"`
 }
 
 // messageCreatedResponse defines the response of a POST messages REST API call.
@@ -100,6 +110,76 @@ type messageCreatedResponse struct {
 	MessageID string `json:"messageId"`
 }
 
+const (
+	messageValidationCheckSyntactic = "syntactic"
+	messageValidationCheckNetwork   = "network"
+	messageValidationCheckPoW       = "pow"
+	messageValidationCheckParents   = "parents"
+	messageValidationCheckLedger    = "ledger"
+)
+
+const (
+	// the message was referenced by a milestone and its transaction mutated the ledger.
+	ledgerInclusionStateIncluded = "included"
+	// the message was referenced by a milestone, but its transaction was conflicting.
+	ledgerInclusionStateConflicting = "conflicting"
+	// the message was referenced by a milestone and contains no transaction.
+	ledgerInclusionStateNoTransaction = "noTransaction"
+)
+
+// messageValidationResponse defines the response of a POST validate message REST API call.
+type messageValidationResponse struct {
+	// Whether the message would be accepted by the node.
+	Valid bool `json:"valid"`
+	// The hex encoded message ID of the message, if it is valid and the PoW is not done by the node.
+	MessageID string `json:"messageId,omitempty"`
+	// The check the message failed ("syntactic", "network", "pow", "parents" or "ledger").
+	FailedCheck string `json:"failedCheck,omitempty"`
+	// The cause why the message is invalid.
+	Error string `json:"error,omitempty"`
+	// The reason why the transaction of the message would be conflicting.
+	ConflictReason *storage.Conflict `json:"conflictReason,omitempty"`
+}
+
+// messageProofResponse defines the response of a GET message proof REST API call.
+// It is also the request of a POST verify message proof REST API call.
+type messageProofResponse struct {
+	// The hex encoded message ID of the message.
+	MessageID string `json:"messageId"`
+	// The index of the milestone that referenced the message.
+	MilestoneIndex milestone.Index `json:"milestoneIndex"`
+	// The hex encoded ID of the milestone payload.
+	MilestoneID string `json:"milestoneId"`
+	// The message containing the milestone.
+	Milestone *iotago.Message `json:"milestone"`
+	// The ledger inclusion state of the message ("included", "conflicting" or "noTransaction").
+	LedgerInclusionState string `json:"ledgerInclusionState"`
+	// The reason why the transaction of the message was conflicting.
+	ConflictReason *storage.Conflict `json:"conflictReason,omitempty"`
+	// The position of the message within the messages that were included in the ledger by the milestone, in white-flag order.
+	InclusionIndex *int `json:"inclusionIndex,omitempty"`
+	// The count of the messages that were included in the ledger by the milestone.
+	InclusionCount *int `json:"inclusionCount,omitempty"`
+	// The hex encoded Merkle audit path of the message to the inclusion Merkle proof of the milestone, if the message was included.
+	AuditPath []string `json:"auditPath,omitempty"`
+	// The messages on the path from a parent of the milestone to a child of the message, if the message was not included.
+	ReferencePath []*iotago.Message `json:"referencePath,omitempty"`
+}
+
+// messageProofVerificationResponse defines the response of a POST verify message proof REST API call.
+type messageProofVerificationResponse struct {
+	// Whether the proof is valid.
+	Valid bool `json:"valid"`
+	// The cause why the proof is invalid.
+	Error string `json:"error,omitempty"`
+}
+
+// childResponse defines a line of a streamed GET children REST API call.
+type childResponse struct {
+	// The hex encoded message ID of the child.
+	MessageID string `json:"messageId"`
+}
+
 // childrenResponse defines the response of a GET children REST API call.
 type childrenResponse struct {
 	// The hex encoded message ID of the message.
@@ -108,10 +188,30 @@ type childrenResponse struct {
 	MaxResults uint32 `json:"maxResults"`
 	// The actual count of results that are returned.
 	Count uint32 `json:"count"`
+	// The maximum count of results that are returned by the node.
+	PageSize uint32 `json:"pageSize"`
+	// The cursor to use for getting the next results.
+	Cursor *string `json:"cursor,omitempty"`
 	// The hex encoded message IDs of the children of this message.
 	Children []string `json:"childrenMessageIds"`
 }
 
+// pinMessageResponse defines the response of a POST pin message REST API call.
+type pinMessageResponse struct {
+	// The hex encoded message ID of the message.
+	MessageID string `json:"messageId"`
+	// The hex encoded message IDs of all messages that were pinned (including the past cone up to the given depth).
+	PinnedMessageIDs []string `json:"pinnedMessageIds"`
+}
+
+// messageConeResponse defines the response of a GET message cone REST API call.
+type messageConeResponse struct {
+	// The hex encoded message ID of the message.
+	MessageID string `json:"messageId"`
+	// The metadata of the messages of the past cone in breadth-first order, starting with the message itself.
+	Messages []*messageMetadataResponse `json:"messages"`
+}
+
 // milestoneResponse defines the response of a GET milestones REST API call.
 type milestoneResponse struct {
 	// The index of the milestone.
@@ -158,16 +258,18 @@ type OutputResponse struct {
 	RawOutput *json.RawMessage `json:"output"`
 }
 
-// addressBalanceResponse defines the response of a GET addresses REST API call.
+// addressBalanceResponse defines the response of a GET address balance REST API call.
 type addressBalanceResponse struct {
-	// The type of the address (0=Ed25519).
+	// The type of the address (0=Ed25519, 8=Alias, 16=NFT).
 	AddressType byte `json:"addressType"`
 	// The hex encoded address.
 	Address string `json:"address"`
-	// The balance of the address.
+	// The sum of the deposits of the outputs with an address unlock condition for the address.
 	Balance uint64 `json:"balance"`
-	// Indicates if dust is allowed on this address.
-	DustAllowed bool `json:"dustAllowed"`
+	// The amount of outputs with an address unlock condition for the address.
+	OutputCount int `json:"outputCount"`
+	// The milestone index at which this balance was calculated.
+	MilestoneIndex milestone.Index `json:"milestoneIndex"`
 	// The ledger index at which this balance was queried at.
 	LedgerIndex milestone.Index `json:"ledgerIndex"`
 }
@@ -184,6 +286,77 @@ type addPeerRequest struct {
 	MultiAddress string `json:"multiAddress"`
 	// The alias of the peer.
 	Alias *string `json:"alias,omitempty"`
+	// Whether the peer should be written to the peering config (default: true).
+	Persist *bool `json:"persist,omitempty"`
+	// The transport used to connect to the peer ("tcp" or "ws", default: "tcp").
+	Transport *string `json:"transport,omitempty"`
+	// The reconnect backoff policy for the peer (default: the policy of the node).
+	Reconnect *p2p.PeerReconnectConfig `json:"reconnect,omitempty"`
+}
+
+// importPeeringBundleResponse defines the response of a POST peering bundle REST API call.
+type importPeeringBundleResponse struct {
+	// The peers of the bundle which were added to the node.
+	Peers []*PeerResponse `json:"peers"`
+	// The multi addresses of the peers of the bundle which were skipped.
+	SkippedPeers []string `json:"skippedPeers"`
+	// The subnets of the IP filter which are in effect after the import.
+	IPFilter *p2p.PeeringBundleIPFilter `json:"ipFilter"`
+}
+
+// peeringConfigDiffRequest defines the request for a POST peering config diff REST API call.
+// It has the same format as the peering config file.
+type peeringConfigDiffRequest struct {
+	// The candidate static peers.
+	Peers []*p2p.PeerConfig `json:"peers"`
+}
+
+// peerConfigChangeResponse defines a peer whose configuration would change.
+type peerConfigChangeResponse struct {
+	// The libp2p identifier of the peer.
+	ID string `json:"id"`
+	// The current configuration of the peer.
+	Old *p2p.PeerConfig `json:"old"`
+	// The candidate configuration of the peer.
+	New *p2p.PeerConfig `json:"new"`
+}
+
+// peeringConfigDiffResponse defines the response of a POST peering config diff REST API call.
+type peeringConfigDiffResponse struct {
+	// The peers which would be added.
+	Added []*p2p.PeerConfig `json:"added"`
+	// The peers whose configuration would change.
+	Modified []*peerConfigChangeResponse `json:"modified"`
+	// The peers which would be removed.
+	Removed []*p2p.PeerConfig `json:"removed"`
+	// The amount of peers which would stay the same.
+	Unchanged int `json:"unchanged"`
+}
+
+// peerBanResponse defines a peer which was banned because of protocol violations.
+type peerBanResponse struct {
+	// The libp2p identifier of the peer.
+	ID string `json:"id"`
+	// The IP addresses the peer was connected from, which are banned as well.
+	IPs []string `json:"ips"`
+	// The amount of violations that led to the ban, by kind.
+	Violations map[p2p.ProtocolViolation]int `json:"violations"`
+	// The unix timestamp at which the peer was banned.
+	BannedAt int64 `json:"bannedAt"`
+	// The unix timestamp until which the peer is banned.
+	Until int64 `json:"until"`
+}
+
+// peerBansResponse defines the response of a GET peer bans REST API call.
+type peerBansResponse struct {
+	// The active bans.
+	Bans []*peerBanResponse `json:"bans"`
+}
+
+// clearPeerBansResponse defines the response of a DELETE peer bans REST API call.
+type clearPeerBansResponse struct {
+	// The amount of bans which were removed.
+	Removed int `json:"removed"`
 }
 
 // PeerResponse defines the response of a GET peer REST API call.
@@ -202,6 +375,22 @@ type PeerResponse struct {
 	Gossip *gossip.Info `json:"gossip,omitempty"`
 }
 
+// peerMetricsResponse defines the gossip metrics of a connected peer in a GET peers metrics REST API call.
+type peerMetricsResponse struct {
+	// The libp2p identifier of the peer.
+	ID string `json:"id"`
+	// The alias of the peer.
+	Alias *string `json:"alias,omitempty"`
+	// The relation (static, autopeered) of the peer.
+	Relation string `json:"relation"`
+	// The unix timestamp at which the gossip stream to the peer was established.
+	ConnectedSince int64 `json:"connectedSince"`
+	// The time the gossip stream to the peer is established in seconds.
+	Uptime int64 `json:"uptime"`
+	// The gossip metrics of the peer.
+	Metrics gossip.MetricsSnapshot `json:"metrics"`
+}
+
 // pruneDatabaseRequest defines the request of a prune database REST API call.
 type pruneDatabaseRequest struct {
 	// The pruning target index.
@@ -212,10 +401,46 @@ type pruneDatabaseRequest struct {
 	TargetDatabaseSize *string `json:"targetDatabaseSize,omitempty"`
 }
 
-// pruneDatabaseResponse defines the response of a prune database REST API call.
+// controlOperationStatus defines the state of a pruning or snapshot creation started via a control route.
+type controlOperationStatus struct {
+	// The state of the operation ("running", "succeeded" or "failed").
+	State string `json:"state"`
+	// The unix timestamp at which the operation was started.
+	StartedAt int64 `json:"startedAt"`
+	// The unix timestamp at which the operation has ended.
+	EndedAt int64 `json:"endedAt,omitempty"`
+	// The cause why the operation failed.
+	Error string `json:"error,omitempty"`
+}
+
+// pruneDatabaseResponse defines the response of a GET and POST prune database REST API call.
 type pruneDatabaseResponse struct {
-	// The index of the snapshot.
+	controlOperationStatus
+	// The index up to which the database is pruned (unknown until the end if a target size was requested).
 	Index milestone.Index `json:"index"`
+	// The current pruning index of the database.
+	PruningIndex milestone.Index `json:"pruningIndex"`
+}
+
+// pruningThrottleRequest defines the request of a POST pruning throttle REST API call.
+// Omitted fields keep the value of the active throttle.
+type pruningThrottleRequest struct {
+	// The maximum amount of milestones that are pruned per minute (0 = unlimited).
+	MilestonesPerMinute *int `json:"milestonesPerMinute,omitempty"`
+	// The maximum amount of message data that is deleted per second (e.g. "10MB", "0" = unlimited).
+	MaxBytesPerSecond string `json:"maxBytesPerSecond,omitempty"`
+	// Whether the pruning is paused.
+	Paused *bool `json:"paused,omitempty"`
+}
+
+// pruningThrottleResponse defines the response of a GET and POST pruning throttle REST API call.
+type pruningThrottleResponse struct {
+	// The maximum amount of milestones that are pruned per minute (0 = unlimited).
+	MilestonesPerMinute int `json:"milestonesPerMinute"`
+	// The maximum amount of message data that is deleted per second (empty = unlimited).
+	MaxBytesPerSecond string `json:"maxBytesPerSecond,omitempty"`
+	// Whether the pruning is paused.
+	Paused bool `json:"paused"`
 }
 
 // createSnapshotsRequest defines the request of a create snapshots REST API call.
@@ -224,16 +449,78 @@ type createSnapshotsRequest struct {
 	FullIndex *milestone.Index `json:"fullIndex,omitempty"`
 	// The index of the delta snapshot.
 	DeltaIndex *milestone.Index `json:"deltaIndex,omitempty"`
+	// The name of the snapshot target the snapshots are streamed to instead of local files (optional).
+	Target string `json:"target,omitempty"`
+}
+
+// snapshotsScheduleRequest defines the request of a POST snapshots schedule REST API call.
+// Omitted fields keep the value of the active schedule.
+type snapshotsScheduleRequest struct {
+	// The policy that triggers the creation of a snapshot ("interval", "cron" or "ledgerGrowth").
+	Policy string `json:"policy,omitempty"`
+	// The interval in milestones of the "interval" policy.
+	Interval *milestone.Index `json:"interval,omitempty"`
+	// The cron expression in the local time of the node of the "cron" policy.
+	Cron string `json:"cron,omitempty"`
+	// The growth of the ledger database of the "ledgerGrowth" policy (e.g. "1GB").
+	LedgerGrowth string `json:"ledgerGrowth,omitempty"`
+}
+
+// snapshotsScheduleResponse defines the response of a GET and POST snapshots schedule REST API call.
+type snapshotsScheduleResponse struct {
+	// The policy that triggers the creation of a snapshot.
+	Policy string `json:"policy"`
+	// The interval in milestones of the "interval" policy.
+	Interval milestone.Index `json:"interval"`
+	// The cron expression of the "cron" policy.
+	Cron string `json:"cron,omitempty"`
+	// The growth of the ledger database of the "ledgerGrowth" policy.
+	LedgerGrowth string `json:"ledgerGrowth,omitempty"`
+	// The unix timestamp at which the next snapshot is created by the "cron" policy.
+	NextSnapshotAt int64 `json:"nextSnapshotAt,omitempty"`
+	// The growth of the ledger database since the last snapshot with the "ledgerGrowth" policy.
+	CurrentLedgerGrowth string `json:"currentLedgerGrowth,omitempty"`
+}
+
+// stageIdentityRotationRequest defines the request of a POST identity rotation REST API call.
+type stageIdentityRotationRequest struct {
+	// The time the new identity is announced to the peers before it is activated (e.g. "24h", optional).
+	GracePeriod string `json:"gracePeriod,omitempty"`
+}
+
+// identityRotationResponse defines the response of a GET and POST identity rotation REST API call.
+type identityRotationResponse struct {
+	// The current peer ID of the node.
+	OldID string `json:"oldId"`
+	// The peer ID which replaces the current one.
+	NewID string `json:"newId"`
+	// The unix timestamp at which the rotation was staged.
+	StagedAt int64 `json:"stagedAt"`
+	// The unix timestamp after which the new identity is activated on the next start of the node.
+	ActivateAfter int64 `json:"activateAfter"`
+}
+
+// standbyResponse defines the response of a GET standby and POST promote standby REST API call.
+type standbyResponse struct {
+	// Whether the node still runs in standby mode.
+	Standby bool `json:"standby"`
+	// The unix timestamp at which the node started in standby mode.
+	StandbySince int64 `json:"standbySince"`
+	// The unix timestamp at which the node got promoted.
+	PromotedAt int64 `json:"promotedAt,omitempty"`
 }
 
-// createSnapshotsResponse defines the response of a create snapshots REST API call.
+// createSnapshotsResponse defines the response of a GET and POST create snapshots REST API call.
 type createSnapshotsResponse struct {
+	controlOperationStatus
 	// The index of the full snapshot.
 	FullIndex milestone.Index `json:"fullIndex,omitempty"`
 	// The index of the delta snapshot.
 	DeltaIndex milestone.Index `json:"deltaIndex,omitempty"`
-	// The file path of the full snapshot file.
+	// The file path of the full snapshot file, or the file name in the snapshot target.
 	FullFilePath string `json:"fullFilePath,omitempty"`
-	// The file path of the delta snapshot file.
+	// The file path of the delta snapshot file, or the file name in the snapshot target.
 	DeltaFilePath string `json:"deltaFilePath,omitempty"`
+	// The name of the snapshot target the snapshots are streamed to.
+	Target string `json:"target,omitempty"`
 }
@@ -88,6 +88,51 @@ func outputByID(c echo.Context) (*OutputResponse, error) {
 	return NewSpentResponse(spent, ledgerIndex)
 }
 
+func addressBalance(c echo.Context) (*addressBalanceResponse, error) {
+
+	address, err := restapi.ParseBech32AddressParam(c, deps.Bech32HRP)
+	if err != nil {
+		return nil, err
+	}
+
+	// the balance of the current ledger state is returned by default
+	msIndex, err := deps.UTXOManager.ReadLedgerIndex()
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "reading ledger index failed, error: %s", err)
+	}
+
+	if len(c.QueryParam(QueryParameterMilestoneIndex)) > 0 {
+		msIndex, err = restapi.ParseMilestoneIndexQueryParam(c, QueryParameterMilestoneIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if snapshotInfo := deps.Storage.SnapshotInfo(); snapshotInfo != nil && msIndex < snapshotInfo.PruningIndex {
+		return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "milestone index %d is below the pruning index %d", msIndex, snapshotInfo.PruningIndex)
+	}
+
+	balance, count, ledgerIndex, err := deps.UTXOManager.AddressBalance(address, msIndex)
+	if err != nil {
+		if errors.Is(err, utxo.ErrMilestoneIndexAboveLedgerIndex) {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "milestone index %d is above the ledger index", msIndex)
+		}
+		if errors.Is(err, kvstore.ErrKeyNotFound) {
+			return nil, errors.WithMessagef(restapi.ErrInvalidParameter, "milestone diffs after milestone index %d are not available", msIndex)
+		}
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "calculating balance failed: %s, error: %s", address.Bech32(deps.Bech32HRP), err)
+	}
+
+	return &addressBalanceResponse{
+		AddressType:    byte(address.Type()),
+		Address:        address.String(),
+		Balance:        balance,
+		OutputCount:    count,
+		MilestoneIndex: msIndex,
+		LedgerIndex:    ledgerIndex,
+	}, nil
+}
+
 func treasury(_ echo.Context) (*treasuryResponse, error) {
 
 	treasuryOutput, err := deps.UTXOManager.UnspentTreasuryOutputWithoutLocking()
@@ -0,0 +1,422 @@
+package v2
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/model/utxo"
+	"github.com/gohornet/hornet/pkg/utils"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/websockethub"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// the message types sent to the websocket clients.
+	wsMsgTypeEvent        = "event"
+	wsMsgTypeSubscribed   = "subscribed"
+	wsMsgTypeUnsubscribed = "unsubscribed"
+	wsMsgTypeError        = "error"
+
+	// the commands sent by the websocket clients.
+	wsCmdSubscribe   = "subscribe"
+	wsCmdUnsubscribe = "unsubscribe"
+
+	// the topics a websocket client can subscribe to.
+	wsTopicMilestonesConfirmed = "milestones/confirmed"
+	wsTopicMessagesTaggedData  = "messages/data/{tag}"
+	wsTopicAddressesOutputs    = "addresses/{address}/outputs"
+
+	wsBroadcastQueueSize    = 20000
+	wsClientSendChannelSize = 1000
+	wsMaxCommandSize        = 1024
+	wsWriteTimeout          = 3 * time.Second
+)
+
+// wsCommand is a command sent by a websocket client.
+type wsCommand struct {
+	// The type of the command ("subscribe" or "unsubscribe").
+	Type string `json:"type"`
+	// The topic of the command.
+	Topic string `json:"topic"`
+}
+
+// wsMessage is a message sent to the websocket clients.
+type wsMessage struct {
+	// The type of the message ("event", "subscribed", "unsubscribed" or "error").
+	Type string `json:"type"`
+	// The topic the message belongs to.
+	Topic string `json:"topic,omitempty"`
+	// The payload of an event.
+	Data interface{} `json:"data,omitempty"`
+	// The reason of an error.
+	Error string `json:"error,omitempty"`
+}
+
+// wsMilestonePayload is the payload of the "milestones/confirmed" events.
+type wsMilestonePayload struct {
+	// The index of the milestone.
+	Index milestone.Index `json:"index"`
+	// The unix time of the milestone payload.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// wsTopicCounter counts the subscriptions of all clients per topic,
+// so the payload of an event is only created if somebody is interested in it.
+type wsTopicCounter struct {
+	sync.RWMutex
+	subscriptions map[string]int
+}
+
+func newWSTopicCounter() *wsTopicCounter {
+	return &wsTopicCounter{subscriptions: make(map[string]int)}
+}
+
+func (t *wsTopicCounter) add(topic string) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.subscriptions[topic]++
+}
+
+func (t *wsTopicCounter) remove(topic string) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.subscriptions[topic]--
+	if t.subscriptions[topic] <= 0 {
+		delete(t.subscriptions, topic)
+	}
+}
+
+func (t *wsTopicCounter) hasSubscribers(topic string) bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.subscriptions[topic] > 0
+}
+
+// wsClientSubscriptions are the subscriptions of a single websocket client.
+// The cancel function of a subscription is only set if it has its own goroutine.
+type wsClientSubscriptions struct {
+	sync.RWMutex
+	topics map[string]context.CancelFunc
+}
+
+func (s *wsClientSubscriptions) contains(topic string) bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	_, subscribed := s.topics[topic]
+	return subscribed
+}
+
+var (
+	wsHub *websockethub.Hub
+	// the subscriptions of all websocket clients per topic.
+	wsTopics = newWSTopicCounter()
+	// the maximum amount of subscriptions per websocket client, 0 disables the websocket route.
+	wsMaxSubscriptionsPerClient int
+)
+
+func configureWebsocket(maxSubscriptionsPerClient int) {
+	wsMaxSubscriptionsPerClient = maxSubscriptionsPerClient
+
+	upgrader := &websocket.Upgrader{
+		HandshakeTimeout: wsWriteTimeout,
+		CheckOrigin:      func(r *http.Request) bool { return true }, // allow any origin for websocket connections
+		// Disable compression due to incompatibilities with latest Safari browsers:
+		// https://github.com/tilt-dev/tilt/issues/4746
+		// https://github.com/gorilla/websocket/issues/731
+		EnableCompression: false,
+	}
+
+	wsHub = websockethub.NewHub(Plugin.Logger(), upgrader, wsBroadcastQueueSize, wsClientSendChannelSize, wsMaxCommandSize)
+}
+
+// runWebsocket publishes the events of the node to the subscribed websocket clients.
+func runWebsocket(ctx context.Context) {
+
+	onConfirmedMilestoneChanged := events.NewClosure(func(cachedMs *storage.CachedMilestone) {
+		defer cachedMs.Release(true) // milestone -1
+
+		if !wsTopics.hasSubscribers(wsTopicMilestonesConfirmed) {
+			return
+		}
+
+		ms := cachedMs.Milestone()
+		wsHub.BroadcastMsg(&wsMessage{
+			Type:  wsMsgTypeEvent,
+			Topic: wsTopicMilestonesConfirmed,
+			Data:  &wsMilestonePayload{Index: ms.Index, Timestamp: ms.Timestamp.Unix()},
+		})
+	})
+
+	onReceivedNewMessage := events.NewClosure(func(cachedMsg *storage.CachedMessage, _ milestone.Index, _ milestone.Index) {
+		defer cachedMsg.Release(true) // message -1
+
+		taggedData := cachedMsg.Message().TaggedData()
+		if taggedData == nil || len(taggedData.Tag) == 0 {
+			return
+		}
+
+		topic := strings.ReplaceAll(wsTopicMessagesTaggedData, "{tag}", hex.EncodeToString(taggedData.Tag))
+		if !wsTopics.hasSubscribers(topic) {
+			return
+		}
+
+		wsHub.BroadcastMsg(&wsMessage{Type: wsMsgTypeEvent, Topic: topic, Data: cachedMsg.Message().Message()})
+	})
+
+	onNewUTXOOutput := events.NewClosure(func(index milestone.Index, output *utxo.Output) {
+		var topics []string
+		for _, address := range outputAddresses(output) {
+			topic := strings.ReplaceAll(wsTopicAddressesOutputs, "{address}", address.Bech32(deps.Bech32HRP))
+			if wsTopics.hasSubscribers(topic) {
+				topics = append(topics, topic)
+			}
+		}
+		if len(topics) == 0 {
+			return
+		}
+
+		response, err := NewOutputResponse(output, index)
+		if err != nil {
+			Plugin.LogWarn(err)
+			return
+		}
+
+		for _, topic := range topics {
+			wsHub.BroadcastMsg(&wsMessage{Type: wsMsgTypeEvent, Topic: topic, Data: response})
+		}
+	})
+
+	go wsHub.Run(ctx)
+	deps.Tangle.Events.ConfirmedMilestoneChanged.Attach(onConfirmedMilestoneChanged)
+	deps.Tangle.Events.ReceivedNewMessage.Attach(onReceivedNewMessage)
+	deps.Tangle.Events.NewUTXOOutput.Attach(onNewUTXOOutput)
+	<-ctx.Done()
+	deps.Tangle.Events.ConfirmedMilestoneChanged.Detach(onConfirmedMilestoneChanged)
+	deps.Tangle.Events.ReceivedNewMessage.Detach(onReceivedNewMessage)
+	deps.Tangle.Events.NewUTXOOutput.Detach(onNewUTXOOutput)
+}
+
+// outputAddresses returns the addresses that can unlock the output.
+func outputAddresses(output *utxo.Output) []iotago.Address {
+	unlockConditionOutput, ok := output.Output().(iotago.UnlockConditionOutput)
+	if !ok {
+		return nil
+	}
+
+	conditions, err := unlockConditionOutput.UnlockConditions().Set()
+	if err != nil {
+		return nil
+	}
+
+	var addresses []iotago.Address
+	if addressUnlock := conditions.Address(); addressUnlock != nil {
+		addresses = append(addresses, addressUnlock.Address)
+	}
+	if stateControllerUnlock := conditions.StateControllerAddress(); stateControllerUnlock != nil {
+		addresses = append(addresses, stateControllerUnlock.Address)
+	}
+	if governorUnlock := conditions.GovernorAddress(); governorUnlock != nil {
+		addresses = append(addresses, governorUnlock.Address)
+	}
+
+	return addresses
+}
+
+// validateTopic checks whether the topic is supported and returns the message ID of a "messages/{messageId}/referenced" topic.
+func validateTopic(topic string) (hornet.MessageID, error) {
+	switch {
+	case topic == wsTopicMilestonesConfirmed:
+		return nil, nil
+
+	case strings.HasPrefix(topic, "messages/data/"):
+		tag, err := hex.DecodeString(strings.TrimPrefix(topic, "messages/data/"))
+		if err != nil || len(tag) == 0 || len(tag) > iotago.MaxTagLength {
+			return nil, errors.Errorf("invalid tag in topic: %s", topic)
+		}
+		return nil, nil
+
+	case strings.HasPrefix(topic, "addresses/") && strings.HasSuffix(topic, "/outputs"):
+		hrp, _, err := iotago.ParseBech32(strings.TrimSuffix(strings.TrimPrefix(topic, "addresses/"), "/outputs"))
+		if err != nil || hrp != deps.Bech32HRP {
+			return nil, errors.Errorf("invalid address in topic: %s", topic)
+		}
+		return nil, nil
+
+	case strings.HasPrefix(topic, "messages/") && strings.HasSuffix(topic, "/referenced"):
+		messageID, err := hornet.MessageIDFromHex(strings.TrimSuffix(strings.TrimPrefix(topic, "messages/"), "/referenced"))
+		if err != nil {
+			return nil, errors.Errorf("invalid message ID in topic: %s", topic)
+		}
+		return messageID, nil
+
+	default:
+		return nil, errors.Errorf("unknown topic: %s", topic)
+	}
+}
+
+func websocketRoute(c echo.Context) error {
+	if wsMaxSubscriptionsPerClient <= 0 {
+		return errors.WithMessage(echo.ErrServiceUnavailable, "websocket subscriptions are disabled")
+	}
+
+	subscriptions := &wsClientSubscriptions{topics: make(map[string]context.CancelFunc)}
+
+	// unsubscribe removes the subscription and stops its goroutine.
+	unsubscribe := func(topic string) bool {
+		subscriptions.Lock()
+		defer subscriptions.Unlock()
+
+		cancel, subscribed := subscriptions.topics[topic]
+		if !subscribed {
+			return false
+		}
+		if cancel != nil {
+			cancel()
+		}
+		delete(subscriptions.topics, topic)
+		wsTopics.remove(topic)
+
+		return true
+	}
+
+	// awaitReferenced sends the metadata of the message once it is referenced and ends the subscription.
+	awaitReferenced := func(ctx context.Context, client *websockethub.Client, topic string, messageID hornet.MessageID) {
+		mergedCtx, mergedCtxCancel := utils.MergeContexts(ctx, Plugin.Daemon().ContextStopped())
+		defer mergedCtxCancel()
+
+		waitForMessageState(mergedCtx, messageID, waitForReferenced)
+		if mergedCtx.Err() != nil {
+			return
+		}
+
+		if !unsubscribe(topic) {
+			// the client unsubscribed in the meantime
+			return
+		}
+
+		metadata, err := messageMetadataByMessageID(messageID)
+		if err != nil {
+			client.Send(&wsMessage{Type: wsMsgTypeError, Topic: topic, Error: err.Error()})
+			return
+		}
+		client.Send(&wsMessage{Type: wsMsgTypeEvent, Topic: topic, Data: metadata})
+	}
+
+	subscribe := func(client *websockethub.Client, topic string) error {
+		messageID, err := validateTopic(topic)
+		if err != nil {
+			return err
+		}
+
+		subscriptions.Lock()
+		defer subscriptions.Unlock()
+
+		if _, subscribed := subscriptions.topics[topic]; subscribed {
+			return nil
+		}
+		if len(subscriptions.topics) >= wsMaxSubscriptionsPerClient {
+			return errors.Errorf("too many subscriptions, the limit is %d per client", wsMaxSubscriptionsPerClient)
+		}
+
+		var cancel context.CancelFunc
+		if messageID != nil {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			go awaitReferenced(ctx, client, topic, messageID)
+		}
+		subscriptions.topics[topic] = cancel
+		wsTopics.add(topic)
+
+		return nil
+	}
+
+	handleCommand := func(client *websockethub.Client, msg *websockethub.WebsocketMsg) {
+		cmd := &wsCommand{}
+		if msg.MsgType != websockethub.TextMessage || json.Unmarshal(msg.Data, cmd) != nil {
+			client.Send(&wsMessage{Type: wsMsgTypeError, Error: "invalid command"})
+			return
+		}
+
+		switch cmd.Type {
+		case wsCmdSubscribe:
+			if err := subscribe(client, cmd.Topic); err != nil {
+				client.Send(&wsMessage{Type: wsMsgTypeError, Topic: cmd.Topic, Error: err.Error()})
+				return
+			}
+			client.Send(&wsMessage{Type: wsMsgTypeSubscribed, Topic: cmd.Topic})
+
+		case wsCmdUnsubscribe:
+			unsubscribe(cmd.Topic)
+			client.Send(&wsMessage{Type: wsMsgTypeUnsubscribed, Topic: cmd.Topic})
+
+		default:
+			client.Send(&wsMessage{Type: wsMsgTypeError, Topic: cmd.Topic, Error: "unknown command type: " + cmd.Type})
+		}
+	}
+
+	wsHub.ServeWebsocket(c.Response(), c.Request(),
+		// onCreate gets called when the client is created
+		func(client *websockethub.Client) {
+			client.FilterCallback = func(_ *websockethub.Client, data interface{}) bool {
+				msg, ok := data.(*wsMessage)
+				if !ok {
+					return false
+				}
+				return subscriptions.contains(msg.Topic)
+			}
+			client.ReceiveChan = make(chan *websockethub.WebsocketMsg, 100)
+
+			go func() {
+				defer func() {
+					// client was disconnected
+					subscriptions.RLock()
+					topics := make([]string, 0, len(subscriptions.topics))
+					for topic := range subscriptions.topics {
+						topics = append(topics, topic)
+					}
+					subscriptions.RUnlock()
+
+					for _, topic := range topics {
+						unsubscribe(topic)
+					}
+				}()
+
+				for {
+					select {
+					case <-client.ExitSignal:
+						return
+
+					case msg, ok := <-client.ReceiveChan:
+						if !ok {
+							return
+						}
+						handleCommand(client, msg)
+					}
+				}
+			}()
+		},
+
+		// onConnect gets called when the client was registered
+		func(_ *websockethub.Client) {
+			Plugin.LogDebug("WebSocket client connection established")
+		})
+
+	return nil
+}
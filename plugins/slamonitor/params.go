@@ -0,0 +1,37 @@
+package slamonitor
+
+import (
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gohornet/hornet/pkg/node"
+)
+
+const (
+	// the amount of confirmed milestones in the sliding window.
+	CfgSLAMonitorWindowSize = "slaMonitor.windowSize"
+	// the minimum referenced rate in percent over the window (0 = disabled).
+	CfgSLAMonitorMinReferencedRate = "slaMonitor.minReferencedRate"
+	// the maximum average time to reference of messages over the window (0 = disabled).
+	CfgSLAMonitorMaxTimeToReference = "slaMonitor.maxTimeToReference"
+	// the URLs the SLA events are posted to.
+	CfgSLAMonitorWebhookURLs = "slaMonitor.webhooks.urls"
+	// the timeout for posting an SLA event to a webhook.
+	CfgSLAMonitorWebhookTimeout = "slaMonitor.webhooks.timeout"
+)
+
+var params = &node.PluginParams{
+	Params: map[string]*flag.FlagSet{
+		"nodeConfig": func() *flag.FlagSet {
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.Int(CfgSLAMonitorWindowSize, 10, "the amount of confirmed milestones in the sliding window")
+			fs.Float64(CfgSLAMonitorMinReferencedRate, 70.0, "the minimum referenced rate in percent over the window (0 = disabled)")
+			fs.Duration(CfgSLAMonitorMaxTimeToReference, 1*time.Minute, "the maximum average time to reference of messages over the window (0 = disabled)")
+			fs.StringSlice(CfgSLAMonitorWebhookURLs, nil, "the URLs the SLA events are posted to")
+			fs.Duration(CfgSLAMonitorWebhookTimeout, 5*time.Second, "the timeout for posting an SLA event to a webhook")
+			return fs
+		}(),
+	},
+	Masked: nil,
+}
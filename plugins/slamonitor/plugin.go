@@ -0,0 +1,139 @@
+package slamonitor
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/dig"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/storage"
+	"github.com/gohornet/hornet/pkg/node"
+	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/gohornet/hornet/pkg/slamonitor"
+	"github.com/gohornet/hornet/pkg/tangle"
+	"github.com/iotaledger/hive.go/configuration"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/workerpool"
+)
+
+const (
+	// the amount of SLA events which are queued to be posted to the webhooks.
+	webhookQueueSize = 100
+)
+
+func init() {
+	Plugin = &node.Plugin{
+		Status: node.StatusDisabled,
+		Pluggable: node.Pluggable{
+			Name:      "SLAMonitor",
+			DepsFunc:  func(cDeps dependencies) { deps = cDeps },
+			Params:    params,
+			Provide:   provide,
+			Configure: configure,
+			Run:       run,
+		},
+	}
+}
+
+var (
+	Plugin *node.Plugin
+	deps   dependencies
+
+	webhookWorkerPool *workerpool.WorkerPool
+
+	// Closures
+	onMessageReferenced           *events.Closure
+	onNewConfirmedMilestoneMetric *events.Closure
+	onSLABreached                 *events.Closure
+	onSLARecovered                *events.Closure
+)
+
+type dependencies struct {
+	dig.In
+	NodeConfig *configuration.Configuration `name:"nodeConfig"`
+	Tangle     *tangle.Tangle
+	SLAMonitor *slamonitor.Monitor
+}
+
+func provide(c *dig.Container) {
+
+	type monitorDeps struct {
+		dig.In
+		NodeConfig *configuration.Configuration `name:"nodeConfig"`
+	}
+
+	if err := c.Provide(func(deps monitorDeps) *slamonitor.Monitor {
+		return slamonitor.New(
+			deps.NodeConfig.Int(CfgSLAMonitorWindowSize),
+			deps.NodeConfig.Float64(CfgSLAMonitorMinReferencedRate),
+			deps.NodeConfig.Duration(CfgSLAMonitorMaxTimeToReference),
+		)
+	}); err != nil {
+		Plugin.LogPanic(err)
+	}
+}
+
+func configure() {
+
+	webhooks := newWebhooks(deps.NodeConfig.Strings(CfgSLAMonitorWebhookURLs), deps.NodeConfig.Duration(CfgSLAMonitorWebhookTimeout))
+
+	webhookWorkerPool = workerpool.New(func(task workerpool.Task) {
+		webhooks.post(task.Param(0).(slaEvent), task.Param(1).(*slamonitor.Status))
+		task.Return(nil)
+	}, workerpool.WorkerCount(1), workerpool.QueueSize(webhookQueueSize))
+
+	configureEvents()
+}
+
+func run() {
+	if err := Plugin.Daemon().BackgroundWorker("SLAMonitor", func(ctx context.Context) {
+		Plugin.LogInfo("Starting SLAMonitor ... done")
+		attachEvents()
+		webhookWorkerPool.Start()
+		<-ctx.Done()
+		Plugin.LogInfo("Stopping SLAMonitor ...")
+		detachEvents()
+		webhookWorkerPool.StopAndWait()
+		Plugin.LogInfo("Stopping SLAMonitor ... done")
+	}, shutdown.PrioritySLAMonitor); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+}
+
+func configureEvents() {
+	onMessageReferenced = events.NewClosure(func(cachedMetadata *storage.CachedMetadata, index milestone.Index, confTime uint64) {
+		defer cachedMetadata.Release(true) // meta -1
+
+		solidificationTime := time.Unix(int64(cachedMetadata.Metadata().SolidificationTimestamp()), 0)
+		deps.SLAMonitor.AddReferencedMessage(index, time.Unix(int64(confTime), 0).Sub(solidificationTime))
+	})
+
+	onNewConfirmedMilestoneMetric = events.NewClosure(func(metric *tangle.ConfirmedMilestoneMetric) {
+		deps.SLAMonitor.AddMilestone(metric.MilestoneIndex, metric.ReferencedRate)
+	})
+
+	onSLABreached = events.NewClosure(func(status *slamonitor.Status) {
+		Plugin.LogWarnf("confirmation SLA breached at milestone %d: %0.2f%% ref.rate, %v avg. time to reference", status.MilestoneIndex, status.ReferencedRate, status.AvgTimeToReference.Truncate(time.Millisecond))
+		webhookWorkerPool.TrySubmit(slaEventBreached, status)
+	})
+
+	onSLARecovered = events.NewClosure(func(status *slamonitor.Status) {
+		Plugin.LogInfof("confirmation SLA recovered at milestone %d: %0.2f%% ref.rate, %v avg. time to reference", status.MilestoneIndex, status.ReferencedRate, status.AvgTimeToReference.Truncate(time.Millisecond))
+		webhookWorkerPool.TrySubmit(slaEventRecovered, status)
+	})
+}
+
+func attachEvents() {
+	deps.Tangle.Events.MessageReferenced.Attach(onMessageReferenced)
+	deps.Tangle.Events.NewConfirmedMilestoneMetric.Attach(onNewConfirmedMilestoneMetric)
+	deps.SLAMonitor.Events.SLABreached.Attach(onSLABreached)
+	deps.SLAMonitor.Events.SLARecovered.Attach(onSLARecovered)
+}
+
+func detachEvents() {
+	deps.Tangle.Events.MessageReferenced.Detach(onMessageReferenced)
+	deps.Tangle.Events.NewConfirmedMilestoneMetric.Detach(onNewConfirmedMilestoneMetric)
+	deps.SLAMonitor.Events.SLABreached.Detach(onSLABreached)
+	deps.SLAMonitor.Events.SLARecovered.Detach(onSLARecovered)
+}
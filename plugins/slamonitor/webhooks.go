@@ -0,0 +1,65 @@
+package slamonitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/slamonitor"
+)
+
+// slaEvent is the type of an event posted to the webhooks.
+type slaEvent string
+
+const (
+	slaEventBreached  slaEvent = "breached"
+	slaEventRecovered slaEvent = "recovered"
+)
+
+// webhookPayload is the JSON payload posted to the webhooks.
+type webhookPayload struct {
+	// The type of the event.
+	Event slaEvent `json:"event"`
+	// The SLA status at the time of the event.
+	Status *slamonitor.Status `json:"status"`
+}
+
+// webhooks posts SLA events to the configured URLs.
+type webhooks struct {
+	urls   []string
+	client *http.Client
+}
+
+func newWebhooks(urls []string, timeout time.Duration) *webhooks {
+	return &webhooks{
+		urls:   urls,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// post posts the given event to all webhooks. Failures are logged and not retried.
+func (w *webhooks) post(event slaEvent, status *slamonitor.Status) {
+	if len(w.urls) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(&webhookPayload{Event: event, Status: status})
+	if err != nil {
+		Plugin.LogWarnf("failed to marshal SLA event: %s", err)
+		return
+	}
+
+	for _, url := range w.urls {
+		res, err := w.client.Post(url, "application/json", bytes.NewReader(payload))
+		if err != nil {
+			Plugin.LogWarnf("failed to post SLA event to %s: %s", url, err)
+			continue
+		}
+		_ = res.Body.Close()
+
+		if res.StatusCode < 200 || res.StatusCode >= 300 {
+			Plugin.LogWarnf("failed to post SLA event to %s: status code %d", url, res.StatusCode)
+		}
+	}
+}
@@ -21,6 +21,12 @@ const (
 	CfgSpammerWorkers = "spammer.workers"
 	// CfgSpammerAutostart automatically starts the spammer on node startup
 	CfgSpammerAutostart = "spammer.autostart"
+	// CfgSpammerValueSpamEnabled defines whether the spammer issues value transactions that cycle the funds between its own addresses
+	CfgSpammerValueSpamEnabled = "spammer.valueSpam.enabled"
+	// CfgSpammerValueSpamAddressCount defines the amount of addresses derived from the seed the funds are cycled between
+	CfgSpammerValueSpamAddressCount = "spammer.valueSpam.addressCount"
+	// CfgSpammerValueSpamOutputCount defines the amount of outputs created by a value transaction
+	CfgSpammerValueSpamOutputCount = "spammer.valueSpam.outputCount"
 )
 
 var params = &node.PluginParams{
@@ -34,6 +40,9 @@ var params = &node.PluginParams{
 			fs.Float64(CfgSpammerMPSRateLimit, 0.0, "the rate limit for the spammer (0 = no limit)")
 			fs.Int(CfgSpammerWorkers, 0, "the amount of parallel running spammers")
 			fs.Bool(CfgSpammerAutostart, false, "automatically start the spammer on node startup")
+			fs.Bool(CfgSpammerValueSpamEnabled, false, "whether the spammer issues value transactions that cycle the funds between its own addresses (the seed is loaded from the SPAMMER_SEED environment variable)")
+			fs.Int(CfgSpammerValueSpamAddressCount, 10, "the amount of addresses derived from the seed the funds are cycled between")
+			fs.Int(CfgSpammerValueSpamOutputCount, 2, "the amount of outputs created by a value transaction")
 			return fs
 		}(),
 	},
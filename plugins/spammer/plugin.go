@@ -2,8 +2,10 @@ package spammer
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,18 +14,23 @@ import (
 	"go.uber.org/dig"
 
 	"github.com/gohornet/hornet/pkg/common"
+	"github.com/gohornet/hornet/pkg/indexer"
 	"github.com/gohornet/hornet/pkg/metrics"
 	"github.com/gohornet/hornet/pkg/model/storage"
 	"github.com/gohornet/hornet/pkg/model/syncmanager"
+	"github.com/gohornet/hornet/pkg/model/utxo"
 	"github.com/gohornet/hornet/pkg/node"
 	"github.com/gohornet/hornet/pkg/p2p"
 	"github.com/gohornet/hornet/pkg/pow"
 	"github.com/gohornet/hornet/pkg/protocol/gossip"
 	"github.com/gohornet/hornet/pkg/shutdown"
 	"github.com/gohornet/hornet/pkg/spammer"
+	"github.com/gohornet/hornet/pkg/tangle"
 	"github.com/gohornet/hornet/pkg/tipselect"
 	"github.com/gohornet/hornet/pkg/utils"
+	"github.com/gohornet/hornet/pkg/whiteflag"
 	"github.com/gohornet/hornet/plugins/coordinator"
+	indexerPlugin "github.com/gohornet/hornet/plugins/indexer"
 	"github.com/gohornet/hornet/plugins/restapi"
 	restapiv2 "github.com/gohornet/hornet/plugins/restapi/v2"
 	"github.com/gohornet/hornet/plugins/urts"
@@ -54,6 +61,9 @@ var (
 	spammerInstance *spammer.Spammer
 	spammerLock     syncutils.RWMutex
 
+	// the wallet of the value spam, nil if the value spam is disabled
+	valueSpamWallet *spammer.ValueSpamWallet
+
 	spammerStartTime    time.Time
 	spammerAvgHeap      *utils.TimeHeap
 	lastSentSpamMsgsCnt uint32
@@ -78,6 +88,9 @@ var (
 
 	// ErrSpammerDisabled is returned if the spammer plugin is disabled.
 	ErrSpammerDisabled = errors.New("spammer plugin disabled")
+
+	// Closures
+	onMilestoneConfirmed *events.Closure
 )
 
 type dependencies struct {
@@ -87,9 +100,14 @@ type dependencies struct {
 	ServerMetrics             *metrics.ServerMetrics
 	PoWHandler                *pow.Handler
 	PeeringManager            *p2p.Manager
-	TipSelector               *tipselect.TipSelector       `optional:"true"`
+	TipSelector               *tipselect.TipSelector `optional:"true"`
+	Tangle                    *tangle.Tangle
+	UTXOManager               *utxo.Manager
+	Indexer                   *indexer.Indexer             `optional:"true"`
 	NodeConfig                *configuration.Configuration `name:"nodeConfig"`
 	NetworkID                 uint64                       `name:"networkId"`
+	BelowMaxDepth             int                          `name:"belowMaxDepth"`
+	Bech32HRP                 iotago.NetworkPrefix         `name:"bech32HRP"`
 	DeserializationParameters *iotago.DeSerializationParameters
 }
 
@@ -114,7 +132,7 @@ func configure() {
 
 	// helper function to send the message to the network
 	sendMessage := func(msg *storage.Message) error {
-		if err := deps.MessageProcessor.Emit(msg); err != nil {
+		if err := deps.MessageProcessor.EmitWithPriority(msg, gossip.EmitPriorityLow); err != nil {
 			return err
 		}
 
@@ -130,6 +148,10 @@ func configure() {
 	}
 	isRunning = false
 
+	if deps.NodeConfig.Bool(CfgSpammerValueSpamEnabled) {
+		configureValueSpam()
+	}
+
 	spammerInstance = spammer.New(
 		deps.NetworkID,
 		deps.DeserializationParameters,
@@ -140,7 +162,46 @@ func configure() {
 		deps.PoWHandler,
 		sendMessage,
 		deps.ServerMetrics,
+		valueSpamWallet,
+	)
+}
+
+// configureValueSpam creates the wallet of the value spam with the seed from the environment.
+func configureValueSpam() {
+	// check if Indexer plugin is disabled
+	if Plugin.Node.IsSkipped(indexerPlugin.Plugin) {
+		Plugin.LogPanic("Indexer plugin needs to be enabled to use the value spam of the Spammer plugin")
+	}
+
+	seedHex, err := utils.LoadStringFromEnvironment("SPAMMER_SEED")
+	if err != nil {
+		Plugin.LogPanicf("loading spammer seed failed, err: %s", err)
+	}
+
+	seed, err := hex.DecodeString(strings.TrimPrefix(seedHex, "0x"))
+	if err != nil {
+		Plugin.LogPanicf("loading spammer seed failed, err: %s", err)
+	}
+
+	valueSpamWallet, err = spammer.NewValueSpamWallet(
+		seed,
+		deps.NodeConfig.Int(CfgSpammerValueSpamAddressCount),
+		deps.NodeConfig.Int(CfgSpammerValueSpamOutputCount),
+		deps.BelowMaxDepth,
+		deps.DeserializationParameters,
+		deps.SyncManager,
+		deps.UTXOManager,
+		deps.Indexer,
 	)
+	if err != nil {
+		Plugin.LogPanicf("creating value spam wallet failed, err: %s", err)
+	}
+
+	Plugin.LogInfof("value spam enabled, send funds to %s to cycle them between %d addresses", valueSpamWallet.Addresses()[0].Bech32(deps.Bech32HRP), len(valueSpamWallet.Addresses()))
+
+	onMilestoneConfirmed = events.NewClosure(func(confirmation *whiteflag.Confirmation) {
+		valueSpamWallet.ApplyConfirmation(confirmation)
+	})
 }
 
 func run() {
@@ -149,6 +210,17 @@ func run() {
 		return
 	}
 
+	if valueSpamWallet != nil {
+		// create a background worker that applies the confirmations to the value spam wallet
+		if err := Plugin.Daemon().BackgroundWorker("Spammer Value Spam", func(ctx context.Context) {
+			deps.Tangle.Events.MilestoneConfirmed.Attach(onMilestoneConfirmed)
+			<-ctx.Done()
+			deps.Tangle.Events.MilestoneConfirmed.Detach(onMilestoneConfirmed)
+		}, shutdown.PrioritySpammer); err != nil {
+			Plugin.LogPanicf("failed to start worker: %s", err)
+		}
+	}
+
 	// create a background worker that "measures" the spammer averages values every second
 	if err := Plugin.Daemon().BackgroundWorker("Spammer Metrics Updater", func(ctx context.Context) {
 		ticker := timeutil.NewTicker(measureSpammerMetrics, 1*time.Second, ctx)
@@ -0,0 +1,28 @@
+package standby
+
+import (
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gohornet/hornet/pkg/node"
+)
+
+const (
+	// the URLs the promotion of the node is posted to, e.g. to reconfigure a load balancer.
+	CfgStandbyWebhookURLs = "standby.webhooks.urls"
+	// the timeout for posting the promotion to a webhook.
+	CfgStandbyWebhookTimeout = "standby.webhooks.timeout"
+)
+
+var params = &node.PluginParams{
+	Params: map[string]*flag.FlagSet{
+		"nodeConfig": func() *flag.FlagSet {
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.StringSlice(CfgStandbyWebhookURLs, nil, "the URLs the promotion of the node is posted to, e.g. to reconfigure a load balancer")
+			fs.Duration(CfgStandbyWebhookTimeout, 5*time.Second, "the timeout for posting the promotion to a webhook")
+			return fs
+		}(),
+	},
+	Masked: nil,
+}
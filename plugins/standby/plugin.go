@@ -0,0 +1,85 @@
+package standby
+
+import (
+	"context"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"go.uber.org/dig"
+
+	"github.com/gohornet/hornet/pkg/node"
+	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/gohornet/hornet/pkg/standby"
+	"github.com/gohornet/hornet/plugins/restapi"
+	"github.com/iotaledger/hive.go/configuration"
+	"github.com/iotaledger/hive.go/events"
+)
+
+func init() {
+	Plugin = &node.Plugin{
+		Status: node.StatusDisabled,
+		Pluggable: node.Pluggable{
+			Name:      "Standby",
+			DepsFunc:  func(cDeps dependencies) { deps = cDeps },
+			Params:    params,
+			Provide:   provide,
+			Configure: configure,
+			Run:       run,
+		},
+	}
+}
+
+var (
+	Plugin *node.Plugin
+	deps   dependencies
+
+	webhookPoster *webhooks
+
+	// Closures
+	onPromoted *events.Closure
+)
+
+type dependencies struct {
+	dig.In
+	NodeConfig *configuration.Configuration `name:"nodeConfig"`
+	Host       host.Host
+	Standby    *standby.Standby
+}
+
+func provide(c *dig.Container) {
+
+	if err := c.Provide(func() *standby.Standby {
+		return standby.New()
+	}); err != nil {
+		Plugin.LogPanic(err)
+	}
+}
+
+func configure() {
+	// the public routes are gated by the RestAPI plugin
+	if Plugin.Node.IsSkipped(restapi.Plugin) {
+		Plugin.LogPanic("RestAPI plugin needs to be enabled to use the Standby plugin")
+	}
+
+	webhookPoster = newWebhooks(deps.NodeConfig.Strings(CfgStandbyWebhookURLs), deps.NodeConfig.Duration(CfgStandbyWebhookTimeout))
+
+	onPromoted = events.NewClosure(func(status *standby.Status) {
+		Plugin.LogInfo("node got promoted, serving the public routes")
+		go webhookPoster.post(deps.Host.ID().String(), status)
+	})
+}
+
+func run() {
+	if err := Plugin.Daemon().BackgroundWorker("Standby", func(ctx context.Context) {
+		Plugin.LogInfo("Starting Standby ... done")
+		deps.Standby.Events.Promoted.Attach(onPromoted)
+		if deps.Standby.IsStandby() {
+			Plugin.LogInfo("node runs in standby mode, the public routes are not served until the node gets promoted")
+		}
+		<-ctx.Done()
+		Plugin.LogInfo("Stopping Standby ...")
+		deps.Standby.Events.Promoted.Detach(onPromoted)
+		Plugin.LogInfo("Stopping Standby ... done")
+	}, shutdown.PriorityStandby); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+}
@@ -0,0 +1,65 @@
+package standby
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/standby"
+)
+
+// the type of the event posted to the webhooks.
+const webhookEventPromoted = "promoted"
+
+// webhookPayload is the JSON payload posted to the webhooks.
+type webhookPayload struct {
+	// The type of the event.
+	Event string `json:"event"`
+	// The peer ID of the promoted node.
+	NodeID string `json:"nodeId"`
+	// The state of the standby mode after the promotion.
+	Status *standby.Status `json:"status"`
+}
+
+// webhooks posts the promotion of the node to the configured URLs.
+type webhooks struct {
+	urls   []string
+	client *http.Client
+}
+
+func newWebhooks(urls []string, timeout time.Duration) *webhooks {
+	return &webhooks{
+		urls:   urls,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// post posts the promotion to all webhooks. Failures are logged and not retried.
+func (w *webhooks) post(nodeID string, status *standby.Status) {
+	if len(w.urls) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(&webhookPayload{Event: webhookEventPromoted, NodeID: nodeID, Status: status})
+	if err != nil {
+		Plugin.LogWarnf("failed to marshal promotion: %s", err)
+		return
+	}
+
+	for _, url := range w.urls {
+		res, err := w.client.Post(url, "application/json", bytes.NewReader(payload))
+		if err != nil {
+			Plugin.LogWarnf("failed to post promotion to %s: %s", url, err)
+			continue
+		}
+		_ = res.Body.Close()
+
+		if res.StatusCode < 200 || res.StatusCode >= 300 {
+			Plugin.LogWarnf("failed to post promotion to %s: status code %d", url, res.StatusCode)
+			continue
+		}
+
+		Plugin.LogInfof("posted promotion to %s", url)
+	}
+}